@@ -23,4 +23,18 @@ var Capabilities = map[Dialect]map[Feature]bool{
 		FeatureJson:           false,
 		FeatureArray:          false,
 	},
+	DialectDuckDB: {
+		FeatureConcat:         true,
+		FeatureConcatOperator: true,
+		FeatureConcatFunction: true,
+		FeatureJson:           true,
+		FeatureArray:          true,
+	},
+	DialectClickHouse: {
+		FeatureConcat:         true,
+		FeatureConcatOperator: true,
+		FeatureConcatFunction: true,
+		FeatureJson:           true,
+		FeatureArray:          true,
+	},
 }
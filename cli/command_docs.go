@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+	"github.com/shibukawa/snapsql/querydoc"
+)
+
+// ErrNoIntermediateFiles is returned when the docs command finds nothing to document
+var ErrNoIntermediateFiles = errors.New("no intermediate files found")
+
+// DocsCmd represents the docs command
+type DocsCmd struct {
+	Input   string `short:"i" help:"Directory of intermediate JSON files (defaults to the json generator output)" type:"path"`
+	Output  string `short:"o" help:"Output file (.html/.md) or directory" default:"generated/docs" type:"path"`
+	Format  string `help:"Catalog format" default:"html" enum:"html,markdown"`
+	Dialect string `help:"Dialect used to render example SQL (defaults to dialect in config)"`
+	Title   string `help:"Catalog title"`
+}
+
+func (cmd *DocsCmd) Run(ctx *Context) error {
+	config, err := LoadConfig(ctx.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	inputDir := cmd.Input
+	if inputDir == "" {
+		inputDir = "./generated"
+		if jsonGen, exists := config.Generation.Generators["json"]; exists && jsonGen.Output != "" {
+			inputDir = jsonGen.Output
+		}
+
+		if ctx.Config != "" && !filepath.IsAbs(inputDir) {
+			if abs, err := filepath.Abs(ctx.Config); err == nil {
+				inputDir = filepath.Join(filepath.Dir(abs), inputDir)
+			}
+		}
+	}
+
+	dialect := config.Dialect
+	if cmd.Dialect != "" {
+		dialect = snapsql.Dialect(cmd.Dialect)
+	}
+
+	catalog := &querydoc.Catalog{Title: cmd.Title, Dialect: dialect}
+
+	files, err := findIntermediateFiles(inputDir)
+	if err != nil {
+		return fmt.Errorf("failed to find intermediate files: %w", err)
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to read intermediate file %s: %w", file, err)
+		}
+
+		format, err := intermediate.Decode(data)
+		if err != nil || format.FormatVersion == "" {
+			// Other JSON files (e.g. openapi.json) may share the output directory
+			if ctx.Verbose {
+				color.Yellow("Skipping %s: not an intermediate file", file)
+			}
+
+			continue
+		}
+
+		source := file
+		if rel, err := filepath.Rel(inputDir, file); err == nil {
+			source = rel
+		}
+
+		if err := catalog.Add(format, source); err != nil {
+			return fmt.Errorf("failed to document %s: %w", file, err)
+		}
+	}
+
+	if len(catalog.Entries) == 0 {
+		return fmt.Errorf("%w in %s (run 'snapsql generate' first)", ErrNoIntermediateFiles, inputDir)
+	}
+
+	outputFile := cmd.Output
+
+	switch ext := strings.ToLower(filepath.Ext(outputFile)); ext {
+	case ".md", ".markdown":
+		cmd.Format = "markdown"
+	case ".html", ".htm":
+		cmd.Format = "html"
+	default:
+		name := "index.html"
+		if cmd.Format == "markdown" {
+			name = "index.md"
+		}
+
+		outputFile = filepath.Join(outputFile, name)
+	}
+
+	var output bytes.Buffer
+	if err := catalog.Write(&output, cmd.Format); err != nil {
+		return fmt.Errorf("failed to render query catalog: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", filepath.Dir(outputFile), err)
+	}
+
+	if err := os.WriteFile(outputFile, output.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write query catalog %s: %w", outputFile, err)
+	}
+
+	if !ctx.Quiet {
+		color.Green("Documented %d queries in %s", len(catalog.Entries), outputFile)
+	}
+
+	return nil
+}
+
+// findIntermediateFiles finds all JSON files under dir
+func findIntermediateFiles(dir string) ([]string, error) {
+	var files []string
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		ext := filepath.Ext(path)
+		if !d.IsDir() && (strings.EqualFold(ext, ".json") || strings.EqualFold(ext, intermediate.BinaryFileExtension)) {
+			files = append(files, path)
+		}
+
+		return nil
+	})
+
+	return files, err
+}
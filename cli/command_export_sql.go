@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+	"github.com/shibukawa/snapsql/markdownparser"
+	"github.com/shibukawa/snapsql/querydoc"
+)
+
+// ExportSQLCmd represents the export-sql command
+type ExportSQLCmd struct {
+	Input   string   `short:"i" help:"Input directory (defaults to input_dir in config)" type:"path"`
+	Files   []string `arg:"" help:"Specific templates to export" optional:""`
+	Const   []string `help:"Constant definition files"`
+	Dialect string   `help:"Dialect of the exported SQL (defaults to dialect in config)"`
+	Out     string   `short:"o" help:"Output directory" default:"generated/sql" type:"path"`
+}
+
+func (cmd *ExportSQLCmd) Run(ctx *Context) error {
+	config, err := LoadConfig(ctx.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if cmd.Dialect != "" {
+		config.Dialect = snapsql.Dialect(cmd.Dialect)
+	}
+
+	constants, err := (&GenerateCmd{Const: cmd.Const}).loadConstants(config, ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load constants: %w", err)
+	}
+
+	inputDir := cmd.Input
+	if inputDir == "" {
+		inputDir = config.InputDir
+		if ctx.Config != "" && inputDir != "" && !filepath.IsAbs(inputDir) {
+			if abs, err := filepath.Abs(ctx.Config); err == nil {
+				inputDir = filepath.Join(filepath.Dir(abs), inputDir)
+			}
+		}
+	}
+
+	files := cmd.Files
+	if len(files) == 0 {
+		files, err = findTemplateFiles(inputDir)
+		if err != nil {
+			return fmt.Errorf("failed to find template files: %w", err)
+		}
+	}
+
+	written, err := cmd.export(files, inputDir, constants, loadRuntimeTables(ctx), config)
+	if err != nil {
+		return err
+	}
+
+	if !ctx.Quiet {
+		color.Green("Exported %d statement(s) to %s", written, cmd.Out)
+	}
+
+	return nil
+}
+
+// export writes one .sql file per template under cmd.Out, keeping the directory layout of inputDir.
+// Static templates are written as the exact statement with placeholders annotated by their parameters;
+// dynamic templates keep their conditional blocks as SnapSQL directives.
+func (cmd *ExportSQLCmd) export(files []string, inputDir string, constants map[string]any, tables map[string]*snapsql.TableInfo, config *snapsql.Config) (int, error) {
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return 0, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		var format *intermediate.IntermediateFormat
+
+		if strings.EqualFold(filepath.Ext(file), ".md") {
+			doc, err := markdownparser.Parse(bytes.NewReader(content))
+			if err != nil {
+				return 0, fmt.Errorf("failed to parse markdown %s: %w", file, err)
+			}
+
+			format, err = intermediate.GenerateFromMarkdown(doc, file, ".", constants, tables, config)
+			if err != nil {
+				return 0, fmt.Errorf("%s: %w", file, err)
+			}
+		} else {
+			format, err = intermediate.GenerateFromSQL(bytes.NewReader(content), constants, file, ".", tables, config)
+			if err != nil {
+				return 0, fmt.Errorf("%s: %w", file, err)
+			}
+		}
+
+		sql, err := querydoc.RenderSQL(format, config.Dialect)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", file, err)
+		}
+
+		source := filepath.Base(file)
+		if rel, err := filepath.Rel(inputDir, file); err == nil && inputDir != "" && !strings.HasPrefix(rel, "..") {
+			source = rel
+		}
+
+		outputFile := filepath.Join(cmd.Out, exportSQLFilename(source))
+		if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil {
+			return 0, fmt.Errorf("failed to create output directory %s: %w", filepath.Dir(outputFile), err)
+		}
+
+		var b strings.Builder
+
+		fmt.Fprintf(&b, "-- %s (%s)\n", format.FunctionName, filepath.ToSlash(source))
+
+		if format.Description != "" {
+			for line := range strings.SplitSeq(strings.TrimSpace(format.Description), "\n") {
+				fmt.Fprintf(&b, "-- %s\n", strings.TrimSpace(line))
+			}
+		}
+
+		b.WriteString(strings.TrimSuffix(sql, ";"))
+		b.WriteString(";\n")
+
+		if err := os.WriteFile(outputFile, []byte(b.String()), 0644); err != nil {
+			return 0, fmt.Errorf("failed to write %s: %w", outputFile, err)
+		}
+	}
+
+	return len(files), nil
+}
+
+// exportSQLFilename replaces the .snap.sql / .snap.md extension of a template path with .sql
+func exportSQLFilename(source string) string {
+	name := strings.TrimSuffix(source, filepath.Ext(source))
+	name = strings.TrimSuffix(name, ".snap")
+
+	return name + ".sql"
+}
@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql"
+)
+
+func TestExportSQL_WritesOneFilePerTemplate(t *testing.T) {
+	t.Parallel()
+
+	inputDir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(inputDir, "users"), 0755))
+
+	static := writeTemp(t, filepath.Join(inputDir, "users"), "find_user.snap.sql", ""+
+		"/*#\n"+
+		"function_name: find_user\n"+
+		"description: Find a user by id\n"+
+		"parameters:\n"+
+		"  user_id: int\n"+
+		"*/\n"+
+		"SELECT id, name FROM users WHERE id = /*= user_id */0\n")
+	dynamic := writeTemp(t, inputDir, "list_users.snap.sql", ""+
+		"/*#\n"+
+		"function_name: list_users\n"+
+		"parameters:\n"+
+		"  active: bool\n"+
+		"*/\n"+
+		"SELECT id, name FROM users\n"+
+		"WHERE deleted = FALSE\n"+
+		"/*# if active */\n"+
+		"  AND active = TRUE\n"+
+		"/*# end */\n")
+
+	outDir := t.TempDir()
+	cmd := &ExportSQLCmd{Out: outDir}
+
+	written, err := cmd.export([]string{static, dynamic}, inputDir, nil, nil, &snapsql.Config{Dialect: snapsql.DialectPostgres})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, written)
+
+	data, err := os.ReadFile(filepath.Join(outDir, "users", "find_user.sql"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "-- find_user (users/find_user.snap.sql)\n-- Find a user by id\n")
+	assert.Contains(t, string(data), "WHERE id = /*= user_id */$1;\n")
+
+	data, err = os.ReadFile(filepath.Join(outDir, "list_users.sql"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "/*# if active */")
+	assert.Contains(t, string(data), "/*# end */")
+}
@@ -0,0 +1,263 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/shibukawa/snapsql/markdownparser"
+	"github.com/shibukawa/snapsql/testrunner"
+	"github.com/shibukawa/snapsql/testrunner/fixtureexecutor"
+)
+
+var (
+	ErrFixtureDataFileNeedsTable = errors.New("fixture data files need exactly one --table")
+	ErrNoFixturesExported        = errors.New("no fixtures matched")
+)
+
+// FixturesCmd groups commands that work with test fixtures
+type FixturesCmd struct {
+	Export FixturesExportCmd `cmd:"" help:"Convert test fixtures into SQL that seeds other databases"`
+}
+
+// FixturesExportCmd converts the fixtures of markdown tests and fixture data files into SQL statements.
+type FixturesExportCmd struct {
+	Table      []string `help:"Export only fixtures of these tables (repeatable)"`
+	Format     string   `help:"Output format" default:"sql" enum:"sql"`
+	Dialect    string   `help:"Target SQL dialect (defaults to the config dialect)"`
+	RunPattern string   `help:"Export only test cases matching the regular expression" short:"r"`
+	Output     string   `short:"o" help:"Output file (defaults to stdout)" type:"path"`
+	Paths      []string `arg:"" optional:"" name:"path" help:"Markdown tests, directories, or YAML/JSON/CSV/TSV fixture files (defaults to the current directory)"`
+}
+
+// fixtureSource is the fixture list of one test case or data file, exported as one block
+type fixtureSource struct {
+	label    string
+	baseDir  string
+	fixtures []markdownparser.TableFixture
+}
+
+func (cmd *FixturesExportCmd) Run(ctx *Context) error {
+	config, err := LoadConfig(ctx.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	dialect := config.Dialect
+	if cmd.Dialect != "" {
+		dialect, err = parseDialectName(cmd.Dialect)
+		if err != nil {
+			return err
+		}
+	}
+
+	var runPattern *regexp.Regexp
+	if cmd.RunPattern != "" {
+		runPattern, err = regexp.Compile(cmd.RunPattern)
+		if err != nil {
+			return fmt.Errorf("invalid run pattern: %w", err)
+		}
+	}
+
+	paths := cmd.Paths
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	var sources []fixtureSource
+
+	for _, path := range paths {
+		found, err := cmd.collectSources(path, runPattern)
+		if err != nil {
+			return err
+		}
+
+		sources = append(sources, found...)
+	}
+
+	executor := fixtureexecutor.NewExecutor(nil, dialect, loadRuntimeTables(ctx))
+
+	var b strings.Builder
+
+	exported := 0
+
+	for _, source := range sources {
+		fixtures := cmd.filterTables(source.fixtures)
+		if len(fixtures) == 0 {
+			continue
+		}
+
+		executor.SetBaseDir(source.baseDir)
+
+		script, err := executor.ExportFixturesSQL(fixtures)
+		if err != nil {
+			return fmt.Errorf("%s: %w", source.label, err)
+		}
+
+		fmt.Fprintf(&b, "-- %s\n%s\n", source.label, script)
+
+		exported++
+	}
+
+	if exported == 0 {
+		return ErrNoFixturesExported
+	}
+
+	if cmd.Output == "" {
+		fmt.Print(b.String())
+		return nil
+	}
+
+	if err := os.WriteFile(cmd.Output, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", cmd.Output, err)
+	}
+
+	if !ctx.Quiet {
+		color.Green("Exported %d fixture sets to %s", exported, cmd.Output)
+	}
+
+	return nil
+}
+
+func (cmd *FixturesExportCmd) collectSources(path string, runPattern *regexp.Regexp) ([]fixtureSource, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access %s: %w", path, err)
+	}
+
+	if !info.IsDir() {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml", ".json", ".csv", ".tsv":
+			return cmd.dataFileSource(path)
+		}
+
+		return markdownFixtureSources(path, runPattern)
+	}
+
+	var files []string
+
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			name := d.Name()
+			if p != path && (name == "vendor" || name == "node_modules" || strings.HasPrefix(name, ".")) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if testrunner.IsTestMarkdownFile(d.Name()) {
+			files = append(files, p)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(files)
+
+	var sources []fixtureSource
+
+	for _, file := range files {
+		found, err := markdownFixtureSources(file, runPattern)
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, found...)
+	}
+
+	return sources, nil
+}
+
+// dataFileSource wraps a standalone fixture data file; the file does not name its table
+func (cmd *FixturesExportCmd) dataFileSource(path string) ([]fixtureSource, error) {
+	if len(cmd.Table) != 1 {
+		return nil, fmt.Errorf("%w: %s", ErrFixtureDataFileNeedsTable, path)
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return []fixtureSource{{
+		label:   filepath.ToSlash(path),
+		baseDir: filepath.Dir(abs),
+		fixtures: []markdownparser.TableFixture{{
+			TableName:    cmd.Table[0],
+			Strategy:     markdownparser.ClearInsert,
+			ExternalFile: filepath.Base(abs),
+		}},
+	}}, nil
+}
+
+// markdownFixtureSources returns the Setup fixtures of a markdown test and the fixtures of its test cases
+func markdownFixtureSources(path string, runPattern *regexp.Regexp) ([]fixtureSource, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	doc, err := markdownparser.Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	label := filepath.ToSlash(path)
+	baseDir := filepath.Dir(path)
+
+	var sources []fixtureSource
+
+	for _, tc := range doc.TestCases {
+		if runPattern != nil && !runPattern.MatchString(tc.Name) {
+			continue
+		}
+
+		if len(tc.Fixtures) == 0 {
+			continue
+		}
+
+		sources = append(sources, fixtureSource{label: label + ": " + tc.Name, baseDir: baseDir, fixtures: tc.Fixtures})
+	}
+
+	// Setup rows are shared by the file's test cases, so they are exported with any of them
+	if doc.Setup != nil && len(doc.Setup.Fixtures) > 0 && (runPattern == nil || len(sources) > 0) {
+		setup := fixtureSource{label: label + " (setup)", baseDir: baseDir, fixtures: doc.Setup.Fixtures}
+		sources = append([]fixtureSource{setup}, sources...)
+	}
+
+	return sources, nil
+}
+
+func (cmd *FixturesExportCmd) filterTables(fixtures []markdownparser.TableFixture) []markdownparser.TableFixture {
+	if len(cmd.Table) == 0 {
+		return fixtures
+	}
+
+	var filtered []markdownparser.TableFixture
+
+	for _, fixture := range fixtures {
+		for _, table := range cmd.Table {
+			if strings.EqualFold(fixture.TableName, table) {
+				filtered = append(filtered, fixture)
+				break
+			}
+		}
+	}
+
+	return filtered
+}
@@ -17,11 +17,14 @@ import (
 	"github.com/fatih/color"
 	"github.com/goccy/go-yaml"
 	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/gencache"
 	"github.com/shibukawa/snapsql/intermediate"
 	"github.com/shibukawa/snapsql/langs/gogen"
 	"github.com/shibukawa/snapsql/langs/mockgen"
+	"github.com/shibukawa/snapsql/langs/openapigen"
 	"github.com/shibukawa/snapsql/langs/pygen"
 	"github.com/shibukawa/snapsql/markdownparser"
+	"github.com/shibukawa/snapsql/parser"
 )
 
 // GenerateCmd represents the generate command
@@ -31,9 +34,23 @@ type GenerateCmd struct {
 	Package  string   `help:"Package name (language-specific)"`
 	Const    []string `help:"Constant definition files"`
 	Validate bool     `help:"Validate templates before generation"`
+	NoCache  bool     `help:"Regenerate every template, ignoring the .snapsql-cache manifest"`
+	Check    bool     `help:"Regenerate in memory and fail when generated files on disk are stale, without writing anything"`
+
+	WorkspaceFlags `embed:""`
 }
 
 func (g *GenerateCmd) Run(ctx *Context) error {
+	if g.Workspace {
+		g.Const = absolutePaths(g.Const)
+
+		return runWorkspace(ctx, g.WorkspaceFlags, "generate", g.run)
+	}
+
+	return g.run(ctx)
+}
+
+func (g *GenerateCmd) run(ctx *Context) error {
 	// Auto-detect local snapsql.yaml when --config not provided so that
 	// running `snapsql generate` inside examples/kanban updates examples/kanban/generated/*
 	if ctx.Config == "" {
@@ -95,13 +112,115 @@ func (g *GenerateCmd) Run(ctx *Context) error {
 		color.Blue("Generating files from %s", inputPath)
 	}
 
+	if g.Check {
+		// Every template is regenerated and compared; the cache manifest is left untouched
+		ctx.generatedFiles = newGeneratedFiles()
+		defer func() { ctx.generatedFiles = nil }()
+	} else if !g.NoCache {
+		cache, err := loadGenerationCache(ctx, constantFiles, runtimeTables)
+		if err != nil {
+			return err
+		}
+
+		ctx.generationCache = cache
+		defer func() { ctx.generationCache = nil }()
+	}
+
+	ctx.templateSources = map[string]string{}
+	defer func() { ctx.templateSources = nil }()
+
 	// If specific language is requested, generate only that
 	if g.Lang != "" {
-		return g.generateSpecificLanguage(ctx, config, inputPath, constantFiles, runtimeTables)
+		err = g.generateSpecificLanguage(ctx, config, inputPath, constantFiles, runtimeTables)
+	} else {
+		// Generate all configured languages
+		err = g.generateAllLanguages(ctx, config, inputPath, constantFiles, runtimeTables)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if g.Check {
+		return reportStaleFiles(ctx.generatedFiles)
+	}
+
+	return saveGenerationCache(ctx, inputPath)
+}
+
+// reportStaleFiles prints the files that "snapsql generate" would change
+func reportStaleFiles(files *generatedFiles) error {
+	stale := files.Stale()
+	if len(stale) == 0 {
+		color.Green("Generated files are up to date")
+		return nil
+	}
+
+	color.Red("%d generated file(s) are stale; run snapsql generate and commit the result:", len(stale))
+	files.WriteSummary(os.Stderr)
+
+	return fmt.Errorf("%w: %d file(s)", ErrGeneratedFilesStale, len(stale))
+}
+
+// loadGenerationCache loads the .snapsql-cache manifest next to the configuration file.
+// Templates are regenerated when the configuration, constants, schema or snapsql build changed.
+func loadGenerationCache(ctx *Context, constantFiles []string, tableCatalog map[string]*snapsql.TableInfo) (*gencache.Manifest, error) {
+	dir := "."
+	if ctx.Config != "" {
+		dir = filepath.Dir(ctx.Config)
+	}
+
+	cache, err := gencache.Load(filepath.Join(dir, gencache.FileName))
+	if err != nil {
+		return nil, err
+	}
+
+	inputs := [][]byte{[]byte(gencache.BuildFingerprint())}
+
+	for _, file := range append([]string{ctx.Config}, constantFiles...) {
+		if file == "" {
+			continue
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file, err)
+		}
+
+		inputs = append(inputs, []byte(file), data)
+	}
+
+	schemaHash, err := gencache.HashValue(tableCatalog)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.SetEnvironment(gencache.Hash(append(inputs, []byte(schemaHash))...))
+
+	return cache, nil
+}
+
+// saveGenerationCache removes the outputs of deleted templates and writes the manifest.
+// Pruning only happens when the whole input directory was processed.
+func saveGenerationCache(ctx *Context, inputPath string) error {
+	if ctx.generationCache == nil {
+		return nil
+	}
+
+	if isDirectory(inputPath) {
+		removed, err := ctx.generationCache.Prune()
+		if err != nil {
+			return fmt.Errorf("failed to remove outputs of deleted templates: %w", err)
+		}
+
+		if ctx.Verbose {
+			for _, file := range removed {
+				color.Yellow("Removed: %s", file)
+			}
+		}
 	}
 
-	// Generate all configured languages
-	return g.generateAllLanguages(ctx, config, inputPath, constantFiles, runtimeTables)
+	return ctx.generationCache.Save()
 }
 
 // generateAllLanguages generates files for all configured languages
@@ -188,15 +307,39 @@ func (g *GenerateCmd) generateAllLanguages(ctx *Context, config *snapsql.Config,
 
 // generateForLanguage generates files for a specific language/generator
 func generateForLanguage(lang string, generator snapsql.GeneratorConfig, intermediateFiles []string, ctx *Context) error {
+	// Shared response types depend on every template, so they are part of the generator configuration
+	var sharedResponseTypes map[string]string
+
+	hashed := any(generator)
+
+	if share, _ := generator.Settings["share_response_types"].(bool); share && lang == "go" {
+		shared, err := loadSharedResponseTypes(intermediateFiles, ctx)
+		if err != nil {
+			return err
+		}
+
+		sharedResponseTypes = shared
+		hashed = []any{generator, shared}
+	}
+
+	generatorHash, err := gencache.HashValue(hashed)
+	if err != nil {
+		return err
+	}
+
+	ctx.generationCache.SetGenerator(lang, generatorHash)
+
 	switch lang {
 	case "json":
 		// JSON generation is handled in the main loop, nothing to do here
 		return nil
 	case "go":
 		// Use built-in Go generator
-		return generateGoFiles(generator, intermediateFiles, ctx)
+		return generateGoFiles(generator, intermediateFiles, sharedResponseTypes, ctx)
 	case "mock":
 		return generateMockFiles(generator, intermediateFiles, ctx)
+	case "openapi":
+		return generateOpenAPIFile(generator, intermediateFiles, ctx)
 	case "typescript":
 		// Use external plugin if available, otherwise show not implemented message
 		_, err := exec.LookPath("snapsql-gen-typescript")
@@ -221,8 +364,45 @@ func generateForLanguage(lang string, generator snapsql.GeneratorConfig, interme
 	}
 }
 
+// loadSharedResponseTypes decodes every intermediate file and returns the response struct each
+// function shares with templates of the same response shape (share_response_types)
+func loadSharedResponseTypes(intermediateFiles []string, ctx *Context) (map[string]string, error) {
+	formats, err := loadIntermediateFormats(intermediateFiles, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := LoadConfig(ctx.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	return gogen.ShareResponseTypes(formats, config.TypeMappings), nil
+}
+
+// loadIntermediateFormats decodes every intermediate file
+func loadIntermediateFormats(intermediateFiles []string, ctx *Context) ([]*intermediate.IntermediateFormat, error) {
+	formats := make([]*intermediate.IntermediateFormat, 0, len(intermediateFiles))
+
+	for _, intermediateFile := range intermediateFiles {
+		data, err := ctx.generatedFiles.ReadFile(intermediateFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read intermediate file %s: %w", intermediateFile, err)
+		}
+
+		format, err := intermediate.Decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse intermediate file %s: %w", intermediateFile, err)
+		}
+
+		formats = append(formats, format)
+	}
+
+	return formats, nil
+}
+
 // generateGoFiles generates Go files using the built-in generator
-func generateGoFiles(generator snapsql.GeneratorConfig, intermediateFiles []string, ctx *Context) error {
+func generateGoFiles(generator snapsql.GeneratorConfig, intermediateFiles []string, sharedResponseTypes map[string]string, ctx *Context) error {
 	// Load config to get dialect
 	config, err := LoadConfig(ctx.Config)
 	if err != nil {
@@ -245,52 +425,335 @@ func generateGoFiles(generator snapsql.GeneratorConfig, intermediateFiles []stri
 		goGen.PackageName = gogen.InferPackageNameFromPath(outputPath)
 	}
 
+	if batchSize, ok := intSetting(generator.Settings, "batch_size"); ok {
+		goGen.BatchSize = batchSize
+	}
+
+	if tracing, ok := generator.Settings["tracing"].(bool); ok {
+		goGen.Tracing = tracing
+	}
+
+	if transactions, ok := generator.Settings["transactions"].(bool); ok {
+		goGen.Transactions = transactions
+	}
+
+	if nullableStyle, ok := generator.Settings["nullable_style"].(string); ok {
+		goGen.NullableStyle = nullableStyle
+	}
+
+	if hierarchyMode, ok := generator.Settings["hierarchy_mode"].(string); ok {
+		goGen.HierarchyMode = hierarchyMode
+	}
+
+	switch queryComment := generator.Settings["query_comment"].(type) {
+	case bool:
+		if queryComment {
+			goGen.QueryComment = gogen.DefaultQueryComment
+		}
+	case string:
+		goGen.QueryComment = queryComment
+	}
+
+	goGen.RedactParams = config.QueryLog.Redact
+	goGen.SharedResponseTypes = sharedResponseTypes
+	goGen.TypeMappings = config.TypeMappings
+	goGen.WhereGuard = config.WhereGuard
+	goGen.Execution = config.Execution
+
+	mockHelpers, _ := generator.Settings["mock_helpers"].(bool)
+	writeSourceMap, _ := generator.Settings["source_map"].(bool)
+	goGen.LineDirectives, _ = generator.Settings["line_directives"].(bool)
+
 	// Process each intermediate file
 	for _, intermediateFile := range intermediateFiles {
+		// Skip templates whose intermediate file and generator settings are unchanged
+		if ctx.generationCache.UpToDate("go", intermediateFile) {
+			continue
+		}
+
 		// Read intermediate format
-		data, err := os.ReadFile(intermediateFile)
+		data, err := ctx.generatedFiles.ReadFile(intermediateFile)
 		if err != nil {
 			return fmt.Errorf("failed to read intermediate file %s: %w", intermediateFile, err)
 		}
 
-		var format intermediate.IntermediateFormat
-		if err := json.Unmarshal(data, &format); err != nil {
+		format, err := intermediate.Decode(data)
+		if err != nil {
 			return fmt.Errorf("failed to parse intermediate file %s: %w", intermediateFile, err)
 		}
 
+		// Determine output file path
+		outputDir := generator.Output
+		if outputDir == "" {
+			outputDir = "./generated/go"
+		}
+
+		// Generate output file name
+		baseName := strings.TrimSuffix(filepath.Base(intermediateFile), filepath.Ext(intermediateFile))
+		outputFile := filepath.Join(outputDir, baseName+".go")
+
 		// Set format and dialect
-		goGen.Format = &format
+		goGen.Format = format
 		goGen.Dialect = config.Dialect
+		goGen.OutputFile = baseName + ".go"
+		goGen.SourcePath = templateSourcePath(ctx, intermediateFile, outputDir)
 
 		// Generate Go code
 		var output strings.Builder
-		if err := goGen.Generate(&output); err != nil {
-			return fmt.Errorf("failed to generate Go code for %s: %w", intermediateFile, err)
-		}
 
-		// Determine output file path
-		outputDir := generator.Output
-		if outputDir == "" {
-			outputDir = "./generated/go"
+		sourceMap, err := goGen.GenerateWithSourceMap(&output)
+		if err != nil {
+			return fmt.Errorf("failed to generate Go code for %s: %w", intermediateFile, err)
 		}
 
 		// Create output directory if it doesn't exist
-		if err := os.MkdirAll(outputDir, 0755); err != nil {
+		if err := ctx.generatedFiles.MkdirAll(outputDir, 0755); err != nil {
 			return fmt.Errorf("failed to create output directory %s: %w", outputDir, err)
 		}
 
-		// Generate output file name
-		baseName := strings.TrimSuffix(filepath.Base(intermediateFile), ".json")
-		outputFile := filepath.Join(outputDir, baseName+".go")
-
 		// Write Go code to file
-		if err := os.WriteFile(outputFile, []byte(output.String()), 0644); err != nil {
+		if err := ctx.generatedFiles.WriteFile(outputFile, []byte(output.String()), 0644); err != nil {
 			return fmt.Errorf("failed to write Go file %s: %w", outputFile, err)
 		}
 
 		if ctx.Verbose {
 			color.Green("Generated: %s", outputFile)
 		}
+
+		outputs := []string{outputFile}
+
+		if writeSourceMap {
+			mapFile := outputFile + ".map"
+
+			mapData, err := json.MarshalIndent(sourceMap, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal source map for %s: %w", intermediateFile, err)
+			}
+
+			if err := ctx.generatedFiles.WriteFile(mapFile, append(mapData, '\n'), 0644); err != nil {
+				return fmt.Errorf("failed to write source map %s: %w", mapFile, err)
+			}
+
+			if ctx.Verbose {
+				color.Green("Generated: %s", mapFile)
+			}
+
+			outputs = append(outputs, mapFile)
+		}
+
+		if mockHelpers {
+			var helper strings.Builder
+			if err := goGen.GenerateMockHelper(&helper); err != nil {
+				return fmt.Errorf("failed to generate mock helper for %s: %w", intermediateFile, err)
+			}
+
+			helperFile := filepath.Join(outputDir, baseName+"_mock_test.go")
+			if err := ctx.generatedFiles.WriteFile(helperFile, []byte(helper.String()), 0644); err != nil {
+				return fmt.Errorf("failed to write mock helper %s: %w", helperFile, err)
+			}
+
+			if ctx.Verbose {
+				color.Green("Generated: %s", helperFile)
+			}
+
+			outputs = append(outputs, helperFile)
+		}
+
+		ctx.generationCache.RecordOutputs("go", intermediateFile, outputs...)
+	}
+
+	if err := writeGoEnums(goGen, generator.Output, intermediateFiles, ctx); err != nil {
+		return err
+	}
+
+	if goGen.Transactions {
+		if err := writeGoTxHelper(goGen, generator.Output, ctx); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeGoEnums writes the Go types of the database enums used by any template of the package
+func writeGoEnums(goGen *gogen.Generator, outputDir string, intermediateFiles []string, ctx *Context) error {
+	formats, err := loadIntermediateFormats(intermediateFiles, ctx)
+	if err != nil {
+		return err
+	}
+
+	enums := gogen.CollectEnums(formats)
+	if len(enums) == 0 {
+		return nil
+	}
+
+	if outputDir == "" {
+		outputDir = "./generated/go"
+	}
+
+	var output strings.Builder
+	if err := goGen.GenerateEnums(&output, enums); err != nil {
+		return fmt.Errorf("failed to generate enums: %w", err)
+	}
+
+	if err := ctx.generatedFiles.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", outputDir, err)
+	}
+
+	enumsFile := filepath.Join(outputDir, gogen.EnumsFileName)
+	if err := ctx.generatedFiles.WriteFile(enumsFile, []byte(output.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write enums %s: %w", enumsFile, err)
+	}
+
+	if ctx.Verbose {
+		color.Green("Generated: %s", enumsFile)
+	}
+
+	return nil
+}
+
+// writeGoTxHelper writes the per-package RunInTx helper next to the generated functions
+func writeGoTxHelper(goGen *gogen.Generator, outputDir string, ctx *Context) error {
+	if outputDir == "" {
+		outputDir = "./generated/go"
+	}
+
+	var helper strings.Builder
+	if err := goGen.GenerateTxHelper(&helper); err != nil {
+		return fmt.Errorf("failed to generate transaction helper: %w", err)
+	}
+
+	if err := ctx.generatedFiles.MkdirAll(outputDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", outputDir, err)
+	}
+
+	helperFile := filepath.Join(outputDir, gogen.TxHelperFileName)
+	if err := ctx.generatedFiles.WriteFile(helperFile, []byte(helper.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write transaction helper %s: %w", helperFile, err)
+	}
+
+	if ctx.Verbose {
+		color.Green("Generated: %s", helperFile)
+	}
+
+	return nil
+}
+
+// recordTemplateSource remembers the template an intermediate file was generated from
+func recordTemplateSource(ctx *Context, intermediateFile, templateFile string) {
+	if ctx.templateSources != nil {
+		ctx.templateSources[intermediateFile] = templateFile
+	}
+}
+
+// templateSourcePath returns the template of an intermediate file relative to outputDir, as
+// referenced from source maps and //line directives written into outputDir
+func templateSourcePath(ctx *Context, intermediateFile, outputDir string) string {
+	source, ok := ctx.templateSources[intermediateFile]
+	if !ok {
+		return ""
+	}
+
+	absSource, err := filepath.Abs(source)
+	if err != nil {
+		return filepath.ToSlash(source)
+	}
+
+	absOutputDir, err := filepath.Abs(outputDir)
+	if err != nil {
+		return filepath.ToSlash(absSource)
+	}
+
+	rel, err := filepath.Rel(absOutputDir, absSource)
+	if err != nil {
+		return filepath.ToSlash(absSource)
+	}
+
+	return filepath.ToSlash(rel)
+}
+
+// intSetting reads an integer generator setting; YAML decodes numbers as int64, uint64 or float64.
+func intSetting(settings map[string]any, key string) (int, bool) {
+	switch v := settings[key].(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case uint64:
+		return int(v), true
+	case float64:
+		return int(v), true
+	default:
+		return 0, false
+	}
+}
+
+// generateOpenAPIFile writes one OpenAPI document containing the schemas of all queries.
+// The output may be a file path (.yaml, .yml or .json) or a directory.
+func generateOpenAPIFile(generator snapsql.GeneratorConfig, intermediateFiles []string, ctx *Context) error {
+	gen := &openapigen.Generator{}
+	if title, ok := generator.Settings["title"].(string); ok {
+		gen.Title = title
+	}
+
+	if version, ok := generator.Settings["version"].(string); ok {
+		gen.Version = version
+	}
+
+	if format, ok := generator.Settings["format"].(string); ok {
+		gen.Format = format
+	}
+
+	outputFile := generator.Output
+	if outputFile == "" {
+		outputFile = openapigen.DefaultOutputPath
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(outputFile)); ext {
+	case ".json":
+		gen.Format = "json"
+	case ".yaml", ".yml":
+		gen.Format = "yaml"
+	default:
+		name := "openapi.yaml"
+		if strings.EqualFold(gen.Format, "json") {
+			name = "openapi.json"
+		}
+
+		outputFile = filepath.Join(outputFile, name)
+	}
+
+	for _, intermediateFile := range intermediateFiles {
+		data, err := ctx.generatedFiles.ReadFile(intermediateFile)
+		if err != nil {
+			return fmt.Errorf("failed to read intermediate file %s: %w", intermediateFile, err)
+		}
+
+		format, err := intermediate.Decode(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse intermediate file %s: %w", intermediateFile, err)
+		}
+
+		if err := gen.Add(format); err != nil {
+			return fmt.Errorf("failed to add schemas for %s: %w", intermediateFile, err)
+		}
+	}
+
+	var output bytes.Buffer
+	if err := gen.Generate(&output); err != nil {
+		return fmt.Errorf("failed to generate OpenAPI document: %w", err)
+	}
+
+	if err := ctx.generatedFiles.MkdirAll(filepath.Dir(outputFile), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory %s: %w", filepath.Dir(outputFile), err)
+	}
+
+	if err := ctx.generatedFiles.WriteFile(outputFile, output.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write OpenAPI file %s: %w", outputFile, err)
+	}
+
+	if ctx.Verbose {
+		color.Green("Generated: %s", outputFile)
 	}
 
 	return nil
@@ -311,7 +774,7 @@ func generatePythonFiles(generator snapsql.GeneratorConfig, intermediateFiles []
 	}
 
 	// Create output directory if it doesn't exist
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
+	if err := ctx.generatedFiles.MkdirAll(outputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory %s: %w", outputDir, err)
 	}
 
@@ -336,18 +799,35 @@ func generatePythonFiles(generator snapsql.GeneratorConfig, intermediateFiles []
 	// Process each intermediate file
 	for _, intermediateFile := range intermediateFiles {
 		// Read intermediate format
-		data, err := os.ReadFile(intermediateFile)
+		data, err := ctx.generatedFiles.ReadFile(intermediateFile)
 		if err != nil {
 			return fmt.Errorf("failed to read intermediate file %s: %w", intermediateFile, err)
 		}
 
-		var format intermediate.IntermediateFormat
-		if err := json.Unmarshal(data, &format); err != nil {
+		format, err := intermediate.Decode(data)
+		if err != nil {
 			return fmt.Errorf("failed to parse intermediate file %s: %w", intermediateFile, err)
 		}
 
+		// Generate output file name
+		baseName := strings.TrimSuffix(filepath.Base(intermediateFile), filepath.Ext(intermediateFile))
+		outputFile := filepath.Join(outputDir, baseName+".py")
+
+		// Track module and function name for __init__.py, including unchanged modules
+		generatedModules = append(generatedModules, struct {
+			moduleName   string
+			functionName string
+		}{
+			moduleName:   baseName,
+			functionName: format.FunctionName,
+		})
+
+		if ctx.generationCache.UpToDate("python", intermediateFile) {
+			continue
+		}
+
 		// Create Python generator with options
-		pyGen := pygen.New(&format,
+		pyGen := pygen.New(format,
 			pygen.WithPackageName(packageName),
 			pygen.WithDialect(config.Dialect),
 			pygen.WithOutputPath(outputDir),
@@ -360,23 +840,12 @@ func generatePythonFiles(generator snapsql.GeneratorConfig, intermediateFiles []
 			return fmt.Errorf("failed to generate Python code for %s: %w", intermediateFile, err)
 		}
 
-		// Generate output file name
-		baseName := strings.TrimSuffix(filepath.Base(intermediateFile), ".json")
-		outputFile := filepath.Join(outputDir, baseName+".py")
-
 		// Write Python code to file
-		if err := os.WriteFile(outputFile, []byte(output.String()), 0644); err != nil {
+		if err := ctx.generatedFiles.WriteFile(outputFile, []byte(output.String()), 0644); err != nil {
 			return fmt.Errorf("failed to write Python file %s: %w", outputFile, err)
 		}
 
-		// Track module and function name for __init__.py
-		generatedModules = append(generatedModules, struct {
-			moduleName   string
-			functionName string
-		}{
-			moduleName:   baseName,
-			functionName: format.FunctionName,
-		})
+		ctx.generationCache.RecordOutputs("python", intermediateFile, outputFile)
 
 		if ctx.Verbose {
 			color.Green("Generated: %s", outputFile)
@@ -390,7 +859,7 @@ func generatePythonFiles(generator snapsql.GeneratorConfig, intermediateFiles []
 	}
 
 	runtimeFile := filepath.Join(outputDir, "snapsql_runtime.py")
-	if err := os.WriteFile(runtimeFile, []byte(runtimeCode), 0644); err != nil {
+	if err := ctx.generatedFiles.WriteFile(runtimeFile, []byte(runtimeCode), 0644); err != nil {
 		return fmt.Errorf("failed to write runtime file %s: %w", runtimeFile, err)
 	}
 
@@ -401,7 +870,7 @@ func generatePythonFiles(generator snapsql.GeneratorConfig, intermediateFiles []
 	// Generate __init__.py (ensure runtime symbols are re-exported even if modules are absent)
 	if len(generatedModules) > 0 || len(pygen.RuntimePublicSymbols) > 0 {
 		initFile := filepath.Join(outputDir, "__init__.py")
-		if err := generatePythonInit(initFile, generatedModules, pygen.RuntimePublicSymbols); err != nil {
+		if err := generatePythonInit(ctx.generatedFiles, initFile, generatedModules, pygen.RuntimePublicSymbols); err != nil {
 			return fmt.Errorf("failed to generate __init__.py: %w", err)
 		}
 
@@ -414,7 +883,7 @@ func generatePythonFiles(generator snapsql.GeneratorConfig, intermediateFiles []
 }
 
 // generatePythonInit generates __init__.py that re-exports all functions
-func generatePythonInit(initFile string, modules []struct {
+func generatePythonInit(files *generatedFiles, initFile string, modules []struct {
 	moduleName   string
 	functionName string
 }, runtimeExports []string) error {
@@ -471,7 +940,7 @@ func generatePythonInit(initFile string, modules []struct {
 
 	buf.WriteString("]\n")
 
-	return os.WriteFile(initFile, []byte(buf.String()), 0644)
+	return files.WriteFile(initFile, []byte(buf.String()), 0644)
 }
 
 func pythonSnakeCase(s string) string {
@@ -576,12 +1045,18 @@ func generateMockFiles(generator snapsql.GeneratorConfig, intermediateFiles []st
 		gen.IntermediateRoot = commonAncestorDir(intermediateFiles)
 	}
 
+	if ctx.generatedFiles != nil {
+		gen.FS = ctx.generatedFiles
+	}
+
 	for _, intermediateFile := range intermediateFiles {
 		outputFile, err := gen.Generate(intermediateFile)
 		if err != nil {
 			return err
 		}
 
+		ctx.generationCache.RecordOutputs("mock", intermediateFile, outputFile)
+
 		if ctx.Verbose {
 			color.Green("Generated: %s", outputFile)
 		}
@@ -600,6 +1075,12 @@ func generateMockFiles(generator snapsql.GeneratorConfig, intermediateFiles []st
 func generateWithExternalPlugin(lang string, generator snapsql.GeneratorConfig, intermediateFiles []string, ctx *Context) error {
 	pluginName := "snapsql-gen-" + lang
 
+	// Plugins write their outputs themselves, so --check cannot compare them
+	if ctx.generatedFiles != nil {
+		color.Yellow("Skipping %s in --check mode", pluginName)
+		return nil
+	}
+
 	// Verify plugin availability
 	if _, err := exec.LookPath(pluginName); err != nil {
 		return fmt.Errorf("%w: '%s'", ErrPluginNotFound, pluginName)
@@ -611,7 +1092,7 @@ func generateWithExternalPlugin(lang string, generator snapsql.GeneratorConfig,
 		outputDir = "./generated/" + lang
 	}
 
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
+	if err := ctx.generatedFiles.MkdirAll(outputDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory %s: %w", outputDir, err)
 	}
 
@@ -641,6 +1122,18 @@ func generateWithExternalPlugin(lang string, generator snapsql.GeneratorConfig,
 			return fmt.Errorf("failed to read intermediate file %s: %w", intermediateFile, err)
 		}
 
+		// Plugins read JSON from stdin regardless of the configured intermediate encoding
+		if intermediate.IsBinary(data) {
+			format, err := intermediate.FromBinary(data)
+			if err != nil {
+				return fmt.Errorf("failed to parse intermediate file %s: %w", intermediateFile, err)
+			}
+
+			if data, err = format.MarshalJSON(); err != nil {
+				return fmt.Errorf("failed to convert intermediate file %s to JSON: %w", intermediateFile, err)
+			}
+		}
+
 		args := append([]string{}, baseArgs...)
 		args = append(args, "--input", intermediateFile)
 
@@ -706,7 +1199,7 @@ func (g *GenerateCmd) generateSpecificLanguage(ctx *Context, config *snapsql.Con
 		// Just generate intermediate files
 		_, err := g.generateIntermediateFiles(ctx, config, inputPath, constantFiles, tableCatalog)
 		return err
-	case "go", "typescript", "java", "python":
+	case "go", "typescript", "java", "python", "openapi":
 		// Generate intermediate files first
 		intermediateFiles, err := g.generateIntermediateFiles(ctx, config, inputPath, constantFiles, tableCatalog)
 		if err != nil {
@@ -781,7 +1274,7 @@ func (g *GenerateCmd) generateIntermediateFiles(ctx *Context, config *snapsql.Co
 	}
 
 	// Ensure output directory exists
-	if err := ensureDir(outputDir); err != nil {
+	if err := ctx.generatedFiles.MkdirAll(outputDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create output directory %s: %w", outputDir, err)
 	}
 
@@ -853,6 +1346,18 @@ func (g *GenerateCmd) processTemplateFile(inputFile, outputDir, inputDir string,
 		return "", fmt.Errorf("failed to read file: %w", err)
 	}
 
+	// Reuse the intermediate file of an unchanged template
+	hash := gencache.Hash(content)
+	if outputFile, ok := ctx.generationCache.Fresh(inputFile, hash); ok {
+		recordTemplateSource(ctx, outputFile, inputFile)
+
+		if ctx.Verbose {
+			color.Cyan("Unchanged: %s", inputFile)
+		}
+
+		return outputFile, nil
+	}
+
 	// Determine file type and process accordingly
 	ext := strings.ToLower(filepath.Ext(inputFile))
 
@@ -887,24 +1392,38 @@ func (g *GenerateCmd) processTemplateFile(inputFile, outputDir, inputDir string,
 
 	// Generate output filename
 	jsonGen := config.Generation.Generators["json"]
+	binaryOutput := jsonGen.Settings["encoding"] == "binary"
+
 	outputFile := g.generateOutputFilename(inputFile, outputDir, inputDir, jsonGen.PreserveHierarchy)
+	if binaryOutput {
+		outputFile = strings.TrimSuffix(outputFile, ".json") + intermediate.BinaryFileExtension
+	}
 
 	// Ensure output directory exists (including subdirectories if preserving hierarchy)
 	outputFileDir := filepath.Dir(outputFile)
-	if err := ensureDir(outputFileDir); err != nil {
+	if err := ctx.generatedFiles.MkdirAll(outputFileDir, 0755); err != nil {
 		return "", err
 	}
 
 	// Write intermediate format to file
-	outputData, err := format.MarshalJSON()
+	var outputData []byte
+	if binaryOutput {
+		outputData, err = format.ToBinary()
+	} else {
+		outputData, err = format.MarshalJSON()
+	}
+
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal intermediate format: %w", err)
 	}
 
-	if err := os.WriteFile(outputFile, outputData, 0644); err != nil {
+	if err := ctx.generatedFiles.WriteFile(outputFile, outputData, 0644); err != nil {
 		return "", fmt.Errorf("failed to write intermediate file: %w", err)
 	}
 
+	ctx.generationCache.RecordIntermediate(inputFile, hash, outputFile)
+	recordTemplateSource(ctx, outputFile, inputFile)
+
 	// Only show output message if verbose mode is enabled
 	if ctx.Verbose {
 		color.Green("Generated: %s", outputFile)
@@ -915,8 +1434,7 @@ func (g *GenerateCmd) processTemplateFile(inputFile, outputDir, inputDir string,
 
 // loadConstants loads constants from configuration and constant files
 func (g *GenerateCmd) loadConstants(config *snapsql.Config, ctx *Context) (map[string]any, error) {
-	_ = config // Config not currently used for constant loading
-	_ = ctx    // Context not currently used for constant loading
+	_ = ctx // Context not currently used for constant loading
 	constants := make(map[string]any)
 
 	// Load constants from files
@@ -935,6 +1453,11 @@ func (g *GenerateCmd) loadConstants(config *snapsql.Config, ctx *Context) (map[s
 		maps.Copy(constants, fileConstants)
 	}
 
+	// Project constants in snapsql.yaml are referenced as const.<name>
+	if config != nil && len(config.Constants) > 0 {
+		constants[parser.ConstNamespace] = config.Constants
+	}
+
 	return constants, nil
 }
 
@@ -997,11 +1520,6 @@ func findTemplateFiles(inputDir string) ([]string, error) {
 	return files, err
 }
 
-// ensureDir creates directory if it doesn't exist
-func ensureDir(dir string) error {
-	return os.MkdirAll(dir, 0755)
-}
-
 // isDirectory checks if path is a directory
 func isDirectory(path string) bool {
 	info, err := os.Stat(path)
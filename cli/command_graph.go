@@ -0,0 +1,119 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/shibukawa/snapsql/depgraph"
+)
+
+// ErrGraphTargetConflict is returned when both --table and --column are given
+var ErrGraphTargetConflict = errors.New("--table and --column cannot be combined")
+
+// GraphCmd represents the graph command
+type GraphCmd struct {
+	Input  string   `short:"i" help:"Input directory (defaults to input_dir in config)" type:"path"`
+	Files  []string `arg:"" help:"Specific template files" optional:""`
+	Const  []string `help:"Constant definition files"`
+	Format string   `help:"Output format" default:"json" enum:"json,dot"`
+	Output string   `short:"o" help:"Write the graph to a file instead of stdout" type:"path"`
+	Table  string   `help:"Show only the templates that touch this table"`
+	Column string   `help:"Show only the templates affected by a change of this column (table.column)"`
+}
+
+func (cmd *GraphCmd) Run(ctx *Context) error {
+	if cmd.Table != "" && cmd.Column != "" {
+		return ErrGraphTargetConflict
+	}
+
+	config, err := LoadConfig(ctx.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	constants, err := (&GenerateCmd{Const: cmd.Const}).loadConstants(config, ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load constants: %w", err)
+	}
+
+	files := cmd.Files
+	if len(files) == 0 {
+		inputDir := cmd.Input
+		if inputDir == "" {
+			inputDir = config.InputDir
+			if ctx.Config != "" && inputDir != "" && !filepath.IsAbs(inputDir) {
+				if abs, err := filepath.Abs(ctx.Config); err == nil {
+					inputDir = filepath.Join(filepath.Dir(abs), inputDir)
+				}
+			}
+		}
+
+		files, err = findTemplateFiles(inputDir)
+		if err != nil {
+			return fmt.Errorf("failed to find template files: %w", err)
+		}
+	}
+
+	// Show paths relative to the working directory when possible
+	if wd, err := os.Getwd(); err == nil {
+		for i, file := range files {
+			if rel, err := filepath.Rel(wd, file); err == nil && !strings.HasPrefix(rel, "..") {
+				files[i] = rel
+			}
+		}
+	}
+
+	graph, err := depgraph.Build(files, depgraph.Options{
+		Constants: constants,
+		Tables:    loadRuntimeTables(ctx),
+		Config:    config,
+	})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case cmd.Table != "":
+		graph = graph.Affected(cmd.Table, "")
+	case cmd.Column != "":
+		table, column, err := depgraph.ParseColumnTarget(cmd.Column)
+		if err != nil {
+			return err
+		}
+
+		graph = graph.Affected(table, column)
+	}
+
+	var w io.Writer = os.Stdout
+
+	if cmd.Output != "" {
+		f, err := os.Create(cmd.Output)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	if cmd.Format == "dot" {
+		err = depgraph.WriteDOT(w, graph)
+	} else {
+		err = depgraph.WriteJSON(w, graph)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write graph: %w", err)
+	}
+
+	if ctx.Verbose {
+		color.Blue("Graph: %d template(s), %d table(s)", len(graph.Templates), len(graph.Tables()))
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/shibukawa/snapsql/lint"
+)
+
+// ErrLintFailed is returned when lint reports at least one error-severity finding
+var ErrLintFailed = errors.New("lint failed")
+
+// LintCmd represents the lint command
+type LintCmd struct {
+	Input  string   `short:"i" help:"Input directory (defaults to input_dir in config)" type:"path"`
+	Files  []string `arg:"" help:"Specific files to lint" optional:""`
+	Const  []string `help:"Constant definition files"`
+	Format string   `help:"Output format" default:"text" enum:"text,json,sarif"`
+	Output string   `short:"o" help:"Write the report to a file instead of stdout" type:"path"`
+	Rule   []string `help:"Override rule severity (id=error|warning|info|off)"`
+
+	WorkspaceFlags `embed:""`
+}
+
+func (cmd *LintCmd) Run(ctx *Context) error {
+	if cmd.Workspace {
+		cmd.Files = absolutePaths(cmd.Files)
+		cmd.Const = absolutePaths(cmd.Const)
+		cmd.Output = absolutePath(cmd.Output)
+
+		return runWorkspace(ctx, cmd.WorkspaceFlags, "lint", cmd.run)
+	}
+
+	return cmd.run(ctx)
+}
+
+func (cmd *LintCmd) run(ctx *Context) error {
+	config, err := LoadConfig(ctx.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	severities := maps.Clone(config.Lint.Rules)
+	if severities == nil {
+		severities = map[string]string{}
+	}
+
+	for _, override := range cmd.Rule {
+		id, sev, err := lint.ParseRuleOverride(override)
+		if err != nil {
+			return err
+		}
+
+		severities[id] = sev
+	}
+
+	constants, err := (&GenerateCmd{Const: cmd.Const}).loadConstants(config, ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load constants: %w", err)
+	}
+
+	linter, err := lint.New(lint.Options{
+		Constants:  constants,
+		Tables:     loadRuntimeTables(ctx),
+		Config:     config,
+		Severities: severities,
+	})
+	if err != nil {
+		return err
+	}
+
+	files := cmd.Files
+	if len(files) == 0 {
+		inputDir := cmd.Input
+		if inputDir == "" {
+			inputDir = config.InputDir
+			if ctx.Config != "" && inputDir != "" && !filepath.IsAbs(inputDir) {
+				if abs, err := filepath.Abs(ctx.Config); err == nil {
+					inputDir = filepath.Join(filepath.Dir(abs), inputDir)
+				}
+			}
+		}
+
+		if ctx.Verbose {
+			color.Blue("Linting templates in %s", inputDir)
+		}
+
+		files, err = findTemplateFiles(inputDir)
+		if err != nil {
+			return fmt.Errorf("failed to find template files: %w", err)
+		}
+	}
+
+	var findings []lint.Finding
+
+	for _, file := range files {
+		result, err := linter.LintFile(file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+
+		findings = append(findings, result...)
+	}
+
+	var w io.Writer = os.Stdout
+
+	if cmd.Output != "" {
+		f, err := os.Create(memberReportPath(cmd.Output, ctx.workspaceMember))
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	switch cmd.Format {
+	case "json":
+		err = lint.WriteJSON(w, findings)
+	case "sarif":
+		err = linter.WriteSARIF(w, findings)
+	default:
+		err = lint.WriteText(w, findings)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write lint report: %w", err)
+	}
+
+	if lint.HasErrors(findings) {
+		return fmt.Errorf("%w: %d finding(s) in %d file(s)", ErrLintFailed, len(findings), len(files))
+	}
+
+	if !ctx.Quiet && cmd.Format == "text" {
+		color.Green("Lint completed: %d file(s), %d finding(s)", len(files), len(findings))
+	}
+
+	return nil
+}
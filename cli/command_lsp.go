@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/shibukawa/snapsql/intermediate"
+	"github.com/shibukawa/snapsql/lsp"
+)
+
+// LspCmd represents the lsp command
+type LspCmd struct {
+	Const []string `help:"Constant definition files"`
+}
+
+// Run starts a language server on stdin/stdout. Nothing else may be written to stdout
+// because it carries the protocol stream.
+func (cmd *LspCmd) Run(ctx *Context) error {
+	quiet := *ctx
+	quiet.Verbose = false
+	quiet.Quiet = true
+
+	config, err := LoadConfig(ctx.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	constants, err := (&GenerateCmd{Const: cmd.Const}).loadConstants(config, &quiet)
+	if err != nil {
+		return fmt.Errorf("failed to load constants: %w", err)
+	}
+
+	server := lsp.NewServer(lsp.Options{
+		Tables:    loadRuntimeTables(&quiet),
+		Constants: constants,
+		Functions: intermediate.TemplateFunctionsFromConfig(config),
+		Version:   "0.1.0",
+	})
+
+	return server.Run(os.Stdin, os.Stdout)
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"github.com/fatih/color"
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/testrunner"
+)
+
+// PerfCmd groups performance baseline commands
+type PerfCmd struct {
+	Baseline PerfBaselineCmd `cmd:"" help:"Run tests and record per-test query times and plan fingerprints as the baseline"`
+}
+
+// PerfBaselineCmd runs the fixture tests and writes the performance baseline file
+// that `snapsql test --perf-check` compares against.
+type PerfBaselineCmd struct {
+	RunPattern string   `help:"Record only tests matching the regular expression" short:"r"`
+	Timeout    string   `help:"Test timeout duration" default:"10m"`
+	Parallel   int      `help:"Number of parallel workers (use 1 for stable timings)" default:"1"`
+	Schema     []string `help:"SQL files or directories to initialize an ephemeral database (repeatable)" short:"s"`
+	Output     string   `short:"o" help:"Baseline file (defaults to performance.baseline.file)" type:"path"`
+	Paths      []string `arg:"" optional:"" name:"path" help:"Optional file or directory paths to limit executed tests"`
+}
+
+func (cmd *PerfBaselineCmd) Run(ctx *Context) error {
+	test := &TestCmd{
+		RunPattern: cmd.RunPattern,
+		Timeout:    cmd.Timeout,
+		Parallel:   cmd.Parallel,
+		Schema:     cmd.Schema,
+		Paths:      cmd.Paths,
+	}
+
+	test.onSummary = func(config *snapsql.Config, summary *testrunner.FixtureTestSummary) error {
+		path := cmd.Output
+		if path == "" {
+			path = config.Performance.Baseline.File
+		}
+
+		baseline := testrunner.NewBaseline(summary, string(config.Dialect))
+		if err := testrunner.WriteBaselineFile(path, baseline); err != nil {
+			return err
+		}
+
+		color.Green("Recorded performance baseline for %d tests in %s", len(baseline.Tests), path)
+
+		return nil
+	}
+
+	return test.run(ctx)
+}
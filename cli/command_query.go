@@ -20,6 +20,7 @@ import (
 	"github.com/shibukawa/snapsql/explain"
 	"github.com/shibukawa/snapsql/intermediate"
 	"github.com/shibukawa/snapsql/intermediate/codegenerator"
+	"github.com/shibukawa/snapsql/langs/snapsqlgo"
 	"github.com/shibukawa/snapsql/markdownparser"
 	"github.com/shibukawa/snapsql/parser"
 	"github.com/shibukawa/snapsql/query"
@@ -38,13 +39,17 @@ var (
 	ErrUnsupportedParamsFormat    = errors.New("unsupported parameters file format")
 	ErrDefaultEnvironmentNotFound = errors.New("default environment not found in config")
 	ErrNoDatabaseConnection       = errors.New("no database connection specified")
+	ErrDriverNotCompiled          = errors.New("database driver is not compiled into this binary")
 	ErrExpressionIndexNotFound    = errors.New("expression index not found")
 	ErrInvalidDialect             = errors.New("invalid dialect")
+	ErrUnknownREPLCommand         = errors.New("unknown command")
+	ErrREPLUsage                  = errors.New("invalid command usage")
+	ErrNoTemplateLoaded           = errors.New("no template loaded (use .load <file>)")
 )
 
 // QueryCmd represents the query command
 type QueryCmd struct {
-	TemplateFile string   `arg:"" help:"SQL template file (.snap.sql or .snap.md)" type:"path"`
+	TemplateFile string   `arg:"" optional:"" help:"SQL template file (.snap.sql or .snap.md); optional with --repl" type:"path"`
 	ParamsFile   string   `short:"P" long:"params" help:"Parameters file (JSON/YAML)" type:"path"`
 	Param        []string `short:"p" long:"param" help:"Individual parameter (key=value format)"`
 	ConstFiles   []string `long:"const" help:"Constant definition files" type:"path"`
@@ -55,11 +60,13 @@ type QueryCmd struct {
 	Timeout               int    `long:"timeout" help:"Query timeout in seconds" default:"30"`
 	Explain               bool   `long:"explain" help:"Show query execution plan"`
 	ExplainAnalyze        bool   `long:"explain-analyze" help:"Show detailed query execution plan with actual execution statistics"`
-	Limit                 int    `long:"limit" help:"Limit number of rows returned"`
+	Limit                 int    `long:"limit" help:"Limit number of rows returned" xor:"limit"`
+	NoLimit               bool   `long:"no-limit" help:"Do not apply the default LIMIT to SELECT queries" xor:"limit"`
 	Offset                int    `long:"offset" help:"Offset for result set"`
 	ExecuteDangerousQuery bool   `long:"execute-dangerous-query" help:"Execute DELETE/UPDATE queries without WHERE clause (dangerous!)"`
 	DryRun                bool   `long:"dry-run" help:"Show generated SQL without executing"`
-	Dialect               string `long:"dialect" help:"SQL dialect for dry-run or when no DB (postgresql|mysql|sqlite|mariadb)"`
+	Dialect               string `long:"dialect" help:"SQL dialect for dry-run or when no DB (postgresql|mysql|sqlite|mariadb|duckdb|clickhouse|cockroach)"`
+	Repl                  bool   `long:"repl" help:"Start an interactive prompt to load templates, set parameters, preview SQL and run queries"`
 }
 
 // Run executes the query command
@@ -70,8 +77,8 @@ func (q *QueryCmd) Run(ctx *Context) error {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	// Verify template file exists
-	if !fileExists(q.TemplateFile) {
+	// Verify template file exists (the REPL can start without one)
+	if (!q.Repl || q.TemplateFile != "") && !fileExists(q.TemplateFile) {
 		return fmt.Errorf("%w: %s", ErrTemplateNotFound, q.TemplateFile)
 	}
 
@@ -111,7 +118,7 @@ func (q *QueryCmd) Run(ctx *Context) error {
 		OutputFile:            q.OutputFile,
 		Explain:               q.Explain,
 		ExplainAnalyze:        q.ExplainAnalyze,
-		Limit:                 q.Limit,
+		Limit:                 q.resolveLimit(config),
 		Offset:                q.Offset,
 		ExecuteDangerousQuery: q.ExecuteDangerousQuery,
 	}
@@ -133,6 +140,10 @@ func (q *QueryCmd) Run(ctx *Context) error {
 		return fmt.Errorf("%w: %s", ErrInvalidOutputFormat, q.Format)
 	}
 
+	if q.Repl {
+		return q.runREPL(ctx, config, params, options, os.Stdin, os.Stdout)
+	}
+
 	// If dry run, just generate SQL and exit (no database connection needed)
 	if q.DryRun {
 		return q.executeDryRun(ctx, params, options)
@@ -147,10 +158,66 @@ func (q *QueryCmd) Run(ctx *Context) error {
 	options.Driver = driver
 	options.ConnectionString = connectionString
 
+	if !ctx.Quiet && !options.Explain {
+		q.warnUnboundedSelect(options, tableMetadata)
+	}
+
 	// Execute query
 	return q.executeQuery(ctx, params, options, slowThreshold, tableMetadata)
 }
 
+// resolveLimit determines the LIMIT applied to SELECT queries that lack one.
+// An explicit --limit wins and --no-limit disables the guard. Otherwise query.limit
+// from the config is used, falling back to query.max_rows.
+func (q *QueryCmd) resolveLimit(config *snapsql.Config) int {
+	switch {
+	case q.Limit > 0:
+		return q.Limit
+	case q.NoLimit:
+		return 0
+	case config.Query.Limit > 0:
+		return config.Query.Limit
+	default:
+		return config.Query.MaxRows
+	}
+}
+
+// warnUnboundedSelect reports how the result set of a SELECT is bounded before it is executed.
+// When no LIMIT applies, tables with a known large expected_rows are listed to highlight the risk.
+func (q *QueryCmd) warnUnboundedSelect(options query.QueryOptions, tableMetadata map[string]explain.TableMetadata) {
+	format, err := query.LoadIntermediateFormat(q.TemplateFile)
+	if err != nil || format.StatementType != "select" || query.HasLimitClause(format) {
+		// Load errors are reported by the execution itself
+		return
+	}
+
+	warn := color.New(color.FgYellow)
+
+	if options.Limit > 0 {
+		if q.Limit <= 0 {
+			warn.Fprintf(os.Stderr, "Applying default LIMIT %d (use --no-limit to fetch all rows)\n", options.Limit)
+		}
+
+		return
+	}
+
+	warn.Fprintln(os.Stderr, "WARNING: SELECT without LIMIT may return a huge result set")
+
+	for _, ref := range format.TableReferences {
+		name := ref.TableName
+		if name == "" {
+			name = ref.Name
+		}
+
+		meta, ok := tableMetadata[strings.ToLower(name)]
+		if !ok || meta.ExpectedRows <= 0 {
+			continue
+		}
+
+		warn.Fprintf(os.Stderr, "  table %s is expected to contain about %d rows\n", name, meta.ExpectedRows)
+	}
+}
+
 // loadParameters loads parameters from file and command line
 func (q *QueryCmd) loadParameters(ctx *Context) (map[string]any, error) {
 	params := make(map[string]any)
@@ -335,6 +402,10 @@ func (q *QueryCmd) getDatabaseConnection(config *snapsql.Config, ctx *Context) (
 		}
 	}
 
+	if err := checkDriverCompiled(driver); err != nil {
+		return "", "", err
+	}
+
 	if ctx.Verbose {
 		color.Blue("Using database driver: %s", driver)
 	}
@@ -355,38 +426,57 @@ func determineDriver(connectionString string) string {
 	if strings.HasPrefix(connectionString, "sqlite://") || strings.HasSuffix(connectionString, ".db") {
 		return normalizeSQLDriverName("sqlite3")
 	}
+
+	if strings.HasSuffix(connectionString, ".duckdb") {
+		return normalizeSQLDriverName("duckdb")
+	}
+
+	if strings.HasPrefix(connectionString, "clickhouse://") {
+		return normalizeSQLDriverName("clickhouse")
+	}
 	// Default to postgres
 	return normalizeSQLDriverName("postgres")
 }
 
-// executeDryRun generates SQL without executing it
-func (q *QueryCmd) executeDryRun(ctx *Context, params map[string]any, options query.QueryOptions) error {
+// renderSQL expands the template with params for the dialect without touching a database.
+// The returned SQL still uses '?' placeholders; args holds the bound values in order.
+func (q *QueryCmd) renderSQL(params map[string]any, dialect snapsql.Dialect) (string, []any, error) {
 	// Load intermediate format
 	format, err := query.LoadIntermediateFormat(q.TemplateFile)
 	if err != nil {
-		return fmt.Errorf("failed to load template: %w", err)
+		return "", nil, fmt.Errorf("failed to load template: %w", err)
 	}
 
 	// Preflight parameter validation
 	if err := query.ValidateParameters(format, params); err != nil {
-		return fmt.Errorf("%w", err)
+		return "", nil, fmt.Errorf("%w", err)
+	}
+
+	optimizedInstructions, err := codegenerator.OptimizeInstructions(format.Instructions, dialect)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to optimize instructions: %w", err)
 	}
 
+	// Build SQL and arguments
+	sql, args, err := q.buildSQLFromOptimized(optimizedInstructions, format, params)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	return sql, args, nil
+}
+
+// executeDryRun generates SQL without executing it
+func (q *QueryCmd) executeDryRun(ctx *Context, params map[string]any, options query.QueryOptions) error {
 	// Determine dialect for dry-run
 	dialect := strings.ToLower(strings.TrimSpace(q.Dialect))
 	if dialect == "" {
 		dialect = q.getDialectFromOptions(options)
 	}
 
-	optimizedInstructions, err := codegenerator.OptimizeInstructions(format.Instructions, snapsql.Dialect(dialect))
+	sql, args, err := q.renderSQL(params, snapsql.Dialect(dialect))
 	if err != nil {
-		return fmt.Errorf("failed to optimize instructions: %w", err)
-	}
-
-	// Build SQL and arguments
-	sql, args, err := q.buildSQLFromOptimized(optimizedInstructions, format, params)
-	if err != nil {
-		return fmt.Errorf("failed to build SQL: %w", err)
+		return err
 	}
 
 	// Format SQL for display (shared with executor)
@@ -497,9 +587,29 @@ func (q *QueryCmd) executeQuery(ctx *Context, params map[string]any, options que
 		q.printPerformanceWarnings(ctx, analyzeEvaluation, result.TableReferences, tableMetadata)
 	}
 
+	if !ctx.Quiet && !options.Explain && q.truncatedByDefaultLimit(options, result) {
+		color.New(color.FgYellow).Fprintf(os.Stderr,
+			"Result truncated to %d rows by the default LIMIT (use --limit or --no-limit to fetch more rows)\n", options.Limit)
+	}
+
 	return nil
 }
 
+// truncatedByDefaultLimit reports whether the result filled the default LIMIT, i.e. rows may have been cut off.
+// Limits given with --limit or written in the template are intentional and never reported.
+func (q *QueryCmd) truncatedByDefaultLimit(options query.QueryOptions, result *query.QueryResult) bool {
+	if q.Limit > 0 || options.Limit <= 0 || result == nil || len(result.Rows) < options.Limit {
+		return false
+	}
+
+	format, err := query.LoadIntermediateFormat(q.TemplateFile)
+	if err != nil {
+		return false
+	}
+
+	return format.StatementType == "select" && !query.HasLimitClause(format)
+}
+
 // getDialectFromOptions determines the dialect from query options
 func (q *QueryCmd) getDialectFromOptions(options query.QueryOptions) string {
 	switch options.Driver {
@@ -509,6 +619,10 @@ func (q *QueryCmd) getDialectFromOptions(options query.QueryOptions) string {
 		return "mysql"
 	case "sqlite3":
 		return "sqlite"
+	case "duckdb":
+		return "duckdb"
+	case "clickhouse":
+		return "clickhouse"
 	default:
 		return "postgresql" // default
 	}
@@ -530,26 +644,35 @@ func (q *QueryCmd) buildSQLFromOptimized(instructions []codegenerator.OptimizedI
 	// Create CEL programs for expressions
 	celPrograms := make(map[int]*cel.Program)
 
-	// Declare variables for CEL: params map + individual keys
-	decls := []cel.EnvOption{cel.Variable("params", cel.MapType(cel.StringType, cel.AnyType))}
+	// Declare variables for CEL: params map + individual keys. Programs are cached per
+	// expression and set of parameter names, so repeated executions skip compilation.
+	names := make([]string, 0, len(paramMap))
 	for k := range paramMap {
-		decls = append(decls, cel.Variable(k, cel.AnyType))
+		names = append(names, k)
 	}
 
-	env, err := cel.NewEnv(decls...)
-	if err != nil {
-		return "", nil, fmt.Errorf("failed to create CEL environment: %w", err)
-	}
+	sort.Strings(names)
 
-	for i, expr := range format.CELExpressions {
-		ast, issues := env.Compile(expr.Expression)
-		if issues.Err() != nil {
-			return "", nil, fmt.Errorf("failed to compile expression %d (%s): %w", i, expr.Expression, issues.Err())
+	newEnv := func() (*cel.Env, error) {
+		decls := []cel.EnvOption{cel.Variable("params", cel.MapType(cel.StringType, cel.AnyType))}
+		for _, name := range names {
+			decls = append(decls, cel.Variable(name, cel.AnyType))
 		}
 
-		program, err := env.Program(ast)
+		env, err := cel.NewEnv(decls...)
 		if err != nil {
-			return "", nil, fmt.Errorf("failed to create program for expression %d: %w", i, err)
+			return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+		}
+
+		return env, nil
+	}
+
+	signature := "cli.QueryCmd:" + strings.Join(names, ",")
+
+	for i, expr := range format.CELExpressions {
+		program, err := snapsqlgo.CompileCEL(signature, expr.Expression, newEnv)
+		if err != nil {
+			return "", nil, fmt.Errorf("expression %d: %w", i, err)
 		}
 
 		celPrograms[i] = &program
@@ -581,13 +704,19 @@ func (q *QueryCmd) buildSQLFromOptimized(instructions []codegenerator.OptimizedI
 				}
 			}
 
-		case "ADD_PARAM":
+		case "ADD_PARAM", codegenerator.OpEmitCursorLimit, codegenerator.OpEmitSystemLimit:
+			if inst.Op == codegenerator.OpEmitCursorLimit && inst.ExprIndex == nil {
+				// Literal page size of cursor pagination
+				builder.WriteString(inst.Value)
+				break
+			}
+
 			if inst.ExprIndex != nil {
 				// Fast path: direct param lookup by expression string
 				if *inst.ExprIndex >= 0 && *inst.ExprIndex < len(format.CELExpressions) {
 					exprStr := format.CELExpressions[*inst.ExprIndex].Expression
 					if v, ok := paramMap[exprStr]; ok {
-						args = append(args, v)
+						args = append(args, query.SystemLimitArg(inst, v))
 						break
 					}
 				}
@@ -605,9 +734,33 @@ func (q *QueryCmd) buildSQLFromOptimized(instructions []codegenerator.OptimizedI
 					return "", nil, fmt.Errorf("failed to evaluate expression %d: %w", *inst.ExprIndex, err)
 				}
 
-				args = append(args, result.Value())
+				args = append(args, query.SystemLimitArg(inst, result.Value()))
+			}
+
+		case codegenerator.OpEmitIdent, codegenerator.OpEmitOrderBy:
+			program, exists := celPrograms[*inst.ExprIndex]
+			if !exists {
+				return "", nil, fmt.Errorf("%w: %d", ErrExpressionIndexNotFound, *inst.ExprIndex)
+			}
+
+			evalParams := map[string]any{"params": paramMap}
+			maps.Copy(evalParams, paramMap)
+
+			result, _, err := (*program).Eval(evalParams)
+			if err != nil {
+				return "", nil, fmt.Errorf("failed to evaluate expression %d: %w", *inst.ExprIndex, err)
 			}
 
+			ident, err := query.AllowedItem(inst.Op, result.Value(), inst.Allowed)
+			if err != nil {
+				return "", nil, err
+			}
+
+			flushDeferred()
+			builder.WriteString(ident)
+
+			hasContentSinceBd = true
+
 		case "EMIT_UNLESS_BOUNDARY":
 			if inst.Value != "" {
 				deferredTokens = append(deferredTokens, inst.Value)
@@ -622,6 +775,9 @@ func (q *QueryCmd) buildSQLFromOptimized(instructions []codegenerator.OptimizedI
 
 			hasContentSinceBd = false
 
+		case codegenerator.OpEmitSystemSoftDelete:
+			builder.WriteString(" " + inst.Value)
+
 		default:
 			// Ignore other ops for dry-run scope
 		}
@@ -0,0 +1,343 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/common/types/ref"
+	"github.com/google/cel-go/common/types/traits"
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/query"
+)
+
+const replHelp = `Commands:
+  .load <file>          Load a template (.snap.sql or .snap.md)
+  .set <name> = <expr>  Set a parameter to the result of a CEL expression
+  .unset <name>         Remove a parameter
+  .params               Show the current parameters
+  .dialect [name]       Show or change the dialect (postgresql|mysql|sqlite|mariadb|duckdb|clickhouse|cockroach)
+  .sql                  Preview the rendered SQL for the current dialect
+  .run                  Execute the template against the database
+  .help                 Show this help
+  .quit, .exit          Leave the REPL
+`
+
+// queryREPL holds the state of an interactive `snapsql query --repl` session.
+// The database connection is opened on the first .run so that templates can
+// be previewed without a reachable database.
+type queryREPL struct {
+	cmd     *QueryCmd
+	ctx     *Context
+	config  *snapsql.Config
+	options query.QueryOptions
+	params  map[string]any
+	dialect string
+	out     io.Writer
+	db      *sql.DB
+}
+
+// runREPL reads commands from in until EOF or .quit and writes results to out.
+// Command errors are printed and do not end the session.
+func (q *QueryCmd) runREPL(ctx *Context, config *snapsql.Config, params map[string]any, options query.QueryOptions, in io.Reader, out io.Writer) error {
+	repl := &queryREPL{
+		cmd:     q,
+		ctx:     ctx,
+		config:  config,
+		options: options,
+		params:  params,
+		dialect: strings.ToLower(strings.TrimSpace(q.Dialect)),
+		out:     out,
+	}
+	defer repl.close()
+
+	if q.TemplateFile != "" {
+		if err := repl.load(q.TemplateFile); err != nil {
+			fmt.Fprintf(out, "error: %v\n", err)
+		}
+	}
+
+	scanner := bufio.NewScanner(in)
+
+	for {
+		fmt.Fprint(out, "snapsql> ")
+
+		if !scanner.Scan() {
+			fmt.Fprintln(out)
+			return scanner.Err()
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if line == ".quit" || line == ".exit" {
+			return nil
+		}
+
+		if err := repl.execute(line); err != nil {
+			fmt.Fprintf(out, "error: %v\n", err)
+		}
+	}
+}
+
+// execute dispatches a single REPL command line.
+func (r *queryREPL) execute(line string) error {
+	command, rest, _ := strings.Cut(line, " ")
+	rest = strings.TrimSpace(rest)
+
+	switch command {
+	case ".help":
+		fmt.Fprint(r.out, replHelp)
+		return nil
+	case ".load":
+		if rest == "" {
+			return fmt.Errorf("%w: .load <file>", ErrREPLUsage)
+		}
+
+		return r.load(rest)
+	case ".set":
+		return r.set(rest)
+	case ".unset":
+		if rest == "" {
+			return fmt.Errorf("%w: .unset <name>", ErrREPLUsage)
+		}
+
+		delete(r.params, rest)
+
+		return nil
+	case ".params":
+		r.printParams()
+		return nil
+	case ".dialect":
+		return r.setDialect(rest)
+	case ".sql":
+		return r.render()
+	case ".run":
+		return r.run()
+	default:
+		return fmt.Errorf("%w: %s (type .help for a list)", ErrUnknownREPLCommand, command)
+	}
+}
+
+// load switches the active template and lists the parameters it declares.
+func (r *queryREPL) load(path string) error {
+	if !fileExists(path) {
+		return fmt.Errorf("%w: %s", ErrTemplateNotFound, path)
+	}
+
+	format, err := query.LoadIntermediateFormat(path)
+	if err != nil {
+		return fmt.Errorf("failed to load template: %w", err)
+	}
+
+	r.cmd.TemplateFile = path
+
+	fmt.Fprintf(r.out, "Loaded %s\n", path)
+
+	for _, p := range format.Parameters {
+		marker := ""
+		if _, ok := r.params[p.Name]; !ok && !p.Optional {
+			marker = " (unset)"
+		}
+
+		fmt.Fprintf(r.out, "  %s: %s%s\n", p.Name, p.Type, marker)
+	}
+
+	return nil
+}
+
+// set evaluates "<name> = <expr>" with CEL. The expression can refer to the
+// parameters that are already set.
+func (r *queryREPL) set(arg string) error {
+	name, expr, ok := strings.Cut(arg, "=")
+	name = strings.TrimSpace(name)
+	expr = strings.TrimSpace(expr)
+
+	if !ok || name == "" || expr == "" {
+		return fmt.Errorf("%w: .set <name> = <expr>", ErrREPLUsage)
+	}
+
+	value, err := evaluateREPLExpression(expr, r.params)
+	if err != nil {
+		return err
+	}
+
+	r.params[name] = value
+
+	return nil
+}
+
+// evaluateREPLExpression compiles and evaluates a CEL expression with params
+// declared as variables and converts the result into plain Go values.
+func evaluateREPLExpression(expr string, params map[string]any) (any, error) {
+	decls := []cel.EnvOption{cel.Variable("params", cel.MapType(cel.StringType, cel.AnyType))}
+	for k := range params {
+		decls = append(decls, cel.Variable(k, cel.AnyType))
+	}
+
+	env, err := cel.NewEnv(decls...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	ast, issues := env.Compile(expr)
+	if issues.Err() != nil {
+		return nil, fmt.Errorf("failed to compile expression (%s): %w", expr, issues.Err())
+	}
+
+	program, err := env.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create program: %w", err)
+	}
+
+	vars := map[string]any{"params": params}
+	maps.Copy(vars, params)
+
+	result, _, err := program.Eval(vars)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate expression (%s): %w", expr, err)
+	}
+
+	return celToNative(result), nil
+}
+
+// celToNative unwraps CEL lists and maps recursively so that the values can
+// be passed to the template like parameters loaded from JSON/YAML.
+func celToNative(v ref.Val) any {
+	switch val := v.(type) {
+	case types.Null:
+		return nil
+	case traits.Mapper:
+		result := make(map[string]any)
+
+		for it := val.Iterator(); it.HasNext() == types.True; {
+			key := it.Next()
+			result[fmt.Sprint(key.Value())] = celToNative(val.Get(key))
+		}
+
+		return result
+	case traits.Lister:
+		var result []any
+
+		for it := val.Iterator(); it.HasNext() == types.True; {
+			result = append(result, celToNative(it.Next()))
+		}
+
+		return result
+	default:
+		return v.Value()
+	}
+}
+
+func (r *queryREPL) printParams() {
+	keys := slices.Sorted(maps.Keys(r.params))
+	if len(keys) == 0 {
+		fmt.Fprintln(r.out, "(no parameters)")
+		return
+	}
+
+	for _, k := range keys {
+		fmt.Fprintf(r.out, "  %s = %#v\n", k, r.params[k])
+	}
+}
+
+func (r *queryREPL) setDialect(name string) error {
+	if name == "" {
+		fmt.Fprintln(r.out, r.currentDialect())
+		return nil
+	}
+
+	name = strings.ToLower(name)
+	switch name {
+	case "postgresql", "postgres":
+		r.dialect = "postgresql"
+	case "mysql", "sqlite", "mariadb", "duckdb", "clickhouse", "cockroach":
+		r.dialect = name
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidDialect, name)
+	}
+
+	return nil
+}
+
+// currentDialect prefers the dialect chosen in the session and falls back to
+// the connected driver, like --dry-run does.
+func (r *queryREPL) currentDialect() string {
+	if r.dialect != "" {
+		return r.dialect
+	}
+
+	return r.cmd.getDialectFromOptions(r.options)
+}
+
+// render previews the SQL and bound arguments without touching the database.
+func (r *queryREPL) render() error {
+	if r.cmd.TemplateFile == "" {
+		return ErrNoTemplateLoaded
+	}
+
+	dialect := snapsql.Dialect(r.currentDialect())
+
+	sqlText, args, err := r.cmd.renderSQL(r.params, dialect)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(r.out, query.FormatSQLForDialect(sqlText, dialect))
+
+	for i, arg := range args {
+		fmt.Fprintf(r.out, "  $%d: %v (%T)\n", i+1, arg, arg)
+	}
+
+	return nil
+}
+
+// run executes the loaded template and tabulates the result.
+func (r *queryREPL) run() error {
+	if r.cmd.TemplateFile == "" {
+		return ErrNoTemplateLoaded
+	}
+
+	if r.db == nil {
+		driver, connectionString, err := r.cmd.getDatabaseConnection(r.config, r.ctx)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrDatabaseConnection, err)
+		}
+
+		db, err := query.OpenDatabase(driver, connectionString, r.options.Timeout)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrDatabaseConnection, err)
+		}
+
+		r.db = db
+		r.options.Driver = driver
+		r.options.ConnectionString = connectionString
+	}
+
+	result, err := query.NewExecutor(r.db).ExecuteWithTemplate(context.Background(), r.cmd.TemplateFile, r.params, r.options)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrQueryExecution, err)
+	}
+
+	formatter := query.NewFormatter(r.options.Format)
+	if r.options.Explain {
+		return formatter.FormatExplain(result, r.out)
+	}
+
+	return formatter.Format(result, r.out)
+}
+
+func (r *queryREPL) close() {
+	if r.db != nil {
+		r.db.Close()
+	}
+}
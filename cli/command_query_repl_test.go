@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/query"
+)
+
+func TestQuery_REPL_RenderWithCELParameters(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sql := "" +
+		"/*#\n" +
+		"function_name: find_item\n" +
+		"parameters:\n" +
+		"  item_id: int\n" +
+		"*/\n" +
+		"SELECT id FROM items WHERE id = /*= item_id */0;\n"
+	path := writeTemp(t, dir, "find_item.snap.sql", sql)
+
+	input := strings.Join([]string{
+		".sql",
+		".set base = 40",
+		".set item_id = base + 2",
+		".params",
+		".dialect mysql",
+		".sql",
+		".dialect oracle",
+		".bogus",
+		".quit",
+		".params",
+	}, "\n")
+
+	var out bytes.Buffer
+
+	q := &QueryCmd{TemplateFile: path}
+	err := q.runREPL(&Context{Quiet: true}, &snapsql.Config{}, map[string]any{}, query.QueryOptions{}, strings.NewReader(input), &out)
+	assert.NoError(t, err)
+
+	text := out.String()
+	assert.Contains(t, text, "Loaded "+path)
+	assert.Contains(t, text, "item_id: int (unset)")
+	assert.Contains(t, text, "missing required parameter")
+	assert.Contains(t, text, "item_id = 42")
+	assert.Contains(t, text, "$1: 42 (int64)")
+	assert.Contains(t, text, "?")
+	assert.Contains(t, text, "invalid dialect: oracle")
+	assert.Contains(t, text, "unknown command: .bogus")
+	// Commands after .quit are not executed
+	assert.Equal(t, 1, strings.Count(text, "item_id = 42"))
+}
+
+func TestQuery_REPL_EvaluateExpression(t *testing.T) {
+	t.Parallel()
+
+	value, err := evaluateREPLExpression(`{"ids": [1, 2], "name": prefix + "x", "none": null}`, map[string]any{"prefix": "a"})
+	assert.NoError(t, err)
+	assert.Equal[any](t, map[string]any{"ids": []any{int64(1), int64(2)}, "name": "ax", "none": nil}, value)
+
+	_, err = evaluateREPLExpression("unknown_var + 1", map[string]any{})
+	assert.Error(t, err)
+}
+
+func TestQuery_REPL_RequiresTemplate(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+
+	q := &QueryCmd{}
+	err := q.runREPL(&Context{Quiet: true}, &snapsql.Config{}, map[string]any{}, query.QueryOptions{}, strings.NewReader(".sql\n.run\n"), &out)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(out.String(), ErrNoTemplateLoaded.Error()))
+}
@@ -169,3 +169,31 @@ func TestQuery_InvalidOutputFormat(t *testing.T) {
 	err := q.Run(&Context{Quiet: true})
 	assert.Error(t, err)
 }
+
+func TestQuery_ResolveLimit(t *testing.T) {
+	config := &snapsql.Config{Query: snapsql.QueryConfig{MaxRows: 1000}}
+
+	assert.Equal(t, 1000, (&QueryCmd{}).resolveLimit(config))
+	assert.Equal(t, 50, (&QueryCmd{Limit: 50}).resolveLimit(config))
+	assert.Equal(t, 0, (&QueryCmd{NoLimit: true}).resolveLimit(config))
+
+	config.Query.Limit = 100
+	assert.Equal(t, 100, (&QueryCmd{}).resolveLimit(config))
+}
+
+func TestQuery_TruncatedByDefaultLimit(t *testing.T) {
+	dir := t.TempDir()
+	unbounded := writeTemp(t, dir, "unbounded.snap.sql", "SELECT id FROM users")
+	bounded := writeTemp(t, dir, "bounded.snap.sql", "SELECT id FROM users LIMIT 2")
+
+	twoRows := &query.QueryResult{Rows: [][]any{{1}, {2}}}
+	oneRow := &query.QueryResult{Rows: [][]any{{1}}}
+	options := query.QueryOptions{Limit: 2}
+
+	assert.True(t, (&QueryCmd{TemplateFile: unbounded}).truncatedByDefaultLimit(options, twoRows))
+	assert.False(t, (&QueryCmd{TemplateFile: unbounded}).truncatedByDefaultLimit(options, oneRow))
+	// Explicit limits are intentional
+	assert.False(t, (&QueryCmd{TemplateFile: unbounded, Limit: 2}).truncatedByDefaultLimit(options, twoRows))
+	assert.False(t, (&QueryCmd{TemplateFile: bounded}).truncatedByDefaultLimit(options, twoRows))
+	assert.False(t, (&QueryCmd{TemplateFile: unbounded}).truncatedByDefaultLimit(query.QueryOptions{}, twoRows))
+}
@@ -0,0 +1,175 @@
+package cli
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/query"
+)
+
+// RenderCmd represents the render command
+type RenderCmd struct {
+	TemplateFile string   `arg:"" help:"SQL template file (.snap.sql or .snap.md)" type:"path"`
+	ParamsFile   string   `short:"P" long:"params" help:"Parameters file (JSON/YAML)" type:"path"`
+	Param        []string `short:"p" long:"param" help:"Individual parameter (key=value format)"`
+	ConstFiles   []string `long:"const" help:"Constant definition files" type:"path"`
+	Dialect      string   `long:"dialect" help:"SQL dialect" default:"postgresql" enum:"postgresql,mysql,sqlite,mariadb,duckdb,clickhouse,cockroach"`
+	Inline       bool     `long:"inline" help:"Inline bound values as SQL literals instead of placeholders"`
+	OutputFile   string   `short:"o" long:"output" help:"Output file (defaults to stdout)" type:"path"`
+}
+
+// Run executes the render command
+func (cmd *RenderCmd) Run(ctx *Context) error {
+	config, err := LoadConfig(ctx.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	if !fileExists(cmd.TemplateFile) {
+		return fmt.Errorf("%w: %s", ErrTemplateNotFound, cmd.TemplateFile)
+	}
+
+	q := &QueryCmd{
+		TemplateFile: cmd.TemplateFile,
+		ParamsFile:   cmd.ParamsFile,
+		Param:        cmd.Param,
+		ConstFiles:   cmd.ConstFiles,
+	}
+
+	params, err := q.loadParameters(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load parameters: %w", err)
+	}
+
+	constants, err := q.loadConstants(config, ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load constants: %w", err)
+	}
+
+	for k, v := range constants {
+		if _, exists := params[k]; !exists {
+			params[k] = v
+		}
+	}
+
+	output := io.Writer(os.Stdout)
+
+	if cmd.OutputFile != "" {
+		file, err := os.Create(cmd.OutputFile)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrOutputFileCreation, err)
+		}
+		defer file.Close()
+
+		output = file
+	}
+
+	return cmd.render(q, params, output)
+}
+
+// render writes the expanded SQL. With --inline the bound values replace the
+// placeholders; otherwise they are listed as SQL comments below the statement.
+func (cmd *RenderCmd) render(q *QueryCmd, params map[string]any, w io.Writer) error {
+	dialect := snapsql.Dialect(cmd.Dialect)
+
+	sql, args, err := q.renderSQL(params, dialect)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Inline {
+		fmt.Fprintln(w, inlineSQLArgs(sql, args, dialect))
+		return nil
+	}
+
+	fmt.Fprintln(w, query.FormatSQLForDialect(sql, dialect))
+
+	for i, arg := range args {
+		fmt.Fprintf(w, "-- $%d: %s (%T)\n", i+1, sqlLiteral(arg, dialect), arg)
+	}
+
+	return nil
+}
+
+// inlineSQLArgs replaces each '?' placeholder outside quoted strings and
+// identifiers with the literal form of the matching argument.
+func inlineSQLArgs(sql string, args []any, dialect snapsql.Dialect) string {
+	var b strings.Builder
+
+	n := 0
+	inSingle, inDouble := false, false
+
+	for i := range len(sql) {
+		ch := sql[i]
+
+		switch {
+		case ch == '\'' && !inDouble:
+			inSingle = !inSingle
+		case ch == '"' && !inSingle:
+			inDouble = !inDouble
+		case ch == '?' && !inSingle && !inDouble && n < len(args):
+			b.WriteString(sqlLiteral(args[n], dialect))
+
+			n++
+
+			continue
+		}
+
+		b.WriteByte(ch)
+	}
+
+	return b.String()
+}
+
+// sqlLiteral formats a bound value as a SQL literal for display. Lists and
+// objects are rendered as JSON strings, matching how they are bound.
+func sqlLiteral(v any, dialect snapsql.Dialect) string {
+	switch val := v.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if dialect == snapsql.DialectSQLite {
+			if val {
+				return "1"
+			}
+
+			return "0"
+		}
+
+		return strings.ToUpper(strconv.FormatBool(val))
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(val)
+	case float32:
+		return strconv.FormatFloat(float64(val), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(val, 'g', -1, 64)
+	case string:
+		return quoteSQLString(val, dialect)
+	case time.Time:
+		return quoteSQLString(val.Format("2006-01-02 15:04:05.999999999Z07:00"), dialect)
+	case []byte:
+		return "X'" + hex.EncodeToString(val) + "'"
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return quoteSQLString(fmt.Sprint(val), dialect)
+		}
+
+		return quoteSQLString(string(data), dialect)
+	}
+}
+
+func quoteSQLString(s string, dialect snapsql.Dialect) string {
+	if dialect == snapsql.DialectMySQL || dialect == snapsql.DialectMariaDB {
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
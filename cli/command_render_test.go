@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql"
+)
+
+func TestRender_PlaceholdersAndInline(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	sql := "" +
+		"/*#\n" +
+		"function_name: find_user\n" +
+		"parameters:\n" +
+		"  user_id: int\n" +
+		"  name: string\n" +
+		"*/\n" +
+		"SELECT id FROM users WHERE id = /*= user_id */0 AND name = /*= name */'x';\n"
+	path := writeTemp(t, dir, "find_user.snap.sql", sql)
+	params := map[string]any{"user_id": 7, "name": "O'Brien"}
+
+	q := &QueryCmd{TemplateFile: path}
+
+	var out bytes.Buffer
+
+	err := (&RenderCmd{Dialect: "postgresql"}).render(q, params, &out)
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "id = $1")
+	assert.Contains(t, out.String(), "name = $2")
+	assert.Contains(t, out.String(), "-- $1: 7 (int)")
+	assert.Contains(t, out.String(), "-- $2: 'O''Brien' (string)")
+
+	out.Reset()
+
+	err = (&RenderCmd{Dialect: "mysql", Inline: true}).render(q, params, &out)
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "id = 7")
+	assert.Contains(t, out.String(), "name = 'O''Brien'")
+	assert.NotContains(t, out.String(), "?")
+
+	out.Reset()
+
+	err = (&RenderCmd{Dialect: "duckdb"}).render(q, params, &out)
+	assert.NoError(t, err)
+	assert.Contains(t, out.String(), "id = $1")
+
+	err = (&RenderCmd{Dialect: "postgresql"}).render(q, map[string]any{}, &out)
+	assert.Error(t, err)
+}
+
+func TestRender_SQLLiteral(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "NULL", sqlLiteral(nil, snapsql.DialectPostgres))
+	assert.Equal(t, "TRUE", sqlLiteral(true, snapsql.DialectPostgres))
+	assert.Equal(t, "0", sqlLiteral(false, snapsql.DialectSQLite))
+	assert.Equal(t, "1.5", sqlLiteral(1.5, snapsql.DialectPostgres))
+	assert.Equal(t, `'a\\b'`, sqlLiteral(`a\b`, snapsql.DialectMySQL))
+	assert.Equal(t, `'a\b'`, sqlLiteral(`a\b`, snapsql.DialectPostgres))
+	assert.Equal(t, `'[1,2]'`, sqlLiteral([]any{1, 2}, snapsql.DialectPostgres))
+	assert.Equal(t, "X'0aff'", sqlLiteral([]byte{0x0a, 0xff}, snapsql.DialectPostgres))
+	assert.Equal(t, "SELECT '?' , 1", inlineSQLArgs("SELECT '?' , ?", []any{1}, snapsql.DialectPostgres))
+}
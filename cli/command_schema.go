@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/query"
+	"github.com/shibukawa/snapsql/schemaimport"
+)
+
+var (
+	ErrSchemaSnapshotUnavailable = errors.New("schema snapshot unavailable")
+	ErrSchemaDrift               = errors.New("schema snapshot differs from the database")
+)
+
+// SchemaCmd groups commands that work with the tbls schema snapshot
+type SchemaCmd struct {
+	Diff SchemaDiffCmd `cmd:"" help:"Compare the schema snapshot with the live database"`
+	Pull SchemaPullCmd `cmd:"" help:"Write the schema YAML from the live database without tbls"`
+}
+
+// SchemaDiffCmd compares the tables loaded from the tbls schema JSON (or the schema YAML)
+// with the tables of the database configured by the tbls dsn.
+type SchemaDiffCmd struct {
+	DB      string `help:"Database connection string (defaults to the tbls dsn)"`
+	Timeout int    `help:"Connection timeout in seconds" default:"30"`
+}
+
+func (cmd *SchemaDiffCmd) Run(ctx *Context) error {
+	snapshot := loadRuntimeTables(ctx)
+	if len(snapshot) == 0 {
+		return fmt.Errorf("%w: check the tbls config or run 'snapsql schema pull'", ErrSchemaSnapshotUnavailable)
+	}
+
+	live, _, err := introspectLiveDatabase(ctx, cmd.DB, cmd.Timeout)
+	if err != nil {
+		return err
+	}
+
+	diffs := schemaimport.DiffTables(snapshot, live)
+	if len(diffs) == 0 {
+		if !ctx.Quiet {
+			color.Green("Schema snapshot matches the database (%d tables)", len(live))
+		}
+
+		return nil
+	}
+
+	for _, diff := range diffs {
+		switch diff.Kind {
+		case schemaimport.DiffMissingTable, schemaimport.DiffMissingColumn:
+			color.Red("- %s", diff)
+		case schemaimport.DiffExtraTable, schemaimport.DiffExtraColumn:
+			color.Green("+ %s", diff)
+		default:
+			color.Yellow("~ %s", diff)
+		}
+	}
+
+	return fmt.Errorf("%w: %d differences (regenerate it with tbls doc or snapsql schema pull)", ErrSchemaDrift, len(diffs))
+}
+
+// SchemaPullCmd introspects the database and writes the schema YAML used for type inference
+// when no tbls schema JSON is available.
+type SchemaPullCmd struct {
+	DB      string `help:"Database connection string (defaults to the tbls dsn)"`
+	Output  string `short:"o" help:"Schema YAML path (defaults to schema.file)" type:"path"`
+	Timeout int    `help:"Connection timeout in seconds" default:"30"`
+}
+
+func (cmd *SchemaPullCmd) Run(ctx *Context) error {
+	path := cmd.Output
+	if path == "" {
+		config, err := LoadConfig(ctx.Config)
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		path = config.Schema.File
+	}
+
+	live, driver, err := introspectLiveDatabase(ctx, cmd.DB, cmd.Timeout)
+	if err != nil {
+		return err
+	}
+
+	if err := schemaimport.WriteSchemaYAML(path, schemaimport.BuildDatabaseSchemas(live, driver)); err != nil {
+		return err
+	}
+
+	if !ctx.Quiet {
+		color.Green("Wrote %d tables to %s", len(live), path)
+	}
+
+	return nil
+}
+
+// introspectLiveDatabase connects to the database given by --db, or the tbls dsn when omitted,
+// and returns its tables together with the normalized driver name.
+func introspectLiveDatabase(ctx *Context, connection string, timeout int) ([]*snapsql.TableInfo, string, error) {
+	var database *snapsql.Database
+
+	if connection != "" {
+		database = &snapsql.Database{
+			Driver:     normalizeSQLDriverName(determineDriver(connection)),
+			Connection: connection,
+		}
+	} else {
+		resolved, err := resolveDatabaseFromTbls(ctx)
+		if err != nil {
+			if errors.Is(err, ErrTblsDatabaseUnavailable) {
+				return nil, "", fmt.Errorf("%w: specify --db", err)
+			}
+
+			return nil, "", err
+		}
+
+		database = resolved
+	}
+
+	if err := checkDriverCompiled(database.Driver); err != nil {
+		return nil, "", err
+	}
+
+	db, err := query.OpenDatabase(database.Driver, database.Connection, timeout)
+	if err != nil {
+		return nil, "", err
+	}
+	defer db.Close()
+
+	introspectCtx, cancel := context.WithTimeout(context.Background(), time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	tables, err := schemaimport.IntrospectDatabase(introspectCtx, db, database.Driver)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return tables, database.Driver, nil
+}
@@ -1,30 +1,55 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/fatih/color"
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/lint"
 )
 
+// ErrDialectIncompatible is returned when --dialects finds a template that cannot be expressed in a dialect
+var ErrDialectIncompatible = errors.New("templates are not compatible with all dialects")
+
 // ValidateCmd represents the validate command
 type ValidateCmd struct {
-	Input  string   `short:"i" help:"Input directory" default:"./queries" type:"path"`
-	Files  []string `arg:"" help:"Specific files to validate" optional:""`
-	Strict bool     `help:"Enable strict validation mode"`
-	Format string   `help:"Output format" default:"text" enum:"text,json"`
+	Input    string   `short:"i" help:"Input directory" default:"./queries" type:"path"`
+	Files    []string `arg:"" help:"Specific files to validate" optional:""`
+	Strict   bool     `help:"Enable strict validation mode"`
+	Format   string   `help:"Output format" default:"text" enum:"text,json"`
+	Dialects []string `help:"Check compatibility with the given dialects (postgres,mysql,sqlite,mariadb,duckdb,clickhouse,cockroach) and print a matrix" sep:","`
+
+	WorkspaceFlags `embed:""`
 }
 
 func (v *ValidateCmd) Run(ctx *Context) error {
+	if v.Workspace {
+		v.Files = absolutePaths(v.Files)
+
+		return runWorkspace(ctx, v.WorkspaceFlags, "validate", v.run)
+	}
+
+	return v.run(ctx)
+}
+
+func (v *ValidateCmd) run(ctx *Context) error {
 	if ctx.Verbose {
 		color.Blue("Validating templates in %s", v.Input)
 	}
 
 	// Load configuration
-	_, err := LoadConfig(ctx.Config)
+	config, err := LoadConfig(ctx.Config)
 	if err != nil {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
+	if len(v.Dialects) > 0 {
+		return v.checkDialects(ctx, config)
+	}
+
 	// TODO: Implement validation logic
 	if !ctx.Quiet {
 		color.Green("Validation completed successfully")
@@ -32,3 +57,99 @@ func (v *ValidateCmd) Run(ctx *Context) error {
 
 	return nil
 }
+
+// checkDialects converts every template to each requested dialect and reports the
+// constructs that cannot be expressed there as a per-template compatibility matrix.
+func (v *ValidateCmd) checkDialects(ctx *Context, config *snapsql.Config) error {
+	dialects := make([]snapsql.Dialect, 0, len(v.Dialects))
+
+	for _, name := range v.Dialects {
+		dialect, err := parseDialectName(name)
+		if err != nil {
+			return err
+		}
+
+		dialects = append(dialects, dialect)
+	}
+
+	files := v.Files
+	if len(files) == 0 {
+		found, err := findTemplateFiles(v.Input)
+		if err != nil {
+			return fmt.Errorf("failed to find template files: %w", err)
+		}
+
+		files = found
+	}
+
+	constants, err := (&GenerateCmd{}).loadConstants(config, ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load constants: %w", err)
+	}
+
+	linter, err := lint.New(lint.Options{
+		Constants: constants,
+		Tables:    loadRuntimeTables(ctx),
+		Config:    config,
+	})
+	if err != nil {
+		return err
+	}
+
+	reports := make([]lint.DialectReport, 0, len(files))
+	incompatible := 0
+
+	for _, file := range files {
+		report, err := linter.CheckDialects(file, dialects)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+
+		for _, d := range dialects {
+			if !report.Compatible(d) {
+				incompatible++
+				break
+			}
+		}
+
+		reports = append(reports, report)
+	}
+
+	if v.Format == "json" {
+		err = lint.WriteDialectJSON(os.Stdout, reports)
+	} else {
+		err = lint.WriteDialectMatrix(os.Stdout, reports, dialects)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to write compatibility matrix: %w", err)
+	}
+
+	if incompatible > 0 {
+		return fmt.Errorf("%w: %d of %d template(s)", ErrDialectIncompatible, incompatible, len(files))
+	}
+
+	return nil
+}
+
+// parseDialectName accepts the dialect names used by the config and the common aliases.
+func parseDialectName(name string) (snapsql.Dialect, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "postgres", "postgresql", "pg":
+		return snapsql.DialectPostgres, nil
+	case "mysql":
+		return snapsql.DialectMySQL, nil
+	case "sqlite", "sqlite3":
+		return snapsql.DialectSQLite, nil
+	case "mariadb":
+		return snapsql.DialectMariaDB, nil
+	case "duckdb":
+		return snapsql.DialectDuckDB, nil
+	case "clickhouse":
+		return snapsql.DialectClickHouse, nil
+	case "cockroach", "cockroachdb":
+		return snapsql.DialectCockroach, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidDialect, name)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build clickhouse
+
+package cli
+
+// The ClickHouse driver is only registered in binaries built with `-tags clickhouse`
+// so the default binary does not carry the ClickHouse client and its dependencies.
+import _ "github.com/ClickHouse/clickhouse-go/v2"
+
+// clickhouseDriverCompiled reports whether the "clickhouse" database/sql driver is registered
+const clickhouseDriverCompiled = true
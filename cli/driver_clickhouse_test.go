@@ -0,0 +1,18 @@
+//go:build clickhouse
+
+package cli
+
+import (
+	"database/sql"
+	"slices"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestClickHouseDriverRegistered(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, checkDriverCompiled("clickhouse"))
+	assert.True(t, slices.Contains(sql.Drivers(), "clickhouse"))
+}
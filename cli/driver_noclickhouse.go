@@ -0,0 +1,6 @@
+//go:build !clickhouse
+
+package cli
+
+// clickhouseDriverCompiled reports whether the "clickhouse" database/sql driver is registered
+const clickhouseDriverCompiled = false
@@ -0,0 +1,20 @@
+//go:build !clickhouse
+
+package cli
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql"
+)
+
+func TestQuery_GetDatabaseConnection_ClickHouseNotCompiled(t *testing.T) {
+	t.Parallel()
+
+	cmd := &QueryCmd{DBConnection: "clickhouse://localhost:9000/default"}
+
+	_, _, err := cmd.getDatabaseConnection(&snapsql.Config{}, &Context{})
+	assert.IsError(t, err, ErrDriverNotCompiled)
+	assert.Contains(t, err.Error(), "rebuild with -tags clickhouse")
+}
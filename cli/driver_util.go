@@ -1,6 +1,9 @@
 package cli
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 func normalizeSQLDriverName(driver string) string {
 	switch strings.ToLower(strings.TrimSpace(driver)) {
@@ -10,6 +13,10 @@ func normalizeSQLDriverName(driver string) string {
 		return "mysql"
 	case "sqlite", "sqlite3":
 		return "sqlite3"
+	case "duckdb":
+		return "duckdb"
+	case "clickhouse":
+		return "clickhouse"
 	default:
 		return strings.ToLower(strings.TrimSpace(driver))
 	}
@@ -23,7 +30,26 @@ func canonicalDialectFromDriver(driver string) string {
 		return "mysql"
 	case "sqlite", "sqlite3":
 		return "sqlite"
+	case "duckdb":
+		return "duckdb"
+	case "clickhouse":
+		return "clickhouse"
 	default:
 		return strings.ToLower(strings.TrimSpace(driver))
 	}
 }
+
+// checkDriverCompiled reports a clear error for drivers that are not linked into this binary
+func checkDriverCompiled(driver string) error {
+	switch normalizeSQLDriverName(driver) {
+	case "duckdb":
+		// DuckDB is supported for SQL generation only; no driver is bundled
+		return fmt.Errorf("%w: duckdb (use --dialect duckdb with --dry-run or render)", ErrDriverNotCompiled)
+	case "clickhouse":
+		if !clickhouseDriverCompiled {
+			return fmt.Errorf("%w: clickhouse (rebuild with -tags clickhouse)", ErrDriverNotCompiled)
+		}
+	}
+
+	return nil
+}
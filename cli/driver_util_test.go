@@ -0,0 +1,18 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql"
+)
+
+func TestQuery_GetDatabaseConnection_DuckDBNotCompiled(t *testing.T) {
+	t.Parallel()
+
+	cmd := &QueryCmd{DBConnection: "analytics.duckdb"}
+
+	_, _, err := cmd.getDatabaseConnection(&snapsql.Config{}, &Context{})
+	assert.IsError(t, err, ErrDriverNotCompiled)
+	assert.Contains(t, err.Error(), "--dialect duckdb")
+}
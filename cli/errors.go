@@ -12,4 +12,5 @@ var (
 	ErrMissingDBOrEnv         = errors.New("missing database or environment")
 	ErrEmptyConnectionString  = errors.New("empty connection string")
 	ErrEmptyDatabaseType      = errors.New("empty database type")
+	ErrGeneratedFilesStale    = errors.New("generated files are stale")
 )
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// generatedFiles is the output of "snapsql generate --check". Nothing is written to disk:
+// generated content is compared with the files on disk and kept in memory, so that
+// generators reading intermediate files see the regenerated ones.
+// A nil *generatedFiles reads and writes the operating system's file system.
+type generatedFiles struct {
+	contents map[string][]byte
+	stale    map[string]staleFile
+}
+
+// staleFile describes a generated file whose content on disk differs from the regenerated one
+type staleFile struct {
+	Path    string
+	Missing bool
+	Added   int
+	Removed int
+}
+
+func newGeneratedFiles() *generatedFiles {
+	return &generatedFiles{
+		contents: map[string][]byte{},
+		stale:    map[string]staleFile{},
+	}
+}
+
+// ReadFile returns content generated in this run before falling back to the disk
+func (f *generatedFiles) ReadFile(name string) ([]byte, error) {
+	if f != nil {
+		if data, ok := f.contents[name]; ok {
+			return data, nil
+		}
+	}
+
+	return os.ReadFile(name)
+}
+
+// WriteFile writes data, or in check mode records whether it differs from the file on disk
+func (f *generatedFiles) WriteFile(name string, data []byte, perm os.FileMode) error {
+	if f == nil {
+		return os.WriteFile(name, data, perm)
+	}
+
+	f.contents[name] = data
+
+	current, err := os.ReadFile(name)
+
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		f.stale[name] = staleFile{Path: name, Missing: true, Added: countLines(data)}
+	case err != nil:
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	case !bytes.Equal(current, data):
+		added, removed := lineChanges(string(current), string(data))
+		f.stale[name] = staleFile{Path: name, Added: added, Removed: removed}
+	default:
+		delete(f.stale, name)
+	}
+
+	return nil
+}
+
+// MkdirAll creates a directory; check mode creates nothing
+func (f *generatedFiles) MkdirAll(path string, perm os.FileMode) error {
+	if f == nil {
+		return os.MkdirAll(path, perm)
+	}
+
+	return nil
+}
+
+// Stale returns the out-of-date files sorted by path
+func (f *generatedFiles) Stale() []staleFile {
+	if f == nil {
+		return nil
+	}
+
+	files := make([]staleFile, 0, len(f.stale))
+	for _, file := range f.stale {
+		files = append(files, file)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+
+	return files
+}
+
+// WriteSummary prints one line per stale file
+func (f *generatedFiles) WriteSummary(w io.Writer) {
+	for _, file := range f.Stale() {
+		if file.Missing {
+			fmt.Fprintf(w, "  missing  %s (+%d)\n", file.Path, file.Added)
+		} else {
+			fmt.Fprintf(w, "  modified %s (+%d -%d)\n", file.Path, file.Added, file.Removed)
+		}
+	}
+}
+
+func countLines(data []byte) int {
+	if len(data) == 0 {
+		return 0
+	}
+
+	return strings.Count(strings.TrimSuffix(string(data), "\n"), "\n") + 1
+}
+
+// lineChanges counts the lines only found in after (added) and only found in before (removed),
+// treating both texts as multisets of lines
+func lineChanges(before, after string) (added, removed int) {
+	counts := map[string]int{}
+	for _, line := range strings.Split(before, "\n") {
+		counts[line]++
+	}
+
+	for _, line := range strings.Split(after, "\n") {
+		if counts[line] > 0 {
+			counts[line]--
+		} else {
+			added++
+		}
+	}
+
+	for _, n := range counts {
+		removed += n
+	}
+
+	return added, removed
+}
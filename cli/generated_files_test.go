@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestGeneratedFilesCheck(t *testing.T) {
+	dir := t.TempDir()
+	current := filepath.Join(dir, "current.go")
+	modified := filepath.Join(dir, "modified.go")
+	missing := filepath.Join(dir, "sub", "missing.go")
+
+	assert.NoError(t, os.WriteFile(current, []byte("package a\n"), 0o644))
+	assert.NoError(t, os.WriteFile(modified, []byte("package a\n\nfunc A() {}\n"), 0o644))
+
+	files := newGeneratedFiles()
+	assert.NoError(t, files.MkdirAll(filepath.Dir(missing), 0o755))
+	assert.NoError(t, files.WriteFile(current, []byte("package a\n"), 0o644))
+	assert.NoError(t, files.WriteFile(modified, []byte("package a\n\nfunc B() {}\nfunc C() {}\n"), 0o644))
+	assert.NoError(t, files.WriteFile(missing, []byte("package sub\n"), 0o644))
+
+	// Nothing is written in check mode
+	_, err := os.Stat(filepath.Dir(missing))
+	assert.True(t, os.IsNotExist(err))
+
+	data, err := os.ReadFile(modified)
+	assert.NoError(t, err)
+	assert.Equal(t, "package a\n\nfunc A() {}\n", string(data))
+
+	// Later generators read the regenerated content
+	data, err = files.ReadFile(missing)
+	assert.NoError(t, err)
+	assert.Equal(t, "package sub\n", string(data))
+
+	assert.Equal(t, []staleFile{
+		{Path: modified, Added: 2, Removed: 1},
+		{Path: missing, Missing: true, Added: 1},
+	}, files.Stale())
+
+	var summary bytes.Buffer
+	files.WriteSummary(&summary)
+	assert.Contains(t, summary.String(), "modified "+modified+" (+2 -1)")
+	assert.Contains(t, summary.String(), "missing  "+missing+" (+1)")
+}
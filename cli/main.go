@@ -12,14 +12,18 @@ import (
 	"runtime"
 	"sort"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/alecthomas/kong"
+	"github.com/fatih/color"
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/gencache"
 	"github.com/shibukawa/snapsql/inspect"
+	"github.com/shibukawa/snapsql/migration"
 	"github.com/shibukawa/snapsql/testrunner"
 	"github.com/shibukawa/snapsql/testrunner/fixtureexecutor"
 )
@@ -27,9 +31,15 @@ import (
 var (
 	ErrFixtureOnlyRequiresRunPattern            = errors.New("--fixture-only mode requires --run pattern to specify which test case to execute")
 	ErrFixtureOnlyAndQueryOnlyMutuallyExclusive = errors.New("--fixture-only and --query-only are mutually exclusive")
+	ErrSeedRequiresShuffle                      = errors.New("--seed requires --shuffle")
+	ErrInvalidMaxFailures                       = errors.New("--max-failures must not be negative")
+	ErrInvalidRetries                           = errors.New("--retries must not be negative")
 	// ErrPathOutsideProjectRoot indicates a provided path escapes the project root.
 	ErrPathOutsideProjectRoot = errors.New("path is outside the project root")
 	ErrUnsupportedPathType    = errors.New("unsupported path type")
+	ErrInvalidReportSpec      = errors.New("invalid report specification")
+	ErrFixtureTestsFailed     = errors.New("fixture tests failed")
+	ErrPerfBaselineNotFound   = errors.New("performance baseline not found")
 )
 
 // Context represents the global context for commands
@@ -38,8 +48,24 @@ type Context struct {
 	Verbose    bool
 	Quiet      bool
 	TblsConfig string
+
+	// runtimeCache is shared between workspace members; nil outside workspace mode
+	runtimeCache *runtimeTablesCache
+	// workspaceMember is the name of the member being run; empty outside workspace mode
+	workspaceMember string
+	// workspaceTests receives the test totals of a workspace member for the combined report
+	workspaceTests *workspaceTestCounts
+	// generationCache tracks unchanged templates during "snapsql generate"; nil when caching is disabled
+	generationCache *gencache.Manifest
+	// generatedFiles compares instead of writing during "snapsql generate --check"; nil otherwise
+	generatedFiles *generatedFiles
+	// templateSources maps intermediate files to the templates they were generated from during "snapsql generate"
+	templateSources map[string]string
 }
 
+// defaultConfigFile is the --config default, looked up in each workspace member
+const defaultConfigFile = "snapsql.yaml"
+
 // TestCmd represents the test command
 type TestCmd struct {
 	RunPattern  string `help:"Run only tests matching the regular expression" short:"r"`
@@ -48,13 +74,46 @@ type TestCmd struct {
 	FixtureOnly bool   `help:"Execute only fixture insertion and commit (requires --run pattern)"`
 	QueryOnly   bool   `help:"Execute only queries without fixtures"`
 	Commit      bool   `help:"Commit transactions instead of rollback"`
+	Shuffle     bool   `help:"Randomize the execution order of test cases to detect order dependence"`
+	Seed        int64  `help:"Seed for --shuffle (default: derived from the current time)"`
+	FailFast    bool   `help:"Stop the run after the first failing test"`
+	MaxFailures int    `help:"Stop the run after N failing tests (0 means no limit)" default:"0"`
+	Retries     int    `help:"Rerun failed tests up to N times and report tests that pass on retry as flaky" default:"0"`
 	// Environment flag removed; tbls uses single DSN and explicit tbls config path is preferred
-	Schema []string `help:"SQL files or directories to initialize an ephemeral database (repeatable)" short:"s"`
-	Paths  []string `arg:"" optional:"" name:"path" help:"Optional file or directory paths to limit executed tests"`
+	Schema    []string `help:"SQL files or directories to initialize an ephemeral database (repeatable)" short:"s"`
+	Report    []string `help:"Write machine-readable results as format=path (junit, json or slow-queries; repeatable)"`
+	Explain   bool     `help:"Record query plans and fail tests that match performance.explain_rules"`
+	PerfCheck bool     `help:"Fail tests whose query time or plan regressed against the baseline written by 'snapsql perf baseline'"`
+	Artifacts string   `help:"Write the actual table contents and SQL trace of tests whose table-state validation fails to this directory"`
+	Paths     []string `arg:"" optional:"" name:"path" help:"Optional file or directory paths to limit executed tests"`
+
+	WorkspaceFlags `embed:""`
+
+	// baseline is loaded from performance.baseline.file when --perf-check is set
+	baseline *testrunner.Baseline
+	// onSummary is called after a fully successful run (used by perf baseline to record results)
+	onSummary func(config *snapsql.Config, summary *testrunner.FixtureTestSummary) error
+	// summary is the result of the last run, reported to the workspace summary
+	summary *testrunner.FixtureTestSummary
+	// member is the workspace member being run; reports get its name as a suffix
+	member string
 }
 
 // Run executes the test command
 func (cmd *TestCmd) Run(ctx *Context) error {
+	if cmd.Workspace {
+		// Target paths stay relative to each member; they select tests inside the project
+		cmd.Schema = absolutePaths(cmd.Schema)
+		cmd.Report = absoluteReportSpecs(cmd.Report)
+		cmd.Artifacts = absolutePath(cmd.Artifacts)
+
+		return runWorkspace(ctx, cmd.WorkspaceFlags, "test", cmd.run)
+	}
+
+	return cmd.run(ctx)
+}
+
+func (cmd *TestCmd) run(ctx *Context) error {
 	// Validate fixture-only mode requirements
 	if cmd.FixtureOnly && cmd.RunPattern == "" {
 		return ErrFixtureOnlyRequiresRunPattern
@@ -65,6 +124,22 @@ func (cmd *TestCmd) Run(ctx *Context) error {
 		return ErrFixtureOnlyAndQueryOnlyMutuallyExclusive
 	}
 
+	if cmd.Seed != 0 && !cmd.Shuffle {
+		return ErrSeedRequiresShuffle
+	}
+
+	if cmd.MaxFailures < 0 {
+		return ErrInvalidMaxFailures
+	}
+
+	if cmd.Retries < 0 {
+		return ErrInvalidRetries
+	}
+
+	if _, err := parseReportSpecs(cmd.Report); err != nil {
+		return err
+	}
+
 	// Get current working directory as project root
 	projectRoot, err := os.Getwd()
 	if err != nil {
@@ -77,6 +152,23 @@ func (cmd *TestCmd) Run(ctx *Context) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	cmd.baseline = nil
+	cmd.summary = nil
+	cmd.member = ctx.workspaceMember
+
+	if cmd.PerfCheck {
+		baselinePath := config.Performance.Baseline.File
+
+		cmd.baseline, err = testrunner.LoadBaseline(baselinePath)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return fmt.Errorf("%w: %s (run 'snapsql perf baseline' first)", ErrPerfBaselineNotFound, baselinePath)
+			}
+
+			return err
+		}
+	}
+
 	// Parse timeout
 	timeout, err := time.ParseDuration(cmd.Timeout)
 	if err != nil {
@@ -106,16 +198,56 @@ func (cmd *TestCmd) Run(ctx *Context) error {
 	}
 	options.PerformanceEnabled = true
 
+	options.MaxFailures = cmd.MaxFailures
+	if cmd.FailFast {
+		options.MaxFailures = 1
+	}
+
+	options.Retries = cmd.Retries
+
+	// Workspace members write their artifacts into a subdirectory named after the member
+	if cmd.Artifacts != "" {
+		options.ArtifactsDir = cmd.Artifacts
+		if cmd.member != "" {
+			options.ArtifactsDir = filepath.Join(cmd.Artifacts, cmd.member)
+		}
+	}
+
+	if cmd.Shuffle {
+		options.Shuffle = true
+
+		options.ShuffleSeed = cmd.Seed
+		if options.ShuffleSeed == 0 {
+			options.ShuffleSeed = time.Now().UnixNano()
+		}
+	}
+
 	options.SlowQueryThreshold = config.Performance.SlowQueryThreshold
 	if options.SlowQueryThreshold <= 0 {
 		options.SlowQueryThreshold = 3 * time.Second
 	}
 
 	options.TableMetadata = buildTableMetadataFromConfig(config.Tables)
+	options.SoftDeleteColumns = buildSoftDeleteColumnsFromConfig(config.Tables)
+
+	if cmd.Explain {
+		options.Explain = true
+		options.ExplainRules = buildExplainRulesFromConfig(config.Performance.ExplainRules)
+	}
+
+	// Baseline comparison and recording need plan fingerprints
+	if cmd.baseline != nil || cmd.onSummary != nil {
+		options.Explain = true
+	}
 
 	verbose := ctx.Verbose
 	options.Verbose = verbose
 
+	// Failure diffs are colored unless NO_COLOR is set (handled by fatih/color) or --quiet is given
+	if ctx.Quiet {
+		color.NoColor = true
+	}
+
 	includePaths, err := cmd.resolveTargetPaths(projectRoot)
 	if err != nil {
 		return err
@@ -126,6 +258,11 @@ func (cmd *TestCmd) Run(ctx *Context) error {
 		return snapsql.ErrNoSchemaYAMLFound
 	}
 
+	// The seed is always shown so that any shuffled run can be reproduced
+	if options.Shuffle {
+		fmt.Printf("Shuffle seed: %d\n", options.ShuffleSeed)
+	}
+
 	if verbose {
 		fmt.Printf("Starting test execution in: %s\n", projectRoot)
 		fmt.Printf("Execution mode: %s\n", mode)
@@ -155,10 +292,22 @@ func (cmd *TestCmd) Run(ctx *Context) error {
 	}
 
 	if len(cmd.Schema) > 0 {
-		return cmd.runWithSchemaDatabase(projectRoot, config, includePaths, options, verbose, runtimeTables)
+		err = cmd.runWithSchemaDatabase(projectRoot, config, includePaths, options, verbose, runtimeTables)
+	} else {
+		err = cmd.runWithTblsDatabase(projectRoot, config, includePaths, options, verbose, runtimeTables, ctx)
 	}
 
-	return cmd.runWithTblsDatabase(projectRoot, config, includePaths, options, verbose, runtimeTables, ctx)
+	if ctx.workspaceMember != "" && cmd.summary != nil {
+		ctx.workspaceTests = &workspaceTestCounts{
+			total:   cmd.summary.TotalTests,
+			passed:  cmd.summary.PassedTests,
+			failed:  cmd.summary.FailedTests,
+			skipped: cmd.summary.SkippedTests,
+			flaky:   cmd.summary.FlakyTests,
+		}
+	}
+
+	return err
 }
 
 func (cmd *TestCmd) resolveTargetPaths(projectRoot string) ([]string, error) {
@@ -214,46 +363,62 @@ func (cmd *TestCmd) resolveTargetPaths(projectRoot string) ([]string, error) {
 func (cmd *TestCmd) runWithSchemaDatabase(projectRoot string, config *snapsql.Config, includePaths []string, options *fixtureexecutor.ExecutionOptions, verbose bool, tableCatalog map[string]*snapsql.TableInfo) error {
 	ctx := context.Background()
 
-	db, cleanup, err := openInMemorySQLite(ctx, verbose)
-	if err != nil {
-		return err
-	}
-	defer cleanup()
+	// Each worker gets its own in-memory database so fixture tests do not serialize on a single connection.
+	workers := max(options.Parallel, 1)
+	dbs := make([]*sql.DB, 0, workers)
 
-	config.Dialect = snapsql.DialectSQLite
+	defer func() {
+		for _, db := range dbs {
+			_ = db.Close()
+		}
+	}()
 
-	if err := cmd.applySchema(ctx, db, cmd.Schema, verbose); err != nil {
-		return err
+	// Shared-cache databases live as long as a connection is open, so the names are unique per run
+	run := inMemorySQLiteRuns.Add(1)
+
+	for i := range workers {
+		db, err := openInMemorySQLite(ctx, fmt.Sprintf("snapsql-test-%d-%d", run, i))
+		if err != nil {
+			return err
+		}
+
+		dbs = append(dbs, db)
+
+		// Schema application is logged once; every worker database receives the same files
+		if err := cmd.applySchema(ctx, db, cmd.Schema, config.Migrations, verbose && i == 0); err != nil {
+			return err
+		}
+	}
+
+	if verbose {
+		fmt.Printf("Provisioned %d in-memory SQLite database(s)\n", len(dbs))
 	}
 
-	return cmd.executeFixtureTests(projectRoot, config, db, tableCatalog, includePaths, options, verbose)
+	config.Dialect = snapsql.DialectSQLite
+
+	return cmd.executeFixtureTests(projectRoot, config, dbs[0], dbs, tableCatalog, includePaths, options, verbose)
 }
 
-func openInMemorySQLite(ctx context.Context, verbose bool) (*sql.DB, func(), error) {
-	dsn := "file:snapsql-test?mode=memory&cache=shared&_foreign_keys=1"
+// inMemorySQLiteRuns numbers the in-memory database sets opened by this process
+var inMemorySQLiteRuns atomic.Uint64
+
+func openInMemorySQLite(ctx context.Context, name string) (*sql.DB, error) {
+	dsn := "file:" + name + "?mode=memory&cache=shared&_foreign_keys=1"
 
 	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to open in-memory sqlite database: %w", err)
+		return nil, fmt.Errorf("failed to open in-memory sqlite database: %w", err)
 	}
 
 	if err := db.PingContext(ctx); err != nil {
 		db.Close()
-		return nil, nil, fmt.Errorf("failed to initialize in-memory sqlite database: %w", err)
+		return nil, fmt.Errorf("failed to initialize in-memory sqlite database: %w", err)
 	}
 
 	db.SetMaxOpenConns(1)
 	db.SetMaxIdleConns(1)
 
-	if verbose {
-		fmt.Println("Provisioned in-memory SQLite database")
-	}
-
-	cleanup := func() {
-		_ = db.Close()
-	}
-
-	return db, cleanup, nil
+	return db, nil
 }
 
 func (cmd *TestCmd) runWithTblsDatabase(projectRoot string, config *snapsql.Config, includePaths []string, options *fixtureexecutor.ExecutionOptions, verbose bool, tableCatalog map[string]*snapsql.TableInfo, appCtx *Context) error {
@@ -267,6 +432,10 @@ func (cmd *TestCmd) runWithTblsDatabase(projectRoot string, config *snapsql.Conf
 		return fmt.Errorf("%w: unsupported database driver: %s", ErrDatabaseConnection, fallback.Driver)
 	}
 
+	if err := checkDriverCompiled(driverName); err != nil {
+		return err
+	}
+
 	dialect := canonicalDialectFromDriver(fallback.Driver)
 	config.Dialect = snapsql.Dialect(dialect)
 
@@ -285,14 +454,18 @@ func (cmd *TestCmd) runWithTblsDatabase(projectRoot string, config *snapsql.Conf
 		return fmt.Errorf("%w: failed to ping database: %s", ErrDatabaseConnection, err.Error())
 	}
 
-	return cmd.executeFixtureTests(projectRoot, config, db, tableCatalog, includePaths, options, verbose)
+	return cmd.executeFixtureTests(projectRoot, config, db, nil, tableCatalog, includePaths, options, verbose)
 }
 
-func (cmd *TestCmd) executeFixtureTests(projectRoot string, config *snapsql.Config, db *sql.DB, tableInfo map[string]*snapsql.TableInfo, includePaths []string, options *fixtureexecutor.ExecutionOptions, verbose bool) error {
+func (cmd *TestCmd) executeFixtureTests(projectRoot string, config *snapsql.Config, db *sql.DB, workerDBs []*sql.DB, tableInfo map[string]*snapsql.TableInfo, includePaths []string, options *fixtureexecutor.ExecutionOptions, verbose bool) error {
 	runner := testrunner.NewFixtureTestRunner(projectRoot, db, config.Dialect)
 	runner.SetVerbose(verbose)
 	runner.SetExecutionOptions(options)
 
+	if len(workerDBs) > 0 {
+		runner.SetWorkerDatabases(workerDBs)
+	}
+
 	if len(tableInfo) > 0 {
 		runner.SetTableInfo(tableInfo)
 	}
@@ -317,17 +490,129 @@ func (cmd *TestCmd) executeFixtureTests(projectRoot string, config *snapsql.Conf
 		return fmt.Errorf("fixture test execution failed: %w", err)
 	}
 
+	cmd.summary = summary
+
+	if cmd.baseline != nil {
+		regressions := testrunner.CheckBaseline(summary, cmd.baseline, testrunner.BaselineCheckOptions{
+			MaxRegressionPercent: config.Performance.Baseline.MaxRegressionPercent,
+			MinRegression:        config.Performance.Baseline.MinRegression,
+			AllowPlanChanges:     config.Performance.Baseline.AllowPlanChanges,
+		})
+
+		if len(regressions) > 0 {
+			color.Red("%d test(s) regressed against the performance baseline", len(regressions))
+		} else if verbose {
+			fmt.Println("No performance regressions against the baseline")
+		}
+	}
+
 	runner.PrintSummary(summary)
 
+	if err := cmd.writeReports(summary); err != nil {
+		return err
+	}
+
 	if summary.FailedTests > 0 {
-		os.Exit(1)
+		return fmt.Errorf("%w: %d of %d tests failed", ErrFixtureTestsFailed, summary.FailedTests, summary.TotalTests)
+	}
+
+	if cmd.onSummary != nil {
+		return cmd.onSummary(config, summary)
 	}
 
 	return nil
 }
 
-func (cmd *TestCmd) applySchema(ctx context.Context, db *sql.DB, schemaPaths []string, verbose bool) error {
-	if len(schemaPaths) == 0 {
+// reportSpec is a parsed --report flag value
+type reportSpec struct {
+	format testrunner.ReportFormat
+	path   string
+}
+
+func parseReportSpecs(values []string) ([]reportSpec, error) {
+	specs := make([]reportSpec, 0, len(values))
+
+	for _, value := range values {
+		format, path, ok := strings.Cut(value, "=")
+		format = strings.ToLower(strings.TrimSpace(format))
+		path = strings.TrimSpace(path)
+
+		if !ok || path == "" {
+			return nil, fmt.Errorf("%w: %q (expected format=path)", ErrInvalidReportSpec, value)
+		}
+
+		switch testrunner.ReportFormat(format) {
+		case testrunner.ReportFormatJUnit, testrunner.ReportFormatJSON, testrunner.ReportFormatSlowQueries:
+		default:
+			return nil, fmt.Errorf("%w: %q (supported formats: junit, json, slow-queries)", ErrInvalidReportSpec, value)
+		}
+
+		specs = append(specs, reportSpec{format: testrunner.ReportFormat(format), path: path})
+	}
+
+	return specs, nil
+}
+
+// absoluteReportSpecs resolves the path part of format=path report flags with absolutePath.
+// Malformed values are kept as they are so that run reports them.
+func absoluteReportSpecs(values []string) []string {
+	resolved := make([]string, len(values))
+
+	for i, value := range values {
+		format, path, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(path) == "" {
+			resolved[i] = value
+			continue
+		}
+
+		resolved[i] = format + "=" + absolutePath(strings.TrimSpace(path))
+	}
+
+	return resolved
+}
+
+// memberReportPath inserts the workspace member name before the extension so that members
+// sharing a --report flag do not overwrite each other's report.
+func memberReportPath(path, member string) string {
+	if member == "" {
+		return path
+	}
+
+	ext := filepath.Ext(path)
+	suffix := strings.ReplaceAll(filepath.ToSlash(member), "/", "-")
+
+	return strings.TrimSuffix(path, ext) + "-" + suffix + ext
+}
+
+func (cmd *TestCmd) writeReports(summary *testrunner.FixtureTestSummary) error {
+	specs, err := parseReportSpecs(cmd.Report)
+	if err != nil {
+		return err
+	}
+
+	for _, spec := range specs {
+		if err := testrunner.WriteReportFile(spec.format, memberReportPath(spec.path, cmd.member), summary); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (cmd *TestCmd) applySchema(ctx context.Context, db *sql.DB, schemaPaths []string, migrations snapsql.MigrationsConfig, verbose bool) error {
+	// The configured migration directory is applied with its tool's rules (up sections only, version table)
+	// whether or not it is listed in --schema; it runs in --schema order when listed, otherwise last.
+	migrationDir := ""
+	if dir := strings.TrimSpace(migrations.Dir); dir != "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("failed to resolve migration directory %s: %w", dir, err)
+		}
+
+		migrationDir = abs
+	}
+
+	if len(schemaPaths) == 0 && migrationDir == "" {
 		if verbose {
 			fmt.Println("No schema paths provided; skipping schema initialization")
 		}
@@ -335,6 +620,8 @@ func (cmd *TestCmd) applySchema(ctx context.Context, db *sql.DB, schemaPaths []s
 		return nil
 	}
 
+	migrationsApplied := false
+
 	for _, rawPath := range schemaPaths {
 		path := strings.TrimSpace(rawPath)
 		if path == "" {
@@ -347,6 +634,16 @@ func (cmd *TestCmd) applySchema(ctx context.Context, db *sql.DB, schemaPaths []s
 		}
 
 		if info.IsDir() {
+			if abs, err := filepath.Abs(path); err == nil && abs == migrationDir {
+				if err := applyMigrations(ctx, db, migrationDir, migrations.Format, verbose); err != nil {
+					return err
+				}
+
+				migrationsApplied = true
+
+				continue
+			}
+
 			files, err := collectSQLFiles(path)
 			if err != nil {
 				return fmt.Errorf("failed to collect SQL files under %s: %w", path, err)
@@ -372,9 +669,35 @@ func (cmd *TestCmd) applySchema(ctx context.Context, db *sql.DB, schemaPaths []s
 		}
 	}
 
+	if migrationDir != "" && !migrationsApplied {
+		return applyMigrations(ctx, db, migrationDir, migrations.Format, verbose)
+	}
+
 	return nil
 }
 
+// applyMigrations applies the pending migrations of dir to the ephemeral SQLite database
+func applyMigrations(ctx context.Context, db *sql.DB, dir, formatName string, verbose bool) error {
+	format, err := migration.ParseFormat(formatName)
+	if err != nil {
+		return err
+	}
+
+	migrations, err := migration.Load(dir, format)
+	if err != nil {
+		return err
+	}
+
+	applied, err := migration.Apply(ctx, db, snapsql.DialectSQLite, format, migrations)
+	if verbose {
+		for _, m := range applied {
+			fmt.Printf("Applied %s migration: %s\n", format, m.Path)
+		}
+	}
+
+	return err
+}
+
 func collectSQLFiles(root string) ([]string, error) {
 	var files []string
 
@@ -428,12 +751,21 @@ var CLI struct {
 	TblsConfig string       `help:"Path to tbls config (.tbls.yaml); overrides --config"`
 	Generate   GenerateCmd  `cmd:"" help:"Generate intermediate files from SQL templates"`
 	Validate   ValidateCmd  `cmd:"" help:"Validate SQL templates"`
+	Lint       LintCmd      `cmd:"" help:"Lint SQL templates for risky patterns"`
 	Init       InitCmd      `cmd:"" help:"Initialize a new SnapSQL project"`
 	Query      QueryCmd     `cmd:"" help:"Execute SQL queries"`
+	Render     RenderCmd    `cmd:"" help:"Expand a template to concrete SQL without executing it"`
 	Test       TestCmd      `cmd:"" help:"Run tests"`
 	Format     FormatCmd    `cmd:"" help:"Format SnapSQL template files"`
 	HelpTypes  HelpTypesCmd `cmd:"help-types" help:"Show detailed information about supported types"`
 	Inspect    InspectCmd   `cmd:"" help:"Inspect an SQL and print JSON summary"`
+	Lsp        LspCmd       `cmd:"" help:"Start a language server on stdin/stdout"`
+	Docs       DocsCmd      `cmd:"" help:"Generate a query catalog from intermediate files"`
+	ExportSQL  ExportSQLCmd `cmd:"export-sql" help:"Write the SQL of each template to a .sql file"`
+	Perf       PerfCmd      `cmd:"" help:"Manage the performance regression baseline"`
+	Schema     SchemaCmd    `cmd:"" help:"Compare the schema snapshot with the database"`
+	Fixtures   FixturesCmd  `cmd:"" help:"Export test fixtures as SQL"`
+	Graph      GraphCmd     `cmd:"" help:"Show which templates, tables and generated functions depend on each other"`
 	Version    VersionCmd   `cmd:"" help:"Show version information"`
 }
 
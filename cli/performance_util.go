@@ -42,6 +42,49 @@ func buildTableMetadataFromConfig(tables map[string]snapsql.TablePerformance) ma
 	return meta
 }
 
+// buildSoftDeleteColumnsFromConfig maps table names (with and without schema) to their soft delete column.
+func buildSoftDeleteColumnsFromConfig(tables map[string]snapsql.TablePerformance) map[string]string {
+	columns := make(map[string]string)
+
+	for rawKey, value := range tables {
+		key := strings.ToLower(strings.TrimSpace(rawKey))
+		if key == "" || value.SoftDelete == nil {
+			continue
+		}
+
+		columns[key] = value.SoftDelete.Column
+
+		if _, table, ok := strings.Cut(key, "."); ok && table != "" {
+			if _, exists := columns[table]; !exists {
+				columns[table] = value.SoftDelete.Column
+			}
+		}
+	}
+
+	if len(columns) == 0 {
+		return nil
+	}
+
+	return columns
+}
+
+func buildExplainRulesFromConfig(rules []snapsql.ExplainRule) []explain.Rule {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	converted := make([]explain.Rule, 0, len(rules))
+	for _, rule := range rules {
+		converted = append(converted, explain.Rule{
+			Kind:    explain.WarningKind(rule.Kind),
+			MinRows: rule.MinRows,
+			Tables:  rule.Tables,
+		})
+	}
+
+	return converted
+}
+
 func formatDuration(d time.Duration) string {
 	if d <= 0 {
 		return "0s"
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/fatih/color"
 	snapsql "github.com/shibukawa/snapsql"
@@ -94,15 +95,92 @@ func resolveDatabaseFromTbls(ctx *Context) (*snapsql.Database, error) {
 	return db, nil
 }
 
+// runtimeTablesCache shares converted tbls schemas between commands run in one process (workspace mode).
+// Entries are keyed by the resolved schema JSON path.
+type runtimeTablesCache struct {
+	mu     sync.Mutex
+	tables map[string]map[string]*snapsql.TableInfo
+}
+
+func newRuntimeTablesCache() *runtimeTablesCache {
+	return &runtimeTablesCache{tables: make(map[string]map[string]*snapsql.TableInfo)}
+}
+
 func loadRuntimeTables(ctx *Context) map[string]*snapsql.TableInfo {
 	opts := buildTblsOptions(ctx)
 
+	cacheKey := ""
+
+	if ctx.runtimeCache != nil {
+		if cfg, err := schemaimport.ResolveConfig(context.Background(), opts); err == nil {
+			cacheKey = cfg.SchemaJSONPath
+		}
+
+		ctx.runtimeCache.mu.Lock()
+		defer ctx.runtimeCache.mu.Unlock()
+
+		if tables, ok := ctx.runtimeCache.tables[cacheKey]; ok && cacheKey != "" {
+			if ctx.Verbose {
+				color.Cyan("Reusing %d cached tables from %s", len(tables), cacheKey)
+			}
+
+			return tables
+		}
+	}
+
 	runtime, err := schemaimport.LoadRuntime(context.Background(), opts)
 	if err != nil {
 		if ctx.Verbose {
 			color.Yellow("tbls runtime unavailable: %v", err)
 		}
 
+		return loadSchemaYAMLTables(ctx)
+	}
+
+	tables := runtime.TablesByName()
+	if len(tables) == 0 {
+		return loadSchemaYAMLTables(ctx)
+	}
+
+	if ctx.Verbose {
+		color.Cyan("Loaded %d tables via tbls schema JSON", len(tables))
+	}
+
+	if ctx.runtimeCache != nil && cacheKey != "" {
+		ctx.runtimeCache.tables[cacheKey] = tables
+	}
+
+	return tables
+}
+
+// schemaYAMLPath returns the schema YAML location configured by schema.file
+func schemaYAMLPath(ctx *Context) string {
+	config, err := LoadConfig(ctx.Config)
+	if err != nil {
+		return ""
+	}
+
+	return config.Schema.File
+}
+
+// loadSchemaYAMLTables loads the tables written by `snapsql schema pull`; it is the fallback
+// for projects that do not use tbls.
+func loadSchemaYAMLTables(ctx *Context) map[string]*snapsql.TableInfo {
+	path := schemaYAMLPath(ctx)
+	if path == "" {
+		return nil
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+
+	runtime, err := schemaimport.LoadSchemaYAML(path)
+	if err != nil {
+		if ctx.Verbose {
+			color.Yellow("schema YAML unavailable: %v", err)
+		}
+
 		return nil
 	}
 
@@ -112,7 +190,7 @@ func loadRuntimeTables(ctx *Context) map[string]*snapsql.TableInfo {
 	}
 
 	if ctx.Verbose {
-		color.Cyan("Loaded %d tables via tbls schema JSON", len(tables))
+		color.Cyan("Loaded %d tables via %s", len(tables), path)
 	}
 
 	return tables
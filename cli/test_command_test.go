@@ -1,9 +1,12 @@
 package cli
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/shibukawa/snapsql/testrunner"
 )
 
 func TestResolveTargetPaths(t *testing.T) {
@@ -43,3 +46,32 @@ func TestResolveTargetPaths(t *testing.T) {
 		t.Fatalf("expected error for path outside project root")
 	}
 }
+
+func TestParseReportSpecs(t *testing.T) {
+	specs, err := parseReportSpecs([]string{"junit=out/report.xml", "JSON = report.json", "slow-queries=slow.json"})
+	if err != nil {
+		t.Fatalf("parseReportSpecs returned error: %v", err)
+	}
+
+	if len(specs) != 3 {
+		t.Fatalf("unexpected spec count: %d", len(specs))
+	}
+
+	if specs[0].format != testrunner.ReportFormatJUnit || specs[0].path != "out/report.xml" {
+		t.Fatalf("unexpected junit spec: %+v", specs[0])
+	}
+
+	if specs[1].format != testrunner.ReportFormatJSON || specs[1].path != "report.json" {
+		t.Fatalf("unexpected json spec: %+v", specs[1])
+	}
+
+	if specs[2].format != testrunner.ReportFormatSlowQueries || specs[2].path != "slow.json" {
+		t.Fatalf("unexpected slow-queries spec: %+v", specs[2])
+	}
+
+	for _, value := range []string{"junit", "junit=", "tap=report.tap"} {
+		if _, err := parseReportSpecs([]string{value}); !errors.Is(err, ErrInvalidReportSpec) {
+			t.Fatalf("expected ErrInvalidReportSpec for %q, got %v", value, err)
+		}
+	}
+}
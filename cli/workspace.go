@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/shibukawa/snapsql"
+)
+
+var ErrWorkspaceMembersFailed = errors.New("workspace members failed")
+
+// WorkspaceFlags enables running a command for every member project of a workspace
+type WorkspaceFlags struct {
+	Workspace     bool   `help:"Run the command for every member project listed in the workspace file"`
+	WorkspaceFile string `help:"Workspace file listing member projects" default:"snapsql.workspace.yaml" type:"path"`
+}
+
+// workspaceMemberResult records the outcome of running a command in one member project
+type workspaceMemberResult struct {
+	member   snapsql.WorkspaceMember
+	duration time.Duration
+	err      error
+	tests    *workspaceTestCounts // nil when the command does not run tests
+}
+
+// workspaceTestCounts holds the test totals of one member, reported by the test command
+type workspaceTestCounts struct {
+	total   int
+	passed  int
+	failed  int
+	skipped int
+	flaky   int
+}
+
+// absolutePath resolves a path flag against the current directory so that it keeps
+// pointing at the same file after runWorkspace changes into a member directory.
+func absolutePath(path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+
+	return abs
+}
+
+// absolutePaths applies absolutePath to every element of a repeatable path flag
+func absolutePaths(paths []string) []string {
+	resolved := make([]string, len(paths))
+	for i, path := range paths {
+		resolved[i] = absolutePath(path)
+	}
+
+	return resolved
+}
+
+// runWorkspace executes run once per workspace member with the member directory as working directory.
+// Members share the tbls runtime cache so that projects pointing at the same schema load it only once.
+// All members are executed even when one fails, and a combined report is printed at the end.
+// Global path flags given at the workspace root are made absolute first; commands resolve their own
+// path flags the same way before calling runWorkspace. Only the default config file name is looked
+// up in each member directory.
+func runWorkspace(ctx *Context, flags WorkspaceFlags, command string, run func(memberCtx *Context) error) error {
+	workspace, err := snapsql.LoadWorkspace(flags.WorkspaceFile)
+	if err != nil {
+		return err
+	}
+
+	members, err := workspace.ResolveMembers()
+	if err != nil {
+		return err
+	}
+
+	originalDir, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	defer func() {
+		_ = os.Chdir(originalDir)
+	}()
+
+	// Explicit config files apply to every member, so they must survive the directory changes
+	tblsConfig := absolutePath(ctx.TblsConfig)
+
+	config := ctx.Config
+	if config != defaultConfigFile {
+		config = absolutePath(config)
+	}
+
+	cache := ctx.runtimeCache
+	if cache == nil {
+		cache = newRuntimeTablesCache()
+	}
+
+	results := make([]workspaceMemberResult, 0, len(members))
+
+	for _, member := range members {
+		if !ctx.Quiet {
+			color.Blue("==> %s %s", command, member.Name)
+		}
+
+		if err := os.Chdir(member.Dir); err != nil {
+			results = append(results, workspaceMemberResult{member: member, err: fmt.Errorf("failed to enter member directory: %w", err)})
+			continue
+		}
+
+		memberCtx := *ctx
+		memberCtx.Config = config
+		memberCtx.TblsConfig = tblsConfig
+		memberCtx.runtimeCache = cache
+		memberCtx.workspaceMember = member.Name
+		memberCtx.workspaceTests = nil
+
+		start := time.Now()
+		err := run(&memberCtx)
+		results = append(results, workspaceMemberResult{member: member, duration: time.Since(start), err: err, tests: memberCtx.workspaceTests})
+	}
+
+	if !ctx.Quiet {
+		printWorkspaceSummary(command, results)
+	}
+
+	var failed []string
+
+	for _, result := range results {
+		if result.err != nil {
+			failed = append(failed, result.member.Name)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("%w: %d of %d (%s)", ErrWorkspaceMembersFailed, len(failed), len(results), strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+func printWorkspaceSummary(command string, results []workspaceMemberResult) {
+	fmt.Fprintln(color.Output)
+	fmt.Fprintf(color.Output, "=== Workspace %s Summary ===\n", command)
+
+	passLabel := color.New(color.Bold, color.FgGreen).Sprint("PASS")
+	failLabel := color.New(color.Bold, color.FgRed).Sprint("FAIL")
+
+	var (
+		failedMembers int
+		tests         workspaceTestCounts
+		hasTests      bool
+		totalDuration time.Duration
+	)
+
+	for _, result := range results {
+		totalDuration += result.duration
+
+		label := passLabel
+		if result.err != nil {
+			label = failLabel
+			failedMembers++
+		}
+
+		detail := ""
+		if result.tests != nil {
+			hasTests = true
+			tests.total += result.tests.total
+			tests.passed += result.tests.passed
+			tests.failed += result.tests.failed
+			tests.skipped += result.tests.skipped
+			tests.flaky += result.tests.flaky
+			detail = fmt.Sprintf(", %d/%d tests passed", result.tests.passed, result.tests.total)
+		}
+
+		fmt.Fprintf(color.Output, "  %s %s (%.3fs%s)\n", label, result.member.Name, result.duration.Seconds(), detail)
+
+		if result.err != nil {
+			fmt.Fprintf(color.Output, "    Error: %v\n", result.err)
+		}
+	}
+
+	fmt.Fprintf(color.Output, "Members: %d total, %d passed, %d failed\n", len(results), len(results)-failedMembers, failedMembers)
+
+	if hasTests {
+		fmt.Fprintf(color.Output, "Tests: %d total, %d passed, %d failed, %d skipped, %d flaky\n",
+			tests.total, tests.passed, tests.failed, tests.skipped, tests.flaky)
+	}
+
+	fmt.Fprintf(color.Output, "Duration: %.3fs\n", totalDuration.Seconds())
+}
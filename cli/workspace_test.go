@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql"
+)
+
+var errWorkspaceTestMember = errors.New("member failed")
+
+func TestRunWorkspace(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"api", "batch"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0o755))
+	}
+
+	workspaceFile := filepath.Join(root, snapsql.DefaultWorkspaceFile)
+	assert.NoError(t, os.WriteFile(workspaceFile, []byte("members:\n  - api\n  - batch\n"), 0o644))
+
+	originalDir, err := os.Getwd()
+	assert.NoError(t, err)
+
+	var (
+		visited []string
+		caches  []*runtimeTablesCache
+	)
+
+	err = runWorkspace(&Context{Quiet: true}, WorkspaceFlags{Workspace: true, WorkspaceFile: workspaceFile}, "test", func(memberCtx *Context) error {
+		cwd, err := os.Getwd()
+		assert.NoError(t, err)
+
+		visited = append(visited, filepath.Base(cwd))
+		caches = append(caches, memberCtx.runtimeCache)
+
+		if filepath.Base(cwd) == "api" {
+			return errWorkspaceTestMember
+		}
+
+		return nil
+	})
+
+	assert.IsError(t, err, ErrWorkspaceMembersFailed)
+	assert.Equal(t, []string{"api", "batch"}, visited)
+	assert.True(t, caches[0] != nil && caches[0] == caches[1], "members should share the runtime cache")
+
+	cwd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.Equal(t, originalDir, cwd)
+}
+
+func TestRunWorkspaceResolvesPathFlagsAtRoot(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"api", "batch"} {
+		assert.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0o755))
+	}
+
+	assert.NoError(t, os.WriteFile(filepath.Join(root, snapsql.DefaultWorkspaceFile), []byte("members:\n  - api\n  - batch\n"), 0o644))
+	t.Chdir(root)
+
+	cmd := &ValidateCmd{Files: []string{"shared/a.snap.sql"}, WorkspaceFlags: WorkspaceFlags{Workspace: true, WorkspaceFile: snapsql.DefaultWorkspaceFile}}
+	cmd.Files = absolutePaths(cmd.Files)
+
+	var (
+		configs []string
+		tbls    []string
+		members []string
+	)
+
+	ctx := &Context{Quiet: true, Config: "configs/snapsql.yaml", TblsConfig: ".tbls.yaml"}
+	err := runWorkspace(ctx, cmd.WorkspaceFlags, "validate", func(memberCtx *Context) error {
+		configs = append(configs, memberCtx.Config)
+		tbls = append(tbls, memberCtx.TblsConfig)
+		members = append(members, memberCtx.workspaceMember)
+
+		memberCtx.workspaceTests = &workspaceTestCounts{total: 2, passed: 2}
+
+		return nil
+	})
+	assert.NoError(t, err)
+
+	rootConfig := filepath.Join(root, "configs", "snapsql.yaml")
+	rootTbls := filepath.Join(root, ".tbls.yaml")
+	assert.Equal(t, []string{rootConfig, rootConfig}, configs)
+	assert.Equal(t, []string{rootTbls, rootTbls}, tbls)
+	assert.Equal(t, []string{"api", "batch"}, members)
+	assert.Equal(t, []string{filepath.Join(root, "shared", "a.snap.sql")}, cmd.Files)
+
+	// The default config file name is looked up in each member
+	ctx.Config = defaultConfigFile
+	configs = nil
+
+	err = runWorkspace(ctx, cmd.WorkspaceFlags, "validate", func(memberCtx *Context) error {
+		configs = append(configs, memberCtx.Config)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{defaultConfigFile, defaultConfigFile}, configs)
+}
+
+func TestWorkspaceReportPaths(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+
+	specs := absoluteReportSpecs([]string{"junit=reports/junit.xml", "json"})
+	assert.Equal(t, []string{"junit=" + filepath.Join(root, "reports", "junit.xml"), "json"}, specs)
+
+	assert.Equal(t, "reports/junit.xml", memberReportPath("reports/junit.xml", ""))
+	assert.Equal(t, "reports/junit-services-api.xml", memberReportPath("reports/junit.xml", "services/api"))
+}
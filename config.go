@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/goccy/go-yaml"
@@ -17,15 +20,25 @@ var ErrConfigValidation = errors.New("configuration validation failed")
 
 // Config represents the SnapSQL configuration
 type Config struct {
-	Dialect       Dialect                     `yaml:"dialect"`
-	InputDir      string                      `yaml:"input_dir"` // Moved from GenerationConfig
-	ConstantFiles []string                    `yaml:"constant_files"`
-	Generation    GenerationConfig            `yaml:"generation"`
-	Validation    ValidationConfig            `yaml:"validation"`
-	Query         QueryConfig                 `yaml:"query"`
-	System        SystemConfig                `yaml:"system"`
-	Performance   PerformanceConfig           `yaml:"performance"`
-	Tables        map[string]TablePerformance `yaml:"tables"`
+	Dialect       Dialect                      `yaml:"dialect"`
+	InputDir      string                       `yaml:"input_dir"` // Moved from GenerationConfig
+	ConstantFiles []string                     `yaml:"constant_files"`
+	Constants     map[string]any               `yaml:"constants"`
+	Generation    GenerationConfig             `yaml:"generation"`
+	Validation    ValidationConfig             `yaml:"validation"`
+	Lint          LintConfig                   `yaml:"lint"`
+	Query         QueryConfig                  `yaml:"query"`
+	Limits        LimitsConfig                 `yaml:"limits"`
+	WhereGuard    WhereGuardConfig             `yaml:"where_guard"`
+	Execution     ExecutionConfig              `yaml:"execution"`
+	System        SystemConfig                 `yaml:"system"`
+	Performance   PerformanceConfig            `yaml:"performance"`
+	Tables        map[string]TablePerformance  `yaml:"tables"`
+	QueryLog      QueryLogConfig               `yaml:"query_log"`
+	Schema        SchemaConfig                 `yaml:"schema"`
+	Migrations    MigrationsConfig             `yaml:"migrations"`
+	CELFunctions  map[string]CELFunctionConfig `yaml:"cel_functions"`
+	TypeMappings  []TypeMappingConfig          `yaml:"type_mappings"`
 }
 
 // Database represents database connection configuration
@@ -65,6 +78,12 @@ type ValidationConfig struct {
 	Rules  []string `yaml:"rules"`
 }
 
+// LintConfig represents settings for the lint command
+type LintConfig struct {
+	// Rules overrides the severity per rule ID (error, warning, info, off)
+	Rules map[string]string `yaml:"rules"`
+}
+
 // QueryConfig represents query execution settings
 type QueryConfig struct {
 	DefaultFormat         string `yaml:"default_format"`
@@ -76,15 +95,234 @@ type QueryConfig struct {
 	ExecuteDangerousQuery bool   `yaml:"execute_dangerous_query"`
 }
 
+// LimitsConfig defines LIMIT guard rails for generated SELECT functions
+type LimitsConfig struct {
+	// MaxLimit caps the rows every SELECT may return (0: no cap). SELECTs without LIMIT get
+	// LIMIT MaxLimit and LIMIT parameters are clamped to it by generated code
+	MaxLimit int `yaml:"max_limit"`
+	// Queries overrides MaxLimit per function name; keys may be path.Match patterns such as "list_*"
+	// and 0 removes the cap for the matching functions
+	Queries map[string]int `yaml:"queries"`
+	// Strict makes generated code return an error when a SELECT without any LIMIT is executed
+	Strict bool `yaml:"strict"`
+}
+
+// MaxLimitFor returns the LIMIT cap of the function. An exact Queries entry wins over patterns,
+// patterns are tried in lexical order, and MaxLimit is used when nothing matches.
+func (c LimitsConfig) MaxLimitFor(functionName string) int {
+	if limit, ok := lookupFunctionPattern(c.Queries, functionName); ok {
+		return limit
+	}
+
+	return c.MaxLimit
+}
+
+// ExecutionConfig declares the execution policies that generated functions apply (execution)
+type ExecutionConfig struct {
+	// Functions maps function names to their policy; keys may be path.Match patterns such as "list_*"
+	Functions map[string]ExecutionPolicy `yaml:"functions"`
+}
+
+// ExecutionPolicy bounds the execution of a generated function. Generated code can override it
+// at runtime with snapsqlgo.WithTimeout, WithMaxRows and WithReadOnly.
+type ExecutionPolicy struct {
+	// Timeout bounds each execution with a context deadline and, on PostgreSQL inside a
+	// transaction, SET LOCAL statement_timeout (0: no timeout)
+	Timeout time.Duration `yaml:"timeout"`
+	// MaxRows makes a query fail when it returns more rows (0: no limit)
+	MaxRows int `yaml:"max_rows"`
+	// ReadOnly rejects INSERT/UPDATE/DELETE functions at generation time, and mutations at runtime
+	// when it is enabled with snapsqlgo.WithReadOnly
+	ReadOnly bool `yaml:"read_only"`
+}
+
+// PolicyFor returns the execution policy of the function. An exact Functions entry wins over
+// patterns, and patterns are tried in lexical order.
+func (c ExecutionConfig) PolicyFor(functionName string) ExecutionPolicy {
+	policy, _ := lookupFunctionPattern(c.Functions, functionName)
+	return policy
+}
+
+// lookupFunctionPattern finds the entry of a map keyed by function names or path.Match patterns
+func lookupFunctionPattern[V any](entries map[string]V, functionName string) (V, bool) {
+	if value, ok := entries[functionName]; ok {
+		return value, true
+	}
+
+	patterns := make([]string, 0, len(entries))
+	for pattern := range entries {
+		patterns = append(patterns, pattern)
+	}
+
+	sort.Strings(patterns)
+
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, functionName); err == nil && matched {
+			return entries[pattern], true
+		}
+	}
+
+	var zero V
+
+	return zero, false
+}
+
+// WhereGuardConfig controls the WHERE clause guard of generated UPDATE/DELETE functions
+type WhereGuardConfig struct {
+	// Mode is "error" (default) to reject mutations without WHERE, or "warn" to report and run them
+	Mode string `yaml:"mode"`
+	// Allow lists function names (or path.Match patterns) that intentionally mutate whole tables
+	Allow []string `yaml:"allow"`
+	// Audit is a snapsqlgo.WhereGuardAudit called for every mutation without WHERE:
+	// "import/path.FuncName", or "FuncName" for a function defined in the generated package
+	Audit string `yaml:"audit"`
+}
+
+// Allows reports whether the function is listed in Allow.
+func (c WhereGuardConfig) Allows(functionName string) bool {
+	for _, pattern := range c.Allow {
+		if matched, err := path.Match(pattern, functionName); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AuditFunction splits Audit into the import path (empty for the generated package) and the function name.
+func (c WhereGuardConfig) AuditFunction() (importPath, name string) {
+	return splitGoFunction(c.Audit)
+}
+
 // PerformanceConfig represents performance-related defaults
 type PerformanceConfig struct {
 	SlowQueryThreshold time.Duration `yaml:"slow_query_threshold"`
+	// ExplainRules fail tests whose plans match them when `snapsql test --explain` is used
+	ExplainRules []ExplainRule `yaml:"explain_rules"`
+	// Baseline configures `snapsql perf baseline` and `snapsql test --perf-check`
+	Baseline BaselineConfig `yaml:"baseline"`
+}
+
+// BaselineConfig controls the performance regression baseline
+type BaselineConfig struct {
+	// File is the baseline file path (default: .snapsql/perf-baseline.json)
+	File string `yaml:"file"`
+	// MaxRegressionPercent is the allowed slowdown against the baseline (default: 20)
+	MaxRegressionPercent float64 `yaml:"max_regression_percent"`
+	// MinRegression ignores slowdowns shorter than this duration (default: 1ms)
+	MinRegression time.Duration `yaml:"min_regression"`
+	// AllowPlanChanges keeps tests passing when the plan fingerprint differs from the baseline
+	AllowPlanChanges bool `yaml:"allow_plan_changes"`
+}
+
+// ExplainRule describes a plan pattern that fails a test
+type ExplainRule struct {
+	// Kind is "full_scan" or "slow_query"
+	Kind string `yaml:"kind"`
+	// MinRows limits full_scan rules to tables with at least this many rows
+	MinRows int64 `yaml:"min_rows"`
+	// Tables limits the rule to specific tables (all tables when empty)
+	Tables []string `yaml:"tables"`
+}
+
+// QueryLogConfig controls how generated code reports query arguments to query loggers
+type QueryLogConfig struct {
+	// Redact lists parameter names whose bound values are masked in query logs.
+	// Names match case-insensitively against each segment of a parameter path (e.g. user.email).
+	Redact []string `yaml:"redact"`
+}
+
+// SchemaConfig controls the schema YAML written by `snapsql schema pull`
+type SchemaConfig struct {
+	// File is the schema YAML path, used when no tbls schema JSON is available (default: .snapsql/schema.yaml)
+	File string `yaml:"file"`
 }
 
+// MigrationsConfig points at a migration directory that `snapsql test --schema` applies
+// to the ephemeral database instead of executing the files as plain SQL
+type MigrationsConfig struct {
+	// Dir is the migration directory
+	Dir string `yaml:"dir"`
+	// Format is the migration tool layout: goose (default), golang-migrate, or atlas
+	Format string `yaml:"format"`
+}
+
+// CELFunctionConfig registers a function that templates may call in /*= */ and
+// /*# if */ expressions, e.g. /*= normalizeEmail(user.email) */
+type CELFunctionConfig struct {
+	// Args lists the snapsql types of the arguments
+	Args []string `yaml:"args"`
+	// Returns is the snapsql type of the result
+	Returns string `yaml:"returns"`
+	// Go is the implementation called by generated Go code: "import/path.FuncName",
+	// or "FuncName" for a function defined in the generated package
+	Go string `yaml:"go"`
+}
+
+// GoFunction splits Go into the import path (empty for the generated package) and the function name.
+func (f CELFunctionConfig) GoFunction() (importPath, name string) {
+	return splitGoFunction(f.Go)
+}
+
+// splitGoFunction splits "import/path.FuncName" into the import path and the function name.
+func splitGoFunction(function string) (importPath, name string) {
+	slash := strings.LastIndex(function, "/")
+
+	dot := strings.LastIndex(function, ".")
+	if dot <= slash {
+		return "", function
+	}
+
+	return function[:dot], function[dot+1:]
+}
+
+// TypeMappingConfig maps result columns to a Go type in place of the one derived from their
+// snapsql type, e.g. uuid columns to uuid.UUID. A mapping matches by the database type of the
+// column, by the column name, or by both when both are given.
+type TypeMappingConfig struct {
+	// ColumnType is the database type of the column (e.g. uuid, jsonb, inet or an enum type), matched case-insensitively
+	ColumnType string `yaml:"column_type"`
+	// Column is a glob pattern (path.Match) matched against the result column name, e.g. "*_uuid"
+	Column string `yaml:"column"`
+	// Go is the Go type: "import/path.TypeName", or "TypeName" for a type defined in the generated package
+	Go string `yaml:"go"`
+}
+
+// GoType splits Go into the import path (empty for the generated package) and the type name.
+func (m TypeMappingConfig) GoType() (importPath, name string) {
+	return splitGoFunction(m.Go)
+}
+
+// Matches reports whether the mapping applies to a column with the database type and name.
+func (m TypeMappingConfig) Matches(databaseType, column string) bool {
+	if m.ColumnType != "" && !strings.EqualFold(m.ColumnType, databaseType) {
+		return false
+	}
+
+	if m.Column != "" {
+		if ok, err := path.Match(strings.ToLower(m.Column), strings.ToLower(column)); err != nil || !ok {
+			return false
+		}
+	}
+
+	return m.ColumnType != "" || m.Column != ""
+}
+
+var celFunctionNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
 // TablePerformance defines per-table performance metadata
 type TablePerformance struct {
-	ExpectedRows  int64 `yaml:"expected_rows"`
-	AllowFullScan bool  `yaml:"allow_full_scan"`
+	ExpectedRows  int64             `yaml:"expected_rows"`
+	AllowFullScan bool              `yaml:"allow_full_scan"`
+	SoftDelete    *SoftDeleteConfig `yaml:"soft_delete"`
+}
+
+// SoftDeleteConfig marks a table as soft-deleted through a timestamp column.
+// SELECTs on the table get "<column> IS NULL" appended to their WHERE clause and,
+// when RewriteDelete is set, DELETE statements are rewritten to set the column instead.
+type SoftDeleteConfig struct {
+	Column        string `yaml:"column"`
+	RewriteDelete bool   `yaml:"rewrite_delete"`
 }
 
 // SystemConfig represents system-level configuration
@@ -192,6 +430,7 @@ func isValidGenerator(name string) bool {
 		"go":         true,
 		"typescript": true,
 		"mock":       true,
+		"openapi":    true,
 		"python":     true, // Python generator is now built-in
 	}
 
@@ -211,13 +450,16 @@ func isValidGenerator(name string) bool {
 func validateConfig(config *Config) error {
 	// Validate dialect
 	validDialects := map[Dialect]bool{
-		DialectPostgres: true,
-		DialectMySQL:    true,
-		DialectSQLite:   true,
-		DialectMariaDB:  true,
+		DialectPostgres:   true,
+		DialectMySQL:      true,
+		DialectSQLite:     true,
+		DialectMariaDB:    true,
+		DialectDuckDB:     true,
+		DialectClickHouse: true,
+		DialectCockroach:  true,
 	}
 	if config.Dialect != "" && !validDialects[config.Dialect] {
-		return fmt.Errorf("%w: invalid dialect '%s': must be one of postgres, mysql, sqlite, mariadb", ErrConfigValidation, config.Dialect)
+		return fmt.Errorf("%w: invalid dialect '%s': must be one of postgres, mysql, sqlite, mariadb, duckdb, clickhouse, cockroach", ErrConfigValidation, config.Dialect)
 	}
 
 	// Validate generator configurations
@@ -229,7 +471,7 @@ func validateConfig(config *Config) error {
 
 		// Validate generator is either built-in or has external command available
 		if !isValidGenerator(name) {
-			return fmt.Errorf("%w: unknown generator '%s': must be a built-in generator (json, go, typescript, mock, python) or have 'snapsql-gen-%s' command in PATH", ErrConfigValidation, name, name)
+			return fmt.Errorf("%w: unknown generator '%s': must be a built-in generator (json, go, typescript, mock, python, openapi) or have 'snapsql-gen-%s' command in PATH", ErrConfigValidation, name, name)
 		}
 	}
 
@@ -264,6 +506,30 @@ func validateConfig(config *Config) error {
 		}
 	}
 
+	// Validate type mappings
+	for i, mapping := range config.TypeMappings {
+		if mapping.ColumnType == "" && mapping.Column == "" {
+			return fmt.Errorf("%w: type_mappings[%d]: column_type or column is required", ErrConfigValidation, i)
+		}
+
+		if _, name := mapping.GoType(); !celFunctionNamePattern.MatchString(name) {
+			return fmt.Errorf("%w: type_mappings[%d]: invalid go type '%s': must be 'import/path.TypeName' or 'TypeName'", ErrConfigValidation, i, mapping.Go)
+		}
+
+		if _, err := path.Match(mapping.Column, ""); err != nil {
+			return fmt.Errorf("%w: type_mappings[%d]: invalid column pattern '%s': %w", ErrConfigValidation, i, mapping.Column, err)
+		}
+	}
+
+	// Validate lint rule severities (rule IDs are checked by the lint command)
+	for rule, severity := range config.Lint.Rules {
+		switch severity {
+		case "error", "warning", "info", "off":
+		default:
+			return fmt.Errorf("%w: lint rule '%s': invalid severity '%s': must be one of error, warning, info, off", ErrConfigValidation, rule, severity)
+		}
+	}
+
 	// Validate query configuration
 	if config.Query.Timeout < 0 {
 		return fmt.Errorf("%w: query.timeout must be non-negative, got %d", ErrConfigValidation, config.Query.Timeout)
@@ -281,6 +547,50 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("%w: query.offset must be non-negative, got %d", ErrConfigValidation, config.Query.Offset)
 	}
 
+	// Validate LIMIT guard rails
+	if config.Limits.MaxLimit < 0 {
+		return fmt.Errorf("%w: limits.max_limit must be non-negative, got %d", ErrConfigValidation, config.Limits.MaxLimit)
+	}
+
+	for name, limit := range config.Limits.Queries {
+		if limit < 0 {
+			return fmt.Errorf("%w: limits.queries.%s must be non-negative, got %d", ErrConfigValidation, name, limit)
+		}
+
+		if _, err := path.Match(name, ""); err != nil {
+			return fmt.Errorf("%w: limits.queries: invalid pattern '%s': %w", ErrConfigValidation, name, err)
+		}
+	}
+
+	for name, policy := range config.Execution.Functions {
+		if policy.Timeout < 0 || policy.MaxRows < 0 {
+			return fmt.Errorf("%w: execution.functions.%s: timeout and max_rows must be non-negative", ErrConfigValidation, name)
+		}
+
+		if _, err := path.Match(name, ""); err != nil {
+			return fmt.Errorf("%w: execution.functions: invalid pattern '%s': %w", ErrConfigValidation, name, err)
+		}
+	}
+
+	// Validate WHERE guard policy
+	switch config.WhereGuard.Mode {
+	case "", "error", "warn":
+	default:
+		return fmt.Errorf("%w: where_guard.mode '%s' is invalid: must be one of error, warn", ErrConfigValidation, config.WhereGuard.Mode)
+	}
+
+	for _, pattern := range config.WhereGuard.Allow {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%w: where_guard.allow: invalid pattern '%s': %w", ErrConfigValidation, pattern, err)
+		}
+	}
+
+	if config.WhereGuard.Audit != "" {
+		if _, name := config.WhereGuard.AuditFunction(); !celFunctionNamePattern.MatchString(name) {
+			return fmt.Errorf("%w: where_guard.audit '%s' is invalid: must be import/path.FuncName or FuncName", ErrConfigValidation, config.WhereGuard.Audit)
+		}
+	}
+
 	// Validate default format
 	if config.Query.DefaultFormat != "" {
 		validFormats := map[string]bool{
@@ -298,10 +608,55 @@ func validateConfig(config *Config) error {
 		return fmt.Errorf("%w: performance.slow_query_threshold must be >= 0, got %s", ErrConfigValidation, config.Performance.SlowQueryThreshold)
 	}
 
+	for i, rule := range config.Performance.ExplainRules {
+		switch rule.Kind {
+		case "full_scan", "slow_query":
+		default:
+			return fmt.Errorf("%w: performance.explain_rules[%d].kind '%s' is invalid: must be one of full_scan, slow_query", ErrConfigValidation, i, rule.Kind)
+		}
+
+		if rule.MinRows < 0 {
+			return fmt.Errorf("%w: performance.explain_rules[%d].min_rows must be >= 0, got %d", ErrConfigValidation, i, rule.MinRows)
+		}
+	}
+
+	if config.Performance.Baseline.MaxRegressionPercent < 0 {
+		return fmt.Errorf("%w: performance.baseline.max_regression_percent must be >= 0, got %g", ErrConfigValidation, config.Performance.Baseline.MaxRegressionPercent)
+	}
+
+	if config.Performance.Baseline.MinRegression < 0 {
+		return fmt.Errorf("%w: performance.baseline.min_regression must be >= 0, got %s", ErrConfigValidation, config.Performance.Baseline.MinRegression)
+	}
+
+	switch config.Migrations.Format {
+	case "", "goose", "golang-migrate", "atlas":
+	default:
+		return fmt.Errorf("%w: migrations.format '%s' is invalid: must be one of goose, golang-migrate, atlas", ErrConfigValidation, config.Migrations.Format)
+	}
+
+	for name, fn := range config.CELFunctions {
+		if !celFunctionNamePattern.MatchString(name) {
+			return fmt.Errorf("%w: cel_functions: '%s' is not a valid function name", ErrConfigValidation, name)
+		}
+
+		if strings.TrimSpace(fn.Returns) == "" {
+			return fmt.Errorf("%w: cel_functions.%s.returns is required", ErrConfigValidation, name)
+		}
+
+		if _, goName := fn.GoFunction(); !celFunctionNamePattern.MatchString(goName) {
+			return fmt.Errorf("%w: cel_functions.%s.go '%s' is invalid: must be import/path.FuncName or FuncName", ErrConfigValidation, name, fn.Go)
+		}
+	}
+
 	for tableName, meta := range config.Tables {
-		if meta.ExpectedRows <= 0 {
+		// expected_rows may be omitted for tables that only configure soft delete
+		if meta.ExpectedRows < 0 || (meta.ExpectedRows == 0 && meta.SoftDelete == nil) {
 			return fmt.Errorf("%w: tables.%s.expected_rows must be a positive integer", ErrConfigValidation, tableName)
 		}
+
+		if meta.SoftDelete != nil && strings.TrimSpace(meta.SoftDelete.Column) == "" {
+			return fmt.Errorf("%w: tables.%s.soft_delete.column must not be empty", ErrConfigValidation, tableName)
+		}
 	}
 
 	return nil
@@ -419,8 +774,14 @@ func getDefaultConfig() *Config {
 		},
 		Performance: PerformanceConfig{
 			SlowQueryThreshold: 3 * time.Second,
+			Baseline: BaselineConfig{
+				File:                 ".snapsql/perf-baseline.json",
+				MaxRegressionPercent: 20,
+				MinRegression:        time.Millisecond,
+			},
 		},
 		Tables: make(map[string]TablePerformance),
+		Schema: SchemaConfig{File: ".snapsql/schema.yaml"},
 	}
 }
 
@@ -501,10 +862,26 @@ func applyDefaults(config *Config) {
 		config.Performance.SlowQueryThreshold = 3 * time.Second
 	}
 
+	if config.Performance.Baseline.File == "" {
+		config.Performance.Baseline.File = ".snapsql/perf-baseline.json"
+	}
+
+	if config.Performance.Baseline.MaxRegressionPercent == 0 {
+		config.Performance.Baseline.MaxRegressionPercent = 20
+	}
+
+	if config.Performance.Baseline.MinRegression == 0 {
+		config.Performance.Baseline.MinRegression = time.Millisecond
+	}
+
 	if config.Tables == nil {
 		config.Tables = make(map[string]TablePerformance)
 	}
 
+	if config.Schema.File == "" {
+		config.Schema.File = ".snapsql/schema.yaml"
+	}
+
 	// NOTE: Do NOT automatically apply system field defaults when loading an
 	// existing configuration file. The initial default config returned by
 	// getDefaultConfig() contains sane system field defaults for first-time
@@ -590,6 +967,35 @@ func (c *Config) GetSystemField(fieldName string) (SystemField, bool) {
 	return SystemField{}, false
 }
 
+// GetSoftDelete returns the soft delete configuration for a table.
+// Names match case-insensitively; a key or name without a schema prefix matches any schema.
+func (c *Config) GetSoftDelete(tableName string) (SoftDeleteConfig, bool) {
+	if c == nil || tableName == "" {
+		return SoftDeleteConfig{}, false
+	}
+
+	name := strings.ToLower(tableName)
+	_, bareName, qualified := strings.Cut(name, ".")
+
+	for key, meta := range c.Tables {
+		if meta.SoftDelete == nil {
+			continue
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+		_, bareKey, keyQualified := strings.Cut(key, ".")
+
+		switch {
+		case key == name,
+			qualified && !keyQualified && key == bareName,
+			!qualified && keyQualified && bareKey == name:
+			return *meta.SoftDelete, true
+		}
+	}
+
+	return SoftDeleteConfig{}, false
+}
+
 // ShouldExcludeFromSelect checks if a specific system field should be excluded from SELECT statements by default
 func (c *Config) ShouldExcludeFromSelect(fieldName string) bool {
 	field, exists := c.GetSystemField(fieldName)
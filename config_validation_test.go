@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/alecthomas/assert/v2"
+	"github.com/goccy/go-yaml"
 )
 
 func TestLoadConfig_StrictMode_UnknownKeys(t *testing.T) {
@@ -62,6 +63,8 @@ generation:
 	assert.Equal(t, "postgres", config.Dialect)
 	assert.Equal(t, "./queries", config.InputDir)
 	assert.Equal(t, 3*time.Second, config.Performance.SlowQueryThreshold)
+	assert.Equal(t, ".snapsql/perf-baseline.json", config.Performance.Baseline.File)
+	assert.Equal(t, 20.0, config.Performance.Baseline.MaxRegressionPercent)
 	assert.Equal(t, 0, len(config.Tables))
 
 	// JSON generator should be enabled by default
@@ -148,6 +151,202 @@ func TestValidateConfig_InvalidQueryTimeout(t *testing.T) {
 	assert.Contains(t, err.Error(), "query.timeout must be non-negative")
 }
 
+func TestValidateConfig_InvalidLimits(t *testing.T) {
+	config := &Config{
+		Dialect: "postgres",
+		Limits: LimitsConfig{
+			Queries: map[string]int{"list_*": -1},
+		},
+	}
+
+	err := validateConfig(config)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "limits.queries.list_* must be non-negative")
+
+	config.Limits.Queries = map[string]int{"list_[": 10}
+	err = validateConfig(config)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "limits.queries: invalid pattern 'list_['")
+}
+
+func TestValidateConfig_InvalidWhereGuard(t *testing.T) {
+	config := &Config{
+		Dialect:    "postgres",
+		WhereGuard: WhereGuardConfig{Mode: "ignore"},
+	}
+
+	err := validateConfig(config)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "where_guard.mode 'ignore' is invalid")
+
+	config.WhereGuard = WhereGuardConfig{Audit: "github.com/acme/audit."}
+	err = validateConfig(config)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "where_guard.audit 'github.com/acme/audit.' is invalid")
+}
+
+func TestWhereGuardConfig(t *testing.T) {
+	guard := WhereGuardConfig{
+		Allow: []string{"purge_*", "reset_counters"},
+		Audit: "github.com/acme/app/dbaudit.WhereGuard",
+	}
+
+	assert.True(t, guard.Allows("purge_sessions"))
+	assert.True(t, guard.Allows("reset_counters"))
+	assert.False(t, guard.Allows("delete_user"))
+
+	importPath, name := guard.AuditFunction()
+	assert.Equal(t, "github.com/acme/app/dbaudit", importPath)
+	assert.Equal(t, "WhereGuard", name)
+}
+
+func TestLimitsConfig_MaxLimitFor(t *testing.T) {
+	limits := LimitsConfig{
+		MaxLimit: 1000,
+		Queries: map[string]int{
+			"list_*":        100,
+			"list_all_logs": 0,
+			"*_report":      5000,
+		},
+	}
+
+	assert.Equal(t, 1000, limits.MaxLimitFor("find_user"))
+	assert.Equal(t, 100, limits.MaxLimitFor("list_users"))
+	assert.Equal(t, 0, limits.MaxLimitFor("list_all_logs"))
+	assert.Equal(t, 5000, limits.MaxLimitFor("sales_report"))
+}
+
+func TestExecutionConfig_PolicyFor(t *testing.T) {
+	var config Config
+
+	err := yaml.Unmarshal([]byte(`
+execution:
+  functions:
+    list_*:
+      timeout: 5s
+      max_rows: 1000
+    list_audit_logs:
+      timeout: 30s
+    find_user:
+      read_only: true
+`), &config)
+	assert.NoError(t, err)
+	assert.NoError(t, validateConfig(&Config{Dialect: "postgres", Execution: config.Execution}))
+
+	execution := config.Execution
+	assert.Equal(t, ExecutionPolicy{Timeout: 5 * time.Second, MaxRows: 1000}, execution.PolicyFor("list_users"))
+	assert.Equal(t, ExecutionPolicy{Timeout: 30 * time.Second}, execution.PolicyFor("list_audit_logs"))
+	assert.Equal(t, ExecutionPolicy{ReadOnly: true}, execution.PolicyFor("find_user"))
+	assert.Equal(t, ExecutionPolicy{}, execution.PolicyFor("delete_user"))
+
+	execution.Functions["bad"] = ExecutionPolicy{MaxRows: -1}
+	err = validateConfig(&Config{Dialect: "postgres", Execution: execution})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "execution.functions.bad")
+}
+
+func TestValidateConfig_InvalidLintSeverity(t *testing.T) {
+	config := &Config{
+		Dialect: "postgres",
+		Lint: LintConfig{
+			Rules: map[string]string{"no-select-star": "fatal"},
+		},
+	}
+
+	err := validateConfig(config)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "lint rule 'no-select-star': invalid severity 'fatal'")
+}
+
+func TestValidateConfig_InvalidExplainRule(t *testing.T) {
+	config := &Config{
+		Dialect: "postgres",
+		Performance: PerformanceConfig{
+			ExplainRules: []ExplainRule{{Kind: "full_scan", MinRows: 1000}, {Kind: "index_scan"}},
+		},
+	}
+
+	err := validateConfig(config)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "performance.explain_rules[1].kind 'index_scan' is invalid")
+}
+
+func TestValidateConfig_InvalidBaseline(t *testing.T) {
+	config := &Config{
+		Dialect: "postgres",
+		Performance: PerformanceConfig{
+			Baseline: BaselineConfig{MaxRegressionPercent: -5},
+		},
+	}
+
+	err := validateConfig(config)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "performance.baseline.max_regression_percent")
+}
+
+func TestValidateConfig_InvalidMigrationFormat(t *testing.T) {
+	config := &Config{
+		Dialect:    "postgres",
+		Migrations: MigrationsConfig{Dir: "db/migrations", Format: "flyway"},
+	}
+
+	err := validateConfig(config)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "migrations.format 'flyway' is invalid")
+}
+
+func TestValidateConfig_CELFunctions(t *testing.T) {
+	config := &Config{
+		Dialect: "postgres",
+		CELFunctions: map[string]CELFunctionConfig{
+			"normalizeEmail": {Args: []string{"string"}, Returns: "string", Go: "github.com/acme/helpers.NormalizeEmail"},
+			"localHelper":    {Args: []string{"int"}, Returns: "int", Go: "localHelper"},
+		},
+	}
+	assert.NoError(t, validateConfig(config))
+
+	importPath, name := config.CELFunctions["normalizeEmail"].GoFunction()
+	assert.Equal(t, "github.com/acme/helpers", importPath)
+	assert.Equal(t, "NormalizeEmail", name)
+
+	config.CELFunctions["broken"] = CELFunctionConfig{Returns: "string", Go: "github.com/acme/helpers"}
+
+	err := validateConfig(config)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "cel_functions.broken.go")
+}
+
+func TestValidateConfig_TypeMappings(t *testing.T) {
+	config := &Config{
+		Dialect: "postgres",
+		TypeMappings: []TypeMappingConfig{
+			{ColumnType: "uuid", Go: "github.com/google/uuid.UUID"},
+			{Column: "*_json", Go: "encoding/json.RawMessage"},
+		},
+	}
+	assert.NoError(t, validateConfig(config))
+
+	assert.True(t, config.TypeMappings[0].Matches("UUID", "id"))
+	assert.False(t, config.TypeMappings[0].Matches("text", "id"))
+	assert.True(t, config.TypeMappings[1].Matches("jsonb", "payload_json"))
+	assert.False(t, config.TypeMappings[1].Matches("jsonb", "payload"))
+
+	for _, tc := range []struct {
+		mapping TypeMappingConfig
+		message string
+	}{
+		{TypeMappingConfig{Go: "github.com/google/uuid.UUID"}, "column_type or column is required"},
+		{TypeMappingConfig{ColumnType: "uuid", Go: "github.com/google/uuid"}, "invalid go type"},
+		{TypeMappingConfig{Column: "[", Go: "string"}, "invalid column pattern"},
+	} {
+		config.TypeMappings = []TypeMappingConfig{tc.mapping}
+
+		err := validateConfig(config)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), tc.message)
+	}
+}
+
 func TestValidateConfig_InvalidDefaultFormat(t *testing.T) {
 	config := &Config{
 		Dialect: "postgres",
@@ -215,6 +414,52 @@ tables:
 	assert.False(t, meta.AllowFullScan)
 }
 
+func TestLoadConfig_SoftDelete(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "snapsql.yaml")
+
+	configContent := `
+dialect: "postgres"
+tables:
+  public.users:
+    soft_delete:
+      column: deleted_at
+      rewrite_delete: true
+`
+
+	err := os.WriteFile(configPath, []byte(configContent), 0o644)
+	assert.NoError(t, err)
+
+	config, err := LoadConfig(configPath)
+	assert.NoError(t, err)
+
+	for _, name := range []string{"users", "USERS", "public.users"} {
+		softDelete, ok := config.GetSoftDelete(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, SoftDeleteConfig{Column: "deleted_at", RewriteDelete: true}, softDelete)
+	}
+
+	_, ok := config.GetSoftDelete("audit.users")
+	assert.False(t, ok)
+	_, ok = config.GetSoftDelete("orders")
+	assert.False(t, ok)
+}
+
+func TestValidateConfig_InvalidSoftDelete(t *testing.T) {
+	config := &Config{
+		Dialect: "postgres",
+		Tables: map[string]TablePerformance{
+			"users": {
+				SoftDelete: &SoftDeleteConfig{},
+			},
+		},
+	}
+
+	err := validateConfig(config)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "tables.users.soft_delete.column")
+}
+
 func TestValidateConfig_ValidConfig(t *testing.T) {
 	config := getDefaultConfig()
 
@@ -0,0 +1,314 @@
+package depgraph
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+	"github.com/shibukawa/snapsql/tokenizer"
+)
+
+// sqlValueWords are identifiers that name values rather than columns
+var sqlValueWords = []string{"current_date", "current_time", "current_timestamp", "default", "excluded", "localtime", "localtimestamp"}
+
+// analyze extracts the tables and columns a template refers to from the static SQL of its
+// instructions. The SQL of every conditional block is included, so the result covers all branches.
+func analyze(format *intermediate.IntermediateFormat, tables map[string]*snapsql.TableInfo) ([]TableUsage, error) {
+	var sql strings.Builder
+
+	for _, inst := range format.Instructions {
+		if inst.Op == intermediate.OpEmitStatic || inst.Op == intermediate.OpEmitUnlessBoundary {
+			sql.WriteString(inst.Value)
+		}
+
+		// Parameters and directives separate the static fragments
+		sql.WriteString(" ")
+	}
+
+	tokens, err := tokenizer.Tokenize(sql.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to tokenize SQL: %w", err)
+	}
+
+	s := &scanner{
+		tokens:   significantTokens(tokens),
+		ctes:     make(map[string]bool),
+		aliases:  make(map[string]string),
+		access:   make(map[string]Access),
+		columns:  make(map[string][]string),
+		consumed: make(map[int]bool),
+		scopes:   make(map[int][]string),
+	}
+
+	s.collectScopes()
+	s.collectCTEs()
+	s.collectTables()
+	s.collectColumns(tables)
+
+	usages := make([]TableUsage, 0, len(s.order))
+	for _, table := range s.order {
+		columns := s.columns[table]
+		slices.Sort(columns)
+
+		usages = append(usages, TableUsage{Table: table, Access: s.access[table], Columns: columns})
+	}
+
+	slices.SortFunc(usages, func(a, b TableUsage) int { return strings.Compare(a.Table, b.Table) })
+
+	return usages, nil
+}
+
+type scanner struct {
+	tokens []tokenizer.Token
+	ctes   map[string]bool
+	// aliases maps aliases and table names to the table; aliases of CTEs and subqueries map to ""
+	aliases  map[string]string
+	order    []string
+	access   map[string]Access
+	columns  map[string][]string
+	outputs  []string     // column aliases of the select list (AS name)
+	consumed map[int]bool // tokens naming tables and their aliases
+	// scopeOf is the parenthesized block of each token (0: top level) and parent the enclosing block
+	scopeOf []int
+	parent  []int
+	scopes  map[int][]string // tables named in each block
+}
+
+// collectScopes numbers the parenthesized blocks so that a column is attributed to the tables of
+// its own query block first
+func (s *scanner) collectScopes() {
+	s.scopeOf = make([]int, len(s.tokens))
+	s.parent = []int{0}
+	stack := []int{0}
+
+	for i, token := range s.tokens {
+		switch token.Type {
+		case tokenizer.OPENED_PARENS:
+			s.parent = append(s.parent, stack[len(stack)-1])
+			stack = append(stack, len(s.parent)-1)
+		case tokenizer.CLOSED_PARENS:
+			if len(stack) > 1 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+
+		s.scopeOf[i] = stack[len(stack)-1]
+	}
+}
+
+// collectCTEs records the names of "name AS (" definitions
+func (s *scanner) collectCTEs() {
+	for i := 0; i+2 < len(s.tokens); i++ {
+		if isName(s.tokens[i]) && s.tokens[i+1].Type == tokenizer.AS && s.tokens[i+2].Type == tokenizer.OPENED_PARENS {
+			s.ctes[normalizeName(s.tokens[i].Value)] = true
+			s.consumed[i] = true
+		}
+	}
+}
+
+// collectTables records the tables named after FROM, JOIN, UPDATE and INTO with their aliases
+func (s *scanner) collectTables() {
+	for i, token := range s.tokens {
+		access := AccessRead
+
+		switch token.Type {
+		case tokenizer.FROM:
+			if i > 0 && s.tokens[i-1].Type == tokenizer.DELETE {
+				access = AccessWrite
+			}
+		case tokenizer.JOIN:
+		case tokenizer.INTO:
+			access = AccessWrite
+		case tokenizer.UPDATE:
+			// ON DUPLICATE KEY UPDATE and ON CONFLICT DO UPDATE SET assign columns, not a table
+			if (i > 0 && s.tokens[i-1].Type == tokenizer.KEY) || (i+1 < len(s.tokens) && s.tokens[i+1].Type == tokenizer.SET) {
+				continue
+			}
+
+			access = AccessWrite
+		default:
+			continue
+		}
+
+		next := s.tableAt(i+1, access)
+		for token.Type == tokenizer.FROM && next < len(s.tokens) && s.tokens[next].Type == tokenizer.COMMA {
+			next = s.tableAt(next+1, access)
+		}
+	}
+}
+
+// tableAt reads "[schema.]table [[AS] alias]" at index and returns the index after it
+func (s *scanner) tableAt(index int, access Access) int {
+	if index >= len(s.tokens) || !isName(s.tokens[index]) {
+		return index
+	}
+
+	name := normalizeName(s.tokens[index].Value)
+	s.consumed[index] = true
+	index++
+
+	for index+1 < len(s.tokens) && s.tokens[index].Type == tokenizer.DOT && isName(s.tokens[index+1]) {
+		name = normalizeName(s.tokens[index+1].Value)
+		s.consumed[index+1] = true
+		index += 2
+	}
+
+	table := name
+	if s.ctes[name] {
+		table = ""
+	} else {
+		s.addTable(table, access, s.scopeOf[index-1])
+	}
+
+	s.aliases[name] = table
+
+	if index < len(s.tokens) && s.tokens[index].Type == tokenizer.AS {
+		index++
+	}
+
+	if index < len(s.tokens) && isName(s.tokens[index]) && (index+1 >= len(s.tokens) || s.tokens[index+1].Type != tokenizer.DOT) {
+		s.aliases[normalizeName(s.tokens[index].Value)] = table
+		s.consumed[index] = true
+		index++
+	}
+
+	return index
+}
+
+func (s *scanner) addTable(table string, access Access, scope int) {
+	if !slices.Contains(s.scopes[scope], table) {
+		s.scopes[scope] = append(s.scopes[scope], table)
+	}
+
+	current, ok := s.access[table]
+	if !ok {
+		s.order = append(s.order, table)
+	}
+
+	if !ok || current == AccessRead {
+		s.access[table] = access
+	}
+}
+
+// collectColumns attributes the remaining identifiers to tables: qualified names through their
+// alias, unqualified names to the tables of their query block whose schema has the column (or to
+// the tables without schema information)
+func (s *scanner) collectColumns(tables map[string]*snapsql.TableInfo) {
+	for i, token := range s.tokens {
+		if isName(token) && !s.consumed[i] && i > 0 && s.tokens[i-1].Type == tokenizer.AS {
+			s.outputs = append(s.outputs, normalizeName(token.Value))
+		}
+	}
+
+	for i, token := range s.tokens {
+		if !isName(token) || s.consumed[i] {
+			continue
+		}
+
+		if i+1 < len(s.tokens) && (s.tokens[i+1].Type == tokenizer.DOT || s.tokens[i+1].Type == tokenizer.OPENED_PARENS) {
+			continue // qualifier or function name
+		}
+
+		if i > 0 && s.tokens[i-1].Type == tokenizer.AS {
+			continue // column alias
+		}
+
+		column := normalizeName(token.Value)
+
+		if i >= 2 && s.tokens[i-1].Type == tokenizer.DOT {
+			if table := s.aliases[normalizeName(s.tokens[i-2].Value)]; table != "" {
+				s.addColumn(table, column)
+			}
+
+			continue
+		}
+
+		if _, isAlias := s.aliases[column]; isAlias || s.ctes[column] || slices.Contains(s.outputs, column) || slices.Contains(sqlValueWords, column) {
+			continue
+		}
+
+		for _, table := range s.owners(column, s.scopeOf[i], tables) {
+			s.addColumn(table, column)
+		}
+	}
+}
+
+// owners returns the tables that may own an unqualified column: those of the innermost enclosing
+// query block that names tables
+func (s *scanner) owners(column string, scope int, tables map[string]*snapsql.TableInfo) []string {
+	for scope != 0 && len(s.scopes[scope]) == 0 {
+		scope = s.parent[scope]
+	}
+
+	var withColumn, unknown []string
+
+	for _, table := range s.scopes[scope] {
+		info, ok := lookupTable(tables, table)
+		if !ok {
+			unknown = append(unknown, table)
+			continue
+		}
+
+		if _, ok := lookupColumn(info, column); ok {
+			withColumn = append(withColumn, table)
+		}
+	}
+
+	if len(withColumn) > 0 {
+		return withColumn
+	}
+
+	return unknown
+}
+
+func (s *scanner) addColumn(table, column string) {
+	if !slices.Contains(s.columns[table], column) {
+		s.columns[table] = append(s.columns[table], column)
+	}
+}
+
+func lookupTable(tables map[string]*snapsql.TableInfo, name string) (*snapsql.TableInfo, bool) {
+	for key, info := range tables {
+		if info != nil && (strings.EqualFold(key, name) || strings.EqualFold(info.Name, name)) {
+			return info, true
+		}
+	}
+
+	return nil, false
+}
+
+func lookupColumn(info *snapsql.TableInfo, name string) (*snapsql.ColumnInfo, bool) {
+	for key, column := range info.Columns {
+		if strings.EqualFold(key, name) {
+			return column, true
+		}
+	}
+
+	return nil, false
+}
+
+// significantTokens drops whitespace, comments and the terminating EOF
+func significantTokens(tokens []tokenizer.Token) []tokenizer.Token {
+	result := make([]tokenizer.Token, 0, len(tokens))
+
+	for _, token := range tokens {
+		switch token.Type {
+		case tokenizer.WHITESPACE, tokenizer.LINE_COMMENT, tokenizer.BLOCK_COMMENT, tokenizer.EOF:
+		default:
+			result = append(result, token)
+		}
+	}
+
+	return result
+}
+
+func isName(token tokenizer.Token) bool {
+	return token.Type == tokenizer.IDENTIFIER || token.Type == tokenizer.CONTEXTUAL_IDENTIFIER
+}
+
+// normalizeName removes identifier quotes and folds the case
+func normalizeName(name string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(name), "\"`[]"))
+}
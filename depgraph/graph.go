@@ -0,0 +1,181 @@
+// Package depgraph builds the dependency graph between templates, the tables they touch and the
+// functions generated from them, and answers impact questions such as "which templates touch
+// table X" or "what is affected if users.email changes".
+package depgraph
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+	"github.com/shibukawa/snapsql/markdownparser"
+)
+
+// ErrInvalidColumnTarget is returned when an impact target is not written as table.column
+var ErrInvalidColumnTarget = errors.New("depgraph: column must be written as table.column")
+
+// Access describes how a template uses a table
+type Access string
+
+const (
+	// AccessRead is a table the template only reads
+	AccessRead Access = "read"
+	// AccessWrite is the table an INSERT, UPDATE or DELETE modifies
+	AccessWrite Access = "write"
+)
+
+// Options configures how templates are compiled into intermediate metadata
+type Options struct {
+	Constants map[string]any
+	Tables    map[string]*snapsql.TableInfo
+	Config    *snapsql.Config
+}
+
+// Graph is the dependency graph of a set of templates
+type Graph struct {
+	Templates []Template `json:"templates"`
+}
+
+// Template is a template node with the function generated from it and the tables it touches
+type Template struct {
+	Path          string       `json:"path"`
+	Function      string       `json:"function"`
+	StatementType string       `json:"statement_type,omitempty"`
+	Tables        []TableUsage `json:"tables"`
+}
+
+// TableUsage is an edge from a template to a table. Columns lists the columns of the table the
+// template refers to; an unqualified column of a query that joins several tables is attributed to
+// every table that may own it, so the list errs on the side of reporting too much.
+type TableUsage struct {
+	Table   string   `json:"table"`
+	Access  Access   `json:"access"`
+	Columns []string `json:"columns,omitempty"`
+}
+
+// Build compiles the template files and returns their dependency graph
+func Build(files []string, opts Options) (*Graph, error) {
+	graph := &Graph{}
+
+	for _, file := range files {
+		format, err := compile(file, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+
+		if err := graph.Add(file, format, opts.Tables); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+	}
+
+	return graph, nil
+}
+
+func compile(path string, opts Options) (*intermediate.IntermediateFormat, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".md") {
+		doc, err := markdownparser.Parse(bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+
+		return intermediate.GenerateFromMarkdown(doc, path, ".", opts.Constants, opts.Tables, opts.Config)
+	}
+
+	return intermediate.GenerateFromSQL(bytes.NewReader(content), opts.Constants, path, ".", opts.Tables, opts.Config)
+}
+
+// Add adds the template compiled into format. tables, when given, decides which table owns an
+// unqualified column.
+func (g *Graph) Add(path string, format *intermediate.IntermediateFormat, tables map[string]*snapsql.TableInfo) error {
+	usages, err := analyze(format, tables)
+	if err != nil {
+		return err
+	}
+
+	function := format.FunctionName
+	if function == "" {
+		function = strings.TrimSuffix(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), ".snap")
+	}
+
+	g.Templates = append(g.Templates, Template{
+		Path:          filepath.ToSlash(path),
+		Function:      function,
+		StatementType: strings.ToLower(format.StatementType),
+		Tables:        usages,
+	})
+
+	slices.SortStableFunc(g.Templates, func(a, b Template) int {
+		return cmp.Compare(a.Path, b.Path)
+	})
+
+	return nil
+}
+
+// Tables returns the names of all tables in the graph, sorted
+func (g *Graph) Tables() []string {
+	var tables []string
+
+	for _, template := range g.Templates {
+		for _, usage := range template.Tables {
+			if !slices.Contains(tables, usage.Table) {
+				tables = append(tables, usage.Table)
+			}
+		}
+	}
+
+	slices.Sort(tables)
+
+	return tables
+}
+
+// Affected returns the part of the graph a change of table (or of one of its columns, when column
+// is not empty) affects: the templates that use it, each with only the matching table edge.
+func (g *Graph) Affected(table, column string) *Graph {
+	table = normalizeName(table)
+	column = normalizeName(column)
+
+	affected := &Graph{Templates: []Template{}}
+
+	for _, template := range g.Templates {
+		for _, usage := range template.Tables {
+			if usage.Table != table || (column != "" && !slices.Contains(usage.Columns, column)) {
+				continue
+			}
+
+			template.Tables = []TableUsage{usage}
+			affected.Templates = append(affected.Templates, template)
+
+			break
+		}
+	}
+
+	return affected
+}
+
+// ParseColumnTarget splits an impact target written as table.column (schema.table.column is
+// accepted and the schema ignored)
+func ParseColumnTarget(target string) (string, string, error) {
+	i := strings.LastIndex(target, ".")
+	if i <= 0 || i == len(target)-1 {
+		return "", "", fmt.Errorf("%w: %q", ErrInvalidColumnTarget, target)
+	}
+
+	table := target[:i]
+	if j := strings.LastIndex(table, "."); j >= 0 {
+		table = table[j+1:]
+	}
+
+	return normalizeName(table), normalizeName(target[i+1:]), nil
+}
@@ -0,0 +1,153 @@
+package depgraph
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql"
+)
+
+func writeTemplates(t *testing.T, templates map[string]string) []string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files := make([]string, 0, len(templates))
+
+	for name, content := range templates {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		files = append(files, path)
+	}
+
+	return files
+}
+
+func schema() map[string]*snapsql.TableInfo {
+	return map[string]*snapsql.TableInfo{
+		"users": {Name: "users", Columns: map[string]*snapsql.ColumnInfo{
+			"id": {Name: "id"}, "email": {Name: "email"}, "name": {Name: "name"}, "created_at": {Name: "created_at"},
+		}},
+		"orders": {Name: "orders", Columns: map[string]*snapsql.ColumnInfo{
+			"id": {Name: "id"}, "user_id": {Name: "user_id"}, "total": {Name: "total"},
+		}},
+	}
+}
+
+func buildGraph(t *testing.T) *Graph {
+	t.Helper()
+
+	files := writeTemplates(t, map[string]string{
+		"find_user.snap.sql": `/*#
+function_name: find_user
+parameters:
+  id: int
+*/
+SELECT id, email FROM users WHERE id = /*= id */1`,
+		"user_orders.snap.sql": `/*#
+function_name: user_orders
+parameters:
+  since: timestamp
+*/
+WITH recent AS (SELECT id, name FROM users WHERE created_at > /*= since */'2024-01-01')
+SELECT r.name, o.total AS amount FROM recent r JOIN orders o ON o.user_id = r.id ORDER BY amount`,
+		"rename_user.snap.sql": `/*#
+function_name: rename_user
+parameters:
+  id: int
+  name: string
+*/
+UPDATE users SET name = /*= name */'x' WHERE id = /*= id */1`,
+	})
+
+	graph, err := Build(files, Options{Tables: schema(), Config: &snapsql.Config{Dialect: snapsql.DialectPostgres}})
+	assert.NoError(t, err)
+
+	return graph
+}
+
+func TestBuild(t *testing.T) {
+	graph := buildGraph(t)
+
+	assert.Equal(t, 3, len(graph.Templates))
+	assert.Equal(t, []string{"orders", "users"}, graph.Tables())
+
+	byFunction := make(map[string]Template)
+	for _, template := range graph.Templates {
+		byFunction[template.Function] = template
+	}
+
+	assert.Equal(t, []TableUsage{{Table: "users", Access: AccessRead, Columns: []string{"email", "id"}}}, byFunction["find_user"].Tables)
+	assert.Equal(t, []TableUsage{{Table: "users", Access: AccessWrite, Columns: []string{"id", "name"}}}, byFunction["rename_user"].Tables)
+	assert.Equal(t, "update", byFunction["rename_user"].StatementType)
+
+	// Columns of the CTE body belong to users; r.* refers to the CTE and the ORDER BY alias to the select list
+	assert.Equal(t, []TableUsage{
+		{Table: "orders", Access: AccessRead, Columns: []string{"total", "user_id"}},
+		{Table: "users", Access: AccessRead, Columns: []string{"created_at", "id", "name"}},
+	}, byFunction["user_orders"].Tables)
+}
+
+func TestAffected(t *testing.T) {
+	graph := buildGraph(t)
+
+	functions := func(g *Graph) []string {
+		var names []string
+		for _, template := range g.Templates {
+			names = append(names, template.Function)
+		}
+
+		return names
+	}
+
+	assert.Equal(t, []string{"find_user", "rename_user", "user_orders"}, functions(graph.Affected("users", "")))
+	assert.Equal(t, []string{"user_orders"}, functions(graph.Affected("ORDERS", "")))
+
+	table, column, err := ParseColumnTarget("public.users.email")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"find_user"}, functions(graph.Affected(table, column)))
+
+	affected := graph.Affected("users", "name")
+	assert.Equal(t, []string{"rename_user", "user_orders"}, functions(affected))
+	assert.Equal(t, 1, len(affected.Templates[1].Tables), "only the matching table edge is kept")
+
+	_, _, err = ParseColumnTarget("email")
+	assert.IsError(t, err, ErrInvalidColumnTarget)
+}
+
+func TestWriteDOT(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, WriteDOT(&out, buildGraph(t).Affected("users", "email")))
+
+	dot := out.String()
+	assert.Contains(t, dot, `"table:users" [label="users", shape=cylinder];`)
+	assert.Contains(t, dot, `-> "function:find_user" [style=dashed];`)
+	assert.Contains(t, dot, `-> "table:users" [label="read: email, id"];`)
+	assert.NotContains(t, dot, "rename_user")
+}
+
+func TestBuildWrites(t *testing.T) {
+	files := writeTemplates(t, map[string]string{
+		"create_order.snap.sql": `/*#
+function_name: create_order
+parameters:
+  user_id: int
+*/
+INSERT INTO orders (user_id, total) VALUES (/*= user_id */1, 0)`,
+		"delete_inactive.snap.sql": `/*#
+function_name: delete_inactive
+*/
+DELETE FROM users WHERE id NOT IN (SELECT user_id FROM orders)`,
+	})
+
+	graph, err := Build(files, Options{Tables: schema(), Config: &snapsql.Config{Dialect: snapsql.DialectPostgres}})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []TableUsage{{Table: "orders", Access: AccessWrite, Columns: []string{"total", "user_id"}}}, graph.Templates[0].Tables)
+	assert.Equal(t, []TableUsage{
+		{Table: "orders", Access: AccessRead, Columns: []string{"user_id"}},
+		{Table: "users", Access: AccessWrite, Columns: []string{"id"}},
+	}, graph.Templates[1].Tables)
+}
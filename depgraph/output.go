@@ -0,0 +1,79 @@
+package depgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// dotColumnLimit is the number of columns shown on an edge of the DOT output
+const dotColumnLimit = 5
+
+// WriteJSON writes the graph as an indented JSON document
+func WriteJSON(w io.Writer, graph *Graph) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(graph)
+}
+
+// WriteDOT writes the graph in Graphviz DOT format. Templates point at the functions generated
+// from them and at the tables they use; write edges are drawn bold.
+func WriteDOT(w io.Writer, graph *Graph) error {
+	var b strings.Builder
+
+	b.WriteString("digraph snapsql {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [fontname=\"Helvetica\"];\n")
+
+	for _, table := range graph.Tables() {
+		fmt.Fprintf(&b, "  %s [label=%s, shape=cylinder];\n", dotID("table", table), strconv.Quote(table))
+	}
+
+	for _, template := range graph.Templates {
+		id := dotID("template", template.Path)
+		function := dotID("function", template.Function)
+
+		fmt.Fprintf(&b, "  %s [label=%s, shape=note];\n", id, strconv.Quote(template.Path))
+		fmt.Fprintf(&b, "  %s [label=%s, shape=box, style=rounded];\n", function, strconv.Quote(template.Function+"()"))
+		fmt.Fprintf(&b, "  %s -> %s [style=dashed];\n", id, function)
+
+		for _, usage := range template.Tables {
+			attrs := "label=" + strconv.Quote(edgeLabel(usage))
+			if usage.Access == AccessWrite {
+				attrs += ", style=bold"
+			}
+
+			fmt.Fprintf(&b, "  %s -> %s [%s];\n", id, dotID("table", usage.Table), attrs)
+		}
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+func dotID(kind, name string) string {
+	return strconv.Quote(kind + ":" + name)
+}
+
+// edgeLabel shows the access and the first columns of a table edge
+func edgeLabel(usage TableUsage) string {
+	if len(usage.Columns) == 0 {
+		return string(usage.Access)
+	}
+
+	columns := usage.Columns
+	more := ""
+
+	if len(columns) > dotColumnLimit {
+		more = fmt.Sprintf(", +%d", len(columns)-dotColumnLimit)
+		columns = columns[:dotColumnLimit]
+	}
+
+	return fmt.Sprintf("%s: %s%s", usage.Access, strings.Join(columns, ", "), more)
+}
@@ -5,10 +5,13 @@ package snapsql
 type Dialect string
 
 const (
-	DialectPostgres Dialect = "postgres"
-	DialectMySQL    Dialect = "mysql"
-	DialectSQLite   Dialect = "sqlite"
-	DialectMariaDB  Dialect = "mariadb"
+	DialectPostgres   Dialect = "postgres"
+	DialectMySQL      Dialect = "mysql"
+	DialectSQLite     Dialect = "sqlite"
+	DialectMariaDB    Dialect = "mariadb"
+	DialectDuckDB     Dialect = "duckdb"
+	DialectClickHouse Dialect = "clickhouse"
+	DialectCockroach  Dialect = "cockroach"
 )
 
 // Feature represents DB-specific feature flags
@@ -22,3 +25,19 @@ const (
 	FeatureArray                  // ARRAY系関数
 	// Add more features as needed
 )
+
+// SupportsTransactions reports whether the dialect can roll back changes. ClickHouse accepts
+// BEGIN/COMMIT through its driver, but the statements are applied immediately.
+func SupportsTransactions(dialect Dialect) bool {
+	return dialect != DialectClickHouse
+}
+
+// GenerationDialect returns the dialect whose SQL is generated for dialect. CockroachDB accepts
+// PostgreSQL syntax, so it reuses the PostgreSQL generation and only differs at runtime.
+func GenerationDialect(dialect Dialect) Dialect {
+	if dialect == DialectCockroach {
+		return DialectPostgres
+	}
+
+	return dialect
+}
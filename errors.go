@@ -170,6 +170,8 @@ var (
 	ErrUnsupportedType = errors.New("unsupported type")
 	// ErrUnsupportedResponseAffinity indicates a declared response affinity is unsupported.
 	ErrUnsupportedResponseAffinity = errors.New("unsupported response affinity")
+	// ErrUnsupportedPagination indicates a declared pagination mode is unsupported.
+	ErrUnsupportedPagination = errors.New("unsupported pagination")
 	// ErrDialectMustBeSpecified indicates a dialect is required but missing.
 	ErrDialectMustBeSpecified = errors.New("dialect must be specified (postgres, mysql, sqlite, mariadb)")
 
@@ -182,6 +184,8 @@ var (
 	ErrUpdateStatementMissingUpdate = errors.New("UPDATE statement missing UPDATE clause")
 	// ErrDeleteStatementMissingFrom indicates a DELETE lacked FROM clause.
 	ErrDeleteStatementMissingFrom = errors.New("DELETE statement missing FROM clause")
+	// ErrMergeStatementMissingInto indicates a MERGE lacked INTO clause.
+	ErrMergeStatementMissingInto = errors.New("MERGE statement missing INTO clause")
 	// ErrColumnDoesNotExist indicates a referenced column does not exist.
 	ErrColumnDoesNotExist = errors.New("column does not exist in table")
 	// ErrSchemaDoesNotExist indicates a referenced schema does not exist.
@@ -196,6 +200,8 @@ var (
 	ErrUnsupportedSubqueryStatementType = errors.New("unsupported subquery statement type")
 	// ErrStatementIsNotSelect indicates a non-SELECT statement where SELECT expected.
 	ErrStatementIsNotSelect = errors.New("statement is not a SELECT statement")
+	// ErrSetOperationColumnMismatch indicates UNION / INTERSECT / EXCEPT branches return different column counts.
+	ErrSetOperationColumnMismatch = errors.New("set operation branches have different column counts")
 	// ErrUnsupportedStatementType indicates a statement type is unsupported.
 	ErrUnsupportedStatementType = errors.New("unsupported statement type")
 	// ErrSchemaValidationFailed indicates schema validation failed.
@@ -218,6 +224,12 @@ var (
 	ErrNotCaseExpression = errors.New("not a CASE expression")
 	// ErrConfigFileNotFound indicates a configuration file could not be located.
 	ErrConfigFileNotFound = errors.New("configuration file not found")
+	// ErrWorkspaceFileNotFound indicates the workspace file could not be located.
+	ErrWorkspaceFileNotFound = errors.New("workspace file not found")
+	// ErrWorkspaceNoMembers indicates a workspace file did not list any member projects.
+	ErrWorkspaceNoMembers = errors.New("workspace has no member projects")
+	// ErrWorkspaceMemberNotFound indicates a workspace member directory does not exist.
+	ErrWorkspaceMemberNotFound = errors.New("workspace member directory not found")
 	// ErrNoResponseFields indicates a generated query had no response fields.
 	ErrNoResponseFields = errors.New("no response fields found")
 	// ErrSchemaDirectoryNotFound indicates the ./schema directory is missing prior to generation.
@@ -1,5 +1,4 @@
 # Generated by snapsql - DO NOT EDIT
-# Generated at: 2025-11-20T20:47:34+09:00
 # Function: post_get
 # Dialect: postgres
 
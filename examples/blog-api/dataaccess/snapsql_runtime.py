@@ -1,6 +1,5 @@
 # Generated by snapsql - DO NOT EDIT
 # Shared runtime helpers
-# Generated at: 2025-11-20T20:47:34+09:00
 
 from dataclasses import dataclass
 from typing import Optional, List, Any, Dict, Protocol
@@ -171,20 +171,7 @@ func durationFromMillis(ms float64) time.Duration {
 }
 
 func analyzeSQLitePlanText(raw string, opts AnalyzerOptions, eval *PerformanceEvaluation) {
-	lines := strings.SplitSeq(raw, "\n")
-	for line := range lines {
-		detail := strings.TrimSpace(line)
-		if detail == "" {
-			continue
-		}
-
-		lower := strings.ToLower(detail)
-		if !strings.HasPrefix(lower, "scan") && !strings.Contains(lower, " scan ") {
-			continue
-		}
-
-		name := extractSQLiteTableName(detail)
-
+	for _, name := range sqliteScannedTables(raw) {
 		allowScan := false
 		if meta, ok := lookupTableMeta(opts.Tables, name); ok {
 			allowScan = meta.AllowFullScan
@@ -207,6 +194,28 @@ func analyzeSQLitePlanText(raw string, opts AnalyzerOptions, eval *PerformanceEv
 	}
 }
 
+// sqliteScannedTables returns the table of every SCAN line in EXPLAIN QUERY PLAN output.
+// The name is empty when it cannot be extracted.
+func sqliteScannedTables(raw string) []string {
+	var names []string
+
+	for line := range strings.SplitSeq(raw, "\n") {
+		detail := strings.TrimSpace(line)
+		if detail == "" {
+			continue
+		}
+
+		lower := strings.ToLower(detail)
+		if !strings.HasPrefix(lower, "scan") && !strings.Contains(lower, " scan ") {
+			continue
+		}
+
+		names = append(names, extractSQLiteTableName(detail))
+	}
+
+	return names
+}
+
 func extractSQLiteTableName(detail string) string {
 	fields := strings.Fields(detail)
 	for i := range fields {
@@ -0,0 +1,63 @@
+package explain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// sqliteNumbers matches volatile numbers in SQLite plan text (e.g. "(~100 rows)")
+var sqliteNumbers = regexp.MustCompile(`\d+`)
+
+// Fingerprint returns a stable hash of the plan shape. Only node types, relations and access
+// types contribute, so costs, row counts and timings do not change the fingerprint.
+// It returns an empty string when the document holds no plan.
+func (d *PlanDocument) Fingerprint() string {
+	if d == nil {
+		return ""
+	}
+
+	var b strings.Builder
+
+	var walk func(node *PlanNode, depth int)
+
+	walk = func(node *PlanNode, depth int) {
+		if node == nil {
+			return
+		}
+
+		b.WriteString(strings.Repeat(" ", depth))
+		b.WriteString(strings.ToLower(node.NodeType))
+		b.WriteByte('|')
+		b.WriteString(canonicalTableKey(node.Schema, node.Relation))
+		b.WriteByte('|')
+		b.WriteString(strings.ToUpper(node.AccessType))
+		b.WriteByte('\n')
+
+		for _, child := range node.Children {
+			walk(child, depth+1)
+		}
+	}
+
+	for _, root := range d.Root {
+		walk(root, 0)
+	}
+
+	if len(d.Root) == 0 {
+		for line := range strings.SplitSeq(d.RawText, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				b.WriteString(sqliteNumbers.ReplaceAllString(line, "#"))
+				b.WriteByte('\n')
+			}
+		}
+	}
+
+	if b.Len() == 0 {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(b.String()))
+
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,161 @@
+package explain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Rule describes a plan pattern that must not occur. Rules are enforced by `snapsql test --explain`.
+type Rule struct {
+	// Kind selects what the rule matches: WarningFullScan or WarningSlowQuery.
+	Kind WarningKind
+	// MinRows limits full-scan rules to tables with at least this many rows. The row count is
+	// taken from TableMetadata.ExpectedRows, falling back to the row counts reported by the plan.
+	MinRows int64
+	// Tables limits the rule to the listed tables (case-insensitive, schema prefix optional).
+	Tables []string
+}
+
+// RuleViolation reports a rule matched by a plan.
+type RuleViolation struct {
+	Rule    Rule
+	Message string
+	Tables  []string
+}
+
+// CheckRules evaluates rules against the plan and the evaluation produced by Analyze.
+// Tables marked AllowFullScan never violate full-scan rules.
+func CheckRules(doc *PlanDocument, eval *PerformanceEvaluation, rules []Rule, tables map[string]TableMetadata) []RuleViolation {
+	if doc == nil || len(rules) == 0 {
+		return nil
+	}
+
+	var violations []RuleViolation
+
+	for _, rule := range rules {
+		switch rule.Kind {
+		case WarningFullScan:
+			violations = append(violations, checkFullScanRule(doc, rule, tables)...)
+		case WarningSlowQuery:
+			if eval == nil {
+				continue
+			}
+
+			for _, warn := range eval.Warnings {
+				if warn.Kind != WarningSlowQuery {
+					continue
+				}
+
+				message := "slow query: " + warn.Message
+				if warn.QueryPath != "" {
+					message = fmt.Sprintf("%s [path=%s]", message, warn.QueryPath)
+				}
+
+				violations = append(violations, RuleViolation{Rule: rule, Message: message, Tables: warn.Tables})
+			}
+		}
+	}
+
+	return violations
+}
+
+func checkFullScanRule(doc *PlanDocument, rule Rule, tables map[string]TableMetadata) []RuleViolation {
+	var violations []RuleViolation
+
+	check := func(schema, relation string, planRows float64) {
+		key := canonicalTableKey(schema, relation)
+		if key == "" || !ruleTargets(rule, key) {
+			return
+		}
+
+		rows := int64(planRows)
+
+		meta, ok := lookupTableMeta(tables, key)
+		if !ok {
+			meta, ok = lookupTableMeta(tables, relation)
+		}
+
+		if ok {
+			if meta.AllowFullScan {
+				return
+			}
+
+			if meta.ExpectedRows > 0 {
+				rows = meta.ExpectedRows
+			}
+		}
+
+		if rows < rule.MinRows {
+			return
+		}
+
+		message := "full scan on " + key
+		if rule.MinRows > 0 {
+			message = fmt.Sprintf("%s (rows=%d, min_rows=%d)", message, rows, rule.MinRows)
+		}
+
+		violations = append(violations, RuleViolation{Rule: rule, Message: message, Tables: []string{key}})
+	}
+
+	var walk func(node *PlanNode)
+
+	walk = func(node *PlanNode) {
+		if node == nil {
+			return
+		}
+
+		if isFullScan(node) {
+			check(node.Schema, node.Relation, max(node.ActualRows, node.PlanRows))
+		}
+
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+
+	for _, root := range doc.Root {
+		walk(root)
+	}
+
+	// SQLite reports no row counts; only metadata can satisfy MinRows
+	if len(doc.Root) == 0 && normalizeDialect(doc.Dialect) == "sqlite" {
+		for _, name := range sqliteScannedTables(doc.RawText) {
+			check("", name, 0)
+		}
+	}
+
+	return violations
+}
+
+func ruleTargets(rule Rule, key string) bool {
+	if len(rule.Tables) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(rule.Tables, func(table string) bool {
+		table = strings.ToLower(strings.TrimSpace(table))
+		return table == key || strings.HasSuffix(key, "."+table)
+	})
+}
+
+// Text returns the plan in a human-readable form: the raw text for SQLite and indented
+// JSON for dialects with structured plans.
+func (d *PlanDocument) Text() string {
+	if d == nil {
+		return ""
+	}
+
+	if len(d.RawJSON) == 0 {
+		return d.RawText
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, d.RawJSON, "", "  "); err != nil {
+		return string(d.RawJSON)
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,114 @@
+package explain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckRulesFullScanMinRows(t *testing.T) {
+	doc := &PlanDocument{
+		Dialect: "postgres",
+		Root: []*PlanNode{{
+			NodeType: "Hash Join",
+			Children: []*PlanNode{
+				{NodeType: "Seq Scan", Schema: "public", Relation: "orders", PlanRows: 20},
+				{NodeType: "Seq Scan", Schema: "public", Relation: "users", PlanRows: 50000},
+				{NodeType: "Seq Scan", Schema: "public", Relation: "audit_logs", PlanRows: 90000},
+			},
+		}},
+	}
+	tables := map[string]TableMetadata{
+		"orders":     {ExpectedRows: 1000000},
+		"audit_logs": {ExpectedRows: 1000000, AllowFullScan: true},
+	}
+
+	violations := CheckRules(doc, nil, []Rule{{Kind: WarningFullScan, MinRows: 10000}}, tables)
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 violations, got %d: %+v", len(violations), violations)
+	}
+
+	if violations[0].Tables[0] != "public.orders" || !strings.Contains(violations[0].Message, "rows=1000000") {
+		t.Fatalf("unexpected violation for orders: %+v", violations[0])
+	}
+
+	if violations[1].Tables[0] != "public.users" || !strings.Contains(violations[1].Message, "rows=50000") {
+		t.Fatalf("unexpected violation for users: %+v", violations[1])
+	}
+
+	violations = CheckRules(doc, nil, []Rule{{Kind: WarningFullScan, Tables: []string{"users"}}}, tables)
+	if len(violations) != 1 || violations[0].Tables[0] != "public.users" {
+		t.Fatalf("expected only users to violate, got %+v", violations)
+	}
+}
+
+func TestCheckRulesSQLite(t *testing.T) {
+	doc := &PlanDocument{Dialect: "sqlite", RawText: "SCAN users\nSEARCH orders USING INDEX idx_orders_user (user_id=?)"}
+	tables := map[string]TableMetadata{"users": {ExpectedRows: 500}}
+
+	if violations := CheckRules(doc, nil, []Rule{{Kind: WarningFullScan, MinRows: 1000}}, tables); len(violations) != 0 {
+		t.Fatalf("expected no violations below min_rows, got %+v", violations)
+	}
+
+	violations := CheckRules(doc, nil, []Rule{{Kind: WarningFullScan, MinRows: 100}}, tables)
+	if len(violations) != 1 || violations[0].Tables[0] != "users" {
+		t.Fatalf("expected users violation, got %+v", violations)
+	}
+}
+
+func TestCheckRulesSlowQuery(t *testing.T) {
+	eval := &PerformanceEvaluation{Warnings: []Warning{
+		{Kind: WarningFullScan, Message: "full scan detected"},
+		{Kind: WarningSlowQuery, Message: "estimated runtime exceeds threshold", QueryPath: "main"},
+	}}
+
+	violations := CheckRules(&PlanDocument{}, eval, []Rule{{Kind: WarningSlowQuery}}, nil)
+	if len(violations) != 1 || violations[0].Message != "slow query: estimated runtime exceeds threshold [path=main]" {
+		t.Fatalf("unexpected violations: %+v", violations)
+	}
+}
+
+func TestPlanDocumentText(t *testing.T) {
+	doc := &PlanDocument{RawJSON: []byte(`[{"Plan":{"Node Type":"Seq Scan"}}]`)}
+	if got := doc.Text(); !strings.Contains(got, "\n    \"Plan\": {") {
+		t.Fatalf("expected indented JSON, got %q", got)
+	}
+
+	doc = &PlanDocument{RawText: "SCAN users"}
+	if got := doc.Text(); got != "SCAN users" {
+		t.Fatalf("unexpected text %q", got)
+	}
+}
+
+func TestPlanDocumentFingerprint(t *testing.T) {
+	plan := func(rows float64, nodeType string) *PlanDocument {
+		return &PlanDocument{Root: []*PlanNode{{
+			NodeType: "Nested Loop",
+			Children: []*PlanNode{
+				{NodeType: nodeType, Relation: "users", PlanRows: rows, ActualTotalTime: rows / 10},
+				{NodeType: "Index Scan", Relation: "orders"},
+			},
+		}}}
+	}
+
+	base := plan(10, "Seq Scan").Fingerprint()
+	if len(base) != 16 {
+		t.Fatalf("unexpected fingerprint %q", base)
+	}
+
+	if got := plan(5000, "Seq Scan").Fingerprint(); got != base {
+		t.Fatalf("row counts must not change the fingerprint: %s != %s", got, base)
+	}
+
+	if got := plan(10, "Index Scan").Fingerprint(); got == base {
+		t.Fatalf("node type change must change the fingerprint")
+	}
+
+	sqlite := &PlanDocument{RawText: "SCAN users"}
+	if sqlite.Fingerprint() == (&PlanDocument{RawText: "SEARCH users USING INTEGER PRIMARY KEY (rowid=?)"}).Fingerprint() {
+		t.Fatalf("different SQLite plans must have different fingerprints")
+	}
+
+	if (&PlanDocument{}).Fingerprint() != "" {
+		t.Fatalf("empty plan must have an empty fingerprint")
+	}
+}
@@ -66,6 +66,10 @@ type AnalyzerOptions struct {
 type PerformanceEvaluation struct {
 	Warnings  []Warning
 	Estimates []QueryEstimate
+
+	// Plan and Violations are only populated when plans are recorded (snapsql test --explain)
+	Plan       *PlanDocument
+	Violations []RuleViolation
 }
 
 // Warning conveys issues detected while analyzing the plan.
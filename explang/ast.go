@@ -27,3 +27,18 @@ type Step struct {
 	Safe       bool
 	Pos        Position
 }
+
+// Call represents a template function call such as normalizeEmail(user.email).
+// Every argument is an access path.
+type Call struct {
+	Function string
+	Args     [][]Step
+	Pos      Position
+}
+
+// Function declares a template function registered in snapsql.yaml.
+// Args and Returns use snapsql parameter type names.
+type Function struct {
+	Args    []string
+	Returns string
+}
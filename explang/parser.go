@@ -3,6 +3,7 @@ package explang
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"unicode"
 )
 
@@ -21,12 +22,43 @@ func ParseSteps(expr string, startLine, startColumn int) ([]Step, error) {
 	return p.steps, nil
 }
 
+// ParseExpression parses an expression that is either an access path or a call of a
+// template function whose arguments are access paths. Exactly one of the returned
+// steps and call is set.
+func ParseExpression(expr string, startLine, startColumn int) ([]Step, *Call, error) {
+	p := newParser(expr, startLine, startColumn)
+
+	p.skipWhitespace()
+
+	name, start, end, ok := p.readIdentifier()
+	if ok {
+		p.skipWhitespace()
+	}
+
+	if !ok || p.peek() != '(' {
+		p.pos = 0
+		if err := p.parse(); err != nil {
+			return nil, nil, err
+		}
+
+		return p.steps, nil, nil
+	}
+
+	call, err := p.parseCall(name, start, end)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return nil, call, nil
+}
+
 type parser struct {
-	src        []rune
-	pos        int
-	steps      []Step
-	baseLine   int
-	baseColumn int
+	src         []rune
+	pos         int
+	steps       []Step
+	terminators string
+	baseLine    int
+	baseColumn  int
 }
 
 func newParser(expr string, startLine, startColumn int) *parser {
@@ -49,7 +81,7 @@ func (p *parser) parse() error {
 	for {
 		p.skipWhitespace()
 
-		if p.eof() {
+		if p.eof() || strings.ContainsRune(p.terminators, p.peek()) {
 			return nil
 		}
 
@@ -87,6 +119,48 @@ func (p *parser) parse() error {
 	}
 }
 
+func (p *parser) parseCall(name string, start, end int) (*Call, error) {
+	call := &Call{Function: name, Pos: p.makePosition(start, end)}
+
+	p.pos++ // '('
+	p.skipWhitespace()
+
+	if !p.match(')') {
+		p.terminators = ",)"
+
+		for {
+			p.steps = nil
+			if err := p.parse(); err != nil {
+				return nil, err
+			}
+
+			call.Args = append(call.Args, p.steps)
+
+			p.skipWhitespace()
+
+			if p.match(',') {
+				continue
+			}
+
+			if p.match(')') {
+				break
+			}
+
+			return nil, fmt.Errorf("%w: expected ',' or ')' in call of %s at position %d", ErrInvalidExpression, name, p.pos+1)
+		}
+	}
+
+	p.skipWhitespace()
+
+	if !p.eof() {
+		return nil, fmt.Errorf("%w: unexpected character '%c' after call of %s at position %d", ErrInvalidExpression, p.peek(), name, p.pos+1)
+	}
+
+	call.Pos.Length = p.pos - start
+
+	return call, nil
+}
+
 func (p *parser) parseRootIdentifier() error {
 	p.skipWhitespace()
 
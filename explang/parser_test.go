@@ -84,6 +84,48 @@ func TestParseSteps_WithBaseLineColumn(t *testing.T) {
 	}, steps)
 }
 
+func TestParseExpression(t *testing.T) {
+	steps, call, err := ParseExpression("user.email", 1, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Nil(t, call)
+	assert.Len(t, steps, 2)
+
+	steps, call, err = ParseExpression("joinTags( user?.tags , ids[0] )", 1, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Nil(t, steps)
+	assert.Equal(t, &Call{
+		Function: "joinTags",
+		Pos:      defaultPos(0, 31),
+		Args: [][]Step{
+			{
+				{Kind: StepIdentifier, Identifier: "user", Pos: defaultPos(10, 4)},
+				{Kind: StepMember, Property: "tags", Safe: true, Pos: defaultPos(14, 6)},
+			},
+			{
+				{Kind: StepIdentifier, Identifier: "ids", Pos: defaultPos(23, 3)},
+				{Kind: StepIndex, Index: 0, Pos: defaultPos(26, 3)},
+			},
+		},
+	}, call)
+
+	_, call, err = ParseExpression("now()", 1, 1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "now", call.Function)
+		assert.Empty(t, call.Args)
+	}
+
+	for _, input := range []string{"f(a", "f(a b)", "f(a).b", "f(g(a))", "f(a,)"} {
+		_, _, err := ParseExpression(input, 1, 1)
+		assert.Error(t, err, input)
+	}
+}
+
 func defaultPos(offset, length int) Position {
 	return Position{Offset: offset, Line: 1, Column: offset + 1, Length: length}
 }
@@ -141,6 +141,32 @@ func ValidateStepsAgainstParameters(steps []Step, params map[string]any, opts *V
 	return errs
 }
 
+// ValidateCall ensures that the call refers to a declared function with the right number of
+// arguments and that every argument path is compatible with the given parameters map.
+func ValidateCall(call *Call, functions map[string]Function, params map[string]any, opts *ValidatorOptions) []ValidationError {
+	if call == nil {
+		return nil
+	}
+
+	callStep := Step{Kind: StepIdentifier, Identifier: call.Function, Pos: call.Pos}
+
+	fn, ok := functions[call.Function]
+	if !ok {
+		return []ValidationError{{Step: callStep, Message: fmt.Sprintf("unknown template function %q", call.Function)}}
+	}
+
+	if len(call.Args) != len(fn.Args) {
+		return []ValidationError{{Step: callStep, Message: fmt.Sprintf("template function %q expects %d argument(s), got %d", call.Function, len(fn.Args), len(call.Args))}}
+	}
+
+	var errs []ValidationError
+	for _, arg := range call.Args {
+		errs = append(errs, ValidateStepsAgainstParameters(arg, params, opts)...)
+	}
+
+	return errs
+}
+
 func joinPath(base, property string) string {
 	if base == "" {
 		return property
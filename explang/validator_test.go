@@ -130,3 +130,35 @@ func TestValidateStepsAgainstParameters_AdditionalRoots(t *testing.T) {
 	errs := ValidateStepsAgainstParameters(steps, map[string]any{}, options)
 	assert.Empty(t, errs)
 }
+
+func TestValidateCall(t *testing.T) {
+	params := map[string]any{
+		"user": map[string]any{"email": "string"},
+	}
+	functions := map[string]Function{
+		"normalizeEmail": {Args: []string{"string"}, Returns: "string"},
+	}
+
+	_, call, err := ParseExpression("normalizeEmail(user.email)", 1, 1)
+	if assert.NoError(t, err) {
+		assert.Empty(t, ValidateCall(call, functions, params, nil))
+	}
+
+	cases := map[string]string{
+		"lowerEmail(user.email)":                 `unknown template function "lowerEmail"`,
+		"normalizeEmail(user.email, user.email)": `expects 1 argument(s), got 2`,
+		"normalizeEmail(user.mail)":              `unknown field "mail"`,
+	}
+
+	for expr, want := range cases {
+		_, call, err := ParseExpression(expr, 1, 1)
+		if !assert.NoError(t, err, expr) {
+			continue
+		}
+
+		errs := ValidateCall(call, functions, params, nil)
+		if assert.Len(t, errs, 1, expr) {
+			assert.Contains(t, errs[0].Message, want, expr)
+		}
+	}
+}
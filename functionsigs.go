@@ -1,15 +1,54 @@
 package snapsql
 
+import "strings"
+
 // FunctionSignature defines the return type and nullability for a SQL function
 // ReturnTypeByArg: trueなら最初の引数の型を返す
 // NullableByArg: trueなら引数のnullableを伝搬
 // CastType: trueならCAST(... AS type)の型を返す
+// TableColumns: FROM 句で使える関数 (json_each など) が返す列
 type FunctionSignature struct {
 	ReturnType      string
 	ReturnTypeByArg bool
 	Nullable        bool
 	NullableByArg   bool
 	CastType        bool
+	TableColumns    []*ColumnInfo
+}
+
+// DialectFunctionSignatures returns the function table of a dialect.
+// MariaDB shares MySQL's table and CockroachDB shares PostgreSQL's.
+func DialectFunctionSignatures(dialect Dialect) map[string]FunctionSignature {
+	dialect = GenerationDialect(dialect)
+	if dialect == DialectMariaDB {
+		dialect = DialectMySQL
+	}
+
+	return FunctionSignatures[dialect]
+}
+
+// LookupFunctionSignature returns the signature of the function in the dialect. The name is case-insensitive.
+func LookupFunctionSignature(dialect Dialect, name string) (FunctionSignature, bool) {
+	sig, ok := DialectFunctionSignatures(dialect)[strings.ToUpper(name)]
+	return sig, ok
+}
+
+// json_each / jsonb_each の列 (PostgreSQL)
+var postgresJSONEachColumns = []*ColumnInfo{
+	{Name: "key", DataType: "string"},
+	{Name: "value", DataType: "json", Nullable: true},
+}
+
+// json_each / json_tree の列 (SQLite, DuckDB)
+var sqliteJSONEachColumns = []*ColumnInfo{
+	{Name: "key", DataType: "any", Nullable: true},
+	{Name: "value", DataType: "any", Nullable: true},
+	{Name: "type", DataType: "string"},
+	{Name: "atom", DataType: "any", Nullable: true},
+	{Name: "id", DataType: "int"},
+	{Name: "parent", DataType: "int", Nullable: true},
+	{Name: "fullkey", DataType: "string"},
+	{Name: "path", DataType: "string"},
 }
 
 // FunctionSignatures maps Dialect to function name to signature
@@ -20,26 +59,67 @@ var FunctionSignatures = map[Dialect]map[string]FunctionSignature{
 		"IFNULL":    {ReturnTypeByArg: true, NullableByArg: true},
 		"CAST":      {CastType: true, NullableByArg: true},
 		"UPPER":     {ReturnType: "string", NullableByArg: true},
-		"NOW":       {ReturnType: "time", Nullable: false},
-		"DATE_ADD":  {ReturnType: "time", NullableByArg: true},
+		"NOW":       {ReturnType: "timestamp", Nullable: false},
+		"DATE_ADD":  {ReturnType: "timestamp", NullableByArg: true},
 		"SUBSTRING": {ReturnType: "string", NullableByArg: true},
 		"TRIM":      {ReturnType: "string", NullableByArg: true},
 		// ウインドウ関数
-		"ROW_NUMBER":         {ReturnType: "int", Nullable: false},
-		"RANK":               {ReturnType: "int", Nullable: false},
-		"DENSE_RANK":         {ReturnType: "int", Nullable: false},
-		"SUM":                {ReturnTypeByArg: true, NullableByArg: true},
-		"AVG":                {ReturnTypeByArg: true, NullableByArg: true},
-		"COUNT":              {ReturnType: "int", Nullable: false},
-		"MIN":                {ReturnTypeByArg: true, NullableByArg: true},
-		"MAX":                {ReturnTypeByArg: true, NullableByArg: true},
-		"FIRST_VALUE":        {ReturnTypeByArg: true, NullableByArg: true},
-		"LAST_VALUE":         {ReturnTypeByArg: true, NullableByArg: true},
-		"LEAD":               {ReturnTypeByArg: true, NullableByArg: true},
-		"LAG":                {ReturnTypeByArg: true, NullableByArg: true},
-		"ARRAY":              {ReturnType: "array", NullableByArg: true},
-		"UNNEST":             {ReturnType: "any", NullableByArg: true},
-		"JSONB_BUILD_OBJECT": {ReturnType: "any", NullableByArg: true},
+		"ROW_NUMBER":  {ReturnType: "int", Nullable: false},
+		"RANK":        {ReturnType: "int", Nullable: false},
+		"DENSE_RANK":  {ReturnType: "int", Nullable: false},
+		"SUM":         {ReturnTypeByArg: true, NullableByArg: true},
+		"AVG":         {ReturnTypeByArg: true, NullableByArg: true},
+		"COUNT":       {ReturnType: "int", Nullable: false},
+		"MIN":         {ReturnTypeByArg: true, NullableByArg: true},
+		"MAX":         {ReturnTypeByArg: true, NullableByArg: true},
+		"FIRST_VALUE": {ReturnTypeByArg: true, NullableByArg: true},
+		"LAST_VALUE":  {ReturnTypeByArg: true, NullableByArg: true},
+		"LEAD":        {ReturnTypeByArg: true, NullableByArg: true},
+		"LAG":         {ReturnTypeByArg: true, NullableByArg: true},
+		"ARRAY":       {ReturnType: "array", NullableByArg: true},
+		"UNNEST":      {ReturnType: "any", NullableByArg: true},
+		// JSON 関数
+		"JSON_BUILD_OBJECT":       {ReturnType: "json", Nullable: false},
+		"JSONB_BUILD_OBJECT":      {ReturnType: "json", Nullable: false},
+		"JSON_BUILD_ARRAY":        {ReturnType: "json", Nullable: false},
+		"JSONB_BUILD_ARRAY":       {ReturnType: "json", Nullable: false},
+		"JSON_OBJECT":             {ReturnType: "json", Nullable: false},
+		"JSON_ARRAY":              {ReturnType: "json", Nullable: false},
+		"JSON_AGG":                {ReturnType: "json", Nullable: true},
+		"JSONB_AGG":               {ReturnType: "json", Nullable: true},
+		"TO_JSON":                 {ReturnType: "json", NullableByArg: true},
+		"TO_JSONB":                {ReturnType: "json", NullableByArg: true},
+		"JSON_EXTRACT_PATH":       {ReturnType: "json", Nullable: true},
+		"JSONB_EXTRACT_PATH":      {ReturnType: "json", Nullable: true},
+		"JSON_EXTRACT_PATH_TEXT":  {ReturnType: "string", Nullable: true},
+		"JSONB_EXTRACT_PATH_TEXT": {ReturnType: "string", Nullable: true},
+		"JSONB_SET":               {ReturnType: "json", NullableByArg: true},
+		"JSON_TYPEOF":             {ReturnType: "string", NullableByArg: true},
+		"JSONB_TYPEOF":            {ReturnType: "string", NullableByArg: true},
+		"JSON_ARRAY_LENGTH":       {ReturnType: "int", NullableByArg: true},
+		"JSONB_ARRAY_LENGTH":      {ReturnType: "int", NullableByArg: true},
+		"JSONB_PATH_EXISTS":       {ReturnType: "bool", NullableByArg: true},
+		"JSONB_PATH_QUERY_FIRST":  {ReturnType: "json", Nullable: true},
+		"JSONB_PATH_QUERY_ARRAY":  {ReturnType: "json", NullableByArg: true},
+		// FROM 句で使う JSON 関数
+		"JSONB_PATH_QUERY": {ReturnType: "json", NullableByArg: true, TableColumns: []*ColumnInfo{
+			{Name: "jsonb_path_query", DataType: "json"},
+		}},
+		"JSON_EACH":  {ReturnType: "any", NullableByArg: true, TableColumns: postgresJSONEachColumns},
+		"JSONB_EACH": {ReturnType: "any", NullableByArg: true, TableColumns: postgresJSONEachColumns},
+		"JSON_EACH_TEXT": {ReturnType: "any", NullableByArg: true, TableColumns: []*ColumnInfo{
+			{Name: "key", DataType: "string"},
+			{Name: "value", DataType: "string", Nullable: true},
+		}},
+		"JSON_ARRAY_ELEMENTS": {ReturnType: "json", NullableByArg: true, TableColumns: []*ColumnInfo{
+			{Name: "value", DataType: "json", Nullable: true},
+		}},
+		"JSONB_ARRAY_ELEMENTS": {ReturnType: "json", NullableByArg: true, TableColumns: []*ColumnInfo{
+			{Name: "value", DataType: "json", Nullable: true},
+		}},
+		"JSON_ARRAY_ELEMENTS_TEXT": {ReturnType: "string", NullableByArg: true, TableColumns: []*ColumnInfo{
+			{Name: "value", DataType: "string", Nullable: true},
+		}},
 	},
 	DialectMySQL: {
 		"LENGTH":    {ReturnType: "int", NullableByArg: true},
@@ -47,8 +127,8 @@ var FunctionSignatures = map[Dialect]map[string]FunctionSignature{
 		"IFNULL":    {ReturnTypeByArg: true, NullableByArg: true},
 		"CAST":      {CastType: true, NullableByArg: true},
 		"UPPER":     {ReturnType: "string", NullableByArg: true},
-		"NOW":       {ReturnType: "time", Nullable: false},
-		"DATE_ADD":  {ReturnType: "time", NullableByArg: true},
+		"NOW":       {ReturnType: "timestamp", Nullable: false},
+		"DATE_ADD":  {ReturnType: "timestamp", NullableByArg: true},
 		"SUBSTRING": {ReturnType: "string", NullableByArg: true},
 		"TRIM":      {ReturnType: "string", NullableByArg: true},
 		// ウインドウ関数
@@ -64,6 +144,19 @@ var FunctionSignatures = map[Dialect]map[string]FunctionSignature{
 		"LAST_VALUE":  {ReturnTypeByArg: true, NullableByArg: true},
 		"LEAD":        {ReturnTypeByArg: true, NullableByArg: true},
 		"LAG":         {ReturnTypeByArg: true, NullableByArg: true},
+		// JSON 関数
+		"JSON_EXTRACT":       {ReturnType: "json", Nullable: true},
+		"JSON_UNQUOTE":       {ReturnType: "string", NullableByArg: true},
+		"JSON_OBJECT":        {ReturnType: "json", Nullable: false},
+		"JSON_ARRAY":         {ReturnType: "json", Nullable: false},
+		"JSON_ARRAYAGG":      {ReturnType: "json", Nullable: true},
+		"JSON_OBJECTAGG":     {ReturnType: "json", Nullable: true},
+		"JSON_SET":           {ReturnType: "json", NullableByArg: true},
+		"JSON_KEYS":          {ReturnType: "json", Nullable: true},
+		"JSON_CONTAINS":      {ReturnType: "bool", NullableByArg: true},
+		"JSON_CONTAINS_PATH": {ReturnType: "bool", NullableByArg: true},
+		"JSON_LENGTH":        {ReturnType: "int", Nullable: true},
+		"JSON_TYPE":          {ReturnType: "string", NullableByArg: true},
 	},
 	DialectSQLite: {
 		"LENGTH":    {ReturnType: "int", NullableByArg: true},
@@ -71,10 +164,92 @@ var FunctionSignatures = map[Dialect]map[string]FunctionSignature{
 		"IFNULL":    {ReturnTypeByArg: true, NullableByArg: true},
 		"CAST":      {CastType: true, NullableByArg: true},
 		"UPPER":     {ReturnType: "string", NullableByArg: true},
-		"NOW":       {ReturnType: "time", Nullable: false},
-		"DATE_ADD":  {ReturnType: "time", NullableByArg: true},
+		"NOW":       {ReturnType: "timestamp", Nullable: false},
+		"DATE_ADD":  {ReturnType: "timestamp", NullableByArg: true},
+		"SUBSTRING": {ReturnType: "string", NullableByArg: true},
+		"TRIM":      {ReturnType: "string", NullableByArg: true},
+		// ウインドウ関数
+		"ROW_NUMBER":  {ReturnType: "int", Nullable: false},
+		"RANK":        {ReturnType: "int", Nullable: false},
+		"DENSE_RANK":  {ReturnType: "int", Nullable: false},
+		"SUM":         {ReturnTypeByArg: true, NullableByArg: true},
+		"AVG":         {ReturnTypeByArg: true, NullableByArg: true},
+		"COUNT":       {ReturnType: "int", Nullable: false},
+		"MIN":         {ReturnTypeByArg: true, NullableByArg: true},
+		"MAX":         {ReturnTypeByArg: true, NullableByArg: true},
+		"FIRST_VALUE": {ReturnTypeByArg: true, NullableByArg: true},
+		"LAST_VALUE":  {ReturnTypeByArg: true, NullableByArg: true},
+		"LEAD":        {ReturnTypeByArg: true, NullableByArg: true},
+		"LAG":         {ReturnTypeByArg: true, NullableByArg: true},
+		// JSON 関数 (JSON_EXTRACT はパスの値に応じた SQL の型を返す)
+		"JSON":              {ReturnType: "json", NullableByArg: true},
+		"JSON_EXTRACT":      {ReturnType: "any", Nullable: true},
+		"JSON_OBJECT":       {ReturnType: "json", Nullable: false},
+		"JSON_ARRAY":        {ReturnType: "json", Nullable: false},
+		"JSON_GROUP_ARRAY":  {ReturnType: "json", Nullable: false},
+		"JSON_GROUP_OBJECT": {ReturnType: "json", Nullable: false},
+		"JSON_SET":          {ReturnType: "json", NullableByArg: true},
+		"JSON_ARRAY_LENGTH": {ReturnType: "int", Nullable: true},
+		"JSON_TYPE":         {ReturnType: "string", Nullable: true},
+		// FROM 句で使う JSON 関数
+		"JSON_EACH": {ReturnType: "any", NullableByArg: true, TableColumns: sqliteJSONEachColumns},
+		"JSON_TREE": {ReturnType: "any", NullableByArg: true, TableColumns: sqliteJSONEachColumns},
+	},
+	DialectDuckDB: {
+		"LENGTH":    {ReturnType: "int", NullableByArg: true},
+		"COALESCE":  {ReturnTypeByArg: true, NullableByArg: true},
+		"IFNULL":    {ReturnTypeByArg: true, NullableByArg: true},
+		"CAST":      {CastType: true, NullableByArg: true},
+		"UPPER":     {ReturnType: "string", NullableByArg: true},
+		"NOW":       {ReturnType: "timestamp", Nullable: false},
+		"DATE_ADD":  {ReturnType: "timestamp", NullableByArg: true},
+		"SUBSTRING": {ReturnType: "string", NullableByArg: true},
+		"TRIM":      {ReturnType: "string", NullableByArg: true},
+		// ウインドウ関数
+		"ROW_NUMBER":  {ReturnType: "int", Nullable: false},
+		"RANK":        {ReturnType: "int", Nullable: false},
+		"DENSE_RANK":  {ReturnType: "int", Nullable: false},
+		"SUM":         {ReturnTypeByArg: true, NullableByArg: true},
+		"AVG":         {ReturnTypeByArg: true, NullableByArg: true},
+		"COUNT":       {ReturnType: "int", Nullable: false},
+		"MIN":         {ReturnTypeByArg: true, NullableByArg: true},
+		"MAX":         {ReturnTypeByArg: true, NullableByArg: true},
+		"FIRST_VALUE": {ReturnTypeByArg: true, NullableByArg: true},
+		"LAST_VALUE":  {ReturnTypeByArg: true, NullableByArg: true},
+		"LEAD":        {ReturnTypeByArg: true, NullableByArg: true},
+		"LAG":         {ReturnTypeByArg: true, NullableByArg: true},
+		"ARRAY":       {ReturnType: "array", NullableByArg: true},
+		"UNNEST":      {ReturnType: "any", NullableByArg: true},
+		// 分析用の集約関数
+		"LIST":                  {ReturnType: "array", NullableByArg: true},
+		"MEDIAN":                {ReturnTypeByArg: true, NullableByArg: true},
+		"QUANTILE_CONT":         {ReturnType: "float", NullableByArg: true},
+		"APPROX_COUNT_DISTINCT": {ReturnType: "int", Nullable: false},
+		// JSON 関数
+		"JSON_EXTRACT":        {ReturnType: "json", Nullable: true},
+		"JSON_EXTRACT_STRING": {ReturnType: "string", Nullable: true},
+		"JSON_OBJECT":         {ReturnType: "json", Nullable: false},
+		"JSON_ARRAY":          {ReturnType: "json", Nullable: false},
+		"JSON_GROUP_ARRAY":    {ReturnType: "json", Nullable: false},
+		"JSON_ARRAY_LENGTH":   {ReturnType: "int", Nullable: true},
+		"JSON_TYPE":           {ReturnType: "string", Nullable: true},
+		// FROM 句で使う JSON 関数
+		"JSON_EACH": {ReturnType: "any", NullableByArg: true, TableColumns: sqliteJSONEachColumns},
+		"JSON_TREE": {ReturnType: "any", NullableByArg: true, TableColumns: sqliteJSONEachColumns},
+	},
+	DialectClickHouse: {
+		"LENGTH":    {ReturnType: "int", NullableByArg: true},
+		"COALESCE":  {ReturnTypeByArg: true, NullableByArg: true},
+		"IFNULL":    {ReturnTypeByArg: true, NullableByArg: true},
+		"CAST":      {CastType: true, NullableByArg: true},
+		"UPPER":     {ReturnType: "string", NullableByArg: true},
 		"SUBSTRING": {ReturnType: "string", NullableByArg: true},
 		"TRIM":      {ReturnType: "string", NullableByArg: true},
+		// 日時関数
+		"NOW":        {ReturnType: "timestamp", Nullable: false},
+		"TODAY":      {ReturnType: "date", Nullable: false},
+		"TODATETIME": {ReturnType: "timestamp", NullableByArg: true},
+		"TODATE":     {ReturnType: "date", NullableByArg: true},
 		// ウインドウ関数
 		"ROW_NUMBER":  {ReturnType: "int", Nullable: false},
 		"RANK":        {ReturnType: "int", Nullable: false},
@@ -88,5 +263,18 @@ var FunctionSignatures = map[Dialect]map[string]FunctionSignature{
 		"LAST_VALUE":  {ReturnTypeByArg: true, NullableByArg: true},
 		"LEAD":        {ReturnTypeByArg: true, NullableByArg: true},
 		"LAG":         {ReturnTypeByArg: true, NullableByArg: true},
+		// 分析用の集約関数
+		"UNIQ":       {ReturnType: "int", Nullable: false},
+		"GROUPARRAY": {ReturnType: "array", NullableByArg: true},
+		"ARRAYJOIN":  {ReturnType: "any", NullableByArg: true},
+		"QUANTILE":   {ReturnType: "float", NullableByArg: true},
+		// JSON 関数 (値がない場合は型のゼロ値を返す)
+		"JSONEXTRACTSTRING": {ReturnType: "string", NullableByArg: true},
+		"JSONEXTRACTINT":    {ReturnType: "int", NullableByArg: true},
+		"JSONEXTRACTFLOAT":  {ReturnType: "float", NullableByArg: true},
+		"JSONEXTRACTBOOL":   {ReturnType: "bool", NullableByArg: true},
+		"JSONEXTRACTRAW":    {ReturnType: "json", NullableByArg: true},
+		"JSONHAS":           {ReturnType: "bool", NullableByArg: true},
+		"JSONLENGTH":        {ReturnType: "int", NullableByArg: true},
 	},
 }
@@ -0,0 +1,327 @@
+// Package gencache keeps the manifest used by "snapsql generate" to skip templates whose
+// inputs did not change since the previous run and to remove the outputs of deleted templates.
+package gencache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"runtime/debug"
+	"slices"
+)
+
+// FileName is the name of the manifest file written next to snapsql.yaml
+const FileName = ".snapsql-cache"
+
+// manifestVersion is bumped when the manifest layout changes; older manifests are discarded
+const manifestVersion = 1
+
+// Manifest records the inputs and outputs of the previous generation.
+// A nil *Manifest disables caching: nothing is fresh and nothing is recorded.
+type Manifest struct {
+	Version int `json:"version"`
+	// Environment is the hash of everything shared by all templates (config, constants, schema, snapsql build)
+	Environment string `json:"environment"`
+	// Generators maps a generator name to the hash of its configuration
+	Generators map[string]string `json:"generators,omitempty"`
+	// Templates maps a template path to what was generated from it
+	Templates map[string]*Template `json:"templates,omitempty"`
+
+	path string
+	// seen holds the templates found in this run; the others are pruned
+	seen map[string]bool
+	// regenerated holds the intermediate files written in this run
+	regenerated map[string]bool
+	// changedGenerators holds the generators whose configuration differs from the previous run
+	changedGenerators map[string]bool
+	// intermediates indexes Templates by intermediate file
+	intermediates map[string]*Template
+}
+
+// Template is the manifest entry of one template file.
+type Template struct {
+	// Hash is the content hash of the template
+	Hash string `json:"hash"`
+	// Intermediate is the intermediate file generated from the template
+	Intermediate string `json:"intermediate"`
+	// Outputs maps a generator name to the files it wrote for this template
+	Outputs map[string][]string `json:"outputs,omitempty"`
+}
+
+// Load reads the manifest at path. A missing or outdated manifest yields an empty one.
+func Load(path string) (*Manifest, error) {
+	m := &Manifest{path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read generation cache %s: %w", path, err)
+	}
+
+	if err == nil {
+		if err := json.Unmarshal(data, m); err != nil {
+			return nil, fmt.Errorf("failed to parse generation cache %s: %w", path, err)
+		}
+	}
+
+	if m.Version != manifestVersion {
+		m.reset("")
+	}
+
+	m.seen = map[string]bool{}
+	m.regenerated = map[string]bool{}
+	m.changedGenerators = map[string]bool{}
+	m.intermediates = map[string]*Template{}
+
+	for _, tmpl := range m.Templates {
+		m.intermediates[tmpl.Intermediate] = tmpl
+	}
+
+	if m.Generators == nil {
+		m.Generators = map[string]string{}
+	}
+
+	return m, nil
+}
+
+func (m *Manifest) reset(environment string) {
+	m.Version = manifestVersion
+	m.Environment = environment
+	m.Generators = map[string]string{}
+
+	// Entries are kept without hashes so that outputs of removed templates can still be pruned
+	for _, tmpl := range m.Templates {
+		tmpl.Hash = ""
+	}
+}
+
+// Hash returns the hex encoded SHA-256 of the concatenated data.
+func Hash(data ...[]byte) string {
+	h := sha256.New()
+	for _, d := range data {
+		h.Write(d)
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HashValue returns the hash of the JSON encoding of v.
+func HashValue(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash %T: %w", v, err)
+	}
+
+	return Hash(data), nil
+}
+
+// BuildFingerprint identifies the running snapsql build, so that upgrading snapsql
+// regenerates everything.
+func BuildFingerprint() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+
+	fingerprint := info.Main.Version + " " + info.Main.Sum
+
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" || setting.Key == "vcs.modified" {
+			fingerprint += " " + setting.Value
+		}
+	}
+
+	return fingerprint
+}
+
+// SetEnvironment invalidates every template when the shared inputs changed.
+func (m *Manifest) SetEnvironment(hash string) {
+	if m == nil || m.Environment == hash {
+		return
+	}
+
+	m.reset(hash)
+}
+
+// Fresh reports whether the template is unchanged since the previous run and returns the
+// intermediate file generated from it. The template is marked as present in this run.
+func (m *Manifest) Fresh(source, hash string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+
+	m.seen[source] = true
+
+	tmpl, ok := m.Templates[source]
+	if !ok || tmpl.Hash == "" || tmpl.Hash != hash || !exists(tmpl.Intermediate) {
+		return "", false
+	}
+
+	return tmpl.Intermediate, true
+}
+
+// RecordIntermediate stores the hash of a regenerated template and its intermediate file.
+// Outputs of previously generated files are kept so that renamed outputs can be pruned.
+func (m *Manifest) RecordIntermediate(source, hash, intermediate string) {
+	if m == nil {
+		return
+	}
+
+	if m.Templates == nil {
+		m.Templates = map[string]*Template{}
+	}
+
+	tmpl, ok := m.Templates[source]
+	if !ok {
+		tmpl = &Template{}
+		m.Templates[source] = tmpl
+	}
+
+	if tmpl.Intermediate != "" && tmpl.Intermediate != intermediate {
+		_ = os.Remove(tmpl.Intermediate)
+		delete(m.intermediates, tmpl.Intermediate)
+	}
+
+	tmpl.Hash = hash
+	tmpl.Intermediate = intermediate
+	m.intermediates[intermediate] = tmpl
+	m.seen[source] = true
+	m.regenerated[intermediate] = true
+}
+
+// SetGenerator stores the configuration hash of a generator. When it changed, every output
+// of the generator is regenerated.
+func (m *Manifest) SetGenerator(name, hash string) {
+	if m == nil {
+		return
+	}
+
+	if m.Generators[name] != hash {
+		m.changedGenerators[name] = true
+	}
+
+	m.Generators[name] = hash
+}
+
+// UpToDate reports whether the generator may skip the intermediate file: the file was not
+// regenerated in this run, the generator configuration is unchanged, and its outputs exist.
+func (m *Manifest) UpToDate(generator, intermediate string) bool {
+	if m == nil || m.regenerated[intermediate] || m.changedGenerators[generator] {
+		return false
+	}
+
+	tmpl := m.intermediates[intermediate]
+	if tmpl == nil || tmpl.Hash == "" {
+		return false
+	}
+
+	outputs, ok := tmpl.Outputs[generator]
+	if !ok {
+		return false
+	}
+
+	for _, output := range outputs {
+		if !exists(output) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RecordOutputs stores the files a generator wrote for an intermediate file.
+// Files written by the previous run but not by this one are removed.
+func (m *Manifest) RecordOutputs(generator, intermediate string, outputs ...string) {
+	if m == nil {
+		return
+	}
+
+	tmpl := m.intermediates[intermediate]
+	if tmpl == nil {
+		return
+	}
+
+	for _, old := range tmpl.Outputs[generator] {
+		if !slices.Contains(outputs, old) {
+			_ = os.Remove(old)
+		}
+	}
+
+	if tmpl.Outputs == nil {
+		tmpl.Outputs = map[string][]string{}
+	}
+
+	tmpl.Outputs[generator] = outputs
+}
+
+// Prune deletes the intermediate files and outputs of templates that were not seen in this
+// run and returns the removed files. Call it only after processing the whole input directory.
+func (m *Manifest) Prune() ([]string, error) {
+	if m == nil {
+		return nil, nil
+	}
+
+	var (
+		removed []string
+		errs    error
+	)
+
+	for _, source := range slices.Sorted(maps.Keys(m.Templates)) {
+		if m.seen[source] {
+			continue
+		}
+
+		tmpl := m.Templates[source]
+
+		files := []string{tmpl.Intermediate}
+		for _, generator := range slices.Sorted(maps.Keys(tmpl.Outputs)) {
+			files = append(files, tmpl.Outputs[generator]...)
+		}
+
+		for _, file := range files {
+			if file == "" {
+				continue
+			}
+
+			err := os.Remove(file)
+			switch {
+			case err == nil:
+				removed = append(removed, file)
+			case !errors.Is(err, os.ErrNotExist):
+				errs = errors.Join(errs, err)
+			}
+		}
+
+		delete(m.Templates, source)
+		delete(m.intermediates, tmpl.Intermediate)
+	}
+
+	return removed, errs
+}
+
+// Save writes the manifest back to the path it was loaded from.
+func (m *Manifest) Save() error {
+	if m == nil {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal generation cache: %w", err)
+	}
+
+	if err := os.WriteFile(m.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write generation cache %s: %w", m.path, err)
+	}
+
+	return nil
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
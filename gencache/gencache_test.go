@@ -0,0 +1,119 @@
+package gencache_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shibukawa/snapsql/gencache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestManifest(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, gencache.FileName)
+
+	users := filepath.Join(dir, "users.snap.sql")
+	usersJSON := filepath.Join(dir, "users.json")
+	usersGo := filepath.Join(dir, "users.go")
+	posts := filepath.Join(dir, "posts.snap.sql")
+	postsJSON := filepath.Join(dir, "posts.json")
+	postsGo := filepath.Join(dir, "posts.go")
+
+	// First run: everything is generated
+	m, err := gencache.Load(manifestPath)
+	require.NoError(t, err)
+	m.SetEnvironment("env1")
+	m.SetGenerator("go", "go1")
+
+	for _, tc := range []struct{ source, intermediate, output string }{
+		{users, usersJSON, usersGo},
+		{posts, postsJSON, postsGo},
+	} {
+		_, fresh := m.Fresh(tc.source, "v1")
+		assert.False(t, fresh)
+
+		writeFile(t, tc.intermediate, "{}")
+		m.RecordIntermediate(tc.source, "v1", tc.intermediate)
+		assert.False(t, m.UpToDate("go", tc.intermediate))
+
+		writeFile(t, tc.output, "package queries")
+		m.RecordOutputs("go", tc.intermediate, tc.output)
+	}
+
+	require.NoError(t, m.Save())
+
+	// Second run: users changed, posts removed
+	m, err = gencache.Load(manifestPath)
+	require.NoError(t, err)
+	m.SetEnvironment("env1")
+	m.SetGenerator("go", "go1")
+
+	_, fresh := m.Fresh(users, "v2")
+	assert.False(t, fresh)
+	m.RecordIntermediate(users, "v2", usersJSON)
+	assert.False(t, m.UpToDate("go", usersJSON))
+
+	removed, err := m.Prune()
+	require.NoError(t, err)
+	assert.Equal(t, []string{postsJSON, postsGo}, removed)
+	assert.NoFileExists(t, postsGo)
+	require.NoError(t, m.Save())
+
+	// Third run: nothing changed
+	m, err = gencache.Load(manifestPath)
+	require.NoError(t, err)
+	m.SetEnvironment("env1")
+	m.SetGenerator("go", "go1")
+
+	intermediate, fresh := m.Fresh(users, "v2")
+	assert.True(t, fresh)
+	assert.Equal(t, usersJSON, intermediate)
+	assert.True(t, m.UpToDate("go", usersJSON))
+
+	// A deleted output is regenerated
+	require.NoError(t, os.Remove(usersGo))
+	assert.False(t, m.UpToDate("go", usersJSON))
+
+	// Changed generator settings regenerate the outputs of that generator only
+	m, err = gencache.Load(manifestPath)
+	require.NoError(t, err)
+	m.SetEnvironment("env1")
+	m.SetGenerator("go", "go2")
+	_, fresh = m.Fresh(users, "v2")
+	assert.True(t, fresh)
+
+	writeFile(t, usersGo, "package queries")
+	assert.False(t, m.UpToDate("go", usersJSON))
+
+	// Changed environment regenerates everything
+	m, err = gencache.Load(manifestPath)
+	require.NoError(t, err)
+	m.SetEnvironment("env2")
+	_, fresh = m.Fresh(users, "v2")
+	assert.False(t, fresh)
+}
+
+func TestNilManifest(t *testing.T) {
+	var m *gencache.Manifest
+
+	m.SetEnvironment("env")
+	m.SetGenerator("go", "hash")
+	m.RecordIntermediate("a.snap.sql", "hash", "a.json")
+	m.RecordOutputs("go", "a.json", "a.go")
+
+	_, fresh := m.Fresh("a.snap.sql", "hash")
+	assert.False(t, fresh)
+	assert.False(t, m.UpToDate("go", "a.json"))
+
+	removed, err := m.Prune()
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+	require.NoError(t, m.Save())
+}
@@ -176,8 +176,8 @@ func mergeStep7WithAST(step7 map[string]*cmn.SQTableReference, astTables []Table
 
 		if step7Ref != nil {
 			// Keep AST's Name and Alias (more accurate for schema-qualified tables)
-			// Update Source and JoinType from Step7
-			if step7Ref.Context != cmn.SQTableContextMain {
+			// Update Source and JoinType from Step7 (Step7 doesn't know table functions)
+			if step7Ref.Context != cmn.SQTableContextMain && ref.Source != "function" {
 				ref.Source = contextToSource(step7Ref.Context)
 			}
 
@@ -199,6 +199,11 @@ func mergeStep7WithAST(step7 map[string]*cmn.SQTableReference, astTables []Table
 			}
 		}
 
+		// テーブル関数は実テーブルではない
+		if ref.Source == "function" {
+			ref.IsTable = false
+		}
+
 		result = append(result, ref)
 	}
 
@@ -349,8 +354,10 @@ func kindToString(tp cmn.NodeType) string {
 		return "update"
 	case cmn.DELETE_FROM_STATEMENT:
 		return "delete"
+	case cmn.MERGE_STATEMENT:
+		return "merge"
 	default:
-		return "select" // safe default; upstream parser only produces 4 kinds
+		return "select" // safe default
 	}
 }
 
@@ -398,6 +405,23 @@ func extractTables(stmt cmn.StatementNode) []TableRef {
 			})
 		}
 
+		return out
+	case *cmn.MergeStatement:
+		out := make([]TableRef, 0, 1)
+		if s.Into != nil {
+			ref := TableRef{
+				Name:     s.Into.Table.TableName,
+				Schema:   s.Into.Table.SchemaName,
+				Source:   "main",
+				JoinType: "none",
+			}
+			if s.Into.Table.ExplicitName {
+				ref.Alias = s.Into.Table.Name
+			}
+
+			out = append(out, ref)
+		}
+
 		return out
 	default:
 		return nil
@@ -435,6 +459,9 @@ func extractFromClause(with *cmn.WithClause, from *cmn.FromClause) []TableRef {
 			}
 
 			src = "subquery"
+		} else if t.TableFunction {
+			// json_each(...) などのテーブル関数
+			src = "function"
 		}
 
 		// Prefer original table name when available, falling back to Name (which may be alias)
@@ -25,3 +25,25 @@ func TestInspect_SelectSimple(t *testing.T) {
 		t.Fatalf("tables[0] = %+v, want name=users source=main joinType=none", got.Tables[0])
 	}
 }
+
+func TestInspect_TableFunction(t *testing.T) {
+	sql := "SELECT d.id, e.value FROM docs d CROSS JOIN json_each(d.data) AS e"
+
+	got, err := Inspect(strings.NewReader(sql), InspectOptions{InspectMode: true})
+	if err != nil {
+		t.Fatalf("Inspect returned error: %v", err)
+	}
+
+	if len(got.Tables) != 2 {
+		t.Fatalf("tables len = %d, want %d", len(got.Tables), 2)
+	}
+
+	if got.Tables[0].Name != "docs" || !got.Tables[0].IsTable {
+		t.Fatalf("tables[0] = %+v, want name=docs is_table=true", got.Tables[0])
+	}
+
+	fn := got.Tables[1]
+	if fn.Name != "json_each" || fn.Alias != "e" || fn.Source != "function" || fn.IsTable {
+		t.Fatalf("tables[1] = %+v, want name=json_each alias=e source=function is_table=false", fn)
+	}
+}
@@ -0,0 +1,95 @@
+package intermediate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// BinaryFileExtension is the file extension of intermediate files written in the binary encoding
+const BinaryFileExtension = ".snapir"
+
+// binaryFormatVersion is bumped when the encoding changes. Fields are keyed by their JSON names,
+// so added or removed fields do not need a new version; renamed fields and changed types do.
+const binaryFormatVersion byte = 2
+
+// binaryMagic prefixes every binary intermediate file so readers can tell it apart from JSON
+var binaryMagic = []byte("SNAPIR")
+
+const binaryHeaderSize = 6 + 1 // magic, version
+
+var (
+	ErrInvalidBinaryFormat     = errors.New("invalid binary intermediate format")
+	ErrUnsupportedBinaryFormat = errors.New("unsupported binary intermediate format, regenerate the intermediate files")
+)
+
+// binaryModes returns the CBOR encoder and decoder. Encoding is deterministic (sorted map keys) so that
+// unchanged templates produce identical files; maps held in `any` fields decode like JSON objects.
+var binaryModes = sync.OnceValues(func() (cbor.EncMode, cbor.DecMode) {
+	enc, err := cbor.CoreDetEncOptions().EncMode()
+	if err != nil {
+		panic(err)
+	}
+
+	dec, err := cbor.DecOptions{DefaultMapType: reflect.TypeFor[map[string]any]()}.DecMode()
+	if err != nil {
+		panic(err)
+	}
+
+	return enc, dec
+})
+
+// ToBinary serializes the intermediate format to the binary encoding: a magic/version header
+// followed by the format encoded as CBOR (RFC 8949) with the JSON field names as keys
+func (f *IntermediateFormat) ToBinary() ([]byte, error) {
+	enc, _ := binaryModes()
+
+	body, err := enc.Marshal(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode intermediate format: %w", err)
+	}
+
+	buf := make([]byte, 0, binaryHeaderSize+len(body))
+	buf = append(buf, binaryMagic...)
+	buf = append(buf, binaryFormatVersion)
+
+	return append(buf, body...), nil
+}
+
+// FromBinary deserializes the intermediate format from the binary encoding
+func FromBinary(data []byte) (*IntermediateFormat, error) {
+	if !IsBinary(data) || len(data) < binaryHeaderSize {
+		return nil, ErrInvalidBinaryFormat
+	}
+
+	if version := data[len(binaryMagic)]; version != binaryFormatVersion {
+		return nil, fmt.Errorf("%w: version %d (supported: %d)", ErrUnsupportedBinaryFormat, version, binaryFormatVersion)
+	}
+
+	_, dec := binaryModes()
+
+	var format IntermediateFormat
+	if err := dec.Unmarshal(data[binaryHeaderSize:], &format); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidBinaryFormat, err)
+	}
+
+	return &format, nil
+}
+
+// IsBinary reports whether data starts with the binary intermediate format header
+func IsBinary(data []byte) bool {
+	return bytes.HasPrefix(data, binaryMagic)
+}
+
+// Decode deserializes the intermediate format from either the JSON or the binary encoding
+func Decode(data []byte) (*IntermediateFormat, error) {
+	if IsBinary(data) {
+		return FromBinary(data)
+	}
+
+	return FromJSON(data)
+}
@@ -0,0 +1,80 @@
+package intermediate
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	snapsql "github.com/shibukawa/snapsql"
+)
+
+func TestBinaryFormatRoundTrip(t *testing.T) {
+	sql := `/*# parameters: { filter: { name: string, active: bool }, ids: [int] } */
+SELECT id, name FROM users WHERE /*# if filter.active */name = /*= filter.name */'x' AND /*# end */id IN (/*= ids */1)`
+
+	format, err := GenerateFromSQL(strings.NewReader(sql), nil, "list_users.snap.sql", "", nil, &snapsql.Config{Dialect: "postgres"})
+	require.NoError(t, err)
+
+	format.ImplicitParameters = []ImplicitParameter{{Name: "tenant_id", Type: "int", Default: 1}}
+	format.MockTestCases = []snapsql.MockTestCase{{
+		Name:       "returns users",
+		Parameters: map[string]any{"ids": []any{1, 2}, "filter": map[string]any{"name": "alice"}},
+		Responses:  []snapsql.MockResponse{{Expected: []map[string]any{{"id": 1, "name": "alice", "deleted_at": nil}}}},
+	}}
+
+	jsonData, err := format.MarshalJSON()
+	require.NoError(t, err)
+
+	fromJSON, err := FromJSON(jsonData)
+	require.NoError(t, err)
+
+	binaryData, err := format.ToBinary()
+	require.NoError(t, err)
+	assert.True(t, IsBinary(binaryData))
+	assert.False(t, IsBinary(jsonData))
+
+	// Numbers in `any` fields keep their integer types in CBOR, so the formats are compared as JSON
+	fromBinary, err := FromBinary(binaryData)
+	require.NoError(t, err)
+
+	binaryJSON, err := fromBinary.MarshalJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, string(jsonData), string(binaryJSON))
+	assert.Equal(t, fromJSON.Instructions, fromBinary.Instructions)
+	assert.Equal(t, map[string]any{"name": "alice"}, fromBinary.MockTestCases[0].Parameters["filter"])
+	assert.Less(t, len(binaryData), len(jsonData))
+
+	again, err := format.ToBinary()
+	require.NoError(t, err)
+	assert.Equal(t, binaryData, again)
+
+	decoded, err := Decode(binaryData)
+	require.NoError(t, err)
+	assert.Equal(t, fromBinary, decoded)
+
+	decoded, err = Decode(jsonData)
+	require.NoError(t, err)
+	assert.Equal(t, fromJSON, decoded)
+}
+
+func TestFromBinaryErrors(t *testing.T) {
+	_, err := FromBinary([]byte(`{"format_version":"1"}`))
+	require.ErrorIs(t, err, ErrInvalidBinaryFormat)
+
+	data, err := (&IntermediateFormat{FormatVersion: "1"}).ToBinary()
+	require.NoError(t, err)
+
+	_, err = FromBinary(data[:len(data)-1])
+	require.ErrorIs(t, err, ErrInvalidBinaryFormat)
+
+	otherVersion := slices.Clone(data)
+	otherVersion[len(binaryMagic)]++
+	_, err = FromBinary(otherVersion)
+	require.ErrorIs(t, err, ErrUnsupportedBinaryFormat)
+
+	_, err = FromBinary(append(slices.Clone(data), 0))
+	require.ErrorIs(t, err, ErrInvalidBinaryFormat)
+}
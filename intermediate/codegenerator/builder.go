@@ -280,6 +280,10 @@ func (b *InstructionBuilder) ProcessTokens(tokens []tokenizer.Token, opts ...Pro
 		tokens = skipLeadingTrivia(tokens)
 	}
 
+	if err := b.validateFullTextSearchDialect(tokens); err != nil {
+		return err
+	}
+
 	// Step 1: 方言変換（トークン列全体を事前処理）
 	convertedTokens := b.applyDialectConversions(tokens)
 
@@ -343,6 +347,35 @@ func (b *InstructionBuilder) ProcessTokens(tokens []tokenizer.Token, opts ...Pro
 
 				continue
 
+			case "ident", "orderby":
+				// 識別子ディレクティブ: /*# ident expression in [a, b] */dummy_identifier
+				// 並び替えディレクティブ: /*# orderby expression in [a, b desc] */dummy_identifier
+				// 許可リストと共に EMIT_IDENT / EMIT_ORDER_BY を生成し、ダミーの識別子は出力しない
+				op := OpEmitIdent
+				if token.Directive.Type == "orderby" {
+					op = OpEmitOrderBy
+				}
+
+				envIndex := b.getCurrentEnvironmentIndex()
+
+				exprIndex := b.context.AddExpression(token.Directive.Condition, envIndex)
+				b.annotateExpression(exprIndex, token, nil)
+				b.instructions = append(b.instructions, Instruction{
+					Op:        op,
+					Pos:       token.Position.String(),
+					ExprIndex: &exprIndex,
+					Allowed:   slices.Clone(token.Directive.Allowed),
+				})
+
+				if i+1 < len(convertedTokens) && convertedTokens[i+1].Type == tokenizer.DUMMY_START {
+					i++
+					for i+1 < len(convertedTokens) && convertedTokens[i].Type != tokenizer.DUMMY_END {
+						i++
+					}
+				}
+
+				continue
+
 			case "if":
 				// 条件分岐の開始: /*# if condition */
 				// CEL式をコンテキストに追加し、IF命令を生成
@@ -455,6 +488,10 @@ func (b *InstructionBuilder) ProcessTokens(tokens []tokenizer.Token, opts ...Pro
 
 				continue
 
+			case "set_optional":
+				// SET 句の中のものは generateSetClause が取り除くため、ここに来るのはそれ以外の場所
+				return fmt.Errorf("%w: set_optional directive at %s must be in the SET clause of an UPDATE", ErrInvalidOptionalSet, token.Position.String())
+
 			default:
 				// 未知のディレクティブは無視
 				continue
@@ -933,6 +970,28 @@ func (b *InstructionBuilder) applyDialectConversions(tokens []tokenizer.Token) [
 	for i := 0; i < len(normalizedTokens); i++ {
 		token := normalizedTokens[i]
 
+		// ClickHouse の日時変換: CURRENT_TIMESTAMP → now(), CAST(expr AS TIMESTAMP) → toDateTime(expr)
+		if b.shouldConvertClickHouseDateTime(token) {
+			convertedTokens, skip := b.convertClickHouseDateTimeInTokens(normalizedTokens, i)
+			if len(convertedTokens) > 0 {
+				result = append(result, convertedTokens...)
+				i += skip
+
+				continue
+			}
+		}
+
+		// 全文検索の展開: SNAPSQL_FTS(column, query) → to_tsvector(column) @@ plainto_tsquery(query) など
+		if b.shouldConvertFullTextSearch(token) {
+			convertedTokens, skip := b.convertFullTextSearchInTokens(normalizedTokens, i)
+			if len(convertedTokens) > 0 {
+				result = append(result, convertedTokens...)
+				i += skip
+
+				continue
+			}
+		}
+
 		// CAST構文の変換: CAST(expr AS type) ⇔ (expr)::type
 		if b.shouldConvertCast(token) {
 			convertedTokens, skip, leftConsumed := b.convertCastSyntaxInTokens(normalizedTokens, i)
@@ -952,6 +1011,25 @@ func (b *InstructionBuilder) applyDialectConversions(tokens []tokenizer.Token) [
 			}
 		}
 
+		// JSON アクセスの変換: data->>'key' ⇔ JSON_UNQUOTE(JSON_EXTRACT(data, '$.key'))
+		if b.shouldConvertJSONAccess(token) {
+			convertedTokens, skip, leftConsumed := b.convertJSONAccessInTokens(normalizedTokens, i)
+			if len(convertedTokens) > 0 {
+				if leftConsumed > 0 {
+					if leftConsumed > len(result) {
+						leftConsumed = len(result)
+					}
+
+					result = result[:len(result)-leftConsumed]
+				}
+
+				result = append(result, convertedTokens...)
+				i += skip
+
+				continue
+			}
+		}
+
 		// 時間関数の変換: NOW() ⇔ CURRENT_TIMESTAMP
 		if b.shouldConvertTimeFunction(token) {
 			convertedTokens, skip := b.convertTimeFunctionInTokens(normalizedTokens, i)
@@ -1054,8 +1132,8 @@ func (b *InstructionBuilder) shouldConvertTimeFunction(token tokenizer.Token) bo
 func (b *InstructionBuilder) shouldConvertDateTime(token tokenizer.Token) bool {
 	upper := strings.ToUpper(strings.TrimSpace(token.Value))
 
-	// MySQL: CURDATE() → CURRENT_DATE (PostgreSQL/SQLite)
-	if b.context.Dialect == snapsql.DialectPostgres || b.context.Dialect == snapsql.DialectSQLite {
+	// MySQL: CURDATE() → CURRENT_DATE (PostgreSQL/SQLite/DuckDB)
+	if b.context.Dialect == snapsql.DialectPostgres || b.context.Dialect == snapsql.DialectSQLite || b.context.Dialect == snapsql.DialectDuckDB {
 		if upper == "CURDATE" || upper == "CURTIME" {
 			return true
 		}
@@ -2359,6 +2437,14 @@ func (b *InstructionBuilder) RegisterEmitSystemFor() {
 	})
 }
 
+// RegisterEmitSystemSoftDelete registers an EMIT_SYSTEM_SOFT_DELETE instruction that outputs the soft delete filter.
+func (b *InstructionBuilder) RegisterEmitSystemSoftDelete(filter string) {
+	b.instructions = append(b.instructions, Instruction{
+		Op:    OpEmitSystemSoftDelete,
+		Value: filter,
+	})
+}
+
 // CheckEvalResultType determines the type of value that a CEL expression evaluates to.
 // This method inspects the CEL environment and variable information to determine whether
 // the expression represents a scalar value, an array, an object, or an array of objects.
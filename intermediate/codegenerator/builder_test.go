@@ -967,6 +967,91 @@ func TestDialectConversions(t *testing.T) {
 				{Op: OpEmitStatic, Value: "SELECT CONCAT(col1, col2, col3) AS merged FROM t", Pos: "1:1"},
 			},
 		},
+		// === DuckDB ===
+		{
+			category: "duckdb",
+			name:     "CURDATE() to CURRENT_DATE",
+			sql:      "SELECT id, CURDATE() FROM users",
+			dialect:  snapsql.DialectDuckDB,
+			expectedInstructions: []Instruction{
+				{Op: OpEmitStatic, Value: "SELECT id, CURRENT_DATE FROM users", Pos: "1:1"},
+			},
+		},
+		{
+			category: "duckdb",
+			name:     "PostgreSQL-style syntax stays",
+			sql:      "SELECT id::TEXT, name || 'x', TRUE, CURRENT_TIMESTAMP FROM users",
+			dialect:  snapsql.DialectDuckDB,
+			expectedInstructions: []Instruction{
+				{Op: OpEmitStatic, Value: "SELECT id::TEXT, name || 'x', TRUE, CURRENT_TIMESTAMP FROM users", Pos: "1:1"},
+			},
+		},
+		// === CockroachDB ===
+		{
+			category: "cockroach",
+			name:     "Reuses PostgreSQL conversions",
+			sql:      "SELECT CAST(id AS TEXT), CURDATE() FROM users",
+			dialect:  snapsql.DialectCockroach,
+			expectedInstructions: []Instruction{
+				{Op: OpEmitStatic, Value: "SELECT (id)::TEXT, CURRENT_DATE FROM users", Pos: "1:1"},
+			},
+		},
+		// === JSON ===
+		{
+			category: "json",
+			name:     "JSON operators to JSON_EXTRACT on MySQL",
+			sql:      "SELECT data->>'name' AS n, u.data -> 'tags' -> 0 AS t, data->'a'->>'b c' AS b FROM users u",
+			dialect:  snapsql.DialectMySQL,
+			expectedInstructions: []Instruction{
+				{Op: OpEmitStatic, Value: `SELECT JSON_UNQUOTE(JSON_EXTRACT(data, '$.name')) AS n, JSON_EXTRACT(u.data, '$.tags[0]') AS t, JSON_UNQUOTE(JSON_EXTRACT(data, '$.a."b c"')) AS b FROM users u`, Pos: "1:1"},
+			},
+		},
+		{
+			category: "json",
+			name:     "MySQL native path stays",
+			sql:      "SELECT data->>'$.name' FROM users",
+			dialect:  snapsql.DialectMariaDB,
+			expectedInstructions: []Instruction{
+				{Op: OpEmitStatic, Value: "SELECT data->>'$.name' FROM users", Pos: "1:1"},
+			},
+		},
+		{
+			category: "json",
+			name:     "JSON_EXTRACT to JSON operators on PostgreSQL",
+			sql:      "SELECT JSON_UNQUOTE(JSON_EXTRACT(u.data, '$.a[1].b')), JSON_EXTRACT(data, '$.\"x y\"'), JSON_EXTRACT(data, '$[*]') FROM users u",
+			dialect:  snapsql.DialectPostgres,
+			expectedInstructions: []Instruction{
+				{Op: OpEmitStatic, Value: "SELECT u.data->'a'->1->>'b', data->'x y', JSON_EXTRACT(data, '$[*]') FROM users u", Pos: "1:1"},
+			},
+		},
+		{
+			category: "json",
+			name:     "JSON operators stay on SQLite",
+			sql:      "SELECT data->>'name' FROM users",
+			dialect:  snapsql.DialectSQLite,
+			expectedInstructions: []Instruction{
+				{Op: OpEmitStatic, Value: "SELECT data->>'name' FROM users", Pos: "1:1"},
+			},
+		},
+		// === ClickHouse ===
+		{
+			category: "clickhouse",
+			name:     "CURRENT_TIMESTAMP and CURDATE() to now() and today()",
+			sql:      "SELECT id, CURRENT_TIMESTAMP, CURDATE() FROM users",
+			dialect:  snapsql.DialectClickHouse,
+			expectedInstructions: []Instruction{
+				{Op: OpEmitStatic, Value: "SELECT id, now(), today() FROM users", Pos: "1:1"},
+			},
+		},
+		{
+			category: "clickhouse",
+			name:     "CAST to TIMESTAMP to toDateTime()",
+			sql:      "SELECT CAST(created_at AS TIMESTAMP), CAST(id AS TEXT) FROM users",
+			dialect:  snapsql.DialectClickHouse,
+			expectedInstructions: []Instruction{
+				{Op: OpEmitStatic, Value: "SELECT toDateTime(created_at), CAST(id AS TEXT) FROM users", Pos: "1:1"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,224 @@
+package codegenerator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shibukawa/snapsql/parser"
+	"github.com/shibukawa/snapsql/tokenizer"
+)
+
+// CursorKey is an ORDER BY column used as a key of cursor pagination.
+type CursorKey struct {
+	// Column is the SQL expression compared in the keyset condition
+	Column string `json:"column"`
+	// Field is the response field holding the key value of a row
+	Field      string `json:"field"`
+	Descending bool   `json:"descending,omitempty"`
+}
+
+// CursorPagination describes the keyset pagination generated for `pagination: cursor`.
+// The page size is either the literal Limit or the expression LimitExprIndex.
+type CursorPagination struct {
+	Keys           []CursorKey `json:"keys"`
+	Limit          int         `json:"limit,omitempty"`
+	LimitExprIndex *int        `json:"limit_expr_index,omitempty"`
+}
+
+// ConditionArgs returns the key index bound to each placeholder of the keyset condition.
+//
+//	(k0 > ? OR (k0 = ? AND k1 > ?))  →  [0, 0, 1]
+func (p *CursorPagination) ConditionArgs() []int {
+	var args []int
+
+	for i := range p.Keys {
+		for j := 0; j <= i; j++ {
+			args = append(args, j)
+		}
+	}
+
+	return args
+}
+
+// cursorCondition は ORDER BY の順で直前のページの最終行より後ろの行を選ぶ条件式を返す
+func (p *CursorPagination) cursorCondition() string {
+	terms := make([]string, len(p.Keys))
+
+	for i, key := range p.Keys {
+		parts := make([]string, 0, i+1)
+		for _, prev := range p.Keys[:i] {
+			parts = append(parts, prev.Column+" = ?")
+		}
+
+		op := ">"
+		if key.Descending {
+			op = "<"
+		}
+
+		parts = append(parts, key.Column+" "+op+" ?")
+
+		if len(parts) == 1 {
+			terms[i] = parts[0]
+		} else {
+			terms[i] = "(" + strings.Join(parts, " AND ") + ")"
+		}
+	}
+
+	return "(" + strings.Join(terms, " OR ") + ")"
+}
+
+// isCursorPagination は関数定義で pagination: cursor が指定されているかを返す
+func isCursorPagination(ctx *GenerationContext) bool {
+	return ctx.FunctionDefinition != nil && ctx.FunctionDefinition.Pagination == "cursor"
+}
+
+// buildCursorKeys は ORDER BY 句からカーソルのキーを取り出す
+//
+// 備考:
+//   - ORDER BY の各項目は SELECT 句で取得している列でなければならない（次のカーソルを行から作るため）
+//   - 式や NULLS FIRST/LAST は扱わない
+//   - OFFSET 句とは併用できない
+func buildCursorKeys(stmt *parser.SelectStatement) ([]CursorKey, error) {
+	if stmt.OrderBy == nil || len(stmt.OrderBy.Fields) == 0 {
+		return nil, fmt.Errorf("%w: ORDER BY clause is required", ErrInvalidCursorPagination)
+	}
+
+	if stmt.Limit == nil {
+		return nil, fmt.Errorf("%w: LIMIT clause is required", ErrInvalidCursorPagination)
+	}
+
+	if stmt.Offset != nil {
+		return nil, fmt.Errorf("%w: OFFSET clause cannot be used", ErrInvalidCursorPagination)
+	}
+
+	keys := make([]CursorKey, 0, len(stmt.OrderBy.Fields))
+
+	for _, field := range stmt.OrderBy.Fields {
+		name := field.Field.Name
+		if field.Field.TableName != "" {
+			name = field.Field.TableName + "." + name
+		}
+
+		if field.Field.Name == "" || !isPlainOrderByColumn(field.Expression, name) {
+			return nil, fmt.Errorf("%w: ORDER BY item %s must be a column", ErrInvalidCursorPagination, strings.TrimSpace(tokensText(field.Expression)))
+		}
+
+		key, err := resolveCursorKey(stmt.Select, field.Field.TableName, field.Field.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		key.Descending = field.Desc
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// isPlainOrderByColumn は ORDER BY の項目が ASC / DESC 付きの列参照だけからなるかを返す
+func isPlainOrderByColumn(tokens []tokenizer.Token, name string) bool {
+	var text strings.Builder
+
+	for _, token := range tokens {
+		switch token.Type {
+		case tokenizer.WHITESPACE, tokenizer.LINE_COMMENT, tokenizer.BLOCK_COMMENT, tokenizer.ASC, tokenizer.DESC:
+			continue
+		}
+
+		text.WriteString(token.Value)
+	}
+
+	return strings.EqualFold(text.String(), name)
+}
+
+// resolveCursorKey は ORDER BY の列に対応する SELECT 句の項目を探す
+//
+// 別名で並べている場合も、WHERE 句では別名を参照できないため元の列で比較する。
+func resolveCursorKey(clause *parser.SelectClause, table, name string) (CursorKey, error) {
+	if clause != nil {
+		for _, field := range clause.Fields {
+			plain := field.FieldKind == parser.SingleField || field.FieldKind == parser.TableField
+			column := field.OriginalField[strings.LastIndex(field.OriginalField, ".")+1:]
+
+			switch {
+			case table == "" && field.ExplicitName && strings.EqualFold(field.FieldName, name):
+				if !plain {
+					return CursorKey{}, fmt.Errorf("%w: ORDER BY alias %s must refer to a column", ErrInvalidCursorPagination, name)
+				}
+			case plain && strings.EqualFold(column, name) && (table == "" || strings.EqualFold(field.TableName, table)):
+			default:
+				continue
+			}
+
+			return CursorKey{Column: field.OriginalField, Field: field.FieldName}, nil
+		}
+	}
+
+	if table != "" {
+		name = table + "." + name
+	}
+
+	return CursorKey{}, fmt.Errorf("%w: ORDER BY column %s must be in the SELECT clause", ErrInvalidCursorPagination, name)
+}
+
+// generateCursorCondition はカーソルが渡されたときだけ出力されるキーセット条件を追加する
+func generateCursorCondition(pagination *CursorPagination, hasWhere bool, builder *InstructionBuilder) {
+	keyword := "WHERE "
+	if hasWhere {
+		keyword = "AND "
+	}
+
+	builder.instructions = append(builder.instructions, Instruction{
+		Op:    OpEmitCursorCondition,
+		Value: keyword + pagination.cursorCondition(),
+	})
+}
+
+// generateCursorLimit は LIMIT 句をページサイズ + 1 の取得に置き換える
+//
+// 1 行多く取得して次のページがあるかを判定する。ページサイズは数値リテラルか
+// `/*= param */` のどちらかで指定する。システム LIMIT による上書きは行わない。
+func generateCursorLimit(clause *parser.LimitClause, pagination *CursorPagination, builder *InstructionBuilder) error {
+	tokens := clause.RawTokens()
+	builder.addStatic(" LIMIT ", &tokens[0].Position)
+
+	value := limitValueToken(tokens)
+
+	switch {
+	case value == nil:
+	case value.Directive != nil && value.Directive.Type == "variable":
+		exprIndex := builder.context.AddExpression(value.Directive.Condition, builder.getCurrentEnvironmentIndex())
+		builder.annotateExpression(exprIndex, *value, nil)
+		builder.instructions = append(builder.instructions, Instruction{
+			Op:        OpEmitCursorLimit,
+			Pos:       value.Position.String(),
+			ExprIndex: &exprIndex,
+		})
+		pagination.LimitExprIndex = &exprIndex
+
+		return nil
+	case value.Type == tokenizer.NUMBER:
+		if limit, err := strconv.Atoi(value.Value); err == nil && limit > 0 {
+			builder.instructions = append(builder.instructions, Instruction{
+				Op:    OpEmitCursorLimit,
+				Pos:   value.Position.String(),
+				Value: value.Value,
+			})
+			pagination.Limit = limit
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: LIMIT must be a positive number or a /*= param */ at %s", ErrInvalidCursorPagination, tokens[0].Position.String())
+}
+
+func tokensText(tokens []tokenizer.Token) string {
+	var text strings.Builder
+	for _, token := range tokens {
+		text.WriteString(token.Value)
+	}
+
+	return text.String()
+}
@@ -0,0 +1,139 @@
+package codegenerator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorPaginationInstructions(t *testing.T) {
+	config := &snapsql.Config{
+		Tables: map[string]snapsql.TablePerformance{
+			"users": {SoftDelete: &snapsql.SoftDeleteConfig{Column: "deleted_at"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		sql      string
+		expected string // cursor condition is rendered as <...>, soft delete filter as {...}
+		keys     []CursorKey
+		args     []int
+	}{
+		{
+			name:     "single key",
+			sql:      "SELECT id, name FROM posts ORDER BY id LIMIT 20",
+			expected: "SELECT id, name FROM posts <WHERE (id > ?)>ORDER BY id  LIMIT [20]",
+			keys:     []CursorKey{{Column: "id", Field: "id"}},
+			args:     []int{0},
+		},
+		{
+			name:     "or condition is parenthesized",
+			sql:      "/*# parameters: { size: int } */ SELECT id, created_at FROM posts WHERE a = 1 OR b = 2 ORDER BY created_at DESC, id DESC LIMIT /*= size */10",
+			expected: "SELECT id, created_at FROM posts WHERE (a = 1 OR b = 2) <AND (created_at < ? OR (created_at = ? AND id < ?))>ORDER BY created_at DESC, id DESC  LIMIT [?]",
+			keys:     []CursorKey{{Column: "created_at", Field: "created_at", Descending: true}, {Column: "id", Field: "id", Descending: true}},
+			args:     []int{0, 0, 1},
+		},
+		{
+			name:     "alias and table prefix",
+			sql:      "SELECT p.id AS post_id, p.title FROM posts p ORDER BY post_id LIMIT 5",
+			expected: "SELECT p.id AS post_id, p.title FROM posts p <WHERE (p.id > ?)>ORDER BY post_id  LIMIT [5]",
+			keys:     []CursorKey{{Column: "p.id", Field: "post_id"}},
+			args:     []int{0},
+		},
+		{
+			name:     "conditional where",
+			sql:      "/*# parameters: { has_name: bool, name: string } */ SELECT id FROM posts /*# if has_name */ WHERE name = /*= name */'x' /*# end */ ORDER BY id LIMIT 10",
+			expected: "SELECT id FROM posts WHERE (name = ?) <AND (id > ?)>ORDER BY id  LIMIT [10]",
+			keys:     []CursorKey{{Column: "id", Field: "id"}},
+			args:     []int{0},
+		},
+		{
+			name:     "soft delete without where",
+			sql:      "SELECT id FROM users ORDER BY id LIMIT 10",
+			expected: "SELECT id FROM users  WHERE 1 = 1{AND users.deleted_at IS NULL}<AND (id > ?)>ORDER BY id  LIMIT [10]",
+			keys:     []CursorKey{{Column: "id", Field: "id"}},
+			args:     []int{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, _, funcDef, err := parser.ParseSQLFile(strings.NewReader(tt.sql), nil, "", "", parser.Options{})
+			require.NoError(t, err)
+
+			funcDef.Pagination = "cursor"
+			ctx := NewGenerationContext(snapsql.DialectPostgres)
+			ctx.Config = config
+			ctx.FunctionDefinition = funcDef
+
+			instructions, _, _, err := GenerateSelectInstructions(stmt, ctx)
+			require.NoError(t, err)
+
+			var sql strings.Builder
+
+		render:
+			for _, inst := range instructions {
+				switch inst.Op {
+				case OpEmitStatic:
+					sql.WriteString(inst.Value)
+				case OpEmitEval:
+					sql.WriteString("?")
+				case OpEmitSystemSoftDelete:
+					sql.WriteString("{" + inst.Value + "}")
+				case OpEmitCursorCondition:
+					sql.WriteString("<" + inst.Value + ">")
+				case OpEmitCursorLimit:
+					if inst.ExprIndex != nil {
+						sql.WriteString("[?]")
+					} else {
+						sql.WriteString("[" + inst.Value + "]")
+					}
+				case OpIfSystemLimit, OpIfSystemOffset, OpEmitSystemFor:
+					break render
+				}
+			}
+
+			assert.Equal(t, tt.expected, strings.TrimSpace(sql.String()))
+
+			pagination := ctx.CursorPagination()
+			require.NotNil(t, pagination)
+			assert.Equal(t, tt.keys, pagination.Keys)
+			assert.Equal(t, tt.args, pagination.ConditionArgs())
+		})
+	}
+}
+
+func TestCursorPaginationErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		sql           string
+		errorContains string
+	}{
+		{name: "no order by", sql: "SELECT id FROM posts LIMIT 10", errorContains: "ORDER BY clause is required"},
+		{name: "no limit", sql: "SELECT id FROM posts ORDER BY id", errorContains: "LIMIT clause is required"},
+		{name: "offset", sql: "SELECT id FROM posts ORDER BY id LIMIT 10 OFFSET 5", errorContains: "OFFSET clause cannot be used"},
+		{name: "nulls ordering", sql: "SELECT id FROM posts ORDER BY id NULLS LAST LIMIT 10", errorContains: "must be a column"},
+		{name: "key not selected", sql: "SELECT id FROM posts ORDER BY created_at LIMIT 10", errorContains: "must be in the SELECT clause"},
+		{name: "invalid limit", sql: "SELECT id FROM posts ORDER BY id LIMIT 0", errorContains: "LIMIT must be a positive number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, _, funcDef, err := parser.ParseSQLFile(strings.NewReader(tt.sql), nil, "", "", parser.Options{})
+			require.NoError(t, err)
+
+			funcDef.Pagination = "cursor"
+			ctx := NewGenerationContext(snapsql.DialectPostgres)
+			ctx.FunctionDefinition = funcDef
+
+			_, _, _, err = GenerateSelectInstructions(stmt, ctx)
+			require.ErrorIs(t, err, ErrInvalidCursorPagination)
+			assert.Contains(t, err.Error(), tt.errorContains)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package codegenerator
 import (
 	"fmt"
 
+	"github.com/shibukawa/snapsql"
 	"github.com/shibukawa/snapsql/parser"
 )
 
@@ -15,6 +16,10 @@ func generateFromClause(clause *parser.FromClause, builder *InstructionBuilder)
 		return fmt.Errorf("%w: FROM clause is nil", ErrClauseNil)
 	}
 
+	if err := validateLateralDialect(clause, builder.context.Dialect); err != nil {
+		return err
+	}
+
 	// RawTokens をそのまま処理（従来の動作を保つ）
 	// サブクエリーの特殊処理は ProcessTokens 内で実現される
 	tokens := clause.RawTokens()
@@ -24,3 +29,21 @@ func generateFromClause(clause *parser.FromClause, builder *InstructionBuilder)
 
 	return nil
 }
+
+// validateLateralDialect rejects LATERAL subqueries on dialects without LATERAL.
+// LATERAL はそのまま出力するので、CROSS APPLY などへの書き換えは行わない
+func validateLateralDialect(clause *parser.FromClause, dialect snapsql.Dialect) error {
+	switch dialect {
+	case snapsql.DialectSQLite, snapsql.DialectMariaDB, snapsql.DialectClickHouse:
+	default:
+		return nil
+	}
+
+	for _, table := range clause.Tables {
+		if table.Lateral {
+			return fmt.Errorf("%w: %s (subquery %s)", ErrLateralNotSupported, dialect, table.Name)
+		}
+	}
+
+	return nil
+}
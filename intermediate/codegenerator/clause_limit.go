@@ -1,10 +1,23 @@
 package codegenerator
 
 import (
+	"fmt"
+	"strconv"
+
 	"github.com/shibukawa/snapsql/parser"
 	"github.com/shibukawa/snapsql/tokenizer"
 )
 
+// LimitGuard describes the LIMIT guard rails applied to a SELECT from the limits section of snapsql.yaml.
+type LimitGuard struct {
+	// MaxLimit caps the rows of the SELECT; LIMIT parameters are clamped to it (0: no cap)
+	MaxLimit int `json:"max_limit,omitempty"`
+	// Strict makes generated code fail when the SELECT runs without any LIMIT
+	Strict bool `json:"strict,omitempty"`
+	// Unbounded reports that the SELECT has neither a LIMIT clause nor a MaxLimit
+	Unbounded bool `json:"unbounded,omitempty"`
+}
+
 // GenerateLimitClauseOrSystem generates instructions for the LIMIT clause or system LIMIT if not present.
 //
 // This is a unified function that handles both cases:
@@ -51,6 +64,96 @@ func GenerateLimitClauseOrSystem(limitClause *parser.LimitClause, builder *Instr
 	return err
 }
 
+// generateSelectLimitClause は SELECT の LIMIT 句を snapsql.yaml の limits に従って生成する
+//
+// 上限（limits.max_limit / limits.queries）がある場合:
+//   - LIMIT 句がなければ LIMIT <上限> を付ける
+//   - 数値リテラルの LIMIT が上限を超えていればエラーにする
+//   - `/*= param */` の LIMIT は生成コードが上限に丸めて束縛する
+//
+// 上限付きの IF_SYSTEM_LIMIT / EMIT_SYSTEM_LIMIT は DefaultValue に上限を持ち、最適化で出力される。
+// 上限がなければ従来どおり GenerateLimitClauseOrSystem に任せる。
+func generateSelectLimitClause(limitClause *parser.LimitClause, builder *InstructionBuilder) error {
+	ctx := builder.context
+	if ctx == nil || ctx.Config == nil {
+		return GenerateLimitClauseOrSystem(limitClause, builder)
+	}
+
+	functionName := ""
+	if ctx.FunctionDefinition != nil {
+		functionName = ctx.FunctionDefinition.FunctionName
+	}
+
+	maxLimit := ctx.Config.Limits.MaxLimitFor(functionName)
+	strict := ctx.Config.Limits.Strict
+
+	if maxLimit > 0 || strict {
+		ctx.limitGuard = &LimitGuard{
+			MaxLimit:  maxLimit,
+			Strict:    strict,
+			Unbounded: limitClause == nil && maxLimit == 0,
+		}
+	}
+
+	if maxLimit == 0 {
+		return GenerateLimitClauseOrSystem(limitClause, builder)
+	}
+
+	maxValue := strconv.Itoa(maxLimit)
+
+	if limitClause == nil {
+		builder.instructions = append(builder.instructions, Instruction{Op: OpIfSystemLimit, DefaultValue: maxValue})
+		builder.addStatic(" LIMIT ", nil)
+		builder.instructions = append(builder.instructions, Instruction{Op: OpEmitSystemLimit, DefaultValue: maxValue})
+		builder.addEndCondition(nil)
+
+		return nil
+	}
+
+	tokens := limitClause.RawTokens()
+	value := limitValueToken(tokens)
+
+	switch {
+	case value == nil:
+	case value.Directive != nil && value.Directive.Type == "variable":
+		builder.addStatic(" LIMIT ", &tokens[0].Position)
+
+		exprIndex := builder.context.AddExpression(value.Directive.Condition, builder.getCurrentEnvironmentIndex())
+		builder.annotateExpression(exprIndex, *value, nil)
+		builder.instructions = append(builder.instructions,
+			Instruction{Op: OpIfSystemLimit, DefaultValue: maxValue},
+			Instruction{Op: OpEmitSystemLimit, Pos: value.Position.String(), ExprIndex: &exprIndex, DefaultValue: maxValue},
+		)
+		builder.addEndCondition(nil)
+
+		return nil
+	case value.Type == tokenizer.NUMBER:
+		limit, err := strconv.Atoi(value.Value)
+		if err != nil {
+			break
+		}
+
+		if limit > maxLimit {
+			return fmt.Errorf("%w: LIMIT %d of %s exceeds the limit %d configured in snapsql.yaml at %s", ErrLimitExceeded, limit, functionName, maxLimit, value.Position.String())
+		}
+
+		return GenerateLimitClauseOrSystem(limitClause, builder)
+	}
+
+	return fmt.Errorf("%w: LIMIT of %s must be a number or a /*= param */ when limits are configured at %s", ErrLimitExceeded, functionName, tokens[0].Position.String())
+}
+
+// limitValueToken は LIMIT キーワードの次にある値のトークンを返す
+func limitValueToken(tokens []tokenizer.Token) *tokenizer.Token {
+	for i := 1; i < len(tokens); i++ {
+		if tokens[i].Type != tokenizer.WHITESPACE && tokens[i].Type != tokenizer.LINE_COMMENT {
+			return &tokens[i]
+		}
+	}
+
+	return nil
+}
+
 func trimTrailingWhitespaceTokens(tokens []tokenizer.Token) []tokenizer.Token {
 	end := len(tokens)
 	for end > 0 {
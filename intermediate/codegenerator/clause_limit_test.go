@@ -0,0 +1,116 @@
+package codegenerator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectLimitGuard(t *testing.T) {
+	config := &snapsql.Config{
+		Limits: snapsql.LimitsConfig{
+			MaxLimit: 100,
+			Queries:  map[string]int{"export_*": 0},
+			Strict:   true,
+		},
+	}
+
+	tests := []struct {
+		name         string
+		functionName string
+		sql          string
+		expected     string // the cap of a clamped LIMIT parameter is rendered as {max}
+		guard        *LimitGuard
+	}{
+		{
+			name:         "without limit",
+			functionName: "list_users",
+			sql:          "SELECT id, name FROM users",
+			expected:     "SELECT id, name FROM users LIMIT 100",
+			guard:        &LimitGuard{MaxLimit: 100, Strict: true},
+		},
+		{
+			name:         "parameterized limit is clamped",
+			functionName: "list_users",
+			sql:          "/*# parameters: { size: int } */ SELECT id FROM users LIMIT /*= size */10",
+			expected:     "SELECT id FROM users LIMIT ?{100}",
+			guard:        &LimitGuard{MaxLimit: 100, Strict: true},
+		},
+		{
+			name:         "literal limit within the cap",
+			functionName: "list_users",
+			sql:          "SELECT id FROM users LIMIT 50",
+			expected:     "SELECT id FROM users LIMIT 50",
+			guard:        &LimitGuard{MaxLimit: 100, Strict: true},
+		},
+		{
+			name:         "cap removed per query",
+			functionName: "export_users",
+			sql:          "SELECT id FROM users",
+			expected:     "SELECT id FROM users",
+			guard:        &LimitGuard{Strict: true, Unbounded: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, _, funcDef, err := parser.ParseSQLFile(strings.NewReader(tt.sql), nil, "", "", parser.Options{})
+			require.NoError(t, err)
+
+			funcDef.FunctionName = tt.functionName
+			ctx := NewGenerationContext(snapsql.DialectPostgres)
+			ctx.Config = config
+			ctx.FunctionDefinition = funcDef
+
+			instructions, _, _, err := GenerateSelectInstructions(stmt, ctx)
+			require.NoError(t, err)
+
+			optimized, err := OptimizeInstructions(instructions, snapsql.DialectMySQL)
+			require.NoError(t, err)
+
+			var sql strings.Builder
+
+			for _, inst := range optimized {
+				switch inst.Op {
+				case "EMIT_STATIC":
+					sql.WriteString(inst.Value)
+				case OpEmitSystemLimit:
+					require.NotNil(t, inst.ExprIndex)
+					sql.WriteString("{" + inst.Value + "}")
+				}
+			}
+
+			assert.Equal(t, tt.expected, strings.Join(strings.Fields(sql.String()), " "))
+			assert.Equal(t, tt.guard, ctx.LimitGuard())
+		})
+	}
+}
+
+func TestSelectLimitGuardErrors(t *testing.T) {
+	config := &snapsql.Config{Limits: snapsql.LimitsConfig{MaxLimit: 100}}
+
+	tests := []struct {
+		name string
+		sql  string
+	}{
+		{name: "literal over the cap", sql: "SELECT id FROM users LIMIT 500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, _, funcDef, err := parser.ParseSQLFile(strings.NewReader(tt.sql), nil, "", "", parser.Options{})
+			require.NoError(t, err)
+
+			ctx := NewGenerationContext(snapsql.DialectPostgres)
+			ctx.Config = config
+			ctx.FunctionDefinition = funcDef
+
+			_, _, _, err = GenerateSelectInstructions(stmt, ctx)
+			assert.ErrorIs(t, err, ErrLimitExceeded)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package codegenerator
 import (
 	"fmt"
 
+	"github.com/shibukawa/snapsql"
 	"github.com/shibukawa/snapsql/parser"
 )
 
@@ -20,8 +21,12 @@ import (
 //	INPUT:  ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name
 //	OUTPUT: EMIT_STATIC " ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name"
 //
+//	INPUT:  ON DUPLICATE KEY UPDATE name = VALUES(name)
+//	OUTPUT: EMIT_STATIC " ON DUPLICATE KEY UPDATE name = VALUES(name)"
+//
 // 備考:
-//   - PostgreSQL固有の機能（Phase 3実装）
+//   - ON CONFLICT は PostgreSQL / SQLite / DuckDB、ON DUPLICATE KEY UPDATE は MySQL / MariaDB
+//   - 方言が対応していない構文はエラー
 //   - DO NOTHING と DO UPDATE SET ... の両方をサポート
 //   - EXCLUDED 疑似テーブルの参照を適切に処理
 //   - 制約名またはカラムリストでの競合検出をサポート
@@ -32,6 +37,12 @@ func generateOnConflictClause(onConflict *parser.OnConflictClause, builder *Inst
 		return nil
 	}
 
+	if builder.context != nil {
+		if err := validateUpsertDialect(onConflict, builder.context.Dialect); err != nil {
+			return err
+		}
+	}
+
 	// RawTokens をそのまま処理
 	tokens := onConflict.RawTokens()
 	if err := builder.ProcessTokens(tokens); err != nil {
@@ -40,3 +51,22 @@ func generateOnConflictClause(onConflict *parser.OnConflictClause, builder *Inst
 
 	return nil
 }
+
+func validateUpsertDialect(onConflict *parser.OnConflictClause, dialect snapsql.Dialect) error {
+	switch dialect {
+	case snapsql.DialectMySQL, snapsql.DialectMariaDB:
+		if !onConflict.IsDuplicateKeyUpdate() {
+			return fmt.Errorf("%w: %s uses ON DUPLICATE KEY UPDATE instead of ON CONFLICT", ErrUpsertNotSupported, dialect)
+		}
+	case snapsql.DialectClickHouse:
+		return fmt.Errorf("%w: %s", ErrUpsertNotSupported, dialect)
+	case "":
+		// 方言未指定（テスト等）では検証しない
+	default:
+		if onConflict.IsDuplicateKeyUpdate() {
+			return fmt.Errorf("%w: %s uses ON CONFLICT instead of ON DUPLICATE KEY UPDATE", ErrUpsertNotSupported, dialect)
+		}
+	}
+
+	return nil
+}
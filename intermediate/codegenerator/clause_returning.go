@@ -23,7 +23,7 @@ import (
 // 備考:
 //   - PostgreSQLとSQLiteでサポート
 //   - MariaDB は INSERT に限定して RETURNING をサポート
-//   - MySQL は RETURNING をサポートしない
+//   - MySQL は RETURNING をサポートしない（INSERT では呼び出されず、挿入行は後続の SELECT で読み戻す）
 //   - SELECT句と同様のカラムリスト処理
 //   - ワイルドカード（*）のサポート
 //   - 式や関数呼び出しのサポート（例: RETURNING id, UPPER(name)）
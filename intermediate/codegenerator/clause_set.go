@@ -2,10 +2,22 @@ package codegenerator
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/shibukawa/snapsql"
 	"github.com/shibukawa/snapsql/parser"
+	"github.com/shibukawa/snapsql/tokenizer"
 )
 
+// OptionalSetColumn is a SET assignment after the set_optional directive. The assignment is
+// emitted only when its parameter is not null.
+type OptionalSetColumn struct {
+	Column    string `json:"column"`
+	Parameter string `json:"parameter"`
+	// ExprIndex is the expression tested by the IF instruction around the assignment
+	ExprIndex int `json:"expr_index"`
+}
+
 // generateSetClause は SET 節から命令を生成する
 //
 // SET節の構造:
@@ -20,14 +32,8 @@ import (
 //   - error: エラー
 //
 // 備考:
-//   - システムフィールド（updated_at等）はこの関数呼び出し後に別途追加される
+//   - システムフィールド（updated_at等）は SET 句の代入の後に追加される
 func generateSetClause(clause *parser.SetClause, builder *InstructionBuilder) error {
-	// SET トークンを処理
-	tokens := clause.RawTokens()
-	if err := builder.ProcessTokens(tokens); err != nil {
-		return fmt.Errorf("code generation: %w", err)
-	}
-
 	// 既存のアサインメントからフィールド名を抽出（重複排除用）
 	existingFields := make(map[string]bool)
 	for _, assign := range clause.Assigns {
@@ -42,7 +48,275 @@ func generateSetClause(clause *parser.SetClause, builder *InstructionBuilder) er
 		return err
 	}
 
+	// SET トークンを処理
+	tokens := clause.RawTokens()
+
+	if marker := findSetOptionalMarker(tokens); marker >= 0 {
+		return generateOptionalSetAssignments(tokens, marker, fields, builder)
+	}
+
+	if err := builder.ProcessTokens(tokens); err != nil {
+		return fmt.Errorf("code generation: %w", err)
+	}
+
 	appendSystemFieldUpdates(builder, fields)
 
 	return nil
 }
+
+// findSetOptionalMarker は /*# set_optional */ ディレクティブの位置を返す（なければ -1）
+func findSetOptionalMarker(tokens []tokenizer.Token) int {
+	for i, token := range tokens {
+		if token.Directive != nil && token.Directive.Type == "set_optional" {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// generateOptionalSetAssignments は set_optional ディレクティブを含む SET 句から命令を生成する
+//
+// ディレクティブより前の代入とシステムフィールドは常に出力し、後ろの代入はそれぞれ
+//
+//	IF param / EMIT_UNLESS_BOUNDARY "," / column = ? / END
+//
+// で囲む。パラメータが null の代入は SET 句から取り除かれる。
+//
+// 備考:
+//   - ディレクティブより後ろの代入は `column = /*= param */dummy` の形でなければならない
+//   - システムフィールドは任意の代入より前に出力する（すべて省略されてもカンマが残らないように）
+//   - 取り除かれる代入の情報は GenerationContext.OptionalSetColumns() で参照できる
+func generateOptionalSetAssignments(tokens []tokenizer.Token, marker int, fields []snapsql.SystemField, builder *InstructionBuilder) error {
+	// ディレクティブより前（SET キーワードと必須の代入）を処理
+	required := trimTrailingSetDelimiter(tokens[:marker])
+	if err := builder.ProcessTokens(required); err != nil {
+		return fmt.Errorf("code generation: %w", err)
+	}
+
+	hasRequired := hasSetAssignment(required)
+
+	if len(fields) > 0 {
+		first := len(builder.instructions)
+		appendSystemFieldUpdates(builder, fields)
+
+		if !hasRequired {
+			builder.instructions[first].Value = " " + strings.TrimPrefix(builder.instructions[first].Value, ", ")
+		}
+	} else if !hasRequired {
+		// 必須の代入がない場合、最初の任意の代入の前にカンマを出さない
+		builder.RegisterBoundary()
+	}
+
+	for _, group := range splitSetAssignments(tokens[marker+1:]) {
+		column, directive, err := parseOptionalSetAssignment(group)
+		if err != nil {
+			return err
+		}
+
+		exprIndex := builder.context.AddExpression(directive.Directive.Condition, builder.getCurrentEnvironmentIndex())
+		builder.annotateExpression(exprIndex, directive, nil)
+		builder.instructions = append(builder.instructions, Instruction{
+			Op:        OpIf,
+			Pos:       directive.Position.String(),
+			ExprIndex: &exprIndex,
+		})
+		builder.instructions = append(builder.instructions, Instruction{
+			Op:    OpEmitUnlessBoundary,
+			Value: ",",
+			Pos:   group[0].Position.String(),
+		})
+		builder.addStatic(" ", &group[0].Position)
+
+		if err := builder.ProcessTokens(group); err != nil {
+			return fmt.Errorf("code generation: %w", err)
+		}
+
+		builder.instructions = append(builder.instructions, Instruction{
+			Op:  OpEnd,
+			Pos: directive.Position.String(),
+		})
+
+		builder.context.addOptionalSetColumn(OptionalSetColumn{
+			Column:    column,
+			Parameter: directive.Directive.Condition,
+			ExprIndex: exprIndex,
+		})
+	}
+
+	return nil
+}
+
+// trimTrailingSetDelimiter はディレクティブ直前の空白・コメント・カンマを取り除く
+func trimTrailingSetDelimiter(tokens []tokenizer.Token) []tokenizer.Token {
+	end := len(tokens)
+	for end > 0 {
+		switch tokens[end-1].Type {
+		case tokenizer.WHITESPACE, tokenizer.BLOCK_COMMENT, tokenizer.LINE_COMMENT, tokenizer.COMMA:
+			end--
+			continue
+		}
+
+		break
+	}
+
+	return tokens[:end]
+}
+
+// hasSetAssignment は SET キーワード以外のトークンがあるかを返す
+func hasSetAssignment(tokens []tokenizer.Token) bool {
+	for _, token := range tokens {
+		switch token.Type {
+		case tokenizer.SET, tokenizer.WHITESPACE, tokenizer.BLOCK_COMMENT, tokenizer.LINE_COMMENT:
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// splitSetAssignments はトップレベルのカンマで代入ごとに分割し、前後の空白とコメントを取り除く
+func splitSetAssignments(tokens []tokenizer.Token) [][]tokenizer.Token {
+	var (
+		groups  [][]tokenizer.Token
+		current []tokenizer.Token
+		depth   int
+	)
+
+	flush := func() {
+		if trimmed := trimSetTrivia(current); len(trimmed) > 0 {
+			groups = append(groups, trimmed)
+		}
+
+		current = nil
+	}
+
+	for _, token := range tokens {
+		switch token.Type {
+		case tokenizer.OPENED_PARENS:
+			depth++
+		case tokenizer.CLOSED_PARENS:
+			depth--
+		case tokenizer.COMMA:
+			if depth == 0 {
+				flush()
+				continue
+			}
+		}
+
+		current = append(current, token)
+	}
+
+	flush()
+
+	return groups
+}
+
+func trimSetTrivia(tokens []tokenizer.Token) []tokenizer.Token {
+	isTrivia := func(token tokenizer.Token) bool {
+		switch token.Type {
+		case tokenizer.WHITESPACE, tokenizer.LINE_COMMENT:
+			return true
+		case tokenizer.BLOCK_COMMENT:
+			return token.Directive == nil
+		}
+
+		return false
+	}
+
+	start, end := 0, len(tokens)
+	for start < end && isTrivia(tokens[start]) {
+		start++
+	}
+
+	for end > start && isTrivia(tokens[end-1]) {
+		end--
+	}
+
+	return tokens[start:end]
+}
+
+// parseOptionalSetAssignment は `column = /*= param */dummy` から列名と変数ディレクティブを取り出す
+func parseOptionalSetAssignment(tokens []tokenizer.Token) (string, tokenizer.Token, error) {
+	invalid := func() (string, tokenizer.Token, error) {
+		text := strings.Builder{}
+		for _, token := range tokens {
+			text.WriteString(token.Value)
+		}
+
+		return "", tokenizer.Token{}, fmt.Errorf("%w: assignment after set_optional at %s must be 'column = /*= param */value': %s",
+			ErrInvalidOptionalSet, tokens[0].Position.String(), strings.TrimSpace(text.String()))
+	}
+
+	equal := -1
+
+	for i, token := range tokens {
+		if token.Type == tokenizer.EQUAL {
+			equal = i
+			break
+		}
+	}
+
+	if equal <= 0 {
+		return invalid()
+	}
+
+	var column strings.Builder
+
+	for _, token := range tokens[:equal] {
+		if token.Type != tokenizer.WHITESPACE {
+			column.WriteString(token.Value)
+		}
+	}
+
+	var directive *tokenizer.Token
+
+	for i := equal + 1; i < len(tokens); i++ {
+		token := tokens[i]
+
+		switch {
+		case token.Type == tokenizer.WHITESPACE || token.Type == tokenizer.DUMMY_START || token.Type == tokenizer.DUMMY_END || token.Type == tokenizer.DUMMY_LITERAL:
+			continue
+		case token.Directive != nil && token.Directive.Type == "variable" && directive == nil:
+			directive = &tokens[i]
+		case directive != nil && isDummyValueToken(token):
+			// ダミー値は出力されない
+			continue
+		default:
+			return invalid()
+		}
+	}
+
+	if directive == nil || !isSimpleIdentifier(directive.Directive.Condition) {
+		return invalid()
+	}
+
+	return column.String(), *directive, nil
+}
+
+func isDummyValueToken(token tokenizer.Token) bool {
+	switch token.Type {
+	case tokenizer.STRING, tokenizer.NUMBER, tokenizer.BOOLEAN, tokenizer.NULL, tokenizer.MINUS:
+		return true
+	}
+
+	return false
+}
+
+func isSimpleIdentifier(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, r := range name {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9') {
+			continue
+		}
+
+		return false
+	}
+
+	return true
+}
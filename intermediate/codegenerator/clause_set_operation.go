@@ -0,0 +1,51 @@
+package codegenerator
+
+import (
+	"fmt"
+
+	"github.com/shibukawa/snapsql/parser"
+)
+
+// generateSetOperations は UNION / INTERSECT / EXCEPT で結合された SELECT を順に出力する
+//
+// 各 SELECT は先頭の SELECT と同じく論理削除フィルタの対象になる。
+// ORDER BY / LIMIT / OFFSET / FOR は結合結果全体に対するものなので呼び出し側で出力する。
+func generateSetOperations(operations []*parser.SetOperation, builder *InstructionBuilder) error {
+	for _, op := range operations {
+		if err := builder.ProcessTokens(op.Operator.RawTokens()); err != nil {
+			return fmt.Errorf("failed to process tokens in %s: %w", op.Operator.Operator, err)
+		}
+
+		if err := generateSelectClause(op.Select, builder, false); err != nil {
+			return fmt.Errorf("failed to generate SELECT clause of %s: %w", op.Operator.Operator, err)
+		}
+
+		if err := generateFromClause(op.From, builder); err != nil {
+			return fmt.Errorf("failed to generate FROM clause of %s: %w", op.Operator.Operator, err)
+		}
+
+		if column := selectSoftDeleteColumn(op.From, op.Where, builder.context); column != "" {
+			if _, err := generateSoftDeleteFilter(op.Where, builder, false, column); err != nil {
+				return fmt.Errorf("failed to generate WHERE clause of %s: %w", op.Operator.Operator, err)
+			}
+		} else if op.Where != nil {
+			if _, err := generateWhereClause(op.Where, builder, false); err != nil {
+				return fmt.Errorf("failed to generate WHERE clause of %s: %w", op.Operator.Operator, err)
+			}
+		}
+
+		if op.GroupBy != nil {
+			if err := generateGroupByClause(op.GroupBy, builder); err != nil {
+				return fmt.Errorf("failed to generate GROUP BY clause of %s: %w", op.Operator.Operator, err)
+			}
+		}
+
+		if op.Having != nil {
+			if err := generateHavingClause(op.Having, builder); err != nil {
+				return fmt.Errorf("failed to generate HAVING clause of %s: %w", op.Operator.Operator, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package codegenerator
+
+import (
+	"strings"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/parser"
+	"github.com/shibukawa/snapsql/tokenizer"
+)
+
+// lookupSoftDelete は snapsql.yaml の tables.<table>.soft_delete 設定を返す
+func lookupSoftDelete(ctx *GenerationContext, tableName string) (snapsql.SoftDeleteConfig, bool) {
+	if ctx == nil || ctx.Config == nil {
+		return snapsql.SoftDeleteConfig{}, false
+	}
+
+	return ctx.Config.GetSoftDelete(tableName)
+}
+
+// selectSoftDeleteColumn は SELECT 文で自動付与する論理削除カラム（エイリアス修飾済み）を返す
+//
+// 対象は FROM 句の先頭テーブルのみ。JOIN 先に付与すると LEFT JOIN の意味が変わるため対象外とする。
+// WHERE 句がカラムを参照している場合はテンプレート側で制御しているとみなして付与しない。
+// WHERE 句全体が if ディレクティブで囲まれている場合も付与する（generateSoftDeleteFilter を参照）。
+func selectSoftDeleteColumn(from *parser.FromClause, where *parser.WhereClause, ctx *GenerationContext) string {
+	if from == nil || len(from.Tables) == 0 {
+		return ""
+	}
+
+	ref := from.Tables[0]
+	if ref.TableName == "" {
+		return ""
+	}
+
+	tableName := ref.TableName
+	if ref.SchemaName != "" {
+		tableName = ref.SchemaName + "." + tableName
+	}
+
+	config, ok := lookupSoftDelete(ctx, tableName)
+	if !ok {
+		return ""
+	}
+
+	if where != nil && referencesColumn(where.RawTokens(), config.Column) {
+		return ""
+	}
+
+	return ref.Name + "." + config.Column
+}
+
+// referencesColumn は tokens が column を参照しているか判定する
+func referencesColumn(tokens []tokenizer.Token, column string) bool {
+	for _, token := range tokens {
+		if token.Type == tokenizer.IDENTIFIER && strings.EqualFold(token.Value, column) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// generateSoftDeleteFilter は論理削除フィルタを出力する
+//
+// WHERE 句がある場合は本体を括弧で囲んでから "AND <column> IS NULL" を追加し、
+// テンプレート内の OR 条件でフィルタが無効化されないようにする。
+// WHERE 句がない場合は "WHERE <column> IS NULL" を追加する。
+//
+// WHERE 句全体が if ディレクティブで囲まれている場合も "AND <column> IS NULL" でよい。
+// SELECT の WHERE 句は常に出力され、DELETE / UPDATE では条件が偽のときに
+// フォールバックの "WHERE 1 = 1" が出力されるため、どちらの分岐でも直前に WHERE がある。
+func generateSoftDeleteFilter(where *parser.WhereClause, builder *InstructionBuilder, critical bool, column string) (*WhereClauseMeta, error) {
+	if where == nil {
+		builder.RegisterEmitSystemSoftDelete("WHERE " + column + " IS NULL")
+
+		return nil, nil //nolint:nilnil // no WHERE clause metadata to report
+	}
+
+	meta, err := generateWhereClauseTokens(where, parenthesizeWhereBody(where.RawTokens()), builder, critical)
+	if err != nil {
+		return nil, err
+	}
+
+	builder.RegisterEmitSystemSoftDelete("AND " + column + " IS NULL")
+
+	return meta, nil
+}
+
+// parenthesizeWhereBody は WHERE キーワードに続く条件式を括弧で囲んだトークン列を返す
+//
+// 行コメントは括弧の範囲を広げない。末尾の "-- ..." の後ろに ")" を置くと
+// 閉じ括弧ごとコメントアウトされるため、行コメントは括弧の外に残す。
+func parenthesizeWhereBody(tokens []tokenizer.Token) []tokenizer.Token {
+	start := -1
+	end := -1
+	afterWhere := false
+
+	for i, token := range tokens {
+		switch {
+		case !afterWhere:
+			afterWhere = token.Type == tokenizer.WHERE
+		case token.Type == tokenizer.WHITESPACE, token.Type == tokenizer.LINE_COMMENT:
+		default:
+			if start < 0 {
+				start = i
+			}
+
+			end = i + 1
+		}
+	}
+
+	if start < 0 {
+		return tokens
+	}
+
+	result := make([]tokenizer.Token, 0, len(tokens)+2)
+	result = append(result, tokens[:start]...)
+	result = append(result, tokenizer.Token{Type: tokenizer.OPENED_PARENS, Value: "(", Position: tokens[start].Position})
+	result = append(result, tokens[start:end]...)
+	result = append(result, tokenizer.Token{Type: tokenizer.CLOSED_PARENS, Value: ")", Position: tokens[end-1].Position})
+	result = append(result, tokens[end:]...)
+
+	return result
+}
+
+// generateSoftDeleteFromClause は DELETE FROM 句を論理削除の UPDATE 文に書き換えて出力する
+//
+//	DELETE FROM users  →  UPDATE users SET deleted_at = NOW()
+func generateSoftDeleteFromClause(clause *parser.DeleteFromClause, builder *InstructionBuilder, skipLeadingTrivia bool, column string) {
+	tokens := clause.RawTokens()
+
+	var table strings.Builder
+
+	afterFrom := false
+
+	for _, token := range tokens {
+		if afterFrom {
+			table.WriteString(token.Value)
+		} else if token.Type == tokenizer.FROM {
+			afterFrom = true
+		}
+	}
+
+	prefix := ""
+	if !skipLeadingTrivia {
+		prefix = " "
+	}
+
+	now := normalizeDefaultExpressionForDialect("NOW()", builder.context.Dialect)
+
+	var pos *tokenizer.Position
+	if len(tokens) > 0 {
+		pos = &tokens[0].Position
+	}
+
+	builder.addStatic(prefix+"UPDATE "+strings.TrimSpace(table.String())+" SET "+column+" = "+now+" ", pos)
+}
+
+// deleteSoftDeleteConfig は DELETE 文の対象テーブルの論理削除設定を返す
+func deleteSoftDeleteConfig(clause *parser.DeleteFromClause, ctx *GenerationContext) (snapsql.SoftDeleteConfig, bool) {
+	if clause == nil {
+		return snapsql.SoftDeleteConfig{}, false
+	}
+
+	tableName := clause.Table.TableName
+	if tableName == "" {
+		tableName = clause.Table.Name
+	}
+
+	if clause.Table.SchemaName != "" {
+		tableName = clause.Table.SchemaName + "." + tableName
+	}
+
+	return lookupSoftDelete(ctx, tableName)
+}
@@ -0,0 +1,158 @@
+package codegenerator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/parser"
+	"github.com/shibukawa/snapsql/tokenizer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSoftDeleteInstructions(t *testing.T) {
+	config := &snapsql.Config{
+		Tables: map[string]snapsql.TablePerformance{
+			"users":  {SoftDelete: &snapsql.SoftDeleteConfig{Column: "deleted_at", RewriteDelete: true}},
+			"orders": {SoftDelete: &snapsql.SoftDeleteConfig{Column: "deleted_at"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		sql      string
+		dialect  snapsql.Dialect
+		expected string // soft delete filter is rendered as {...}
+	}{
+		{
+			name:     "select without where",
+			sql:      "SELECT id FROM users",
+			dialect:  snapsql.DialectPostgres,
+			expected: "SELECT id FROM users{WHERE users.deleted_at IS NULL}",
+		},
+		{
+			name:     "select with where is parenthesized",
+			sql:      "SELECT id FROM users u WHERE u.id = 1 OR u.id = 2 ORDER BY id",
+			dialect:  snapsql.DialectPostgres,
+			expected: "SELECT id FROM users u WHERE (u.id = 1 OR u.id = 2) {AND u.deleted_at IS NULL}ORDER BY id",
+		},
+		{
+			name:     "trailing line comment stays outside the parentheses",
+			sql:      "SELECT id FROM users WHERE id = 1 -- only one user\nORDER BY id",
+			dialect:  snapsql.DialectPostgres,
+			expected: "SELECT id FROM users WHERE (id = 1) {AND users.deleted_at IS NULL}ORDER BY id",
+		},
+		{
+			name:     "trailing line comment at the end of the statement",
+			sql:      "DELETE FROM users WHERE id = 1 -- remove one user",
+			dialect:  snapsql.DialectPostgres,
+			expected: "UPDATE users SET deleted_at = NOW() WHERE (id = 1) {AND deleted_at IS NULL}",
+		},
+		{
+			name:     "where referencing the column is left as is",
+			sql:      "SELECT id FROM users WHERE deleted_at IS NOT NULL",
+			dialect:  snapsql.DialectPostgres,
+			expected: "SELECT id FROM users WHERE deleted_at IS NOT NULL",
+		},
+		{
+			name:     "joined tables are not filtered",
+			sql:      "SELECT p.id FROM posts p JOIN users u ON u.id = p.user_id",
+			dialect:  snapsql.DialectPostgres,
+			expected: "SELECT p.id FROM posts p JOIN users u ON u.id = p.user_id",
+		},
+		{
+			name:     "every union branch is filtered",
+			sql:      "SELECT id FROM users UNION ALL SELECT id FROM orders WHERE total > 0 ORDER BY id",
+			dialect:  snapsql.DialectPostgres,
+			expected: "SELECT id FROM users {WHERE users.deleted_at IS NULL}UNION ALL SELECT id FROM orders WHERE (total > 0) {AND orders.deleted_at IS NULL}ORDER BY id",
+		},
+		{
+			name:     "delete is rewritten to update",
+			sql:      "DELETE FROM users WHERE id = 1",
+			dialect:  snapsql.DialectPostgres,
+			expected: "UPDATE users SET deleted_at = NOW() WHERE (id = 1){AND deleted_at IS NULL}",
+		},
+		{
+			name:     "rewritten delete uses dialect timestamp",
+			sql:      "DELETE FROM users WHERE id = 1",
+			dialect:  snapsql.DialectSQLite,
+			expected: "UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE (id = 1){AND deleted_at IS NULL}",
+		},
+		{
+			name:     "select with conditional where",
+			sql:      "/*# parameters: { has_name: bool, name: string } */ SELECT id FROM users /*# if has_name */ WHERE name = /*= name */'x' /*# end */ ORDER BY id",
+			dialect:  snapsql.DialectPostgres,
+			expected: "SELECT id FROM users WHERE (name = ?) {AND users.deleted_at IS NULL}ORDER BY id",
+		},
+		{
+			name:     "rewritten delete with conditional where",
+			sql:      "/*# parameters: { has_name: bool, name: string } */ DELETE FROM users /*# if has_name */ WHERE name = /*= name */'x' /*# end */",
+			dialect:  snapsql.DialectPostgres,
+			expected: "UPDATE users SET deleted_at = NOW() [if]WHERE (name = ?) [else]WHERE 1 = 1[end]{AND deleted_at IS NULL}",
+		},
+		{
+			name:     "delete without rewrite_delete",
+			sql:      "DELETE FROM orders WHERE id = 1",
+			dialect:  snapsql.DialectPostgres,
+			expected: "DELETE FROM orders WHERE id = 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, _, _, err := parser.ParseSQLFile(strings.NewReader(tt.sql), nil, "", "", parser.Options{})
+			require.NoError(t, err)
+
+			ctx := NewGenerationContext(tt.dialect)
+			ctx.Config = config
+
+			var instructions []Instruction
+
+			switch stmt.(type) {
+			case *parser.DeleteFromStatement:
+				instructions, _, _, err = GenerateDeleteInstructions(stmt, ctx)
+			default:
+				instructions, _, _, err = GenerateSelectInstructions(stmt, ctx)
+			}
+
+			require.NoError(t, err)
+
+			var sql strings.Builder
+
+		render:
+			for _, inst := range instructions {
+				switch inst.Op {
+				case OpEmitStatic, OpFallbackCondition:
+					sql.WriteString(inst.Value)
+				case OpEmitEval:
+					sql.WriteString("?")
+				case OpIf, OpElse, OpEnd:
+					sql.WriteString("[" + strings.ToLower(inst.Op) + "]")
+				case OpEmitSystemSoftDelete:
+					sql.WriteString("{" + inst.Value + "}")
+				case OpIfSystemLimit:
+					// system LIMIT / OFFSET / FOR clauses follow; they are not part of the assertion
+					break render
+				}
+			}
+
+			assert.Equal(t, tt.expected, strings.TrimSpace(sql.String()))
+		})
+	}
+}
+
+func TestParenthesizeWhereBodyKeepsLineCommentOutside(t *testing.T) {
+	tokens, err := tokenizer.Tokenize("WHERE a = 1 -- note\n  OR b = 2 -- trailing\n")
+	require.NoError(t, err)
+
+	// Clause tokens never include EOF
+	tokens = tokens[:len(tokens)-1]
+
+	var sql strings.Builder
+	for _, token := range parenthesizeWhereBody(tokens) {
+		sql.WriteString(token.Value)
+	}
+
+	assert.Equal(t, "WHERE (a = 1 -- note\n  OR b = 2) -- trailing\n", sql.String())
+}
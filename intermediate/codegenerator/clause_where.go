@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/shibukawa/snapsql/parser"
+	"github.com/shibukawa/snapsql/tokenizer"
 )
 
 // generateWhereClause は WHERE 句から命令列を生成し、メタデータを返す
@@ -13,7 +14,11 @@ func generateWhereClause(clause *parser.WhereClause, builder *InstructionBuilder
 		return nil, fmt.Errorf("%w: WHERE clause is nil", ErrClauseNil)
 	}
 
-	tokens := clause.RawTokens()
+	return generateWhereClauseTokens(clause, clause.RawTokens(), builder, critical)
+}
+
+// generateWhereClauseTokens は WHERE 句のトークン列（加工済みの場合あり）から命令列を生成する
+func generateWhereClauseTokens(clause *parser.WhereClause, tokens []tokenizer.Token, builder *InstructionBuilder, critical bool) (*WhereClauseMeta, error) {
 	meta := newWhereClauseMeta(clause.SourceText())
 
 	var (
@@ -25,7 +30,7 @@ func generateWhereClause(clause *parser.WhereClause, builder *InstructionBuilder
 		envIndex := builder.getCurrentEnvironmentIndex()
 		clauseExprIndex = builder.context.AddExpression(cond, envIndex)
 
-		if tokens := clause.RawTokens(); len(tokens) > 0 {
+		if len(tokens) > 0 {
 			start := tokens[0].Position
 			builder.context.SetExpressionMetadata(clauseExprIndex, Position{Line: start.Line, Column: start.Column}, nil)
 		}
@@ -38,6 +38,15 @@ type GenerationContext struct {
 
 	// whereMeta captures metadata about the top-level WHERE clause when applicable.
 	whereMeta *WhereClauseMeta
+
+	// optionalSet lists the SET assignments made optional by the set_optional directive.
+	optionalSet []OptionalSetColumn
+
+	// cursorPagination describes the keyset pagination generated for `pagination: cursor`.
+	cursorPagination *CursorPagination
+
+	// limitGuard describes the LIMIT guard rails applied from the limits section of snapsql.yaml.
+	limitGuard *LimitGuard
 }
 
 // NewGenerationContext creates a new GenerationContext with the root environment initialized.
@@ -47,7 +56,7 @@ func NewGenerationContext(dialect snapsql.Dialect) *GenerationContext {
 		Expressions:        make([]CELExpression, 0),
 		CELEnvironments:    make([]CELEnvironment, 0),
 		Environments:       make([]string, 0),
-		Dialect:            dialect,
+		Dialect:            snapsql.GenerationDialect(dialect),
 		TableInfo:          nil,
 		Statement:          nil,
 		Config:             nil,
@@ -183,6 +192,25 @@ func (ctx *GenerationContext) WhereClauseMeta() *WhereClauseMeta {
 	return ctx.whereMeta.clone()
 }
 
+func (ctx *GenerationContext) addOptionalSetColumn(column OptionalSetColumn) {
+	ctx.optionalSet = append(ctx.optionalSet, column)
+}
+
+// OptionalSetColumns returns the SET assignments made optional by the set_optional directive.
+func (ctx *GenerationContext) OptionalSetColumns() []OptionalSetColumn {
+	return append([]OptionalSetColumn(nil), ctx.optionalSet...)
+}
+
+// CursorPagination returns the keyset pagination generated for `pagination: cursor`, or nil.
+func (ctx *GenerationContext) CursorPagination() *CursorPagination {
+	return ctx.cursorPagination
+}
+
+// LimitGuard returns the LIMIT guard rails applied to the SELECT, or nil when none are configured.
+func (ctx *GenerationContext) LimitGuard() *LimitGuard {
+	return ctx.limitGuard
+}
+
 // SetConfig sets the SnapSQL configuration for this generation context.
 // This is used to access system field definitions and other settings.
 func (ctx *GenerationContext) SetConfig(config *snapsql.Config) {
@@ -0,0 +1,122 @@
+package codegenerator
+
+import (
+	"strings"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/tokenizer"
+)
+
+// clickhouseCastFunctions は CAST(expr AS type) を置き換える ClickHouse の変換関数
+var clickhouseCastFunctions = map[string]string{
+	"TIMESTAMP":   "toDateTime",
+	"TIMESTAMPTZ": "toDateTime",
+	"DATETIME":    "toDateTime",
+	"DATE":        "toDate",
+}
+
+// shouldConvertClickHouseDateTime は ClickHouse 向けの日時変換が必要かを判定
+func (b *InstructionBuilder) shouldConvertClickHouseDateTime(token tokenizer.Token) bool {
+	if b.context.Dialect != snapsql.DialectClickHouse {
+		return false
+	}
+
+	switch strings.ToUpper(strings.TrimSpace(token.Value)) {
+	case "CURRENT_TIMESTAMP", "CURRENT_DATE", "CURDATE", "CAST":
+		return true
+	}
+
+	return false
+}
+
+// convertClickHouseDateTimeInTokens は ClickHouse の日時関数に変換する
+//   - CURRENT_TIMESTAMP → now()
+//   - CURRENT_DATE / CURDATE() → today()
+//   - CAST(expr AS TIMESTAMP) → toDateTime(expr), CAST(expr AS DATE) → toDate(expr)
+//
+// 返り値: 変換後のトークン列, スキップするトークン数
+func (b *InstructionBuilder) convertClickHouseDateTimeInTokens(tokens []tokenizer.Token, startIndex int) ([]tokenizer.Token, int) {
+	token := tokens[startIndex]
+
+	switch strings.ToUpper(strings.TrimSpace(token.Value)) {
+	case "CURRENT_TIMESTAMP":
+		return clickhouseCall(token, "now"), 0
+	case "CURRENT_DATE":
+		return clickhouseCall(token, "today"), 0
+	case "CURDATE":
+		if startIndex+2 < len(tokens) && tokens[startIndex+1].Type == tokenizer.OPENED_PARENS && tokens[startIndex+2].Type == tokenizer.CLOSED_PARENS {
+			return clickhouseCall(token, "today"), 2
+		}
+	case "CAST":
+		return convertCastToClickHouse(tokens, startIndex)
+	}
+
+	return nil, 0
+}
+
+// clickhouseCall は引数なしの関数呼び出し name() のトークン列を返す
+func clickhouseCall(token tokenizer.Token, name string) []tokenizer.Token {
+	return []tokenizer.Token{
+		{Type: token.Type, Value: name, Position: token.Position},
+		{Type: tokenizer.OPENED_PARENS, Value: "(", Position: token.Position},
+		{Type: tokenizer.CLOSED_PARENS, Value: ")", Position: token.Position},
+	}
+}
+
+// convertCastToClickHouse は日時型への CAST を toDateTime() / toDate() に変換する。
+// それ以外の型への CAST は ClickHouse でもそのまま使えるので変換しない。
+func convertCastToClickHouse(tokens []tokenizer.Token, castIndex int) ([]tokenizer.Token, int) {
+	open := castIndex + 1
+	for open < len(tokens) && tokens[open].Type == tokenizer.WHITESPACE {
+		open++
+	}
+
+	if open >= len(tokens) || tokens[open].Type != tokenizer.OPENED_PARENS {
+		return nil, 0
+	}
+
+	// CAST( の閉じ括弧と、同じ深さの AS を探す
+	asIndex := -1
+	depth := 0
+
+	for i := open; i < len(tokens); i++ {
+		switch {
+		case tokens[i].Type == tokenizer.OPENED_PARENS:
+			depth++
+		case tokens[i].Type == tokenizer.CLOSED_PARENS:
+			depth--
+			if depth > 0 {
+				continue
+			}
+
+			if asIndex < 0 {
+				return nil, 0
+			}
+
+			var typeName strings.Builder
+
+			for _, t := range tokens[asIndex+1 : i] {
+				typeName.WriteString(strings.TrimSpace(t.Value))
+			}
+
+			function, ok := clickhouseCastFunctions[strings.ToUpper(typeName.String())]
+			if !ok {
+				return nil, 0
+			}
+
+			expr := trimWhitespaceTokens(tokens[open+1 : asIndex])
+
+			result := make([]tokenizer.Token, 0, len(expr)+3)
+			result = append(result, tokenizer.Token{Type: tokens[castIndex].Type, Value: function, Position: tokens[castIndex].Position})
+			result = append(result, tokens[open])
+			result = append(result, expr...)
+			result = append(result, tokens[i])
+
+			return result, i - castIndex
+		case depth == 1 && strings.EqualFold(strings.TrimSpace(tokens[i].Value), "AS"):
+			asIndex = i
+		}
+	}
+
+	return nil, 0
+}
@@ -0,0 +1,98 @@
+package codegenerator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/parser"
+	"github.com/shibukawa/snapsql/tokenizer"
+)
+
+// validateFullTextSearchDialect rejects /*# fts() */ on dialects without full-text search
+func (b *InstructionBuilder) validateFullTextSearchDialect(tokens []tokenizer.Token) error {
+	switch b.context.Dialect {
+	case snapsql.DialectPostgres, snapsql.DialectMySQL, snapsql.DialectMariaDB, snapsql.DialectSQLite:
+		return nil
+	}
+
+	for _, token := range tokens {
+		if isFullTextSearchToken(token) {
+			return fmt.Errorf("%w: %s at %s", ErrFullTextSearchNotSupported, b.context.Dialect, token.Position.String())
+		}
+	}
+
+	return nil
+}
+
+func isFullTextSearchToken(token tokenizer.Token) bool {
+	return token.Type == tokenizer.IDENTIFIER && strings.EqualFold(token.Value, parser.FullTextSearchFunction)
+}
+
+// shouldConvertFullTextSearch は /*# fts() */ の展開が必要かを判定
+func (b *InstructionBuilder) shouldConvertFullTextSearch(token tokenizer.Token) bool {
+	return isFullTextSearchToken(token)
+}
+
+// convertFullTextSearchInTokens は次の形の呼び出しを方言の全文検索に変換する
+//
+//	SNAPSQL_FTS(column, /*= query */'')
+//
+// 方言ごとの変換結果:
+//   - PostgreSQL: to_tsvector(column) @@ plainto_tsquery(query)
+//   - MySQL/MariaDB: MATCH (column) AGAINST (query IN NATURAL LANGUAGE MODE)
+//   - SQLite (FTS5): column MATCH query
+//
+// 返り値: 変換後のトークン列, スキップするトークン数
+func (b *InstructionBuilder) convertFullTextSearchInTokens(tokens []tokenizer.Token, startIndex int) ([]tokenizer.Token, int) {
+	open := skipWhitespaceTokens(tokens, startIndex+1)
+	if open >= len(tokens) || tokens[open].Type != tokenizer.OPENED_PARENS {
+		return nil, 0
+	}
+
+	closeIndex, ok := findMatchingParen(tokens, open)
+	if !ok {
+		return nil, 0
+	}
+
+	args := splitTokensByTopLevelComma(tokens[open+1 : closeIndex])
+	if len(args) != 2 {
+		return nil, 0
+	}
+
+	column := trimWhitespaceTokens(args[0])
+	query := trimWhitespaceTokens(args[1])
+
+	pos := tokens[startIndex].Position
+	word := func(value string) tokenizer.Token {
+		return tokenizer.Token{Type: tokenizer.IDENTIFIER, Value: value, Position: pos}
+	}
+	space := tokenizer.Token{Type: tokenizer.WHITESPACE, Value: " ", Position: pos}
+	openParen := tokenizer.Token{Type: tokenizer.OPENED_PARENS, Value: "(", Position: pos}
+	closeParen := tokenizer.Token{Type: tokenizer.CLOSED_PARENS, Value: ")", Position: pos}
+
+	var converted []tokenizer.Token
+
+	switch b.context.Dialect {
+	case snapsql.DialectPostgres:
+		converted = append(converted, word("to_tsvector"), openParen)
+		converted = append(converted, column...)
+		converted = append(converted, closeParen, space, tokenizer.Token{Type: tokenizer.OTHER, Value: "@@", Position: pos}, space, word("plainto_tsquery"), openParen)
+		converted = append(converted, query...)
+		converted = append(converted, closeParen)
+	case snapsql.DialectMySQL, snapsql.DialectMariaDB:
+		converted = append(converted, word("MATCH"), space, openParen)
+		converted = append(converted, column...)
+		converted = append(converted, closeParen, space, word("AGAINST"), space, openParen)
+		converted = append(converted, query...)
+		converted = append(converted, space, word("IN"), space, word("NATURAL"), space, word("LANGUAGE"), space, word("MODE"), closeParen)
+	case snapsql.DialectSQLite:
+		converted = append(converted, column...)
+		converted = append(converted, space, word("MATCH"), space)
+		converted = append(converted, query...)
+	default:
+		return nil, 0
+	}
+
+	return converted, closeIndex - startIndex
+}
@@ -0,0 +1,371 @@
+package codegenerator
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/tokenizer"
+)
+
+// jsonPathKeyPattern は JSON パスで引用符なしに書けるキー
+var jsonPathKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// jsonPathSegmentPattern は JSON パスの1要素 (.key / ."key" / [n]) にマッチする
+var jsonPathSegmentPattern = regexp.MustCompile(`^(?:\.([A-Za-z_][A-Za-z0-9_]*)|\."((?:[^"\\]|\\.)*)"|\[([0-9]+)\])`)
+
+// jsonPathSegment は JSON パスの1要素。index が 0 以上なら配列の添字
+type jsonPathSegment struct {
+	key   string
+	index int
+}
+
+// shouldConvertJSONAccess は JSON アクセスの変換が必要かを判定
+//   - MySQL/MariaDB: PostgreSQL 形式の ->, ->> 演算子
+//   - PostgreSQL: JSON_EXTRACT() / JSON_UNQUOTE(JSON_EXTRACT())
+func (b *InstructionBuilder) shouldConvertJSONAccess(token tokenizer.Token) bool {
+	switch b.context.Dialect {
+	case snapsql.DialectMySQL, snapsql.DialectMariaDB:
+		return token.Type == tokenizer.JSON_OPERATOR && (token.Value == "->" || token.Value == "->>")
+	case snapsql.DialectPostgres:
+		switch strings.ToUpper(strings.TrimSpace(token.Value)) {
+		case "JSON_EXTRACT", "JSON_UNQUOTE":
+			return true
+		}
+	}
+
+	return false
+}
+
+// convertJSONAccessInTokens は JSON アクセスの書き方を方言に合わせて変換する
+//   - MySQL/MariaDB: data -> 'a' ->> 'b' → JSON_UNQUOTE(JSON_EXTRACT(data, '$.a.b'))
+//   - PostgreSQL: JSON_UNQUOTE(JSON_EXTRACT(data, '$.a.b')) → data->'a'->>'b'
+//
+// キーやパスがリテラルでない場合 (ディレクティブを含む場合など) は変換しない。
+// 返り値: 変換後のトークン列, スキップするトークン数, 変換済みの結果から取り除く左側のトークン数
+func (b *InstructionBuilder) convertJSONAccessInTokens(tokens []tokenizer.Token, startIndex int) ([]tokenizer.Token, int, int) {
+	if tokens[startIndex].Type == tokenizer.JSON_OPERATOR {
+		return convertJSONOperatorToMySQL(tokens, startIndex)
+	}
+
+	converted, skip := convertJSONExtractToPostgres(tokens, startIndex)
+
+	return converted, skip, 0
+}
+
+// convertJSONOperatorToMySQL は ->, ->> の連鎖を JSON_EXTRACT() に変換する。
+// MySQL ネイティブの data->>'$.name' はそのまま使えるので変換しない。
+func convertJSONOperatorToMySQL(tokens []tokenizer.Token, operatorIndex int) ([]tokenizer.Token, int, int) {
+	exprStart := max(findCastExpressionStart(tokens, operatorIndex), 0)
+
+	// a #> '{x}' -> 'y' のように別の JSON 演算子の右側から始まる場合は対象外
+	if prev := exprStart - 1; prev >= 0 {
+		for prev >= 0 && tokens[prev].Type == tokenizer.WHITESPACE {
+			prev--
+		}
+
+		if prev >= 0 && tokens[prev].Type == tokenizer.JSON_OPERATOR {
+			return nil, 0, 0
+		}
+	}
+
+	exprTokens := trimWhitespaceTokens(tokens[exprStart:operatorIndex])
+	if len(exprTokens) == 0 || hasDirectiveToken(exprTokens) {
+		return nil, 0, 0
+	}
+
+	var (
+		segments []jsonPathSegment
+		lastOp   string
+		end      int
+	)
+
+	for i := operatorIndex; ; {
+		keyIndex := skipWhitespaceTokens(tokens, i+1)
+		if keyIndex >= len(tokens) {
+			return nil, 0, 0
+		}
+
+		segment, ok := jsonOperatorKey(tokens[keyIndex])
+		if !ok {
+			return nil, 0, 0
+		}
+
+		segments = append(segments, segment)
+		lastOp = tokens[i].Value
+		end = keyIndex
+
+		// ->> の結果はテキストなので連鎖はそこで終わる
+		next := skipWhitespaceTokens(tokens, keyIndex+1)
+		if lastOp == "->>" || next >= len(tokens) || tokens[next].Type != tokenizer.JSON_OPERATOR {
+			break
+		}
+
+		if tokens[next].Value != "->" && tokens[next].Value != "->>" {
+			return nil, 0, 0
+		}
+
+		i = next
+	}
+
+	pos := tokens[operatorIndex].Position
+	extract := make([]tokenizer.Token, 0, len(exprTokens)+6)
+	extract = append(extract, tokenizer.Token{Type: tokenizer.IDENTIFIER, Value: "JSON_EXTRACT", Position: pos})
+	extract = append(extract, tokenizer.Token{Type: tokenizer.OPENED_PARENS, Value: "(", Position: pos})
+	extract = append(extract, exprTokens...)
+	extract = append(extract, tokenizer.Token{Type: tokenizer.COMMA, Value: ",", Position: pos})
+	extract = append(extract, tokenizer.Token{Type: tokenizer.WHITESPACE, Value: " ", Position: pos})
+	extract = append(extract, tokenizer.Token{Type: tokenizer.STRING, Value: quoteSQLString(formatJSONPath(segments)), Position: pos})
+	extract = append(extract, tokenizer.Token{Type: tokenizer.CLOSED_PARENS, Value: ")", Position: pos})
+
+	converted := extract
+	if lastOp == "->>" {
+		converted = make([]tokenizer.Token, 0, len(extract)+3)
+		converted = append(converted, tokenizer.Token{Type: tokenizer.IDENTIFIER, Value: "JSON_UNQUOTE", Position: pos})
+		converted = append(converted, tokenizer.Token{Type: tokenizer.OPENED_PARENS, Value: "(", Position: pos})
+		converted = append(converted, extract...)
+		converted = append(converted, tokenizer.Token{Type: tokenizer.CLOSED_PARENS, Value: ")", Position: pos})
+	}
+
+	return converted, end - operatorIndex, operatorIndex - exprStart
+}
+
+// jsonOperatorKey は -> / ->> の右辺のリテラルをパスの要素に変換する
+func jsonOperatorKey(token tokenizer.Token) (jsonPathSegment, bool) {
+	if token.Directive != nil {
+		return jsonPathSegment{}, false
+	}
+
+	switch token.Type {
+	case tokenizer.NUMBER:
+		index, err := strconv.Atoi(token.Value)
+		if err != nil || index < 0 {
+			return jsonPathSegment{}, false
+		}
+
+		return jsonPathSegment{index: index}, true
+	case tokenizer.STRING:
+		key, ok := unquoteSQLString(token.Value)
+		if !ok || strings.HasPrefix(key, "$") {
+			return jsonPathSegment{}, false
+		}
+
+		return jsonPathSegment{key: key, index: -1}, true
+	}
+
+	return jsonPathSegment{}, false
+}
+
+// convertJSONExtractToPostgres は JSON_EXTRACT(expr, '$.a.b') を expr->'a'->'b' に、
+// JSON_UNQUOTE(JSON_EXTRACT(expr, '$.a.b')) を expr->'a'->>'b' に変換する
+func convertJSONExtractToPostgres(tokens []tokenizer.Token, startIndex int) ([]tokenizer.Token, int) {
+	unquote := false
+	extractIndex := startIndex
+
+	var outerClose int
+
+	if strings.EqualFold(strings.TrimSpace(tokens[startIndex].Value), "JSON_UNQUOTE") {
+		open := skipWhitespaceTokens(tokens, startIndex+1)
+		if open >= len(tokens) || tokens[open].Type != tokenizer.OPENED_PARENS {
+			return nil, 0
+		}
+
+		closeIndex, ok := findMatchingParen(tokens, open)
+		if !ok {
+			return nil, 0
+		}
+
+		extractIndex = skipWhitespaceTokens(tokens, open+1)
+		if extractIndex >= len(tokens) || !strings.EqualFold(strings.TrimSpace(tokens[extractIndex].Value), "JSON_EXTRACT") {
+			return nil, 0
+		}
+
+		unquote = true
+		outerClose = closeIndex
+	}
+
+	open := skipWhitespaceTokens(tokens, extractIndex+1)
+	if open >= len(tokens) || tokens[open].Type != tokenizer.OPENED_PARENS {
+		return nil, 0
+	}
+
+	closeIndex, ok := findMatchingParen(tokens, open)
+	if !ok {
+		return nil, 0
+	}
+
+	if unquote && skipWhitespaceTokens(tokens, closeIndex+1) != outerClose {
+		return nil, 0
+	}
+
+	args := splitTokensByTopLevelComma(tokens[open+1 : closeIndex])
+	if len(args) != 2 {
+		return nil, 0
+	}
+
+	exprTokens := trimWhitespaceTokens(args[0])
+
+	pathTokens := trimWhitespaceTokens(args[1])
+	if len(exprTokens) == 0 || hasDirectiveToken(exprTokens) ||
+		len(pathTokens) != 1 || pathTokens[0].Type != tokenizer.STRING || pathTokens[0].Directive != nil {
+		return nil, 0
+	}
+
+	path, ok := unquoteSQLString(pathTokens[0].Value)
+	if !ok {
+		return nil, 0
+	}
+
+	segments, ok := parseJSONPath(path)
+	if !ok || len(segments) == 0 {
+		return nil, 0
+	}
+
+	pos := tokens[startIndex].Position
+	converted := make([]tokenizer.Token, 0, len(exprTokens)+len(segments)*2+2)
+
+	if isSimpleJSONTarget(exprTokens) {
+		converted = append(converted, exprTokens...)
+	} else {
+		converted = append(converted, tokenizer.Token{Type: tokenizer.OPENED_PARENS, Value: "(", Position: pos})
+		converted = append(converted, stripOuterParentheses(exprTokens)...)
+		converted = append(converted, tokenizer.Token{Type: tokenizer.CLOSED_PARENS, Value: ")", Position: pos})
+	}
+
+	for i, segment := range segments {
+		op := "->"
+		if unquote && i == len(segments)-1 {
+			op = "->>"
+		}
+
+		converted = append(converted, tokenizer.Token{Type: tokenizer.JSON_OPERATOR, Value: op, Position: pos})
+		if segment.index >= 0 {
+			converted = append(converted, tokenizer.Token{Type: tokenizer.NUMBER, Value: strconv.Itoa(segment.index), Position: pos})
+		} else {
+			converted = append(converted, tokenizer.Token{Type: tokenizer.STRING, Value: quoteSQLString(segment.key), Position: pos})
+		}
+	}
+
+	end := closeIndex
+	if unquote {
+		end = outerClose
+	}
+
+	return converted, end - startIndex
+}
+
+// parseJSONPath は '$.a[0]."b c"' 形式の単純な JSON パスを分解する。
+// ワイルドカードなどを含むパスは PostgreSQL の演算子で表せないので false を返す
+func parseJSONPath(path string) ([]jsonPathSegment, bool) {
+	rest, ok := strings.CutPrefix(path, "$")
+	if !ok {
+		return nil, false
+	}
+
+	var segments []jsonPathSegment
+
+	for rest != "" {
+		match := jsonPathSegmentPattern.FindStringSubmatch(rest)
+		if match == nil {
+			return nil, false
+		}
+
+		switch {
+		case match[1] != "":
+			segments = append(segments, jsonPathSegment{key: match[1], index: -1})
+		case match[3] != "":
+			index, err := strconv.Atoi(match[3])
+			if err != nil {
+				return nil, false
+			}
+
+			segments = append(segments, jsonPathSegment{index: index})
+		default:
+			key, err := strconv.Unquote(`"` + match[2] + `"`)
+			if err != nil {
+				return nil, false
+			}
+
+			segments = append(segments, jsonPathSegment{key: key, index: -1})
+		}
+
+		rest = rest[len(match[0]):]
+	}
+
+	return segments, true
+}
+
+// formatJSONPath はパスの要素を MySQL の JSON パス ('$.a[0]."b c"') に組み立てる
+func formatJSONPath(segments []jsonPathSegment) string {
+	var path strings.Builder
+
+	path.WriteString("$")
+
+	for _, segment := range segments {
+		switch {
+		case segment.index >= 0:
+			path.WriteString("[" + strconv.Itoa(segment.index) + "]")
+		case jsonPathKeyPattern.MatchString(segment.key):
+			path.WriteString("." + segment.key)
+		default:
+			path.WriteString("." + strconv.Quote(segment.key))
+		}
+	}
+
+	return path.String()
+}
+
+// isSimpleJSONTarget は括弧で囲まずに JSON 演算子の左辺に置ける式かを判定 (col, t.col, 関数呼び出し)
+func isSimpleJSONTarget(tokens []tokenizer.Token) bool {
+	if len(tokens) == 1 {
+		return true
+	}
+
+	for i, t := range tokens {
+		switch {
+		case t.Type == tokenizer.IDENTIFIER && i%2 == 0:
+		case t.Type == tokenizer.DOT && i%2 == 1:
+		default:
+			if t.Type == tokenizer.OPENED_PARENS && i > 0 {
+				closeIndex, ok := findMatchingParen(tokens, i)
+				return ok && closeIndex == len(tokens)-1
+			}
+
+			return false
+		}
+	}
+
+	return true
+}
+
+func hasDirectiveToken(tokens []tokenizer.Token) bool {
+	for _, t := range tokens {
+		if t.Directive != nil || t.Type == tokenizer.DUMMY_LITERAL {
+			return true
+		}
+	}
+
+	return false
+}
+
+func skipWhitespaceTokens(tokens []tokenizer.Token, index int) int {
+	for index < len(tokens) && tokens[index].Type == tokenizer.WHITESPACE {
+		index++
+	}
+
+	return index
+}
+
+// unquoteSQLString は 'abc' 形式の SQL 文字列リテラルの中身を返す
+func unquoteSQLString(value string) (string, bool) {
+	if len(value) < 2 || value[0] != '\'' || value[len(value)-1] != '\'' {
+		return "", false
+	}
+
+	return strings.ReplaceAll(value[1:len(value)-1], "''", "'"), true
+}
+
+func quoteSQLString(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
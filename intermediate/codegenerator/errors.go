@@ -28,3 +28,21 @@ var ErrMissingClause = errors.New("missing required clause")
 
 // ErrConflictingClauses is returned when conflicting clauses exist together.
 var ErrConflictingClauses = errors.New("conflicting clauses")
+
+// ErrInvalidOptionalSet is returned when an assignment after the set_optional directive is not a single parameter.
+var ErrInvalidOptionalSet = errors.New("invalid set_optional assignment")
+
+// ErrInvalidCursorPagination is returned when a query cannot use cursor pagination.
+var ErrInvalidCursorPagination = errors.New("invalid cursor pagination")
+
+// ErrLimitExceeded is returned when a SELECT's LIMIT cannot satisfy the limits configured in snapsql.yaml.
+var ErrLimitExceeded = errors.New("LIMIT exceeds configured limit")
+
+// ErrUpsertNotSupported is returned when the dialect doesn't support the upsert syntax (ON CONFLICT, ON DUPLICATE KEY UPDATE or MERGE).
+var ErrUpsertNotSupported = errors.New("upsert syntax is not supported by the dialect")
+
+// ErrLateralNotSupported is returned when the dialect doesn't support LATERAL subqueries.
+var ErrLateralNotSupported = errors.New("LATERAL is not supported by the dialect")
+
+// ErrFullTextSearchNotSupported is returned when the dialect has no full-text search for /*# fts() */.
+var ErrFullTextSearchNotSupported = errors.New("full-text search is not supported by the dialect")
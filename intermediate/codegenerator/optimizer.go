@@ -20,6 +20,9 @@ type OptimizedInstruction struct {
 	SystemField         string
 	Critical            bool
 	FallbackCombos      [][]RemovalLiteral
+	Allowed             []string
+	// Pos is the template position ("line:column") of the instruction this one was generated from
+	Pos string
 }
 
 // OptimizeInstructions filters and optimizes instructions for a specific dialect.
@@ -37,6 +40,8 @@ func OptimizeInstructions(instructions []Instruction, dialect snapsql.Dialect) (
 	}
 
 	for i, inst := range instructions {
+		start := len(result)
+
 		if isSkippingSystem() {
 			switch inst.Op {
 			case OpIfSystemLimit, OpIfSystemOffset:
@@ -61,6 +66,10 @@ func OptimizeInstructions(instructions []Instruction, dialect snapsql.Dialect) (
 			result = append(result, OptimizedInstruction{Op: "EMIT_STATIC", Value: "?"})
 			result = append(result, OptimizedInstruction{Op: "ADD_PARAM", ExprIndex: inst.ExprIndex})
 
+		case OpEmitIdent, OpEmitOrderBy:
+			// 識別子はプレースホルダにできないため、生成コードが許可リストで検査してから埋め込む
+			result = append(result, OptimizedInstruction{Op: inst.Op, ExprIndex: inst.ExprIndex, Allowed: inst.Allowed})
+
 		case OpEmitUnlessBoundary:
 			isStaticContext := true
 			boundaryFound := false
@@ -126,12 +135,39 @@ func OptimizeInstructions(instructions []Instruction, dialect snapsql.Dialect) (
 			result = append(result, OptimizedInstruction{Op: "LOOP_END", EnvIndex: inst.EnvIndex})
 
 		case OpIfSystemLimit, OpIfSystemOffset:
-			systemStack = append(systemStack, systemClauseState{skipping: true})
+			// limits の上限を持つ IF_SYSTEM_LIMIT は then 側を出力する
+			systemStack = append(systemStack, systemClauseState{skipping: inst.Op != OpIfSystemLimit || inst.DefaultValue == ""})
 			continue
 
-		case OpEmitSystemLimit, OpEmitSystemOffset:
+		case OpEmitSystemLimit:
+			switch {
+			case inst.DefaultValue == "":
+				// ignored for static SQL
+			case inst.ExprIndex != nil:
+				// 生成コードが LIMIT パラメータを上限（Value）に丸めて束縛する
+				result = append(result, OptimizedInstruction{Op: "EMIT_STATIC", Value: "?"})
+				result = append(result, OptimizedInstruction{Op: OpEmitSystemLimit, ExprIndex: inst.ExprIndex, Value: inst.DefaultValue})
+			default:
+				result = append(result, OptimizedInstruction{Op: "EMIT_STATIC", Value: inst.DefaultValue})
+			}
+
+		case OpEmitSystemOffset:
 			// ignored for static SQL
 
+		case OpEmitSystemSoftDelete:
+			result = append(result, OptimizedInstruction{Op: OpEmitSystemSoftDelete, Value: inst.Value})
+
+		case OpEmitCursorCondition:
+			result = append(result, OptimizedInstruction{Op: OpEmitCursorCondition, Value: inst.Value})
+
+		case OpEmitCursorLimit:
+			// 生成コードがページサイズ + 1 を束縛する
+			if inst.ExprIndex != nil {
+				result = append(result, OptimizedInstruction{Op: "EMIT_STATIC", Value: "?"})
+			}
+
+			result = append(result, OptimizedInstruction{Op: OpEmitCursorLimit, Value: inst.Value, ExprIndex: inst.ExprIndex})
+
 		case OpEmitSystemValue:
 			result = append(result, OptimizedInstruction{Op: "EMIT_STATIC", Value: "?"})
 			result = append(result, OptimizedInstruction{Op: "ADD_SYSTEM_PARAM", SystemField: inst.SystemField})
@@ -152,6 +188,10 @@ func OptimizeInstructions(instructions []Instruction, dialect snapsql.Dialect) (
 				FallbackCombos: combos,
 			})
 		}
+
+		for j := start; j < len(result); j++ {
+			result[j].Pos = inst.Pos
+		}
 	}
 
 	merged := MergeAdjacentStatic(result)
@@ -170,11 +210,12 @@ func MergeAdjacentStatic(instructions []OptimizedInstruction) []OptimizedInstruc
 		result        []OptimizedInstruction
 		currentStatic strings.Builder
 		hasStatic     bool
+		staticPos     string
 	)
 
 	flushStatic := func() {
 		if hasStatic {
-			result = append(result, OptimizedInstruction{Op: "EMIT_STATIC", Value: currentStatic.String()})
+			result = append(result, OptimizedInstruction{Op: "EMIT_STATIC", Value: currentStatic.String(), Pos: staticPos})
 			currentStatic.Reset()
 
 			hasStatic = false
@@ -183,6 +224,10 @@ func MergeAdjacentStatic(instructions []OptimizedInstruction) []OptimizedInstruc
 
 	for _, inst := range instructions {
 		if inst.Op == "EMIT_STATIC" {
+			if !hasStatic {
+				staticPos = inst.Pos
+			}
+
 			currentStatic.WriteString(inst.Value)
 
 			hasStatic = true
@@ -202,7 +247,7 @@ func MergeAdjacentStatic(instructions []OptimizedInstruction) []OptimizedInstruc
 
 func applyPlaceholderStyle(instructions []OptimizedInstruction, dialect snapsql.Dialect) []OptimizedInstruction {
 	d := strings.ToLower(strings.TrimSpace(string(dialect)))
-	if d != "postgres" && d != "postgresql" && d != "pgx" && d != "pg" {
+	if d != "postgres" && d != "postgresql" && d != "pgx" && d != "pg" && d != "duckdb" && d != "cockroach" {
 		return instructions
 	}
 
@@ -255,6 +300,13 @@ func applyPlaceholderStyle(instructions []OptimizedInstruction, dialect snapsql.
 		switch instructions[i].Op {
 		case "EMIT_STATIC", "EMIT_UNLESS_BOUNDARY":
 			instructions[i].Value = convert(instructions[i].Value)
+		case OpEmitCursorCondition:
+			// カーソル条件は実行時に出力されるかが決まるため、条件内で $1 から番号を振る。
+			// 後続のプレースホルダの番号は動的 SQL の生成コードが実行時に振り直す
+			next := nextIndex
+			nextIndex = 1
+			instructions[i].Value = convert(instructions[i].Value)
+			nextIndex = next
 		}
 	}
 
@@ -265,7 +317,7 @@ func applyPlaceholderStyle(instructions []OptimizedInstruction, dialect snapsql.
 func HasDynamicInstructions(instructions []OptimizedInstruction) bool {
 	for _, inst := range instructions {
 		switch inst.Op {
-		case "IF", "ELSEIF", "ELSE", "LOOP_START", "LOOP_END", OpEmitSystemFor, OpFallbackCondition:
+		case "IF", "ELSEIF", "ELSE", "LOOP_START", "LOOP_END", OpEmitSystemFor, OpEmitSystemSoftDelete, OpEmitCursorCondition, OpFallbackCondition, OpEmitIdent, OpEmitOrderBy:
 			return true
 		}
 	}
@@ -320,7 +372,7 @@ func OptimizeLoopBoundaries(instructions []OptimizedInstruction) []OptimizedInst
 					if isLast {
 						result = append(result, loopInst)
 					} else {
-						result = append(result, OptimizedInstruction{Op: "EMIT_STATIC", Value: loopInst.Value})
+						result = append(result, OptimizedInstruction{Op: "EMIT_STATIC", Value: loopInst.Value, Pos: loopInst.Pos})
 					}
 				} else {
 					result = append(result, loopInst)
@@ -41,15 +41,36 @@ func GenerateDeleteInstructions(stmt parser.StatementNode, ctx *GenerationContex
 	}
 
 	// Phase 2: DELETE FROM 句を処理（必須）
+	// rewrite_delete が有効な論理削除テーブルは UPDATE 文に書き換える
 	skipLeading := deleteStmt.CTE() == nil
-	if err := generateDeleteFromClause(deleteStmt.From, builder, skipLeading); err != nil {
+	softDelete, ok := deleteSoftDeleteConfig(deleteStmt.From, ctx)
+	softDeleteColumn := ""
+
+	if ok && softDelete.RewriteDelete {
+		generateSoftDeleteFromClause(deleteStmt.From, builder, skipLeading, softDelete.Column)
+
+		if deleteStmt.Where == nil || !referencesColumn(deleteStmt.Where.RawTokens(), softDelete.Column) {
+			softDeleteColumn = softDelete.Column
+		}
+	} else if err := generateDeleteFromClause(deleteStmt.From, builder, skipLeading); err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to generate DELETE FROM clause: %w", err)
 	}
 
 	// Phase 3: WHERE 句を処理（任意）
 	var whereMeta *WhereClauseMeta
 
-	if deleteStmt.Where != nil {
+	if softDeleteColumn != "" {
+		var err error
+
+		whereMeta, err = generateSoftDeleteFilter(deleteStmt.Where, builder, true, softDeleteColumn)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to generate WHERE clause: %w", err)
+		}
+
+		if whereMeta == nil {
+			whereMeta = &WhereClauseMeta{Present: false, Status: StatusFullScan}
+		}
+	} else if deleteStmt.Where != nil {
 		var err error
 
 		whereMeta, err = generateWhereClause(deleteStmt.Where, builder, true)
@@ -3,6 +3,7 @@ package codegenerator
 import (
 	"fmt"
 
+	"github.com/shibukawa/snapsql"
 	"github.com/shibukawa/snapsql/parser"
 )
 
@@ -139,7 +140,8 @@ func GenerateInsertInstructionsWithFunctionDef(stmt parser.StatementNode, ctx *G
 	}
 
 	// RETURNING句を処理
-	if insertStmt.Returning != nil {
+	// MySQL は RETURNING をサポートしないため出力せず、生成コードが LAST_INSERT_ID() で挿入行を読み戻す
+	if insertStmt.Returning != nil && ctx.Dialect != snapsql.DialectMySQL {
 		if err := generateReturningClause(insertStmt.Returning, builder); err != nil {
 			return nil, nil, nil, fmt.Errorf("failed to generate RETURNING clause: %w", err)
 		}
@@ -134,6 +134,17 @@ ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name`,
 			expectedCELCount: 0,
 			expectedEnvCount: 1,
 		},
+		{
+			name:        "insert with returning clause on mysql",
+			sql:         "INSERT INTO users (id, name) VALUES (1, 'John') RETURNING id, name",
+			dialect:     snapsql.DialectMySQL,
+			expectError: false,
+			expectedInstructions: []Instruction{
+				{Op: OpEmitStatic, Value: "INSERT INTO users (id, name) VALUES (1, 'John')", Pos: "1:1"},
+			},
+			expectedCELCount: 0,
+			expectedEnvCount: 1,
+		},
 		{
 			name: "insert with single object element expansion",
 			sql: `/*# parameters: { user: { id: int, name: string } } */
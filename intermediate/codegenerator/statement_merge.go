@@ -0,0 +1,86 @@
+package codegenerator
+
+import (
+	"fmt"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/parser"
+)
+
+// GenerateMergeInstructions は MERGE 文から命令列と CEL 式を生成する
+//
+// Parameters:
+//   - stmt: parser.StatementNode (内部で *parser.MergeStatement にキャスト)
+//   - ctx: GenerationContext（方言、テーブル情報等）
+//
+// Returns:
+//   - []Instruction: 生成された命令列
+//   - []CELExpression: CEL 式のリスト
+//   - []CELEnvironment: CEL 環境のリスト
+//   - error: エラー
+//
+// 備考:
+//   - MERGE は PostgreSQL (15+) と DuckDB のみ。MySQL / MariaDB / SQLite ではエラー
+//   - WHEN 句内の UPDATE SET / INSERT VALUES / DELETE は WHEN 句のトークンとしてそのまま処理する
+//   - WHERE 句を持たないので、UPDATE / DELETE の全件更新ガードの対象外
+func GenerateMergeInstructions(stmt parser.StatementNode, ctx *GenerationContext) ([]Instruction, []CELExpression, []CELEnvironment, error) {
+	mergeStmt, ok := stmt.(*parser.MergeStatement)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("%w: expected *parser.MergeStatement, got %T", ErrStatementTypeMismatch, stmt)
+	}
+
+	switch ctx.Dialect {
+	case snapsql.DialectMySQL, snapsql.DialectMariaDB, snapsql.DialectSQLite, snapsql.DialectClickHouse:
+		return nil, nil, nil, fmt.Errorf("%w: MERGE is not available on %s", ErrUpsertNotSupported, ctx.Dialect)
+	}
+
+	// Root 環境がなければ作成（最初の呼び出しのみ）
+	if len(ctx.CELEnvironments) == 0 {
+		ctx.AddCELEnvironment(CELEnvironment{
+			Container:   "root",
+			ParentIndex: nil,
+		})
+	}
+
+	builder := NewInstructionBuilder(ctx)
+
+	// Phase 1: CTE（WITH句）を処理（任意）
+	if mergeStmt.CTE() != nil {
+		if err := generateCTEClause(mergeStmt.CTE(), builder); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to generate CTE clause: %w", err)
+		}
+	}
+
+	// Phase 2: MERGE INTO / USING ... ON / WHEN [NOT] MATCHED 句を処理（必須）
+	options := []ProcessTokensOption{}
+	if mergeStmt.CTE() == nil {
+		options = append(options, WithSkipLeadingTrivia())
+	}
+
+	if err := builder.ProcessTokens(mergeStmt.Into.RawTokens(), options...); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to generate MERGE INTO clause: %w", err)
+	}
+
+	if err := builder.ProcessTokens(mergeStmt.Using.RawTokens()); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to generate USING clause: %w", err)
+	}
+
+	for _, when := range mergeStmt.When {
+		if err := builder.ProcessTokens(when.RawTokens()); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to generate WHEN clause: %w", err)
+		}
+	}
+
+	// Phase 3: RETURNING 句を処理（任意、PostgreSQL 17+）
+	if mergeStmt.Returning != nil {
+		if err := generateReturningClause(mergeStmt.Returning, builder); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to generate RETURNING clause: %w", err)
+		}
+	}
+
+	instructions := builder.Finalize()
+	celExpressions := ctx.Expressions
+	celEnvironments := builder.GetCELEnvironments()
+
+	return instructions, celExpressions, celEnvironments, nil
+}
@@ -51,13 +51,49 @@ func GenerateSelectInstructions(stmt parser.StatementNode, ctx *GenerationContex
 		return nil, nil, nil, fmt.Errorf("failed to generate FROM clause: %w", err)
 	}
 
+	// pagination: cursor ではキーセット条件と LIMIT を差し替える
+	var pagination *CursorPagination
+
+	if isCursorPagination(ctx) {
+		if len(selectStmt.SetOperations) > 0 {
+			return nil, nil, nil, fmt.Errorf("%w: %s cannot be used", ErrInvalidCursorPagination, selectStmt.SetOperations[0].Operator.Operator)
+		}
+
+		keys, err := buildCursorKeys(selectStmt)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		pagination = &CursorPagination{Keys: keys}
+	}
+
 	// WHERE 句を処理（任意）
-	if selectStmt.Where != nil {
-		if _, err := generateWhereClause(selectStmt.Where, builder, false); err != nil {
+	// 論理削除テーブルの場合は "<column> IS NULL" を付与する
+	softDeleteColumn := selectSoftDeleteColumn(selectStmt.From, selectStmt.Where, ctx)
+	if softDeleteColumn != "" {
+		if pagination != nil && selectStmt.Where == nil {
+			// 論理削除フィルタは実行時に省略されうるため、カーソル条件の前に WHERE を確定させる
+			builder.addStatic(" WHERE 1 = 1", nil)
+			builder.RegisterEmitSystemSoftDelete("AND " + softDeleteColumn + " IS NULL")
+		} else if _, err := generateSoftDeleteFilter(selectStmt.Where, builder, false, softDeleteColumn); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to generate WHERE clause: %w", err)
+		}
+	} else if selectStmt.Where != nil {
+		// カーソル条件を AND で繋ぐため、テンプレートの OR 条件を括弧で囲む
+		tokens := selectStmt.Where.RawTokens()
+		if pagination != nil {
+			tokens = parenthesizeWhereBody(tokens)
+		}
+
+		if _, err := generateWhereClauseTokens(selectStmt.Where, tokens, builder, false); err != nil {
 			return nil, nil, nil, fmt.Errorf("failed to generate WHERE clause: %w", err)
 		}
 	}
 
+	if pagination != nil {
+		generateCursorCondition(pagination, selectStmt.Where != nil || softDeleteColumn != "", builder)
+	}
+
 	// GROUP BY 句を処理（任意）
 	if selectStmt.GroupBy != nil {
 		if err := generateGroupByClause(selectStmt.GroupBy, builder); err != nil {
@@ -72,6 +108,11 @@ func GenerateSelectInstructions(stmt parser.StatementNode, ctx *GenerationContex
 		}
 	}
 
+	// UNION / INTERSECT / EXCEPT で結合された SELECT を処理（任意）
+	if err := generateSetOperations(selectStmt.SetOperations, builder); err != nil {
+		return nil, nil, nil, err
+	}
+
 	// ORDER BY 句を処理（任意）
 	if selectStmt.OrderBy != nil {
 		if err := generateOrderByClause(selectStmt.OrderBy, builder); err != nil {
@@ -79,16 +120,25 @@ func GenerateSelectInstructions(stmt parser.StatementNode, ctx *GenerationContex
 		}
 	}
 
-	// LIMIT 句を処理（任意）
-	// GenerateLimitClauseOrSystem が nil と非 nil の両方のケースを処理
-	if err := GenerateLimitClauseOrSystem(selectStmt.Limit, builder); err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to generate LIMIT clause: %w", err)
-	}
+	if pagination != nil {
+		// ページサイズ + 1 行を取得する。OFFSET は使わない
+		if err := generateCursorLimit(selectStmt.Limit, pagination, builder); err != nil {
+			return nil, nil, nil, err
+		}
 
-	// OFFSET 句を処理（任意）
-	// GenerateOffsetClauseOrSystem が nil と非 nil の両方のケースを処理
-	if err := GenerateOffsetClauseOrSystem(selectStmt.Offset, builder); err != nil {
-		return nil, nil, nil, fmt.Errorf("failed to generate OFFSET clause: %w", err)
+		ctx.cursorPagination = pagination
+	} else {
+		// LIMIT 句を処理（任意）
+		// snapsql.yaml の limits による上限もここで適用する
+		if err := generateSelectLimitClause(selectStmt.Limit, builder); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to generate LIMIT clause: %w", err)
+		}
+
+		// OFFSET 句を処理（任意）
+		// GenerateOffsetClauseOrSystem が nil と非 nil の両方のケースを処理
+		if err := GenerateOffsetClauseOrSystem(selectStmt.Offset, builder); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to generate OFFSET clause: %w", err)
+		}
 	}
 
 	// FOR 句を処理（任意）- 行ロック句
@@ -271,3 +271,71 @@ WHERE
 	assert.Equal(t, StatusExists, meta.Status)
 	assert.Empty(t, meta.RemovalCombos)
 }
+
+func TestGenerateUpdateInstructionsSetOptional(t *testing.T) {
+	tests := []struct {
+		name                 string
+		sql                  string
+		expectedInstructions []Instruction
+		expectedColumns      []OptionalSetColumn
+		errorContains        string
+	}{
+		{
+			name: "all assignments optional",
+			sql: `/*# parameters: { user_id: int, name: string, email: string } */
+UPDATE users SET /*# set_optional */ name = /*= name */'Alice', email = /*= email */'a@example.com' WHERE id = /*= user_id */1`,
+			expectedInstructions: []Instruction{
+				{Op: OpEmitStatic, Value: "UPDATE users SET", Pos: "2:1"},
+				{Op: OpBoundary},
+				{Op: OpIf, Pos: "2:45", ExprIndex: ptr(0)},
+				{Op: OpEmitUnlessBoundary, Value: ",", Pos: "2:38"},
+				{Op: OpEmitStatic, Value: " name = ", Pos: "2:38"},
+				{Op: OpEmitEval, Pos: "2:45", ExprIndex: ptr(0)},
+				{Op: OpEnd, Pos: "2:45"},
+			},
+			expectedColumns: []OptionalSetColumn{
+				{Column: "name", Parameter: "name", ExprIndex: 0},
+				{Column: "email", Parameter: "email", ExprIndex: 1},
+			},
+		},
+		{
+			name: "required assignments before the directive",
+			sql: `/*# parameters: { user_id: int, status: string, name: string } */
+UPDATE users SET status = /*= status */'active', /*# set_optional */ name = /*= name */'Alice' WHERE id = /*= user_id */1`,
+			expectedColumns: []OptionalSetColumn{
+				{Column: "name", Parameter: "name", ExprIndex: 1},
+			},
+		},
+		{
+			name: "non parameter value",
+			sql: `/*# parameters: { user_id: int } */
+UPDATE users SET /*# set_optional */ updated_at = NOW() WHERE id = /*= user_id */1`,
+			errorContains: "must be 'column = /*= param */value'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, _, _, err := parser.ParseSQLFile(strings.NewReader(tt.sql), nil, "", "", parser.Options{})
+			require.NoError(t, err)
+
+			ctx := NewGenerationContext(snapsql.DialectPostgres)
+			instructions, _, _, err := GenerateUpdateInstructions(stmt, ctx)
+
+			if tt.errorContains != "" {
+				require.ErrorIs(t, err, ErrInvalidOptionalSet)
+				assert.Contains(t, err.Error(), tt.errorContains)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			if len(tt.expectedInstructions) > 0 {
+				assert.Equal(t, tt.expectedInstructions, instructions[:len(tt.expectedInstructions)])
+			}
+
+			assert.Equal(t, tt.expectedColumns, ctx.OptionalSetColumns())
+		})
+	}
+}
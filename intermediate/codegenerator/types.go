@@ -152,6 +152,10 @@ const (
 	OpEmitStatic = "EMIT_STATIC" // Output static text
 	// OpEmitEval outputs an evaluated expression.
 	OpEmitEval = "EMIT_EVAL" // Output evaluated expression
+	// OpEmitIdent outputs an identifier taken from an expression after checking it against Allowed.
+	OpEmitIdent = "EMIT_IDENT" // Output allowlisted identifier (ident directive)
+	// OpEmitOrderBy outputs an ORDER BY item taken from an expression after checking it against Allowed.
+	OpEmitOrderBy = "EMIT_ORDER_BY" // Output allowlisted ORDER BY item (orderby directive); an empty value selects Allowed[0]
 
 	// OpEmitUnlessBoundary outputs text unless followed by a boundary delimiter.
 	OpEmitUnlessBoundary = "EMIT_UNLESS_BOUNDARY" // Output text unless followed by boundary
@@ -180,13 +184,19 @@ const (
 	// OpIfSystemOffset conditionally emits content based on presence of system offset.
 	OpIfSystemOffset = "IF_SYSTEM_OFFSET" // Conditional based on system offset
 	// OpEmitSystemLimit outputs the system limit value.
-	OpEmitSystemLimit = "EMIT_SYSTEM_LIMIT" // Output system limit value
+	OpEmitSystemLimit = "EMIT_SYSTEM_LIMIT" // Output system limit value; with DefaultValue the cap, and with ExprIndex the LIMIT parameter clamped to it
 	// OpEmitSystemOffset outputs the system offset value.
 	OpEmitSystemOffset = "EMIT_SYSTEM_OFFSET" // Output system offset value
 	// OpEmitSystemFor outputs the system FOR clause value (not wrapped in IF).
 	OpEmitSystemFor = "EMIT_SYSTEM_FOR" // Output system FOR clause value
 	// OpEmitSystemValue outputs a specific system field value.
 	OpEmitSystemValue = "EMIT_SYSTEM_VALUE" // Output system value for specific field
+	// OpEmitSystemSoftDelete outputs the soft delete filter unless deleted rows are requested at runtime.
+	OpEmitSystemSoftDelete = "EMIT_SYSTEM_SOFT_DELETE" // Output soft delete filter (Value holds "WHERE ..." or "AND ...")
+	// OpEmitCursorCondition outputs the keyset condition of cursor pagination when a cursor is given.
+	OpEmitCursorCondition = "EMIT_CURSOR_CONDITION" // Value holds "WHERE ..." or "AND ..." with one "?" per CursorPagination.ConditionArgs
+	// OpEmitCursorLimit outputs the page size of cursor pagination plus one to detect further pages.
+	OpEmitCursorLimit = "EMIT_CURSOR_LIMIT" // Page size from ExprIndex, or the literal in Value
 
 	// SqlFragment and Dialects fields may be present in older IR payloads to
 	// carry per-dialect fragments. They are retained for compatibility with
@@ -206,10 +216,11 @@ type Instruction struct {
 	Collection          string             `json:"collection,omitempty"`            // For FOR (deprecated, use CollectionExprIndex)
 	CollectionExprIndex *int               `json:"collection_expr_index,omitempty"` // Index into expressions array for collection
 	EnvIndex            *int               `json:"env_index,omitempty"`             // Environment index for LOOP_START/LOOP_END
-	DefaultValue        string             `json:"default_value,omitempty"`         // For EMIT_SYSTEM_LIMIT, EMIT_SYSTEM_OFFSET
+	DefaultValue        string             `json:"default_value,omitempty"`         // For EMIT_SYSTEM_LIMIT, EMIT_SYSTEM_OFFSET; on IF_SYSTEM_LIMIT / EMIT_SYSTEM_LIMIT the LIMIT cap from snapsql.yaml
 	SystemField         string             `json:"system_field,omitempty"`          // For EMIT_SYSTEM_VALUE - system field name
 	Critical            bool               `json:"critical,omitempty"`              // For FALLBACK_CONDITION - indicates mutation guard should trigger when emitted
 	FallbackCombos      [][]RemovalLiteral `json:"fallback_combos,omitempty"`       // For FALLBACK_CONDITION - OR-of-AND condition combos
+	Allowed             []string           `json:"allowed,omitempty"`               // For EMIT_IDENT / EMIT_ORDER_BY - items the expression may evaluate to
 
 	// Database dialect fields
 	// SqlFragment / Dialects are retained fields for compatibility with
@@ -0,0 +1,76 @@
+package intermediate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	snapsql "github.com/shibukawa/snapsql"
+)
+
+func TestGenerateFromSQL_FoldsConstantExpressions(t *testing.T) {
+	sql := `/*# parameters: { id: int, status: string } */
+SELECT id FROM users WHERE id = /*= id */1 AND kind = /*= default_kind */'a' AND status = /*= status */'b' AND score > /*= limits.min_score */0`
+
+	constants := map[string]any{
+		"default_kind": "it's",
+		"status":       "shadowed by the parameter",
+		"limits":       map[string]any{"min_score": 10},
+	}
+
+	format, err := GenerateFromSQL(strings.NewReader(sql), constants, "", "", nil, &snapsql.Config{Dialect: "postgres"})
+	require.NoError(t, err)
+
+	var expressions []string
+	for _, expr := range format.CELExpressions {
+		expressions = append(expressions, expr.Expression)
+	}
+
+	assert.Equal(t, []string{"id", "status"}, expressions)
+
+	var static strings.Builder
+
+	for _, inst := range format.Instructions {
+		if inst.Op == OpEmitStatic {
+			static.WriteString(inst.Value)
+		}
+	}
+
+	assert.Contains(t, static.String(), "kind = 'it''s'")
+	assert.Contains(t, static.String(), "score > 10")
+}
+
+func TestGenerateFromSQL_FoldsProjectConstants(t *testing.T) {
+	sql := `/*# parameters: { status: string } */
+SELECT id FROM orders WHERE status = /*= status */'open' AND state = /*= const.order_status.shipped */0 LIMIT /*= const.max_page_size */10`
+
+	constants := map[string]any{
+		"const": map[string]any{
+			"max_page_size": 100,
+			"order_status":  map[string]any{"shipped": 3},
+		},
+	}
+
+	format, err := GenerateFromSQL(strings.NewReader(sql), constants, "", "", nil, &snapsql.Config{Dialect: "postgres"})
+	require.NoError(t, err)
+
+	var expressions []string
+	for _, expr := range format.CELExpressions {
+		expressions = append(expressions, expr.Expression)
+	}
+
+	assert.Equal(t, []string{"status"}, expressions)
+
+	var static strings.Builder
+
+	for _, inst := range format.Instructions {
+		if inst.Op == OpEmitStatic {
+			static.WriteString(inst.Value)
+		}
+	}
+
+	assert.Contains(t, static.String(), "state = 3")
+	assert.Contains(t, static.String(), "LIMIT 100")
+}
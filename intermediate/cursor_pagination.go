@@ -0,0 +1,32 @@
+package intermediate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shibukawa/snapsql"
+)
+
+// validateCursorPagination checks that a query declared with `pagination: cursor` returns flat
+// rows. The page is cut by LIMIT, so nested responses aggregated from joined rows cannot be paged.
+func validateCursorPagination(ctx *ProcessingContext, responses []Response) error {
+	if ctx.FunctionDef == nil || ctx.FunctionDef.Pagination != "cursor" {
+		return nil
+	}
+
+	if ctx.CursorPagination == nil {
+		return fmt.Errorf("%w: cursor pagination requires a SELECT statement", snapsql.ErrUnsupportedPagination)
+	}
+
+	if ctx.ResponseAffinity != string(ResponseAffinityMany) {
+		return fmt.Errorf("%w: cursor pagination requires the many response affinity, got %s", snapsql.ErrUnsupportedPagination, ctx.ResponseAffinity)
+	}
+
+	for _, response := range responses {
+		if strings.Contains(response.Name, "__") {
+			return fmt.Errorf("%w: cursor pagination does not support nested response %s", snapsql.ErrUnsupportedPagination, response.Name)
+		}
+	}
+
+	return nil
+}
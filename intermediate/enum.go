@@ -0,0 +1,194 @@
+package intermediate
+
+import (
+	"strings"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/parser"
+	cmn "github.com/shibukawa/snapsql/parser/parsercommon"
+	"github.com/shibukawa/snapsql/tokenizer"
+)
+
+// columnEnum returns the enum of a column, or nil when the column is not an enum. A Postgres
+// enum is named after its type; a MySQL ENUM column, which has no type name, after the table and
+// the column.
+func columnEnum(table *snapsql.TableInfo, column *snapsql.ColumnInfo) *Enum {
+	if table == nil || column == nil || len(column.EnumValues) == 0 {
+		return nil
+	}
+
+	name := column.DatabaseType
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		name = name[idx+1:]
+	}
+
+	if name == "" || name == "enum" {
+		name = table.Name + "_" + column.Name
+	}
+
+	return &Enum{Name: name, Values: column.EnumValues}
+}
+
+// bindEnumParameters attaches the enum of a column to the string parameters written directly
+// against it: "column = /*= param */", "column IN (/*= param */)", SET assignments and the
+// values of INSERT INTO (columns) VALUES (...).
+func bindEnumParameters(stmt parser.StatementNode, refs []TableReferenceInfo, tableInfo map[string]*snapsql.TableInfo, params []Parameter) {
+	if stmt == nil || len(tableInfo) == 0 || len(params) == 0 {
+		return
+	}
+
+	var insertColumns []cmn.FieldName
+	if insert, ok := stmt.(*cmn.InsertIntoStatement); ok {
+		insertColumns = insert.Columns
+	}
+
+	for _, clause := range stmt.Clauses() {
+		tokens := enumBindingTokens(clause.RawTokens())
+
+		for i, token := range tokens {
+			if token.Directive == nil || token.Directive.Type != "variable" {
+				continue
+			}
+
+			param := findEnumCandidate(params, strings.TrimSpace(token.Directive.Condition))
+			if param == nil || param.Enum != nil {
+				continue
+			}
+
+			var qualifier, column string
+			if clause.Type() == cmn.VALUES_CLAUSE {
+				if index := valuePosition(tokens[:i]); index >= 0 && index < len(insertColumns) {
+					qualifier, column = insertColumns[index].TableName, insertColumns[index].Name
+				}
+			} else {
+				qualifier, column = comparedColumn(tokens[:i])
+			}
+
+			if column != "" {
+				param.Enum = resolveColumnEnum(qualifier, column, refs, tableInfo)
+			}
+		}
+	}
+}
+
+// enumBindingTokens drops whitespace, comments other than directives and the dummy literals
+// that follow variable directives
+func enumBindingTokens(raw []tokenizer.Token) []tokenizer.Token {
+	tokens := make([]tokenizer.Token, 0, len(raw))
+	inDummy := false
+
+	for _, token := range raw {
+		switch {
+		case token.Type == tokenizer.DUMMY_START:
+			inDummy = true
+		case token.Type == tokenizer.DUMMY_END:
+			inDummy = false
+		case inDummy, token.Type == tokenizer.WHITESPACE, token.Type == tokenizer.LINE_COMMENT:
+		case token.Type == tokenizer.BLOCK_COMMENT && token.Directive == nil:
+		default:
+			tokens = append(tokens, token)
+		}
+	}
+
+	return tokens
+}
+
+// findEnumCandidate returns the parameter named by expression when it is a string or a string list
+func findEnumCandidate(params []Parameter, expression string) *Parameter {
+	for i := range params {
+		if params[i].Name != expression {
+			continue
+		}
+
+		switch strings.ToLower(params[i].Type) {
+		case "string", "string[]":
+			return &params[i]
+		}
+
+		return nil
+	}
+
+	return nil
+}
+
+// comparedColumn returns the column before "= <value>", "<> <value>" or "IN (<value>"
+func comparedColumn(before []tokenizer.Token) (string, string) {
+	end := len(before)
+
+	switch {
+	case end >= 1 && (before[end-1].Type == tokenizer.EQUAL || before[end-1].Type == tokenizer.NOT_EQUAL):
+		end--
+	case end >= 2 && before[end-1].Type == tokenizer.OPENED_PARENS && strings.EqualFold(before[end-2].Value, "IN"):
+		end -= 2
+	default:
+		return "", ""
+	}
+
+	if end < 1 || !isColumnToken(before[end-1]) {
+		return "", ""
+	}
+
+	column := before[end-1].Value
+	if end >= 3 && before[end-2].Type == tokenizer.DOT && isColumnToken(before[end-3]) {
+		return before[end-3].Value, column
+	}
+
+	return "", column
+}
+
+func isColumnToken(token tokenizer.Token) bool {
+	return token.Type == tokenizer.IDENTIFIER || token.Type == tokenizer.CONTEXTUAL_IDENTIFIER
+}
+
+// valuePosition returns the index of the value being written in the current row of a VALUES
+// clause, or -1 when the value is nested in an expression
+func valuePosition(before []tokenizer.Token) int {
+	depth, index := 0, 0
+
+	for _, token := range before {
+		switch token.Type {
+		case tokenizer.OPENED_PARENS:
+			depth++
+			if depth == 1 {
+				index = 0
+			}
+		case tokenizer.CLOSED_PARENS:
+			depth--
+		case tokenizer.COMMA:
+			if depth == 1 {
+				index++
+			}
+		}
+	}
+
+	if depth != 1 {
+		return -1
+	}
+
+	return index
+}
+
+// resolveColumnEnum finds the enum of a column through the table references of the statement.
+// An unqualified column belongs to the first referenced table that has it.
+func resolveColumnEnum(qualifier, column string, refs []TableReferenceInfo, tableInfo map[string]*snapsql.TableInfo) *Enum {
+	column = normalizeColumnName(column)
+	qualifier = strings.Trim(qualifier, "`\"[]")
+
+	for _, ref := range refs {
+		if qualifier != "" && !strings.EqualFold(ref.Alias, qualifier) && !strings.EqualFold(ref.Name, qualifier) {
+			continue
+		}
+
+		tableName := ref.TableName
+		if tableName == "" {
+			tableName = ref.Name
+		}
+
+		table := lookupTableInfo(tableInfo, tableName)
+		if col := lookupColumnInfo(table, column); col != nil {
+			return columnEnum(table, col)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package intermediate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql"
+)
+
+func enumTestTables() map[string]*snapsql.TableInfo {
+	return map[string]*snapsql.TableInfo{
+		"tasks": {
+			Name: "tasks",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":       {Name: "id", DataType: "int", IsPrimaryKey: true},
+				"title":    {Name: "title", DataType: "string"},
+				"status":   {Name: "status", DataType: "string", DatabaseType: "task_status", EnumValues: []string{"todo", "done"}},
+				"priority": {Name: "priority", DataType: "string", DatabaseType: "enum", EnumValues: []string{"low", "high"}},
+			},
+		},
+	}
+}
+
+func TestEnumParametersAndResponses(t *testing.T) {
+	taskStatus := &Enum{Name: "task_status", Values: []string{"todo", "done"}}
+	taskPriority := &Enum{Name: "tasks_priority", Values: []string{"low", "high"}}
+
+	tests := []struct {
+		name      string
+		sql       string
+		params    map[string]*Enum
+		responses map[string]*Enum
+	}{
+		{
+			name: "Select",
+			sql: `/*#
+function_name: list_tasks
+parameters:
+  id: int
+  status: string
+  priorities: string[]
+*/
+SELECT id, status, priority FROM tasks t WHERE t.status = /*= status */'todo' AND priority IN (/*= priorities */'low') AND id = /*= id */1`,
+			params:    map[string]*Enum{"id": nil, "status": taskStatus, "priorities": taskPriority},
+			responses: map[string]*Enum{"id": nil, "status": taskStatus, "priority": taskPriority},
+		},
+		{
+			name: "Update",
+			sql: `/*#
+function_name: set_status
+parameters:
+  id: int
+  status: string
+*/
+UPDATE tasks SET status = /*= status */'todo' WHERE id = /*= id */1`,
+			params: map[string]*Enum{"id": nil, "status": taskStatus},
+		},
+		{
+			name: "Insert",
+			sql: `/*#
+function_name: add_task
+parameters:
+  title: string
+  priority: string
+*/
+INSERT INTO tasks (title, priority) VALUES (/*= title */'x', /*= priority */'low')`,
+			params: map[string]*Enum{"title": nil, "priority": taskPriority},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := GenerateFromSQL(strings.NewReader(tt.sql), nil, "tasks.snap.sql", ".", enumTestTables(), &snapsql.Config{Dialect: "postgres"})
+			assert.NoError(t, err)
+
+			for _, param := range format.Parameters {
+				assert.Equal(t, tt.params[param.Name], param.Enum, param.Name)
+			}
+
+			for _, response := range format.Responses {
+				if want, ok := tt.responses[response.Name]; ok {
+					assert.Equal(t, want, response.Enum, response.Name)
+				}
+			}
+		})
+	}
+}
@@ -1,9 +1,22 @@
 package intermediate
 
 // ExplangExpression stores parsed explang steps aligned with CELExpressions.
+// A call of a template function has no steps; Function and Args are set instead.
 type ExplangExpression struct {
-	ID               string    `json:"id"`
-	EnvironmentIndex int       `json:"environment_index"`
-	Position         Position  `json:"position,omitzero"`
-	Steps            []Expressions `json:"steps"`
+	ID               string          `json:"id"`
+	EnvironmentIndex int             `json:"environment_index"`
+	Position         Position        `json:"position,omitzero"`
+	Steps            []Expressions   `json:"steps"`
+	Function         string          `json:"function,omitempty"`
+	Args             [][]Expressions `json:"args,omitempty"`
+}
+
+// TemplateFunction is a function registered by cel_functions in snapsql.yaml and called by the template.
+type TemplateFunction struct {
+	Name    string   `json:"name"`
+	Args    []string `json:"args,omitempty"`
+	Returns string   `json:"returns"`
+	// GoPackage is the import path of the Go implementation; empty means the generated package
+	GoPackage string `json:"go_package,omitempty"`
+	GoName    string `json:"go_name"`
 }
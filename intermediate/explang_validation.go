@@ -3,8 +3,10 @@ package intermediate
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 
+	"github.com/shibukawa/snapsql"
 	"github.com/shibukawa/snapsql/explang"
 	"github.com/shibukawa/snapsql/intermediate/codegenerator"
 	"github.com/shibukawa/snapsql/parser"
@@ -14,7 +16,13 @@ import (
 // ErrExplangValidation is returned when explang expressions fail schema validation.
 var ErrExplangValidation = errors.New("explang validation failed")
 
-func validateExplangExpressions(funcDef *parser.FunctionDefinition, expressions []codegenerator.CELExpression, envs []codegenerator.CELEnvironment) ([][]explang.Step, error) {
+// parsedExplang is an access path or a template function call parsed from a CEL expression.
+type parsedExplang struct {
+	steps []explang.Step
+	call  *explang.Call
+}
+
+func validateExplangExpressions(funcDef *parser.FunctionDefinition, expressions []codegenerator.CELExpression, envs []codegenerator.CELEnvironment, functions map[string]explang.Function) ([]parsedExplang, error) {
 	if funcDef == nil || len(expressions) == 0 {
 		return nil, nil
 	}
@@ -24,7 +32,7 @@ func validateExplangExpressions(funcDef *parser.FunctionDefinition, expressions
 		params = map[string]any{}
 	}
 
-	stepsPerExpr := make([][]explang.Step, len(expressions))
+	parsed := make([]parsedExplang, len(expressions))
 
 	for idx, expr := range expressions {
 		source := strings.TrimSpace(expr.Expression)
@@ -36,7 +44,7 @@ func validateExplangExpressions(funcDef *parser.FunctionDefinition, expressions
 
 		column := max(expr.Position.Column, 1)
 
-		steps, err := explang.ParseSteps(source, line, column)
+		steps, call, err := explang.ParseExpression(source, line, column)
 		if err != nil {
 			return nil, fmt.Errorf("%w for %s at line %d column %d: %w", ErrExplangValidation, expr.ID, line, column, err)
 		}
@@ -46,15 +54,73 @@ func validateExplangExpressions(funcDef *parser.FunctionDefinition, expressions
 			opts = &explang.ValidatorOptions{AdditionalRoots: extras}
 		}
 
-		if errs := explang.ValidateStepsAgainstParameters(steps, params, opts); len(errs) > 0 {
+		var errs []explang.ValidationError
+		if call != nil {
+			errs = explang.ValidateCall(call, functions, params, opts)
+		} else {
+			errs = explang.ValidateStepsAgainstParameters(steps, params, opts)
+		}
+
+		if len(errs) > 0 {
 			ve := errs[0]
 			return nil, fmt.Errorf("%w for %s at line %d column %d: %s", ErrExplangValidation, expr.ID, ve.Step.Pos.Line, ve.Step.Pos.Column, ve.Message)
 		}
 
-		stepsPerExpr[idx] = steps
+		parsed[idx] = parsedExplang{steps: steps, call: call}
+	}
+
+	return parsed, nil
+}
+
+// TemplateFunctionsFromConfig converts cel_functions in snapsql.yaml into the declarations
+// passed to the parser through parser.Options.Functions.
+func TemplateFunctionsFromConfig(config *snapsql.Config) map[string]explang.Function {
+	if config == nil || len(config.CELFunctions) == 0 {
+		return nil
+	}
+
+	functions := make(map[string]explang.Function, len(config.CELFunctions))
+	for name, fn := range config.CELFunctions {
+		functions[name] = explang.Function{Args: fn.Args, Returns: fn.Returns}
 	}
 
-	return stepsPerExpr, nil
+	return functions
+}
+
+// usedTemplateFunctions lists the configured functions called by the expressions, sorted by name.
+func usedTemplateFunctions(config *snapsql.Config, exprs []ExplangExpression) []TemplateFunction {
+	if config == nil {
+		return nil
+	}
+
+	var names []string
+
+	for _, expr := range exprs {
+		if expr.Function != "" && !slices.Contains(names, expr.Function) {
+			names = append(names, expr.Function)
+		}
+	}
+
+	slices.Sort(names)
+
+	result := make([]TemplateFunction, 0, len(names))
+	for _, name := range names {
+		fn := config.CELFunctions[name]
+		goPackage, goName := fn.GoFunction()
+		result = append(result, TemplateFunction{
+			Name:      name,
+			Args:      fn.Args,
+			Returns:   fn.Returns,
+			GoPackage: goPackage,
+			GoName:    goName,
+		})
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
 }
 
 func buildAdditionalRoots(funcDef *parser.FunctionDefinition, envs []codegenerator.CELEnvironment, envIndex int) map[string]any {
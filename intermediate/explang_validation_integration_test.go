@@ -24,3 +24,34 @@ SELECT id FROM users WHERE user_id = /*= user.id */1`
 		require.NotEmpty(t, format.Expressions[0].Steps)
 	}
 }
+
+func TestGenerateFromSQL_TemplateFunction(t *testing.T) {
+	sql := `/*# parameters: { user: { email: string } } */
+SELECT id FROM users WHERE email = /*= normalizeEmail(user.email) */'a@example.com'`
+
+	cfg := &snapsql.Config{
+		Dialect: "postgres",
+		CELFunctions: map[string]snapsql.CELFunctionConfig{
+			"normalizeEmail": {Args: []string{"string"}, Returns: "string", Go: "github.com/acme/helpers.NormalizeEmail"},
+			"unused":         {Returns: "string", Go: "Unused"},
+		},
+	}
+
+	format, err := GenerateFromSQL(strings.NewReader(sql), nil, "", "", nil, cfg)
+	require.NoError(t, err)
+	require.Len(t, format.Expressions, 1)
+	require.Empty(t, format.Expressions[0].Steps)
+	require.Equal(t, "normalizeEmail", format.Expressions[0].Function)
+	require.Len(t, format.Expressions[0].Args, 1)
+	require.Equal(t, []TemplateFunction{{
+		Name:      "normalizeEmail",
+		Args:      []string{"string"},
+		Returns:   "string",
+		GoPackage: "github.com/acme/helpers",
+		GoName:    "NormalizeEmail",
+	}}, format.TemplateFunctions)
+
+	_, err = GenerateFromSQL(strings.NewReader(sql), nil, "", "", nil, &snapsql.Config{Dialect: "postgres"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `unknown template function "normalizeEmail"`)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/shibukawa/snapsql/explang"
 	"github.com/shibukawa/snapsql/intermediate/codegenerator"
 	"github.com/shibukawa/snapsql/parser"
 )
@@ -30,10 +31,10 @@ func TestValidateExplangExpressions_Success(t *testing.T) {
 
 	envs := []codegenerator.CELEnvironment{{Index: 0}}
 
-	steps, err := validateExplangExpressions(funcDef, []codegenerator.CELExpression{expr}, envs)
+	parsed, err := validateExplangExpressions(funcDef, []codegenerator.CELExpression{expr}, envs, nil)
 	require.NoError(t, err)
-	require.Len(t, steps, 1)
-	assert.Len(t, steps[0], 3)
+	require.Len(t, parsed, 1)
+	assert.Len(t, parsed[0].steps, 3)
 }
 
 func TestValidateExplangExpressions_UnknownRoot(t *testing.T) {
@@ -52,7 +53,7 @@ func TestValidateExplangExpressions_UnknownRoot(t *testing.T) {
 
 	envs := []codegenerator.CELEnvironment{{Index: 0}}
 
-	_, err := validateExplangExpressions(funcDef, []codegenerator.CELExpression{expr}, envs)
+	_, err := validateExplangExpressions(funcDef, []codegenerator.CELExpression{expr}, envs, nil)
 	require.Error(t, err)
 	assert.ErrorIs(t, err, ErrExplangValidation)
 	assert.Contains(t, err.Error(), `unknown root parameter "foo"`)
@@ -87,10 +88,10 @@ func TestValidateExplangExpressions_AdditionalRootSuccess(t *testing.T) {
 		Position:         codegenerator.Position{Line: 5, Column: 8},
 	}
 
-	steps, err := validateExplangExpressions(funcDef, []codegenerator.CELExpression{expr}, []codegenerator.CELEnvironment{rootEnv, loopEnv})
+	parsed, err := validateExplangExpressions(funcDef, []codegenerator.CELExpression{expr}, []codegenerator.CELEnvironment{rootEnv, loopEnv}, nil)
 	require.NoError(t, err)
-	require.Len(t, steps, 1)
-	assert.True(t, len(steps[0]) > 0)
+	require.Len(t, parsed, 1)
+	assert.True(t, len(parsed[0].steps) > 0)
 }
 
 func TestValidateExplangExpressions_AdditionalRootUnknownField(t *testing.T) {
@@ -124,8 +125,40 @@ func TestValidateExplangExpressions_AdditionalRootUnknownField(t *testing.T) {
 		Position:         codegenerator.Position{Line: 9, Column: 3},
 	}
 
-	_, err := validateExplangExpressions(funcDef, []codegenerator.CELExpression{expr}, envs)
+	_, err := validateExplangExpressions(funcDef, []codegenerator.CELExpression{expr}, envs, nil)
 	require.Error(t, err)
 	assert.ErrorIs(t, err, ErrExplangValidation)
 	assert.Contains(t, err.Error(), `unknown field "unknown"`)
 }
+
+func TestValidateExplangExpressions_TemplateFunction(t *testing.T) {
+	funcDef := &parser.FunctionDefinition{
+		Parameters: map[string]any{
+			"user": map[string]any{"email": "string"},
+		},
+	}
+
+	expr := codegenerator.CELExpression{
+		ID:               "expr_call",
+		Expression:       "normalizeEmail(user.email)",
+		EnvironmentIndex: 0,
+		Position:         codegenerator.Position{Line: 2, Column: 4},
+	}
+
+	envs := []codegenerator.CELEnvironment{{Index: 0}}
+
+	_, err := validateExplangExpressions(funcDef, []codegenerator.CELExpression{expr}, envs, nil)
+	require.ErrorIs(t, err, ErrExplangValidation)
+	assert.Contains(t, err.Error(), `unknown template function "normalizeEmail"`)
+
+	functions := map[string]explang.Function{"normalizeEmail": {Args: []string{"string"}, Returns: "string"}}
+
+	parsed, err := validateExplangExpressions(funcDef, []codegenerator.CELExpression{expr}, envs, functions)
+	require.NoError(t, err)
+	require.Len(t, parsed, 1)
+	assert.Nil(t, parsed[0].steps)
+	require.NotNil(t, parsed[0].call)
+	assert.Equal(t, "normalizeEmail", parsed[0].call.Function)
+	require.Len(t, parsed[0].call.Args, 1)
+	assert.Len(t, parsed[0].call.Args[0], 2)
+}
@@ -0,0 +1,77 @@
+package intermediate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate/codegenerator"
+)
+
+func TestFullTextSearchDirective(t *testing.T) {
+	tables := map[string]*snapsql.TableInfo{
+		"articles": {
+			Name: "articles",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":    {Name: "id", DataType: "int", IsPrimaryKey: true},
+				"title": {Name: "title", DataType: "string"},
+			},
+		},
+	}
+
+	sql := `/*#
+function_name: search_articles
+parameters:
+  query: string
+*/
+SELECT id, title FROM articles a WHERE /*# fts(a.title, query) */ AND id > 0`
+
+	tests := []struct {
+		dialect snapsql.Dialect
+		want    string
+	}{
+		{snapsql.DialectPostgres, "SELECT id, title FROM articles a WHERE to_tsvector(a.title) @@ plainto_tsquery(?) AND id > 0"},
+		{snapsql.DialectCockroach, "SELECT id, title FROM articles a WHERE to_tsvector(a.title) @@ plainto_tsquery(?) AND id > 0"},
+		{snapsql.DialectMySQL, "SELECT id, title FROM articles a WHERE MATCH (a.title) AGAINST (? IN NATURAL LANGUAGE MODE) AND id > 0"},
+		{snapsql.DialectMariaDB, "SELECT id, title FROM articles a WHERE MATCH (a.title) AGAINST (? IN NATURAL LANGUAGE MODE) AND id > 0"},
+		{snapsql.DialectSQLite, "SELECT id, title FROM articles a WHERE a.title MATCH ? AND id > 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.dialect), func(t *testing.T) {
+			format, err := GenerateFromSQL(strings.NewReader(sql), nil, "q.snap.sql", "", tables, &snapsql.Config{Dialect: tt.dialect})
+			assert.NoError(t, err)
+
+			var (
+				got   strings.Builder
+				evals int
+			)
+
+			for _, inst := range format.Instructions {
+				switch inst.Op {
+				case codegenerator.OpIfSystemLimit:
+					assert.Equal(t, tt.want, got.String())
+					assert.Equal(t, 1, evals)
+
+					return
+				case codegenerator.OpEmitStatic:
+					got.WriteString(inst.Value)
+				case codegenerator.OpEmitEval:
+					got.WriteString("?")
+
+					evals++
+				}
+			}
+
+			t.Fatalf("system LIMIT is not found: %s", got.String())
+		})
+	}
+
+	for _, dialect := range []snapsql.Dialect{snapsql.DialectDuckDB, snapsql.DialectClickHouse} {
+		t.Run(string(dialect), func(t *testing.T) {
+			_, err := GenerateFromSQL(strings.NewReader(sql), nil, "q.snap.sql", "", tables, &snapsql.Config{Dialect: dialect})
+			assert.IsError(t, err, codegenerator.ErrFullTextSearchNotSupported)
+		})
+	}
+}
@@ -14,7 +14,7 @@ import (
 // GenerateFromSQL generates the intermediate format for a SQL template
 func GenerateFromSQL(reader io.Reader, constants map[string]any, basePath string, projectRootPath string, tableInfo map[string]*snapsql.TableInfo, config *snapsql.Config) (*IntermediateFormat, error) {
 	// Parse the SQL
-	stmt, typeInfoMap, funcDef, err := parser.ParseSQLFile(reader, constants, basePath, projectRootPath, parser.DefaultOptions)
+	stmt, typeInfoMap, funcDef, err := parser.ParseSQLFile(reader, constants, basePath, projectRootPath, parserOptions(config))
 	if err != nil {
 		return nil, err
 	}
@@ -26,7 +26,7 @@ func GenerateFromSQL(reader io.Reader, constants map[string]any, basePath string
 // GenerateFromMarkdown generates the intermediate format for a Markdown file containing SQL
 func GenerateFromMarkdown(doc *markdownparser.SnapSQLDocument, basePath string, projectRootPath string, constants map[string]any, tableInfo map[string]*snapsql.TableInfo, config *snapsql.Config) (*IntermediateFormat, error) {
 	// Parse the Markdown
-	stmt, typeInfoMap, funcDef, err := parser.ParseMarkdownFile(doc, basePath, projectRootPath, constants, parser.DefaultOptions)
+	stmt, typeInfoMap, funcDef, err := parser.ParseMarkdownFile(doc, basePath, projectRootPath, constants, parserOptions(config))
 	if err != nil {
 		return nil, err
 	}
@@ -43,6 +43,14 @@ func GenerateFromMarkdown(doc *markdownparser.SnapSQLDocument, basePath string,
 	return format, nil
 }
 
+// parserOptions returns the parser options for templates of the project configured by config
+func parserOptions(config *snapsql.Config) parser.Options {
+	opts := parser.DefaultOptions
+	opts.Functions = TemplateFunctionsFromConfig(config)
+
+	return opts
+}
+
 // generateIntermediateFormat is the common implementation using the new pipeline approach
 func generateIntermediateFormat(stmt parsercommon.StatementNode, typeInfoMap map[string]any, funcDef *parsercommon.FunctionDefinition, filePath string, tableInfo map[string]*snapsql.TableInfo, config *snapsql.Config) (*IntermediateFormat, error) {
 	_ = filePath // File path not currently used in pipeline processing
@@ -0,0 +1,67 @@
+package intermediate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate/codegenerator"
+)
+
+func TestIdentDirective(t *testing.T) {
+	sql := `/*#
+function_name: sorted_users
+parameters:
+  sort: string
+*/
+SELECT id, name FROM users ORDER BY /*# ident sort in [name, u.created_at] */name DESC`
+
+	format, err := GenerateFromSQL(strings.NewReader(sql), nil, "q.snap.sql", "", nil, &snapsql.Config{Dialect: snapsql.DialectPostgres})
+	assert.NoError(t, err)
+
+	var ident *Instruction
+
+	for i, inst := range format.Instructions {
+		if inst.Op == OpEmitIdent {
+			ident = &format.Instructions[i]
+
+			assert.Equal(t, "SELECT id, name FROM users ORDER BY ", format.Instructions[i-1].Value)
+			assert.Equal(t, " DESC", format.Instructions[i+1].Value)
+		}
+	}
+
+	assert.NotZero(t, ident)
+	assert.Equal(t, []string{"name", "u.created_at"}, ident.Allowed)
+	assert.Equal(t, "sort", format.CELExpressions[*ident.ExprIndex].Expression)
+
+	optimized, err := codegenerator.OptimizeInstructions(format.Instructions, snapsql.DialectPostgres)
+	assert.NoError(t, err)
+	assert.True(t, codegenerator.HasDynamicInstructions(optimized))
+}
+
+func TestOrderByDirective(t *testing.T) {
+	sql := `/*#
+function_name: sorted_users
+parameters:
+  sort: string
+*/
+SELECT id, name FROM users ORDER BY /*# orderby sort in [name, created_at desc] */created_at DESC, id`
+
+	format, err := GenerateFromSQL(strings.NewReader(sql), nil, "q.snap.sql", "", nil, &snapsql.Config{Dialect: snapsql.DialectPostgres})
+	assert.NoError(t, err)
+
+	var orderBy *Instruction
+
+	for i, inst := range format.Instructions {
+		if inst.Op == OpEmitOrderBy {
+			orderBy = &format.Instructions[i]
+
+			assert.Equal(t, "SELECT id, name FROM users ORDER BY ", format.Instructions[i-1].Value)
+			assert.Equal(t, ", id", format.Instructions[i+1].Value)
+		}
+	}
+
+	assert.NotZero(t, orderBy)
+	assert.Equal(t, []string{"name", "created_at DESC"}, orderBy.Allowed)
+}
@@ -14,6 +14,8 @@ type Parameter struct {
 	Type        string `json:"type"`
 	Optional    bool   `json:"optional,omitempty"`
 	Description string `json:"description,omitempty"`
+	// Enum is the enum of the column the parameter is compared with or written to
+	Enum *Enum `json:"enum,omitempty"`
 }
 
 // Response represents a result field
@@ -28,11 +30,21 @@ type Response struct {
 	// a__b__c のような多段 prefix に対応する将来拡張を想定
 	// 設定タイミング: SELECT 解析 Processor (未実装) が prefix 分解とスキーマ主キー照合で決定する予定
 	HierarchyKeyLevel int `json:"hierarchy_key_level,omitempty"`
+	// DatabaseType is the database type of the source column (e.g. uuid, jsonb), used by type_mappings
+	DatabaseType string `json:"database_type,omitempty"`
+	// Enum is set when the source column is a database enum
+	Enum *Enum `json:"enum,omitempty"`
 	// Internal only: precise source origin (not exported to final intermediate JSON)
 	SourceTable  string `json:"-"`
 	SourceColumn string `json:"-"`
 }
 
+// Enum is a database enum type: a Postgres enum type or a MySQL ENUM column
+type Enum struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
 // ImplicitParameter represents a parameter that should be obtained from context/TLS
 type ImplicitParameter struct {
 	Name    string `json:"name"`
@@ -73,6 +85,16 @@ type WhereClauseMeta struct {
 	RawText           string                  `json:"raw_text,omitempty"`
 }
 
+// ReturningFollowUp describes the SELECT that reads back the rows inserted by INSERT ... RETURNING
+// on dialects without RETURNING (MySQL). The RETURNING clause is removed from the instructions and
+// generated code runs SQL with the first generated key (LAST_INSERT_ID()) and the key after the
+// last inserted row as arguments.
+type ReturningFollowUp struct {
+	Table     string `json:"table"`
+	KeyColumn string `json:"key_column"`
+	SQL       string `json:"sql"`
+}
+
 // RemovalLiteral describes a single boolean requirement controlling WHERE removal.
 type RemovalLiteral struct {
 	ExprIndex int  `json:"expr_index"`
@@ -118,7 +140,7 @@ type IntermediateFormat struct {
 	// Format version
 	FormatVersion string `json:"format_version"`
 
-	// StatementType describes the root SQL statement (select/insert/update/delete)
+	// StatementType describes the root SQL statement (select/insert/update/delete/upsert)
 	StatementType string `json:"statement_type,omitempty"`
 
 	// Query name
@@ -142,6 +164,9 @@ type IntermediateFormat struct {
 	// Response affinity (database type mapping)
 	ResponseAffinity string `json:"response_affinity,omitempty"`
 
+	// ResponseType is the user-declared type the generated code returns instead of its own response struct (response_type)
+	ResponseType string `json:"response_type,omitempty"`
+
 	// Instruction sequence
 	Instructions []Instruction `json:"instructions"`
 
@@ -151,6 +176,9 @@ type IntermediateFormat struct {
 	// Explang expressions (flattened steps) aligned with CELExpressions by index.
 	Expressions []ExplangExpression `json:"expressions,omitempty"`
 
+	// Template functions called by the expressions
+	TemplateFunctions []TemplateFunction `json:"template_functions,omitempty"`
+
 	// CEL environments with variable definitions
 	CELEnvironments []CELEnvironment `json:"cel_environments"`
 
@@ -172,6 +200,18 @@ type IntermediateFormat struct {
 	// WhereClauseMeta stores metadata about the top-level WHERE clause (mainly for UPDATE/DELETE guards)
 	WhereClauseMeta *WhereClauseMeta `json:"where_clause,omitempty"`
 
+	// ReturningFollowUp reads back inserted rows when the dialect has no RETURNING clause
+	ReturningFollowUp *ReturningFollowUp `json:"returning_follow_up,omitempty"`
+
+	// OptionalSet lists the UPDATE SET assignments that are skipped when their parameter is null (set_optional directive)
+	OptionalSet []OptionalSetColumn `json:"optional_set,omitempty"`
+
+	// CursorPagination describes the keyset pagination of a SELECT declared with `pagination: cursor`
+	CursorPagination *CursorPagination `json:"cursor_pagination,omitempty"`
+
+	// LimitGuard describes the LIMIT guard rails of a SELECT configured in the limits section of snapsql.yaml
+	LimitGuard *LimitGuard `json:"limit_guard,omitempty"`
+
 	// MockTestCases stores parsed test cases for mock generation / WithMock integration
 	MockTestCases []snapsql.MockTestCase `json:"test_cases,omitempty"`
 
@@ -0,0 +1,98 @@
+package intermediate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql"
+)
+
+func TestJSONFunctionResponseTypes(t *testing.T) {
+	tables := map[string]*snapsql.TableInfo{
+		"docs": {
+			Name: "docs",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":   {Name: "id", DataType: "int", IsPrimaryKey: true},
+				"data": {Name: "data", DataType: "json"},
+			},
+		},
+	}
+
+	type column struct {
+		name       string
+		typ        string
+		isNullable bool
+	}
+
+	tests := []struct {
+		name    string
+		dialect snapsql.Dialect
+		sql     string
+		want    []column
+	}{
+		{
+			name:    "operators and MySQL style functions on PostgreSQL",
+			dialect: snapsql.DialectPostgres,
+			sql: `SELECT id, data->>'name' AS name, data->'tags' AS tags,
+  JSON_UNQUOTE(JSON_EXTRACT(data, '$.kind')) AS kind, jsonb_path_query_first(data, '$.a') AS a,
+  jsonb_extract_path_text(data, 'b') AS b FROM docs`,
+			want: []column{
+				{"id", "int", false},
+				{"name", "string", true},
+				{"tags", "json", true},
+				{"kind", "string", true},
+				{"a", "json", true},
+				{"b", "string", true},
+			},
+		},
+		{
+			name:    "JSON functions on MySQL",
+			dialect: snapsql.DialectMySQL,
+			sql:     `SELECT JSON_EXTRACT(data, '$.a') AS a, JSON_LENGTH(data) AS size, JSON_TYPE(data) AS kind FROM docs`,
+			want: []column{
+				{"a", "json", true},
+				{"size", "int", true},
+				{"kind", "string", false},
+			},
+		},
+		{
+			name:    "json_each on PostgreSQL",
+			dialect: snapsql.DialectPostgres,
+			sql:     `SELECT d.id, e.key, e.value FROM docs d CROSS JOIN jsonb_each(d.data) AS e`,
+			want: []column{
+				{"id", "int", false},
+				{"key", "string", false},
+				{"value", "json", true},
+			},
+		},
+		{
+			name:    "json_each on SQLite",
+			dialect: snapsql.DialectSQLite,
+			sql:     `SELECT d.id, e.fullkey, e.type FROM docs d CROSS JOIN json_each(d.data) AS e`,
+			want: []column{
+				{"id", "int", false},
+				{"fullkey", "string", false},
+				{"type", "string", false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql := "/*#\nfunction_name: list_docs\n*/\n" + tt.sql
+
+			format, err := GenerateFromSQL(strings.NewReader(sql), nil, "q.snap.sql", "", tables, &snapsql.Config{Dialect: tt.dialect})
+			assert.NoError(t, err)
+			assert.Equal(t, 0, len(format.Warnings), "warnings: %v", format.Warnings)
+			assert.Equal(t, len(tt.want), len(format.Responses))
+
+			for i, want := range tt.want {
+				got := format.Responses[i]
+				assert.Equal(t, want.name, got.Name)
+				assert.Equal(t, want.typ, got.Type, "type of %s", want.name)
+				assert.Equal(t, want.isNullable, got.IsNullable, "nullability of %s", want.name)
+			}
+		})
+	}
+}
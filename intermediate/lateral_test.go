@@ -0,0 +1,133 @@
+package intermediate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate/codegenerator"
+)
+
+func TestFromClauseSubqueryResponseTypes(t *testing.T) {
+	tables := map[string]*snapsql.TableInfo{
+		"users": {
+			Name: "users",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":   {Name: "id", DataType: "int", IsPrimaryKey: true},
+				"name": {Name: "name", DataType: "string"},
+			},
+		},
+		"orders": {
+			Name: "orders",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":         {Name: "id", DataType: "int", IsPrimaryKey: true},
+				"user_id":    {Name: "user_id", DataType: "int"},
+				"amount":     {Name: "amount", DataType: "decimal"},
+				"created_at": {Name: "created_at", DataType: "timestamp"},
+			},
+		},
+	}
+
+	type column struct {
+		name       string
+		typ        string
+		isNullable bool
+	}
+
+	tests := []struct {
+		name     string
+		sql      string
+		dialects []snapsql.Dialect
+		want     []column
+	}{
+		{
+			name: "derived table",
+			sql: `/*#
+function_name: order_summary
+*/
+SELECT s.user_id, s.total, s.cnt FROM (SELECT user_id, SUM(amount) AS total, COUNT(*) AS cnt FROM orders GROUP BY user_id) s`,
+			dialects: []snapsql.Dialect{snapsql.DialectPostgres, snapsql.DialectMySQL, snapsql.DialectSQLite},
+			want: []column{
+				{"user_id", "int", false},
+				{"total", "decimal", true},
+				{"cnt", "int", false},
+			},
+		},
+		{
+			name: "joined derived table",
+			sql: `/*#
+function_name: user_totals
+*/
+SELECT u.id, u.name, s.total FROM users u JOIN (SELECT user_id, SUM(amount) AS total FROM orders GROUP BY user_id) s ON s.user_id = u.id`,
+			dialects: []snapsql.Dialect{snapsql.DialectPostgres, snapsql.DialectMySQL, snapsql.DialectSQLite},
+			want: []column{
+				{"id", "int", false},
+				{"name", "string", false},
+				{"total", "decimal", true},
+			},
+		},
+		{
+			name: "cross join lateral",
+			sql: `/*#
+function_name: latest_orders
+*/
+SELECT u.id, o.id AS order_id, o.amount, o.owner FROM users u
+CROSS JOIN LATERAL (SELECT id, amount, u.name AS owner FROM orders WHERE orders.user_id = u.id ORDER BY created_at DESC LIMIT 3) o`,
+			dialects: []snapsql.Dialect{snapsql.DialectPostgres, snapsql.DialectMySQL},
+			want: []column{
+				{"id", "int", false},
+				{"order_id", "int", false},
+				{"amount", "decimal", false},
+				{"owner", "string", false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, dialect := range tt.dialects {
+			t.Run(tt.name+"/"+string(dialect), func(t *testing.T) {
+				format, err := GenerateFromSQL(strings.NewReader(tt.sql), nil, "q.snap.sql", "", tables, &snapsql.Config{Dialect: dialect})
+				assert.NoError(t, err)
+				assert.Equal(t, 0, len(format.Warnings), "warnings: %v", format.Warnings)
+
+				assert.Equal(t, len(tt.want), len(format.Responses))
+
+				for i, want := range tt.want {
+					got := format.Responses[i]
+					assert.Equal(t, want.name, got.Name)
+					assert.Equal(t, want.typ, got.Type, "type of %s", want.name)
+					assert.Equal(t, want.isNullable, got.IsNullable, "nullability of %s", want.name)
+				}
+			})
+		}
+	}
+}
+
+func TestLateralUnsupportedDialect(t *testing.T) {
+	tables := map[string]*snapsql.TableInfo{
+		"users": {
+			Name:    "users",
+			Columns: map[string]*snapsql.ColumnInfo{"id": {Name: "id", DataType: "int", IsPrimaryKey: true}},
+		},
+		"orders": {
+			Name: "orders",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"user_id": {Name: "user_id", DataType: "int"},
+				"amount":  {Name: "amount", DataType: "decimal"},
+			},
+		},
+	}
+
+	sql := `/*#
+function_name: latest_order
+*/
+SELECT u.id, o.amount FROM users u LEFT JOIN LATERAL (SELECT amount FROM orders WHERE orders.user_id = u.id LIMIT 1) o ON true`
+
+	for _, dialect := range []snapsql.Dialect{snapsql.DialectSQLite, snapsql.DialectMariaDB} {
+		t.Run(string(dialect), func(t *testing.T) {
+			_, err := GenerateFromSQL(strings.NewReader(sql), nil, "q.snap.sql", "", tables, &snapsql.Config{Dialect: dialect})
+			assert.IsError(t, err, codegenerator.ErrLateralNotSupported)
+		})
+	}
+}
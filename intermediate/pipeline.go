@@ -50,6 +50,7 @@ type ProcessingContext struct {
 	CELExpressions  []CELExpression
 	CELEnvironments []CELEnvironment
 	ExplangExprs    []ExplangExpression
+	TemplateFuncs   []TemplateFunction
 
 	// Table references extracted from the statement
 	TableReferences []TableReferenceInfo
@@ -57,6 +58,15 @@ type ProcessingContext struct {
 	// WhereMeta stores WHERE clause metadata collected during code generation.
 	WhereMeta *codegenerator.WhereClauseMeta
 
+	// OptionalSet stores the SET assignments made optional by the set_optional directive.
+	OptionalSet []OptionalSetColumn
+
+	// CursorPagination stores the keyset pagination generated for `pagination: cursor`.
+	CursorPagination *CursorPagination
+
+	// LimitGuard stores the LIMIT guard rails applied from the limits section of snapsql.yaml.
+	LimitGuard *LimitGuard
+
 	// Metadata
 	Description      string
 	FunctionName     string
@@ -102,7 +112,7 @@ func (p *TokenPipeline) Execute() (*IntermediateFormat, error) {
 	}
 
 	// Build the final intermediate format
-	responsesRaw, responseWarnings := determineResponseType(ctx.Statement, ctx.TableInfo)
+	responsesRaw, responseWarnings := determineResponseType(ctx.Statement, ctx.TableInfo, ctx.Dialect)
 	responses := applyHierarchyKeyLevels(responsesRaw, ctx.TableInfo)
 
 	if len(responses) == 0 {
@@ -116,6 +126,10 @@ func (p *TokenPipeline) Execute() (*IntermediateFormat, error) {
 		}
 	}
 
+	if err := validateScalarResponses(ctx.ResponseAffinity, responses); err != nil {
+		return nil, err
+	}
+
 	result := &IntermediateFormat{
 		FormatVersion:      "1",
 		StatementType:      determineStatementType(ctx.Statement),
@@ -124,6 +138,7 @@ func (p *TokenPipeline) Execute() (*IntermediateFormat, error) {
 		Parameters:         ctx.Parameters,
 		CELExpressions:     ctx.CELExpressions,
 		Expressions:        ctx.ExplangExprs,
+		TemplateFunctions:  ctx.TemplateFuncs,
 		CELEnvironments:    ctx.CELEnvironments,
 		Envs:               convertEnvironmentsToEnvs(ctx.Environments), // Convert environments to Envs format
 		Instructions:       ctx.Instructions,
@@ -134,6 +149,36 @@ func (p *TokenPipeline) Execute() (*IntermediateFormat, error) {
 		TableReferences:    ctx.TableReferences, // Add table references
 	}
 
+	followUp, err := buildReturningFollowUp(ctx.Statement, ctx.Dialect, ctx.TableInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	result.ReturningFollowUp = followUp
+	result.OptionalSet = ctx.OptionalSet
+
+	if err := validateCursorPagination(ctx, responses); err != nil {
+		return nil, err
+	}
+
+	if ctx.FunctionDef != nil {
+		result.ResponseType = strings.TrimSpace(ctx.FunctionDef.ResponseType)
+	}
+
+	result.CursorPagination = ctx.CursorPagination
+	result.LimitGuard = ctx.LimitGuard
+
+	bindEnumParameters(ctx.Statement, ctx.TableReferences, ctx.TableInfo, result.Parameters)
+
+	// set_optional の対象パラメータは null を受け付ける
+	for _, column := range ctx.OptionalSet {
+		for i := range result.Parameters {
+			if result.Parameters[i].Name == column.Parameter {
+				result.Parameters[i].Optional = true
+			}
+		}
+	}
+
 	if whereMeta := convertWhereClauseMeta(ctx.WhereMeta, ctx.Statement); whereMeta != nil {
 		result.WhereClauseMeta = whereMeta
 	}
@@ -269,6 +314,17 @@ func buildDMLReturningResponses(stmt parser.StatementNode, tableInfo map[string]
 			return nil, nil
 		}
 
+		return buildResponsesFromReturningClause(s.Returning, baseTable, aliasMap, tableInfo), nil
+	case *parser.MergeStatement:
+		if s.Returning == nil || s.Into == nil {
+			return nil, nil
+		}
+
+		baseTable, aliasMap := resolveTableReference(s.Into.Table, tableInfo)
+		if baseTable == "" {
+			return nil, nil
+		}
+
 		return buildResponsesFromReturningClause(s.Returning, baseTable, aliasMap, tableInfo), nil
 	default:
 		return nil, nil
@@ -357,6 +413,8 @@ func buildResponsesFromReturningClause(returning *parser.ReturningClause, baseTa
 			MaxLength:    colInfo.MaxLength,
 			Precision:    colInfo.Precision,
 			Scale:        colInfo.Scale,
+			DatabaseType: colInfo.DatabaseType,
+			Enum:         columnEnum(tblInfo, colInfo),
 			SourceTable:  tblInfo.Name,
 			SourceColumn: columnName,
 		})
@@ -426,12 +484,22 @@ func lookupColumnInfo(table *snapsql.TableInfo, column string) *snapsql.ColumnIn
 	return nil
 }
 
+// determineStatementType returns the statement type of the intermediate format. INSERT with
+// ON CONFLICT DO UPDATE / ON DUPLICATE KEY UPDATE and MERGE are "upsert": they may update
+// existing rows but are bounded by the conflict target or the ON condition, so they don't need
+// the WHERE guard of UPDATE / DELETE.
 func determineStatementType(stmt parser.StatementNode) string {
-	switch stmt.(type) {
+	switch s := stmt.(type) {
 	case *parser.SelectStatement:
 		return "select"
 	case *parser.InsertIntoStatement:
+		if s.OnConflict != nil && !s.OnConflict.IsDoNothing() {
+			return "upsert"
+		}
+
 		return "insert"
+	case *parser.MergeStatement:
+		return "upsert"
 	case *parser.UpdateStatement:
 		return "update"
 	case *parser.DeleteFromStatement:
@@ -455,7 +523,7 @@ func CreateDefaultPipeline(stmt parser.StatementNode, funcDef *parser.FunctionDe
 	return pipeline
 }
 
-// normalizeDialect returns a normalized Dialect (postgres, mysql, sqlite, mariadb)
+// normalizeDialect returns a normalized Dialect (postgres, mysql, sqlite, mariadb, duckdb, clickhouse, cockroach)
 func normalizeDialect(cfg *snapsql.Config) snapsql.Dialect {
 	if cfg == nil || cfg.Dialect == "" {
 		return snapsql.DialectPostgres
@@ -471,6 +539,12 @@ func normalizeDialect(cfg *snapsql.Config) snapsql.Dialect {
 		return snapsql.DialectSQLite
 	case "mariadb":
 		return snapsql.DialectMariaDB
+	case "duckdb":
+		return snapsql.DialectDuckDB
+	case "clickhouse":
+		return snapsql.DialectClickHouse
+	case "cockroach", "cockroachdb":
+		return snapsql.DialectCockroach
 	default:
 		return snapsql.Dialect(d)
 	}
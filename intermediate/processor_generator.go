@@ -43,6 +43,8 @@ func (i *InstructionGenerator) Process(ctx *ProcessingContext) error {
 		instructions, expressions, environments, err = codegenerator.GenerateUpdateInstructions(stmt, genCtx)
 	case *parser.DeleteFromStatement:
 		instructions, expressions, environments, err = codegenerator.GenerateDeleteInstructions(stmt, genCtx)
+	case *parser.MergeStatement:
+		instructions, expressions, environments, err = codegenerator.GenerateMergeInstructions(stmt, genCtx)
 	default:
 		return fmt.Errorf("%w: %T", errUnsupportedStatementType, ctx.Statement)
 	}
@@ -55,22 +57,34 @@ func (i *InstructionGenerator) Process(ctx *ProcessingContext) error {
 	ctx.CELExpressions = expressions
 	ctx.CELEnvironments = environments
 	ctx.WhereMeta = genCtx.WhereClauseMeta()
+	ctx.OptionalSet = genCtx.OptionalSetColumns()
+	ctx.CursorPagination = genCtx.CursorPagination()
+	ctx.LimitGuard = genCtx.LimitGuard()
 
-	stepSets, err := validateExplangExpressions(ctx.FunctionDef, expressions, ctx.CELEnvironments)
+	functions := TemplateFunctionsFromConfig(ctx.Config)
+
+	parsed, err := validateExplangExpressions(ctx.FunctionDef, expressions, ctx.CELEnvironments, functions)
 	if err != nil {
 		return err
 	}
 
-	if len(stepSets) > 0 {
+	if len(parsed) > 0 {
 		ctx.ExplangExprs = make([]ExplangExpression, len(expressions))
 		for i, expr := range expressions {
 			ctx.ExplangExprs[i] = ExplangExpression{
 				ID:               expr.ID,
 				EnvironmentIndex: expr.EnvironmentIndex,
 				Position:         expr.Position,
-				Steps:            stepSets[i],
+				Steps:            parsed[i].steps,
+			}
+
+			if call := parsed[i].call; call != nil {
+				ctx.ExplangExprs[i].Function = call.Function
+				ctx.ExplangExprs[i].Args = call.Args
 			}
 		}
+
+		ctx.TemplateFuncs = usedTemplateFunctions(ctx.Config, ctx.ExplangExprs)
 	}
 
 	ctx.Environments = append([]string(nil), genCtx.Environments...)
@@ -101,7 +115,12 @@ func resolveGenerationDialect(dialect snapsql.Dialect) snapsql.Dialect {
 		return snapsql.DialectSQLite
 	case "mariadb":
 		return snapsql.DialectMariaDB
-	case "postgres", "postgresql", "pg":
+	case "duckdb":
+		return snapsql.DialectDuckDB
+	case "clickhouse":
+		return snapsql.DialectClickHouse
+	case "postgres", "postgresql", "pg", "cockroach", "cockroachdb":
+		// CockroachDB reuses the PostgreSQL generation
 		return snapsql.DialectPostgres
 	default:
 		return snapsql.DialectPostgres
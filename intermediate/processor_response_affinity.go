@@ -1,6 +1,7 @@
 package intermediate
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 
@@ -17,6 +18,18 @@ func (r *ResponseAffinityDetector) Name() string {
 }
 
 func (r *ResponseAffinityDetector) Process(ctx *ProcessingContext) error {
+	// An affinity declared in the function definition wins over detection
+	if ctx.FunctionDef != nil && ctx.FunctionDef.ResponseAffinity != "" {
+		affinity := ResponseAffinity(ctx.FunctionDef.ResponseAffinity)
+		if affinity.IsScalar() && ctx.Statement.Type() != parser.SELECT_STATEMENT {
+			return fmt.Errorf("%w: %s requires a SELECT statement", snapsql.ErrUnsupportedResponseAffinity, affinity)
+		}
+
+		ctx.ResponseAffinity = string(affinity)
+
+		return nil
+	}
+
 	// Use existing DetermineResponseAffinity function
 	affinity := determineResponseAffinity(ctx.Statement, ctx.TableInfo)
 	ctx.ResponseAffinity = string(affinity)
@@ -36,8 +49,28 @@ const (
 
 	// ResponseAffinityNone indicates the query doesn't return any rows (e.g., INSERT, UPDATE, DELETE)
 	ResponseAffinityNone ResponseAffinity = "none"
+
+	// ResponseAffinityExists indicates the query returns a single bool column (false when no row is returned)
+	ResponseAffinityExists ResponseAffinity = "exists"
+
+	// ResponseAffinityCount indicates the query returns a single int64 column (0 when no row is returned)
+	ResponseAffinityCount ResponseAffinity = "count"
 )
 
+// IsScalar reports whether the affinity returns a scalar value instead of rows
+func (a ResponseAffinity) IsScalar() bool {
+	return a == ResponseAffinityExists || a == ResponseAffinityCount
+}
+
+// validateScalarResponses checks that exists/count queries return exactly one column
+func validateScalarResponses(affinity string, responses []Response) error {
+	if !ResponseAffinity(affinity).IsScalar() || len(responses) <= 1 {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s requires a single result column, got %d", snapsql.ErrUnsupportedResponseAffinity, affinity, len(responses))
+}
+
 // determineResponseAffinity analyzes the statement and determines the response affinity
 func determineResponseAffinity(stmt parser.StatementNode, tableInfo map[string]*snapsql.TableInfo) ResponseAffinity {
 	// Default affinity is "many" for SELECT statements
@@ -108,6 +141,15 @@ func determineResponseAffinity(stmt parser.StatementNode, tableInfo map[string]*
 			affinity = ResponseAffinityNone
 		}
 
+	case parser.MERGE_STATEMENT:
+		// MERGE with RETURNING may return any number of source rows
+		mergeStmt, ok := stmt.(*parser.MergeStatement)
+		if ok && mergeStmt.Returning != nil {
+			affinity = ResponseAffinityMany
+		} else {
+			affinity = ResponseAffinityNone
+		}
+
 	default:
 		// For other statement types, default to "none"
 		affinity = ResponseAffinityNone
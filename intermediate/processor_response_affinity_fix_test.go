@@ -66,3 +66,67 @@ SELECT id, title FROM lists WHERE id = 1`
 	aff := determineResponseAffinity(stmt, ti)
 	assert.Equal(t, ResponseAffinityOne, aff)
 }
+
+func TestAffinity_DeclaredScalarAffinity(t *testing.T) {
+	cfg := &snapsql.Config{Dialect: "postgres"}
+	ti := buildTableInfo("users", []string{"id"}, "email", "name")
+
+	t.Run("count", func(t *testing.T) {
+		sql := `/*#
+function_name: countUsers
+response_affinity: count
+*/
+SELECT COUNT(*) AS total FROM users`
+
+		format, err := GenerateFromSQL(strings.NewReader(sql), nil, "", "", ti, cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, string(ResponseAffinityCount), format.ResponseAffinity)
+	})
+
+	t.Run("exists", func(t *testing.T) {
+		sql := `/*#
+function_name: emailExists
+response_affinity: EXISTS
+parameters:
+  email: string
+*/
+SELECT 1 AS found FROM users WHERE email = /*= email */'a@example.com' LIMIT 1`
+
+		format, err := GenerateFromSQL(strings.NewReader(sql), nil, "", "", ti, cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, string(ResponseAffinityExists), format.ResponseAffinity)
+	})
+
+	t.Run("multiple columns", func(t *testing.T) {
+		sql := `/*#
+function_name: listUsers
+response_affinity: count
+*/
+SELECT id, name FROM users`
+
+		_, err := GenerateFromSQL(strings.NewReader(sql), nil, "", "", ti, cfg)
+		assert.IsError(t, err, snapsql.ErrUnsupportedResponseAffinity)
+	})
+
+	t.Run("not a select", func(t *testing.T) {
+		sql := `/*#
+function_name: deleteUsers
+response_affinity: exists
+*/
+DELETE FROM users WHERE id = 1`
+
+		_, err := GenerateFromSQL(strings.NewReader(sql), nil, "", "", ti, cfg)
+		assert.IsError(t, err, snapsql.ErrUnsupportedResponseAffinity)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		sql := `/*#
+function_name: listUsers
+response_affinity: scalar
+*/
+SELECT id FROM users`
+
+		_, err := GenerateFromSQL(strings.NewReader(sql), nil, "", "", ti, cfg)
+		assert.IsError(t, err, snapsql.ErrUnsupportedResponseAffinity)
+	})
+}
@@ -0,0 +1,94 @@
+package intermediate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql"
+)
+
+func TestRecursiveCTEResponseTypes(t *testing.T) {
+	tables := map[string]*snapsql.TableInfo{
+		"categories": {
+			Name: "categories",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":        {Name: "id", DataType: "int", IsPrimaryKey: true},
+				"parent_id": {Name: "parent_id", DataType: "int", Nullable: true},
+				"name":      {Name: "name", DataType: "string"},
+				"nickname":  {Name: "nickname", DataType: "string", Nullable: true},
+			},
+		},
+	}
+
+	type column struct {
+		name       string
+		typ        string
+		isNullable bool
+	}
+
+	tests := []struct {
+		name string
+		sql  string
+		want []column
+	}{
+		{
+			name: "anchor aliases name the columns",
+			sql: `/*#
+function_name: category_tree
+parameters:
+  root_id: int
+*/
+WITH RECURSIVE tree AS (
+  SELECT id, parent_id, name, 1 AS depth FROM categories WHERE id = /*= root_id */1
+  UNION ALL
+  SELECT c.id, c.parent_id, c.name, t.depth + 1 FROM categories c JOIN tree t ON c.parent_id = t.id
+)
+SELECT id, parent_id, name, depth FROM tree ORDER BY depth, id`,
+			want: []column{
+				{"id", "int", false},
+				{"parent_id", "int", true},
+				{"name", "string", false},
+				{"depth", "int", false},
+			},
+		},
+		{
+			name: "column list names the columns",
+			sql: `/*#
+function_name: category_labels
+*/
+WITH RECURSIVE tree (id, label, depth) AS (
+  SELECT id, name, 1 FROM categories WHERE parent_id IS NULL
+  UNION ALL
+  SELECT c.id, c.nickname, t.depth + 1 FROM categories c JOIN tree t ON c.parent_id = t.id
+)
+SELECT id, label, depth FROM tree`,
+			want: []column{
+				{"id", "int", false},
+				// 再帰側のブランチが NULL を返しうる
+				{"label", "string", true},
+				{"depth", "int", false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		for _, dialect := range []snapsql.Dialect{snapsql.DialectPostgres, snapsql.DialectMySQL, snapsql.DialectSQLite} {
+			t.Run(tt.name+"/"+string(dialect), func(t *testing.T) {
+				format, err := GenerateFromSQL(strings.NewReader(tt.sql), nil, "q.snap.sql", "", tables, &snapsql.Config{Dialect: dialect})
+				assert.NoError(t, err)
+				assert.Equal(t, 0, len(format.Warnings), "warnings: %v", format.Warnings)
+				assert.Equal(t, "select", format.StatementType)
+
+				assert.Equal(t, len(tt.want), len(format.Responses))
+
+				for i, want := range tt.want {
+					got := format.Responses[i]
+					assert.Equal(t, want.name, got.Name)
+					assert.Equal(t, want.typ, got.Type, "type of %s", want.name)
+					assert.Equal(t, want.isNullable, got.IsNullable, "nullability of %s", want.name)
+				}
+			})
+		}
+	}
+}
@@ -14,14 +14,15 @@ import (
 
 // determineResponseType analyzes the statement and determines the response type.
 // It returns the inferred responses along with warning messages.
-func determineResponseType(stmt parser.StatementNode, tableInfo map[string]*snapsql.TableInfo) ([]Response, []string) {
+func determineResponseType(stmt parser.StatementNode, tableInfo map[string]*snapsql.TableInfo, dialect snapsql.Dialect) ([]Response, []string) {
 	collector := newWarningCollector()
 
-	// Augment tableInfo with CTE/subquery derived tables
+	// Augment tableInfo with CTE/subquery derived tables and table functions
 	augmentedTableInfo := augmentTableInfoWithDerivedTables(stmt, tableInfo)
+	augmentTableInfoWithTableFunctions(stmt, augmentedTableInfo, dialect)
 
 	// First attempt with provided schema (now including derived tables)
-	schemas := convertTableInfoToSchemas(augmentedTableInfo)
+	schemas := convertTableInfoToSchemas(augmentedTableInfo, dialect)
 	inferredFields, inferWarnings, inferErr := typeinference.InferFieldTypesWithWarnings(schemas, stmt, nil)
 	collector.AddAll(inferWarnings)
 
@@ -61,6 +62,13 @@ func determineResponseType(stmt parser.StatementNode, tableInfo map[string]*snap
 			if field.Source.Type == "column" {
 				response.SourceTable = cleanIdentifier(field.Source.Table)
 				response.SourceColumn = cleanIdentifier(field.Source.Column)
+
+				if tbl := lookupTableInfo(augmentedTableInfo, response.SourceTable); tbl != nil {
+					if col := lookupColumnInfo(tbl, response.SourceColumn); col != nil {
+						response.DatabaseType = col.DatabaseType
+						response.Enum = columnEnum(tbl, col)
+					}
+				}
 			}
 
 			fields = append(fields, response)
@@ -132,6 +140,45 @@ func augmentTableInfoWithDerivedTables(stmt parser.StatementNode, tableInfo map[
 	return augmented
 }
 
+// augmentTableInfoWithTableFunctions adds the columns of the table functions (json_each etc.)
+// in the FROM clause as virtual tables.
+func augmentTableInfoWithTableFunctions(stmt parser.StatementNode, tableInfo map[string]*snapsql.TableInfo, dialect snapsql.Dialect) {
+	selectStmt, ok := stmt.(*parser.SelectStatement)
+	if !ok || selectStmt.From == nil {
+		return
+	}
+
+	for _, table := range selectStmt.From.Tables {
+		if !table.TableFunction {
+			continue
+		}
+
+		// 同名のテーブルがスキーマにある場合はそちらを優先
+		if _, exists := tableInfo[table.TableName]; exists {
+			continue
+		}
+
+		sig, ok := snapsql.LookupFunctionSignature(dialect, table.TableName)
+		if !ok || len(sig.TableColumns) == 0 {
+			continue
+		}
+
+		columns := make(map[string]*snapsql.ColumnInfo, len(sig.TableColumns))
+		columnOrder := make([]string, 0, len(sig.TableColumns))
+
+		for _, col := range sig.TableColumns {
+			columns[col.Name] = col
+			columnOrder = append(columnOrder, col.Name)
+		}
+
+		tableInfo[table.TableName] = &snapsql.TableInfo{
+			Name:        table.TableName,
+			Columns:     columns,
+			ColumnOrder: columnOrder,
+		}
+	}
+}
+
 // buildFallbackResponses synthesizes Response metadata based on SELECT clause when type inference fails.
 func buildFallbackResponses(stmt parser.StatementNode) []Response {
 	selectStmt, ok := stmt.(*parser.SelectStatement)
@@ -348,16 +395,18 @@ func inferTypeFromFieldKind(sf cmn.SelectField) string {
 	return "any"
 }
 
-// convertTableInfoToSchemas converts intermediate.TableInfo to typeinference.DatabaseSchema
-func convertTableInfoToSchemas(tableInfo map[string]*snapsql.TableInfo) []snapsql.DatabaseSchema {
+// convertTableInfoToSchemas converts intermediate.TableInfo to typeinference.DatabaseSchema.
+// The dialect selects the function signatures used by type inference.
+func convertTableInfoToSchemas(tableInfo map[string]*snapsql.TableInfo, dialect snapsql.Dialect) []snapsql.DatabaseSchema {
 	if len(tableInfo) == 0 {
 		return nil
 	}
 
 	// Create a single database schema with all tables
 	schema := snapsql.DatabaseSchema{
-		Name:   "default", // Default database name
-		Tables: make([]*snapsql.TableInfo, 0, len(tableInfo)),
+		Name:         "default", // Default database name
+		Tables:       make([]*snapsql.TableInfo, 0, len(tableInfo)),
+		DatabaseInfo: snapsql.DatabaseInfo{Type: string(dialect)},
 	}
 
 	// TableInfo is already in the correct format, just add to schema
@@ -15,7 +15,7 @@ func TestDetermineResponseType(t *testing.T) {
 	t.Run("EmptyTableInfo", func(t *testing.T) {
 		// This test would require a proper SQL statement node
 		// For now, we'll test the conversion function
-		result := convertTableInfoToSchemas(nil)
+		result := convertTableInfoToSchemas(nil, DialectPostgres)
 		assert.Equal(t, []DatabaseSchema(nil), result)
 	})
 
@@ -40,7 +40,7 @@ func TestDetermineResponseType(t *testing.T) {
 			},
 		}
 
-		schemas := convertTableInfoToSchemas(tableInfo)
+		schemas := convertTableInfoToSchemas(tableInfo, DialectPostgres)
 
 		// Verify schema structure
 		assert.Equal(t, 1, len(schemas))
@@ -87,7 +87,7 @@ func TestDetermineResponseType(t *testing.T) {
 		assert.NoError(t, err)
 
 		// Call determineResponseType with empty schema to trigger fallback any responses
-		responses, warnings := determineResponseType(stmt, nil)
+		responses, warnings := determineResponseType(stmt, nil, DialectPostgres)
 		assert.Equal(t, 3, len(responses))
 		assert.Equal(t, "parent__id", responses[0].Name)
 		assert.Equal(t, "any", responses[0].Type)
@@ -119,3 +119,57 @@ func TestFallbackFieldNamingFromSQL(t *testing.T) {
 	assert.Equal(t, "id", responses[0].Name)
 	assert.Equal(t, "name", responses[1].Name)
 }
+
+func TestDeclaredResponseType(t *testing.T) {
+	sql := `/*#
+function_name: list_users
+response_type: github.com/acme/app/models.User
+*/
+SELECT id, name FROM users`
+
+	format, err := GenerateFromSQL(strings.NewReader(sql), nil, "", "", nil, &Config{Dialect: "postgres"})
+	assert.NoError(t, err)
+	assert.Equal(t, "github.com/acme/app/models.User", format.ResponseType)
+}
+
+func TestSetOperationResponseTypes(t *testing.T) {
+	tables := map[string]*TableInfo{
+		"users": {
+			Name: "users",
+			Columns: map[string]*ColumnInfo{
+				"id":         {Name: "id", DataType: "int", IsPrimaryKey: true},
+				"name":       {Name: "name", DataType: "string"},
+				"created_at": {Name: "created_at", DataType: "date"},
+			},
+		},
+		"archived_users": {
+			Name: "archived_users",
+			Columns: map[string]*ColumnInfo{
+				"id":         {Name: "id", DataType: "decimal", IsPrimaryKey: true},
+				"name":       {Name: "name", DataType: "string", Nullable: true},
+				"created_at": {Name: "created_at", DataType: "timestamp"},
+			},
+		},
+	}
+
+	sql := `SELECT id, name, created_at FROM users
+UNION ALL
+SELECT id, name, created_at FROM archived_users
+ORDER BY id`
+
+	format, err := GenerateFromSQL(strings.NewReader(sql), nil, "report.snap.sql", "", tables, &Config{Dialect: "postgres"})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(format.Responses))
+
+	// int + decimal は decimal、NULL を返すブランチがあれば nullable
+	assert.Equal(t, "decimal", format.Responses[0].Type)
+	assert.Equal(t, "string", format.Responses[1].Type)
+	assert.True(t, format.Responses[1].IsNullable)
+	assert.Equal(t, "timestamp", format.Responses[2].Type)
+
+	// 型推論の失敗は他のクエリと同様に警告として報告される
+	sql = `SELECT id, name FROM users UNION SELECT id FROM archived_users`
+	format, err = GenerateFromSQL(strings.NewReader(sql), nil, "report.snap.sql", "", tables, &Config{Dialect: "postgres"})
+	assert.NoError(t, err)
+	assert.Contains(t, strings.Join(format.Warnings, ";"), ErrSetOperationColumnMismatch.Error())
+}
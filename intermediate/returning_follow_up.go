@@ -0,0 +1,81 @@
+package intermediate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/parser"
+	"github.com/shibukawa/snapsql/tokenizer"
+)
+
+var (
+	// ErrReturningRequiresPrimaryKey is returned when inserted rows cannot be read back on a dialect without RETURNING.
+	ErrReturningRequiresPrimaryKey = errors.New("INSERT ... RETURNING on MySQL requires a table with a single-column primary key")
+	// ErrReturningDirective is returned when the RETURNING clause of a MySQL INSERT contains template directives.
+	ErrReturningDirective = errors.New("INSERT ... RETURNING on MySQL does not support directives in the RETURNING clause")
+	// ErrReturningUpsert is returned for ON DUPLICATE KEY UPDATE ... RETURNING on MySQL.
+	ErrReturningUpsert = errors.New("INSERT ... ON DUPLICATE KEY UPDATE ... RETURNING is not supported on MySQL")
+)
+
+// buildReturningFollowUp returns the SELECT that replaces the RETURNING clause of an INSERT on MySQL.
+// Rows inserted by one statement get consecutive AUTO_INCREMENT keys starting at LAST_INSERT_ID(),
+// so the generated code selects the key range [first, first+rows affected).
+func buildReturningFollowUp(stmt parser.StatementNode, dialect snapsql.Dialect, tableInfo map[string]*snapsql.TableInfo) (*ReturningFollowUp, error) {
+	insertStmt, ok := stmt.(*parser.InsertIntoStatement)
+	if !ok || insertStmt.Returning == nil || insertStmt.Into == nil || dialect != snapsql.DialectMySQL {
+		return nil, nil
+	}
+
+	// 更新された行は LAST_INSERT_ID() の範囲に入らず、rows affected も 1 行の更新で 2 になるので
+	// 読み戻す行を特定できない
+	if insertStmt.OnConflict != nil {
+		return nil, fmt.Errorf("%w: read the row back with a separate SELECT", ErrReturningUpsert)
+	}
+
+	var columns strings.Builder
+
+	for _, token := range insertStmt.Returning.RawTokens() {
+		if token.Directive != nil {
+			return nil, ErrReturningDirective
+		}
+
+		switch token.Type {
+		case tokenizer.RETURNING, tokenizer.LINE_COMMENT, tokenizer.BLOCK_COMMENT:
+			continue
+		}
+
+		columns.WriteString(token.Value)
+	}
+
+	columnList := strings.TrimRight(strings.Join(strings.Fields(columns.String()), " "), ";")
+	if columnList == "" {
+		return nil, fmt.Errorf("%w: empty RETURNING clause", ErrReturningRequiresPrimaryKey)
+	}
+
+	table := lookupTableInfo(tableInfo, canonicalTableName(insertStmt.Into.Table, tableInfo))
+	if table == nil {
+		return nil, fmt.Errorf("%w: table %s is not in the schema", ErrReturningRequiresPrimaryKey, insertStmt.Into.Table.Name)
+	}
+
+	var keys []string
+
+	for name, column := range table.Columns {
+		if column.IsPrimaryKey {
+			keys = append(keys, name)
+		}
+	}
+
+	if len(keys) != 1 {
+		return nil, fmt.Errorf("%w: table %s has %d primary key columns", ErrReturningRequiresPrimaryKey, table.Name, len(keys))
+	}
+
+	key := keys[0]
+
+	return &ReturningFollowUp{
+		Table:     table.Name,
+		KeyColumn: key,
+		SQL:       fmt.Sprintf("SELECT %s FROM %s WHERE %s >= ? AND %s < ? ORDER BY %s", columnList, table.Name, key, key, key),
+	}, nil
+}
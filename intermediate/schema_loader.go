@@ -100,7 +100,9 @@ func LoadSchemaFromSQL(path string) (map[string]*snapsql.TableInfo, error) {
 			// Map type
 			dataType := mapSQLType(rawType)
 
-			colInfo := &snapsql.ColumnInfo{Name: colName, DataType: dataType}
+			databaseType, _, _ := strings.Cut(strings.ToLower(rawType), "(")
+
+			colInfo := &snapsql.ColumnInfo{Name: colName, DataType: dataType, DatabaseType: databaseType}
 			if strings.Contains(rest, "NOT NULL") {
 				colInfo.Nullable = false
 			} else {
@@ -24,6 +24,18 @@ type CELVariableInfo = codegenerator.CELVariableInfo
 // Position is an alias for codegenerator.Position
 type Position = codegenerator.Position
 
+// OptionalSetColumn is an alias for codegenerator.OptionalSetColumn
+type OptionalSetColumn = codegenerator.OptionalSetColumn
+
+// CursorPagination is an alias for codegenerator.CursorPagination
+type CursorPagination = codegenerator.CursorPagination
+
+// LimitGuard is an alias for codegenerator.LimitGuard
+type LimitGuard = codegenerator.LimitGuard
+
+// CursorKey is an alias for codegenerator.CursorKey
+type CursorKey = codegenerator.CursorKey
+
 // Expressions is an alias for explang.Step
 type Expressions = explang.Step
 
@@ -41,20 +53,25 @@ const (
 
 // Op constants (re-exported from codegenerator)
 const (
-	OpEmitStatic         = codegenerator.OpEmitStatic
-	OpEmitEval           = codegenerator.OpEmitEval
-	OpEmitUnlessBoundary = codegenerator.OpEmitUnlessBoundary
-	OpBoundary           = codegenerator.OpBoundary
-	OpIf                 = codegenerator.OpIf
-	OpElseIf             = codegenerator.OpElseIf
-	OpElse               = codegenerator.OpElse
-	OpEnd                = codegenerator.OpEnd
-	OpLoopStart          = codegenerator.OpLoopStart
-	OpLoopEnd            = codegenerator.OpLoopEnd
-	OpIfSystemLimit      = codegenerator.OpIfSystemLimit
-	OpIfSystemOffset     = codegenerator.OpIfSystemOffset
-	OpEmitSystemLimit    = codegenerator.OpEmitSystemLimit
-	OpEmitSystemOffset   = codegenerator.OpEmitSystemOffset
-	OpEmitSystemValue    = codegenerator.OpEmitSystemValue
-	OpEmitSystemFor      = codegenerator.OpEmitSystemFor
+	OpEmitStatic           = codegenerator.OpEmitStatic
+	OpEmitEval             = codegenerator.OpEmitEval
+	OpEmitIdent            = codegenerator.OpEmitIdent
+	OpEmitOrderBy          = codegenerator.OpEmitOrderBy
+	OpEmitUnlessBoundary   = codegenerator.OpEmitUnlessBoundary
+	OpBoundary             = codegenerator.OpBoundary
+	OpIf                   = codegenerator.OpIf
+	OpElseIf               = codegenerator.OpElseIf
+	OpElse                 = codegenerator.OpElse
+	OpEnd                  = codegenerator.OpEnd
+	OpLoopStart            = codegenerator.OpLoopStart
+	OpLoopEnd              = codegenerator.OpLoopEnd
+	OpIfSystemLimit        = codegenerator.OpIfSystemLimit
+	OpIfSystemOffset       = codegenerator.OpIfSystemOffset
+	OpEmitSystemLimit      = codegenerator.OpEmitSystemLimit
+	OpEmitSystemOffset     = codegenerator.OpEmitSystemOffset
+	OpEmitSystemValue      = codegenerator.OpEmitSystemValue
+	OpEmitSystemFor        = codegenerator.OpEmitSystemFor
+	OpEmitSystemSoftDelete = codegenerator.OpEmitSystemSoftDelete
+	OpEmitCursorCondition  = codegenerator.OpEmitCursorCondition
+	OpEmitCursorLimit      = codegenerator.OpEmitCursorLimit
 )
@@ -0,0 +1,178 @@
+package intermediate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate/codegenerator"
+)
+
+func TestUpsertStatementType(t *testing.T) {
+	tables := map[string]*snapsql.TableInfo{
+		"users": {
+			Name: "users",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":   {Name: "id", DataType: "int", IsPrimaryKey: true},
+				"name": {Name: "name", DataType: "string"},
+			},
+		},
+		"staging_users": {
+			Name: "staging_users",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":   {Name: "id", DataType: "int", IsPrimaryKey: true},
+				"name": {Name: "name", DataType: "string"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		dialect       snapsql.Dialect
+		sql           string
+		statementType string
+		sqlContains   string
+		responses     []string
+	}{
+		{
+			name:    "on conflict do update with returning",
+			dialect: "postgres",
+			sql: `/*#
+function_name: upsert_user
+parameters:
+  id: int
+  name: string
+*/
+INSERT INTO users (id, name) VALUES (/*= id */1, /*= name */'alice')
+ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name WHERE users.name <> EXCLUDED.name
+RETURNING id, name`,
+			statementType: "upsert",
+			sqlContains:   "ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name WHERE users.name <> EXCLUDED.name RETURNING id, name",
+			responses:     []string{"id", "name"},
+		},
+		{
+			name:    "on conflict do nothing stays insert",
+			dialect: "sqlite",
+			sql: `/*#
+function_name: insert_user
+parameters:
+  id: int
+  name: string
+*/
+INSERT INTO users (id, name) VALUES (/*= id */1, /*= name */'alice') ON CONFLICT DO NOTHING`,
+			statementType: "insert",
+			sqlContains:   "ON CONFLICT DO NOTHING",
+		},
+		{
+			name:    "on duplicate key update",
+			dialect: "mysql",
+			sql: `/*#
+function_name: upsert_user
+parameters:
+  id: int
+  name: string
+*/
+INSERT INTO users (id, name) VALUES (/*= id */1, /*= name */'alice') ON DUPLICATE KEY UPDATE name = VALUES(name)`,
+			statementType: "upsert",
+			sqlContains:   "ON DUPLICATE KEY UPDATE name = VALUES(name)",
+		},
+		{
+			name:    "merge",
+			dialect: "postgres",
+			sql: `/*#
+function_name: merge_users
+parameters:
+  name: string
+*/
+MERGE INTO users AS u
+USING staging_users s ON u.id = s.id
+WHEN MATCHED AND s.name = '' THEN DELETE
+WHEN MATCHED THEN UPDATE SET name = s.name
+WHEN NOT MATCHED THEN INSERT (id, name) VALUES (s.id, /*= name */'alice')
+RETURNING u.id, u.name`,
+			statementType: "upsert",
+			sqlContains:   "WHEN NOT MATCHED THEN INSERT (id, name) VALUES (s.id,",
+			responses:     []string{"id", "name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := GenerateFromSQL(strings.NewReader(tt.sql), nil, "query.snap.sql", "", tables, &snapsql.Config{Dialect: tt.dialect})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.statementType, format.StatementType)
+
+			if tt.responses != nil {
+				names := make([]string, 0, len(format.Responses))
+				for _, r := range format.Responses {
+					names = append(names, r.Name)
+				}
+
+				assert.Equal(t, tt.responses, names)
+				assert.Equal(t, "int", format.Responses[0].Type)
+			}
+
+			var sql strings.Builder
+			for _, inst := range format.Instructions {
+				if inst.Op == OpEmitStatic {
+					sql.WriteString(inst.Value)
+				}
+			}
+
+			assert.Contains(t, strings.Join(strings.Fields(sql.String()), " "), tt.sqlContains)
+		})
+	}
+}
+
+func TestUpsertDialectErrors(t *testing.T) {
+	tables := map[string]*snapsql.TableInfo{
+		"users": {
+			Name: "users",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":   {Name: "id", DataType: "int", IsPrimaryKey: true},
+				"name": {Name: "name", DataType: "string"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		dialect snapsql.Dialect
+		sql     string
+		err     error
+	}{
+		{
+			name:    "on conflict on mysql",
+			dialect: "mysql",
+			sql:     `INSERT INTO users (id, name) VALUES (1, 'alice') ON CONFLICT (id) DO UPDATE SET name = EXCLUDED.name`,
+			err:     codegenerator.ErrUpsertNotSupported,
+		},
+		{
+			name:    "on duplicate key update on postgres",
+			dialect: "postgres",
+			sql:     `INSERT INTO users (id, name) VALUES (1, 'alice') ON DUPLICATE KEY UPDATE name = VALUES(name)`,
+			err:     codegenerator.ErrUpsertNotSupported,
+		},
+		{
+			name:    "merge on sqlite",
+			dialect: "sqlite",
+			sql:     `MERGE INTO users USING users s ON users.id = s.id WHEN MATCHED THEN DELETE`,
+			err:     codegenerator.ErrUpsertNotSupported,
+		},
+		{
+			// 更新された行は LAST_INSERT_ID() では読み戻せない
+			name:    "returning of on duplicate key update on mysql",
+			dialect: "mysql",
+			sql:     `INSERT INTO users (id, name) VALUES (1, 'alice') ON DUPLICATE KEY UPDATE name = VALUES(name) RETURNING id, name`,
+			err:     ErrReturningUpsert,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GenerateFromSQL(strings.NewReader(tt.sql), nil, "query.snap.sql", "", tables, &snapsql.Config{Dialect: tt.dialect})
+			assert.IsError(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package gogen
+
+import (
+	"strings"
+
+	"github.com/shibukawa/snapsql/intermediate"
+)
+
+// batchVariantData describes the optional XxxBatch function emitted for bulk INSERT templates.
+// The batch function chunks the slice iterated by the template's loop and calls the
+// single-statement function once per chunk.
+type batchVariantData struct {
+	FunctionName  string
+	SizeConstName string
+	DefaultSize   int
+	// Collection is the Go name of the slice parameter that is split into chunks
+	Collection string
+	Parameters []parameterData
+}
+
+// buildBatchVariant returns the batch variant for INSERT templates whose VALUES rows come from a
+// single top-level `for` loop over a slice parameter. It returns nil when batching is disabled
+// (batchSize <= 0) or the template does not qualify.
+func buildBatchVariant(format *intermediate.IntermediateFormat, parameters []parameterData, funcName, responseType string, batchSize int) *batchVariantData {
+	if batchSize <= 0 || !strings.EqualFold(format.StatementType, "insert") || responseType != "sql.Result" {
+		return nil
+	}
+
+	collectionExpr := ""
+	depth := 0
+	loops := 0
+
+	for _, inst := range format.Instructions {
+		switch inst.Op {
+		case intermediate.OpLoopStart:
+			if depth == 0 {
+				loops++
+
+				if inst.CollectionExprIndex != nil && *inst.CollectionExprIndex < len(format.CELExpressions) {
+					collectionExpr = strings.TrimSpace(format.CELExpressions[*inst.CollectionExprIndex].Expression)
+				}
+			}
+
+			depth++
+		case intermediate.OpLoopEnd:
+			depth--
+		}
+	}
+
+	if loops != 1 || collectionExpr == "" {
+		return nil
+	}
+
+	for _, param := range parameters {
+		if param.OriginalName != collectionExpr || !strings.HasPrefix(param.Type, "[]") {
+			continue
+		}
+
+		return &batchVariantData{
+			FunctionName:  funcName + "Batch",
+			SizeConstName: toLowerCamel(funcName) + "BatchSize",
+			DefaultSize:   batchSize,
+			Collection:    param.Name,
+			Parameters:    parameters,
+		}
+	}
+
+	return nil
+}
@@ -23,6 +23,15 @@ type Config struct {
 	PreserveHierarchy bool   `yaml:"preserve_hierarchy"` // Whether to preserve directory hierarchy
 	MockPath          string `yaml:"mock_path"`          // Base path for mock data files
 	GenerateTests     bool   `yaml:"generate_tests"`     // Whether to generate test files
+	BatchSize         int    `yaml:"batch_size"`         // Rows per statement of XxxBatch functions for bulk INSERT templates (0 disables them)
+	Tracing           bool   `yaml:"tracing"`            // Wrap generated functions in OpenTelemetry spans
+	Transactions      bool   `yaml:"transactions"`       // Apply transaction propagation policies and generate the RunInTx helper
+	// ShareResponseTypes makes templates with identical response shapes share one struct (see ShareResponseTypes)
+	ShareResponseTypes bool `yaml:"share_response_types"`
+	// NullableStyle selects the type of nullable response fields: pointer (default), sqlnull or option
+	NullableStyle string `yaml:"nullable_style"`
+	// HierarchyMode selects where hierarchical responses are aggregated: rows (default) or json
+	HierarchyMode string `yaml:"hierarchy_mode"`
 }
 
 // DefaultConfig returns default configuration for Go generator
@@ -76,6 +85,11 @@ func WithConfig(config Config, outputPath string) Option {
 		if config.MockPath != "" {
 			g.MockPath = config.MockPath
 		}
+
+		g.BatchSize = config.BatchSize
+		g.Tracing = config.Tracing
+		g.NullableStyle = config.NullableStyle
+		g.HierarchyMode = config.HierarchyMode
 		// GenerateTests and PreserveHierarchy will be added in future versions
 	}
 }
@@ -91,6 +105,11 @@ func WithConfig(config Config, outputPath string) Option {
 //     preserve_hierarchy: true        # Optional: default true
 //     mock_path: "./testdata/mocks"   # Optional
 //     generate_tests: true            # Optional: default false
+//     batch_size: 500                 # Optional: emit XxxBatch for bulk INSERT templates
+//     tracing: true                   # Optional: wrap functions in OpenTelemetry spans
+//     share_response_types: true      # Optional: one struct for identical response shapes
+//     nullable_style: option          # Optional: pointer (default), sqlnull or option
+//     hierarchy_mode: json            # Optional: aggregate parent__child responses in the database
 //
 // Auto-inference examples:
 // output: "./internal/queries"     -> package: "queries"
@@ -0,0 +1,108 @@
+package gogen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shibukawa/snapsql/intermediate"
+)
+
+// cursorAfterParamName is the Go parameter that carries the cursor of the previous page.
+const cursorAfterParamName = "after"
+
+// cursorPageData describes the page function generated for `pagination: cursor`.
+type cursorPageData struct {
+	// ItemType is the response struct collected into the page
+	ItemType string
+	// Limit is the Go expression of the page size
+	Limit string
+	// Keys are the Go expressions reading the ORDER BY key values of a row
+	Keys []string
+}
+
+// cursorPageLimit returns the Go expression of the page size. A parameterized LIMIT must be a
+// single integer parameter because the page function trims the extra row with it.
+func cursorPageLimit(format *intermediate.IntermediateFormat) (string, error) {
+	pagination := format.CursorPagination
+	if pagination.LimitExprIndex == nil {
+		return strconv.Itoa(pagination.Limit), nil
+	}
+
+	index := *pagination.LimitExprIndex
+	if index < 0 || index >= len(format.CELExpressions) {
+		return "", fmt.Errorf("%w: cursor pagination limit expression %d is out of range", ErrGenerateGoCode, index)
+	}
+
+	expression := strings.TrimSpace(format.CELExpressions[index].Expression)
+	for _, param := range format.Parameters {
+		if param.Name != expression {
+			continue
+		}
+
+		goType, err := convertToGoType(param.Type)
+		if err != nil || (goType != "int" && goType != "int32" && goType != "int64") {
+			return "", fmt.Errorf("%w: cursor pagination limit parameter %q must be an integer", ErrGenerateGoCode, param.Name)
+		}
+
+		return snakeToCamelLower(param.Name), nil
+	}
+
+	return "", fmt.Errorf("%w: cursor pagination limit %q must be a parameter", ErrGenerateGoCode, expression)
+}
+
+// applyCursorPage appends the after parameter that receives the NextCursor of the previous page.
+func applyCursorPage(format *intermediate.IntermediateFormat, params []parameterData) ([]parameterData, error) {
+	if format.CursorPagination == nil {
+		return params, nil
+	}
+
+	for _, param := range params {
+		if param.Name == cursorAfterParamName {
+			return nil, fmt.Errorf("%w: parameter %q conflicts with the cursor pagination parameter", ErrGenerateGoCode, param.OriginalName)
+		}
+	}
+
+	return append(params, parameterData{
+		Name:         cursorAfterParamName,
+		OriginalName: cursorAfterParamName,
+		Type:         "string",
+	}), nil
+}
+
+// buildCursorPage resolves the page size and the response fields holding the cursor keys.
+func buildCursorPage(format *intermediate.IntermediateFormat, responseStruct *responseStructData) (*cursorPageData, error) {
+	if format.CursorPagination == nil {
+		return nil, nil
+	}
+
+	if responseStruct == nil {
+		return nil, fmt.Errorf("%w: cursor pagination of %s requires a response struct", ErrGenerateGoCode, format.FunctionName)
+	}
+
+	limit, err := cursorPageLimit(format)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &cursorPageData{ItemType: responseStruct.Name, Limit: limit}
+
+	for _, key := range format.CursorPagination.Keys {
+		found := false
+
+		for _, field := range responseStruct.Fields {
+			if field.JSONTag == key.Field {
+				page.Keys = append(page.Keys, "row."+field.Name)
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("%w: cursor key %q is not a response field of %s", ErrGenerateGoCode, key.Field, format.FunctionName)
+		}
+	}
+
+	return page, nil
+}
@@ -30,7 +30,7 @@ func TestEmitUnlessBoundaryWithParenthesis(t *testing.T) {
 		},
 	}
 
-	sqlData, err := processSQLBuilderWithDialect(format, "postgres", "TestQuery")
+	sqlData, err := processSQLBuilderWithDialect(format, "postgres", "TestQuery", nil)
 	if err != nil {
 		t.Fatalf("Failed to process SQL builder: %v", err)
 	}
@@ -77,7 +77,7 @@ func TestEmitUnlessBoundaryWithClosingParenthesis(t *testing.T) {
 		},
 	}
 
-	sqlData, err := processSQLBuilderWithDialect(format, "postgres", "TestQuery")
+	sqlData, err := processSQLBuilderWithDialect(format, "postgres", "TestQuery", nil)
 	if err != nil {
 		t.Fatalf("Failed to process SQL builder: %v", err)
 	}
@@ -0,0 +1,189 @@
+package gogen
+
+import (
+	"fmt"
+	"go/format"
+	"io"
+	"slices"
+	"strings"
+	"text/template"
+	"unicode"
+
+	"github.com/shibukawa/snapsql/intermediate"
+)
+
+// EnumsFileName is the per-package file holding the Go types of the database enums used by the
+// generated functions.
+const EnumsFileName = "snapsql_enums.go"
+
+// CollectEnums returns the enums used by the response fields and parameters of formats, sorted by
+// name. An enum used by several templates is returned once.
+func CollectEnums(formats []*intermediate.IntermediateFormat) []intermediate.Enum {
+	var enums []intermediate.Enum
+
+	add := func(enum *intermediate.Enum) {
+		if enum == nil || slices.ContainsFunc(enums, func(e intermediate.Enum) bool { return e.Name == enum.Name }) {
+			return
+		}
+
+		enums = append(enums, *enum)
+	}
+
+	for _, format := range formats {
+		for _, param := range format.Parameters {
+			add(param.Enum)
+		}
+
+		for _, response := range format.Responses {
+			add(response.Enum)
+		}
+	}
+
+	slices.SortFunc(enums, func(a, b intermediate.Enum) int { return strings.Compare(a.Name, b.Name) })
+
+	return enums
+}
+
+// usesEnums reports whether a parameter or response field of format is a database enum
+func usesEnums(format *intermediate.IntermediateFormat) bool {
+	return slices.ContainsFunc(format.Parameters, func(p intermediate.Parameter) bool { return p.Enum != nil }) ||
+		slices.ContainsFunc(format.Responses, func(r intermediate.Response) bool { return r.Enum != nil })
+}
+
+// enumTypeName returns the Go type generated for an enum, e.g. task_status -> TaskStatus
+func enumTypeName(enum intermediate.Enum) string {
+	return snakeToCamel(goIdentifierWords(enum.Name))
+}
+
+// enumFieldType returns the type of a string (or string list) field holding enum values
+func enumFieldType(snapType string, enum *intermediate.Enum) string {
+	if enum == nil {
+		return snapType
+	}
+
+	switch strings.ToLower(snapType) {
+	case "string":
+		return enumTypeName(*enum)
+	case "string[]":
+		return enumTypeName(*enum) + "[]"
+	}
+
+	return snapType
+}
+
+// goIdentifierWords replaces the characters that cannot appear in a Go identifier with "_"
+func goIdentifierWords(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+
+		return '_'
+	}, value)
+}
+
+type enumData struct {
+	TypeName string
+	Name     string
+	Values   []enumValueData
+}
+
+type enumValueData struct {
+	Name  string
+	Value string
+}
+
+// newEnumData names the constants of an enum after its values: TaskStatus + "in-progress" ->
+// TaskStatusInProgress. Values that give no name or a duplicated one are numbered.
+func newEnumData(enum intermediate.Enum) enumData {
+	data := enumData{TypeName: enumTypeName(enum), Name: enum.Name}
+
+	for i, value := range enum.Values {
+		suffix := strings.ReplaceAll(snakeToCamel(strings.ToLower(goIdentifierWords(value))), "_", "")
+		if suffix == "" || !unicode.IsLetter(rune(suffix[0])) {
+			suffix = fmt.Sprintf("Value%d", i+1)
+		}
+
+		name := data.TypeName + suffix
+		if slices.ContainsFunc(data.Values, func(v enumValueData) bool { return v.Name == name }) {
+			name = fmt.Sprintf("%sValue%d", data.TypeName, i+1)
+		}
+
+		data.Values = append(data.Values, enumValueData{Name: name, Value: fmt.Sprintf("%q", value)})
+	}
+
+	return data
+}
+
+// GenerateEnums writes the per-package Go types of enums: a string type per enum with a constant
+// per value, and helpers that list, validate and parse its values.
+func (g *Generator) GenerateEnums(w io.Writer, enums []intermediate.Enum) error {
+	data := struct {
+		PackageName string
+		Enums       []enumData
+	}{PackageName: g.PackageName}
+
+	for _, enum := range enums {
+		data.Enums = append(data.Enums, newEnumData(enum))
+	}
+
+	var buf strings.Builder
+	if err := enumsTemplate.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute enums template: %w", err)
+	}
+
+	formatted, err := format.Source([]byte(buf.String()))
+	if err != nil {
+		return fmt.Errorf("failed to format enums: %w", err)
+	}
+
+	_, err = w.Write(formatted)
+
+	return err
+}
+
+var enumsTemplate = template.Must(template.New("enums").Parse(`// Code generated by snapsql. DO NOT EDIT.
+
+package {{ .PackageName }}
+
+import (
+	"fmt"
+
+	"github.com/shibukawa/snapsql/langs/snapsqlgo"
+)
+{{ range $enum := .Enums }}
+// {{ .TypeName }} is the database enum {{ .Name }}.
+type {{ .TypeName }} string
+
+const (
+{{- range .Values }}
+	{{ .Name }} {{ $enum.TypeName }} = {{ .Value }}
+{{- end }}
+)
+
+// {{ .TypeName }}Values returns the values of {{ .TypeName }} in the order the database defines them.
+func {{ .TypeName }}Values() []{{ .TypeName }} {
+	return []{{ .TypeName }}{ {{- range $i, $v := .Values }}{{ if $i }}, {{ end }}{{ $v.Name }}{{ end -}} }
+}
+
+// Valid reports whether v is one of the values of {{ .TypeName }}.
+func (v {{ .TypeName }}) Valid() bool {
+	switch v {
+	case {{ range $i, $v := .Values }}{{ if $i }}, {{ end }}{{ $v.Name }}{{ end }}:
+		return true
+	}
+
+	return false
+}
+
+// Parse{{ .TypeName }} converts s to {{ .TypeName }}, failing with snapsqlgo.ErrInvalidEnumValue
+// when s is not one of its values.
+func Parse{{ .TypeName }}(s string) ({{ .TypeName }}, error) {
+	v := {{ .TypeName }}(s)
+	if !v.Valid() {
+		return "", fmt.Errorf("%w: %q is not a {{ .TypeName }}", snapsqlgo.ErrInvalidEnumValue, s)
+	}
+
+	return v, nil
+}
+{{ end }}`))
@@ -0,0 +1,67 @@
+package gogen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+)
+
+func TestGenerateEnums(t *testing.T) {
+	status := &intermediate.Enum{Name: "task_status", Values: []string{"todo", "in-progress", "done"}}
+
+	format := userQuery("list_tasks", "many")
+	format.Parameters = []intermediate.Parameter{{Name: "status", Type: "string", Enum: status}}
+	format.Responses = []intermediate.Response{
+		{Name: "id", Type: "int"},
+		{Name: "status", Type: "string", Enum: status},
+		{Name: "priority", Type: "string", IsNullable: true, DatabaseType: "enum", Enum: &intermediate.Enum{Name: "tasks_priority", Values: []string{"low", "high"}}},
+	}
+
+	var out strings.Builder
+
+	generator := &Generator{
+		PackageName:  "testgen",
+		Format:       format,
+		Dialect:      "postgres",
+		TypeMappings: []snapsql.TypeMappingConfig{{Column: "priority", Go: "string"}},
+	}
+	if err := generator.Generate(&out); err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	code := out.String()
+	for _, want := range []string{
+		"status TaskStatus, opts ...snapsqlgo.FuncOpt",
+		"Status   TaskStatus",
+		"Priority *string",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code does not contain %q\n%s", want, code)
+		}
+	}
+
+	enums := CollectEnums([]*intermediate.IntermediateFormat{format, format})
+	if len(enums) != 2 || enums[0].Name != "task_status" || enums[1].Name != "tasks_priority" {
+		t.Fatalf("unexpected enums: %v", enums)
+	}
+
+	var enumsCode strings.Builder
+	if err := generator.GenerateEnums(&enumsCode, enums); err != nil {
+		t.Fatalf("GenerateEnums returned error: %v", err)
+	}
+
+	for _, want := range []string{
+		"package testgen",
+		"type TaskStatus string",
+		`TaskStatusInProgress TaskStatus = "in-progress"`,
+		"func (v TaskStatus) Valid() bool",
+		"func ParseTaskStatus(s string) (TaskStatus, error)",
+		"func TasksPriorityValues() []TasksPriority",
+	} {
+		if !strings.Contains(enumsCode.String(), want) {
+			t.Errorf("enums do not contain %q\n%s", want, enumsCode.String())
+		}
+	}
+}
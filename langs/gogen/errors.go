@@ -2,5 +2,21 @@ package gogen
 
 import "errors"
 
-// ErrGenerateGoCode is returned when Go code generation encounters unrecoverable metadata issues.
-var ErrGenerateGoCode = errors.New("gogen: generate go code failure")
+var (
+	// ErrGenerateGoCode is returned when Go code generation encounters unrecoverable metadata issues.
+	ErrGenerateGoCode = errors.New("gogen: generate go code failure")
+	// ErrUnknownTemplateFunction is returned when an expression calls a function missing from the intermediate format.
+	ErrUnknownTemplateFunction = errors.New("gogen: unknown template function")
+	// ErrInvalidResponseType is returned when response_type is not a usable Go type for the template.
+	ErrInvalidResponseType = errors.New("gogen: invalid response_type")
+	// ErrInvalidNullableStyle is returned when nullable_style is not pointer, sqlnull or option.
+	ErrInvalidNullableStyle = errors.New("gogen: invalid nullable_style")
+	// ErrInvalidHierarchyMode is returned when hierarchy_mode is not rows or json.
+	ErrInvalidHierarchyMode = errors.New("gogen: invalid hierarchy_mode")
+	// ErrInvalidQueryComment is returned when query_comment could end the comment or add a placeholder to the SQL.
+	ErrInvalidQueryComment = errors.New("gogen: invalid query_comment")
+	// ErrInvalidExecutionPolicy is returned when the execution policy of snapsql.yaml cannot apply to the template.
+	ErrInvalidExecutionPolicy = errors.New("gogen: invalid execution policy")
+	// ErrJSONHierarchyUnsupported is returned when a hierarchical response cannot be aggregated into JSON.
+	ErrJSONHierarchyUnsupported = errors.New("gogen: json hierarchy unsupported")
+)
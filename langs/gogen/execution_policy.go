@@ -0,0 +1,78 @@
+package gogen
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shibukawa/snapsql"
+)
+
+// executionPolicyLiteral renders the execution policy declared in snapsql.yaml as the
+// snapsqlgo.ExecutionPolicy literal passed to ResolveExecutionPolicy
+func executionPolicyLiteral(policy snapsql.ExecutionPolicy) string {
+	var fields []string
+
+	if policy.Timeout > 0 {
+		fields = append(fields, "Timeout: "+durationLiteral(policy.Timeout))
+	}
+
+	if policy.MaxRows > 0 {
+		fields = append(fields, fmt.Sprintf("MaxRows: %d", policy.MaxRows))
+	}
+
+	if policy.ReadOnly {
+		fields = append(fields, "ReadOnly: true")
+	}
+
+	return "snapsqlgo.ExecutionPolicy{" + strings.Join(fields, ", ") + "}"
+}
+
+// durationLiteral renders d as a Go expression of the time package (2 * time.Second)
+func durationLiteral(d time.Duration) string {
+	switch {
+	case d%time.Second == 0:
+		return fmt.Sprintf("%d * time.Second", d/time.Second)
+	case d%time.Millisecond == 0:
+		return fmt.Sprintf("%d * time.Millisecond", d/time.Millisecond)
+	default:
+		return fmt.Sprintf("time.Duration(%d)", int64(d))
+	}
+}
+
+// validateExecutionPolicy rejects read_only for templates that modify data; such a function could never succeed
+func validateExecutionPolicy(policy snapsql.ExecutionPolicy, functionName string, isSelectQuery bool) error {
+	if policy.ReadOnly && !isSelectQuery {
+		return fmt.Errorf("%w: %s modifies data but read_only is set", ErrInvalidExecutionPolicy, functionName)
+	}
+
+	return nil
+}
+
+// rowGuard tells the scan code generators whether generated rows.Next loops count the rows
+// against the max_rows execution policy. It is enabled only for functions that have a policy.
+type rowGuard bool
+
+// init returns the counter declaration placed before the rows.Next loop
+func (g rowGuard) init() []string {
+	if !g {
+		return nil
+	}
+
+	return []string{"rowCount := 0"}
+}
+
+// check returns the lines placed at the top of the rows.Next loop that fail the query once it
+// returns more rows than the policy allows. onError is the statement returning err.
+func (g rowGuard) check(indent, onError string) []string {
+	if !g {
+		return nil
+	}
+
+	return []string{
+		indent + "rowCount++",
+		indent + "if err := execPolicy.CheckRows(rowCount); err != nil {",
+		indent + "\t" + onError,
+		indent + "}",
+	}
+}
@@ -19,6 +19,7 @@ type expressionRenderer struct {
 	scope       *expressionScope
 	exprs       []intermediate.ExplangExpression
 	celExprs    []intermediate.CELExpression
+	functions   []intermediate.TemplateFunction
 	tempCounter int
 }
 
@@ -30,9 +31,10 @@ type renderedAccess struct {
 
 func newExpressionRenderer(format *intermediate.IntermediateFormat, scope *expressionScope) *expressionRenderer {
 	return &expressionRenderer{
-		scope:    scope,
-		exprs:    format.Expressions,
-		celExprs: format.CELExpressions,
+		scope:     scope,
+		exprs:     format.Expressions,
+		celExprs:  format.CELExpressions,
+		functions: format.TemplateFunctions,
 	}
 }
 
@@ -45,6 +47,10 @@ func (r *expressionRenderer) renderIterable(index int) (*renderedAccess, error)
 }
 
 func (r *expressionRenderer) render(index int, mode expressionMode) (*renderedAccess, error) {
+	if index >= 0 && index < len(r.exprs) && r.exprs[index].Function != "" {
+		return r.renderFromCall(index)
+	}
+
 	if index >= 0 && index < len(r.exprs) && len(r.exprs[index].Steps) > 0 {
 		return r.renderFromSteps(index, mode)
 	}
@@ -58,7 +64,59 @@ func (r *expressionRenderer) renderFromSteps(index int, mode expressionMode) (*r
 		panic(fmt.Sprintf("explang expression %d has no steps", index))
 	}
 
-	rootName := expr.Steps[0].Identifier
+	return r.renderSteps(expr.Steps), nil
+}
+
+// renderFromCall はテンプレート関数の呼び出しを Go の関数呼び出しとして出力する
+// 引数のいずれかが安全アクセスで解決できなかった場合、結果は nil になる
+func (r *expressionRenderer) renderFromCall(index int) (*renderedAccess, error) {
+	expr := r.exprs[index]
+
+	fn, ok := findTemplateFunction(r.functions, expr.Function)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTemplateFunction, expr.Function)
+	}
+
+	plan := &renderedAccess{}
+
+	var (
+		args     []string
+		validity []string
+	)
+
+	for _, argSteps := range expr.Args {
+		arg := r.renderSteps(argSteps)
+		plan.Setup = append(plan.Setup, arg.Setup...)
+		args = append(args, arg.ValueVar)
+
+		if arg.ValidVar != "" {
+			validity = append(validity, arg.ValidVar)
+		}
+	}
+
+	call := fmt.Sprintf("%s(%s)", templateFunctionReference(fn), strings.Join(args, ", "))
+	plan.ValueVar = r.nextTempVar("call")
+
+	if len(validity) == 0 {
+		plan.Setup = append(plan.Setup, fmt.Sprintf("%s := %s", plan.ValueVar, call))
+		return plan, nil
+	}
+
+	plan.ValidVar = r.nextTempVar("ok")
+	plan.Setup = append(plan.Setup,
+		fmt.Sprintf("%s := %s", plan.ValidVar, strings.Join(validity, " && ")),
+		fmt.Sprintf("var %s any", plan.ValueVar),
+		fmt.Sprintf("if %s {", plan.ValidVar),
+		fmt.Sprintf("\t%s = %s", plan.ValueVar, call),
+		"}",
+	)
+
+	return plan, nil
+}
+
+// renderSteps はアクセスパスを評価するコードを出力する
+func (r *expressionRenderer) renderSteps(expressionSteps []intermediate.Expressions) *renderedAccess {
+	rootName := expressionSteps[0].Identifier
 
 	goName, ok := r.scope.lookup(rootName)
 	if !ok {
@@ -67,7 +125,7 @@ func (r *expressionRenderer) renderFromSteps(index int, mode expressionMode) (*r
 
 	plan := &renderedAccess{}
 	valueVar := goName
-	steps := expr.Steps[1:]
+	steps := expressionSteps[1:]
 
 	if len(steps) > 0 {
 		tmp := r.nextTempVar("tmp")
@@ -133,7 +191,7 @@ func (r *expressionRenderer) renderFromSteps(index int, mode expressionMode) (*r
 		}
 	}
 
-	return plan, nil
+	return plan
 }
 
 func (r *expressionRenderer) renderFromCEL(index int) (*renderedAccess, error) {
@@ -23,13 +23,33 @@ import (
 
 // Generator generates Go code from intermediate format
 type Generator struct {
-	PackageName       string
-	OutputPath        string
-	Format            *intermediate.IntermediateFormat
-	MockPath          string
-	Dialect           snapsql.Dialect         // Target database dialect (postgres, mysql, sqlite, mariadb)
-	Hierarchy         *FileHierarchy          // File hierarchy information (optional)
-	BaseImport        string                  // Base import path for hierarchical packages
+	PackageName    string
+	OutputPath     string
+	Format         *intermediate.IntermediateFormat
+	MockPath       string
+	Dialect        snapsql.Dialect          // Target database dialect (postgres, mysql, sqlite, mariadb)
+	Hierarchy      *FileHierarchy           // File hierarchy information (optional)
+	BaseImport     string                   // Base import path for hierarchical packages
+	BatchSize      int                      // Default chunk size of XxxBatch functions for bulk INSERT templates (0 disables them)
+	Tracing        bool                     // Wrap generated functions in OpenTelemetry spans
+	RedactParams   []string                 // Parameter names whose argument values are masked in query logs
+	Transactions   bool                     // Apply the transaction propagation policy (snapsqlgo.WithTxPropagation) in generated functions
+	WhereGuard     snapsql.WhereGuardConfig // WHERE guard policy embedded in UPDATE/DELETE functions
+	SourcePath     string                   // Template path recorded in source maps and //line directives
+	OutputFile     string                   // Generated file name that //line directives switch back to
+	LineDirectives bool                     // Emit //line directives pointing the SQL building code at the template
+	// SharedResponseTypes maps function names to the response struct they share (see ShareResponseTypes)
+	SharedResponseTypes map[string]string
+	// TypeMappings selects Go types for response columns by database type or column name (type_mappings)
+	TypeMappings []snapsql.TypeMappingConfig
+	// NullableStyle selects the type of nullable response fields: pointer (default), sqlnull or option
+	NullableStyle string
+	// HierarchyMode selects where hierarchical responses are aggregated: rows (default, in Go) or json (in the database)
+	HierarchyMode string
+	// QueryComment is the format of the comment prepended to the executed SQL ({func}, {name}, {package}); empty disables it
+	QueryComment string
+	// Execution declares the per-function execution policies (timeout, max rows, read-only) baked into generated functions
+	Execution         snapsql.ExecutionConfig
 	hierarchicalMetas []*hierarchicalNodeMeta // internal: prepared metas for hierarchical aggregation
 }
 
@@ -63,6 +83,20 @@ func WithPackageName(name string) Option {
 	}
 }
 
+// WithRedactParams sets the parameter names whose argument values are masked in query logs
+func WithRedactParams(names ...string) Option {
+	return func(g *Generator) {
+		g.RedactParams = names
+	}
+}
+
+// WithWhereGuard sets the WHERE guard policy embedded in generated UPDATE/DELETE functions
+func WithWhereGuard(config snapsql.WhereGuardConfig) Option {
+	return func(g *Generator) {
+		g.WhereGuard = config
+	}
+}
+
 // WithDialect sets the target database dialect
 func WithDialect(dialect snapsql.Dialect) Option {
 	return func(g *Generator) {
@@ -175,6 +209,9 @@ func runtimeWhereStatusLiteral(status string) string {
 	}
 }
 
+// mutationKindFromStatementType returns the mutation kind checked by the WHERE guard. "upsert"
+// (ON CONFLICT DO UPDATE / ON DUPLICATE KEY UPDATE / MERGE) is bounded by the conflict target
+// or the ON condition, so it has no guard.
 func mutationKindFromStatementType(stmtType string) string {
 	switch strings.ToLower(stmtType) {
 	case "update":
@@ -186,18 +223,79 @@ func mutationKindFromStatementType(stmtType string) string {
 	}
 }
 
+// whereGuardPolicy returns the snapsqlgo.WhereGuardPolicy literal passed to EnforceNonEmptyWhereClause
+// (empty for the default policy) and the import path of the audit function.
+func whereGuardPolicy(config snapsql.WhereGuardConfig, functionName string) (policy string, importPath string) {
+	var fields []string
+
+	if config.Mode == "warn" {
+		fields = append(fields, "Mode: snapsqlgo.WhereGuardModeWarn")
+	}
+
+	if config.Allows(functionName) {
+		fields = append(fields, "Allowed: true")
+	}
+
+	if config.Audit != "" {
+		auditImport, name := config.AuditFunction()
+		if auditImport != "" {
+			name = templateFunctionPackageName(auditImport) + "." + name
+		}
+
+		fields = append(fields, "Audit: "+name)
+		importPath = auditImport
+	}
+
+	if len(fields) == 0 {
+		return "", ""
+	}
+
+	return "snapsqlgo.WhereGuardPolicy{" + strings.Join(fields, ", ") + "}", importPath
+}
+
 // Generate generates Go code and writes it to the writer
 func (g *Generator) Generate(w io.Writer) error {
+	_, err := g.GenerateWithSourceMap(w)
+	return err
+}
+
+// GenerateWithSourceMap generates Go code, writes it to the writer and returns the source map
+// linking the SQL building code to the template positions of its instructions
+func (g *Generator) GenerateWithSourceMap(w io.Writer) (*SourceMap, error) {
 	// Reset per-file state to avoid leaking hierarchical metas across files
 	g.hierarchicalMetas = nil
 
+	// type_mappings replace the Go types of matching response columns while generating this file
+	mappedFormat, typeImports := mapResponseTypes(g.Format, g.TypeMappings)
+
+	original := g.Format
+	g.Format = mappedFormat
+
+	defer func() { g.Format = original }()
+
 	// Build explang expressions for downstream consumers
 	explangExprs := buildExplangExpressionData(g.Format)
 
 	// Process parameters
 	parameters, structDefinitions, err := processParameters(g.Format.Parameters, g.Format.FunctionName)
 	if err != nil {
-		return fmt.Errorf("failed to process parameters: %w", err)
+		return nil, fmt.Errorf("failed to process parameters: %w", err)
+	}
+
+	// set_optional columns are passed through a single options struct
+	parameters, optionalSetStruct, err := applyOptionalSet(g.Format, snakeToCamel(g.Format.FunctionName), parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	if optionalSetStruct != "" {
+		structDefinitions = append(structDefinitions, optionalSetStruct)
+	}
+
+	// pagination: cursor receives the cursor of the previous page
+	parameters, err = applyCursorPage(g.Format, parameters)
+	if err != nil {
+		return nil, err
 	}
 
 	// Note: Loop variables and other CEL environment variables are NOT function parameters
@@ -206,35 +304,60 @@ func (g *Generator) Generate(w io.Writer) error {
 	// Process response type
 	responseType, err := processResponseType(g.Format)
 	if err != nil {
-		return fmt.Errorf("failed to process response type: %w", err)
+		return nil, fmt.Errorf("failed to process response type: %w", err)
 	}
 
+	// exists/count affinities return a scalar directly, so no response struct is generated
+	scalarResponse := intermediate.ResponseAffinity(strings.ToLower(g.Format.ResponseAffinity)).IsScalar()
+
 	// Process response struct
-	responseStruct, err := processResponseStruct(g.Format)
-	if err != nil {
-		if errors.Is(err, ErrNoResponseFields) {
-			if !strings.EqualFold(g.Format.ResponseAffinity, string(intermediate.ResponseAffinityNone)) && len(g.Format.Responses) > 0 {
-				return fmt.Errorf("%w: function %s requires response struct metadata; ensure table definitions exist", ErrGenerateGoCode, g.Format.FunctionName)
+	var responseStruct *responseStructData
+	if !scalarResponse {
+		responseStruct, err = processResponseStruct(g.Format)
+		if err != nil {
+			if errors.Is(err, ErrNoResponseFields) {
+				if !strings.EqualFold(g.Format.ResponseAffinity, string(intermediate.ResponseAffinityNone)) && len(g.Format.Responses) > 0 {
+					return nil, fmt.Errorf("%w: function %s requires response struct metadata; ensure table definitions exist", ErrGenerateGoCode, g.Format.FunctionName)
+				}
+			} else {
+				return nil, fmt.Errorf("failed to process response struct: %w", err)
 			}
-		} else {
-			return fmt.Errorf("failed to process response struct: %w", err)
 		}
+
+		if responseStruct == nil && len(g.Format.Responses) > 0 && !strings.EqualFold(g.Format.ResponseAffinity, string(intermediate.ResponseAffinityNone)) {
+			return nil, fmt.Errorf("%w: function %s requires response struct metadata; ensure table definitions exist", ErrGenerateGoCode, g.Format.FunctionName)
+		}
+
+		if err := validateNullableStyle(g.NullableStyle); err != nil {
+			return nil, err
+		}
+
+		applyNullableStyle(responseStruct, g.NullableStyle)
 	}
 
-	if responseStruct == nil && len(g.Format.Responses) > 0 && !strings.EqualFold(g.Format.ResponseAffinity, string(intermediate.ResponseAffinityNone)) {
-		return fmt.Errorf("%w: function %s requires response struct metadata; ensure table definitions exist", ErrGenerateGoCode, g.Format.FunctionName)
+	responseType, responseImport, err := g.applyResponseType(responseType, responseStruct)
+	if err != nil {
+		return nil, err
 	}
 
-	// Generate hierarchical structs if needed
-	hierarchicalGroups, _, err := detectHierarchicalStructure(g.Format.Responses)
+	cursorPage, err := buildCursorPage(g.Format, responseStruct)
 	if err != nil {
-		return fmt.Errorf("failed to detect hierarchical structure: %w", err)
+		return nil, err
+	}
+
+	// Generate hierarchical structs if needed
+	var hierarchicalGroups map[string]*node
+	if !scalarResponse {
+		hierarchicalGroups, _, err = detectHierarchicalStructure(g.Format.Responses)
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect hierarchical structure: %w", err)
+		}
 	}
 
 	if len(hierarchicalGroups) > 0 {
 		hierarchicalStructs, _, err := generateHierarchicalStructs(g.Format.FunctionName, hierarchicalGroups, nil)
 		if err != nil {
-			return fmt.Errorf("failed to generate hierarchical structs: %w", err)
+			return nil, fmt.Errorf("failed to generate hierarchical structs: %w", err)
 		}
 
 		structDefinitions = append(structDefinitions, hierarchicalStructs...)
@@ -260,9 +383,13 @@ func (g *Generator) Generate(w io.Writer) error {
 
 	// Process SQL builder
 	// processSQLBuilderWithDialect expects a string dialect; convert here from snapsql.Dialect
-	sqlBuilder, err := processSQLBuilderWithDialect(g.Format, string(g.Dialect), funcName)
+	sqlBuilder, err := processSQLBuilderWithDialect(g.Format, string(g.Dialect), funcName, g.RedactParams)
 	if err != nil {
-		return fmt.Errorf("failed to process SQL builder: %w", err)
+		return nil, fmt.Errorf("failed to process SQL builder: %w", err)
+	}
+
+	if err := validateQueryComment(g.QueryComment); err != nil {
+		return nil, err
 	}
 
 	hasRowLockInstruction := hasEmitSystemFor(g.Format.Instructions)
@@ -270,16 +397,41 @@ func (g *Generator) Generate(w io.Writer) error {
 		sqlBuilder.NeedsRowLockClause = hasRowLockInstruction
 	}
 
+	// execution policy of snapsql.yaml; functions without one get no policy code
+	executionPolicy := g.Execution.PolicyFor(g.Format.FunctionName)
+	hasExecutionPolicy := executionPolicy != snapsql.ExecutionPolicy{}
+
 	// Process query execution
-	queryExecution, err := generateQueryExecution(g.Format, responseStruct, g.hierarchicalMetas, responseType, funcName, errorZeroValue, true)
+	queryExecution, err := generateQueryExecution(g.Format, responseStruct, g.hierarchicalMetas, responseType, funcName, errorZeroValue, true, rowGuard(hasExecutionPolicy))
 	if err != nil {
-		return fmt.Errorf("failed to generate query execution: %w", err)
+		return nil, fmt.Errorf("failed to generate query execution: %w", err)
+	}
+
+	// hierarchy_mode: json replaces the row aggregation with JSON columns built by the database
+	if err := validateHierarchyMode(g.HierarchyMode); err != nil {
+		return nil, err
+	}
+
+	var jsonHierarchy *jsonHierarchyData
+
+	if g.HierarchyMode == HierarchyModeJSON && len(hierarchicalGroups) > 0 {
+		jsonHierarchy, err = buildJSONHierarchy(g.Dialect, responseStruct, g.hierarchicalMetas, g.Format.ResponseAffinity == "many", rowGuard(hasExecutionPolicy))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", g.Format.FunctionName, err)
+		}
+
+		queryExecution.Code = jsonHierarchy.Code
+		queryExecution.NeedsSnapsqlImport = g.Format.ResponseAffinity != "many"
+	}
+
+	if cursorPage != nil && !queryExecution.IsIterator {
+		return nil, fmt.Errorf("%w: cursor pagination of %s requires a flat many response", ErrGenerateGoCode, g.Format.FunctionName)
 	}
 
 	// Process implicit parameters (system columns)
 	implicitParams, err := processImplicitParameters(g.Format)
 	if err != nil {
-		return fmt.Errorf("failed to process implicit parameters: %w", err)
+		return nil, fmt.Errorf("failed to process implicit parameters: %w", err)
 	}
 
 	implicitParams = ensureImplicitParams(g.Format, sqlBuilder, implicitParams)
@@ -309,6 +461,10 @@ func (g *Generator) Generate(w io.Writer) error {
 		isSelectQuery = !strings.EqualFold(g.Format.ResponseAffinity, string(intermediate.ResponseAffinityNone))
 	}
 
+	if err := validateExecutionPolicy(executionPolicy, g.Format.FunctionName, isSelectQuery); err != nil {
+		return nil, err
+	}
+
 	sliceElementType := ""
 	if after, ok := strings.CutPrefix(responseType, "[]"); ok {
 		sliceElementType = after
@@ -343,6 +499,7 @@ func (g *Generator) Generate(w io.Writer) error {
 		Imports            map[string]struct{}
 		ImportSlice        []string
 		HierarchicalMetas  []*hierarchicalNodeMeta
+		JSONHierarchy      *jsonHierarchyData
 		IteratorYieldType  string
 		DeclareResult      bool
 		ErrorZeroValue     string
@@ -350,6 +507,17 @@ func (g *Generator) Generate(w io.Writer) error {
 		ResponseAffinity   string
 		WhereMeta          *whereClauseMetaData
 		MutationKind       string
+		WhereGuardPolicy   string
+		Batch              *batchVariantData
+		Tracing            bool
+		StatementType      string
+		Registry           *registryData
+		CursorPage         *cursorPageData
+		EntryFuncName      string
+		StrictLimit        bool
+		Transactions       bool
+		QueryComment       string
+		ExecutionPolicy    string
 	}{
 		Timestamp:          time.Now(),
 		PackageName:        g.PackageName,
@@ -371,6 +539,7 @@ func (g *Generator) Generate(w io.Writer) error {
 		ImplicitParams:     implicitParams,
 		Imports:            make(map[string]struct{}),
 		HierarchicalMetas:  g.hierarchicalMetas,
+		JSONHierarchy:      jsonHierarchy,
 		FunctionReturnType: functionReturnType,
 		IteratorYieldType:  iteratorYieldType,
 		DeclareResult:      declareResult,
@@ -379,28 +548,74 @@ func (g *Generator) Generate(w io.Writer) error {
 		ResponseAffinity:   responseAffinity,
 		WhereMeta:          convertWhereMeta(g.Format.WhereClauseMeta),
 		MutationKind:       mutationKindFromStatementType(g.Format.StatementType),
+		Batch:              buildBatchVariant(g.Format, parameters, funcName, responseType, g.BatchSize),
+		Tracing:            g.Tracing,
+		StatementType:      strings.ToLower(g.Format.StatementType),
+		Registry:           buildRegistryData(g.Format),
+		CursorPage:         cursorPage,
+		EntryFuncName:      funcName,
+		StrictLimit:        g.Format.LimitGuard != nil && g.Format.LimitGuard.Strict && g.Format.LimitGuard.Unbounded,
+		Transactions:       g.Transactions,
+		QueryComment:       g.queryCommentPrefix(funcName),
+	}
+
+	if hasExecutionPolicy {
+		data.ExecutionPolicy = executionPolicyLiteral(executionPolicy)
+	}
+
+	if executionPolicy.Timeout > 0 {
+		data.Imports["time"] = struct{}{}
+	}
+
+	if data.MutationKind != "" {
+		policy, importPath := whereGuardPolicy(g.WhereGuard, g.Format.FunctionName)
+		data.WhereGuardPolicy = policy
+
+		if importPath != "" {
+			data.Imports[importPath] = struct{}{}
+		}
+	}
+
+	if cursorPage != nil {
+		// the row iterator becomes an unexported helper of the page function
+		data.EntryFuncName = toLowerCamel(g.Format.FunctionName) + "Rows"
 	}
 
 	if queryExecution.IsIterator && responseStruct != nil {
 		data.Imports["iter"] = struct{}{}
 	}
 
-	// Add time import if any implicit parameter uses time.Now() as default
-	for _, param := range implicitParams {
-		if param.DefaultValueLiteral == "time.Now()" {
-			data.Imports["time"] = struct{}{}
-			break
+	if responseImport != "" {
+		data.Imports[responseImport] = struct{}{}
+	}
+
+	// Mapped types appear in the generated structs only
+	if len(hierarchicalGroups) > 0 || (data.ResponseStruct != nil && data.ResponseStruct.Generated()) {
+		for _, importPath := range typeImports {
+			data.Imports[importPath] = struct{}{}
 		}
 	}
 
+	if jsonHierarchy != nil {
+		data.Imports["encoding/json"] = struct{}{}
+	}
+
 	// Add time import if any struct field uses time.Time
-	if data.ResponseStruct != nil {
+	if data.ResponseStruct != nil && data.ResponseStruct.Generated() {
 		for _, f := range data.ResponseStruct.Fields {
 			if strings.Contains(f.Type, "time.Time") {
 				data.Imports["time"] = struct{}{}
 				break
 			}
 		}
+
+		// nullable_style: sqlnull (snapsqlgo is always imported)
+		for _, f := range data.ResponseStruct.Fields {
+			if strings.HasPrefix(f.Type, "sql.") && data.ResponseType != "sql.Result" {
+				data.Imports["database/sql"] = struct{}{}
+				break
+			}
+		}
 	}
 
 	// Add time/decimal imports if appear in struct definitions
@@ -414,6 +629,13 @@ func (g *Generator) Generate(w io.Writer) error {
 		}
 	}
 
+	// Add imports of template functions called by the expressions
+	for _, fn := range g.Format.TemplateFunctions {
+		if fn.GoPackage != "" {
+			data.Imports[fn.GoPackage] = struct{}{}
+		}
+	}
+
 	// Convert imports map to slice for template
 	var importSlice []string
 	for imp := range data.Imports {
@@ -523,14 +745,14 @@ func (g *Generator) Generate(w io.Writer) error {
 		},
 	}).Parse(goTemplate)
 	if err != nil {
-		return fmt.Errorf("failed to parse template: %w", err)
+		return nil, fmt.Errorf("failed to parse template: %w", err)
 	}
 
 	var buf strings.Builder
 
 	err = tmpl.Execute(&buf, data)
 	if err != nil {
-		return fmt.Errorf("failed to execute template: %w", err)
+		return nil, fmt.Errorf("failed to execute template: %w", err)
 	}
 
 	formatted, err := format.Source([]byte(buf.String()))
@@ -542,9 +764,21 @@ func (g *Generator) Generate(w io.Writer) error {
 		panic(err)
 	}
 
-	_, err = w.Write(formatted)
+	sourceMap := &SourceMap{
+		Version:  SourceMapVersion,
+		File:     g.OutputFile,
+		Source:   g.SourcePath,
+		Mappings: []SourceMapping{},
+	}
+	// directives need both file names to point at the template and back
+	lineDirectives := g.LineDirectives && g.SourcePath != "" && g.OutputFile != ""
+	formatted = applySourceMarkers(formatted, sourceMap, lineDirectives)
 
-	return err
+	if _, err := w.Write(formatted); err != nil {
+		return nil, err
+	}
+
+	return sourceMap, nil
 }
 
 // snakeToCamel converts a snake_case string to CamelCase
@@ -788,6 +1022,8 @@ func convertToGoType(snapType string) (string, error) {
 		return "*time.Time", nil
 	case "bytes":
 		return "[]byte", nil
+	case "json":
+		return "snapsqlgo.JSON", nil
 	case "any":
 		return "any", nil
 	default:
@@ -802,6 +1038,13 @@ func convertToGoType(snapType string) (string, error) {
 
 // processResponseType determines the response type based on response affinity and responses
 func processResponseType(format *intermediate.IntermediateFormat) (string, error) {
+	switch intermediate.ResponseAffinity(strings.ToLower(format.ResponseAffinity)) {
+	case intermediate.ResponseAffinityExists:
+		return "bool", nil
+	case intermediate.ResponseAffinityCount:
+		return "int64", nil
+	}
+
 	if len(format.Responses) == 0 {
 		affinity := strings.ToLower(format.ResponseAffinity)
 		switch affinity {
@@ -873,6 +1116,15 @@ type responseStructData struct {
 	Fields []responseFieldData
 	// RawResponses keeps original intermediate.Response slice for advanced generation (hierarchical, PK, etc.)
 	RawResponses []intermediate.Response
+	// Declared is set when Name is the user type declared by response_type; no struct is generated
+	Declared bool
+	// AliasOf names the struct of another template with the same shape; Name is generated as its alias
+	AliasOf string
+}
+
+// Generated reports whether the struct is declared with its fields in the generated file
+func (s *responseStructData) Generated() bool {
+	return !s.Declared && s.AliasOf == ""
 }
 
 // responseFieldData represents a field in a response struct
@@ -1243,8 +1495,8 @@ func generateDefaultValueLiteral(defaultValue any, goType string) (string, error
 	switch v := defaultValue.(type) {
 	case string:
 		if v == "NOW()" {
-			// For NOW() function, generate time.Now() call
-			return "time.Now()", nil
+			// NOW() は context の Clock から取得する（snapsqlgo.WithClock でテスト時に固定できる）
+			return "snapsqlgo.Now(ctx)", nil
 		}
 		// For other string values, quote them
 		return fmt.Sprintf("%q", v), nil
@@ -1311,7 +1563,10 @@ import (
 {{ . }}
 
 {{- end }}
-{{- if .ResponseStruct }}
+{{- if and .ResponseStruct .ResponseStruct.AliasOf }}
+// {{ .ResponseStruct.Name }} represents the response structure for {{ .FunctionName }}, shared with other queries of the same shape
+type {{ .ResponseStruct.Name }} = {{ .ResponseStruct.AliasOf }}
+{{- else if and .ResponseStruct .ResponseStruct.Generated }}
 // {{ .ResponseStruct.Name }} represents the response structure for {{ .FunctionName }}
 type {{ .ResponseStruct.Name }} struct {
 	{{- range .ResponseStruct.Fields }}
@@ -1331,12 +1586,106 @@ var {{ .FunctionName }}ExplangExpressions = []snapsqlgo.ExplangExpression{
 
 const {{ .LowerFuncName }}MockPath = "{{ .MockPath }}"
 
-{{- if .Description }}
+{{- if .SQLBuilder.IsStatic }}
+
+// {{ .FunctionName }}SQL is the {{ .Dialect }} statement executed by {{ .FunctionName }}.
+const {{ .FunctionName }}SQL = {{ printf "%q" .SQLBuilder.StaticSQL }}
+{{- end }}
+
+{{- if .CursorPage }}
+// {{ .EntryFuncName }} yields the rows of a single page of {{ .FunctionName }}, including the extra row that tells whether another page exists.
+{{- else if .Description }}
 // {{ .FunctionName }} {{ .Description }}
 {{- else }}
 // {{ .FunctionName }} - {{ .ResponseType }} Affinity
 {{- end }}
-func {{ .FunctionName }}(ctx context.Context, executor snapsqlgo.DBExecutor{{- range .Parameters }}, {{ .Name }} {{ .Type }}{{- end }}, opts ...snapsqlgo.FuncOpt) {{ .FunctionReturnType }} {
+func {{ .EntryFuncName }}(ctx context.Context, executor snapsqlgo.DBExecutor{{- range .Parameters }}, {{ .Name }} {{ .Type }}{{- end }}, opts ...snapsqlgo.FuncOpt) {{ .FunctionReturnType }} {
+{{- if .Tracing }}
+{{- if .QueryExecution.IsIterator }}
+	return func(yield func({{ .IteratorYieldType }}, error) bool) {
+		ctx, span := snapsqlgo.StartQuerySpan(ctx, "{{ .FunctionName }}", "{{ .Dialect }}", "{{ .StatementType }}")
+		var spanErr error
+		var returnedRows int64
+		for item, err := range {{ .LowerFuncName }}Untraced(ctx, executor{{- range .Parameters }}, {{ .Name }}{{- end }}, opts...) {
+			if err != nil {
+				spanErr = err
+			} else {
+				returnedRows++
+			}
+			if !yield(item, err) {
+				break
+			}
+		}
+		span.SetReturnedRows(returnedRows)
+		span.End(spanErr)
+	}
+{{- else }}
+	ctx, span := snapsqlgo.StartQuerySpan(ctx, "{{ .FunctionName }}", "{{ .Dialect }}", "{{ .StatementType }}")
+	result, err := {{ .LowerFuncName }}Untraced(ctx, executor{{- range .Parameters }}, {{ .Name }}{{- end }}, opts...)
+{{- if eq .ResponseType "sql.Result" }}
+	if err == nil && result != nil {
+		if rowsAffected, rowsErr := result.RowsAffected(); rowsErr == nil {
+			span.SetRowsAffected(rowsAffected)
+		}
+	}
+{{- else if .SliceElementType }}
+	span.SetReturnedRows(int64(len(result)))
+{{- end }}
+	span.End(err)
+	return result, err
+{{- end }}
+}
+
+// {{ .LowerFuncName }}Untraced executes {{ .FunctionName }} without creating a span.
+func {{ .LowerFuncName }}Untraced(ctx context.Context, executor snapsqlgo.DBExecutor{{- range .Parameters }}, {{ .Name }} {{ .Type }}{{- end }}, opts ...snapsqlgo.FuncOpt) {{ .FunctionReturnType }} {
+{{- end }}
+	executor = snapsqlgo.RouteExecutor(ctx, executor, "{{ .FunctionName }}", "{{ .StatementType }}", opts...)
+{{- if .Transactions }}
+{{- if .QueryExecution.IsIterator }}
+	if beginTx, err := snapsqlgo.ResolveTxPropagation(ctx, executor, "{{ .FunctionName }}", "{{ .StatementType }}", opts...); err != nil {
+		return func(yield func({{ .IteratorYieldType }}, error) bool) {
+			var zero {{ .IteratorYieldType }}
+			_ = yield(zero, err)
+		}
+	} else if beginTx {
+		return snapsqlgo.SeqInTx(ctx, executor, opts, func(tx snapsqlgo.DBExecutor, opts []snapsqlgo.FuncOpt) {{ .FunctionReturnType }} {
+			return {{ if .Tracing }}{{ .LowerFuncName }}Untraced{{ else }}{{ .EntryFuncName }}{{ end }}(ctx, tx{{- range .Parameters }}, {{ .Name }}{{- end }}, opts...)
+		})
+	}
+{{- else }}
+	beginTx, err := snapsqlgo.ResolveTxPropagation(ctx, executor, "{{ .FunctionName }}", "{{ .StatementType }}", opts...)
+	if err != nil {
+		var zero {{ .ResponseType }}
+		return zero, err
+	}
+{{- end }}
+{{- end }}
+{{- if not .QueryExecution.IsIterator }}
+	retryOpts := snapsqlgo.ResolveRetryOptions(ctx, "{{ .FunctionName }}", "{{ .Dialect }}", "{{ .StatementType }}", opts...)
+	return snapsqlgo.Retry(ctx, retryOpts, executor, func(ctx context.Context) ({{ .ResponseType }}, error) {
+{{- if .Transactions }}
+		if beginTx {
+			// The transaction is begun per attempt so that a retry replays the whole transaction
+			return snapsqlgo.RunFuncInTx(ctx, executor, nil, func(tx snapsqlgo.DBExecutor) ({{ .ResponseType }}, error) {
+				return {{ .LowerFuncName }}Attempt(ctx, tx{{- range .Parameters }}, {{ .Name }}{{- end }}, opts...)
+			})
+		}
+{{- if not .IsSelectQuery }}
+		if snapsqlgo.ResolveSavepoint(ctx, executor, "{{ .FunctionName }}", "{{ .StatementType }}", opts...) {
+			// A failure rolls back only this statement and keeps the caller's transaction usable
+			return snapsqlgo.RunFuncInSavepoint(ctx, executor, "{{ .Dialect }}", func(tx snapsqlgo.DBExecutor) ({{ .ResponseType }}, error) {
+				return {{ .LowerFuncName }}Attempt(ctx, tx{{- range .Parameters }}, {{ .Name }}{{- end }}, opts...)
+			})
+		}
+{{- end }}
+{{- end }}
+		return {{ .LowerFuncName }}Attempt(ctx, executor{{- range .Parameters }}, {{ .Name }}{{- end }}, opts...)
+	})
+}
+
+// {{ .LowerFuncName }}Attempt executes {{ .FunctionName }} once. Retries are driven by {{ .FunctionName }}.
+func {{ .LowerFuncName }}Attempt(ctx context.Context, executor snapsqlgo.DBExecutor{{- range .Parameters }}, {{ .Name }} {{ .Type }}{{- end }}, opts ...snapsqlgo.FuncOpt) {{ .FunctionReturnType }} {
+{{- end }}
 {{- if .DeclareResult }}
 var result {{ .ResponseType }}
 
@@ -1367,10 +1716,10 @@ var result {{ .ResponseType }}
 {{- end }}
 
 	execCtx := snapsqlgo.ExtractExecutionContext(ctx)
-	rowLockMode := snapsqlgo.RowLockNone
-	if execCtx != nil {
-		rowLockMode = execCtx.RowLockMode()
-	}
+{{- with .ExecutionPolicy }}
+	execPolicy := snapsqlgo.ResolveExecutionPolicy(ctx, "{{ $.FunctionName }}", "{{ $.StatementType }}", {{ . }}, opts...)
+{{- end }}
+	rowLockMode := snapsqlgo.ResolveRowLockMode(ctx, "{{ .FunctionName }}", "{{ .StatementType }}", opts...)
 	if rowLockMode != snapsqlgo.RowLockNone {
 		snapsqlgo.EnsureRowLockAllowed(snapsqlgo.QueryLogQueryType{{ if .IsSelectQuery }}Select{{ else }}Exec{{ end }}, rowLockMode)
 	}
@@ -1378,21 +1727,17 @@ var result {{ .ResponseType }}
 		if rowLockMode != snapsqlgo.RowLockNone {
 			var rowLockErr error
 			// Call dialect-specific helper generated for each target dialect to avoid runtime dialect checks.
-			{{- if eq .Dialect "postgres" }}
+			{{- if or (eq .Dialect "postgres") (eq .Dialect "cockroach") }}
 			rowLockClause, rowLockErr = snapsqlgo.BuildRowLockClausePostgres(rowLockMode)
 			{{- else if eq .Dialect "mysql" }}
 			rowLockClause, rowLockErr = snapsqlgo.BuildRowLockClauseMySQL(rowLockMode)
 			{{- else if eq .Dialect "mariadb" }}
 			rowLockClause, rowLockErr = snapsqlgo.BuildRowLockClauseMariaDB(rowLockMode)
 			{{- else if eq .Dialect "sqlite" }}
-			// SQLite does not support row locks. For SELECT queries we silently ignore the clause;
-			// for mutation queries we treat this as an error.
-			{{- if .IsSelectQuery }}
-			rowLockClause, _ = snapsqlgo.BuildRowLockClauseSQLite(rowLockMode)
-			{{- else }}
+			// SQLite does not support row locks. FOR UPDATE / FOR SHARE are dropped;
+			// NOWAIT and SKIP LOCKED are reported as errors.
 			rowLockClause, rowLockErr = snapsqlgo.BuildRowLockClauseSQLite(rowLockMode)
 			{{- end }}
-			{{- end }}
 			if rowLockErr != nil {
 				// Return error in a manner appropriate for the function kind (iterator vs normal).
 				{{- if .QueryExecution.IsIterator }}
@@ -1411,6 +1756,7 @@ var result {{ .ResponseType }}
 	queryLogOptions := snapsqlgo.QueryOptionsSnapshot{
 		RowLockClause: rowLockClause,
 		RowLockMode:   rowLockMode,
+		DryRun:        snapsqlgo.IsDryRun(ctx, "{{ .FunctionName }}", "{{ .StatementType }}", opts...),
 	}
 
 {{- if .MutationKind }}
@@ -1461,10 +1807,21 @@ var result {{ .ResponseType }}
 	{{ .SQLBuilder.FallbackVarName }} := false
 {{- end }}
 
+{{- if .SQLBuilder.HasRedactedArgs }}
+	// Positions of arguments masked in query logs (query_log.redact)
+	var redactedArgs []int
+{{- end }}
+
 	// Build SQL
 	buildQueryAndArgs := func() (string, []any, error) {
 	{{- if .SQLBuilder.IsStatic }}
-	query := {{ printf "%q" .SQLBuilder.StaticSQL }}
+	{{- if .SQLBuilder.StaticPos }}
+	//snapsql:pos {{ .SQLBuilder.StaticPos }}
+	{{- end }}
+	query := {{ if .QueryComment }}{{ printf "%q" .QueryComment }} + {{ end }}{{ .FunctionName }}SQL
+	{{- if .SQLBuilder.StaticPos }}
+	//snapsql:end
+	{{- end }}
 	args := make([]any, 0)
 	{{- if .SQLBuilder.HasArguments }}
 		{{- range .SQLBuilder.ArgumentExprs }}
@@ -1486,12 +1843,13 @@ var result {{ .ResponseType }}
 	{{ . }}
 	{{- end }}
 
-	query := strings.TrimSpace(builder.String())
+	query := {{ if .QueryComment }}{{ printf "%q" .QueryComment }} + {{ end }}strings.TrimSpace(builder.String())
 	return query, args, nil
 		{{- end }}
 	}
 
 {{- if .QueryExecution.IsIterator }}
+	streamOpts := snapsqlgo.ResolveStreamOptions(ctx, "{{ .FunctionName }}", "{{ .Dialect }}", opts...)
 	return func(yield func({{ .IteratorYieldType }}, error) bool) {
 		query, args, err := buildQueryAndArgs()
 		if err != nil {
@@ -1508,9 +1866,23 @@ var result {{ .ResponseType }}
 		whereMeta.FallbackTriggered = {{ .SQLBuilder.FallbackVarName }}
 	}
 {{- end }}
+{{- if and .ExecutionPolicy (not .IsSelectQuery) }}
+	// Reject data modification under a read-only execution policy
+	if err := snapsqlgo.EnforceReadOnly("{{ .FunctionName }}", execPolicy); err != nil {
+		_ = yield(nil, err)
+		return
+	}
+{{- end }}
 {{- if .MutationKind }}
 	// Enforce WHERE clause guard when mutations are generated
-	if err := snapsqlgo.EnforceNonEmptyWhereClause(ctx, "{{ .FunctionName }}", snapsqlgo.{{ .MutationKind }}, whereMeta, query); err != nil {
+	if err := snapsqlgo.EnforceNonEmptyWhereClause(ctx, "{{ .FunctionName }}", snapsqlgo.{{ .MutationKind }}, whereMeta, query{{ with .WhereGuardPolicy }}, {{ . }}{{ end }}); err != nil {
+		_ = yield(nil, err)
+		return
+	}
+{{- end }}
+{{- if .StrictLimit }}
+	// Reject SELECT without LIMIT (limits.strict)
+	if err := snapsqlgo.EnforceLimit(ctx, "{{ .FunctionName }}", opts...); err != nil {
 		_ = yield(nil, err)
 		return
 	}
@@ -1550,8 +1922,24 @@ var result {{ .ResponseType }}
 				SourceFile: "{{ .PackageName }}/{{ .FunctionName }}",
 				QueryType:  snapsqlgo.QueryLogQueryType{{ if .IsSelectQuery }}Select{{ else }}Exec{{ end }},
 				Options:    queryLogOptions,
+{{- if .SQLBuilder.HasRedactedArgs }}
+				RedactedArgs: redactedArgs,
+{{- end }}
 			}, executor
 		})
+		if queryLogOptions.DryRun {
+			// Dry run: the statement is logged but not sent to the database
+			return
+		}
+		{{- if .ExecutionPolicy }}
+		// Bound the execution by the timeout of the execution policy
+		ctx, cancelPolicy, err := snapsqlgo.ApplyExecutionPolicy(ctx, executor, "{{ .Dialect }}", execPolicy)
+		if err != nil {
+			_ = yield(nil, err)
+			return
+		}
+		defer cancelPolicy()
+		{{- end }}
 		{{- range .QueryExecution.IteratorBody }}
 		{{ . }}
 		{{- end }}
@@ -1566,14 +1954,30 @@ var result {{ .ResponseType }}
 		query += queryLogOptions.RowLockClause
 	}
 {{- end }}
+{{- with .JSONHierarchy }}
+	// Aggregate the hierarchy into JSON columns in the database (hierarchy_mode: json)
+	query = {{ printf "%q" .Prefix }} + query + {{ printf "%q" .Suffix }}
+{{- end }}
 {{- if and .SQLBuilder.HasFallbackGuard .MutationKind }}
 	if whereMeta != nil {
 		whereMeta.FallbackTriggered = {{ .SQLBuilder.FallbackVarName }}
 	}
 {{- end }}
+{{- if and .ExecutionPolicy (not .IsSelectQuery) }}
+	// Reject data modification under a read-only execution policy
+	if err := snapsqlgo.EnforceReadOnly("{{ .FunctionName }}", execPolicy); err != nil {
+		return {{ .ErrorZeroValue }}, err
+	}
+{{- end }}
 {{- if .MutationKind }}
 	// Enforce WHERE clause guard when mutations are generated
-	if err := snapsqlgo.EnforceNonEmptyWhereClause(ctx, "{{ .FunctionName }}", snapsqlgo.{{ .MutationKind }}, whereMeta, query); err != nil {
+	if err := snapsqlgo.EnforceNonEmptyWhereClause(ctx, "{{ .FunctionName }}", snapsqlgo.{{ .MutationKind }}, whereMeta, query{{ with .WhereGuardPolicy }}, {{ . }}{{ end }}); err != nil {
+		return {{ .ErrorZeroValue }}, err
+	}
+{{- end }}
+{{- if .StrictLimit }}
+	// Reject SELECT without LIMIT (limits.strict)
+	if err := snapsqlgo.EnforceLimit(ctx, "{{ .FunctionName }}", opts...); err != nil {
 		return {{ .ErrorZeroValue }}, err
 	}
 {{- end }}
@@ -1616,6 +2020,20 @@ var result {{ .ResponseType }}
             }
             result = mapped
             return result, nil
+{{- else if eq .ResponseAffinity "exists" }}
+            mapped, err := snapsqlgo.MapMockExecutionToExists(mockExec)
+            if err != nil {
+                return {{ .ErrorZeroValue }}, fmt.Errorf("{{ .FunctionName }}: failed to map mock execution: %w", err)
+            }
+            result = mapped
+            return result, nil
+{{- else if eq .ResponseAffinity "count" }}
+            mapped, err := snapsqlgo.MapMockExecutionToCount(mockExec)
+            if err != nil {
+                return {{ .ErrorZeroValue }}, fmt.Errorf("{{ .FunctionName }}: failed to map mock execution: %w", err)
+            }
+            result = mapped
+            return result, nil
 {{- else if eq .ResponseAffinity "many" }}
             mapped, err := snapsqlgo.MapMockExecutionToSlice[{{ .SliceElementType }}](mockExec)
             if err != nil {
@@ -1641,10 +2059,29 @@ var result {{ .ResponseType }}
 			SourceFile: "{{ .PackageName }}/{{ .FunctionName }}",
 			QueryType:  snapsqlgo.QueryLogQueryType{{ if .IsSelectQuery }}Select{{ else }}Exec{{ end }},
 			Options:    queryLogOptions,
+{{- if .SQLBuilder.HasRedactedArgs }}
+			RedactedArgs: redactedArgs,
+{{- end }}
 		}, executor
 	})
+	if queryLogOptions.DryRun {
+		// Dry run: the statement is logged but not sent to the database
+{{- if .QueryExecution.ReturnsSQLResult }}
+		return snapsqlgo.NewMockResult(0, 0), nil
+{{- else }}
+		return {{ .ErrorZeroValue }}, nil
+{{- end }}
+	}
+{{- if .ExecutionPolicy }}
+	// Bound the execution by the timeout of the execution policy
+	ctx, cancelPolicy, err := snapsqlgo.ApplyExecutionPolicy(ctx, executor, "{{ .Dialect }}", execPolicy)
+	if err != nil {
+		return {{ .ErrorZeroValue }}, err
+	}
+	defer cancelPolicy()
+{{- end }}
 	// Execute query
-	stmt, err := executor.PrepareContext(ctx, query)
+	stmt, err := snapsqlgo.PrepareContext(ctx, executor, query)
 	if err != nil {
 		err = fmt.Errorf("{{ .FunctionName }}: failed to prepare statement: %w (query: %s)", err, query)
 		return {{ .ErrorZeroValue }}, err
@@ -1658,6 +2095,106 @@ var result {{ .ResponseType }}
 	return result, nil
 {{- end }}
 }
+{{- if .CursorPage }}
+
+{{- if .Description }}
+// {{ .FunctionName }} {{ .Description }}
+{{- else }}
+// {{ .FunctionName }} - snapsqlgo.CursorPage[{{ .CursorPage.ItemType }}] Affinity
+{{- end }}
+// Pass the NextCursor of the returned page as after to read the next page; an empty after reads the first page.
+func {{ .FunctionName }}(ctx context.Context, executor snapsqlgo.DBExecutor{{- range .Parameters }}, {{ .Name }} {{ .Type }}{{- end }}, opts ...snapsqlgo.FuncOpt) (snapsqlgo.CursorPage[{{ .CursorPage.ItemType }}], error) {
+	rows := {{ .EntryFuncName }}(ctx, executor{{- range .Parameters }}, {{ .Name }}{{- end }}, opts...)
+	return snapsqlgo.CollectCursorPage(rows, int({{ .CursorPage.Limit }}), func(row *{{ .CursorPage.ItemType }}) []any {
+		return []any{ {{- range $i, $key := .CursorPage.Keys }}{{ if $i }}, {{ end }}{{ $key }}{{ end -}} }
+	})
+}
+{{- end }}
+{{- if .Batch }}
+
+// {{ .Batch.SizeConstName }} is the default number of rows per statement used by {{ .Batch.FunctionName }}.
+const {{ .Batch.SizeConstName }} = {{ .Batch.DefaultSize }}
+
+// {{ .Batch.FunctionName }} executes {{ .FunctionName }} once per chunk of batchSize rows of {{ .Batch.Collection }}
+// to stay below the placeholder limit of the database, and returns the total number of affected rows.
+// batchSize <= 0 uses {{ .Batch.SizeConstName }}. Chunks are not wrapped in a transaction; pass a transaction
+// as executor when the import must be applied atomically.
+func {{ .Batch.FunctionName }}(ctx context.Context, executor snapsqlgo.DBExecutor{{- range .Batch.Parameters }}, {{ .Name }} {{ .Type }}{{- end }}, batchSize int, opts ...snapsqlgo.FuncOpt) (int64, error) {
+	if batchSize <= 0 {
+		batchSize = {{ .Batch.SizeConstName }}
+	}
+
+	var total int64
+
+	for start := 0; start < len({{ .Batch.Collection }}); start += batchSize {
+		end := start + batchSize
+		if end > len({{ .Batch.Collection }}) {
+			end = len({{ .Batch.Collection }})
+		}
+
+		result, err := {{ .FunctionName }}(ctx, executor{{- range .Batch.Parameters }}, {{ if eq .Name $.Batch.Collection }}{{ .Name }}[start:end]{{ else }}{{ .Name }}{{ end }}{{- end }}, opts...)
+		if err != nil {
+			return total, fmt.Errorf("{{ .Batch.FunctionName }}: rows %d-%d: %w", start, end, err)
+		}
+
+		if result != nil {
+			affected, err := result.RowsAffected()
+			if err != nil {
+				return total, fmt.Errorf("{{ .Batch.FunctionName }}: failed to get rows affected: %w", err)
+			}
+
+			total += affected
+		}
+	}
+
+	return total, nil
+}
+{{- end }}
+
+func init() {
+	snapsqlgo.Registry.Register(snapsqlgo.QueryInfo{
+		Name:             "{{ .FunctionName }}",
+		Package:          "{{ .PackageName }}",
+		Description:      {{ .Registry.Description }},
+		Dialect:          "{{ .Dialect }}",
+		StatementType:    "{{ .StatementType }}",
+		SQL:              {{ .Registry.SQL }},
+		Parameters: []snapsqlgo.QueryParam{
+			{{- range .Parameters }}
+			{Name: "{{ .OriginalName }}", GoName: "{{ .Name }}", Type: "{{ .Type }}", Optional: {{ not .Required }}},
+			{{- end }}
+		},
+		ResponseType:     "{{ .ResponseType }}",
+		ResponseAffinity: "{{ .ResponseAffinity }}",
+{{- if .ResponseStruct }}
+		ResponseFields: []snapsqlgo.QueryField{
+			{{- range .ResponseStruct.Fields }}
+			{Name: "{{ .JSONTag }}", GoName: "{{ .Name }}", Type: "{{ .Type }}"},
+			{{- end }}
+		},
+{{- end }}
+		Invoke: func(ctx context.Context, executor snapsqlgo.DBExecutor, registryParams map[string]any, opts ...snapsqlgo.FuncOpt) (any, error) {
+			{{- range .Parameters }}
+			{{ .Name }}, err := snapsqlgo.RegistryParam[{{ .Type }}](registryParams, "{{ .OriginalName }}")
+			if err != nil {
+				return nil, err
+			}
+			{{- end }}
+{{- if and .QueryExecution.IsIterator (not .CursorPage) }}
+			var items []{{ .IteratorYieldType }}
+			for item, err := range {{ .FunctionName }}(ctx, executor{{- range .Parameters }}, {{ .Name }}{{- end }}, opts...) {
+				if err != nil {
+					return items, err
+				}
+				items = append(items, item)
+			}
+			return items, nil
+{{- else }}
+			return {{ .FunctionName }}(ctx, executor{{- range .Parameters }}, {{ .Name }}{{- end }}, opts...)
+{{- end }}
+		},
+	})
+}
 `
 
 // Helper function to convert snake_case to PascalCase for Go field names
@@ -1732,7 +2269,7 @@ func guessSelectFromInstructions(instructions []intermediate.Instruction) *bool
 			return &val
 		}
 
-		if strings.HasPrefix(upper, "INSERT") || strings.HasPrefix(upper, "UPDATE") || strings.HasPrefix(upper, "DELETE") {
+		if strings.HasPrefix(upper, "INSERT") || strings.HasPrefix(upper, "UPDATE") || strings.HasPrefix(upper, "DELETE") || strings.HasPrefix(upper, "MERGE") {
 			val := false
 			return &val
 		}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/shibukawa/snapsql"
 	"github.com/shibukawa/snapsql/intermediate"
 )
 
@@ -29,6 +31,19 @@ func TestConvertToGoTypeFloatNormalization(t *testing.T) {
 	}
 }
 
+func TestConvertToGoTypeJSON(t *testing.T) {
+	for in, want := range map[string]string{"json": "snapsqlgo.JSON", "json[]": "[]snapsqlgo.JSON"} {
+		got, err := convertToGoType(in)
+		if err != nil {
+			t.Fatalf("convertToGoType(%s) unexpected error: %v", in, err)
+		}
+
+		if got != want {
+			t.Errorf("convertToGoType(%s) = %s, want %s", in, got, want)
+		}
+	}
+}
+
 func TestConvertToGoTypeTemporalAliases(t *testing.T) {
 	aliases := []string{"timestamp", "datetime", "date", "time"}
 
@@ -288,7 +303,7 @@ func TestGenerateQueryExecutionManyIterator(t *testing.T) {
 		t.Fatalf("processResponseStruct returned error: %v", err)
 	}
 
-	data, err := generateQueryExecution(format, respStruct, nil, respStruct.Name, "ListByBoard", "result", true)
+	data, err := generateQueryExecution(format, respStruct, nil, respStruct.Name, "ListByBoard", "result", true, false)
 	if err != nil {
 		t.Fatalf("generateQueryExecution returned error: %v", err)
 	}
@@ -305,6 +320,10 @@ func TestGenerateQueryExecutionManyIterator(t *testing.T) {
 	if data.IteratorYieldType != expectedYield {
 		t.Errorf("expected iterator yield type %s, got %s", expectedYield, data.IteratorYieldType)
 	}
+
+	if body := strings.Join(data.IteratorBody, "\n"); !strings.Contains(body, "snapsqlgo.QueryStream(ctx, executor, query, args, streamOpts)") {
+		t.Errorf("expected iterator to read rows through snapsqlgo.QueryStream, got:\n%s", body)
+	}
 }
 
 func TestProcessResponseTypeMissingMetadataErrors(t *testing.T) {
@@ -318,3 +337,1089 @@ func TestProcessResponseTypeMissingMetadataErrors(t *testing.T) {
 		t.Fatalf("expected error when response metadata is missing for affinity 'many'")
 	}
 }
+
+func TestGenerateMockHelper(t *testing.T) {
+	format := &intermediate.IntermediateFormat{
+		FunctionName:     "get_user_by_id",
+		ResponseAffinity: "one",
+		Responses: []intermediate.Response{
+			{Name: "id", Type: "int"},
+			{Name: "name", Type: "string"},
+		},
+	}
+
+	var buf strings.Builder
+	if err := New(format, WithPackageName("queries")).GenerateMockHelper(&buf); err != nil {
+		t.Fatalf("GenerateMockHelper returned error: %v", err)
+	}
+
+	code := buf.String()
+	for _, want := range []string{
+		"package queries",
+		"func WithGetUserByIDMock(ctx context.Context, rows ...GetUserByIDResult) (context.Context, error) {",
+		`snapsqlgo.MockRows("GetUserByID", rows...)`,
+		"func WithGetUserByIDMockError(ctx context.Context, err error) (context.Context, error) {",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("mock helper missing %q:\n%s", want, code)
+		}
+	}
+
+	format = &intermediate.IntermediateFormat{FunctionName: "delete_user", ResponseAffinity: "none"}
+	buf.Reset()
+
+	if err := New(format).GenerateMockHelper(&buf); err != nil {
+		t.Fatalf("GenerateMockHelper returned error: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "func WithDeleteUserMock(ctx context.Context, rowsAffected int64) (context.Context, error) {") {
+		t.Errorf("expected rows affected helper for exec function:\n%s", buf.String())
+	}
+}
+
+func TestGenerateScalarAffinity(t *testing.T) {
+	cases := []struct {
+		affinity   string
+		returnType string
+		mockMapper string
+	}{
+		{"exists", "(bool, error)", "snapsqlgo.MapMockExecutionToExists(mockExec)"},
+		{"count", "(int64, error)", "snapsqlgo.MapMockExecutionToCount(mockExec)"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.affinity, func(t *testing.T) {
+			format := &intermediate.IntermediateFormat{
+				FormatVersion:    "1",
+				FunctionName:     "check_users",
+				StatementType:    "select",
+				ResponseAffinity: c.affinity,
+				Responses:        []intermediate.Response{{Name: "value", Type: "int"}},
+				Instructions: []intermediate.Instruction{
+					{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "SELECT COUNT(*) AS value FROM users"},
+				},
+			}
+
+			var out strings.Builder
+
+			generator := &Generator{PackageName: "testgen", Format: format, Dialect: "postgres"}
+			if err := generator.Generate(&out); err != nil {
+				t.Fatalf("Generate returned error: %v", err)
+			}
+
+			code := out.String()
+			for _, want := range []string{
+				"func CheckUsers(ctx context.Context, executor snapsqlgo.DBExecutor, opts ...snapsqlgo.FuncOpt) " + c.returnType,
+				c.mockMapper,
+				"rows.Scan(&result)",
+			} {
+				if !strings.Contains(code, want) {
+					t.Errorf("generated code does not contain %q\n%s", want, code)
+				}
+			}
+
+			if strings.Contains(code, "type CheckUsersResult struct") {
+				t.Errorf("scalar affinity must not generate a response struct")
+			}
+		})
+	}
+}
+
+func TestGenerateStaticSQLConstant(t *testing.T) {
+	format := &intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "find_user",
+		StatementType:    "select",
+		ResponseAffinity: "one",
+		Parameters:       []intermediate.Parameter{{Name: "user_id", Type: "int"}},
+		Responses:        []intermediate.Response{{Name: "id", Type: "int"}},
+		CELExpressions:   []intermediate.CELExpression{{ID: "expr_001", Expression: "user_id"}},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "SELECT id FROM users WHERE id = "},
+			{Op: intermediate.OpEmitEval, Pos: "1:33", ExprIndex: intPtr(0)},
+		},
+	}
+
+	var out strings.Builder
+
+	generator := &Generator{PackageName: "testgen", Format: format, Dialect: "postgres"}
+	if err := generator.Generate(&out); err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	code := out.String()
+	for _, want := range []string{
+		"// FindUserSQL is the postgres statement executed by FindUser.",
+		`const FindUserSQL = "SELECT id FROM users`,
+		`WHERE id = $1"`,
+		"query := FindUserSQL",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code does not contain %q\n%s", want, code)
+		}
+	}
+
+	format.Instructions = []intermediate.Instruction{
+		{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "SELECT id FROM users ORDER BY "},
+		{Op: intermediate.OpEmitIdent, Pos: "1:31", ExprIndex: intPtr(0), Allowed: []string{"id"}},
+	}
+
+	out.Reset()
+
+	if err := generator.Generate(&out); err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	if strings.Contains(out.String(), "FindUserSQL") {
+		t.Errorf("dynamic queries must not export a SQL constant")
+	}
+}
+
+func TestGenerateQueryComment(t *testing.T) {
+	format := &intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "get_user_by_id",
+		StatementType:    "select",
+		ResponseAffinity: "one",
+		Parameters:       []intermediate.Parameter{{Name: "user_id", Type: "int"}},
+		Responses:        []intermediate.Response{{Name: "id", Type: "int"}},
+		CELExpressions:   []intermediate.CELExpression{{ID: "expr_001", Expression: "user_id"}},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "SELECT id FROM users WHERE id = "},
+			{Op: intermediate.OpEmitEval, Pos: "1:33", ExprIndex: intPtr(0)},
+		},
+	}
+
+	var out strings.Builder
+
+	generator := &Generator{PackageName: "testgen", Format: format, Dialect: "postgres", QueryComment: DefaultQueryComment}
+	if err := generator.Generate(&out); err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	if want := `query := "/* snapsql:func=GetUserByID */ " + GetUserByIDSQL`; !strings.Contains(out.String(), want) {
+		t.Errorf("generated code does not contain %q\n%s", want, out.String())
+	}
+
+	format.Instructions = []intermediate.Instruction{
+		{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "SELECT id FROM users ORDER BY "},
+		{Op: intermediate.OpEmitIdent, Pos: "1:31", ExprIndex: intPtr(0), Allowed: []string{"id"}},
+	}
+	generator.QueryComment = "app=billing,template={name},pkg={package}"
+
+	out.Reset()
+
+	if err := generator.Generate(&out); err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	if want := `query := "/* app=billing,template=get_user_by_id,pkg=testgen */ " + strings.TrimSpace(builder.String())`; !strings.Contains(out.String(), want) {
+		t.Errorf("generated code does not contain %q\n%s", want, out.String())
+	}
+
+	generator.QueryComment = "func={func} */ DROP TABLE users; /*"
+	if err := generator.Generate(&out); !errors.Is(err, ErrInvalidQueryComment) {
+		t.Errorf("expected ErrInvalidQueryComment, got %v", err)
+	}
+}
+
+func TestGenerateExecutionPolicy(t *testing.T) {
+	format := &intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "list_users",
+		StatementType:    "select",
+		ResponseAffinity: "many",
+		Responses:        []intermediate.Response{{Name: "id", Type: "int"}},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "SELECT id FROM users"},
+		},
+	}
+
+	execution := snapsql.ExecutionConfig{Functions: map[string]snapsql.ExecutionPolicy{
+		"list_*": {Timeout: 2 * time.Second, MaxRows: 1000, ReadOnly: true},
+	}}
+
+	var out strings.Builder
+
+	generator := &Generator{PackageName: "testgen", Format: format, Dialect: "postgres", Execution: execution}
+	if err := generator.Generate(&out); err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	code := out.String()
+	for _, want := range []string{
+		`"time"`,
+		`execPolicy := snapsqlgo.ResolveExecutionPolicy(ctx, "ListUsers", "select", snapsqlgo.ExecutionPolicy{Timeout: 2 * time.Second, MaxRows: 1000, ReadOnly: true}, opts...)`,
+		`ctx, cancelPolicy, err := snapsqlgo.ApplyExecutionPolicy(ctx, executor, "postgres", execPolicy)`,
+		`if err := execPolicy.CheckRows(rowCount); err != nil {`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code does not contain %q\n%s", want, code)
+		}
+	}
+
+	if strings.Contains(code, "EnforceReadOnly") {
+		t.Errorf("SELECT function should not check read-only policy\n%s", code)
+	}
+
+	format.FunctionName = "delete_user"
+	format.StatementType = "delete"
+	format.ResponseAffinity = "none"
+	format.Responses = nil
+	format.Instructions = []intermediate.Instruction{
+		{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "DELETE FROM users WHERE id = 1"},
+	}
+
+	out.Reset()
+
+	if err := generator.Generate(&out); err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	// Functions without a policy get no policy code
+	if strings.Contains(out.String(), "execPolicy") {
+		t.Errorf("function without execution policy should not resolve one\n%s", out.String())
+	}
+
+	generator.Execution.Functions["delete_user"] = snapsql.ExecutionPolicy{Timeout: 1500 * time.Millisecond}
+	out.Reset()
+
+	if err := generator.Generate(&out); err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	for _, want := range []string{
+		`snapsqlgo.ResolveExecutionPolicy(ctx, "DeleteUser", "delete", snapsqlgo.ExecutionPolicy{Timeout: 1500 * time.Millisecond}, opts...)`,
+		`snapsqlgo.EnforceReadOnly("DeleteUser", execPolicy)`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("generated code does not contain %q\n%s", want, out.String())
+		}
+	}
+
+	generator.Execution.Functions["delete_user"] = snapsql.ExecutionPolicy{ReadOnly: true}
+	if err := generator.Generate(&out); !errors.Is(err, ErrInvalidExecutionPolicy) {
+		t.Errorf("expected ErrInvalidExecutionPolicy, got %v", err)
+	}
+}
+
+func TestGenerateBatchVariant(t *testing.T) {
+	collection := 0
+	idExpr := 1
+
+	newFormat := func() *intermediate.IntermediateFormat {
+		return &intermediate.IntermediateFormat{
+			FormatVersion:    "1",
+			FunctionName:     "insert_tags",
+			StatementType:    "insert",
+			ResponseAffinity: "none",
+			Parameters: []intermediate.Parameter{
+				{Name: "tag", Type: "string"},
+				{Name: "user_ids", Type: "int[]"},
+			},
+			CELExpressions: []intermediate.CELExpression{
+				{ID: "expr_001", Expression: "user_ids", EnvironmentIndex: 0},
+				{ID: "expr_002", Expression: "user_id", EnvironmentIndex: 1},
+			},
+			Instructions: []intermediate.Instruction{
+				{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "INSERT INTO user_tags (user_id, tag) VALUES "},
+				{Op: intermediate.OpLoopStart, Pos: "2:1", Variable: "user_id", CollectionExprIndex: &collection},
+				{Op: intermediate.OpEmitStatic, Pos: "2:10", Value: "("},
+				{Op: intermediate.OpEmitEval, Pos: "2:11", ExprIndex: &idExpr},
+				{Op: intermediate.OpEmitStatic, Pos: "2:20", Value: ", 'tag')"},
+				{Op: intermediate.OpLoopEnd, Pos: "2:30"},
+			},
+		}
+	}
+
+	generate := func(format *intermediate.IntermediateFormat, batchSize int) string {
+		t.Helper()
+
+		var out strings.Builder
+
+		generator := &Generator{PackageName: "testgen", Format: format, Dialect: "postgres", BatchSize: batchSize}
+		if err := generator.Generate(&out); err != nil {
+			t.Fatalf("Generate returned error: %v", err)
+		}
+
+		return out.String()
+	}
+
+	code := generate(newFormat(), 500)
+	for _, want := range []string{
+		"const insertTagsBatchSize = 500",
+		"func InsertTagsBatch(ctx context.Context, executor snapsqlgo.DBExecutor, tag string, userIds []int, batchSize int, opts ...snapsqlgo.FuncOpt) (int64, error)",
+		"InsertTags(ctx, executor, tag, userIds[start:end], opts...)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code does not contain %q\n%s", want, code)
+		}
+	}
+
+	if code := generate(newFormat(), 0); strings.Contains(code, "InsertTagsBatch") {
+		t.Errorf("batch variant must not be generated when batch_size is 0")
+	}
+
+	selectFormat := newFormat()
+	selectFormat.StatementType = "select"
+	selectFormat.ResponseAffinity = "many"
+	selectFormat.Responses = []intermediate.Response{{Name: "id", Type: "int"}}
+
+	if code := generate(selectFormat, 500); strings.Contains(code, "InsertTagsBatch") {
+		t.Errorf("batch variant must only be generated for INSERT statements")
+	}
+}
+
+func TestGenerateTracing(t *testing.T) {
+	generate := func(format *intermediate.IntermediateFormat, tracing bool) string {
+		t.Helper()
+
+		var out strings.Builder
+
+		generator := &Generator{PackageName: "testgen", Format: format, Dialect: "postgres", Tracing: tracing}
+		if err := generator.Generate(&out); err != nil {
+			t.Fatalf("Generate returned error: %v", err)
+		}
+
+		return out.String()
+	}
+
+	listFormat := func() *intermediate.IntermediateFormat {
+		return &intermediate.IntermediateFormat{
+			FormatVersion:    "1",
+			FunctionName:     "list_users",
+			StatementType:    "select",
+			ResponseAffinity: "many",
+			Responses:        []intermediate.Response{{Name: "id", Type: "int"}},
+			Instructions: []intermediate.Instruction{
+				{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "SELECT id FROM users"},
+			},
+		}
+	}
+
+	code := generate(listFormat(), true)
+	for _, want := range []string{
+		`ctx, span := snapsqlgo.StartQuerySpan(ctx, "ListUsers", "postgres", "select")`,
+		"for item, err := range listUsersUntraced(ctx, executor, opts...) {",
+		"span.SetReturnedRows(returnedRows)",
+		"func listUsersUntraced(ctx context.Context, executor snapsqlgo.DBExecutor, opts ...snapsqlgo.FuncOpt) iter.Seq2[",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code does not contain %q\n%s", want, code)
+		}
+	}
+
+	deleteFormat := &intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "delete_user",
+		StatementType:    "delete",
+		ResponseAffinity: "none",
+		Parameters:       []intermediate.Parameter{{Name: "id", Type: "int"}},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "DELETE FROM users WHERE id = 1"},
+		},
+	}
+
+	code = generate(deleteFormat, true)
+	for _, want := range []string{
+		`ctx, span := snapsqlgo.StartQuerySpan(ctx, "DeleteUser", "postgres", "delete")`,
+		"result, err := deleteUserUntraced(ctx, executor, id, opts...)",
+		"span.SetRowsAffected(rowsAffected)",
+		"span.End(err)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code does not contain %q\n%s", want, code)
+		}
+	}
+
+	if code := generate(listFormat(), false); strings.Contains(code, "StartQuerySpan") || strings.Contains(code, "Untraced") {
+		t.Errorf("spans must not be generated when tracing is disabled")
+	}
+}
+
+func TestGenerateTransactions(t *testing.T) {
+	generate := func(format *intermediate.IntermediateFormat, transactions bool) string {
+		t.Helper()
+
+		var out strings.Builder
+
+		generator := &Generator{PackageName: "testgen", Format: format, Dialect: "postgres", Transactions: transactions}
+		if err := generator.Generate(&out); err != nil {
+			t.Fatalf("Generate returned error: %v", err)
+		}
+
+		return out.String()
+	}
+
+	listFormat := &intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "list_users",
+		StatementType:    "select",
+		ResponseAffinity: "many",
+		Responses:        []intermediate.Response{{Name: "id", Type: "int"}},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "SELECT id FROM users"},
+		},
+	}
+
+	code := generate(listFormat, true)
+	for _, want := range []string{
+		`if beginTx, err := snapsqlgo.ResolveTxPropagation(ctx, executor, "ListUsers", "select", opts...); err != nil {`,
+		"return snapsqlgo.SeqInTx(ctx, executor, opts, func(tx snapsqlgo.DBExecutor, opts []snapsqlgo.FuncOpt) iter.Seq2[",
+		"return ListUsers(ctx, tx, opts...)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code does not contain %q\n%s", want, code)
+		}
+	}
+
+	deleteFormat := &intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "delete_user",
+		StatementType:    "delete",
+		ResponseAffinity: "none",
+		Parameters:       []intermediate.Parameter{{Name: "id", Type: "int"}},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "DELETE FROM users WHERE id = 1"},
+		},
+	}
+
+	code = generate(deleteFormat, true)
+	for _, want := range []string{
+		`beginTx, err := snapsqlgo.ResolveTxPropagation(ctx, executor, "DeleteUser", "delete", opts...)`,
+		"return snapsqlgo.RunFuncInTx(ctx, executor, nil, func(tx snapsqlgo.DBExecutor) (sql.Result, error) {",
+		"return deleteUserAttempt(ctx, tx, id, opts...)",
+		`if snapsqlgo.ResolveSavepoint(ctx, executor, "DeleteUser", "delete", opts...) {`,
+		`return snapsqlgo.RunFuncInSavepoint(ctx, executor, "postgres", func(tx snapsqlgo.DBExecutor) (sql.Result, error) {`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code does not contain %q\n%s", want, code)
+		}
+	}
+
+	if code := generate(listFormat, true); strings.Contains(code, "ResolveSavepoint") {
+		t.Errorf("savepoints must not be generated for select functions")
+	}
+
+	if code := generate(deleteFormat, false); strings.Contains(code, "ResolveTxPropagation") {
+		t.Errorf("transaction propagation must not be generated when transactions is disabled")
+	}
+
+	var helper strings.Builder
+	if err := (&Generator{PackageName: "testgen"}).GenerateTxHelper(&helper); err != nil {
+		t.Fatalf("GenerateTxHelper returned error: %v", err)
+	}
+
+	if want := "return snapsqlgo.RunInTx(ctx, db, nil, fn)"; !strings.Contains(helper.String(), want) {
+		t.Errorf("transaction helper does not contain %q\n%s", want, helper.String())
+	}
+}
+
+func TestGenerateExecutorRouting(t *testing.T) {
+	for _, tc := range []struct {
+		statementType string
+		want          string
+	}{
+		{"select", `executor = snapsqlgo.RouteExecutor(ctx, executor, "FindUser", "select", opts...)`},
+		{"update", `executor = snapsqlgo.RouteExecutor(ctx, executor, "FindUser", "update", opts...)`},
+	} {
+		format := &intermediate.IntermediateFormat{
+			FormatVersion:    "1",
+			FunctionName:     "find_user",
+			StatementType:    tc.statementType,
+			ResponseAffinity: "none",
+			Instructions: []intermediate.Instruction{
+				{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "SELECT 1"},
+			},
+		}
+
+		var out strings.Builder
+
+		generator := &Generator{PackageName: "testgen", Format: format, Dialect: "postgres"}
+		if err := generator.Generate(&out); err != nil {
+			t.Fatalf("Generate returned error: %v", err)
+		}
+
+		if !strings.Contains(out.String(), tc.want) {
+			t.Errorf("generated code does not contain %q\n%s", tc.want, out.String())
+		}
+	}
+}
+
+func TestGenerateExecutionWrapper(t *testing.T) {
+	format := &intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "delete_user",
+		StatementType:    "delete",
+		ResponseAffinity: "none",
+		Parameters:       []intermediate.Parameter{{Name: "id", Type: "int"}},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "DELETE FROM users WHERE id = 1"},
+		},
+	}
+
+	var out strings.Builder
+
+	generator := &Generator{PackageName: "testgen", Format: format, Dialect: "mysql"}
+	if err := generator.Generate(&out); err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	code := out.String()
+	for _, want := range []string{
+		`retryOpts := snapsqlgo.ResolveRetryOptions(ctx, "DeleteUser", "mysql", "delete", opts...)`,
+		"return snapsqlgo.Retry(ctx, retryOpts, executor, func(ctx context.Context) (sql.Result, error) {",
+		"return deleteUserAttempt(ctx, executor, id, opts...)",
+		"func deleteUserAttempt(ctx context.Context, executor snapsqlgo.DBExecutor, id int, opts ...snapsqlgo.FuncOpt) (sql.Result, error) {",
+		"stmt, err := snapsqlgo.PrepareContext(ctx, executor, query)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code does not contain %q\n%s", want, code)
+		}
+	}
+}
+
+func TestGenerateDryRun(t *testing.T) {
+	generate := func(format *intermediate.IntermediateFormat) string {
+		t.Helper()
+
+		var out strings.Builder
+
+		generator := &Generator{PackageName: "testgen", Format: format, Dialect: "postgres"}
+		if err := generator.Generate(&out); err != nil {
+			t.Fatalf("Generate returned error: %v", err)
+		}
+
+		return out.String()
+	}
+
+	code := generate(&intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "delete_user",
+		StatementType:    "delete",
+		ResponseAffinity: "none",
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "DELETE FROM users WHERE id = 1"},
+		},
+	})
+	for _, want := range []string{
+		`DryRun:        snapsqlgo.IsDryRun(ctx, "DeleteUser", "delete", opts...),`,
+		"if queryLogOptions.DryRun {\n\t\t// Dry run: the statement is logged but not sent to the database\n\t\treturn snapsqlgo.NewMockResult(0, 0), nil",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code does not contain %q\n%s", want, code)
+		}
+	}
+
+	if strings.Index(code, "if queryLogOptions.DryRun {") > strings.Index(code, "snapsqlgo.PrepareContext(ctx, executor, query)") {
+		t.Errorf("dry run must return before the statement is prepared\n%s", code)
+	}
+
+	code = generate(&intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "list_users",
+		StatementType:    "select",
+		ResponseAffinity: "many",
+		Responses:        []intermediate.Response{{Name: "id", Type: "int"}},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "SELECT id FROM users"},
+		},
+	})
+	if want := "if queryLogOptions.DryRun {\n\t\t\t// Dry run: the statement is logged but not sent to the database\n\t\t\treturn\n"; !strings.Contains(code, want) {
+		t.Errorf("generated code does not contain %q\n%s", want, code)
+	}
+}
+
+func TestGenerateSoftDeleteFilter(t *testing.T) {
+	format := &intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "list_users",
+		StatementType:    "select",
+		ResponseAffinity: "many",
+		Responses:        []intermediate.Response{{Name: "id", Type: "int"}},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "SELECT id FROM users u"},
+			{Op: intermediate.OpEmitSystemSoftDelete, Value: "WHERE u.deleted_at IS NULL"},
+			{Op: intermediate.OpEmitStatic, Pos: "1:24", Value: " ORDER BY id"},
+		},
+	}
+
+	var out strings.Builder
+
+	generator := &Generator{PackageName: "testgen", Format: format, Dialect: "postgres"}
+	if err := generator.Generate(&out); err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	code := out.String()
+	for _, want := range []string{
+		`if !snapsqlgo.IncludeDeleted(ctx, "ListUsers", "select", opts...) { // soft delete filter`,
+		`builder.WriteString(" WHERE u.deleted_at IS NULL")`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code does not contain %q\n%s", want, code)
+		}
+	}
+}
+
+func TestGenerateIdent(t *testing.T) {
+	format := &intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "sorted_users",
+		StatementType:    "select",
+		ResponseAffinity: "many",
+		Parameters:       []intermediate.Parameter{{Name: "sort", Type: "string"}},
+		Responses:        []intermediate.Response{{Name: "id", Type: "int"}},
+		CELExpressions:   []intermediate.CELExpression{{ID: "expr_001", Expression: "sort"}},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "SELECT id FROM users ORDER BY "},
+			{Op: intermediate.OpEmitIdent, Pos: "1:31", ExprIndex: intPtr(0), Allowed: []string{"name", "created_at"}},
+		},
+	}
+
+	var out strings.Builder
+
+	generator := &Generator{PackageName: "testgen", Format: format, Dialect: "postgres"}
+	if err := generator.Generate(&out); err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	code := out.String()
+	for _, want := range []string{
+		`ident, err := snapsqlgo.AllowedIdentifier(sort, "name", "created_at")`,
+		`return "", nil, fmt.Errorf("SortedUsers: %w", err)`,
+		"builder.WriteString(ident)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code does not contain %q\n%s", want, code)
+		}
+	}
+}
+
+func TestGenerateOrderBy(t *testing.T) {
+	format := &intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "sorted_users",
+		StatementType:    "select",
+		ResponseAffinity: "many",
+		Parameters:       []intermediate.Parameter{{Name: "sort", Type: "string"}},
+		Responses:        []intermediate.Response{{Name: "id", Type: "int"}},
+		CELExpressions:   []intermediate.CELExpression{{ID: "expr_001", Expression: "sort"}},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "SELECT id FROM users ORDER BY "},
+			{Op: intermediate.OpEmitOrderBy, Pos: "1:31", ExprIndex: intPtr(0), Allowed: []string{"name", "created_at DESC"}},
+			{Op: intermediate.OpEmitStatic, Pos: "1:75", Value: ", id"},
+		},
+	}
+
+	var out strings.Builder
+
+	generator := &Generator{PackageName: "testgen", Format: format, Dialect: "postgres"}
+	if err := generator.Generate(&out); err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	code := out.String()
+	for _, want := range []string{
+		`ident, err := snapsqlgo.AllowedOrderBy(sort, "name", "created_at DESC")`,
+		`return "", nil, fmt.Errorf("SortedUsers: %w", err)`,
+		"builder.WriteString(ident)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code does not contain %q\n%s", want, code)
+		}
+	}
+}
+
+func TestGenerateWhereGuardPolicy(t *testing.T) {
+	format := &intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "purge_sessions",
+		StatementType:    "delete",
+		ResponseAffinity: "none",
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "DELETE FROM sessions"},
+		},
+	}
+
+	tests := []struct {
+		name   string
+		config snapsql.WhereGuardConfig
+		want   []string
+	}{
+		{
+			name: "default policy",
+			want: []string{`snapsqlgo.EnforceNonEmptyWhereClause(ctx, "PurgeSessions", snapsqlgo.MutationDelete, whereMeta, query); err != nil`},
+		},
+		{
+			name:   "allowed with external audit",
+			config: snapsql.WhereGuardConfig{Mode: "warn", Allow: []string{"purge_*"}, Audit: "github.com/acme/audit.Record"},
+			want: []string{
+				`"github.com/acme/audit"`,
+				`whereMeta, query, snapsqlgo.WhereGuardPolicy{Mode: snapsqlgo.WhereGuardModeWarn, Allowed: true, Audit: audit.Record}); err != nil`,
+			},
+		},
+		{
+			name:   "local audit",
+			config: snapsql.WhereGuardConfig{Allow: []string{"reset_*"}, Audit: "auditMutation"},
+			want:   []string{`whereMeta, query, snapsqlgo.WhereGuardPolicy{Audit: auditMutation}); err != nil`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+
+			generator := New(format, WithPackageName("testgen"), WithDialect("postgres"), WithWhereGuard(tt.config))
+			if err := generator.Generate(&out); err != nil {
+				t.Fatalf("Generate returned error: %v", err)
+			}
+
+			code := out.String()
+			for _, want := range tt.want {
+				if !strings.Contains(code, want) {
+					t.Errorf("generated code does not contain %q\n%s", want, code)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateCursorPage(t *testing.T) {
+	newFormat := func() *intermediate.IntermediateFormat {
+		return &intermediate.IntermediateFormat{
+			FormatVersion:    "1",
+			FunctionName:     "list_posts",
+			StatementType:    "select",
+			ResponseAffinity: "many",
+			Responses:        []intermediate.Response{{Name: "id", Type: "int"}, {Name: "created_at", Type: "timestamp"}},
+			Instructions: []intermediate.Instruction{
+				{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "SELECT id, created_at FROM posts "},
+				{Op: intermediate.OpEmitCursorCondition, Value: "WHERE (created_at < ? OR (created_at = ? AND id < ?))"},
+				{Op: intermediate.OpEmitStatic, Pos: "1:36", Value: " ORDER BY created_at DESC, id DESC LIMIT "},
+				{Op: intermediate.OpEmitCursorLimit, Pos: "1:77", Value: "20"},
+			},
+			CursorPagination: &intermediate.CursorPagination{
+				Keys: []intermediate.CursorKey{
+					{Column: "created_at", Field: "created_at", Descending: true},
+					{Column: "id", Field: "id", Descending: true},
+				},
+				Limit: 20,
+			},
+		}
+	}
+
+	var out strings.Builder
+
+	generator := &Generator{PackageName: "testgen", Format: newFormat(), Dialect: "postgres"}
+	if err := generator.Generate(&out); err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	code := out.String()
+	for _, want := range []string{
+		`func listPostsRows(ctx context.Context, executor snapsqlgo.DBExecutor, after string, opts ...snapsqlgo.FuncOpt) iter.Seq2[*ListPostsResult, error] {`,
+		`cursorValues, err := snapsqlgo.DecodeCursor(after, 2)`,
+		`args = append(args, cursorValues[0], cursorValues[0], cursorValues[1])`,
+		`builder.WriteString("21")`,
+		`func ListPosts(ctx context.Context, executor snapsqlgo.DBExecutor, after string, opts ...snapsqlgo.FuncOpt) (snapsqlgo.CursorPage[ListPostsResult], error) {`,
+		`return []any{row.CreatedAt, row.ID}`,
+		`return ListPosts(ctx, executor, after, opts...)`,
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code does not contain %q\n%s", want, code)
+		}
+	}
+
+	conflict := newFormat()
+	conflict.Parameters = []intermediate.Parameter{{Name: "after", Type: "string"}}
+
+	generator = &Generator{PackageName: "testgen", Format: conflict, Dialect: "postgres"}
+	if err := generator.Generate(&out); !errors.Is(err, ErrGenerateGoCode) {
+		t.Fatalf("expected ErrGenerateGoCode for the after parameter, got %v", err)
+	}
+}
+
+func TestGenerateLimitGuard(t *testing.T) {
+	sizeExpr := 0
+
+	generate := func(format *intermediate.IntermediateFormat) string {
+		t.Helper()
+
+		var out strings.Builder
+
+		generator := &Generator{PackageName: "testgen", Format: format, Dialect: "postgres"}
+		if err := generator.Generate(&out); err != nil {
+			t.Fatalf("Generate returned error: %v", err)
+		}
+
+		return out.String()
+	}
+
+	clamped := generate(&intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "list_users",
+		StatementType:    "select",
+		ResponseAffinity: "many",
+		Responses:        []intermediate.Response{{Name: "id", Type: "int"}},
+		Parameters:       []intermediate.Parameter{{Name: "size", Type: "int"}},
+		CELExpressions:   []intermediate.CELExpression{{ID: "expr_001", Expression: "size", EnvironmentIndex: 0}},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "SELECT id FROM users LIMIT "},
+			{Op: intermediate.OpIfSystemLimit, DefaultValue: "100"},
+			{Op: intermediate.OpEmitSystemLimit, Pos: "1:28", ExprIndex: &sizeExpr, DefaultValue: "100"},
+			{Op: intermediate.OpEnd},
+		},
+		LimitGuard: &intermediate.LimitGuard{MaxLimit: 100, Strict: true},
+	})
+
+	if !strings.Contains(clamped, "args = append(args, snapsqlgo.ClampLimit(size, 100))") {
+		t.Errorf("generated code does not clamp the LIMIT parameter\n%s", clamped)
+	}
+
+	if strings.Contains(clamped, "snapsqlgo.EnforceLimit") {
+		t.Errorf("bounded SELECT must not enforce LIMIT\n%s", clamped)
+	}
+
+	unbounded := generate(&intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "export_users",
+		StatementType:    "select",
+		ResponseAffinity: "many",
+		Responses:        []intermediate.Response{{Name: "id", Type: "int"}},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "SELECT id FROM users"},
+		},
+		LimitGuard: &intermediate.LimitGuard{Strict: true, Unbounded: true},
+	})
+
+	if !strings.Contains(unbounded, `if err := snapsqlgo.EnforceLimit(ctx, "ExportUsers", opts...); err != nil {`) {
+		t.Errorf("generated code does not enforce LIMIT\n%s", unbounded)
+	}
+}
+
+func TestGenerateRedactedArgs(t *testing.T) {
+	passwordExpr := 0
+	idExpr := 1
+
+	format := &intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "update_password",
+		StatementType:    "update",
+		ResponseAffinity: "none",
+		Parameters: []intermediate.Parameter{
+			{Name: "password", Type: "string"},
+			{Name: "user_id", Type: "int"},
+		},
+		CELExpressions: []intermediate.CELExpression{
+			{ID: "expr_001", Expression: "password", EnvironmentIndex: 0},
+			{ID: "expr_002", Expression: "user_id", EnvironmentIndex: 0},
+		},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "UPDATE users SET password = "},
+			{Op: intermediate.OpEmitEval, Pos: "1:30", ExprIndex: &passwordExpr},
+			{Op: intermediate.OpEmitStatic, Pos: "1:40", Value: " WHERE id = "},
+			{Op: intermediate.OpEmitEval, Pos: "1:52", ExprIndex: &idExpr},
+		},
+	}
+
+	generate := func(redact ...string) string {
+		t.Helper()
+
+		var out strings.Builder
+
+		generator := New(format, WithPackageName("testgen"), WithDialect("postgres"), WithRedactParams(redact...))
+		if err := generator.Generate(&out); err != nil {
+			t.Fatalf("Generate returned error: %v", err)
+		}
+
+		return out.String()
+	}
+
+	code := generate("PASSWORD", "email")
+	if got := strings.Count(code, "redactedArgs = append(redactedArgs, len(args))"); got != 1 {
+		t.Errorf("expected one redacted argument, got %d\n%s", got, code)
+	}
+
+	if !strings.Contains(code, "RedactedArgs: redactedArgs,") {
+		t.Errorf("expected RedactedArgs in query log metadata\n%s", code)
+	}
+
+	if code := generate(); strings.Contains(code, "redactedArgs") {
+		t.Errorf("redaction code must not be generated without query_log.redact")
+	}
+}
+
+func TestGenerateRegistryEntry(t *testing.T) {
+	idExpr := 0
+	activeExpr := 1
+
+	format := &intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "delete_user",
+		Description:      "removes a user",
+		StatementType:    "delete",
+		ResponseAffinity: "none",
+		Parameters: []intermediate.Parameter{
+			{Name: "user_id", Type: "int"},
+			{Name: "active", Type: "bool", Optional: true},
+		},
+		CELExpressions: []intermediate.CELExpression{
+			{ID: "expr_001", Expression: "user_id", EnvironmentIndex: 0},
+			{ID: "expr_002", Expression: "active", EnvironmentIndex: 0},
+		},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "DELETE FROM users WHERE id = "},
+			{Op: intermediate.OpEmitEval, Pos: "1:30", ExprIndex: &idExpr},
+			{Op: intermediate.OpIf, Pos: "1:40", ExprIndex: &activeExpr},
+			{Op: intermediate.OpEmitStatic, Pos: "1:60", Value: " AND active"},
+			{Op: intermediate.OpEnd, Pos: "1:80"},
+		},
+	}
+
+	var out strings.Builder
+
+	generator := New(format, WithPackageName("testgen"), WithDialect("postgres"))
+	if err := generator.Generate(&out); err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	// gofmt aligns the composite literal, so compare with collapsed whitespace
+	code := strings.Join(strings.Fields(out.String()), " ")
+	for _, want := range []string{
+		"snapsqlgo.Registry.Register(snapsqlgo.QueryInfo{",
+		`Package: "testgen",`,
+		`Description: "removes a user",`,
+		`SQL: "DELETE FROM users WHERE id = /*= user_id */?/*# if active */ AND active/*# end */",`,
+		`{Name: "user_id", GoName: "userID", Type: "int", Optional: false},`,
+		`{Name: "active", GoName: "active", Type: "bool", Optional: true},`,
+		`userID, err := snapsqlgo.RegistryParam[int](registryParams, "user_id")`,
+		"return DeleteUser(ctx, executor, userID, active, opts...)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code does not contain %q\n%s", want, code)
+		}
+	}
+}
+
+func TestGenerateTemplateFunctionCall(t *testing.T) {
+	emailExpr := 0
+
+	format := &intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "delete_by_email",
+		StatementType:    "delete",
+		ResponseAffinity: "none",
+		Parameters: []intermediate.Parameter{
+			{Name: "email", Type: "string"},
+		},
+		CELExpressions: []intermediate.CELExpression{
+			{ID: "expr_001", Expression: "normalizeEmail(email)", EnvironmentIndex: 0},
+		},
+		Expressions: []intermediate.ExplangExpression{
+			{
+				ID:       "expr_001",
+				Function: "normalizeEmail",
+				Args:     [][]intermediate.Expressions{{{Kind: intermediate.StepIdentifier, Identifier: "email"}}},
+			},
+		},
+		TemplateFunctions: []intermediate.TemplateFunction{
+			{Name: "normalizeEmail", Args: []string{"string"}, Returns: "string", GoPackage: "github.com/acme/helpers/v2", GoName: "NormalizeEmail"},
+		},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "DELETE FROM users WHERE email = "},
+			{Op: intermediate.OpEmitEval, Pos: "1:33", ExprIndex: &emailExpr},
+		},
+	}
+
+	var out strings.Builder
+
+	generator := New(format, WithPackageName("testgen"), WithDialect("postgres"))
+	if err := generator.Generate(&out); err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	code := out.String()
+	for _, want := range []string{
+		`"github.com/acme/helpers/v2"`,
+		"call0 := helpers.NormalizeEmail(email)",
+		"args = append(args, snapsqlgo.NormalizeNullableTimestamp(call0))",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code does not contain %q\n%s", want, code)
+		}
+	}
+
+	format.TemplateFunctions = nil
+
+	if err := New(format, WithPackageName("testgen"), WithDialect("postgres")).Generate(&out); !errors.Is(err, ErrUnknownTemplateFunction) {
+		t.Errorf("expected ErrUnknownTemplateFunction, got %v", err)
+	}
+}
+
+func TestRenderRegistrySQLOmitsSystemLimit(t *testing.T) {
+	format := &intermediate.IntermediateFormat{
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Value: "SELECT id FROM users LIMIT "},
+			{Op: intermediate.OpIfSystemLimit},
+			{Op: intermediate.OpEmitSystemLimit},
+			{Op: intermediate.OpElse},
+			{Op: intermediate.OpEmitStatic, Value: "10"},
+			{Op: intermediate.OpEnd},
+		},
+	}
+
+	if got := renderRegistrySQL(format); got != "SELECT id FROM users LIMIT 10" {
+		t.Errorf("unexpected SQL template: %q", got)
+	}
+}
+
+func TestGenerateReturningFollowUp(t *testing.T) {
+	followUp := &intermediate.ReturningFollowUp{
+		Table:     "users",
+		KeyColumn: "id",
+		SQL:       "SELECT id, name FROM users WHERE id >= ? AND id < ? ORDER BY id",
+	}
+
+	for _, affinity := range []string{"one", "many"} {
+		t.Run(affinity, func(t *testing.T) {
+			format := &intermediate.IntermediateFormat{
+				FormatVersion:    "1",
+				FunctionName:     "insert_user",
+				StatementType:    "insert",
+				ResponseAffinity: affinity,
+				Responses: []intermediate.Response{
+					{Name: "id", Type: "int"},
+					{Name: "name", Type: "string"},
+				},
+				Instructions: []intermediate.Instruction{
+					{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "INSERT INTO users (name) VALUES ('alice')"},
+				},
+				ReturningFollowUp: followUp,
+			}
+
+			var out strings.Builder
+
+			generator := &Generator{PackageName: "testgen", Format: format, Dialect: "mysql"}
+			if err := generator.Generate(&out); err != nil {
+				t.Fatalf("Generate returned error: %v", err)
+			}
+
+			code := out.String()
+			for _, want := range []string{
+				"insertResult, err := ",
+				"insertedID, err := insertResult.LastInsertId()",
+				"insertedCount, err := insertResult.RowsAffected()",
+				`"SELECT id, name FROM users WHERE id >= ? AND id < ? ORDER BY id"`,
+			} {
+				if !strings.Contains(code, want) {
+					t.Errorf("generated code does not contain %q\n%s", want, code)
+				}
+			}
+
+			if strings.Contains(code, "QueryRowContext") || strings.Contains(code, "QueryStream(ctx, executor, query") {
+				t.Errorf("the INSERT must be executed instead of queried\n%s", code)
+			}
+		})
+	}
+}
@@ -174,8 +174,15 @@ func generateHierarchicalStructs(functionName string, nodes map[string]*node, ro
 			b.WriteString(fmt.Sprintf("\t%s %s `json:\"%s\"`\n", f.Name, f.GoType, f.JSONTag))
 		}
 		// Child slice fields (pointer slices to allow later in-place mutation)
-		// We add after fields for stability
+		// We add after fields, sorted by name for stability
+		childSegs := make([]string, 0, len(n.Children))
 		for childSeg := range n.Children {
+			childSegs = append(childSegs, childSeg)
+		}
+
+		sort.Strings(childSegs)
+
+		for _, childSeg := range childSegs {
 			childPath := append(append([]string{}, n.PathSegments...), childSeg)
 			childStruct := structNames[pathKey(childPath)]
 			b.WriteString(fmt.Sprintf("\t%s []*%s `json:\"%s\"`\n", celNameToGoName(childSeg), childStruct, childSeg))
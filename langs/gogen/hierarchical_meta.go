@@ -10,8 +10,9 @@ import (
 )
 
 var (
-	ErrHierarchicalNoKeys = errors.New("hierarchical node has no key fields")
-	ErrMultipleRootKeys   = errors.New("multiple root key fields detected; multi-root not supported")
+	ErrHierarchicalNoKeys   = errors.New("hierarchical node has no key fields")
+	ErrMultipleRootKeys     = errors.New("multiple root key fields detected; multi-root not supported")
+	ErrHierarchicalNoParent = errors.New("hierarchical node has no parent node columns")
 )
 
 // hierarchicalNodeMeta represents one hierarchical grouping unit (a path of segments like ["lists","cards"]).
@@ -128,6 +129,10 @@ func buildHierarchicalNodeMetas(functionName string, responses []intermediate.Re
 		if len(meta.KeyFields) == 0 {
 			return nil, fmt.Errorf("%w (node=%s, depth=%d)", ErrHierarchicalNoKeys, k, depth)
 		}
+		// 中間ノード（a__b__c に対する a__b）にも列が無いと親の集約先が決まらない
+		if depth > 1 && groups[strings.Join(parentPath, "__")] == nil {
+			return nil, fmt.Errorf("%w (node=%s, parent=%s)", ErrHierarchicalNoParent, k, strings.Join(parentPath, "__"))
+		}
 
 		metas = append(metas, meta)
 	}
@@ -0,0 +1,162 @@
+package gogen
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+
+	"github.com/shibukawa/snapsql/testdata/gogenruntime/ordertree"
+	ordertreepg "github.com/shibukawa/snapsql/testdata/jsonhierarchy/postgres"
+	ordertreesqlite "github.com/shibukawa/snapsql/testdata/jsonhierarchy/sqlite"
+)
+
+// orderTreeFixture creates the tables of 053_join_three_levels_ok. Rows are inserted out of key
+// order so that the result order must come from ORDER BY, not from insertion.
+var orderTreeFixture = []string{
+	`CREATE TABLE orders (id INTEGER PRIMARY KEY, customer TEXT NOT NULL)`,
+	`CREATE TABLE items (id INTEGER PRIMARY KEY, order_id INTEGER NOT NULL, product TEXT NOT NULL, quantity INTEGER NOT NULL)`,
+	`CREATE TABLE item_options (item_id INTEGER NOT NULL, code TEXT NOT NULL, value TEXT, PRIMARY KEY (item_id, code))`,
+	`CREATE TABLE payments (id INTEGER PRIMARY KEY, order_id INTEGER NOT NULL, amount INTEGER NOT NULL)`,
+	`INSERT INTO orders (id, customer) VALUES (2, 'bob'), (1, 'alice'), (3, 'carol')`,
+	`INSERT INTO items (id, order_id, product, quantity) VALUES (11, 1, 'tea', 1), (10, 1, 'coffee', 2), (20, 2, 'cake', 1)`,
+	`INSERT INTO item_options (item_id, code, value) VALUES (10, 'size', 'large'), (10, 'milk', NULL), (11, 'size', 'small'), (20, 'size', 'small')`,
+	`INSERT INTO payments (id, order_id, amount) VALUES (101, 1, 300), (100, 1, 200)`,
+}
+
+func TestHierarchicalAggregationSQLite(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	want := assertOrderTrees(t, db)
+
+	// hierarchy_mode: json returns the same tree
+	assertSameOrderTrees(t, want, func(ctx context.Context) (any, error) { return ordertreesqlite.ListOrderTrees(ctx, db) })
+}
+
+func TestHierarchicalAggregationPostgres(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping PostgreSQL integration test in short mode")
+	}
+
+	pgContainer, err := postgres.Run(t.Context(),
+		"postgres:18-alpine",
+		postgres.WithDatabase("testdb"),
+		postgres.WithUsername("testuser"),
+		postgres.WithPassword("testpass"),
+		postgres.BasicWaitStrategies(),
+	)
+	if err != nil {
+		t.Fatalf("failed to start container: %v", err)
+	}
+
+	defer func() {
+		if err := pgContainer.Terminate(context.Background()); err != nil {
+			t.Fatalf("failed to terminate container: %v", err)
+		}
+	}()
+
+	connStr, err := pgContainer.ConnectionString(t.Context(), "sslmode=disable")
+	if err != nil {
+		t.Fatalf("failed to get connection string: %v", err)
+	}
+
+	db, err := sql.Open("pgx", connStr)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	want := assertOrderTrees(t, db)
+
+	assertSameOrderTrees(t, want, func(ctx context.Context) (any, error) { return ordertreepg.ListOrderTrees(ctx, db) })
+}
+
+// assertOrderTrees loads the fixture, checks the tree aggregated from rows and returns it as JSON
+func assertOrderTrees(t *testing.T, db *sql.DB) string {
+	t.Helper()
+
+	for _, stmt := range orderTreeFixture {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("failed to execute %q: %v", stmt, err)
+		}
+	}
+
+	// Run twice: the order must not depend on map iteration
+	for range 2 {
+		orders, err := ordertree.ListOrderTrees(t.Context(), db)
+		if err != nil {
+			t.Fatalf("ListOrderTrees returned error: %v", err)
+		}
+
+		if len(orders) != 3 || orders[0].ID != 1 || orders[1].ID != 2 || orders[2].ID != 3 {
+			t.Fatalf("unexpected orders: %+v", orders)
+		}
+
+		alice := orders[0]
+		// items x options x payments are joined, so every item and option appears in several rows
+		if len(alice.Items) != 2 || alice.Items[0].Product != "coffee" || alice.Items[1].Product != "tea" {
+			t.Fatalf("unexpected items of order 1: %+v", alice.Items)
+		}
+
+		if len(alice.Payments) != 2 || alice.Payments[0].ID != 100 || alice.Payments[1].Amount != 300 {
+			t.Fatalf("unexpected payments of order 1: %+v", alice.Payments)
+		}
+
+		coffee := alice.Items[0].Options
+		if len(coffee) != 2 || coffee[0].Code != "milk" || coffee[0].Value != nil || coffee[1].Code != "size" || *coffee[1].Value != "large" {
+			t.Fatalf("unexpected options of coffee: %+v", coffee)
+		}
+
+		// The same option key under another item is another node
+		if tea := alice.Items[1].Options; len(tea) != 1 || tea[0].ItemID != 11 || *tea[0].Value != "small" {
+			t.Fatalf("unexpected options of tea: %+v", tea)
+		}
+
+		if bob := orders[1]; len(bob.Items) != 1 || len(bob.Items[0].Options) != 1 || len(bob.Payments) != 0 {
+			t.Fatalf("unexpected order 2: %+v", bob)
+		}
+
+		// LEFT JOIN without matches keeps empty slices
+		if carol := orders[2]; carol.Items == nil || len(carol.Items) != 0 || len(carol.Payments) != 0 {
+			t.Fatalf("unexpected order 3: %+v", carol)
+		}
+	}
+
+	orders, err := ordertree.ListOrderTrees(t.Context(), db)
+	if err != nil {
+		t.Fatalf("ListOrderTrees returned error: %v", err)
+	}
+
+	data, err := json.Marshal(orders)
+	if err != nil {
+		t.Fatalf("failed to marshal orders: %v", err)
+	}
+
+	return string(data)
+}
+
+func assertSameOrderTrees(t *testing.T, want string, list func(ctx context.Context) (any, error)) {
+	t.Helper()
+
+	orders, err := list(t.Context())
+	if err != nil {
+		t.Fatalf("ListOrderTrees returned error: %v", err)
+	}
+
+	data, err := json.Marshal(orders)
+	if err != nil {
+		t.Fatalf("failed to marshal orders: %v", err)
+	}
+
+	if string(data) != want {
+		t.Errorf("JSON aggregated tree differs\n got: %s\nwant: %s", data, want)
+	}
+}
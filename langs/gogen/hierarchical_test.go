@@ -1,6 +1,7 @@
 package gogen
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/shibukawa/snapsql/intermediate"
@@ -61,3 +62,15 @@ func TestGenerateHierarchicalStructs_MultiLevel(t *testing.T) {
 		t.Errorf("expected at least 3 nested structs, got %d", len(structs))
 	}
 }
+
+func TestBuildHierarchicalNodeMetas_MissingParent(t *testing.T) {
+	// a__b__id without any a__ column: the b nodes have nothing to attach to
+	responses := []intermediate.Response{
+		{Name: "id", Type: "int", HierarchyKeyLevel: 1},
+		{Name: "a__b__id", Type: "int", HierarchyKeyLevel: 3},
+	}
+
+	if _, err := buildHierarchicalNodeMetas("sample_query", responses); !errors.Is(err, ErrHierarchicalNoParent) {
+		t.Errorf("expected ErrHierarchicalNoParent, got %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+package gogen
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+)
+
+// Hierarchy modes select how hierarchical (parent__child) responses are aggregated (hierarchy_mode setting)
+const (
+	// HierarchyModeRows scans the joined rows and aggregates them in Go (default)
+	HierarchyModeRows = "rows"
+	// HierarchyModeJSON aggregates the children into JSON columns in the database
+	HierarchyModeJSON = "json"
+)
+
+// jsonRowsCTE and jsonRowNumber name the helpers of the wrapping query
+const (
+	jsonRowsCTE   = "_snapsql_rows"
+	jsonRowNumber = "_snapsql_rn"
+)
+
+// jsonHierarchyData wraps the original query: Prefix + query + Suffix returns one row per parent
+// with a JSON array column for each top-level child
+type jsonHierarchyData struct {
+	Prefix string
+	Suffix string
+	Code   []string
+}
+
+// jsonDialect holds the JSON functions of a dialect
+type jsonDialect struct {
+	object     string // function building an object from key/value pairs
+	arrayAgg   string // aggregate building an array; %s is the element, %s the order key
+	emptyArray string
+	nested     string // wraps a nested array so that it is embedded as JSON, not as a string
+	orderRows  bool   // order the rows of the derived table when the aggregate has no ORDER BY
+}
+
+var jsonDialects = map[snapsql.Dialect]jsonDialect{
+	snapsql.DialectPostgres:  {object: "json_build_object", arrayAgg: "json_agg(%s ORDER BY %s)", emptyArray: "'[]'::json", nested: "%s"},
+	snapsql.DialectCockroach: {object: "json_build_object", arrayAgg: "json_agg(%s ORDER BY %s)", emptyArray: "'[]'::json", nested: "%s"},
+	snapsql.DialectMySQL:     {object: "JSON_OBJECT", arrayAgg: "JSON_ARRAYAGG(%s)", emptyArray: "JSON_ARRAY()", nested: "CAST(%s AS JSON)", orderRows: true},
+	snapsql.DialectMariaDB:   {object: "JSON_OBJECT", arrayAgg: "JSON_ARRAYAGG(%s)", emptyArray: "JSON_ARRAY()", nested: "JSON_EXTRACT(%s, '$')", orderRows: true},
+	snapsql.DialectSQLite:    {object: "json_object", arrayAgg: "json_group_array(%s)", emptyArray: "'[]'", nested: "json(%s)", orderRows: true},
+}
+
+func validateHierarchyMode(mode string) error {
+	switch mode {
+	case "", HierarchyModeRows, HierarchyModeJSON:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q (must be rows or json)", ErrInvalidHierarchyMode, mode)
+}
+
+// buildJSONHierarchy rewrites a hierarchical SELECT so that the database returns one row per
+// parent: the root columns followed by a JSON array for each top-level child, nested children
+// included. The joined rows are numbered first so that every array keeps the order of the
+// original ORDER BY. The generated code unmarshals the arrays into the child struct slices.
+func buildJSONHierarchy(dialect snapsql.Dialect, responseStruct *responseStructData, metas []*hierarchicalNodeMeta, isMany bool, guard rowGuard) (*jsonHierarchyData, error) {
+	funcs, ok := jsonDialects[dialect]
+	if !ok {
+		return nil, fmt.Errorf("%w: dialect %q", ErrJSONHierarchyUnsupported, dialect)
+	}
+
+	if responseStruct == nil || len(metas) == 0 {
+		return nil, fmt.Errorf("%w: every level needs its primary key columns", ErrJSONHierarchyUnsupported)
+	}
+
+	var rootCols, rootKeys []string
+
+	for _, r := range responseStruct.RawResponses {
+		if strings.Contains(r.Name, "__") {
+			continue
+		}
+
+		rootCols = append(rootCols, r.Name)
+		if r.HierarchyKeyLevel == 1 {
+			rootKeys = append(rootKeys, r.Name)
+		}
+	}
+
+	if len(rootKeys) == 0 {
+		return nil, fmt.Errorf("%w: %w", ErrJSONHierarchyUnsupported, snapsql.ErrHierarchicalNoParentPrimaryKey)
+	}
+
+	b := &jsonQueryBuilder{funcs: funcs, responses: responseStruct.RawResponses, metas: metas}
+
+	selects := make([]string, 0, len(rootCols)+len(metas))
+	for _, col := range rootCols {
+		selects = append(selects, "_r0."+col)
+	}
+
+	conds := make([]string, 0, len(rootKeys))
+	for _, key := range rootKeys {
+		conds = append(conds, fmt.Sprintf("%s = _r0.%s", key, key))
+	}
+
+	var top []*hierarchicalNodeMeta
+
+	for _, m := range metas {
+		if m.Depth == 1 {
+			top = append(top, m)
+			selects = append(selects, b.array(m, conds)+" AS "+m.Path[0])
+		}
+	}
+
+	columns := strings.Join(rootCols, ", ")
+	suffix := fmt.Sprintf(") _q) SELECT %s FROM (SELECT %s, MIN(%s) AS %s FROM %s GROUP BY %s) _r0 ORDER BY _r0.%s",
+		strings.Join(selects, ", "), columns, jsonRowNumber, jsonRowNumber, jsonRowsCTE, columns, jsonRowNumber)
+
+	return &jsonHierarchyData{
+		Prefix: fmt.Sprintf("WITH %s AS (SELECT _q.*, ROW_NUMBER() OVER () AS %s FROM (", jsonRowsCTE, jsonRowNumber),
+		Suffix: suffix,
+		Code:   generateJSONHierarchyScanCode(responseStruct, top, isMany, guard),
+	}, nil
+}
+
+type jsonQueryBuilder struct {
+	funcs     jsonDialect
+	responses []intermediate.Response
+	metas     []*hierarchicalNodeMeta
+}
+
+// array returns the expression of the JSON array of node m. conds correlate the rows with the
+// keys of the ancestors.
+func (b *jsonQueryBuilder) array(m *hierarchicalNodeMeta, conds []string) string {
+	alias := fmt.Sprintf("_r%d", m.Depth)
+
+	var columns, pairs []string
+
+	prefix := strings.Join(m.Path, "__") + "__"
+	for _, r := range b.responses {
+		if leaf, ok := strings.CutPrefix(r.Name, prefix); ok && !strings.Contains(leaf, "__") {
+			columns = append(columns, r.Name)
+			pairs = append(pairs, fmt.Sprintf("'%s', %s.%s", leaf, alias, r.Name))
+		}
+	}
+
+	own := slices.Clone(conds)
+	for _, key := range m.KeyFields {
+		own = append(own, key+" IS NOT NULL")
+	}
+
+	children := slices.Clone(conds)
+	for _, key := range m.KeyFields {
+		children = append(children, fmt.Sprintf("%s = %s.%s", key, alias, key))
+	}
+
+	for _, child := range childMetas(b.metas, m) {
+		seg := child.Path[len(child.Path)-1]
+		pairs = append(pairs, fmt.Sprintf("'%s', %s", seg, fmt.Sprintf(b.funcs.nested, b.array(child, children))))
+	}
+
+	inner := fmt.Sprintf("SELECT %s, MIN(%s) AS %s FROM %s WHERE %s GROUP BY %s",
+		strings.Join(columns, ", "), jsonRowNumber, jsonRowNumber, jsonRowsCTE, strings.Join(own, " AND "), strings.Join(columns, ", "))
+	if b.funcs.orderRows {
+		inner += " ORDER BY " + jsonRowNumber
+	}
+
+	object := fmt.Sprintf("%s(%s)", b.funcs.object, strings.Join(pairs, ", "))
+
+	var agg string
+	if strings.Count(b.funcs.arrayAgg, "%s") == 2 {
+		agg = fmt.Sprintf(b.funcs.arrayAgg, object, alias+"."+jsonRowNumber)
+	} else {
+		agg = fmt.Sprintf(b.funcs.arrayAgg, object)
+	}
+
+	return fmt.Sprintf("COALESCE((SELECT %s FROM (%s) %s), %s)", agg, inner, alias, b.funcs.emptyArray)
+}
+
+// generateJSONHierarchyScanCode scans the root columns into the struct and unmarshals the JSON
+// array columns into the child slices
+func generateJSONHierarchyScanCode(responseStruct *responseStructData, top []*hierarchicalNodeMeta, isMany bool, guard rowGuard) []string {
+	target := "result"
+	if isMany {
+		target = "item"
+	}
+
+	var targets, decode []string
+
+	for _, r := range responseStruct.RawResponses {
+		if !strings.Contains(r.Name, "__") {
+			targets = append(targets, fmt.Sprintf("&%s.%s", target, celNameToGoName(r.Name)))
+		}
+	}
+
+	jsonVars := make([]string, 0, len(top))
+	for _, m := range top {
+		v := "_json_" + m.Path[0]
+		jsonVars = append(jsonVars, v)
+		targets = append(targets, "&"+v)
+		decode = append(decode,
+			fmt.Sprintf("    if err := json.Unmarshal(%s, &%s.%s); err != nil {", v, target, celNameToGoName(m.Path[0])),
+			fmt.Sprintf("        return result, fmt.Errorf(\"failed to decode %s: %%w\", err)", m.Path[0]),
+			"    }")
+	}
+
+	code := []string{
+		"// JSON aggregated hierarchy (hierarchy_mode: json): one row per parent",
+		"rows, err := stmt.QueryContext(ctx, args...)",
+		"if err != nil { return result, fmt.Errorf(\"failed to query rows: %w\", err) }",
+		"defer rows.Close()",
+	}
+
+	if !isMany {
+		code = append(code, "found := false")
+	}
+
+	code = append(code, guard.init()...)
+	code = append(code, "for rows.Next() {")
+	code = append(code, guard.check("    ", "return result, err")...)
+	if isMany {
+		code = append(code, "    var item "+responseStruct.Name)
+	} else {
+		code = append(code,
+			"    if found { return result, snapsql.ErrHierarchicalMultipleParentsForOne }",
+			"    found = true")
+	}
+
+	code = append(code, "    var "+strings.Join(jsonVars, ", ")+" []byte")
+	code = append(code, "    if err := rows.Scan("+strings.Join(targets, ", ")+"); err != nil {")
+	code = append(code, "        return result, fmt.Errorf(\"failed to scan row: %w\", err)")
+	code = append(code, "    }")
+	code = append(code, decode...)
+
+	if isMany {
+		code = append(code, "    result = append(result, item)")
+	}
+
+	code = append(code, "}")
+	code = append(code, "if err = rows.Err(); err != nil { return result, fmt.Errorf(\"error iterating rows: %w\", err) }")
+
+	if !isMany {
+		code = append(code, "if !found { return result, snapsql.ErrNotFound }")
+	}
+
+	return code
+}
@@ -0,0 +1,73 @@
+package gogen
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+)
+
+// TestJSONHierarchyGeneration writes testdata/jsonhierarchy, which the runtime tests execute
+func TestJSONHierarchyGeneration(t *testing.T) {
+	data, err := os.ReadFile("../../testdata/acceptancetests/053_join_three_levels_ok/expected.json")
+	if err != nil {
+		t.Fatalf("failed to read intermediate format: %v", err)
+	}
+
+	for _, tt := range []struct {
+		dialect snapsql.Dialect
+		dir     string
+		pkg     string
+	}{
+		{snapsql.DialectSQLite, "sqlite", "ordertreesqlite"},
+		{snapsql.DialectPostgres, "postgres", "ordertreepg"},
+	} {
+		t.Run(tt.dir, func(t *testing.T) {
+			format, err := intermediate.FromJSON(data)
+			if err != nil {
+				t.Fatalf("failed to parse intermediate format: %v", err)
+			}
+
+			var out strings.Builder
+
+			generator := New(format, WithPackageName(tt.pkg), WithDialect(tt.dialect))
+			generator.HierarchyMode = HierarchyModeJSON
+
+			if err := generator.Generate(&out); err != nil {
+				t.Fatalf("Generate returned error: %v", err)
+			}
+
+			// Written like the acceptance tests; the runtime tests import the package
+			path := filepath.Join("../../testdata/jsonhierarchy", tt.dir, "list_order_trees.go")
+			if err := os.WriteFile(path, []byte(out.String()), 0o644); err != nil {
+				t.Fatalf("failed to write %s: %v", path, err)
+			}
+
+			if !strings.Contains(out.String(), "WITH _snapsql_rows AS") || !strings.Contains(out.String(), "json.Unmarshal(_json_items, &item.Items)") {
+				t.Errorf("JSON aggregation missing\n%s", out.String())
+			}
+		})
+	}
+}
+
+func TestJSONHierarchyErrors(t *testing.T) {
+	format := userQuery("get_user", "one")
+	format.Responses = []intermediate.Response{
+		{Name: "id", Type: "int", HierarchyKeyLevel: 1},
+		{Name: "posts__id", Type: "int", HierarchyKeyLevel: 2},
+	}
+
+	generator := &Generator{PackageName: "testgen", Format: format, Dialect: snapsql.DialectClickHouse, HierarchyMode: HierarchyModeJSON}
+	if err := generator.Generate(&strings.Builder{}); !errors.Is(err, ErrJSONHierarchyUnsupported) {
+		t.Errorf("expected ErrJSONHierarchyUnsupported, got %v", err)
+	}
+
+	generator = &Generator{PackageName: "testgen", Format: format, Dialect: snapsql.DialectPostgres, HierarchyMode: "tree"}
+	if err := generator.Generate(&strings.Builder{}); !errors.Is(err, ErrInvalidHierarchyMode) {
+		t.Errorf("expected ErrInvalidHierarchyMode, got %v", err)
+	}
+}
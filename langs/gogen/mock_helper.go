@@ -0,0 +1,136 @@
+package gogen
+
+import (
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/shibukawa/snapsql/intermediate"
+)
+
+// mockHelperData is the template input for GenerateMockHelper
+type mockHelperData struct {
+	PackageName  string
+	FunctionName string
+	RowType      string // response struct name; empty when the function has no row response
+	RowImport    string // package of a RowType declared by response_type
+	ExecOnly     bool   // the function returns sql.Result
+	ScalarType   string // bool or int64 for the exists / count affinities
+	ScalarColumn string // column name of the single-column mock row of a scalar function
+}
+
+// GenerateMockHelper writes the _mock_test.go helpers for the function: typed builders such as
+// WithGetUserByIDMock(ctx, rows ...GetUserByIDResult) that install context-scoped mocks
+// through snapsqlgo.WithMock. Functions with the exists / count affinity take the scalar value instead.
+func (g *Generator) GenerateMockHelper(w io.Writer) error {
+	data := mockHelperData{
+		PackageName:  g.PackageName,
+		FunctionName: snakeToCamel(g.Format.FunctionName),
+	}
+
+	// exists/count functions have no response struct; their mock is a single-column row
+	if affinity := intermediate.ResponseAffinity(strings.ToLower(g.Format.ResponseAffinity)); affinity.IsScalar() {
+		data.ScalarType = "int64"
+		if affinity == intermediate.ResponseAffinityExists {
+			data.ScalarType = "bool"
+		}
+
+		data.ScalarColumn = "value"
+		if len(g.Format.Responses) == 1 && g.Format.Responses[0].Name != "" {
+			data.ScalarColumn = g.Format.Responses[0].Name
+		}
+
+		return writeMockHelper(w, g.Format.FunctionName, data)
+	}
+
+	responseStruct, err := processResponseStruct(g.Format)
+	if err != nil && !errors.Is(err, ErrNoResponseFields) {
+		return fmt.Errorf("failed to process response struct: %w", err)
+	}
+
+	responseType, err := processResponseType(g.Format)
+	if err != nil {
+		return fmt.Errorf("failed to process response type: %w", err)
+	}
+
+	responseType, rowImport, err := g.applyResponseType(responseType, responseStruct)
+	if err != nil {
+		return err
+	}
+
+	data.ExecOnly = responseType == "sql.Result"
+
+	if responseStruct != nil && !data.ExecOnly {
+		data.RowType = responseStruct.Name
+		data.RowImport = rowImport
+	}
+
+	return writeMockHelper(w, g.Format.FunctionName, data)
+}
+
+// writeMockHelper renders the mock helper template and formats the result
+func writeMockHelper(w io.Writer, functionName string, data mockHelperData) error {
+	var buf strings.Builder
+	if err := mockHelperTemplate.Execute(&buf, data); err != nil {
+		return fmt.Errorf("failed to execute mock helper template: %w", err)
+	}
+
+	formatted, err := format.Source([]byte(buf.String()))
+	if err != nil {
+		return fmt.Errorf("failed to format mock helper for %s: %w", functionName, err)
+	}
+
+	_, err = w.Write(formatted)
+
+	return err
+}
+
+var mockHelperTemplate = template.Must(template.New("mock_helper").Parse(`// Code generated by snapsql. DO NOT EDIT.
+
+package {{ .PackageName }}
+
+import (
+	"context"
+
+	"github.com/shibukawa/snapsql/langs/snapsqlgo"
+	{{- if .RowImport }}
+	"{{ .RowImport }}"
+	{{- end }}
+)
+{{ if .RowType }}
+// With{{ .FunctionName }}Mock installs a context-scoped mock so that {{ .FunctionName }} returns rows
+// instead of querying the database.
+func With{{ .FunctionName }}Mock(ctx context.Context, rows ...{{ .RowType }}) (context.Context, error) {
+	mockCase, err := snapsqlgo.MockRows("{{ .FunctionName }}", rows...)
+	if err != nil {
+		return ctx, err
+	}
+
+	return snapsqlgo.WithMock(ctx, "{{ .FunctionName }}", []snapsqlgo.MockCase{mockCase})
+}
+{{ else if .ScalarType }}
+// With{{ .FunctionName }}Mock installs a context-scoped mock so that {{ .FunctionName }} returns value
+// instead of querying the database.
+func With{{ .FunctionName }}Mock(ctx context.Context, value {{ .ScalarType }}) (context.Context, error) {
+	mockCase, err := snapsqlgo.MockRows("{{ .FunctionName }}", map[string]{{ .ScalarType }}{ {{ printf "%q" .ScalarColumn }}: value })
+	if err != nil {
+		return ctx, err
+	}
+
+	return snapsqlgo.WithMock(ctx, "{{ .FunctionName }}", []snapsqlgo.MockCase{mockCase})
+}
+{{ else if .ExecOnly }}
+// With{{ .FunctionName }}Mock installs a context-scoped mock so that {{ .FunctionName }} reports
+// rowsAffected instead of executing the statement.
+func With{{ .FunctionName }}Mock(ctx context.Context, rowsAffected int64) (context.Context, error) {
+	return snapsqlgo.WithMock(ctx, "{{ .FunctionName }}", []snapsqlgo.MockCase{snapsqlgo.MockExecResult("{{ .FunctionName }}", rowsAffected)})
+}
+{{ end }}
+// With{{ .FunctionName }}MockError installs a context-scoped mock so that {{ .FunctionName }} fails with err.
+func With{{ .FunctionName }}MockError(ctx context.Context, err error) (context.Context, error) {
+	return snapsqlgo.WithMock(ctx, "{{ .FunctionName }}", []snapsqlgo.MockCase{ {Name: "{{ .FunctionName }}"} }, snapsqlgo.MockOpt{Err: err})
+}
+`))
@@ -0,0 +1,76 @@
+package gogen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Nullable styles select the Go type of nullable response fields (nullable_style setting)
+const (
+	// NullableStylePointer uses *T (default)
+	NullableStylePointer = "pointer"
+	// NullableStyleSQLNull uses sql.NullString, sql.NullInt64 and the like, or sql.Null[T]
+	NullableStyleSQLNull = "sqlnull"
+	// NullableStyleOption uses snapsqlgo.Option[T]
+	NullableStyleOption = "option"
+)
+
+// sqlNullTypes are the database/sql types of nullable values that have a dedicated type
+var sqlNullTypes = map[string]string{
+	"string":    "sql.NullString",
+	"int64":     "sql.NullInt64",
+	"int32":     "sql.NullInt32",
+	"int16":     "sql.NullInt16",
+	"byte":      "sql.NullByte",
+	"float64":   "sql.NullFloat64",
+	"bool":      "sql.NullBool",
+	"time.Time": "sql.NullTime",
+}
+
+func validateNullableStyle(style string) error {
+	switch style {
+	case "", NullableStylePointer, NullableStyleSQLNull, NullableStyleOption:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q (must be pointer, sqlnull or option)", ErrInvalidNullableStyle, style)
+}
+
+// nullableType returns the type of a nullable field whose pointer type is goType
+func nullableType(goType, style string) string {
+	base, ok := strings.CutPrefix(goType, "*")
+	if !ok {
+		return goType
+	}
+
+	switch style {
+	case NullableStyleSQLNull:
+		if nullType, ok := sqlNullTypes[base]; ok {
+			return nullType
+		}
+
+		return "sql.Null[" + base + "]"
+	case NullableStyleOption:
+		return "snapsqlgo.Option[" + base + "]"
+	}
+
+	return goType
+}
+
+// applyNullableStyle replaces the pointer types of the nullable fields of a flat response struct.
+// Hierarchical structs keep pointers: their aggregation code tests the scanned values for nil.
+func applyNullableStyle(responseStruct *responseStructData, style string) {
+	if responseStruct == nil || style == "" || style == NullableStylePointer {
+		return
+	}
+
+	if groups, _, err := detectHierarchicalStructure(responseStruct.RawResponses); err != nil || len(groups) > 0 {
+		return
+	}
+
+	for i, field := range responseStruct.Fields {
+		if field.IsPointer {
+			responseStruct.Fields[i].Type = nullableType(field.Type, style)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package gogen
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/shibukawa/snapsql/intermediate"
+)
+
+func TestGenerateNullableStyle(t *testing.T) {
+	tests := []struct {
+		style string
+		want  []string
+	}{
+		{
+			style: NullableStylePointer,
+			want:  []string{"Nickname  *string", "Score     *float64", "DeletedAt *time.Time"},
+		},
+		{
+			style: NullableStyleSQLNull,
+			want:  []string{`"database/sql"`, "Nickname  sql.NullString", "Score     sql.NullFloat64", "DeletedAt sql.NullTime", "Age       sql.Null[int]"},
+		},
+		{
+			style: NullableStyleOption,
+			want:  []string{"Nickname  snapsqlgo.Option[string]", "DeletedAt snapsqlgo.Option[time.Time]", "ID        int"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			format := userQuery("get_user", "one")
+			format.Responses = []intermediate.Response{
+				{Name: "id", Type: "int"},
+				{Name: "nickname", Type: "string", IsNullable: true},
+				{Name: "age", Type: "int", IsNullable: true},
+				{Name: "score", Type: "float64", IsNullable: true},
+				{Name: "deleted_at", Type: "timestamp", IsNullable: true},
+			}
+
+			var out strings.Builder
+
+			generator := &Generator{PackageName: "testgen", Format: format, Dialect: "postgres", NullableStyle: tt.style}
+			if err := generator.Generate(&out); err != nil {
+				t.Fatalf("Generate returned error: %v", err)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("generated code does not contain %q\n%s", want, out.String())
+				}
+			}
+		})
+	}
+
+	generator := &Generator{PackageName: "testgen", Format: userQuery("get_user", "one"), NullableStyle: "maybe"}
+	if err := generator.Generate(&strings.Builder{}); !errors.Is(err, ErrInvalidNullableStyle) {
+		t.Errorf("expected ErrInvalidNullableStyle, got %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+package gogen
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/shibukawa/snapsql/intermediate"
+)
+
+// optionalSetParamName is the Go parameter that carries the set_optional columns of an UPDATE.
+const optionalSetParamName = "set"
+
+// optionalSetParameter returns the optional SET column whose parameter is name.
+func optionalSetParameter(format *intermediate.IntermediateFormat, name string) (intermediate.OptionalSetColumn, bool) {
+	for _, column := range format.OptionalSet {
+		if column.Parameter == name {
+			return column, true
+		}
+	}
+
+	return intermediate.OptionalSetColumn{}, false
+}
+
+// isOptionalSetCondition reports whether exprIndex is the condition guarding a set_optional assignment.
+func isOptionalSetCondition(format *intermediate.IntermediateFormat, exprIndex int) bool {
+	return slices.ContainsFunc(format.OptionalSet, func(column intermediate.OptionalSetColumn) bool {
+		return column.ExprIndex == exprIndex
+	})
+}
+
+// optionalSetBindings maps the parameters of set_optional columns to fields of the options struct.
+func optionalSetBindings(format *intermediate.IntermediateFormat) map[string]string {
+	if len(format.OptionalSet) == 0 {
+		return nil
+	}
+
+	bindings := make(map[string]string, len(format.OptionalSet))
+	for _, column := range format.OptionalSet {
+		bindings[column.Parameter] = optionalSetParamName + "." + snakeToCamel(column.Parameter)
+	}
+
+	return bindings
+}
+
+// applyOptionalSet moves the parameters of set_optional columns into a <Func>Set struct with
+// pointer fields and replaces them with a single parameter of that type. Nil fields are left
+// out of the SET clause.
+func applyOptionalSet(format *intermediate.IntermediateFormat, funcName string, params []parameterData) ([]parameterData, string, error) {
+	if len(format.OptionalSet) == 0 {
+		return params, "", nil
+	}
+
+	structName := funcName + "Set"
+
+	var (
+		result []parameterData
+		fields []string
+	)
+
+	for _, param := range params {
+		if param.OriginalName == optionalSetParamName {
+			return nil, "", fmt.Errorf("%w: parameter %q conflicts with the set_optional options parameter", ErrGenerateGoCode, param.OriginalName)
+		}
+
+		if _, ok := optionalSetParameter(format, param.OriginalName); !ok {
+			result = append(result, param)
+			continue
+		}
+
+		goType := param.Type
+		if !strings.HasPrefix(goType, "*") {
+			goType = "*" + goType
+		}
+
+		fields = append(fields, fmt.Sprintf("\t%s %s `json:\"%s,omitempty\"`", snakeToCamel(param.OriginalName), goType, param.OriginalName))
+	}
+
+	structDef := fmt.Sprintf("// %s holds the optional SET columns of %s. Nil fields are left unchanged.\ntype %s struct {\n%s\n}",
+		structName, funcName, structName, strings.Join(fields, "\n"))
+
+	result = append(result, parameterData{
+		Name:         optionalSetParamName,
+		OriginalName: optionalSetParamName,
+		Type:         structName,
+	})
+
+	return result, structDef, nil
+}
@@ -0,0 +1,41 @@
+package gogen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultQueryComment is the query_comment format used when the setting is true
+const DefaultQueryComment = "snapsql:func={func}"
+
+// validateQueryComment rejects formats that would end the comment early or add a placeholder to the SQL
+func validateQueryComment(format string) error {
+	for _, forbidden := range []string{"/*", "*/", "?", "$", "\n"} {
+		if strings.Contains(format, forbidden) {
+			return fmt.Errorf("%w: %q must not contain %q", ErrInvalidQueryComment, format, forbidden)
+		}
+	}
+
+	return nil
+}
+
+// queryCommentPrefix renders the query_comment format as a SQL comment placed in front of the statement.
+// Monitoring tools such as pg_stat_statements and slow query logs keep the comment, so a statement can be
+// traced back to the generated function. The placeholders are:
+//
+//	{func}     Go function name (GetUserByID)
+//	{name}     function_name of the template (get_user_by_id)
+//	{package}  Go package name
+func (g *Generator) queryCommentPrefix(funcName string) string {
+	if g.QueryComment == "" {
+		return ""
+	}
+
+	text := strings.NewReplacer(
+		"{func}", funcName,
+		"{name}", g.Format.FunctionName,
+		"{package}", g.PackageName,
+	).Replace(g.QueryComment)
+
+	return "/* " + text + " */ "
+}
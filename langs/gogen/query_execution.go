@@ -3,6 +3,7 @@ package gogen
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
@@ -28,7 +29,7 @@ type queryExecutionData struct {
 }
 
 // generateQueryExecution generates query execution and result mapping code
-func generateQueryExecution(format *intermediate.IntermediateFormat, responseStruct *responseStructData, metas []*hierarchicalNodeMeta, responseType, functionName, errorZeroValue string, withLogger bool) (*queryExecutionData, error) {
+func generateQueryExecution(format *intermediate.IntermediateFormat, responseStruct *responseStructData, metas []*hierarchicalNodeMeta, responseType, functionName, errorZeroValue string, withLogger bool, guard rowGuard) (*queryExecutionData, error) {
 	var code []string
 
 	needsSnapsql := false
@@ -80,12 +81,30 @@ func generateQueryExecution(format *intermediate.IntermediateFormat, responseStr
 			needsSnapsql = true // aggregation(one) uses snapsql error constants
 		}
 
-		scanCode, err := generateScanCode(responseStruct, false, metas)
+		scanCode, err := generateScanCode(responseStruct, false, metas, guard)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate scan code: %w", err)
 		}
 
 		code = append(code, scanCode...)
+	case "exists", "count":
+		// Scalar affinity: scan the first column of the first row; no row leaves the zero value (false / 0)
+		code = append(code, "// Execute query and scan a single scalar value")
+		code = append(code, "rows, err := stmt.QueryContext(ctx, args...)")
+		code = append(code, "if err != nil {")
+		code = append(code, fmt.Sprintf("    return %s, fmt.Errorf(\"%sfailed to execute query: %%w\", err)", errorZeroValue, errorPrefix))
+		code = append(code, "}")
+		code = append(code, "defer rows.Close()")
+		code = append(code, "")
+		code = append(code, "if rows.Next() {")
+		code = append(code, "    if err := rows.Scan(&result); err != nil {")
+		code = append(code, fmt.Sprintf("        return %s, fmt.Errorf(\"%sfailed to scan row: %%w\", err)", errorZeroValue, errorPrefix))
+		code = append(code, "    }")
+		code = append(code, "}")
+		code = append(code, "")
+		code = append(code, "if err := rows.Err(); err != nil {")
+		code = append(code, fmt.Sprintf("    return %s, fmt.Errorf(\"%serror iterating rows: %%w\", err)", errorZeroValue, errorPrefix))
+		code = append(code, "}")
 	case "many":
 		needsAggregation := false
 
@@ -105,7 +124,7 @@ func generateQueryExecution(format *intermediate.IntermediateFormat, responseStr
 		}
 
 		if !needsAggregation {
-			iteratorBody, err := generateIteratorBody(responseStruct, functionName)
+			iteratorBody, err := generateIteratorBody(responseStruct, functionName, format.ReturningFollowUp, guard)
 			if err != nil {
 				return nil, fmt.Errorf("failed to generate iterator body: %w", err)
 			}
@@ -128,7 +147,7 @@ func generateQueryExecution(format *intermediate.IntermediateFormat, responseStr
 		code = append(code, "defer rows.Close()")
 		code = append(code, "")
 
-		scanCode, err := generateScanCode(responseStruct, true, metas)
+		scanCode, err := generateScanCode(responseStruct, true, metas, guard)
 		if err != nil {
 			return nil, fmt.Errorf("failed to generate scan code: %w", err)
 		}
@@ -138,6 +157,12 @@ func generateQueryExecution(format *intermediate.IntermediateFormat, responseStr
 		panic("unsupported response affinity: " + format.ResponseAffinity)
 	}
 
+	if format.ReturningFollowUp != nil {
+		code = replaceWithReturningFollowUp(code, format.ReturningFollowUp, errorZeroValue, errorPrefix)
+		// a missing inserted row is reported as snapsql.ErrNotFound
+		needsSnapsql = needsSnapsql || format.ResponseAffinity == "one"
+	}
+
 	return &queryExecutionData{
 		Code:               code,
 		NeedsSnapsqlImport: needsSnapsql,
@@ -146,8 +171,71 @@ func generateQueryExecution(format *intermediate.IntermediateFormat, responseStr
 	}, nil
 }
 
+// returningFollowUpLines executes an INSERT whose RETURNING clause was removed (MySQL) and
+// queries the inserted rows back by key into rows. query renders the call reading the rows back
+// and fail renders the lines reporting err.
+func returningFollowUpLines(followUp *intermediate.ReturningFollowUp, exec string, query func(sql string) string, fail func(msg string) []string) []string {
+	check := func(msg string) []string {
+		lines := []string{"if err != nil {"}
+		lines = append(lines, fail(msg)...)
+
+		return append(lines, "}")
+	}
+
+	code := []string{
+		"// RETURNING is not supported: execute the INSERT and read the inserted rows back by key",
+		"insertResult, err := " + exec,
+	}
+	code = append(code, check("failed to execute statement")...)
+	code = append(code, "insertedID, err := insertResult.LastInsertId()")
+	code = append(code, check("failed to get last insert id")...)
+	code = append(code, "insertedCount, err := insertResult.RowsAffected()")
+	code = append(code, check("failed to get affected rows")...)
+	code = append(code, "rows, err := "+query(followUp.SQL))
+
+	return code
+}
+
+// replaceWithReturningFollowUp swaps the statement query of the generated execution code for the
+// INSERT followed by the query reading the inserted rows back.
+func replaceWithReturningFollowUp(code []string, followUp *intermediate.ReturningFollowUp, errorZeroValue, errorPrefix string) []string {
+	fail := func(msg string) []string {
+		return []string{fmt.Sprintf("    return %s, fmt.Errorf(\"%s%s: %%w\", err)", errorZeroValue, errorPrefix, msg)}
+	}
+
+	query := func(sql string) string {
+		return fmt.Sprintf("executor.QueryContext(ctx, %q, insertedID, insertedID+insertedCount)", sql)
+	}
+
+	result := make([]string, 0, len(code)+16)
+
+	for _, line := range code {
+		switch line {
+		case "rows, err := stmt.QueryContext(ctx, args...)":
+			result = append(result, returningFollowUpLines(followUp, "stmt.ExecContext(ctx, args...)", query, fail)...)
+		case "row := stmt.QueryRowContext(ctx, args...)":
+			result = append(result, returningFollowUpLines(followUp, "stmt.ExecContext(ctx, args...)", query, fail)...)
+			result = append(result, "if err != nil {")
+			result = append(result, fail("failed to execute query")...)
+			result = append(result, "}")
+			result = append(result, "defer rows.Close()")
+			result = append(result, "if !rows.Next() {")
+			result = append(result, "    if err := rows.Err(); err != nil {")
+			result = append(result, fmt.Sprintf("        return %s, fmt.Errorf(\"%serror iterating rows: %%w\", err)", errorZeroValue, errorPrefix))
+			result = append(result, "    }")
+			result = append(result, fmt.Sprintf("    return %s, snapsql.ErrNotFound", errorZeroValue))
+			result = append(result, "}")
+			result = append(result, "row := rows")
+		default:
+			result = append(result, line)
+		}
+	}
+
+	return result
+}
+
 // generateScanCode generates code for scanning database results
-func generateScanCode(responseStruct *responseStructData, isMany bool, metas []*hierarchicalNodeMeta) ([]string, error) {
+func generateScanCode(responseStruct *responseStructData, isMany bool, metas []*hierarchicalNodeMeta, guard rowGuard) ([]string, error) {
 	// Check if we need aggregation (has __ fields in JSON tags)
 	hasAggregation := false
 	if len(metas) > 0 {
@@ -164,13 +252,13 @@ func generateScanCode(responseStruct *responseStructData, isMany bool, metas []*
 	if hasAggregation {
 		// Prefer meta-driven aggregation if metas supplied
 		if len(metas) > 0 {
-			return generateMetaDrivenAggregatedScanCode(responseStruct, isMany, metas)
+			return generateMetaDrivenAggregatedScanCode(responseStruct, isMany, metas, guard)
 		}
 
-		return generateAggregatedScanCode(responseStruct, isMany)
+		return generateAggregatedScanCode(responseStruct, isMany, guard)
 	}
 
-	return generateSimpleScanCode(responseStruct, isMany)
+	return generateSimpleScanCode(responseStruct, isMany, guard)
 }
 
 // generateHierarchicalManyScan builds code lines that aggregate rows with __ hierarchical fields.
@@ -181,12 +269,14 @@ func generateScanCode(responseStruct *responseStructData, isMany bool, metas []*
 // NOTE: hierarchical many aggregation for __ fields is deferred; future implementation
 
 // generateSimpleScanCode generates simple scanning code without aggregation
-func generateSimpleScanCode(responseStruct *responseStructData, isMany bool) ([]string, error) {
+func generateSimpleScanCode(responseStruct *responseStructData, isMany bool, guard rowGuard) ([]string, error) {
 	var code []string
 
 	if isMany {
 		// Multiple rows
+		code = append(code, guard.init()...)
 		code = append(code, "for rows.Next() {")
+		code = append(code, guard.check("    ", "return result, err")...)
 		code = append(code, "    var item "+responseStruct.Name)
 		code = append(code, "    err := rows.Scan(")
 
@@ -222,7 +312,7 @@ func generateSimpleScanCode(responseStruct *responseStructData, isMany bool) ([]
 }
 
 // generateIteratorBody builds the body of an iterator for non-aggregated many responses.
-func generateIteratorBody(responseStruct *responseStructData, functionName string) ([]string, error) {
+func generateIteratorBody(responseStruct *responseStructData, functionName string, followUp *intermediate.ReturningFollowUp, guard rowGuard) ([]string, error) {
 	if responseStruct == nil {
 		return nil, ErrIteratorRequiresStruct
 	}
@@ -231,15 +321,23 @@ func generateIteratorBody(responseStruct *responseStructData, functionName strin
 
 	prefix := functionName + ": "
 
-	code = append(code, "stmt, err := executor.PrepareContext(ctx, query)")
-	code = append(code, "if err != nil {")
-	code = append(code, fmt.Sprintf("\terr = fmt.Errorf(\"%sfailed to prepare statement: %%w (query: %%s)\", err, query)", prefix))
-	code = append(code, "\t_ = yield(nil, err)")
-	code = append(code, "\treturn")
-	code = append(code, "}")
-	code = append(code, "defer stmt.Close()")
-	code = append(code, "")
-	code = append(code, "rows, err := stmt.QueryContext(ctx, args...)")
+	if followUp != nil {
+		// streamOpts is resolved by the function template from WithStreaming options
+		query := func(sql string) string {
+			return fmt.Sprintf("snapsqlgo.QueryStream(ctx, executor, %q, []any{insertedID, insertedID + insertedCount}, streamOpts)", sql)
+		}
+		code = append(code, returningFollowUpLines(followUp, "executor.ExecContext(ctx, query, args...)", query, func(msg string) []string {
+			return []string{
+				fmt.Sprintf("\terr = fmt.Errorf(\"%s%s: %%w\", err)", prefix, msg),
+				"\t_ = yield(nil, err)",
+				"\treturn",
+			}
+		})...)
+	} else {
+		// streamOpts is resolved by the function template from WithStreaming options
+		code = append(code, "rows, err := snapsqlgo.QueryStream(ctx, executor, query, args, streamOpts)")
+	}
+
 	code = append(code, "if err != nil {")
 	code = append(code, fmt.Sprintf("\terr = fmt.Errorf(\"%sfailed to execute query: %%w\", err)", prefix))
 	code = append(code, "\t_ = yield(nil, err)")
@@ -247,7 +345,9 @@ func generateIteratorBody(responseStruct *responseStructData, functionName strin
 	code = append(code, "}")
 	code = append(code, "defer rows.Close()")
 	code = append(code, "")
+	code = append(code, guard.init()...)
 	code = append(code, "for rows.Next() {")
+	code = append(code, guard.check("\t", "_ = yield(nil, err)\n\t\treturn")...)
 	code = append(code, fmt.Sprintf("\titem := new(%s)", responseStruct.Name))
 
 	code = append(code, "\tif err := rows.Scan(")
@@ -275,7 +375,7 @@ func generateIteratorBody(responseStruct *responseStructData, functionName strin
 }
 
 // generateAggregatedScanCode generates scanning code with __ field aggregation
-func generateAggregatedScanCode(responseStruct *responseStructData, isMany bool) ([]string, error) {
+func generateAggregatedScanCode(responseStruct *responseStructData, isMany bool, guard rowGuard) ([]string, error) {
 	// Multi-level hierarchical aggregation.
 	if responseStruct == nil || len(responseStruct.RawResponses) == 0 {
 		return nil, snapsql.ErrHierarchicalNoRawResponses
@@ -425,6 +525,7 @@ func generateAggregatedScanCode(responseStruct *responseStructData, isMany bool)
 	}
 
 	code = append(code, "var _parentMap map[string]*"+responseStruct.Name)
+	code = append(code, "var _parentOrder []*"+responseStruct.Name)
 	// Node instance maps (for fast lookup) keyed by chain keys
 	for _, n := range orderedNodes {
 		structName := responseStruct.Name
@@ -447,7 +548,9 @@ func generateAggregatedScanCode(responseStruct *responseStructData, isMany bool)
 		code = append(code, "defer rows.Close()")
 	}
 
+	code = append(code, guard.init()...)
 	code = append(code, "for rows.Next() {")
+	code = append(code, guard.check("    ", "return result, err")...)
 	// Declarations
 	for _, c := range allCols {
 		goType, _ := convertToGoType(c.Resp.Type)
@@ -508,6 +611,7 @@ func generateAggregatedScanCode(responseStruct *responseStructData, isMany bool)
 	// Initialize top-level slices (they are pointers slice fields)
 	// Not strictly required; appends will allocate.
 	code = append(code, "        _parentMap[parentKey] = parentObj")
+	code = append(code, "        _parentOrder = append(_parentOrder, parentObj)")
 	code = append(code, "    }")
 	code = append(code, "    _chain_parent := parentKey")
 
@@ -625,11 +729,11 @@ func generateAggregatedScanCode(responseStruct *responseStructData, isMany bool)
 
 	code = append(code, "if err = rows.Err(); err != nil { return result, fmt.Errorf(\"error iterating rows: %w\", err) }")
 	if isMany {
-		code = append(code, "for _, v := range _parentMap { result = append(result, *v) }")
+		code = append(code, "for _, v := range _parentOrder { result = append(result, *v) }")
 	} else {
-		code = append(code, "if len(_parentMap) == 0 { return result, snapsql.ErrNotFound }")
-		code = append(code, "if len(_parentMap) > 1 { return result, snapsql.ErrHierarchicalMultipleParentsForOne }")
-		code = append(code, "for _, v := range _parentMap { result = *v }")
+		code = append(code, "if len(_parentOrder) == 0 { return result, snapsql.ErrNotFound }")
+		code = append(code, "if len(_parentOrder) > 1 { return result, snapsql.ErrHierarchicalMultipleParentsForOne }")
+		code = append(code, "result = *_parentOrder[0]")
 	}
 
 	return code, nil
@@ -637,7 +741,7 @@ func generateAggregatedScanCode(responseStruct *responseStructData, isMany bool)
 
 // generateMetaDrivenAggregatedScanCode builds hierarchical aggregation scan code using precomputed metas.
 // This avoids re-parsing response names and duplicates the logic with a simpler deterministic expansion.
-func generateMetaDrivenAggregatedScanCode(responseStruct *responseStructData, isMany bool, metas []*hierarchicalNodeMeta) ([]string, error) {
+func generateMetaDrivenAggregatedScanCode(responseStruct *responseStructData, isMany bool, metas []*hierarchicalNodeMeta, guard rowGuard) ([]string, error) {
 	if responseStruct == nil || len(responseStruct.RawResponses) == 0 {
 		return nil, snapsql.ErrHierarchicalNoRawResponses
 	}
@@ -734,23 +838,18 @@ func generateMetaDrivenAggregatedScanCode(responseStruct *responseStructData, is
 
 		code = append(code, fmt.Sprintf("var %s %s", c.varName, goType))
 	}
-	// Maps per node: path chain key -> struct pointers
+	// Maps per node: chain key (keys of the node and all its ancestors) -> struct pointers.
+	// _parentOrder keeps the parents in the order of their first row.
 	code = append(code, "var _parentMap map[string]*"+mainStruct)
-	for _, m := range metas {
-		// Compose struct name same as generateHierarchicalStructs
-		structName := mainStruct
-
-		var structNameSb805 strings.Builder
-		for _, seg := range m.Path {
-			structNameSb805.WriteString(celNameToGoName(seg))
-		}
+	code = append(code, "var _parentOrder []*"+mainStruct)
 
-		structName += structNameSb805.String()
-
-		code = append(code, fmt.Sprintf("var _nodeMap_%s map[string]*%s", strings.Join(m.Path, "_"), structName))
+	for _, m := range metas {
+		code = append(code, fmt.Sprintf("var _nodeMap_%s map[string]*%s", strings.Join(m.Path, "_"), mainStruct+joinCamel(m.Path)))
 	}
 
+	code = append(code, guard.init()...)
 	code = append(code, "for rows.Next() {")
+	code = append(code, guard.check("    ", "return result, err")...)
 	// Scan line
 	code = append(code, "    err = rows.Scan(")
 	for _, c := range allCols {
@@ -796,150 +895,86 @@ func generateMetaDrivenAggregatedScanCode(responseStruct *responseStructData, is
 	// Initialize child slices to empty arrays (depth 1)
 	for _, m := range metas {
 		if m.Depth == 1 {
-			childStructName := mainStruct
-
-			var childStructNameSb859 strings.Builder
-			for _, seg := range m.Path {
-				childStructNameSb859.WriteString(celNameToGoName(seg))
-			}
-
-			childStructName += childStructNameSb859.String()
-
-			code = append(code, fmt.Sprintf("        parentObj.%s = make([]*%s, 0)", celNameToGoName(m.Path[0]), childStructName))
+			code = append(code, fmt.Sprintf("        parentObj.%s = make([]*%s, 0)", celNameToGoName(m.Path[0]), mainStruct+joinCamel(m.Path)))
 		}
 	}
 
 	code = append(code, "        _parentMap[pk_parent] = parentObj")
+	code = append(code, "        _parentOrder = append(_parentOrder, parentObj)")
 	code = append(code, "    }")
-	// Parent full key for child chain
-	code = append(code, "    _chain_parent := pk_parent")
-	// For each meta (ordered by depth)
+	// Nodes are ordered by depth, so the node of the parent path is resolved before its children.
+	// A node is skipped when its keys are NULL (LEFT JOIN without match) or its parent was skipped.
 	for _, m := range metas {
-		// Key fields must all be non-nil
-		condNil := []string{}
-		for _, kf := range m.KeyFields {
-			condNil = append(condNil, fmt.Sprintf("col_%s == nil", strings.ToLower(celNameToGoName(kf))))
-		}
+		suffix := strings.Join(m.Path, "_")
+		structName := mainStruct + joinCamel(m.Path)
+		children := childMetas(metas, m)
 
-		code = append(code, "    // Node "+strings.Join(m.Path, "__"))
-		code = append(code, fmt.Sprintf("    if !(%s) {", strings.Join(condNil, " || ")))
-		// Build node key (key fields are scanned as pointers when nullable; safe to deref due to nil guard)
-		if len(m.KeyFields) == 1 {
-			kf := m.KeyFields[0]
-			k := strings.ToLower(celNameToGoName(kf))
-
-			expr := "col_" + k
-			if strings.Contains(kf, "__") {
-				expr = "*" + expr
-			} else if resp, ok := respByName[kf]; ok {
-				if resp.IsNullable {
-					expr = "*" + expr
-				}
-			}
+		parentChain := "pk_parent"
+		conds := []string{}
 
-			code = append(code, fmt.Sprintf("        _k_%s := fmt.Sprintf(\"%%v\", %s)", strings.Join(m.Path, "_"), expr))
-		} else {
-			fmtParts := make([]string, len(m.KeyFields))
-
-			args := make([]string, len(m.KeyFields))
-			for i, kf := range m.KeyFields {
-				fmtParts[i] = "%v"
-
-				arg := "col_" + strings.ToLower(celNameToGoName(kf))
-				if strings.Contains(kf, "__") {
-					arg = "*" + arg
-				} else if resp, ok := respByName[kf]; ok {
-					if resp.IsNullable {
-						arg = "*" + arg
-					}
-				}
-
-				args[i] = arg
-			}
-
-			code = append(code, fmt.Sprintf("        _k_%s := fmt.Sprintf(\"%s\", %s)", strings.Join(m.Path, "_"), strings.Join(fmtParts, "|"), strings.Join(args, ", ")))
+		if len(m.ParentPath) > 0 {
+			parentChain = "_chain_" + strings.Join(m.ParentPath, "_")
+			conds = append(conds, fmt.Sprintf("node_%s != nil", strings.Join(m.ParentPath, "_")))
 		}
 
-		code = append(code, fmt.Sprintf("        if _nodeMap_%s == nil { _nodeMap_%s = make(map[string]*%s) }", strings.Join(m.Path, "_"), strings.Join(m.Path, "_"), mainStruct+joinCamel(m.Path)))
-		code = append(code, fmt.Sprintf("        _chain_%s := _chain_parent + \"|%s:\" + _k_%s", strings.Join(m.Path, "_"), strings.Join(m.Path, "__"), strings.Join(m.Path, "_")))
-		code = append(code, fmt.Sprintf("        node_%s, _exists_%s := _nodeMap_%s[_chain_%s]", strings.Join(m.Path, "_"), strings.Join(m.Path, "_"), strings.Join(m.Path, "_"), strings.Join(m.Path, "_")))
-		code = append(code, fmt.Sprintf("        if ! _exists_%s {", strings.Join(m.Path, "_")))
-		code = append(code, fmt.Sprintf("            node_%s = &%s{}", strings.Join(m.Path, "_"), mainStruct+joinCamel(m.Path)))
-		// Assign key fields first (hierarchy keys like ID)
-		for _, kf := range m.KeyFields {
-			leaf := kf[strings.LastIndex(kf, "__")+1:]
+		fmtParts := make([]string, len(m.KeyFields))
+		args := make([]string, len(m.KeyFields))
+
+		for i, kf := range m.KeyFields {
 			colVar := "col_" + strings.ToLower(celNameToGoName(kf))
-			code = append(code, fmt.Sprintf("            node_%s.%s = %s", strings.Join(m.Path, "_"), celNameToGoName(leaf), colVar))
+			conds = append(conds, colVar+" != nil")
+			fmtParts[i] = "%v"
+			// Hierarchical columns are always scanned as pointers; safe to deref due to nil guard
+			args[i] = "*" + colVar
 		}
-		// Assign data fields (exclude keys already assigned)
-		keySet := map[string]struct{}{}
-		for _, kf := range m.KeyFields {
-			keySet[kf] = struct{}{}
-		}
-
-		for _, df := range m.DataFields {
-			if _, exists := keySet[df]; exists {
-				continue
-			}
-
-			leaf := df[strings.LastIndex(df, "__")+1:]
-			resp := respByName[df]
 
-			colVar := "col_" + strings.ToLower(celNameToGoName(df))
-			if resp.IsNullable {
-				code = append(code, fmt.Sprintf("            node_%s.%s = %s", strings.Join(m.Path, "_"), celNameToGoName(leaf), colVar))
-			} else if strings.Contains(df, "__") {
+		code = append(code, "    // Node "+strings.Join(m.Path, "__"))
+		code = append(code, fmt.Sprintf("    var node_%s *%s", suffix, structName))
+
+		assign := ":="
+		if len(children) > 0 {
+			code = append(code, fmt.Sprintf("    var _chain_%s string", suffix))
+			assign = "="
+		}
+
+		code = append(code, fmt.Sprintf("    if %s {", strings.Join(conds, " && ")))
+		code = append(code, fmt.Sprintf("        _chain_%s %s %s + \"|%s:\" + fmt.Sprintf(\"%s\", %s)", suffix, assign, parentChain, strings.Join(m.Path, "__"), strings.Join(fmtParts, "|"), strings.Join(args, ", ")))
+		code = append(code, fmt.Sprintf("        if _nodeMap_%s == nil { _nodeMap_%s = make(map[string]*%s) }", suffix, suffix, structName))
+		code = append(code, fmt.Sprintf("        var _exists_%s bool", suffix))
+		code = append(code, fmt.Sprintf("        node_%s, _exists_%s = _nodeMap_%s[_chain_%s]", suffix, suffix, suffix, suffix))
+		code = append(code, fmt.Sprintf("        if !_exists_%s {", suffix))
+		code = append(code, fmt.Sprintf("            node_%s = &%s{}", suffix, structName))
+		// Key fields first (hierarchy keys like ID), then data fields
+		for _, name := range append(append([]string{}, m.KeyFields...), m.DataFields...) {
+			leaf := celNameToGoName(name[strings.LastIndex(name, "__")+2:])
+			colVar := "col_" + strings.ToLower(celNameToGoName(name))
+
+			switch {
+			case respByName[name].IsNullable:
+				code = append(code, fmt.Sprintf("            node_%s.%s = %s", suffix, leaf, colVar))
+			case slices.Contains(m.KeyFields, name):
+				code = append(code, fmt.Sprintf("            node_%s.%s = *%s", suffix, leaf, colVar))
+			default:
 				code = append(code, fmt.Sprintf("            if %s != nil {", colVar))
-				code = append(code, fmt.Sprintf("                node_%s.%s = *%s", strings.Join(m.Path, "_"), celNameToGoName(leaf), colVar))
+				code = append(code, fmt.Sprintf("                node_%s.%s = *%s", suffix, leaf, colVar))
 				code = append(code, "            }")
-			} else {
-				code = append(code, fmt.Sprintf("            node_%s.%s = %s", strings.Join(m.Path, "_"), celNameToGoName(leaf), colVar))
 			}
 		}
 		// Initialize child slices for this node
-		for _, child := range metas {
-			// Check if child is a direct child of current node (parent path matches current path)
-			if len(child.ParentPath) == len(m.Path) {
-				isChild := true
-
-				for i, seg := range m.Path {
-					if child.ParentPath[i] != seg {
-						isChild = false
-						break
-					}
-				}
-
-				if isChild {
-					childStructName := mainStruct
-
-					var childStructNameSb969 strings.Builder
-					for _, seg := range child.Path {
-						childStructNameSb969.WriteString(celNameToGoName(seg))
-					}
-
-					childStructName += childStructNameSb969.String()
-
-					childFieldName := celNameToGoName(child.Path[len(child.Path)-1])
-					code = append(code, fmt.Sprintf("            node_%s.%s = make([]*%s, 0)", strings.Join(m.Path, "_"), childFieldName, childStructName))
-				}
-			}
+		for _, child := range children {
+			code = append(code, fmt.Sprintf("            node_%s.%s = make([]*%s, 0)", suffix, celNameToGoName(child.Path[len(child.Path)-1]), mainStruct+joinCamel(child.Path)))
 		}
-		// Append to parent slice
-		if len(m.ParentPath) == 0 { // top-level
-			code = append(code, fmt.Sprintf("            parentObj.%s = append(parentObj.%s, node_%s)", celNameToGoName(m.Path[0]), celNameToGoName(m.Path[0]), strings.Join(m.Path, "_")))
+		// Append to the parent slice
+		sliceField := celNameToGoName(m.Path[len(m.Path)-1])
+		if len(m.ParentPath) == 0 {
+			code = append(code, fmt.Sprintf("            parentObj.%s = append(parentObj.%s, node_%s)", sliceField, sliceField, suffix))
 		} else {
-			// Lookup parent by current chain key and append to its slice
-			parentMapVar := "_nodeMap_" + strings.Join(m.ParentPath, "_")
-			sliceField := celNameToGoName(m.Path[len(m.Path)-1])
-
-			code = append(code, fmt.Sprintf("            if p, ok := %s[_chain_parent]; ok {", parentMapVar))
-			code = append(code, fmt.Sprintf("                p.%s = append(p.%s, node_%s)", sliceField, sliceField, strings.Join(m.Path, "_")))
-			code = append(code, "            }")
+			parentNode := "node_" + strings.Join(m.ParentPath, "_")
+			code = append(code, fmt.Sprintf("            %s.%s = append(%s.%s, node_%s)", parentNode, sliceField, parentNode, sliceField, suffix))
 		}
 
-		code = append(code, fmt.Sprintf("            _nodeMap_%s[_chain_%s] = node_%s", strings.Join(m.Path, "_"), strings.Join(m.Path, "_"), strings.Join(m.Path, "_")))
+		code = append(code, fmt.Sprintf("            _nodeMap_%s[_chain_%s] = node_%s", suffix, suffix, suffix))
 		code = append(code, "        }")
-		code = append(code, "        _chain_parent = _chain_"+strings.Join(m.Path, "_"))
 		code = append(code, "    }")
 	}
 
@@ -947,16 +982,29 @@ func generateMetaDrivenAggregatedScanCode(responseStruct *responseStructData, is
 
 	code = append(code, "if err = rows.Err(); err != nil { return result, fmt.Errorf(\"error iterating rows: %w\", err) }")
 	if isMany {
-		code = append(code, "for _, v := range _parentMap { result = append(result, *v) }")
+		code = append(code, "for _, v := range _parentOrder { result = append(result, *v) }")
 	} else {
-		code = append(code, "if len(_parentMap) == 0 { return result, snapsql.ErrNotFound }")
-		code = append(code, "if len(_parentMap) > 1 { return result, snapsql.ErrHierarchicalMultipleParentsForOne }")
-		code = append(code, "for _, v := range _parentMap { result = *v }")
+		code = append(code, "if len(_parentOrder) == 0 { return result, snapsql.ErrNotFound }")
+		code = append(code, "if len(_parentOrder) > 1 { return result, snapsql.ErrHierarchicalMultipleParentsForOne }")
+		code = append(code, "result = *_parentOrder[0]")
 	}
 
 	return code, nil
 }
 
+// childMetas returns the metas whose parent path is the path of m, in the order of metas
+func childMetas(metas []*hierarchicalNodeMeta, m *hierarchicalNodeMeta) []*hierarchicalNodeMeta {
+	var children []*hierarchicalNodeMeta
+
+	for _, child := range metas {
+		if slices.Equal(child.ParentPath, m.Path) {
+			children = append(children, child)
+		}
+	}
+
+	return children
+}
+
 // joinCamel combines path segments converting each to Go-style CamelCase.
 func joinCamel(segs []string) string {
 	var b strings.Builder
@@ -27,7 +27,7 @@ func TestGenerateAggregatedScanCode_MultiLevel(t *testing.T) {
 		},
 	}
 
-	code, err := generateAggregatedScanCode(rs, true)
+	code, err := generateAggregatedScanCode(rs, true, false)
 	if err != nil {
 		t.Fatalf("generateAggregatedScanCode error: %v", err)
 	}
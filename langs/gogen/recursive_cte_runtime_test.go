@@ -0,0 +1,54 @@
+package gogen
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/shibukawa/snapsql/testdata/gogenruntime/categorytree"
+)
+
+func TestRecursiveCTESQLite(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	for _, stmt := range []string{
+		`CREATE TABLE categories (id INTEGER PRIMARY KEY, parent_id INTEGER, name TEXT NOT NULL)`,
+		`INSERT INTO categories (id, parent_id, name) VALUES (1, NULL, 'root'), (2, 1, 'books'), (3, 1, 'music'), (4, 2, 'comics'), (5, NULL, 'other')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("failed to execute %q: %v", stmt, err)
+		}
+	}
+
+	type node struct {
+		id    int
+		depth int
+	}
+
+	var got []node
+
+	for item, err := range categorytree.ListCategoryTree(t.Context(), db, 1) {
+		if err != nil {
+			t.Fatalf("ListCategoryTree returned error: %v", err)
+		}
+
+		got = append(got, node{item.ID, item.Depth})
+	}
+
+	// 別の木 (id=5) は辿らない
+	want := []node{{1, 1}, {2, 2}, {3, 2}, {4, 3}}
+	if len(got) != len(want) {
+		t.Fatalf("unexpected tree: %+v", got)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("unexpected tree: %+v", got)
+		}
+	}
+}
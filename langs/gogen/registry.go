@@ -0,0 +1,133 @@
+package gogen
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/shibukawa/snapsql/intermediate"
+)
+
+// registryData holds the literals of the snapsqlgo.Registry entry emitted for a function
+type registryData struct {
+	SQL         string // quoted Go string literal
+	Description string // quoted Go string literal
+}
+
+func buildRegistryData(format *intermediate.IntermediateFormat) *registryData {
+	return &registryData{
+		SQL:         strconv.Quote(renderRegistrySQL(format)),
+		Description: strconv.Quote(format.Description),
+	}
+}
+
+// renderRegistrySQL reconstructs a readable SQL template from the instructions.
+// Directives are rendered as snapsql comments and evaluated values as '?' placeholders.
+// The system LIMIT/OFFSET overrides are not part of the template and are left out.
+func renderRegistrySQL(format *intermediate.IntermediateFormat) string {
+	type block struct {
+		system   bool
+		skipping bool
+	}
+
+	var (
+		b      strings.Builder
+		blocks []block
+	)
+
+	expression := func(index *int, fallback string) string {
+		if index != nil && *index >= 0 && *index < len(format.CELExpressions) {
+			return format.CELExpressions[*index].Expression
+		}
+
+		return fallback
+	}
+
+	skipping := func() bool {
+		for _, blk := range blocks {
+			if blk.skipping {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, inst := range format.Instructions {
+		switch inst.Op {
+		case intermediate.OpIfSystemLimit, intermediate.OpIfSystemOffset:
+			// A LIMIT capped by snapsql.yaml (limits) is always written
+			blocks = append(blocks, block{system: true, skipping: inst.DefaultValue == ""})
+			continue
+		case intermediate.OpEnd, intermediate.OpLoopEnd:
+			system := false
+			if len(blocks) > 0 {
+				system = blocks[len(blocks)-1].system
+				blocks = blocks[:len(blocks)-1]
+			}
+
+			if !system && !skipping() {
+				b.WriteString("/*# end */")
+			}
+
+			continue
+		case intermediate.OpIf, intermediate.OpLoopStart:
+			// Pushed even while skipping so that the matching END pops the right block
+			skipped := skipping()
+			blocks = append(blocks, block{})
+
+			if skipped {
+				continue
+			}
+		case intermediate.OpElse, intermediate.OpElseIf:
+			if len(blocks) > 0 && blocks[len(blocks)-1].system {
+				// The else branch of a system override holds the LIMIT/OFFSET written in the template
+				blocks[len(blocks)-1].skipping = false
+				continue
+			}
+		}
+
+		if skipping() {
+			continue
+		}
+
+		switch inst.Op {
+		case intermediate.OpEmitStatic, intermediate.OpEmitUnlessBoundary, intermediate.OpEmitSystemSoftDelete:
+			b.WriteString(inst.Value)
+		case intermediate.OpEmitCursorCondition:
+			b.WriteString(inst.Value + " ")
+		case intermediate.OpEmitEval:
+			b.WriteString("/*= " + expression(inst.ExprIndex, inst.Param) + " */?")
+		case intermediate.OpEmitIdent:
+			b.WriteString("/*# ident " + expression(inst.ExprIndex, "") + " in [" + strings.Join(inst.Allowed, ", ") + "] */" + inst.Allowed[0])
+		case intermediate.OpEmitOrderBy:
+			b.WriteString("/*# orderby " + expression(inst.ExprIndex, "") + " in [" + strings.Join(inst.Allowed, ", ") + "] */" + inst.Allowed[0])
+		case intermediate.OpEmitCursorLimit:
+			if inst.ExprIndex != nil {
+				b.WriteString("/*= " + expression(inst.ExprIndex, "") + " */?")
+			} else {
+				b.WriteString(inst.Value)
+			}
+		case intermediate.OpEmitSystemLimit:
+			switch {
+			case inst.DefaultValue == "":
+				b.WriteString("?")
+			case inst.ExprIndex != nil:
+				b.WriteString("/*= " + expression(inst.ExprIndex, "") + " */?")
+			default:
+				b.WriteString(inst.DefaultValue)
+			}
+		case intermediate.OpEmitSystemValue, intermediate.OpEmitSystemOffset:
+			b.WriteString("?")
+		case intermediate.OpIf:
+			b.WriteString("/*# if " + expression(inst.ExprIndex, inst.Condition) + " */")
+		case intermediate.OpElseIf:
+			b.WriteString("/*# elseif " + expression(inst.ExprIndex, inst.Condition) + " */")
+		case intermediate.OpElse:
+			b.WriteString("/*# else */")
+		case intermediate.OpLoopStart:
+			b.WriteString("/*# for " + inst.Variable + " : " + expression(inst.CollectionExprIndex, inst.Collection) + " */")
+		}
+	}
+
+	return strings.TrimSpace(b.String())
+}
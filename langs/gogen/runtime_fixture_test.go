@@ -0,0 +1,114 @@
+package gogen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+)
+
+// TestRuntimeFixtureGeneration writes testdata/gogenruntime, which the runtime tests execute.
+// The generated code of the acceptance tests is not tracked, so the runtime tests import these copies.
+func TestRuntimeFixtureGeneration(t *testing.T) {
+	for _, tt := range []struct {
+		testCase string
+		pkg      string
+		file     string
+	}{
+		{"053_join_three_levels_ok", "ordertree", "list_order_trees.go"},
+		{"054_recursive_cte_ok", "categorytree", "list_category_tree.go"},
+	} {
+		t.Run(tt.pkg, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("../../testdata/acceptancetests", tt.testCase, "expected.json"))
+			if err != nil {
+				t.Fatalf("failed to read intermediate format: %v", err)
+			}
+
+			format, err := intermediate.FromJSON(data)
+			if err != nil {
+				t.Fatalf("failed to parse intermediate format: %v", err)
+			}
+
+			var out strings.Builder
+
+			generator := New(format, WithPackageName(tt.pkg), WithDialect(snapsql.DialectPostgres))
+			if err := generator.Generate(&out); err != nil {
+				t.Fatalf("Generate returned error: %v", err)
+			}
+
+			path := filepath.Join("../../testdata/gogenruntime", tt.pkg, tt.file)
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatalf("failed to create %s: %v", filepath.Dir(path), err)
+			}
+
+			if err := os.WriteFile(path, []byte(out.String()), 0o644); err != nil {
+				t.Fatalf("failed to write %s: %v", path, err)
+			}
+		})
+	}
+}
+
+// TestScalarMockHelperGeneration writes testdata/gogenruntime/scalarmock: exists and count functions
+// together with their mock helpers. The helpers are written as regular files so that
+// TestScalarMockHelperRuntime compiles and calls them.
+func TestScalarMockHelperGeneration(t *testing.T) {
+	for _, tt := range []struct {
+		affinity     string
+		functionName string
+		query        string
+		column       string
+		helper       string
+	}{
+		{"exists", "user_exists", "SELECT EXISTS (SELECT 1 FROM users) AS found", "found", "func WithUserExistsMock(ctx context.Context, value bool) (context.Context, error) {"},
+		{"count", "count_users", "SELECT COUNT(*) AS total FROM users", "total", "func WithCountUsersMock(ctx context.Context, value int64) (context.Context, error) {"},
+	} {
+		t.Run(tt.affinity, func(t *testing.T) {
+			format := &intermediate.IntermediateFormat{
+				FormatVersion:    "1",
+				FunctionName:     tt.functionName,
+				StatementType:    "select",
+				ResponseAffinity: tt.affinity,
+				Responses:        []intermediate.Response{{Name: tt.column, Type: "int"}},
+				Instructions: []intermediate.Instruction{
+					{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: tt.query},
+				},
+			}
+
+			generator := New(format, WithPackageName("scalarmock"), WithDialect(snapsql.DialectPostgres))
+
+			var code, helper strings.Builder
+			if err := generator.Generate(&code); err != nil {
+				t.Fatalf("Generate returned error: %v", err)
+			}
+
+			if err := generator.GenerateMockHelper(&helper); err != nil {
+				t.Fatalf("GenerateMockHelper returned error: %v", err)
+			}
+
+			if !strings.Contains(helper.String(), tt.helper) {
+				t.Errorf("mock helper missing %q:\n%s", tt.helper, helper.String())
+			}
+
+			if strings.Contains(helper.String(), "Result") {
+				t.Errorf("scalar mock helper must not refer to a response struct:\n%s", helper.String())
+			}
+
+			dir := "../../testdata/gogenruntime/scalarmock"
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				t.Fatalf("failed to create %s: %v", dir, err)
+			}
+
+			for file, content := range map[string]string{
+				tt.functionName + ".go":      code.String(),
+				tt.functionName + "_mock.go": helper.String(),
+			} {
+				if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644); err != nil {
+					t.Fatalf("failed to write %s: %v", file, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package gogen
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shibukawa/snapsql/testdata/gogenruntime/scalarmock"
+)
+
+// TestScalarMockHelperRuntime calls the generated mock helpers of exists and count functions.
+// No database is involved: the mocks answer before the executor is used.
+func TestScalarMockHelperRuntime(t *testing.T) {
+	ctx, err := scalarmock.WithUserExistsMock(context.Background(), true)
+	if err != nil {
+		t.Fatalf("WithUserExistsMock returned error: %v", err)
+	}
+
+	found, err := scalarmock.UserExists(ctx, nil)
+	if err != nil || !found {
+		t.Errorf("UserExists = %v, %v; want true", found, err)
+	}
+
+	ctx, err = scalarmock.WithCountUsersMock(context.Background(), 42)
+	if err != nil {
+		t.Fatalf("WithCountUsersMock returned error: %v", err)
+	}
+
+	total, err := scalarmock.CountUsers(ctx, nil)
+	if err != nil || total != 42 {
+		t.Errorf("CountUsers = %v, %v; want 42", total, err)
+	}
+
+	errBoom := errors.New("boom")
+
+	ctx, err = scalarmock.WithCountUsersMockError(context.Background(), errBoom)
+	if err != nil {
+		t.Fatalf("WithCountUsersMockError returned error: %v", err)
+	}
+
+	if _, err := scalarmock.CountUsers(ctx, nil); !errors.Is(err, errBoom) {
+		t.Errorf("CountUsers error = %v; want %v", err, errBoom)
+	}
+}
@@ -0,0 +1,132 @@
+package gogen
+
+import (
+	"cmp"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+)
+
+// ShareResponseTypes finds templates whose flat response structs have identical fields and
+// returns, by function name, the response struct each of them shares: the struct of the template
+// with the smallest function name in the group. That template keeps its struct and is not in the
+// result. Templates declaring response_type, returning scalars or hierarchical responses are skipped.
+// mappings are the type_mappings the structs are generated with.
+func ShareResponseTypes(formats []*intermediate.IntermediateFormat, mappings []snapsql.TypeMappingConfig) map[string]string {
+	sorted := slices.Clone(formats)
+	slices.SortStableFunc(sorted, func(a, b *intermediate.IntermediateFormat) int {
+		return cmp.Compare(a.FunctionName, b.FunctionName)
+	})
+
+	owners := make(map[string]string) // shape -> shared struct name
+	shared := make(map[string]string)
+
+	for _, format := range sorted {
+		format, _ = mapResponseTypes(format, mappings)
+
+		shape, ok := responseShape(format)
+		if !ok {
+			continue
+		}
+
+		if owner, exists := owners[shape]; exists {
+			shared[format.FunctionName] = owner
+			continue
+		}
+
+		owners[shape] = generateStructName(format.FunctionName)
+	}
+
+	return shared
+}
+
+// responseShape describes the fields of the flat response struct generated for format
+func responseShape(format *intermediate.IntermediateFormat) (string, bool) {
+	affinity := intermediate.ResponseAffinity(strings.ToLower(format.ResponseAffinity))
+	if format.ResponseType != "" || affinity.IsScalar() || affinity == intermediate.ResponseAffinityNone {
+		return "", false
+	}
+
+	if groups, _, err := detectHierarchicalStructure(format.Responses); err != nil || len(groups) > 0 {
+		return "", false
+	}
+
+	responseStruct, err := processResponseStruct(format)
+	if err != nil {
+		return "", false
+	}
+
+	var b strings.Builder
+	for _, field := range responseStruct.Fields {
+		fmt.Fprintf(&b, "%s %s %s;", field.Name, field.Type, field.JSONTag)
+	}
+
+	return b.String(), true
+}
+
+// applyResponseType makes the generated code return the type declared by response_type, or the
+// struct shared with another template (SharedResponseTypes), in place of its own response struct.
+// It returns the updated response type and the package the declared type is imported from.
+func (g *Generator) applyResponseType(responseType string, responseStruct *responseStructData) (string, string, error) {
+	if g.Format.ResponseType == "" {
+		if responseStruct != nil {
+			if shared := g.SharedResponseTypes[g.Format.FunctionName]; shared != "" && shared != responseStruct.Name {
+				responseStruct.AliasOf = shared
+			}
+		}
+
+		return responseType, "", nil
+	}
+
+	if responseStruct == nil {
+		return "", "", fmt.Errorf("%w: function %s returns no rows", ErrInvalidResponseType, g.Format.FunctionName)
+	}
+
+	if groups, _, err := detectHierarchicalStructure(g.Format.Responses); err == nil && len(groups) > 0 {
+		return "", "", fmt.Errorf("%w: function %s has a hierarchical response", ErrInvalidResponseType, g.Format.FunctionName)
+	}
+
+	name, importPath, err := declaredResponseType(g.Format.ResponseType)
+	if err != nil {
+		return "", "", err
+	}
+
+	responseType = strings.Replace(responseType, responseStruct.Name, name, 1)
+	responseStruct.Name = name
+	responseStruct.Declared = true
+
+	return responseType, importPath, nil
+}
+
+// declaredResponseType splits a response_type value into the type name used by generated code
+// and its import path: "User" is a type of the generated package, "github.com/acme/app/models.User"
+// a type of the models package.
+func declaredResponseType(value string) (string, string, error) {
+	value = strings.TrimSpace(value)
+
+	i := strings.LastIndex(value, ".")
+	if i < 0 {
+		if !isValidGoIdentifier(value) {
+			return "", "", fmt.Errorf("%w: %q", ErrInvalidResponseType, value)
+		}
+
+		return value, "", nil
+	}
+
+	importPath, name := value[:i], value[i+1:]
+	pkg := path.Base(importPath)
+
+	if importPath == "" || !isValidGoIdentifier(pkg) || !isValidGoIdentifier(name) || !isExported(name) {
+		return "", "", fmt.Errorf("%w: %q", ErrInvalidResponseType, value)
+	}
+
+	return pkg + "." + name, importPath, nil
+}
+
+func isExported(name string) bool {
+	return name != "" && name[0] >= 'A' && name[0] <= 'Z'
+}
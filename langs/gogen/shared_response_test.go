@@ -0,0 +1,173 @@
+package gogen
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+)
+
+func userQuery(functionName, affinity string) *intermediate.IntermediateFormat {
+	return &intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     functionName,
+		StatementType:    "select",
+		ResponseAffinity: affinity,
+		Responses: []intermediate.Response{
+			{Name: "id", Type: "int"},
+			{Name: "name", Type: "string"},
+			{Name: "created_at", Type: "timestamp"},
+		},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Pos: "1:1", Value: "SELECT id, name, created_at FROM users"},
+		},
+	}
+}
+
+func TestShareResponseTypes(t *testing.T) {
+	other := userQuery("list_orders", "many")
+	other.Responses = []intermediate.Response{{Name: "id", Type: "int"}}
+
+	declared := userQuery("find_user_by_name", "one")
+	declared.ResponseType = "User"
+
+	shared := ShareResponseTypes([]*intermediate.IntermediateFormat{
+		userQuery("list_users", "many"),
+		userQuery("get_user", "one"),
+		userQuery("find_user_by_email", "one"),
+		declared,
+		other,
+	}, nil)
+
+	expected := map[string]string{
+		"get_user":   "FindUserByEmailResult",
+		"list_users": "FindUserByEmailResult",
+	}
+
+	if len(shared) != len(expected) {
+		t.Fatalf("unexpected shared types: %v", shared)
+	}
+
+	for function, name := range expected {
+		if shared[function] != name {
+			t.Errorf("%s shares %q, want %q", function, shared[function], name)
+		}
+	}
+}
+
+func TestGenerateSharedResponseType(t *testing.T) {
+	var out strings.Builder
+
+	generator := &Generator{
+		PackageName:         "testgen",
+		Format:              userQuery("list_users", "many"),
+		Dialect:             "postgres",
+		SharedResponseTypes: map[string]string{"list_users": "FindUserByEmailResult"},
+	}
+	if err := generator.Generate(&out); err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	code := out.String()
+	if !strings.Contains(code, "type ListUsersResult = FindUserByEmailResult") {
+		t.Errorf("expected an alias of the shared struct:\n%s", code)
+	}
+
+	if strings.Contains(code, "type ListUsersResult struct") || strings.Contains(code, `"time"`) {
+		t.Errorf("shared response struct must not be declared again:\n%s", code)
+	}
+}
+
+func TestGenerateDeclaredResponseType(t *testing.T) {
+	format := userQuery("list_users", "many")
+	format.ResponseType = "github.com/acme/app/models.User"
+
+	var out strings.Builder
+
+	generator := &Generator{PackageName: "testgen", Format: format, Dialect: "postgres"}
+	if err := generator.Generate(&out); err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	code := out.String()
+	for _, want := range []string{
+		`"github.com/acme/app/models"`,
+		"iter.Seq2[*models.User, error]",
+		"item := new(models.User)",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code does not contain %q\n%s", want, code)
+		}
+	}
+
+	if strings.Contains(code, "ListUsersResult") {
+		t.Errorf("declared response type must replace the generated struct:\n%s", code)
+	}
+
+	var helper strings.Builder
+	if err := generator.GenerateMockHelper(&helper); err != nil {
+		t.Fatalf("GenerateMockHelper returned error: %v", err)
+	}
+
+	if !strings.Contains(helper.String(), "rows ...models.User") || !strings.Contains(helper.String(), `"github.com/acme/app/models"`) {
+		t.Errorf("mock helper must use the declared type:\n%s", helper.String())
+	}
+}
+
+func TestDeclaredResponseTypeErrors(t *testing.T) {
+	for _, value := range []string{"not a type", "github.com/acme/app/models.user", ".User"} {
+		if _, _, err := declaredResponseType(value); !errors.Is(err, ErrInvalidResponseType) {
+			t.Errorf("%q: expected ErrInvalidResponseType, got %v", value, err)
+		}
+	}
+
+	format := &intermediate.IntermediateFormat{FunctionName: "delete_user", ResponseAffinity: "none", ResponseType: "User"}
+
+	var out strings.Builder
+	if err := (&Generator{Format: format, Dialect: "postgres"}).Generate(&out); !errors.Is(err, ErrInvalidResponseType) {
+		t.Errorf("expected ErrInvalidResponseType for a function without rows, got %v", err)
+	}
+}
+
+func TestGenerateTypeMappings(t *testing.T) {
+	format := userQuery("get_user", "one")
+	format.Responses = []intermediate.Response{
+		{Name: "id", Type: "string", DatabaseType: "uuid"},
+		{Name: "name", Type: "string", DatabaseType: "text"},
+		{Name: "settings_json", Type: "string", DatabaseType: "text"},
+	}
+
+	var out strings.Builder
+
+	generator := &Generator{
+		PackageName: "testgen",
+		Format:      format,
+		Dialect:     "postgres",
+		TypeMappings: []snapsql.TypeMappingConfig{
+			{ColumnType: "uuid", Go: "github.com/google/uuid.UUID"},
+			{Column: "*_json", Go: "encoding/json.RawMessage"},
+		},
+	}
+	if err := generator.Generate(&out); err != nil {
+		t.Fatalf("Generate returned error: %v", err)
+	}
+
+	code := out.String()
+	for _, want := range []string{
+		`"github.com/google/uuid"`,
+		`"encoding/json"`,
+		"ID           uuid.UUID",
+		"Name         string",
+		"SettingsJson json.RawMessage",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code does not contain %q\n%s", want, code)
+		}
+	}
+
+	if format.Responses[0].Type != "string" {
+		t.Errorf("type mappings must not modify the intermediate format")
+	}
+}
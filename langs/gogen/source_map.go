@@ -0,0 +1,132 @@
+package gogen
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+)
+
+// Markers written into the SQL building code before formatting. They are removed from the
+// output and turned into source map entries (or //line directives).
+const (
+	sourcePosMarker = "//snapsql:pos "
+	sourceEndMarker = "//snapsql:end"
+)
+
+// SourceMapVersion is the version of the source map layout
+const SourceMapVersion = 1
+
+// SourceMap links lines of a generated Go file to positions in the template it was generated from.
+type SourceMap struct {
+	Version int `json:"version"`
+	// File is the generated Go file
+	File string `json:"file,omitempty"`
+	// Source is the template file
+	Source string `json:"source,omitempty"`
+	// Mappings lists the generated lines that come from a template position, in line order
+	Mappings []SourceMapping `json:"mappings"`
+}
+
+// SourceMapping maps one generated line to a template position.
+type SourceMapping struct {
+	// Line is the 1-based line in the generated file
+	Line int `json:"line"`
+	// SourceLine is the 1-based line in the template
+	SourceLine int `json:"source_line"`
+	// SourceColumn is the column in the template (0 when only the line is known)
+	SourceColumn int `json:"source_column,omitempty"`
+}
+
+// Lookup returns the template position of a generated line.
+func (m *SourceMap) Lookup(line int) (SourceMapping, bool) {
+	for _, mapping := range m.Mappings {
+		if mapping.Line == line {
+			return mapping, true
+		}
+	}
+
+	return SourceMapping{}, false
+}
+
+// applySourceMarkers removes the position markers from formatted code, returns the cleaned code
+// and appends the mappings of the marked lines to sourceMap. When lineDirectives is true, markers
+// become //line directives pointing the compiler at the template, and back at the generated file
+// after the SQL building code.
+func applySourceMarkers(code []byte, sourceMap *SourceMap, lineDirectives bool) []byte {
+	var (
+		out     strings.Builder
+		line    int
+		current *SourceMapping
+	)
+
+	writeLine := func(text string) {
+		out.WriteString(text)
+		out.WriteByte('\n')
+
+		line++
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(code)))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(code)+1)
+
+	for scanner.Scan() {
+		text := scanner.Text()
+		trimmed := strings.TrimSpace(text)
+
+		switch {
+		case strings.HasPrefix(trimmed, sourcePosMarker):
+			current = parseSourcePos(strings.TrimPrefix(trimmed, sourcePosMarker))
+			if current != nil && lineDirectives {
+				writeLine(lineDirective(sourceMap.Source, current.SourceLine, current.SourceColumn))
+			}
+
+			continue
+		case trimmed == sourceEndMarker:
+			if current != nil && lineDirectives {
+				// the directive applies to the line following it
+				writeLine(lineDirective(sourceMap.File, line+2, 0))
+			}
+
+			current = nil
+
+			continue
+		}
+
+		writeLine(text)
+
+		if current != nil && trimmed != "" {
+			sourceMap.Mappings = append(sourceMap.Mappings, SourceMapping{
+				Line:         line,
+				SourceLine:   current.SourceLine,
+				SourceColumn: current.SourceColumn,
+			})
+		}
+	}
+
+	return []byte(out.String())
+}
+
+// parseSourcePos parses an instruction position ("line:column")
+func parseSourcePos(pos string) *SourceMapping {
+	lineText, columnText, _ := strings.Cut(strings.TrimSpace(pos), ":")
+
+	line, err := strconv.Atoi(lineText)
+	if err != nil || line <= 0 {
+		return nil
+	}
+
+	column, _ := strconv.Atoi(columnText)
+	if column < 0 {
+		column = 0
+	}
+
+	return &SourceMapping{SourceLine: line, SourceColumn: column}
+}
+
+func lineDirective(file string, line, column int) string {
+	if column > 0 {
+		return "//line " + file + ":" + strconv.Itoa(line) + ":" + strconv.Itoa(column)
+	}
+
+	return "//line " + file + ":" + strconv.Itoa(line)
+}
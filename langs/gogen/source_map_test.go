@@ -0,0 +1,130 @@
+package gogen
+
+import (
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+)
+
+func sourceMapTestFormat() *intermediate.IntermediateFormat {
+	return &intermediate.IntermediateFormat{
+		FunctionName:     "DeleteUsers",
+		StatementType:    "delete",
+		ResponseAffinity: "none",
+		Parameters: []intermediate.Parameter{
+			{Name: "user_id", Type: "int"},
+			{Name: "only_inactive", Type: "bool"},
+		},
+		CELExpressions: []intermediate.CELExpression{
+			{ID: "expr_001", Expression: "user_id", EnvironmentIndex: 0},
+			{ID: "expr_002", Expression: "only_inactive", EnvironmentIndex: 0},
+		},
+		Instructions: []intermediate.Instruction{
+			{Op: "EMIT_STATIC", Value: "DELETE FROM users WHERE id = ", Pos: "3:1"},
+			{Op: "EMIT_EVAL", ExprIndex: intPtr(0), Pos: "3:30"},
+			{Op: "IF", ExprIndex: intPtr(1), Pos: "4:1"},
+			{Op: "EMIT_STATIC", Value: " AND active = false", Pos: "5:5"},
+			{Op: "END", Pos: "6:1"},
+		},
+	}
+}
+
+func TestGenerateWithSourceMap(t *testing.T) {
+	g := &Generator{
+		PackageName: "queries",
+		Format:      sourceMapTestFormat(),
+		Dialect:     snapsql.DialectPostgres,
+		SourcePath:  "../queries/delete_users.snap.sql",
+		OutputFile:  "delete_users.go",
+	}
+
+	var out strings.Builder
+
+	sourceMap, err := g.GenerateWithSourceMap(&out)
+	if err != nil {
+		t.Fatalf("GenerateWithSourceMap failed: %v", err)
+	}
+
+	code := out.String()
+	if strings.Contains(code, "snapsql:pos") || strings.Contains(code, "snapsql:end") {
+		t.Fatalf("markers must be removed from the output:\n%s", code)
+	}
+
+	if strings.Contains(code, "//line ") {
+		t.Errorf("line directives are disabled by default")
+	}
+
+	if sourceMap.Source != g.SourcePath || sourceMap.File != g.OutputFile {
+		t.Errorf("unexpected file names: %+v", sourceMap)
+	}
+
+	lines := strings.Split(code, "\n")
+	found := map[int]bool{}
+
+	for _, mapping := range sourceMap.Mappings {
+		if mapping.Line < 1 || mapping.Line > len(lines) {
+			t.Fatalf("mapping out of range: %+v", mapping)
+		}
+
+		found[mapping.SourceLine] = true
+		text := lines[mapping.Line-1]
+
+		if mapping.SourceLine == 3 && mapping.SourceColumn == 1 && strings.Contains(text, `_frag := "DELETE FROM users`) {
+			found[0] = true
+		}
+	}
+
+	for _, line := range []int{0, 3, 4, 5, 6} {
+		if !found[line] {
+			t.Errorf("missing mapping for template line %d: %+v", line, sourceMap.Mappings)
+		}
+	}
+
+	if m, ok := sourceMap.Lookup(sourceMap.Mappings[0].Line); !ok || m != sourceMap.Mappings[0] {
+		t.Errorf("Lookup did not return the first mapping: %+v", m)
+	}
+}
+
+func TestGenerateWithLineDirectives(t *testing.T) {
+	g := &Generator{
+		PackageName:    "queries",
+		Format:         sourceMapTestFormat(),
+		Dialect:        snapsql.DialectPostgres,
+		SourcePath:     "../queries/delete_users.snap.sql",
+		OutputFile:     "delete_users.go",
+		LineDirectives: true,
+	}
+
+	var out strings.Builder
+	if _, err := g.GenerateWithSourceMap(&out); err != nil {
+		t.Fatalf("GenerateWithSourceMap failed: %v", err)
+	}
+
+	code := out.String()
+	if !strings.Contains(code, "\n//line ../queries/delete_users.snap.sql:3:1\n") {
+		t.Errorf("missing directive pointing at the template:\n%s", code)
+	}
+
+	// The directive after the SQL building code restores the generated file position
+	lines := strings.Split(code, "\n")
+	restored := false
+
+	for i, line := range lines {
+		if strings.HasPrefix(line, "//line delete_users.go:") {
+			restored = line == "//line delete_users.go:"+strconv.Itoa(i+2)
+		}
+	}
+
+	if !restored {
+		t.Errorf("missing directive restoring the generated file position:\n%s", code)
+	}
+
+	if _, err := parser.ParseFile(token.NewFileSet(), "delete_users.go", code, parser.ParseComments); err != nil {
+		t.Errorf("generated code with directives does not parse: %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package gogen
 import (
 	"fmt"
 	"slices"
+	"strconv"
 	"strings"
 	"unicode"
 
@@ -23,6 +24,8 @@ type sqlBuilderData struct {
 	HasSystemArguments   bool     // true if Arguments includes system parameters
 	NeedsRowLockClause   bool     // true if SQL expects a runtime row-lock clause appended
 	HasFallbackGuard     bool     // true if FALLBACK_CONDITION instructions are present
+	StaticPos            string   // template position of the static SQL, used for source maps
+	HasRedactedArgs      bool     // true if some arguments are recorded in redactedArgs for query logs
 	FallbackVarName      string   // name of the boolean flag tracking fallback usage
 }
 
@@ -75,6 +78,51 @@ func buildArgumentLines(plan *renderedAccess) []string {
 	return lines
 }
 
+// buildClampedLimitLines binds a LIMIT parameter clamped to the cap configured in snapsql.yaml (limits).
+func buildClampedLimitLines(plan *renderedAccess, maxLimit string) []string {
+	lines := make([]string, 0, len(plan.Setup)+4)
+	lines = append(lines, plan.Setup...)
+
+	appendLine := fmt.Sprintf("args = append(args, snapsqlgo.ClampLimit(%s, %s))", plan.ValueVar, maxLimit)
+	if plan.ValidVar != "" {
+		lines = append(lines, fmt.Sprintf("if %s {", plan.ValidVar))
+		lines = append(lines, "\t"+appendLine)
+		lines = append(lines, "} else {")
+		lines = append(lines, fmt.Sprintf("\targs = append(args, int64(%s))", maxLimit))
+		lines = append(lines, "}")
+	} else {
+		lines = append(lines, appendLine)
+	}
+
+	return lines
+}
+
+// buildIdentifierBlock writes the identifier of an ident or orderby directive after checking it against
+// the allowlist with the snapsqlgo helper. The text before the directive keeps its own whitespace, so no
+// separator is added.
+func buildIdentifierBlock(plan *renderedAccess, helper string, allowed []string, functionName string) string {
+	quoted := make([]string, 0, len(allowed))
+	for _, name := range allowed {
+		quoted = append(quoted, strconv.Quote(name))
+	}
+
+	lines := []string{"{ // allowlisted identifier"}
+	for _, line := range plan.Setup {
+		lines = append(lines, "\t"+line)
+	}
+
+	lines = append(lines,
+		fmt.Sprintf("\tident, err := snapsqlgo.%s(%s, %s)", helper, plan.ValueVar, strings.Join(quoted, ", ")),
+		"\tif err != nil {",
+		fmt.Sprintf("\t\treturn \"\", nil, fmt.Errorf(\"%s: %%w\", err)", functionName),
+		"\t}",
+		"\tbuilder.WriteString(ident)",
+		"}",
+	)
+
+	return strings.Join(lines, "\n")
+}
+
 func buildConditionLines(plan *renderedAccess, condVar string) []string {
 	lines := make([]string, 0, len(plan.Setup)+3)
 	lines = append(lines, plan.Setup...)
@@ -90,7 +138,8 @@ func buildConditionLines(plan *renderedAccess, condVar string) []string {
 }
 
 // processSQLBuilderWithDialect processes instructions and generates SQL building code for a specific dialect
-func processSQLBuilderWithDialect(format *intermediate.IntermediateFormat, dialect, functionName string) (*sqlBuilderData, error) {
+// redact lists parameter names whose argument positions are recorded for query log redaction.
+func processSQLBuilderWithDialect(format *intermediate.IntermediateFormat, dialect, functionName string, redact []string) (*sqlBuilderData, error) {
 	// Require dialect to be specified
 	if dialect == "" {
 		return nil, snapsql.ErrDialectMustBeSpecified
@@ -107,13 +156,92 @@ func processSQLBuilderWithDialect(format *intermediate.IntermediateFormat, diale
 
 	if !needsDynamic {
 		// Generate static SQL
-		return generateStaticSQLFromOptimized(optimizedInstructions, format)
+		return generateStaticSQLFromOptimized(optimizedInstructions, format, redact)
 	}
 
 	// Generate dynamic SQL building code
-	return generateDynamicSQLFromOptimized(optimizedInstructions, format, functionName)
+	return generateDynamicSQLFromOptimized(optimizedInstructions, format, functionName, redact)
+}
+
+// isRedactedExpression reports whether the expression bound as an argument references a parameter
+// listed in redact. Every identifier segment of the expression is compared case-insensitively, so
+// "password" matches both `password` and `user.password`.
+func isRedactedExpression(format *intermediate.IntermediateFormat, exprIndex int, redact []string) bool {
+	if len(redact) == 0 || exprIndex < 0 || exprIndex >= len(format.CELExpressions) {
+		return false
+	}
+
+	segments := strings.FieldsFunc(format.CELExpressions[exprIndex].Expression, func(r rune) bool {
+		return r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, segment := range segments {
+		for _, name := range redact {
+			if strings.EqualFold(segment, name) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// runtimePlaceholderFragment returns a Go expression that numbers the placeholders ($1, $2, ...)
+// of a dynamic fragment from the arguments bound so far. Numbers fixed at generation time are
+// wrong once a conditional block before them is skipped. The arguments of a fragment are always
+// appended right after it, so its k-th placeholder is len(args)+k. It returns "" when the
+// fragment has no numbered placeholder.
+func runtimePlaceholderFragment(val string) string {
+	var (
+		parts        []string
+		literal      strings.Builder
+		inSingle     bool
+		inDouble     bool
+		placeholders int
+	)
+
+	for i := 0; i < len(val); i++ {
+		ch := val[i]
+
+		switch {
+		case ch == '\'' && !inDouble:
+			inSingle = !inSingle
+		case ch == '"' && !inSingle:
+			inDouble = !inDouble
+		case ch == '$' && !inSingle && !inDouble && i+1 < len(val) && val[i+1] >= '0' && val[i+1] <= '9':
+			for i+1 < len(val) && val[i+1] >= '0' && val[i+1] <= '9' {
+				i++
+			}
+
+			placeholders++
+
+			if literal.Len() > 0 {
+				parts = append(parts, fmt.Sprintf("%q", literal.String()))
+				literal.Reset()
+			}
+
+			parts = append(parts, fmt.Sprintf(`fmt.Sprintf("$%%d", len(args)+%d)`, placeholders))
+
+			continue
+		}
+
+		literal.WriteByte(ch)
+	}
+
+	if placeholders == 0 {
+		return ""
+	}
+
+	if literal.Len() > 0 {
+		parts = append(parts, fmt.Sprintf("%q", literal.String()))
+	}
+
+	return strings.Join(parts, " + ")
 }
 
+// redactedArgLine records the position of the next argument for query log redaction.
+const redactedArgLine = "redactedArgs = append(redactedArgs, len(args))"
+
 func ensureSpaceBeforePlaceholders(s string) string {
 	if len(s) == 0 {
 		return s
@@ -215,12 +343,13 @@ func isWordCharBeforePlaceholder(b byte) bool {
 }
 
 // generateStaticSQLFromOptimized generates a static SQL string from optimized instructions
-func generateStaticSQLFromOptimized(instructions []codegenerator.OptimizedInstruction, format *intermediate.IntermediateFormat) (*sqlBuilderData, error) {
+func generateStaticSQLFromOptimized(instructions []codegenerator.OptimizedInstruction, format *intermediate.IntermediateFormat, redact []string) (*sqlBuilderData, error) {
 	var (
 		sqlParts             []string
 		argumentExprs        []argumentExpr
 		argumentSystemFields []string
 		hasSystemArguments   bool
+		hasRedactedArgs      bool
 		parameterIndex       = 1
 	)
 
@@ -284,9 +413,23 @@ func generateStaticSQLFromOptimized(instructions []codegenerator.OptimizedInstru
 					return nil, err
 				}
 
-				argumentExprs = append(argumentExprs, argumentExpr{Lines: indentLines(buildArgumentLines(plan), 1)})
+				lines := buildArgumentLines(plan)
+				if isRedactedExpression(format, *inst.ExprIndex, redact) {
+					lines = append([]string{redactedArgLine}, lines...)
+					hasRedactedArgs = true
+				}
+
+				argumentExprs = append(argumentExprs, argumentExpr{Lines: indentLines(lines, 1)})
 				argumentSystemFields = append(argumentSystemFields, "")
 			}
+		case codegenerator.OpEmitSystemLimit:
+			plan, err := renderer.renderValue(*inst.ExprIndex)
+			if err != nil {
+				return nil, err
+			}
+
+			argumentExprs = append(argumentExprs, argumentExpr{Lines: indentLines(buildClampedLimitLines(plan, inst.Value), 1)})
+			argumentSystemFields = append(argumentSystemFields, "")
 		case "ADD_SYSTEM_PARAM":
 			line := fmt.Sprintf("args = append(args, snapsqlgo.NormalizeNullableTimestamp(systemValues[%q]))", inst.SystemField)
 			argumentExprs = append(argumentExprs, argumentExpr{Lines: []string{"\t" + line}})
@@ -299,26 +442,35 @@ func generateStaticSQLFromOptimized(instructions []codegenerator.OptimizedInstru
 
 	staticSQL := strings.Join(sqlParts, "")
 
+	staticPos := ""
+	if i := slices.IndexFunc(instructions, func(inst codegenerator.OptimizedInstruction) bool { return inst.Pos != "" }); i >= 0 {
+		staticPos = instructions[i].Pos
+	}
+
 	return &sqlBuilderData{
 		IsStatic:             true,
 		StaticSQL:            staticSQL,
+		StaticPos:            staticPos,
 		HasArguments:         len(argumentExprs) > 0,
 		ArgumentExprs:        argumentExprs,
 		ArgumentSystemFields: argumentSystemFields,
 		HasSystemArguments:   hasSystemArguments,
 		NeedsRowLockClause:   needsRowLockClause,
+		HasRedactedArgs:      hasRedactedArgs,
 	}, nil
 }
 
 // generateDynamicSQLFromOptimized generates dynamic SQL building code from optimized instructions
-func generateDynamicSQLFromOptimized(instructions []codegenerator.OptimizedInstruction, format *intermediate.IntermediateFormat, functionName string) (*sqlBuilderData, error) {
+func generateDynamicSQLFromOptimized(instructions []codegenerator.OptimizedInstruction, format *intermediate.IntermediateFormat, functionName string, redact []string) (*sqlBuilderData, error) {
 	var code []string
 
 	scope := newExpressionScope(format.Parameters)
+	scope.push(optionalSetBindings(format))
 	renderer := newExpressionRenderer(format, scope)
 
 	hasArguments := false
 	hasSystemArguments := false
+	hasRedactedArgs := false
 	condCounter := 0
 	loopCounter := 0
 	fallbackCounter := 0
@@ -375,7 +527,15 @@ func generateDynamicSQLFromOptimized(instructions []codegenerator.OptimizedInstr
 		code = append(code, "var boundaryNeeded bool")
 	}
 
+	hasPositions := false
+
 	for i, inst := range instructions {
+		// ソースマップ用に命令のテンプレート上の位置を記録する（生成後に取り除かれる）
+		if inst.Pos != "" {
+			code = append(code, sourcePosMarker+inst.Pos)
+			hasPositions = true
+		}
+
 		switch inst.Op {
 		case "EMIT_STATIC":
 			// WHERE/RETURNING の前にスペースを強制する
@@ -390,6 +550,10 @@ func generateDynamicSQLFromOptimized(instructions []codegenerator.OptimizedInstr
 			{
 				// Normal static content processing
 				frag := fmt.Sprintf("%q", val)
+				if numbered := runtimePlaceholderFragment(val); numbered != "" {
+					frag = numbered
+				}
+
 				// 直前に出力がある場合のみワンスペースを追加する
 				code = append(code, fmt.Sprintf(`{ // append static fragment
 	_frag := %s
@@ -422,10 +586,38 @@ func generateDynamicSQLFromOptimized(instructions []codegenerator.OptimizedInstr
 				}
 
 				code = append(code, fmt.Sprintf("// Evaluate expression %d", *inst.ExprIndex))
+				if isRedactedExpression(format, *inst.ExprIndex, redact) {
+					code = append(code, redactedArgLine)
+					hasRedactedArgs = true
+				}
+
 				code = append(code, buildArgumentLines(plan)...)
 				hasArguments = true
 			}
 
+		case codegenerator.OpEmitSystemLimit:
+			plan, err := renderer.renderValue(*inst.ExprIndex)
+			if err != nil {
+				return nil, err
+			}
+
+			code = append(code, fmt.Sprintf("// Evaluate expression %d clamped to LIMIT %s", *inst.ExprIndex, inst.Value))
+			code = append(code, buildClampedLimitLines(plan, inst.Value)...)
+			hasArguments = true
+
+		case codegenerator.OpEmitIdent, codegenerator.OpEmitOrderBy:
+			plan, err := renderer.renderValue(*inst.ExprIndex)
+			if err != nil {
+				return nil, err
+			}
+
+			helper := "AllowedIdentifier"
+			if inst.Op == codegenerator.OpEmitOrderBy {
+				helper = "AllowedOrderBy"
+			}
+
+			code = append(code, buildIdentifierBlock(plan, helper, inst.Allowed, functionName))
+
 		case "ADD_SYSTEM_PARAM":
 			code = append(code, "// Add system parameter: "+inst.SystemField)
 			code = append(code, fmt.Sprintf("args = append(args, snapsqlgo.NormalizeNullableTimestamp(systemValues[%q]))", inst.SystemField))
@@ -435,6 +627,46 @@ func generateDynamicSQLFromOptimized(instructions []codegenerator.OptimizedInstr
 		case codegenerator.OpEmitSystemFor:
 			// Row-lock clause appended after query construction
 
+		case codegenerator.OpEmitSystemSoftDelete:
+			code = append(code, fmt.Sprintf(`if !snapsqlgo.IncludeDeleted(ctx, %q, %q, opts...) { // soft delete filter
+	builder.WriteString(%q)
+}`, functionName, strings.ToLower(format.StatementType), " "+inst.Value))
+
+		case codegenerator.OpEmitCursorCondition:
+			frag := fmt.Sprintf("%q", " "+inst.Value)
+			if numbered := runtimePlaceholderFragment(" " + inst.Value); numbered != "" {
+				frag = numbered
+			}
+
+			cursorArgs := make([]string, 0, len(format.CursorPagination.ConditionArgs()))
+			for _, key := range format.CursorPagination.ConditionArgs() {
+				cursorArgs = append(cursorArgs, fmt.Sprintf("cursorValues[%d]", key))
+			}
+
+			code = append(code, fmt.Sprintf(`if %s != "" { // cursor pagination
+	cursorValues, err := snapsqlgo.DecodeCursor(%s, %d)
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: %%w", err)
+	}
+	builder.WriteString(%s)
+	args = append(args, %s)
+}`, cursorAfterParamName, cursorAfterParamName, len(format.CursorPagination.Keys), functionName, frag, strings.Join(cursorArgs, ", ")))
+			hasArguments = true
+
+		case codegenerator.OpEmitCursorLimit:
+			// 次のページの有無を判定するためにページサイズ + 1 行を取得する
+			limit, err := cursorPageLimit(format)
+			if err != nil {
+				return nil, err
+			}
+
+			if inst.ExprIndex != nil {
+				code = append(code, fmt.Sprintf("args = append(args, %s+1)", limit))
+				hasArguments = true
+			} else {
+				code = append(code, fmt.Sprintf("builder.WriteString(%q)", strconv.Itoa(format.CursorPagination.Limit+1)))
+			}
+
 		case "EMIT_UNLESS_BOUNDARY":
 			if needsBoundaryTracking {
 				// Check if we're inside a loop
@@ -499,6 +731,14 @@ func generateDynamicSQLFromOptimized(instructions []codegenerator.OptimizedInstr
 					return nil, err
 				}
 
+				if isOptionalSetCondition(format, *inst.ExprIndex) {
+					// set_optional: a nil field leaves the column unchanged
+					code = append(code, fmt.Sprintf("if %s != nil { // set_optional", plan.ValueVar))
+					controlStack = append(controlStack, controlFrame{typ: "if"})
+
+					break
+				}
+
 				condVar := fmt.Sprintf("condValue%d", condCounter)
 				condCounter++
 
@@ -666,6 +906,10 @@ func generateDynamicSQLFromOptimized(instructions []codegenerator.OptimizedInstr
 		}
 	}
 
+	if hasPositions {
+		code = append(code, sourceEndMarker)
+	}
+
 	return &sqlBuilderData{
 		IsStatic:           false,
 		BuilderCode:        code,
@@ -674,5 +918,6 @@ func generateDynamicSQLFromOptimized(instructions []codegenerator.OptimizedInstr
 		NeedsRowLockClause: needsRowLockClause,
 		HasFallbackGuard:   hasFallbackGuard,
 		FallbackVarName:    fallbackGuardVar,
+		HasRedactedArgs:    hasRedactedArgs,
 	}, nil
 }
@@ -90,8 +90,8 @@ func TestSystemColumnsIntegration(t *testing.T) {
 	assert.Contains(t, generatedCode, "$1,$2, $3, $4, $5, $6")
 
 	// Verify system column handling with default values
-	assert.Contains(t, generatedCode, `{Name: "created_at", Type: "time.Time", Required: false, DefaultValue: time.Now()}`)
-	assert.Contains(t, generatedCode, `{Name: "updated_at", Type: "time.Time", Required: false, DefaultValue: time.Now()}`)
+	assert.Contains(t, generatedCode, `{Name: "created_at", Type: "time.Time", Required: false, DefaultValue: snapsqlgo.Now(ctx)}`)
+	assert.Contains(t, generatedCode, `{Name: "updated_at", Type: "time.Time", Required: false, DefaultValue: snapsqlgo.Now(ctx)}`)
 	assert.Contains(t, generatedCode, `{Name: "created_by", Type: "int", Required: true}`)
 	assert.Contains(t, generatedCode, `{Name: "version", Type: "int", Required: false, DefaultValue: 1}`)
 
@@ -164,11 +164,13 @@ func TestSystemColumnsWithMissingRequiredValue(t *testing.T) {
 
 func TestSystemColumnsWithDefaults(t *testing.T) {
 	// Only set some values, let others use defaults
+	fixed := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
 	ctx := snapsqlgo.WithSystemValue(t.Context(), "created_by", 123)
+	ctx = snapsqlgo.WithClock(ctx, snapsqlgo.FixedClock(fixed))
 
 	implicitSpecs := []snapsqlgo.ImplicitParamSpec{
-		{Name: "created_at", Type: "time.Time", Required: false, DefaultValue: time.Now()},
-		{Name: "updated_at", Type: "time.Time", Required: false, DefaultValue: time.Now()},
+		{Name: "created_at", Type: "time.Time", Required: false, DefaultValue: snapsqlgo.Now(ctx)},
+		{Name: "updated_at", Type: "time.Time", Required: false, DefaultValue: snapsqlgo.Now(ctx)},
 		{Name: "created_by", Type: "int", Required: true},
 		{Name: "version", Type: "int", Required: false, DefaultValue: 1},
 	}
@@ -177,9 +179,9 @@ func TestSystemColumnsWithDefaults(t *testing.T) {
 
 	// Verify values
 	assert.Equal(t, 123, systemValues["created_by"])
-	assert.Equal(t, 1, systemValues["version"])  // default value from spec
-	assert.NotNil(t, systemValues["created_at"]) // time.Now() from spec
-	assert.NotNil(t, systemValues["updated_at"]) // time.Now() from spec
+	assert.Equal(t, 1, systemValues["version"])        // default value from spec
+	assert.Equal(t, fixed, systemValues["created_at"]) // clock time from spec
+	assert.Equal(t, fixed, systemValues["updated_at"]) // clock time from spec
 }
 
 func TestOptimizedInstructionsWithSystemColumns(t *testing.T) {
@@ -237,6 +239,7 @@ func TestGenerateRowLockExecutionCode(t *testing.T) {
 	require.NoError(t, gen.Generate(&output))
 
 	code := output.String()
+	assert.Contains(t, code, `rowLockMode := snapsqlgo.ResolveRowLockMode(ctx, "RowLockSelect", "", opts...)`)
 	assert.Contains(t, code, "EnsureRowLockAllowed(snapsqlgo.QueryLogQueryTypeSelect, rowLockMode)")
 	assert.Contains(t, code, `rowLockClause, rowLockErr = snapsqlgo.BuildRowLockClausePostgres(rowLockMode)`)
 	assert.Contains(t, code, "Options:    queryLogOptions")
@@ -0,0 +1,39 @@
+package gogen
+
+import (
+	"path"
+	"regexp"
+
+	"github.com/shibukawa/snapsql/intermediate"
+)
+
+var majorVersionSuffix = regexp.MustCompile(`^v[0-9]+$`)
+
+func findTemplateFunction(functions []intermediate.TemplateFunction, name string) (intermediate.TemplateFunction, bool) {
+	for _, fn := range functions {
+		if fn.Name == name {
+			return fn, true
+		}
+	}
+
+	return intermediate.TemplateFunction{}, false
+}
+
+// templateFunctionReference は生成コードから関数を参照する式を返す
+// パッケージ名はインポートパスの末尾（/vN は除く）と一致している前提
+func templateFunctionReference(fn intermediate.TemplateFunction) string {
+	if fn.GoPackage == "" {
+		return fn.GoName
+	}
+
+	return templateFunctionPackageName(fn.GoPackage) + "." + fn.GoName
+}
+
+func templateFunctionPackageName(importPath string) string {
+	base := path.Base(importPath)
+	if majorVersionSuffix.MatchString(base) {
+		base = path.Base(path.Dir(importPath))
+	}
+
+	return base
+}
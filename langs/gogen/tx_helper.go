@@ -0,0 +1,49 @@
+package gogen
+
+import (
+	"fmt"
+	"go/format"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// TxHelperFileName is the per-package file holding the RunInTx helper written with `transactions: true`.
+const TxHelperFileName = "snapsql_tx.go"
+
+// GenerateTxHelper writes the per-package RunInTx helper that runs a function in a transaction
+// joined by the generated functions of the package.
+func (g *Generator) GenerateTxHelper(w io.Writer) error {
+	var buf strings.Builder
+	if err := txHelperTemplate.Execute(&buf, g); err != nil {
+		return fmt.Errorf("failed to execute transaction helper template: %w", err)
+	}
+
+	formatted, err := format.Source([]byte(buf.String()))
+	if err != nil {
+		return fmt.Errorf("failed to format transaction helper: %w", err)
+	}
+
+	_, err = w.Write(formatted)
+
+	return err
+}
+
+var txHelperTemplate = template.Must(template.New("tx_helper").Parse(`// Code generated by snapsql. DO NOT EDIT.
+
+package {{ .PackageName }}
+
+import (
+	"context"
+
+	"github.com/shibukawa/snapsql/langs/snapsqlgo"
+)
+
+// RunInTx runs fn in a transaction begun on db, committing it when fn returns nil and rolling
+// it back otherwise. Pass tx as the executor of the functions of this package to run them in
+// the transaction. When db already is a *sql.Tx, fn joins it and the caller keeps control of
+// the commit.
+func RunInTx(ctx context.Context, db snapsqlgo.DBExecutor, fn func(ctx context.Context, tx snapsqlgo.DBExecutor) error) error {
+	return snapsqlgo.RunInTx(ctx, db, nil, fn)
+}
+`))
@@ -0,0 +1,59 @@
+package gogen
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+)
+
+// mapResponseTypes returns a copy of format whose response fields and parameters carry the Go
+// types of their database enums and whose response fields carry the Go types selected by the
+// type mappings (the first matching mapping wins, and mappings take precedence over enums), and
+// the packages those types come from. Hierarchical fields (a__b) are matched by their last segment.
+func mapResponseTypes(format *intermediate.IntermediateFormat, mappings []snapsql.TypeMappingConfig) (*intermediate.IntermediateFormat, []string) {
+	if len(mappings) == 0 && !usesEnums(format) {
+		return format, nil
+	}
+
+	mapped := *format
+	mapped.Responses = slices.Clone(format.Responses)
+	mapped.Parameters = slices.Clone(format.Parameters)
+
+	for i, param := range mapped.Parameters {
+		mapped.Parameters[i].Type = enumFieldType(param.Type, param.Enum)
+	}
+
+	var imports []string
+
+	for i, response := range mapped.Responses {
+		mapped.Responses[i].Type = enumFieldType(response.Type, response.Enum)
+
+		column := response.Name
+		if idx := strings.LastIndex(column, "__"); idx >= 0 {
+			column = column[idx+2:]
+		}
+
+		for _, mapping := range mappings {
+			if !mapping.Matches(response.DatabaseType, column) {
+				continue
+			}
+
+			importPath, name := mapping.GoType()
+			if importPath != "" {
+				name = templateFunctionPackageName(importPath) + "." + name
+
+				if !slices.Contains(imports, importPath) {
+					imports = append(imports, importPath)
+				}
+			}
+
+			mapped.Responses[i].Type = name
+
+			break
+		}
+	}
+
+	return &mapped, imports
+}
@@ -15,6 +15,23 @@ import (
 
 const DefaultOutputDir = "testdata/mock"
 
+// FileSystem is the file access used by the generator.
+type FileSystem interface {
+	ReadFile(name string) ([]byte, error)
+	WriteFile(name string, data []byte, perm os.FileMode) error
+	MkdirAll(path string, perm os.FileMode) error
+}
+
+type osFileSystem struct{}
+
+func (osFileSystem) ReadFile(name string) ([]byte, error) { return os.ReadFile(name) }
+
+func (osFileSystem) WriteFile(name string, data []byte, perm os.FileMode) error {
+	return os.WriteFile(name, data, perm)
+}
+
+func (osFileSystem) MkdirAll(path string, perm os.FileMode) error { return os.MkdirAll(path, perm) }
+
 // Generator writes mock JSON files from intermediate artifacts.
 type Generator struct {
 	OutputDir         string
@@ -23,18 +40,28 @@ type Generator struct {
 	GenerateEmbed     bool
 	EmbedPackage      string
 	EmbedFile         string
-	generatedPaths    []string
+	// FS replaces the operating system's file system when set
+	FS             FileSystem
+	generatedPaths []string
+}
+
+func (g *Generator) fs() FileSystem {
+	if g.FS != nil {
+		return g.FS
+	}
+
+	return osFileSystem{}
 }
 
-// Generate reads the given intermediate JSON file and writes the corresponding mock JSON.
+// Generate reads the given intermediate file and writes the corresponding mock JSON.
 func (g *Generator) Generate(intermediatePath string) (string, error) {
-	data, err := os.ReadFile(intermediatePath)
+	data, err := g.fs().ReadFile(intermediatePath)
 	if err != nil {
 		return "", fmt.Errorf("mockgen: failed to read intermediate file %s: %w", intermediatePath, err)
 	}
 
-	var format intermediate.IntermediateFormat
-	if err := json.Unmarshal(data, &format); err != nil {
+	format, err := intermediate.Decode(data)
+	if err != nil {
 		return "", fmt.Errorf("mockgen: failed to unmarshal intermediate file %s: %w", intermediatePath, err)
 	}
 
@@ -59,7 +86,7 @@ func (g *Generator) Generate(intermediatePath string) (string, error) {
 	relativePath := filepath.Join(relativeDir, baseName+".json")
 	outputPath := filepath.Join(outputDir, relativePath)
 
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+	if err := g.fs().MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
 		return "", fmt.Errorf("mockgen: failed to create directory %s: %w", filepath.Dir(outputPath), err)
 	}
 
@@ -72,7 +99,7 @@ func (g *Generator) Generate(intermediatePath string) (string, error) {
 
 	encoded = append(encoded, '\n')
 
-	if err := os.WriteFile(outputPath, encoded, 0o644); err != nil {
+	if err := g.fs().WriteFile(outputPath, encoded, 0o644); err != nil {
 		return "", fmt.Errorf("mockgen: failed to write %s: %w", outputPath, err)
 	}
 
@@ -148,11 +175,11 @@ func Provider() snapsqlgo.MockProvider {
 	}
 
 	outputPath := filepath.Join(g.OutputDir, embedFile)
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
+	if err := g.fs().MkdirAll(filepath.Dir(outputPath), 0o755); err != nil {
 		return "", fmt.Errorf("mockgen: failed to create embed directory %s: %w", filepath.Dir(outputPath), err)
 	}
 
-	if err := os.WriteFile(outputPath, formatted, 0o644); err != nil {
+	if err := g.fs().WriteFile(outputPath, formatted, 0o644); err != nil {
 		return "", fmt.Errorf("mockgen: failed to write embed file %s: %w", outputPath, err)
 	}
 
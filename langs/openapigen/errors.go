@@ -0,0 +1,14 @@
+package openapigen
+
+import "errors"
+
+var (
+	// ErrMissingFunctionName is returned when an intermediate format has no function_name.
+	ErrMissingFunctionName = errors.New("openapigen: missing function name")
+
+	// ErrDuplicateSchema is returned when two queries map to the same schema name.
+	ErrDuplicateSchema = errors.New("openapigen: duplicate schema name")
+
+	// ErrUnsupportedFormat is returned for output formats other than yaml and json.
+	ErrUnsupportedFormat = errors.New("openapigen: unsupported output format")
+)
@@ -0,0 +1,308 @@
+package openapigen
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/shibukawa/snapsql/intermediate"
+)
+
+const (
+	// DefaultOutputPath is used when the generator output is not configured.
+	DefaultOutputPath = "generated/openapi.yaml"
+
+	openAPIVersion = "3.1.0"
+)
+
+// Generator collects intermediate formats and writes an OpenAPI 3.1 document whose
+// components.schemas contain a parameter schema and a result schema per query.
+//
+// For a query with function_name "get_board" the schemas are named GetBoardParams and
+// GetBoardResult. Result fields named like "lists__id" become nested arrays of objects,
+// matching the hierarchical structs emitted by the Go generator.
+type Generator struct {
+	Title   string
+	Version string
+	// Format is "yaml" (default) or "json".
+	Format string
+
+	schemas map[string]any
+}
+
+// Add registers the schemas of one query.
+func (g *Generator) Add(format *intermediate.IntermediateFormat) error {
+	if format.FunctionName == "" {
+		return fmt.Errorf("%w: function_name is empty", ErrMissingFunctionName)
+	}
+
+	if g.schemas == nil {
+		g.schemas = make(map[string]any)
+	}
+
+	base := pascalCase(format.FunctionName)
+
+	for _, name := range []string{base + "Params", base + "Result"} {
+		if _, exists := g.schemas[name]; exists {
+			return fmt.Errorf("%w: %s", ErrDuplicateSchema, name)
+		}
+	}
+
+	g.schemas[base+"Params"] = parameterSchema(format)
+
+	if len(format.Responses) > 0 {
+		g.schemas[base+"Result"] = resultSchema(format)
+	}
+
+	return nil
+}
+
+// Generate writes the OpenAPI document.
+func (g *Generator) Generate(w io.Writer) error {
+	title := g.Title
+	if title == "" {
+		title = "SnapSQL queries"
+	}
+
+	version := g.Version
+	if version == "" {
+		version = "1.0.0"
+	}
+
+	schemas := g.schemas
+	if schemas == nil {
+		schemas = map[string]any{}
+	}
+
+	doc := map[string]any{
+		"openapi": openAPIVersion,
+		"info": map[string]any{
+			"title":   title,
+			"version": version,
+		},
+		"components": map[string]any{
+			"schemas": schemas,
+		},
+	}
+
+	switch strings.ToLower(g.Format) {
+	case "", "yaml", "yml":
+		b, err := yaml.MarshalWithOptions(doc, yaml.IndentSequence(true))
+		if err != nil {
+			return fmt.Errorf("openapigen: failed to encode YAML: %w", err)
+		}
+
+		_, err = w.Write(b)
+
+		return err
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(doc)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, g.Format)
+	}
+}
+
+func parameterSchema(format *intermediate.IntermediateFormat) map[string]any {
+	properties := make(map[string]any, len(format.Parameters))
+	required := make([]string, 0, len(format.Parameters))
+
+	for _, p := range format.Parameters {
+		schema := typeSchema(p.Type)
+		if p.Description != "" {
+			schema["description"] = p.Description
+		}
+
+		properties[p.Name] = schema
+
+		if !p.Optional {
+			required = append(required, p.Name)
+		}
+	}
+
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	if format.Description != "" {
+		schema["description"] = format.Description
+	}
+
+	return schema
+}
+
+// resultNode is one level of a hierarchical result (root or a "parent__child" group)
+type resultNode struct {
+	fields   []intermediate.Response
+	names    []string
+	children map[string]*resultNode
+	order    []string
+}
+
+func newResultNode() *resultNode {
+	return &resultNode{children: make(map[string]*resultNode)}
+}
+
+func resultSchema(format *intermediate.IntermediateFormat) map[string]any {
+	root := newResultNode()
+
+	for _, r := range format.Responses {
+		segments := strings.Split(r.Name, "__")
+		node := root
+
+		for _, seg := range segments[:len(segments)-1] {
+			child, ok := node.children[seg]
+			if !ok {
+				child = newResultNode()
+				node.children[seg] = child
+				node.order = append(node.order, seg)
+			}
+
+			node = child
+		}
+
+		node.fields = append(node.fields, r)
+		node.names = append(node.names, segments[len(segments)-1])
+	}
+
+	schema := root.schema()
+	if affinity := strings.ToLower(format.ResponseAffinity); affinity != "" {
+		schema["x-snapsql-response-affinity"] = affinity
+	}
+
+	return schema
+}
+
+func (n *resultNode) schema() map[string]any {
+	properties := make(map[string]any, len(n.fields)+len(n.children))
+	required := make([]string, 0, len(n.fields)+len(n.children))
+
+	for i, field := range n.fields {
+		name := n.names[i]
+		schema := typeSchema(field.Type)
+
+		if field.MaxLength != nil {
+			schema["maxLength"] = *field.MaxLength
+		}
+
+		if field.IsNullable {
+			schema = nullable(schema)
+		}
+
+		properties[name] = schema
+		required = append(required, name)
+	}
+
+	for _, name := range n.order {
+		properties[name] = map[string]any{
+			"type":  "array",
+			"items": n.children[name].schema(),
+		}
+		required = append(required, name)
+	}
+
+	sort.Strings(required)
+
+	return map[string]any{
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// typeSchema converts a SnapSQL type name to a JSON Schema (OpenAPI 3.1) fragment.
+func typeSchema(snapType string) map[string]any {
+	if base, ok := strings.CutSuffix(snapType, "[]"); ok {
+		return map[string]any{"type": "array", "items": typeSchema(base)}
+	}
+
+	if base, ok := strings.CutPrefix(snapType, "*"); ok {
+		return nullable(typeSchema(base))
+	}
+
+	switch strings.ToLower(snapType) {
+	case "int", "int64":
+		return map[string]any{"type": "integer", "format": "int64"}
+	case "int32":
+		return map[string]any{"type": "integer", "format": "int32"}
+	case "int16", "int8":
+		return map[string]any{"type": "integer", "format": "int32"}
+	case "float", "float64":
+		return map[string]any{"type": "number", "format": "double"}
+	case "float32":
+		return map[string]any{"type": "number", "format": "float"}
+	case "decimal":
+		// Keep precision by transferring decimals as strings
+		return map[string]any{"type": "string", "format": "decimal"}
+	case "bool", "boolean":
+		return map[string]any{"type": "boolean"}
+	case "string":
+		return map[string]any{"type": "string"}
+	case "timestamp", "datetime":
+		return map[string]any{"type": "string", "format": "date-time"}
+	case "date":
+		return map[string]any{"type": "string", "format": "date"}
+	case "time":
+		return map[string]any{"type": "string", "format": "time"}
+	case "uuid":
+		return map[string]any{"type": "string", "format": "uuid"}
+	case "bytes":
+		return map[string]any{"type": "string", "contentEncoding": "base64"}
+	case "json", "any", "":
+		return map[string]any{}
+	default:
+		// Common types (e.g. "User", "./User") are not resolved here
+		name := snapType
+		if i := strings.LastIndex(name, "/"); i >= 0 {
+			name = name[i+1:]
+		}
+
+		return map[string]any{"type": "object", "x-snapsql-type": name}
+	}
+}
+
+// nullable adds "null" to the schema type list (JSON Schema 2020-12 style used by OpenAPI 3.1)
+func nullable(schema map[string]any) map[string]any {
+	// Untyped schemas already accept null
+	if t, ok := schema["type"].(string); ok {
+		schema["type"] = []string{t, "null"}
+	}
+
+	return schema
+}
+
+func pascalCase(s string) string {
+	var b strings.Builder
+
+	upper := true
+
+	for _, r := range s {
+		if r == '_' || r == '-' || r == ' ' {
+			upper = true
+			continue
+		}
+
+		if upper {
+			b.WriteString(strings.ToUpper(string(r)))
+
+			upper = false
+
+			continue
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
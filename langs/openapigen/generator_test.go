@@ -0,0 +1,97 @@
+package openapigen_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/shibukawa/snapsql/intermediate"
+	"github.com/shibukawa/snapsql/langs/openapigen"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestGeneratorWritesComponentSchemas(t *testing.T) {
+	gen := &openapigen.Generator{Title: "Kanban", Format: "json"}
+
+	require.NoError(t, gen.Add(&intermediate.IntermediateFormat{
+		FunctionName:     "get_board",
+		Description:      "Fetch a board with its lists",
+		ResponseAffinity: "one",
+		Parameters: []intermediate.Parameter{
+			{Name: "board_id", Type: "int"},
+			{Name: "tags", Type: "string[]", Optional: true},
+		},
+		Responses: []intermediate.Response{
+			{Name: "id", Type: "int"},
+			{Name: "name", Type: "string", MaxLength: intPtr(100)},
+			{Name: "archived_at", Type: "timestamp", IsNullable: true},
+			{Name: "lists__id", Type: "int"},
+			{Name: "lists__cards__title", Type: "string"},
+		},
+	}))
+	require.NoError(t, gen.Add(&intermediate.IntermediateFormat{
+		FunctionName: "delete_board",
+		Parameters:   []intermediate.Parameter{{Name: "board_id", Type: "int"}},
+	}))
+
+	var buf bytes.Buffer
+	require.NoError(t, gen.Generate(&buf))
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	require.Equal(t, "3.1.0", doc["openapi"])
+	require.Equal(t, "Kanban", doc["info"].(map[string]any)["title"])
+
+	schemas := doc["components"].(map[string]any)["schemas"].(map[string]any)
+	require.Len(t, schemas, 3) // DeleteBoardResult is omitted because the query has no responses
+
+	params := schemas["GetBoardParams"].(map[string]any)
+	require.Equal(t, []any{"board_id"}, params["required"])
+	require.Equal(t, "Fetch a board with its lists", params["description"])
+
+	props := params["properties"].(map[string]any)
+	require.Equal(t, map[string]any{"type": "integer", "format": "int64"}, props["board_id"])
+	require.Equal(t, map[string]any{"type": "array", "items": map[string]any{"type": "string"}}, props["tags"])
+
+	result := schemas["GetBoardResult"].(map[string]any)
+	require.Equal(t, "one", result["x-snapsql-response-affinity"])
+	require.Equal(t, []any{"archived_at", "id", "lists", "name"}, result["required"])
+
+	fields := result["properties"].(map[string]any)
+	require.Equal(t, map[string]any{"type": []any{"string", "null"}, "format": "date-time"}, fields["archived_at"])
+	require.Equal(t, map[string]any{"type": "string", "maxLength": float64(100)}, fields["name"])
+
+	lists := fields["lists"].(map[string]any)
+	require.Equal(t, "array", lists["type"])
+
+	listItem := lists["items"].(map[string]any)
+	cards := listItem["properties"].(map[string]any)["cards"].(map[string]any)
+	require.Equal(t, map[string]any{"type": "string"}, cards["items"].(map[string]any)["properties"].(map[string]any)["title"])
+}
+
+func TestGeneratorErrors(t *testing.T) {
+	gen := &openapigen.Generator{}
+	require.ErrorIs(t, gen.Add(&intermediate.IntermediateFormat{}), openapigen.ErrMissingFunctionName)
+
+	require.NoError(t, gen.Add(&intermediate.IntermediateFormat{FunctionName: "get_board"}))
+	require.ErrorIs(t, gen.Add(&intermediate.IntermediateFormat{FunctionName: "GetBoard"}), openapigen.ErrDuplicateSchema)
+
+	gen.Format = "toml"
+	require.ErrorIs(t, gen.Generate(&bytes.Buffer{}), openapigen.ErrUnsupportedFormat)
+}
+
+func TestGeneratorYAML(t *testing.T) {
+	gen := &openapigen.Generator{}
+	require.NoError(t, gen.Add(&intermediate.IntermediateFormat{
+		FunctionName: "list_users",
+		Parameters:   []intermediate.Parameter{{Name: "limit", Type: "int32"}},
+	}))
+
+	var buf bytes.Buffer
+	require.NoError(t, gen.Generate(&buf))
+	require.Contains(t, buf.String(), "openapi: 3.1.0")
+	require.Contains(t, buf.String(), "ListUsersParams:")
+}
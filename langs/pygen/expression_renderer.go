@@ -16,6 +16,7 @@ type pythonExpressionRenderer struct {
 var (
 	errExpressionIndexOutOfRange = errors.New("explang expression index out of range")
 	errExpressionMissingSteps    = errors.New("explang expression has no steps")
+	errTemplateFunctionCall      = errors.New("template functions (cel_functions) are not supported by the Python generator")
 )
 
 func newPythonExpressionRenderer(format *intermediate.IntermediateFormat, scope *expressionScope) *pythonExpressionRenderer {
@@ -28,6 +29,10 @@ func (r *pythonExpressionRenderer) render(index int) (string, error) {
 	}
 
 	expr := r.format.Expressions[index]
+	if expr.Function != "" {
+		return "", fmt.Errorf("%w: %s", errTemplateFunctionCall, expr.Function)
+	}
+
 	if len(expr.Steps) == 0 {
 		return "", fmt.Errorf("%w: index %d", errExpressionMissingSteps, index)
 	}
@@ -7,7 +7,6 @@ import (
 	"io"
 	"strings"
 	"text/template"
-	"time"
 
 	"github.com/shibukawa/snapsql"
 	"github.com/shibukawa/snapsql/intermediate"
@@ -105,12 +104,13 @@ func (g *Generator) Generate(w io.Writer) error {
 
 // prepareTemplateData prepares the data structure for the Python template
 func (g *Generator) prepareTemplateData() (*templateData, error) {
-	// Generate timestamp
-	timestamp := time.Now().Format(time.RFC3339)
+	if g.Format.CursorPagination != nil {
+		return nil, fmt.Errorf("%w: pagination: cursor is not supported by the Python generator", ErrGeneratePythonCode)
+	}
 
-	// Initialize template data
+	// Initialize template data. No timestamp is emitted so that regenerating
+	// unchanged templates yields identical files.
 	data := &templateData{
-		Timestamp:    timestamp,
 		FunctionName: g.Format.FunctionName,
 		Description:  g.Format.Description,
 		Dialect:      g.Dialect,
@@ -197,8 +197,11 @@ func (g *Generator) prepareTemplateData() (*templateData, error) {
 
 	// Set response affinity for template
 	data.ResponseAffinity = g.Format.ResponseAffinity
-	if data.ResponseAffinity == "" {
+	switch data.ResponseAffinity {
+	case "":
 		data.ResponseAffinity = "none"
+	case "exists", "count":
+		data.ResponseAffinity = "one"
 	}
 
 	// Determine query type from statement type
@@ -249,14 +252,18 @@ func (g *Generator) buildRuntimeImports(data *templateData) []string {
 		imports = append(imports, name)
 	}
 
-	if data.HasImplicitParams || data.WhereGuardCode != "" || data.EnableQueryLogging || data.NeedsRowLockClause {
+	if data.HasImplicitParams || data.WhereGuardCode != "" || data.EnableQueryLogging || data.NeedsRowLockClause || hasEmitSystemSoftDelete(g.Format.Instructions) {
 		add("get_snapsql_context")
 	}
 
-	if data.HasValidation {
+	if data.HasValidation || hasAllowlistedItems(g.Format.Instructions) {
 		add("ValidationError")
 	}
 
+	if hasInstruction(g.Format.Instructions, codegenerator.OpEmitOrderBy) {
+		add("normalize_order_by")
+	}
+
 	if data.QueryExecution.UsesNotFoundError {
 		add("NotFoundError")
 	}
@@ -292,6 +299,31 @@ func hasEmitSystemFor(instructions []intermediate.Instruction) bool {
 	return false
 }
 
+// hasAllowlistedItems reports whether ident/orderby directives raise ValidationError for values outside the allowlist
+func hasAllowlistedItems(instructions []intermediate.Instruction) bool {
+	return hasInstruction(instructions, codegenerator.OpEmitIdent) || hasInstruction(instructions, codegenerator.OpEmitOrderBy)
+}
+
+func hasInstruction(instructions []intermediate.Instruction, op string) bool {
+	for _, inst := range instructions {
+		if inst.Op == op {
+			return true
+		}
+	}
+
+	return false
+}
+
+func hasEmitSystemSoftDelete(instructions []intermediate.Instruction) bool {
+	for _, inst := range instructions {
+		if inst.Op == codegenerator.OpEmitSystemSoftDelete {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (g *Generator) rowLockBuilderName() string {
 	switch g.Dialect {
 	case snapsql.DialectPostgres:
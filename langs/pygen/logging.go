@@ -12,7 +12,7 @@ type QueryLogMetadata struct {
 	FuncName   string
 	SourceFile string
 	Dialect    string
-	QueryType  string // "select", "insert", "update", "delete"
+	QueryType  string // "select", "insert", "update", "delete", "upsert"
 }
 
 // generateQueryLoggerProtocol generates Python Protocol definition for QueryLogger
@@ -29,7 +29,7 @@ class QueryLogMetadata:
     func_name: str
     source_file: str
     dialect: str
-    query_type: str  # "select", "insert", "update", "delete"
+    query_type: str  # "select", "insert", "update", "delete", "upsert"
 
 
 class QueryLogger(Protocol):
@@ -363,6 +363,8 @@ func getQueryTypeFromStatementType(stmtType string) string {
 		return "update"
 	case "delete":
 		return "delete"
+	case "upsert":
+		return "upsert"
 	default:
 		return "exec"
 	}
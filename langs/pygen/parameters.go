@@ -2,6 +2,7 @@ package pygen
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -40,6 +41,18 @@ func (g *Generator) processParameters() ([]parameterData, error) {
 		params = append(params, paramData)
 	}
 
+	// Parameters with defaults must follow the required ones
+	slices.SortStableFunc(params, func(a, b parameterData) int {
+		switch {
+		case a.HasDefault == b.HasDefault:
+			return 0
+		case b.HasDefault:
+			return -1
+		default:
+			return 1
+		}
+	})
+
 	return params, nil
 }
 
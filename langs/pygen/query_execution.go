@@ -24,7 +24,8 @@ func generateQueryExecution(
 	switch affinity {
 	case "none":
 		return generateNoneAffinityExecution(format, dialect)
-	case "one":
+	case "one", "exists", "count":
+		// Scalar affinities (exists/count) are returned as a single-row result in Python
 		return generateOneAffinityExecution(format, responseStruct, dialect)
 	case "many":
 		return generateManyAffinityExecution(format, responseStruct, dialect)
@@ -144,7 +145,7 @@ if row is None:
 return %s(**row)
 `, format.FunctionName, comment, responseStruct.ClassName))
 		} else {
-			code.WriteString(fmt.Sprintf(`await cursor.execute(sql, args)
+			code.WriteString(fmt.Sprintf(`%s
 row = await cursor.fetchone()
 
 if row is None:
@@ -161,7 +162,7 @@ if row is None:
 # Map row to dataclass
 %s
 return %s(**row)
-`, format.FunctionName, comment, responseStruct.ClassName))
+`, mysqlExecute(format), format.FunctionName, comment, responseStruct.ClassName))
 		}
 
 	default:
@@ -332,12 +333,12 @@ for row in rows:
 `, responseStruct.ClassName))
 
 	case snapsql.DialectMySQL, snapsql.DialectSQLite:
-		code.WriteString(fmt.Sprintf(`await cursor.execute(sql, args)
+		code.WriteString(fmt.Sprintf(`%s
 
 # Fetch and yield rows
 async for row in cursor:
     yield %s(**row)
-`, responseStruct.ClassName))
+`, mysqlExecute(format), responseStruct.ClassName))
 
 	default:
 		panic(fmt.Sprintf("unsupported dialect: %s", dialect))
@@ -349,6 +350,18 @@ async for row in cursor:
 	}, nil
 }
 
+// mysqlExecute returns the statement executing the query on an aiomysql/aiosqlite cursor.
+// INSERT ... RETURNING on MySQL executes the INSERT and then reads the inserted rows back by key.
+func mysqlExecute(format *intermediate.IntermediateFormat) string {
+	if format.ReturningFollowUp == nil {
+		return "await cursor.execute(sql, args)"
+	}
+
+	return fmt.Sprintf(`await cursor.execute(sql, args)
+# RETURNING is not supported: read the inserted rows back by key
+await cursor.execute(%q, (cursor.lastrowid, cursor.lastrowid + cursor.rowcount))`, convertPlaceholders(format.ReturningFollowUp.SQL, snapsql.DialectMySQL))
+}
+
 // hasHierarchicalFields checks if responses contain hierarchical fields (with __)
 func hasHierarchicalFields(responses []intermediate.Response) bool {
 	for _, r := range responses {
@@ -3,12 +3,9 @@ package pygen
 import (
 	"bytes"
 	"text/template"
-	"time"
 )
 
-type runtimeTemplateData struct {
-	Timestamp string
-}
+type runtimeTemplateData struct{}
 
 // RuntimePublicSymbols lists the names exported from snapsql_runtime.py and re-exported via __all__.
 var RuntimePublicSymbols = []string{
@@ -27,6 +24,7 @@ var RuntimePublicSymbols = []string{
 	"set_row_lock_mode",
 	"set_mock_mode",
 	"allow_unsafe_queries",
+	"set_include_deleted",
 	"set_query_logger",
 	"enable_query_logging",
 	"ROW_LOCK_NONE",
@@ -36,13 +34,12 @@ var RuntimePublicSymbols = []string{
 	"build_row_lock_clause_mariadb",
 	"build_row_lock_clause_sqlite",
 	"RowLockError",
+	"normalize_order_by",
 }
 
 // RenderRuntimeModule returns the shared Python runtime module content.
 func RenderRuntimeModule() (string, error) {
-	data := runtimeTemplateData{
-		Timestamp: time.Now().Format(time.RFC3339),
-	}
+	data := runtimeTemplateData{}
 
 	tmpl, err := template.New("python_runtime").Parse(pythonRuntimeTemplate)
 	if err != nil {
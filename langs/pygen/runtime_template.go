@@ -2,7 +2,6 @@ package pygen
 
 const pythonRuntimeTemplate = `# Generated by snapsql - DO NOT EDIT
 # Shared runtime helpers
-# Generated at: {{ .Timestamp }}
 
 from dataclasses import dataclass
 from typing import Optional, List, Any, Dict, Protocol
@@ -34,6 +33,9 @@ class SnapSQLContext:
     # Unsafe mutation allowance
     allow_unsafe_mutations: bool = False
     
+    # Soft delete filter bypass
+    include_deleted: bool = False
+    
     def __post_init__(self):
         if self.system_values is None:
             self.system_values = {}
@@ -127,6 +129,12 @@ def allow_unsafe_queries(allow: bool = True) -> None:
     ctx.allow_unsafe_mutations = allow
 
 
+def set_include_deleted(enabled: bool = True) -> None:
+    """Disable the soft delete filter so soft-deleted rows are included"""
+    ctx = get_snapsql_context()
+    ctx.include_deleted = enabled
+
+
 # ============================================================================
 # Query Logging
 # ============================================================================
@@ -137,7 +145,7 @@ class QueryLogMetadata:
     func_name: str
     source_file: str
     dialect: str
-    query_type: str  # "select", "insert", "update", "delete"
+    query_type: str  # "select", "insert", "update", "delete", "upsert"
 
 
 class QueryLogger(Protocol):
@@ -349,4 +357,18 @@ def build_row_lock_clause_sqlite(mode: str) -> str:
     """SQLite does not support row locks, so this always returns an empty clause"""
     return ""
 
+
+def normalize_order_by(value: Optional[str]) -> str:
+    """Normalize an ORDER BY item for the orderby directive allowlist ("created_at desc" -> "created_at DESC", ASC is dropped)"""
+    if not value:
+        return ""
+
+    parts = value.split()
+    if len(parts) > 1 and parts[-1].upper() == "ASC":
+        parts = parts[:-1]
+    elif len(parts) > 1 and parts[-1].upper() == "DESC":
+        parts[-1] = "DESC"
+
+    return " ".join(parts)
+
 `
@@ -2,6 +2,7 @@ package pygen
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"unicode"
 
@@ -56,6 +57,13 @@ func generateStaticSQL(instructions []codegenerator.OptimizedInstruction, format
 
 				arguments = append(arguments, valueExpr)
 			}
+		case codegenerator.OpEmitSystemLimit:
+			valueExpr, err := renderer.render(*inst.ExprIndex)
+			if err != nil {
+				return nil, err
+			}
+
+			arguments = append(arguments, clampLimitExpr(valueExpr, inst.Value))
 		case "ADD_SYSTEM_PARAM":
 			arguments = append(arguments, inst.SystemField)
 		case codegenerator.OpEmitSystemFor:
@@ -108,7 +116,18 @@ func generateDynamicSQL(instructions []codegenerator.OptimizedInstruction, forma
 	indentLevel := 0
 	paramIndex := 1
 
-	for _, inst := range instructions {
+	inLoop := func() bool {
+		return slices.ContainsFunc(controlStack, func(f controlFrame) bool { return f.typ == "for" })
+	}
+
+	// Delimiters outside loops (e.g. commas of set_optional assignments) are written only after
+	// some content, tracked by boundary_needed
+	needsBoundary := hasBoundaryOutsideLoop(instructions)
+	if needsBoundary {
+		code.WriteString("boundary_needed = False\n")
+	}
+
+	for i, inst := range instructions {
 		switch inst.Op {
 		case "EMIT_STATIC":
 			value := inst.Value
@@ -120,11 +139,18 @@ func generateDynamicSQL(instructions []codegenerator.OptimizedInstruction, forma
 				paramIndex++
 			}
 
-			if indentLevel > 0 {
-				code.WriteString(strings.Repeat("    ", indentLevel))
+			indent := strings.Repeat("    ", indentLevel)
+
+			if numbered := pythonPlaceholderFragment(value); numbered != "" {
+				code.WriteString(fmt.Sprintf("%ssql_parts.append(%s)\n", indent, numbered))
+			} else {
+				code.WriteString(fmt.Sprintf("%ssql_parts.append(%q)\n", indent, value))
 			}
 
-			code.WriteString(fmt.Sprintf("sql_parts.append(%q)\n", value))
+			nextIsDelimiter := i+1 < len(instructions) && instructions[i+1].Op == "EMIT_UNLESS_BOUNDARY"
+			if needsBoundary && !inLoop() && !nextIsDelimiter && strings.TrimSpace(value) != "" {
+				code.WriteString(indent + "boundary_needed = True\n")
+			}
 
 		case "EMIT_EVAL":
 			if inst.ExprIndex != nil && hasExplangExpression(format, *inst.ExprIndex) {
@@ -164,6 +190,62 @@ func generateDynamicSQL(instructions []codegenerator.OptimizedInstruction, forma
 				code.WriteString(fmt.Sprintf("args.append(%s)\n", exprStr))
 			}
 
+		case codegenerator.OpEmitSystemLimit:
+			exprStr, err := renderer.render(*inst.ExprIndex)
+			if err != nil {
+				return nil, err
+			}
+
+			if indentLevel > 0 {
+				code.WriteString(strings.Repeat("    ", indentLevel))
+			}
+
+			code.WriteString(fmt.Sprintf("args.append(%s)\n", clampLimitExpr(exprStr, inst.Value)))
+
+		case codegenerator.OpEmitIdent:
+			exprStr, err := renderer.render(*inst.ExprIndex)
+			if err != nil {
+				return nil, err
+			}
+
+			quoted := make([]string, 0, len(inst.Allowed))
+			for _, name := range inst.Allowed {
+				quoted = append(quoted, fmt.Sprintf("%q", name))
+			}
+
+			// Identifiers cannot be bound as parameters, so only allowlisted names are written
+			indent := strings.Repeat("    ", indentLevel)
+			code.WriteString(fmt.Sprintf("%sident_value = %s\n", indent, exprStr))
+			code.WriteString(fmt.Sprintf("%sif ident_value not in (%s,):\n", indent, strings.Join(quoted, ", ")))
+			code.WriteString(fmt.Sprintf("%s    raise ValidationError(\n", indent))
+			code.WriteString(fmt.Sprintf("%s        message=f\"identifier {ident_value!r} is not allowed\",\n", indent))
+			code.WriteString(fmt.Sprintf("%s        param_name=%q,\n", indent, format.CELExpressions[*inst.ExprIndex].Expression))
+			code.WriteString(fmt.Sprintf("%s        func_name=%q\n", indent, format.FunctionName))
+			code.WriteString(indent + "    )\n")
+			code.WriteString(indent + "sql_parts.append(ident_value)\n")
+
+		case codegenerator.OpEmitOrderBy:
+			exprStr, err := renderer.render(*inst.ExprIndex)
+			if err != nil {
+				return nil, err
+			}
+
+			quoted := make([]string, 0, len(inst.Allowed))
+			for _, item := range inst.Allowed {
+				quoted = append(quoted, fmt.Sprintf("%q", item))
+			}
+
+			// An empty value falls back to the first allowlisted item
+			indent := strings.Repeat("    ", indentLevel)
+			code.WriteString(fmt.Sprintf("%sorder_by_value = normalize_order_by(%s) or %q\n", indent, exprStr, inst.Allowed[0]))
+			code.WriteString(fmt.Sprintf("%sif order_by_value not in (%s,):\n", indent, strings.Join(quoted, ", ")))
+			code.WriteString(fmt.Sprintf("%s    raise ValidationError(\n", indent))
+			code.WriteString(fmt.Sprintf("%s        message=f\"order by {order_by_value!r} is not allowed\",\n", indent))
+			code.WriteString(fmt.Sprintf("%s        param_name=%q,\n", indent, format.CELExpressions[*inst.ExprIndex].Expression))
+			code.WriteString(fmt.Sprintf("%s        func_name=%q\n", indent, format.FunctionName))
+			code.WriteString(indent + "    )\n")
+			code.WriteString(indent + "sql_parts.append(order_by_value)\n")
+
 		case "ADD_SYSTEM_PARAM":
 			if indentLevel > 0 {
 				code.WriteString(strings.Repeat("    ", indentLevel))
@@ -180,7 +262,13 @@ func generateDynamicSQL(instructions []codegenerator.OptimizedInstruction, forma
 
 				indent := strings.Repeat("    ", indentLevel)
 				code.WriteString(fmt.Sprintf("%scond_value = %s\n", indent, exprStr))
-				code.WriteString(indent + "if cond_value:\n")
+
+				if isOptionalSetCondition(format, *inst.ExprIndex) {
+					// set_optional: None leaves the column unchanged
+					code.WriteString(indent + "if cond_value is not None:\n")
+				} else {
+					code.WriteString(indent + "if cond_value:\n")
+				}
 
 				controlStack = append(controlStack, controlFrame{typ: "if"})
 				indentLevel++
@@ -252,8 +340,35 @@ func generateDynamicSQL(instructions []codegenerator.OptimizedInstruction, forma
 				controlStack = controlStack[:len(controlStack)-1]
 			}
 
+		case codegenerator.OpEmitSystemSoftDelete:
+			indent := strings.Repeat("    ", indentLevel)
+			code.WriteString(indent + "if not get_snapsql_context().include_deleted:\n")
+			code.WriteString(fmt.Sprintf("%s    sql_parts.append(%q)\n", indent, " "+inst.Value))
+
 		case codegenerator.OpEmitSystemFor:
-		case "FALLBACK_CONDITION", "BOUNDARY", "EMIT_UNLESS_BOUNDARY":
+		case "FALLBACK_CONDITION":
+			// Whole-clause fallback ("WHERE 1 = 1") keeps a WHERE keyword for trailing
+			// conditions such as the soft delete filter; combo-based fallbacks are not supported yet.
+			if len(inst.FallbackCombos) == 0 {
+				if indentLevel > 0 {
+					code.WriteString(strings.Repeat("    ", indentLevel))
+				}
+
+				code.WriteString(fmt.Sprintf("sql_parts.append(%q)\n", " "+inst.Value))
+			}
+
+		case "EMIT_UNLESS_BOUNDARY":
+			last := i+1 >= len(instructions) || instructions[i+1].Op == "END" || instructions[i+1].Op == "BOUNDARY"
+			if needsBoundary && !inLoop() && !last {
+				indent := strings.Repeat("    ", indentLevel)
+				code.WriteString(indent + "if boundary_needed:\n")
+				code.WriteString(fmt.Sprintf("%s    sql_parts.append(%q)\n", indent, inst.Value))
+			}
+
+		case "BOUNDARY":
+			if needsBoundary && !inLoop() {
+				code.WriteString(strings.Repeat("    ", indentLevel) + "boundary_needed = False\n")
+			}
 		}
 	}
 
@@ -312,3 +427,87 @@ func hasExplangExpression(format *intermediate.IntermediateFormat, index int) bo
 
 	return index >= 0 && index < len(format.Expressions)
 }
+
+// hasBoundaryOutsideLoop reports whether a conditional delimiter appears outside loops.
+func hasBoundaryOutsideLoop(instructions []codegenerator.OptimizedInstruction) bool {
+	depth := 0
+
+	for _, inst := range instructions {
+		switch inst.Op {
+		case "LOOP_START":
+			depth++
+		case "LOOP_END":
+			depth--
+		case "EMIT_UNLESS_BOUNDARY":
+			if depth == 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isOptionalSetCondition reports whether exprIndex is the condition guarding a set_optional assignment.
+func isOptionalSetCondition(format *intermediate.IntermediateFormat, exprIndex int) bool {
+	return slices.ContainsFunc(format.OptionalSet, func(column intermediate.OptionalSetColumn) bool {
+		return column.ExprIndex == exprIndex
+	})
+}
+
+// pythonPlaceholderFragment returns a Python expression that numbers the placeholders ($1, $2, ...)
+// of a dynamic fragment from the arguments bound so far, so skipped conditional blocks do not
+// leave gaps. It returns "" when the fragment has no numbered placeholder.
+func pythonPlaceholderFragment(value string) string {
+	var (
+		parts        []string
+		literal      strings.Builder
+		inSingle     bool
+		inDouble     bool
+		placeholders int
+	)
+
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+
+		switch {
+		case ch == '\'' && !inDouble:
+			inSingle = !inSingle
+		case ch == '"' && !inSingle:
+			inDouble = !inDouble
+		case ch == '$' && !inSingle && !inDouble && i+1 < len(value) && value[i+1] >= '0' && value[i+1] <= '9':
+			for i+1 < len(value) && value[i+1] >= '0' && value[i+1] <= '9' {
+				i++
+			}
+
+			placeholders++
+
+			if literal.Len() > 0 {
+				parts = append(parts, fmt.Sprintf("%q", literal.String()))
+				literal.Reset()
+			}
+
+			parts = append(parts, fmt.Sprintf(`"$" + str(len(args) + %d)`, placeholders))
+
+			continue
+		}
+
+		literal.WriteByte(ch)
+	}
+
+	if placeholders == 0 {
+		return ""
+	}
+
+	if literal.Len() > 0 {
+		parts = append(parts, fmt.Sprintf("%q", literal.String()))
+	}
+
+	return strings.Join(parts, " + ")
+}
+
+// clampLimitExpr caps a LIMIT argument to the limit configured in snapsql.yaml (limits);
+// None, zero and negative values are replaced by the cap as well.
+func clampLimitExpr(expr, maxLimit string) string {
+	return fmt.Sprintf("(min(%s, %s) if (%s or 0) > 0 else %s)", expr, maxLimit, expr, maxLimit)
+}
@@ -101,6 +101,23 @@ func TestGenerateStaticSQL(t *testing.T) {
 			wantArgs:     []string{"username", "updated_by", "user_id"},
 			wantIsStatic: true,
 		},
+		{
+			name: "LIMIT clamped to the configured cap",
+			format: &intermediate.IntermediateFormat{
+				FunctionName: "list_users",
+				Instructions: []codegenerator.Instruction{
+					{Op: codegenerator.OpEmitStatic, Value: "SELECT id FROM users LIMIT "},
+					{Op: codegenerator.OpIfSystemLimit, DefaultValue: "100"},
+					{Op: codegenerator.OpEmitSystemLimit, ExprIndex: intPtr(0), DefaultValue: "100"},
+					{Op: codegenerator.OpEnd},
+				},
+				Expressions: stubExpressions("size"),
+			},
+			dialect:      "postgres",
+			wantSQL:      "SELECT id FROM users LIMIT $1",
+			wantArgs:     []string{"(min(size, 100) if (size or 0) > 0 else 100)"},
+			wantIsStatic: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -212,6 +229,30 @@ func TestGenerateDynamicSQL(t *testing.T) {
 				"sql_parts.append(\" active = false\")",
 			},
 		},
+		{
+			name: "soft delete filter after conditional WHERE",
+			format: &intermediate.IntermediateFormat{
+				FunctionName: "delete_users",
+				Parameters:   []intermediate.Parameter{{Name: "name"}},
+				Instructions: []codegenerator.Instruction{
+					{Op: codegenerator.OpEmitStatic, Value: "UPDATE users SET deleted_at = NOW() "},
+					{Op: codegenerator.OpIf, ExprIndex: intPtr(0)},
+					{Op: codegenerator.OpEmitStatic, Value: "WHERE name = "},
+					{Op: codegenerator.OpEmitEval, ExprIndex: intPtr(1)},
+					{Op: codegenerator.OpElse},
+					{Op: codegenerator.OpFallbackCondition, Value: "WHERE 1 = 1", Critical: true},
+					{Op: codegenerator.OpEnd},
+					{Op: codegenerator.OpEmitSystemSoftDelete, Value: "AND deleted_at IS NULL"},
+				},
+				Expressions: stubExpressions("name_present", "name"),
+			},
+			dialect:      "postgres",
+			wantIsStatic: false,
+			wantCodeContains: []string{
+				"else:\n    sql_parts.append(\" WHERE 1 = 1\")",
+				"if not get_snapsql_context().include_deleted:\n    sql_parts.append(\" AND deleted_at IS NULL\")",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -5,7 +5,6 @@ import "github.com/shibukawa/snapsql"
 // pythonTemplate is the Go text/template for generating Python code
 // It follows the same pattern as gogen but outputs Python instead of Go
 const pythonTemplate = `# Generated by snapsql - DO NOT EDIT
-# Generated at: {{ .Timestamp }}
 # Function: {{ .FunctionName }}
 # Dialect: {{ .Dialect }}
 
@@ -209,7 +208,6 @@ async def {{ .FunctionName | snakeCase }}(
 // templateData represents the data passed to the Python template
 type templateData struct {
 	// Metadata
-	Timestamp    string
 	FunctionName string
 	Description  string
 	Dialect      snapsql.Dialect
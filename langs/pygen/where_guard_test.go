@@ -133,6 +133,7 @@ func TestGetMutationKind(t *testing.T) {
 		{"DELETE", "MutationDelete"},
 		{"select", ""},
 		{"insert", ""},
+		{"upsert", ""},
 		{"", ""},
 	}
 
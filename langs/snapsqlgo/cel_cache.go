@@ -0,0 +1,119 @@
+package snapsqlgo
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/cel-go/cel"
+)
+
+// CELProgramCache keeps compiled CEL programs keyed by a hash of the expression and the
+// signature of the environment it was compiled in, so identical expressions used by several
+// functions are compiled once per process. It is safe for concurrent use.
+//
+// The signature must identify the environment's declarations: two environments with the same
+// signature have to accept the same programs.
+type CELProgramCache struct {
+	mu       sync.RWMutex
+	programs map[[sha256.Size]byte]cel.Program
+	hits     atomic.Int64
+	misses   atomic.Int64
+}
+
+// CELProgramCacheStats reports the activity of a CELProgramCache.
+type CELProgramCacheStats struct {
+	Hits   int64
+	Misses int64
+	Size   int
+}
+
+// DefaultCELProgramCache is the process-wide cache used by CompileCEL.
+var DefaultCELProgramCache = NewCELProgramCache()
+
+// NewCELProgramCache creates an empty cache.
+func NewCELProgramCache() *CELProgramCache {
+	return &CELProgramCache{programs: make(map[[sha256.Size]byte]cel.Program)}
+}
+
+// CompileCEL returns the program for expression from DefaultCELProgramCache, compiling it in the
+// environment returned by env on a miss.
+func CompileCEL(signature, expression string, env func() (*cel.Env, error)) (cel.Program, error) {
+	return DefaultCELProgramCache.Program(signature, expression, env)
+}
+
+// Program returns the cached program for expression in the environment identified by signature.
+// On a miss the environment is obtained from env, the expression is compiled and the program is
+// stored; errors are not cached.
+func (c *CELProgramCache) Program(signature, expression string, env func() (*cel.Env, error)) (cel.Program, error) {
+	key := celProgramKey(signature, expression)
+
+	c.mu.RLock()
+	program, ok := c.programs[key]
+	c.mu.RUnlock()
+
+	if ok {
+		c.hits.Add(1)
+		return program, nil
+	}
+
+	c.misses.Add(1)
+
+	// Compile without holding the lock; a concurrent miss for the same key keeps the first program.
+	e, err := env()
+	if err != nil {
+		return nil, err
+	}
+
+	ast, issues := e.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, fmt.Errorf("failed to compile expression '%s': %w", expression, issues.Err())
+	}
+
+	program, err = e.Program(ast)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create program for expression '%s': %w", expression, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.programs[key]; ok {
+		return cached, nil
+	}
+
+	c.programs[key] = program
+
+	return program, nil
+}
+
+// Stats returns a snapshot of the cache counters.
+func (c *CELProgramCache) Stats() CELProgramCacheStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return CELProgramCacheStats{Hits: c.hits.Load(), Misses: c.misses.Load(), Size: len(c.programs)}
+}
+
+// Reset drops every cached program and clears the counters.
+func (c *CELProgramCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.programs = make(map[[sha256.Size]byte]cel.Program)
+	c.hits.Store(0)
+	c.misses.Store(0)
+}
+
+func celProgramKey(signature, expression string) [sha256.Size]byte {
+	h := sha256.New()
+	h.Write([]byte(signature))
+	h.Write([]byte{0})
+	h.Write([]byte(expression))
+
+	var key [sha256.Size]byte
+	h.Sum(key[:0])
+
+	return key
+}
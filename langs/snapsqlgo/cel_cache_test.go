@@ -0,0 +1,50 @@
+package snapsqlgo_test
+
+import (
+	"testing"
+
+	"github.com/google/cel-go/cel"
+	snapsqlgo "github.com/shibukawa/snapsql/langs/snapsqlgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCELProgramCache(t *testing.T) {
+	cache := snapsqlgo.NewCELProgramCache()
+
+	envBuilds := 0
+	env := func() (*cel.Env, error) {
+		envBuilds++
+		return cel.NewEnv(cel.Variable("age", cel.IntType))
+	}
+
+	for range 3 {
+		program, err := cache.Program("age:int", "age >= 18", env)
+		require.NoError(t, err)
+
+		result, _, err := program.Eval(map[string]any{"age": 20})
+		require.NoError(t, err)
+		assert.Equal(t, true, result.Value())
+	}
+
+	_, err := cache.Program("age:int", "age < 18", env)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, envBuilds)
+	assert.Equal(t, snapsqlgo.CELProgramCacheStats{Hits: 2, Misses: 2, Size: 2}, cache.Stats())
+
+	// The same expression in another environment is compiled separately
+	_, err = cache.Program("age:dyn", "age >= 18", func() (*cel.Env, error) {
+		return cel.NewEnv(cel.Variable("age", cel.DynType))
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, cache.Stats().Size)
+
+	// Errors are reported and not cached
+	_, err = cache.Program("age:int", "age >=", env)
+	require.Error(t, err)
+	assert.Equal(t, 3, cache.Stats().Size)
+
+	cache.Reset()
+	assert.Equal(t, snapsqlgo.CELProgramCacheStats{}, cache.Stats())
+}
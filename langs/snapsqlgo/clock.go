@@ -0,0 +1,42 @@
+package snapsqlgo
+
+import (
+	"context"
+	"time"
+)
+
+type clockKey struct{}
+
+// Clock supplies the current time to generated code. Implicit parameters whose default is
+// NOW() (e.g. created_at) read it through Now.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts a function to the Clock interface.
+type ClockFunc func() time.Time
+
+// Now returns f().
+func (f ClockFunc) Now() time.Time {
+	return f()
+}
+
+// FixedClock returns a Clock that always reports t. It makes time defaults deterministic in tests.
+func FixedClock(t time.Time) Clock {
+	return ClockFunc(func() time.Time { return t })
+}
+
+// WithClock returns a context whose generated function calls take the current time from clock.
+func WithClock(ctx context.Context, clock Clock) context.Context {
+	return context.WithValue(ctx, clockKey{}, clock)
+}
+
+// Now returns the current time of the Clock registered with WithClock, or time.Now() when
+// none is registered.
+func Now(ctx context.Context) time.Time {
+	if clock, ok := ctx.Value(clockKey{}).(Clock); ok && clock != nil {
+		return clock.Now()
+	}
+
+	return time.Now()
+}
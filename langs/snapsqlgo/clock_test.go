@@ -0,0 +1,24 @@
+package snapsqlgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNowWithClock(t *testing.T) {
+	fixed := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	ctx := WithClock(t.Context(), FixedClock(fixed))
+	assert.Equal(t, fixed, Now(ctx))
+
+	// Implicit parameter defaults take the clock time
+	values := ExtractImplicitParams(ctx, []ImplicitParamSpec{
+		{Name: "created_at", Type: "time.Time", DefaultValue: Now(ctx)},
+	})
+	assert.Equal(t, fixed, values["created_at"])
+
+	before := time.Now()
+	assert.False(t, Now(t.Context()).Before(before))
+}
@@ -0,0 +1,189 @@
+package snapsqlgo
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a cursor passed to a cursor-paginated function cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CursorPage is a page returned by functions generated with `pagination: cursor`.
+// Pass NextCursor as the after argument of the next call to read the following page.
+type CursorPage[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// cursorValue is one tagged key value of an encoded cursor.
+type cursorValue struct {
+	Kind  string `json:"k"`
+	Value string `json:"v,omitempty"`
+}
+
+// EncodeCursor encodes the ORDER BY key values of a row into an opaque cursor string.
+// Integers, floats, strings, booleans, time.Time, []byte and nil are supported, as well as
+// pointers to them and driver.Valuer / fmt.Stringer implementations (decimals are kept as strings).
+func EncodeCursor(values ...any) (string, error) {
+	encoded := make([]cursorValue, len(values))
+
+	for i, value := range values {
+		v, err := encodeCursorValue(value)
+		if err != nil {
+			return "", fmt.Errorf("%w: key %d: %w", ErrInvalidCursor, i, err)
+		}
+
+		encoded[i] = v
+	}
+
+	data, err := json.Marshal(encoded)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes a cursor created by EncodeCursor and checks that it holds n key values.
+func DecodeCursor(cursor string, n int) ([]any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	var encoded []cursorValue
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	if len(encoded) != n {
+		return nil, fmt.Errorf("%w: expected %d keys, got %d", ErrInvalidCursor, n, len(encoded))
+	}
+
+	values := make([]any, n)
+
+	for i, v := range encoded {
+		value, err := decodeCursorValue(v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key %d: %w", ErrInvalidCursor, i, err)
+		}
+
+		values[i] = value
+	}
+
+	return values, nil
+}
+
+// CollectCursorPage reads up to limit rows from rows into a CursorPage. The generated query fetches
+// limit+1 rows, so an extra row means another page exists; NextCursor is then encoded from the key
+// values that key returns for the last row of the page.
+func CollectCursorPage[T any](rows iter.Seq2[*T, error], limit int, key func(*T) []any) (CursorPage[T], error) {
+	page := CursorPage[T]{Items: make([]T, 0, max(limit, 0))}
+
+	for row, err := range rows {
+		if err != nil {
+			return CursorPage[T]{}, err
+		}
+
+		if len(page.Items) >= limit {
+			page.HasMore = true
+			break
+		}
+
+		page.Items = append(page.Items, *row)
+	}
+
+	if page.HasMore && len(page.Items) > 0 {
+		cursor, err := EncodeCursor(key(&page.Items[len(page.Items)-1])...)
+		if err != nil {
+			return CursorPage[T]{}, err
+		}
+
+		page.NextCursor = cursor
+	}
+
+	return page, nil
+}
+
+func encodeCursorValue(value any) (cursorValue, error) {
+	switch v := value.(type) {
+	case nil:
+		return cursorValue{Kind: "n"}, nil
+	case time.Time:
+		return cursorValue{Kind: "t", Value: v.Format(time.RFC3339Nano)}, nil
+	case []byte:
+		return cursorValue{Kind: "x", Value: base64.RawURLEncoding.EncodeToString(v)}, nil
+	case string:
+		return cursorValue{Kind: "s", Value: v}, nil
+	case bool:
+		return cursorValue{Kind: "b", Value: strconv.FormatBool(v)}, nil
+	}
+
+	rv := reflect.ValueOf(value)
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return cursorValue{Kind: "n"}, nil
+		}
+
+		return encodeCursorValue(rv.Elem().Interface())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cursorValue{Kind: "i", Value: strconv.FormatInt(rv.Int(), 10)}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return cursorValue{Kind: "i", Value: strconv.FormatUint(rv.Uint(), 10)}, nil
+	case reflect.Float32, reflect.Float64:
+		return cursorValue{Kind: "f", Value: strconv.FormatFloat(rv.Float(), 'g', -1, 64)}, nil
+	case reflect.String:
+		return cursorValue{Kind: "s", Value: rv.String()}, nil
+	case reflect.Bool:
+		return cursorValue{Kind: "b", Value: strconv.FormatBool(rv.Bool())}, nil
+	}
+
+	switch v := value.(type) {
+	case driver.Valuer:
+		dv, err := v.Value()
+		if err != nil {
+			return cursorValue{}, err
+		}
+
+		if _, ok := dv.(driver.Valuer); ok {
+			return cursorValue{}, fmt.Errorf("unsupported key type %T", value)
+		}
+
+		return encodeCursorValue(dv)
+	case fmt.Stringer:
+		return cursorValue{Kind: "s", Value: v.String()}, nil
+	}
+
+	return cursorValue{}, fmt.Errorf("unsupported key type %T", value)
+}
+
+func decodeCursorValue(v cursorValue) (any, error) {
+	switch v.Kind {
+	case "n":
+		return nil, nil
+	case "i":
+		return strconv.ParseInt(v.Value, 10, 64)
+	case "f":
+		return strconv.ParseFloat(v.Value, 64)
+	case "s":
+		return v.Value, nil
+	case "b":
+		return strconv.ParseBool(v.Value)
+	case "t":
+		return time.Parse(time.RFC3339Nano, v.Value)
+	case "x":
+		return base64.RawURLEncoding.DecodeString(v.Value)
+	}
+
+	return nil, fmt.Errorf("unknown key kind %q", v.Kind)
+}
@@ -0,0 +1,89 @@
+package snapsqlgo_test
+
+import (
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	snapsqlgo "github.com/shibukawa/snapsql/langs/snapsqlgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type cursorRow struct {
+	ID        int
+	CreatedAt time.Time
+	Name      *string
+}
+
+func cursorRows(rows []cursorRow, err error) iter.Seq2[*cursorRow, error] {
+	return func(yield func(*cursorRow, error) bool) {
+		for i := range rows {
+			if !yield(&rows[i], nil) {
+				return
+			}
+		}
+
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+func TestEncodeDecodeCursor(t *testing.T) {
+	createdAt := time.Date(2024, 5, 1, 12, 30, 0, 123456789, time.UTC)
+	name := "alice"
+
+	cursor, err := snapsqlgo.EncodeCursor(createdAt, int32(42), &name, (*string)(nil), 1.5, true, []byte{1, 2})
+	require.NoError(t, err)
+
+	values, err := snapsqlgo.DecodeCursor(cursor, 7)
+	require.NoError(t, err)
+	assert.Equal(t, []any{createdAt, int64(42), "alice", nil, 1.5, true, []byte{1, 2}}, values)
+
+	_, err = snapsqlgo.DecodeCursor(cursor, 2)
+	assert.ErrorIs(t, err, snapsqlgo.ErrInvalidCursor)
+
+	_, err = snapsqlgo.DecodeCursor("not a cursor", 1)
+	assert.ErrorIs(t, err, snapsqlgo.ErrInvalidCursor)
+
+	_, err = snapsqlgo.EncodeCursor(struct{}{})
+	assert.ErrorIs(t, err, snapsqlgo.ErrInvalidCursor)
+}
+
+func TestCollectCursorPage(t *testing.T) {
+	base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	key := func(row *cursorRow) []any { return []any{row.CreatedAt, row.ID} }
+
+	rows := []cursorRow{
+		{ID: 1, CreatedAt: base},
+		{ID: 2, CreatedAt: base.Add(time.Hour)},
+		{ID: 3, CreatedAt: base.Add(2 * time.Hour)},
+	}
+
+	t.Run("more rows", func(t *testing.T) {
+		page, err := snapsqlgo.CollectCursorPage(cursorRows(rows, nil), 2, key)
+		require.NoError(t, err)
+		assert.Len(t, page.Items, 2)
+		assert.True(t, page.HasMore)
+
+		values, err := snapsqlgo.DecodeCursor(page.NextCursor, 2)
+		require.NoError(t, err)
+		assert.Equal(t, []any{base.Add(time.Hour), int64(2)}, values)
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		page, err := snapsqlgo.CollectCursorPage(cursorRows(rows, nil), 3, key)
+		require.NoError(t, err)
+		assert.Len(t, page.Items, 3)
+		assert.False(t, page.HasMore)
+		assert.Empty(t, page.NextCursor)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		boom := errors.New("boom")
+		_, err := snapsqlgo.CollectCursorPage(cursorRows(rows, boom), 5, key)
+		assert.ErrorIs(t, err, boom)
+	})
+}
@@ -0,0 +1,22 @@
+package snapsqlgo
+
+import (
+	"context"
+	"strings"
+)
+
+// WithDryRun makes generated functions build the SQL and arguments, pass them to the query
+// logger, and return zero values without sending anything to the executor. Mocks registered
+// with WithMockData still take precedence. Register it with WithConfig (e.g. "delete:*") to
+// rehearse a group of functions, for example in a canary deploy.
+func WithDryRun() FuncOpt {
+	return func(config *FuncConfig) {
+		config.DryRun = true
+	}
+}
+
+// IsDryRun reports whether funcName runs in dry-run mode by WithDryRun, either passed per
+// call or registered with WithConfig.
+func IsDryRun(ctx context.Context, funcName, statementType string, opts ...FuncOpt) bool {
+	return resolveFuncConfig(ctx, funcName, strings.ToLower(statementType), opts).DryRun
+}
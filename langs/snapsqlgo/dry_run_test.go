@@ -0,0 +1,83 @@
+package snapsqlgo
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// touchedExecutor records whether any statement was sent to it.
+type touchedExecutor struct {
+	touched bool
+}
+
+func (e *touchedExecutor) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	e.touched = true
+	return nil, sql.ErrConnDone
+}
+
+func (e *touchedExecutor) QueryContext(context.Context, string, ...any) (*sql.Rows, error) {
+	e.touched = true
+	return nil, sql.ErrConnDone
+}
+
+func (e *touchedExecutor) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	e.touched = true
+	return nil, sql.ErrConnDone
+}
+
+func TestIsDryRun(t *testing.T) {
+	ctx := t.Context()
+
+	assert.False(t, IsDryRun(ctx, "DeleteUser", "delete"))
+	assert.True(t, IsDryRun(ctx, "DeleteUser", "delete", WithDryRun()))
+
+	configured := WithConfig(ctx, "delete:*", WithDryRun())
+	assert.True(t, IsDryRun(configured, "DeleteUser", "DELETE"))
+	assert.False(t, IsDryRun(configured, "ListUsers", "select"))
+}
+
+func TestDryRunLogsWithoutExecutor(t *testing.T) {
+	sink := &testSink{}
+
+	var outcomes []QueryOutcome
+
+	ctx := WithLogger(t.Context(), sink.sink(), LoggerOpt{ExplainMode: ExplainModePlan})
+	ctx = WithMetrics(ctx, MetricsCollectorFunc(func(outcome QueryOutcome) {
+		outcomes = append(outcomes, outcome)
+	}))
+	executor := &touchedExecutor{}
+
+	logger := ExtractExecutionContext(ctx).QueryLogger()
+	require.NotNil(t, logger)
+
+	logger.SetQuery("SELECT id FROM users WHERE id = $1", []any{1})
+	logger.Write(ctx, func() (QueryLogMetadata, DBExecutor) {
+		return QueryLogMetadata{
+			FuncName:  "GetUser",
+			QueryType: QueryLogQueryTypeSelect,
+			Options:   QueryOptionsSnapshot{DryRun: true},
+		}, executor
+	})
+
+	require.Len(t, sink.entries, 1)
+	assert.True(t, sink.entries[0].Options.DryRun)
+	assert.Equal(t, "SELECT id FROM users WHERE id = $1", sink.entries[0].SQL)
+	assert.Nil(t, sink.entries[0].Explain)
+	assert.False(t, executor.touched, "EXPLAIN must not run in dry-run mode")
+	assert.Empty(t, outcomes, "dry runs are not counted as executed queries")
+}
+
+func TestDryRunSkipsTransaction(t *testing.T) {
+	executor := &sql.DB{}
+
+	begin, err := ResolveTxPropagation(t.Context(), executor, "DeleteUser", "delete", WithTxPropagation(TxRequired), WithDryRun())
+	require.NoError(t, err)
+	assert.False(t, begin)
+
+	_, err = ResolveTxPropagation(t.Context(), executor, "DeleteUser", "delete", WithTxRequired(), WithDryRun())
+	assert.ErrorIs(t, err, ErrTxRequired)
+}
@@ -0,0 +1,7 @@
+package snapsqlgo
+
+import "errors"
+
+// ErrInvalidEnumValue is returned by the generated Parse functions of database enums when a
+// string is not one of the values of the enum.
+var ErrInvalidEnumValue = errors.New("snapsqlgo: invalid enum value")
@@ -22,6 +22,10 @@ const (
 	RowLockForUpdateNoWait
 	// RowLockForUpdateSkipLocked emits "FOR UPDATE SKIP LOCKED".
 	RowLockForUpdateSkipLocked
+	// RowLockForShareNoWait emits "FOR SHARE NOWAIT" (where supported).
+	RowLockForShareNoWait
+	// RowLockForShareSkipLocked emits "FOR SHARE SKIP LOCKED" (where supported).
+	RowLockForShareSkipLocked
 )
 
 type rowLockConfig struct {
@@ -33,6 +37,10 @@ type ExecutionContext struct {
 	logger  *loggingConfig
 	rowLock *rowLockConfig
 	mocks   *mockRegistry
+	tracing *tracingConfig
+	metrics *metricsConfig
+
+	stmtCache *StmtCache
 }
 
 // RowLockMode reports the configured pessimistic lock mode, defaulting to RowLockNone.
@@ -0,0 +1,141 @@
+package snapsqlgo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrReadOnly is returned when a function that modifies data runs under a read-only execution policy.
+	ErrReadOnly = errors.New("snapsqlgo: data modification under read-only policy")
+	// ErrTooManyRows is returned when a query returns more rows than the max_rows execution policy allows.
+	ErrTooManyRows = errors.New("snapsqlgo: too many rows")
+)
+
+// ExecutionPolicy bounds the execution of a generated function. Functions that have a policy in
+// snapsql.yaml (execution.functions) pass it to ResolveExecutionPolicy; WithTimeout, WithMaxRows and
+// WithReadOnly override it per call or through WithConfig. Functions without a policy ignore them.
+type ExecutionPolicy struct {
+	// Timeout bounds each execution attempt with a context deadline (0: no timeout)
+	Timeout time.Duration
+	// MaxRows is the number of rows a query may return before it fails with ErrTooManyRows (0: no limit)
+	MaxRows int
+	// ReadOnly makes functions that modify data fail with ErrReadOnly
+	ReadOnly bool
+}
+
+// WithTimeout overrides the timeout of the execution policy. 0 removes the timeout.
+func WithTimeout(timeout time.Duration) FuncOpt {
+	return func(config *FuncConfig) {
+		config.Timeout = &timeout
+	}
+}
+
+// WithMaxRows overrides the maximum number of rows of the execution policy. 0 removes the limit.
+func WithMaxRows(maxRows int) FuncOpt {
+	return func(config *FuncConfig) {
+		config.MaxRows = &maxRows
+	}
+}
+
+// WithReadOnly overrides the read-only flag of the execution policy. Register it with WithConfig
+// (e.g. WithConfig(ctx, "*", WithReadOnly(true))) to make the mutations of a request fail.
+func WithReadOnly(readOnly bool) FuncOpt {
+	return func(config *FuncConfig) {
+		config.ReadOnly = &readOnly
+	}
+}
+
+// ResolveExecutionPolicy is called by generated functions. It applies the options passed per call
+// or registered with WithConfig on top of policy, the policy declared in snapsql.yaml.
+func ResolveExecutionPolicy(ctx context.Context, funcName, statementType string, policy ExecutionPolicy, opts ...FuncOpt) ExecutionPolicy {
+	config := resolveFuncConfig(ctx, funcName, strings.ToLower(statementType), opts)
+
+	if config.Timeout != nil {
+		policy.Timeout = *config.Timeout
+	}
+
+	if config.MaxRows != nil {
+		policy.MaxRows = *config.MaxRows
+	}
+
+	if config.ReadOnly != nil {
+		policy.ReadOnly = *config.ReadOnly
+	}
+
+	return policy
+}
+
+// EnforceReadOnly is called by generated INSERT/UPDATE/DELETE functions before anything is sent
+// to the database. It returns ErrReadOnly when the policy is read-only.
+func EnforceReadOnly(funcName string, policy ExecutionPolicy) error {
+	if policy.ReadOnly {
+		return fmt.Errorf("%w: %s modifies data", ErrReadOnly, funcName)
+	}
+
+	return nil
+}
+
+// CheckRows is called by generated scan loops with the number of rows read so far. It returns
+// ErrTooManyRows once MaxRows is exceeded.
+func (p ExecutionPolicy) CheckRows(rows int) error {
+	if p.MaxRows > 0 && rows > p.MaxRows {
+		return fmt.Errorf("%w: more than %d rows", ErrTooManyRows, p.MaxRows)
+	}
+
+	return nil
+}
+
+// ApplyExecutionPolicy is called by generated functions right before the statement is sent. It bounds
+// ctx by the timeout of the policy. On PostgreSQL and CockroachDB inside a transaction it also sets
+// statement_timeout with SET LOCAL, so that the server cancels the statement by itself. The returned
+// cancel function must always be called after the statement (and its rows) is done; it sets
+// statement_timeout back to the previous value, so later statements of the caller's transaction
+// keep their own timeout.
+func ApplyExecutionPolicy(ctx context.Context, executor DBExecutor, dialect string, policy ExecutionPolicy) (context.Context, context.CancelFunc, error) {
+	if policy.Timeout <= 0 {
+		return ctx, func() {}, nil
+	}
+
+	tx, inTx := executor.(*sql.Tx)
+	if !inTx {
+		ctx, cancel := context.WithTimeout(ctx, policy.Timeout)
+		return ctx, cancel, nil
+	}
+
+	switch strings.ToLower(dialect) {
+	case "postgres", "cockroach":
+	default:
+		ctx, cancel := context.WithTimeout(ctx, policy.Timeout)
+		return ctx, cancel, nil
+	}
+
+	// SHOW and SET run before the timeout starts, so that a short timeout bounds only the statement
+	var previous string
+	if err := tx.QueryRowContext(ctx, "SHOW statement_timeout").Scan(&previous); err != nil {
+		return ctx, func() {}, fmt.Errorf("failed to read statement_timeout: %w", err)
+	}
+
+	// statement_timeout is in milliseconds and 0 disables it, so a sub-millisecond timeout is rounded up
+	timeoutMillis := (policy.Timeout + time.Millisecond - 1) / time.Millisecond
+	stmt := fmt.Sprintf("SET LOCAL statement_timeout = %d", timeoutMillis)
+	if _, err := tx.ExecContext(ctx, stmt); err != nil {
+		return ctx, func() {}, fmt.Errorf("failed to set statement_timeout: %w", err)
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, policy.Timeout)
+
+	restore := "SET LOCAL statement_timeout = '" + strings.ReplaceAll(previous, "'", "''") + "'"
+
+	return ctx, func() {
+		cancel()
+		// The deadline of ctx may have passed already. The restore fails only when the statement
+		// aborted the transaction, and then the rollback discards the setting anyway.
+		_, _ = tx.ExecContext(context.WithoutCancel(parent), restore)
+	}, nil
+}
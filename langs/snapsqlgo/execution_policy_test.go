@@ -0,0 +1,167 @@
+package snapsqlgo
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveExecutionPolicy(t *testing.T) {
+	declared := ExecutionPolicy{Timeout: 2 * time.Second, MaxRows: 100}
+	ctx := t.Context()
+
+	assert.Equal(t, declared, ResolveExecutionPolicy(ctx, "ListUsers", "select", declared))
+
+	policy := ResolveExecutionPolicy(ctx, "ListUsers", "select", declared, WithTimeout(0), WithMaxRows(10))
+	assert.Equal(t, ExecutionPolicy{MaxRows: 10}, policy)
+
+	configured := WithConfig(ctx, "*", WithReadOnly(true))
+	policy = ResolveExecutionPolicy(configured, "DeleteUser", "DELETE", ExecutionPolicy{})
+	assert.True(t, policy.ReadOnly)
+	require.ErrorIs(t, EnforceReadOnly("DeleteUser", policy), ErrReadOnly)
+
+	policy = ResolveExecutionPolicy(configured, "DeleteUser", "delete", ExecutionPolicy{}, WithReadOnly(false))
+	assert.NoError(t, EnforceReadOnly("DeleteUser", policy))
+}
+
+func TestExecutionPolicyCheckRows(t *testing.T) {
+	assert.NoError(t, ExecutionPolicy{}.CheckRows(1000))
+	assert.NoError(t, ExecutionPolicy{MaxRows: 2}.CheckRows(2))
+	assert.ErrorIs(t, ExecutionPolicy{MaxRows: 2}.CheckRows(3), ErrTooManyRows)
+}
+
+func TestApplyExecutionPolicy(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	ctx, cancel, err := ApplyExecutionPolicy(t.Context(), db, "postgres", ExecutionPolicy{})
+	require.NoError(t, err)
+
+	cancel()
+
+	_, hasDeadline := ctx.Deadline()
+	assert.False(t, hasDeadline)
+
+	// Outside a transaction only the context deadline is applied
+	ctx, cancel, err = ApplyExecutionPolicy(t.Context(), db, "postgres", ExecutionPolicy{Timeout: time.Second})
+	require.NoError(t, err)
+
+	_, hasDeadline = ctx.Deadline()
+	assert.True(t, hasDeadline)
+
+	cancel()
+	assert.ErrorIs(t, ctx.Err(), context.Canceled)
+
+	tx, err := db.BeginTx(t.Context(), nil)
+	require.NoError(t, err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	_, cancel, err = ApplyExecutionPolicy(t.Context(), tx, "sqlite", ExecutionPolicy{Timeout: time.Second})
+	require.NoError(t, err)
+
+	cancel()
+
+	// SQLite does not know SHOW, which shows that statement_timeout is handled for PostgreSQL
+	_, cancel, err = ApplyExecutionPolicy(t.Context(), tx, "postgres", ExecutionPolicy{Timeout: time.Second})
+	require.ErrorContains(t, err, "failed to read statement_timeout")
+
+	cancel()
+}
+
+func TestApplyExecutionPolicyRestoresStatementTimeout(t *testing.T) {
+	recorder := &statementRecorder{}
+	db := sql.OpenDB(recorder)
+
+	defer db.Close()
+
+	tx, err := db.BeginTx(t.Context(), nil)
+	require.NoError(t, err)
+
+	defer func() { _ = tx.Rollback() }()
+
+	_, cancel, err := ApplyExecutionPolicy(t.Context(), tx, "postgres", ExecutionPolicy{Timeout: 1500 * time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SHOW statement_timeout", "SET LOCAL statement_timeout = 1500"}, recorder.statements)
+
+	cancel()
+	assert.Equal(t, "SET LOCAL statement_timeout = '5s'", recorder.statements[len(recorder.statements)-1])
+}
+
+func TestApplyExecutionPolicyRoundsUpStatementTimeout(t *testing.T) {
+	for _, tt := range []struct {
+		timeout time.Duration
+		want    string
+	}{
+		{500 * time.Microsecond, "SET LOCAL statement_timeout = 1"},
+		{time.Nanosecond, "SET LOCAL statement_timeout = 1"},
+		{time.Millisecond, "SET LOCAL statement_timeout = 1"},
+		{1500 * time.Microsecond, "SET LOCAL statement_timeout = 2"},
+	} {
+		recorder := &statementRecorder{}
+		db := sql.OpenDB(recorder)
+
+		tx, err := db.BeginTx(t.Context(), nil)
+		require.NoError(t, err)
+
+		_, cancel, err := ApplyExecutionPolicy(t.Context(), tx, "postgres", ExecutionPolicy{Timeout: tt.timeout})
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, recorder.statements[1], "timeout %s", tt.timeout)
+
+		cancel()
+		_ = tx.Rollback()
+		db.Close()
+	}
+}
+
+// statementRecorder is a database/sql driver that records statements and answers SHOW with 5s
+type statementRecorder struct {
+	statements []string
+}
+
+func (r *statementRecorder) Connect(context.Context) (driver.Conn, error) { return r, nil }
+func (r *statementRecorder) Driver() driver.Driver                        { return nil }
+func (r *statementRecorder) Prepare(string) (driver.Stmt, error)          { return nil, driver.ErrSkip }
+func (r *statementRecorder) Close() error                                 { return nil }
+func (r *statementRecorder) Begin() (driver.Tx, error)                    { return r, nil }
+func (r *statementRecorder) Commit() error                                { return nil }
+func (r *statementRecorder) Rollback() error                              { return nil }
+
+func (r *statementRecorder) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	r.statements = append(r.statements, query)
+	return driver.RowsAffected(0), nil
+}
+
+func (r *statementRecorder) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	r.statements = append(r.statements, query)
+	return &showRows{value: "5s"}, nil
+}
+
+// showRows returns a single row with a single column
+type showRows struct {
+	value string
+	done  bool
+}
+
+func (r *showRows) Columns() []string { return []string{"statement_timeout"} }
+func (r *showRows) Close() error      { return nil }
+
+func (r *showRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+
+	r.done = true
+	dest[0] = r.value
+
+	return nil
+}
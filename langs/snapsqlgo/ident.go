@@ -0,0 +1,36 @@
+package snapsqlgo
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrIdentifierNotAllowed is returned when the value of an ident directive is not in its allowlist.
+var ErrIdentifierNotAllowed = errors.New("snapsqlgo: identifier not allowed")
+
+// AllowedIdentifier is called by generated code for /*# ident expr in [a, b] */ directives.
+// Identifiers cannot be bound as placeholders, so the value is written into the SQL text
+// only when it exactly matches one of the allowed identifiers.
+func AllowedIdentifier(value any, allowed ...string) (string, error) {
+	var name string
+
+	switch v := value.(type) {
+	case string:
+		name = v
+	case *string:
+		if v == nil {
+			return "", fmt.Errorf("%w: nil (allowed: %v)", ErrIdentifierNotAllowed, allowed)
+		}
+
+		name = *v
+	default:
+		return "", fmt.Errorf("%w: %v of type %T (allowed: %v)", ErrIdentifierNotAllowed, value, value, allowed)
+	}
+
+	if !slices.Contains(allowed, name) {
+		return "", fmt.Errorf("%w: %q (allowed: %v)", ErrIdentifierNotAllowed, name, allowed)
+	}
+
+	return name, nil
+}
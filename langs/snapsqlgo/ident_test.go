@@ -0,0 +1,31 @@
+package snapsqlgo_test
+
+import (
+	"testing"
+
+	snapsqlgo "github.com/shibukawa/snapsql/langs/snapsqlgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllowedIdentifier(t *testing.T) {
+	name, err := snapsqlgo.AllowedIdentifier("created_at", "name", "created_at")
+	assert.NoError(t, err)
+	assert.Equal(t, "created_at", name)
+
+	column := "u.name"
+	name, err = snapsqlgo.AllowedIdentifier(&column, "u.name")
+	assert.NoError(t, err)
+	assert.Equal(t, "u.name", name)
+
+	_, err = snapsqlgo.AllowedIdentifier("name; DROP TABLE users", "name")
+	assert.ErrorIs(t, err, snapsqlgo.ErrIdentifierNotAllowed)
+
+	_, err = snapsqlgo.AllowedIdentifier("NAME", "name")
+	assert.ErrorIs(t, err, snapsqlgo.ErrIdentifierNotAllowed)
+
+	_, err = snapsqlgo.AllowedIdentifier((*string)(nil), "name")
+	assert.ErrorIs(t, err, snapsqlgo.ErrIdentifierNotAllowed)
+
+	_, err = snapsqlgo.AllowedIdentifier(1, "name")
+	assert.ErrorIs(t, err, snapsqlgo.ErrIdentifierNotAllowed)
+}
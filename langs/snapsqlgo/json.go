@@ -0,0 +1,62 @@
+package snapsqlgo
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidJSONValue is returned when a database value can't be scanned into JSON.
+var ErrInvalidJSONValue = errors.New("invalid JSON value")
+
+// JSON holds a raw JSON document returned by JSON/JSONB columns and JSON functions.
+// PostgreSQL and MySQL return the document as bytes and SQLite returns it as text, so JSON
+// accepts both. Decode it with json.Unmarshal to use the content.
+type JSON json.RawMessage
+
+var (
+	_ json.Marshaler   = JSON(nil)
+	_ json.Unmarshaler = (*JSON)(nil)
+	_ driver.Valuer    = JSON(nil)
+)
+
+// Scan implements sql.Scanner.
+func (j *JSON) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*j = nil
+	case []byte:
+		*j = append((*j)[:0], v...)
+	case string:
+		*j = JSON(v)
+	default:
+		return fmt.Errorf("%w: can't scan %T into snapsqlgo.JSON", ErrInvalidJSONValue, src)
+	}
+
+	return nil
+}
+
+// Value implements driver.Valuer. A nil JSON is stored as NULL.
+func (j JSON) Value() (driver.Value, error) {
+	if j == nil {
+		return nil, nil
+	}
+
+	return []byte(j), nil
+}
+
+// MarshalJSON embeds the document as is.
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if j == nil {
+		return []byte("null"), nil
+	}
+
+	return j, nil
+}
+
+// UnmarshalJSON stores a copy of the document.
+func (j *JSON) UnmarshalJSON(data []byte) error {
+	*j = append((*j)[:0], data...)
+	return nil
+}
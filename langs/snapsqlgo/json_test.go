@@ -0,0 +1,61 @@
+package snapsqlgo
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestJSONScan(t *testing.T) {
+	var fromBytes JSON
+	if err := fromBytes.Scan([]byte(`{"a":1}`)); err != nil {
+		t.Fatalf("scan bytes: %v", err)
+	}
+
+	if string(fromBytes) != `{"a":1}` {
+		t.Fatalf("unexpected value: %s", fromBytes)
+	}
+
+	var fromString JSON
+	if err := fromString.Scan(`[1,2]`); err != nil {
+		t.Fatalf("scan string: %v", err)
+	}
+
+	if string(fromString) != `[1,2]` {
+		t.Fatalf("unexpected value: %s", fromString)
+	}
+
+	if err := fromString.Scan(nil); err != nil || fromString != nil {
+		t.Fatalf("scan nil: %v, %v", err, fromString)
+	}
+
+	if err := fromString.Scan(1); !errors.Is(err, ErrInvalidJSONValue) {
+		t.Fatalf("expected ErrInvalidJSONValue, got %v", err)
+	}
+}
+
+func TestJSONMarshal(t *testing.T) {
+	type row struct {
+		Data JSON  `json:"data"`
+		Null JSON  `json:"null"`
+		Ptr  *JSON `json:"ptr"`
+	}
+
+	out, err := json.Marshal(row{Data: JSON(`{"a":[1,2]}`)})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	if string(out) != `{"data":{"a":[1,2]},"null":null,"ptr":null}` {
+		t.Fatalf("unexpected json: %s", out)
+	}
+
+	var decoded row
+	if err := json.Unmarshal(out, &decoded); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if string(decoded.Data) != `{"a":[1,2]}` {
+		t.Fatalf("unexpected data: %s", decoded.Data)
+	}
+}
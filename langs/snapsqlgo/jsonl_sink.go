@@ -0,0 +1,221 @@
+package snapsqlgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrJSONLSinkClosed is returned when writing to a closed JSONLSink.
+var ErrJSONLSinkClosed = errors.New("jsonl sink is closed")
+
+// JSONLSinkOpts configures NewJSONLSink.
+type JSONLSinkOpts struct {
+	// Path is the log file. Rotated files are named Path.1 (newest) to Path.N.
+	Path string
+	// MaxSizeBytes rotates the file before a write would make it larger than this (0 disables rotation).
+	MaxSizeBytes int64
+	// MaxBackups is the number of rotated files kept (default 3).
+	MaxBackups int
+	// SampleRate is the fraction of successful queries written, between 0 and 1. Zero or values
+	// of 1 and above write every query. Failed, canceled and timed out queries are always written.
+	SampleRate float64
+	// OnError receives write failures; they are dropped when nil so logging never fails a query.
+	OnError func(error)
+}
+
+// JSONLSink writes QueryLogEntry values as JSON lines. Register it with
+// WithLogger(ctx, sink.Logger()). Argument values are written as redacted by the
+// generated QueryLogMetadata (see RedactedArgs).
+type JSONLSink struct {
+	opts JSONLSinkOpts
+
+	mu     sync.Mutex
+	out    io.Writer
+	file   *os.File
+	size   int64
+	closed bool
+	sample func() float64
+}
+
+// jsonlRecord is the serialized form of one query log line.
+type jsonlRecord struct {
+	Time             time.Time      `json:"time"`
+	Func             string         `json:"func"`
+	Source           string         `json:"source,omitempty"`
+	SQL              string         `json:"sql"`
+	Args             []any          `json:"args,omitempty"`
+	DurationMS       float64        `json:"duration_ms"`
+	RowLock          string         `json:"row_lock,omitempty"`
+	DryRun           bool           `json:"dry_run,omitempty"`
+	Error            string         `json:"error,omitempty"`
+	Canceled         bool           `json:"canceled,omitempty"`
+	DeadlineExceeded bool           `json:"deadline_exceeded,omitempty"`
+	Explain          *ExplainResult `json:"explain,omitempty"`
+}
+
+// NewJSONLSink opens (or appends to) opts.Path.
+func NewJSONLSink(opts JSONLSinkOpts) (*JSONLSink, error) {
+	if opts.MaxBackups <= 0 {
+		opts.MaxBackups = 3
+	}
+
+	sink := &JSONLSink{opts: opts, sample: rand.Float64}
+	if err := sink.open(); err != nil {
+		return nil, err
+	}
+
+	return sink, nil
+}
+
+// NewJSONLWriterSink writes JSON lines to w. Rotation options are ignored.
+func NewJSONLWriterSink(w io.Writer, opts JSONLSinkOpts) *JSONLSink {
+	return &JSONLSink{opts: opts, out: w, sample: rand.Float64}
+}
+
+// Logger returns the LoggerFunc to pass to WithLogger.
+func (s *JSONLSink) Logger() LoggerFunc {
+	return func(_ context.Context, entry QueryLogEntry) {
+		if err := s.Write(entry); err != nil && s.opts.OnError != nil {
+			s.opts.OnError(err)
+		}
+	}
+}
+
+// Write appends entry as one JSON line, subject to sampling.
+func (s *JSONLSink) Write(entry QueryLogEntry) error {
+	if !s.sampled(entry) {
+		return nil
+	}
+
+	line, err := json.Marshal(jsonlRecord{
+		Time:             entry.StartAt,
+		Func:             entry.FuncName,
+		Source:           entry.SourceFile,
+		SQL:              entry.SQL,
+		Args:             entry.Args,
+		DurationMS:       float64(entry.Duration.Microseconds()) / 1000,
+		RowLock:          entry.Options.RowLockClause,
+		DryRun:           entry.Options.DryRun,
+		Error:            entry.Error,
+		Canceled:         entry.Canceled,
+		DeadlineExceeded: entry.DeadlineExceeded,
+		Explain:          entry.Explain,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode query log entry: %w", err)
+	}
+
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrJSONLSinkClosed
+	}
+
+	// A failed rotation is reported after the entry is written to the current file
+	var rotateErr error
+	if s.file != nil && s.opts.MaxSizeBytes > 0 && s.size > 0 && s.size+int64(len(line)) > s.opts.MaxSizeBytes {
+		rotateErr = s.rotate()
+	}
+
+	n, err := s.out.Write(line)
+	s.size += int64(n)
+
+	return errors.Join(rotateErr, err)
+}
+
+// Close closes the log file. Closing a writer sink only stops further writes.
+func (s *JSONLSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+
+	if s.file != nil {
+		return s.file.Close()
+	}
+
+	return nil
+}
+
+func (s *JSONLSink) sampled(entry QueryLogEntry) bool {
+	rate := s.opts.SampleRate
+	if rate <= 0 || rate >= 1 || entry.Error != "" || entry.Canceled || entry.DeadlineExceeded {
+		return true
+	}
+
+	return s.sample() < rate
+}
+
+func (s *JSONLSink) open() error {
+	file, size, err := openJSONLFile(s.opts.Path)
+	if err != nil {
+		return err
+	}
+
+	s.file = file
+	s.out = file
+	s.size = size
+
+	return nil
+}
+
+func openJSONLFile(path string) (*os.File, int64, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open query log %s: %w", path, err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, fmt.Errorf("failed to stat query log %s: %w", path, err)
+	}
+
+	return file, info.Size(), nil
+}
+
+// rotate shifts Path.N-1 -> Path.N, ..., Path -> Path.1 and reopens Path. The current handle is
+// kept until the new file is open, so a failed rotation leaves the sink writing to the old file
+// and the rotation is retried on the next write.
+func (s *JSONLSink) rotate() error {
+	for i := s.opts.MaxBackups - 1; i >= 1; i-- {
+		from := fmt.Sprintf("%s.%d", s.opts.Path, i)
+		if err := os.Rename(from, fmt.Sprintf("%s.%d", s.opts.Path, i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to rotate query log %s: %w", from, err)
+		}
+	}
+
+	if err := os.Rename(s.opts.Path, s.opts.Path+".1"); err != nil {
+		return fmt.Errorf("failed to rotate query log %s: %w", s.opts.Path, err)
+	}
+
+	file, size, err := openJSONLFile(s.opts.Path)
+	if err != nil {
+		return err
+	}
+
+	old := s.file
+	s.file = file
+	s.out = file
+	s.size = size
+
+	if err := old.Close(); err != nil {
+		return fmt.Errorf("failed to close rotated query log %s: %w", s.opts.Path+".1", err)
+	}
+
+	return nil
+}
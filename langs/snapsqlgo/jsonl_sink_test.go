@@ -0,0 +1,106 @@
+package snapsqlgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONLSinkWritesRedactedEntries(t *testing.T) {
+	var buf bytes.Buffer
+
+	sink := NewJSONLWriterSink(&buf, JSONLSinkOpts{})
+	ctx := WithLogger(context.Background(), sink.Logger())
+
+	logger := ExtractExecutionContext(ctx).QueryLogger()
+	logger.SetQuery("UPDATE users SET password = $1 WHERE email = $2 AND id = $3", []any{"s3cret", "a@example.com", 7})
+	logger.Write(ctx, func() (QueryLogMetadata, DBExecutor) {
+		return QueryLogMetadata{FuncName: "UpdatePassword", SourceFile: "users/UpdatePassword", QueryType: QueryLogQueryTypeExec, RedactedArgs: []int{0, 1}}, nil
+	})
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "UpdatePassword", record["func"])
+	assert.Equal(t, "users/UpdatePassword", record["source"])
+	assert.Equal(t, []any{RedactedValue, RedactedValue, float64(7)}, record["args"])
+	assert.NotContains(t, buf.String(), "s3cret")
+	assert.True(t, strings.HasSuffix(buf.String(), "}\n"))
+}
+
+func TestJSONLSinkSampling(t *testing.T) {
+	var buf bytes.Buffer
+
+	sink := NewJSONLWriterSink(&buf, JSONLSinkOpts{SampleRate: 0.5})
+	sink.sample = func() float64 { return 0.9 }
+
+	require.NoError(t, sink.Write(QueryLogEntry{FuncName: "Skipped"}))
+	require.NoError(t, sink.Write(QueryLogEntry{FuncName: "Failed", Error: "boom"}))
+	require.NoError(t, sink.Write(QueryLogEntry{FuncName: "TimedOut", DeadlineExceeded: true}))
+
+	sink.sample = func() float64 { return 0.1 }
+	require.NoError(t, sink.Write(QueryLogEntry{FuncName: "Sampled"}))
+
+	output := buf.String()
+	assert.NotContains(t, output, "Skipped")
+	assert.Contains(t, output, `"func":"Failed"`)
+	assert.Contains(t, output, `"deadline_exceeded":true`)
+	assert.Contains(t, output, `"func":"Sampled"`)
+}
+
+func TestJSONLSinkRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "query.jsonl")
+
+	sink, err := NewJSONLSink(JSONLSinkOpts{Path: path, MaxSizeBytes: 150, MaxBackups: 2})
+	require.NoError(t, err)
+
+	for i := range 6 {
+		require.NoError(t, sink.Write(QueryLogEntry{FuncName: "ListUsers", SQL: "SELECT id FROM users", Duration: time.Duration(i) * time.Millisecond}))
+	}
+
+	require.NoError(t, sink.Close())
+	assert.ErrorIs(t, sink.Write(QueryLogEntry{}), ErrJSONLSinkClosed)
+
+	for _, name := range []string{path, path + ".1", path + ".2"} {
+		data, err := os.ReadFile(name)
+		require.NoError(t, err, name)
+		assert.LessOrEqual(t, len(data), 150, name)
+		assert.Equal(t, 1, strings.Count(string(data), "\n"), name)
+	}
+
+	_, err = os.Stat(path + ".3")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestJSONLSinkRotationFailureKeepsWriting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "query.jsonl")
+
+	// A non-empty directory at the backup path makes the rename fail
+	require.NoError(t, os.MkdirAll(filepath.Join(path+".1", "keep"), 0o755))
+
+	sink, err := NewJSONLSink(JSONLSinkOpts{Path: path, MaxSizeBytes: 150, MaxBackups: 1})
+	require.NoError(t, err)
+
+	entry := QueryLogEntry{FuncName: "ListUsers", SQL: "SELECT id FROM users"}
+	require.NoError(t, sink.Write(entry))
+	require.Error(t, sink.Write(entry))
+
+	require.NoError(t, os.RemoveAll(path+".1"))
+	require.NoError(t, sink.Write(entry), "rotation is retried on the next write")
+	require.NoError(t, sink.Close())
+
+	rotated, err := os.ReadFile(path + ".1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(rotated), "\n"), "the entry written while rotation failed is kept")
+
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(current), "\n"))
+}
@@ -0,0 +1,86 @@
+package snapsqlgo
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// ErrLimitRequired is returned when limits.strict is enabled in snapsql.yaml and a SELECT
+// without any LIMIT is executed.
+var ErrLimitRequired = errors.New("snapsqlgo: SELECT without LIMIT")
+
+// WithNoLimitSelect allows a SELECT without LIMIT to run although limits.strict is enabled.
+// Register it with WithConfig (e.g. "select:Export*") for functions that read whole tables on purpose.
+func WithNoLimitSelect() FuncOpt {
+	return func(config *FuncConfig) {
+		config.AllowNoLimitSelect = true
+	}
+}
+
+// EnforceLimit is called by functions generated with limits.strict whose SELECT has no LIMIT.
+// It returns ErrLimitRequired unless WithNoLimitSelect is passed per call or registered with WithConfig.
+func EnforceLimit(ctx context.Context, funcName string, opts ...FuncOpt) error {
+	if resolveFuncConfig(ctx, funcName, "select", opts).AllowNoLimitSelect {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s reads every matching row. Add LIMIT to the template, set limits.max_limit, or use snapsqlgo.WithNoLimitSelect to opt-in when intentional", ErrLimitRequired, funcName)
+}
+
+// ClampLimit caps a LIMIT argument to maxLimit configured in snapsql.yaml. Integer and float
+// values (and pointers or driver.Valuer holding them) above maxLimit, zero, negative, nil and
+// unsupported values are replaced by maxLimit.
+func ClampLimit(limit any, maxLimit int) int64 {
+	value, ok := limitValue(limit)
+	if !ok || value <= 0 || value > int64(maxLimit) {
+		return int64(maxLimit)
+	}
+
+	return value
+}
+
+func limitValue(limit any) (int64, bool) {
+	if valuer, ok := limit.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return 0, false
+		}
+
+		if _, nested := v.(driver.Valuer); nested {
+			return 0, false
+		}
+
+		return limitValue(v)
+	}
+
+	rv := reflect.ValueOf(limit)
+
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return 0, false
+		}
+
+		return limitValue(rv.Elem().Interface())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.Uint() > math.MaxInt64 {
+			return math.MaxInt64, true
+		}
+
+		return int64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		if rv.Float() >= math.MaxInt64 {
+			return math.MaxInt64, true
+		}
+
+		return int64(rv.Float()), true
+	}
+
+	return 0, false
+}
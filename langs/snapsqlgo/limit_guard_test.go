@@ -0,0 +1,33 @@
+package snapsqlgo_test
+
+import (
+	"database/sql"
+	"testing"
+
+	snapsqlgo "github.com/shibukawa/snapsql/langs/snapsqlgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClampLimit(t *testing.T) {
+	size := int32(20)
+
+	assert.Equal(t, int64(20), snapsqlgo.ClampLimit(20, 100))
+	assert.Equal(t, int64(100), snapsqlgo.ClampLimit(int64(5000), 100))
+	assert.Equal(t, int64(100), snapsqlgo.ClampLimit(0, 100))
+	assert.Equal(t, int64(100), snapsqlgo.ClampLimit(-1, 100))
+	assert.Equal(t, int64(20), snapsqlgo.ClampLimit(&size, 100))
+	assert.Equal(t, int64(100), snapsqlgo.ClampLimit((*int32)(nil), 100))
+	assert.Equal(t, int64(100), snapsqlgo.ClampLimit(nil, 100))
+	assert.Equal(t, int64(30), snapsqlgo.ClampLimit(sql.NullInt64{Int64: 30, Valid: true}, 100))
+	assert.Equal(t, int64(100), snapsqlgo.ClampLimit(sql.NullInt64{}, 100))
+	assert.Equal(t, int64(100), snapsqlgo.ClampLimit("20", 100))
+}
+
+func TestEnforceLimit(t *testing.T) {
+	assert.ErrorIs(t, snapsqlgo.EnforceLimit(t.Context(), "ListUsers"), snapsqlgo.ErrLimitRequired)
+	assert.NoError(t, snapsqlgo.EnforceLimit(t.Context(), "ListUsers", snapsqlgo.WithNoLimitSelect()))
+
+	ctx := snapsqlgo.WithConfig(t.Context(), "select:Export*", snapsqlgo.WithNoLimitSelect())
+	assert.NoError(t, snapsqlgo.EnforceLimit(ctx, "ExportUsers"))
+	assert.ErrorIs(t, snapsqlgo.EnforceLimit(ctx, "ListUsers"), snapsqlgo.ErrLimitRequired)
+}
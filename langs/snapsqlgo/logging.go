@@ -49,6 +49,8 @@ const (
 type QueryOptionsSnapshot struct {
 	RowLockClause string
 	RowLockMode   RowLockMode
+	// DryRun reports that the query was built and logged but not sent to the database (WithDryRun).
+	DryRun bool
 }
 
 // QueryLogEntry represents a single query execution event.
@@ -64,6 +66,10 @@ type QueryLogEntry struct {
 	StackTrace []runtime.Frame
 	Explain    *ExplainResult
 	Error      string
+	// Canceled reports that the query was aborted because its context was canceled.
+	Canceled bool
+	// DeadlineExceeded reports that the query was aborted because its context deadline passed.
+	DeadlineExceeded bool
 }
 
 // QueryLogMetadata describes immutable attributes passed to the QueryLogger.
@@ -72,11 +78,18 @@ type QueryLogMetadata struct {
 	SourceFile string
 	QueryType  QueryLogQueryType
 	Options    QueryOptionsSnapshot
+	// RedactedArgs lists the positions of arguments bound from parameters matching the
+	// query_log.redact rules of snapsql.yaml. Their values are replaced with RedactedValue.
+	RedactedArgs []int
 }
 
+// RedactedValue replaces redacted argument values in QueryLogEntry.Args.
+const RedactedValue = "[REDACTED]"
+
 // QueryLogger coordinates per-query logging lifecycle.
 type QueryLogger struct {
-	cfg     *loggingConfig
+	cfg     *loggingConfig // nil when only metrics are configured
+	metrics *metricsConfig
 	startAt time.Time
 	sql     string
 	args    []any
@@ -86,12 +99,22 @@ type QueryLogger struct {
 
 // QueryLogger produces a QueryLogger from the execution context.
 func (ec *ExecutionContext) QueryLogger() *QueryLogger {
-	if ec == nil || ec.logger == nil || ec.logger.logger == nil {
+	if ec == nil {
+		return nil
+	}
+
+	var cfg *loggingConfig
+	if ec.logger != nil && ec.logger.logger != nil {
+		cfg = ec.logger
+	}
+
+	if cfg == nil && ec.metrics == nil {
 		return nil
 	}
 
 	return &QueryLogger{
-		cfg:     ec.logger,
+		cfg:     cfg,
+		metrics: ec.metrics,
 		startAt: time.Now(),
 	}
 }
@@ -155,6 +178,13 @@ func (l *QueryLogger) Write(ctx context.Context, metaProvider func() (QueryLogMe
 	if len(l.args) > 0 {
 		copied := make([]any, len(l.args))
 		copy(copied, l.args)
+
+		for _, idx := range metadata.RedactedArgs {
+			if idx >= 0 && idx < len(copied) && copied[idx] != nil {
+				copied[idx] = RedactedValue
+			}
+		}
+
 		entry.Args = copied
 	}
 
@@ -162,11 +192,21 @@ func (l *QueryLogger) Write(ctx context.Context, metaProvider func() (QueryLogMe
 		entry.Error = l.err.Error()
 	}
 
+	entry.Canceled, entry.DeadlineExceeded = cancellationCause(ctx, l.err)
+
+	if l.metrics != nil && !metadata.Options.DryRun {
+		l.metrics.observe(metadata, entry)
+	}
+
+	if l.cfg == nil {
+		return
+	}
+
 	if l.cfg.includeStack {
 		entry.StackTrace = captureStackTrace(l.cfg.stackDepth)
 	}
 
-	if l.err == nil && metadata.QueryType == QueryLogQueryTypeSelect && l.shouldCaptureExplain(entry.Duration) {
+	if l.err == nil && !entry.Canceled && !entry.DeadlineExceeded && !metadata.Options.DryRun && metadata.QueryType == QueryLogQueryTypeSelect && l.shouldCaptureExplain(entry.Duration) {
 		if executor != nil && entry.SQL != "" {
 			if explain := l.runExplain(ctx, executor, entry.SQL, l.args); explain != nil {
 				entry.Explain = explain
@@ -2,7 +2,10 @@ package snapsqlgo
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 )
 
 type testSink struct {
@@ -99,3 +102,84 @@ func TestExecutionContextQueryLoggerNil(t *testing.T) {
 		t.Fatalf("expected nil logger for nil execution context")
 	}
 }
+
+func TestLoggerRecordsCancellation(t *testing.T) {
+	sink := &testSink{}
+	counters := &QueryCounters{}
+	ctx := WithLogger(context.Background(), sink.sink())
+	ctx = WithMetrics(ctx, counters)
+
+	write := func(ctx context.Context, err error) {
+		logger := ExtractExecutionContext(ctx).QueryLogger()
+		logger.SetQuery("SELECT 1", nil)
+		logger.SetErr(err)
+		logger.Write(ctx, func() (QueryLogMetadata, DBExecutor) {
+			return QueryLogMetadata{FuncName: "TestFunc", QueryType: QueryLogQueryTypeSelect}, nil
+		})
+	}
+
+	write(ctx, nil)
+
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	// Drivers may surface their own error type; the context decides the cause.
+	write(canceledCtx, errors.New("driver: query aborted"))
+
+	deadlineCtx, cancelDeadline := context.WithDeadline(ctx, time.Now().Add(-time.Second))
+	defer cancelDeadline()
+	write(deadlineCtx, fmt.Errorf("TestFunc: failed to execute query: %w", context.DeadlineExceeded))
+
+	// A successful query whose context ended before the log was written (e.g. an iterator
+	// drained after the caller's deadline) is not an aborted query.
+	write(deadlineCtx, nil)
+
+	if len(sink.entries) != 4 {
+		t.Fatalf("expected four log entries, got %d", len(sink.entries))
+	}
+
+	if sink.entries[0].Canceled || sink.entries[0].DeadlineExceeded {
+		t.Errorf("expected completed query not to be marked as aborted: %+v", sink.entries[0])
+	}
+
+	if !sink.entries[1].Canceled || sink.entries[1].DeadlineExceeded {
+		t.Errorf("expected canceled query to be marked canceled: %+v", sink.entries[1])
+	}
+
+	if sink.entries[2].Canceled || !sink.entries[2].DeadlineExceeded {
+		t.Errorf("expected timed out query to be marked deadline exceeded: %+v", sink.entries[2])
+	}
+
+	if sink.entries[3].Canceled || sink.entries[3].DeadlineExceeded {
+		t.Errorf("expected successful query on an ended context not to be marked as aborted: %+v", sink.entries[3])
+	}
+
+	expected := QueryCountersSnapshot{Total: 4, Failed: 2, Canceled: 1, DeadlineExceeded: 1}
+	if got := counters.Snapshot(); got != expected {
+		t.Errorf("unexpected counters: got %+v, want %+v", got, expected)
+	}
+}
+
+func TestMetricsWithoutLogger(t *testing.T) {
+	var outcomes []QueryOutcome
+
+	ctx := WithMetrics(context.Background(), MetricsCollectorFunc(func(outcome QueryOutcome) {
+		outcomes = append(outcomes, outcome)
+	}), MetricsOpt{SlowQueryThreshold: time.Nanosecond})
+
+	logger := ExtractExecutionContext(ctx).QueryLogger()
+	if logger == nil {
+		t.Fatalf("expected logger instance when only metrics are configured")
+	}
+
+	logger.Write(ctx, func() (QueryLogMetadata, DBExecutor) {
+		return QueryLogMetadata{FuncName: "TestFunc", QueryType: QueryLogQueryTypeExec}, nil
+	})
+
+	if len(outcomes) != 1 || outcomes[0].FuncName != "TestFunc" || outcomes[0].Failed || !outcomes[0].Slow {
+		t.Fatalf("unexpected outcomes: %+v", outcomes)
+	}
+
+	if ctx := WithMetrics(ctx, nil); ExtractExecutionContext(ctx).QueryLogger() != nil {
+		t.Fatalf("expected nil logger after clearing logger and metrics")
+	}
+}
@@ -0,0 +1,170 @@
+package snapsqlgo
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// QueryOutcome describes how a single query executed by generated code finished.
+type QueryOutcome struct {
+	FuncName         string
+	QueryType        QueryLogQueryType
+	Duration         time.Duration
+	Failed           bool
+	Canceled         bool
+	DeadlineExceeded bool
+	// Slow reports that Duration reached MetricsOpt.SlowQueryThreshold.
+	Slow bool
+}
+
+// MetricsCollector receives the outcome of every query executed by generated code, for example
+// to export per-function counters and histograms. Register it with WithMetrics.
+// ObserveQuery is called synchronously at the end of every query and must be safe for
+// concurrent use.
+type MetricsCollector interface {
+	ObserveQuery(outcome QueryOutcome)
+}
+
+// MetricsCollectorFunc adapts a function to the MetricsCollector interface.
+type MetricsCollectorFunc func(outcome QueryOutcome)
+
+// ObserveQuery calls f(outcome).
+func (f MetricsCollectorFunc) ObserveQuery(outcome QueryOutcome) {
+	f(outcome)
+}
+
+// MetricsOpt configures optional metrics behaviour passed to WithMetrics.
+type MetricsOpt struct {
+	// SlowQueryThreshold marks queries taking at least this long as slow (0 disables it).
+	SlowQueryThreshold time.Duration
+}
+
+type metricsConfig struct {
+	collector          MetricsCollector
+	slowQueryThreshold time.Duration
+}
+
+// WithMetrics stores the metrics collector on the execution context. Metrics are collected
+// independently of WithLogger. Passing a nil collector disables metrics collection.
+func WithMetrics(ctx context.Context, collector MetricsCollector, opts ...MetricsOpt) context.Context {
+	ctx, ec := withExecutionContext(ctx)
+
+	if collector == nil {
+		ec.metrics = nil
+		return ctx
+	}
+
+	var singleOpt MetricsOpt
+	if len(opts) > 0 {
+		singleOpt = opts[0]
+	}
+
+	ec.metrics = &metricsConfig{
+		collector:          collector,
+		slowQueryThreshold: max(singleOpt.SlowQueryThreshold, 0),
+	}
+
+	return ctx
+}
+
+func (m *metricsConfig) observe(metadata QueryLogMetadata, entry QueryLogEntry) {
+	m.collector.ObserveQuery(QueryOutcome{
+		FuncName:         metadata.FuncName,
+		QueryType:        metadata.QueryType,
+		Duration:         entry.Duration,
+		Failed:           entry.Error != "" || entry.Canceled || entry.DeadlineExceeded,
+		Canceled:         entry.Canceled,
+		DeadlineExceeded: entry.DeadlineExceeded,
+		Slow:             m.slowQueryThreshold > 0 && entry.Duration >= m.slowQueryThreshold,
+	})
+}
+
+// QueryCounters is a ready-to-use MetricsCollector that keeps process-wide totals.
+type QueryCounters struct {
+	total            atomic.Int64
+	failed           atomic.Int64
+	canceled         atomic.Int64
+	deadlineExceeded atomic.Int64
+	slow             atomic.Int64
+	retries          atomic.Int64
+}
+
+// QueryCountersSnapshot is a point-in-time copy of QueryCounters.
+type QueryCountersSnapshot struct {
+	Total            int64
+	Failed           int64
+	Canceled         int64
+	DeadlineExceeded int64
+	Slow             int64
+	Retries          int64
+}
+
+// ObserveQuery implements MetricsCollector.
+func (c *QueryCounters) ObserveQuery(outcome QueryOutcome) {
+	c.total.Add(1)
+
+	if outcome.Failed {
+		c.failed.Add(1)
+	}
+
+	if outcome.Canceled {
+		c.canceled.Add(1)
+	}
+
+	if outcome.DeadlineExceeded {
+		c.deadlineExceeded.Add(1)
+	}
+
+	if outcome.Slow {
+		c.slow.Add(1)
+	}
+}
+
+// ObserveRetry implements RetryObserver.
+func (c *QueryCounters) ObserveRetry(RetryEvent) {
+	c.retries.Add(1)
+}
+
+// Snapshot returns the current counter values.
+func (c *QueryCounters) Snapshot() QueryCountersSnapshot {
+	return QueryCountersSnapshot{
+		Total:            c.total.Load(),
+		Failed:           c.failed.Load(),
+		Canceled:         c.canceled.Load(),
+		DeadlineExceeded: c.deadlineExceeded.Load(),
+		Slow:             c.slow.Load(),
+		Retries:          c.retries.Load(),
+	}
+}
+
+// cancellationCause reports whether a query was aborted by context cancellation or by its
+// deadline. The recorded error is checked first; drivers that wrap the abort in their own
+// error type are covered by inspecting the context itself once the query has finished.
+// A query without an error succeeded, even if its context ended before the log was written.
+func cancellationCause(ctx context.Context, err error) (canceled, deadlineExceeded bool) {
+	if err == nil {
+		return false, false
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return false, true
+	case errors.Is(err, context.Canceled):
+		return true, false
+	}
+
+	if ctx == nil {
+		return false, false
+	}
+
+	switch ctxErr := ctx.Err(); {
+	case errors.Is(ctxErr, context.DeadlineExceeded):
+		return false, true
+	case errors.Is(ctxErr, context.Canceled):
+		return true, false
+	}
+
+	return false, false
+}
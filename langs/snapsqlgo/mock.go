@@ -153,6 +153,50 @@ func MapMockExecutionToSlice[T any](exec *MockExecution) ([]T, error) {
 	return MapMockDataToSlice[T](items)
 }
 
+// MapMockExecutionToExists converts the mock execution for the exists affinity.
+// The single column of the first expected row is evaluated with Truthy; no rows yields false.
+func MapMockExecutionToExists(exec *MockExecution) (bool, error) {
+	value, ok, err := mockScalarValue(exec)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	return Truthy(value), nil
+}
+
+// MapMockExecutionToCount converts the mock execution for the count affinity.
+// The single column of the first expected row is converted to int64; no rows yields 0.
+func MapMockExecutionToCount(exec *MockExecution) (int64, error) {
+	value, ok, err := mockScalarValue(exec)
+	if err != nil || !ok {
+		return 0, err
+	}
+
+	return MapMockDataToStruct[int64](value)
+}
+
+// mockScalarValue returns the only column of the first expected row.
+func mockScalarValue(exec *MockExecution) (any, bool, error) {
+	if exec == nil {
+		return nil, false, fmt.Errorf("%w: mock execution is nil", ErrMock)
+	}
+
+	rows := exec.ExpectedRows()
+	if len(rows) == 0 {
+		return nil, false, nil
+	}
+
+	if len(rows[0]) != 1 {
+		return nil, false, fmt.Errorf("%w: mock case %s must have exactly one column for a scalar result, got %d", ErrMock, exec.Case.Name, len(rows[0]))
+	}
+
+	for _, value := range rows[0] {
+		return value, true, nil
+	}
+
+	return nil, false, nil
+}
+
 type mockScenario struct {
 	caseDef       MockCase
 	opt           MockOpt
@@ -335,6 +379,41 @@ func MatchMock(ctx context.Context, functionName string) (*MockExecution, bool,
 	return ec.mocks.consume(functionName)
 }
 
+// MockRows builds a mock case with a single response returning rows. Rows are converted
+// through their JSON representation, so generated response structs map back unchanged.
+// Generated _mock_test.go helpers use it to install typed mocks.
+func MockRows[T any](name string, rows ...T) (MockCase, error) {
+	expected := make([]map[string]any, 0, len(rows))
+
+	for i, row := range rows {
+		data, err := json.Marshal(row)
+		if err != nil {
+			return MockCase{}, fmt.Errorf("%w: failed to marshal row %d of %s: %w", ErrMock, i, name, err)
+		}
+
+		var mapped map[string]any
+		if err := json.Unmarshal(data, &mapped); err != nil {
+			return MockCase{}, fmt.Errorf("%w: row %d of %s is not an object: %w", ErrMock, i, name, err)
+		}
+
+		expected = append(expected, mapped)
+	}
+
+	return MockCase{
+		Name:      name,
+		Responses: []MockResponse{{Expected: expected}},
+	}, nil
+}
+
+// MockExecResult builds a mock case whose single response reports rowsAffected as the
+// sql.Result of a DML statement without RETURNING.
+func MockExecResult(name string, rowsAffected int64) MockCase {
+	return MockCase{
+		Name:      name,
+		Responses: []MockResponse{{Result: &MockSQLResult{RowsAffected: &rowsAffected}}},
+	}
+}
+
 // MockProvider loads mock cases from various sources.
 type MockProvider interface {
 	Cases(functionName string) ([]MockCase, error)
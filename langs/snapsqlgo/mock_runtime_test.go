@@ -223,6 +223,30 @@ func TestWithMockIteratorSequence(t *testing.T) {
 	require.ErrorIs(t, seqErr, snapsqlgo.ErrMockSequenceDepleted)
 }
 
+func TestMapMockExecutionToScalar(t *testing.T) {
+	exec := func(rows ...map[string]any) *snapsqlgo.MockExecution {
+		return &snapsqlgo.MockExecution{
+			Case:     snapsqlgo.MockCase{Name: "scalar"},
+			Response: &snapsqlgo.MockResponse{Expected: rows},
+		}
+	}
+
+	exists, err := snapsqlgo.MapMockExecutionToExists(exec(map[string]any{"found": 1}))
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	exists, err = snapsqlgo.MapMockExecutionToExists(exec())
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	count, err := snapsqlgo.MapMockExecutionToCount(exec(map[string]any{"total": 42}))
+	require.NoError(t, err)
+	require.Equal(t, int64(42), count)
+
+	_, err = snapsqlgo.MapMockExecutionToCount(exec(map[string]any{"id": 1, "name": "Alpha"}))
+	require.ErrorIs(t, err, snapsqlgo.ErrMock)
+}
+
 func TestFilesystemMockProvider(t *testing.T) {
 	startDir, err := filepath.Abs(filepath.Join("..", "..", "testdata", "appsample"))
 	require.NoError(t, err)
@@ -241,3 +265,36 @@ func TestFilesystemMockProvider(t *testing.T) {
 func ptrInt64(v int64) *int64 {
 	return &v
 }
+
+func TestMockRowsRoundTrip(t *testing.T) {
+	name := "Primary"
+	mockCase, err := snapsqlgo.MockRows("AccountGet", generator.AccountGetResult{ID: 10, Name: &name})
+	require.NoError(t, err)
+
+	ctx, err := snapsqlgo.WithMock(context.Background(), "AccountGet", []snapsqlgo.MockCase{mockCase})
+	require.NoError(t, err)
+
+	result, err := generator.AccountGet(ctx, noopExecutor{TB: t}, 10)
+	require.NoError(t, err)
+	require.Equal(t, 10, result.ID)
+	require.NotNil(t, result.Name)
+	require.Equal(t, "Primary", *result.Name)
+	require.Nil(t, result.Status)
+
+	_, err = snapsqlgo.MockRows("Scalar", 1)
+	require.ErrorIs(t, err, snapsqlgo.ErrMock)
+}
+
+func TestMockExecResult(t *testing.T) {
+	ctx, err := snapsqlgo.WithMock(context.Background(), "UpdateAccountStatusConditional", []snapsqlgo.MockCase{
+		snapsqlgo.MockExecResult("UpdateAccountStatusConditional", 3),
+	})
+	require.NoError(t, err)
+
+	sqlRes, err := generator.UpdateAccountStatusConditional(ctx, noopExecutor{TB: t}, "active", 10, true)
+	require.NoError(t, err)
+
+	rows, err := sqlRes.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(3), rows)
+}
@@ -0,0 +1,103 @@
+package snapsqlgo
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// Option holds a value that may be absent. Generated code uses it for nullable result columns
+// with the `nullable_style: option` generator setting: NULL scans to an empty Option, and an
+// empty Option marshals to JSON null. Options of comparable types can be compared with ==.
+type Option[T any] struct {
+	V     T
+	Valid bool
+}
+
+// Some returns an Option holding v.
+func Some[T any](v T) Option[T] {
+	return Option[T]{V: v, Valid: true}
+}
+
+// None returns an empty Option.
+func None[T any]() Option[T] {
+	return Option[T]{}
+}
+
+// OptionFromPtr returns an Option holding *p, or an empty Option when p is nil.
+func OptionFromPtr[T any](p *T) Option[T] {
+	if p == nil {
+		return Option[T]{}
+	}
+
+	return Some(*p)
+}
+
+// Get returns the value and whether it is present.
+func (o Option[T]) Get() (T, bool) {
+	return o.V, o.Valid
+}
+
+// OrElse returns the value, or fallback when it is absent.
+func (o Option[T]) OrElse(fallback T) T {
+	if !o.Valid {
+		return fallback
+	}
+
+	return o.V
+}
+
+// Ptr returns a pointer to a copy of the value, or nil when it is absent.
+func (o Option[T]) Ptr() *T {
+	if !o.Valid {
+		return nil
+	}
+
+	v := o.V
+
+	return &v
+}
+
+// Scan implements sql.Scanner.
+func (o *Option[T]) Scan(src any) error {
+	var null sql.Null[T]
+	if err := null.Scan(src); err != nil {
+		return err
+	}
+
+	o.V, o.Valid = null.V, null.Valid
+
+	return nil
+}
+
+// Value implements driver.Valuer.
+func (o Option[T]) Value() (driver.Value, error) {
+	return sql.Null[T]{V: o.V, Valid: o.Valid}.Value()
+}
+
+// MarshalJSON writes the value, or null when it is absent.
+func (o Option[T]) MarshalJSON() ([]byte, error) {
+	if !o.Valid {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(o.V)
+}
+
+// UnmarshalJSON reads null as an empty Option and anything else as the value.
+func (o *Option[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*o = Option[T]{}
+		return nil
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*o = Some(v)
+
+	return nil
+}
@@ -0,0 +1,71 @@
+package snapsqlgo
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestOptionScan(t *testing.T) {
+	var name Option[string]
+	if err := name.Scan("snap"); err != nil {
+		t.Fatalf("Scan returned error: %v", err)
+	}
+
+	if name != Some("snap") {
+		t.Fatalf("expected Some(snap), got %+v", name)
+	}
+
+	if err := name.Scan(nil); err != nil {
+		t.Fatalf("Scan(nil) returned error: %v", err)
+	}
+
+	if name != None[string]() {
+		t.Fatalf("expected None after scanning NULL, got %+v", name)
+	}
+
+	var count Option[int64]
+	if err := count.Scan(int64(3)); err != nil || count.OrElse(0) != 3 {
+		t.Fatalf("expected 3, got %+v (%v)", count, err)
+	}
+
+	if v, err := count.Value(); err != nil || v != int64(3) {
+		t.Fatalf("Value() = %v, %v", v, err)
+	}
+
+	if v, err := None[int64]().Value(); err != nil || v != nil {
+		t.Fatalf("Value() of None = %v, %v", v, err)
+	}
+}
+
+func TestOptionJSON(t *testing.T) {
+	type row struct {
+		Name  Option[string] `json:"name"`
+		Email Option[string] `json:"email"`
+	}
+
+	data, err := json.Marshal(row{Name: Some("snap")})
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+
+	if string(data) != `{"name":"snap","email":null}` {
+		t.Fatalf("unexpected JSON: %s", data)
+	}
+
+	var decoded row
+	if err := json.Unmarshal([]byte(`{"name":null,"email":"a@example.com"}`), &decoded); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+
+	if decoded.Name.Valid || decoded.Email != Some("a@example.com") {
+		t.Fatalf("unexpected value: %+v", decoded)
+	}
+
+	if decoded.Name.Ptr() != nil || *decoded.Email.Ptr() != "a@example.com" {
+		t.Fatalf("unexpected Ptr results")
+	}
+
+	if OptionFromPtr[string](nil).Valid || !OptionFromPtr(new(string)).Valid {
+		t.Fatalf("unexpected OptionFromPtr results")
+	}
+}
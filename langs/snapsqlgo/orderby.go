@@ -0,0 +1,57 @@
+package snapsqlgo
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrOrderByNotAllowed is returned when the value of an orderby directive is not in its allowlist.
+var ErrOrderByNotAllowed = errors.New("snapsqlgo: ORDER BY item not allowed")
+
+// NormalizeOrderBy returns the canonical form of an ORDER BY item such as "created_at desc":
+// whitespace is collapsed, the direction is upper-cased and a redundant ASC is dropped.
+func NormalizeOrderBy(item string) string {
+	fields := strings.Fields(item)
+	if len(fields) == 2 {
+		switch strings.ToUpper(fields[1]) {
+		case "ASC":
+			return fields[0]
+		case "DESC":
+			return fields[0] + " DESC"
+		}
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// AllowedOrderBy is called by generated code for /*# orderby expr in [name, created_at desc] */
+// directives. The value is compared with the allowlist in canonical form (see NormalizeOrderBy)
+// and the matching item is written into the SQL text. An empty value selects the first item.
+func AllowedOrderBy(value any, allowed ...string) (string, error) {
+	var item string
+
+	switch v := value.(type) {
+	case nil:
+	case string:
+		item = v
+	case *string:
+		if v != nil {
+			item = *v
+		}
+	default:
+		return "", fmt.Errorf("%w: %v of type %T (allowed: %v)", ErrOrderByNotAllowed, value, value, allowed)
+	}
+
+	item = NormalizeOrderBy(item)
+	if item == "" && len(allowed) > 0 {
+		return allowed[0], nil
+	}
+
+	if !slices.Contains(allowed, item) {
+		return "", fmt.Errorf("%w: %q (allowed: %v)", ErrOrderByNotAllowed, item, allowed)
+	}
+
+	return item, nil
+}
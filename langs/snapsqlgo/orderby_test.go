@@ -0,0 +1,44 @@
+package snapsqlgo_test
+
+import (
+	"testing"
+
+	snapsqlgo "github.com/shibukawa/snapsql/langs/snapsqlgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeOrderBy(t *testing.T) {
+	assert.Equal(t, "created_at DESC", snapsqlgo.NormalizeOrderBy("created_at  desc"))
+	assert.Equal(t, "name", snapsqlgo.NormalizeOrderBy("name ASC"))
+	assert.Equal(t, "u.name", snapsqlgo.NormalizeOrderBy(" u.name "))
+	assert.Equal(t, "", snapsqlgo.NormalizeOrderBy(""))
+}
+
+func TestAllowedOrderBy(t *testing.T) {
+	allowed := []string{"name", "created_at DESC"}
+
+	item, err := snapsqlgo.AllowedOrderBy("created_at desc", allowed...)
+	assert.NoError(t, err)
+	assert.Equal(t, "created_at DESC", item)
+
+	item, err = snapsqlgo.AllowedOrderBy("name asc", allowed...)
+	assert.NoError(t, err)
+	assert.Equal(t, "name", item)
+
+	item, err = snapsqlgo.AllowedOrderBy("", allowed...)
+	assert.NoError(t, err)
+	assert.Equal(t, "name", item)
+
+	item, err = snapsqlgo.AllowedOrderBy((*string)(nil), allowed...)
+	assert.NoError(t, err)
+	assert.Equal(t, "name", item)
+
+	_, err = snapsqlgo.AllowedOrderBy("created_at", allowed...)
+	assert.ErrorIs(t, err, snapsqlgo.ErrOrderByNotAllowed)
+
+	_, err = snapsqlgo.AllowedOrderBy("name; DROP TABLE users", allowed...)
+	assert.ErrorIs(t, err, snapsqlgo.ErrOrderByNotAllowed)
+
+	_, err = snapsqlgo.AllowedOrderBy(1, allowed...)
+	assert.ErrorIs(t, err, snapsqlgo.ErrOrderByNotAllowed)
+}
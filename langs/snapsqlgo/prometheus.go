@@ -0,0 +1,234 @@
+package snapsqlgo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultPrometheusBuckets are the query duration histogram buckets in seconds.
+var DefaultPrometheusBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// PrometheusOpts configures NewPrometheusCollector.
+type PrometheusOpts struct {
+	// Namespace prefixes every metric name (default "snapsql").
+	Namespace string
+	// Buckets are the upper bounds of the duration histogram in seconds (default DefaultPrometheusBuckets).
+	Buckets []float64
+}
+
+// PrometheusCollector is a MetricsCollector that aggregates query metrics per function and
+// serves them in the Prometheus text exposition format:
+//
+//   - <namespace>_query_duration_seconds: histogram labelled by func and query_type
+//   - <namespace>_query_errors_total: counter labelled by func and reason (error, canceled, deadline_exceeded)
+//   - <namespace>_slow_queries_total: counter labelled by func (see MetricsOpt.SlowQueryThreshold)
+//   - <namespace>_query_retries_total: counter labelled by func (see WithRetry)
+//
+// Mount it as an http.Handler (e.g. on /metrics) or call WriteTo from an existing endpoint.
+type PrometheusCollector struct {
+	namespace string
+	buckets   []float64
+
+	mu        sync.Mutex
+	durations map[durationKey]*durationHistogram
+	errors    map[errorKey]uint64
+	slow      map[string]uint64
+	retries   map[string]uint64
+}
+
+type durationKey struct {
+	funcName  string
+	queryType QueryLogQueryType
+}
+
+type errorKey struct {
+	funcName string
+	reason   string
+}
+
+type durationHistogram struct {
+	counts []uint64 // cumulative count per bucket
+	sum    float64
+	count  uint64
+}
+
+// NewPrometheusCollector creates an empty PrometheusCollector.
+func NewPrometheusCollector(opts ...PrometheusOpts) *PrometheusCollector {
+	var singleOpt PrometheusOpts
+	if len(opts) > 0 {
+		singleOpt = opts[0]
+	}
+
+	namespace := singleOpt.Namespace
+	if namespace == "" {
+		namespace = "snapsql"
+	}
+
+	buckets := slices.Clone(singleOpt.Buckets)
+	if len(buckets) == 0 {
+		buckets = slices.Clone(DefaultPrometheusBuckets)
+	}
+
+	slices.Sort(buckets)
+
+	return &PrometheusCollector{
+		namespace: namespace,
+		buckets:   slices.Compact(buckets),
+		durations: make(map[durationKey]*durationHistogram),
+		errors:    make(map[errorKey]uint64),
+		slow:      make(map[string]uint64),
+		retries:   make(map[string]uint64),
+	}
+}
+
+// ObserveQuery implements MetricsCollector.
+func (c *PrometheusCollector) ObserveQuery(outcome QueryOutcome) {
+	seconds := outcome.Duration.Seconds()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := durationKey{funcName: outcome.FuncName, queryType: outcome.QueryType}
+
+	hist, ok := c.durations[key]
+	if !ok {
+		hist = &durationHistogram{counts: make([]uint64, len(c.buckets))}
+		c.durations[key] = hist
+	}
+
+	for i, upper := range c.buckets {
+		if seconds <= upper {
+			hist.counts[i]++
+		}
+	}
+
+	hist.sum += seconds
+	hist.count++
+
+	switch {
+	case outcome.Canceled:
+		c.errors[errorKey{funcName: outcome.FuncName, reason: "canceled"}]++
+	case outcome.DeadlineExceeded:
+		c.errors[errorKey{funcName: outcome.FuncName, reason: "deadline_exceeded"}]++
+	case outcome.Failed:
+		c.errors[errorKey{funcName: outcome.FuncName, reason: "error"}]++
+	}
+
+	if outcome.Slow {
+		c.slow[outcome.FuncName]++
+	}
+}
+
+// ObserveRetry implements RetryObserver.
+func (c *PrometheusCollector) ObserveRetry(event RetryEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.retries[event.FuncName]++
+}
+
+// ServeHTTP writes the metrics in the Prometheus text exposition format.
+func (c *PrometheusCollector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = c.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format. Series are sorted by
+// label values so the output is stable.
+func (c *PrometheusCollector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counter := &countingWriter{w: w}
+	buf := bufio.NewWriter(counter)
+
+	durationName := c.namespace + "_query_duration_seconds"
+	fmt.Fprintf(buf, "# HELP %s Duration of queries executed by generated functions.\n", durationName)
+	fmt.Fprintf(buf, "# TYPE %s histogram\n", durationName)
+
+	durationKeys := sortedKeys(c.durations, func(a, b durationKey) int {
+		return strings.Compare(a.funcName+"\x00"+string(a.queryType), b.funcName+"\x00"+string(b.queryType))
+	})
+	for _, key := range durationKeys {
+		hist := c.durations[key]
+		labels := fmt.Sprintf(`func="%s",query_type="%s"`, escapeLabelValue(key.funcName), escapeLabelValue(string(key.queryType)))
+
+		for i, upper := range c.buckets {
+			fmt.Fprintf(buf, "%s_bucket{%s,le=\"%s\"} %d\n", durationName, labels, formatFloat(upper), hist.counts[i])
+		}
+
+		fmt.Fprintf(buf, "%s_bucket{%s,le=\"+Inf\"} %d\n", durationName, labels, hist.count)
+		fmt.Fprintf(buf, "%s_sum{%s} %s\n", durationName, labels, formatFloat(hist.sum))
+		fmt.Fprintf(buf, "%s_count{%s} %d\n", durationName, labels, hist.count)
+	}
+
+	errorsName := c.namespace + "_query_errors_total"
+	fmt.Fprintf(buf, "# HELP %s Queries that failed, were canceled or exceeded their deadline.\n", errorsName)
+	fmt.Fprintf(buf, "# TYPE %s counter\n", errorsName)
+
+	errorKeys := sortedKeys(c.errors, func(a, b errorKey) int {
+		return strings.Compare(a.funcName+"\x00"+a.reason, b.funcName+"\x00"+b.reason)
+	})
+	for _, key := range errorKeys {
+		fmt.Fprintf(buf, "%s{func=\"%s\",reason=\"%s\"} %d\n", errorsName, escapeLabelValue(key.funcName), key.reason, c.errors[key])
+	}
+
+	slowName := c.namespace + "_slow_queries_total"
+	fmt.Fprintf(buf, "# HELP %s Queries that took at least the slow query threshold.\n", slowName)
+	fmt.Fprintf(buf, "# TYPE %s counter\n", slowName)
+
+	for _, funcName := range sortedKeys(c.slow, strings.Compare) {
+		fmt.Fprintf(buf, "%s{func=\"%s\"} %d\n", slowName, escapeLabelValue(funcName), c.slow[funcName])
+	}
+
+	retriesName := c.namespace + "_query_retries_total"
+	fmt.Fprintf(buf, "# HELP %s Attempts retried after a transient error.\n", retriesName)
+	fmt.Fprintf(buf, "# TYPE %s counter\n", retriesName)
+
+	for _, funcName := range sortedKeys(c.retries, strings.Compare) {
+		fmt.Fprintf(buf, "%s{func=\"%s\"} %d\n", retriesName, escapeLabelValue(funcName), c.retries[funcName])
+	}
+
+	err := buf.Flush()
+
+	return counter.n, err
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+
+	return n, err
+}
+
+func sortedKeys[K comparable, V any](m map[K]V, cmp func(a, b K) int) []K {
+	keys := make([]K, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	slices.SortFunc(keys, cmp)
+
+	return keys
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(v string) string {
+	return labelValueEscaper.Replace(v)
+}
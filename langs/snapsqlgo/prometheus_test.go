@@ -0,0 +1,81 @@
+package snapsqlgo
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrometheusCollectorWritesTextFormat(t *testing.T) {
+	collector := NewPrometheusCollector(PrometheusOpts{Buckets: []float64{0.1, 0.01}})
+
+	collector.ObserveQuery(QueryOutcome{FuncName: "ListUsers", QueryType: QueryLogQueryTypeSelect, Duration: 5 * time.Millisecond})
+	collector.ObserveQuery(QueryOutcome{FuncName: "ListUsers", QueryType: QueryLogQueryTypeSelect, Duration: 50 * time.Millisecond, Slow: true})
+	collector.ObserveQuery(QueryOutcome{FuncName: "ListUsers", QueryType: QueryLogQueryTypeSelect, Duration: 2 * time.Second, Failed: true, DeadlineExceeded: true, Slow: true})
+	collector.ObserveQuery(QueryOutcome{FuncName: "Delete\"User", QueryType: QueryLogQueryTypeExec, Duration: time.Millisecond, Failed: true})
+	collector.ObserveRetry(RetryEvent{FuncName: "Delete\"User", Attempt: 1})
+	collector.ObserveRetry(RetryEvent{FuncName: "Delete\"User", Attempt: 2})
+
+	var out strings.Builder
+
+	n, err := collector.WriteTo(&out)
+	require.NoError(t, err)
+	assert.Equal(t, int64(out.Len()), n)
+
+	expected := `# HELP snapsql_query_duration_seconds Duration of queries executed by generated functions.
+# TYPE snapsql_query_duration_seconds histogram
+snapsql_query_duration_seconds_bucket{func="Delete\"User",query_type="exec",le="0.01"} 1
+snapsql_query_duration_seconds_bucket{func="Delete\"User",query_type="exec",le="0.1"} 1
+snapsql_query_duration_seconds_bucket{func="Delete\"User",query_type="exec",le="+Inf"} 1
+snapsql_query_duration_seconds_sum{func="Delete\"User",query_type="exec"} 0.001
+snapsql_query_duration_seconds_count{func="Delete\"User",query_type="exec"} 1
+snapsql_query_duration_seconds_bucket{func="ListUsers",query_type="select",le="0.01"} 1
+snapsql_query_duration_seconds_bucket{func="ListUsers",query_type="select",le="0.1"} 2
+snapsql_query_duration_seconds_bucket{func="ListUsers",query_type="select",le="+Inf"} 3
+snapsql_query_duration_seconds_sum{func="ListUsers",query_type="select"} 2.055
+snapsql_query_duration_seconds_count{func="ListUsers",query_type="select"} 3
+# HELP snapsql_query_errors_total Queries that failed, were canceled or exceeded their deadline.
+# TYPE snapsql_query_errors_total counter
+snapsql_query_errors_total{func="Delete\"User",reason="error"} 1
+snapsql_query_errors_total{func="ListUsers",reason="deadline_exceeded"} 1
+# HELP snapsql_slow_queries_total Queries that took at least the slow query threshold.
+# TYPE snapsql_slow_queries_total counter
+snapsql_slow_queries_total{func="ListUsers"} 2
+# HELP snapsql_query_retries_total Attempts retried after a transient error.
+# TYPE snapsql_query_retries_total counter
+snapsql_query_retries_total{func="Delete\"User"} 2
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestPrometheusCollectorServeHTTP(t *testing.T) {
+	collector := NewPrometheusCollector(PrometheusOpts{Namespace: "app"})
+	collector.ObserveQuery(QueryOutcome{FuncName: "GetUser", QueryType: QueryLogQueryTypeSelect, Duration: time.Millisecond})
+
+	recorder := httptest.NewRecorder()
+	collector.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), `app_query_duration_seconds_count{func="GetUser",query_type="select"} 1`)
+}
+
+func TestPrometheusCollectorFedByQueryLogger(t *testing.T) {
+	collector := NewPrometheusCollector()
+	ctx := WithMetrics(t.Context(), collector)
+
+	logger := ExtractExecutionContext(ctx).QueryLogger()
+	logger.Write(ctx, func() (QueryLogMetadata, DBExecutor) {
+		return QueryLogMetadata{FuncName: "CountUsers", QueryType: QueryLogQueryTypeSelect}, nil
+	})
+
+	var out strings.Builder
+
+	_, err := collector.WriteTo(&out)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), `snapsql_query_duration_seconds_count{func="CountUsers",query_type="select"} 1`)
+	assert.NotContains(t, out.String(), "snapsql_query_errors_total{")
+}
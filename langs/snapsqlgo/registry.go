@@ -0,0 +1,187 @@
+package snapsqlgo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	// ErrQueryNotFound is returned when no registered query matches a name.
+	ErrQueryNotFound = errors.New("snapsqlgo: query not found")
+	// ErrAmbiguousQueryName is returned when an unqualified name matches queries of several packages.
+	ErrAmbiguousQueryName = errors.New("snapsqlgo: ambiguous query name")
+	// ErrQueryParameter is returned when a map parameter cannot be converted to the function's parameter type.
+	ErrQueryParameter = errors.New("snapsqlgo: invalid query parameter")
+)
+
+// QueryParam describes a parameter of a generated function.
+type QueryParam struct {
+	// Name is the parameter name of the SQL template, used as the key of map parameters.
+	Name string
+	// GoName is the argument name of the generated function.
+	GoName   string
+	Type     string
+	Optional bool
+}
+
+// QueryField describes a column of the response struct of a generated function.
+type QueryField struct {
+	// Name is the column name (the JSON tag of the response struct field).
+	Name   string
+	GoName string
+	Type   string
+}
+
+// QueryInvoker calls a generated function with parameters given by name. Iterator functions
+// are drained into a slice so that every invoker returns a plain value.
+type QueryInvoker func(ctx context.Context, executor DBExecutor, params map[string]any, opts ...FuncOpt) (any, error)
+
+// QueryInfo is the metadata that generated code registers for each function.
+type QueryInfo struct {
+	// Name is the Go function name.
+	Name string
+	// Package is the name of the generated package.
+	Package     string
+	Description string
+	Dialect     string
+	// StatementType is "select", "insert", "update", "delete" or "upsert".
+	StatementType string
+	// SQL is the SQL template with directives shown as comments and parameters as placeholders.
+	SQL        string
+	Parameters []QueryParam
+	// ResponseType is the Go return type of the function (without the error).
+	ResponseType string
+	// ResponseAffinity is "one", "many", "none", "exists" or "count".
+	ResponseAffinity string
+	ResponseFields   []QueryField
+	Invoke           QueryInvoker
+}
+
+// QualifiedName returns "package.Function", the key the query is registered under.
+func (q QueryInfo) QualifiedName() string {
+	if q.Package == "" {
+		return q.Name
+	}
+
+	return q.Package + "." + q.Name
+}
+
+// QueryRegistry holds the metadata of generated functions so that applications can enumerate
+// queries at runtime (e.g. admin dashboards) or call them by name with map parameters.
+// It is safe for concurrent use.
+type QueryRegistry struct {
+	mu      sync.RWMutex
+	queries map[string]QueryInfo
+}
+
+// Registry is the registry generated code registers its functions in from init().
+var Registry = NewQueryRegistry()
+
+// NewQueryRegistry creates an empty registry.
+func NewQueryRegistry() *QueryRegistry {
+	return &QueryRegistry{queries: make(map[string]QueryInfo)}
+}
+
+// Register adds a query. A query with the same qualified name is replaced.
+func (r *QueryRegistry) Register(info QueryInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.queries[info.QualifiedName()] = info
+}
+
+// Lookup finds a query by qualified name ("package.Function") or, when only one package
+// defines it, by function name.
+func (r *QueryRegistry) Lookup(name string) (QueryInfo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if info, ok := r.queries[name]; ok {
+		return info, nil
+	}
+
+	var (
+		found QueryInfo
+		count int
+	)
+
+	for _, info := range r.queries {
+		if info.Name == name {
+			found = info
+			count++
+		}
+	}
+
+	switch count {
+	case 0:
+		return QueryInfo{}, fmt.Errorf("%w: %s", ErrQueryNotFound, name)
+	case 1:
+		return found, nil
+	default:
+		return QueryInfo{}, fmt.Errorf("%w: %s is defined in %d packages", ErrAmbiguousQueryName, name, count)
+	}
+}
+
+// All returns the registered queries sorted by qualified name.
+func (r *QueryRegistry) All() []QueryInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]QueryInfo, 0, len(r.queries))
+	for _, info := range r.queries {
+		result = append(result, info)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].QualifiedName() < result[j].QualifiedName()
+	})
+
+	return result
+}
+
+// Invoke looks up a query and calls it with params keyed by the template parameter names.
+func (r *QueryRegistry) Invoke(ctx context.Context, name string, executor DBExecutor, params map[string]any, opts ...FuncOpt) (any, error) {
+	info, err := r.Lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Invoke == nil {
+		return nil, fmt.Errorf("%w: %s cannot be invoked dynamically", ErrQueryNotFound, name)
+	}
+
+	return info.Invoke(ctx, executor, params, opts...)
+}
+
+// RegistryParam converts params[name] to the parameter type T of a generated function.
+// Values that are not already a T are converted through JSON, so numbers decoded as float64,
+// nested maps for struct parameters and RFC 3339 strings for time.Time are accepted.
+// A missing parameter yields the zero value.
+func RegistryParam[T any](params map[string]any, name string) (T, error) {
+	var zero T
+
+	raw, ok := params[name]
+	if !ok || raw == nil {
+		return zero, nil
+	}
+
+	if v, ok := raw.(T); ok {
+		return v, nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return zero, fmt.Errorf("%w %s: %w", ErrQueryParameter, name, err)
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return zero, fmt.Errorf("%w %s: %w", ErrQueryParameter, name, err)
+	}
+
+	return v, nil
+}
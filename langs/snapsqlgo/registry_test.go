@@ -0,0 +1,107 @@
+package snapsqlgo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	snapsqlgo "github.com/shibukawa/snapsql/langs/snapsqlgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryRegistry(t *testing.T) {
+	registry := snapsqlgo.NewQueryRegistry()
+
+	type filter struct {
+		Status string `json:"status"`
+	}
+
+	registry.Register(snapsqlgo.QueryInfo{
+		Name:    "ListUsers",
+		Package: "users",
+		Parameters: []snapsqlgo.QueryParam{
+			{Name: "limit", GoName: "limit", Type: "int"},
+			{Name: "filter", GoName: "filter", Type: "Filter"},
+		},
+		Invoke: func(ctx context.Context, executor snapsqlgo.DBExecutor, params map[string]any, opts ...snapsqlgo.FuncOpt) (any, error) {
+			limit, err := snapsqlgo.RegistryParam[int](params, "limit")
+			if err != nil {
+				return nil, err
+			}
+
+			f, err := snapsqlgo.RegistryParam[filter](params, "filter")
+			if err != nil {
+				return nil, err
+			}
+
+			return []any{limit, f.Status}, nil
+		},
+	})
+	registry.Register(snapsqlgo.QueryInfo{Name: "GetUser", Package: "users"})
+	registry.Register(snapsqlgo.QueryInfo{Name: "GetUser", Package: "admin"})
+
+	t.Run("all", func(t *testing.T) {
+		var names []string
+		for _, info := range registry.All() {
+			names = append(names, info.QualifiedName())
+		}
+
+		assert.Equal(t, []string{"admin.GetUser", "users.GetUser", "users.ListUsers"}, names)
+	})
+
+	t.Run("lookup", func(t *testing.T) {
+		info, err := registry.Lookup("ListUsers")
+		require.NoError(t, err)
+		assert.Equal(t, "users", info.Package)
+
+		info, err = registry.Lookup("admin.GetUser")
+		require.NoError(t, err)
+		assert.Equal(t, "admin", info.Package)
+
+		_, err = registry.Lookup("GetUser")
+		require.ErrorIs(t, err, snapsqlgo.ErrAmbiguousQueryName)
+
+		_, err = registry.Lookup("DeleteUser")
+		require.ErrorIs(t, err, snapsqlgo.ErrQueryNotFound)
+	})
+
+	t.Run("invoke", func(t *testing.T) {
+		result, err := registry.Invoke(context.Background(), "ListUsers", nil, map[string]any{
+			"limit":  float64(10),
+			"filter": map[string]any{"status": "active"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []any{10, "active"}, result)
+
+		_, err = registry.Invoke(context.Background(), "ListUsers", nil, map[string]any{"limit": "ten"})
+		require.ErrorIs(t, err, snapsqlgo.ErrQueryParameter)
+
+		_, err = registry.Invoke(context.Background(), "users.GetUser", nil, nil)
+		require.ErrorIs(t, err, snapsqlgo.ErrQueryNotFound)
+	})
+}
+
+func TestRegistryParam(t *testing.T) {
+	params := map[string]any{
+		"id":         42,
+		"created_at": "2024-01-02T03:04:05Z",
+		"tags":       []any{"a", "b"},
+	}
+
+	id, err := snapsqlgo.RegistryParam[int](params, "id")
+	require.NoError(t, err)
+	assert.Equal(t, 42, id)
+
+	createdAt, err := snapsqlgo.RegistryParam[time.Time](params, "created_at")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), createdAt)
+
+	tags, err := snapsqlgo.RegistryParam[[]string](params, "tags")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, tags)
+
+	missing, err := snapsqlgo.RegistryParam[*string](params, "missing")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
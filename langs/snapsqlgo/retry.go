@@ -0,0 +1,295 @@
+package snapsqlgo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Defaults applied to zero fields of RetryPolicy.
+const (
+	DefaultRetryMaxAttempts    = 3
+	DefaultRetryInitialBackoff = 50 * time.Millisecond
+	DefaultRetryMaxBackoff     = 2 * time.Second
+	DefaultRetryMultiplier     = 2.0
+)
+
+// RetryPolicy controls how generated functions retry statements that failed with a transient error.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry. Each later wait is multiplied by
+	// Multiplier and capped at MaxBackoff; the actual wait is randomized between half and all of it.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// Retryable classifies errors. nil uses IsTransientError.
+	Retryable func(dialect string, err error) bool
+}
+
+// WithRetry makes generated functions retry statements that fail with a transient error such as
+// a serialization failure or a deadlock. Zero fields of policy use the DefaultRetry* values.
+//
+// Retries are skipped when the executor is a *sql.Tx: the database has already rolled the
+// transaction back, so the whole transaction has to be retried by the caller (see Retry).
+// Iterator functions only retry opening the result set, never after a row was yielded.
+func WithRetry(policy RetryPolicy) FuncOpt {
+	return func(config *FuncConfig) {
+		config.Retry = &policy
+	}
+}
+
+// RetryOptions is the resolved retry setting of a single generated function call.
+type RetryOptions struct {
+	FuncName string
+	// Dialect is the database dialect of the generated code (postgres, mysql, mariadb, sqlite, cockroach).
+	Dialect string
+	// Policy is nil when retries are disabled.
+	Policy *RetryPolicy
+}
+
+// ResolveRetryOptions merges the configuration registered with WithConfig for funcName and the
+// per-call options into the retry settings used by generated functions.
+//
+// CockroachDB aborts conflicting transactions with serialization failures (40001) and expects
+// clients to retry them, so code generated for the cockroach dialect retries with the default
+// policy unless WithRetry sets another one.
+func ResolveRetryOptions(ctx context.Context, funcName, dialect, statementType string, opts ...FuncOpt) RetryOptions {
+	config := resolveFuncConfig(ctx, funcName, strings.ToLower(statementType), opts)
+
+	policy := config.Retry
+	if policy == nil && strings.EqualFold(dialect, "cockroach") {
+		policy = &RetryPolicy{}
+	}
+
+	return RetryOptions{
+		FuncName: funcName,
+		Dialect:  strings.ToLower(dialect),
+		Policy:   policy,
+	}
+}
+
+// RetryEvent describes a failed attempt that is about to be retried.
+type RetryEvent struct {
+	FuncName string
+	// Attempt is the 1-based number of the attempt that failed.
+	Attempt int
+	// Delay is the wait before the next attempt.
+	Delay time.Duration
+	Err   error
+}
+
+// RetryObserver is implemented by MetricsCollectors that also count retries. Collectors
+// registered with WithMetrics are checked for it, so existing collectors keep working.
+type RetryObserver interface {
+	ObserveRetry(event RetryEvent)
+}
+
+// Retry calls attempt until it succeeds, returns an error the policy does not consider
+// transient, or the policy runs out of attempts. The error of the last attempt is returned
+// unchanged. Generated functions call it around each execution; it can also wrap a whole
+// transaction when opts is built by hand:
+//
+//	opts := snapsqlgo.RetryOptions{FuncName: "Transfer", Dialect: "postgres", Policy: &policy}
+//	_, err := snapsqlgo.Retry(ctx, opts, db, func(ctx context.Context) (struct{}, error) {
+//		return struct{}{}, transfer(ctx, db)
+//	})
+func Retry[T any](ctx context.Context, opts RetryOptions, executor DBExecutor, attempt func(ctx context.Context) (T, error)) (T, error) {
+	result, err := attempt(ctx)
+	if err == nil || opts.Policy == nil {
+		return result, err
+	}
+
+	if _, ok := executor.(*sql.Tx); ok {
+		return result, err
+	}
+
+	policy := opts.Policy.withDefaults()
+	delay := policy.InitialBackoff
+
+	for n := 1; n < policy.MaxAttempts && policy.Retryable(opts.Dialect, err); n++ {
+		wait := jitter(delay)
+		observeRetry(ctx, RetryEvent{FuncName: opts.FuncName, Attempt: n, Delay: wait, Err: err})
+
+		if !sleepContext(ctx, wait) {
+			return result, err
+		}
+
+		result, err = attempt(ctx)
+		if err == nil {
+			return result, nil
+		}
+
+		delay = min(time.Duration(float64(delay)*policy.Multiplier), policy.MaxBackoff)
+	}
+
+	return result, err
+}
+
+func (p *RetryPolicy) withDefaults() RetryPolicy {
+	policy := *p
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = DefaultRetryMaxAttempts
+	}
+
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = DefaultRetryInitialBackoff
+	}
+
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = DefaultRetryMaxBackoff
+	}
+
+	if policy.Multiplier < 1 {
+		policy.Multiplier = DefaultRetryMultiplier
+	}
+
+	if policy.Retryable == nil {
+		policy.Retryable = IsTransientError
+	}
+
+	return policy
+}
+
+func jitter(delay time.Duration) time.Duration {
+	half := int64(delay / 2)
+	if half <= 0 {
+		return delay
+	}
+
+	return time.Duration(half + rand.Int64N(half+1))
+}
+
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+func observeRetry(ctx context.Context, event RetryEvent) {
+	ec := ExtractExecutionContext(ctx)
+	if ec == nil || ec.metrics == nil {
+		return
+	}
+
+	if observer, ok := ec.metrics.collector.(RetryObserver); ok {
+		observer.ObserveRetry(event)
+	}
+}
+
+// IsTransientError reports whether err is a failure that succeeds when the statement is run
+// again: serialization failures (SQLSTATE 40001) and deadlocks (40P01) on PostgreSQL and
+// CockroachDB, deadlocks (1213) and lock wait timeouts (1205) on MySQL and MariaDB, and
+// SQLITE_BUSY / SQLITE_LOCKED on SQLite. Driver errors are recognized by shape, so no driver package is imported. An empty
+// dialect accepts the errors of every dialect.
+func IsTransientError(dialect string, err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	switch strings.ToLower(dialect) {
+	case "postgres", "postgresql", "pg", "cockroach":
+		return isTransientPostgres(err)
+	case "mysql", "mariadb":
+		return isTransientMySQL(err)
+	case "sqlite", "sqlite3":
+		return isTransientSQLite(err)
+	default:
+		return isTransientPostgres(err) || isTransientMySQL(err) || isTransientSQLite(err)
+	}
+}
+
+// sqlStateError is implemented by pgx (*pgconn.PgError) and lib/pq (*pq.Error).
+type sqlStateError interface {
+	SQLState() string
+}
+
+func isTransientPostgres(err error) bool {
+	var stateErr sqlStateError
+	if !errors.As(err, &stateErr) {
+		return false
+	}
+
+	switch stateErr.SQLState() {
+	case "40001", "40P01": // serialization_failure, deadlock_detected
+		return true
+	default:
+		return false
+	}
+}
+
+func isTransientMySQL(err error) bool {
+	// *mysql.MySQLError{Number uint16, SQLState [5]byte, ...}
+	mysqlErr, ok := errorStruct(err, "Number", reflect.Uint16)
+	if !ok {
+		return false
+	}
+
+	switch mysqlErr.FieldByName("Number").Uint() {
+	case 1213, 1205: // ER_LOCK_DEADLOCK, ER_LOCK_WAIT_TIMEOUT
+		return true
+	default:
+		return false
+	}
+}
+
+func isTransientSQLite(err error) bool {
+	// sqlite3.Error{Code ErrNo, ExtendedCode ErrNoExtended, ...}
+	sqliteErr, ok := errorStruct(err, "ExtendedCode", reflect.Int)
+	if !ok {
+		return false
+	}
+
+	code := sqliteErr.FieldByName("Code")
+	if code.Kind() != reflect.Int {
+		return false
+	}
+
+	switch code.Int() {
+	case 5, 6: // SQLITE_BUSY, SQLITE_LOCKED
+		return true
+	default:
+		return false
+	}
+}
+
+// errorStruct returns the first error in err's chain that is a struct (or a pointer to one)
+// with a field of the given name and kind.
+func errorStruct(err error, field string, kind reflect.Kind) (reflect.Value, bool) {
+	for err != nil {
+		v := reflect.ValueOf(err)
+		if v.Kind() == reflect.Pointer && !v.IsNil() {
+			v = v.Elem()
+		}
+
+		if v.Kind() == reflect.Struct {
+			if f := v.FieldByName(field); f.IsValid() && f.Kind() == kind {
+				return v, true
+			}
+		}
+
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			for _, inner := range joined.Unwrap() {
+				if found, ok := errorStruct(inner, field, kind); ok {
+					return found, true
+				}
+			}
+
+			return reflect.Value{}, false
+		}
+
+		err = errors.Unwrap(err)
+	}
+
+	return reflect.Value{}, false
+}
@@ -0,0 +1,155 @@
+package snapsqlgo_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+	snapsqlgo "github.com/shibukawa/snapsql/langs/snapsqlgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name     string
+		dialect  string
+		err      error
+		expected bool
+	}{
+		{"postgres serialization failure", "postgres", &pgconn.PgError{Code: "40001"}, true},
+		{"postgres deadlock", "postgresql", fmt.Errorf("wrapped: %w", &pgconn.PgError{Code: "40P01"}), true},
+		{"postgres unique violation", "postgres", &pgconn.PgError{Code: "23505"}, false},
+		{"cockroach restart transaction", "cockroach", &pgconn.PgError{Code: "40001"}, true},
+		{"mysql deadlock", "mysql", &mysql.MySQLError{Number: 1213}, true},
+		{"mariadb lock wait timeout", "mariadb", fmt.Errorf("wrapped: %w", &mysql.MySQLError{Number: 1205}), true},
+		{"mysql duplicate entry", "mysql", &mysql.MySQLError{Number: 1062}, false},
+		{"sqlite busy", "sqlite", sqlite3.Error{Code: sqlite3.ErrBusy}, true},
+		{"sqlite constraint", "sqlite", sqlite3.Error{Code: sqlite3.ErrConstraint}, false},
+		{"other dialect's error", "mysql", &pgconn.PgError{Code: "40001"}, false},
+		{"any dialect", "", errors.Join(errors.New("other"), &mysql.MySQLError{Number: 1213}), true},
+		{"canceled", "postgres", context.Canceled, false},
+		{"plain error", "postgres", errors.New("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, snapsqlgo.IsTransientError(tt.dialect, tt.err))
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	db := openStreamTestDB(t, 0)
+	policy := snapsqlgo.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+	deadlock := &pgconn.PgError{Code: "40P01"}
+
+	failing := func(failures int, err error) (func(context.Context) (int, error), *int) {
+		calls := 0
+
+		return func(context.Context) (int, error) {
+			calls++
+			if calls <= failures {
+				return 0, err
+			}
+
+			return calls, nil
+		}, &calls
+	}
+
+	t.Run("retries transient errors", func(t *testing.T) {
+		counters := &snapsqlgo.QueryCounters{}
+		ctx := snapsqlgo.WithMetrics(t.Context(), counters)
+		opts := snapsqlgo.ResolveRetryOptions(ctx, "UpdateItem", "postgres", "update", snapsqlgo.WithRetry(policy))
+
+		attempt, calls := failing(2, deadlock)
+		result, err := snapsqlgo.Retry(ctx, opts, db, attempt)
+		require.NoError(t, err)
+		assert.Equal(t, 3, result)
+		assert.Equal(t, 3, *calls)
+		assert.Equal(t, int64(2), counters.Snapshot().Retries)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		opts := snapsqlgo.ResolveRetryOptions(t.Context(), "UpdateItem", "postgres", "update", snapsqlgo.WithRetry(policy))
+
+		attempt, calls := failing(5, deadlock)
+		_, err := snapsqlgo.Retry(t.Context(), opts, db, attempt)
+		assert.ErrorIs(t, err, deadlock)
+		assert.Equal(t, 3, *calls)
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		opts := snapsqlgo.ResolveRetryOptions(t.Context(), "UpdateItem", "postgres", "update", snapsqlgo.WithRetry(policy))
+
+		attempt, calls := failing(1, &pgconn.PgError{Code: "23505"})
+		_, err := snapsqlgo.Retry(t.Context(), opts, db, attempt)
+		require.Error(t, err)
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("disabled without policy", func(t *testing.T) {
+		opts := snapsqlgo.ResolveRetryOptions(t.Context(), "UpdateItem", "postgres", "update")
+
+		attempt, calls := failing(1, deadlock)
+		_, err := snapsqlgo.Retry(t.Context(), opts, db, attempt)
+		require.Error(t, err)
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("enabled by default for cockroach", func(t *testing.T) {
+		opts := snapsqlgo.ResolveRetryOptions(t.Context(), "UpdateItem", "cockroach", "update")
+		require.NotNil(t, opts.Policy)
+
+		serialization := &pgconn.PgError{Code: "40001"}
+		opts.Policy.InitialBackoff = time.Millisecond
+
+		attempt, calls := failing(1, serialization)
+		_, err := snapsqlgo.Retry(t.Context(), opts, db, attempt)
+		require.NoError(t, err)
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("configured with WithConfig", func(t *testing.T) {
+		ctx := snapsqlgo.WithConfig(t.Context(), "update:*", snapsqlgo.WithRetry(policy))
+		opts := snapsqlgo.ResolveRetryOptions(ctx, "UpdateItem", "postgres", "update")
+
+		attempt, calls := failing(1, deadlock)
+		_, err := snapsqlgo.Retry(ctx, opts, db, attempt)
+		require.NoError(t, err)
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("skipped inside a transaction", func(t *testing.T) {
+		tx, err := db.BeginTx(t.Context(), nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = tx.Rollback() })
+
+		opts := snapsqlgo.ResolveRetryOptions(t.Context(), "UpdateItem", "postgres", "update", snapsqlgo.WithRetry(policy))
+
+		attempt, calls := failing(1, deadlock)
+		_, err = snapsqlgo.Retry(t.Context(), opts, tx, attempt)
+		require.Error(t, err)
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		opts := snapsqlgo.ResolveRetryOptions(ctx, "UpdateItem", "postgres", "update", snapsqlgo.WithRetry(snapsqlgo.RetryPolicy{InitialBackoff: time.Hour}))
+
+		calls := 0
+		_, err := snapsqlgo.Retry(ctx, opts, db, func(context.Context) (int, error) {
+			calls++
+			cancel()
+
+			return 0, deadlock
+		})
+		assert.ErrorIs(t, err, deadlock)
+		assert.Equal(t, 1, calls)
+	})
+}
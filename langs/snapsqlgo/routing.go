@@ -0,0 +1,113 @@
+package snapsqlgo
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync/atomic"
+)
+
+// ExecutorRoute selects which executor of a RoutingExecutor a generated function uses.
+type ExecutorRoute int
+
+const (
+	// RouteAuto sends SELECT statements to a replica and everything else to the primary.
+	RouteAuto ExecutorRoute = iota
+	// RoutePrimary always uses the primary executor.
+	RoutePrimary
+	// RouteReplica uses a replica executor even for mutations.
+	RouteReplica
+)
+
+// WithRoute overrides the statement-type based routing of a RoutingExecutor, e.g. to read
+// from the primary right after a write. It has no effect on other executors.
+func WithRoute(route ExecutorRoute) FuncOpt {
+	return func(config *FuncConfig) {
+		config.Route = route
+	}
+}
+
+// RoutingExecutor splits reads and writes between a primary and its replicas. Generated
+// functions recognize it and pick the executor from the statement type recorded at generation
+// time, so the SQL text is never inspected. Used directly as a DBExecutor it forwards to the
+// primary.
+type RoutingExecutor struct {
+	primary  DBExecutor
+	replicas []DBExecutor
+	next     atomic.Uint64
+}
+
+var _ DBExecutor = (*RoutingExecutor)(nil)
+
+// NewRoutingExecutor creates a RoutingExecutor. Replicas are used in round-robin order; with
+// no replicas every statement goes to the primary.
+func NewRoutingExecutor(primary DBExecutor, replicas ...DBExecutor) *RoutingExecutor {
+	return &RoutingExecutor{
+		primary:  primary,
+		replicas: replicas,
+	}
+}
+
+// Primary returns the primary executor.
+func (r *RoutingExecutor) Primary() DBExecutor {
+	return r.primary
+}
+
+// Replica returns the next replica executor, or the primary when no replica is configured.
+func (r *RoutingExecutor) Replica() DBExecutor {
+	if len(r.replicas) == 0 {
+		return r.primary
+	}
+
+	return r.replicas[(r.next.Add(1)-1)%uint64(len(r.replicas))]
+}
+
+// Route returns the executor for a statement of statementType ("select", "insert", "update",
+// "delete", "upsert"). Unknown statement types go to the primary.
+func (r *RoutingExecutor) Route(statementType string, route ExecutorRoute) DBExecutor {
+	switch route {
+	case RoutePrimary:
+		return r.primary
+	case RouteReplica:
+		return r.Replica()
+	case RouteAuto:
+	}
+
+	if strings.EqualFold(statementType, "select") {
+		return r.Replica()
+	}
+
+	return r.primary
+}
+
+// PrepareContext prepares query on the primary.
+func (r *RoutingExecutor) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return r.primary.PrepareContext(ctx, query)
+}
+
+// QueryContext runs query on the primary.
+func (r *RoutingExecutor) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return r.primary.QueryContext(ctx, query, args...)
+}
+
+// ExecContext runs query on the primary.
+func (r *RoutingExecutor) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return r.primary.ExecContext(ctx, query, args...)
+}
+
+// RouteExecutor resolves the executor a generated function runs its statement on. Executors
+// other than *RoutingExecutor are returned unchanged. SELECT statements with a row lock
+// requested via WithRowLock or WithRowLockMode stay on the primary unless WithRoute says otherwise.
+func RouteExecutor(ctx context.Context, executor DBExecutor, funcName, statementType string, opts ...FuncOpt) DBExecutor {
+	router, ok := executor.(*RoutingExecutor)
+	if !ok {
+		return executor
+	}
+
+	config := resolveFuncConfig(ctx, funcName, strings.ToLower(statementType), opts)
+	if config.Route == RouteAuto && rowLockModeFromConfig(ctx, config) != RowLockNone {
+		return router.primary
+	}
+
+	return router.Route(statementType, config.Route)
+}
@@ -0,0 +1,48 @@
+package snapsqlgo_test
+
+import (
+	"context"
+	"testing"
+
+	snapsqlgo "github.com/shibukawa/snapsql/langs/snapsqlgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteExecutor(t *testing.T) {
+	primary := openStreamTestDB(t, 0)
+	replica1 := openStreamTestDB(t, 0)
+	replica2 := openStreamTestDB(t, 0)
+	router := snapsqlgo.NewRoutingExecutor(primary, replica1, replica2)
+	ctx := context.Background()
+
+	assert.Same(t, replica1, snapsqlgo.RouteExecutor(ctx, router, "ListItems", "select"))
+	assert.Same(t, replica2, snapsqlgo.RouteExecutor(ctx, router, "ListItems", "select"))
+	assert.Same(t, replica1, snapsqlgo.RouteExecutor(ctx, router, "ListItems", "select"))
+
+	for _, statementType := range []string{"insert", "update", "delete", ""} {
+		assert.Same(t, primary, snapsqlgo.RouteExecutor(ctx, router, "ChangeItem", statementType), statementType)
+	}
+
+	t.Run("override", func(t *testing.T) {
+		assert.Same(t, primary, snapsqlgo.RouteExecutor(ctx, router, "ListItems", "select", snapsqlgo.WithRoute(snapsqlgo.RoutePrimary)))
+		assert.NotSame(t, primary, snapsqlgo.RouteExecutor(ctx, router, "UpdateItem", "update", snapsqlgo.WithRoute(snapsqlgo.RouteReplica)))
+
+		configured := snapsqlgo.WithConfig(ctx, "ListItems", snapsqlgo.WithRoute(snapsqlgo.RoutePrimary))
+		assert.Same(t, primary, snapsqlgo.RouteExecutor(configured, router, "ListItems", "select"))
+	})
+
+	t.Run("row lock stays on primary", func(t *testing.T) {
+		locked := snapsqlgo.WithRowLock(ctx)
+		assert.Same(t, primary, snapsqlgo.RouteExecutor(locked, router, "ListItems", "select"))
+		assert.Same(t, primary, snapsqlgo.RouteExecutor(ctx, router, "ClaimJobs", "select", snapsqlgo.WithRowLockMode(snapsqlgo.RowLockForUpdateSkipLocked)))
+	})
+
+	t.Run("without replicas", func(t *testing.T) {
+		single := snapsqlgo.NewRoutingExecutor(primary)
+		assert.Same(t, primary, snapsqlgo.RouteExecutor(ctx, single, "ListItems", "select"))
+	})
+
+	t.Run("plain executor", func(t *testing.T) {
+		assert.Same(t, replica1, snapsqlgo.RouteExecutor(ctx, replica1, "UpdateItem", "update"))
+	})
+}
@@ -1,12 +1,61 @@
 package snapsqlgo
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 )
 
 // ErrRowLockNotSupported is returned when a requested row-lock mode is not supported by the dialect.
 var ErrRowLockNotSupported = errors.New("row lock is not supported for this dialect")
 
+// String returns the lock clause keywords of the mode (e.g. "FOR UPDATE SKIP LOCKED").
+func (m RowLockMode) String() string {
+	switch m {
+	case RowLockNone:
+		return "NONE"
+	case RowLockForUpdate:
+		return "FOR UPDATE"
+	case RowLockForShare:
+		return "FOR SHARE"
+	case RowLockForUpdateNoWait:
+		return "FOR UPDATE NOWAIT"
+	case RowLockForUpdateSkipLocked:
+		return "FOR UPDATE SKIP LOCKED"
+	case RowLockForShareNoWait:
+		return "FOR SHARE NOWAIT"
+	case RowLockForShareSkipLocked:
+		return "FOR SHARE SKIP LOCKED"
+	}
+
+	return fmt.Sprintf("RowLockMode(%d)", int(m))
+}
+
+// WithRowLockMode requests a pessimistic lock for a single function call or, registered with
+// WithConfig, for matching functions. It takes precedence over the mode set by WithRowLock, and
+// RowLockNone disables a lock requested on the context. Job-queue style workers typically use
+// RowLockForUpdateSkipLocked so that concurrent workers pick different rows.
+func WithRowLockMode(mode RowLockMode) FuncOpt {
+	return func(config *FuncConfig) {
+		config.RowLock = &mode
+	}
+}
+
+// ResolveRowLockMode returns the lock mode a generated function applies: the mode given by
+// WithRowLockMode (per call or registered with WithConfig) if any, otherwise the mode set by WithRowLock.
+func ResolveRowLockMode(ctx context.Context, funcName, statementType string, opts ...FuncOpt) RowLockMode {
+	return rowLockModeFromConfig(ctx, resolveFuncConfig(ctx, funcName, strings.ToLower(statementType), opts))
+}
+
+func rowLockModeFromConfig(ctx context.Context, config FuncConfig) RowLockMode {
+	if config.RowLock != nil {
+		return *config.RowLock
+	}
+
+	return ExtractExecutionContext(ctx).RowLockMode()
+}
+
 // EnsureRowLockAllowed panics if a row-lock directive is applied to an unsupported query type.
 func EnsureRowLockAllowed(queryType QueryLogQueryType, mode RowLockMode) {
 	if mode == RowLockNone {
@@ -33,50 +82,66 @@ func BuildRowLockClauseMySQL(mode RowLockMode) (string, error) {
 }
 
 // BuildRowLockClauseMariaDB is an exported helper for generated code to call
-// when targeting MariaDB. MariaDB behaves like MySQL for row-lock clauses
-// so this wraps the same internal implementation.
+// when targeting MariaDB. MariaDB has no FOR SHARE, so shared locks are emitted
+// as LOCK IN SHARE MODE. NOWAIT and SKIP LOCKED require MariaDB 10.6 or later.
 func BuildRowLockClauseMariaDB(mode RowLockMode) (string, error) {
-	// MariaDB behaves like MySQL for row-lock clauses.
-	return mysqlRowLockClause(mode)
+	return mariadbRowLockClause(mode)
 }
 
 // BuildRowLockClauseSQLite is an exported helper for generated code to call
-// when targeting SQLite. SQLite does not support pessimistic row locking
-// so this either returns an empty clause or ErrRowLockNotSupported.
+// when targeting SQLite. SQLite does not support pessimistic row locking: FOR UPDATE
+// and FOR SHARE are dropped because SQLite serializes writers on the database file,
+// while NOWAIT and SKIP LOCKED cannot be emulated and return ErrRowLockNotSupported.
 func BuildRowLockClauseSQLite(mode RowLockMode) (string, error) {
-	if mode == RowLockNone {
+	switch mode {
+	case RowLockNone, RowLockForUpdate, RowLockForShare:
 		return "", nil
 	}
 
-	return "", ErrRowLockNotSupported
+	return "", unsupportedRowLock("sqlite", mode)
 }
 
 func postgresRowLockClause(mode RowLockMode) (string, error) {
 	switch mode {
-	case RowLockForUpdate:
-		return " FOR UPDATE", nil
-	case RowLockForShare:
-		return " FOR SHARE", nil
-	case RowLockForUpdateNoWait:
-		return " FOR UPDATE NOWAIT", nil
-	case RowLockForUpdateSkipLocked:
-		return " FOR UPDATE SKIP LOCKED", nil
+	case RowLockNone:
+		return "", nil
+	case RowLockForUpdate, RowLockForShare, RowLockForUpdateNoWait, RowLockForUpdateSkipLocked,
+		RowLockForShareNoWait, RowLockForShareSkipLocked:
+		return " " + mode.String(), nil
 	}
 
-	return "", nil
+	return "", unsupportedRowLock("postgres", mode)
 }
 
 func mysqlRowLockClause(mode RowLockMode) (string, error) {
 	switch mode {
-	case RowLockForUpdate:
-		return " FOR UPDATE", nil
+	case RowLockNone:
+		return "", nil
+	case RowLockForUpdate, RowLockForShare, RowLockForUpdateNoWait, RowLockForUpdateSkipLocked,
+		RowLockForShareNoWait, RowLockForShareSkipLocked:
+		return " " + mode.String(), nil
+	}
+
+	return "", unsupportedRowLock("mysql", mode)
+}
+
+func mariadbRowLockClause(mode RowLockMode) (string, error) {
+	switch mode {
+	case RowLockNone:
+		return "", nil
+	case RowLockForUpdate, RowLockForUpdateNoWait, RowLockForUpdateSkipLocked:
+		return " " + mode.String(), nil
 	case RowLockForShare:
-		return " FOR SHARE", nil
-	case RowLockForUpdateNoWait:
-		return " FOR UPDATE NOWAIT", nil
-	case RowLockForUpdateSkipLocked:
-		return " FOR UPDATE SKIP LOCKED", nil
+		return " LOCK IN SHARE MODE", nil
+	case RowLockForShareNoWait:
+		return " LOCK IN SHARE MODE NOWAIT", nil
+	case RowLockForShareSkipLocked:
+		return " LOCK IN SHARE MODE SKIP LOCKED", nil
 	}
 
-	return "", nil
+	return "", unsupportedRowLock("mariadb", mode)
+}
+
+func unsupportedRowLock(dialect string, mode RowLockMode) error {
+	return fmt.Errorf("%w: %s on %s", ErrRowLockNotSupported, mode, dialect)
 }
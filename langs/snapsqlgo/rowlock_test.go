@@ -0,0 +1,79 @@
+package snapsqlgo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildRowLockClauses(t *testing.T) {
+	tests := []struct {
+		mode     RowLockMode
+		postgres string
+		mysql    string
+		mariadb  string
+	}{
+		{RowLockNone, "", "", ""},
+		{RowLockForUpdate, " FOR UPDATE", " FOR UPDATE", " FOR UPDATE"},
+		{RowLockForShare, " FOR SHARE", " FOR SHARE", " LOCK IN SHARE MODE"},
+		{RowLockForUpdateNoWait, " FOR UPDATE NOWAIT", " FOR UPDATE NOWAIT", " FOR UPDATE NOWAIT"},
+		{RowLockForUpdateSkipLocked, " FOR UPDATE SKIP LOCKED", " FOR UPDATE SKIP LOCKED", " FOR UPDATE SKIP LOCKED"},
+		{RowLockForShareNoWait, " FOR SHARE NOWAIT", " FOR SHARE NOWAIT", " LOCK IN SHARE MODE NOWAIT"},
+		{RowLockForShareSkipLocked, " FOR SHARE SKIP LOCKED", " FOR SHARE SKIP LOCKED", " LOCK IN SHARE MODE SKIP LOCKED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			clause, err := BuildRowLockClausePostgres(tt.mode)
+			require.NoError(t, err)
+			assert.Equal(t, tt.postgres, clause)
+
+			clause, err = BuildRowLockClauseMySQL(tt.mode)
+			require.NoError(t, err)
+			assert.Equal(t, tt.mysql, clause)
+
+			clause, err = BuildRowLockClauseMariaDB(tt.mode)
+			require.NoError(t, err)
+			assert.Equal(t, tt.mariadb, clause)
+		})
+	}
+
+	t.Run("unknown mode", func(t *testing.T) {
+		_, err := BuildRowLockClausePostgres(RowLockMode(99))
+		require.ErrorIs(t, err, ErrRowLockNotSupported)
+	})
+}
+
+func TestBuildRowLockClauseSQLite(t *testing.T) {
+	for _, mode := range []RowLockMode{RowLockNone, RowLockForUpdate, RowLockForShare} {
+		clause, err := BuildRowLockClauseSQLite(mode)
+		require.NoError(t, err, mode.String())
+		assert.Empty(t, clause)
+	}
+
+	for _, mode := range []RowLockMode{RowLockForUpdateNoWait, RowLockForUpdateSkipLocked, RowLockForShareNoWait, RowLockForShareSkipLocked} {
+		_, err := BuildRowLockClauseSQLite(mode)
+		require.ErrorIs(t, err, ErrRowLockNotSupported, mode.String())
+		assert.Contains(t, err.Error(), mode.String()+" on sqlite")
+	}
+}
+
+func TestResolveRowLockMode(t *testing.T) {
+	ctx := t.Context()
+	assert.Equal(t, RowLockNone, ResolveRowLockMode(ctx, "ClaimJobs", "SELECT"))
+
+	locked := WithRowLock(ctx)
+	assert.Equal(t, RowLockForUpdate, ResolveRowLockMode(locked, "ClaimJobs", "SELECT"))
+
+	t.Run("func opt overrides context", func(t *testing.T) {
+		assert.Equal(t, RowLockForUpdateSkipLocked, ResolveRowLockMode(locked, "ClaimJobs", "SELECT", WithRowLockMode(RowLockForUpdateSkipLocked)))
+		assert.Equal(t, RowLockNone, ResolveRowLockMode(locked, "ClaimJobs", "SELECT", WithRowLockMode(RowLockNone)))
+	})
+
+	t.Run("registered with WithConfig", func(t *testing.T) {
+		configured := WithConfig(ctx, "select:Claim*", WithRowLockMode(RowLockForShareNoWait))
+		assert.Equal(t, RowLockForShareNoWait, ResolveRowLockMode(configured, "ClaimJobs", "SELECT"))
+		assert.Equal(t, RowLockNone, ResolveRowLockMode(configured, "ListJobs", "SELECT"))
+	})
+}
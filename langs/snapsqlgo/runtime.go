@@ -128,6 +128,18 @@ type FuncConfig struct {
 	RuntimeOffset        *int
 	AllowNoWhereUpdate   bool
 	AllowNoWhereDelete   bool
+	StreamFetchSize      int
+	Route                ExecutorRoute
+	Retry                *RetryPolicy
+	IncludeDeleted       bool
+	AllowNoLimitSelect   bool
+	TxPropagation        TxPropagation
+	RowLock              *RowLockMode
+	Savepoint            bool
+	DryRun               bool
+	Timeout              *time.Duration
+	MaxRows              *int
+	ReadOnly             *bool
 }
 
 // LogFormat defines the output format for logs
@@ -294,6 +306,20 @@ func GetFunctionConfig(ctx context.Context, funcName string, queryType string) *
 	return matchFunctionConfig(configMap, funcName, queryType)
 }
 
+// resolveFuncConfig applies per-call options on top of the configuration registered with WithConfig.
+func resolveFuncConfig(ctx context.Context, funcName, queryType string, opts []FuncOpt) FuncConfig {
+	config := FuncConfig{}
+	if registered := GetFunctionConfig(ctx, funcName, queryType); registered != nil {
+		config = *registered
+	}
+
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return config
+}
+
 // matchFunctionConfig finds the best matching configuration for a function
 func matchFunctionConfig(configMap map[string]*FuncConfig, funcName string, queryType string) *FuncConfig {
 	// 1. Exact match
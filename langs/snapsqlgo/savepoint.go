@@ -0,0 +1,118 @@
+package snapsqlgo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrSavepointNotSupported is returned when savepoints are requested for a dialect without them.
+var ErrSavepointNotSupported = errors.New("snapsqlgo: savepoints are not supported for this dialect")
+
+// savepointSeq numbers savepoints so that nested WithSavepoint calls never reuse a name.
+var savepointSeq atomic.Uint64
+
+// WithSavepointScope makes a generated mutation function run its statement inside a savepoint
+// when it is called with a *sql.Tx, so that a failure rolls back only this statement and the
+// surrounding transaction stays usable. It only takes effect in code generated with the
+// `transactions: true` Go generator setting and is ignored outside a transaction.
+func WithSavepointScope() FuncOpt {
+	return func(config *FuncConfig) {
+		config.Savepoint = true
+	}
+}
+
+// ResolveSavepoint is called by generated mutation functions. It reports whether the statement
+// has to run inside a savepoint: WithSavepointScope is set and executor is a *sql.Tx.
+func ResolveSavepoint(ctx context.Context, executor DBExecutor, funcName, statementType string, opts ...FuncOpt) bool {
+	if _, inTx := executor.(*sql.Tx); !inTx {
+		return false
+	}
+
+	config := resolveFuncConfig(ctx, funcName, strings.ToLower(statementType), opts)
+
+	return config.Savepoint && !config.DryRun
+}
+
+// WithSavepoint runs fn inside a savepoint of tx. The savepoint is released when fn returns nil;
+// when fn returns an error or panics, the work done by fn is rolled back to the savepoint and
+// the transaction can continue. tx must be a *sql.Tx, otherwise ErrTxRequired is returned.
+// Calls can be nested. The SAVEPOINT / ROLLBACK TO SAVEPOINT / RELEASE SAVEPOINT statements
+// are shared by PostgreSQL, CockroachDB, MySQL, MariaDB and SQLite.
+func WithSavepoint(ctx context.Context, tx DBExecutor, fn func(ctx context.Context, tx DBExecutor) error) error {
+	_, err := runInSavepoint(ctx, tx, "", func(tx DBExecutor) (struct{}, error) {
+		return struct{}{}, fn(ctx, tx)
+	})
+
+	return err
+}
+
+// RunFuncInSavepoint is the generic counterpart of WithSavepoint used by generated functions.
+// dialect is the dialect of the generated code; dialects without savepoints fail with
+// ErrSavepointNotSupported before any statement is sent.
+func RunFuncInSavepoint[T any](ctx context.Context, tx DBExecutor, dialect string, fn func(tx DBExecutor) (T, error)) (T, error) {
+	return runInSavepoint(ctx, tx, dialect, fn)
+}
+
+func runInSavepoint[T any](ctx context.Context, executor DBExecutor, dialect string, fn func(tx DBExecutor) (T, error)) (result T, err error) {
+	if err := validateSavepointDialect(dialect); err != nil {
+		return result, err
+	}
+
+	tx, ok := executor.(*sql.Tx)
+	if !ok {
+		return result, fmt.Errorf("%w: savepoints need a *sql.Tx, got %T", ErrTxRequired, executor)
+	}
+
+	name := fmt.Sprintf("snapsql_sp_%d", savepointSeq.Add(1))
+
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return result, fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			_, _ = tx.ExecContext(context.WithoutCancel(ctx), "ROLLBACK TO SAVEPOINT "+name)
+			panic(recovered)
+		}
+	}()
+
+	result, err = fn(tx)
+
+	return result, finishSavepoint(ctx, tx, name, err)
+}
+
+// finishSavepoint releases the savepoint when err is nil and rolls back to it otherwise.
+func finishSavepoint(ctx context.Context, tx *sql.Tx, name string, err error) error {
+	if err != nil {
+		// Roll back even when ctx is already canceled so that the transaction stays usable
+		rollbackCtx := context.WithoutCancel(ctx)
+		if _, rollbackErr := tx.ExecContext(rollbackCtx, "ROLLBACK TO SAVEPOINT "+name); rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to roll back to savepoint: %w", rollbackErr))
+		}
+
+		if _, releaseErr := tx.ExecContext(rollbackCtx, "RELEASE SAVEPOINT "+name); releaseErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to release savepoint: %w", releaseErr))
+		}
+
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+
+	return nil
+}
+
+func validateSavepointDialect(dialect string) error {
+	switch strings.ToLower(dialect) {
+	case "", "postgres", "cockroach", "mysql", "mariadb", "sqlite":
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s", ErrSavepointNotSupported, dialect)
+}
@@ -0,0 +1,21 @@
+package snapsqlgo
+
+import (
+	"context"
+	"strings"
+)
+
+// WithDeleted disables the soft delete filter that generated functions append for tables
+// configured with tables.<name>.soft_delete, so soft-deleted rows are returned (or, for
+// rewritten DELETE statements, deleted again).
+func WithDeleted() FuncOpt {
+	return func(config *FuncConfig) {
+		config.IncludeDeleted = true
+	}
+}
+
+// IncludeDeleted reports whether the soft delete filter is disabled for funcName by WithDeleted,
+// either passed per call or registered with WithConfig.
+func IncludeDeleted(ctx context.Context, funcName, statementType string, opts ...FuncOpt) bool {
+	return resolveFuncConfig(ctx, funcName, strings.ToLower(statementType), opts).IncludeDeleted
+}
@@ -0,0 +1,17 @@
+package snapsqlgo_test
+
+import (
+	"testing"
+
+	snapsqlgo "github.com/shibukawa/snapsql/langs/snapsqlgo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncludeDeleted(t *testing.T) {
+	assert.False(t, snapsqlgo.IncludeDeleted(t.Context(), "ListUsers", "select"))
+	assert.True(t, snapsqlgo.IncludeDeleted(t.Context(), "ListUsers", "select", snapsqlgo.WithDeleted()))
+
+	ctx := snapsqlgo.WithConfig(t.Context(), "select:ListDeleted*", snapsqlgo.WithDeleted())
+	assert.True(t, snapsqlgo.IncludeDeleted(ctx, "ListDeletedUsers", "SELECT"))
+	assert.False(t, snapsqlgo.IncludeDeleted(ctx, "ListUsers", "select"))
+}
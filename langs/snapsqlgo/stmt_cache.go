@@ -0,0 +1,284 @@
+package snapsqlgo
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+// DefaultStmtCacheCapacity is the number of statements kept by NewStmtCache when capacity <= 0.
+const DefaultStmtCacheCapacity = 256
+
+// StmtCache is an LRU cache of prepared statements keyed by executor and SQL text. Register it
+// with WithStmtCache so generated functions reuse statements instead of preparing on every call.
+// Only *sql.DB and *sql.Conn executors are cached; statements of a *sql.Tx end with the
+// transaction and are always prepared per call.
+//
+// Statements of a *sql.Conn or *sql.DB that has been closed are dropped the first time they
+// report sql.ErrConnDone or "database is closed". Call Invalidate before closing an executor
+// to release its statements eagerly, and Close when the cache is no longer used.
+type StmtCache struct {
+	capacity int
+
+	mu      sync.Mutex
+	entries map[stmtCacheKey]*list.Element
+	lru     *list.List // front is most recently used; values are *stmtCacheEntry
+	stats   StmtCacheStats
+}
+
+// StmtCacheStats reports the activity of a StmtCache.
+type StmtCacheStats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Size      int
+}
+
+type stmtCacheKey struct {
+	executor DBExecutor
+	query    string
+}
+
+type stmtCacheEntry struct {
+	key     stmtCacheKey
+	stmt    *sql.Stmt
+	refs    int
+	removed bool
+}
+
+// NewStmtCache creates a StmtCache holding up to capacity statements.
+func NewStmtCache(capacity int) *StmtCache {
+	if capacity <= 0 {
+		capacity = DefaultStmtCacheCapacity
+	}
+
+	return &StmtCache{
+		capacity: capacity,
+		entries:  make(map[stmtCacheKey]*list.Element),
+		lru:      list.New(),
+	}
+}
+
+// WithStmtCache stores the statement cache on the execution context. Passing nil disables caching.
+func WithStmtCache(ctx context.Context, cache *StmtCache) context.Context {
+	ctx, ec := withExecutionContext(ctx)
+	ec.stmtCache = cache
+
+	return ctx
+}
+
+// Stats returns a snapshot of the cache counters.
+func (c *StmtCache) Stats() StmtCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Size = c.lru.Len()
+
+	return stats
+}
+
+// Invalidate closes and drops every statement prepared on executor. Statements still in use are
+// closed when their current call finishes.
+func (c *StmtCache) Invalidate(executor DBExecutor) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+
+	for key, elem := range c.entries {
+		if key.executor == executor {
+			errs = append(errs, c.removeLocked(elem))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close closes and drops every cached statement.
+func (c *StmtCache) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var errs []error
+
+	for _, elem := range c.entries {
+		errs = append(errs, c.removeLocked(elem))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c *StmtCache) acquire(ctx context.Context, executor DBExecutor, query string) (*stmtCacheEntry, error) {
+	key := stmtCacheKey{executor: executor, query: query}
+
+	c.mu.Lock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(elem)
+
+		entry := elem.Value.(*stmtCacheEntry) //nolint:forcetypeassert // the list only holds entries
+		entry.refs++
+		c.stats.Hits++
+		c.mu.Unlock()
+
+		return entry, nil
+	}
+
+	c.stats.Misses++
+	c.mu.Unlock()
+
+	// Prepare without holding the lock; a concurrent miss for the same key keeps the first entry.
+	stmt, err := executor.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		_ = stmt.Close()
+
+		entry := elem.Value.(*stmtCacheEntry) //nolint:forcetypeassert // the list only holds entries
+		entry.refs++
+
+		return entry, nil
+	}
+
+	entry := &stmtCacheEntry{key: key, stmt: stmt, refs: 1}
+	c.entries[key] = c.lru.PushFront(entry)
+
+	for c.lru.Len() > c.capacity {
+		_ = c.removeLocked(c.lru.Back())
+		c.stats.Evictions++
+	}
+
+	return entry, nil
+}
+
+func (c *StmtCache) release(entry *stmtCacheEntry, err error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry.refs--
+
+	if isClosedExecutorError(err) && !entry.removed {
+		if elem, ok := c.entries[entry.key]; ok && elem.Value == entry {
+			return c.removeLocked(elem)
+		}
+	}
+
+	if entry.removed && entry.refs == 0 {
+		return entry.stmt.Close()
+	}
+
+	return nil
+}
+
+// removeLocked drops elem from the cache and closes its statement unless it is still in use.
+func (c *StmtCache) removeLocked(elem *list.Element) error {
+	entry := elem.Value.(*stmtCacheEntry) //nolint:forcetypeassert // the list only holds entries
+
+	c.lru.Remove(elem)
+	delete(c.entries, entry.key)
+	entry.removed = true
+
+	if entry.refs == 0 {
+		return entry.stmt.Close()
+	}
+
+	return nil
+}
+
+func isClosedExecutorError(err error) bool {
+	return err != nil && (errors.Is(err, sql.ErrConnDone) || err.Error() == "sql: database is closed")
+}
+
+// Stmt is a prepared statement used by generated code. It is either prepared for a single call
+// or borrowed from the StmtCache registered with WithStmtCache; Close closes or returns it.
+type Stmt struct {
+	stmt   *sql.Stmt
+	cache  *StmtCache
+	entry  *stmtCacheEntry
+	err    error
+	closed bool
+}
+
+// PrepareContext prepares query on executor, reusing a cached statement when a StmtCache is
+// registered on ctx and executor is a *sql.DB or *sql.Conn.
+func PrepareContext(ctx context.Context, executor DBExecutor, query string) (*Stmt, error) {
+	var cache *StmtCache
+	if ec := ExtractExecutionContext(ctx); ec != nil {
+		cache = ec.stmtCache
+	}
+
+	switch executor.(type) {
+	case *sql.DB, *sql.Conn:
+	default:
+		cache = nil
+	}
+
+	if cache == nil {
+		stmt, err := executor.PrepareContext(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+
+		return &Stmt{stmt: stmt}, nil
+	}
+
+	entry, err := cache.acquire(ctx, executor, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Stmt{stmt: entry.stmt, cache: cache, entry: entry}, nil
+}
+
+// ExecContext executes the statement with args.
+func (s *Stmt) ExecContext(ctx context.Context, args ...any) (sql.Result, error) {
+	result, err := s.stmt.ExecContext(ctx, args...)
+	s.record(err)
+
+	return result, err
+}
+
+// QueryContext runs the statement with args and returns the rows.
+func (s *Stmt) QueryContext(ctx context.Context, args ...any) (*sql.Rows, error) {
+	rows, err := s.stmt.QueryContext(ctx, args...)
+	s.record(err)
+
+	return rows, err
+}
+
+// QueryRowContext runs the statement with args and returns at most one row.
+func (s *Stmt) QueryRowContext(ctx context.Context, args ...any) *sql.Row {
+	row := s.stmt.QueryRowContext(ctx, args...)
+	s.record(row.Err())
+
+	return row
+}
+
+// Close closes a per-call statement or returns a cached one to its StmtCache.
+func (s *Stmt) Close() error {
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+
+	if s.cache == nil {
+		return s.stmt.Close()
+	}
+
+	return s.cache.release(s.entry, s.err)
+}
+
+func (s *Stmt) record(err error) {
+	if isClosedExecutorError(err) {
+		s.err = err
+	}
+}
@@ -0,0 +1,118 @@
+package snapsqlgo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	snapsqlgo "github.com/shibukawa/snapsql/langs/snapsqlgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func countItems(t *testing.T, ctx context.Context, executor snapsqlgo.DBExecutor, query string) int {
+	t.Helper()
+
+	stmt, err := snapsqlgo.PrepareContext(ctx, executor, query)
+	require.NoError(t, err)
+
+	defer stmt.Close()
+
+	var count int
+	require.NoError(t, stmt.QueryRowContext(ctx).Scan(&count))
+
+	return count
+}
+
+func TestStmtCache(t *testing.T) {
+	db := openStreamTestDB(t, 3)
+
+	t.Run("reuses statements", func(t *testing.T) {
+		cache := snapsqlgo.NewStmtCache(2)
+		t.Cleanup(func() { cache.Close() })
+
+		ctx := snapsqlgo.WithStmtCache(t.Context(), cache)
+
+		for range 3 {
+			assert.Equal(t, 3, countItems(t, ctx, db, "SELECT COUNT(*) FROM items"))
+		}
+
+		assert.Equal(t, snapsqlgo.StmtCacheStats{Hits: 2, Misses: 1, Size: 1}, cache.Stats())
+	})
+
+	t.Run("evicts least recently used", func(t *testing.T) {
+		cache := snapsqlgo.NewStmtCache(2)
+		t.Cleanup(func() { cache.Close() })
+
+		ctx := snapsqlgo.WithStmtCache(t.Context(), cache)
+
+		countItems(t, ctx, db, "SELECT COUNT(*) FROM items")
+		countItems(t, ctx, db, "SELECT COUNT(*) FROM items WHERE id > 1")
+		countItems(t, ctx, db, "SELECT COUNT(*) FROM items")
+		countItems(t, ctx, db, "SELECT COUNT(*) FROM items WHERE id > 2")
+		assert.Equal(t, 1, countItems(t, ctx, db, "SELECT COUNT(*) FROM items WHERE id > 2"))
+		countItems(t, ctx, db, "SELECT COUNT(*) FROM items")
+
+		assert.Equal(t, snapsqlgo.StmtCacheStats{Hits: 3, Misses: 3, Evictions: 1, Size: 2}, cache.Stats())
+	})
+
+	t.Run("does not cache transactions", func(t *testing.T) {
+		cache := snapsqlgo.NewStmtCache(0)
+		t.Cleanup(func() { cache.Close() })
+
+		ctx := snapsqlgo.WithStmtCache(t.Context(), cache)
+
+		tx, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = tx.Rollback() })
+
+		countItems(t, ctx, tx, "SELECT COUNT(*) FROM items")
+		assert.Equal(t, snapsqlgo.StmtCacheStats{}, cache.Stats())
+	})
+
+	t.Run("invalidates closed connections", func(t *testing.T) {
+		cache := snapsqlgo.NewStmtCache(0)
+		t.Cleanup(func() { cache.Close() })
+
+		ctx := snapsqlgo.WithStmtCache(t.Context(), cache)
+
+		conn, err := db.Conn(ctx)
+		require.NoError(t, err)
+
+		countItems(t, ctx, conn, "SELECT COUNT(*) FROM items")
+		require.NoError(t, conn.Close())
+
+		stmt, err := snapsqlgo.PrepareContext(ctx, conn, "SELECT COUNT(*) FROM items")
+		require.NoError(t, err)
+
+		_, err = stmt.QueryContext(ctx)
+		require.ErrorIs(t, err, sql.ErrConnDone)
+		require.NoError(t, stmt.Close())
+
+		assert.Equal(t, 0, cache.Stats().Size)
+	})
+
+	t.Run("invalidate drops executor statements", func(t *testing.T) {
+		cache := snapsqlgo.NewStmtCache(0)
+		t.Cleanup(func() { cache.Close() })
+
+		ctx := snapsqlgo.WithStmtCache(t.Context(), cache)
+		countItems(t, ctx, db, "SELECT COUNT(*) FROM items")
+
+		// A statement that is still borrowed stays usable until it is returned.
+		stmt, err := snapsqlgo.PrepareContext(ctx, db, "SELECT COUNT(*) FROM items")
+		require.NoError(t, err)
+		require.NoError(t, cache.Invalidate(db))
+
+		var count int
+		require.NoError(t, stmt.QueryRowContext(ctx).Scan(&count))
+		assert.Equal(t, 3, count)
+		require.NoError(t, stmt.Close())
+
+		assert.Equal(t, 0, cache.Stats().Size)
+	})
+
+	t.Run("without cache", func(t *testing.T) {
+		assert.Equal(t, 3, countItems(t, context.Background(), db, "SELECT COUNT(*) FROM items"))
+	})
+}
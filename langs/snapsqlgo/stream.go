@@ -0,0 +1,267 @@
+package snapsqlgo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultStreamFetchSize is the number of rows fetched per round trip when WithStreaming is given a non-positive size.
+const DefaultStreamFetchSize = 1000
+
+var (
+	// ErrStreamClosed is returned when a closed RowStream is read.
+	ErrStreamClosed = errors.New("row stream is closed")
+	// ErrStreamRequiresTransaction is returned when PostgreSQL cursor streaming is requested with an
+	// executor that is neither a transaction nor able to begin one.
+	ErrStreamRequiresTransaction = errors.New("cursor streaming requires a *sql.Tx or an executor that can begin one (*sql.DB, *sql.Conn)")
+)
+
+var streamCursorSeq atomic.Uint64
+
+// StreamOptions controls how generated iterator functions read their result set.
+type StreamOptions struct {
+	// Dialect is the database dialect of the generated code (postgres, mysql, mariadb, sqlite).
+	Dialect string
+	// FetchSize enables streaming when positive. PostgreSQL reads through a server-side cursor
+	// FetchSize rows at a time; other dialects send the query without preparing it and read the
+	// rows one by one from the connection.
+	FetchSize int
+	// Retry retries opening the result set; rows already yielded are never read twice.
+	Retry RetryOptions
+}
+
+// Streaming reports whether streaming mode is enabled.
+func (o StreamOptions) Streaming() bool {
+	return o.FetchSize > 0
+}
+
+// WithStreaming makes iterator functions stream very large result sets instead of reading them
+// through a prepared statement. fetchSize <= 0 uses DefaultStreamFetchSize.
+//
+// On PostgreSQL the query runs as a cursor (DECLARE ... / FETCH n), which needs a transaction:
+// a *sql.Tx is used as is, and when the executor is a *sql.DB or *sql.Conn a read-only transaction
+// is opened for the lifetime of the iterator. Any other executor fails with
+// ErrStreamRequiresTransaction.
+//
+// On MySQL, MariaDB and SQLite the query is sent without a prepared statement (and bypasses the
+// statement cache), and each row is read from the connection as the iterator advances, so the
+// result set is never held in memory. fetchSize has no effect there. The connection stays busy
+// until the iterator finishes, so do not run other queries on the same *sql.Conn or *sql.Tx from
+// inside the loop.
+func WithStreaming(fetchSize int) FuncOpt {
+	return func(config *FuncConfig) {
+		if fetchSize <= 0 {
+			fetchSize = DefaultStreamFetchSize
+		}
+
+		config.StreamFetchSize = fetchSize
+	}
+}
+
+// ResolveStreamOptions merges the configuration registered with WithConfig for funcName and the
+// per-call options into the streaming settings used by generated iterator functions.
+func ResolveStreamOptions(ctx context.Context, funcName, dialect string, opts ...FuncOpt) StreamOptions {
+	config := resolveFuncConfig(ctx, funcName, "select", opts)
+
+	return StreamOptions{
+		Dialect:   strings.ToLower(dialect),
+		FetchSize: config.StreamFetchSize,
+		Retry: RetryOptions{
+			FuncName: funcName,
+			Dialect:  strings.ToLower(dialect),
+			Policy:   config.Retry,
+		},
+	}
+}
+
+// txBeginner is implemented by *sql.DB and *sql.Conn.
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// RowStream is the row source of generated iterator functions. Its Next/Scan/Err/Close methods
+// mirror *sql.Rows; in PostgreSQL streaming mode it transparently fetches the next batch from
+// the cursor when the current one is exhausted.
+type RowStream struct {
+	ctx  context.Context
+	rows *sql.Rows
+	stmt *Stmt
+
+	// cursor state (PostgreSQL streaming mode only)
+	executor  DBExecutor
+	cursor    string
+	fetchSize int
+	fetched   int
+	ownedTx   *sql.Tx
+
+	err    error
+	closed bool
+}
+
+// QueryStream runs query and returns its rows as a RowStream. Without streaming the statement is
+// prepared and executed as before; see WithStreaming.
+// Opening the result set is retried according to opts.Retry.
+func QueryStream(ctx context.Context, executor DBExecutor, query string, args []any, opts StreamOptions) (*RowStream, error) {
+	return Retry(ctx, opts.Retry, executor, func(ctx context.Context) (*RowStream, error) {
+		return queryStream(ctx, executor, query, args, opts)
+	})
+}
+
+func queryStream(ctx context.Context, executor DBExecutor, query string, args []any, opts StreamOptions) (*RowStream, error) {
+	if !opts.Streaming() {
+		stmt, err := PrepareContext(ctx, executor, query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare statement: %w (query: %s)", err, query)
+		}
+
+		rows, err := stmt.QueryContext(ctx, args...)
+		if err != nil {
+			stmt.Close()
+			return nil, err
+		}
+
+		return &RowStream{ctx: ctx, rows: rows, stmt: stmt}, nil
+	}
+
+	if opts.Dialect != "postgres" && opts.Dialect != "postgresql" && opts.Dialect != "cockroach" {
+		// Unprepared queries read rows from the connection one at a time as Next advances
+		rows, err := executor.QueryContext(ctx, query, args...)
+		if err != nil {
+			return nil, err
+		}
+
+		return &RowStream{ctx: ctx, rows: rows}, nil
+	}
+
+	stream := &RowStream{
+		ctx:       ctx,
+		executor:  executor,
+		cursor:    fmt.Sprintf("snapsql_stream_%d", streamCursorSeq.Add(1)),
+		fetchSize: opts.FetchSize,
+	}
+
+	switch e := executor.(type) {
+	case *sql.Tx:
+		// The caller's transaction keeps the cursor open
+	case txBeginner:
+		tx, err := e.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to begin streaming transaction: %w", err)
+		}
+
+		stream.ownedTx = tx
+		stream.executor = tx
+	default:
+		return nil, fmt.Errorf("%w: got %T", ErrStreamRequiresTransaction, executor)
+	}
+
+	if _, err := stream.executor.ExecContext(ctx, "DECLARE "+stream.cursor+" NO SCROLL CURSOR FOR "+query, args...); err != nil {
+		stream.rollback()
+		return nil, fmt.Errorf("failed to declare cursor: %w", err)
+	}
+
+	if err := stream.fetch(); err != nil {
+		stream.rollback()
+		return nil, err
+	}
+
+	return stream, nil
+}
+
+// Next prepares the next row for Scan. It returns false at the end of the result set or on error.
+func (s *RowStream) Next() bool {
+	if s.closed || s.err != nil {
+		return false
+	}
+
+	for {
+		if s.rows.Next() {
+			s.fetched++
+			return true
+		}
+
+		if err := s.rows.Err(); err != nil {
+			s.err = err
+			return false
+		}
+
+		// A short batch means the cursor is exhausted.
+		if s.cursor == "" || s.fetched < s.fetchSize {
+			return false
+		}
+
+		s.rows.Close()
+
+		if err := s.fetch(); err != nil {
+			s.err = err
+			return false
+		}
+	}
+}
+
+// Scan copies the columns of the current row into dest.
+func (s *RowStream) Scan(dest ...any) error {
+	if s.closed {
+		return ErrStreamClosed
+	}
+
+	return s.rows.Scan(dest...)
+}
+
+// Err returns the error, if any, encountered while iterating.
+func (s *RowStream) Err() error {
+	return s.err
+}
+
+// Close releases the rows, the prepared statement and, in PostgreSQL streaming mode, the cursor
+// and the transaction opened for it.
+func (s *RowStream) Close() error {
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+
+	var errs []error
+
+	if s.rows != nil {
+		errs = append(errs, s.rows.Close())
+	}
+
+	if s.stmt != nil {
+		errs = append(errs, s.stmt.Close())
+	}
+
+	if s.cursor != "" {
+		if s.ownedTx != nil {
+			// Ending the transaction closes the cursor as well.
+			errs = append(errs, s.ownedTx.Commit())
+		} else if _, err := s.executor.ExecContext(context.WithoutCancel(s.ctx), "CLOSE "+s.cursor); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close cursor: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *RowStream) fetch() error {
+	rows, err := s.executor.QueryContext(s.ctx, fmt.Sprintf("FETCH FORWARD %d FROM %s", s.fetchSize, s.cursor))
+	if err != nil {
+		return fmt.Errorf("failed to fetch from cursor: %w", err)
+	}
+
+	s.rows = rows
+	s.fetched = 0
+
+	return nil
+}
+
+func (s *RowStream) rollback() {
+	if s.ownedTx != nil {
+		_ = s.ownedTx.Rollback()
+	}
+}
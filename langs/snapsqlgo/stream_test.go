@@ -0,0 +1,159 @@
+package snapsqlgo_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	snapsqlgo "github.com/shibukawa/snapsql/langs/snapsqlgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openStreamTestDB(t *testing.T, rows int) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE items (id INTEGER PRIMARY KEY)")
+	require.NoError(t, err)
+
+	for i := 1; i <= rows; i++ {
+		_, err = db.Exec("INSERT INTO items (id) VALUES (?)", i)
+		require.NoError(t, err)
+	}
+
+	return db
+}
+
+func collectStream(t *testing.T, stream *snapsqlgo.RowStream) []int {
+	t.Helper()
+
+	var ids []int
+
+	for stream.Next() {
+		var id int
+		require.NoError(t, stream.Scan(&id))
+
+		ids = append(ids, id)
+	}
+
+	require.NoError(t, stream.Err())
+	require.NoError(t, stream.Close())
+
+	return ids
+}
+
+func TestResolveStreamOptions(t *testing.T) {
+	ctx := context.Background()
+
+	opts := snapsqlgo.ResolveStreamOptions(ctx, "ListItems", "PostgreSQL")
+	assert.False(t, opts.Streaming())
+	assert.Equal(t, "postgresql", opts.Dialect)
+
+	opts = snapsqlgo.ResolveStreamOptions(ctx, "ListItems", "postgres", snapsqlgo.WithStreaming(0))
+	assert.Equal(t, snapsqlgo.DefaultStreamFetchSize, opts.FetchSize)
+
+	ctx = snapsqlgo.WithConfig(ctx, "List*", snapsqlgo.WithStreaming(200))
+	assert.Equal(t, 200, snapsqlgo.ResolveStreamOptions(ctx, "ListItems", "postgres").FetchSize)
+	assert.Equal(t, 50, snapsqlgo.ResolveStreamOptions(ctx, "ListItems", "postgres", snapsqlgo.WithStreaming(50)).FetchSize)
+	assert.Equal(t, 0, snapsqlgo.ResolveStreamOptions(ctx, "GetItem", "postgres").FetchSize)
+}
+
+func TestQueryStreamReadsAllRows(t *testing.T) {
+	db := openStreamTestDB(t, 25)
+	ctx := context.Background()
+	query := "SELECT id FROM items WHERE id > ? ORDER BY id"
+
+	buffered, err := snapsqlgo.QueryStream(ctx, db, query, []any{5}, snapsqlgo.StreamOptions{Dialect: "sqlite"})
+	require.NoError(t, err)
+	assert.Len(t, collectStream(t, buffered), 20)
+
+	streamed, err := snapsqlgo.QueryStream(ctx, db, query, []any{5}, snapsqlgo.StreamOptions{Dialect: "sqlite", FetchSize: 7})
+	require.NoError(t, err)
+
+	ids := collectStream(t, streamed)
+	require.Len(t, ids, 20)
+	assert.Equal(t, 6, ids[0])
+	assert.Equal(t, 25, ids[19])
+
+	assert.ErrorIs(t, streamed.Scan(new(int)), snapsqlgo.ErrStreamClosed)
+}
+
+func TestQueryStreamPostgresRequiresTransaction(t *testing.T) {
+	_, err := snapsqlgo.QueryStream(context.Background(), failingExecutor{}, "SELECT 1", nil, snapsqlgo.StreamOptions{Dialect: "postgres", FetchSize: 10})
+	require.ErrorIs(t, err, snapsqlgo.ErrStreamRequiresTransaction)
+}
+
+func TestQueryStreamPostgresDeclareError(t *testing.T) {
+	db := openStreamTestDB(t, 0)
+	ctx := context.Background()
+	opts := snapsqlgo.StreamOptions{Dialect: "postgres", FetchSize: 10}
+
+	// SQLite rejects DECLARE, which exercises both the owned and the caller's transaction paths
+	_, err := snapsqlgo.QueryStream(ctx, db, "SELECT 1", nil, opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to declare cursor")
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+
+	defer tx.Rollback()
+
+	_, err = snapsqlgo.QueryStream(ctx, tx, "SELECT 1", nil, opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to declare cursor")
+}
+
+// countingExecutor records how queries reach the underlying executor
+type countingExecutor struct {
+	snapsqlgo.DBExecutor
+	prepares int
+	queries  int
+}
+
+func (c *countingExecutor) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	c.prepares++
+	return c.DBExecutor.PrepareContext(ctx, query)
+}
+
+func (c *countingExecutor) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	c.queries++
+	return c.DBExecutor.QueryContext(ctx, query, args...)
+}
+
+func TestQueryStreamReadsRowsUnpreparedWhenStreaming(t *testing.T) {
+	db := openStreamTestDB(t, 10)
+	ctx := context.Background()
+	query := "SELECT id FROM items ORDER BY id"
+
+	for _, dialect := range []string{"mysql", "mariadb", "sqlite"} {
+		executor := &countingExecutor{DBExecutor: db}
+
+		stream, err := snapsqlgo.QueryStream(ctx, executor, query, nil, snapsqlgo.StreamOptions{Dialect: dialect, FetchSize: 3})
+		require.NoError(t, err)
+
+		// Rows are read one at a time; closing early leaves the rest unread
+		require.True(t, stream.Next())
+
+		var id int
+		require.NoError(t, stream.Scan(&id))
+		assert.Equal(t, 1, id)
+		require.NoError(t, stream.Close())
+
+		assert.Equal(t, 0, executor.prepares, dialect)
+		assert.Equal(t, 1, executor.queries, dialect)
+	}
+
+	executor := &countingExecutor{DBExecutor: db}
+
+	buffered, err := snapsqlgo.QueryStream(ctx, executor, query, nil, snapsqlgo.StreamOptions{Dialect: "mysql"})
+	require.NoError(t, err)
+	assert.Len(t, collectStream(t, buffered), 10)
+	assert.Equal(t, 1, executor.prepares)
+}
@@ -0,0 +1,103 @@
+package snapsqlgo
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation scope of spans created by generated query functions.
+const TracerName = "github.com/shibukawa/snapsql/langs/snapsqlgo"
+
+// Span attribute keys recorded by generated query functions.
+const (
+	AttrDBSystem       = attribute.Key("db.system.name")
+	AttrDBOperation    = attribute.Key("db.operation.name")
+	AttrReturnedRows   = attribute.Key("db.response.returned_rows")
+	AttrRowsAffected   = attribute.Key("snapsql.rows_affected")
+	AttrSnapSQLDialect = attribute.Key("snapsql.dialect")
+)
+
+type tracingConfig struct {
+	provider trace.TracerProvider
+}
+
+// WithTracerProvider makes generated functions create spans with provider instead of the
+// global provider returned by otel.GetTracerProvider. Passing nil restores the global provider.
+func WithTracerProvider(ctx context.Context, provider trace.TracerProvider) context.Context {
+	ctx, ec := withExecutionContext(ctx)
+
+	if provider == nil {
+		ec.tracing = nil
+		return ctx
+	}
+
+	ec.tracing = &tracingConfig{provider: provider}
+
+	return ctx
+}
+
+// QuerySpan is the span of a single generated query function call.
+type QuerySpan struct {
+	span trace.Span
+}
+
+// StartQuerySpan starts a client span named after the generated function. It is emitted by
+// code generated with the Go generator's `tracing: true` setting. With the default no-op
+// global provider the span is not recording and no attributes are built.
+func StartQuerySpan(ctx context.Context, funcName, dialect, statementType string) (context.Context, *QuerySpan) {
+	provider := otel.GetTracerProvider()
+	if ec := ExtractExecutionContext(ctx); ec != nil && ec.tracing != nil {
+		provider = ec.tracing.provider
+	}
+
+	ctx, span := provider.Tracer(TracerName).Start(ctx, funcName, trace.WithSpanKind(trace.SpanKindClient))
+	if span.IsRecording() {
+		span.SetAttributes(
+			AttrDBSystem.String(dbSystemName(dialect)),
+			AttrSnapSQLDialect.String(dialect),
+			AttrDBOperation.String(strings.ToUpper(statementType)),
+		)
+	}
+
+	return ctx, &QuerySpan{span: span}
+}
+
+// SetRowsAffected records the number of rows changed by a statement.
+func (s *QuerySpan) SetRowsAffected(n int64) {
+	if s.span.IsRecording() {
+		s.span.SetAttributes(AttrRowsAffected.Int64(n))
+	}
+}
+
+// SetReturnedRows records the number of rows returned to the caller.
+func (s *QuerySpan) SetReturnedRows(n int64) {
+	if s.span.IsRecording() {
+		s.span.SetAttributes(AttrReturnedRows.Int64(n))
+	}
+}
+
+// End marks the span as failed when err is non-nil and ends it.
+func (s *QuerySpan) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+
+	s.span.End()
+}
+
+func dbSystemName(dialect string) string {
+	switch strings.ToLower(dialect) {
+	case "postgres", "postgresql":
+		return "postgresql"
+	case "cockroach":
+		return "cockroachdb"
+	default:
+		return strings.ToLower(dialect)
+	}
+}
@@ -0,0 +1,107 @@
+package snapsqlgo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	snapsqlgo "github.com/shibukawa/snapsql/langs/snapsqlgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+type recordingProvider struct {
+	noop.TracerProvider
+
+	spans []*recordingSpan
+}
+
+func (p *recordingProvider) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return &recordingTracer{provider: p}
+}
+
+type recordingTracer struct {
+	noop.Tracer
+
+	provider *recordingProvider
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, _ ...trace.SpanStartOption) (context.Context, trace.Span) {
+	span := &recordingSpan{name: name, attrs: map[attribute.Key]attribute.Value{}}
+	t.provider.spans = append(t.provider.spans, span)
+
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+type recordingSpan struct {
+	noop.Span
+
+	name   string
+	attrs  map[attribute.Key]attribute.Value
+	status codes.Code
+	errs   []error
+	ended  bool
+}
+
+func (s *recordingSpan) IsRecording() bool { return true }
+
+func (s *recordingSpan) SetAttributes(kv ...attribute.KeyValue) {
+	for _, attr := range kv {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *recordingSpan) RecordError(err error, _ ...trace.EventOption) { s.errs = append(s.errs, err) }
+
+func (s *recordingSpan) SetStatus(code codes.Code, _ string) { s.status = code }
+
+func (s *recordingSpan) End(...trace.SpanEndOption) { s.ended = true }
+
+func TestStartQuerySpanRecordsAttributes(t *testing.T) {
+	provider := &recordingProvider{}
+	ctx := snapsqlgo.WithTracerProvider(context.Background(), provider)
+
+	_, span := snapsqlgo.StartQuerySpan(ctx, "DeleteUser", "postgres", "delete")
+	span.SetRowsAffected(3)
+	span.End(nil)
+
+	require.Len(t, provider.spans, 1)
+
+	recorded := provider.spans[0]
+	assert.Equal(t, "DeleteUser", recorded.name)
+	assert.Equal(t, "postgresql", recorded.attrs[snapsqlgo.AttrDBSystem].AsString())
+	assert.Equal(t, "postgres", recorded.attrs[snapsqlgo.AttrSnapSQLDialect].AsString())
+	assert.Equal(t, "DELETE", recorded.attrs[snapsqlgo.AttrDBOperation].AsString())
+	assert.Equal(t, int64(3), recorded.attrs[snapsqlgo.AttrRowsAffected].AsInt64())
+	assert.Equal(t, codes.Unset, recorded.status)
+	assert.True(t, recorded.ended)
+}
+
+func TestQuerySpanEndRecordsError(t *testing.T) {
+	provider := &recordingProvider{}
+	ctx := snapsqlgo.WithTracerProvider(context.Background(), provider)
+
+	spanCtx, span := snapsqlgo.StartQuerySpan(ctx, "ListUsers", "sqlite", "select")
+	assert.Equal(t, provider.spans[0], trace.SpanFromContext(spanCtx))
+
+	queryErr := errors.New("no such table: users")
+	span.SetReturnedRows(0)
+	span.End(queryErr)
+
+	recorded := provider.spans[0]
+	assert.Equal(t, codes.Error, recorded.status)
+	assert.Equal(t, []error{queryErr}, recorded.errs)
+	assert.Equal(t, int64(0), recorded.attrs[snapsqlgo.AttrReturnedRows].AsInt64())
+}
+
+func TestStartQuerySpanDefaultsToGlobalNoop(t *testing.T) {
+	ctx, span := snapsqlgo.StartQuerySpan(context.Background(), "ListUsers", "mysql", "select")
+	span.SetReturnedRows(10)
+	span.End(nil)
+
+	assert.False(t, trace.SpanFromContext(ctx).IsRecording())
+}
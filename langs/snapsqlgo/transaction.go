@@ -0,0 +1,222 @@
+package snapsqlgo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+var (
+	// ErrTxRequired is returned when a function marked with WithTxRequired is called without a *sql.Tx.
+	ErrTxRequired = errors.New("snapsqlgo: transaction required")
+	// ErrTxNotSupported is returned when a transaction has to be begun on an executor that cannot begin one.
+	ErrTxNotSupported = errors.New("snapsqlgo: executor cannot begin a transaction")
+)
+
+// TxPropagation selects how a generated function relates to the transaction of its executor.
+// It only takes effect in code generated with the `transactions: true` Go generator setting.
+type TxPropagation int
+
+const (
+	// TxSupports runs the statement on the executor as given (default).
+	TxSupports TxPropagation = iota
+	// TxMandatory fails fast with ErrTxRequired unless the executor is a *sql.Tx.
+	TxMandatory
+	// TxRequired joins the *sql.Tx passed as executor, or begins a transaction for the call.
+	TxRequired
+	// TxRequiresNew always begins a transaction for the call. database/sql cannot begin a
+	// transaction from a *sql.Tx, so passing one fails with ErrTxNotSupported.
+	TxRequiresNew
+)
+
+// String returns the policy name used in error messages.
+func (p TxPropagation) String() string {
+	switch p {
+	case TxSupports:
+		return "SUPPORTS"
+	case TxMandatory:
+		return "MANDATORY"
+	case TxRequired:
+		return "REQUIRED"
+	case TxRequiresNew:
+		return "REQUIRES_NEW"
+	}
+
+	return fmt.Sprintf("TxPropagation(%d)", int(p))
+}
+
+// WithTxRequired marks a function as requiring an existing transaction: calling it with an
+// executor other than *sql.Tx fails with ErrTxRequired instead of running outside a transaction.
+// Register it with WithConfig (e.g. "update:*") to enforce it for a group of functions.
+func WithTxRequired() FuncOpt {
+	return WithTxPropagation(TxMandatory)
+}
+
+// WithTxPropagation sets the transaction propagation policy of a generated function.
+func WithTxPropagation(propagation TxPropagation) FuncOpt {
+	return func(config *FuncConfig) {
+		config.TxPropagation = propagation
+	}
+}
+
+// ResolveTxPropagation is called by generated functions before they run their statement. It
+// reports whether the function has to begin its own transaction on executor, and fails fast
+// when the propagation policy cannot be satisfied.
+func ResolveTxPropagation(ctx context.Context, executor DBExecutor, funcName, statementType string, opts ...FuncOpt) (bool, error) {
+	config := resolveFuncConfig(ctx, funcName, strings.ToLower(statementType), opts)
+	propagation := config.TxPropagation
+	_, inTx := executor.(*sql.Tx)
+
+	switch propagation {
+	case TxSupports:
+		return false, nil
+	case TxMandatory:
+		if !inTx {
+			return false, fmt.Errorf("%w: %s must be called with a *sql.Tx (use RunInTx)", ErrTxRequired, funcName)
+		}
+
+		return false, nil
+	case TxRequired:
+		if inTx {
+			return false, nil
+		}
+	case TxRequiresNew:
+		if inTx {
+			return false, fmt.Errorf("%w: %s is %s but was called with a *sql.Tx", ErrTxNotSupported, funcName, propagation)
+		}
+	default:
+		return false, fmt.Errorf("snapsqlgo: %s: unknown transaction propagation %s", funcName, propagation)
+	}
+
+	if _, ok := txBeginnerOf(executor); !ok {
+		return false, fmt.Errorf("%w: %s is %s but %T cannot begin a transaction", ErrTxNotSupported, funcName, propagation, executor)
+	}
+
+	// A dry run never touches the executor, so the transaction is not begun
+	return !config.DryRun, nil
+}
+
+// RunInTx runs fn in a transaction. When executor already is a *sql.Tx, fn joins it and the
+// caller keeps control of the commit. Otherwise a transaction is begun on executor (a *sql.DB,
+// *sql.Conn, or the primary of a RoutingExecutor) and committed when fn returns nil; it is
+// rolled back when fn returns an error or panics.
+func RunInTx(ctx context.Context, executor DBExecutor, txOpts *sql.TxOptions, fn func(ctx context.Context, tx DBExecutor) error) error {
+	if tx, ok := executor.(*sql.Tx); ok {
+		return fn(ctx, tx)
+	}
+
+	_, err := RunFuncInTx(ctx, executor, txOpts, func(tx DBExecutor) (struct{}, error) {
+		return struct{}{}, fn(ctx, tx)
+	})
+
+	return err
+}
+
+// RunFuncInTx begins a transaction on executor, runs fn with it and commits when fn succeeds.
+// It is used by generated functions whose propagation policy begins a transaction.
+func RunFuncInTx[T any](ctx context.Context, executor DBExecutor, txOpts *sql.TxOptions, fn func(tx DBExecutor) (T, error)) (result T, err error) {
+	tx, err := beginTx(ctx, executor, txOpts)
+	if err != nil {
+		return result, err
+	}
+
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			_ = tx.Rollback()
+			panic(recovered)
+		}
+	}()
+
+	result, err = fn(tx)
+
+	return result, finishTx(tx, err)
+}
+
+// SeqInTx is the iterator counterpart of RunFuncInTx. The transaction is begun when iteration
+// starts and committed when the rows are exhausted or the consumer stops early; it is rolled
+// back when an error is yielded. seq receives opts with the propagation reset to TxSupports so
+// that the function can be called again with the transaction as executor.
+func SeqInTx[T any](ctx context.Context, executor DBExecutor, opts []FuncOpt, seq func(tx DBExecutor, opts []FuncOpt) iter.Seq2[T, error]) iter.Seq2[T, error] {
+	joined := append(opts[:len(opts):len(opts)], WithTxPropagation(TxSupports))
+
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		tx, err := beginTx(ctx, executor, nil)
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		var seqErr error
+
+		stopped := false
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				_ = tx.Rollback()
+				panic(recovered)
+			}
+
+			if err := finishTx(tx, seqErr); err != nil && seqErr == nil && !stopped {
+				yield(zero, err)
+			}
+		}()
+
+		for item, err := range seq(tx, joined) {
+			if err != nil {
+				seqErr = err
+			}
+
+			if !yield(item, err) {
+				stopped = true
+				return
+			}
+		}
+	}
+}
+
+// txBeginnerOf returns the executor transactions are begun on.
+func txBeginnerOf(executor DBExecutor) (txBeginner, bool) {
+	if router, ok := executor.(*RoutingExecutor); ok {
+		executor = router.Primary()
+	}
+
+	beginner, ok := executor.(txBeginner)
+
+	return beginner, ok
+}
+
+func beginTx(ctx context.Context, executor DBExecutor, txOpts *sql.TxOptions) (*sql.Tx, error) {
+	beginner, ok := txBeginnerOf(executor)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrTxNotSupported, executor)
+	}
+
+	tx, err := beginner.BeginTx(ctx, txOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	return tx, nil
+}
+
+// finishTx commits tx when err is nil and rolls it back otherwise.
+func finishTx(tx *sql.Tx, err error) error {
+	if err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to roll back transaction: %w", rollbackErr))
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
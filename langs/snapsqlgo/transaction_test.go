@@ -0,0 +1,220 @@
+package snapsqlgo_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"iter"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	snapsqlgo "github.com/shibukawa/snapsql/langs/snapsqlgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func countTxItems(t *testing.T, db *sql.DB) int {
+	t.Helper()
+
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM items").Scan(&count))
+
+	return count
+}
+
+func TestResolveTxPropagation(t *testing.T) {
+	ctx := context.Background()
+	db := openStreamTestDB(t, 0)
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+
+	defer tx.Rollback()
+
+	tests := []struct {
+		name     string
+		executor snapsqlgo.DBExecutor
+		opts     []snapsqlgo.FuncOpt
+		begin    bool
+		err      error
+	}{
+		{name: "default", executor: db},
+		{name: "mandatory without tx", executor: db, opts: []snapsqlgo.FuncOpt{snapsqlgo.WithTxRequired()}, err: snapsqlgo.ErrTxRequired},
+		{name: "mandatory with tx", executor: tx, opts: []snapsqlgo.FuncOpt{snapsqlgo.WithTxRequired()}},
+		{name: "required begins", executor: db, opts: []snapsqlgo.FuncOpt{snapsqlgo.WithTxPropagation(snapsqlgo.TxRequired)}, begin: true},
+		{name: "required joins", executor: tx, opts: []snapsqlgo.FuncOpt{snapsqlgo.WithTxPropagation(snapsqlgo.TxRequired)}},
+		{name: "requires new begins", executor: db, opts: []snapsqlgo.FuncOpt{snapsqlgo.WithTxPropagation(snapsqlgo.TxRequiresNew)}, begin: true},
+		{name: "requires new with tx", executor: tx, opts: []snapsqlgo.FuncOpt{snapsqlgo.WithTxPropagation(snapsqlgo.TxRequiresNew)}, err: snapsqlgo.ErrTxNotSupported},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			begin, err := snapsqlgo.ResolveTxPropagation(ctx, tt.executor, "InsertItem", "insert", tt.opts...)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.begin, begin)
+		})
+	}
+
+	t.Run("registered with WithConfig", func(t *testing.T) {
+		ctx := snapsqlgo.WithConfig(ctx, "insert:*", snapsqlgo.WithTxRequired())
+
+		_, err := snapsqlgo.ResolveTxPropagation(ctx, db, "InsertItem", "insert")
+		assert.ErrorIs(t, err, snapsqlgo.ErrTxRequired)
+
+		begin, err := snapsqlgo.ResolveTxPropagation(ctx, db, "ListItems", "select")
+		require.NoError(t, err)
+		assert.False(t, begin)
+	})
+}
+
+func TestRunInTx(t *testing.T) {
+	ctx := context.Background()
+	db := openStreamTestDB(t, 0)
+	boom := errors.New("boom")
+
+	insert := func(ctx context.Context, tx snapsqlgo.DBExecutor) error {
+		_, err := tx.ExecContext(ctx, "INSERT INTO items (id) VALUES (1)")
+		return err
+	}
+
+	err := snapsqlgo.RunInTx(ctx, db, nil, func(ctx context.Context, tx snapsqlgo.DBExecutor) error {
+		require.NoError(t, insert(ctx, tx))
+		return boom
+	})
+	assert.ErrorIs(t, err, boom)
+	assert.Equal(t, 0, countTxItems(t, db))
+
+	require.NoError(t, snapsqlgo.RunInTx(ctx, snapsqlgo.NewRoutingExecutor(db), nil, insert))
+	assert.Equal(t, 1, countTxItems(t, db))
+
+	_, err = snapsqlgo.RunFuncInTx(ctx, db, nil, func(tx snapsqlgo.DBExecutor) (sql.Result, error) {
+		return tx.ExecContext(ctx, "INSERT INTO items (id) VALUES (2)")
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, countTxItems(t, db))
+}
+
+func TestSeqInTx(t *testing.T) {
+	ctx := context.Background()
+	db := openStreamTestDB(t, 3)
+
+	var joined []snapsqlgo.FuncOpt
+
+	seq := snapsqlgo.SeqInTx(ctx, db, []snapsqlgo.FuncOpt{snapsqlgo.WithTxPropagation(snapsqlgo.TxRequiresNew)},
+		func(tx snapsqlgo.DBExecutor, opts []snapsqlgo.FuncOpt) iter.Seq2[int, error] {
+			joined = opts
+
+			return func(yield func(int, error) bool) {
+				_, isTx := tx.(*sql.Tx)
+				assert.True(t, isTx)
+
+				if _, err := tx.ExecContext(ctx, "DELETE FROM items WHERE id = 1"); err != nil {
+					yield(0, err)
+					return
+				}
+
+				for id := 2; id <= 3; id++ {
+					if !yield(id, nil) {
+						return
+					}
+				}
+			}
+		})
+
+	var ids []int
+
+	for id, err := range seq {
+		require.NoError(t, err)
+
+		ids = append(ids, id)
+	}
+
+	assert.Equal(t, []int{2, 3}, ids)
+	assert.Equal(t, 2, countTxItems(t, db))
+
+	begin, err := snapsqlgo.ResolveTxPropagation(ctx, &sql.Tx{}, "ListItems", "select", joined...)
+	require.NoError(t, err)
+	assert.False(t, begin)
+}
+
+func TestWithSavepoint(t *testing.T) {
+	ctx := context.Background()
+	db := openStreamTestDB(t, 0)
+	boom := errors.New("boom")
+
+	insert := func(id int) func(ctx context.Context, tx snapsqlgo.DBExecutor) error {
+		return func(ctx context.Context, tx snapsqlgo.DBExecutor) error {
+			_, err := tx.ExecContext(ctx, "INSERT INTO items (id) VALUES (?)", id)
+			return err
+		}
+	}
+
+	err := snapsqlgo.RunInTx(ctx, db, nil, func(ctx context.Context, tx snapsqlgo.DBExecutor) error {
+		require.NoError(t, snapsqlgo.WithSavepoint(ctx, tx, insert(1)))
+
+		// Only the work inside the failed savepoint is undone
+		err := snapsqlgo.WithSavepoint(ctx, tx, func(ctx context.Context, tx snapsqlgo.DBExecutor) error {
+			require.NoError(t, insert(2)(ctx, tx))
+			return boom
+		})
+		assert.ErrorIs(t, err, boom)
+
+		// The transaction stays usable after a duplicate key error
+		assert.Error(t, snapsqlgo.WithSavepoint(ctx, tx, insert(1)))
+
+		// Nested savepoints
+		return snapsqlgo.WithSavepoint(ctx, tx, func(ctx context.Context, tx snapsqlgo.DBExecutor) error {
+			require.NoError(t, insert(3)(ctx, tx))
+
+			return snapsqlgo.WithSavepoint(ctx, tx, insert(4))
+		})
+	})
+	require.NoError(t, err)
+
+	var ids []int
+
+	rows, err := db.QueryContext(ctx, "SELECT id FROM items ORDER BY id")
+	require.NoError(t, err)
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		require.NoError(t, rows.Scan(&id))
+
+		ids = append(ids, id)
+	}
+
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []int{1, 3, 4}, ids)
+
+	t.Run("requires transaction", func(t *testing.T) {
+		err := snapsqlgo.WithSavepoint(ctx, db, insert(5))
+		assert.ErrorIs(t, err, snapsqlgo.ErrTxRequired)
+	})
+
+	t.Run("unsupported dialect", func(t *testing.T) {
+		_, err := snapsqlgo.RunFuncInSavepoint(ctx, &sql.Tx{}, "duckdb", func(tx snapsqlgo.DBExecutor) (int, error) {
+			return 0, nil
+		})
+		assert.ErrorIs(t, err, snapsqlgo.ErrSavepointNotSupported)
+	})
+}
+
+func TestResolveSavepoint(t *testing.T) {
+	ctx := context.Background()
+	db := openStreamTestDB(t, 0)
+	tx := &sql.Tx{}
+
+	assert.False(t, snapsqlgo.ResolveSavepoint(ctx, tx, "InsertItem", "insert"))
+	assert.True(t, snapsqlgo.ResolveSavepoint(ctx, tx, "InsertItem", "insert", snapsqlgo.WithSavepointScope()))
+	assert.False(t, snapsqlgo.ResolveSavepoint(ctx, db, "InsertItem", "insert", snapsqlgo.WithSavepointScope()))
+
+	configured := snapsqlgo.WithConfig(ctx, "insert:*", snapsqlgo.WithSavepointScope())
+	assert.True(t, snapsqlgo.ResolveSavepoint(configured, tx, "InsertItem", "INSERT"))
+}
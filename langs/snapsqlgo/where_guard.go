@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 )
 
@@ -80,6 +81,48 @@ type WhereDynamicCondition struct {
 // ErrEmptyWhereClause is returned when a mutation would execute without a WHERE clause.
 var ErrEmptyWhereClause = errors.New("snapsqlgo: empty WHERE clause")
 
+// WhereGuardMode selects how EnforceNonEmptyWhereClause reacts to a mutation without WHERE clause.
+type WhereGuardMode uint8
+
+const (
+	// WhereGuardModeError rejects the mutation with ErrEmptyWhereClause (default).
+	WhereGuardModeError WhereGuardMode = iota
+	// WhereGuardModeWarn reports the mutation and lets it run.
+	WhereGuardModeWarn
+)
+
+// WhereGuardAction is the outcome of a mutation without WHERE clause reported to WhereGuardAudit.
+type WhereGuardAction string
+
+const (
+	WhereGuardBlocked WhereGuardAction = "blocked"
+	WhereGuardWarned  WhereGuardAction = "warned"
+	WhereGuardAllowed WhereGuardAction = "allowed"
+)
+
+// WhereGuardEvent describes a mutation that runs, or was rejected, without a WHERE clause.
+type WhereGuardEvent struct {
+	FuncName string
+	Mutation MutationKind
+	Action   WhereGuardAction
+	Query    string
+	// Err explains why the WHERE clause is missing; it wraps ErrEmptyWhereClause
+	Err error
+}
+
+// WhereGuardAudit receives every mutation without WHERE clause, including allowed ones.
+type WhereGuardAudit func(ctx context.Context, event WhereGuardEvent)
+
+// WhereGuardPolicy carries the where_guard section of snapsql.yaml that the Go generator
+// embeds in generated UPDATE/DELETE functions.
+type WhereGuardPolicy struct {
+	Mode WhereGuardMode
+	// Allowed marks an intentional full-table mutation listed in where_guard.allow
+	Allowed bool
+	// Audit is called for each mutation without WHERE clause; warnings are logged with slog when nil
+	Audit WhereGuardAudit
+}
+
 // WithAllowingNoWhereOperation opts specific functions into executing UPDATE/DELETE without WHERE.
 func WithAllowingNoWhereOperation(ctx context.Context, funcPattern string, ops ...NoWhereOperation) context.Context {
 	mask := NoWhereOperation(0)
@@ -103,15 +146,47 @@ func WithAllowingNoWhereOperation(ctx context.Context, funcPattern string, ops .
 }
 
 // EnforceNonEmptyWhereClause validates that the generated SQL still contains a WHERE clause.
-func EnforceNonEmptyWhereClause(ctx context.Context, funcName string, mutation MutationKind, meta *WhereClauseMeta, query string) error {
+// The optional policy comes from snapsql.yaml: allowed functions and the warn mode let the
+// mutation run, and its audit callback is told about every mutation without WHERE clause.
+func EnforceNonEmptyWhereClause(ctx context.Context, funcName string, mutation MutationKind, meta *WhereClauseMeta, query string, policy ...WhereGuardPolicy) error {
 	if mutation != MutationUpdate && mutation != MutationDelete {
 		return nil
 	}
 
-	if isNoWhereAllowed(ctx, funcName, mutation) {
+	violation := checkWhereClause(funcName, mutation, meta, query)
+	if violation == nil {
 		return nil
 	}
 
+	var p WhereGuardPolicy
+	if len(policy) > 0 {
+		p = policy[0]
+	}
+
+	action := WhereGuardBlocked
+
+	switch {
+	case p.Allowed || isNoWhereAllowed(ctx, funcName, mutation):
+		action = WhereGuardAllowed
+	case p.Mode == WhereGuardModeWarn:
+		action = WhereGuardWarned
+	}
+
+	if p.Audit != nil {
+		p.Audit(ctx, WhereGuardEvent{FuncName: funcName, Mutation: mutation, Action: action, Query: query, Err: violation})
+	} else if action == WhereGuardWarned {
+		slog.WarnContext(ctx, "snapsqlgo: mutation without WHERE clause", "func", funcName, "query", query, "reason", violation)
+	}
+
+	if action == WhereGuardBlocked {
+		return violation
+	}
+
+	return nil
+}
+
+// checkWhereClause returns the ErrEmptyWhereClause error when the mutation has no WHERE clause.
+func checkWhereClause(funcName string, mutation MutationKind, meta *WhereClauseMeta, query string) error {
 	if meta != nil && strings.EqualFold(meta.Status, WhereClauseStatusFullScan) {
 		return buildEmptyWhereError(funcName, mutation, meta)
 	}
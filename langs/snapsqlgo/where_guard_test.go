@@ -87,3 +87,41 @@ func TestEnforceNonEmptyWhereClause_ConditionalFallbackTriggered(t *testing.T) {
 		t.Fatalf("expected ErrEmptyWhereClause, got %v", err)
 	}
 }
+
+func TestEnforceNonEmptyWhereClause_Policy(t *testing.T) {
+	meta := &WhereClauseMeta{Status: WhereClauseStatusFullScan}
+	query := "DELETE FROM sessions"
+
+	var events []WhereGuardEvent
+
+	audit := func(_ context.Context, event WhereGuardEvent) {
+		events = append(events, event)
+	}
+
+	err := EnforceNonEmptyWhereClause(context.Background(), "PurgeSessions", MutationDelete, meta, query, WhereGuardPolicy{Audit: audit})
+	if !errors.Is(err, ErrEmptyWhereClause) {
+		t.Fatalf("expected ErrEmptyWhereClause, got %v", err)
+	}
+
+	if err := EnforceNonEmptyWhereClause(context.Background(), "PurgeSessions", MutationDelete, meta, query, WhereGuardPolicy{Mode: WhereGuardModeWarn, Audit: audit}); err != nil {
+		t.Fatalf("expected warn mode to allow execution, got %v", err)
+	}
+
+	if err := EnforceNonEmptyWhereClause(context.Background(), "PurgeSessions", MutationDelete, meta, query, WhereGuardPolicy{Allowed: true, Audit: audit}); err != nil {
+		t.Fatalf("expected allowed function to run, got %v", err)
+	}
+
+	if err := EnforceNonEmptyWhereClause(context.Background(), "DeleteSession", MutationDelete, &WhereClauseMeta{Status: WhereClauseStatusExists}, "DELETE FROM sessions WHERE id = 1", WhereGuardPolicy{Audit: audit}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("expected 3 audit events, got %d", len(events))
+	}
+
+	for i, want := range []WhereGuardAction{WhereGuardBlocked, WhereGuardWarned, WhereGuardAllowed} {
+		if events[i].Action != want || events[i].Query != query || !errors.Is(events[i].Err, ErrEmptyWhereClause) {
+			t.Errorf("event %d = %+v, want action %s", i, events[i], want)
+		}
+	}
+}
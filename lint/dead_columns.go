@@ -0,0 +1,195 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shibukawa/snapsql/intermediate"
+	cmn "github.com/shibukawa/snapsql/parser/parsercommon"
+	"github.com/shibukawa/snapsql/tokenizer"
+)
+
+// checkNoDeadColumns reports columns that a CTE or a subquery in FROM selects but that nothing
+// reads: the rest of the statement never refers to them and no response field comes from them.
+func checkNoDeadColumns(target *Target) []Finding {
+	stmt, ok := target.Statement.(*cmn.SelectStatement)
+	if !ok || target.Format == nil || !stmt.HasSubqueryAnalysis() {
+		return nil
+	}
+
+	analysis := stmt.GetSubqueryAnalysis()
+	if analysis == nil {
+		return nil
+	}
+
+	tokens := statementTokens(stmt)
+
+	var findings []Finding
+
+	for _, derived := range analysis.DerivedTables {
+		start, end, ok := derivedTableSpan(tokens, derived)
+		if !ok {
+			continue
+		}
+
+		used, star := referencedOutside(tokens, start, end)
+		if star {
+			continue // SELECT * passes every column on
+		}
+
+		for _, field := range derived.SelectFields {
+			name := normalizeIdentifier(field.FieldName)
+			if name == "" || exposedColumn(target.Format.Responses, derived.Name, name) {
+				continue
+			}
+
+			if _, ok := used[name]; ok {
+				continue
+			}
+
+			findings = append(findings, Finding{
+				File:    target.File,
+				Line:    field.Pos.Line,
+				Column:  field.Pos.Column,
+				Message: fmt.Sprintf("column %q of %s is selected but never used by the query or exposed in the response", field.FieldName, derived.Name),
+			})
+		}
+	}
+
+	return findings
+}
+
+// statementTokens returns the significant tokens of a statement in source order
+func statementTokens(stmt cmn.StatementNode) []tokenizer.Token {
+	var raw []tokenizer.Token
+
+	raw = append(raw, stmt.LeadingTokens()...)
+
+	// The WITH clause is one of the clauses
+	for _, clause := range stmt.Clauses() {
+		raw = append(raw, clause.RawTokens()...)
+	}
+
+	tokens := make([]tokenizer.Token, 0, len(raw))
+
+	for _, token := range raw {
+		switch token.Type {
+		case tokenizer.WHITESPACE, tokenizer.LINE_COMMENT, tokenizer.BLOCK_COMMENT, tokenizer.EOF:
+		default:
+			tokens = append(tokens, token)
+		}
+	}
+
+	return tokens
+}
+
+// derivedTableSpan returns the indexes of the parentheses around the body of a CTE
+// ("name AS (...)") or of a subquery in FROM ("(...) [AS] name")
+func derivedTableSpan(tokens []tokenizer.Token, derived cmn.DerivedTableInfo) (int, int, bool) {
+	name := normalizeIdentifier(derived.Name)
+
+	for i, token := range tokens {
+		if !isIdentifier(token) || normalizeIdentifier(token.Value) != name {
+			continue
+		}
+
+		if i+2 < len(tokens) && tokens[i+1].Type == tokenizer.AS && tokens[i+2].Type == tokenizer.OPENED_PARENS {
+			if end := matchingParen(tokens, i+2); end > 0 {
+				return i + 2, end, true
+			}
+		}
+
+		closing := i - 1
+		if closing >= 0 && tokens[closing].Type == tokenizer.AS {
+			closing--
+		}
+
+		if closing >= 0 && tokens[closing].Type == tokenizer.CLOSED_PARENS {
+			if start := openingParen(tokens, closing); start >= 0 {
+				return start, closing, true
+			}
+		}
+	}
+
+	return 0, 0, false
+}
+
+func matchingParen(tokens []tokenizer.Token, open int) int {
+	depth := 0
+
+	for i := open; i < len(tokens); i++ {
+		switch tokens[i].Type {
+		case tokenizer.OPENED_PARENS:
+			depth++
+		case tokenizer.CLOSED_PARENS:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+func openingParen(tokens []tokenizer.Token, closing int) int {
+	depth := 0
+
+	for i := closing; i >= 0; i-- {
+		switch tokens[i].Type {
+		case tokenizer.CLOSED_PARENS:
+			depth++
+		case tokenizer.OPENED_PARENS:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// referencedOutside collects the identifiers outside tokens[start:end+1]. star reports a
+// "*" or "t.*" select item there.
+func referencedOutside(tokens []tokenizer.Token, start, end int) (map[string]struct{}, bool) {
+	used := make(map[string]struct{})
+
+	for i, token := range tokens {
+		if i >= start && i <= end {
+			continue
+		}
+
+		if isIdentifier(token) {
+			used[normalizeIdentifier(token.Value)] = struct{}{}
+		}
+
+		if token.Type == tokenizer.MULTIPLY && i > 0 {
+			switch tokens[i-1].Type {
+			case tokenizer.SELECT, tokenizer.COMMA, tokenizer.DOT, tokenizer.DISTINCT:
+				return nil, true
+			}
+		}
+	}
+
+	return used, false
+}
+
+func exposedColumn(responses []intermediate.Response, table, column string) bool {
+	for _, response := range responses {
+		if strings.EqualFold(response.SourceTable, table) && strings.EqualFold(response.SourceColumn, column) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isIdentifier(token tokenizer.Token) bool {
+	return token.Type == tokenizer.IDENTIFIER || token.Type == tokenizer.CONTEXTUAL_IDENTIFIER
+}
+
+// normalizeIdentifier removes identifier quotes and folds the case
+func normalizeIdentifier(name string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(name), "\"`[]"))
+}
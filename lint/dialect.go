@@ -0,0 +1,197 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate/codegenerator"
+	"github.com/shibukawa/snapsql/tokenizer"
+)
+
+// DialectReport lists, per target dialect, the constructs of a template that are
+// still unsupported after dialect conversion. A dialect without issues is compatible.
+type DialectReport struct {
+	File   string                       `json:"file"`
+	Issues map[snapsql.Dialect][]string `json:"issues"`
+}
+
+// Compatible reports whether the template has no issues for the dialect.
+func (r DialectReport) Compatible(dialect snapsql.Dialect) bool {
+	return len(r.Issues[dialect]) == 0
+}
+
+// CheckDialects generates the template once per dialect, so the same conversions as
+// code generation are applied, and scans the resulting SQL for constructs the dialect
+// cannot express.
+func (l *Linter) CheckDialects(path string, dialects []snapsql.Dialect) (DialectReport, error) {
+	report := DialectReport{File: path, Issues: map[snapsql.Dialect][]string{}}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return report, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	for _, dialect := range dialects {
+		config := snapsql.Config{}
+		if l.opts.Config != nil {
+			config = *l.opts.Config
+		}
+
+		config.Dialect = dialect
+
+		issues, err := l.checkDialect(path, content, &config)
+		if err != nil {
+			issues = []string{err.Error()}
+		}
+
+		report.Issues[dialect] = issues
+	}
+
+	return report, nil
+}
+
+func (l *Linter) checkDialect(path string, content []byte, config *snapsql.Config) ([]string, error) {
+	format, err := l.generateWith(path, content, config)
+	if err != nil {
+		return nil, err
+	}
+
+	optimized, err := codegenerator.OptimizeInstructions(format.Instructions, config.Dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	var sql strings.Builder
+
+	for _, inst := range optimized {
+		if inst.Op == "EMIT_STATIC" || inst.Op == "EMIT_UNLESS_BOUNDARY" || inst.Op == codegenerator.OpEmitSystemSoftDelete || inst.Op == codegenerator.OpEmitCursorCondition {
+			sql.WriteString(inst.Value)
+			sql.WriteString(" ")
+		}
+	}
+
+	tokens, err := tokenizer.Tokenize(sql.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return unsupportedConstructs(tokens, config.Dialect), nil
+}
+
+// unsupportedConstructs reports operators and keywords that have no equivalent in the
+// dialect and functions that are known to other dialects but not to this one.
+func unsupportedConstructs(tokens []tokenizer.Token, dialect snapsql.Dialect) []string {
+	dialect = snapsql.GenerationDialect(dialect)
+	signatures := snapsql.DialectFunctionSignatures(dialect)
+
+	var issues []string
+
+	add := func(issue string) {
+		if !slices.Contains(issues, issue) {
+			issues = append(issues, issue)
+		}
+	}
+
+	tokens = slices.DeleteFunc(slices.Clone(tokens), func(t tokenizer.Token) bool {
+		return t.Type == tokenizer.WHITESPACE || t.Type == tokenizer.LINE_COMMENT || t.Type == tokenizer.BLOCK_COMMENT
+	})
+
+	postgresLike := dialect == snapsql.DialectPostgres || dialect == snapsql.DialectDuckDB
+	// ClickHouse accepts '::' casts and ILIKE but has no RETURNING or ON CONFLICT
+	clickhouse := dialect == snapsql.DialectClickHouse
+
+	for i, token := range tokens {
+		switch {
+		case token.Type == tokenizer.DOUBLE_COLON && !postgresLike && !clickhouse:
+			add("'::' cast is not supported")
+		case token.Type == tokenizer.CONCAT && (dialect == snapsql.DialectMySQL || dialect == snapsql.DialectMariaDB):
+			add("'||' string concatenation is not supported")
+		case strings.EqualFold(token.Value, "ILIKE") && !postgresLike && !clickhouse:
+			add("ILIKE is not supported")
+		case token.Type == tokenizer.RETURNING && (dialect == snapsql.DialectMySQL || clickhouse):
+			add("RETURNING is not supported")
+		case clickhouse && token.Type == tokenizer.CONFLICT:
+			add("ON CONFLICT is not supported")
+		case i+1 < len(tokens) && tokens[i+1].Type == tokenizer.OPENED_PARENS:
+			name := strings.ToUpper(token.Value)
+			if _, ok := signatures[name]; !ok && isKnownFunction(name) {
+				add(fmt.Sprintf("function %s is not supported", name))
+			}
+		}
+	}
+
+	return issues
+}
+
+// isKnownFunction reports whether any dialect declares the function. Unknown names are
+// user-defined functions and are not reported.
+func isKnownFunction(name string) bool {
+	for _, signatures := range snapsql.FunctionSignatures {
+		if _, ok := signatures[name]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WriteDialectMatrix writes one row per template and one column per dialect, followed
+// by the issues of incompatible templates.
+func WriteDialectMatrix(w io.Writer, reports []DialectReport, dialects []snapsql.Dialect) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	header := []string{"TEMPLATE"}
+	for _, d := range dialects {
+		header = append(header, string(d))
+	}
+
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, report := range reports {
+		row := []string{report.File}
+
+		for _, d := range dialects {
+			if report.Compatible(d) {
+				row = append(row, "ok")
+			} else {
+				row = append(row, "NG")
+			}
+		}
+
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, report := range reports {
+		for _, d := range dialects {
+			for _, issue := range report.Issues[d] {
+				if _, err := fmt.Fprintf(w, "%s: %s: %s\n", report.File, d, issue); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// WriteDialectJSON writes the reports as a JSON array.
+func WriteDialectJSON(w io.Writer, reports []DialectReport) error {
+	if reports == nil {
+		reports = []DialectReport{}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(reports)
+}
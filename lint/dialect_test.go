@@ -0,0 +1,49 @@
+package lint
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql"
+)
+
+func TestCheckDialects(t *testing.T) {
+	dialects := []snapsql.Dialect{snapsql.DialectPostgres, snapsql.DialectMySQL, snapsql.DialectSQLite}
+
+	l, err := New(Options{})
+	assert.NoError(t, err)
+
+	portable := writeTemplate(t, "portable.snap.sql", "/*#\nfunction_name: portable\n*/\nSELECT id, CAST(name AS TEXT) AS name, name || 'x' AS tagged FROM users WHERE id = 1")
+	report, err := l.CheckDialects(portable, dialects)
+	assert.NoError(t, err)
+
+	for _, d := range dialects {
+		assert.True(t, report.Compatible(d), "%s: %v", d, report.Issues[d])
+	}
+
+	pgOnly := writeTemplate(t, "pg_only.snap.sql", "/*#\nfunction_name: pg_only\n*/\nSELECT id, UNNEST(tags) AS tag FROM users WHERE name ILIKE 'a%'")
+	report, err = l.CheckDialects(pgOnly, append(dialects, snapsql.DialectDuckDB, snapsql.DialectClickHouse))
+	assert.NoError(t, err)
+	assert.True(t, report.Compatible(snapsql.DialectPostgres), "%v", report.Issues[snapsql.DialectPostgres])
+	assert.True(t, report.Compatible(snapsql.DialectDuckDB), "%v", report.Issues[snapsql.DialectDuckDB])
+	assert.Equal(t, []string{"function UNNEST is not supported", "ILIKE is not supported"}, report.Issues[snapsql.DialectMySQL])
+	assert.Equal(t, []string{"function UNNEST is not supported", "ILIKE is not supported"}, report.Issues[snapsql.DialectSQLite])
+	assert.Equal(t, []string{"function UNNEST is not supported"}, report.Issues[snapsql.DialectClickHouse])
+
+	var out bytes.Buffer
+	assert.NoError(t, WriteDialectMatrix(&out, []DialectReport{report}, dialects))
+	assert.Contains(t, out.String(), "TEMPLATE")
+	assert.Contains(t, out.String(), pgOnly+": mysql: ILIKE is not supported")
+}
+
+func TestCheckDialectsInsertReturning(t *testing.T) {
+	l, err := New(Options{})
+	assert.NoError(t, err)
+
+	insert := writeTemplate(t, "insert_returning.snap.sql", "/*#\nfunction_name: insert_returning\n*/\nINSERT INTO users (name) VALUES ('a') RETURNING id, name")
+	report, err := l.CheckDialects(insert, []snapsql.Dialect{snapsql.DialectMySQL})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(report.Issues[snapsql.DialectMySQL]))
+	assert.Contains(t, report.Issues[snapsql.DialectMySQL][0], "single-column primary key")
+}
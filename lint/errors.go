@@ -0,0 +1,11 @@
+package lint
+
+import "errors"
+
+var (
+	// ErrUnknownRule is returned when a severity override refers to a rule that does not exist.
+	ErrUnknownRule = errors.New("lint: unknown rule")
+
+	// ErrInvalidSeverity is returned when a severity is not one of error, warning, info or off.
+	ErrInvalidSeverity = errors.New("lint: invalid severity")
+)
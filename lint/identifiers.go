@@ -0,0 +1,126 @@
+package lint
+
+import (
+	"github.com/shibukawa/snapsql/tokenizer"
+)
+
+// identifierContext tracks whether the items of the current list are identifiers
+// (FROM tables, ORDER BY / GROUP BY keys) at one parenthesis depth
+type identifierContext struct {
+	identifiers bool
+	prev        tokenizer.TokenType
+}
+
+// checkNoInterpolatedIdentifiers reports /*= */ directives written where SQL expects a table or
+// column name. A bound parameter there either breaks the statement or silently orders by a
+// constant, and interpolating the value instead would open the door to SQL injection.
+// Such templates must use /*# ident expr in [...] */ with an allowlist.
+func checkNoInterpolatedIdentifiers(target *Target) []Finding {
+	if target.Statement == nil {
+		return nil
+	}
+
+	var findings []Finding
+
+	for _, clause := range target.Statement.Clauses() {
+		tokens := clause.RawTokens()
+		stack := []identifierContext{{prev: tokenizer.WHITESPACE}}
+
+		for i, token := range tokens {
+			current := &stack[len(stack)-1]
+
+			if token.Directive != nil {
+				if token.Directive.Type == "variable" && inIdentifierPosition(tokens, i, current) {
+					findings = append(findings, Finding{
+						File:    target.File,
+						Line:    token.Position.Line,
+						Column:  token.Position.Column,
+						Message: "expression '" + token.Directive.Condition + "' is used as an identifier; use /*# ident " + token.Directive.Condition + " in [...] */ with an allowlist",
+					})
+				}
+
+				continue
+			}
+
+			switch token.Type {
+			case tokenizer.WHITESPACE, tokenizer.LINE_COMMENT, tokenizer.BLOCK_COMMENT,
+				tokenizer.DUMMY_START, tokenizer.DUMMY_END, tokenizer.DUMMY_LITERAL, tokenizer.DUMMY_PLACEHOLDER:
+				continue
+			case tokenizer.OPENED_PARENS:
+				current.prev = token.Type
+				stack = append(stack, identifierContext{prev: token.Type})
+
+				continue
+			case tokenizer.CLOSED_PARENS:
+				if len(stack) > 1 {
+					stack = stack[:len(stack)-1]
+				}
+
+				current = &stack[len(stack)-1]
+			case tokenizer.BY:
+				current.identifiers = current.prev == tokenizer.ORDER || current.prev == tokenizer.GROUP
+			case tokenizer.FROM, tokenizer.JOIN, tokenizer.UPDATE, tokenizer.INTO:
+				current.identifiers = true
+			case tokenizer.SELECT, tokenizer.WHERE, tokenizer.ON, tokenizer.USING, tokenizer.SET, tokenizer.VALUES,
+				tokenizer.HAVING, tokenizer.LIMIT, tokenizer.OFFSET, tokenizer.RETURNING, tokenizer.CASE, tokenizer.OVER:
+				current.identifiers = false
+			}
+
+			current.prev = token.Type
+		}
+	}
+
+	return findings
+}
+
+// inIdentifierPosition reports whether the directive at index stands for (a part of) a name
+func inIdentifierPosition(tokens []tokenizer.Token, index int, ctx *identifierContext) bool {
+	directive := tokens[index]
+
+	// users_/*= suffix */dev: glued to the preceding name
+	if index > 0 {
+		prev := tokens[index-1]
+		if prev.Type == tokenizer.IDENTIFIER && prev.Position.Offset+len(prev.Value) == directive.Position.Offset {
+			return true
+		}
+	}
+
+	// u./*= column */name or /*= table */users.id: qualified name
+	if ctx.prev == tokenizer.DOT {
+		return true
+	}
+
+	if next := tokenAfterDummy(tokens, index); next >= 0 && tokens[next].Type == tokenizer.DOT {
+		return true
+	}
+
+	// FROM /*= table */users, ORDER BY /*= column */name
+	return ctx.identifiers && (ctx.prev == tokenizer.COMMA || isListKeyword(ctx.prev))
+}
+
+func isListKeyword(tokenType tokenizer.TokenType) bool {
+	switch tokenType {
+	case tokenizer.BY, tokenizer.FROM, tokenizer.JOIN, tokenizer.UPDATE, tokenizer.INTO:
+		return true
+	default:
+		return false
+	}
+}
+
+// tokenAfterDummy returns the index of the token following the dummy value of the directive at index
+func tokenAfterDummy(tokens []tokenizer.Token, index int) int {
+	next := index + 1
+	if next < len(tokens) && tokens[next].Type == tokenizer.DUMMY_START {
+		for next < len(tokens) && tokens[next].Type != tokenizer.DUMMY_END {
+			next++
+		}
+
+		next++
+	}
+
+	if next >= len(tokens) {
+		return -1
+	}
+
+	return next
+}
@@ -0,0 +1,210 @@
+// Package lint checks SQL templates for risky or wasteful patterns that are still valid SQL.
+package lint
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+	"github.com/shibukawa/snapsql/markdownparser"
+	"github.com/shibukawa/snapsql/parser"
+)
+
+// Options configures a Linter.
+type Options struct {
+	Constants map[string]any
+	Tables    map[string]*snapsql.TableInfo
+	Config    *snapsql.Config
+
+	// Severities overrides the default severity per rule ID.
+	Severities map[string]string
+}
+
+// Linter runs the enabled rules against templates.
+type Linter struct {
+	opts       Options
+	severities map[string]Severity
+}
+
+// New creates a Linter. Unknown rule IDs and invalid severities in opts.Severities are rejected.
+func New(opts Options) (*Linter, error) {
+	severities := make(map[string]Severity, len(Rules))
+	for _, rule := range Rules {
+		severities[rule.ID] = rule.DefaultSeverity
+	}
+
+	for id, value := range opts.Severities {
+		if _, ok := findRule(id); !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownRule, id)
+		}
+
+		sev, err := ParseSeverity(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s=%s", err, id, value)
+		}
+
+		severities[id] = sev
+	}
+
+	if opts.Tables == nil {
+		opts.Tables = map[string]*snapsql.TableInfo{}
+	}
+
+	return &Linter{opts: opts, severities: severities}, nil
+}
+
+// Severity returns the effective severity of a rule.
+func (l *Linter) Severity(ruleID string) Severity {
+	return l.severities[ruleID]
+}
+
+// LintFile parses a .snap.sql or .snap.md template and runs the rules on it.
+func (l *Linter) LintFile(path string) ([]Finding, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	target := &Target{File: path, Tables: l.opts.Tables}
+
+	stmt, err := l.parse(path, content, parser.Options{InspectMode: true})
+	if err != nil {
+		return l.report([]Finding{{RuleID: RuleParseError, File: path, Message: err.Error()}}), nil
+	}
+
+	target.Statement = stmt
+
+	format, genErr := l.generate(path, content)
+	if genErr == nil {
+		target.Format = format
+	}
+
+	findings := l.Lint(target)
+
+	// Strict generation rejects some patterns that rules already report (e.g. SELECT *).
+	// Only surface the generation error when no rule explains it.
+	if genErr != nil && !hasRule(findings, RuleNoSelectStar) {
+		findings = append(findings, l.report([]Finding{{RuleID: RuleParseError, File: path, Message: genErr.Error()}})...)
+	}
+
+	return findings, nil
+}
+
+// Lint runs the enabled rules against a parsed target.
+func (l *Linter) Lint(target *Target) []Finding {
+	var findings []Finding
+
+	for _, rule := range Rules {
+		if rule.check == nil || l.severities[rule.ID] == SeverityOff {
+			continue
+		}
+
+		for _, f := range rule.check(target) {
+			f.RuleID = rule.ID
+			findings = append(findings, f)
+		}
+	}
+
+	return l.report(findings)
+}
+
+// report applies the configured severities and drops findings of disabled rules.
+func (l *Linter) report(findings []Finding) []Finding {
+	result := make([]Finding, 0, len(findings))
+
+	for _, f := range findings {
+		sev := l.severities[f.RuleID]
+		if sev == SeverityOff {
+			continue
+		}
+
+		f.Severity = sev
+		result = append(result, f)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Line != result[j].Line {
+			return result[i].Line < result[j].Line
+		}
+
+		return result[i].Column < result[j].Column
+	})
+
+	return result
+}
+
+func (l *Linter) parse(path string, content []byte, opts parser.Options) (parser.StatementNode, error) {
+	if isMarkdown(path) {
+		doc, err := markdownparser.Parse(bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+
+		stmt, _, _, err := parser.ParseMarkdownFile(doc, path, ".", l.opts.Constants, opts)
+
+		return stmt, err
+	}
+
+	stmt, _, _, err := parser.ParseSQLFile(bytes.NewReader(content), l.opts.Constants, path, ".", opts)
+
+	return stmt, err
+}
+
+func (l *Linter) generate(path string, content []byte) (*intermediate.IntermediateFormat, error) {
+	return l.generateWith(path, content, l.opts.Config)
+}
+
+// generateWith generates the intermediate format with an explicit config, e.g. one
+// whose dialect differs from the project's.
+func (l *Linter) generateWith(path string, content []byte, config *snapsql.Config) (*intermediate.IntermediateFormat, error) {
+	if isMarkdown(path) {
+		doc, err := markdownparser.Parse(bytes.NewReader(content))
+		if err != nil {
+			return nil, err
+		}
+
+		return intermediate.GenerateFromMarkdown(doc, path, ".", l.opts.Constants, l.opts.Tables, config)
+	}
+
+	return intermediate.GenerateFromSQL(bytes.NewReader(content), l.opts.Constants, path, ".", l.opts.Tables, config)
+}
+
+// HasErrors reports whether any finding has error severity.
+func HasErrors(findings []Finding) bool {
+	for _, f := range findings {
+		if f.Severity == SeverityError {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ParseRuleOverride parses an "id=severity" command line override.
+func ParseRuleOverride(s string) (string, string, error) {
+	id, sev, ok := strings.Cut(s, "=")
+	if !ok || strings.TrimSpace(id) == "" {
+		return "", "", fmt.Errorf("%w: expected id=severity, got %q", ErrInvalidSeverity, s)
+	}
+
+	return strings.TrimSpace(id), strings.TrimSpace(sev), nil
+}
+
+func hasRule(findings []Finding, id string) bool {
+	for _, f := range findings {
+		if f.RuleID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isMarkdown(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".md")
+}
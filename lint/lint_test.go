@@ -0,0 +1,248 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql"
+)
+
+func writeTemplate(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	return path
+}
+
+func ruleIDs(findings []Finding) []string {
+	var ids []string
+	for _, f := range findings {
+		ids = append(ids, f.RuleID)
+	}
+
+	return ids
+}
+
+func usersTable() map[string]*snapsql.TableInfo {
+	return map[string]*snapsql.TableInfo{
+		"users": {
+			Name: "users",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":   {Name: "id", DataType: "int", IsPrimaryKey: true},
+				"name": {Name: "name", DataType: "string"},
+			},
+			ColumnOrder: []string{"id", "name"},
+		},
+	}
+}
+
+func TestNewRejectsInvalidOverrides(t *testing.T) {
+	_, err := New(Options{Severities: map[string]string{"no-such-rule": "error"}})
+	assert.IsError(t, err, ErrUnknownRule)
+
+	_, err = New(Options{Severities: map[string]string{RuleNoSelectStar: "fatal"}})
+	assert.IsError(t, err, ErrInvalidSeverity)
+
+	l, err := New(Options{Severities: map[string]string{RuleNoSelectStar: "warning"}})
+	assert.NoError(t, err)
+	assert.Equal(t, SeverityWarning, l.Severity(RuleNoSelectStar))
+	assert.Equal(t, SeverityError, l.Severity(RuleRequireWhereClause))
+}
+
+func TestLintFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		template string
+		expected []string
+	}{
+		{
+			name:     "delete without where",
+			file:     "delete_all.snap.sql",
+			template: "DELETE FROM users",
+			expected: []string{RuleRequireWhereClause},
+		},
+		{
+			name: "update with where",
+			file: "update_user.snap.sql",
+			template: `/*#
+function_name: update_user
+parameters:
+  id: int
+  name: string
+*/
+UPDATE users SET name = /*= name */'x'
+WHERE id = /*= id */1`,
+			expected: nil,
+		},
+		{
+			name:     "select star",
+			file:     "select_all.snap.sql",
+			template: "SELECT * FROM users",
+			expected: []string{RuleNoSelectStar},
+		},
+		{
+			name: "unused parameter",
+			file: "find_user.snap.sql",
+			template: `/*#
+function_name: find_user
+parameters:
+  id: int
+  unused: string
+*/
+SELECT id, name FROM users WHERE id = /*= id */1`,
+			expected: []string{RuleNoUnusedParameters},
+		},
+		{
+			name: "dead cte column",
+			file: "recent_users.snap.sql",
+			template: `WITH recent AS (SELECT id, name FROM users WHERE id > 10)
+SELECT r.id FROM recent r`,
+			expected: []string{RuleNoDeadColumns},
+		},
+		{
+			name: "cte column used in where",
+			file: "named_users.snap.sql",
+			template: `WITH recent AS (SELECT id, name FROM users)
+SELECT r.id FROM recent r WHERE r.name <> ''`,
+			expected: nil,
+		},
+		{
+			name:     "dead subquery column",
+			file:     "user_ids.snap.sql",
+			template: "SELECT x.id FROM (SELECT id, name FROM users) AS x",
+			expected: []string{RuleNoDeadColumns},
+		},
+		{
+			name:     "limit without order by",
+			file:     "first_users.snap.sql",
+			template: "SELECT id, name FROM users LIMIT 10",
+			expected: []string{RuleRequirePKOrderWithLimit},
+		},
+		{
+			name:     "limit ordered by non primary key",
+			file:     "users_by_name.snap.sql",
+			template: "SELECT id, name FROM users ORDER BY name LIMIT 10",
+			expected: []string{RuleRequirePKOrderWithLimit},
+		},
+		{
+			name:     "limit ordered by primary key",
+			file:     "users_by_id.snap.sql",
+			template: "SELECT id, name FROM users ORDER BY name, id LIMIT 10",
+			expected: nil,
+		},
+		{
+			name: "expression as order by column",
+			file: "sorted_users.snap.sql",
+			template: `/*#
+function_name: sorted_users
+parameters:
+  sort: string
+*/
+SELECT id, name FROM users ORDER BY id, /*= sort */name`,
+			expected: []string{RuleNoInterpolatedIdent},
+		},
+		{
+			name: "expression glued to table name",
+			file: "suffixed_users.snap.sql",
+			template: `/*#
+function_name: suffixed_users
+parameters:
+  suffix: string
+*/
+SELECT id, name FROM users_/*= suffix */dev`,
+			expected: []string{RuleNoInterpolatedIdent},
+		},
+		{
+			name: "allowlisted order by column",
+			file: "allowlisted_users.snap.sql",
+			template: `/*#
+function_name: allowlisted_users
+parameters:
+  sort: string
+*/
+SELECT id, name FROM users ORDER BY /*# ident sort in [id, name] */name`,
+			expected: nil,
+		},
+		{
+			name: "expression as value",
+			file: "named_user.snap.sql",
+			template: `/*#
+function_name: named_user
+parameters:
+  name: string
+*/
+SELECT id, name FROM users WHERE name = /*= name */'x' ORDER BY id`,
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := New(Options{Tables: usersTable()})
+			assert.NoError(t, err)
+
+			findings, err := l.LintFile(writeTemplate(t, tt.file, tt.template))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, ruleIDs(findings), "%v", findings)
+		})
+	}
+}
+
+func TestLintFileSeverityOverride(t *testing.T) {
+	path := writeTemplate(t, "delete_all.snap.sql", "DELETE FROM users")
+
+	l, err := New(Options{Tables: usersTable(), Severities: map[string]string{RuleRequireWhereClause: "off"}})
+	assert.NoError(t, err)
+
+	findings, err := l.LintFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(findings))
+
+	l, err = New(Options{Tables: usersTable(), Severities: map[string]string{RuleRequireWhereClause: "warning"}})
+	assert.NoError(t, err)
+
+	findings, err = l.LintFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(findings))
+	assert.Equal(t, SeverityWarning, findings[0].Severity)
+	assert.False(t, HasErrors(findings))
+}
+
+func TestReferencedIdentifiers(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected []string
+	}{
+		{expr: "user.name", expected: []string{"user"}},
+		{expr: `size(items) > 0 && status == "active"`, expected: []string{"items", "status"}},
+		{expr: `'ignored' + name`, expected: []string{"name"}},
+		{expr: "a.b.c(d)", expected: []string{"a", "d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			ids := referencedIdentifiers(tt.expr)
+			assert.Equal(t, len(tt.expected), len(ids), "%v", ids)
+
+			for _, name := range tt.expected {
+				_, ok := ids[name]
+				assert.True(t, ok, "missing %s in %v", name, ids)
+			}
+		})
+	}
+}
+
+func TestParseRuleOverride(t *testing.T) {
+	id, sev, err := ParseRuleOverride("no-select-star=warning")
+	assert.NoError(t, err)
+	assert.Equal(t, "no-select-star", id)
+	assert.Equal(t, "warning", sev)
+
+	_, _, err = ParseRuleOverride("no-select-star")
+	assert.IsError(t, err, ErrInvalidSeverity)
+}
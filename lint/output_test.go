@@ -0,0 +1,47 @@
+package lint
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	l, err := New(Options{Severities: map[string]string{RuleNoUnusedParameters: "info"}})
+	assert.NoError(t, err)
+
+	findings := []Finding{
+		{RuleID: RuleNoSelectStar, Severity: SeverityError, Message: "SELECT * is not allowed", File: "queries/users.snap.sql", Line: 1, Column: 8},
+		{RuleID: RuleNoUnusedParameters, Severity: SeverityInfo, Message: "parameter \"x\" is declared but never used", File: "queries/users.snap.sql"},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, l.WriteSARIF(&buf, findings))
+
+	var log sarifLog
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	assert.Equal(t, 1, len(log.Runs))
+
+	run := log.Runs[0]
+	assert.Equal(t, "snapsql", run.Tool.Driver.Name)
+	assert.Equal(t, len(Rules), len(run.Tool.Driver.Rules))
+
+	assert.Equal(t, 2, len(run.Results))
+	assert.Equal(t, "error", run.Results[0].Level)
+	assert.Equal(t, "queries/users.snap.sql", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, &sarifRegion{StartLine: 1, StartColumn: 8}, run.Results[0].Locations[0].PhysicalLocation.Region)
+	assert.Equal(t, "note", run.Results[1].Level)
+	assert.Zero(t, run.Results[1].Locations[0].PhysicalLocation.Region)
+}
+
+func TestWriteText(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WriteText(&buf, []Finding{
+		{RuleID: RuleRequireWhereClause, Severity: SeverityError, Message: "DELETE statement has no WHERE clause", File: "a.snap.sql", Line: 2, Column: 1},
+		{RuleID: RuleParseError, Severity: SeverityError, Message: "broken", File: "b.snap.sql"},
+	}))
+	assert.Equal(t, "a.snap.sql:2:1: error: DELETE statement has no WHERE clause [require-where-clause]\nb.snap.sql: error: broken [parse-error]\n", buf.String())
+}
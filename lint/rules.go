@@ -0,0 +1,444 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+	"github.com/shibukawa/snapsql/intermediate/codegenerator"
+	cmn "github.com/shibukawa/snapsql/parser/parsercommon"
+	"github.com/shibukawa/snapsql/tokenizer"
+)
+
+// Rule IDs
+const (
+	RuleParseError              = "parse-error"
+	RuleRequireWhereClause      = "require-where-clause"
+	RuleNoSelectStar            = "no-select-star"
+	RuleNoUnusedParameters      = "no-unused-parameters"
+	RuleNoUnusedVariables       = "no-unused-variables"
+	RuleRequirePKOrderWithLimit = "require-pk-order-with-limit"
+	RuleNoDeadColumns           = "no-dead-columns"
+	RuleNoInterpolatedIdent     = "no-interpolated-identifiers"
+)
+
+// Rules lists every available rule in reporting order.
+var Rules = []Rule{
+	{
+		ID:              RuleParseError,
+		Description:     "Template cannot be parsed or converted to the intermediate format",
+		DefaultSeverity: SeverityError,
+	},
+	{
+		ID:              RuleRequireWhereClause,
+		Description:     "UPDATE and DELETE statements must have a WHERE clause that cannot be removed at runtime",
+		DefaultSeverity: SeverityError,
+		check:           checkRequireWhereClause,
+	},
+	{
+		ID:              RuleNoSelectStar,
+		Description:     "SELECT * is not allowed; list the columns explicitly",
+		DefaultSeverity: SeverityError,
+		check:           checkNoSelectStar,
+	},
+	{
+		ID:              RuleNoUnusedParameters,
+		Description:     "Declared parameters must be referenced by the template",
+		DefaultSeverity: SeverityWarning,
+		check:           checkNoUnusedParameters,
+	},
+	{
+		ID:              RuleNoUnusedVariables,
+		Description:     "Loop variables introduced by for directives must be referenced",
+		DefaultSeverity: SeverityWarning,
+		check:           checkNoUnusedVariables,
+	},
+	{
+		ID:              RuleRequirePKOrderWithLimit,
+		Description:     "SELECT with LIMIT must be ordered by the primary key to return stable pages",
+		DefaultSeverity: SeverityWarning,
+		check:           checkRequirePKOrderWithLimit,
+	},
+	{
+		ID:              RuleNoDeadColumns,
+		Description:     "Columns selected by a CTE or subquery must be used by the query or exposed in the response",
+		DefaultSeverity: SeverityWarning,
+		check:           checkNoDeadColumns,
+	},
+	{
+		ID:              RuleNoInterpolatedIdent,
+		Description:     "Expressions used as table or column names must go through /*# ident */ with an allowlist",
+		DefaultSeverity: SeverityError,
+		check:           checkNoInterpolatedIdentifiers,
+	},
+}
+
+func findRule(id string) (Rule, bool) {
+	for _, rule := range Rules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+
+	return Rule{}, false
+}
+
+func checkRequireWhereClause(target *Target) []Finding {
+	var (
+		kind  string
+		where *cmn.WhereClause
+		pos   cmn.ClauseNode
+	)
+
+	switch stmt := target.Statement.(type) {
+	case *cmn.UpdateStatement:
+		kind, where = "UPDATE", stmt.Where
+		if stmt.Update != nil {
+			pos = stmt.Update
+		}
+	case *cmn.DeleteFromStatement:
+		kind, where = "DELETE", stmt.Where
+		if stmt.From != nil {
+			pos = stmt.From
+		}
+	default:
+		return nil
+	}
+
+	if where == nil {
+		return []Finding{newFinding(target, pos, "%s statement has no WHERE clause", kind)}
+	}
+
+	if target.Format != nil && target.Format.WhereClauseMeta != nil && target.Format.WhereClauseMeta.Status == codegenerator.StatusConditional {
+		return []Finding{newFinding(target, where, "WHERE clause of %s statement can be removed by template conditions", kind)}
+	}
+
+	return nil
+}
+
+func checkNoSelectStar(target *Target) []Finding {
+	stmt, ok := target.Statement.(*cmn.SelectStatement)
+	if !ok || stmt.Select == nil {
+		return nil
+	}
+
+	var findings []Finding
+
+	// Inspect-mode parsing keeps the SELECT clause as tokens, so look for a '*' that
+	// stands for a whole field ("*" or "t.*") rather than a multiplication.
+	depth := 0
+	prev := tokenizer.COMMA
+
+	for _, token := range stmt.Select.ContentTokens() {
+		switch token.Type {
+		case tokenizer.WHITESPACE, tokenizer.LINE_COMMENT, tokenizer.BLOCK_COMMENT:
+			continue
+		case tokenizer.OPENED_PARENS:
+			depth++
+		case tokenizer.CLOSED_PARENS:
+			depth--
+		case tokenizer.MULTIPLY:
+			if depth == 0 && (prev == tokenizer.COMMA || prev == tokenizer.DOT || prev == tokenizer.DISTINCT) {
+				findings = append(findings, Finding{
+					File:    target.File,
+					Line:    token.Position.Line,
+					Column:  token.Position.Column,
+					Message: "SELECT * is not allowed; list the columns explicitly",
+				})
+			}
+		}
+
+		prev = token.Type
+	}
+
+	return findings
+}
+
+func checkNoUnusedParameters(target *Target) []Finding {
+	if target.Format == nil {
+		return nil
+	}
+
+	// Only expressions evaluated by an instruction count; the others never reach the SQL
+	used := make(map[string]struct{})
+	for _, index := range instructionExpressions(target.Format.Instructions) {
+		if index < 0 || index >= len(target.Format.CELExpressions) {
+			continue
+		}
+
+		for name := range referencedIdentifiers(target.Format.CELExpressions[index].Expression) {
+			used[name] = struct{}{}
+		}
+	}
+
+	var findings []Finding
+
+	for _, param := range target.Format.Parameters {
+		if _, ok := used[param.Name]; ok {
+			continue
+		}
+
+		findings = append(findings, Finding{
+			File:    target.File,
+			Message: fmt.Sprintf("parameter %q is declared but never used", param.Name),
+		})
+	}
+
+	return findings
+}
+
+func checkNoUnusedVariables(target *Target) []Finding {
+	if target.Format == nil {
+		return nil
+	}
+
+	envs := target.Format.CELEnvironments
+
+	// references[env] holds identifiers used by expressions evaluated in env or its descendants
+	references := make(map[int]map[string]struct{})
+	for _, expr := range target.Format.CELExpressions {
+		names := referencedIdentifiers(expr.Expression)
+		for env := expr.EnvironmentIndex; env >= 0; env = parentEnv(envs, env) {
+			if references[env] == nil {
+				references[env] = make(map[string]struct{})
+			}
+
+			for name := range names {
+				references[env][name] = struct{}{}
+			}
+		}
+	}
+
+	var findings []Finding
+
+	for _, env := range envs {
+		// The root environment holds the parameters, which no-unused-parameters covers
+		if env.ParentIndex == nil {
+			continue
+		}
+
+		for _, variable := range env.AdditionalVariables {
+			if _, ok := references[env.Index][variable.Name]; ok {
+				continue
+			}
+
+			line, column := loopPosition(target.Format.Instructions, env.Index)
+			findings = append(findings, Finding{
+				File:    target.File,
+				Line:    line,
+				Column:  column,
+				Message: fmt.Sprintf("loop variable %q is never referenced", variable.Name),
+			})
+		}
+	}
+
+	return findings
+}
+
+// instructionExpressions returns the indexes of the CEL expressions the instructions evaluate
+func instructionExpressions(instructions []intermediate.Instruction) []int {
+	var indexes []int
+
+	for _, inst := range instructions {
+		if inst.ExprIndex != nil {
+			indexes = append(indexes, *inst.ExprIndex)
+		}
+
+		if inst.CollectionExprIndex != nil {
+			indexes = append(indexes, *inst.CollectionExprIndex)
+		}
+	}
+
+	return indexes
+}
+
+func checkRequirePKOrderWithLimit(target *Target) []Finding {
+	stmt, ok := target.Statement.(*cmn.SelectStatement)
+	if !ok || stmt.Limit == nil {
+		return nil
+	}
+
+	if stmt.OrderBy == nil || len(stmt.OrderBy.Fields) == 0 {
+		return []Finding{newFinding(target, stmt.Limit, "LIMIT without ORDER BY returns rows in an unspecified order")}
+	}
+
+	table := mainTable(target)
+	if table == "" {
+		return nil
+	}
+
+	info, ok := target.Tables[table]
+	if !ok || info == nil {
+		return nil
+	}
+
+	ordered := make([]string, 0, len(stmt.OrderBy.Fields))
+	for _, field := range stmt.OrderBy.Fields {
+		ordered = append(ordered, strings.ToLower(field.Field.Name))
+	}
+
+	var missing []string
+
+	for _, column := range primaryKeyColumns(info.Columns, info.ColumnOrder) {
+		if !slices.Contains(ordered, strings.ToLower(column)) {
+			missing = append(missing, column)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	return []Finding{newFinding(target, stmt.OrderBy, "ORDER BY with LIMIT does not include primary key column(s) %s of %s", strings.Join(missing, ", "), table)}
+}
+
+// mainTable returns the physical table of the main FROM clause when it can be resolved
+func mainTable(target *Target) string {
+	if target.Format != nil {
+		for _, ref := range target.Format.TableReferences {
+			if ref.Context == "main" && ref.TableName != "" {
+				return ref.TableName
+			}
+		}
+	}
+
+	if stmt, ok := target.Statement.(*cmn.SelectStatement); ok && stmt.From != nil && len(stmt.From.Tables) > 0 {
+		return stmt.From.Tables[0].TableName
+	}
+
+	return ""
+}
+
+func primaryKeyColumns(columns map[string]*snapsql.ColumnInfo, order []string) []string {
+	names := order
+	if len(names) == 0 {
+		for name := range columns {
+			names = append(names, name)
+		}
+
+		slices.Sort(names)
+	}
+
+	var pks []string
+
+	for _, name := range names {
+		if col, ok := columns[name]; ok && col != nil && col.IsPrimaryKey {
+			pks = append(pks, name)
+		}
+	}
+
+	return pks
+}
+
+func parentEnv(envs []intermediate.CELEnvironment, index int) int {
+	for _, env := range envs {
+		if env.Index == index && env.ParentIndex != nil {
+			return *env.ParentIndex
+		}
+	}
+
+	return -1
+}
+
+// loopPosition returns the template position of the for directive that opens env
+func loopPosition(instructions []intermediate.Instruction, env int) (int, int) {
+	for _, inst := range instructions {
+		if inst.Op != intermediate.OpLoopStart || inst.EnvIndex == nil || *inst.EnvIndex != env {
+			continue
+		}
+
+		line, column, _ := strings.Cut(inst.Pos, ":")
+		l, _ := strconv.Atoi(line)
+		c, _ := strconv.Atoi(column)
+
+		return l, c
+	}
+
+	return 0, 0
+}
+
+// referencedIdentifiers returns the root identifiers referenced by a CEL expression.
+// Member accesses (a.b), function names and string literals are skipped.
+func referencedIdentifiers(expr string) map[string]struct{} {
+	result := make(map[string]struct{})
+	runes := []rune(expr)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+
+		switch {
+		case r == '"' || r == '\'':
+			i++
+			for i < len(runes) && runes[i] != r {
+				if runes[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i++
+		case isIdentStart(r):
+			start := i
+			for i < len(runes) && isIdentPart(runes[i]) {
+				i++
+			}
+
+			if prev := previousNonSpace(runes, start); prev == '.' {
+				continue
+			}
+
+			if next := nextNonSpace(runes, i); next == '(' {
+				continue
+			}
+
+			result[string(runes[start:i])] = struct{}{}
+		default:
+			i++
+		}
+	}
+
+	return result
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+func isIdentPart(r rune) bool {
+	return isIdentStart(r) || (r >= '0' && r <= '9')
+}
+
+func previousNonSpace(runes []rune, i int) rune {
+	for j := i - 1; j >= 0; j-- {
+		if runes[j] != ' ' && runes[j] != '\t' && runes[j] != '\n' {
+			return runes[j]
+		}
+	}
+
+	return 0
+}
+
+func nextNonSpace(runes []rune, i int) rune {
+	for ; i < len(runes); i++ {
+		if runes[i] != ' ' && runes[i] != '\t' && runes[i] != '\n' {
+			return runes[i]
+		}
+	}
+
+	return 0
+}
+
+func newFinding(target *Target, node cmn.ClauseNode, format string, args ...any) Finding {
+	finding := Finding{File: target.File, Message: fmt.Sprintf(format, args...)}
+
+	if node != nil {
+		if tokens := node.RawTokens(); len(tokens) > 0 {
+			finding.Line = tokens[0].Position.Line
+			finding.Column = tokens[0].Position.Column
+		}
+	}
+
+	return finding
+}
@@ -0,0 +1,62 @@
+package lint
+
+import (
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+	cmn "github.com/shibukawa/snapsql/parser/parsercommon"
+)
+
+// Severity controls how a rule violation is reported.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityInfo    Severity = "info"
+	SeverityOff     Severity = "off"
+)
+
+// ParseSeverity validates a severity name.
+func ParseSeverity(s string) (Severity, error) {
+	switch sev := Severity(s); sev {
+	case SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		return sev, nil
+	default:
+		return "", ErrInvalidSeverity
+	}
+}
+
+// Finding is a single rule violation.
+type Finding struct {
+	RuleID   string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"`   // 0 when the position is unknown
+	Column   int      `json:"column,omitempty"` // 0 when the position is unknown
+}
+
+// Rule describes a lint rule.
+type Rule struct {
+	ID              string
+	Description     string
+	DefaultSeverity Severity
+
+	check func(target *Target) []Finding
+}
+
+// Target is a parsed template handed to the rules.
+type Target struct {
+	File string
+
+	// Statement is parsed in inspect mode so that constructs rejected by code generation
+	// (e.g. SELECT *) can still be reported by rules.
+	Statement cmn.StatementNode
+
+	// Format is the intermediate format generated in strict mode. It is nil when the
+	// template cannot be generated; rules depending on it are skipped in that case.
+	Format *intermediate.IntermediateFormat
+
+	// Tables is the schema used for primary-key checks; it may be empty.
+	Tables map[string]*snapsql.TableInfo
+}
@@ -0,0 +1,183 @@
+package lsp
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/shibukawa/snapsql"
+)
+
+// tableRefPattern finds "FROM users u", "JOIN orders AS o", "UPDATE users" and "INTO users"
+var tableRefPattern = regexp.MustCompile(`(?i)\b(?:from|join|update|into)\s+([A-Za-z_][\w.]*)(?:\s+(?:as\s+)?([A-Za-z_]\w*))?`)
+
+// sqlKeywords never count as table aliases
+var sqlKeywords = map[string]struct{}{
+	"where": {}, "join": {}, "inner": {}, "left": {}, "right": {}, "full": {}, "cross": {}, "on": {},
+	"using": {}, "group": {}, "order": {}, "limit": {}, "offset": {}, "set": {}, "values": {},
+	"returning": {}, "having": {}, "union": {}, "natural": {}, "outer": {}, "select": {},
+}
+
+// Complete returns completion candidates at pos. After "name." only columns of the table
+// or alias "name" are returned; otherwise tables and the columns of tables used in the document.
+func (s *Server) Complete(text string, pos Position) []CompletionItem {
+	line := lineAt(text, pos.Line)
+	if pos.Character < len(line) {
+		line = line[:pos.Character]
+	}
+
+	// Strip the identifier being typed
+	prefix := strings.TrimRightFunc(line, isIdentRune)
+
+	if qualifier, ok := strings.CutSuffix(prefix, "."); ok {
+		name := lastIdent(qualifier)
+		if table := s.resolveTable(text, name); table != nil {
+			return columnItems(table)
+		}
+
+		return []CompletionItem{}
+	}
+
+	items := make([]CompletionItem, 0, len(s.opts.Tables))
+
+	for _, name := range sortedKeys(s.opts.Tables) {
+		items = append(items, CompletionItem{Label: name, Kind: CompletionKindClass, Detail: "table"})
+	}
+
+	seen := make(map[string]struct{})
+
+	for _, ref := range tableRefs(text) {
+		table, ok := s.opts.Tables[ref.table]
+		if !ok {
+			continue
+		}
+
+		if _, done := seen[ref.table]; done {
+			continue
+		}
+
+		seen[ref.table] = struct{}{}
+		items = append(items, columnItems(table)...)
+	}
+
+	return items
+}
+
+type tableRef struct {
+	table string
+	alias string
+}
+
+func tableRefs(text string) []tableRef {
+	var refs []tableRef
+
+	for _, m := range tableRefPattern.FindAllStringSubmatch(text, -1) {
+		table := m[1]
+		// Drop the schema qualifier
+		if i := strings.LastIndex(table, "."); i >= 0 {
+			table = table[i+1:]
+		}
+
+		alias := m[2]
+		if _, keyword := sqlKeywords[strings.ToLower(alias)]; keyword {
+			alias = ""
+		}
+
+		refs = append(refs, tableRef{table: table, alias: alias})
+	}
+
+	return refs
+}
+
+// resolveTable finds a table by name or by an alias declared in the document
+func (s *Server) resolveTable(text, name string) *snapsql.TableInfo {
+	if table, ok := s.opts.Tables[name]; ok {
+		return table
+	}
+
+	for _, ref := range tableRefs(text) {
+		if strings.EqualFold(ref.alias, name) {
+			return s.opts.Tables[ref.table]
+		}
+	}
+
+	return nil
+}
+
+func columnItems(table *snapsql.TableInfo) []CompletionItem {
+	names := table.ColumnOrder
+	if len(names) == 0 {
+		names = sortedKeys(table.Columns)
+	}
+
+	items := make([]CompletionItem, 0, len(names))
+
+	for _, name := range names {
+		col, ok := table.Columns[name]
+		if !ok || col == nil {
+			continue
+		}
+
+		detail := table.Name + "." + name + " " + col.DataType
+		if col.IsPrimaryKey {
+			detail += " (primary key)"
+		}
+
+		items = append(items, CompletionItem{Label: name, Kind: CompletionKindField, Detail: detail})
+	}
+
+	return items
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+func lineAt(text string, line int) string {
+	lines := strings.Split(text, "\n")
+	if line < 0 || line >= len(lines) {
+		return ""
+	}
+
+	return strings.TrimSuffix(lines[line], "\r")
+}
+
+func lastIdent(s string) string {
+	end := len(s)
+	start := end
+
+	for start > 0 && isIdentRune(rune(s[start-1])) {
+		start--
+	}
+
+	return s[start:end]
+}
+
+// identAt returns the dotted identifier (e.g. "user.name") around the byte offset col
+func identAt(line string, col int) string {
+	if col > len(line) {
+		col = len(line)
+	}
+
+	start, end := col, col
+	for start > 0 && (isIdentRune(rune(line[start-1])) || line[start-1] == '.') {
+		start--
+	}
+
+	for end < len(line) && (isIdentRune(rune(line[end])) || line[end] == '.') {
+		end++
+	}
+
+	return strings.Trim(line[start:end], ".")
+}
+
+func isIdentRune(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
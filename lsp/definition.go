@@ -0,0 +1,45 @@
+package lsp
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// linkPattern matches Markdown links used for external fixture and expected result files
+var linkPattern = regexp.MustCompile(`\[[^\]]*\]\(([^)\s]+)\)`)
+
+// Definition jumps from an external file link in a .snap.md test case
+// (e.g. "- users[clear-insert]: [users](fixtures/users.yaml)") to the linked file.
+func (s *Server) Definition(uri, text string, pos Position) *Location {
+	path := uriToPath(uri)
+	if !isMarkdown(path) {
+		return nil
+	}
+
+	line := lineAt(text, pos.Line)
+
+	for _, m := range linkPattern.FindAllStringSubmatchIndex(line, -1) {
+		if pos.Character < m[0] || pos.Character > m[1] {
+			continue
+		}
+
+		target := line[m[2]:m[3]]
+		if strings.Contains(target, "://") {
+			return nil
+		}
+
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(path), filepath.FromSlash(target))
+		}
+
+		if _, err := os.Stat(target); err != nil {
+			return nil
+		}
+
+		return &Location{URI: pathToURI(target)}
+	}
+
+	return nil
+}
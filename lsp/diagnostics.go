@@ -0,0 +1,86 @@
+package lsp
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shibukawa/snapsql/markdownparser"
+	"github.com/shibukawa/snapsql/parser"
+)
+
+// positionPattern matches "line:column" positions embedded in parser error messages
+var positionPattern = regexp.MustCompile(`\b(\d+):(\d+)\b`)
+
+// Diagnose parses a template and converts parser errors to diagnostics.
+func (s *Server) Diagnose(path, text string) []Diagnostic {
+	// Correction applied to parser line numbers (1-based) to get document lines (0-based)
+	lineOffset := -1
+
+	var err error
+
+	opts := parser.DefaultOptions
+	opts.Functions = s.opts.Functions
+
+	if isMarkdown(path) {
+		doc, parseErr := markdownparser.Parse(strings.NewReader(text))
+		if parseErr != nil {
+			return []Diagnostic{newDiagnostic(0, 0, parseErr.Error())}
+		}
+
+		// The parser adds SQLStartLine (the 1-based line of the first SQL line) to
+		// 1-based SQL lines, so positions are one line past the document line.
+		lineOffset = -2
+
+		_, _, _, err = parser.ParseMarkdownFile(doc, path, ".", s.opts.Constants, opts)
+	} else {
+		_, _, _, err = parser.ParseSQLFile(strings.NewReader(text), s.opts.Constants, path, ".", opts)
+	}
+
+	if err == nil {
+		return []Diagnostic{}
+	}
+
+	errs := []error{err}
+	if perr, ok := parser.AsParseError(err); ok && len(perr.Errors) > 0 {
+		errs = perr.Errors
+	}
+
+	diagnostics := make([]Diagnostic, 0, len(errs))
+
+	for _, e := range errs {
+		msg := e.Error()
+		line, column := 0, 0
+
+		if m := positionPattern.FindStringSubmatch(msg); m != nil {
+			l, _ := strconv.Atoi(m[1])
+			c, _ := strconv.Atoi(m[2])
+
+			line = max(l+lineOffset, 0)
+
+			if c > 0 {
+				column = c - 1
+			}
+		}
+
+		diagnostics = append(diagnostics, newDiagnostic(line, column, msg))
+	}
+
+	return diagnostics
+}
+
+func newDiagnostic(line, column int, msg string) Diagnostic {
+	return Diagnostic{
+		Range: Range{
+			Start: Position{Line: line, Character: column},
+			End:   Position{Line: line, Character: column + 1},
+		},
+		Severity: SeverityError,
+		Source:   "snapsql",
+		Message:  msg,
+	}
+}
+
+func isMarkdown(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".md")
+}
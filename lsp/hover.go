@@ -0,0 +1,96 @@
+package lsp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/shibukawa/snapsql/markdownparser"
+	cmn "github.com/shibukawa/snapsql/parser/parsercommon"
+	"github.com/shibukawa/snapsql/tokenizer"
+)
+
+// Hover shows the declared type of the parameter under the cursor. Dotted references
+// such as user.name resolve to the nested field type.
+func (s *Server) Hover(uri, text string, pos Position) *Hover {
+	ident := identAt(lineAt(text, pos.Line), pos.Character)
+	if ident == "" {
+		return nil
+	}
+
+	params := parameterDefinitions(uriToPath(uri), text)
+	if params == nil {
+		return nil
+	}
+
+	parts := strings.Split(ident, ".")
+
+	var value any = params
+	for i, part := range parts {
+		m, _ := value.(map[string]any)
+
+		next, ok := m[part]
+		if !ok {
+			if i == 0 {
+				return nil
+			}
+
+			// Method calls or unknown fields show the deepest declared type
+			parts = parts[:i]
+
+			break
+		}
+
+		value = next
+	}
+
+	return &Hover{Contents: markupContent{Kind: "markdown", Value: describeParameter(strings.Join(parts, "."), value)}}
+}
+
+func describeParameter(name string, value any) string {
+	if typ, ok := value.(string); ok {
+		return fmt.Sprintf("**parameter** `%s`: `%s`", name, typ)
+	}
+
+	b, err := yaml.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("**parameter** `%s`", name)
+	}
+
+	return fmt.Sprintf("**parameter** `%s`\n\n```yaml\n%s```", name, string(b))
+}
+
+// parameterDefinitions returns the parameters declared in the template header.
+// It only reads the function definition so that hover keeps working while the SQL body is being edited.
+func parameterDefinitions(path, text string) map[string]any {
+	var (
+		def *cmn.FunctionDefinition
+		err error
+	)
+
+	if isMarkdown(path) {
+		doc, parseErr := markdownparser.Parse(strings.NewReader(text))
+		if parseErr != nil {
+			return nil
+		}
+
+		def, err = cmn.ParseFunctionDefinitionFromSnapSQLDocument(doc, path, ".")
+	} else {
+		tokens, tokenErr := tokenizer.Tokenize(text)
+		if tokenErr != nil {
+			return nil
+		}
+
+		def, err = cmn.ParseFunctionDefinitionFromSQLComment(tokens, path, ".")
+	}
+
+	if err != nil || def == nil {
+		return nil
+	}
+
+	if err := def.Finalize(path, "."); err != nil {
+		return nil
+	}
+
+	return def.OriginalParameters
+}
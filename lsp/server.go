@@ -0,0 +1,273 @@
+// Package lsp implements a Language Server Protocol server for .snap.sql and .snap.md templates.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/explang"
+)
+
+var (
+	// ErrInvalidMessage is returned when a client message cannot be decoded.
+	ErrInvalidMessage = errors.New("lsp: invalid message")
+)
+
+// Options configures a Server.
+type Options struct {
+	// Tables is the schema used for completion; it may be empty.
+	Tables map[string]*snapsql.TableInfo
+
+	// Constants are passed to the parser when computing diagnostics.
+	Constants map[string]any
+
+	// Functions are the template functions (cel_functions) expressions may call.
+	Functions map[string]explang.Function
+
+	// Version is reported to the client in serverInfo.
+	Version string
+}
+
+// Server is a single-client language server communicating over a stream.
+type Server struct {
+	opts     Options
+	docs     map[string]string // URI -> text
+	out      io.Writer
+	shutdown bool
+}
+
+// NewServer creates a Server.
+func NewServer(opts Options) *Server {
+	if opts.Tables == nil {
+		opts.Tables = map[string]*snapsql.TableInfo{}
+	}
+
+	return &Server{opts: opts, docs: make(map[string]string)}
+}
+
+// Run serves requests read from r and writes responses to w until the client sends exit
+// or r is closed.
+func (s *Server) Run(r io.Reader, w io.Writer) error {
+	s.out = w
+	reader := bufio.NewReader(r)
+
+	for {
+		body, err := readMessage(reader)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		var msg message
+		if err := json.Unmarshal(body, &msg); err != nil {
+			if err := s.reply(nil, nil, &responseError{Code: codeParseError, Message: err.Error()}); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if msg.Method == "exit" {
+			return nil
+		}
+
+		if err := s.handle(&msg); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) handle(msg *message) error {
+	result, rpcErr := s.dispatch(msg)
+
+	// Notifications never get a response
+	if msg.ID == nil {
+		return nil
+	}
+
+	return s.reply(msg.ID, result, rpcErr)
+}
+
+func (s *Server) dispatch(msg *message) (any, *responseError) {
+	switch msg.Method {
+	case "initialize":
+		return initializeResult{
+			Capabilities: serverCapabilities{
+				TextDocumentSync:   1,
+				CompletionProvider: completionProvider{TriggerCharacters: []string{"."}},
+				HoverProvider:      true,
+				DefinitionProvider: true,
+			},
+			ServerInfo: serverInfo{Name: "snapsql", Version: s.opts.Version},
+		}, nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var params didOpenParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		s.docs[params.TextDocument.URI] = params.TextDocument.Text
+
+		return nil, s.publishDiagnostics(params.TextDocument.URI)
+	case "textDocument/didChange":
+		var params didChangeParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		// Full document sync: the last change holds the whole text
+		if n := len(params.ContentChanges); n > 0 {
+			s.docs[params.TextDocument.URI] = params.ContentChanges[n-1].Text
+		}
+
+		return nil, s.publishDiagnostics(params.TextDocument.URI)
+	case "textDocument/didSave":
+		var params didCloseParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		return nil, s.publishDiagnostics(params.TextDocument.URI)
+	case "textDocument/didClose":
+		var params didCloseParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		delete(s.docs, params.TextDocument.URI)
+
+		// Clear diagnostics of the closed document
+		if err := s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []Diagnostic{}}); err != nil {
+			return nil, &responseError{Code: codeInternalError, Message: err.Error()}
+		}
+
+		return nil, nil
+	case "textDocument/completion":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		return completionList{Items: s.Complete(s.docs[params.TextDocument.URI], params.Position)}, nil
+	case "textDocument/hover":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		if hover := s.Hover(params.TextDocument.URI, s.docs[params.TextDocument.URI], params.Position); hover != nil {
+			return hover, nil
+		}
+
+		return nil, nil
+	case "textDocument/definition":
+		var params textDocumentPositionParams
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			return nil, invalidParams(err)
+		}
+
+		if loc := s.Definition(params.TextDocument.URI, s.docs[params.TextDocument.URI], params.Position); loc != nil {
+			return loc, nil
+		}
+
+		return nil, nil
+	default:
+		// Unknown notifications ($/cancelRequest, workspace/didChangeConfiguration, ...) are ignored
+		if msg.ID == nil {
+			return nil, nil
+		}
+
+		return nil, &responseError{Code: codeMethodNotFound, Message: "method not found: " + msg.Method}
+	}
+}
+
+func (s *Server) publishDiagnostics(uri string) *responseError {
+	text, ok := s.docs[uri]
+	if !ok {
+		return nil
+	}
+
+	diagnostics := s.Diagnose(uriToPath(uri), text)
+	if err := s.notify("textDocument/publishDiagnostics", publishDiagnosticsParams{URI: uri, Diagnostics: diagnostics}); err != nil {
+		return &responseError{Code: codeInternalError, Message: err.Error()}
+	}
+
+	return nil
+}
+
+func (s *Server) reply(id *json.RawMessage, result any, rpcErr *responseError) error {
+	msg := message{JSONRPC: "2.0", ID: id, Error: rpcErr}
+	if rpcErr == nil {
+		// A null result must still be present in a successful response
+		if result == nil {
+			result = json.RawMessage("null")
+		}
+
+		msg.Result = result
+	}
+
+	if id == nil {
+		msg.ID = rawNull()
+	}
+
+	return writeMessage(s.out, msg)
+}
+
+func (s *Server) notify(method string, params any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	return writeMessage(s.out, message{JSONRPC: "2.0", Method: method, Params: body})
+}
+
+func invalidParams(err error) *responseError {
+	return &responseError{Code: codeInvalidParams, Message: err.Error()}
+}
+
+func rawNull() *json.RawMessage {
+	null := json.RawMessage("null")
+	return &null
+}
+
+// uriToPath converts a file:// URI to a local path. Other strings are returned unchanged.
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+
+	path := u.Path
+	// file:///C:/dir -> C:/dir on Windows
+	if runtime.GOOS == "windows" && len(path) > 2 && path[0] == '/' && path[2] == ':' {
+		path = path[1:]
+	}
+
+	return filepath.FromSlash(path)
+}
+
+// pathToURI converts a local path to a file:// URI.
+func pathToURI(path string) string {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	return (&url.URL{Scheme: "file", Path: path}).String()
+}
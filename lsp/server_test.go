@@ -0,0 +1,195 @@
+package lsp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql"
+)
+
+func testTables() map[string]*snapsql.TableInfo {
+	return map[string]*snapsql.TableInfo{
+		"users": {
+			Name: "users",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":   {Name: "id", DataType: "int", IsPrimaryKey: true},
+				"name": {Name: "name", DataType: "string"},
+			},
+			ColumnOrder: []string{"id", "name"},
+		},
+		"orders": {
+			Name: "orders",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":      {Name: "id", DataType: "int", IsPrimaryKey: true},
+				"user_id": {Name: "user_id", DataType: "int"},
+			},
+			ColumnOrder: []string{"id", "user_id"},
+		},
+	}
+}
+
+func labels(items []CompletionItem) []string {
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		result = append(result, item.Label)
+	}
+
+	return result
+}
+
+func frame(t *testing.T, msgs ...string) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	for _, msg := range msgs {
+		assert.NoError(t, writeMessage(&buf, json.RawMessage(msg)))
+	}
+
+	return &buf
+}
+
+func readAll(t *testing.T, r *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var result []map[string]any
+
+	reader := bufio.NewReader(r)
+	for {
+		body, err := readMessage(reader)
+		if err != nil {
+			break
+		}
+
+		var msg map[string]any
+		assert.NoError(t, json.Unmarshal(body, &msg))
+		result = append(result, msg)
+	}
+
+	return result
+}
+
+func TestReadWriteMessage(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, writeMessage(&buf, map[string]int{"a": 1}))
+	assert.Equal(t, "Content-Length: 7\r\n\r\n{\"a\":1}", buf.String())
+
+	body, err := readMessage(bufio.NewReader(&buf))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(body))
+
+	_, err = readMessage(bufio.NewReader(strings.NewReader("Content-Type: x\r\n\r\n{}")))
+	assert.IsError(t, err, ErrInvalidMessage)
+}
+
+func TestComplete(t *testing.T) {
+	s := NewServer(Options{Tables: testTables()})
+	text := "SELECT u.\nFROM users u JOIN orders o ON o.user_id = u.id"
+
+	t.Run("alias columns", func(t *testing.T) {
+		items := s.Complete(text, Position{Line: 0, Character: 9})
+		assert.Equal(t, []string{"id", "name"}, labels(items))
+	})
+
+	t.Run("table name columns", func(t *testing.T) {
+		items := s.Complete("SELECT orders.us FROM orders", Position{Line: 0, Character: 16})
+		assert.Equal(t, []string{"id", "user_id"}, labels(items))
+	})
+
+	t.Run("tables and referenced columns", func(t *testing.T) {
+		items := s.Complete("SELECT  FROM users", Position{Line: 0, Character: 7})
+		assert.Equal(t, []string{"orders", "users", "id", "name"}, labels(items))
+	})
+
+	t.Run("unknown qualifier", func(t *testing.T) {
+		items := s.Complete("SELECT x. FROM users", Position{Line: 0, Character: 9})
+		assert.Equal(t, 0, len(items))
+	})
+}
+
+func TestDefinition(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "fixtures"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "fixtures", "users.yaml"), []byte("- id: 1\n"), 0o644))
+
+	doc := filepath.Join(dir, "find_user.snap.md")
+	text := "### Fixtures: users[clear-insert]\n\n[users](fixtures/users.yaml)\n\n[missing](fixtures/none.yaml)\n"
+	s := NewServer(Options{})
+
+	loc := s.Definition(pathToURI(doc), text, Position{Line: 2, Character: 12})
+	assert.NotZero(t, loc)
+	assert.Equal(t, pathToURI(filepath.Join(dir, "fixtures", "users.yaml")), loc.URI)
+
+	assert.Zero(t, s.Definition(pathToURI(doc), text, Position{Line: 4, Character: 12}))
+	assert.Zero(t, s.Definition(pathToURI(doc), text, Position{Line: 0, Character: 3}))
+}
+
+func TestHover(t *testing.T) {
+	text := `/*#
+function_name: find_user
+parameters:
+  user_id: int
+  filter:
+    name: string
+*/
+SELECT id FROM users WHERE id = /*= user_id */1 AND name = /*= filter.name */'x'`
+	s := NewServer(Options{})
+	uri := pathToURI(filepath.Join(t.TempDir(), "find_user.snap.sql"))
+
+	hover := s.Hover(uri, text, Position{Line: 7, Character: 38})
+	assert.NotZero(t, hover)
+	assert.Equal(t, "**parameter** `user_id`: `int`", hover.Contents.Value)
+
+	hover = s.Hover(uri, text, Position{Line: 7, Character: 72})
+	assert.NotZero(t, hover)
+	assert.Equal(t, "**parameter** `filter.name`: `string`", hover.Contents.Value)
+
+	assert.Zero(t, s.Hover(uri, text, Position{Line: 7, Character: 1}))
+}
+
+func TestServerSession(t *testing.T) {
+	uri := pathToURI(filepath.Join(t.TempDir(), "broken.snap.sql"))
+	input := frame(t,
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"initialized","params":{}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didOpen","params":{"textDocument":{"uri":"`+uri+`","languageId":"sql","version":1,"text":"SELECT id FROM users WHERE id = /*= missing_param */1"}}}`,
+		`{"jsonrpc":"2.0","id":2,"method":"textDocument/completion","params":{"textDocument":{"uri":"`+uri+`"},"position":{"line":0,"character":7}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"unknown/method","params":{}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+	)
+
+	var output bytes.Buffer
+
+	s := NewServer(Options{Tables: testTables()})
+	assert.NoError(t, s.Run(input, &output))
+
+	msgs := readAll(t, &output)
+	assert.Equal(t, 5, len(msgs))
+
+	// initialize
+	caps := msgs[0]["result"].(map[string]any)["capabilities"].(map[string]any)
+	assert.Equal[any](t, true, caps["hoverProvider"])
+
+	// didOpen publishes diagnostics for the undeclared parameter
+	assert.Equal[any](t, "textDocument/publishDiagnostics", msgs[1]["method"])
+	diagnostics := msgs[1]["params"].(map[string]any)["diagnostics"].([]any)
+	assert.NotEqual(t, 0, len(diagnostics))
+
+	// completion
+	items := msgs[2]["result"].(map[string]any)["items"].([]any)
+	assert.NotEqual(t, 0, len(items))
+
+	// unknown method
+	assert.Equal[any](t, float64(codeMethodNotFound), msgs[3]["error"].(map[string]any)["code"])
+
+	// shutdown returns a null result
+	result, ok := msgs[4]["result"]
+	assert.True(t, ok)
+	assert.Zero(t, result)
+}
@@ -0,0 +1,112 @@
+package markdownparser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// ErrInvalidAssertion is returned when a line of an Assertions block cannot be parsed
+var ErrInvalidAssertion = errors.New("invalid assertion")
+
+// QueryAssertion is a lightweight check that compares a single value with the expected one
+// instead of enumerating expected rows.
+//
+//	expect_count: users = 5
+//	expect: SELECT COUNT(*) FROM orders WHERE status = 'paid' == 3
+type QueryAssertion struct {
+	Table    string // expect_count: table whose rows are counted
+	Query    string // expect: SELECT that returns a single value
+	Expected any
+}
+
+// String returns the assertion as written in markdown
+func (a QueryAssertion) String() string {
+	if a.Table != "" {
+		return fmt.Sprintf("expect_count: %s = %v", a.Table, a.Expected)
+	}
+
+	return fmt.Sprintf("expect: %s == %v", a.Query, a.Expected)
+}
+
+var assertionTableRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)?$`)
+
+// parseAssertions parses an Assertions block. Each non-empty line is either
+// "expect_count: <table> = <value>" or "expect: <select> == <value>".
+func parseAssertions(content []byte) ([]QueryAssertion, error) {
+	var assertions []QueryAssertion
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+
+	for scanner.Scan() {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		key, body, ok := strings.Cut(text, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidAssertion, text)
+		}
+
+		var (
+			assertion QueryAssertion
+			target    string
+			value     string
+		)
+
+		switch strings.TrimSpace(key) {
+		case "expect_count":
+			target, value, ok = cutLast(body, "=")
+			if !ok || !assertionTableRegexp.MatchString(target) {
+				return nil, fmt.Errorf("%w: %q (expected \"expect_count: <table> = <count>\")", ErrInvalidAssertion, text)
+			}
+
+			assertion.Table = target
+		case "expect":
+			target, value, ok = cutLast(body, "==")
+			if !ok || target == "" {
+				return nil, fmt.Errorf("%w: %q (expected \"expect: <query> == <value>\")", ErrInvalidAssertion, text)
+			}
+
+			assertion.Query = target
+		default:
+			return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidAssertion, key)
+		}
+
+		var expected any
+		if err := yaml.Unmarshal([]byte(value), &expected); err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", ErrInvalidAssertion, text, err)
+		}
+
+		assertion.Expected = normalizeValue(expected)
+		assertions = append(assertions, assertion)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(assertions) == 0 {
+		return nil, fmt.Errorf("%w: assertions block is empty", ErrInvalidAssertion)
+	}
+
+	return assertions, nil
+}
+
+// cutLast splits s around the last separator and trims both sides
+func cutLast(s, sep string) (string, string, bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return "", "", false
+	}
+
+	value := strings.TrimSpace(s[i+len(sep):])
+
+	return strings.TrimSpace(s[:i]), value, value != ""
+}
@@ -166,6 +166,74 @@ func parseExpectedResults(content []byte) ([]map[string]any, error) {
 	return result, nil
 }
 
+// parseDeltaExpectedResults parses the expectation of the delta strategy.
+//
+//	added:   [{id: 3, name: Carol}]
+//	updated: [{id: 1, name: Alice2}]
+//	deleted: [{id: 2}]
+//
+// The rows are flattened with the change kind stored in DeltaChangeKey. A plain list whose rows
+// already have DeltaChangeKey is also accepted (the form used by external files).
+func parseDeltaExpectedResults(content []byte) ([]map[string]any, error) {
+	content = bytes.TrimSpace(content)
+	if len(content) == 0 {
+		return nil, snapsql.ErrEmptyContent
+	}
+
+	if bytes.HasPrefix(content, []byte("[")) || bytes.HasPrefix(content, []byte("-")) {
+		rows, err := parseExpectedResults(content)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, row := range rows {
+			if !isDeltaChange(row[DeltaChangeKey]) {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidDeltaChange, row[DeltaChangeKey])
+			}
+		}
+
+		return rows, nil
+	}
+
+	var changes map[string][]map[string]any
+
+	err := yaml.Unmarshal(content, &changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse delta expected results: %w", err)
+	}
+
+	result := make([]map[string]any, 0)
+
+	for _, change := range []string{"added", "updated", "deleted"} {
+		for _, row := range changes[change] {
+			item := make(map[string]any, len(row)+1)
+			for k, v := range row {
+				item[k] = normalizeValue(v)
+			}
+
+			item[DeltaChangeKey] = change
+			result = append(result, item)
+		}
+
+		delete(changes, change)
+	}
+
+	for change := range changes {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidDeltaChange, change)
+	}
+
+	return result, nil
+}
+
+func isDeltaChange(value any) bool {
+	switch value {
+	case "added", "updated", "deleted":
+		return true
+	default:
+		return false
+	}
+}
+
 // parseValue converts string value to appropriate type
 func parseValue(value string) any {
 	value = strings.TrimSpace(value)
@@ -2,6 +2,7 @@ package markdownparser
 
 import (
 	"errors"
+	"regexp"
 	"strings"
 
 	"github.com/go-sql-driver/mysql"
@@ -15,10 +16,47 @@ type ExpectedError struct {
 	Type           ErrorType `yaml:"type"`
 	Constraint     string    `yaml:"constraint,omitempty"`
 	Column         string    `yaml:"column,omitempty"`
+	Message        string    `yaml:"message,omitempty"` // substring of the error message
 	MessagePattern string    `yaml:"message_pattern,omitempty"`
 	SQLState       string    `yaml:"sqlstate,omitempty"`
 }
 
+// String summarizes the expectation for reports, e.g. "unique violation (constraint: users_email_key)"
+func (e *ExpectedError) String() string {
+	var details []string
+
+	if e.SQLState != "" {
+		details = append(details, "sqlstate: "+e.SQLState)
+	}
+
+	if e.Constraint != "" {
+		details = append(details, "constraint: "+e.Constraint)
+	}
+
+	if e.Column != "" {
+		details = append(details, "column: "+e.Column)
+	}
+
+	if e.Message != "" {
+		details = append(details, "message: "+e.Message)
+	}
+
+	if e.MessagePattern != "" {
+		details = append(details, "message_pattern: "+e.MessagePattern)
+	}
+
+	summary := strings.Join(details, ", ")
+
+	switch {
+	case e.Type == "":
+		return summary
+	case summary == "":
+		return string(e.Type)
+	default:
+		return string(e.Type) + " (" + summary + ")"
+	}
+}
+
 // ClassifyDatabaseError classifies a database error into one of the predefined ErrorType constants
 // Returns an empty string if the error cannot be classified
 func ClassifyDatabaseError(err error) ErrorType {
@@ -152,3 +190,84 @@ func MatchesExpectedError(actualErr error, expectedType string) (bool, string) {
 
 	return true, ""
 }
+
+// MatchesExpectedErrorSpec checks the actual error against every field set in expected.
+// SQLSTATE, constraint and column are read from the driver error when it provides them,
+// otherwise constraint and column fall back to a substring match on the error message.
+func MatchesExpectedErrorSpec(actualErr error, expected *ExpectedError) (bool, string) {
+	if actualErr == nil {
+		return false, "expected error but got no error"
+	}
+
+	if expected.Type != "" {
+		if ok, message := MatchesExpectedError(actualErr, string(expected.Type)); !ok {
+			return false, message
+		}
+	}
+
+	errMsg := actualErr.Error()
+	details := databaseErrorDetails(actualErr)
+
+	if expected.SQLState != "" && !strings.EqualFold(details.sqlState, expected.SQLState) {
+		if details.sqlState == "" {
+			return false, "sqlstate mismatch: expected " + expected.SQLState + ", but the driver reports no SQLSTATE: " + errMsg
+		}
+
+		return false, "sqlstate mismatch: expected " + expected.SQLState + ", got " + details.sqlState
+	}
+
+	if expected.Constraint != "" && !matchesErrorDetail(details.constraint, expected.Constraint, errMsg) {
+		return false, "constraint mismatch: expected " + expected.Constraint + ", got: " + errMsg
+	}
+
+	if expected.Column != "" && !matchesErrorDetail(details.column, expected.Column, errMsg) {
+		return false, "column mismatch: expected " + expected.Column + ", got: " + errMsg
+	}
+
+	if expected.Message != "" && !strings.Contains(errMsg, expected.Message) {
+		return false, "error message does not contain " + expected.Message + ": " + errMsg
+	}
+
+	if expected.MessagePattern != "" {
+		re, err := regexp.Compile(expected.MessagePattern)
+		if err != nil {
+			return false, "invalid message_pattern: " + err.Error()
+		}
+
+		if !re.MatchString(errMsg) {
+			return false, "error message does not match " + expected.MessagePattern + ": " + errMsg
+		}
+	}
+
+	return true, ""
+}
+
+// errorDetails holds the structured fields a driver error exposes
+type errorDetails struct {
+	sqlState   string
+	constraint string
+	column     string
+}
+
+func databaseErrorDetails(err error) errorDetails {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return errorDetails{sqlState: pgErr.Code, constraint: pgErr.ConstraintName, column: pgErr.ColumnName}
+	}
+
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		return errorDetails{sqlState: strings.TrimRight(string(myErr.SQLState[:]), "\x00")}
+	}
+
+	return errorDetails{}
+}
+
+// matchesErrorDetail compares a structured field when available, or searches the message otherwise
+func matchesErrorDetail(actual, expected, errMsg string) bool {
+	if actual != "" {
+		return strings.EqualFold(actual, expected)
+	}
+
+	return strings.Contains(strings.ToLower(errMsg), strings.ToLower(expected))
+}
@@ -450,6 +450,53 @@ func TestMatchesExpectedError(t *testing.T) {
 	})
 }
 
+func TestMatchesExpectedErrorSpec(t *testing.T) {
+	db, cleanup := setupSQLite(t)
+	defer cleanup()
+
+	setupTestTable(t, db, snapsql.DialectSQLite)
+
+	_, _ = db.Exec("DELETE FROM test_users")
+	_, _ = db.Exec("INSERT INTO test_users (email, name, age) VALUES ('spec@example.com', 'Alice', 25)")
+	_, err := db.Exec("INSERT INTO test_users (email, name, age) VALUES ('spec@example.com', 'Bob', 30)")
+
+	if err == nil {
+		t.Fatal("expected error but got none")
+	}
+
+	tests := []struct {
+		name     string
+		expected ExpectedError
+		matches  bool
+		message  string
+	}{
+		{name: "type and message", expected: ExpectedError{Type: ErrorTypeUniqueViolation, Message: "UNIQUE constraint failed"}, matches: true},
+		{name: "column falls back to message", expected: ExpectedError{Column: "email"}, matches: true},
+		{name: "message pattern", expected: ExpectedError{MessagePattern: `test_users\.email$`}, matches: true},
+		{name: "type mismatch", expected: ExpectedError{Type: ErrorTypeCheckViolation}, message: "error type mismatch"},
+		{name: "message mismatch", expected: ExpectedError{Message: "duplicate key"}, message: "does not contain"},
+		{name: "sqlite has no sqlstate", expected: ExpectedError{SQLState: "23505"}, message: "no SQLSTATE"},
+		{name: "constraint mismatch", expected: ExpectedError{Constraint: "test_users_name_key"}, message: "constraint mismatch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, msg := MatchesExpectedErrorSpec(err, &tt.expected)
+			if matches != tt.matches {
+				t.Fatalf("expected match=%v, got %v (message: %s)", tt.matches, matches, msg)
+			}
+
+			if tt.message != "" && !strings.Contains(msg, tt.message) {
+				t.Errorf("expected message to contain %q, got %q", tt.message, msg)
+			}
+		})
+	}
+
+	if matches, _ := MatchesExpectedErrorSpec(nil, &ExpectedError{Message: "x"}); matches {
+		t.Error("expected match=false for nil error")
+	}
+}
+
 func containsIgnoreCase(s, substr string) bool {
 	if len(s) < len(substr) {
 		return false
@@ -230,6 +230,84 @@ INSERT INTO users (email) VALUES (/*= email */'test')
 	}
 }
 
+func TestParseExpectedErrorSpecFromMarkdown(t *testing.T) {
+	markdown := `## Description
+
+Test detailed error expectations
+
+## SQL
+
+` + "```sql" + `
+INSERT INTO users (email) VALUES (/*= email */'test')
+` + "```" + `
+
+## Test Cases
+
+### Duplicate email
+
+**Expect_Error:**
+` + "```yaml" + `
+type: unique_violation
+sqlstate: "23505"
+constraint: users_email_key
+` + "```" + `
+
+### Negative price
+
+**Expected Error:**
+` + "```yaml" + `
+message: price_positive
+` + "```" + `
+`
+
+	doc, err := Parse(bytes.NewReader([]byte(markdown)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(doc.TestCases) != 2 {
+		t.Fatalf("expected 2 test cases, got %d", len(doc.TestCases))
+	}
+
+	first := doc.TestCases[0]
+	if first.ExpectedErrorSpec == nil || first.ExpectedError == nil {
+		t.Fatal("expected error spec is not set")
+	}
+
+	if first.ExpectedErrorSpec.Type != ErrorTypeUniqueViolation || first.ExpectedErrorSpec.SQLState != "23505" || first.ExpectedErrorSpec.Constraint != "users_email_key" {
+		t.Errorf("unexpected spec: %+v", first.ExpectedErrorSpec)
+	}
+
+	if want := "unique violation (sqlstate: 23505, constraint: users_email_key)"; *first.ExpectedError != want {
+		t.Errorf("expected summary %q, got %q", want, *first.ExpectedError)
+	}
+
+	second := doc.TestCases[1]
+	if second.ExpectedErrorSpec == nil || second.ExpectedErrorSpec.Message != "price_positive" {
+		t.Errorf("unexpected spec: %+v", second.ExpectedErrorSpec)
+	}
+}
+
+func TestParseExpectedErrorSpecInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{name: "empty", yaml: "{}"},
+		{name: "unknown type", yaml: "type: deadlock"},
+		{name: "unknown field", yaml: "state: 23505"},
+		{name: "invalid pattern", yaml: "message_pattern: '('"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseExpectedErrorSpec([]byte(tt.yaml)); err == nil {
+				t.Error("expected error but got none")
+			}
+		})
+	}
+}
+
 func TestExpectedErrorAndExpectedResultsMutuallyExclusive(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -3,12 +3,18 @@ package markdownparser
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
+
+	"github.com/goccy/go-yaml"
 )
 
 // ErrInvalidErrorType is returned when an invalid error type is specified
 var ErrInvalidErrorType = errors.New("invalid error type")
 
+// ErrEmptyExpectedError is returned when a detailed Expected Error block specifies nothing to match
+var ErrEmptyExpectedError = errors.New("expected error block must specify type, sqlstate, constraint, column, message or message_pattern")
+
 // ErrorType represents a database error type that can occur at runtime
 type ErrorType string
 
@@ -71,3 +77,38 @@ func ParseExpectedError(content string) (*string, error) {
 
 	return &errorType, nil
 }
+
+// ParseExpectedErrorSpec parses the detailed YAML form of an Expected Error block:
+//
+//	type: unique violation
+//	sqlstate: "23505"
+//	constraint: users_email_key
+//	message: duplicate key
+func ParseExpectedErrorSpec(content []byte) (*ExpectedError, error) {
+	var spec ExpectedError
+
+	if err := yaml.UnmarshalWithOptions(content, &spec, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("failed to parse expected error: %w", err)
+	}
+
+	if spec.Type != "" {
+		errorType, err := ParseExpectedError(string(spec.Type))
+		if err != nil {
+			return nil, err
+		}
+
+		spec.Type = ErrorType(*errorType)
+	}
+
+	if spec.MessagePattern != "" {
+		if _, err := regexp.Compile(spec.MessagePattern); err != nil {
+			return nil, fmt.Errorf("invalid message_pattern %q: %w", spec.MessagePattern, err)
+		}
+	}
+
+	if spec.Type == "" && spec.SQLState == "" && spec.Constraint == "" && spec.Column == "" && spec.Message == "" && spec.MessagePattern == "" {
+		return nil, ErrEmptyExpectedError
+	}
+
+	return &spec, nil
+}
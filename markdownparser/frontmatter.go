@@ -12,6 +12,8 @@ import (
 var (
 	errPerformanceMapType       = errors.New("performance must be a map with string keys")
 	errPerformanceThresholdType = errors.New("performance.slow_query_threshold must be a string duration")
+	errMaxDurationType          = errors.New("performance.max_duration must be a string duration")
+	errAssertScopedType         = errors.New("assert_scoped must be a column name string")
 )
 
 // parseFrontMatter extracts YAML front matter from markdown content
@@ -78,9 +80,87 @@ func parsePerformanceSettings(frontMatter map[string]any) (PerformanceSettings,
 		}
 	}
 
+	if rawMax, exists := perfMap["max_duration"]; exists && rawMax != nil {
+		maxStr, ok := rawMax.(string)
+		if !ok {
+			return settings, errMaxDurationType
+		}
+
+		dur, err := parseMaxDuration(maxStr)
+		if err != nil {
+			return settings, fmt.Errorf("invalid performance.max_duration: %w", err)
+		}
+
+		settings.MaxDuration = dur
+	}
+
 	return settings, nil
 }
 
+// parseMaxDuration parses the query time budget given by performance.max_duration or a test
+// case's Max Duration marker. An empty value means no budget.
+func parseMaxDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	dur, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if dur <= 0 {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidMaxDuration, value)
+	}
+
+	return dur, nil
+}
+
+// nowLayouts are the formats accepted by the now setting. Timestamps without a zone are UTC.
+var nowLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02"}
+
+// parseNow parses the fixed current time given by the front matter or a test case's Now marker
+func parseNow(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range nowLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %q (use RFC 3339, e.g. 2024-06-01T00:00:00Z)", ErrInvalidNow, value)
+}
+
+// parseFrontMatterNow reads the file-wide fixed current time declared by now.
+func parseFrontMatterNow(frontMatter map[string]any) (time.Time, error) {
+	switch v := frontMatter["now"].(type) {
+	case nil:
+		return time.Time{}, nil
+	case time.Time:
+		return v.UTC(), nil
+	case string:
+		return parseNow(v)
+	default:
+		return time.Time{}, fmt.Errorf("%w: %v", ErrInvalidNow, v)
+	}
+}
+
+// parseAssertScoped reads the scope column declared by assert_scoped.
+func parseAssertScoped(frontMatter map[string]any) (string, error) {
+	raw, ok := frontMatter["assert_scoped"]
+	if !ok || raw == nil {
+		return "", nil
+	}
+
+	column, ok := raw.(string)
+	if !ok {
+		return "", errAssertScopedType
+	}
+
+	return strings.TrimSpace(column), nil
+}
+
 func normalizeStringMap(value any) (map[string]any, bool) {
 	switch m := value.(type) {
 	case map[string]any:
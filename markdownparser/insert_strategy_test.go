@@ -27,6 +27,12 @@ func TestParseTableNameAndStrategy(t *testing.T) {
 			expectedTable:    "users",
 			expectedStrategy: ClearInsert,
 		},
+		{
+			name:             "Table with truncate strategy",
+			input:            "users[truncate]",
+			expectedTable:    "users",
+			expectedStrategy: Truncate,
+		},
 		{
 			name:             "Table with upsert strategy",
 			input:            "users[upsert]",
@@ -62,6 +68,123 @@ func TestParseTableNameAndStrategy(t *testing.T) {
 	}
 }
 
+func TestParseFixtureSpecCount(t *testing.T) {
+	table, strategy, count, err := parseFixtureSpec("users[upsert, count: 100]")
+	require.NoError(t, err)
+	assert.Equal(t, "users", table)
+	assert.Equal(t, Upsert, strategy)
+	assert.Equal(t, 100, count)
+
+	table, strategy, count, err = parseFixtureSpec("users[count=3]")
+	require.NoError(t, err)
+	assert.Equal(t, "users", table)
+	assert.Equal(t, ClearInsert, strategy)
+	assert.Equal(t, 3, count)
+
+	_, _, _, err = parseFixtureSpec("users[count: many]")
+	require.ErrorIs(t, err, ErrInvalidFixtureCount)
+
+	for _, spec := range []string{"users[insert]", "users[transaction-wrapped]", "users[transaction-wrapped, count: 2]"} {
+		table, strategy, _, err = parseFixtureSpec(spec)
+		require.NoError(t, err, spec)
+		assert.Equal(t, "users", table)
+		assert.Equal(t, ClearInsert, strategy, spec)
+	}
+
+	for _, spec := range []string{"users[invalid]", "users[upsert, cuont: 3]", "users[upsert,]", "users[Upsert]"} {
+		_, _, _, err = parseFixtureSpec(spec)
+		require.ErrorIs(t, err, ErrUnknownFixtureOption, spec)
+	}
+}
+
+func TestFixtureAutofillOption(t *testing.T) {
+	tests := []struct {
+		spec     string
+		autofill bool
+	}{
+		{spec: "users", autofill: false},
+		{spec: "users[autofill]", autofill: true},
+		{spec: "users[upsert, autofill: true]", autofill: true},
+		{spec: "users[autofill=false]", autofill: false},
+		{spec: "[autofill: true]", autofill: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			section, err := newFixtureSection("fixtures: "+tt.spec, "test", nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.autofill, section.Autofill)
+		})
+	}
+
+	_, _, _, err := parseFixtureSpec("users[autofill: yes please]")
+	assert.ErrorIs(t, err, ErrUnknownFixtureOption)
+}
+
+func TestFixtureDeferConstraints(t *testing.T) {
+	input := `---
+function_name: "test_defer_constraints"
+---
+
+# Test Defer Constraints
+
+## Description
+
+Test deferred constraint checks for fixtures.
+
+## SQL
+
+` + "```sql" + `
+SELECT * FROM teams;
+` + "```" + `
+
+## Test Cases
+
+### Test: Circular references
+
+**Fixtures: teams[clear-insert, defer-constraints]**
+` + "```yaml" + `
+- id: 1
+  leader_id: 10
+` + "```" + `
+
+**Fixtures: [defer-constraints]**
+` + "```yaml" + `
+members:
+  - id: 10
+    team_id: 1
+` + "```" + `
+
+**Fixtures: projects**
+` + "```yaml" + `
+- id: 100
+` + "```" + `
+
+**Parameters:**
+` + "```yaml" + `
+id: 1
+` + "```" + `
+
+**Expected Results:**
+` + "```yaml" + `
+[]
+` + "```" + `
+`
+
+	doc, err := Parse(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, doc.TestCases, 1)
+
+	fixtures := doc.TestCases[0].Fixtures
+	require.Len(t, fixtures, 3)
+	assert.Equal(t, "teams", fixtures[0].TableName)
+	assert.Equal(t, ClearInsert, fixtures[0].Strategy)
+	assert.True(t, fixtures[0].DeferConstraints)
+	assert.Equal(t, "members", fixtures[1].TableName)
+	assert.True(t, fixtures[1].DeferConstraints)
+	assert.False(t, fixtures[2].DeferConstraints)
+}
+
 func TestFixtureWithoutStrategy(t *testing.T) {
 	input := `---
 function_name: "test_default_strategy"
@@ -184,3 +307,50 @@ limit: 10
 	assert.Equal(t, 1, len(second.Data))
 	assert.Equal(t, "Jane", second.Data[0]["name"])
 }
+
+func TestDeprecatedFixtureOptionsWarn(t *testing.T) {
+	input := `# Deprecated Fixture Options
+
+## Description
+
+Fixture options from older documentation are still accepted.
+
+## SQL
+
+` + "```sql" + `
+SELECT * FROM users;
+` + "```" + `
+
+## Test Cases
+
+### Insert alias
+
+**Fixtures: users[insert]**
+` + "```yaml" + `
+- id: 1
+` + "```" + `
+
+**Fixtures: posts[transaction-wrapped]**
+` + "```yaml" + `
+- id: 1
+` + "```" + `
+
+**Expected Results:**
+` + "```yaml" + `
+- id: 1
+` + "```" + `
+`
+
+	doc, err := Parse(strings.NewReader(input))
+	require.NoError(t, err)
+
+	fixtures := doc.TestCases[0].Fixtures
+	require.Equal(t, 2, len(fixtures))
+	assert.Equal(t, ClearInsert, fixtures[0].Strategy)
+	assert.Equal(t, ClearInsert, fixtures[1].Strategy)
+
+	assert.Equal(t, []string{
+		`Insert alias: fixture option "insert" for table users is deprecated; use "clear-insert"`,
+		`Insert alias: fixture option "transaction-wrapped" for table posts is deprecated; use "clear-insert"`,
+	}, doc.Warnings)
+}
@@ -0,0 +1,230 @@
+package markdownparser
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/goccy/go-yaml"
+)
+
+// ErrInvalidMatrix is returned when a Matrix block cannot be expanded into sub-cases
+var ErrInvalidMatrix = errors.New("invalid matrix")
+
+// MatrixEntry is one row of a test case's Matrix block. It expands into a sub-case named
+// "<test case>/<name>" that overrides the parameters and, optionally, the expected rows.
+//
+//	# Matrix block: one entry per sub-case
+//	- name: active
+//	  parameters: {status: active}
+//	  expected:
+//	    - {id: 1}
+type MatrixEntry struct {
+	Name       string           `yaml:"name"`
+	Parameters map[string]any   `yaml:"parameters"`
+	Expected   []map[string]any `yaml:"expected"`
+}
+
+// parseMatrix parses a Matrix block
+func parseMatrix(content []byte) ([]MatrixEntry, error) {
+	var entries []MatrixEntry
+
+	if err := yaml.UnmarshalWithOptions(content, &entries, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidMatrix, err)
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: matrix has no entries", ErrInvalidMatrix)
+	}
+
+	names := make(map[string]bool, len(entries))
+
+	for i := range entries {
+		if entries[i].Name == "" {
+			entries[i].Name = fmt.Sprintf("#%d", i+1)
+		}
+
+		if names[entries[i].Name] {
+			return nil, fmt.Errorf("%w: duplicate entry name %q", ErrInvalidMatrix, entries[i].Name)
+		}
+
+		names[entries[i].Name] = true
+
+		for k, v := range entries[i].Parameters {
+			entries[i].Parameters[k] = normalizeValue(v)
+		}
+
+		for _, row := range entries[i].Expected {
+			for k, v := range row {
+				row[k] = normalizeValue(v)
+			}
+		}
+	}
+
+	return entries, nil
+}
+
+// expandMatrix returns the sub-cases of a test case with a Matrix block, or the test case itself.
+// Parameters are merged key by key with the entry's values taking precedence. An entry with
+// expected rows replaces the unnamed expected result; table-qualified expectations are kept.
+// Each sub-case gets its own copy of the fixtures, parameters and expectations so that a runner
+// mutating one sub-case cannot affect its siblings.
+func expandMatrix(testCase *TestCase) []TestCase {
+	if len(testCase.Matrix) == 0 {
+		return []TestCase{*testCase}
+	}
+
+	subCases := make([]TestCase, 0, len(testCase.Matrix))
+
+	for _, entry := range testCase.Matrix {
+		subCase := cloneTestCase(testCase)
+		subCase.Name = testCase.Name + "/" + entry.Name
+		subCase.Matrix = nil
+
+		params := make(map[string]any, len(subCase.Parameters)+len(entry.Parameters))
+		maps.Copy(params, subCase.Parameters)
+		maps.Copy(params, cloneParameters(entry.Parameters))
+		subCase.Parameters = params
+		subCase.HasParameters = testCase.HasParameters || len(entry.Parameters) > 0
+
+		if entry.Expected != nil {
+			expected := cloneRows(entry.Expected)
+			tableSpecs := subCase.ExpectedResults
+
+			subCase.ExpectedResult = expected
+			subCase.ExpectedResults = []ExpectedResultSpec{{Strategy: "all", Data: expected}}
+
+			for _, spec := range tableSpecs {
+				if spec.TableName != "" {
+					subCase.ExpectedResults = append(subCase.ExpectedResults, spec)
+				}
+			}
+		}
+
+		subCases = append(subCases, subCase)
+	}
+
+	return subCases
+}
+
+// cloneTestCase returns a copy of the test case that shares no mutable state with the original.
+// The file-wide Setup is shared on purpose because it is executed once per file.
+func cloneTestCase(testCase *TestCase) TestCase {
+	clone := *testCase
+
+	if testCase.Fixtures != nil {
+		clone.Fixtures = make([]TableFixture, len(testCase.Fixtures))
+		for i, fixture := range testCase.Fixtures {
+			fixture.Data = cloneRows(fixture.Data)
+			clone.Fixtures[i] = fixture
+		}
+	}
+
+	if testCase.Fixture != nil {
+		clone.Fixture = make(map[string][]map[string]any, len(testCase.Fixture))
+		for table, rows := range testCase.Fixture {
+			clone.Fixture[table] = cloneRows(rows)
+		}
+	}
+
+	clone.Parameters = cloneParameters(testCase.Parameters)
+	clone.ExpectedResult = cloneRows(testCase.ExpectedResult)
+
+	if testCase.ExpectedResults != nil {
+		clone.ExpectedResults = make([]ExpectedResultSpec, len(testCase.ExpectedResults))
+		for i, spec := range testCase.ExpectedResults {
+			spec.Data = cloneRows(spec.Data)
+			clone.ExpectedResults[i] = spec
+		}
+	}
+
+	if testCase.ExpectedError != nil {
+		expectedError := *testCase.ExpectedError
+		clone.ExpectedError = &expectedError
+	}
+
+	if testCase.ExpectedErrorSpec != nil {
+		spec := *testCase.ExpectedErrorSpec
+		clone.ExpectedErrorSpec = &spec
+	}
+
+	if testCase.SQLArgs != nil {
+		clone.SQLArgs = make([]any, len(testCase.SQLArgs))
+		for i, arg := range testCase.SQLArgs {
+			clone.SQLArgs[i] = cloneValue(arg)
+		}
+	}
+
+	if testCase.Assertions != nil {
+		clone.Assertions = make([]QueryAssertion, len(testCase.Assertions))
+		for i, assertion := range testCase.Assertions {
+			assertion.Expected = cloneValue(assertion.Expected)
+			clone.Assertions[i] = assertion
+		}
+	}
+
+	if testCase.Steps != nil {
+		clone.Steps = make([]TestStep, len(testCase.Steps))
+		for i, step := range testCase.Steps {
+			step.Parameters = cloneParameters(step.Parameters)
+			step.ExpectedResult = cloneRows(step.ExpectedResult)
+			step.ExpectedResults = slices.Clone(step.ExpectedResults)
+			for j := range step.ExpectedResults {
+				step.ExpectedResults[j].Data = cloneRows(step.ExpectedResults[j].Data)
+			}
+			clone.Steps[i] = step
+		}
+	}
+
+	return clone
+}
+
+// cloneParameters deep-copies a parameter map
+func cloneParameters(params map[string]any) map[string]any {
+	if params == nil {
+		return nil
+	}
+
+	clone, _ := cloneValue(params).(map[string]any)
+
+	return clone
+}
+
+// cloneRows deep-copies fixture or expected rows
+func cloneRows(rows []map[string]any) []map[string]any {
+	if rows == nil {
+		return nil
+	}
+
+	clone := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		clone[i] = cloneParameters(row)
+	}
+
+	return clone
+}
+
+// cloneValue deep-copies the maps and slices produced by the YAML decoder
+func cloneValue(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		clone := make(map[string]any, len(value))
+		for k, item := range value {
+			clone[k] = cloneValue(item)
+		}
+
+		return clone
+	case []any:
+		clone := make([]any, len(value))
+		for i, item := range value {
+			clone[i] = cloneValue(item)
+		}
+
+		return clone
+	case []map[string]any:
+		return cloneRows(value)
+	default:
+		return v
+	}
+}
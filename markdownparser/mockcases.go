@@ -36,6 +36,9 @@ func ExtractMockTestCases(doc *SnapSQLDocument) []snapsql.MockTestCase {
 		if tc.ExpectedError != nil {
 			message := *tc.ExpectedError
 			response.Error = &snapsql.MockError{Message: message}
+			if tc.ExpectedErrorSpec != nil {
+				response.Error.Code = tc.ExpectedErrorSpec.SQLState
+			}
 		}
 
 		responses := make([]snapsql.MockResponse, 0, 1)
@@ -24,6 +24,17 @@ var (
 	ErrConflictingExpectations                  = errors.New("cannot specify both expected results and expected error")
 	ErrInvalidExpectedResultsExternalLinkFormat = errors.New("invalid expected results external file link format")
 	ErrInvalidFixturesExternalLinkFormat        = errors.New("invalid fixtures external file link format")
+	ErrInvalidFixtureCount                      = errors.New("invalid fixture count")
+	ErrUnknownFixtureOption                     = errors.New("unknown fixture option")
+	ErrInvalidSetupSection                      = errors.New("setup section only accepts fixtures")
+	ErrInvalidDefaultsSection                   = errors.New("defaults section only accepts parameters and fixtures")
+	ErrInvalidDeltaChange                       = errors.New("delta expectation only accepts added, updated and deleted")
+	ErrInvalidNow                               = errors.New("invalid now timestamp")
+	ErrInvalidMaxDuration                       = errors.New("max_duration must be positive")
+	ErrVerifyQueryWithoutExpectedResults        = errors.New("named verify query has no expected results section of the same name")
+	ErrUnknownExpectedResultsOption             = errors.New("unknown expected results option")
+	ErrStepsWithExpectedError                   = errors.New("test case with steps cannot specify expected error")
+	ErrUnnamedExpectedResultsOutsideStep        = errors.New("unnamed expected results of a test case with steps must follow a step")
 )
 
 // ParseOptions contains options for parsing markdown documents
@@ -48,11 +59,16 @@ type SnapSQLDocument struct {
 	SQLStartLine   int // Line number where SQL code block starts
 	TestCases      []TestCase
 	Performance    PerformanceSettings
+	AssertScoped   string        // Scope column every test case's SQL must constrain (front matter assert_scoped)
+	Setup          *FixtureSetup // Fixtures shared by all test cases ("## Setup" section)
+	Defaults       *TestDefaults // Parameters and fixtures inherited by each test case ("## Defaults" section)
+	Warnings       []string      // Deprecated syntax that was accepted (e.g. users[insert])
 }
 
 // PerformanceSettings represents parsed performance metadata.
 type PerformanceSettings struct {
 	SlowQueryThreshold time.Duration
+	MaxDuration        time.Duration // Query time budget of each test case (performance.max_duration)
 }
 
 // Parse parses a markdown query file and returns a SnapSQLDocument
@@ -80,6 +96,16 @@ func ParseWithOptions(reader io.Reader, options *ParseOptions) (*SnapSQLDocument
 		return nil, err
 	}
 
+	assertScoped, err := parseAssertScoped(frontMatter)
+	if err != nil {
+		return nil, err
+	}
+
+	now, err := parseFrontMatterNow(frontMatter)
+	if err != nil {
+		return nil, err
+	}
+
 	// Apply database override if provided (dialect hint only)
 	if options != nil && options.DatabaseOverride != nil {
 		if frontMatter == nil {
@@ -115,8 +141,9 @@ func ParseWithOptions(reader io.Reader, options *ParseOptions) (*SnapSQLDocument
 
 	// Build SnapSQL document
 	document := &SnapSQLDocument{
-		Metadata:    frontMatter,
-		Performance: performance,
+		Metadata:     frontMatter,
+		Performance:  performance,
+		AssertScoped: assertScoped,
 	}
 
 	// Set title if available (do not derive function_name from title)
@@ -157,9 +184,29 @@ func ParseWithOptions(reader io.Reader, options *ParseOptions) (*SnapSQLDocument
 		}
 	}
 
+	// Parse shared setup fixtures
+	if setupSection, exists := sections["setup"]; exists {
+		setup, err := parseSetupFromAST(setupSection.Content, contentBytes, lineMapper, &document.Warnings)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse setup: %w", err)
+		}
+
+		document.Setup = setup
+	}
+
+	// Parse defaults inherited by test cases
+	if defaultsSection, exists := sections["defaults"]; exists {
+		defaults, err := parseDefaultsFromAST(defaultsSection.Content, contentBytes, lineMapper, &document.Warnings)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse defaults: %w", err)
+		}
+
+		document.Defaults = defaults
+	}
+
 	// Parse test cases
 	if testSection, exists := sections["test cases"]; exists {
-		testCases, err := parseTestCasesFromAST(testSection.Content, contentBytes, lineMapper)
+		testCases, err := parseTestCasesFromAST(testSection.Content, contentBytes, lineMapper, &document.Warnings)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse test cases: %w", err)
 		}
@@ -167,6 +214,15 @@ func ParseWithOptions(reader io.Reader, options *ParseOptions) (*SnapSQLDocument
 		document.TestCases = testCases
 		for i := range document.TestCases {
 			document.TestCases[i].SlowQueryThreshold = performance.SlowQueryThreshold
+			document.TestCases[i].AssertScoped = assertScoped
+			if document.TestCases[i].Now.IsZero() {
+				document.TestCases[i].Now = now
+			}
+			if document.TestCases[i].MaxDuration == 0 {
+				document.TestCases[i].MaxDuration = performance.MaxDuration
+			}
+			document.TestCases[i].Setup = document.Setup
+			applyDefaults(&document.TestCases[i], document.Defaults)
 		}
 	}
 
@@ -143,6 +143,337 @@ SELECT 1;
 	assert.Contains(t, err.Error(), "performance.slow_query_threshold")
 }
 
+func TestParseAssertScopedFrontmatter(t *testing.T) {
+	input := `---
+function_name: sample
+assert_scoped: tenant_id
+---
+
+## Description
+
+Sample description.
+
+## SQL
+
+` + "```sql" + `
+SELECT id FROM orders WHERE tenant_id = /*= tenant_id */1;
+` + "```" + `
+
+## Test Cases
+
+### Sample
+
+**Expected Results:**
+` + "```yaml" + `
+- {id: 1}
+` + "```" + `
+`
+
+	doc, err := Parse(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, "tenant_id", doc.AssertScoped)
+
+	if len(doc.TestCases) != 1 {
+		t.Fatalf("expected 1 test case, got %d", len(doc.TestCases))
+	}
+
+	assert.Equal(t, "tenant_id", doc.TestCases[0].AssertScoped)
+}
+
+func TestParseNow(t *testing.T) {
+	input := `---
+function_name: sample
+now: "2024-06-01 09:30:00"
+---
+
+## Description
+
+Sample description.
+
+## SQL
+
+` + "```sql" + `
+SELECT id FROM orders WHERE created_at < NOW();
+` + "```" + `
+
+## Test Cases
+
+### File default
+
+**Expected Results:**
+` + "```yaml" + `
+- {id: 1}
+` + "```" + `
+
+### Own time
+
+**Now:** 2024-12-31T15:00:00+09:00
+
+**Expected Results:**
+` + "```yaml" + `
+- {id: 1}
+` + "```" + `
+`
+
+	doc, err := Parse(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(doc.TestCases))
+
+	assert.Equal(t, time.Date(2024, 6, 1, 9, 30, 0, 0, time.UTC), doc.TestCases[0].Now)
+	assert.Equal(t, time.Date(2024, 12, 31, 6, 0, 0, 0, time.UTC), doc.TestCases[1].Now)
+
+	_, err = Parse(strings.NewReader(strings.Replace(input, "2024-12-31T15:00:00+09:00", "tomorrow", 1)))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), ErrInvalidNow.Error())
+}
+
+func TestParseMaxDuration(t *testing.T) {
+	input := `---
+function_name: sample
+performance:
+  max_duration: 50ms
+---
+
+## Description
+
+Sample description.
+
+## SQL
+
+` + "```sql" + `
+SELECT id FROM orders;
+` + "```" + `
+
+## Test Cases
+
+### File default
+
+**Expected Results:**
+` + "```yaml" + `
+- {id: 1}
+` + "```" + `
+
+### Own budget
+
+**Max Duration:** 200ms
+
+**Expected Results:**
+` + "```yaml" + `
+- {id: 1}
+` + "```" + `
+`
+
+	doc, err := Parse(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(doc.TestCases))
+
+	assert.Equal(t, 50*time.Millisecond, doc.Performance.MaxDuration)
+	assert.Equal(t, 50*time.Millisecond, doc.TestCases[0].MaxDuration)
+	assert.Equal(t, 200*time.Millisecond, doc.TestCases[1].MaxDuration)
+
+	_, err = Parse(strings.NewReader(strings.Replace(input, "200ms", "fast", 1)))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid max duration")
+
+	_, err = Parse(strings.NewReader(strings.Replace(input, "max_duration: 50ms", "max_duration: 0s", 1)))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), ErrInvalidMaxDuration.Error())
+}
+
+func TestParseNamedVerifyQueries(t *testing.T) {
+	input := `---
+function_name: place_order
+---
+
+## Description
+
+Sample description.
+
+## SQL
+
+` + "```sql" + `
+INSERT INTO orders (id, user_id) VALUES (1, 1);
+` + "```" + `
+
+## Test Cases
+
+### Order and items are created
+
+` + "```sql verify:orders" + `
+SELECT id, user_id FROM orders ORDER BY id;
+` + "```" + `
+
+` + "```sql verify:items" + `
+SELECT order_id, sku FROM order_items ORDER BY sku;
+` + "```" + `
+
+**Expected Results: orders**
+` + "```yaml" + `
+- {id: 1, user_id: 1}
+` + "```" + `
+
+**Expected Results: items**
+` + "```yaml" + `
+- {order_id: 1, sku: A}
+` + "```" + `
+`
+
+	doc, err := Parse(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(doc.TestCases))
+
+	tc := doc.TestCases[0]
+	assert.Equal(t, 2, len(tc.VerifyQueries))
+	assert.Equal(t, "orders", tc.VerifyQueries[0].Name)
+	assert.Equal(t, "SELECT id, user_id FROM orders ORDER BY id;", tc.VerifyQueries[0].Query)
+	assert.Equal(t, "items", tc.VerifyQueries[1].Name)
+	assert.Equal(t, 2, len(tc.ExpectedResults))
+	assert.Equal(t, "items", tc.ExpectedResults[1].TableName)
+
+	_, err = Parse(strings.NewReader(strings.Replace(input, "**Expected Results: items**", "**Expected Results: orders**", 1)))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), ErrDuplicateExpectedResults.Error())
+
+	_, err = Parse(strings.NewReader(strings.Replace(input, "verify:items", "verify:lines", 1)))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), ErrVerifyQueryWithoutExpectedResults.Error())
+
+	_, err = Parse(strings.NewReader(strings.Replace(input, "verify:items", "verify:orders", 1)))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "duplicate verify query")
+}
+
+func TestParseExpectedResultsSpec(t *testing.T) {
+	yes, no := true, false
+
+	tests := []struct {
+		label   string
+		want    ExpectedResultSpec
+		wantErr bool
+	}{
+		{label: "", want: ExpectedResultSpec{Strategy: "all"}},
+		{label: "users", want: ExpectedResultSpec{TableName: "users", Strategy: "all"}},
+		{label: "users[pk-match]", want: ExpectedResultSpec{TableName: "users", Strategy: "pk-match"}},
+		{label: "[ordered: false]", want: ExpectedResultSpec{Strategy: "all", Ordered: &no}},
+		{label: "[order_by: created_at, id]", want: ExpectedResultSpec{Strategy: "all", OrderBy: []string{"created_at", "id"}}},
+		{label: "users[all, order_by=name, ordered: true]", want: ExpectedResultSpec{TableName: "users", Strategy: "all", Ordered: &yes, OrderBy: []string{"name"}}},
+		{
+			label: "users[pk-match, ignore_columns: [updated_at, uuid], order_by: [id]]",
+			want:  ExpectedResultSpec{TableName: "users", Strategy: "pk-match", IgnoreColumns: []string{"updated_at", "uuid"}, OrderBy: []string{"id"}},
+		},
+		{label: "users[only_columns: name, email]", want: ExpectedResultSpec{TableName: "users", Strategy: "all", OnlyColumns: []string{"name", "email"}}},
+		{label: "[ordered: maybe]", wantErr: true},
+		{label: "[sorted: true]", wantErr: true},
+		{label: "[ignore_columns: []]", wantErr: true},
+		{label: "users[all, pk-match]", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			spec, err := parseExpectedResultsSpec(tt.label)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), ErrUnknownExpectedResultsOption.Error())
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, spec)
+		})
+	}
+}
+
+func TestParseSetupSection(t *testing.T) {
+	input := `# Setup
+
+## Description
+
+Sample description.
+
+## SQL
+
+` + "```sql" + `
+SELECT id FROM users;
+` + "```" + `
+
+## Setup
+
+**Fixtures: users[upsert]**
+` + "```yaml" + `
+- {id: 1, name: Alice}
+- {id: 2, name: Bob}
+` + "```" + `
+
+## Test Cases
+
+### First
+
+**Expected Results:**
+` + "```yaml" + `
+- {id: 1}
+- {id: 2}
+` + "```" + `
+
+### Second
+
+**Fixtures: users**
+` + "```yaml" + `
+- {id: 3, name: Carol}
+` + "```" + `
+
+**Expected Results:**
+` + "```yaml" + `
+- {id: 3}
+` + "```" + `
+`
+
+	doc, err := Parse(strings.NewReader(input))
+	assert.NoError(t, err)
+
+	if doc.Setup == nil || len(doc.Setup.Fixtures) != 1 {
+		t.Fatalf("expected 1 setup fixture, got %+v", doc.Setup)
+	}
+
+	assert.Equal(t, "users", doc.Setup.Fixtures[0].TableName)
+	assert.Equal(t, Upsert, doc.Setup.Fixtures[0].Strategy)
+	assert.Equal(t, 2, len(doc.Setup.Fixtures[0].Data))
+
+	if len(doc.TestCases) != 2 {
+		t.Fatalf("expected 2 test cases, got %d", len(doc.TestCases))
+	}
+
+	assert.True(t, doc.TestCases[0].Setup == doc.Setup)
+	assert.True(t, doc.TestCases[1].Setup == doc.Setup)
+	assert.Equal(t, 0, len(doc.TestCases[0].Fixtures))
+	assert.Equal(t, 1, len(doc.TestCases[1].Fixtures))
+}
+
+func TestParseSetupSectionRejectsOtherBlocks(t *testing.T) {
+	input := `# Setup
+
+## Description
+
+Sample description.
+
+## SQL
+
+` + "```sql" + `
+SELECT id FROM users;
+` + "```" + `
+
+## Setup
+
+**Parameters:**
+` + "```yaml" + `
+id: 1
+` + "```" + `
+`
+
+	_, err := Parse(strings.NewReader(input))
+	assert.IsError(t, err, ErrInvalidSetupSection)
+}
+
 func TestParseAllowsEmptyExpectedResults(t *testing.T) {
 	input := `# Empty Expected Results
 
@@ -668,3 +999,457 @@ param1: value1
 		return ast.WalkContinue, nil
 	})
 }
+
+func TestParseDeltaExpectedResults(t *testing.T) {
+	input := `# Delta Expected Results
+
+## Description
+
+Delta expectation lists only the changed rows.
+
+## SQL
+
+` + "```sql" + `
+UPDATE users SET name = 'Alice2' WHERE id = 1;
+` + "```" + `
+
+## Test Cases
+
+### Rename user
+
+**Expected Results: users[delta]**
+` + "```yaml" + `
+added:
+  - {id: 3, name: Carol}
+updated:
+  - {id: 1, name: Alice2}
+deleted:
+  - {id: 2}
+` + "```" + `
+`
+
+	doc, err := Parse(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(doc.TestCases))
+
+	spec := doc.TestCases[0].ExpectedResults[0]
+	assert.Equal(t, "users", spec.TableName)
+	assert.Equal(t, "delta", spec.Strategy)
+	assert.Equal(t, []map[string]any{
+		{DeltaChangeKey: "added", "id": uint64(3), "name": "Carol"},
+		{DeltaChangeKey: "updated", "id": uint64(1), "name": "Alice2"},
+		{DeltaChangeKey: "deleted", "id": uint64(2)},
+	}, spec.Data)
+}
+
+func TestParseExpectedResultsColumnLists(t *testing.T) {
+	input := `# Column Lists
+
+## Description
+
+Only the listed columns are compared.
+
+## SQL
+
+` + "```sql" + `
+UPDATE users SET name = 'Alice2' WHERE id = 1;
+` + "```" + `
+
+## Test Cases
+
+### Rename user
+
+**Expected Results: users[pk-match, ignore_columns: [updated_at, uuid]]**
+` + "```yaml" + `
+- {id: 1, name: Alice2}
+` + "```" + `
+`
+
+	doc, err := Parse(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(doc.TestCases))
+
+	spec := doc.TestCases[0].ExpectedResults[0]
+	assert.Equal(t, "users", spec.TableName)
+	assert.Equal(t, "pk-match", spec.Strategy)
+	assert.Equal(t, []string{"updated_at", "uuid"}, spec.IgnoreColumns)
+}
+
+func TestParseDeltaExpectedResultsRejectsUnknownChange(t *testing.T) {
+	_, err := parseDeltaExpectedResults([]byte("inserted:\n  - {id: 1}\n"))
+	assert.IsError(t, err, ErrInvalidDeltaChange)
+
+	_, err = parseDeltaExpectedResults([]byte("- {_change: modified, id: 1}\n"))
+	assert.IsError(t, err, ErrInvalidDeltaChange)
+}
+
+func TestParseAssertions(t *testing.T) {
+	input := `# Assertions
+
+## Description
+
+Assertions check counts without listing rows.
+
+## SQL
+
+` + "```sql" + `
+UPDATE orders SET status = 'paid' WHERE id = 1;
+` + "```" + `
+
+## Test Cases
+
+### Pay order
+
+**Assertions:**
+` + "```" + `
+expect_count: orders = 5
+expect: SELECT COUNT(*) FROM orders WHERE status = 'paid' == 3
+` + "```" + `
+`
+
+	doc, err := Parse(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(doc.TestCases))
+
+	assertions := doc.TestCases[0].Assertions
+	assert.Equal(t, 2, len(assertions))
+	assert.Equal(t, "orders", assertions[0].Table)
+	assert.Equal(t, any(uint64(5)), assertions[0].Expected)
+	assert.Equal(t, "SELECT COUNT(*) FROM orders WHERE status = 'paid'", assertions[1].Query)
+	assert.Equal(t, any(uint64(3)), assertions[1].Expected)
+}
+
+func TestParseAssertionsInvalid(t *testing.T) {
+	for _, content := range []string{
+		"expect_count: orders",
+		"expect_count: orders; DROP TABLE x = 1",
+		"expect: SELECT 1",
+		"expect_rows: orders = 1",
+		"",
+	} {
+		_, err := parseAssertions([]byte(content))
+		assert.IsError(t, err, ErrInvalidAssertion)
+	}
+}
+
+func TestParseDefaultsSection(t *testing.T) {
+	input := `# Defaults
+
+## Description
+
+Test cases inherit the defaults.
+
+## SQL
+
+` + "```sql" + `
+SELECT id FROM users WHERE tenant_id = /*= tenant_id */1 AND status = /*= status */'active';
+` + "```" + `
+
+## Defaults
+
+**Parameters:**
+` + "```yaml" + `
+tenant_id: 1
+status: active
+` + "```" + `
+
+**Fixtures: users**
+` + "```yaml" + `
+- {id: 1, tenant_id: 1, status: active}
+` + "```" + `
+
+## Test Cases
+
+### Inherits everything
+
+**Expected Results:**
+` + "```yaml" + `
+- {id: 1}
+` + "```" + `
+
+### Overrides status
+
+**Parameters:**
+` + "```yaml" + `
+status: inactive
+` + "```" + `
+
+**Fixtures: users[upsert]**
+` + "```yaml" + `
+- {id: 2, tenant_id: 1, status: inactive}
+` + "```" + `
+
+**Expected Results:**
+` + "```yaml" + `
+- {id: 2}
+` + "```" + `
+`
+
+	doc, err := Parse(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(doc.TestCases))
+
+	first := doc.TestCases[0]
+	assert.True(t, first.HasParameters)
+	assert.Equal(t, map[string]any{"tenant_id": uint64(1), "status": "active"}, first.Parameters)
+	assert.Equal(t, 1, len(first.Fixtures))
+	assert.Equal(t, ClearInsert, first.Fixtures[0].Strategy)
+
+	second := doc.TestCases[1]
+	assert.Equal(t, map[string]any{"tenant_id": uint64(1), "status": "inactive"}, second.Parameters)
+	assert.Equal(t, 2, len(second.Fixtures))
+	assert.Equal(t, ClearInsert, second.Fixtures[0].Strategy)
+	assert.Equal(t, Upsert, second.Fixtures[1].Strategy)
+
+	// The defaults themselves are not modified by a test case's overrides
+	assert.Equal(t, "active", doc.Defaults.Parameters["status"])
+}
+
+func TestParseDefaultsSectionRejectsOtherBlocks(t *testing.T) {
+	input := `# Defaults
+
+## Description
+
+Defaults only accept parameters and fixtures.
+
+## SQL
+
+` + "```sql" + `
+SELECT 1;
+` + "```" + `
+
+## Defaults
+
+**Expected Results:**
+` + "```yaml" + `
+- {id: 1}
+` + "```" + `
+`
+
+	_, err := Parse(strings.NewReader(input))
+	assert.IsError(t, err, ErrInvalidDefaultsSection)
+}
+
+func TestParseMatrix(t *testing.T) {
+	input := `# Matrix
+
+## Description
+
+A matrix expands into sub-cases.
+
+## SQL
+
+` + "```sql" + `
+SELECT id FROM users WHERE tenant_id = /*= tenant_id */1 AND status = /*= status */'active';
+` + "```" + `
+
+## Test Cases
+
+### Users by status
+
+**Parameters:**
+` + "```yaml" + `
+tenant_id: 1
+status: active
+` + "```" + `
+
+**Matrix:**
+` + "```yaml" + `
+- name: active
+  expected:
+    - {id: 1}
+- name: inactive
+  parameters: {status: inactive}
+  expected: []
+- parameters: {tenant_id: 2}
+` + "```" + `
+
+**Expected Results:**
+` + "```yaml" + `
+- {id: 9}
+` + "```" + `
+`
+
+	doc, err := Parse(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(doc.TestCases))
+
+	active := doc.TestCases[0]
+	assert.Equal(t, "Users by status/active", active.Name)
+	assert.Equal(t, map[string]any{"tenant_id": uint64(1), "status": "active"}, active.Parameters)
+	assert.Equal(t, []map[string]any{{"id": uint64(1)}}, active.ExpectedResult)
+
+	inactive := doc.TestCases[1]
+	assert.Equal(t, "Users by status/inactive", inactive.Name)
+	assert.Equal(t, map[string]any{"tenant_id": uint64(1), "status": "inactive"}, inactive.Parameters)
+	assert.Equal(t, 0, len(inactive.ExpectedResult))
+	assert.Equal(t, 1, len(inactive.ExpectedResults))
+
+	// Entries without expected rows inherit the test case's expectation
+	unnamed := doc.TestCases[2]
+	assert.Equal(t, "Users by status/#3", unnamed.Name)
+	assert.Equal(t, map[string]any{"tenant_id": uint64(2), "status": "active"}, unnamed.Parameters)
+	assert.Equal(t, []map[string]any{{"id": uint64(9)}}, unnamed.ExpectedResult)
+}
+
+func TestParseMatrixInvalid(t *testing.T) {
+	for _, content := range []string{
+		"[]",
+		"- name: a\n- name: a\n",
+		"- name: a\n  params: {id: 1}\n",
+	} {
+		_, err := parseMatrix([]byte(content))
+		assert.IsError(t, err, ErrInvalidMatrix)
+	}
+}
+
+func TestExpandMatrixIsolatesSubCases(t *testing.T) {
+	testCase := &TestCase{
+		Name:       "Users",
+		Parameters: map[string]any{"filter": map[string]any{"status": "active"}},
+		Fixtures: []TableFixture{
+			{TableName: "users", Strategy: ClearInsert, Data: []map[string]any{{"id": 1, "tags": []any{"a"}}}},
+		},
+		ExpectedResult:  []map[string]any{{"id": 1}},
+		ExpectedResults: []ExpectedResultSpec{{Strategy: "all", Data: []map[string]any{{"id": 1}}}},
+		Matrix: []MatrixEntry{
+			{Name: "a", Parameters: map[string]any{"limit": 1}},
+			{Name: "b", Parameters: map[string]any{"limit": 2}},
+		},
+	}
+
+	subCases := expandMatrix(testCase)
+	assert.Equal(t, 2, len(subCases))
+
+	a := subCases[0]
+	a.Parameters["filter"].(map[string]any)["status"] = "inactive"
+	a.Fixtures[0].Data[0]["id"] = 2
+	a.Fixtures[0].Data[0]["tags"].([]any)[0] = "z"
+	a.Fixtures = append(a.Fixtures, TableFixture{TableName: "posts"})
+	a.ExpectedResult[0]["id"] = 2
+	a.ExpectedResults[0].Data[0]["id"] = 2
+
+	b := subCases[1]
+	assert.Equal(t, map[string]any{"filter": map[string]any{"status": "active"}, "limit": 2}, b.Parameters)
+	assert.Equal(t, []map[string]any{{"id": 1, "tags": []any{"a"}}}, b.Fixtures[0].Data)
+	assert.Equal(t, 1, len(b.Fixtures))
+	assert.Equal(t, []map[string]any{{"id": 1}}, b.ExpectedResult)
+	assert.Equal(t, []map[string]any{{"id": 1}}, b.ExpectedResults[0].Data)
+
+	// The source test case is left untouched as well
+	assert.Equal(t, any(map[string]any{"status": "active"}), testCase.Parameters["filter"])
+	assert.Equal(t, any(1), testCase.Fixtures[0].Data[0]["id"])
+}
+
+func TestParseScenarioSteps(t *testing.T) {
+	input := `# Notification Workflow
+
+## Description
+
+Create then cancel a notification.
+
+## SQL
+
+` + "```sql" + `
+SELECT id, status FROM notifications;
+` + "```" + `
+
+## Test Cases
+
+### Create then cancel
+
+**Fixtures: notifications**
+` + "```yaml" + `
+- {id: 1, status: sent}
+` + "```" + `
+
+**Step:** Create_Notification.snap.sql
+
+**Parameters:**
+` + "```yaml" + `
+id: 2
+` + "```" + `
+
+**Expected Results:**
+` + "```yaml" + `
+- {id: 2}
+` + "```" + `
+
+**Step: cancel_notification**
+
+**Parameters:**
+` + "```yaml" + `
+id: 2
+` + "```" + `
+
+**Expected Results: notifications[pk-match]**
+` + "```yaml" + `
+- {id: 2, status: canceled}
+` + "```" + `
+
+**Step:**
+
+**Expected Results:**
+` + "```yaml" + `
+- {id: 1, status: sent}
+- {id: 2, status: canceled}
+` + "```" + `
+
+**Assertions:**
+` + "```" + `
+expect_count: notifications = 2
+` + "```" + `
+`
+
+	doc, err := Parse(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(doc.TestCases))
+
+	tc := doc.TestCases[0]
+	assert.Equal(t, 1, len(tc.Fixtures))
+	assert.Equal(t, 0, len(tc.ExpectedResults))
+	assert.Equal(t, 1, len(tc.Assertions), "sections other than parameters and expected results belong to the test case")
+	assert.Equal(t, 3, len(tc.Steps))
+
+	assert.Equal(t, "Create_Notification.snap.sql", tc.Steps[0].Template)
+	assert.Equal(t, map[string]any{"id": uint64(2)}, tc.Steps[0].Parameters)
+	assert.Equal(t, []map[string]any{{"id": uint64(2)}}, tc.Steps[0].ExpectedResult)
+
+	assert.Equal(t, "cancel_notification", tc.Steps[1].Template)
+	assert.Equal(t, 1, len(tc.Steps[1].ExpectedResults))
+	assert.Equal(t, "notifications", tc.Steps[1].ExpectedResults[0].TableName)
+	assert.Equal(t, 0, len(tc.Steps[1].ExpectedResult))
+
+	assert.Equal(t, "", tc.Steps[2].Template)
+	assert.False(t, tc.Steps[2].HasParameters)
+	assert.Equal(t, 2, len(tc.Steps[2].ExpectedResult))
+}
+
+func TestParseScenarioStepsValidation(t *testing.T) {
+	header := "# Scenario\n\n## Description\n\nSteps.\n\n## SQL\n\n```sql\nSELECT 1;\n```\n\n## Test Cases\n\n### Case\n\n"
+
+	tests := []struct {
+		name string
+		body string
+		want error
+	}{
+		{
+			name: "unnamed expected results before the first step",
+			body: "**Expected Results:**\n```yaml\n- {id: 1}\n```\n\n**Step:** a.snap.sql\n\n**Expected Results:**\n```yaml\n- {id: 1}\n```\n",
+			want: ErrUnnamedExpectedResultsOutsideStep,
+		},
+		{
+			name: "expected error",
+			body: "**Step:** a.snap.sql\n\n**Expected Error:** unique violation\n",
+			want: ErrStepsWithExpectedError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(header + tt.body))
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want.Error())
+		})
+	}
+}
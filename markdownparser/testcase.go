@@ -2,7 +2,10 @@ package markdownparser
 
 import (
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -16,27 +19,63 @@ type InsertStrategy string
 const (
 	// ClearInsert truncates the table then inserts data (default)
 	ClearInsert InsertStrategy = "clear-insert"
+	// Truncate empties the table and resets its identity counters, then inserts data
+	Truncate InsertStrategy = "truncate"
 	// Upsert inserts data into the table, or updates if the row already exists
 	Upsert InsertStrategy = "upsert"
 	// Delete deletes rows in the table that match the dataset's primary keys
 	Delete InsertStrategy = "delete"
 )
 
+// DeferConstraintsOption is the fixture option that defers foreign key checks so that tables
+// referencing each other can be loaded in any order, e.g. "fixtures: users[defer-constraints]".
+const DeferConstraintsOption = "defer-constraints"
+
+// AutofillOption is the fixture option that fills the non-null columns a row omits with
+// type-appropriate defaults, e.g. "fixtures: users[autofill: true]".
+const AutofillOption = "autofill"
+
 // TableFixture represents fixture data for a single table with its insert strategy
 type TableFixture struct {
 	TableName    string
 	Strategy     InsertStrategy
 	Data         []map[string]any
-	ExternalFile string // when fixture rows are provided via external YAML/JSON link
+	ExternalFile string // when fixture rows are provided via external YAML/JSON/CSV/TSV link
 	Line         int    // Source line of the fixture block
+	Count        int    // Replicates the rows as a template this many times (0 = as written)
+	// DeferConstraints postpones foreign key checks while the fixtures of the test case are loaded
+	DeferConstraints bool
+	// Autofill fills the non-null columns missing from the rows with defaults of their type
+	Autofill bool
 }
 
 // ExpectedResultSpec represents expected result for a table with strategy and data
 type ExpectedResultSpec struct {
-	TableName    string
-	Strategy     string           // "all", "pk-match", "pk-exists", "pk-not-exists"
-	Data         []map[string]any // 値比較特殊指定（[null],[notnull],[any],[regexp,...]）含む
-	ExternalFile string           // 外部ファイル参照時のパス
+	TableName     string
+	Strategy      string           // "all", "pk-match", "pk-exists", "pk-not-exists", "delta"
+	Data          []map[string]any // 値比較特殊指定（[null],[notnull],[any],[regexp,...]）含む
+	ExternalFile  string           // 外部ファイル参照時のパス
+	Ordered       *bool            // 行の順序を比較するか（nilならテストケースのResultOrderedに従う）
+	OrderBy       []string         // 比較前に期待値・実際の行の両方をこの列で並べ替える
+	IgnoreColumns []string         // 比較から除外する列（updated_at など変化する列）
+	OnlyColumns   []string         // 指定した場合はこの列（と主キー）だけを比較する
+}
+
+// DeltaChangeKey is the column that holds the change kind ("added", "updated", "deleted") of
+// each row of a delta expectation
+const DeltaChangeKey = "_change"
+
+// TestDefaults holds the "## Defaults" section of a markdown file: parameters and fixtures that
+// every test case inherits and may override.
+type TestDefaults struct {
+	Parameters map[string]any
+	Fixtures   []TableFixture
+}
+
+// FixtureSetup holds the fixtures of a markdown file's "## Setup" section. They are inserted once
+// per file and every test case of the file runs inside a SAVEPOINT that is rolled back afterwards.
+type FixtureSetup struct {
+	Fixtures []TableFixture
 }
 
 // TestCase represents a single test case
@@ -51,12 +90,44 @@ type TestCase struct {
 	ExpectedResult     []map[string]any     // 従来型（無名配列）
 	ExpectedResults    []ExpectedResultSpec // 新型（テーブル名・戦略付き）
 	ExpectedError      *string              // 期待されるエラータイプ（normalized form）
+	ExpectedErrorSpec  *ExpectedError       // YAMLブロックで指定された詳細な期待エラー（SQLSTATE・制約名など）
 	SourceFile         string               // 元となるMarkdownファイルのパス
 	Line               int                  // 見出し行番号（1-origin）
 	PreparedSQL        string               // 方言・条件適用後に評価されたSQL
 	SQLArgs            []any                // PreparedSQLに対応するパラメータ
 	ResultOrdered      bool
 	SlowQueryThreshold time.Duration
+	AssertScoped       string            // 実行SQLが必ず絞り込むべきスコープカラム（例: tenant_id）
+	Setup              *FixtureSetup     // ファイル共通のSetup fixture（同じファイルのテストケース間で共有）
+	Assertions         []QueryAssertion  // 行を列挙しない軽量な検証（expect_count / expect）
+	Matrix             []MatrixEntry     // パラメータと期待結果を変えたサブケースへの展開（解析後は空）
+	Now                time.Time         // テスト実行時の現在時刻（ゼロ値なら実時刻）
+	MaxDuration        time.Duration     // メインクエリの実行時間の上限（ゼロなら無制限）
+	VerifyQueries      []VerifyQuerySpec // 名前付き検証クエリ（```sql verify:<name>）
+	Steps              []TestStep        // 順に実行するクエリ（**Step: <template>**）。空なら通常のテストケース
+}
+
+// TestStep is one query of a scenario test case (**Step: <template>**). The steps run in order in
+// the test case's transaction after its fixtures, and each step validates its own expected results
+// against the database state at that point.
+type TestStep struct {
+	Template        string               // テンプレートのパス（Markdownファイルからの相対パス。空ならファイル自身のSQL）
+	Line            int                  // Source line of the step marker
+	Parameters      map[string]any       // ステップのパラメータ（テストケースのパラメータは引き継がない）
+	HasParameters   bool                 // Parametersセクションがあるか
+	ExpectedResult  []map[string]any     // ステップのクエリ結果の期待値（無名）
+	ExpectedResults []ExpectedResultSpec // ステップ実行後のテーブル状態などの期待値
+	PreparedSQL     string               // 方言・条件適用後に評価されたSQL
+	SQLArgs         []any                // PreparedSQLに対応するパラメータ
+	ResultOrdered   bool
+}
+
+// VerifyQuerySpec is a named verify query (a ```sql verify:<name> block). Its rows are validated
+// against the expected results section of the same name (**Expected Results: <name>**).
+type VerifyQuerySpec struct {
+	Name  string
+	Query string
+	Line  int
 }
 
 // TestSection represents a section within a test case
@@ -64,15 +135,23 @@ type TestSection struct {
 	Type      string         // "parameters", "expected", "fixtures"
 	TableName string         // Only used for CSV fixtures
 	Strategy  InsertStrategy // Insert strategy for fixtures
+	Count     int            // Template replication count for fixtures
+	// DeferConstraints is set by the defer-constraints fixture option
+	DeferConstraints bool
+	// Autofill is set by the autofill fixture option
+	Autofill bool
 }
 
 // parseTestCasesFromAST parses test cases from AST nodes
-func parseTestCasesFromAST(nodes []ast.Node, content []byte, mapper *indexToLine) ([]TestCase, error) {
+// Warnings about deprecated syntax are appended to warnings.
+func parseTestCasesFromAST(nodes []ast.Node, content []byte, mapper *indexToLine, warnings *[]string) ([]TestCase, error) {
 	var (
 		testCases       []TestCase
 		currentTestCase *TestCase
 		errors          []error
 		currentSection  TestSection
+		// currentStep is the index of the step that receives parameters and expected results (-1: the test case)
+		currentStep = -1
 	)
 
 	for _, node := range nodes {
@@ -80,12 +159,14 @@ func parseTestCasesFromAST(nodes []ast.Node, content []byte, mapper *indexToLine
 		case *ast.Heading:
 			// Save previous test case if exists
 			if currentTestCase != nil {
-				err := validateTestCase(currentTestCase)
-				if err != nil {
-					errors = append(errors, err)
-				}
+				for _, testCase := range expandMatrix(currentTestCase) {
+					err := validateTestCase(&testCase)
+					if err != nil {
+						errors = append(errors, err)
+					}
 
-				testCases = append(testCases, *currentTestCase)
+					testCases = append(testCases, testCase)
+				}
 			}
 
 			// Start new test case
@@ -103,6 +184,7 @@ func parseTestCasesFromAST(nodes []ast.Node, content []byte, mapper *indexToLine
 			}
 
 			currentSection = TestSection{}
+			currentStep = -1
 
 		case *ast.Paragraph:
 			// Check for section markers in emphasis nodes
@@ -114,11 +196,11 @@ func parseTestCasesFromAST(nodes []ast.Node, content []byte, mapper *indexToLine
 
 					if strings.HasPrefix(text, "parameters:") || text == "params:" || strings.HasPrefix(text, "input parameters:") {
 						currentSection = TestSection{Type: "parameters"}
-					} else if strings.HasPrefix(text, "expected error:") {
+					} else if label, ok := expectedErrorLabel(text); ok {
 						// Extract error type from the same paragraph
 						fullText := extractTextFromNode(n, content)
-						if idx := strings.Index(strings.ToLower(fullText), "expected error:"); idx >= 0 {
-							errorText := strings.TrimSpace(fullText[idx+len("expected error:"):])
+						if idx := strings.Index(strings.ToLower(fullText), label); idx >= 0 {
+							errorText := strings.TrimSpace(fullText[idx+len(label):])
 							if errorText != "" {
 								parsedError, err := ParseExpectedError(errorText)
 								if err != nil {
@@ -138,48 +220,94 @@ func parseTestCasesFromAST(nodes []ast.Node, content []byte, mapper *indexToLine
 								currentSection.TableName = spec
 							}
 						}
+					} else if strings.HasPrefix(text, "now:") {
+						// 値は同じ段落に書く: **Now:** 2024-06-01T00:00:00Z
+						fullText := extractTextFromNode(n, content)
+						if idx := strings.Index(strings.ToLower(fullText), "now:"); idx >= 0 {
+							now, err := parseNow(fullText[idx+len("now:"):])
+							if err != nil {
+								errors = append(errors, fmt.Errorf("in test case %q: %w", currentTestCase.Name, err))
+							} else {
+								currentTestCase.Now = now
+							}
+						}
+
+						currentSection = TestSection{}
+					} else if label, ok := maxDurationLabel(text); ok {
+						// 値は同じ段落に書く: **Max Duration:** 50ms
+						fullText := extractTextFromNode(n, content)
+						if idx := strings.Index(strings.ToLower(fullText), label); idx >= 0 {
+							maxDuration, err := parseMaxDuration(fullText[idx+len(label):])
+							if err != nil {
+								errors = append(errors, fmt.Errorf("in test case %q: invalid max duration: %w", currentTestCase.Name, err))
+							} else {
+								currentTestCase.MaxDuration = maxDuration
+							}
+						}
+
+						currentSection = TestSection{}
+					} else if text == "step" || strings.HasPrefix(text, "step:") {
+						// テンプレートは同じ段落に書く: **Step:** notifications/create.snap.md
+						// 大文字小文字を保つため元のテキストから取り出す
+						fullText := extractTextFromNode(n, content)
+						template := ""
+						if idx := strings.Index(strings.ToLower(fullText), "step:"); idx >= 0 {
+							template = strings.TrimSpace(fullText[idx+len("step:"):])
+						}
+
+						step := TestStep{Template: template, Parameters: make(map[string]any)}
+						if lines := n.Lines(); lines != nil && lines.Len() > 0 {
+							step.Line = mapper.lineFor(lines.At(0).Start)
+						}
+
+						currentTestCase.Steps = append(currentTestCase.Steps, step)
+						currentStep = len(currentTestCase.Steps) - 1
+						currentSection = TestSection{}
+					} else if text == "matrix:" {
+						currentSection = TestSection{Type: "matrix"}
+					} else if text == "assertions:" {
+						currentSection = TestSection{Type: "assertions"}
 					} else if strings.HasPrefix(text, "verify query:") || strings.HasPrefix(text, "verification query:") {
 						currentSection = TestSection{Type: "verify_query"}
 					} else if strings.HasPrefix(text, "fixtures") {
-						currentSection = TestSection{Type: "fixtures", Strategy: ClearInsert} // デフォルト戦略
-						// Extract table name and strategy if present
-						if i := strings.Index(text, ":"); i >= 0 {
-							tableSpec := strings.TrimSpace(text[i+1:])
-							if tableSpec != "" {
-								tableName, strategy := parseTableNameAndStrategy(tableSpec)
-								currentSection.TableName = tableName
-								currentSection.Strategy = strategy
-							}
+						section, err := newFixtureSection(text, currentTestCase.Name, warnings)
+						if err != nil {
+							errors = append(errors, fmt.Errorf("in test case %q: %w", currentTestCase.Name, err))
 						}
+
+						currentSection = section
 					}
 				}
 			}
 
 		case *ast.FencedCodeBlock:
-			if currentTestCase != nil && currentSection.Type != "" {
-				var info string
-				if n.Info != nil {
-					info = strings.ToLower(strings.TrimSpace(string(n.Info.Value(content))))
+			if currentTestCase == nil {
+				break
+			}
+
+			// 名前付き検証クエリはラベルなしでも書ける: ```sql verify:orders
+			if name, ok := verifyQueryName(n, content); ok {
+				_, code, sectionLine := readFencedCodeBlock(n, content, mapper)
+
+				if err := addVerifyQuery(currentTestCase, name, code, sectionLine); err != nil {
+					errors = append(errors, fmt.Errorf("in test case %q: %w", currentTestCase.Name, err))
 				}
 
-				var codeContent strings.Builder
+				currentSection = TestSection{}
 
-				lines := n.Lines()
-				for i := range lines.Len() {
-					line := lines.At(i)
-					codeContent.Write(line.Value(content))
+				break
+			}
 
-					if i < lines.Len()-1 {
-						codeContent.WriteString("\n")
-					}
-				}
+			if currentSection.Type != "" {
+				info, code, sectionLine := readFencedCodeBlock(n, content, mapper)
 
-				sectionLine := -1
-				if lines != nil && lines.Len() > 0 {
-					sectionLine = mapper.lineFor(lines.At(0).Start)
+				var err error
+				if currentStep >= 0 && (currentSection.Type == "parameters" || currentSection.Type == "expected") {
+					err = processStepSection(currentTestCase, &currentTestCase.Steps[currentStep], currentSection, info, code, sectionLine)
+				} else {
+					err = processTestSection(currentTestCase, currentSection, info, code, sectionLine)
 				}
 
-				err := processTestSection(currentTestCase, currentSection, info, []byte(codeContent.String()), sectionLine)
 				if err != nil {
 					errors = append(errors, fmt.Errorf("in test case %q: %w", currentTestCase.Name, err))
 				}
@@ -192,12 +320,14 @@ func parseTestCasesFromAST(nodes []ast.Node, content []byte, mapper *indexToLine
 
 	// Handle last test case
 	if currentTestCase != nil {
-		err := validateTestCase(currentTestCase)
-		if err != nil {
-			errors = append(errors, err)
-		}
+		for _, testCase := range expandMatrix(currentTestCase) {
+			err := validateTestCase(&testCase)
+			if err != nil {
+				errors = append(errors, err)
+			}
 
-		testCases = append(testCases, *currentTestCase)
+			testCases = append(testCases, testCase)
+		}
 	}
 
 	// If there were any errors, return them
@@ -216,6 +346,362 @@ func parseTestCasesFromAST(nodes []ast.Node, content []byte, mapper *indexToLine
 	return testCases, nil
 }
 
+// parseSetupFromAST parses the "## Setup" section, which holds fixture blocks shared by all test cases in the file
+func parseSetupFromAST(nodes []ast.Node, content []byte, mapper *indexToLine, warnings *[]string) (*FixtureSetup, error) {
+	setup, err := parseSharedBlocksFromAST(nodes, content, mapper, "Setup", false, ErrInvalidSetupSection, warnings)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(setup.Fixtures) == 0 {
+		return nil, nil //nolint:nilnil // an empty setup section shares nothing
+	}
+
+	return &FixtureSetup{Fixtures: setup.Fixtures}, nil
+}
+
+// parseDefaultsFromAST parses the "## Defaults" section, which holds parameters and fixtures every
+// test case of the file inherits
+func parseDefaultsFromAST(nodes []ast.Node, content []byte, mapper *indexToLine, warnings *[]string) (*TestDefaults, error) {
+	defaults, err := parseSharedBlocksFromAST(nodes, content, mapper, "Defaults", true, ErrInvalidDefaultsSection, warnings)
+	if err != nil {
+		return nil, err
+	}
+
+	if !defaults.HasParameters && len(defaults.Fixtures) == 0 {
+		return nil, nil //nolint:nilnil // an empty defaults section shares nothing
+	}
+
+	return &TestDefaults{Parameters: defaults.Parameters, Fixtures: defaults.Fixtures}, nil
+}
+
+// parseSharedBlocksFromAST collects the fixture (and optionally parameter) blocks of a document-level
+// section into a scratch test case. Any other bold label is rejected with invalid.
+func parseSharedBlocksFromAST(nodes []ast.Node, content []byte, mapper *indexToLine, name string, allowParameters bool, invalid error, warnings *[]string) (*TestCase, error) {
+	shared := &TestCase{Name: name, Fixture: make(map[string][]map[string]any), Parameters: make(map[string]any)}
+
+	var currentSection TestSection
+
+	for _, node := range nodes {
+		switch n := node.(type) {
+		case *ast.Paragraph:
+			emphasis := findFirstEmphasis(n)
+			if emphasis == nil {
+				continue
+			}
+
+			text := strings.ToLower(strings.TrimSpace(extractTextFromNode(emphasis, content)))
+
+			switch {
+			case strings.HasPrefix(text, "fixtures"):
+				section, err := newFixtureSection(text, name, warnings)
+				if err != nil {
+					return nil, err
+				}
+
+				currentSection = section
+			case allowParameters && (text == "parameters:" || text == "params:"):
+				currentSection = TestSection{Type: "parameters"}
+			default:
+				return nil, fmt.Errorf("%w: %q", invalid, extractTextFromNode(emphasis, content))
+			}
+
+		case *ast.FencedCodeBlock:
+			if currentSection.Type == "" {
+				continue
+			}
+
+			info, code, sectionLine := readFencedCodeBlock(n, content, mapper)
+			if err := processTestSection(shared, currentSection, info, code, sectionLine); err != nil {
+				return nil, err
+			}
+
+			currentSection = TestSection{}
+		}
+	}
+
+	return shared, nil
+}
+
+// applyDefaults merges the document defaults into a test case. Parameters are merged key by key with
+// the test case's values taking precedence. Default fixtures run before the test case's own fixtures,
+// so a case overrides them per table with its own strategy (clear-insert replaces the rows, upsert
+// overrides rows by primary key).
+func applyDefaults(testCase *TestCase, defaults *TestDefaults) {
+	if defaults == nil {
+		return
+	}
+
+	if len(defaults.Parameters) > 0 {
+		params := make(map[string]any, len(defaults.Parameters)+len(testCase.Parameters))
+		maps.Copy(params, defaults.Parameters)
+		maps.Copy(params, testCase.Parameters)
+		testCase.Parameters = params
+		testCase.HasParameters = true
+	}
+
+	if len(defaults.Fixtures) > 0 {
+		fixtures := make([]TableFixture, 0, len(defaults.Fixtures)+len(testCase.Fixtures))
+		fixtures = append(fixtures, defaults.Fixtures...)
+		testCase.Fixtures = append(fixtures, testCase.Fixtures...)
+	}
+}
+
+// newFixtureSection builds a fixtures section from a marker such as "fixtures: users[upsert]".
+// Deprecated fixture options are accepted and reported to warnings with the owner's name.
+func newFixtureSection(text string, owner string, warnings *[]string) (TestSection, error) {
+	section := TestSection{Type: "fixtures", Strategy: ClearInsert} // デフォルト戦略
+
+	// Extract table name and strategy if present
+	if i := strings.Index(text, ":"); i >= 0 {
+		tableSpec := strings.TrimSpace(text[i+1:])
+		section.DeferConstraints = slices.Contains(fixtureOptionsIn(tableSpec), DeferConstraintsOption)
+		section.Autofill = fixtureAutofillIn(tableSpec)
+
+		// "fixtures: [defer-constraints]" sets options of a block holding several tables
+		if strings.HasPrefix(tableSpec, "[") && strings.HasSuffix(tableSpec, "]") {
+			strategy, _, err := parseFixtureOptions("", tableSpec[1:len(tableSpec)-1])
+			section.Strategy = strategy
+
+			return section, err
+		}
+
+		if tableSpec != "" {
+			tableName, strategy, count, err := parseFixtureSpec(tableSpec)
+
+			if err == nil && warnings != nil {
+				for _, option := range deprecatedFixtureOptionsIn(tableSpec) {
+					*warnings = append(*warnings, fmt.Sprintf("%s: fixture option %q for table %s is deprecated; use %q",
+						owner, option, tableName, deprecatedFixtureOptions[option]))
+				}
+			}
+
+			section.TableName = tableName
+			section.Strategy = strategy
+			section.Count = count
+
+			return section, err
+		}
+	}
+
+	return section, nil
+}
+
+// readFencedCodeBlock returns the info string, body and first line of a fenced code block
+func readFencedCodeBlock(n *ast.FencedCodeBlock, content []byte, mapper *indexToLine) (string, []byte, int) {
+	var info string
+	if n.Info != nil {
+		info = strings.ToLower(strings.TrimSpace(string(n.Info.Value(content))))
+	}
+
+	var codeContent strings.Builder
+
+	lines := n.Lines()
+	for i := range lines.Len() {
+		line := lines.At(i)
+		codeContent.Write(line.Value(content))
+
+		if i < lines.Len()-1 {
+			codeContent.WriteString("\n")
+		}
+	}
+
+	sectionLine := -1
+	if lines != nil && lines.Len() > 0 {
+		sectionLine = mapper.lineFor(lines.At(0).Start)
+	}
+
+	return info, []byte(codeContent.String()), sectionLine
+}
+
+// expectedResultsSpecPattern matches "table[options]", "table" and "[options]" of an Expected Results label.
+// Options may hold bracketed lists, e.g. users[pk-match, ignore_columns: [updated_at, uuid]].
+var expectedResultsSpecPattern = regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_]*)?(?:\[(.+)\])?$`)
+
+// parseExpectedResultsSpec parses the label suffix of an Expected Results section, e.g.
+// "users[pk-match]" or "[ordered: false]". Besides the strategy, the options accept
+// "ordered: true|false" and the column lists "order_by", "ignore_columns" and "only_columns".
+// A list is written in brackets ([a, b]) or as the names following the option.
+func parseExpectedResultsSpec(label string) (ExpectedResultSpec, error) {
+	spec := ExpectedResultSpec{Strategy: "all"}
+
+	matches := expectedResultsSpecPattern.FindStringSubmatch(strings.TrimSpace(label))
+	if len(matches) == 0 {
+		return spec, nil
+	}
+
+	spec.TableName = matches[1]
+	if matches[2] == "" {
+		return spec, nil
+	}
+
+	var (
+		list        *[]string // column list continued by the following bare names
+		hasStrategy bool
+	)
+
+	for _, option := range splitSpecOptions(matches[2]) {
+		key, value, ok := cutFixtureOption(option)
+		if !ok {
+			if list != nil {
+				*list = append(*list, option)
+				continue
+			}
+
+			if hasStrategy {
+				return ExpectedResultSpec{}, fmt.Errorf("%w: %q", ErrUnknownExpectedResultsOption, option)
+			}
+
+			spec.Strategy = option
+			hasStrategy = true
+
+			continue
+		}
+
+		list = nil
+
+		switch key {
+		case "ordered":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return ExpectedResultSpec{}, fmt.Errorf("%w: %q (expected ordered: true or false)", ErrUnknownExpectedResultsOption, option)
+			}
+
+			spec.Ordered = &b
+		case "order_by", "ignore_columns", "only_columns":
+			target := &spec.OrderBy
+
+			switch key {
+			case "ignore_columns":
+				target = &spec.IgnoreColumns
+			case "only_columns":
+				target = &spec.OnlyColumns
+			}
+
+			columns := specOptionList(value)
+			if len(columns) == 0 {
+				return ExpectedResultSpec{}, fmt.Errorf("%w: %q (%s needs a column)", ErrUnknownExpectedResultsOption, option, key)
+			}
+
+			*target = append(*target, columns...)
+
+			// Without brackets the names that follow belong to the list
+			if !strings.HasPrefix(value, "[") {
+				list = target
+			}
+		default:
+			return ExpectedResultSpec{}, fmt.Errorf("%w: %q", ErrUnknownExpectedResultsOption, option)
+		}
+	}
+
+	return spec, nil
+}
+
+// splitSpecOptions splits the options of a label at the commas outside brackets
+func splitSpecOptions(options string) []string {
+	var (
+		parts []string
+		depth int
+		start int
+	)
+
+	add := func(part string) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	for i, r := range options {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ',':
+			if depth == 0 {
+				add(options[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	add(options[start:])
+
+	return parts
+}
+
+// specOptionList returns the column names of a list option value: "[a, b]" or "a"
+func specOptionList(value string) []string {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		value = value[1 : len(value)-1]
+	}
+
+	var columns []string
+
+	for column := range strings.SplitSeq(value, ",") {
+		if column = strings.TrimSpace(column); column != "" {
+			columns = append(columns, column)
+		}
+	}
+
+	return columns
+}
+
+// verifyQueryName returns the name of a named verify query block (```sql verify:<name>)
+func verifyQueryName(n *ast.FencedCodeBlock, content []byte) (string, bool) {
+	if n.Info == nil {
+		return "", false
+	}
+
+	for field := range strings.FieldsSeq(strings.ToLower(string(n.Info.Value(content)))) {
+		if name, ok := strings.CutPrefix(field, "verify:"); ok && name != "" {
+			return name, true
+		}
+	}
+
+	return "", false
+}
+
+// addVerifyQuery adds a named verify query to testCase
+func addVerifyQuery(testCase *TestCase, name string, code []byte, line int) error {
+	for _, existing := range testCase.VerifyQueries {
+		if existing.Name == name {
+			return fmt.Errorf("%w: %q verify:%s", snapsql.ErrDuplicateVerifyQuery, testCase.Name, name)
+		}
+	}
+
+	testCase.VerifyQueries = append(testCase.VerifyQueries, VerifyQuerySpec{
+		Name:  name,
+		Query: strings.TrimSpace(string(code)),
+		Line:  line,
+	})
+
+	return nil
+}
+
+// expectedErrorLabel returns the label of an Expected Error marker ("expected error:" or "expect_error:")
+func expectedErrorLabel(text string) (string, bool) {
+	for _, label := range []string{"expected error:", "expect error:", "expect_error:"} {
+		if strings.HasPrefix(text, label) {
+			return label, true
+		}
+	}
+
+	return "", false
+}
+
+// maxDurationLabel reports whether text is a query time budget label and returns the matched label.
+func maxDurationLabel(text string) (string, bool) {
+	for _, label := range []string{"max duration:", "max_duration:"} {
+		if strings.HasPrefix(text, label) {
+			return label, true
+		}
+	}
+
+	return "", false
+}
+
 // findFirstEmphasis finds the first emphasis node (italic or bold) in a paragraph
 func findFirstEmphasis(paragraph *ast.Paragraph) *ast.Emphasis {
 	var emphasis *ast.Emphasis
@@ -255,21 +741,75 @@ func extractTextFromNode(node ast.Node, content []byte) string {
 // validateTestCase validates a test case for required sections and format
 func validateTestCase(testCase *TestCase) error {
 	// ExpectedError and ExpectedResults are mutually exclusive
-	hasResults := len(testCase.ExpectedResult) > 0 || len(testCase.ExpectedResults) > 0
+	hasResults := len(testCase.ExpectedResult) > 0 || len(testCase.ExpectedResults) > 0 || len(testCase.Assertions) > 0
 	hasError := testCase.ExpectedError != nil
 
+	if len(testCase.Steps) > 0 {
+		if hasError {
+			return fmt.Errorf("%w: test case %q", ErrStepsWithExpectedError, testCase.Name)
+		}
+
+		// The test case's own query is not executed, so only the steps' results can be compared
+		if _, ok := unnamedExpectedResults(testCase.ExpectedResults); ok || len(testCase.ExpectedResult) > 0 {
+			return fmt.Errorf("%w: test case %q", ErrUnnamedExpectedResultsOutsideStep, testCase.Name)
+		}
+
+		for _, step := range testCase.Steps {
+			hasResults = hasResults || len(step.ExpectedResult) > 0 || len(step.ExpectedResults) > 0
+		}
+	}
+
 	if hasResults && hasError {
 		return fmt.Errorf("%w: test case %q", ErrConflictingExpectations, testCase.Name)
 	}
 
-	// Either Expected Results or Expected Error must be specified
+	// Either Expected Results (or Assertions) or Expected Error must be specified
 	if !hasResults && !hasError {
-		return fmt.Errorf("%w: %q must specify either Expected Results or Expected Error", snapsql.ErrTestCaseMissingData, testCase.Name)
+		return fmt.Errorf("%w: %q must specify either Expected Results, Assertions or Expected Error", snapsql.ErrTestCaseMissingData, testCase.Name)
+	}
+
+	for _, verify := range testCase.VerifyQueries {
+		if !slices.ContainsFunc(testCase.ExpectedResults, func(spec ExpectedResultSpec) bool { return spec.TableName == verify.Name }) {
+			return fmt.Errorf("%w: %q in test case %q", ErrVerifyQueryWithoutExpectedResults, verify.Name, testCase.Name)
+		}
+	}
+
+	return nil
+}
+
+// processStepSection processes a parameters or expected results section that follows a step marker
+func processStepSection(testCase *TestCase, step *TestStep, section TestSection, format string, content []byte, line int) error {
+	scratch := &TestCase{
+		Name:            testCase.Name,
+		Parameters:      step.Parameters,
+		HasParameters:   step.HasParameters,
+		ExpectedResult:  step.ExpectedResult,
+		ExpectedResults: step.ExpectedResults,
 	}
 
+	if err := processTestSection(scratch, section, format, content, line); err != nil {
+		return err
+	}
+
+	step.Parameters = scratch.Parameters
+	step.HasParameters = scratch.HasParameters
+	step.ExpectedResult = scratch.ExpectedResult
+	step.ExpectedResults = scratch.ExpectedResults
+
 	return nil
 }
 
+// unnamedExpectedResults returns the expected results section without a table name
+func unnamedExpectedResults(specs []ExpectedResultSpec) (ExpectedResultSpec, bool) {
+	for _, spec := range specs {
+		if spec.TableName == "" {
+			return spec, true
+		}
+	}
+
+	return ExpectedResultSpec{}, false
+}
+
 // processTestSection processes a section of a test case
 func processTestSection(testCase *TestCase, section TestSection, format string, content []byte, line int) error {
 	switch section.Type {
@@ -292,25 +832,19 @@ func processTestSection(testCase *TestCase, section TestSection, format string,
 			return fmt.Errorf("%w: test case %q", ErrConflictingExpectations, testCase.Name)
 		}
 
-		if len(testCase.ExpectedResult) > 0 || len(testCase.ExpectedResults) > 0 {
-			return fmt.Errorf("%w in test case %q", ErrDuplicateExpectedResults, testCase.Name)
+		// Extract tableName, table-level strategy and comparison options from section.TableName if provided
+		spec, err := parseExpectedResultsSpec(section.TableName)
+		if err != nil {
+			return fmt.Errorf("in test case %q: %w", testCase.Name, err)
 		}
 
-		// Extract tableName and table-level strategy from section.TableName if provided
-		tableName := ""
-		strategy := "all"
-
-		if section.TableName != "" {
-			re := regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_]*)(?:\[([^\]]+)\])?$`)
-
-			matches := re.FindStringSubmatch(section.TableName)
-			if len(matches) > 0 {
-				tableName = matches[1]
+		tableName := spec.TableName
 
-				if len(matches) > 2 && matches[2] != "" {
-					strategy = matches[2]
-				}
-			}
+		// Sections for different tables (or named verify queries) may be combined
+		if (tableName == "" && len(testCase.ExpectedResult) > 0) || slices.ContainsFunc(testCase.ExpectedResults, func(spec ExpectedResultSpec) bool {
+			return spec.TableName == tableName
+		}) {
+			return fmt.Errorf("%w in test case %q", ErrDuplicateExpectedResults, testCase.Name)
 		}
 
 		contentStr := strings.TrimSpace(string(content))
@@ -331,23 +865,45 @@ func processTestSection(testCase *TestCase, section TestSection, format string,
 		} else {
 			var err error
 
-			results, err = parseExpectedResults(content)
+			if spec.Strategy == "delta" {
+				results, err = parseDeltaExpectedResults(content)
+			} else {
+				results, err = parseExpectedResults(content)
+			}
+
 			if err != nil {
 				return fmt.Errorf("failed to parse expected results in test case %q: %w", testCase.Name, err)
 			}
 		}
 
-		testCase.ExpectedResults = append(testCase.ExpectedResults, ExpectedResultSpec{
-			TableName:    tableName,
-			Strategy:     strategy,
-			Data:         results,
-			ExternalFile: externalFile,
-		})
+		spec.Data = results
+		spec.ExternalFile = externalFile
+		testCase.ExpectedResults = append(testCase.ExpectedResults, spec)
 
 		if tableName == "" {
 			testCase.ExpectedResult = results
 		}
 
+	case "matrix":
+		if len(testCase.Matrix) > 0 {
+			return fmt.Errorf("%w: duplicate matrix section in test case %q", ErrInvalidMatrix, testCase.Name)
+		}
+
+		entries, err := parseMatrix(content)
+		if err != nil {
+			return fmt.Errorf("failed to parse matrix in test case %q: %w", testCase.Name, err)
+		}
+
+		testCase.Matrix = entries
+
+	case "assertions":
+		assertions, err := parseAssertions(content)
+		if err != nil {
+			return fmt.Errorf("failed to parse assertions in test case %q: %w", testCase.Name, err)
+		}
+
+		testCase.Assertions = append(testCase.Assertions, assertions...)
+
 	case "verify_query":
 		if testCase.VerifyQuery != "" {
 			return fmt.Errorf("%w: %q", snapsql.ErrDuplicateVerifyQuery, testCase.Name)
@@ -355,7 +911,27 @@ func processTestSection(testCase *TestCase, section TestSection, format string,
 
 		testCase.VerifyQuery = strings.TrimSpace(string(content))
 
+	case "expected_error":
+		if testCase.ExpectedError != nil {
+			return fmt.Errorf("%w in test case %q", ErrDuplicateExpectedError, testCase.Name)
+		}
+
+		if len(testCase.ExpectedResult) > 0 || len(testCase.ExpectedResults) > 0 {
+			return fmt.Errorf("%w: test case %q", ErrConflictingExpectations, testCase.Name)
+		}
+
+		spec, err := ParseExpectedErrorSpec(content)
+		if err != nil {
+			return fmt.Errorf("in test case %q: %w", testCase.Name, err)
+		}
+
+		summary := spec.String()
+		testCase.ExpectedError = &summary
+		testCase.ExpectedErrorSpec = spec
+
 	case "fixtures":
+		firstFixture := len(testCase.Fixtures)
+
 		if format == "csv" {
 			if section.TableName == "" {
 				return fmt.Errorf("%w: %q", snapsql.ErrTableNameRequired, testCase.Name)
@@ -409,16 +985,24 @@ func processTestSection(testCase *TestCase, section TestSection, format string,
 					testCase.Fixture[entry.Name] = append(testCase.Fixture[entry.Name], entry.Rows...)
 
 					switch strategy {
-					case Upsert:
-						addOrUpdateTableFixture(testCase, entry.Name, Upsert, entry.Rows, line)
-					case Delete:
-						addOrUpdateTableFixture(testCase, entry.Name, Delete, entry.Rows, line)
+					case Truncate, Upsert, Delete:
+						addOrUpdateTableFixture(testCase, entry.Name, strategy, entry.Rows, line)
 					default:
 						addOrUpdateTableFixture(testCase, entry.Name, ClearInsert, entry.Rows, line)
 					}
 				}
 			}
 		}
+
+		for i := firstFixture; i < len(testCase.Fixtures); i++ {
+			testCase.Fixtures[i].DeferConstraints = section.DeferConstraints
+			testCase.Fixtures[i].Autofill = section.Autofill
+		}
+
+		// count applies to the single fixture block declared for the named table
+		if section.Count > 0 && section.TableName != "" && len(testCase.Fixtures) > 0 {
+			testCase.Fixtures[len(testCase.Fixtures)-1].Count = section.Count
+		}
 	}
 
 	return nil
@@ -427,33 +1011,146 @@ func processTestSection(testCase *TestCase, section TestSection, format string,
 // parseTableNameAndStrategy parses table name and insert strategy from fixture specification
 // Format: "table_name" or "table_name[strategy]"
 func parseTableNameAndStrategy(spec string) (string, InsertStrategy) {
-	// 正規表現でテーブル名と戦略を解析
-	// 形式: "table_name" または "table_name[strategy]"
+	tableName, strategy, _, _ := parseFixtureSpec(spec)
+	return tableName, strategy
+}
+
+// parseFixtureSpec parses table name, insert strategy and template count from fixture specification
+// Format: "table_name", "table_name[strategy]" or "table_name[strategy, count: N]"
+func parseFixtureSpec(spec string) (string, InsertStrategy, int, error) {
+	// 正規表現でテーブル名とオプションを解析
+	// 形式: "table_name" または "table_name[strategy, count: N]"
 	re := regexp.MustCompile(`^([a-zA-Z_][a-zA-Z0-9_]*)(?:\[([^\]]+)\])?$`)
 	matches := re.FindStringSubmatch(strings.TrimSpace(spec))
 
 	if len(matches) == 0 {
 		// 無効な形式の場合はそのままテーブル名として扱い、デフォルト戦略を使用
-		return spec, ClearInsert
+		return spec, ClearInsert, 0, nil
 	}
 
 	tableName := matches[1]
+	strategy, count, err := parseFixtureOptions(tableName, matches[2])
+
+	return tableName, strategy, count, err
+}
+
+// parseFixtureOptions parses the comma separated options between the brackets of a fixture specification
+func parseFixtureOptions(tableName string, options string) (InsertStrategy, int, error) {
 	strategy := ClearInsert // デフォルト
+	count := 0
+
+	if options == "" {
+		return strategy, count, nil
+	}
+
+	for _, option := range strings.Split(options, ",") {
+		option = strings.TrimSpace(option)
+
+		if key, value, ok := cutFixtureOption(option); ok {
+			if key == AutofillOption {
+				if _, err := strconv.ParseBool(value); err != nil {
+					return strategy, 0, fmt.Errorf("%w: %q for table %s (expected autofill: true or false)", ErrUnknownFixtureOption, option, tableName)
+				}
+
+				continue
+			}
 
-	if len(matches) > 2 && matches[2] != "" {
-		switch InsertStrategy(matches[2]) {
-		case Upsert:
-			strategy = Upsert
-		case Delete:
-			strategy = Delete
-		case ClearInsert:
-			strategy = ClearInsert
+			if key != "count" {
+				return strategy, 0, fmt.Errorf("%w: %q for table %s", ErrUnknownFixtureOption, option, tableName)
+			}
+
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return strategy, 0, fmt.Errorf("%w: %q for table %s", ErrInvalidFixtureCount, value, tableName)
+			}
+
+			count = n
+
+			continue
+		}
+
+		if option == DeferConstraintsOption || option == AutofillOption {
+			continue
+		}
+
+		if alias, ok := deprecatedFixtureOptions[option]; ok {
+			strategy = alias
+
+			continue
+		}
+
+		switch InsertStrategy(option) {
+		case ClearInsert, Truncate, Upsert, Delete:
+			strategy = InsertStrategy(option)
 		default:
-			strategy = ClearInsert
+			return ClearInsert, 0, fmt.Errorf("%w: %q for table %s (expected clear-insert, truncate, upsert, delete, defer-constraints, autofill or count: N)", ErrUnknownFixtureOption, option, tableName)
 		}
 	}
 
-	return tableName, strategy
+	return strategy, count, nil
+}
+
+// deprecatedFixtureOptions maps fixture options from older documentation to the strategy they
+// stand for. Every test already runs in a transaction that is rolled back, so "transaction-wrapped"
+// is the same as the default clear-insert.
+var deprecatedFixtureOptions = map[string]InsertStrategy{
+	"insert":              ClearInsert,
+	"transaction-wrapped": ClearInsert,
+}
+
+// deprecatedFixtureOptionsIn returns the deprecated options used in a fixture specification
+func deprecatedFixtureOptionsIn(spec string) []string {
+	var found []string
+
+	for _, option := range fixtureOptionsIn(spec) {
+		if _, ok := deprecatedFixtureOptions[option]; ok {
+			found = append(found, option)
+		}
+	}
+
+	return found
+}
+
+// fixtureOptionsIn returns the options between the brackets of a fixture specification
+func fixtureOptionsIn(spec string) []string {
+	start := strings.Index(spec, "[")
+	end := strings.LastIndex(spec, "]")
+
+	if start < 0 || end < start {
+		return nil
+	}
+
+	options := strings.Split(spec[start+1:end], ",")
+	for i, option := range options {
+		options[i] = strings.TrimSpace(option)
+	}
+
+	return options
+}
+
+// fixtureAutofillIn reports whether a fixture specification enables autofill, either as
+// "autofill" or "autofill: true"
+func fixtureAutofillIn(spec string) bool {
+	enabled := false
+
+	for _, option := range fixtureOptionsIn(spec) {
+		if option == AutofillOption {
+			enabled = true
+		} else if key, value, ok := cutFixtureOption(option); ok && key == AutofillOption {
+			enabled, _ = strconv.ParseBool(value)
+		}
+	}
+
+	return enabled
+}
+
+// cutFixtureOption splits "key: value" or "key=value"
+func cutFixtureOption(option string) (string, string, bool) {
+	if i := strings.IndexAny(option, ":="); i >= 0 {
+		return strings.TrimSpace(option[:i]), strings.TrimSpace(option[i+1:]), true
+	}
+
+	return "", "", false
 }
 
 // addOrUpdateTableFixture adds or updates fixture data for a table
@@ -0,0 +1,230 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shibukawa/snapsql"
+)
+
+// Apply runs the migrations that the version table of format does not list yet, in version order.
+// Each migration and its version record are committed in one transaction. The applied migrations are returned.
+func Apply(ctx context.Context, db *sql.DB, dialect snapsql.Dialect, format Format, migrations []Migration) ([]Migration, error) {
+	table, err := newVersionTable(dialect, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := db.ExecContext(ctx, table.createSQL()); err != nil {
+		return nil, fmt.Errorf("failed to create migration version table %s: %w", table.name(), err)
+	}
+
+	applied, err := table.appliedVersions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+
+	for _, m := range migrations {
+		if applied(m.Version) {
+			continue
+		}
+
+		if err := applyOne(ctx, db, table, m); err != nil {
+			return done, err
+		}
+
+		done = append(done, m)
+	}
+
+	return done, nil
+}
+
+func applyOne(ctx context.Context, db *sql.DB, table versionTable, m Migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %s: %w", m.Path, err)
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	started := time.Now()
+
+	if strings.TrimSpace(m.SQL) != "" {
+		if _, err := tx.ExecContext(ctx, m.SQL); err != nil {
+			return fmt.Errorf("failed to apply migration %s: %w", m.Path, err)
+		}
+	}
+
+	if err := table.record(ctx, tx, m, time.Since(started)); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", m.Path, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %s: %w", m.Path, err)
+	}
+
+	return nil
+}
+
+// versionTable mirrors the bookkeeping table of a migration tool so that the tool
+// sees the ephemeral database as migrated.
+type versionTable struct {
+	dialect snapsql.Dialect
+	format  Format
+}
+
+func newVersionTable(dialect snapsql.Dialect, format Format) (versionTable, error) {
+	switch dialect {
+	case snapsql.DialectPostgres, snapsql.DialectCockroach, snapsql.DialectMySQL, snapsql.DialectMariaDB, snapsql.DialectSQLite:
+	default:
+		return versionTable{}, fmt.Errorf("%w: %s", ErrUnsupportedDriver, dialect)
+	}
+
+	switch format {
+	case FormatGoose, FormatGolangMigrate, FormatAtlas:
+	default:
+		return versionTable{}, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+
+	return versionTable{dialect: dialect, format: format}, nil
+}
+
+func (t versionTable) name() string {
+	switch t.format {
+	case FormatGolangMigrate:
+		return "schema_migrations"
+	case FormatAtlas:
+		return "atlas_schema_revisions"
+	default:
+		return "goose_db_version"
+	}
+}
+
+func (t versionTable) createSQL() string {
+	switch t.format {
+	case FormatGolangMigrate:
+		return "CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)"
+	case FormatAtlas:
+		return "CREATE TABLE IF NOT EXISTS atlas_schema_revisions (version VARCHAR(255) NOT NULL PRIMARY KEY, description VARCHAR(255) NOT NULL, applied BIGINT NOT NULL DEFAULT 0, total BIGINT NOT NULL DEFAULT 0, executed_at TIMESTAMP NOT NULL, execution_time BIGINT NOT NULL)"
+	}
+
+	var id string
+
+	switch t.dialect {
+	case snapsql.DialectPostgres, snapsql.DialectCockroach:
+		id = "id SERIAL PRIMARY KEY"
+	case snapsql.DialectMySQL, snapsql.DialectMariaDB:
+		id = "id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY"
+	default:
+		id = "id INTEGER PRIMARY KEY AUTOINCREMENT"
+	}
+
+	return "CREATE TABLE IF NOT EXISTS goose_db_version (" + id + ", version_id BIGINT NOT NULL, is_applied BOOLEAN NOT NULL, tstamp TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP)"
+}
+
+// appliedVersions returns a predicate reporting whether a version is already applied.
+func (t versionTable) appliedVersions(ctx context.Context, db *sql.DB) (func(int64) bool, error) {
+	if t.format == FormatGolangMigrate {
+		// golang-migrate keeps a single row with the latest version
+		var (
+			current int64
+			dirty   bool
+		)
+
+		err := db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&current, &dirty)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			current = -1
+		case err != nil:
+			return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+		case dirty:
+			return nil, fmt.Errorf("%w: version %d", ErrDirtyDatabase, current)
+		}
+
+		return func(v int64) bool { return v <= current }, nil
+	}
+
+	query := "SELECT version_id FROM goose_db_version WHERE is_applied = " + t.boolLiteral(true)
+	if t.format == FormatAtlas {
+		query = "SELECT version FROM atlas_schema_revisions"
+	}
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", t.name(), err)
+	}
+	defer rows.Close()
+
+	versions := make(map[int64]bool)
+
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", t.name(), err)
+		}
+
+		if v, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			versions[v] = true
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", t.name(), err)
+	}
+
+	return func(v int64) bool { return versions[v] }, nil
+}
+
+func (t versionTable) record(ctx context.Context, tx *sql.Tx, m Migration, elapsed time.Duration) error {
+	switch t.format {
+	case FormatGolangMigrate:
+		if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations"); err != nil {
+			return err
+		}
+
+		_, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, dirty) VALUES ("+t.placeholder(1)+", "+t.boolLiteral(false)+")", m.Version)
+
+		return err
+	case FormatAtlas:
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO atlas_schema_revisions (version, description, applied, total, executed_at, execution_time) VALUES ("+
+				t.placeholder(1)+", "+t.placeholder(2)+", 1, 1, "+t.placeholder(3)+", "+t.placeholder(4)+")",
+			strconv.FormatInt(m.Version, 10), m.Name, time.Now().UTC(), elapsed.Nanoseconds())
+
+		return err
+	default:
+		_, err := tx.ExecContext(ctx, "INSERT INTO goose_db_version (version_id, is_applied) VALUES ("+t.placeholder(1)+", "+t.boolLiteral(true)+")", m.Version)
+
+		return err
+	}
+}
+
+func (t versionTable) placeholder(n int) string {
+	if t.dialect == snapsql.DialectPostgres || t.dialect == snapsql.DialectCockroach {
+		return "$" + strconv.Itoa(n)
+	}
+
+	return "?"
+}
+
+func (t versionTable) boolLiteral(v bool) string {
+	switch {
+	case t.dialect == snapsql.DialectSQLite && v:
+		return "1"
+	case t.dialect == snapsql.DialectSQLite:
+		return "0"
+	case v:
+		return "TRUE"
+	default:
+		return "FALSE"
+	}
+}
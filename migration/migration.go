@@ -0,0 +1,193 @@
+// Package migration reads migration directories written for goose, golang-migrate, and atlas
+// and applies the pending up migrations while maintaining each tool's version table.
+package migration
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Format identifies the migration tool whose file layout and version table are used.
+type Format string
+
+const (
+	FormatGoose         Format = "goose"
+	FormatGolangMigrate Format = "golang-migrate"
+	FormatAtlas         Format = "atlas"
+)
+
+var (
+	ErrUnknownFormat     = errors.New("unknown migration format")
+	ErrDuplicateVersion  = errors.New("duplicate migration version")
+	ErrDirtyDatabase     = errors.New("database is marked dirty by a failed migration")
+	ErrMissingGooseUp    = errors.New("goose migration has no '-- +goose Up' section")
+	ErrUnsupportedDriver = errors.New("migration version tables are not supported for dialect")
+)
+
+// Migration is a single up migration read from a migration directory.
+type Migration struct {
+	Version int64
+	Name    string
+	Path    string
+	// SQL is the up part of the migration; down sections are never included
+	SQL string
+}
+
+var (
+	versionedFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.sql$`)
+	migrateUpPattern     = regexp.MustCompile(`^(\d+)_(.+)\.up\.sql$`)
+)
+
+// ParseFormat validates a format name from the configuration. An empty name selects goose.
+func ParseFormat(name string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(name))) {
+	case "", FormatGoose:
+		return FormatGoose, nil
+	case FormatGolangMigrate, "migrate":
+		return FormatGolangMigrate, nil
+	case FormatAtlas:
+		return FormatAtlas, nil
+	default:
+		return "", fmt.Errorf("%w: %s (use goose, golang-migrate, or atlas)", ErrUnknownFormat, name)
+	}
+}
+
+// Load reads the up migrations of dir in version order.
+func Load(dir string, format Format) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration directory %s: %w", dir, err)
+	}
+
+	var migrations []Migration
+
+	seen := make(map[int64]string)
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		version, name, ok := matchFile(entry.Name(), format)
+		if !ok {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if prev, exists := seen[version]; exists {
+			return nil, fmt.Errorf("%w %d: %s and %s", ErrDuplicateVersion, version, prev, path)
+		}
+
+		seen[version] = path
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", path, err)
+		}
+
+		sql := string(content)
+		if format == FormatGoose {
+			sql, err = gooseUpSection(sql)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", err, path)
+			}
+		}
+
+		migrations = append(migrations, Migration{Version: version, Name: name, Path: path, SQL: sql})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+func matchFile(fileName string, format Format) (int64, string, bool) {
+	var match []string
+
+	switch format {
+	case FormatGolangMigrate:
+		match = migrateUpPattern.FindStringSubmatch(fileName)
+	case FormatGoose, FormatAtlas:
+		if strings.HasSuffix(fileName, ".down.sql") {
+			return 0, "", false
+		}
+
+		match = versionedFilePattern.FindStringSubmatch(fileName)
+	}
+
+	if match == nil {
+		return 0, "", false
+	}
+
+	version, err := strconv.ParseInt(match[1], 10, 64)
+	if err != nil {
+		return 0, "", false
+	}
+
+	return version, match[2], true
+}
+
+// gooseUpSection extracts the statements between '-- +goose Up' and '-- +goose Down'.
+// StatementBegin/StatementEnd markers only group statements for goose's own splitter and are dropped.
+func gooseUpSection(content string) (string, error) {
+	var (
+		b     strings.Builder
+		inUp  bool
+		found bool
+	)
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if directive, ok := gooseDirective(line); ok {
+			switch directive {
+			case "up":
+				inUp = true
+				found = true
+			case "down":
+				inUp = false
+			}
+
+			continue
+		}
+
+		if inUp {
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+
+	if !found {
+		return "", ErrMissingGooseUp
+	}
+
+	return b.String(), nil
+}
+
+func gooseDirective(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "--") {
+		return "", false
+	}
+
+	fields := strings.Fields(strings.TrimSpace(strings.TrimPrefix(trimmed, "--")))
+	if len(fields) < 2 || fields[0] != "+goose" {
+		return "", false
+	}
+
+	return strings.ToLower(fields[1]), true
+}
@@ -0,0 +1,184 @@
+package migration
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/shibukawa/snapsql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeMigrations(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+
+	return dir
+}
+
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+func tableExists(t *testing.T, db *sql.DB, name string) bool {
+	t.Helper()
+
+	var count int
+	require.NoError(t, db.QueryRowContext(t.Context(), "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&count))
+
+	return count > 0
+}
+
+func TestParseFormat(t *testing.T) {
+	for input, want := range map[string]Format{"": FormatGoose, "goose": FormatGoose, "golang-migrate": FormatGolangMigrate, "migrate": FormatGolangMigrate, "Atlas": FormatAtlas} {
+		got, err := ParseFormat(input)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, input)
+	}
+
+	_, err := ParseFormat("flyway")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestLoadGooseKeepsOnlyUpSection(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"00002_add_email.sql": "-- +goose Up\nALTER TABLE users ADD COLUMN email TEXT;\n-- +goose Down\nALTER TABLE users DROP COLUMN email;\n",
+		"00001_users.sql":     "-- +goose Up\n-- +goose StatementBegin\nCREATE TABLE users (id INTEGER PRIMARY KEY);\n-- +goose StatementEnd\n\n-- +goose Down\nDROP TABLE users;\n",
+		"README.md":           "not a migration",
+	})
+
+	migrations, err := Load(dir, FormatGoose)
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "users", migrations[0].Name)
+	assert.Equal(t, "CREATE TABLE users (id INTEGER PRIMARY KEY);\n\n", migrations[0].SQL)
+	assert.NotContains(t, migrations[1].SQL, "DROP COLUMN")
+}
+
+func TestLoadGooseWithoutUpSection(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{"1_broken.sql": "CREATE TABLE users (id INTEGER);"})
+
+	_, err := Load(dir, FormatGoose)
+	assert.ErrorIs(t, err, ErrMissingGooseUp)
+}
+
+func TestLoadGolangMigrateSkipsDownFiles(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"000001_users.up.sql":   "CREATE TABLE users (id INTEGER PRIMARY KEY);",
+		"000001_users.down.sql": "DROP TABLE users;",
+	})
+
+	migrations, err := Load(dir, FormatGolangMigrate)
+	require.NoError(t, err)
+	require.Len(t, migrations, 1)
+	assert.Equal(t, "users", migrations[0].Name)
+}
+
+func TestLoadDuplicateVersion(t *testing.T) {
+	dir := writeMigrations(t, map[string]string{
+		"1_users.sql": "-- +goose Up\nSELECT 1;",
+		"01_more.sql": "-- +goose Up\nSELECT 1;",
+	})
+
+	_, err := Load(dir, FormatGoose)
+	assert.ErrorIs(t, err, ErrDuplicateVersion)
+}
+
+func TestApplyRecordsVersionsAndSkipsApplied(t *testing.T) {
+	tests := []struct {
+		format Format
+		files  map[string]string
+		table  string
+	}{
+		{
+			format: FormatGoose,
+			files: map[string]string{
+				"00001_users.sql":  "-- +goose Up\nCREATE TABLE users (id INTEGER PRIMARY KEY);\n-- +goose Down\nDROP TABLE users;\n",
+				"00002_orders.sql": "-- +goose Up\nCREATE TABLE orders (id INTEGER PRIMARY KEY);\n-- +goose Down\nDROP TABLE orders;\n",
+			},
+			table: "goose_db_version",
+		},
+		{
+			format: FormatGolangMigrate,
+			files: map[string]string{
+				"1_users.up.sql":    "CREATE TABLE users (id INTEGER PRIMARY KEY);",
+				"1_users.down.sql":  "DROP TABLE users;",
+				"2_orders.up.sql":   "CREATE TABLE orders (id INTEGER PRIMARY KEY);",
+				"2_orders.down.sql": "DROP TABLE orders;",
+			},
+			table: "schema_migrations",
+		},
+		{
+			format: FormatAtlas,
+			files: map[string]string{
+				"20240101000000_users.sql":  "CREATE TABLE users (id INTEGER PRIMARY KEY);",
+				"20240102000000_orders.sql": "CREATE TABLE orders (id INTEGER PRIMARY KEY);",
+				"atlas.sum":                 "h1:ignored",
+			},
+			table: "atlas_schema_revisions",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			migrations, err := Load(writeMigrations(t, tt.files), tt.format)
+			require.NoError(t, err)
+			require.Len(t, migrations, 2)
+
+			db := openSQLite(t)
+
+			// Apply the first migration only, then the rest: the second run must pick up where the first stopped
+			applied, err := Apply(t.Context(), db, snapsql.DialectSQLite, tt.format, migrations[:1])
+			require.NoError(t, err)
+			assert.Len(t, applied, 1)
+
+			applied, err = Apply(t.Context(), db, snapsql.DialectSQLite, tt.format, migrations)
+			require.NoError(t, err)
+			require.Len(t, applied, 1)
+			assert.Equal(t, migrations[1].Version, applied[0].Version)
+
+			assert.True(t, tableExists(t, db, "users"))
+			assert.True(t, tableExists(t, db, "orders"))
+			assert.True(t, tableExists(t, db, tt.table))
+
+			applied, err = Apply(t.Context(), db, snapsql.DialectSQLite, tt.format, migrations)
+			require.NoError(t, err)
+			assert.Empty(t, applied)
+		})
+	}
+}
+
+func TestApplyRollsBackFailedMigration(t *testing.T) {
+	migrations, err := Load(writeMigrations(t, map[string]string{
+		"1_users.sql":  "-- +goose Up\nCREATE TABLE users (id INTEGER PRIMARY KEY);",
+		"2_broken.sql": "-- +goose Up\nCREATE TABLE broken (id INTEGER);\nINSERT INTO missing VALUES (1);",
+	}), FormatGoose)
+	require.NoError(t, err)
+
+	db := openSQLite(t)
+
+	applied, err := Apply(t.Context(), db, snapsql.DialectSQLite, FormatGoose, migrations)
+	require.Error(t, err)
+	assert.Len(t, applied, 1)
+	assert.False(t, tableExists(t, db, "broken"))
+
+	var count int
+	require.NoError(t, db.QueryRowContext(t.Context(), "SELECT COUNT(*) FROM goose_db_version").Scan(&count))
+	assert.Equal(t, 1, count)
+}
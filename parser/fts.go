@@ -0,0 +1,80 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// FullTextSearchFunction is the placeholder function that /*# fts(column, query) */ expands to.
+// The code generator replaces it with the full-text search syntax of the dialect.
+const FullTextSearchFunction = "SNAPSQL_FTS"
+
+// ErrFullTextSearchInvalid is returned when an fts directive is malformed.
+var ErrFullTextSearchInvalid = errors.New("invalid fts directive")
+
+var (
+	ftsDirectiveRe = regexp.MustCompile(`(?s)/\*#\s*fts\s*\((.*?)\)\s*\*/`)
+	ftsColumnRe    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+)
+
+// expandFullTextSearch rewrites the portable full-text search directive before tokenizing.
+//
+//	WHERE /*# fts(title, query) */
+//
+// becomes
+//
+//	WHERE SNAPSQL_FTS(title, /*= query */'')
+//
+// and the code generator expands it to to_tsvector(title) @@ plainto_tsquery($1),
+// MATCH (title) AGAINST (?) or title MATCH ?.
+func expandFullTextSearch(sql string) (string, error) {
+	if !strings.Contains(sql, "/*#") {
+		return sql, nil
+	}
+
+	var (
+		out  strings.Builder
+		last int
+	)
+
+	for _, m := range ftsDirectiveRe.FindAllStringSubmatchIndex(sql, -1) {
+		column, query, err := splitFullTextSearchArgs(sql[m[2]:m[3]])
+		if err != nil {
+			return "", err
+		}
+
+		out.WriteString(sql[last:m[0]])
+		fmt.Fprintf(&out, "%s(%s, /*= %s */'')", FullTextSearchFunction, column, query)
+		// 行番号がずれないように改行を残す
+		out.WriteString(strings.Repeat("\n", strings.Count(sql[m[0]:m[1]], "\n")))
+
+		last = m[1]
+	}
+
+	if last == 0 {
+		return sql, nil
+	}
+
+	out.WriteString(sql[last:])
+
+	return out.String(), nil
+}
+
+// splitFullTextSearchArgs splits "column, query expression" at the first comma
+func splitFullTextSearchArgs(args string) (string, string, error) {
+	column, query, ok := strings.Cut(args, ",")
+	column = strings.TrimSpace(column)
+	query = strings.TrimSpace(query)
+
+	if !ok || query == "" {
+		return "", "", fmt.Errorf("%w: fts(%s) needs a column and a query parameter", ErrFullTextSearchInvalid, args)
+	}
+
+	if !ftsColumnRe.MatchString(column) {
+		return "", "", fmt.Errorf("%w: %q is not a column name", ErrFullTextSearchInvalid, column)
+	}
+
+	return column, query, nil
+}
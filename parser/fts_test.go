@@ -0,0 +1,30 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestExpandFullTextSearch(t *testing.T) {
+	got, err := expandFullTextSearch("SELECT id FROM articles a\nWHERE /*# fts(a.title,\n  query.trim()) */ AND id > 0")
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM articles a\nWHERE SNAPSQL_FTS(a.title, /*= query.trim() */'')\n AND id > 0", got)
+
+	// 他のディレクティブはそのまま
+	sql := "SELECT id FROM t WHERE /*# if query != \"\" */ title = /*= query */'' /*# end */"
+	got, err = expandFullTextSearch(sql)
+	assert.NoError(t, err)
+	assert.Equal(t, sql, got)
+}
+
+func TestExpandFullTextSearchErrors(t *testing.T) {
+	for _, sql := range []string{
+		"SELECT id FROM t WHERE /*# fts(title) */",
+		"SELECT id FROM t WHERE /*# fts(title, ) */",
+		"SELECT id FROM t WHERE /*# fts(lower(title), query) */",
+	} {
+		_, err := expandFullTextSearch(sql)
+		assert.IsError(t, err, ErrFullTextSearchInvalid, sql)
+	}
+}
@@ -0,0 +1,403 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Sentinel errors for macro expansion
+var (
+	ErrMacroInvalid          = errors.New("invalid macro directive")
+	ErrMacroUnterminated     = errors.New("macro is not terminated with /*# end */")
+	ErrMacroDuplicate        = errors.New("macro is defined more than once")
+	ErrMacroUndefined        = errors.New("undefined macro")
+	ErrMacroArgumentCount    = errors.New("macro argument count mismatch")
+	ErrMacroRecursionTooDeep = errors.New("macro expansion is too deep (recursive macro?)")
+)
+
+// maxMacroDepth limits nested macro uses so that recursive macros fail instead of looping
+const maxMacroDepth = 16
+
+var (
+	directiveCommentRe = regexp.MustCompile(`(?s)/\*#\s*(.*?)\s*\*/`)
+	macroHeaderRe      = regexp.MustCompile(`(?s)^(macro|use)\s+([A-Za-z_][A-Za-z0-9_]*)\s*(?:\((.*)\))?$`)
+	macroParamRe       = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+type macroDefinition struct {
+	name   string
+	params []string
+	body   string
+}
+
+// expandMacros expands parameterized macros before tokenizing.
+//
+//	/*# macro audit_filter(tbl) */
+//	AND tbl.deleted_at IS NULL
+//	/*# end */
+//	...
+//	WHERE /*# use audit_filter("orders") */
+//
+// Definitions are removed from the SQL (their lines are kept as empty lines so that the
+// positions of the following tokens do not move) and every use is replaced by the body of the
+// macro joined into one line, in which the SQL identifiers named like a parameter are replaced by
+// the argument. Comments, directives and string literals of the body are left as they are.
+func expandMacros(sql string) (string, error) {
+	if !strings.Contains(sql, "/*#") {
+		return sql, nil
+	}
+
+	macros, rest, err := collectMacros(sql)
+	if err != nil {
+		return "", err
+	}
+
+	return expandMacroUses(rest, macros, 0)
+}
+
+// collectMacros removes the macro definitions from sql and returns them
+func collectMacros(sql string) (map[string]*macroDefinition, string, error) {
+	macros := map[string]*macroDefinition{}
+
+	var out strings.Builder
+
+	matches := directiveCommentRe.FindAllStringSubmatchIndex(sql, -1)
+	last := 0
+
+	for i := 0; i < len(matches); i++ {
+		m := matches[i]
+		content := sql[m[2]:m[3]]
+
+		if !isDirective(content, "macro") {
+			continue
+		}
+
+		name, params, err := parseMacroHeader(content)
+		if err != nil {
+			return nil, "", err
+		}
+
+		if _, ok := macros[name]; ok {
+			return nil, "", fmt.Errorf("%w: %s", ErrMacroDuplicate, name)
+		}
+
+		// /*# end */ of if/for blocks inside the body must not close the macro
+		depth := 1
+		j := i + 1
+
+		for ; j < len(matches); j++ {
+			inner := sql[matches[j][2]:matches[j][3]]
+
+			switch {
+			case isDirective(inner, "macro"):
+				return nil, "", fmt.Errorf("%w: macro %s cannot be defined inside macro %s", ErrMacroInvalid, strings.Fields(inner)[1], name)
+			case isDirective(inner, "if"), isDirective(inner, "for"):
+				depth++
+			case inner == "end":
+				depth--
+			}
+
+			if depth == 0 {
+				break
+			}
+		}
+
+		if j == len(matches) {
+			return nil, "", fmt.Errorf("%w: %s", ErrMacroUnterminated, name)
+		}
+
+		end := matches[j]
+		macros[name] = &macroDefinition{
+			name:   name,
+			params: params,
+			body:   strings.TrimSpace(sql[m[1]:end[0]]),
+		}
+
+		out.WriteString(sql[last:m[0]])
+		out.WriteString(strings.Repeat("\n", strings.Count(sql[m[0]:end[1]], "\n")))
+
+		last = end[1]
+		i = j
+	}
+
+	out.WriteString(sql[last:])
+
+	return macros, out.String(), nil
+}
+
+// expandMacroUses replaces every /*# use name(args) */ with the substituted macro body
+func expandMacroUses(sql string, macros map[string]*macroDefinition, depth int) (string, error) {
+	if depth > maxMacroDepth {
+		return "", ErrMacroRecursionTooDeep
+	}
+
+	var (
+		out  strings.Builder
+		last int
+	)
+
+	for _, m := range directiveCommentRe.FindAllStringSubmatchIndex(sql, -1) {
+		content := sql[m[2]:m[3]]
+		if !isDirective(content, "use") {
+			continue
+		}
+
+		name, args, err := parseMacroUse(content)
+		if err != nil {
+			return "", err
+		}
+
+		macro, ok := macros[name]
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrMacroUndefined, name)
+		}
+
+		if len(args) != len(macro.params) {
+			return "", fmt.Errorf("%w: %s takes %d argument(s) but %d given", ErrMacroArgumentCount, name, len(macro.params), len(args))
+		}
+
+		body, err := macro.substitute(args)
+		if err != nil {
+			return "", err
+		}
+
+		body, err = expandMacroUses(body, macros, depth+1)
+		if err != nil {
+			return "", err
+		}
+
+		// The body is a single line; the line breaks of the directive itself are kept so that
+		// the positions of the following tokens do not move
+		out.WriteString(sql[last:m[0]])
+		out.WriteString(body)
+		out.WriteString(strings.Repeat("\n", strings.Count(sql[m[0]:m[1]], "\n")))
+
+		last = m[1]
+	}
+
+	if last == 0 {
+		return sql, nil
+	}
+
+	out.WriteString(sql[last:])
+
+	return out.String(), nil
+}
+
+// substitute returns the body of the macro on a single line with the arguments in place of the
+// parameters. Only SQL identifiers are replaced: names after a "." (qualified column names), string
+// literals, quoted identifiers and comments, including the /*= */ and /*# if */ expressions, are kept.
+// Arguments of nested /*# use */ directives that name a parameter are replaced as well. Line comments
+// are dropped and line breaks become spaces.
+func (m *macroDefinition) substitute(args []string) (string, error) {
+	values := make(map[string]string, len(m.params))
+	for i, p := range m.params {
+		values[p] = args[i]
+	}
+
+	body := m.body
+
+	var out strings.Builder
+
+	for i := 0; i < len(body); {
+		c := body[i]
+
+		switch {
+		case c == '\'' || c == '"':
+			end := i + 1
+			for end < len(body) && (body[end] != c || (end+1 < len(body) && body[end+1] == c)) {
+				if body[end] == c {
+					end++ // doubled quote
+				}
+
+				end++
+			}
+
+			literal := body[i:min(end+1, len(body))]
+			if strings.ContainsAny(literal, "\r\n") {
+				return "", fmt.Errorf("%w: macro %s has a line break inside %s", ErrMacroInvalid, m.name, literal)
+			}
+
+			out.WriteString(literal)
+			i += len(literal)
+		case strings.HasPrefix(body[i:], "/*"):
+			end := strings.Index(body[i+2:], "*/")
+			if end < 0 {
+				end = len(body)
+			} else {
+				end += i + 4
+			}
+
+			comment := body[i:end]
+			if dm := directiveCommentRe.FindStringSubmatch(comment); dm != nil && dm[0] == comment && isDirective(dm[1], "use") {
+				comment = substituteUseArguments(dm[1], values)
+			}
+
+			out.WriteString(strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ").Replace(comment))
+			i = end
+		case strings.HasPrefix(body[i:], "--"):
+			for i < len(body) && body[i] != '\n' {
+				i++
+			}
+		case c == '\r' || c == '\n':
+			if c == '\n' || !strings.HasPrefix(body[i:], "\r\n") {
+				out.WriteByte(' ')
+			}
+
+			i++
+		case isIdentStart(c) || isDigit(c):
+			end := i + 1
+			for end < len(body) && (isIdentStart(body[end]) || isDigit(body[end])) {
+				end++
+			}
+
+			word := body[i:end]
+			if value, ok := values[word]; ok && !isDigit(c) && !strings.HasSuffix(out.String(), ".") {
+				word = value
+			}
+
+			out.WriteString(word)
+			i = end
+		default:
+			out.WriteByte(c)
+			i++
+		}
+	}
+
+	return strings.TrimSpace(out.String()), nil
+}
+
+// substituteUseArguments rewrites a nested /*# use */ directive, replacing the unquoted arguments
+// that name a parameter of the enclosing macro by the quoted argument value
+func substituteUseArguments(content string, values map[string]string) string {
+	name, items, err := splitMacroDirective(content)
+	if err != nil || items == nil {
+		// invalid directives are reported when the use is expanded
+		return "/*# " + content + " */"
+	}
+
+	for i, item := range items {
+		if value, ok := values[item]; ok {
+			items[i] = strconv.Quote(value)
+		}
+	}
+
+	return "/*# use " + name + "(" + strings.Join(items, ", ") + ") */"
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// isDirective reports whether the directive content starts with keyword
+func isDirective(content, keyword string) bool {
+	rest, ok := strings.CutPrefix(content, keyword)
+	return ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\n' || rest[0] == '\r')
+}
+
+func parseMacroHeader(content string) (string, []string, error) {
+	name, rawParams, err := splitMacroDirective(content)
+	if err != nil {
+		return "", nil, err
+	}
+
+	params := make([]string, 0, len(rawParams))
+	seen := map[string]bool{}
+
+	for _, p := range rawParams {
+		if !macroParamRe.MatchString(p) {
+			return "", nil, fmt.Errorf("%w: parameter %q of macro %s must be an identifier", ErrMacroInvalid, p, name)
+		}
+
+		if seen[p] {
+			return "", nil, fmt.Errorf("%w: parameter %q of macro %s is duplicated", ErrMacroInvalid, p, name)
+		}
+
+		seen[p] = true
+		params = append(params, p)
+	}
+
+	return name, params, nil
+}
+
+func parseMacroUse(content string) (string, []string, error) {
+	name, rawArgs, err := splitMacroDirective(content)
+	if err != nil {
+		return "", nil, err
+	}
+
+	args := make([]string, 0, len(rawArgs))
+
+	for _, a := range rawArgs {
+		switch {
+		case len(a) >= 2 && a[0] == '"' && a[len(a)-1] == '"':
+			v, err := strconv.Unquote(a)
+			if err != nil {
+				return "", nil, fmt.Errorf("%w: argument %s of macro %s: %w", ErrMacroInvalid, a, name, err)
+			}
+
+			args = append(args, v)
+		case len(a) >= 2 && a[0] == '\'' && a[len(a)-1] == '\'':
+			args = append(args, a[1:len(a)-1])
+		case a == "":
+			return "", nil, fmt.Errorf("%w: empty argument of macro %s", ErrMacroInvalid, name)
+		default:
+			args = append(args, a)
+		}
+	}
+
+	return name, args, nil
+}
+
+// splitMacroDirective splits "macro name(a, b)" / "use name(a, b)" into the name and the raw
+// comma separated items. Commas inside quoted arguments are kept.
+func splitMacroDirective(content string) (string, []string, error) {
+	m := macroHeaderRe.FindStringSubmatch(content)
+	if m == nil {
+		return "", nil, fmt.Errorf("%w: /*# %s */", ErrMacroInvalid, content)
+	}
+
+	list := strings.TrimSpace(m[3])
+	if list == "" {
+		return m[2], nil, nil
+	}
+
+	var (
+		items []string
+		item  strings.Builder
+		quote rune
+	)
+
+	for _, r := range list {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == ',':
+			items = append(items, strings.TrimSpace(item.String()))
+			item.Reset()
+
+			continue
+		}
+
+		item.WriteRune(r)
+	}
+
+	if quote != 0 {
+		return "", nil, fmt.Errorf("%w: unterminated string in /*# %s */", ErrMacroInvalid, content)
+	}
+
+	items = append(items, strings.TrimSpace(item.String()))
+
+	return m[2], items, nil
+}
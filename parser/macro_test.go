@@ -0,0 +1,106 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestExpandMacros(t *testing.T) {
+	sql := `/*# macro audit_filter(tbl) */
+AND tbl.deleted_at IS NULL
+/*# if include_archived */
+AND tbl.archived = false
+/*# end */
+/*# end */
+SELECT id FROM orders
+WHERE status = 'open'
+/*# use audit_filter("orders") */`
+
+	got, err := expandMacros(sql)
+	assert.NoError(t, err)
+
+	// The definition is removed but its lines are kept
+	assert.True(t, strings.HasPrefix(got, "\n\n\n\n\n\nSELECT id FROM orders"))
+	// The body is expanded on the line of the use, so the line count does not change
+	assert.Contains(t, got, "WHERE status = 'open'\nAND orders.deleted_at IS NULL /*# if include_archived */ AND orders.archived = false /*# end */")
+	assert.Equal(t, strings.Count(sql, "\n"), strings.Count(got, "\n"))
+	assert.NotContains(t, got, "macro")
+	assert.NotContains(t, got, "tbl")
+}
+
+func TestExpandMacrosReplacesIdentifiersOnly(t *testing.T) {
+	sql := `/*# macro flag_filter(flag, status) */
+AND t.status = status -- status of flag
+AND t.flag = /*= flag */false
+/*# if flag */AND t.note = 'flag status'/*# end */
+/*# end */
+SELECT id FROM t
+WHERE 1 = 1 /*# use flag_filter(enabled, "'open'") */
+AND id = /*= id */1`
+
+	got, err := expandMacros(sql)
+	assert.NoError(t, err)
+
+	want := "WHERE 1 = 1 AND t.status = 'open'  AND t.flag = /*= flag */false /*# if flag */AND t.note = 'flag status'/*# end */\nAND id = /*= id */1"
+	assert.Contains(t, got, want)
+	assert.Equal(t, strings.Count(sql, "\n"), strings.Count(got, "\n"))
+}
+
+func TestExpandMacrosArguments(t *testing.T) {
+	sql := `/*# macro range_filter(col, low, high) */col BETWEEN low AND high/*# end */
+/*# macro active */status = 'active'/*# end */
+/*# macro both(col) *//*# use range_filter(col, 1, "10") */ AND /*# use active *//*# end */
+SELECT * FROM t WHERE /*# use both(price) */ AND label = 'col'`
+
+	got, err := expandMacros(sql)
+	assert.NoError(t, err)
+	assert.Equal(t, "\n\n\nSELECT * FROM t WHERE price BETWEEN 1 AND 10 AND status = 'active' AND label = 'col'", got)
+}
+
+func TestExpandMacrosErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		sql  string
+		want error
+	}{
+		{name: "undefined", sql: `SELECT 1 /*# use missing("x") */`, want: ErrMacroUndefined},
+		{name: "argument count", sql: `/*# macro f(a, b) */a = b/*# end */ SELECT 1 WHERE /*# use f("x") */`, want: ErrMacroArgumentCount},
+		{name: "unterminated", sql: `/*# macro f(a) */ /*# if a */ x /*# end */ SELECT 1`, want: ErrMacroUnterminated},
+		{name: "duplicate", sql: `/*# macro f */x/*# end *//*# macro f */y/*# end */`, want: ErrMacroDuplicate},
+		{name: "invalid parameter", sql: `/*# macro f("a") */x/*# end */`, want: ErrMacroInvalid},
+		{name: "nested definition", sql: `/*# macro f */ /*# macro g */x/*# end */ /*# end */`, want: ErrMacroInvalid},
+		{name: "multi-line literal", sql: "/*# macro f */x = 'a\nb'/*# end */ SELECT 1 WHERE /*# use f */", want: ErrMacroInvalid},
+		{name: "recursive", sql: `/*# macro f *//*# use f *//*# end */ SELECT /*# use f */`, want: ErrMacroRecursionTooDeep},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := expandMacros(tt.sql)
+			assert.IsError(t, err, tt.want)
+		})
+	}
+}
+
+func TestParseSQLFileWithMacro(t *testing.T) {
+	sql := `/*#
+function_name: list_orders
+parameters:
+  include_archived: bool
+*/
+/*# macro audit_filter(tbl) */
+AND tbl.deleted_at IS NULL
+/*# if include_archived */
+AND tbl.archived = false
+/*# end */
+/*# end */
+SELECT id, status FROM orders
+WHERE status = 'open'
+/*# use audit_filter("orders") */`
+
+	stmt, _, def, err := ParseSQLFile(strings.NewReader(sql), nil, "", "", Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, "list_orders", def.FunctionName)
+	assert.NotZero(t, stmt)
+}
@@ -1,11 +1,16 @@
 package parser
 
+import "github.com/shibukawa/snapsql/explang"
+
 // Options controls parser behaviors that can be relaxed or enabled.
 type Options struct {
 	// InspectMode relaxes validations intended for code generation/runtime execution.
 	// When true, parser steps that require strict directive/variable checks may skip them
 	// in favor of extracting structural information.
 	InspectMode bool
+	// Functions are the template functions (cel_functions in snapsql.yaml) that
+	// expressions may call.
+	Functions map[string]explang.Function
 }
 
 // DefaultOptions provides the default parser options (all strict validations enabled).
@@ -42,6 +42,8 @@ type (
 	UpdateStatement = cmn.UpdateStatement
 	// DeleteFromStatement represents a parsed DELETE FROM statement (re-export).
 	DeleteFromStatement = cmn.DeleteFromStatement
+	// MergeStatement represents a parsed MERGE INTO statement (re-export).
+	MergeStatement = cmn.MergeStatement
 
 	// SelectClause represents the SELECT clause (re-export).
 	SelectClause = cmn.SelectClause
@@ -63,6 +65,10 @@ type (
 	WithClause = cmn.WithClause
 	// ForClause represents the FOR clause (re-export).
 	ForClause = cmn.ForClause
+	// SetOperationClause represents a UNION / INTERSECT / EXCEPT operator (re-export).
+	SetOperationClause = cmn.SetOperationClause
+	// SetOperation represents a SELECT branch of a compound query (re-export).
+	SetOperation = cmn.SetOperation
 	// InsertIntoClause represents the INSERT INTO clause (re-export).
 	InsertIntoClause = cmn.InsertIntoClause
 	// ValuesClause represents the VALUES clause (re-export).
@@ -75,6 +81,12 @@ type (
 	DeleteFromClause = cmn.DeleteFromClause
 	// OnConflictClause represents the ON CONFLICT clause (re-export).
 	OnConflictClause = cmn.OnConflictClause
+	// MergeIntoClause represents the MERGE INTO clause (re-export).
+	MergeIntoClause = cmn.MergeIntoClause
+	// UsingClause represents the USING ... ON clause of MERGE (re-export).
+	UsingClause = cmn.UsingClause
+	// WhenMatchedClause represents a WHEN [NOT] MATCHED clause of MERGE (re-export).
+	WhenMatchedClause = cmn.WhenMatchedClause
 	// ReturningClause represents the RETURNING clause (re-export).
 	ReturningClause = cmn.ReturningClause
 
@@ -84,6 +96,8 @@ type (
 	FieldType = cmn.FieldType
 	// SelectField represents one field expression in a SELECT list (re-export).
 	SelectField = cmn.SelectField
+	// WindowSpec represents the OVER clause of a window function field (re-export).
+	WindowSpec = cmn.WindowSpec
 
 	// FunctionDefinition represents a function signature definition (re-export).
 	FunctionDefinition = cmn.FunctionDefinition
@@ -128,6 +142,9 @@ type (
 
 // Re-export constants
 const (
+	// ConstNamespace is the root name of the project constants in snapsql.yaml (re-export).
+	ConstNamespace = cmn.ConstNamespace
+
 	// UNKNOWN indicates unknown node type (re-export).
 	// SQL statement structures
 	UNKNOWN            = cmn.UNKNOWN
@@ -163,6 +180,9 @@ const (
 	// DELETE_FROM_STATEMENT represents a full DELETE statement node type.
 	DELETE_FROM_STATEMENT = cmn.DELETE_FROM_STATEMENT
 
+	// MERGE_STATEMENT represents a full MERGE statement node type.
+	MERGE_STATEMENT = cmn.MERGE_STATEMENT
+
 	// SingleField is a FieldType for single unqualified field names.
 	SingleField = cmn.SingleField
 	// TableField is a FieldType for qualified table.field names.
@@ -289,6 +309,8 @@ func RawParse(tokens []tokenizer.Token, functionDef *FunctionDefinition, constan
 		return nil, nil, fmt.Errorf("failed to create parameter namespace: %w", err)
 	}
 
+	paramNamespace.SetFunctions(opts.Functions)
+
 	// Create a separate namespace for constants if provided
 	var constNamespace *cmn.Namespace
 	if len(constants) > 0 {
@@ -348,8 +370,19 @@ func ParseSQLFile(reader io.Reader, constants map[string]any, basePath string, p
 		return nil, nil, nil, fmt.Errorf("failed to read SQL content: %w", err)
 	}
 
+	// Expand template macros before tokenizing
+	sql, err := expandMacros(string(content))
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("macro expansion failed: %w", err)
+	}
+
+	sql, err = expandFullTextSearch(sql)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	// Tokenize the SQL content
-	tokens, err := tokenizer.Tokenize(string(content))
+	tokens, err := tokenizer.Tokenize(sql)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("tokenization failed: %w", err)
 	}
@@ -420,8 +453,19 @@ func ParseMarkdownFile(doc *markdownparser.SnapSQLDocument, basePath string, pro
 		return nil, nil, functionDef, fmt.Errorf("failed to finalize function definition: %w", err)
 	}
 
+	// Expand template macros before tokenizing
+	sql, err := expandMacros(doc.SQL)
+	if err != nil {
+		return nil, nil, functionDef, fmt.Errorf("macro expansion failed: %w", err)
+	}
+
+	sql, err = expandFullTextSearch(sql)
+	if err != nil {
+		return nil, nil, functionDef, err
+	}
+
 	// Tokenize the SQL content with line offset from markdown
-	tokens, err := tokenizer.Tokenize(doc.SQL, doc.SQLStartLine)
+	tokens, err := tokenizer.Tokenize(sql, doc.SQLStartLine)
 	if err != nil {
 		return nil, nil, functionDef, fmt.Errorf("tokenization failed: %w", err)
 	}
@@ -406,11 +406,40 @@ func (n *ReturningClause) String() string {
 
 var _ ClauseNode = (*ReturningClause)(nil)
 
+// SetOperationClause represents the UNION / INTERSECT / EXCEPT operator that starts the next
+// SELECT branch of a compound query
+type SetOperationClause struct {
+	clauseBaseNode
+
+	Operator string // Normalized operator: UNION, UNION ALL, INTERSECT, EXCEPT ALL, ...
+}
+
+func NewSetOperationClause(srcText string, heading, body []tokenizer.Token) *SetOperationClause {
+	return &SetOperationClause{
+		clauseBaseNode: clauseBaseNode{
+			clauseSourceText: srcText,
+			headingTokens:    heading,
+			bodyTokens:       body,
+		},
+		Operator: strings.ToUpper(strings.Join(strings.Fields(srcText), " ")),
+	}
+}
+
+func (n *SetOperationClause) Type() NodeType {
+	return SET_OPERATION_CLAUSE
+}
+func (n *SetOperationClause) String() string {
+	return n.Operator
+}
+
+var _ ClauseNode = (*SetOperationClause)(nil)
+
 // Helper structures
 
 // CTEDefinition represents a Common Table Expression definition
 type CTEDefinition struct {
 	Name           string
+	Columns        []string // Column list of "name (col1, col2) AS (...)"; empty when omitted
 	Select         AstNode
 	TrailingTokens []tokenizer.Token
 	RawTokens      []tokenizer.Token // Raw tokens for the SELECT statement (for re-parsing)
@@ -503,6 +532,43 @@ func (n *OnConflictClause) String() string {
 	return "ON_CONFLICT_CLAUSE"
 }
 
+// IsDuplicateKeyUpdate reports whether the clause is MySQL's ON DUPLICATE KEY UPDATE.
+func (n *OnConflictClause) IsDuplicateKeyUpdate() bool {
+	for _, t := range n.headingTokens {
+		if strings.EqualFold(t.Value, "DUPLICATE") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsDoNothing reports whether the clause is ON CONFLICT ... DO NOTHING, that never updates the
+// existing row.
+func (n *OnConflictClause) IsDoNothing() bool {
+	depth := 0
+	prevDo := false
+
+	for _, t := range n.bodyTokens {
+		switch t.Type {
+		case tokenizer.WHITESPACE, tokenizer.LINE_COMMENT, tokenizer.BLOCK_COMMENT:
+			continue
+		case tokenizer.OPENED_PARENS:
+			depth++
+		case tokenizer.CLOSED_PARENS:
+			depth--
+		}
+
+		if depth == 0 && prevDo && strings.EqualFold(t.Value, "NOTHING") {
+			return true
+		}
+
+		prevDo = depth == 0 && strings.EqualFold(t.Value, "DO")
+	}
+
+	return false
+}
+
 var _ ClauseNode = (*OnConflictClause)(nil)
 
 type ValuesClause struct {
@@ -615,3 +681,95 @@ func (d *DeleteFromClause) Type() NodeType {
 }
 
 var _ ClauseNode = (*DeleteFromClause)(nil)
+
+// MergeIntoClause represents the MERGE INTO target clause.
+type MergeIntoClause struct {
+	clauseBaseNode
+
+	Table TableReference
+}
+
+func NewMergeIntoClause(srcText string, heading, body []tokenizer.Token) *MergeIntoClause {
+	return &MergeIntoClause{
+		clauseBaseNode: clauseBaseNode{
+			clauseSourceText: srcText,
+			headingTokens:    heading,
+			bodyTokens:       body,
+		},
+	}
+}
+
+// Type implements ClauseNode.
+func (n *MergeIntoClause) Type() NodeType {
+	return MERGE_INTO_CLAUSE
+}
+
+func (n *MergeIntoClause) String() string {
+	return "MERGE_INTO"
+}
+
+var _ ClauseNode = (*MergeIntoClause)(nil)
+
+// UsingClause represents the "USING source ON condition" clause of a MERGE statement.
+type UsingClause struct {
+	clauseBaseNode
+}
+
+func NewUsingClause(srcText string, heading, body []tokenizer.Token) *UsingClause {
+	return &UsingClause{
+		clauseBaseNode: clauseBaseNode{
+			clauseSourceText: srcText,
+			headingTokens:    heading,
+			bodyTokens:       body,
+		},
+	}
+}
+
+// Type implements ClauseNode.
+func (n *UsingClause) Type() NodeType {
+	return USING_CLAUSE
+}
+
+func (n *UsingClause) String() string {
+	return "USING"
+}
+
+var _ ClauseNode = (*UsingClause)(nil)
+
+// WhenMatchedClause represents a "WHEN [NOT] MATCHED [AND condition] THEN action" clause of a
+// MERGE statement.
+type WhenMatchedClause struct {
+	clauseBaseNode
+}
+
+func NewWhenMatchedClause(srcText string, heading, body []tokenizer.Token) *WhenMatchedClause {
+	return &WhenMatchedClause{
+		clauseBaseNode: clauseBaseNode{
+			clauseSourceText: srcText,
+			headingTokens:    heading,
+			bodyTokens:       body,
+		},
+	}
+}
+
+// Type implements ClauseNode.
+func (n *WhenMatchedClause) Type() NodeType {
+	return WHEN_MATCHED_CLAUSE
+}
+
+func (n *WhenMatchedClause) String() string {
+	return "WHEN_MATCHED"
+}
+
+// NotMatched reports whether the clause is WHEN NOT MATCHED.
+func (n *WhenMatchedClause) NotMatched() bool {
+	for _, t := range n.headingTokens {
+		if t.Type == tokenizer.NOT {
+			return true
+		}
+	}
+
+	return false
+}
+
+var _ ClauseNode = (*WhenMatchedClause)(nil)
@@ -59,6 +59,16 @@ type SelectField struct {
 	FieldName     string
 	ExplicitName  bool
 	Pos           tok.Position
+	Window        *WindowSpec // OVER clause of window functions (nil for other fields)
+}
+
+// WindowSpec is the OVER clause of a window function call.
+// Either Name (OVER w) or the inline specification (OVER (PARTITION BY ... ORDER BY ...)) is set.
+type WindowSpec struct {
+	Name        string      // Named window reference
+	PartitionBy []tok.Token // Tokens after PARTITION BY (without the keywords)
+	OrderBy     []tok.Token // Tokens after ORDER BY (without the keywords)
+	Frame       []tok.Token // ROWS / RANGE / GROUPS frame clause
 }
 
 func (n SelectField) String() string {
@@ -120,6 +130,8 @@ type TableReferenceForFrom struct {
 	JoinCondition []tok.Token // ON/USING clause tokens
 	Expression    []tok.Token // Optional expression for complex references
 	RawTokens     []tok.Token // Raw tokens for subquery or CTE (includes parentheses if subquery)
+	Lateral       bool        // LATERAL subquery: it can refer to the preceding tables
+	TableFunction bool        // Table function call like json_each(data): TableName holds the function name
 }
 
 func (n TableReferenceForFrom) String() string {
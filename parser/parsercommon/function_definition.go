@@ -44,6 +44,12 @@ type FunctionDefinition struct {
 	Generators         map[string]map[string]any `yaml:"generators"`
 	Performance        PerformanceDefinition     `yaml:"performance"`
 	SlowQueryThreshold time.Duration             `yaml:"-"`
+	// ResponseAffinity overrides the detected affinity (one, many, none, exists or count)
+	ResponseAffinity string `yaml:"response_affinity"`
+	// Pagination selects generated pagination support ("cursor" for keyset pagination)
+	Pagination string `yaml:"pagination"`
+	// ResponseType names a user-declared type that generated code returns instead of its own response struct
+	ResponseType string `yaml:"response_type"`
 
 	// Common type related fields
 	commonTypes     map[string]map[string]map[string]any // Loaded common type definitions
@@ -84,8 +90,11 @@ func ParseFunctionDefinitionFromSnapSQLDocument(doc *markdownparser.SnapSQLDocum
 	// Create a new FunctionDefinition
 	def := &FunctionDefinition{
 		// Copy metadata fields
-		FunctionName: getStringFromMap(doc.Metadata, "function_name", ""),
-		Description:  getStringFromMap(doc.Metadata, "description", ""),
+		FunctionName:     getStringFromMap(doc.Metadata, "function_name", ""),
+		Description:      getStringFromMap(doc.Metadata, "description", ""),
+		ResponseAffinity: getStringFromMap(doc.Metadata, "response_affinity", ""),
+		Pagination:       getStringFromMap(doc.Metadata, "pagination", ""),
+		ResponseType:     getStringFromMap(doc.Metadata, "response_type", ""),
 	}
 
 	if doc.Performance.SlowQueryThreshold > 0 {
@@ -285,6 +294,20 @@ func (f *FunctionDefinition) Finalize(basePath string, projectRootPath string) e
 		}
 	}
 
+	f.ResponseAffinity = strings.ToLower(strings.TrimSpace(f.ResponseAffinity))
+	switch f.ResponseAffinity {
+	case "", "one", "many", "none", "exists", "count":
+	default:
+		return fmt.Errorf("%w: %s (must be one of one, many, none, exists, count)", snapsql.ErrUnsupportedResponseAffinity, f.ResponseAffinity)
+	}
+
+	f.Pagination = strings.ToLower(strings.TrimSpace(f.Pagination))
+	switch f.Pagination {
+	case "", "cursor":
+	default:
+		return fmt.Errorf("%w: %s (must be cursor)", snapsql.ErrUnsupportedPagination, f.Pagination)
+	}
+
 	return nil
 }
 
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/shibukawa/snapsql"
 	"github.com/shibukawa/snapsql/markdownparser"
 	"github.com/stretchr/testify/assert"
 )
@@ -722,3 +723,20 @@ func TestFunctionDefinition_PerformanceThresholdFromDocument(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 2*time.Second, def.SlowQueryThreshold)
 }
+
+func TestFunctionDefinition_Pagination(t *testing.T) {
+	def, err := parseFunctionDefinitionFromYAML(`
+function_name: list_users
+pagination: Cursor
+parameters:
+  page_size: int
+`, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, "cursor", def.Pagination)
+
+	_, err = parseFunctionDefinitionFromYAML(`
+function_name: list_users
+pagination: offset
+`, "", "")
+	assert.ErrorIs(t, err, snapsql.ErrUnsupportedPagination)
+}
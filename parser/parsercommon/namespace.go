@@ -3,6 +3,7 @@ package parsercommon
 import (
 	"fmt"
 	"maps"
+	"regexp"
 	"strings"
 	"time"
 
@@ -10,10 +11,20 @@ import (
 	"github.com/google/cel-go/common/decls"
 	"github.com/google/cel-go/common/types/ref"
 	"github.com/google/uuid"
+	"github.com/shibukawa/snapsql/explang"
 	"github.com/shibukawa/snapsql/langs/snapsqlgo"
 	"github.com/shopspring/decimal"
 )
 
+// ConstNamespace is the root name of the project constants defined in snapsql.yaml
+// (const.max_page_size). const is reserved in CEL, so expressions are evaluated with
+// celConstVariable instead.
+const ConstNamespace = "const"
+
+const celConstVariable = "snapsql_const"
+
+var constReferenceRe = regexp.MustCompile(`(^|[^\w.])const\s*\.`)
+
 type frame struct {
 	values map[string]any
 	env    *cel.Env
@@ -24,6 +35,22 @@ type Namespace struct {
 	frames        []frame
 	currentEnv    *cel.Env
 	currentValues map[string]any
+	functions     map[string]explang.Function
+}
+
+// SetFunctions registers the template functions (cel_functions in snapsql.yaml) that
+// expressions may call.
+func (ns *Namespace) SetFunctions(functions map[string]explang.Function) {
+	ns.functions = functions
+}
+
+// Functions returns the template functions registered with SetFunctions.
+func (ns *Namespace) Functions() map[string]explang.Function {
+	if ns == nil {
+		return nil
+	}
+
+	return ns.functions
 }
 
 // ParameterSchema returns the normalized parameter map associated with this namespace.
@@ -91,7 +118,7 @@ func NewNamespaceFromConstants(constants map[string]any) (*Namespace, error) {
 			return nil, fmt.Errorf("failed to convert constant '%s' type: %w", key, err)
 		}
 
-		consts = append(consts, decls.NewVariable(key, celType))
+		consts = append(consts, decls.NewVariable(celVariableName(key), celType))
 	}
 
 	root := cel.VariableDecls(consts...)
@@ -115,7 +142,7 @@ func NewNamespaceFromConstants(constants map[string]any) (*Namespace, error) {
 }
 
 func (ns *Namespace) Eval(exp string) (value any, tp string, err error) {
-	ast, issues := ns.currentEnv.Compile(exp)
+	ast, issues := ns.currentEnv.Compile(constReferenceRe.ReplaceAllString(exp, "${1}"+celConstVariable+"."))
 	if issues != nil && issues.Err() != nil {
 		return nil, "", fmt.Errorf("%w: CEL expression compile error: %w", ErrInvalidForSnapSQL, issues.Err())
 	}
@@ -125,7 +152,7 @@ func (ns *Namespace) Eval(exp string) (value any, tp string, err error) {
 		return nil, "", fmt.Errorf("%w: CEL program creation error: %w", ErrInvalidForSnapSQL, err)
 	}
 
-	v, _, err := prg.Eval(ns.currentValues)
+	v, _, err := prg.Eval(celActivation(ns.currentValues))
 	if err != nil {
 		return nil, "", fmt.Errorf("%w: CEL program evaluation error: %w", ErrInvalidForSnapSQL, err)
 	}
@@ -151,6 +178,29 @@ func (ns *Namespace) Eval(exp string) (value any, tp string, err error) {
 	return result, inferTypeStringFromActualValues(result, v.Type()), nil
 }
 
+// celVariableName maps the project constants namespace (const), a reserved word in CEL, to the
+// name declared in the CEL environment
+func celVariableName(name string) string {
+	if name == ConstNamespace {
+		return celConstVariable
+	}
+
+	return name
+}
+
+func celActivation(values map[string]any) map[string]any {
+	v, ok := values[ConstNamespace]
+	if !ok {
+		return values
+	}
+
+	activation := maps.Clone(values)
+	delete(activation, ConstNamespace)
+	activation[celConstVariable] = v
+
+	return activation
+}
+
 // EnterLoop creates a new frame for a loop variable
 // It can accept either an expression string or a slice of values
 func (ns *Namespace) EnterLoop(variableName string, loopTarget any) error {
@@ -130,6 +130,21 @@ type SelectStatement struct {
 	Limit   *LimitClause
 	Offset  *OffsetClause
 	For     *ForClause
+
+	// SetOperations holds the branches combined with UNION / INTERSECT / EXCEPT.
+	// The fields above describe the first branch; ORDER BY, LIMIT, OFFSET and FOR
+	// apply to the whole compound query.
+	SetOperations []*SetOperation
+}
+
+// SetOperation is a SELECT branch combined with the preceding branches
+type SetOperation struct {
+	Operator *SetOperationClause
+	Select   *SelectClause
+	From     *FromClause
+	Where    *WhereClause
+	GroupBy  *GroupByClause
+	Having   *HavingClause
 }
 
 func NewSelectStatement(leadingTokens []tokenizer.Token, with *WithClause, clauses []ClauseNode) *SelectStatement {
@@ -348,6 +363,58 @@ func (n *DeleteFromStatement) CTE() *WithClause {
 
 var _ StatementNode = (*DeleteFromStatement)(nil)
 
+// MergeStatement represents a MERGE INTO statement.
+type MergeStatement struct {
+	baseStatement
+
+	Into      *MergeIntoClause
+	Using     *UsingClause
+	When      []*WhenMatchedClause
+	Returning *ReturningClause
+}
+
+// NewMergeStatement creates a new MergeStatement node.
+func NewMergeStatement(leadingTokens []tokenizer.Token, with *WithClause, clauses []ClauseNode) *MergeStatement {
+	return &MergeStatement{
+		baseStatement: baseStatement{
+			leadingTokens: leadingTokens,
+			with:          with,
+			clauses:       clauses,
+		},
+	}
+}
+
+// Clauses implements StatementNode.
+func (n *MergeStatement) Clauses() []ClauseNode {
+	return n.clauses
+}
+
+// Position implements StatementNode.
+func (n *MergeStatement) Position() tokenizer.Position {
+	panic("unimplemented")
+}
+
+// RawTokens implements StatementNode.
+func (n *MergeStatement) RawTokens() []tokenizer.Token {
+	panic("unimplemented")
+}
+
+// Type implements StatementNode.
+func (n *MergeStatement) Type() NodeType {
+	return MERGE_STATEMENT
+}
+
+func (n *MergeStatement) String() string {
+	return "MERGE"
+}
+
+// CTE returns the WITH clause (CTE definitions) for the MERGE statement.
+func (n *MergeStatement) CTE() *WithClause {
+	return n.with
+}
+
+var _ StatementNode = (*MergeStatement)(nil)
+
 // External setter functions for StatementNode
 
 // SetFieldSources sets field sources for a statement
@@ -360,6 +427,8 @@ func SetFieldSources(stmt StatementNode, sources map[string]*SQFieldSource) {
 		bs.fieldSources = sources
 	} else if bs, ok := stmt.(*DeleteFromStatement); ok {
 		bs.fieldSources = sources
+	} else if bs, ok := stmt.(*MergeStatement); ok {
+		bs.fieldSources = sources
 	}
 }
 
@@ -373,6 +442,8 @@ func SetTableReferences(stmt StatementNode, refs map[string]*SQTableReference) {
 		bs.tableReferences = refs
 	} else if bs, ok := stmt.(*DeleteFromStatement); ok {
 		bs.tableReferences = refs
+	} else if bs, ok := stmt.(*MergeStatement); ok {
+		bs.tableReferences = refs
 	}
 }
 
@@ -386,6 +457,8 @@ func SetSubqueryDependencies(stmt StatementNode, deps *SQDependencyGraph) {
 		bs.subqueryDependencies = deps
 	} else if bs, ok := stmt.(*DeleteFromStatement); ok {
 		bs.subqueryDependencies = deps
+	} else if bs, ok := stmt.(*MergeStatement); ok {
+		bs.subqueryDependencies = deps
 	}
 }
 
@@ -399,6 +472,8 @@ func SetProcessingOrder(stmt StatementNode, order []string) {
 		bs.processingOrder = order
 	} else if bs, ok := stmt.(*DeleteFromStatement); ok {
 		bs.processingOrder = order
+	} else if bs, ok := stmt.(*MergeStatement); ok {
+		bs.processingOrder = order
 	}
 }
 
@@ -412,5 +487,7 @@ func SetSubqueryAnalysis(stmt StatementNode, analysis *SubqueryAnalysisResult) {
 		bs.subqueryAnalysis = analysis
 	} else if bs, ok := stmt.(*DeleteFromStatement); ok {
 		bs.subqueryAnalysis = analysis
+	} else if bs, ok := stmt.(*MergeStatement); ok {
+		bs.subqueryAnalysis = analysis
 	}
 }
@@ -145,6 +145,7 @@ func (dg *SQDependencyGraph) AddNode(node *SQDependencyNode) {
 // SQDependencyNode represents a node in the dependency graph
 type SQDependencyNode struct {
 	ID           string              // Unique ID
+	Alias        string              // Alias of FROM clause subquery (derived table name)
 	Statement    StatementNode       // Statement reference
 	NodeType     SQDependencyType    // Node type
 	Dependencies []string            // Dependent node IDs
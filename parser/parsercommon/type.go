@@ -91,6 +91,17 @@ const (
 
 	// COLUMN_REFERENCE represents a column reference node.
 	COLUMN_REFERENCE
+	// SET_OPERATION_CLAUSE represents a UNION / INTERSECT / EXCEPT operator between SELECT branches.
+	SET_OPERATION_CLAUSE
+
+	// MERGE_STATEMENT represents a MERGE statement.
+	MERGE_STATEMENT
+	// MERGE_INTO_CLAUSE represents a MERGE INTO clause.
+	MERGE_INTO_CLAUSE
+	// USING_CLAUSE represents the USING ... ON clause of a MERGE statement.
+	USING_CLAUSE
+	// WHEN_MATCHED_CLAUSE represents a WHEN [NOT] MATCHED clause of a MERGE statement.
+	WHEN_MATCHED_CLAUSE
 	// LAST_NODE_TYPE marks the upper bound (sentinel) for node types.
 	LAST_NODE_TYPE
 )
@@ -173,6 +184,17 @@ func (n NodeType) String() string {
 		return "RETURNING"
 	case COLUMN_REFERENCE:
 		return "COLUMN_REFERENCE"
+	case SET_OPERATION_CLAUSE:
+		return "SET_OPERATION"
+	// merge
+	case MERGE_STATEMENT:
+		return "MERGE_STATEMENT"
+	case MERGE_INTO_CLAUSE:
+		return "MERGE_INTO"
+	case USING_CLAUSE:
+		return "USING"
+	case WHEN_MATCHED_CLAUSE:
+		return "WHEN_MATCHED"
 	default:
 		return "UNKNOWN"
 	}
@@ -3,6 +3,7 @@ package parserstep2
 
 import (
 	"slices"
+	"strings"
 
 	pc "github.com/shibukawa/parsercombinator"
 	cmn "github.com/shibukawa/snapsql/parser/parsercommon"
@@ -91,6 +92,30 @@ func primitiveType(typeName string, types ...tokenizer.TokenType) pc.Parser[Enti
 	})
 }
 
+// keyword matches a non-reserved keyword (tokenized as an identifier) by its value
+func keyword(typeName, word string) pc.Parser[Entity] {
+	return pc.Trace(typeName, func(pctx *pc.ParseContext[Entity], tokens []pc.Token[Entity]) (int, []pc.Token[Entity], error) {
+		if len(tokens) > 0 && tokens[0].Type == "raw" {
+			o := tokens[0].Val.Original
+			if strings.EqualFold(o.Value, word) {
+				return 1, []pc.Token[Entity]{
+					{
+						Type: typeName,
+						Pos:  tokens[0].Pos,
+						Val: Entity{
+							Original:  o,
+							rawTokens: []tokenizer.Token{o},
+						},
+						Raw: o.Value,
+					},
+				}, nil
+			}
+		}
+
+		return 0, nil, pc.ErrNotMatch
+	})
+}
+
 // anyIdentifier parses any valid identifier (including contextual and quoted)
 var anyIdentifier = ws(
 	pc.Trace("any-identifier", func(pctx *pc.ParseContext[Entity], tokens []pc.Token[Entity]) (int, []pc.Token[Entity], error) {
@@ -126,6 +151,11 @@ var (
 		ws(primitiveType("group", tokenizer.GROUP)),
 		ws(primitiveType("by", tokenizer.BY)))
 
+	// UNION / INTERSECT / EXCEPT with optional ALL / DISTINCT starts the next SELECT branch
+	setOperationClause = pc.SeqWithLabel("set operation clause",
+		ws(primitiveType("set-operation", tokenizer.UNION, tokenizer.INTERSECT, tokenizer.EXCEPT)),
+		pc.Optional(ws(primitiveType("quantifier", tokenizer.ALL, tokenizer.DISTINCT))))
+
 	orderByClause = ws(pc.SeqWithLabel("order by clause",
 		ws(primitiveType("order", tokenizer.ORDER)),
 		primitiveType("by", tokenizer.BY),
@@ -139,13 +169,30 @@ var (
 	// valuesClause
 	valuesClause = ws(primitiveType("values", tokenizer.VALUES))
 
-	onConflictClause = pc.SeqWithLabel("on conflict clause",
-		ws(primitiveType("on", tokenizer.ON)),
-		ws(primitiveType("conflict", tokenizer.CONFLICT)))
+	// ON CONFLICT (PostgreSQL, SQLite) or ON DUPLICATE KEY UPDATE (MySQL, MariaDB)
+	onConflictClause = pc.Or(
+		pc.SeqWithLabel("on conflict clause",
+			ws(primitiveType("on", tokenizer.ON)),
+			ws(primitiveType("conflict", tokenizer.CONFLICT))),
+		pc.SeqWithLabel("on duplicate key update clause",
+			ws(primitiveType("on", tokenizer.ON)),
+			ws(keyword("duplicate", "DUPLICATE")),
+			ws(keyword("key", "KEY")),
+			ws(primitiveType("update", tokenizer.UPDATE))))
 
 	deleteFromStatement = pc.SeqWithLabel("delete from clause",
 		ws(primitiveType("delete", tokenizer.DELETE)),
 		ws(primitiveType("from", tokenizer.FROM)))
 
-	// --- Statement Keyword Parsers (for DELETE) ---
+	// --- Statement Keyword Parsers (for MERGE) ---
+	mergeIntoStatement = pc.SeqWithLabel("merge into statement",
+		ws(primitiveType("merge", tokenizer.MERGE)),
+		ws(primitiveType("into", tokenizer.INTO)))
+
+	usingClause = ws(primitiveType("using", tokenizer.USING))
+
+	whenMatchedClause = pc.SeqWithLabel("when matched clause",
+		ws(primitiveType("when", tokenizer.WHEN)),
+		pc.Optional(ws(primitiveType("not", tokenizer.NOT))),
+		ws(keyword("matched", "MATCHED")))
 )
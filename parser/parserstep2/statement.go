@@ -106,19 +106,47 @@ func subQuery(pctx *pc.ParseContext[Entity], t []pc.Token[Entity]) (consumed int
 }
 
 var (
-	// firstCte returns: identity, as, subquery
+	// cteColumnList parses the optional column list: name (col1, col2) AS (...)
+	cteColumnList = pc.Seq(
+		ws(parenOpen),
+		anyIdentifier,
+		pc.ZeroOrMore("cte columns", pc.Seq(comma, anyIdentifier)),
+		ws(parenClose),
+	)
+	// firstCte returns: identity, [column list], as, subquery
 	firstCte = pc.Seq(
 		anyIdentifier,
+		pc.Optional(cteColumnList),
 		as,
 		subQuery,
 	)
-	// subCte returns: comma, identity, as, subquery
+	// subCte returns: comma, identity, [column list], as, subquery
 	subCte = pc.Seq(
 		comma,
 		firstCte,
 	)
 )
 
+// newCTEDefinition builds a CTE definition from the match of firstCte
+func newCTEDefinition(match []pc.Token[Entity]) cmn.CTEDefinition {
+	sq := match[len(match)-1]
+
+	var columns []string
+
+	for _, t := range match[1 : len(match)-2] {
+		if t.Type == "identifier" {
+			columns = append(columns, t.Val.Original.Value)
+		}
+	}
+
+	return cmn.CTEDefinition{
+		Name:      match[0].Val.Original.Value,
+		Columns:   columns,
+		Select:    sq.Val.NewValue,
+		RawTokens: sq.Val.RawTokens(),
+	}
+}
+
 func parseCTE() pc.Parser[Entity] {
 	return pc.Trace("with-clause", func(pctx *pc.ParseContext[Entity], tokens []pc.Token[Entity]) (int, []pc.Token[Entity], error) {
 		consume, heading, err := withClause(pctx, tokens)
@@ -148,11 +176,7 @@ func parseCTE() pc.Parser[Entity] {
 
 		offset += consume
 
-		cteDefs = append(cteDefs, cmn.CTEDefinition{
-			Name:      match[0].Val.Original.Value,
-			Select:    match[2].Val.NewValue,
-			RawTokens: match[2].Val.RawTokens(),
-		})
+		cteDefs = append(cteDefs, newCTEDefinition(match))
 
 		// second and subsequent CTEs
 		for {
@@ -171,11 +195,7 @@ func parseCTE() pc.Parser[Entity] {
 
 			offset += consume
 
-			cteDefs = append(cteDefs, cmn.CTEDefinition{
-				Name:      match[1].Val.Original.Value,
-				Select:    match[3].Val.NewValue,
-				RawTokens: match[3].Val.RawTokens(),
-			})
+			cteDefs = append(cteDefs, newCTEDefinition(match[1:]))
 		}
 
 		var trailingTokens []tok.Token
@@ -209,7 +229,8 @@ var statementStart = pc.Or(
 	selectStatement,
 	insertIntoStatement,
 	updateStatement,
-	deleteFromStatement)
+	deleteFromStatement,
+	mergeIntoStatement)
 
 func DumpStatement(tokens []pc.Token[Entity]) string {
 	var sb strings.Builder
@@ -284,6 +305,14 @@ func ParseStatement(perr *cmn.ParseError) pc.Parser[Entity] {
 					},
 				},
 			}, nil
+		case tok.MERGE:
+			return offset, []pc.Token[Entity]{
+				{
+					Val: Entity{
+						NewValue: cmn.NewMergeStatement(entityToToken(skipped), withClause, clauses),
+					},
+				},
+			}, nil
 		default:
 			perr.Add(fmt.Errorf("%w: unsupported statement type %s at %s",
 				cmn.ErrInvalidForSnapSQL,
@@ -385,6 +414,18 @@ func detectWrappedIfCondition(clauseHead []pc.Token[Entity], clauseBody []pc.Tok
 }
 
 func splitter(tt tok.TokenType) pc.Parser[Entity] {
+	if tt == tok.MERGE {
+		// UPDATE SET / INSERT VALUES / DELETE inside WHEN clauses are a part of the WHEN clause
+		return pc.Or(
+			ws(parenOpen),
+			ws(parenClose),
+			mergeIntoStatement,
+			usingClause,
+			whenMatchedClause,
+			returningClause,
+		)
+	}
+
 	return pc.Or(
 		ws(parenOpen),
 		ws(parenClose),
@@ -400,6 +441,7 @@ func splitter(tt tok.TokenType) pc.Parser[Entity] {
 		offsetClause,
 		forClause,
 		returningClause,
+		when(tt == tok.SELECT, setOperationClause),
 
 		// for insert
 		insertIntoStatement,
@@ -422,6 +464,9 @@ func clauseIter(pctx *pc.ParseContext[Entity], tt tok.TokenType, tokens []pc.Tok
 		count := 0
 		consume := 0
 		nest := 0
+		// ON CONFLICT ... DO UPDATE SET ... WHERE / ON DUPLICATE KEY UPDATE ... VALUES(...)
+		// の中のキーワードは RETURNING まで ON CONFLICT 句の一部として扱う
+		upsert := false
 
 		var skipped []pc.Token[Entity]
 
@@ -456,7 +501,15 @@ func clauseIter(pctx *pc.ParseContext[Entity], tt tok.TokenType, tokens []pc.Tok
 
 					consume += part.Consume + len(part.Skipped)
 					nest = 1
+				} else if upsert && part.Match[0].Val.Original.Type != tok.RETURNING {
+					skipped = append(skipped, part.Skipped...)
+					for _, m := range part.Match {
+						skipped = append(skipped, tokenToEntity(m.Val.RawTokens())...)
+					}
+
+					consume += part.Consume + len(part.Skipped)
 				} else {
+					upsert = tt == tok.INSERT && part.Match[0].Val.Original.Type == tok.ON
 					yield(count, pc.Consume[Entity]{
 						Consume: part.Consume,
 						Skipped: append(skipped, part.Skipped...),
@@ -576,6 +629,11 @@ func newClauseNode(clauseHead []pc.Token[Entity], clauseBody []pc.Token[Entity],
 			clauseTokenSourceText(0, 0, clauseHead),
 			entityToToken(clauseHead),
 			entityToToken(clauseBody))
+	case tok.UNION, tok.INTERSECT, tok.EXCEPT:
+		clauseNode = cmn.NewSetOperationClause(
+			clauseTokenSourceText(0, len(clauseHead)-1, clauseHead),
+			entityToToken(clauseHead),
+			entityToToken(clauseBody))
 	case tok.RETURNING:
 		clauseNode = cmn.NewReturningClause(
 			clauseTokenSourceText(0, 0, clauseHead),
@@ -595,7 +653,7 @@ func newClauseNode(clauseHead []pc.Token[Entity], clauseBody []pc.Token[Entity],
 			entityToToken(clauseBody))
 	case tok.ON:
 		clauseNode = cmn.NewOnConflictClause(
-			clauseTokenSourceText(0, 1, clauseHead),
+			clauseTokenSourceText(0, len(clauseHead)-1, clauseHead),
 			entityToToken(clauseHead),
 			entityToToken(clauseBody))
 
@@ -618,6 +676,23 @@ func newClauseNode(clauseHead []pc.Token[Entity], clauseBody []pc.Token[Entity],
 			entityToToken(clauseHead),
 			entityToToken(clauseBody))
 
+	// Merge
+	case tok.MERGE:
+		clauseNode = cmn.NewMergeIntoClause(
+			clauseTokenSourceText(0, 1, clauseHead),
+			entityToToken(clauseHead),
+			entityToToken(clauseBody))
+	case tok.USING:
+		clauseNode = cmn.NewUsingClause(
+			clauseTokenSourceText(0, 0, clauseHead),
+			entityToToken(clauseHead),
+			entityToToken(clauseBody))
+	case tok.WHEN:
+		clauseNode = cmn.NewWhenMatchedClause(
+			clauseTokenSourceText(0, len(clauseHead)-1, clauseHead),
+			entityToToken(clauseHead),
+			entityToToken(clauseBody))
+
 	default:
 		panic("unknown clause type")
 	}
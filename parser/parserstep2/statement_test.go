@@ -126,6 +126,24 @@ func TestParseStatementWithAllClauses(t *testing.T) {
 			wantClauses: 5, // INSERT INTO, VALUES, WHERE, ON CONFLICT, RETURNING
 			wantType:    cmn.INSERT_INTO_STATEMENT,
 		},
+		{
+			name:        "on conflict do update with where",
+			sql:         `INSERT INTO users (a, b) VALUES (1, 2) ON CONFLICT (a) DO UPDATE SET b = EXCLUDED.b WHERE users.b <> EXCLUDED.b RETURNING a, b;`,
+			wantClauses: 4, // INSERT INTO, VALUES, ON CONFLICT (including SET and WHERE), RETURNING
+			wantType:    cmn.INSERT_INTO_STATEMENT,
+		},
+		{
+			name:        "on duplicate key update",
+			sql:         `INSERT INTO users (a, b) VALUES (1, 2) ON DUPLICATE KEY UPDATE b = VALUES(b);`,
+			wantClauses: 3, // INSERT INTO, VALUES, ON DUPLICATE KEY UPDATE (including VALUES())
+			wantType:    cmn.INSERT_INTO_STATEMENT,
+		},
+		{
+			name:        "merge",
+			sql:         `MERGE INTO users u USING (SELECT id, name FROM tmp) s ON u.id = s.id WHEN MATCHED THEN UPDATE SET name = s.name WHEN NOT MATCHED THEN INSERT (id, name) VALUES (s.id, s.name) RETURNING u.id;`,
+			wantClauses: 5, // MERGE INTO, USING, WHEN MATCHED, WHEN NOT MATCHED, RETURNING
+			wantType:    cmn.MERGE_STATEMENT,
+		},
 		{
 			name:        "insert into select",
 			sql:         `INSERT INTO users (id, name) SELECT id, name FROM tmp WHERE id > 10 ORDER BY id DESC LIMIT 5 OFFSET 2;`,
@@ -190,6 +208,16 @@ func TestClauseSourceText(t *testing.T) {
 			sql:          `INSERT INTO users (a, b, c) VALUES (1, 2, 3) WHERE a > 1 ON CONFLICT (a) DO UPDATE SET b = EXCLUDED.b RETURNING a, b;`,
 			wantSrcTexts: []string{"INTO", "VALUES", "WHERE", "ON CONFLICT", "RETURNING"},
 		},
+		{
+			name:         "on duplicate key update",
+			sql:          `INSERT INTO users (a, b) VALUES (1, 2) ON DUPLICATE KEY UPDATE b = VALUES(b);`,
+			wantSrcTexts: []string{"INTO", "VALUES", "ON DUPLICATE KEY UPDATE"},
+		},
+		{
+			name:         "merge",
+			sql:          `MERGE INTO users USING tmp ON users.id = tmp.id WHEN MATCHED AND tmp.deleted THEN DELETE WHEN NOT MATCHED THEN INSERT (id) VALUES (tmp.id);`,
+			wantSrcTexts: []string{"MERGE INTO", "USING", "WHEN MATCHED", "WHEN NOT MATCHED"},
+		},
 		{
 			name:         "all update clauses",
 			sql:          `UPDATE users SET name = 'Bob', age = 20 WHERE id = 1 RETURNING id, name;`,
@@ -241,6 +269,7 @@ func TestParseStatementWithCTE(t *testing.T) {
 		wantType      cmn.NodeType
 		wantCTEs      int
 		wantRecursive bool
+		wantColumns   []string
 		wantErr       bool
 	}{
 		{
@@ -280,6 +309,27 @@ func TestParseStatementWithCTE(t *testing.T) {
 			wantRecursive: true,
 			wantErr:       false,
 		},
+		{
+			name: "select with recursive CTE referencing itself",
+			args: args{
+				src: `WITH RECURSIVE tree AS (SELECT id, parent_id FROM categories WHERE parent_id IS NULL UNION ALL SELECT c.id, c.parent_id FROM categories c JOIN tree t ON c.parent_id = t.id) SELECT id FROM tree;`,
+			},
+			wantType:      cmn.SELECT_STATEMENT,
+			wantCTEs:      1,
+			wantRecursive: true,
+			wantErr:       false,
+		},
+		{
+			name: "select with recursive CTE and column list",
+			args: args{
+				src: `WITH RECURSIVE tree (id, depth) AS (SELECT id, 1 FROM categories UNION ALL SELECT c.id, t.depth + 1 FROM categories c JOIN tree t ON c.parent_id = t.id) SELECT id, depth FROM tree;`,
+			},
+			wantType:      cmn.SELECT_STATEMENT,
+			wantCTEs:      1,
+			wantRecursive: true,
+			wantColumns:   []string{"id", "depth"},
+			wantErr:       false,
+		},
 		{
 			name: "select with CTE and extra comma",
 			args: args{
@@ -361,6 +411,7 @@ func TestParseStatementWithCTE(t *testing.T) {
 				assert.True(t, stmt.CTE() != nil)
 				assert.Equal(t, tt.wantRecursive, stmt.CTE().Recursive, "ParseStatement() should return correct recursive flag")
 				assert.Equal(t, tt.wantCTEs, len(stmt.CTE().CTEs), "ParseStatement() should return correct number of CTEs")
+				assert.Equal(t, tt.wantColumns, stmt.CTE().CTEs[0].Columns, "ParseStatement() should return the column list of the CTE")
 			}
 		})
 	}
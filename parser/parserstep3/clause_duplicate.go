@@ -15,6 +15,11 @@ var ErrDuplicateClause = errors.New("duplicate clause detected")
 func ValidateClauseDuplicates(clauses []cmn.ClauseNode, perr *cmn.ParseError) {
 	seen := make(map[cmn.NodeType]int)
 	for _, clause := range clauses {
+		// MERGE の WHEN [NOT] MATCHED 句は繰り返し書ける
+		if clause.Type() == cmn.WHEN_MATCHED_CLAUSE {
+			continue
+		}
+
 		seen[clause.Type()]++
 		if seen[clause.Type()] > 1 {
 			if perr != nil {
@@ -27,6 +27,8 @@ var nodeTypeToTokenTypes = map[cmn.NodeType][]tok.TokenType{
 	cmn.WITH_CLAUSE:        {tok.WITH},
 	cmn.ON_CONFLICT_CLAUSE: {tok.CONFLICT, tok.ON},
 	cmn.FOR_CLAUSE:         {tok.FOR},
+	cmn.MERGE_INTO_CLAUSE:  {tok.INTO},
+	cmn.USING_CLAUSE:       {tok.USING},
 }
 
 // Extracts the actual clause keyword as written by the user from RawTokens
@@ -76,6 +78,8 @@ func ValidateClauseOrder(stmtType cmn.NodeType, clauses []cmn.ClauseNode, perr *
 		key = "UPDATE"
 	case cmn.DELETE_FROM_STATEMENT:
 		key = "DELETE"
+	case cmn.MERGE_STATEMENT:
+		key = "MERGE"
 	default:
 		return // No order check for other types
 	}
@@ -49,6 +49,25 @@ func TestValidateClauseOrder(t *testing.T) {
 			wantErr:   true,
 			wantMsg:   "clause order violation: Please move 'select' at 1:33 clause before 'RETURNING' clause at 1:20",
 		},
+		{
+			name:      "INSERT ON CONFLICT before RETURNING",
+			statement: cmn.INSERT_INTO_STATEMENT,
+			sql:       "INSERT INTO t (id) VALUES (1) ON CONFLICT (id) DO UPDATE SET id = EXCLUDED.id RETURNING id",
+			wantErr:   false,
+		},
+		{
+			name:      "MERGE with repeated WHEN clauses",
+			statement: cmn.MERGE_STATEMENT,
+			sql:       "MERGE INTO t USING s ON t.id = s.id WHEN MATCHED THEN DELETE WHEN NOT MATCHED THEN INSERT (id) VALUES (s.id) RETURNING t.id",
+			wantErr:   false,
+		},
+		{
+			name:      "MERGE invalid order (WHEN before USING)",
+			statement: cmn.MERGE_STATEMENT,
+			sql:       "MERGE INTO t WHEN MATCHED THEN DELETE USING s ON t.id = s.id",
+			wantErr:   true,
+			wantMsg:   "clause order violation: Please move 'USING' at 1:39 clause before 'WHEN MATCHED' clause at 1:14",
+		},
 	}
 
 	for _, tt := range tests {
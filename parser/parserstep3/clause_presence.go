@@ -35,6 +35,8 @@ func ValidateClausePresence(stmtType cmn.NodeType, clauses []cmn.ClauseNode, per
 		key = "UPDATE"
 	case cmn.DELETE_FROM_STATEMENT:
 		key = "DELETE"
+	case cmn.MERGE_STATEMENT:
+		key = "MERGE"
 	default:
 		key = ""
 	}
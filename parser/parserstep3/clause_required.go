@@ -16,6 +16,7 @@ var required = map[string][]cmn.NodeType{
 	"INSERT_SELECT": {cmn.INSERT_INTO_CLAUSE, cmn.SELECT_CLAUSE, cmn.FROM_CLAUSE},
 	"UPDATE":        {cmn.UPDATE_CLAUSE, cmn.SET_CLAUSE},
 	"DELETE":        {cmn.DELETE_FROM_CLAUSE},
+	"MERGE":         {cmn.MERGE_INTO_CLAUSE, cmn.USING_CLAUSE, cmn.WHEN_MATCHED_CLAUSE},
 }
 
 // ValidateClauseRequired checks for required (mandatory) clauses for a given statement type.
@@ -36,6 +37,8 @@ func ValidateClauseRequired(stmtType cmn.NodeType, clauses []cmn.ClauseNode, per
 		key = "UPDATE"
 	case cmn.DELETE_FROM_STATEMENT:
 		key = "DELETE"
+	case cmn.MERGE_STATEMENT:
+		key = "MERGE"
 	default:
 		return // No check for other types
 	}
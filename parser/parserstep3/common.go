@@ -39,15 +39,15 @@ var clauseOrder = map[string]map[cmn.NodeType]int{
 		cmn.ORDER_BY_CLAUSE:    8,
 		cmn.LIMIT_CLAUSE:       9,
 		cmn.OFFSET_CLAUSE:      10,
-		cmn.RETURNING_CLAUSE:   11,
-		cmn.ON_CONFLICT_CLAUSE: 12,
+		cmn.ON_CONFLICT_CLAUSE: 11,
+		cmn.RETURNING_CLAUSE:   12,
 	},
 	"INSERT_VALUES": {
 		cmn.WITH_CLAUSE:        1,
 		cmn.INSERT_INTO_CLAUSE: 2,
 		cmn.VALUES_CLAUSE:      3,
-		cmn.RETURNING_CLAUSE:   4,
-		cmn.ON_CONFLICT_CLAUSE: 5,
+		cmn.ON_CONFLICT_CLAUSE: 4,
+		cmn.RETURNING_CLAUSE:   5,
 	},
 	"UPDATE": {
 		cmn.WITH_CLAUSE:      1,
@@ -62,4 +62,11 @@ var clauseOrder = map[string]map[cmn.NodeType]int{
 		cmn.WHERE_CLAUSE:       3,
 		cmn.RETURNING_CLAUSE:   4,
 	},
+	"MERGE": {
+		cmn.WITH_CLAUSE:         1,
+		cmn.MERGE_INTO_CLAUSE:   2,
+		cmn.USING_CLAUSE:        3,
+		cmn.WHEN_MATCHED_CLAUSE: 4,
+		cmn.RETURNING_CLAUSE:    5,
+	},
 }
@@ -7,6 +7,17 @@ import (
 // Execute runs all clause checks and assigns clause fields to the statement struct.
 func Execute(stmt cmn.StatementNode) error {
 	perr := &cmn.ParseError{}
+
+	if s, ok := stmt.(*cmn.SelectStatement); ok && hasSetOperation(s.Clauses()) {
+		executeSetOperation(s, perr)
+
+		if len(perr.Errors) > 0 {
+			return perr
+		}
+
+		return nil
+	}
+
 	clauses := ValidateClausePresence(stmt.Type(), stmt.Clauses(), perr)
 	ValidateClauseDuplicates(clauses, perr)
 	ValidateClauseRequired(stmt.Type(), clauses, perr)
@@ -32,6 +43,8 @@ func assignStatementFields(stmt cmn.StatementNode, clauses []cmn.ClauseNode) {
 		assignUpdateStatementFields(s, clauses)
 	case *cmn.DeleteFromStatement:
 		assignDeleteStatementFields(s, clauses)
+	case *cmn.MergeStatement:
+		assignMergeStatementFields(s, clauses)
 	}
 }
 
@@ -174,3 +187,26 @@ func assignDeleteStatementFields(stmt *cmn.DeleteFromStatement, clauses []cmn.Cl
 		}
 	}
 }
+
+func assignMergeStatementFields(stmt *cmn.MergeStatement, clauses []cmn.ClauseNode) {
+	for _, c := range clauses {
+		switch c.Type() {
+		case cmn.MERGE_INTO_CLAUSE:
+			if v, ok := c.(*cmn.MergeIntoClause); ok {
+				stmt.Into = v
+			}
+		case cmn.USING_CLAUSE:
+			if v, ok := c.(*cmn.UsingClause); ok {
+				stmt.Using = v
+			}
+		case cmn.WHEN_MATCHED_CLAUSE:
+			if v, ok := c.(*cmn.WhenMatchedClause); ok {
+				stmt.When = append(stmt.When, v)
+			}
+		case cmn.RETURNING_CLAUSE:
+			if v, ok := c.(*cmn.ReturningClause); ok {
+				stmt.Returning = v
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package parserstep3
+
+import (
+	"errors"
+	"fmt"
+
+	cmn "github.com/shibukawa/snapsql/parser/parsercommon"
+	tok "github.com/shibukawa/snapsql/tokenizer"
+)
+
+// ErrInvalidSetOperation is returned when a UNION / INTERSECT / EXCEPT branch is malformed
+var ErrInvalidSetOperation = errors.New("invalid set operation")
+
+// compoundClauses are the clauses that apply to the whole compound query. They must follow the
+// last SELECT branch.
+var compoundClauses = map[cmn.NodeType]bool{
+	cmn.ORDER_BY_CLAUSE: true,
+	cmn.LIMIT_CLAUSE:    true,
+	cmn.OFFSET_CLAUSE:   true,
+	cmn.FOR_CLAUSE:      true,
+}
+
+// hasSetOperation returns true if the clause list combines SELECT branches
+func hasSetOperation(clauses []cmn.ClauseNode) bool {
+	for _, c := range clauses {
+		if c.Type() == cmn.SET_OPERATION_CLAUSE {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitSetOperationBranches splits the clauses of a compound SELECT at each set operator
+func splitSetOperationBranches(clauses []cmn.ClauseNode) ([][]cmn.ClauseNode, []*cmn.SetOperationClause) {
+	branches := [][]cmn.ClauseNode{nil}
+
+	var operators []*cmn.SetOperationClause
+
+	for _, c := range clauses {
+		if op, ok := c.(*cmn.SetOperationClause); ok {
+			operators = append(operators, op)
+			branches = append(branches, nil)
+
+			continue
+		}
+
+		branches[len(branches)-1] = append(branches[len(branches)-1], c)
+	}
+
+	return branches, operators
+}
+
+// executeSetOperation validates every branch of a compound SELECT on its own and assigns the
+// branches to SelectStatement.SetOperations
+func executeSetOperation(stmt *cmn.SelectStatement, perr *cmn.ParseError) {
+	branches, operators := splitSetOperationBranches(stmt.Clauses())
+
+	for _, op := range operators {
+		for _, t := range op.ContentTokens() {
+			switch t.Type {
+			case tok.WHITESPACE, tok.LINE_COMMENT:
+				continue
+			case tok.BLOCK_COMMENT:
+				if t.Directive == nil {
+					continue
+				}
+			}
+
+			perr.Add(fmt.Errorf("%w: %s at %s must be followed by SELECT (parenthesized branches and directives are not supported)", ErrInvalidSetOperation, op.Operator, toPosText(op)))
+
+			break
+		}
+	}
+
+	validBranches := make([][]cmn.ClauseNode, 0, len(branches))
+
+	for i, branch := range branches {
+		clauses := ValidateClausePresence(cmn.SELECT_STATEMENT, branch, perr)
+
+		for _, c := range clauses {
+			if i < len(branches)-1 && compoundClauses[c.Type()] {
+				perr.Add(fmt.Errorf("%w: '%s' at %s must follow the last SELECT of %s", ErrInvalidSetOperation, c.SourceText(), toPosText(c), operators[i].Operator))
+			}
+
+			if i > 0 && c.Type() == cmn.WITH_CLAUSE {
+				perr.Add(fmt.Errorf("%w: WITH clause at %s must precede the first SELECT", ErrInvalidSetOperation, toPosText(c)))
+			}
+		}
+
+		ValidateClauseDuplicates(clauses, perr)
+		ValidateClauseRequired(cmn.SELECT_STATEMENT, clauses, perr)
+		ValidateClauseOrder(cmn.SELECT_STATEMENT, clauses, perr)
+
+		validBranches = append(validBranches, clauses)
+	}
+
+	assignSelectStatementFields(stmt, validBranches[0])
+
+	for i, clauses := range validBranches[1:] {
+		branch := &cmn.SelectStatement{}
+		assignSelectStatementFields(branch, clauses)
+
+		stmt.SetOperations = append(stmt.SetOperations, &cmn.SetOperation{
+			Operator: operators[i],
+			Select:   branch.Select,
+			From:     branch.From,
+			Where:    branch.Where,
+			GroupBy:  branch.GroupBy,
+			Having:   branch.Having,
+		})
+
+		if i == len(validBranches)-2 {
+			stmt.OrderBy = branch.OrderBy
+			stmt.Limit = branch.Limit
+			stmt.Offset = branch.Offset
+			stmt.For = branch.For
+		}
+	}
+}
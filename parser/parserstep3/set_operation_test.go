@@ -0,0 +1,89 @@
+package parserstep3
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	cmn "github.com/shibukawa/snapsql/parser/parsercommon"
+	step2 "github.com/shibukawa/snapsql/parser/parserstep2"
+	tokenizer "github.com/shibukawa/snapsql/tokenizer"
+)
+
+func TestExecuteSetOperation(t *testing.T) {
+	tests := []struct {
+		name      string
+		sql       string
+		operators []string
+		wantErr   bool
+	}{
+		{
+			name:      "union_all_with_trailing_order_by",
+			sql:       "SELECT id FROM users WHERE active = true UNION ALL SELECT id FROM groups ORDER BY id LIMIT 10",
+			operators: []string{"UNION ALL"},
+		},
+		{
+			name:      "chained_operators",
+			sql:       "SELECT id FROM a UNION SELECT id FROM b INTERSECT SELECT id FROM c EXCEPT DISTINCT SELECT id FROM d",
+			operators: []string{"UNION", "INTERSECT", "EXCEPT DISTINCT"},
+		},
+		{
+			name:    "order_by_before_union_is_error",
+			sql:     "SELECT id FROM a ORDER BY id UNION SELECT id FROM b",
+			wantErr: true,
+		},
+		{
+			name:    "parenthesized_branch_is_error",
+			sql:     "SELECT id FROM a UNION (SELECT id FROM b)",
+			wantErr: true,
+		},
+		{
+			name:    "duplicate_where_in_branch_is_error",
+			sql:     "SELECT id FROM a UNION SELECT id FROM b WHERE x = 1 WHERE y = 2",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := tokenizer.Tokenize(tt.sql)
+			assert.NoError(t, err)
+
+			stmt, err := step2.Execute(tokens)
+			assert.NoError(t, err)
+
+			err = Execute(stmt)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+
+			selectStmt, ok := stmt.(*cmn.SelectStatement)
+			assert.True(t, ok)
+			assert.Equal(t, len(tt.operators), len(selectStmt.SetOperations))
+
+			for i, op := range selectStmt.SetOperations {
+				assert.Equal(t, tt.operators[i], op.Operator.Operator)
+				assert.NotZero(t, op.Select)
+				assert.NotZero(t, op.From)
+			}
+
+			assert.NotZero(t, selectStmt.From)
+		})
+	}
+
+	t.Run("compound_clauses_belong_to_statement", func(t *testing.T) {
+		tokens, err := tokenizer.Tokenize("SELECT id FROM users UNION ALL SELECT id FROM groups ORDER BY id LIMIT 10")
+		assert.NoError(t, err)
+
+		stmt, err := step2.Execute(tokens)
+		assert.NoError(t, err)
+		assert.NoError(t, Execute(stmt))
+
+		selectStmt := stmt.(*cmn.SelectStatement)
+		assert.NotZero(t, selectStmt.OrderBy)
+		assert.NotZero(t, selectStmt.Limit)
+		assert.Zero(t, selectStmt.SetOperations[0].Where)
+	})
+}
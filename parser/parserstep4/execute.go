@@ -36,6 +36,27 @@ func ExecuteWithOptions(stmt cmn.StatementNode, inspectMode bool) error {
 			finalizeHavingClause(s.Having, s.GroupBy, perr)
 		}
 
+		// UNION / INTERSECT / EXCEPT branches
+		for _, op := range s.SetOperations {
+			if !inspectMode {
+				finalizeSelectClause(op.Select, perr)
+			}
+
+			finalizeFromClauseWithOptions(op.From, perr, inspectMode)
+
+			if op.Where != nil {
+				emptyCheck(op.Where, perr)
+			}
+
+			if op.GroupBy != nil {
+				finalizeGroupByClause(op.GroupBy, perr)
+			}
+
+			if op.Having != nil {
+				finalizeHavingClause(op.Having, op.GroupBy, perr)
+			}
+		}
+
 		if s.OrderBy != nil {
 			finalizeOrderByClause(s.OrderBy, perr)
 		}
@@ -115,6 +136,18 @@ func ExecuteWithOptions(stmt cmn.StatementNode, inspectMode bool) error {
 			emptyCheck(s.Where, perr)
 		}
 
+		if s.Returning != nil {
+			emptyCheck(s.Returning, perr)
+			finalizeReturningClause(s.Returning, perr)
+		}
+	case *cmn.MergeStatement:
+		finalizeMergeIntoClause(s.Into, perr)
+		emptyCheck(s.Using, perr)
+
+		for _, when := range s.When {
+			emptyCheck(when, perr)
+		}
+
 		if s.Returning != nil {
 			emptyCheck(s.Returning, perr)
 			finalizeReturningClause(s.Returning, perr)
@@ -16,6 +16,7 @@ var (
 	ErrNaturalJoinWithCondition = fmt.Errorf("%w: natural join can't have condition (on/using)", cmn.ErrInvalidSQL)
 	ErrNaturalJoin              = fmt.Errorf("%w: natural join is not allowed in SnapSQL", cmn.ErrInvalidForSnapSQL)
 	ErrSubQueryNeedsAlias       = fmt.Errorf("%w: sub query needs alias", cmn.ErrInvalidSQL)
+	ErrLateralNeedsSubQuery     = fmt.Errorf("%w: LATERAL needs sub query", cmn.ErrInvalidSQL)
 	ErrRequiredClauseMissing    = fmt.Errorf("%w: required clause missing", cmn.ErrInvalidSQL)
 )
 
@@ -297,6 +298,22 @@ func parseTableReferenceWithOptions(pctx *pc.ParseContext[tok.Token], head, body
 		return cmn.TableReferenceForFrom{}, fmt.Errorf("%w: JOIN can't be use for first table reference", ErrInvalidJoinType)
 	}
 
+	// LATERAL (SELECT ...) alias
+	if len(body) > 0 && isKeyword(body[0], "LATERAL") {
+		pos := body[0].Val.Position
+
+		body = body[1:]
+		for len(body) > 0 && isSpaceOrComment(body[0].Val.Type) {
+			body = body[1:]
+		}
+
+		if len(body) == 0 || body[0].Val.Type != tok.OPENED_PARENS {
+			return cmn.TableReferenceForFrom{}, fmt.Errorf("%w at %s", ErrLateralNeedsSubQuery, pos.String())
+		}
+
+		result.Lateral = true
+	}
+
 	beforeAlias, alias, _, _, ok := pc.Find(pctx, alias, body)
 	if ok {
 		// Alias
@@ -383,5 +400,32 @@ func parseTableReferenceWithOptions(pctx *pc.ParseContext[tok.Token], head, body
 		}
 	}
 
+	// json_each(data) のように関数呼び出しをテーブルとして使う
+	if result.TableName != "" {
+		if ok {
+			result.TableFunction = isTableFunctionCall(beforeAlias)
+		} else {
+			result.TableFunction = isTableFunctionCall(body)
+		}
+	}
+
 	return result, nil
 }
+
+// isTableFunctionCall reports whether the table reference is "[schema.]name(...)"
+func isTableFunctionCall(tokens []pc.Token[tok.Token]) bool {
+	if len(tokens) == 0 || tokens[0].Val.Type != tok.IDENTIFIER {
+		return false
+	}
+
+	i := 1
+	if i+1 < len(tokens) && tokens[i].Val.Type == tok.DOT && tokens[i+1].Val.Type == tok.IDENTIFIER {
+		i += 2
+	}
+
+	for i < len(tokens) && isSpaceOrComment(tokens[i].Val.Type) {
+		i++
+	}
+
+	return i < len(tokens) && tokens[i].Val.Type == tok.OPENED_PARENS
+}
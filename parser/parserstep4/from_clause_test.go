@@ -18,6 +18,8 @@ func TestFinalizeFromClause(t *testing.T) {
 		wantTable []string
 		wantOrig  []string
 		wantJoin  []cmn.JoinType
+		wantLat   []bool
+		wantFunc  []bool
 	}{
 		{
 			name:      "single table",
@@ -151,6 +153,47 @@ func TestFinalizeFromClause(t *testing.T) {
 			sql:       "SELECT * FROM users JOIN (SELECT * FROM orders) ON users.id = orders.user_id JOIN payments p ON orders.id = p.order_id",
 			wantError: true,
 		},
+		{
+			name:      "CROSS JOIN LATERAL subquery",
+			sql:       "SELECT * FROM users u CROSS JOIN LATERAL (SELECT id FROM orders WHERE orders.user_id = u.id LIMIT 3) o",
+			wantError: false,
+			wantTable: []string{"u", "o"},
+			wantOrig:  []string{"users", ""},
+			wantJoin:  []cmn.JoinType{cmn.JoinNone, cmn.JoinCross},
+			wantLat:   []bool{false, true},
+		},
+		{
+			name:      "LEFT JOIN LATERAL subquery with AS",
+			sql:       "SELECT * FROM users u LEFT JOIN lateral (SELECT amount FROM orders WHERE orders.user_id = u.id LIMIT 1) AS o ON true",
+			wantError: false,
+			wantTable: []string{"u", "o"},
+			wantOrig:  []string{"users", ""},
+			wantJoin:  []cmn.JoinType{cmn.JoinNone, cmn.JoinLeft},
+			wantLat:   []bool{false, true},
+		},
+		{
+			name:      "LATERAL without subquery",
+			sql:       "SELECT * FROM users u CROSS JOIN LATERAL orders o",
+			wantError: true,
+		},
+		{
+			name:      "table function with AS alias",
+			sql:       "SELECT * FROM docs d CROSS JOIN json_each(d.data) AS e",
+			wantError: false,
+			wantTable: []string{"d", "e"},
+			wantOrig:  []string{"docs", "json_each"},
+			wantJoin:  []cmn.JoinType{cmn.JoinNone, cmn.JoinCross},
+			wantFunc:  []bool{false, true},
+		},
+		{
+			name:      "table function with alias",
+			sql:       "SELECT * FROM jsonb_array_elements('[1, 2]') e",
+			wantError: false,
+			wantTable: []string{"e"},
+			wantOrig:  []string{"jsonb_array_elements"},
+			wantJoin:  []cmn.JoinType{cmn.JoinNone},
+			wantFunc:  []bool{true},
+		},
 	}
 
 	for _, tc := range tests {
@@ -180,16 +223,28 @@ func TestFinalizeFromClause(t *testing.T) {
 					gotOrig := make([]string, len(got))
 
 					gotJoin := make([]cmn.JoinType, len(got))
+					gotLat := make([]bool, len(got))
+					gotFunc := make([]bool, len(got))
 
 					for i := range got {
 						gotTable[i] = got[i].Name
 						gotOrig[i] = got[i].TableName
 						gotJoin[i] = got[i].JoinType
+						gotLat[i] = got[i].Lateral
+						gotFunc[i] = got[i].TableFunction
 					}
 
 					assert.Equal(t, tc.wantTable, gotTable, "table name")
 					assert.Equal(t, tc.wantOrig, gotOrig, "original table")
 					assert.Equal(t, tc.wantJoin, gotJoin, "join type")
+
+					if tc.wantLat != nil {
+						assert.Equal(t, tc.wantLat, gotLat, "lateral")
+					}
+
+					if tc.wantFunc != nil {
+						assert.Equal(t, tc.wantFunc, gotFunc, "table function")
+					}
 				}
 			}
 		})
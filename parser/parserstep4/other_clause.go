@@ -12,6 +12,7 @@ import (
 var (
 	assign      = pc.Seq(cmn.Identifier, cmn.SP, equal)
 	singleField = pc.Seq(cmn.Identifier, cmn.SP, cmn.EOS)
+	tableField  = pc.Seq(cmn.Identifier, cmn.Dot, cmn.Identifier, cmn.SP, cmn.EOS)
 )
 
 // finalizeDeleteFromClause validates DeleteFromClause
@@ -65,6 +66,45 @@ func finalizeUpdateClause(clause *cmn.UpdateClause, perr *cmn.ParseError) {
 	*/
 }
 
+// finalizeMergeIntoClause validates MergeIntoClause
+//
+//	MERGE INTO users AS u
+func finalizeMergeIntoClause(clause *cmn.MergeIntoClause, perr *cmn.ParseError) {
+	tokens := clause.ContentTokens()
+
+	pctx := pc.NewParseContext[tok.Token]()
+	pTokens := cmn.ToParserToken(tokens)
+
+	consume, tableName, err := parseTableName(pctx, pTokens, false)
+	if err != nil {
+		perr.Add(err)
+		return
+	}
+
+	tableName.TableName = tableName.Name
+
+	// 別名 (AS u / u)
+	for _, t := range pTokens[consume:] {
+		switch t.Val.Type {
+		case tok.WHITESPACE, tok.BLOCK_COMMENT, tok.LINE_COMMENT, tok.AS:
+			continue
+		case tok.IDENTIFIER:
+			if !tableName.ExplicitName {
+				tableName.Name = t.Val.Value
+				tableName.ExplicitName = true
+
+				continue
+			}
+		}
+
+		perr.Add(fmt.Errorf("%w at %s: unexpected '%s' in MERGE INTO clause", cmn.ErrInvalidSQL, t.Val.Position.String(), t.Val.Value))
+
+		return
+	}
+
+	clause.Table = tableName
+}
+
 // finalizeSetClause validates SetClause for UPDATE
 func finalizeSetClause(clause *cmn.SetClause, perr *cmn.ParseError) {
 	tokens := clause.ContentTokens()
@@ -140,6 +180,16 @@ func finalizeReturningClause(clause *cmn.ReturningClause, perr *cmn.ParseError)
 			field.FieldKind = cmn.SingleField
 			field.OriginalField = match[0].Val.Value
 			nameToPos[field.FieldName] = append(nameToPos[field.FieldName], field.Pos.String())
+		} else if _, match, err := tableField(pctx, fieldTokens); err == nil {
+			// t.field (MERGE INTO users AS u ... RETURNING u.id など)
+			if field.FieldName == "" {
+				field.FieldName = match[2].Val.Value
+			}
+
+			field.FieldKind = cmn.TableField
+			field.TableName = match[0].Val.Value
+			field.OriginalField = match[0].Val.Value + "." + match[2].Val.Value
+			nameToPos[field.FieldName] = append(nameToPos[field.FieldName], field.Pos.String())
 		} else {
 			// complex field
 			field.FieldKind = cmn.ComplexField
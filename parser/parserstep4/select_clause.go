@@ -125,12 +125,12 @@ func finalizeSelectClause(clause *cmn.SelectClause, perr *cmn.ParseError) {
 	//   8. expressions
 	//   9. literal values
 	//   10. JSON
-	for _, token := range pc.FindIter(pctx, cmn.WS2(cmn.Comma), pTokens) {
-		if len(token.Skipped) == 0 {
+	for _, item := range splitSelectItems(pTokens) {
+		if len(item) == 0 {
 			continue
 		}
 
-		field, fieldTokens := parseFieldQualifier(token.Skipped)
+		field, fieldTokens := parseFieldQualifier(item)
 
 		// Main Part
 		// JSON operator: it should be any type
@@ -189,8 +189,15 @@ func finalizeSelectClause(clause *cmn.SelectClause, perr *cmn.ParseError) {
 				}
 			}
 
+			window, err := parseWindowSpec(fieldTokens)
+			if err != nil {
+				perr.Add(err)
+				continue
+			}
+
 			field.FieldKind = cmn.FunctionField
-			field.Expression = cmn.ToToken(match)
+			field.Expression = cmn.ToToken(fieldTokens)
+			field.Window = window
 			clause.Fields = append(clause.Fields, field)
 		case "subquery": // sub query
 			field.FieldKind = cmn.ComplexField
@@ -270,7 +277,8 @@ func parseFieldQualifier(fieldTokens []pc.Token[tok.Token]) (cmn.SelectField, []
 		case "without-as": // it requires before the word
 			if len(beforeAlias) > 0 {
 				lastType := beforeAlias[len(beforeAlias)-1].Val.Type
-				if lastType != tok.DOUBLE_COLON && lastType != tok.DOT {
+				// OVER w は名前付きウィンドウの参照でエイリアスではない
+				if lastType != tok.DOUBLE_COLON && lastType != tok.DOT && !isKeyword(beforeAlias[len(beforeAlias)-1], "OVER") {
 					result.FieldName = match[0].Val.Value
 					fieldTokens = beforeAlias
 					result.ExplicitName = true
@@ -0,0 +1,246 @@
+package parserstep4
+
+import (
+	"fmt"
+	"strings"
+
+	pc "github.com/shibukawa/parsercombinator"
+	cmn "github.com/shibukawa/snapsql/parser/parsercommon"
+	tok "github.com/shibukawa/snapsql/tokenizer"
+)
+
+// splitSelectItems splits the SELECT list at the commas outside of parentheses.
+// Leading spaces and comments after each comma are dropped like cmn.WS2(cmn.Comma) does.
+func splitSelectItems(tokens []pc.Token[tok.Token]) [][]pc.Token[tok.Token] {
+	var (
+		items [][]pc.Token[tok.Token]
+		depth int
+		start int
+	)
+
+	skipSpaces := func(i int) int {
+		for i < len(tokens) && isSpaceOrComment(tokens[i].Val.Type) {
+			i++
+		}
+
+		return i
+	}
+
+	for i, t := range tokens {
+		switch t.Val.Type {
+		case tok.OPENED_PARENS:
+			depth++
+		case tok.CLOSED_PARENS:
+			depth--
+		case tok.COMMA:
+			if depth == 0 {
+				items = append(items, tokens[start:i])
+				start = skipSpaces(i + 1)
+			}
+		}
+	}
+
+	if start < len(tokens) {
+		items = append(items, tokens[start:])
+	}
+
+	return items
+}
+
+func isSpaceOrComment(t tok.TokenType) bool {
+	return t == tok.WHITESPACE || t == tok.BLOCK_COMMENT || t == tok.LINE_COMMENT
+}
+
+func isKeyword(t pc.Token[tok.Token], keyword string) bool {
+	return strings.EqualFold(t.Val.Value, keyword)
+}
+
+// parseWindowSpec parses the OVER clause of a function call field.
+//
+//	ROW_NUMBER() OVER (PARTITION BY dept ORDER BY salary DESC)
+//	SUM(amount) OVER w
+//
+// It returns nil if the field doesn't call a window function, or if the window function call is
+// a part of a larger expression.
+func parseWindowSpec(tokens []pc.Token[tok.Token]) (*cmn.WindowSpec, error) {
+	over := -1
+	depth := 0
+
+	for i, t := range tokens {
+		switch t.Val.Type {
+		case tok.OPENED_PARENS:
+			depth++
+		case tok.CLOSED_PARENS:
+			depth--
+		default:
+			if depth == 0 && isKeyword(t, "OVER") {
+				over = i
+			}
+		}
+
+		if over != -1 {
+			break
+		}
+	}
+
+	if over == -1 {
+		return nil, nil
+	}
+
+	pos := tokens[over].Val.Position
+
+	i := over + 1
+	for i < len(tokens) && isSpaceOrComment(tokens[i].Val.Type) {
+		i++
+	}
+
+	if i == len(tokens) {
+		return nil, fmt.Errorf("%w at %s: OVER requires a window name or a window specification", cmn.ErrInvalidSQL, pos.String())
+	}
+
+	var (
+		spec *cmn.WindowSpec
+		rest []pc.Token[tok.Token]
+	)
+
+	switch tokens[i].Val.Type {
+	case tok.IDENTIFIER:
+		spec = &cmn.WindowSpec{Name: tokens[i].Val.Value}
+		rest = tokens[i+1:]
+	case tok.OPENED_PARENS:
+		end := matchingParen(tokens, i)
+		if end == -1 {
+			return nil, fmt.Errorf("%w at %s: unclosed window specification", cmn.ErrInvalidSQL, pos.String())
+		}
+
+		var err error
+
+		spec, err = parseWindowBody(tokens[i+1:end], pos)
+		if err != nil {
+			return nil, err
+		}
+
+		rest = tokens[end+1:]
+	default:
+		return nil, fmt.Errorf("%w at %s: OVER requires a window name or a window specification", cmn.ErrInvalidSQL, pos.String())
+	}
+
+	for _, t := range rest {
+		if !isSpaceOrComment(t.Val.Type) {
+			// ROW_NUMBER() OVER (...) - 1 のような式の一部
+			return nil, nil
+		}
+	}
+
+	return spec, nil
+}
+
+// parseWindowBody splits "[name] [PARTITION BY ...] [ORDER BY ...] [frame]" of a window specification
+func parseWindowBody(tokens []pc.Token[tok.Token], pos tok.Position) (*cmn.WindowSpec, error) {
+	spec := &cmn.WindowSpec{}
+
+	var (
+		current *[]tok.Token
+		depth   int
+		first   = true
+	)
+
+	nextWord := func(i int) int {
+		for i < len(tokens) && isSpaceOrComment(tokens[i].Val.Type) {
+			i++
+		}
+
+		return i
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+
+		if isSpaceOrComment(t.Val.Type) {
+			if current != nil && len(*current) > 0 {
+				*current = append(*current, t.Val)
+			}
+
+			continue
+		}
+
+		if depth == 0 {
+			switch {
+			case isKeyword(t, "PARTITION") || t.Val.Type == tok.ORDER:
+				by := nextWord(i + 1)
+				if by >= len(tokens) || tokens[by].Val.Type != tok.BY {
+					return nil, fmt.Errorf("%w at %s: %s must be followed by BY in window specification", cmn.ErrInvalidSQL, pos.String(), strings.ToUpper(t.Val.Value))
+				}
+
+				if t.Val.Type == tok.ORDER {
+					current = &spec.OrderBy
+				} else {
+					current = &spec.PartitionBy
+				}
+
+				if len(*current) > 0 {
+					return nil, fmt.Errorf("%w at %s: duplicate %s BY in window specification", cmn.ErrInvalidSQL, pos.String(), strings.ToUpper(t.Val.Value))
+				}
+
+				i = by
+				first = false
+
+				continue
+			case isKeyword(t, "ROWS") || isKeyword(t, "RANGE") || isKeyword(t, "GROUPS"):
+				current = &spec.Frame
+			case first && t.Val.Type == tok.IDENTIFIER:
+				// OVER (w ORDER BY ...): 名前付きウィンドウの拡張
+				spec.Name = t.Val.Value
+				first = false
+
+				continue
+			case current == nil:
+				return nil, fmt.Errorf("%w at %s: unexpected '%s' in window specification", cmn.ErrInvalidSQL, pos.String(), t.Val.Value)
+			}
+		}
+
+		first = false
+
+		switch t.Val.Type {
+		case tok.OPENED_PARENS:
+			depth++
+		case tok.CLOSED_PARENS:
+			depth--
+		}
+
+		*current = append(*current, t.Val)
+	}
+
+	spec.PartitionBy = trimTrailingSpaces(spec.PartitionBy)
+	spec.OrderBy = trimTrailingSpaces(spec.OrderBy)
+	spec.Frame = trimTrailingSpaces(spec.Frame)
+
+	return spec, nil
+}
+
+func trimTrailingSpaces(tokens []tok.Token) []tok.Token {
+	for len(tokens) > 0 && isSpaceOrComment(tokens[len(tokens)-1].Type) {
+		tokens = tokens[:len(tokens)-1]
+	}
+
+	return tokens
+}
+
+// matchingParen returns the index of the parenthesis that closes tokens[open]
+func matchingParen(tokens []pc.Token[tok.Token], open int) int {
+	depth := 0
+
+	for i := open; i < len(tokens); i++ {
+		switch tokens[i].Val.Type {
+		case tok.OPENED_PARENS:
+			depth++
+		case tok.CLOSED_PARENS:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,116 @@
+package parserstep4
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	cmn "github.com/shibukawa/snapsql/parser/parsercommon"
+	"github.com/shibukawa/snapsql/parser/parserstep2"
+	"github.com/shibukawa/snapsql/parser/parserstep3"
+	"github.com/shibukawa/snapsql/tokenizer"
+)
+
+func tokensText(tokens []tokenizer.Token) string {
+	var b strings.Builder
+	for _, t := range tokens {
+		b.WriteString(t.Value)
+	}
+
+	return strings.TrimSpace(b.String())
+}
+
+func TestWindowFunctionFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		sql         string
+		wantError   bool
+		wantNames   []string
+		wantWindow  []bool
+		partitionBy string
+		orderBy     string
+		frame       string
+		windowName  string
+	}{
+		{
+			name:        "partition and order with commas",
+			sql:         "SELECT id, ROW_NUMBER() OVER (PARTITION BY dept, team ORDER BY salary DESC, id) AS rn FROM emp",
+			wantNames:   []string{"id", "rn"},
+			wantWindow:  []bool{false, true},
+			partitionBy: "dept, team",
+			orderBy:     "salary DESC, id",
+		},
+		{
+			name:       "function arguments with commas",
+			sql:        "SELECT LAG(salary, 1, 0) OVER (ORDER BY id) AS prev, COALESCE(a, b) AS c FROM emp",
+			wantNames:  []string{"prev", "c"},
+			wantWindow: []bool{true, false},
+			orderBy:    "id",
+		},
+		{
+			name:       "frame clause",
+			sql:        "SELECT SUM(amount) OVER (ORDER BY id ROWS BETWEEN 2 PRECEDING AND CURRENT ROW) AS moving FROM sales",
+			wantNames:  []string{"moving"},
+			wantWindow: []bool{true},
+			orderBy:    "id",
+			frame:      "ROWS BETWEEN 2 PRECEDING AND CURRENT ROW",
+		},
+		{
+			name:       "named window is not an alias",
+			sql:        "SELECT RANK() OVER w FROM emp",
+			wantNames:  []string{""},
+			wantWindow: []bool{true},
+			windowName: "w",
+		},
+		{
+			name:       "window function inside expression",
+			sql:        "SELECT ROW_NUMBER() OVER (ORDER BY id) - 1 AS idx FROM emp",
+			wantNames:  []string{"idx"},
+			wantWindow: []bool{false},
+		},
+		{
+			name:      "OVER without specification",
+			sql:       "SELECT ROW_NUMBER() OVER AS rn FROM emp",
+			wantError: true,
+		},
+		{
+			name:      "unknown keyword in specification",
+			sql:       "SELECT ROW_NUMBER() OVER (dept, id) AS rn FROM emp",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := tokenizer.Tokenize(tt.sql)
+			assert.NoError(t, err)
+			ast, err := parserstep2.Execute(tokens)
+			assert.NoError(t, err)
+			assert.NoError(t, parserstep3.Execute(ast))
+
+			selectClause := ast.(*cmn.SelectStatement).Select
+			perr := &cmn.ParseError{}
+			finalizeSelectClause(selectClause, perr)
+
+			if tt.wantError {
+				assert.True(t, len(perr.Errors) > 0, "should return error")
+				return
+			}
+
+			assert.Equal(t, 0, len(perr.Errors))
+			assert.Equal(t, len(tt.wantNames), len(selectClause.Fields))
+
+			for i, field := range selectClause.Fields {
+				assert.Equal(t, tt.wantNames[i], field.FieldName)
+				assert.Equal(t, tt.wantWindow[i], field.Window != nil)
+
+				if field.Window != nil {
+					assert.Equal(t, tt.windowName, field.Window.Name)
+					assert.Equal(t, tt.partitionBy, tokensText(field.Window.PartitionBy))
+					assert.Equal(t, tt.orderBy, tokensText(field.Window.OrderBy))
+					assert.Equal(t, tt.frame, tokensText(field.Window.Frame))
+				}
+			}
+		})
+	}
+}
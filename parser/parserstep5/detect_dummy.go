@@ -45,7 +45,7 @@ func detectDummyRangesInTokens(tokens []tokenizer.Token) {
 // isDummyableDirective checks if the directive type can have dummy elements
 func isDummyableDirective(directiveType string) bool {
 	switch directiveType {
-	case "variable", "const", "end":
+	case "variable", "const", "ident", "orderby", "end":
 		return true
 	default:
 		return false
@@ -0,0 +1,138 @@
+package parserstep6
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/shibukawa/snapsql/langs/snapsqlgo"
+	cmn "github.com/shibukawa/snapsql/parser/parsercommon"
+	"github.com/shibukawa/snapsql/tokenizer"
+)
+
+var (
+	// allowedIdentifierPattern は ident ディレクティブの許可リストに書ける識別子（table または table.column）
+	allowedIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+	// allowedOrderByPattern は orderby ディレクティブの許可リストに書ける項目（識別子と任意の ASC/DESC）
+	allowedOrderByPattern = regexp.MustCompile(`(?i)^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?(\s+(ASC|DESC))?$`)
+)
+
+// validateIdentDirective validates an ident or orderby directive and returns the end index (exclusive)
+// of its dummy identifier (for orderby, including the optional ASC/DESC).
+//
+//	FROM /*# ident table_name in [users, archived_users] */users
+//	ORDER BY /*# orderby sort in [name, created_at desc] */name
+func validateIdentDirective(tokens []tokenizer.Token, index int, paramNs *cmn.Namespace, perr *cmn.ParseError) (int, bool) {
+	token := tokens[index]
+	directive := token.Directive
+	kind := directive.Type
+
+	expression := strings.TrimSpace(directive.Condition)
+	if expression == "" {
+		perr.Add(fmt.Errorf("%w at %s: %s directive missing expression", cmn.ErrInvalidForSnapSQL, token.Position.String(), kind))
+		return 0, false
+	}
+
+	ok := true
+
+	// 許可リストのない識別子は SQL インジェクションの入口になるため必須とする
+	switch {
+	case directive.Allowed == nil:
+		perr.Add(fmt.Errorf("%w at %s: %s directive requires an allowlist such as /*# %s %s in [id, name] */", cmn.ErrInvalidForSnapSQL, token.Position.String(), kind, kind, expression))
+		ok = false
+	case len(directive.Allowed) == 0:
+		perr.Add(fmt.Errorf("%w at %s: %s directive allowlist is empty", cmn.ErrInvalidForSnapSQL, token.Position.String(), kind))
+		ok = false
+	}
+
+	pattern := allowedIdentifierPattern
+	if kind == "orderby" {
+		pattern = allowedOrderByPattern
+	}
+
+	for i, item := range directive.Allowed {
+		if !pattern.MatchString(item) {
+			perr.Add(fmt.Errorf("%w at %s: '%s' in %s directive allowlist is not a plain identifier", cmn.ErrInvalidForSnapSQL, token.Position.String(), item, kind))
+			ok = false
+
+			continue
+		}
+
+		// 実行時の比較は正規化した形（"created_at DESC"、ASC は省略）で行う
+		if kind == "orderby" {
+			directive.Allowed[i] = snapsqlgo.NormalizeOrderBy(item)
+		}
+	}
+
+	parsed, parsedOK := parseAndValidateExpression(expression, token, paramNs, perr)
+	if parsedOK {
+		value, err := evaluateParsedExpression(parsed, paramNs)
+		if err != nil {
+			perr.Add(fmt.Errorf("%w at %s: %w", cmn.ErrInvalidForSnapSQL, token.Position.String(), err))
+			ok = false
+		} else if _, isString := value.(string); !isString {
+			perr.Add(fmt.Errorf("%w at %s: %s expression '%s' must evaluate to string", cmn.ErrInvalidForSnapSQL, token.Position.String(), kind, expression))
+			ok = false
+		}
+	} else {
+		ok = false
+	}
+
+	end := dummyIdentifierEnd(tokens, index)
+	if end < 0 {
+		perr.Add(fmt.Errorf("%w at %s: %s directive must be followed by a dummy identifier without whitespace", cmn.ErrInvalidForSnapSQL, token.Position.String(), kind))
+		return 0, false
+	}
+
+	if kind == "orderby" {
+		end = dummyDirectionEnd(tokens, end)
+	}
+
+	return end, ok
+}
+
+// dummyIdentifierEnd returns the end index (exclusive) of the identifier ("name" or "u.name")
+// written right after the directive, or -1 when there is none
+func dummyIdentifierEnd(tokens []tokenizer.Token, index int) int {
+	directive := tokens[index]
+
+	next := index + 1
+	if next >= len(tokens) || tokens[next].Type != tokenizer.IDENTIFIER {
+		return -1
+	}
+
+	if directive.Position.Offset+len(directive.Value) != tokens[next].Position.Offset {
+		return -1
+	}
+
+	end := next + 1
+	for end+1 < len(tokens) && tokens[end].Type == tokenizer.DOT && tokens[end+1].Type == tokenizer.IDENTIFIER {
+		end += 2
+	}
+
+	return end
+}
+
+// dummyDirectionEnd extends the dummy of an orderby directive over a following ASC/DESC
+func dummyDirectionEnd(tokens []tokenizer.Token, end int) int {
+	next := end
+	for next < len(tokens) && tokens[next].Type == tokenizer.WHITESPACE {
+		next++
+	}
+
+	if next < len(tokens) && (tokens[next].Type == tokenizer.ASC || tokens[next].Type == tokenizer.DESC) {
+		return next + 1
+	}
+
+	return end
+}
+
+// wrapDummyIdentifier は ident / orderby ディレクティブのダミー識別子を DUMMY_START/DUMMY_END で囲む
+func wrapDummyIdentifier(tokens []tokenizer.Token, pos tokenizer.Position) []tokenizer.Token {
+	result := make([]tokenizer.Token, 0, len(tokens)+2)
+	result = append(result, tokenizer.Token{Type: tokenizer.DUMMY_START, Value: "DUMMY_START", Position: pos})
+	result = append(result, tokens...)
+	result = append(result, tokenizer.Token{Type: tokenizer.DUMMY_END, Value: "DUMMY_END", Position: pos})
+
+	return result
+}
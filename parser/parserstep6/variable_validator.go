@@ -71,7 +71,13 @@ func validateVariables(statement cmn.StatementNode, paramNs *cmn.Namespace, cons
 					ok        bool
 				)
 
-				if token.Directive.Type == "variable" {
+				if token.Directive.Type == "variable" && isConstantExpression(token, paramNs, constNs) {
+					// 定数だけを参照する式は生成時に評価し、const ディレクティブと同じくリテラルとして埋め込む
+					value, valueType, ok = validateConstDirective(token, constNs, perr)
+					if ok {
+						token.Directive.Type = "const"
+					}
+				} else if token.Directive.Type == "variable" {
 					value, valueType, ok = validateVariableDirective(token, paramNs, perr)
 				} else {
 					value, valueType, ok = validateConstDirective(token, constNs, perr)
@@ -96,6 +102,20 @@ func validateVariables(statement cmn.StatementNode, paramNs *cmn.Namespace, cons
 						})
 					}
 				}
+			case "ident", "orderby":
+				end, ok := validateIdentDirective(tokens, i, paramNs, perr)
+				if !ok {
+					continue
+				}
+
+				setTypeInfo(typeInfo, token.Position, "string")
+
+				// ダミーの識別子は構文解析と型推論のために残し、コード生成では読み飛ばす
+				replacements = append(replacements, tokenReplacement{
+					startIndex: i + 1,
+					endIndex:   end,
+					tokens:     wrapDummyIdentifier(slices.Clone(tokens[i+1:end]), token.Position),
+				})
 			case "if":
 				if validateIfDirective(token, paramNs, constNs, perr) {
 					setTypeInfo(typeInfo, token.Position, "bool")
@@ -483,12 +503,12 @@ func validateVariableDirective(token tokenizer.Token, paramNs *cmn.Namespace, pe
 		return "SYSTEM_VALUE_" + strings.TrimSpace(expression), "string", true
 	}
 
-	steps, ok := parseAndValidateExpression(expression, token, paramNs, perr)
+	parsed, ok := parseAndValidateExpression(expression, token, paramNs, perr)
 	if !ok {
 		return nil, "", false
 	}
 
-	value, err := evaluateStepsWithValues(steps, paramNs.CurrentValues())
+	value, err := evaluateParsedExpression(parsed, paramNs)
 	if err != nil {
 		perr.Add(fmt.Errorf("%w at %s: %s", cmn.ErrInvalidForSnapSQL, token.Position.String(), err.Error()))
 		return nil, "", false
@@ -502,9 +522,43 @@ func validateVariableDirective(token tokenizer.Token, paramNs *cmn.Namespace, pe
 	return value, valueType, true
 }
 
-// validateConstDirective validates a const directive
+// isConstantExpression reports whether a variable directive refers only to a constant.
+// Parameters and loop variables shadow constants of the same name.
+func isConstantExpression(token tokenizer.Token, paramNs *cmn.Namespace, constNs *cmn.Namespace) bool {
+	if constNs == nil || len(constNs.CurrentValues()) == 0 {
+		return false
+	}
+
+	expression := strings.TrimSpace(extractExpressionFromDirective(token.Value, "/*=", "*/"))
+
+	steps, call, err := explang.ParseExpression(expression, 1, 1)
+	if err != nil || call != nil || len(steps) == 0 {
+		return false
+	}
+
+	root := steps[0].Identifier
+
+	if _, ok := paramNs.CurrentValues()[root]; ok {
+		return false
+	}
+
+	if _, ok := paramNs.ParameterSchema()[root]; ok {
+		return false
+	}
+
+	_, ok := constNs.CurrentValues()[root]
+
+	return ok
+}
+
+// validateConstDirective validates a const directive, or a variable directive folded into a constant
 func validateConstDirective(token tokenizer.Token, constNs *cmn.Namespace, perr *cmn.ParseError) (any, string, bool) {
-	expression := extractExpressionFromDirective(token.Value, "/*$", "*/")
+	prefix := "/*$"
+	if strings.HasPrefix(token.Value, "/*=") {
+		prefix = "/*="
+	}
+
+	expression := extractExpressionFromDirective(token.Value, prefix, "*/")
 	if expression == "" {
 		perr.Add(fmt.Errorf("%w at %s: invalid const directive format", cmn.ErrInvalidForSnapSQL, token.Position.String()))
 		return nil, "", false
@@ -535,12 +589,12 @@ func validateConditionalDirective(token tokenizer.Token, clause string, paramNs
 		return false
 	}
 
-	steps, ok := parseAndValidateExpression(condition, token, paramNs, perr)
+	parsed, ok := parseAndValidateExpression(condition, token, paramNs, perr)
 	if !ok {
 		return false
 	}
 
-	value, err := evaluateStepsWithValues(steps, paramNs.CurrentValues())
+	value, err := evaluateParsedExpression(parsed, paramNs)
 	if err != nil {
 		perr.Add(fmt.Errorf("%w at %s: %w", cmn.ErrInvalidForSnapSQL, token.Position.String(), err))
 		return false
@@ -568,12 +622,12 @@ func processForLoop(token tokenizer.Token, paramNs *cmn.Namespace, constNs *cmn.
 	itemName := strings.TrimSpace(parts[0])
 	itemsExpr := strings.TrimSpace(parts[1])
 
-	steps, ok := parseAndValidateExpression(itemsExpr, token, paramNs, perr)
+	parsed, ok := parseAndValidateExpression(itemsExpr, token, paramNs, perr)
 	if !ok {
 		return false, nil
 	}
 
-	itemsValue, err := evaluateStepsWithValues(steps, paramNs.CurrentValues())
+	itemsValue, err := evaluateParsedExpression(parsed, paramNs)
 	if err != nil {
 		perr.Add(fmt.Errorf("%w at %s: %s", cmn.ErrInvalidForSnapSQL, token.Position.String(), err.Error()))
 		return false, nil
@@ -635,6 +689,29 @@ func expressionStartPosition(token tokenizer.Token, expr string) (int, int) {
 	return line, column
 }
 
+// parsedExpression is either an access path or a call of a template function.
+type parsedExpression struct {
+	steps []explang.Step
+	call  *explang.Call
+}
+
+// evaluateParsedExpression returns the dummy value of the expression. A template function
+// call evaluates to the placeholder value of the function's declared return type.
+func evaluateParsedExpression(expr parsedExpression, paramNs *cmn.Namespace) (any, error) {
+	if expr.call == nil {
+		return evaluateStepsWithValues(expr.steps, paramNs.CurrentValues())
+	}
+
+	fn := paramNs.Functions()[expr.call.Function]
+
+	placeholder, err := cmn.GeneratePlaceholderData(map[string]any{"result": fn.Returns})
+	if err != nil {
+		return nil, err
+	}
+
+	return placeholder["result"], nil
+}
+
 func evaluateStepsWithValues(steps []explang.Step, values map[string]any) (any, error) {
 	if len(steps) == 0 {
 		return nil, fmt.Errorf("%w: empty explang expression", cmn.ErrInvalidForSnapSQL)
@@ -785,19 +862,19 @@ func buildDefinitionFromValue(val any) any {
 	}
 }
 
-func parseAndValidateExpression(expr string, token tokenizer.Token, paramNs *cmn.Namespace, perr *cmn.ParseError) ([]explang.Step, bool) {
+func parseAndValidateExpression(expr string, token tokenizer.Token, paramNs *cmn.Namespace, perr *cmn.ParseError) (parsedExpression, bool) {
 	expr = strings.TrimSpace(expr)
 	if expr == "" {
 		perr.Add(fmt.Errorf("%w at %s: empty expression", cmn.ErrInvalidForSnapSQL, token.Position.String()))
-		return nil, false
+		return parsedExpression{}, false
 	}
 
 	line, column := expressionStartPosition(token, expr)
 
-	steps, err := explang.ParseSteps(expr, line, column)
+	steps, call, err := explang.ParseExpression(expr, line, column)
 	if err != nil {
 		perr.Add(fmt.Errorf("%w: %w", cmn.ErrInvalidForSnapSQL, err))
-		return nil, false
+		return parsedExpression{}, false
 	}
 
 	var schema map[string]any
@@ -814,16 +891,22 @@ func parseAndValidateExpression(expr string, token tokenizer.Token, paramNs *cmn
 		validatorOpts = &explang.ValidatorOptions{AdditionalRoots: extraRoots}
 	}
 
-	valErrs := explang.ValidateStepsAgainstParameters(steps, schema, validatorOpts)
+	var valErrs []explang.ValidationError
+	if call != nil {
+		valErrs = explang.ValidateCall(call, paramNs.Functions(), schema, validatorOpts)
+	} else {
+		valErrs = explang.ValidateStepsAgainstParameters(steps, schema, validatorOpts)
+	}
+
 	if len(valErrs) > 0 {
 		for _, ve := range valErrs {
 			perr.Add(fmt.Errorf("%w at line %d column %d: %s", cmn.ErrInvalidForSnapSQL, ve.Step.Pos.Line, ve.Step.Pos.Column, ve.Message))
 		}
 
-		return nil, false
+		return parsedExpression{}, false
 	}
 
-	return steps, true
+	return parsedExpression{steps: steps, call: call}, true
 }
 
 func appendPathSegment(base, property string) string {
@@ -58,6 +58,97 @@ SELECT id, name FROM users LIMIT /*= limit */10`,
 			environment:    map[string]any{},
 			expectedErrors: 0,
 		},
+		{
+			name: "Ident directive with allowlist",
+			sql: `
+/*#
+name: sortedUsers
+function_name: sorted_users
+parameters:
+  sort: string
+*/
+SELECT id, name FROM users ORDER BY /*# ident sort in [name, u.created_at] */name`,
+			environment:    map[string]any{},
+			expectedErrors: 0,
+		},
+		{
+			name: "Ident directive without allowlist",
+			sql: `
+/*#
+name: sortedUsers
+function_name: sorted_users
+parameters:
+  sort: string
+*/
+SELECT id, name FROM users ORDER BY /*# ident sort */name`,
+			environment:    map[string]any{},
+			expectedErrors: 1,
+		},
+		{
+			name: "Ident directive with invalid allowlist entry",
+			sql: `
+/*#
+name: sortedUsers
+function_name: sorted_users
+parameters:
+  sort: string
+*/
+SELECT id, name FROM users ORDER BY /*# ident sort in [name, "name desc"] */name`,
+			environment:    map[string]any{},
+			expectedErrors: 1,
+		},
+		{
+			name: "Ident directive without dummy identifier",
+			sql: `
+/*#
+name: sortedUsers
+function_name: sorted_users
+parameters:
+  sort: string
+*/
+SELECT id, name FROM users ORDER BY /*# ident sort in [name] */ name`,
+			environment:    map[string]any{},
+			expectedErrors: 1,
+		},
+		{
+			name: "Ident directive with non-string expression",
+			sql: `
+/*#
+name: sortedUsers
+function_name: sorted_users
+parameters:
+  sort: int
+*/
+SELECT id, name FROM users ORDER BY /*# ident sort in [name] */name`,
+			environment:    map[string]any{},
+			expectedErrors: 1,
+		},
+		{
+			name: "Orderby directive with directions",
+			sql: `
+/*#
+name: sortedUsers
+function_name: sorted_users
+parameters:
+  sort: string
+*/
+SELECT id, name FROM users ORDER BY /*# orderby sort in [name, "created_at desc", u.id asc] */created_at DESC, id`,
+			environment:    map[string]any{},
+			expectedErrors: 0,
+		},
+		{
+			name: "Orderby directive with invalid direction",
+			sql: `
+/*#
+name: sortedUsers
+function_name: sorted_users
+parameters:
+  sort: string
+*/
+SELECT id, name FROM users ORDER BY /*# orderby sort in [name, "name; DROP TABLE users"] */name`,
+			environment:    map[string]any{},
+			expectedErrors: 1,
+		},
 	}
 
 	for _, tt := range tests {
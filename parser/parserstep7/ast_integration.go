@@ -89,6 +89,11 @@ func (ai *ASTIntegrator) extractCTEDependencies(cte *cmn.WithClause, stmt cmn.St
 	for _, cteDef := range cte.CTEs {
 		cteID := cteDef.Name // Use CTE name directly without prefix/suffix
 
+		// WITH RECURSIVE の CTE は自身を参照できるので、本体の解析前に CTE として登録する
+		if cte.Recursive {
+			processedCTEs[cteDef.Name] = struct{}{}
+		}
+
 		// Parse CTE's raw tokens to get SelectStatement
 		var cteStmt cmn.StatementNode
 
@@ -132,7 +137,7 @@ func (ai *ASTIntegrator) extractCTEDependencies(cte *cmn.WithClause, stmt cmn.St
 		derivedTable := cmn.DerivedTableInfo{
 			Name:             cteDef.Name,
 			SourceType:       "cte",
-			SelectFields:     selectFields,
+			SelectFields:     renameCTEColumns(selectFields, cteDef.Columns),
 			ReferencedTables: referencedTables,
 		}
 		ai.parser.derivedTables = append(ai.parser.derivedTables, derivedTable)
@@ -173,7 +178,7 @@ func (ai *ASTIntegrator) extractCTEDependencies(cte *cmn.WithClause, stmt cmn.St
 					continue
 				}
 
-				if _, isProcessedCTE := processedCTEs[refName]; isProcessedCTE {
+				if _, isProcessedCTE := processedCTEs[refName]; isProcessedCTE && refName != cteID {
 					// Add dependency: referencedCTE -> currentCTE (referenced first)
 					ai.parser.dependencies.AddDependency(refName, cteID)
 				}
@@ -266,6 +271,7 @@ func (ai *ASTIntegrator) extractFromClauseSubqueries(stmt cmn.StatementNode) err
 			if subqueryStmt != nil {
 				subqueryNode := &cmn.SQDependencyNode{
 					ID:        subqueryID,
+					Alias:     table.Name,
 					Statement: subqueryStmt,
 					NodeType:  cmn.SQDependencyFromSubquery,
 				}
@@ -332,6 +338,25 @@ func (ai *ASTIntegrator) buildNodeFieldSources(node *cmn.SQDependencyNode) error
 	return nil
 }
 
+// renameCTEColumns applies the column list of "name (col1, col2) AS (...)" to the SELECT fields of the CTE
+func renameCTEColumns(fields []cmn.SelectField, columns []string) []cmn.SelectField {
+	if len(columns) == 0 {
+		return fields
+	}
+
+	renamed := make([]cmn.SelectField, len(fields))
+	copy(renamed, fields)
+
+	for i := range renamed {
+		if i < len(columns) {
+			renamed[i].FieldName = columns[i]
+			renamed[i].ExplicitName = true
+		}
+	}
+
+	return renamed
+}
+
 // extractTableRefsFromStatementWithCTEs extracts table references with context of already-defined CTEs
 func extractTableRefsFromStatementWithCTEs(stmt cmn.StatementNode, processedCTEs map[string]struct{}) []*cmn.SQTableReference {
 	var refs []*cmn.SQTableReference
@@ -363,6 +388,10 @@ func extractTableRefsFromStatement(stmt cmn.StatementNode) []*cmn.SQTableReferen
 	switch s := stmt.(type) {
 	case *cmn.SelectStatement:
 		refs = append(refs, extractFromClauseTablesWithCTE(s.CTE(), s.From)...)
+		// Branches of UNION / INTERSECT / EXCEPT share the WITH clause of the first branch
+		for _, op := range s.SetOperations {
+			refs = append(refs, extractFromClauseTablesWithCTE(s.CTE(), op.From)...)
+		}
 	case *cmn.InsertIntoStatement:
 		if s.Into != nil {
 			tr := &cmn.SQTableReference{
@@ -398,11 +427,66 @@ func extractTableRefsFromStatement(stmt cmn.StatementNode) []*cmn.SQTableReferen
 			}
 			refs = append(refs, tr)
 		}
+	case *cmn.MergeStatement:
+		if s.Into != nil {
+			tr := &cmn.SQTableReference{
+				Name:     nameOrAlias(s.Into.Table),
+				RealName: realName(s.Into.Table),
+				Schema:   s.Into.Table.SchemaName,
+				Join:     cmn.JoinNone,
+				Context:  cmn.SQTableContextMain,
+			}
+			refs = append(refs, tr)
+		}
+
+		if tr := mergeSourceTable(s.Using); tr != nil {
+			refs = append(refs, tr)
+		}
 	}
 
 	return refs
 }
 
+// mergeSourceTable returns the table of "USING [schema.]table [AS alias] ON ...". A subquery
+// source returns nil.
+func mergeSourceTable(using *cmn.UsingClause) *cmn.SQTableReference {
+	if using == nil {
+		return nil
+	}
+
+	var (
+		tr       *cmn.SQTableReference
+		afterDot bool
+	)
+
+loop:
+	for _, t := range using.ContentTokens() {
+		switch t.Type {
+		case tokenizer.WHITESPACE, tokenizer.BLOCK_COMMENT, tokenizer.LINE_COMMENT, tokenizer.AS:
+		case tokenizer.DOT:
+			afterDot = true
+		case tokenizer.IDENTIFIER:
+			switch {
+			case tr == nil:
+				tr = &cmn.SQTableReference{Name: t.Value, RealName: t.Value, Join: cmn.JoinInner, Context: cmn.SQTableContextJoin}
+			case afterDot:
+				tr.Schema = tr.RealName
+				tr.Name = t.Value
+				tr.RealName = t.Value
+				afterDot = false
+			default:
+				tr.Name = t.Value
+
+				break loop
+			}
+		default:
+			break loop
+		}
+	}
+
+	return tr
+}
+
 func extractFromClauseTablesWithCTE(with *cmn.WithClause, from *cmn.FromClause) []*cmn.SQTableReference {
 	if from == nil {
 		return nil
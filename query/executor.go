@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"maps"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,7 +17,9 @@ import (
 	"github.com/shibukawa/snapsql"
 	"github.com/shibukawa/snapsql/intermediate"
 	"github.com/shibukawa/snapsql/intermediate/codegenerator"
+	"github.com/shibukawa/snapsql/langs/snapsqlgo"
 	"github.com/shibukawa/snapsql/markdownparser"
+	"github.com/shibukawa/snapsql/tokenizer"
 )
 
 // Error definitions
@@ -115,7 +119,7 @@ func (e *Executor) ExecuteWithTemplate(ctx context.Context, templateFile string,
 		sqlText, readErr := readOriginalSQL(templateFile)
 		if readErr == nil && sqlText != "" {
 			// Dangerous query check
-			sqlText = addLimitOffsetIfNeeded(sqlText, options)
+			sqlText = addLimitOffsetIfNeeded(sqlText, format, options)
 
 			sqlText = FormatSQLForDriver(sqlText, options.Driver)
 			if IsDangerousQuery(sqlText) && !options.ExecuteDangerousQuery {
@@ -162,10 +166,10 @@ func IsDangerousQuery(sql string) bool {
 	return false
 }
 
-// isWriteWithoutReturning detects INSERT/UPDATE/DELETE without RETURNING clause
+// isWriteWithoutReturning detects INSERT/UPDATE/DELETE/MERGE without RETURNING clause
 func isWriteWithoutReturning(sql string) bool {
 	s := strings.ToUpper(strings.TrimSpace(sql))
-	if strings.HasPrefix(s, "INSERT") || strings.HasPrefix(s, "UPDATE") || strings.HasPrefix(s, "DELETE") {
+	if strings.HasPrefix(s, "INSERT") || strings.HasPrefix(s, "UPDATE") || strings.HasPrefix(s, "DELETE") || strings.HasPrefix(s, "MERGE") {
 		// crude check: no RETURNING keyword
 		return !strings.Contains(s, " RETURNING ") && !strings.HasSuffix(s, " RETURNING")
 	}
@@ -173,6 +177,9 @@ func isWriteWithoutReturning(sql string) bool {
 	return false
 }
 
+// executorEnvSignature identifies the environment of buildSQLFromOptimized in CEL program cache keys
+const executorEnvSignature = "query.Executor:"
+
 // buildSQLFromOptimized builds SQL from optimized instructions
 func (e *Executor) buildSQLFromOptimized(instructions []codegenerator.OptimizedInstruction, format *intermediate.IntermediateFormat, params map[string]any) (string, []any, error) {
 	var (
@@ -190,26 +197,29 @@ func (e *Executor) buildSQLFromOptimized(instructions []codegenerator.OptimizedI
 	// Create CEL programs for expressions
 	celPrograms := make(map[int]*cel.Program)
 
-	// Declare variables for CEL: params map + individual keys
-	decls := []cel.EnvOption{cel.Variable("params", cel.MapType(cel.StringType, cel.AnyType))}
-	for k := range paramMap {
-		decls = append(decls, cel.Variable(k, cel.AnyType))
-	}
+	// Declare variables for CEL: params map + individual keys. Programs are cached per
+	// expression and set of parameter names, so repeated executions skip compilation.
+	names := slices.Sorted(maps.Keys(paramMap))
+	newEnv := func() (*cel.Env, error) {
+		decls := []cel.EnvOption{cel.Variable("params", cel.MapType(cel.StringType, cel.AnyType))}
+		for _, name := range names {
+			decls = append(decls, cel.Variable(name, cel.AnyType))
+		}
 
-	env, err := cel.NewEnv(decls...)
-	if err != nil {
-		return "", nil, fmt.Errorf("failed to create CEL environment: %w", err)
+		env, err := cel.NewEnv(decls...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+		}
+
+		return env, nil
 	}
 
-	for i, expr := range format.CELExpressions {
-		ast, issues := env.Compile(expr.Expression)
-		if issues.Err() != nil {
-			return "", nil, fmt.Errorf("failed to compile expression %d (%s): %w", i, expr.Expression, issues.Err())
-		}
+	signature := executorEnvSignature + strings.Join(names, ",")
 
-		program, err := env.Program(ast)
+	for i, expr := range format.CELExpressions {
+		program, err := snapsqlgo.CompileCEL(signature, expr.Expression, newEnv)
 		if err != nil {
-			return "", nil, fmt.Errorf("failed to create program for expression %d: %w", i, err)
+			return "", nil, fmt.Errorf("expression %d: %w", i, err)
 		}
 
 		celPrograms[i] = &program
@@ -259,13 +269,19 @@ func (e *Executor) buildSQLFromOptimized(instructions []codegenerator.OptimizedI
 				}
 			}
 
-		case "ADD_PARAM":
+		case "ADD_PARAM", codegenerator.OpEmitCursorLimit, codegenerator.OpEmitSystemLimit:
+			if inst.Op == codegenerator.OpEmitCursorLimit && inst.ExprIndex == nil {
+				// Literal page size of cursor pagination
+				builder.WriteString(inst.Value)
+				break
+			}
+
 			if inst.ExprIndex != nil {
 				// Fast path: direct param lookup by expression string
 				if *inst.ExprIndex >= 0 && *inst.ExprIndex < len(format.CELExpressions) {
 					exprStr := format.CELExpressions[*inst.ExprIndex].Expression
 					if v, ok := paramMap[exprStr]; ok {
-						args = append(args, v)
+						args = append(args, SystemLimitArg(inst, v))
 						break
 					}
 				}
@@ -283,9 +299,33 @@ func (e *Executor) buildSQLFromOptimized(instructions []codegenerator.OptimizedI
 					return "", nil, fmt.Errorf("failed to evaluate expression %d: %w", *inst.ExprIndex, err)
 				}
 
-				args = append(args, result.Value())
+				args = append(args, SystemLimitArg(inst, result.Value()))
+			}
+
+		case codegenerator.OpEmitIdent, codegenerator.OpEmitOrderBy:
+			program, exists := celPrograms[*inst.ExprIndex]
+			if !exists {
+				return "", nil, fmt.Errorf("%w: %d", snapsql.ErrExpressionIndexNotFound, *inst.ExprIndex)
 			}
 
+			evalParams := map[string]any{"params": paramMap}
+			maps.Copy(evalParams, paramMap)
+
+			result, _, err := (*program).Eval(evalParams)
+			if err != nil {
+				return "", nil, fmt.Errorf("failed to evaluate expression %d: %w", *inst.ExprIndex, err)
+			}
+
+			ident, err := AllowedItem(inst.Op, result.Value(), inst.Allowed)
+			if err != nil {
+				return "", nil, err
+			}
+
+			flushDeferred()
+			builder.WriteString(ident)
+
+			hasContentSinceBd = true
+
 		case "EMIT_UNLESS_BOUNDARY":
 			// Defer emission until we know content appears before the next boundary
 			if inst.Value != "" {
@@ -303,6 +343,12 @@ func (e *Executor) buildSQLFromOptimized(instructions []codegenerator.OptimizedI
 			// Reset for next region
 			hasContentSinceBd = false
 
+		case codegenerator.OpEmitSystemSoftDelete:
+			builder.WriteString(" " + inst.Value)
+
+		case codegenerator.OpEmitCursorCondition:
+			// Queries run from the CLI always read the first page
+
 		default:
 			// Ignore other control flow ops here (IF/ELSE/END/LOOP_* are resolved at optimization or not supported yet)
 		}
@@ -395,6 +441,10 @@ func getDialectFromDriver(driver string) snapsql.Dialect {
 		return snapsql.DialectMySQL
 	case "sqlite3":
 		return snapsql.DialectSQLite
+	case "duckdb":
+		return snapsql.DialectDuckDB
+	case "clickhouse":
+		return snapsql.DialectClickHouse
 	default:
 		return snapsql.DialectPostgres // default
 	}
@@ -417,7 +467,7 @@ func (e *Executor) Execute(ctx context.Context, format *intermediate.Intermediat
 	}
 
 	// Apply optional LIMIT/OFFSET for SELECT when not present in SQL
-	sql = addLimitOffsetIfNeeded(sql, options)
+	sql = addLimitOffsetIfNeeded(sql, format, options)
 	// Convert placeholders and ensure readability (shared logic)
 	sql = FormatSQLForDriver(sql, options.Driver)
 
@@ -577,41 +627,133 @@ func (e *Executor) executeSQL(ctx context.Context, sql string, args []any, optio
 	return result, nil
 }
 
-// addLimitOffsetIfNeeded appends LIMIT/OFFSET to SELECT when not present
-func addLimitOffsetIfNeeded(sql string, options QueryOptions) string {
+// addLimitOffsetIfNeeded appends LIMIT/OFFSET to SELECT when not present.
+// The statement type comes from the parsed template, so SELECTs starting with a WITH clause are covered too.
+func addLimitOffsetIfNeeded(sql string, format *intermediate.IntermediateFormat, options QueryOptions) string {
 	if options.Limit <= 0 && options.Offset <= 0 {
 		return sql
 	}
 
-	s := strings.TrimSpace(sql)
-
-	upper := strings.ToUpper(s)
-	if !strings.HasPrefix(upper, "SELECT") {
+	if format == nil || format.StatementType != "select" {
 		return sql
 	}
-	// Skip if already contains LIMIT or OFFSET (naive check)
-	if strings.Contains(upper, " LIMIT ") || strings.Contains(upper, " OFFSET ") {
+
+	pos, ok := limitInsertionPoint(sql)
+	if !ok {
 		return sql
 	}
-	// Handle trailing semicolon
-	hasSemi := strings.HasSuffix(s, ";")
-	if hasSemi {
-		s = strings.TrimSuffix(s, ";")
-	}
 
+	// The clauses start on a new line so that a trailing -- comment cannot swallow them
+	var clauses strings.Builder
 	if options.Limit > 0 {
-		s = s + fmt.Sprintf(" LIMIT %d", options.Limit)
+		fmt.Fprintf(&clauses, "\nLIMIT %d", options.Limit)
 	}
 
 	if options.Offset > 0 {
-		s = s + fmt.Sprintf(" OFFSET %d", options.Offset)
+		fmt.Fprintf(&clauses, "\nOFFSET %d", options.Offset)
 	}
 
-	if hasSemi {
-		s += ";"
+	return sql[:pos] + clauses.String() + sql[pos:]
+}
+
+// limitInsertionPoint returns the byte offset where LIMIT/OFFSET is added to sql: right after its last
+// token that is not whitespace, a comment or the terminating semicolon. ok is false when the outer query
+// already has LIMIT or OFFSET (clauses inside CTEs, subqueries, literals and comments do not count) or
+// when sql cannot be tokenized.
+func limitInsertionPoint(sql string) (pos int, ok bool) {
+	tokens, err := tokenizer.Tokenize(sql)
+	if err != nil {
+		return 0, false
+	}
+
+	depth := 0
+	last := -1
+
+	for i, token := range tokens {
+		switch token.Type {
+		case tokenizer.WHITESPACE, tokenizer.LINE_COMMENT, tokenizer.BLOCK_COMMENT, tokenizer.SEMICOLON, tokenizer.EOF:
+			continue
+		case tokenizer.OPENED_PARENS:
+			depth++
+		case tokenizer.CLOSED_PARENS:
+			depth--
+		case tokenizer.LIMIT, tokenizer.OFFSET:
+			if depth == 0 {
+				return 0, false
+			}
+		}
+
+		last = i
+	}
+
+	if last < 0 {
+		return 0, false
 	}
 
-	return s
+	return min(tokens[last+1].Position.Offset, len(sql)), true
+}
+
+// HasLimitClause reports whether the template already bounds its result set with LIMIT.
+// Both literal LIMIT clauses and system LIMIT instructions are taken into account. The
+// IF_SYSTEM_LIMIT / IF_SYSTEM_OFFSET blocks every SELECT carries only apply when a system
+// value is passed at runtime, so their LIMIT does not count unless it is capped by snapsql.yaml (limits).
+func HasLimitClause(format *intermediate.IntermediateFormat) bool {
+	if format == nil {
+		return false
+	}
+
+	// depth of the system block whose then-branch is being skipped (0: not skipping)
+	skipDepth := 0
+	depth := 0
+
+	for _, instr := range format.Instructions {
+		switch instr.Op {
+		case intermediate.OpIfSystemLimit, intermediate.OpIfSystemOffset, intermediate.OpIf, intermediate.OpLoopStart:
+			depth++
+
+			if skipDepth == 0 && instr.DefaultValue == "" && (instr.Op == intermediate.OpIfSystemLimit || instr.Op == intermediate.OpIfSystemOffset) {
+				skipDepth = depth
+			}
+
+			continue
+		case intermediate.OpElse, intermediate.OpElseIf:
+			if skipDepth == depth {
+				skipDepth = 0
+			}
+
+			continue
+		case intermediate.OpEnd, intermediate.OpLoopEnd:
+			if skipDepth == depth {
+				skipDepth = 0
+			}
+
+			depth--
+
+			continue
+		}
+
+		if skipDepth != 0 {
+			continue
+		}
+
+		switch instr.Op {
+		case intermediate.OpEmitSystemLimit:
+			return true
+		case intermediate.OpEmitStatic:
+			for _, word := range strings.FieldsFunc(strings.ToUpper(instr.Value), isSQLWordSeparator) {
+				if word == "LIMIT" {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// isSQLWordSeparator splits static SQL fragments into keyword candidates
+func isSQLWordSeparator(r rune) bool {
+	return !(r == '_' || (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'))
 }
 
 // readOriginalSQL reads SQL content from .snap.sql or extracts SQL from .snap.md
@@ -695,3 +837,17 @@ func OpenDatabase(driver, connectionString string, timeout int) (*sql.DB, error)
 
 	return db, nil
 }
+
+// SystemLimitArg clamps the LIMIT parameter of an EMIT_SYSTEM_LIMIT instruction to the cap from snapsql.yaml (limits).
+func SystemLimitArg(inst codegenerator.OptimizedInstruction, value any) any {
+	if inst.Op != codegenerator.OpEmitSystemLimit {
+		return value
+	}
+
+	maxLimit, err := strconv.Atoi(inst.Value)
+	if err != nil {
+		return value
+	}
+
+	return snapsqlgo.ClampLimit(value, maxLimit)
+}
@@ -119,3 +119,146 @@ func TestBuildSQLAddsSpacingAfterBoundaryTokens(t *testing.T) {
 	assert.Equal(t, "SELECT id FROM t WHERE a =? AND b >?", sql)
 	assert.Equal(t, 2, len(args))
 }
+
+func TestHasLimitClause(t *testing.T) {
+	testCases := []struct {
+		name         string
+		instructions []intermediate.Instruction
+		expected     bool
+	}{
+		{
+			name:         "no limit",
+			instructions: []intermediate.Instruction{{Op: intermediate.OpEmitStatic, Value: "SELECT id FROM users"}},
+			expected:     false,
+		},
+		{
+			name:         "literal limit",
+			instructions: []intermediate.Instruction{{Op: intermediate.OpEmitStatic, Value: "SELECT id FROM users LIMIT 10"}},
+			expected:     true,
+		},
+		{
+			name: "system limit",
+			instructions: []intermediate.Instruction{
+				{Op: intermediate.OpEmitStatic, Value: "SELECT id FROM users"},
+				{Op: intermediate.OpEmitSystemLimit, DefaultValue: "10"},
+			},
+			expected: true,
+		},
+		{
+			name: "runtime system limit block",
+			instructions: []intermediate.Instruction{
+				{Op: intermediate.OpEmitStatic, Value: "SELECT id FROM users"},
+				{Op: intermediate.OpIfSystemLimit},
+				{Op: intermediate.OpEmitStatic, Value: " LIMIT "},
+				{Op: intermediate.OpEmitSystemLimit},
+				{Op: intermediate.OpEnd},
+			},
+			expected: false,
+		},
+		{
+			name: "limit capped by snapsql.yaml",
+			instructions: []intermediate.Instruction{
+				{Op: intermediate.OpEmitStatic, Value: "SELECT id FROM users"},
+				{Op: intermediate.OpIfSystemLimit, DefaultValue: "100"},
+				{Op: intermediate.OpEmitStatic, Value: " LIMIT "},
+				{Op: intermediate.OpEmitSystemLimit, DefaultValue: "100"},
+				{Op: intermediate.OpEnd},
+			},
+			expected: true,
+		},
+		{
+			name: "literal limit overridable by system limit",
+			instructions: []intermediate.Instruction{
+				{Op: intermediate.OpEmitStatic, Value: "SELECT id FROM users LIMIT "},
+				{Op: intermediate.OpIfSystemLimit},
+				{Op: intermediate.OpEmitSystemLimit},
+				{Op: intermediate.OpElse},
+				{Op: intermediate.OpEmitStatic, Value: "10"},
+				{Op: intermediate.OpEnd},
+			},
+			expected: true,
+		},
+		{
+			name:         "limit as part of identifier",
+			instructions: []intermediate.Instruction{{Op: intermediate.OpEmitStatic, Value: "SELECT credit_limit FROM users"}},
+			expected:     false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			format := &intermediate.IntermediateFormat{Instructions: tc.instructions}
+			assert.Equal(t, tc.expected, HasLimitClause(format))
+		})
+	}
+}
+
+func TestAddLimitOffsetIfNeeded(t *testing.T) {
+	options := QueryOptions{Limit: 100}
+
+	testCases := []struct {
+		name          string
+		sql           string
+		statementType string
+		expected      string
+	}{
+		{
+			name:          "select",
+			sql:           "SELECT id FROM users;",
+			statementType: "select",
+			expected:      "SELECT id FROM users\nLIMIT 100;",
+		},
+		{
+			name:          "select with CTE",
+			sql:           "WITH active AS (SELECT id FROM users WHERE active) SELECT id FROM active",
+			statementType: "select",
+			expected:      "WITH active AS (SELECT id FROM users WHERE active) SELECT id FROM active\nLIMIT 100",
+		},
+		{
+			name:          "limit inside CTE",
+			sql:           "WITH recent AS (SELECT id FROM users ORDER BY id DESC LIMIT 10) SELECT id FROM recent",
+			statementType: "select",
+			expected:      "WITH recent AS (SELECT id FROM users ORDER BY id DESC LIMIT 10) SELECT id FROM recent\nLIMIT 100",
+		},
+		{
+			name:          "existing limit",
+			sql:           "WITH active AS (SELECT id FROM users) SELECT id FROM active\nLIMIT 5",
+			statementType: "select",
+			expected:      "WITH active AS (SELECT id FROM users) SELECT id FROM active\nLIMIT 5",
+		},
+		{
+			name:          "limit in string literal",
+			sql:           "SELECT id FROM users WHERE note = ' LIMIT '",
+			statementType: "select",
+			expected:      "SELECT id FROM users WHERE note = ' LIMIT '\nLIMIT 100",
+		},
+		{
+			name:          "limit in comments",
+			sql:           "/*#\nfunction_name: list_users\ndescription: list users with limit and offset\n*/\nSELECT id FROM users -- no limit here\n",
+			statementType: "select",
+			expected:      "/*#\nfunction_name: list_users\ndescription: list users with limit and offset\n*/\nSELECT id FROM users\nLIMIT 100 -- no limit here\n",
+		},
+		{
+			name:          "trailing line comment",
+			sql:           "SELECT id FROM users\n-- newest first\n;",
+			statementType: "select",
+			expected:      "SELECT id FROM users\nLIMIT 100\n-- newest first\n;",
+		},
+		{
+			name:          "update with CTE",
+			sql:           "WITH stale AS (SELECT id FROM users) UPDATE users SET active = false WHERE id IN (SELECT id FROM stale)",
+			statementType: "update",
+			expected:      "WITH stale AS (SELECT id FROM users) UPDATE users SET active = false WHERE id IN (SELECT id FROM stale)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			format := &intermediate.IntermediateFormat{StatementType: tc.statementType}
+			assert.Equal(t, tc.expected, addLimitOffsetIfNeeded(tc.sql, format, options))
+		})
+	}
+
+	format := &intermediate.IntermediateFormat{StatementType: "select"}
+	assert.Equal(t, "SELECT id FROM users\nLIMIT 10\nOFFSET 20", addLimitOffsetIfNeeded("SELECT id FROM users", format, QueryOptions{Limit: 10, Offset: 20}))
+}
@@ -6,6 +6,8 @@ import (
 	"maps"
 	"math/rand"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -17,6 +19,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/shibukawa/snapsql"
 	"github.com/shibukawa/snapsql/intermediate"
+	"github.com/shibukawa/snapsql/langs/snapsqlgo"
 )
 
 // Error definitions for SQL generation
@@ -27,6 +30,8 @@ var (
 	ErrUnsupportedOperation   = errors.New("unsupported operation")
 )
 
+var celIdentifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
 var randomSource = rand.New(rand.NewSource(time.Now().UnixNano()))
 
 // SQLGenerator generates SQL from intermediate format instructions
@@ -40,6 +45,8 @@ type SQLGenerator struct {
 	celEnv          *cel.Env
 	loopBoundaries  map[int]int
 	loopBoundaryErr error
+	optionalSet     map[int]string // IF expression index -> set_optional parameter
+	now             time.Time      // fixed value of NOW() system defaults; zero uses the wall time
 }
 
 // NewSQLGenerator creates a new SQL generator
@@ -49,6 +56,7 @@ func NewSQLGenerator(format *intermediate.IntermediateFormat, dialect snapsql.Di
 		expressions  []intermediate.CELExpression
 		systemFields map[string]intermediate.SystemFieldInfo
 		implicitMap  map[string]intermediate.ImplicitParameter
+		optionalSet  map[int]string
 	)
 
 	if format != nil {
@@ -68,6 +76,13 @@ func NewSQLGenerator(format *intermediate.IntermediateFormat, dialect snapsql.Di
 				implicitMap[strings.ToLower(ip.Name)] = ip
 			}
 		}
+
+		if len(format.OptionalSet) > 0 {
+			optionalSet = make(map[int]string, len(format.OptionalSet))
+			for _, column := range format.OptionalSet {
+				optionalSet[column.ExprIndex] = column.Parameter
+			}
+		}
 	}
 
 	// (No normalization of dialect-specific instruction names is performed
@@ -85,6 +100,7 @@ func NewSQLGenerator(format *intermediate.IntermediateFormat, dialect snapsql.Di
 	// Create CEL environment
 	env, _ := cel.NewEnv(
 		cel.Variable("params", cel.MapType(cel.StringType, cel.AnyType)),
+		cel.CrossTypeNumericComparisons(true),
 	)
 
 	generator := &SQLGenerator{
@@ -95,6 +111,7 @@ func NewSQLGenerator(format *intermediate.IntermediateFormat, dialect snapsql.Di
 		generated:    make(map[string]any),
 		dialect:      dialect,
 		celEnv:       env,
+		optionalSet:  optionalSet,
 	}
 
 	boundaries, err := computeLoopBoundaries(generator.instructions)
@@ -104,6 +121,21 @@ func NewSQLGenerator(format *intermediate.IntermediateFormat, dialect snapsql.Di
 	return generator
 }
 
+// SetNow fixes the time that NOW()/CURRENT_TIMESTAMP system defaults and generated timestamps
+// take. A zero time restores the wall time.
+func (g *SQLGenerator) SetNow(now time.Time) {
+	g.now = now
+}
+
+// currentTime returns the time used for generated timestamp values
+func (g *SQLGenerator) currentTime() time.Time {
+	if g.now.IsZero() {
+		return time.Now().UTC()
+	}
+
+	return g.now.UTC()
+}
+
 // Generate generates SQL and parameters from the instructions
 func (g *SQLGenerator) Generate(params map[string]any) (string, []any, error) {
 	if g.loopBoundaryErr != nil {
@@ -248,7 +280,13 @@ func (g *SQLGenerator) processInstructions(state *generationState, params map[st
 				state.boundaryNeeded = true
 			}
 
-		case intermediate.OpEmitEval:
+		case intermediate.OpEmitEval, intermediate.OpEmitCursorLimit:
+			if instr.Op == intermediate.OpEmitCursorLimit && instr.ExprIndex == nil {
+				// Literal page size of cursor pagination
+				state.appendSQL(instr.Value)
+				break
+			}
+
 			if instr.ExprIndex == nil {
 				return fmt.Errorf("%w: instruction %d has no expression index", ErrInvalidExpressionIndex, i)
 			}
@@ -271,6 +309,27 @@ func (g *SQLGenerator) processInstructions(state *generationState, params map[st
 				state.boundaryNeeded = true
 			}
 
+		case intermediate.OpEmitIdent, intermediate.OpEmitOrderBy:
+			if instr.ExprIndex == nil || *instr.ExprIndex < 0 || *instr.ExprIndex >= len(g.expressions) {
+				return fmt.Errorf("%w: instruction %d has no valid expression index", ErrInvalidExpressionIndex, i)
+			}
+
+			value, err := g.evaluateExpression(g.expressions[*instr.ExprIndex].Expression, params)
+			if err != nil {
+				return fmt.Errorf("%w: %w", ErrExpressionEvaluation, err)
+			}
+
+			ident, err := AllowedItem(instr.Op, value, instr.Allowed)
+			if err != nil {
+				return err
+			}
+
+			state.appendSQL(ident)
+
+			if state.boundaryEnabled {
+				state.boundaryNeeded = true
+			}
+
 		case intermediate.OpIf:
 			if instr.ExprIndex == nil {
 				return fmt.Errorf("%w: IF instruction %d has no expression index", ErrInvalidExpressionIndex, i)
@@ -280,6 +339,14 @@ func (g *SQLGenerator) processInstructions(state *generationState, params map[st
 				return fmt.Errorf("%w: IF instruction %d has invalid expression index %d", ErrInvalidExpressionIndex, i, *instr.ExprIndex)
 			}
 
+			// set_optional assignments are emitted whenever the parameter is given and not null
+			if param, ok := g.optionalSet[*instr.ExprIndex]; ok {
+				value, exists := params[param]
+				*conditionStack = append(*conditionStack, exists && !isNilValue(value))
+
+				continue
+			}
+
 			expr := g.expressions[*instr.ExprIndex]
 
 			condition, err := g.evaluateCondition(expr.Expression, params)
@@ -359,10 +426,31 @@ func (g *SQLGenerator) processInstructions(state *generationState, params map[st
 			// Loop end is handled when the corresponding start is processed.
 
 		case intermediate.OpIfSystemLimit:
-			*conditionStack = append(*conditionStack, g.shouldEmitSystemClause(params, "limit"))
+			// A LIMIT capped by snapsql.yaml (limits) is always emitted
+			*conditionStack = append(*conditionStack, instr.DefaultValue != "" || g.shouldEmitSystemClause(params, "limit"))
 		case intermediate.OpIfSystemOffset:
 			*conditionStack = append(*conditionStack, g.shouldEmitSystemClause(params, "offset"))
 		case intermediate.OpEmitSystemLimit:
+			if instr.ExprIndex != nil {
+				// LIMIT parameter clamped to the cap from snapsql.yaml (limits)
+				if *instr.ExprIndex < 0 || *instr.ExprIndex >= len(g.expressions) {
+					return fmt.Errorf("%w: instruction %d has invalid expression index %d", ErrInvalidExpressionIndex, i, *instr.ExprIndex)
+				}
+
+				value, err := g.evaluateExpression(g.expressions[*instr.ExprIndex].Expression, params)
+				if err != nil {
+					return fmt.Errorf("%w: %w", ErrExpressionEvaluation, err)
+				}
+
+				maxLimit, _ := strconv.Atoi(instr.DefaultValue)
+
+				state.appendSQL("?")
+
+				*state.sqlParams = append(*state.sqlParams, snapsqlgo.ClampLimit(value, maxLimit))
+
+				break
+			}
+
 			limitLiteral := g.resolveSystemNumeric(instr.DefaultValue, "limit")
 			state.appendSQL(limitLiteral)
 		case intermediate.OpEmitSystemOffset:
@@ -381,6 +469,13 @@ func (g *SQLGenerator) processInstructions(state *generationState, params map[st
 				state.boundaryNeeded = true
 			}
 
+		case intermediate.OpEmitSystemSoftDelete:
+			// Soft-deleted rows are always filtered out here; WithDeleted only exists in generated code.
+			state.appendSQL(" " + instr.Value)
+
+		case intermediate.OpEmitCursorCondition:
+			// Without a cursor the first page is read.
+
 		// OpEmitIfDialect is resolved at generator construction time; legacy
 		// IR should have been normalized to EMIT_STATIC. Runtime handling is
 		// therefore no longer necessary.
@@ -654,7 +749,7 @@ func (g *SQLGenerator) normalizeSystemDefault(raw any, typeName, fieldName strin
 		upper := strings.ToUpper(strings.TrimSpace(v))
 		switch upper {
 		case "NOW()", "CURRENT_TIMESTAMP", "CURRENT_TIMESTAMP()":
-			return time.Now().UTC()
+			return g.currentTime()
 		case "UUID_GENERATE_V4()", "GEN_RANDOM_UUID()", "UUID()":
 			return uuid.NewString()
 		default:
@@ -674,7 +769,7 @@ func (g *SQLGenerator) generateFallbackValue(typeName, fieldName string) any {
 	name := strings.ToLower(fieldName)
 
 	if strings.Contains(lowerType, "time") || strings.HasSuffix(name, "_at") {
-		return time.Now().UTC()
+		return g.currentTime()
 	}
 
 	if strings.Contains(lowerType, "uuid") || strings.Contains(lowerType, "guid") || strings.HasSuffix(name, "_id") || strings.HasSuffix(name, "_by") {
@@ -744,15 +839,16 @@ func (g *SQLGenerator) evaluateExpression(expression string, params map[string]a
 		return value, nil
 	}
 
-	// Try to evaluate as CEL expression
-	ast, issues := g.celEnv.Compile(expression)
-	if issues.Err() != nil {
-		return nil, fmt.Errorf("failed to compile expression '%s': %w", expression, issues.Err())
-	}
+	// Try to evaluate as CEL expression. Parameters are declared as dynamic variables so that
+	// expressions such as "min_age > 0" or "user.name" can refer to them directly.
+	// Programs are shared by every generator whose parameters have the same names.
+	names := expressionVariables(params)
 
-	program, err := g.celEnv.Program(ast)
+	program, err := snapsqlgo.CompileCEL(sqlGeneratorEnvSignature+strings.Join(names, ","), expression, func() (*cel.Env, error) {
+		return g.expressionEnv(names)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create program for expression '%s': %w", expression, err)
+		return nil, fmt.Errorf("%w: %w", ErrExpressionEvaluation, err)
 	}
 
 	// Create evaluation context
@@ -771,6 +867,46 @@ func (g *SQLGenerator) evaluateExpression(expression string, params map[string]a
 	return result.Value(), nil
 }
 
+// sqlGeneratorEnvSignature identifies the base environment in CEL program cache keys
+const sqlGeneratorEnvSignature = "query.SQLGenerator:"
+
+// expressionVariables returns the sorted parameter names declared as CEL variables
+func expressionVariables(params map[string]any) []string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		if name != "params" && celIdentifierPattern.MatchString(name) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// expressionEnv extends the base environment with a dynamic variable for each name
+func (g *SQLGenerator) expressionEnv(names []string) (*cel.Env, error) {
+	if g.celEnv == nil {
+		return nil, fmt.Errorf("%w: CEL environment is not available", ErrExpressionEvaluation)
+	}
+
+	if len(names) == 0 {
+		return g.celEnv, nil
+	}
+
+	decls := make([]cel.EnvOption, 0, len(names))
+	for _, name := range names {
+		decls = append(decls, cel.Variable(name, cel.DynType))
+	}
+
+	if env, err := g.celEnv.Extend(decls...); err == nil {
+		return env, nil
+	}
+
+	// Reserved words cannot be declared; such parameters stay reachable through params
+	return g.celEnv, nil
+}
+
 // evaluateCondition evaluates a condition expression and returns a boolean result
 func (g *SQLGenerator) evaluateCondition(expression string, params map[string]any) (bool, error) {
 	result, err := g.evaluateExpression(expression, params)
@@ -796,3 +932,24 @@ func (g *SQLGenerator) evaluateCondition(expression string, params map[string]an
 		return true, nil // treat non-zero/non-empty as truthy
 	}
 }
+
+// isNilValue reports whether value is nil or a nil pointer.
+func isNilValue(value any) bool {
+	if value == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(value)
+
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
+
+// AllowedItem checks the value of an EMIT_IDENT or EMIT_ORDER_BY instruction against its allowlist
+// and returns the text to write into the SQL.
+func AllowedItem(op string, value any, allowed []string) (string, error) {
+	if op == intermediate.OpEmitOrderBy {
+		return snapsqlgo.AllowedOrderBy(value, allowed...)
+	}
+
+	return snapsqlgo.AllowedIdentifier(value, allowed...)
+}
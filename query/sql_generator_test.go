@@ -9,6 +9,7 @@ import (
 	"github.com/alecthomas/assert/v2"
 	"github.com/shibukawa/snapsql"
 	"github.com/shibukawa/snapsql/intermediate"
+	"github.com/shibukawa/snapsql/langs/snapsqlgo"
 )
 
 func TestSQLGenerator_Generate_BasicOperations(t *testing.T) {
@@ -253,6 +254,123 @@ func TestSQLGenerator_SystemValueDefaults(t *testing.T) {
 
 	assert.Equal(t, args[0], params["created_at"])
 	assert.Equal(t, args[1], params["created_by"])
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	generator.SetNow(now)
+
+	_, args, err = generator.Generate(map[string]any{})
+	assert.NoError(t, err)
+	assert.Equal[any](t, now, args[0])
+}
+
+func TestSQLGenerator_OptionalSet(t *testing.T) {
+	format := &intermediate.IntermediateFormat{
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Value: "UPDATE users SET"},
+			{Op: intermediate.OpBoundary},
+			{Op: intermediate.OpIf, ExprIndex: intPtr(0)},
+			{Op: intermediate.OpEmitUnlessBoundary, Value: ","},
+			{Op: intermediate.OpEmitStatic, Value: " name = "},
+			{Op: intermediate.OpEmitEval, ExprIndex: intPtr(0)},
+			{Op: intermediate.OpEnd},
+			{Op: intermediate.OpIf, ExprIndex: intPtr(1)},
+			{Op: intermediate.OpEmitUnlessBoundary, Value: ","},
+			{Op: intermediate.OpEmitStatic, Value: " active = "},
+			{Op: intermediate.OpEmitEval, ExprIndex: intPtr(1)},
+			{Op: intermediate.OpEnd},
+			{Op: intermediate.OpEmitStatic, Value: " WHERE id = "},
+			{Op: intermediate.OpEmitEval, ExprIndex: intPtr(2)},
+		},
+		CELExpressions: []intermediate.CELExpression{
+			{Expression: "name"},
+			{Expression: "active"},
+			{Expression: "user_id"},
+		},
+		OptionalSet: []intermediate.OptionalSetColumn{
+			{Column: "name", Parameter: "name", ExprIndex: 0},
+			{Column: "active", Parameter: "active", ExprIndex: 1},
+		},
+	}
+
+	testCases := []struct {
+		name         string
+		params       map[string]any
+		expectedSQL  string
+		expectedArgs []any
+	}{
+		{
+			name:         "all columns",
+			params:       map[string]any{"name": "Alice", "active": true, "user_id": 1},
+			expectedSQL:  "UPDATE users SET name = ?, active = ? WHERE id = ?",
+			expectedArgs: []any{"Alice", true, 1},
+		},
+		{
+			name:         "falsy value is still assigned",
+			params:       map[string]any{"active": false, "user_id": 1},
+			expectedSQL:  "UPDATE users SET active = ? WHERE id = ?",
+			expectedArgs: []any{false, 1},
+		},
+		{
+			name:         "nil pointer is skipped",
+			params:       map[string]any{"name": (*string)(nil), "active": true, "user_id": 1},
+			expectedSQL:  "UPDATE users SET active = ? WHERE id = ?",
+			expectedArgs: []any{true, 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sql, args, err := NewSQLGenerator(format, snapsql.DialectSQLite).Generate(tc.params)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedSQL, sql)
+			assert.Equal(t, tc.expectedArgs, args)
+		})
+	}
+}
+
+func TestSQLGenerator_Ident(t *testing.T) {
+	format := &intermediate.IntermediateFormat{
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Value: "SELECT id FROM users ORDER BY "},
+			{Op: intermediate.OpEmitIdent, ExprIndex: intPtr(0), Allowed: []string{"name", "created_at"}},
+			{Op: intermediate.OpEmitStatic, Value: " DESC"},
+		},
+		CELExpressions: []intermediate.CELExpression{
+			{Expression: "sort"},
+		},
+	}
+
+	sql, args, err := NewSQLGenerator(format, snapsql.DialectSQLite).Generate(map[string]any{"sort": "created_at"})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY created_at DESC", sql)
+	assert.Equal(t, 0, len(args))
+
+	_, _, err = NewSQLGenerator(format, snapsql.DialectSQLite).Generate(map[string]any{"sort": "name; DROP TABLE users"})
+	assert.IsError(t, err, snapsqlgo.ErrIdentifierNotAllowed)
+}
+
+func TestSQLGenerator_OrderBy(t *testing.T) {
+	format := &intermediate.IntermediateFormat{
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Value: "SELECT id FROM users ORDER BY "},
+			{Op: intermediate.OpEmitOrderBy, ExprIndex: intPtr(0), Allowed: []string{"name", "created_at DESC"}},
+			{Op: intermediate.OpEmitStatic, Value: ", id"},
+		},
+		CELExpressions: []intermediate.CELExpression{
+			{Expression: "sort"},
+		},
+	}
+
+	sql, _, err := NewSQLGenerator(format, snapsql.DialectSQLite).Generate(map[string]any{"sort": "created_at desc"})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY created_at DESC, id", sql)
+
+	sql, _, err = NewSQLGenerator(format, snapsql.DialectSQLite).Generate(map[string]any{"sort": ""})
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM users ORDER BY name, id", sql)
+
+	_, _, err = NewSQLGenerator(format, snapsql.DialectSQLite).Generate(map[string]any{"sort": "created_at"})
+	assert.IsError(t, err, snapsqlgo.ErrOrderByNotAllowed)
 }
 
 func TestSQLGenerator_Generate_LoopOperations(t *testing.T) {
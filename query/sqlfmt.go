@@ -16,11 +16,11 @@ func FormatSQLForDriver(sql string, driver string) string {
 
 // FormatSQLForDialect converts placeholders for display (dry-run use)
 // and ensures readability by adding a space after placeholders when needed.
-// Dialect: postgresql/mysql/sqlite.
+// Dialect: postgresql/mysql/sqlite/duckdb/clickhouse/cockroach.
 func FormatSQLForDialect(sql string, dialect snapsql.Dialect) string {
 	d := strings.ToLower(strings.TrimSpace(string(dialect)))
 	switch d {
-	case "postgres", "postgresql", "pg", "pgx":
+	case "postgres", "postgresql", "pg", "pgx", "duckdb", "cockroach":
 		var b strings.Builder
 
 		n := 1
@@ -30,8 +30,8 @@ func LoadIntermediateFormat(templateFile string) (*intermediate.IntermediateForm
 	ext := strings.ToLower(filepath.Ext(templateFile))
 
 	switch ext {
-	case ".json":
-		return loadFromJSON(templateFile)
+	case ".json", intermediate.BinaryFileExtension:
+		return loadIntermediateFile(templateFile)
 	case ".sql":
 		return generateFromSQL(templateFile)
 	case ".md":
@@ -41,14 +41,14 @@ func LoadIntermediateFormat(templateFile string) (*intermediate.IntermediateForm
 	}
 }
 
-// loadFromJSON loads intermediate format from a JSON file
-func loadFromJSON(filename string) (*intermediate.IntermediateFormat, error) {
+// loadIntermediateFile loads intermediate format from a JSON or binary intermediate file
+func loadIntermediateFile(filename string) (*intermediate.IntermediateFormat, error) {
 	data, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrFileRead, err)
 	}
 
-	format, err := intermediate.FromJSON(data)
+	format, err := intermediate.Decode(data)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %w", ErrTemplateGeneration, err)
 	}
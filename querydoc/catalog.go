@@ -0,0 +1,134 @@
+// Package querydoc renders a browsable catalog of the queries described by intermediate files.
+package querydoc
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+)
+
+// Entry is the documentation of one query.
+type Entry struct {
+	Name             string
+	Description      string
+	Source           string
+	StatementType    string
+	ResponseAffinity string
+	Parameters       []intermediate.Parameter
+	Responses        []intermediate.Response
+	Tables           []string
+	SQL              string
+}
+
+// Anchor returns the fragment identifier used to link to the entry.
+func (e Entry) Anchor() string {
+	var b strings.Builder
+
+	for _, r := range strings.ToLower(e.Name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('-')
+		}
+	}
+
+	return b.String()
+}
+
+// Catalog collects entries and writes them as a single HTML or Markdown document.
+type Catalog struct {
+	Title   string
+	Dialect snapsql.Dialect
+	Entries []Entry
+}
+
+// Add documents one query. source is the path shown as the origin of the query.
+func (c *Catalog) Add(format *intermediate.IntermediateFormat, source string) error {
+	sql, err := RenderSQL(format, c.Dialect)
+	if err != nil {
+		return fmt.Errorf("%s: %w", source, err)
+	}
+
+	name := format.FunctionName
+	if name == "" {
+		name = format.Name
+	}
+
+	if name == "" {
+		name = strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))
+	}
+
+	c.Entries = append(c.Entries, Entry{
+		Name:             name,
+		Description:      format.Description,
+		Source:           filepath.ToSlash(source),
+		StatementType:    strings.ToLower(format.StatementType),
+		ResponseAffinity: strings.ToLower(format.ResponseAffinity),
+		Parameters:       format.Parameters,
+		Responses:        format.Responses,
+		Tables:           referencedTables(format.TableReferences),
+		SQL:              sql,
+	})
+
+	return nil
+}
+
+// Write writes the catalog in the given format ("html" or "markdown").
+func (c *Catalog) Write(w io.Writer, format string) error {
+	slices.SortStableFunc(c.Entries, func(a, b Entry) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	data := struct {
+		*Catalog
+		DialectName string
+	}{c, string(c.Dialect)}
+
+	if data.Title == "" {
+		data.Title = "SnapSQL query catalog"
+	}
+
+	if data.DialectName == "" {
+		data.DialectName = "generic"
+	}
+
+	switch strings.ToLower(format) {
+	case "html":
+		return htmlTemplate.Execute(w, data)
+	case "markdown", "md":
+		return markdownTemplate.Execute(w, data)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+}
+
+// referencedTables lists the physical tables a query touches; CTEs and subqueries are skipped
+func referencedTables(refs []intermediate.TableReferenceInfo) []string {
+	var tables []string
+
+	for _, ref := range refs {
+		name := ref.TableName
+		if name == "" && (ref.Context == "cte" || ref.Context == "subquery") {
+			continue
+		}
+
+		if name == "" {
+			name = ref.Name
+		}
+
+		if name != "" && !slices.Contains(tables, name) {
+			tables = append(tables, name)
+		}
+	}
+
+	slices.Sort(tables)
+
+	return tables
+}
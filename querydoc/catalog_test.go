@@ -0,0 +1,92 @@
+package querydoc
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+)
+
+func intPtr(v int) *int { return &v }
+
+func findUser() *intermediate.IntermediateFormat {
+	return &intermediate.IntermediateFormat{
+		FunctionName:     "find_user",
+		Description:      "Find a user | by id",
+		StatementType:    "SELECT",
+		ResponseAffinity: "one",
+		Parameters: []intermediate.Parameter{
+			{Name: "user_id", Type: "int"},
+			{Name: "active", Type: "bool", Optional: true},
+		},
+		Responses: []intermediate.Response{
+			{Name: "id", Type: "int"},
+			{Name: "email", Type: "string", IsNullable: true},
+		},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Value: "SELECT id, email FROM users u JOIN orders o ON o.user_id = u.id WHERE u.id = "},
+			{Op: intermediate.OpEmitEval, ExprIndex: intPtr(0)},
+			{Op: intermediate.OpIf, ExprIndex: intPtr(1)},
+			{Op: intermediate.OpEmitStatic, Value: " AND u.active = "},
+			{Op: intermediate.OpEmitEval, ExprIndex: intPtr(1)},
+			{Op: intermediate.OpEnd},
+		},
+		CELExpressions: []intermediate.CELExpression{
+			{Expression: "user_id"},
+			{Expression: "active"},
+		},
+		TableReferences: []intermediate.TableReferenceInfo{
+			{Name: "users", TableName: "users", Alias: "u", Context: "main"},
+			{Name: "orders", TableName: "orders", Alias: "o", Context: "join"},
+			{Name: "recent", Context: "cte"},
+		},
+	}
+}
+
+func TestRenderSQL(t *testing.T) {
+	sql, err := RenderSQL(findUser(), snapsql.DialectPostgres)
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id, email FROM users u JOIN orders o ON o.user_id = u.id WHERE u.id = /*= user_id */$1/*# if active */ AND u.active = /*= active */$2/*# end */", sql)
+
+	sql, err = RenderSQL(findUser(), snapsql.DialectMySQL)
+	assert.NoError(t, err)
+	assert.Contains(t, sql, "WHERE u.id = /*= user_id */?")
+}
+
+func TestCatalogMarkdown(t *testing.T) {
+	catalog := &Catalog{Title: "Users", Dialect: snapsql.DialectSQLite}
+	assert.NoError(t, catalog.Add(findUser(), "generated/find_user.json"))
+	assert.NoError(t, catalog.Add(&intermediate.IntermediateFormat{StatementType: "DELETE"}, "generated/cleanup.json"))
+
+	var buf bytes.Buffer
+	assert.NoError(t, catalog.Write(&buf, "markdown"))
+
+	out := buf.String()
+	assert.Contains(t, out, "# Users\n")
+	assert.Contains(t, out, "- [cleanup](#cleanup)\n- [find_user](#find_user) — Find a user \\| by id\n")
+	assert.Contains(t, out, "- Tables: `orders`, `users`\n")
+	assert.Contains(t, out, "| `active` | `bool` | no |  |\n")
+	assert.Contains(t, out, "| `email` | `string` | yes |\n")
+	assert.Contains(t, out, "```sql\nSELECT id, email FROM users")
+	assert.True(t, strings.Index(out, "## cleanup") < strings.Index(out, "## find_user"))
+}
+
+func TestCatalogHTML(t *testing.T) {
+	catalog := &Catalog{}
+	assert.NoError(t, catalog.Add(findUser(), "find_user.json"))
+
+	var buf bytes.Buffer
+	assert.NoError(t, catalog.Write(&buf, "html"))
+
+	out := buf.String()
+	assert.Contains(t, out, "<title>SnapSQL query catalog</title>")
+	assert.Contains(t, out, `<section id="find_user">`)
+	assert.Contains(t, out, "Dialect: <code>generic</code>")
+	assert.Contains(t, out, "o.user_id = u.id")
+	assert.NotContains(t, out, "<script")
+
+	assert.IsError(t, catalog.Write(&buf, "pdf"), ErrUnsupportedFormat)
+}
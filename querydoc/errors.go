@@ -0,0 +1,8 @@
+package querydoc
+
+import "errors"
+
+var (
+	// ErrUnsupportedFormat is returned for catalog formats other than html and markdown.
+	ErrUnsupportedFormat = errors.New("querydoc: unsupported format")
+)
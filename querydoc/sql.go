@@ -0,0 +1,88 @@
+package querydoc
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+	"github.com/shibukawa/snapsql/intermediate/codegenerator"
+)
+
+// trailingPlaceholder matches the placeholder emitted right before an ADD_PARAM instruction
+var trailingPlaceholder = regexp.MustCompile(`(\?|\$\d+)$`)
+
+// RenderSQL renders the query for the dialect as an example SQL text.
+//
+// Placeholders are annotated with the expression that binds them (e.g. "/*= user_id */$1") and
+// conditional or loop blocks are kept as SnapSQL directives, so every branch stays visible.
+func RenderSQL(format *intermediate.IntermediateFormat, dialect snapsql.Dialect) (string, error) {
+	optimized, err := codegenerator.OptimizeInstructions(format.Instructions, dialect)
+	if err != nil {
+		return "", fmt.Errorf("failed to optimize instructions: %w", err)
+	}
+
+	var b strings.Builder
+
+	for _, inst := range optimized {
+		switch inst.Op {
+		case "EMIT_STATIC", "EMIT_UNLESS_BOUNDARY":
+			b.WriteString(inst.Value)
+		case "ADD_PARAM":
+			annotatePlaceholder(&b, "/*= "+expressionText(format, inst.ExprIndex)+" */")
+		case codegenerator.OpEmitSystemLimit:
+			annotatePlaceholder(&b, "/*= min("+expressionText(format, inst.ExprIndex)+", "+inst.Value+") */")
+		case "ADD_SYSTEM_PARAM":
+			annotatePlaceholder(&b, "/*= "+inst.SystemField+" */")
+		case codegenerator.OpEmitIdent:
+			b.WriteString("/*# ident " + expressionText(format, inst.ExprIndex) + " in [" + strings.Join(inst.Allowed, ", ") + "] */" + inst.Allowed[0])
+		case codegenerator.OpEmitOrderBy:
+			b.WriteString("/*# orderby " + expressionText(format, inst.ExprIndex) + " in [" + strings.Join(inst.Allowed, ", ") + "] */" + inst.Allowed[0])
+		case codegenerator.OpEmitSystemSoftDelete, codegenerator.OpEmitCursorCondition:
+			b.WriteString(" " + inst.Value)
+		case codegenerator.OpEmitCursorLimit:
+			if inst.ExprIndex != nil {
+				annotatePlaceholder(&b, "/*= "+expressionText(format, inst.ExprIndex)+" */")
+			} else {
+				b.WriteString(inst.Value)
+			}
+		case "IF":
+			b.WriteString("/*# if " + expressionText(format, inst.ExprIndex) + " */")
+		case "ELSEIF":
+			b.WriteString("/*# elseif " + expressionText(format, inst.ExprIndex) + " */")
+		case "ELSE":
+			b.WriteString("/*# else */")
+		case "END", "LOOP_END":
+			b.WriteString("/*# end */")
+		case "LOOP_START":
+			b.WriteString("/*# for " + inst.Variable + " : " + expressionText(format, inst.CollectionExprIndex) + " */")
+		}
+	}
+
+	return strings.TrimSpace(b.String()), nil
+}
+
+// annotatePlaceholder inserts the comment in front of the placeholder at the end of b
+func annotatePlaceholder(b *strings.Builder, comment string) {
+	current := b.String()
+
+	loc := trailingPlaceholder.FindStringIndex(current)
+	if loc == nil {
+		b.WriteString(comment)
+		return
+	}
+
+	b.Reset()
+	b.WriteString(current[:loc[0]])
+	b.WriteString(comment)
+	b.WriteString(current[loc[0]:])
+}
+
+func expressionText(format *intermediate.IntermediateFormat, index *int) string {
+	if index == nil || *index < 0 || *index >= len(format.CELExpressions) {
+		return "?"
+	}
+
+	return format.CELExpressions[*index].Expression
+}
@@ -0,0 +1,107 @@
+package querydoc
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+)
+
+var markdownTemplate = template.Must(template.New("markdown").Funcs(template.FuncMap{
+	"cell": markdownCell,
+}).Parse(`# {{.Title}}
+
+Dialect: ` + "`{{.DialectName}}`" + `
+
+## Queries
+
+{{range .Entries}}- [{{.Name}}](#{{.Anchor}}){{if .Description}} — {{cell .Description}}{{end}}
+{{end}}{{range .Entries}}
+## {{.Name}}
+{{if .Description}}
+{{.Description}}
+{{end}}
+- Source: ` + "`{{.Source}}`" + `
+{{- if .StatementType}}
+- Statement: ` + "`{{.StatementType}}`" + `{{end}}
+{{- if .ResponseAffinity}}
+- Response affinity: ` + "`{{.ResponseAffinity}}`" + `{{end}}
+{{- if .Tables}}
+- Tables: {{range $i, $t := .Tables}}{{if $i}}, {{end}}` + "`{{$t}}`" + `{{end}}{{end}}
+
+### Parameters
+{{if .Parameters}}
+| Name | Type | Required | Description |
+|------|------|----------|-------------|
+{{range .Parameters}}| ` + "`{{.Name}}`" + ` | ` + "`{{cell .Type}}`" + ` | {{if .Optional}}no{{else}}yes{{end}} | {{cell .Description}} |
+{{end}}{{else}}
+None.
+{{end}}
+### Response
+{{if .Responses}}
+| Name | Type | Nullable |
+|------|------|----------|
+{{range .Responses}}| ` + "`{{.Name}}`" + ` | ` + "`{{cell .Type}}`" + ` | {{if .IsNullable}}yes{{else}}no{{end}} |
+{{end}}{{else}}
+None.
+{{end}}
+### SQL
+
+` + "```sql" + `
+{{.SQL}}
+` + "```" + `
+{{end}}`))
+
+var htmlTemplate = htmltemplate.Must(htmltemplate.New("html").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem auto; max-width: 960px; padding: 0 1rem; color: #222; }
+table { border-collapse: collapse; margin: 0.5rem 0 1rem; }
+th, td { border: 1px solid #ccc; padding: 0.25rem 0.75rem; text-align: left; }
+pre { background: #f6f8fa; padding: 1rem; overflow-x: auto; }
+section { border-top: 1px solid #ddd; margin-top: 2rem; }
+.meta { color: #555; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">Dialect: <code>{{.DialectName}}</code></p>
+<h2>Queries</h2>
+<ul>
+{{range .Entries}}<li><a href="#{{.Anchor}}">{{.Name}}</a>{{if .Description}} — {{.Description}}{{end}}</li>
+{{end}}</ul>
+{{range .Entries}}<section id="{{.Anchor}}">
+<h2>{{.Name}}</h2>
+{{if .Description}}<p>{{.Description}}</p>
+{{end}}<ul class="meta">
+<li>Source: <code>{{.Source}}</code></li>
+{{if .StatementType}}<li>Statement: <code>{{.StatementType}}</code></li>
+{{end}}{{if .ResponseAffinity}}<li>Response affinity: <code>{{.ResponseAffinity}}</code></li>
+{{end}}{{if .Tables}}<li>Tables: {{range $i, $t := .Tables}}{{if $i}}, {{end}}<code>{{$t}}</code>{{end}}</li>
+{{end}}</ul>
+<h3>Parameters</h3>
+{{if .Parameters}}<table>
+<tr><th>Name</th><th>Type</th><th>Required</th><th>Description</th></tr>
+{{range .Parameters}}<tr><td><code>{{.Name}}</code></td><td><code>{{.Type}}</code></td><td>{{if .Optional}}no{{else}}yes{{end}}</td><td>{{.Description}}</td></tr>
+{{end}}</table>
+{{else}}<p>None.</p>
+{{end}}<h3>Response</h3>
+{{if .Responses}}<table>
+<tr><th>Name</th><th>Type</th><th>Nullable</th></tr>
+{{range .Responses}}<tr><td><code>{{.Name}}</code></td><td><code>{{.Type}}</code></td><td>{{if .IsNullable}}yes{{else}}no{{end}}</td></tr>
+{{end}}</table>
+{{else}}<p>None.</p>
+{{end}}<h3>SQL</h3>
+<pre><code class="language-sql">{{.SQL}}</code></pre>
+</section>
+{{end}}</body>
+</html>
+`))
+
+// markdownCell makes a value safe to place in a Markdown table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,200 @@
+// Package dynamic executes intermediate format files at runtime. Parameters are given as a
+// map and the template's CEL expressions are evaluated on every call, so tools and scripts
+// can run snapsql templates without generating Go code ahead of time.
+package dynamic
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+	"github.com/shibukawa/snapsql/langs/snapsqlgo"
+	"github.com/shibukawa/snapsql/query"
+)
+
+var (
+	// ErrDangerousQuery is returned for UPDATE/DELETE statements without a WHERE clause
+	// unless the statement was created with AllowDangerousQuery.
+	ErrDangerousQuery = errors.New("dynamic: UPDATE/DELETE without WHERE clause")
+	// ErrNoRows is returned by QueryOne when the statement returned no rows.
+	ErrNoRows = errors.New("dynamic: no rows in result set")
+)
+
+// Statement is an intermediate format prepared for execution with map parameters.
+// It is safe for concurrent use.
+type Statement struct {
+	format         *intermediate.IntermediateFormat
+	dialect        snapsql.Dialect
+	allowDangerous bool
+}
+
+// Option configures a Statement.
+type Option func(*Statement)
+
+// AllowDangerousQuery permits UPDATE/DELETE statements whose WHERE clause is empty after
+// the template's conditions were evaluated.
+func AllowDangerousQuery() Option {
+	return func(s *Statement) {
+		s.allowDangerous = true
+	}
+}
+
+// New prepares a parsed intermediate format for the dialect of the database it runs on.
+func New(format *intermediate.IntermediateFormat, dialect snapsql.Dialect, opts ...Option) *Statement {
+	s := &Statement{format: format, dialect: dialect}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Parse prepares an intermediate file produced by "snapsql generate --lang json",
+// either JSON or the binary encoding.
+func Parse(data []byte, dialect snapsql.Dialect, opts ...Option) (*Statement, error) {
+	format, err := intermediate.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(format, dialect, opts...), nil
+}
+
+// Load reads and prepares an intermediate file.
+func Load(path string, dialect snapsql.Dialect, opts ...Option) (*Statement, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read intermediate file %s: %w", path, err)
+	}
+
+	s, err := Parse(data, dialect, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// Name returns the function name of the template.
+func (s *Statement) Name() string {
+	return s.format.FunctionName
+}
+
+// Format returns the underlying intermediate format.
+func (s *Statement) Format() *intermediate.IntermediateFormat {
+	return s.format
+}
+
+// Returns reports whether the statement returns rows (its response affinity is not "none").
+func (s *Statement) Returns() bool {
+	affinity := intermediate.ResponseAffinity(s.format.ResponseAffinity)
+	return affinity != "" && affinity != intermediate.ResponseAffinityNone
+}
+
+// Build evaluates the template with params and returns the SQL with the dialect's
+// placeholders and its arguments. Required parameters missing from params are reported.
+func (s *Statement) Build(params map[string]any) (string, []any, error) {
+	if err := query.ValidateParameters(s.format, params); err != nil {
+		return "", nil, err
+	}
+
+	// The generator keeps per-call state and writes loop variables into the map
+	sqlText, args, err := query.NewSQLGenerator(s.format, s.dialect).Generate(maps.Clone(params))
+	if err != nil {
+		return "", nil, fmt.Errorf("%s: %w", s.Name(), err)
+	}
+
+	sqlText = query.FormatSQLForDialect(sqlText, s.dialect)
+
+	if !s.allowDangerous && query.IsDangerousQuery(sqlText) {
+		return "", nil, fmt.Errorf("%w: %s", ErrDangerousQuery, s.Name())
+	}
+
+	return sqlText, args, nil
+}
+
+// Exec runs a statement that does not return rows.
+func (s *Statement) Exec(ctx context.Context, executor snapsqlgo.DBExecutor, params map[string]any) (sql.Result, error) {
+	sqlText, args, err := s.Build(params)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := executor.ExecContext(ctx, sqlText, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.Name(), err)
+	}
+
+	return result, nil
+}
+
+// Query runs the statement and returns every row as a map keyed by column name.
+// []byte values are returned as strings.
+func (s *Statement) Query(ctx context.Context, executor snapsqlgo.DBExecutor, params map[string]any) ([]map[string]any, error) {
+	sqlText, args, err := s.Build(params)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := executor.QueryContext(ctx, sqlText, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.Name(), err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", s.Name(), err)
+	}
+
+	var result []map[string]any
+
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, fmt.Errorf("%s: failed to scan row: %w", s.Name(), err)
+		}
+
+		row := make(map[string]any, len(columns))
+		for i, column := range columns {
+			if b, ok := values[i].([]byte); ok {
+				row[column] = string(b)
+			} else {
+				row[column] = values[i]
+			}
+		}
+
+		result = append(result, row)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", s.Name(), err)
+	}
+
+	return result, nil
+}
+
+// QueryOne runs the statement and returns its first row, or ErrNoRows.
+func (s *Statement) QueryOne(ctx context.Context, executor snapsqlgo.DBExecutor, params map[string]any) (map[string]any, error) {
+	rows, err := s.Query(ctx, executor, params)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoRows, s.Name())
+	}
+
+	return rows[0], nil
+}
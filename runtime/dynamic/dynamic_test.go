@@ -0,0 +1,166 @@
+package dynamic_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+	"github.com/shibukawa/snapsql/runtime/dynamic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, age INTEGER NOT NULL);
+		INSERT INTO users (id, name, age) VALUES (1, 'alice', 30), (2, 'bob', 17), (3, 'carol', 45);`)
+	require.NoError(t, err)
+
+	return db
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+// listUsersFormat corresponds to:
+//
+//	SELECT id, name FROM users WHERE age >= /*= min_age */0 /*# if filter.name != "" */AND name = /*= filter.name */'' /*# end */ORDER BY id
+func listUsersFormat() *intermediate.IntermediateFormat {
+	return &intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "list_users",
+		StatementType:    "select",
+		ResponseAffinity: "many",
+		Parameters: []intermediate.Parameter{
+			{Name: "min_age", Type: "int"},
+			{Name: "filter", Type: "object", Optional: true},
+		},
+		CELExpressions: []intermediate.CELExpression{
+			{ID: "expr_001", Expression: "min_age"},
+			{ID: "expr_002", Expression: `has(filter.name) && filter.name != ""`},
+			{ID: "expr_003", Expression: "filter.name"},
+		},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Value: "SELECT id, name FROM users WHERE age >= "},
+			{Op: intermediate.OpEmitEval, ExprIndex: intPtr(0)},
+			{Op: intermediate.OpIf, ExprIndex: intPtr(1)},
+			{Op: intermediate.OpEmitStatic, Value: " AND name = "},
+			{Op: intermediate.OpEmitEval, ExprIndex: intPtr(2)},
+			{Op: intermediate.OpEnd},
+			{Op: intermediate.OpEmitStatic, Value: " ORDER BY id"},
+		},
+	}
+}
+
+func TestStatementQuery(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	stmt := dynamic.New(listUsersFormat(), snapsql.DialectSQLite)
+
+	assert.Equal(t, "list_users", stmt.Name())
+	assert.True(t, stmt.Returns())
+
+	rows, err := stmt.Query(ctx, db, map[string]any{"min_age": 18, "filter": map[string]any{}})
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{"id": int64(1), "name": "alice"},
+		{"id": int64(3), "name": "carol"},
+	}, rows)
+
+	row, err := stmt.QueryOne(ctx, db, map[string]any{"min_age": 18, "filter": map[string]any{"name": "carol"}})
+	require.NoError(t, err)
+	assert.Equal(t, "carol", row["name"])
+
+	_, err = stmt.QueryOne(ctx, db, map[string]any{"min_age": 50, "filter": map[string]any{}})
+	require.ErrorIs(t, err, dynamic.ErrNoRows)
+
+	_, err = stmt.Query(ctx, db, map[string]any{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "min_age")
+}
+
+func TestStatementBuildPlaceholders(t *testing.T) {
+	stmt := dynamic.New(listUsersFormat(), snapsql.DialectPostgres)
+
+	sqlText, args, err := stmt.Build(map[string]any{"min_age": 18, "filter": map[string]any{"name": "bob"}})
+	require.NoError(t, err)
+	assert.Contains(t, sqlText, "age >= $1")
+	assert.Contains(t, sqlText, "name = $2")
+	assert.Equal(t, []any{18, "bob"}, args)
+
+	data, err := listUsersFormat().ToBinary()
+	require.NoError(t, err)
+
+	stmt, err = dynamic.Parse(data, snapsql.DialectPostgres)
+	require.NoError(t, err)
+
+	binarySQL, binaryArgs, err := stmt.Build(map[string]any{"min_age": 18, "filter": map[string]any{"name": "bob"}})
+	require.NoError(t, err)
+	assert.Equal(t, sqlText, binarySQL)
+	assert.Equal(t, args, binaryArgs)
+}
+
+func TestStatementExec(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	format := &intermediate.IntermediateFormat{
+		FormatVersion:    "1",
+		FunctionName:     "delete_users",
+		StatementType:    "delete",
+		ResponseAffinity: "none",
+		Parameters:       []intermediate.Parameter{{Name: "max_age", Type: "int", Optional: true}},
+		CELExpressions:   []intermediate.CELExpression{{ID: "expr_001", Expression: "max_age > 0"}, {ID: "expr_002", Expression: "max_age"}},
+		Instructions: []intermediate.Instruction{
+			{Op: intermediate.OpEmitStatic, Value: "DELETE FROM users"},
+			{Op: intermediate.OpIf, ExprIndex: intPtr(0)},
+			{Op: intermediate.OpEmitStatic, Value: " WHERE age < "},
+			{Op: intermediate.OpEmitEval, ExprIndex: intPtr(1)},
+			{Op: intermediate.OpEnd},
+		},
+	}
+
+	data, err := json.Marshal(format)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "delete_users.json")
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	stmt, err := dynamic.Load(path, snapsql.DialectSQLite)
+	require.NoError(t, err)
+	assert.False(t, stmt.Returns())
+
+	result, err := stmt.Exec(ctx, db, map[string]any{"max_age": 18})
+	require.NoError(t, err)
+
+	affected, err := result.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), affected)
+
+	_, err = stmt.Exec(ctx, db, map[string]any{"max_age": 0})
+	require.ErrorIs(t, err, dynamic.ErrDangerousQuery)
+
+	stmt, err = dynamic.Load(path, snapsql.DialectSQLite, dynamic.AllowDangerousQuery())
+	require.NoError(t, err)
+
+	result, err = stmt.Exec(ctx, db, map[string]any{"max_age": 0})
+	require.NoError(t, err)
+
+	affected, err = result.RowsAffected()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), affected)
+}
@@ -2,15 +2,17 @@ package snapsql
 
 // ColumnInfo is a unified column definition for schema, type inference, etc.
 type ColumnInfo struct {
-	Name         string `json:"name" yaml:"name"`                     // Column name
-	DataType     string `json:"data_type" yaml:"data_type"`           // Normalized type (snapsql type)
-	Nullable     bool   `json:"nullable" yaml:"nullable"`             // Is nullable
-	DefaultValue string `json:"default_value" yaml:"default_value"`   // Default value (optional)
-	Comment      string `json:"comment" yaml:"comment"`               // Comment (optional)
-	IsPrimaryKey bool   `json:"is_primary_key" yaml:"is_primary_key"` // Is primary key (optional)
-	MaxLength    *int   `json:"max_length" yaml:"max_length"`         // For string types (optional)
-	Precision    *int   `json:"precision" yaml:"precision"`           // For numeric types (optional)
-	Scale        *int   `json:"scale" yaml:"scale"`                   // For numeric types (optional)
+	Name         string   `json:"name" yaml:"name"`                                       // Column name
+	DataType     string   `json:"data_type" yaml:"data_type"`                             // Normalized type (snapsql type)
+	DatabaseType string   `json:"database_type,omitempty" yaml:"database_type,omitempty"` // Type name in the database, e.g. uuid or an enum type (optional)
+	EnumValues   []string `json:"enum_values,omitempty" yaml:"enum_values,omitempty"`     // Allowed values of an enum column (optional)
+	Nullable     bool     `json:"nullable" yaml:"nullable"`                               // Is nullable
+	DefaultValue string   `json:"default_value" yaml:"default_value"`                     // Default value (optional)
+	Comment      string   `json:"comment" yaml:"comment"`                                 // Comment (optional)
+	IsPrimaryKey bool     `json:"is_primary_key" yaml:"is_primary_key"`                   // Is primary key (optional)
+	MaxLength    *int     `json:"max_length" yaml:"max_length"`                           // For string types (optional)
+	Precision    *int     `json:"precision" yaml:"precision"`                             // For numeric types (optional)
+	Scale        *int     `json:"scale" yaml:"scale"`                                     // For numeric types (optional)
 }
 
 // TableInfo is a unified table definition
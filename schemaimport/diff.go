@@ -0,0 +1,168 @@
+package schemaimport
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	snapsql "github.com/shibukawa/snapsql"
+)
+
+// DifferenceKind classifies a single schema difference.
+type DifferenceKind string
+
+const (
+	// DiffMissingTable means the snapshot has a table the database lacks.
+	DiffMissingTable DifferenceKind = "missing_table"
+	// DiffExtraTable means the database has a table the snapshot lacks.
+	DiffExtraTable DifferenceKind = "extra_table"
+	// DiffMissingColumn means the snapshot has a column the database lacks.
+	DiffMissingColumn DifferenceKind = "missing_column"
+	// DiffExtraColumn means the database has a column the snapshot lacks.
+	DiffExtraColumn DifferenceKind = "extra_column"
+	// DiffTypeMismatch means the normalized column types differ.
+	DiffTypeMismatch DifferenceKind = "type_mismatch"
+	// DiffNullableMismatch means the column nullability differs.
+	DiffNullableMismatch DifferenceKind = "nullable_mismatch"
+	// DiffPrimaryKeyMismatch means the column is a primary key on only one side.
+	DiffPrimaryKeyMismatch DifferenceKind = "primary_key_mismatch"
+)
+
+// Difference describes one mismatch between the schema snapshot and the live database.
+// Expected holds the snapshot value and Actual the database value.
+type Difference struct {
+	Kind     DifferenceKind
+	Table    string
+	Column   string
+	Expected string
+	Actual   string
+}
+
+// String renders the difference as a single human readable line.
+func (d Difference) String() string {
+	switch d.Kind {
+	case DiffMissingTable:
+		return fmt.Sprintf("table %s: missing in database", d.Table)
+	case DiffExtraTable:
+		return fmt.Sprintf("table %s: not in schema snapshot", d.Table)
+	case DiffMissingColumn:
+		return fmt.Sprintf("column %s.%s: missing in database", d.Table, d.Column)
+	case DiffExtraColumn:
+		return fmt.Sprintf("column %s.%s: not in schema snapshot", d.Table, d.Column)
+	case DiffTypeMismatch:
+		return fmt.Sprintf("column %s.%s: type %s in snapshot, %s in database", d.Table, d.Column, d.Expected, d.Actual)
+	case DiffNullableMismatch:
+		return fmt.Sprintf("column %s.%s: nullable %s in snapshot, %s in database", d.Table, d.Column, d.Expected, d.Actual)
+	case DiffPrimaryKeyMismatch:
+		return fmt.Sprintf("column %s.%s: primary key %s in snapshot, %s in database", d.Table, d.Column, d.Expected, d.Actual)
+	default:
+		return fmt.Sprintf("%s %s.%s", d.Kind, d.Table, d.Column)
+	}
+}
+
+// DiffTables compares the snapshot tables against the tables read from the database.
+// The snapshot map may contain the same table under both its plain and schema-qualified
+// names (see Runtime.TablesByName); each table is compared once by its plain name.
+// Differences are returned sorted by table and column.
+func DiffTables(snapshot map[string]*snapsql.TableInfo, live []*snapsql.TableInfo) []Difference {
+	expected := tablesByPlainName(snapshot)
+
+	actual := make(map[string]*snapsql.TableInfo, len(live))
+	for _, tbl := range live {
+		if tbl != nil {
+			actual[strings.ToLower(tbl.Name)] = tbl
+		}
+	}
+
+	var diffs []Difference
+
+	for key, want := range expected {
+		got, ok := actual[key]
+		if !ok {
+			diffs = append(diffs, Difference{Kind: DiffMissingTable, Table: want.Name})
+			continue
+		}
+
+		diffs = append(diffs, diffColumns(want, got)...)
+	}
+
+	for key, got := range actual {
+		if _, ok := expected[key]; !ok {
+			diffs = append(diffs, Difference{Kind: DiffExtraTable, Table: got.Name})
+		}
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		if diffs[i].Table != diffs[j].Table {
+			return diffs[i].Table < diffs[j].Table
+		}
+
+		if diffs[i].Column != diffs[j].Column {
+			return diffs[i].Column < diffs[j].Column
+		}
+
+		return diffs[i].Kind < diffs[j].Kind
+	})
+
+	return diffs
+}
+
+func tablesByPlainName(tables map[string]*snapsql.TableInfo) map[string]*snapsql.TableInfo {
+	result := make(map[string]*snapsql.TableInfo, len(tables))
+
+	for _, tbl := range tables {
+		if tbl == nil {
+			continue
+		}
+
+		name := tbl.Name
+		if idx := strings.LastIndex(name, "."); idx >= 0 {
+			name = name[idx+1:]
+		}
+
+		result[strings.ToLower(name)] = tbl
+	}
+
+	return result
+}
+
+func diffColumns(want, got *snapsql.TableInfo) []Difference {
+	var diffs []Difference
+
+	actual := make(map[string]*snapsql.ColumnInfo, len(got.Columns))
+	for name, col := range got.Columns {
+		actual[strings.ToLower(name)] = col
+	}
+
+	expected := make(map[string]*snapsql.ColumnInfo, len(want.Columns))
+
+	for name, wantCol := range want.Columns {
+		key := strings.ToLower(name)
+		expected[key] = wantCol
+
+		gotCol, ok := actual[key]
+		if !ok {
+			diffs = append(diffs, Difference{Kind: DiffMissingColumn, Table: want.Name, Column: name})
+			continue
+		}
+
+		if wantCol.DataType != gotCol.DataType {
+			diffs = append(diffs, Difference{Kind: DiffTypeMismatch, Table: want.Name, Column: name, Expected: wantCol.DataType, Actual: gotCol.DataType})
+		}
+
+		if wantCol.IsPrimaryKey != gotCol.IsPrimaryKey {
+			diffs = append(diffs, Difference{Kind: DiffPrimaryKeyMismatch, Table: want.Name, Column: name, Expected: fmt.Sprint(wantCol.IsPrimaryKey), Actual: fmt.Sprint(gotCol.IsPrimaryKey)})
+		} else if !wantCol.IsPrimaryKey && wantCol.Nullable != gotCol.Nullable {
+			// Primary keys are implicitly NOT NULL; drivers disagree on how they report it.
+			diffs = append(diffs, Difference{Kind: DiffNullableMismatch, Table: want.Name, Column: name, Expected: fmt.Sprint(wantCol.Nullable), Actual: fmt.Sprint(gotCol.Nullable)})
+		}
+	}
+
+	for name := range got.Columns {
+		if _, ok := expected[strings.ToLower(name)]; !ok {
+			diffs = append(diffs, Difference{Kind: DiffExtraColumn, Table: want.Name, Column: name})
+		}
+	}
+
+	return diffs
+}
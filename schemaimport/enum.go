@@ -0,0 +1,168 @@
+package schemaimport
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	tblsschema "github.com/k1LoW/tbls/schema"
+
+	snapsql "github.com/shibukawa/snapsql"
+)
+
+const postgresEnumsQuery = `SELECT t.typname, e.enumlabel
+FROM pg_type t
+JOIN pg_enum e ON e.enumtypid = t.oid
+ORDER BY t.typname, e.enumsortorder`
+
+const mysqlEnumColumnsQuery = `SELECT c.table_name, c.column_name, c.column_type
+FROM information_schema.columns c
+WHERE c.table_schema = DATABASE() AND c.data_type = 'enum'`
+
+// enumTypeKey returns the lookup key of an enum type name: lower-case and without its schema.
+func enumTypeKey(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if idx := strings.LastIndex(name, "."); idx >= 0 {
+		name = name[idx+1:]
+	}
+
+	return strings.Trim(name, `"`)
+}
+
+// collectEnums indexes the enum types of a tbls schema (Postgres) by enumTypeKey.
+func collectEnums(enums []*tblsschema.Enum) map[string][]string {
+	result := make(map[string][]string, len(enums))
+
+	for _, enum := range enums {
+		if enum == nil || len(enum.Values) == 0 {
+			continue
+		}
+
+		result[enumTypeKey(enum.Name)] = enum.Values
+	}
+
+	return result
+}
+
+// columnEnumValues returns the allowed values of a column typed as a named enum type (Postgres)
+// or as an inline enum('a', 'b') (MySQL), or nil for other columns.
+func columnEnumValues(rawType string, enums map[string][]string) []string {
+	if databaseTypeName(rawType) == "enum" {
+		return parseEnumLiteral(rawType)
+	}
+
+	return enums[enumTypeKey(rawType)]
+}
+
+// parseEnumLiteral reads the quoted values of a MySQL column type such as enum('todo','done').
+func parseEnumLiteral(rawType string) []string {
+	start := strings.Index(rawType, "(")
+	end := strings.LastIndex(rawType, ")")
+
+	if start < 0 || end <= start {
+		return nil
+	}
+
+	var (
+		values  []string
+		current strings.Builder
+		quoted  bool
+	)
+
+	body := rawType[start+1 : end]
+	for i := 0; i < len(body); i++ {
+		ch := body[i]
+
+		switch {
+		case !quoted && ch == '\'':
+			quoted = true
+
+			current.Reset()
+		case quoted && ch == '\'' && i+1 < len(body) && body[i+1] == '\'':
+			// '' は値の中のクォート
+			current.WriteByte('\'')
+
+			i++
+		case quoted && ch == '\'':
+			quoted = false
+
+			values = append(values, current.String())
+		case quoted:
+			current.WriteByte(ch)
+		}
+	}
+
+	return values
+}
+
+// introspectEnums fills EnumValues of the enum columns of introspected tables.
+func introspectEnums(ctx context.Context, db *sql.DB, driver string, tables []*snapsql.TableInfo) error {
+	switch driver {
+	case "postgres":
+		return introspectPostgresEnums(ctx, db, tables)
+	case "mysql":
+		return introspectMySQLEnums(ctx, db, tables)
+	}
+
+	return nil
+}
+
+func introspectPostgresEnums(ctx context.Context, db *sql.DB, tables []*snapsql.TableInfo) error {
+	rows, err := db.QueryContext(ctx, postgresEnumsQuery)
+	if err != nil {
+		return fmt.Errorf("schemaimport: query enums: %w", err)
+	}
+	defer rows.Close()
+
+	enums := make(map[string][]string)
+
+	for rows.Next() {
+		var name, value string
+		if err := rows.Scan(&name, &value); err != nil {
+			return fmt.Errorf("schemaimport: scan enum: %w", err)
+		}
+
+		key := enumTypeKey(name)
+		enums[key] = append(enums[key], value)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("schemaimport: read enums: %w", err)
+	}
+
+	for _, table := range tables {
+		for _, col := range table.Columns {
+			col.EnumValues = enums[enumTypeKey(col.DatabaseType)]
+		}
+	}
+
+	return nil
+}
+
+func introspectMySQLEnums(ctx context.Context, db *sql.DB, tables []*snapsql.TableInfo) error {
+	rows, err := db.QueryContext(ctx, mysqlEnumColumnsQuery)
+	if err != nil {
+		return fmt.Errorf("schemaimport: query enum columns: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tableName, columnName, columnType string
+		if err := rows.Scan(&tableName, &columnName, &columnType); err != nil {
+			return fmt.Errorf("schemaimport: scan enum column: %w", err)
+		}
+
+		for _, table := range tables {
+			if col, ok := table.Columns[columnName]; ok && table.Name == tableName {
+				col.EnumValues = parseEnumLiteral(columnType)
+			}
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("schemaimport: read enum columns: %w", err)
+	}
+
+	return nil
+}
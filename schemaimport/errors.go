@@ -23,4 +23,6 @@ var (
 	ErrSchemaTablesEmpty = errors.New("schemaimport: no tables present in schema")
 	// ErrTblsConfigNotFound indicates no tbls configuration file was discovered.
 	ErrTblsConfigNotFound = errors.New("schemaimport: no tbls config found")
+	// ErrIntrospectionUnsupported indicates live introspection is not available for the driver.
+	ErrIntrospectionUnsupported = errors.New("schemaimport: database introspection is not supported for driver")
 )
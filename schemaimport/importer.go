@@ -139,6 +139,8 @@ func (i *Importer) Convert(ctx context.Context) ([]snapsql.DatabaseSchema, error
 
 	i.logf("Converting schema for driver=%s tables=%d", driverName, len(i.schema.Tables))
 
+	enums := collectEnums(i.schema.Enums)
+
 	for _, tbl := range i.schema.Tables {
 		select {
 		case <-ctx.Done():
@@ -159,7 +161,7 @@ func (i *Importer) Convert(ctx context.Context) ([]snapsql.DatabaseSchema, error
 			schema.Views = append(schema.Views, view)
 		default:
 			schema := ensureDatabaseSchema(schemas, schemaName, dbInfo)
-			table := convertTable(tbl, schemaName, tableName, driverName)
+			table := convertTable(tbl, schemaName, tableName, driverName, enums)
 			schema.Tables = append(schema.Tables, table)
 		}
 	}
@@ -260,7 +262,7 @@ func ensureDatabaseSchema(target map[string]*snapsql.DatabaseSchema, schemaName
 	return schema
 }
 
-func convertTable(tbl *tblsschema.Table, schemaName, tableName, driver string) *snapsql.TableInfo {
+func convertTable(tbl *tblsschema.Table, schemaName, tableName, driver string, enums map[string][]string) *snapsql.TableInfo {
 	columns := make(map[string]*snapsql.ColumnInfo)
 	order := make([]string, 0, len(tbl.Columns))
 
@@ -272,6 +274,8 @@ func convertTable(tbl *tblsschema.Table, schemaName, tableName, driver string) *
 		columns[col.Name] = &snapsql.ColumnInfo{
 			Name:         col.Name,
 			DataType:     normalizeColumnType(col, driver),
+			DatabaseType: databaseTypeName(col.Type),
+			EnumValues:   columnEnumValues(col.Type, enums),
 			Nullable:     col.Nullable,
 			DefaultValue: nullStringValue(col.Default),
 			Comment:      col.Comment,
@@ -401,31 +405,7 @@ func normalizeColumnType(col *tblsschema.Column, driver string) string {
 		return ""
 	}
 
-	raw := strings.TrimSpace(col.Type)
-
-	normalized := strings.ToLower(raw)
-	if idx := strings.Index(normalized, "("); idx >= 0 {
-		normalized = normalized[:idx]
-	}
-
-	normalized = strings.TrimSpace(normalized)
-
-	switch strings.ToLower(driver) {
-	case "postgres", "postgresql":
-		if snap := mapPostgresType(normalized); snap != "" {
-			return snap
-		}
-	case "mysql":
-		if snap := mapMySQLType(normalized); snap != "" {
-			return snap
-		}
-	case "sqlite", "sqlite3":
-		if snap := mapSQLiteType(normalized); snap != "" {
-			return snap
-		}
-	}
-
-	return inferGenericType(normalized)
+	return normalizeRawType(col.Type, driver)
 }
 
 func mapPostgresType(t string) string {
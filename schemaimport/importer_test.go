@@ -3,6 +3,7 @@ package schemaimport
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	tblsconfig "github.com/k1LoW/tbls/config"
@@ -215,6 +216,59 @@ func TestConvertAppliesDriverSpecificTypeMapping(t *testing.T) {
 	}
 }
 
+func TestConvertReadsEnumValues(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name   string
+		schema string
+	}{
+		{"postgres", `{"driver":{"name":"postgres"},"tables":[{"name":"public.tasks","type":"TABLE","columns":[{"name":"id","type":"integer"},{"name":"status","type":"task_status"}]}],"enums":[{"name":"public.task_status","values":["todo","doing","done"]}]}`},
+		{"mysql", `{"driver":{"name":"mysql"},"tables":[{"name":"tasks","type":"TABLE","columns":[{"name":"id","type":"int"},{"name":"status","type":"enum('todo','doing','done')"}]}]}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			schemaPath := filepath.Join(t.TempDir(), "schema.json")
+			if err := os.WriteFile(schemaPath, []byte(tc.schema), 0o644); err != nil {
+				t.Fatalf("write schema: %v", err)
+			}
+
+			importer := NewImporter(NewConfig(Options{SchemaJSONPath: schemaPath}))
+			if err := importer.LoadSchemaJSON(t.Context()); err != nil {
+				t.Fatalf("LoadSchemaJSON returned error: %v", err)
+			}
+
+			schemas, err := importer.Convert(t.Context())
+			if err != nil {
+				t.Fatalf("Convert failed: %v", err)
+			}
+
+			cols := schemas[0].Tables[0].Columns
+			if got := strings.Join(cols["status"].EnumValues, ","); got != "todo,doing,done" {
+				t.Fatalf("expected status enum values, got %q", got)
+			}
+
+			if cols["status"].DataType != snapTypeString {
+				t.Fatalf("expected status to map to string, got %s", cols["status"].DataType)
+			}
+
+			if cols["id"].EnumValues != nil {
+				t.Fatalf("expected no enum values for id, got %v", cols["id"].EnumValues)
+			}
+		})
+	}
+}
+
+func TestParseEnumLiteral(t *testing.T) {
+	t.Parallel()
+
+	got := parseEnumLiteral(`enum('a','it''s','c,d')`)
+	if strings.Join(got, "|") != "a|it's|c,d" {
+		t.Fatalf("unexpected values: %q", got)
+	}
+}
+
 func TestConvertMarksPrimaryKeysFromConstraints(t *testing.T) {
 	t.Parallel()
 
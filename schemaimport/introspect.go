@@ -0,0 +1,214 @@
+package schemaimport
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	snapsql "github.com/shibukawa/snapsql"
+)
+
+// IntrospectDatabase reads table and column metadata directly from a live database.
+// The result uses the same type normalization as the tbls importer so it can be compared
+// with (or written in place of) the converted schema JSON.
+func IntrospectDatabase(ctx context.Context, db *sql.DB, driver string) ([]*snapsql.TableInfo, error) {
+	switch normalizeDriverName(driver) {
+	case "postgres":
+		return introspectInformationSchema(ctx, db, "postgres", postgresColumnsQuery)
+	case "mysql":
+		return introspectInformationSchema(ctx, db, "mysql", mysqlColumnsQuery)
+	case "sqlite":
+		return introspectSQLite(ctx, db)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrIntrospectionUnsupported, driver)
+	}
+}
+
+const postgresColumnsQuery = `SELECT c.table_schema, c.table_name, c.column_name,
+       CASE WHEN c.data_type = 'ARRAY' THEN ltrim(c.udt_name, '_') || '[]'
+            WHEN c.data_type = 'USER-DEFINED' THEN c.udt_name
+            ELSE c.data_type END,
+       c.is_nullable = 'YES',
+       COALESCE(c.column_default, ''),
+       EXISTS (
+           SELECT 1 FROM information_schema.table_constraints tc
+           JOIN information_schema.key_column_usage kcu
+             ON tc.constraint_name = kcu.constraint_name AND tc.table_schema = kcu.table_schema
+           WHERE tc.constraint_type = 'PRIMARY KEY'
+             AND tc.table_schema = c.table_schema AND tc.table_name = c.table_name
+             AND kcu.column_name = c.column_name
+       )
+FROM information_schema.columns c
+JOIN information_schema.tables t ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+WHERE t.table_type = 'BASE TABLE' AND c.table_schema NOT IN ('pg_catalog', 'information_schema')
+ORDER BY c.table_schema, c.table_name, c.ordinal_position`
+
+const mysqlColumnsQuery = `SELECT c.table_schema, c.table_name, c.column_name, c.data_type, c.is_nullable = 'YES',
+       COALESCE(c.column_default, ''), c.column_key = 'PRI'
+FROM information_schema.columns c
+JOIN information_schema.tables t ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+WHERE t.table_type = 'BASE TABLE' AND c.table_schema = DATABASE()
+ORDER BY c.table_name, c.ordinal_position`
+
+func introspectInformationSchema(ctx context.Context, db *sql.DB, driver, query string) ([]*snapsql.TableInfo, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("schemaimport: query columns: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []*snapsql.TableInfo
+
+	byName := make(map[string]*snapsql.TableInfo)
+
+	for rows.Next() {
+		var (
+			schemaName, tableName, columnName, dataType, defaultValue string
+			nullable, primaryKey                                      bool
+		)
+
+		if err := rows.Scan(&schemaName, &tableName, &columnName, &dataType, &nullable, &defaultValue, &primaryKey); err != nil {
+			return nil, fmt.Errorf("schemaimport: scan column: %w", err)
+		}
+
+		key := schemaName + "." + tableName
+
+		table, ok := byName[key]
+		if !ok {
+			table = &snapsql.TableInfo{Name: tableName, Schema: schemaName, Columns: make(map[string]*snapsql.ColumnInfo)}
+			byName[key] = table
+			tables = append(tables, table)
+		}
+
+		addIntrospectedColumn(table, &snapsql.ColumnInfo{
+			Name:         columnName,
+			DataType:     normalizeRawType(dataType, driver),
+			DatabaseType: databaseTypeName(dataType),
+			Nullable:     nullable,
+			DefaultValue: defaultValue,
+			IsPrimaryKey: primaryKey,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("schemaimport: read columns: %w", err)
+	}
+
+	if err := introspectEnums(ctx, db, driver, tables); err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+func introspectSQLite(ctx context.Context, db *sql.DB) ([]*snapsql.TableInfo, error) {
+	rows, err := db.QueryContext(ctx, `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("schemaimport: query tables: %w", err)
+	}
+
+	var names []string
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("schemaimport: scan table: %w", err)
+		}
+
+		names = append(names, name)
+	}
+
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("schemaimport: read tables: %w", err)
+	}
+
+	tables := make([]*snapsql.TableInfo, 0, len(names))
+
+	for _, name := range names {
+		table, err := introspectSQLiteTable(ctx, db, name)
+		if err != nil {
+			return nil, err
+		}
+
+		tables = append(tables, table)
+	}
+
+	return tables, nil
+}
+
+func introspectSQLiteTable(ctx context.Context, db *sql.DB, name string) (*snapsql.TableInfo, error) {
+	rows, err := db.QueryContext(ctx, `SELECT name, type, "notnull", COALESCE(dflt_value, ''), pk FROM pragma_table_info(?)`, name)
+	if err != nil {
+		return nil, fmt.Errorf("schemaimport: query columns of %s: %w", name, err)
+	}
+	defer rows.Close()
+
+	table := &snapsql.TableInfo{Name: name, Columns: make(map[string]*snapsql.ColumnInfo)}
+
+	for rows.Next() {
+		var (
+			columnName, dataType, defaultValue string
+			notNull, pk                        int
+		)
+
+		if err := rows.Scan(&columnName, &dataType, &notNull, &defaultValue, &pk); err != nil {
+			return nil, fmt.Errorf("schemaimport: scan column of %s: %w", name, err)
+		}
+
+		addIntrospectedColumn(table, &snapsql.ColumnInfo{
+			Name:         columnName,
+			DataType:     normalizeRawType(dataType, "sqlite"),
+			DatabaseType: databaseTypeName(dataType),
+			Nullable:     notNull == 0 && pk == 0,
+			DefaultValue: defaultValue,
+			IsPrimaryKey: pk > 0,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("schemaimport: read columns of %s: %w", name, err)
+	}
+
+	return table, nil
+}
+
+func addIntrospectedColumn(table *snapsql.TableInfo, col *snapsql.ColumnInfo) {
+	table.Columns[col.Name] = col
+	table.ColumnOrder = append(table.ColumnOrder, col.Name)
+}
+
+// databaseTypeName returns the lower-case type name of a database column without its length or precision
+func databaseTypeName(raw string) string {
+	normalized := strings.ToLower(strings.TrimSpace(raw))
+	if idx := strings.Index(normalized, "("); idx >= 0 {
+		normalized = strings.TrimSpace(normalized[:idx])
+	}
+
+	return normalized
+}
+
+// normalizeRawType maps a database type name to a SnapSQL type using the importer's per-driver tables.
+func normalizeRawType(raw, driver string) string {
+	normalized := databaseTypeName(raw)
+
+	var snap string
+
+	switch normalizeDriverName(driver) {
+	case "postgres":
+		snap = mapPostgresType(normalized)
+	case "mysql":
+		snap = mapMySQLType(normalized)
+	case "sqlite":
+		snap = mapSQLiteType(normalized)
+	}
+
+	if snap != "" {
+		return snap
+	}
+
+	return inferGenericType(normalized)
+}
@@ -0,0 +1,131 @@
+package schemaimport
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	snapsql "github.com/shibukawa/snapsql"
+)
+
+func openIntrospectionDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	ddl := `CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, email VARCHAR(255), created_at DATETIME);
+CREATE TABLE audit_logs (id INTEGER PRIMARY KEY, payload TEXT)`
+	if _, err := db.ExecContext(t.Context(), ddl); err != nil {
+		t.Fatalf("create tables: %v", err)
+	}
+
+	return db
+}
+
+func TestIntrospectDatabaseSQLite(t *testing.T) {
+	db := openIntrospectionDB(t)
+
+	tables, err := IntrospectDatabase(t.Context(), db, "sqlite3")
+	if err != nil {
+		t.Fatalf("IntrospectDatabase returned error: %v", err)
+	}
+
+	if len(tables) != 2 {
+		t.Fatalf("expected two tables, got %d", len(tables))
+	}
+
+	users := tables[1]
+	if users.Name != "users" {
+		t.Fatalf("expected tables ordered by name, got %s", users.Name)
+	}
+
+	if got := users.ColumnOrder; len(got) != 4 || got[0] != "id" || got[3] != "created_at" {
+		t.Fatalf("unexpected column order: %v", got)
+	}
+
+	if col := users.Columns["id"]; col.DataType != snapTypeInt || !col.IsPrimaryKey || col.Nullable {
+		t.Fatalf("unexpected id column: %+v", col)
+	}
+
+	if col := users.Columns["name"]; col.DataType != snapTypeString || col.Nullable {
+		t.Fatalf("unexpected name column: %+v", col)
+	}
+
+	if col := users.Columns["created_at"]; col.DataType != snapTypeDateTime || !col.Nullable {
+		t.Fatalf("unexpected created_at column: %+v", col)
+	}
+}
+
+func TestIntrospectDatabaseUnsupportedDriver(t *testing.T) {
+	if _, err := IntrospectDatabase(t.Context(), nil, "oracle"); err == nil {
+		t.Fatalf("expected error for unsupported driver")
+	}
+}
+
+func TestDiffTablesReportsDrift(t *testing.T) {
+	db := openIntrospectionDB(t)
+
+	live, err := IntrospectDatabase(t.Context(), db, "sqlite")
+	if err != nil {
+		t.Fatalf("IntrospectDatabase returned error: %v", err)
+	}
+
+	users := &snapsql.TableInfo{
+		Name:   "users",
+		Schema: "main",
+		Columns: map[string]*snapsql.ColumnInfo{
+			"id":         {Name: "id", DataType: snapTypeInt, IsPrimaryKey: true},
+			"name":       {Name: "name", DataType: snapTypeString},
+			"email":      {Name: "email", DataType: snapTypeInt, Nullable: true},
+			"created_at": {Name: "created_at", DataType: snapTypeDateTime, Nullable: true},
+			"deleted_at": {Name: "deleted_at", DataType: snapTypeDateTime, Nullable: true},
+		},
+	}
+	snapshot := map[string]*snapsql.TableInfo{
+		"users":      users,
+		"main.users": users,
+		"orders":     {Name: "orders", Columns: map[string]*snapsql.ColumnInfo{}},
+	}
+
+	diffs := DiffTables(snapshot, live)
+
+	want := []Difference{
+		{Kind: DiffExtraTable, Table: "audit_logs"},
+		{Kind: DiffMissingTable, Table: "orders"},
+		{Kind: DiffMissingColumn, Table: "users", Column: "deleted_at"},
+		{Kind: DiffTypeMismatch, Table: "users", Column: "email", Expected: snapTypeInt, Actual: snapTypeString},
+	}
+
+	if len(diffs) != len(want) {
+		t.Fatalf("expected %d differences, got %d: %v", len(want), len(diffs), diffs)
+	}
+
+	for i := range want {
+		if diffs[i] != want[i] {
+			t.Fatalf("difference %d: expected %+v, got %+v", i, want[i], diffs[i])
+		}
+	}
+}
+
+func TestDiffTablesNoDrift(t *testing.T) {
+	db := openIntrospectionDB(t)
+
+	live, err := IntrospectDatabase(t.Context(), db, "sqlite")
+	if err != nil {
+		t.Fatalf("IntrospectDatabase returned error: %v", err)
+	}
+
+	snapshot := make(map[string]*snapsql.TableInfo)
+	for _, tbl := range live {
+		snapshot[tbl.Name] = tbl
+	}
+
+	if diffs := DiffTables(snapshot, live); len(diffs) != 0 {
+		t.Fatalf("expected no differences, got %v", diffs)
+	}
+}
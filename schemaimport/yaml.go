@@ -0,0 +1,96 @@
+package schemaimport
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/goccy/go-yaml"
+	snapsql "github.com/shibukawa/snapsql"
+)
+
+// schemaFile is the on-disk layout of the snapsql schema YAML written by `snapsql schema pull`.
+type schemaFile struct {
+	Schemas []snapsql.DatabaseSchema `yaml:"schemas"`
+}
+
+// BuildDatabaseSchemas groups introspected tables by schema name.
+// Schemas and tables are sorted by name so the written file is stable between runs.
+func BuildDatabaseSchemas(tables []*snapsql.TableInfo, driver string) []snapsql.DatabaseSchema {
+	grouped := make(map[string]*snapsql.DatabaseSchema)
+
+	var names []string
+
+	for _, tbl := range tables {
+		if tbl == nil {
+			continue
+		}
+
+		schema, ok := grouped[tbl.Schema]
+		if !ok {
+			schema = &snapsql.DatabaseSchema{
+				Name:         tbl.Schema,
+				DatabaseInfo: snapsql.DatabaseInfo{Type: normalizeDriverName(driver), Name: tbl.Schema},
+			}
+			grouped[tbl.Schema] = schema
+			names = append(names, tbl.Schema)
+		}
+
+		schema.Tables = append(schema.Tables, tbl)
+	}
+
+	sort.Strings(names)
+
+	schemas := make([]snapsql.DatabaseSchema, 0, len(names))
+
+	for _, name := range names {
+		schema := grouped[name]
+		sort.Slice(schema.Tables, func(i, j int) bool { return schema.Tables[i].Name < schema.Tables[j].Name })
+		schemas = append(schemas, *schema)
+	}
+
+	return schemas
+}
+
+// WriteSchemaYAML writes schemas to path, creating parent directories as needed.
+func WriteSchemaYAML(path string, schemas []snapsql.DatabaseSchema) error {
+	data, err := yaml.Marshal(schemaFile{Schemas: schemas})
+	if err != nil {
+		return fmt.Errorf("schemaimport: encode schema YAML: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("schemaimport: create schema directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("schemaimport: write schema YAML: %w", err)
+	}
+
+	return nil
+}
+
+// LoadSchemaYAML reads a schema YAML written by WriteSchemaYAML and wraps it in a Runtime
+// so callers can use the same lookups as for tbls-derived schemas.
+func LoadSchemaYAML(path string) (*Runtime, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("schemaimport: read schema YAML: %w", err)
+	}
+
+	var file schemaFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("schemaimport: decode schema YAML %s: %w", path, err)
+	}
+
+	for _, schema := range file.Schemas {
+		for _, tbl := range schema.Tables {
+			if tbl != nil && tbl.Columns == nil {
+				tbl.Columns = make(map[string]*snapsql.ColumnInfo)
+			}
+		}
+	}
+
+	return &Runtime{Schemas: file.Schemas}, nil
+}
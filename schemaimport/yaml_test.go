@@ -0,0 +1,50 @@
+package schemaimport
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestSchemaYAMLRoundTrip(t *testing.T) {
+	db := openIntrospectionDB(t)
+
+	tables, err := IntrospectDatabase(t.Context(), db, "sqlite")
+	if err != nil {
+		t.Fatalf("IntrospectDatabase returned error: %v", err)
+	}
+
+	schemas := BuildDatabaseSchemas(tables, "sqlite3")
+	if len(schemas) != 1 || schemas[0].DatabaseInfo.Type != "sqlite" {
+		t.Fatalf("unexpected schemas: %+v", schemas)
+	}
+
+	path := filepath.Join(t.TempDir(), ".snapsql", "schema.yaml")
+	if err := WriteSchemaYAML(path, schemas); err != nil {
+		t.Fatalf("WriteSchemaYAML returned error: %v", err)
+	}
+
+	runtime, err := LoadSchemaYAML(path)
+	if err != nil {
+		t.Fatalf("LoadSchemaYAML returned error: %v", err)
+	}
+
+	loaded := runtime.TablesByName()
+	if diffs := DiffTables(loaded, tables); len(diffs) != 0 {
+		t.Fatalf("expected round trip without differences, got %v", diffs)
+	}
+
+	users := loaded["users"]
+	if users == nil {
+		t.Fatalf("expected users table in lookup")
+	}
+
+	if len(users.ColumnOrder) != 4 || users.ColumnOrder[1] != "name" {
+		t.Fatalf("unexpected column order: %v", users.ColumnOrder)
+	}
+}
+
+func TestLoadSchemaYAMLMissingFile(t *testing.T) {
+	if _, err := LoadSchemaYAML(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatalf("expected error for missing schema YAML")
+	}
+}
@@ -0,0 +1,14 @@
+/*#
+function_name: updateUser
+parameters:
+  user_id: int
+  name: string
+  email: string
+  active: bool
+*/
+UPDATE users
+SET /*# set_optional */
+    name = /*= name */'John Doe',
+    email = /*= email */'john@example.com',
+    active = /*= active */true
+WHERE id = /*= user_id */1
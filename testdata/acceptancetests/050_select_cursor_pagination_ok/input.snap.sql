@@ -0,0 +1,12 @@
+/*#
+function_name: listUsers
+pagination: cursor
+parameters:
+  status: string
+  page_size: int
+*/
+SELECT id, name, created_at
+FROM users
+WHERE status = /*= status */'active'
+ORDER BY created_at DESC, id DESC
+LIMIT /*= page_size */20
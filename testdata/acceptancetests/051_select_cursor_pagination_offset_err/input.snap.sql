@@ -0,0 +1,8 @@
+/*#
+function_name: listUsers
+pagination: cursor
+*/
+SELECT id, name
+FROM users
+ORDER BY id
+LIMIT 20 OFFSET 40
@@ -0,0 +1,11 @@
+/*#
+function_name: search_users
+parameters:
+  status: string
+  size: int
+*/
+SELECT id, name
+FROM users
+WHERE status = /*= status */'active'
+ORDER BY id
+LIMIT /*= size */20
@@ -0,0 +1,19 @@
+/*#
+function_name: list_order_trees
+*/
+SELECT
+    o.id,
+    o.customer,
+    i.id AS items__id,
+    i.product AS items__product,
+    i.quantity AS items__quantity,
+    io.item_id AS items__options__item_id,
+    io.code AS items__options__code,
+    io.value AS items__options__value,
+    p.id AS payments__id,
+    p.amount AS payments__amount
+FROM orders o
+LEFT JOIN items i ON i.order_id = o.id
+LEFT JOIN item_options io ON io.item_id = i.id
+LEFT JOIN payments p ON p.order_id = o.id
+ORDER BY o.id, i.id, io.code, p.id
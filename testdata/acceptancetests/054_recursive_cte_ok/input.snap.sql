@@ -0,0 +1,13 @@
+/*#
+function_name: list_category_tree
+parameters:
+  root_id: int
+*/
+WITH RECURSIVE tree (id, parent_id, name, depth) AS (
+    SELECT id, parent_id, name, 1 FROM categories WHERE id = /*= root_id */1
+    UNION ALL
+    SELECT c.id, c.parent_id, c.name, t.depth + 1
+    FROM categories c
+    JOIN tree t ON c.parent_id = t.id
+)
+SELECT id, parent_id, name, depth FROM tree ORDER BY depth, id
@@ -0,0 +1,271 @@
+package testrunner
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/shibukawa/snapsql/testrunner/fixtureexecutor"
+)
+
+// BaselineVersion is the current baseline file format version
+const BaselineVersion = 1
+
+var (
+	ErrPerformanceRegression   = errors.New("performance regression")
+	ErrUnsupportedBaselineFile = errors.New("unsupported baseline file version")
+)
+
+// Baseline stores per-test execution times and plan fingerprints recorded by `snapsql perf baseline`
+type Baseline struct {
+	Version   int                      `json:"version"`
+	Dialect   string                   `json:"dialect,omitempty"`
+	CreatedAt time.Time                `json:"created_at"`
+	Tests     map[string]BaselineEntry `json:"tests"`
+}
+
+// BaselineEntry is the recorded performance of a single test case
+type BaselineEntry struct {
+	DurationMS      float64 `json:"duration_ms"`
+	PlanFingerprint string  `json:"plan_fingerprint,omitempty"`
+}
+
+// BaselineCheckOptions controls how strictly results are compared against a baseline
+type BaselineCheckOptions struct {
+	// MaxRegressionPercent is the allowed slowdown relative to the baseline duration
+	MaxRegressionPercent float64
+	// MinRegression ignores slowdowns smaller than this absolute duration (timer noise on fast queries)
+	MinRegression time.Duration
+	// AllowPlanChanges disables the plan fingerprint comparison
+	AllowPlanChanges bool
+}
+
+// PerfRegression describes a test that regressed against the baseline
+type PerfRegression struct {
+	Key                 string
+	TestName            string
+	SourceFile          string
+	BaselineDuration    time.Duration
+	Duration            time.Duration
+	BaselineFingerprint string
+	Fingerprint         string
+}
+
+// PlanChanged reports whether the execution plan differs from the baseline
+func (r PerfRegression) PlanChanged() bool {
+	return r.BaselineFingerprint != "" && r.Fingerprint != "" && r.BaselineFingerprint != r.Fingerprint
+}
+
+// Message returns a human-readable description of the regression
+func (r PerfRegression) Message() string {
+	if r.PlanChanged() {
+		return fmt.Sprintf("plan changed (baseline %s, now %s)", r.BaselineFingerprint, r.Fingerprint)
+	}
+
+	percent := 0.0
+	if r.BaselineDuration > 0 {
+		percent = (float64(r.Duration)/float64(r.BaselineDuration) - 1) * 100
+	}
+
+	return fmt.Sprintf("query took %s, baseline %s (+%.1f%%)", r.Duration, r.BaselineDuration, percent)
+}
+
+// NewBaseline builds a baseline from the successful results of a test run
+func NewBaseline(summary *FixtureTestSummary, dialect string) *Baseline {
+	baseline := &Baseline{
+		Version:   BaselineVersion,
+		Dialect:   dialect,
+		CreatedAt: time.Now().UTC(),
+		Tests:     make(map[string]BaselineEntry),
+	}
+
+	if summary == nil {
+		return baseline
+	}
+
+	keys := baselineKeys(summary.Results)
+	for i, result := range summary.Results {
+		if !result.Success {
+			continue
+		}
+
+		baseline.Tests[keys[i]] = BaselineEntry{
+			DurationMS:      float64(measuredDuration(result)) / float64(time.Millisecond),
+			PlanFingerprint: planFingerprint(result),
+		}
+	}
+
+	return baseline
+}
+
+// LoadBaseline reads a baseline file written by WriteBaselineFile
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read baseline file %s: %w", path, err)
+	}
+
+	var baseline Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline file %s: %w", path, err)
+	}
+
+	if baseline.Version != BaselineVersion {
+		return nil, fmt.Errorf("%w: %s has version %d", ErrUnsupportedBaselineFile, path, baseline.Version)
+	}
+
+	if baseline.Tests == nil {
+		baseline.Tests = make(map[string]BaselineEntry)
+	}
+
+	return &baseline, nil
+}
+
+// WriteBaselineFile writes the baseline as indented JSON.
+// Parent directories are created when missing.
+func WriteBaselineFile(path string, baseline *Baseline) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create baseline directory %s: %w", dir, err)
+		}
+	}
+
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode baseline: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write baseline file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// CheckBaseline compares successful results against the baseline. Regressed results are
+// marked as assertion failures and the summary counters are updated accordingly.
+// Tests that are not part of the baseline are ignored.
+func CheckBaseline(summary *FixtureTestSummary, baseline *Baseline, opts BaselineCheckOptions) []PerfRegression {
+	if summary == nil || baseline == nil {
+		return nil
+	}
+
+	var regressions []PerfRegression
+
+	keys := baselineKeys(summary.Results)
+	for i := range summary.Results {
+		result := &summary.Results[i]
+		if !result.Success {
+			continue
+		}
+
+		entry, ok := baseline.Tests[keys[i]]
+		if !ok {
+			continue
+		}
+
+		regression := PerfRegression{
+			Key:                 keys[i],
+			TestName:            result.TestName,
+			SourceFile:          result.SourceFile,
+			BaselineDuration:    time.Duration(entry.DurationMS * float64(time.Millisecond)),
+			Duration:            measuredDuration(*result),
+			BaselineFingerprint: entry.PlanFingerprint,
+			Fingerprint:         planFingerprint(*result),
+		}
+
+		if !isRegression(regression, opts) {
+			continue
+		}
+
+		result.Success = false
+		result.FailureKind = fixtureexecutor.FailureKindAssertion
+		result.Error = fixtureexecutor.NewFixtureFailure(fixtureexecutor.FailureKindAssertion,
+			fmt.Errorf("%w: %s", ErrPerformanceRegression, regression.Message()))
+
+		summary.PassedTests--
+		summary.FailedTests++
+		summary.AssertionFailures++
+
+		regressions = append(regressions, regression)
+	}
+
+	return regressions
+}
+
+func isRegression(r PerfRegression, opts BaselineCheckOptions) bool {
+	if !opts.AllowPlanChanges && r.PlanChanged() {
+		return true
+	}
+
+	if r.BaselineDuration <= 0 {
+		return false
+	}
+
+	slowdown := r.Duration - r.BaselineDuration
+	if slowdown <= 0 || slowdown < opts.MinRegression {
+		return false
+	}
+
+	return float64(slowdown)/float64(r.BaselineDuration)*100 > opts.MaxRegressionPercent
+}
+
+// baselineKeys returns a stable key per result: "<source file>::<test name>",
+// suffixed with "#n" when the same name appears more than once in a file. Duplicates are
+// numbered in source order, so the keys do not depend on the order in which results arrived.
+func baselineKeys(results []FixtureTestResult) []string {
+	order := make([]int, len(results))
+	for i := range order {
+		order[i] = i
+	}
+
+	sort.SliceStable(order, func(a, b int) bool {
+		ra, rb := results[order[a]], results[order[b]]
+		if ra.SourceFile != rb.SourceFile {
+			return ra.SourceFile < rb.SourceFile
+		}
+
+		if ra.SourceLine != rb.SourceLine {
+			return ra.SourceLine < rb.SourceLine
+		}
+
+		return ra.TestName < rb.TestName
+	})
+
+	keys := make([]string, len(results))
+	seen := make(map[string]int, len(results))
+
+	for _, i := range order {
+		key := filepath.ToSlash(results[i].SourceFile) + "::" + results[i].TestName
+
+		seen[key]++
+		if n := seen[key]; n > 1 {
+			key += "#" + strconv.Itoa(n)
+		}
+
+		keys[i] = key
+	}
+
+	return keys
+}
+
+func measuredDuration(result FixtureTestResult) time.Duration {
+	if result.QueryDuration > 0 {
+		return result.QueryDuration
+	}
+
+	return result.Duration
+}
+
+func planFingerprint(result FixtureTestResult) string {
+	if result.Performance == nil || result.Performance.Plan == nil {
+		return ""
+	}
+
+	return result.Performance.Plan.Fingerprint()
+}
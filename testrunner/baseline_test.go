@@ -0,0 +1,141 @@
+package testrunner
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql/explain"
+	"github.com/shibukawa/snapsql/testrunner/fixtureexecutor"
+)
+
+func baselineTestSummary(listDuration time.Duration, listPlan string) *FixtureTestSummary {
+	return &FixtureTestSummary{
+		TotalTests:  3,
+		PassedTests: 2,
+		FailedTests: 1,
+		Results: []FixtureTestResult{
+			{
+				TestName:      "list users",
+				SourceFile:    "queries/users.snap.md",
+				Success:       true,
+				Duration:      time.Second,
+				QueryDuration: listDuration,
+				Performance: &explain.PerformanceEvaluation{
+					Plan: &explain.PlanDocument{RawText: listPlan},
+				},
+			},
+			{
+				TestName:   "find user",
+				SourceFile: "queries/users.snap.md",
+				Success:    true,
+				Duration:   20 * time.Millisecond,
+			},
+			{
+				TestName:    "broken",
+				SourceFile:  "queries/users.snap.md",
+				Success:     false,
+				Error:       errReportTestBroken,
+				FailureKind: fixtureexecutor.FailureKindDefinition,
+			},
+		},
+		DefinitionFailures: 1,
+	}
+}
+
+func TestNewBaselineRecordsSuccessfulTests(t *testing.T) {
+	baseline := NewBaseline(baselineTestSummary(10*time.Millisecond, "SCAN users"), "sqlite")
+
+	assert.Equal(t, BaselineVersion, baseline.Version)
+	assert.Equal(t, "sqlite", baseline.Dialect)
+	assert.Equal(t, 2, len(baseline.Tests))
+
+	list := baseline.Tests["queries/users.snap.md::list users"]
+	assert.Equal(t, 10.0, list.DurationMS)
+	assert.Equal(t, 16, len(list.PlanFingerprint))
+
+	find := baseline.Tests["queries/users.snap.md::find user"]
+	assert.Equal(t, 20.0, find.DurationMS)
+	assert.Equal(t, "", find.PlanFingerprint)
+}
+
+func TestBaselineKeysFollowSourceOrder(t *testing.T) {
+	first := FixtureTestResult{TestName: "dup", SourceFile: "q.snap.md", SourceLine: 10}
+	second := FixtureTestResult{TestName: "dup", SourceFile: "q.snap.md", SourceLine: 30}
+
+	assert.Equal(t, []string{"q.snap.md::dup", "q.snap.md::dup#2"}, baselineKeys([]FixtureTestResult{first, second}))
+	assert.Equal(t, []string{"q.snap.md::dup#2", "q.snap.md::dup"}, baselineKeys([]FixtureTestResult{second, first}))
+}
+
+func TestBaselineFileRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "perf", "baseline.json")
+	baseline := NewBaseline(baselineTestSummary(10*time.Millisecond, "SCAN users"), "sqlite")
+
+	assert.NoError(t, WriteBaselineFile(path, baseline))
+
+	loaded, err := LoadBaseline(path)
+	assert.NoError(t, err)
+	assert.Equal(t, baseline.Tests, loaded.Tests)
+
+	baseline.Version = 99
+	assert.NoError(t, WriteBaselineFile(path, baseline))
+
+	_, err = LoadBaseline(path)
+	assert.True(t, errors.Is(err, ErrUnsupportedBaselineFile))
+}
+
+func TestCheckBaseline(t *testing.T) {
+	baseline := NewBaseline(baselineTestSummary(10*time.Millisecond, "SCAN users"), "sqlite")
+	opts := BaselineCheckOptions{MaxRegressionPercent: 20, MinRegression: time.Millisecond}
+
+	t.Run("within tolerance", func(t *testing.T) {
+		summary := baselineTestSummary(11*time.Millisecond, "SCAN users")
+
+		regressions := CheckBaseline(summary, baseline, opts)
+		assert.Equal(t, 0, len(regressions))
+		assert.Equal(t, 2, summary.PassedTests)
+		assert.Equal(t, 1, summary.FailedTests)
+	})
+
+	t.Run("slower than allowed", func(t *testing.T) {
+		summary := baselineTestSummary(15*time.Millisecond, "SCAN users")
+
+		regressions := CheckBaseline(summary, baseline, opts)
+		assert.Equal(t, 1, len(regressions))
+		assert.Equal(t, "queries/users.snap.md::list users", regressions[0].Key)
+		assert.Equal(t, 1, summary.PassedTests)
+		assert.Equal(t, 2, summary.FailedTests)
+		assert.Equal(t, 1, summary.AssertionFailures)
+
+		result := summary.Results[0]
+		assert.False(t, result.Success)
+		assert.Equal(t, fixtureexecutor.FailureKindAssertion, result.FailureKind)
+		assert.True(t, errors.Is(result.Error, ErrPerformanceRegression))
+		assert.Contains(t, result.Error.Error(), "+50.0%")
+	})
+
+	t.Run("below minimum regression", func(t *testing.T) {
+		loose := opts
+		loose.MinRegression = 10 * time.Millisecond
+
+		summary := baselineTestSummary(15*time.Millisecond, "SCAN users")
+		assert.Equal(t, 0, len(CheckBaseline(summary, baseline, loose)))
+	})
+
+	t.Run("plan changed", func(t *testing.T) {
+		summary := baselineTestSummary(10*time.Millisecond, "SEARCH users USING INDEX idx_users_email (email=?)")
+
+		regressions := CheckBaseline(summary, baseline, opts)
+		assert.Equal(t, 1, len(regressions))
+		assert.True(t, regressions[0].PlanChanged())
+		assert.Contains(t, summary.Results[0].Error.Error(), "plan changed")
+
+		allowed := opts
+		allowed.AllowPlanChanges = true
+
+		summary = baselineTestSummary(10*time.Millisecond, "SEARCH users USING INDEX idx_users_email (email=?)")
+		assert.Equal(t, 0, len(CheckBaseline(summary, baseline, allowed)))
+	})
+}
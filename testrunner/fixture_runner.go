@@ -31,6 +31,7 @@ var (
 type FixtureTestRunner struct {
 	projectRoot  string
 	db           *sql.DB
+	workerDBs    []*sql.DB
 	dialect      snapsql.Dialect
 	verbose      bool
 	runPattern   string
@@ -78,6 +79,12 @@ func (ftr *FixtureTestRunner) SetVerbose(verbose bool) {
 	}
 }
 
+// SetWorkerDatabases provides one isolated database per parallel worker.
+// When set, test cases are distributed across these databases instead of the shared connection.
+func (ftr *FixtureTestRunner) SetWorkerDatabases(dbs []*sql.DB) {
+	ftr.workerDBs = dbs
+}
+
 // SetRunPattern sets the test name filter pattern
 func (ftr *FixtureTestRunner) SetRunPattern(pattern string) {
 	ftr.runPattern = pattern
@@ -132,6 +139,8 @@ func (ftr *FixtureTestRunner) RunAllFixtureTests(ctx context.Context) (*FixtureT
 	// Parse test cases from filtered markdown files
 	var allTestCases []*markdownparser.TestCase
 
+	functionNames := make(map[*markdownparser.TestCase]string)
+
 	fileSummaries := make([]fileTestSummary, 0, len(testFiles))
 	parseIssues := make([]preparationIssue, 0)
 
@@ -163,7 +172,7 @@ func (ftr *FixtureTestRunner) RunAllFixtureTests(ctx context.Context) (*FixtureT
 		// Use SQL and parameters from the first successfully parsed file
 		casesForFile := make([]*markdownparser.TestCase, 0, len(fileInfo.TestCases))
 		for _, tc := range fileInfo.TestCases {
-			if tc == nil {
+			if tc == nil || !ftr.matchesTestCase(tc.Name) {
 				continue
 			}
 
@@ -185,6 +194,7 @@ func (ftr *FixtureTestRunner) RunAllFixtureTests(ctx context.Context) (*FixtureT
 
 			allTestCases = append(allTestCases, tc)
 			casesForFile = append(casesForFile, tc)
+			functionNames[tc] = documentFunctionName(file, fileInfo.Document)
 		}
 
 		fileSummaries = append(fileSummaries, fileTestSummary{path: file, cases: casesForFile, doc: fileInfo.Document})
@@ -233,6 +243,10 @@ func (ftr *FixtureTestRunner) RunAllFixtureTests(ctx context.Context) (*FixtureT
 		runner := fixtureexecutor.NewTestRunner(ftr.db, ftr.dialect, ftr.options)
 		runner.SetVerbose(ftr.verbose)
 
+		if len(ftr.workerDBs) > 0 {
+			runner.SetWorkerDatabases(ftr.workerDBs)
+		}
+
 		if len(ftr.tableInfo) > 0 {
 			runner.SetTableInfo(ftr.tableInfo)
 		}
@@ -252,6 +266,8 @@ func (ftr *FixtureTestRunner) RunAllFixtureTests(ctx context.Context) (*FixtureT
 		TotalTests:    summary.TotalTests + len(additionalIssues),
 		PassedTests:   summary.PassedTests,
 		FailedTests:   summary.FailedTests + len(additionalIssues),
+		SkippedTests:  summary.SkippedTests,
+		FlakyTests:    summary.FlakyTests,
 		TotalDuration: summary.TotalDuration,
 		Results:       make([]FixtureTestResult, 0, len(summary.Results)+len(additionalIssues)),
 	}
@@ -271,17 +287,27 @@ func (ftr *FixtureTestRunner) RunAllFixtureTests(ctx context.Context) (*FixtureT
 			testName = result.TestCase.Name
 		}
 
+		var queryDuration time.Duration
+		if result.Result != nil {
+			queryDuration = result.Result.Duration
+		}
+
 		fixtureSummary.Results = append(fixtureSummary.Results, FixtureTestResult{
-			TestName:    testName,
-			TestCase:    result.TestCase,
-			Success:     result.Success,
-			Duration:    result.Duration,
-			Error:       result.Error,
-			FailureKind: kind,
-			SourceFile:  sourceFile,
-			SourceLine:  sourceLine,
-			ExecutedSQL: result.Trace,
-			Performance: result.Performance,
+			TestName:      testName,
+			TestCase:      result.TestCase,
+			Function:      functionNames[result.TestCase],
+			Success:       result.Success,
+			Duration:      result.Duration,
+			QueryDuration: queryDuration,
+			Error:         result.Error,
+			FailureKind:   kind,
+			SourceFile:    sourceFile,
+			SourceLine:    sourceLine,
+			ExecutedSQL:   result.Trace,
+			Performance:   result.Performance,
+			Attempts:      result.Attempts,
+			Flaky:         result.Flaky,
+			FlakyError:    result.FlakyError,
 		})
 
 		if !result.Success {
@@ -301,6 +327,9 @@ func (ftr *FixtureTestRunner) RunAllFixtureTests(ctx context.Context) (*FixtureT
 		fixtureSummary.DefinitionFailures++
 	}
 
+	fixtureSummary.SlowQueryThreshold = ftr.options.SlowQueryThreshold
+	fixtureSummary.SlowQueries = CollectSlowQueries(fixtureSummary.Results, ftr.options.SlowQueryThreshold)
+
 	return fixtureSummary, nil
 }
 
@@ -338,9 +367,7 @@ func (ftr *FixtureTestRunner) maybeAddMarkdownFile(path string, info os.FileInfo
 		return
 	}
 
-	name := info.Name()
-
-	if strings.HasSuffix(name, ".snap.md") || (strings.HasSuffix(name, ".md") && (strings.Contains(name, "test") || strings.Contains(name, "spec"))) {
+	if IsTestMarkdownFile(info.Name()) {
 		if _, ok := seen[path]; ok {
 			return
 		}
@@ -350,6 +377,12 @@ func (ftr *FixtureTestRunner) maybeAddMarkdownFile(path string, info os.FileInfo
 	}
 }
 
+// IsTestMarkdownFile reports whether a file name is a markdown test file: *.snap.md, or a
+// markdown file whose name contains "test" or "spec".
+func IsTestMarkdownFile(name string) bool {
+	return strings.HasSuffix(name, ".snap.md") || (strings.HasSuffix(name, ".md") && (strings.Contains(name, "test") || strings.Contains(name, "spec")))
+}
+
 // TestFileInfo represents parsed test file information
 type TestFileInfo struct {
 	TestCases  []*markdownparser.TestCase
@@ -391,6 +424,10 @@ func (ftr *FixtureTestRunner) parseTestFile(filePath string) (*TestFileInfo, err
 		tc.SourceFile = relPath
 	}
 
+	for _, warning := range doc.Warnings {
+		fmt.Printf("Warning: %s: %s\n", relPath, warning)
+	}
+
 	// Parse parameters from parameter block if present
 	parameters := make(map[string]any)
 
@@ -416,6 +453,7 @@ func (ftr *FixtureTestRunner) prepareTestCases(summaries []fileTestSummary) ([]*
 	config := &snapsql.Config{Dialect: ftr.dialect}
 	valid := make([]*markdownparser.TestCase, 0)
 	issues := make([]preparationIssue, 0)
+	templates := make(map[string]*intermediate.IntermediateFormat)
 
 	for _, summary := range summaries {
 		if len(summary.cases) == 0 {
@@ -456,7 +494,23 @@ func (ftr *FixtureTestRunner) prepareTestCases(summaries []fileTestSummary) ([]*
 				continue
 			}
 
-			if err := fixtureexecutor.NormalizeParameters(tc.Parameters); err != nil {
+			// A scenario runs its steps instead of the file's own query
+			if len(tc.Steps) > 0 {
+				if err := ftr.prepareSteps(tc, summary.path, format, config, templates); err != nil {
+					issues = append(issues, preparationIssue{
+						testCase: tc,
+						err:      fmt.Errorf("failed to prepare steps for %s: %w", tc.Name, err),
+					})
+
+					continue
+				}
+
+				valid = append(valid, tc)
+
+				continue
+			}
+
+			if err := fixtureexecutor.NormalizeParametersAt(tc.Parameters, tc.Now); err != nil {
 				issues = append(issues, preparationIssue{
 					testCase: tc,
 					err:      fmt.Errorf("failed to normalize parameters for %s: %w", tc.Name, err),
@@ -465,6 +519,9 @@ func (ftr *FixtureTestRunner) prepareTestCases(summaries []fileTestSummary) ([]*
 				continue
 			}
 
+			// Generated system values such as created_at follow the test case's `now`
+			generator.SetNow(tc.Now)
+
 			finalSQL, args, err := generator.Generate(tc.Parameters)
 			if err != nil {
 				issues = append(issues, preparationIssue{
@@ -579,17 +636,33 @@ func (ftr *FixtureTestRunner) printVerboseDiscovery(summaries []fileTestSummary)
 	fmt.Println()
 }
 
+// splitRunPattern splits the run pattern into the file name part and the test case name part.
+// Like Go's -run flag, "/" separates the levels: "user_list/Active users/admin" selects the file
+// prefixed "user_list" and the test cases (or matrix sub-cases) whose names start with "Active users/admin".
+func splitRunPattern(pattern string) (string, string) {
+	filePattern, casePattern, _ := strings.Cut(pattern, "/")
+	return filePattern, casePattern
+}
+
+// matchesTestCase reports whether the test case name matches the test case part of the run pattern
+func (ftr *FixtureTestRunner) matchesTestCase(name string) bool {
+	_, casePattern := splitRunPattern(ftr.runPattern)
+	return strings.HasPrefix(name, casePattern)
+}
+
 // filterTestFiles filters test files by the run pattern (filename without extension)
 func (ftr *FixtureTestRunner) filterTestFiles(testFiles []string) []string {
 	var filtered []string
 
+	filePattern, _ := splitRunPattern(ftr.runPattern)
+
 	for _, file := range testFiles {
 		// Get filename without extension
 		filename := filepath.Base(file)
 		nameWithoutExt := strings.TrimSuffix(filename, filepath.Ext(filename))
 
 		// Use prefix matching like Go's -run flag
-		if strings.HasPrefix(nameWithoutExt, ftr.runPattern) {
+		if strings.HasPrefix(nameWithoutExt, filePattern) {
 			filtered = append(filtered, file)
 		}
 	}
@@ -599,16 +672,24 @@ func (ftr *FixtureTestRunner) filterTestFiles(testFiles []string) []string {
 
 // FixtureTestResult represents the result of a fixture test
 type FixtureTestResult struct {
-	TestName    string
-	TestCase    *markdownparser.TestCase
-	Success     bool
-	Duration    time.Duration
-	Error       error
-	FailureKind fixtureexecutor.FailureKind
-	SourceFile  string
-	SourceLine  int
-	ExecutedSQL []fixtureexecutor.SQLTrace
-	Performance *explain.PerformanceEvaluation
+	TestName string
+	TestCase *markdownparser.TestCase
+	// Function is the name of the query function the test case belongs to
+	Function string
+	Success  bool
+	Duration time.Duration
+	// QueryDuration is the execution time of the main query alone (0 when it was not measured)
+	QueryDuration time.Duration
+	Error         error
+	FailureKind   fixtureexecutor.FailureKind
+	SourceFile    string
+	SourceLine    int
+	ExecutedSQL   []fixtureexecutor.SQLTrace
+	Performance   *explain.PerformanceEvaluation
+	// Attempts and Flaky are set by --retries; FlakyError is the error of the first failed attempt
+	Attempts   int
+	Flaky      bool
+	FlakyError error
 }
 
 // FixtureTestSummary represents the summary of fixture test execution
@@ -616,27 +697,53 @@ type FixtureTestSummary struct {
 	TotalTests         int
 	PassedTests        int
 	FailedTests        int
+	SkippedTests       int // not run because the run stopped at --max-failures / --fail-fast
+	FlakyTests         int // passed only on a retry (--retries); also counted in PassedTests
 	TotalDuration      time.Duration
 	Results            []FixtureTestResult
 	AssertionFailures  int
 	DefinitionFailures int
 	UnknownFailures    int
+	// SlowQueries ranks the functions whose queries took at least SlowQueryThreshold
+	SlowQueries        []SlowQueryStat
+	SlowQueryThreshold time.Duration
 }
 
 // PrintSummary prints the fixture test execution summary
 func (ftr *FixtureTestRunner) PrintSummary(summary *FixtureTestSummary) {
 	fmt.Fprintln(color.Output)
 	fmt.Fprintln(color.Output, "=== Fixture Test Summary ===")
-	fmt.Fprintf(color.Output, "Tests: %d total, %d passed, %d failed\n",
-		summary.TotalTests, summary.PassedTests, summary.FailedTests)
+	if summary.SkippedTests > 0 {
+		fmt.Fprintf(color.Output, "Tests: %d total, %d passed, %d failed, %d skipped\n",
+			summary.TotalTests, summary.PassedTests, summary.FailedTests, summary.SkippedTests)
+		fmt.Fprintf(color.Output, "Stopped after %d failure(s); the remaining tests were skipped\n", summary.FailedTests)
+	} else {
+		fmt.Fprintf(color.Output, "Tests: %d total, %d passed, %d failed\n",
+			summary.TotalTests, summary.PassedTests, summary.FailedTests)
+	}
+
+	if summary.FlakyTests > 0 {
+		fmt.Fprintf(color.Output, "Flaky: %d test(s) passed only on retry\n", summary.FlakyTests)
+	}
 
 	if summary.FailedTests > 0 {
 		fmt.Fprintf(color.Output, "Assertions Failed: %d, Definition Failures: %d, Unknown Failures: %d\n",
 			summary.AssertionFailures, summary.DefinitionFailures, summary.UnknownFailures)
+
+		if ftr.options != nil && ftr.options.Shuffle {
+			fmt.Fprintf(color.Output, "Shuffle seed: %d (reproduce the order with --shuffle --seed %d)\n",
+				ftr.options.ShuffleSeed, ftr.options.ShuffleSeed)
+		}
 	}
 
 	fmt.Fprintf(color.Output, "Duration: %.3fs\n", summary.TotalDuration.Seconds())
 
+	if summary.FlakyTests > 0 {
+		ftr.printFlakyTests(summary.Results)
+	}
+
+	PrintSlowQueryReport(color.Output, summary.SlowQueries, summary.SlowQueryThreshold)
+
 	fileOrder, fileGroups := groupResultsByFile(summary.Results)
 
 	if ftr.verbose {
@@ -767,6 +874,30 @@ func (ftr *FixtureTestRunner) PrintSummary(summary *FixtureTestSummary) {
 	}
 }
 
+// printFlakyTests lists the test cases that passed only on a retry
+func (ftr *FixtureTestRunner) printFlakyTests(results []FixtureTestResult) {
+	flakyLabel := color.New(color.Bold, color.FgYellow).SprintFunc()
+
+	fmt.Fprintln(color.Output, "\nFlaky tests (passed on retry):")
+
+	for _, result := range results {
+		if !result.Flaky {
+			continue
+		}
+
+		location := result.SourceFile
+		if location != "" && result.SourceLine > 0 {
+			location = fmt.Sprintf("%s:%d", location, result.SourceLine)
+		}
+
+		fmt.Fprintf(color.Output, "  %s %s (%s, %d attempts)\n", flakyLabel("[Flaky]"), result.TestName, location, result.Attempts)
+
+		if result.FlakyError != nil {
+			fmt.Fprintf(color.Output, "    First failure: %v\n", result.FlakyError)
+		}
+	}
+}
+
 type testCaseMetadata struct {
 	tableMap map[string]intermediate.TableReferenceInfo
 }
@@ -876,11 +1007,24 @@ func (ftr *FixtureTestRunner) printVerboseFileReports(fileOrder []string, groups
 				name = res.TestCase.Name
 			}
 
-			fmt.Fprintf(color.Output, "  %s %s (%s)\n", statusLabel, strings.TrimSpace(name), formatDuration(res.Duration))
+			timing := formatDuration(res.Duration)
+			if budget := formatQueryBudget(res); budget != "" {
+				timing += ", " + budget
+			}
+
+			fmt.Fprintf(color.Output, "  %s %s (%s)\n", statusLabel, strings.TrimSpace(name), timing)
 
 			if !res.Success && res.Error != nil {
 				fmt.Fprintf(color.Output, "    error: %v\n", res.Error)
 			}
+
+			if res.Performance != nil && res.Performance.Plan != nil {
+				fmt.Fprintln(color.Output, "    plan:")
+
+				for line := range strings.SplitSeq(res.Performance.Plan.Text(), "\n") {
+					fmt.Fprintf(color.Output, "      %s\n", line)
+				}
+			}
 		}
 
 		perfs := ftr.aggregatePerformance(results, true)
@@ -1176,69 +1320,22 @@ func formatDuration(d time.Duration) string {
 	return d.Round(time.Millisecond).String()
 }
 
-func printColoredDiff(diffText string) {
-	lines := strings.Split(diffText, "\n")
-	if len(lines) > 0 && lines[len(lines)-1] == "" {
-		lines = lines[:len(lines)-1]
+// formatQueryBudget renders the measured query time against the test case's max_duration,
+// e.g. "query 62.10ms / max 50ms". It is empty for test cases without a budget.
+func formatQueryBudget(res FixtureTestResult) string {
+	if res.TestCase == nil || res.TestCase.MaxDuration <= 0 {
+		return ""
 	}
 
-	for _, line := range lines {
-		if line == "" {
-			fmt.Fprintln(color.Output)
-			continue
-		}
-
-		fmt.Fprintln(color.Output, formatDiffLine(line))
-	}
+	return fmt.Sprintf("query %s / max %s", formatDuration(res.QueryDuration), res.TestCase.MaxDuration)
 }
 
-func formatDiffLine(line string) string {
-	if line == "" {
-		return line
-	}
-
-	if strings.HasPrefix(line, "---") || strings.HasPrefix(line, "+++") {
-		return color.New(color.Bold, color.FgCyan).Sprint(line)
-	}
-
-	if strings.HasPrefix(line, "@@") {
-		return color.New(color.FgCyan).Sprint(line)
-	}
-
-	attrs := make([]color.Attribute, 0, 3)
-
-	switch {
-	case strings.HasPrefix(line, "+"):
-		attrs = append(attrs, color.FgGreen)
-	case strings.HasPrefix(line, "-"):
-		attrs = append(attrs, color.FgRed)
-	}
-
-	content := line
-	if strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") || strings.HasPrefix(line, " ") {
-		content = line[1:]
-	}
-
-	trimmed := strings.TrimSpace(content)
-	if trimmed == "" {
-		if len(attrs) == 0 {
-			return line
-		}
-
-		return color.New(attrs...).Sprint(line)
-	}
-
-	if strings.HasPrefix(trimmed, "row ") {
-		attrs = append(attrs, color.Bold, color.FgBlue)
-	} else if strings.HasPrefix(trimmed, "table:") || strings.HasPrefix(trimmed, "primary_keys:") || strings.HasPrefix(trimmed, "rows:") {
-		attrs = append(attrs, color.Bold)
-	}
-
-	if len(attrs) == 0 {
-		return line
+// printColoredDiff prints a diff rendered by fixtureexecutor.FormatDiffUnifiedYAML, which is
+// already colored
+func printColoredDiff(diffText string) {
+	for line := range strings.SplitSeq(strings.TrimRight(diffText, "\n"), "\n") {
+		fmt.Fprintln(color.Output, line)
 	}
-
-	return color.New(attrs...).Sprint(line)
 }
 
 func printSQLTrace(traces []fixtureexecutor.SQLTrace) {
@@ -37,3 +37,19 @@ func TestFindMarkdownTestFilesWithIncludePaths(t *testing.T) {
 	require.NoError(t, err)
 	require.ElementsMatch(t, []string{fileA}, filesFile)
 }
+
+func TestRunPatternSelectsFilesAndTestCases(t *testing.T) {
+	files := []string{"/q/user_list.snap.md", "/q/user_get.snap.md"}
+
+	runner := &FixtureTestRunner{}
+
+	runner.SetRunPattern("user_list")
+	require.Equal(t, []string{"/q/user_list.snap.md"}, runner.filterTestFiles(files))
+	require.True(t, runner.matchesTestCase("Active users/admin"))
+
+	runner.SetRunPattern("user_list/Active users/admin")
+	require.Equal(t, []string{"/q/user_list.snap.md"}, runner.filterTestFiles(files))
+	require.True(t, runner.matchesTestCase("Active users/admin"))
+	require.False(t, runner.matchesTestCase("Active users/guest"))
+	require.False(t, runner.matchesTestCase("Inactive users"))
+}
@@ -176,11 +176,11 @@ func TestFixtureTestRunnerPrintSummaryLabelsFailures(t *testing.T) {
 		t.Fatalf("expected table header in output, got: %s", output)
 	}
 
-	if !strings.Contains(output, "- Expected") || !strings.Contains(output, "+ Actual") {
+	if !strings.Contains(output, "--- Expected") || !strings.Contains(output, "+++ Actual") {
 		t.Fatalf("expected legend lines in output, got: %s", output)
 	}
 
-	if !strings.Contains(output, "id: 10 [mismatch]") || !strings.Contains(output, "+ name: Todo") || !strings.Contains(output, "- name: Todo!") {
+	if !strings.Contains(output, "@@ id: 10 [mismatch] @@") || !strings.Contains(output, "- name: Todo\n") || !strings.Contains(output, "+ name: Todo!") {
 		t.Fatalf("expected diff body in output, got: %s", output)
 	}
 
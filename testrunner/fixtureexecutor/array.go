@@ -0,0 +1,433 @@
+package fixtureexecutor
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shibukawa/snapsql"
+)
+
+// isPostgresFamily reports whether the dialect speaks the PostgreSQL array and row literal syntax
+func (e *Executor) isPostgresFamily() bool {
+	switch e.dialect {
+	case "postgres", "postgresql", "pg", "pgx", snapsql.DialectCockroach:
+		return true
+	default:
+		return false
+	}
+}
+
+// encodeFixtureRows converts the sequence and mapping values of normalized fixture rows into values
+// the driver accepts. On PostgreSQL a sequence becomes an array literal, or a row literal when the
+// schema declares a scalar type for the column (composite types are imported as strings). JSON
+// columns and the other dialects receive the value encoded as JSON. rows are modified in place.
+func (e *Executor) encodeFixtureRows(tableName string, rows []map[string]any) error {
+	tbl := e.tableInfo[tableName]
+
+	for _, row := range rows {
+		for col, value := range row {
+			var column *snapsql.ColumnInfo
+			if tbl != nil {
+				column = tbl.Columns[col]
+			}
+
+			encoded, err := e.encodeFixtureValue(column, value)
+			if err != nil {
+				return fmt.Errorf("failed to encode fixture value for %s: %w", col, err)
+			}
+			row[col] = encoded
+		}
+	}
+
+	return nil
+}
+
+func (e *Executor) encodeFixtureValue(column *snapsql.ColumnInfo, value any) (any, error) {
+	switch v := value.(type) {
+	case []any:
+		dataType := ""
+		if column != nil {
+			dataType = strings.ToLower(column.DataType)
+		}
+
+		if e.isPostgresFamily() && !strings.Contains(dataType, "json") {
+			if dataType == "" || dataType == "array" || strings.HasSuffix(dataType, "[]") || strings.HasPrefix(dataType, "_") {
+				return formatPostgresArray(v), nil
+			}
+			return formatPostgresRow(v), nil
+		}
+
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	default:
+		return value, nil
+	}
+}
+
+// formatPostgresArray renders values as a PostgreSQL array literal such as {1,2,"a b",NULL}.
+// Nested sequences become nested arrays.
+func formatPostgresArray(values []any) string {
+	var b strings.Builder
+
+	b.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		switch v := value.(type) {
+		case nil:
+			b.WriteString("NULL")
+		case []any:
+			b.WriteString(formatPostgresArray(v))
+		default:
+			b.WriteString(quotePostgresLiteralElement(formatLiteralElement(v)))
+		}
+	}
+	b.WriteByte('}')
+
+	return b.String()
+}
+
+// formatPostgresRow renders values as a PostgreSQL row literal such as (1,"a b",) for composite
+// columns. A nil field is written as nothing, which PostgreSQL reads as NULL.
+func formatPostgresRow(values []any) string {
+	var b strings.Builder
+
+	b.WriteByte('(')
+	for i, value := range values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		switch v := value.(type) {
+		case nil:
+		case []any:
+			b.WriteString(quotePostgresLiteralElement(formatPostgresArray(v)))
+		default:
+			b.WriteString(quotePostgresLiteralElement(formatLiteralElement(v)))
+		}
+	}
+	b.WriteByte(')')
+
+	return b.String()
+}
+
+func formatLiteralElement(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// quotePostgresLiteralElement double-quotes an element of an array or row literal. Quoting every
+// element keeps strings such as "NULL", "" or "a,b" intact.
+func quotePostgresLiteralElement(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// parsePostgresArray parses a PostgreSQL array literal as returned in text format. Elements are
+// strings, nested arrays are []any and NULL is nil.
+func parsePostgresArray(s string) ([]any, bool) {
+	s = strings.TrimSpace(s)
+	// "[1:3]={1,2,3}" carries explicit bounds
+	if strings.HasPrefix(s, "[") {
+		if _, rest, ok := strings.Cut(s, "="); ok {
+			s = rest
+		}
+	}
+	if len(s) < 2 || s[0] != '{' || s[len(s)-1] != '}' {
+		return nil, false
+	}
+
+	values, rest, ok := parsePostgresArrayBody(s[1:])
+
+	return values, ok && rest == ""
+}
+
+// parsePostgresArrayBody parses the elements following an opening brace and returns the text
+// after the matching closing brace
+func parsePostgresArrayBody(s string) ([]any, string, bool) {
+	values := make([]any, 0)
+	if strings.HasPrefix(s, "}") {
+		return values, s[1:], true
+	}
+
+	for {
+		var (
+			value any
+			ok    bool
+		)
+
+		switch {
+		case strings.HasPrefix(s, "{"):
+			value, s, ok = parsePostgresArrayBody(s[1:])
+		case strings.HasPrefix(s, `"`):
+			value, s, ok = parseQuotedLiteralElement(s)
+		default:
+			end := strings.IndexAny(s, ",}")
+			if end < 0 {
+				return nil, "", false
+			}
+			element := strings.TrimSpace(s[:end])
+			if strings.EqualFold(element, "NULL") {
+				value = nil
+			} else {
+				value = element
+			}
+			s, ok = s[end:], true
+		}
+		if !ok || s == "" {
+			return nil, "", false
+		}
+
+		values = append(values, value)
+
+		switch s[0] {
+		case ',':
+			s = s[1:]
+		case '}':
+			return values, s[1:], true
+		default:
+			return nil, "", false
+		}
+	}
+}
+
+// parsePostgresRow parses a PostgreSQL row literal of a composite value. Empty fields are nil.
+func parsePostgresRow(s string) ([]any, bool) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != '(' || s[len(s)-1] != ')' {
+		return nil, false
+	}
+
+	s = s[1 : len(s)-1]
+	values := make([]any, 0)
+
+	for {
+		var value any
+
+		if strings.HasPrefix(s, `"`) {
+			var ok bool
+			if value, s, ok = parseQuotedLiteralElement(s); !ok {
+				return nil, false
+			}
+		} else {
+			end := strings.IndexByte(s, ',')
+			if end < 0 {
+				end = len(s)
+			}
+			if field := s[:end]; field != "" {
+				value = field
+			}
+			s = s[end:]
+		}
+
+		values = append(values, value)
+
+		if s == "" {
+			return values, true
+		}
+		if s[0] != ',' {
+			return nil, false
+		}
+		s = s[1:]
+	}
+}
+
+// parseQuotedLiteralElement reads a double-quoted element. Row literals escape quotes by doubling
+// them, array literals with a backslash; both are accepted.
+func parseQuotedLiteralElement(s string) (string, string, bool) {
+	var b strings.Builder
+
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		case '"':
+			if i+1 < len(s) && s[i+1] == '"' {
+				i++
+				b.WriteByte('"')
+				continue
+			}
+			return b.String(), s[i+1:], true
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", "", false
+}
+
+// decodeScannedValue converts a PostgreSQL array scanned in text format into a Go slice whose
+// elements have the Go type of the array's element type, so that it compares with the
+// sequences written in expected results. dbType is the driver's type name, e.g. "_INT4".
+func (e *Executor) decodeScannedValue(dbType string, value any) any {
+	if !e.isPostgresFamily() || !strings.HasPrefix(dbType, "_") {
+		return value
+	}
+
+	var text string
+	switch v := value.(type) {
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		return value
+	}
+
+	values, ok := parsePostgresArray(text)
+	if !ok {
+		return value
+	}
+
+	return convertArrayElements(values, strings.ToUpper(dbType[1:]))
+}
+
+// columnTypeNames returns the driver's type name of each result column; the names are empty
+// when the driver does not report them
+func columnTypeNames(rows *sql.Rows, count int) []string {
+	names := make([]string, count)
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return names
+	}
+	for i, ct := range types {
+		if i < count {
+			names[i] = ct.DatabaseTypeName()
+		}
+	}
+
+	return names
+}
+
+func convertArrayElements(values []any, elemType string) []any {
+	for i, value := range values {
+		switch v := value.(type) {
+		case []any:
+			values[i] = convertArrayElements(v, elemType)
+		case string:
+			values[i] = convertArrayElement(v, elemType)
+		}
+	}
+
+	return values
+}
+
+func convertArrayElement(s string, elemType string) any {
+	switch elemType {
+	case "INT2", "INT4", "INT8":
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n
+		}
+	case "FLOAT4", "FLOAT8", "NUMERIC":
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	case "BOOL":
+		if b, err := strconv.ParseBool(s); err == nil {
+			return b
+		}
+	}
+
+	return s
+}
+
+// comparableSlice returns v as a slice for element-wise comparison. Besides slices it accepts
+// PostgreSQL array and row literals and JSON arrays returned as text.
+func comparableSlice(v any) ([]any, bool) {
+	var text string
+
+	switch val := v.(type) {
+	case []any:
+		return val, true
+	case string:
+		text = strings.TrimSpace(val)
+	case []byte:
+		text = strings.TrimSpace(string(val))
+	default:
+		return nil, false
+	}
+
+	switch {
+	case strings.HasPrefix(text, "{"):
+		return parsePostgresArray(text)
+	case strings.HasPrefix(text, "("):
+		return parsePostgresRow(text)
+	case strings.HasPrefix(text, "["):
+		var decoded []any
+		if err := json.Unmarshal([]byte(text), &decoded); err != nil {
+			return nil, false
+		}
+		return decoded, true
+	default:
+		return nil, false
+	}
+}
+
+// slicesEqual compares arrays element-wise. Elements parsed from a text literal are strings, so
+// they also match the number or boolean they spell.
+func slicesEqual(a, b []any) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !arrayElementEquals(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func arrayElementEquals(a, b any) bool {
+	if valueEquals(a, b) {
+		return true
+	}
+
+	s, ok := a.(string)
+	other := b
+	if !ok {
+		if s, ok = b.(string); !ok {
+			return false
+		}
+		other = a
+	}
+
+	switch o := other.(type) {
+	case bool:
+		parsed, err := strconv.ParseBool(s)
+		return err == nil && parsed == o
+	case []any:
+		return false
+	default:
+		if _, isNumber := toFloat(o); isNumber {
+			parsed, err := strconv.ParseFloat(s, 64)
+			return err == nil && valueEquals(parsed, o)
+		}
+		return false
+	}
+}
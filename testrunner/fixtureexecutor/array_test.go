@@ -0,0 +1,146 @@
+package fixtureexecutor
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/markdownparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeFixtureValue(t *testing.T) {
+	columns := map[string]*snapsql.ColumnInfo{
+		"tags":    {Name: "tags", DataType: "array"},
+		"address": {Name: "address", DataType: "string"},
+		"profile": {Name: "profile", DataType: "json"},
+	}
+
+	tests := []struct {
+		name    string
+		dialect snapsql.Dialect
+		column  string
+		value   any
+		want    any
+	}{
+		{name: "array", dialect: snapsql.DialectPostgres, column: "tags", value: []any{"a", `b"c`, nil, int64(3)}, want: `{"a","b\"c",NULL,"3"}`},
+		{name: "nested array", dialect: snapsql.DialectPostgres, column: "tags", value: []any{[]any{int64(1), int64(2)}, []any{int64(3), int64(4)}}, want: `{{"1","2"},{"3","4"}}`},
+		{name: "array without schema", dialect: snapsql.DialectPostgres, value: []any{true, false}, want: `{"true","false"}`},
+		{name: "composite", dialect: snapsql.DialectPostgres, column: "address", value: []any{"Tokyo", nil, int64(100)}, want: `("Tokyo",,"100")`},
+		{name: "json column", dialect: snapsql.DialectPostgres, column: "profile", value: []any{"a", int64(1)}, want: `["a",1]`},
+		{name: "other dialects", dialect: snapsql.DialectSQLite, column: "tags", value: []any{"a", int64(1)}, want: `["a",1]`},
+		{name: "mapping", dialect: snapsql.DialectPostgres, column: "profile", value: map[string]any{"a": int64(1)}, want: `{"a":1}`},
+		{name: "scalar", dialect: snapsql.DialectPostgres, column: "address", value: "Tokyo", want: "Tokyo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewExecutor(nil, tt.dialect, nil).encodeFixtureValue(columns[tt.column], tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParsePostgresLiterals(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []any
+	}{
+		{input: "{}", want: []any{}},
+		{input: "{1,2,3}", want: []any{"1", "2", "3"}},
+		{input: `{a,"b c","d\"e",NULL,"NULL"}`, want: []any{"a", "b c", `d"e`, nil, "NULL"}},
+		{input: "{{1,2},{3,4}}", want: []any{[]any{"1", "2"}, []any{"3", "4"}}},
+		{input: `(Tokyo,,"a ""b""",)`, want: []any{"Tokyo", nil, `a "b"`, nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, ok := comparableSlice(tt.input)
+			require.True(t, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, input := range []string{"{1,2", `{"a}`, "(a,b", "plain"} {
+		_, ok := comparableSlice(input)
+		assert.False(t, ok, input)
+	}
+}
+
+func TestDecodeScannedValue(t *testing.T) {
+	executor := NewExecutor(nil, snapsql.DialectPostgres, nil)
+
+	assert.Equal(t, []any{int64(1), nil, int64(3)}, executor.decodeScannedValue("_INT4", "{1,NULL,3}"))
+	assert.Equal(t, []any{true, false}, executor.decodeScannedValue("_BOOL", []byte("{t,f}")))
+	assert.Equal(t, []any{[]any{1.5}, []any{2.0}}, executor.decodeScannedValue("_FLOAT8", "{{1.5},{2}}"))
+	assert.Equal(t, []any{"a", "b"}, executor.decodeScannedValue("_TEXT", "{a,b}"))
+	assert.Equal(t, []any{int64(5), int64(6)}, executor.decodeScannedValue("_INT8", "[0:1]={5,6}"))
+	assert.Equal(t, "{a,b}", executor.decodeScannedValue("TEXT", "{a,b}"))
+	assert.Equal(t, "{1}", NewExecutor(nil, snapsql.DialectSQLite, nil).decodeScannedValue("_INT4", "{1}"))
+}
+
+func TestCompareRowsWithMatchersArray(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected any
+		actual   any
+		match    bool
+	}{
+		{name: "decoded array", expected: []any{1, 2, 3}, actual: []any{int64(1), int64(2), int64(3)}, match: true},
+		{name: "array literal", expected: []any{"a", "b"}, actual: "{a,b}", match: true},
+		{name: "boolean array literal", expected: []any{true, false}, actual: "{t,f}", match: true},
+		{name: "JSON array", expected: []any{1, "x"}, actual: []byte(`[1,"x"]`), match: true},
+		{name: "composite", expected: []any{"Tokyo", nil, 100}, actual: "(Tokyo,,100)", match: true},
+		{name: "empty array", expected: []any{}, actual: "{}", match: true},
+		{name: "different element", expected: []any{1, 2}, actual: "{1,3}", match: false},
+		{name: "different length", expected: []any{1, 2}, actual: []any{int64(1)}, match: false},
+		{name: "order matters", expected: []any{"a", "b"}, actual: "{b,a}", match: false},
+		{name: "not an array", expected: []any{1}, actual: int64(1), match: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := compareRowsWithMatchers(map[string]any{"v": tt.expected}, map[string]any{"v": tt.actual})
+			diff := evaluateMatcherDiff("v", tt.expected, tt.actual)
+
+			if tt.match {
+				require.NoError(t, err)
+				assert.Nil(t, diff)
+			} else {
+				require.ErrorIs(t, err, errValueMismatch)
+				require.NotNil(t, diff)
+				assert.Equal(t, "value mismatch", diff.Reason)
+			}
+		})
+	}
+}
+
+func TestExecutor_ArrayFixtureRoundTrip(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE posts (id INTEGER PRIMARY KEY, tags TEXT NOT NULL)`)
+	require.NoError(t, err)
+
+	executor := NewExecutor(db, snapsql.DialectSQLite, nil)
+	ctx := context.Background()
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+
+	defer tx.Rollback()
+
+	require.NoError(t, executor.executeFixtures(ctx, tx, []markdownparser.TableFixture{
+		{TableName: "posts", Strategy: markdownparser.ClearInsert, Data: []map[string]any{{"id": 1, "tags": []any{"go", "sql"}}}},
+	}))
+
+	result, err := executor.executeSelectQuery(ctx, tx, "SELECT tags FROM posts", nil, "test")
+	require.NoError(t, err)
+	require.Len(t, result.Data, 1)
+	assert.NoError(t, compareRowsWithMatchers(map[string]any{"tags": []any{"go", "sql"}}, result.Data[0]))
+}
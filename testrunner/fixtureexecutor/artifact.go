@@ -0,0 +1,162 @@
+package fixtureexecutor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/shibukawa/snapsql/markdownparser"
+)
+
+// unsafePathChars matches the characters replaced in artifact directory names
+var unsafePathChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// traceArtifact is an SQLTrace as written to trace.yaml
+type traceArtifact struct {
+	Label      string           `yaml:"label"`
+	Statement  string           `yaml:"statement"`
+	Parameters map[string]any   `yaml:"parameters,omitempty"`
+	Args       []any            `yaml:"args,omitempty"`
+	QueryType  string           `yaml:"query_type"`
+	TotalRows  int              `yaml:"total_rows,omitempty"`
+	Rows       []map[string]any `yaml:"rows,omitempty"`
+}
+
+// recordFailedTable keeps the actual rows of a table whose expected results did not match, for
+// the failure artifacts. It is a no-op unless ExecutionOptions.ArtifactsDir is set.
+func (te *TestExecution) recordFailedTable(tableName string, rows []map[string]any) {
+	if te.Options == nil || te.Options.ArtifactsDir == "" {
+		return
+	}
+	if te.FailedTables == nil {
+		te.FailedTables = make(map[string][]map[string]any)
+	}
+
+	te.FailedTables[tableName] = rows
+}
+
+// artifactDir returns the per-test directory of the failure artifacts:
+// <ArtifactsDir>/<markdown file name>/<test case name>
+func artifactDir(root string, testCase *markdownparser.TestCase) string {
+	file := "tests"
+	name := "test"
+
+	if testCase != nil {
+		if testCase.SourceFile != "" {
+			file = filepath.Base(testCase.SourceFile)
+			file = strings.TrimSuffix(strings.TrimSuffix(file, ".md"), ".snap")
+		}
+		if testCase.Name != "" {
+			name = testCase.Name
+		}
+	}
+
+	return filepath.Join(root, sanitizePathElement(file), sanitizePathElement(name))
+}
+
+func sanitizePathElement(s string) string {
+	s = strings.Trim(unsafePathChars.ReplaceAllString(s, "_"), "_")
+	if s == "" || s == "." || s == ".." {
+		return "_"
+	}
+
+	return s
+}
+
+// writeFailureArtifacts writes the actual contents of the tables that failed validation
+// (tables/<table>.yaml, in fixture format), the SQL trace (trace.yaml) and the failure message
+// (error.txt) into the test's artifact directory. Artifacts of a previous run are replaced.
+func writeFailureArtifacts(root string, execution *TestExecution, failure error) error {
+	dir := artifactDir(root, execution.TestCase)
+
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to clean artifact directory: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "tables"), 0o755); err != nil {
+		return fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+
+	for table, rows := range execution.FailedTables {
+		if err := writeYAMLArtifact(filepath.Join(dir, "tables", sanitizePathElement(table)+".yaml"), artifactRows(rows)); err != nil {
+			return err
+		}
+	}
+
+	traces := make([]traceArtifact, 0, len(execution.Trace))
+	for _, trace := range execution.Trace {
+		traces = append(traces, traceArtifact{
+			Label:      trace.Label,
+			Statement:  trace.Statement,
+			Parameters: trace.Parameters,
+			Args:       artifactValues(trace.Args),
+			QueryType:  trace.QueryType.String(),
+			TotalRows:  trace.TotalRows,
+			Rows:       artifactRows(trace.Rows),
+		})
+	}
+
+	if err := writeYAMLArtifact(filepath.Join(dir, "trace.yaml"), traces); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "error.txt"), []byte(failure.Error()+"\n"), 0o644); err != nil {
+		return fmt.Errorf("failed to write failure artifact: %w", err)
+	}
+
+	return nil
+}
+
+func writeYAMLArtifact(path string, value any) error {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to encode artifact %s: %w", filepath.Base(path), err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write artifact %s: %w", filepath.Base(path), err)
+	}
+
+	return nil
+}
+
+// artifactRows converts scanned rows into values YAML writes readably ([]byte becomes a string)
+func artifactRows(rows []map[string]any) []map[string]any {
+	if rows == nil {
+		return nil
+	}
+
+	out := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		converted := make(map[string]any, len(row))
+		for k, v := range row {
+			converted[k] = artifactValue(v)
+		}
+		out[i] = converted
+	}
+
+	return out
+}
+
+func artifactValues(values []any) []any {
+	if values == nil {
+		return nil
+	}
+
+	out := make([]any, len(values))
+	for i, v := range values {
+		out[i] = artifactValue(v)
+	}
+
+	return out
+}
+
+func artifactValue(v any) any {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+
+	return v
+}
@@ -0,0 +1,90 @@
+package fixtureexecutor
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/markdownparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutor_FailureArtifacts(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL)`)
+	require.NoError(t, err)
+
+	tableInfo := map[string]*snapsql.TableInfo{
+		"users": {
+			Name: "users",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":   {Name: "id", DataType: "int", IsPrimaryKey: true},
+				"name": {Name: "name", DataType: "string"},
+			},
+		},
+	}
+	executor := NewExecutor(db, snapsql.DialectSQLite, tableInfo)
+
+	testCase := &markdownparser.TestCase{
+		Name:       "Rename user: alice",
+		SourceFile: "/project/queries/update_user.snap.md",
+		Fixtures: []markdownparser.TableFixture{
+			{TableName: "users", Strategy: markdownparser.ClearInsert, Data: []map[string]any{{"id": 1, "name": "alice"}, {"id": 2, "name": "bob"}}},
+		},
+		ExpectedResults: []markdownparser.ExpectedResultSpec{
+			{TableName: "users", Strategy: "pk-match", Data: []map[string]any{{"id": 1, "name": "carol"}}},
+		},
+	}
+
+	dir := t.TempDir()
+	options := &ExecutionOptions{Mode: FullTest, Parallel: 1, Timeout: time.Minute, ArtifactsDir: dir}
+
+	_, trace, _, err := executor.ExecuteTest(testCase, "UPDATE users SET name = 'dave' WHERE id = 1", map[string]any{}, options)
+	require.Error(t, err)
+	assert.Empty(t, trace, "the trace is only returned in verbose mode")
+
+	testDir := filepath.Join(dir, "update_user", "Rename_user_alice")
+
+	var rows []map[string]any
+
+	data, err := os.ReadFile(filepath.Join(testDir, "tables", "users.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, yaml.Unmarshal(data, &rows))
+	assert.Equal(t, []map[string]any{{"id": uint64(1), "name": "dave"}, {"id": uint64(2), "name": "bob"}}, rows)
+
+	var traces []traceArtifact
+
+	data, err = os.ReadFile(filepath.Join(testDir, "trace.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, yaml.Unmarshal(data, &traces))
+	require.NotEmpty(t, traces)
+	assert.Contains(t, traces[0].Statement, "UPDATE users")
+
+	message, err := os.ReadFile(filepath.Join(testDir, "error.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(message), "carol")
+
+	t.Run("passing test writes nothing", func(t *testing.T) {
+		dir := t.TempDir()
+		testCase := *testCase
+		testCase.ExpectedResults = []markdownparser.ExpectedResultSpec{
+			{TableName: "users", Strategy: "pk-match", Data: []map[string]any{{"id": 1, "name": "dave"}}},
+		}
+
+		_, _, _, err := executor.ExecuteTest(&testCase, "UPDATE users SET name = 'dave' WHERE id = 1", map[string]any{}, &ExecutionOptions{Mode: FullTest, Parallel: 1, Timeout: time.Minute, ArtifactsDir: dir})
+		require.NoError(t, err)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+}
@@ -0,0 +1,290 @@
+package fixtureexecutor
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/markdownparser"
+)
+
+// Clock supplies the current time a test case runs at. It is the base of the [currentdate]
+// fixture values and matchers.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+// SystemClock is the Clock of the wall time (default).
+var SystemClock Clock = systemClock{}
+
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time { return time.Time(c) }
+
+// FixedClock returns a Clock that always reports t.
+func FixedClock(t time.Time) Clock { return fixedClock(t) }
+
+// SetClock sets the clock used by the test cases that do not declare their own current time
+// with `now`. A nil clock restores SystemClock.
+func (e *Executor) SetClock(clock Clock) { e.clock = clock }
+
+// now returns the current time of testCase: the time declared with `now`, or the executor's clock
+func (e *Executor) now(testCase *markdownparser.TestCase) time.Time {
+	if testCase != nil && !testCase.Now.IsZero() {
+		return testCase.Now.UTC()
+	}
+	if e.clock == nil {
+		return time.Now().UTC()
+	}
+
+	return e.clock.Now().UTC()
+}
+
+// currentDateAnchor is appended to a [currentdate, ...] value to carry the time of the test case
+// it belongs to. Test cases run in parallel, so the time travels with the data instead of a
+// package-level variable.
+type currentDateAnchor time.Time
+
+// String keeps anchored values readable in diff output
+func (a currentDateAnchor) String() string { return time.Time(a).Format(time.RFC3339) }
+
+func isCurrentDateName(v any) bool {
+	name, ok := v.(string)
+	if !ok {
+		return false
+	}
+
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	return name == "currentdate" || name == "current_date"
+}
+
+func isLiteralMatcherName(v any) bool {
+	name, ok := v.(string)
+	if !ok {
+		return false
+	}
+
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	return name == "literal" || name == "json"
+}
+
+// anchorCurrentDate returns a copy of value whose [currentdate, ...] sequences carry now. With
+// literals, bracket strings such as "[currentdate, -1d]" (fixture and parameter shorthand) are
+// anchored as well.
+func anchorCurrentDate(value any, now time.Time, literals bool) any {
+	switch v := value.(type) {
+	case []any:
+		if len(v) > 0 && isCurrentDateName(v[0]) {
+			if _, anchored := v[len(v)-1].(currentDateAnchor); anchored {
+				return v
+			}
+
+			return append(v[:len(v):len(v)], currentDateAnchor(now))
+		}
+
+		// The contents of [literal, ...] and [json, ...] are compared as plain values, not matchers
+		if len(v) > 0 && isLiteralMatcherName(v[0]) {
+			return v
+		}
+
+		out := make([]any, len(v))
+		for i, elem := range v {
+			out[i] = anchorCurrentDate(elem, now, literals)
+		}
+
+		return out
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, elem := range v {
+			out[key] = anchorCurrentDate(elem, now, literals)
+		}
+
+		return out
+	case string:
+		if literals {
+			if arr, ok := parseBracketLiteral(v); ok && len(arr) > 0 && isCurrentDateName(arr[0]) {
+				return anchorCurrentDate(arr, now, literals)
+			}
+		}
+
+		return v
+	default:
+		return value
+	}
+}
+
+// anchorRows applies anchorCurrentDate to each row
+func anchorRows(rows []map[string]any, now time.Time, literals bool) []map[string]any {
+	if rows == nil {
+		return nil
+	}
+
+	out := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		if row != nil {
+			out[i] = anchorCurrentDate(row, now, literals).(map[string]any)
+		}
+	}
+
+	return out
+}
+
+// splitCurrentDateAnchor removes the anchor from a [currentdate, ...] value and returns the time
+// it carries. Values that were not anchored fall back to the wall time.
+func splitCurrentDateAnchor(arr []any) ([]any, time.Time) {
+	if len(arr) > 0 {
+		if anchor, ok := arr[len(arr)-1].(currentDateAnchor); ok {
+			return arr[:len(arr)-1], time.Time(anchor)
+		}
+	}
+
+	return arr, time.Now().UTC()
+}
+
+// anchorTestCase returns a copy of testCase whose expectations carry now. The parsed test case is
+// left untouched so that a retried transaction starts from the original values.
+func anchorTestCase(testCase *markdownparser.TestCase, now time.Time) *markdownparser.TestCase {
+	if testCase == nil {
+		return nil
+	}
+
+	anchored := *testCase
+	anchored.ExpectedResult = anchorRows(testCase.ExpectedResult, now, false)
+
+	if testCase.ExpectedResults != nil {
+		anchored.ExpectedResults = make([]markdownparser.ExpectedResultSpec, len(testCase.ExpectedResults))
+		for i, spec := range testCase.ExpectedResults {
+			spec.Data = anchorRows(spec.Data, now, false)
+			anchored.ExpectedResults[i] = spec
+		}
+	}
+
+	if testCase.Assertions != nil {
+		anchored.Assertions = make([]markdownparser.QueryAssertion, len(testCase.Assertions))
+		for i, assertion := range testCase.Assertions {
+			assertion.Expected = anchorCurrentDate(assertion.Expected, now, false)
+			anchored.Assertions[i] = assertion
+		}
+	}
+
+	if testCase.Steps != nil {
+		anchored.Steps = make([]markdownparser.TestStep, len(testCase.Steps))
+		for i, step := range testCase.Steps {
+			anchoredStep := anchorTestCase(&markdownparser.TestCase{ExpectedResult: step.ExpectedResult, ExpectedResults: step.ExpectedResults}, now)
+			step.ExpectedResult = anchoredStep.ExpectedResult
+			step.ExpectedResults = anchoredStep.ExpectedResults
+			anchored.Steps[i] = step
+		}
+	}
+
+	return &anchored
+}
+
+type currentDateKey struct{}
+
+// withCurrentDate binds the time of the running test case to ctx for the fixtures inserted with it
+func withCurrentDate(ctx context.Context, now time.Time) context.Context {
+	return context.WithValue(ctx, currentDateKey{}, now)
+}
+
+func currentDateFrom(ctx context.Context) (time.Time, bool) {
+	if ctx == nil {
+		return time.Time{}, false
+	}
+
+	now, ok := ctx.Value(currentDateKey{}).(time.Time)
+
+	return now, ok
+}
+
+// currentTimePattern matches the current time functions replaced for a test case declaring `now`
+var currentTimePattern = regexp.MustCompile(`(?i)\bNOW\s*\(\s*\)|\bCURRENT_TIMESTAMP\b(\s*\(\s*\d*\s*\))?`)
+
+// rewriteCurrentTime replaces NOW() and CURRENT_TIMESTAMP in query with a timestamp literal of now,
+// so that a test case declaring `now` sees the same time in the database as in its fixtures.
+// Quoted literals, quoted identifiers and comments are left as written.
+func (e *Executor) rewriteCurrentTime(query string, now time.Time) string {
+	literal := e.timestampLiteral(now)
+
+	var b strings.Builder
+
+	rewrite := func(code string) {
+		b.WriteString(currentTimePattern.ReplaceAllLiteralString(code, literal))
+	}
+
+	start := 0
+	for i := 0; i < len(query); i++ {
+		var end int
+
+		switch {
+		case query[i] == '\'' || query[i] == '"' || query[i] == '`':
+			end = closingQuote(query, i)
+		case strings.HasPrefix(query[i:], "--"):
+			end = strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query)
+			} else {
+				end += i
+			}
+		case strings.HasPrefix(query[i:], "/*"):
+			end = strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				end = len(query)
+			} else {
+				end += i + 4
+			}
+		default:
+			continue
+		}
+
+		rewrite(query[start:i])
+		b.WriteString(query[i:end])
+		start = end
+		i = end - 1
+	}
+	rewrite(query[start:])
+
+	return b.String()
+}
+
+// closingQuote returns the index after the quote closing the literal that starts at query[start].
+// A doubled quote is part of the literal.
+func closingQuote(query string, start int) int {
+	quote := query[start]
+	for i := start + 1; i < len(query); i++ {
+		if query[i] != quote {
+			continue
+		}
+		if i+1 < len(query) && query[i+1] == quote {
+			i++
+			continue
+		}
+
+		return i + 1
+	}
+
+	return len(query)
+}
+
+// timestampLiteral renders now as a timestamp literal of the dialect
+func (e *Executor) timestampLiteral(now time.Time) string {
+	now = now.UTC()
+
+	switch {
+	case e.isPostgresFamily():
+		return "'" + now.Format(time.RFC3339Nano) + "'::timestamptz"
+	case e.dialect == snapsql.DialectSQLite:
+		// CURRENT_TIMESTAMP of SQLite is text in this format
+		return "'" + now.Format("2006-01-02 15:04:05") + "'"
+	default:
+		return "TIMESTAMP '" + now.Format("2006-01-02 15:04:05.999999") + "'"
+	}
+}
@@ -0,0 +1,102 @@
+package fixtureexecutor
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/markdownparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteCurrentTime(t *testing.T) {
+	now := time.Date(2024, 6, 1, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		dialect snapsql.Dialect
+		input   string
+		want    string
+	}{
+		{name: "postgres", dialect: snapsql.DialectPostgres, input: "SELECT * FROM t WHERE at < now()", want: "SELECT * FROM t WHERE at < '2024-06-01T09:30:00Z'::timestamptz"},
+		{name: "mysql", dialect: snapsql.DialectMySQL, input: "UPDATE t SET at = CURRENT_TIMESTAMP(6)", want: "UPDATE t SET at = TIMESTAMP '2024-06-01 09:30:00'"},
+		{name: "sqlite", dialect: snapsql.DialectSQLite, input: "SELECT CURRENT_TIMESTAMP, NOW ( )", want: "SELECT '2024-06-01 09:30:00', '2024-06-01 09:30:00'"},
+		{name: "literals and comments", dialect: snapsql.DialectSQLite, input: "SELECT 'now()', \"CURRENT_TIMESTAMP\" -- now()\n, now() /* CURRENT_TIMESTAMP */", want: "SELECT 'now()', \"CURRENT_TIMESTAMP\" -- now()\n, '2024-06-01 09:30:00' /* CURRENT_TIMESTAMP */"},
+		{name: "identifiers", dialect: snapsql.DialectSQLite, input: "SELECT known(), current_timestamp_col FROM t", want: "SELECT known(), current_timestamp_col FROM t"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewExecutor(nil, tt.dialect, nil).rewriteCurrentTime(tt.input, now))
+		})
+	}
+}
+
+func TestAnchorCurrentDate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	original := []any{"currentdate", "-1d"}
+
+	anchored := anchorCurrentDate(map[string]any{"at": original, "tags": []any{"a"}, "raw": "[currentdate, +1h]"}, now, true).(map[string]any)
+	assert.Equal(t, []any{"currentdate", "-1d"}, original, "the parsed value must not be modified")
+
+	args, base := splitCurrentDateAnchor(anchored["at"].([]any))
+	assert.Equal(t, original, args)
+	assert.Equal(t, now, base)
+	assert.Equal(t, []any{"a"}, anchored["tags"])
+
+	resolved, err := resolveFixtureValue(anchored["raw"], nil)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(time.Hour), resolved)
+
+	// Anchoring twice keeps the first anchor
+	twice := anchorCurrentDate(anchored["at"], now.Add(time.Hour), false).([]any)
+	_, base = splitCurrentDateAnchor(twice)
+	assert.Equal(t, now, base)
+}
+
+func TestExecutor_FixedNow(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE events (id INTEGER PRIMARY KEY, created_at TIMESTAMP NOT NULL)`)
+	require.NoError(t, err)
+
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	testCase := &markdownparser.TestCase{
+		Name: "events before now",
+		Now:  now,
+		Fixtures: []markdownparser.TableFixture{{
+			TableName: "events",
+			Strategy:  markdownparser.ClearInsert,
+			Data: []map[string]any{
+				{"id": 1, "created_at": []any{"currentdate", "-1d"}},
+				{"id": 2, "created_at": []any{"currentdate", "+1d"}},
+			},
+		}},
+		ExpectedResult: []map[string]any{
+			{"id": 1, "created_at": []any{"currentdate", "-1d"}},
+		},
+	}
+
+	options := &ExecutionOptions{Mode: FullTest, Parallel: 1, Timeout: time.Minute}
+	executor := NewExecutor(db, snapsql.DialectSQLite, nil)
+
+	result, _, _, err := executor.ExecuteTest(testCase, "SELECT id, created_at FROM events WHERE created_at < CURRENT_TIMESTAMP", map[string]any{}, options)
+	require.NoError(t, err)
+	require.Len(t, result.Data, 1)
+
+	t.Run("executor clock", func(t *testing.T) {
+		testCase := *testCase
+		testCase.Now = time.Time{}
+
+		executor.SetClock(FixedClock(now.Add(time.Hour)))
+		assert.Equal(t, now.Add(time.Hour), executor.now(&testCase))
+
+		executor.SetClock(nil)
+		assert.WithinDuration(t, time.Now(), executor.now(&testCase), time.Minute)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestMain(m *testing.M) {
@@ -39,11 +40,11 @@ func TestFormatDiffUnifiedYAML(t *testing.T) {
 
 	checks := []string{
 		"Table: lists",
-		"- Expected",
-		"+ Actual",
-		"id: 10 [mismatch]",
-		"+ name: Todo",
-		"- name: Todo!",
+		"--- Expected",
+		"+++ Actual",
+		"@@ id: 10 [mismatch] @@",
+		"- name: Todo\n",
+		"+ name: Todo!",
 	}
 	for _, want := range checks {
 		if !strings.Contains(diffText, want) {
@@ -57,3 +58,43 @@ func TestFormatDiffUnifiedYAML(t *testing.T) {
 		}
 	}
 }
+
+func TestFormatDiffUnifiedYAML_RowsAndStatus(t *testing.T) {
+	diff := &DiffError{
+		Table:            "lists",
+		PrimaryKeys:      []string{"id"},
+		RowCountMismatch: true,
+		ExpectedRows:     2,
+		ActualRows:       2,
+		RowDiffs: []RowDiff{
+			{Key: map[string]any{"id": 3}, RowStatus: "unexpected", Diffs: []ColumnDiff{{Column: "__row__", Expected: "<missing>", Actual: "id=3, name=c"}}},
+			{Key: map[string]any{"id": 1}, Diffs: []ColumnDiff{
+				{Column: "updated_at", Expected: "[currentdate]", Actual: "2020-01-01T00:00:00Z", Reason: "timestamp outside tolerance"},
+				{Column: "name", Expected: "a", Actual: "A"},
+			}},
+			{Key: map[string]any{"id": 2}, RowStatus: "missing", Diffs: []ColumnDiff{{Column: "__row__", Expected: "id=2, name=b", Actual: "<missing>"}}},
+			{Key: map[string]any{"row_index": 0}, Diffs: []ColumnDiff{{Column: "total", Expected: 1, Actual: nil}}},
+		},
+	}
+
+	want := `Table: lists
+--- Expected
++++ Actual
+@@ rows @@
+- rows: 2
++ rows: 2
+@@ row #1 [mismatch] @@
+- total: 1
++ total: <nil>
+@@ id: 1 [mismatch] @@
+- name: a
++ name: A
+- updated_at: [currentdate]
++ updated_at: 2020-01-01T00:00:00Z  # timestamp outside tolerance
+@@ id: 2 [missing] @@
+- id=2, name=b
+@@ id: 3 [unexpected] @@
++ id=3, name=c`
+
+	assert.Equal(t, want, FormatDiffUnifiedYAML(diff))
+}
@@ -0,0 +1,50 @@
+package fixtureexecutor
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/markdownparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckMaxDuration(t *testing.T) {
+	result := &ValidationResult{Duration: 62 * time.Millisecond}
+
+	assert.NoError(t, checkMaxDuration(&markdownparser.TestCase{}, result))
+	assert.NoError(t, checkMaxDuration(&markdownparser.TestCase{MaxDuration: 100 * time.Millisecond}, result))
+	assert.NoError(t, checkMaxDuration(&markdownparser.TestCase{MaxDuration: 50 * time.Millisecond}, nil))
+
+	err := checkMaxDuration(&markdownparser.TestCase{MaxDuration: 50 * time.Millisecond}, result)
+	assert.True(t, errors.Is(err, ErrMaxDurationExceeded), "got %v", err)
+	assert.Equal(t, FailureKindAssertion, ClassifyFailure(err))
+	assert.Contains(t, err.Error(), "query took 62ms, exceeding max_duration 50ms")
+}
+
+func TestExecutor_MaxDuration(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY)`)
+	require.NoError(t, err)
+
+	executor := NewExecutor(db, snapsql.DialectSQLite, nil)
+	options := &ExecutionOptions{Mode: FullTest, Parallel: 1, Timeout: time.Minute}
+
+	testCase := &markdownparser.TestCase{Name: "budget", MaxDuration: time.Minute, ExpectedResult: []map[string]any{}}
+	_, _, _, err = executor.ExecuteTest(testCase, "SELECT id FROM users", map[string]any{}, options)
+	require.NoError(t, err)
+
+	testCase.MaxDuration = time.Nanosecond
+	result, _, _, err := executor.ExecuteTest(testCase, "SELECT id FROM users", map[string]any{}, options)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrMaxDurationExceeded), "got %v", err)
+	require.NotNil(t, result)
+	assert.Greater(t, result.Duration, time.Nanosecond)
+}
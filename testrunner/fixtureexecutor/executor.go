@@ -4,17 +4,22 @@ package fixtureexecutor
 import (
 	"context"
 	"database/sql"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"math"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
-	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/goccy/go-yaml"
@@ -47,10 +52,33 @@ var (
 	keywordDeleteRegexp      = regexp.MustCompile(`\bDELETE\b`)
 	keywordInsertRegexp      = regexp.MustCompile(`\bINSERT\b`)
 	errUnknownFixtureColumn  = errors.New("fixture row contains unknown column")
+	errExternalCSVHeader     = errors.New("external CSV file has an invalid header")
+	errExternalCSVValue      = errors.New("external CSV cell cannot be converted to column type")
+	errExpectedNumber        = errors.New("expected number")
+	errOutsideTolerance      = errors.New("value outside tolerance")
+	errOutsideRange          = errors.New("value outside range")
+	errExpectedJSON          = errors.New("expected JSON value")
+	errJSONMismatch          = errors.New("json mismatch")
+	errDeltaSnapshotMissing  = errors.New("table snapshot for delta expectation not found")
+	errDeltaMismatch         = errors.New("delta change mismatch")
+	errDeltaUnexpectedChange = errors.New("unexpected table change")
+	errAssertionNotScalar    = errors.New("assertion query must return a single value")
+	errAssertionMismatch     = errors.New("assertion value mismatch")
+	errDeferNotSupported     = errors.New("defer-constraints is not supported by the dialect")
+	errTruncateNotSupported  = errors.New("truncate strategy is not supported by the dialect")
 )
 
 const maxTraceRows = 20
 
+const (
+	// mysqlMaxPlaceholders is the number of placeholders MySQL and MariaDB accept in a prepared statement
+	mysqlMaxPlaceholders = 65535
+	// sqliteMaxPlaceholders is SQLITE_MAX_VARIABLE_NUMBER of SQLite builds older than 3.32.0
+	sqliteMaxPlaceholders = 999
+	// maxFixtureRowsPerInsert keeps multi-row fixture INSERT statements below max_allowed_packet
+	maxFixtureRowsPerInsert = 500
+)
+
 // SQLTrace captures executed statements for verbose output.
 type SQLTrace struct {
 	Label         string
@@ -83,6 +111,25 @@ type ExecutionOptions struct {
 	SlowQueryThreshold time.Duration
 	TableMetadata      map[string]explain.TableMetadata
 	TableReferenceMap  map[string]intermediate.TableReferenceInfo
+	// Explain records the plan of the main query and fails the test when it matches one of ExplainRules
+	Explain      bool
+	ExplainRules []explain.Rule
+	// SoftDeleteColumns maps lower-cased table names to their soft delete column (tables.<name>.soft_delete).
+	// pk-exists / pk-not-exists treat rows whose column is set as deleted.
+	SoftDeleteColumns map[string]string
+	// Shuffle randomizes the execution order of test cases (and of the cases sharing a setup) with
+	// ShuffleSeed, so tests that depend on state left by others can be found and reproduced.
+	Shuffle     bool
+	ShuffleSeed int64
+	// MaxFailures stops the run once this many test cases have failed (0 = no limit). Test cases that
+	// have not started yet are skipped and running ones are cancelled through their context.
+	MaxFailures int
+	// Retries reruns a failed test case up to this many times. Test cases that pass on a retry
+	// are reported as flaky.
+	Retries int
+	// ArtifactsDir receives the actual table contents and the SQL trace of test cases whose
+	// table-state validation fails (one directory per test case). Empty disables the artifacts.
+	ArtifactsDir string
 }
 
 // DefaultExecutionOptions returns default execution options
@@ -97,6 +144,15 @@ func DefaultExecutionOptions() *ExecutionOptions {
 	}
 }
 
+// withTimeout bounds ctx by Timeout. A zero Timeout keeps the deadline of ctx.
+func (o *ExecutionOptions) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if o.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, o.Timeout)
+}
+
 // QueryType represents the type of SQL query
 type QueryType int
 
@@ -127,36 +183,8 @@ type ValidationResult struct {
 	Data         []map[string]any
 	RowsAffected int64
 	QueryType    QueryType
-}
-
-var (
-	currentDateAnchorMu  sync.RWMutex
-	currentDateAnchor    time.Time
-	currentDateAnchorSet bool
-)
-
-func setCurrentDateAnchor(t time.Time) {
-	currentDateAnchorMu.Lock()
-	currentDateAnchor = t
-	currentDateAnchorSet = true
-	currentDateAnchorMu.Unlock()
-}
-
-func clearCurrentDateAnchor() {
-	currentDateAnchorMu.Lock()
-	currentDateAnchorSet = false
-	currentDateAnchorMu.Unlock()
-}
-
-func currentDateAnchorNow() time.Time {
-	currentDateAnchorMu.RLock()
-	if currentDateAnchorSet {
-		t := currentDateAnchor
-		currentDateAnchorMu.RUnlock()
-		return t
-	}
-	currentDateAnchorMu.RUnlock()
-	return time.Now().UTC()
+	// Duration is the time spent executing the main query (EXPLAIN is not included)
+	Duration time.Duration
 }
 
 // ExpectedResultsStrategy defines comparison strategy for table state validation.
@@ -170,16 +198,20 @@ type TestExecution struct {
 	Parameters         map[string]any // Parameters from test case
 	Args               []any          // Positional arguments for PreparedSQL
 	Options            *ExecutionOptions
+	Context            context.Context // Bounds every statement of the test case (timeout and cancellation)
 	Transaction        *sql.Tx
 	Executor           *Executor
 	Trace              []SQLTrace
 	TimeAnchor         time.Time
 	SlowQueryThreshold time.Duration
 	Performance        *explain.PerformanceEvaluation
+	TableSnapshots     map[string][]map[string]any // Table rows before the main query (delta strategy)
+	FailedTables       map[string][]map[string]any // Actual rows of tables that failed validation (ArtifactsDir)
 }
 
 func (te *TestExecution) addTrace(label, statement string, params map[string]any, args []any, result *ValidationResult) {
-	if te == nil || te.Options == nil || !te.Options.Verbose {
+	// The trace is also kept for the failure artifacts
+	if te == nil || te.Options == nil || (!te.Options.Verbose && te.Options.ArtifactsDir == "") {
 		return
 	}
 
@@ -276,14 +308,17 @@ func sanitizeRows(rows []map[string]any) ([]map[string]any, bool) {
 	return out, truncated
 }
 
-func normalizeFixtureRows(rows []map[string]any) ([]map[string]any, error) {
+// normalizeFixtureRows resolves special values in fixture rows. seed selects the generated values
+// (see fixtureSeed).
+func normalizeFixtureRows(rows []map[string]any, seed int64) ([]map[string]any, error) {
 	if len(rows) == 0 {
 		return rows, nil
 	}
 
+	gen := newValueGenerator(seed)
 	result := make([]map[string]any, len(rows))
 	for i, row := range rows {
-		conv, err := normalizeFixtureRow(row)
+		conv, err := normalizeFixtureRow(row, i, gen)
 		if err != nil {
 			return nil, err
 		}
@@ -293,14 +328,15 @@ func normalizeFixtureRows(rows []map[string]any) ([]map[string]any, error) {
 	return result, nil
 }
 
-func normalizeFixtureRow(row map[string]any) (map[string]any, error) {
+func normalizeFixtureRow(row map[string]any, index int, gen *valueGenerator) (map[string]any, error) {
 	if row == nil {
 		return nil, nil
 	}
 
 	result := make(map[string]any, len(row))
 	for k, v := range row {
-		nv, err := resolveFixtureValue(v)
+		gen.moveTo(index, k)
+		nv, err := resolveFixtureValue(v, gen)
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve fixture value for %s: %w", k, err)
 		}
@@ -310,7 +346,9 @@ func normalizeFixtureRow(row map[string]any) (map[string]any, error) {
 	return result, nil
 }
 
-func resolveFixtureValue(value any) (any, error) {
+// resolveFixtureValue resolves special fixture values such as [null], [currentdate, -1d] and
+// the generators [seq], [uuid] and [faker, kind]. gen may be nil outside of fixture rows.
+func resolveFixtureValue(value any, gen *valueGenerator) (any, error) {
 	switch v := value.(type) {
 	case []any:
 		if len(v) == 0 {
@@ -331,7 +369,7 @@ func resolveFixtureValue(value any) (any, error) {
 			matcher := strings.ToLower(strings.TrimSpace(first))
 			switch matcher {
 			case "currentdate", "current_date":
-				base := currentDateAnchorNow()
+				v, base := splitCurrentDateAnchor(v)
 				offset := time.Duration(0)
 				if len(v) >= 2 {
 					if durStr, ok := v[1].(string); ok && strings.TrimSpace(durStr) != "" {
@@ -344,11 +382,17 @@ func resolveFixtureValue(value any) (any, error) {
 				}
 				return base.Add(offset), nil
 			}
+			if isGeneratorName(matcher) {
+				if gen == nil {
+					gen = newValueGenerator(generatorSeed)
+				}
+				return gen.generate(matcher, v[1:])
+			}
 		}
 
 		resolved := make([]any, len(v))
 		for i, elem := range v {
-			val, err := resolveFixtureValue(elem)
+			val, err := resolveFixtureValue(elem, gen)
 			if err != nil {
 				return nil, err
 			}
@@ -358,7 +402,7 @@ func resolveFixtureValue(value any) (any, error) {
 	case map[string]any:
 		out := make(map[string]any, len(v))
 		for key, elem := range v {
-			val, err := resolveFixtureValue(elem)
+			val, err := resolveFixtureValue(elem, gen)
 			if err != nil {
 				return nil, err
 			}
@@ -367,7 +411,7 @@ func resolveFixtureValue(value any) (any, error) {
 		return out, nil
 	case string:
 		if arr, ok := parseBracketLiteral(v); ok {
-			return resolveFixtureValue(arr)
+			return resolveFixtureValue(arr, gen)
 		}
 		return v, nil
 	default:
@@ -398,6 +442,10 @@ type Executor struct {
 	dialect   snapsql.Dialect
 	tableInfo map[string]*snapsql.TableInfo
 	baseDir   string
+	clock     Clock
+
+	// fixtureRuns counts the fixture lists inserted by this executor; it feeds fixtureSeed
+	fixtureRuns atomic.Uint64
 }
 
 // NewExecutor creates a new fixture executor
@@ -414,9 +462,44 @@ func (e *Executor) SetBaseDir(dir string) { e.baseDir = dir }
 
 // ExecuteTest executes a complete test case within a transaction
 func (e *Executor) ExecuteTest(testCase *markdownparser.TestCase, sql string, parameters map[string]any, opts *ExecutionOptions) (*ValidationResult, []SQLTrace, *explain.PerformanceEvaluation, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	return e.ExecuteTestContext(context.Background(), testCase, sql, parameters, opts)
+}
+
+// ExecuteTestContext is ExecuteTest bound to ctx. Cancelling ctx rolls back the transaction, which
+// aborts the test case at its next statement.
+//
+// On CockroachDB a test case whose transaction is aborted with a serialization failure (40001) is
+// run again in a new transaction, up to cockroachRetryAttempts times in total.
+func (e *Executor) ExecuteTestContext(ctx context.Context, testCase *markdownparser.TestCase, sql string, parameters map[string]any, opts *ExecutionOptions) (*ValidationResult, []SQLTrace, *explain.PerformanceEvaluation, error) {
+	if opts == nil {
+		opts = DefaultExecutionOptions()
+	}
+
+	ctx, cancel := opts.withTimeout(ctx)
 	defer cancel()
 
+	for attempt := 1; ; attempt++ {
+		result, trace, perf, err := e.executeTestAttempt(ctx, testCase, sql, parameters, opts)
+		if err == nil || e.dialect != snapsql.DialectCockroach || attempt >= cockroachRetryAttempts ||
+			!isSerializationFailure(err) || ctx.Err() != nil {
+			return result, trace, perf, err
+		}
+	}
+}
+
+// cockroachRetryAttempts is the number of times a CockroachDB test case is run when its
+// transaction keeps failing with a serialization failure
+const cockroachRetryAttempts = 3
+
+// isSerializationFailure reports whether err carries SQLSTATE 40001 (pgx and lib/pq errors)
+func isSerializationFailure(err error) bool {
+	var stateErr interface{ SQLState() string }
+
+	return errors.As(err, &stateErr) && stateErr.SQLState() == "40001"
+}
+
+// executeTestAttempt runs a test case once in its own transaction
+func (e *Executor) executeTestAttempt(ctx context.Context, testCase *markdownparser.TestCase, sql string, parameters map[string]any, opts *ExecutionOptions) (*ValidationResult, []SQLTrace, *explain.PerformanceEvaluation, error) {
 	tx, err := e.db.BeginTx(ctx, nil)
 	if err != nil {
 		return nil, nil, nil, wrapDefinitionFailure(err, "failed to begin transaction")
@@ -430,15 +513,151 @@ func (e *Executor) ExecuteTest(testCase *markdownparser.TestCase, sql string, pa
 		}
 	}()
 
+	// Without transactions the rows written by the previous test case are still in the database, so
+	// every table the test case touches is emptied before its fixtures are inserted
+	if !snapsql.SupportsTransactions(e.dialect) {
+		if err := e.truncateTables(ctx, tx, touchedTables(testCase, opts.TableReferenceMap)); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	// Without transactions the setup cannot be shared through savepoints, so the test runner does
+	// not group the test cases and every test case inserts the setup fixtures again
+	if testCase.Setup != nil && !snapsql.SupportsTransactions(e.dialect) {
+		if err := e.executeFixtures(ctx, tx, testCase.Setup.Fixtures); err != nil {
+			return nil, nil, nil, wrapDefinitionFailure(err, "failed to execute setup fixtures")
+		}
+	}
+
+	return e.executeTestInTx(ctx, tx, testCase, sql, parameters, opts)
+}
+
+// touchedTables returns the tables a test case reads or writes: the tables of its fixtures and setup
+// fixtures, the tables of its table-state expectations and the physical tables the template references
+func touchedTables(testCase *markdownparser.TestCase, refs map[string]intermediate.TableReferenceInfo) []string {
+	seen := make(map[string]struct{})
+	add := func(name string) {
+		if name = strings.TrimSpace(name); name != "" {
+			seen[name] = struct{}{}
+		}
+	}
+
+	if testCase != nil {
+		if testCase.Setup != nil {
+			for _, fixture := range testCase.Setup.Fixtures {
+				add(fixture.TableName)
+			}
+		}
+
+		for _, fixture := range testCase.Fixtures {
+			add(fixture.TableName)
+		}
+
+		for _, spec := range testCase.ExpectedResults {
+			add(spec.TableName)
+		}
+
+		for _, step := range testCase.Steps {
+			for _, spec := range step.ExpectedResults {
+				add(spec.TableName)
+			}
+		}
+	}
+
+	// The map is keyed by aliases and CTE names as well; only resolved physical tables are emptied
+	for _, ref := range refs {
+		add(ref.TableName)
+	}
+
+	return slices.Sorted(maps.Keys(seen))
+}
+
+// truncateTables empties tables before a test case runs on a dialect without transactions
+func (e *Executor) truncateTables(ctx context.Context, tx *sql.Tx, tables []string) error {
+	for _, table := range tables {
+		stmts, err := e.truncateStatements(table)
+		if err != nil {
+			return wrapDefinitionFailure(err, "failed to isolate the test case")
+		}
+
+		for _, stmt := range stmts {
+			if _, err := tx.ExecContext(ctx, stmt); err != nil {
+				return wrapDefinitionFailureWithContext(map[string]string{"table": table, "operation": "truncate", "sql": stmt}, err, "failed to truncate table %s", table)
+			}
+		}
+	}
+
+	return nil
+}
+
+// BeginSetup begins the transaction shared by the test cases of a file and inserts the setup
+// fixtures into it. The caller commits or rolls back the returned transaction after running the
+// test cases with ExecuteTestInSavepoint.
+func (e *Executor) BeginSetup(ctx context.Context, setup *markdownparser.FixtureSetup) (*sql.Tx, error) {
+	tx, err := e.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, wrapDefinitionFailure(err, "failed to begin transaction")
+	}
+
+	if setup != nil {
+		if err := e.executeFixtures(ctx, tx, setup.Fixtures); err != nil {
+			tx.Rollback()
+			return nil, wrapDefinitionFailure(err, "failed to execute setup fixtures")
+		}
+	}
+
+	return tx, nil
+}
+
+// ExecuteTestInSavepoint executes a test case inside a SAVEPOINT of tx opened by BeginSetup.
+// The savepoint is rolled back afterwards so the next test case sees only the setup fixtures.
+// With opts.Commit the savepoint is released instead and its changes stay in tx.
+func (e *Executor) ExecuteTestInSavepoint(ctx context.Context, tx *sql.Tx, testCase *markdownparser.TestCase, sql string, parameters map[string]any, opts *ExecutionOptions) (*ValidationResult, []SQLTrace, *explain.PerformanceEvaluation, error) {
 	if opts == nil {
 		opts = DefaultExecutionOptions()
 	}
 
-	anchor := time.Now().UTC()
-	setCurrentDateAnchor(anchor)
-	defer clearCurrentDateAnchor()
+	testCtx, cancel := opts.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := tx.ExecContext(testCtx, "SAVEPOINT "+testCaseSavepoint); err != nil {
+		return nil, nil, nil, wrapDefinitionFailure(err, "failed to create savepoint")
+	}
+
+	result, trace, perf, err := e.executeTestInTx(testCtx, tx, testCase, sql, parameters, opts)
+
+	// The savepoint is released or rolled back even after ctx expired, so the next test case
+	// sharing the setup starts from the setup state
+	ctx = context.WithoutCancel(ctx)
+
+	if err == nil && opts.Commit {
+		if _, releaseErr := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+testCaseSavepoint); releaseErr != nil {
+			err = wrapDefinitionFailure(releaseErr, "failed to release savepoint")
+		}
+
+		return result, trace, perf, err
+	}
+
+	if _, rollbackErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+testCaseSavepoint); rollbackErr != nil && err == nil {
+		err = wrapDefinitionFailure(rollbackErr, "failed to roll back to savepoint")
+	}
+
+	return result, trace, perf, err
+}
+
+// testCaseSavepoint is the savepoint name used by ExecuteTestInSavepoint
+const testCaseSavepoint = "snapsql_test_case"
+
+// executeTestInTx runs the steps of a test case on tx
+func (e *Executor) executeTestInTx(ctx context.Context, tx *sql.Tx, testCase *markdownparser.TestCase, sql string, parameters map[string]any, opts *ExecutionOptions) (*ValidationResult, []SQLTrace, *explain.PerformanceEvaluation, error) {
+	anchor := e.now(testCase)
+	ctx = withCurrentDate(ctx, anchor)
+	testCase = anchorTestCase(testCase, anchor)
 
 	finalSQL, args := e.resolveExecutableSQL(testCase, sql)
+	if testCase != nil && !testCase.Now.IsZero() {
+		finalSQL = e.rewriteCurrentTime(finalSQL, anchor)
+	}
 
 	execution := &TestExecution{
 		TestCase:    testCase,
@@ -446,12 +665,13 @@ func (e *Executor) ExecuteTest(testCase *markdownparser.TestCase, sql string, pa
 		Parameters:  parameters,
 		Args:        args,
 		Options:     opts,
+		Context:     ctx,
 		Transaction: tx,
 		Executor:    e,
 		TimeAnchor:  anchor,
 	}
 
-	if err := NormalizeParameters(execution.Parameters); err != nil {
+	if err := NormalizeParametersAt(execution.Parameters, anchor); err != nil {
 		return nil, nil, nil, wrapDefinitionFailure(err, "failed to normalize parameters")
 	}
 
@@ -467,7 +687,62 @@ func (e *Executor) ExecuteTest(testCase *markdownparser.TestCase, sql string, pa
 	}
 
 	result, err := e.executeTestSteps(execution)
-	return result, execution.Trace, execution.Performance, err
+	if err == nil {
+		err = checkExplainRules(execution.Performance)
+	}
+
+	if err == nil && opts.Mode != FixtureOnly {
+		for _, statement := range mainStatements(testCase, finalSQL) {
+			if err = checkScopeAssertion(testCase, statement); err != nil {
+				break
+			}
+		}
+	}
+
+	if err == nil && opts.Mode != FixtureOnly {
+		err = checkMaxDuration(testCase, result)
+	}
+
+	if err != nil && opts.ArtifactsDir != "" && len(execution.FailedTables) > 0 {
+		if artifactErr := writeFailureArtifacts(opts.ArtifactsDir, execution, err); artifactErr != nil {
+			err = errors.Join(err, artifactErr)
+		}
+	}
+
+	var trace []SQLTrace
+	if opts.Verbose {
+		trace = execution.Trace
+	}
+
+	return result, trace, execution.Performance, err
+}
+
+// checkExplainRules turns rule violations recorded by --explain into an assertion failure
+func checkExplainRules(perf *explain.PerformanceEvaluation) error {
+	if perf == nil || len(perf.Violations) == 0 {
+		return nil
+	}
+
+	messages := make([]string, 0, len(perf.Violations))
+	for _, v := range perf.Violations {
+		messages = append(messages, v.Message)
+	}
+
+	return wrapAssertionFailure(ErrExplainRuleViolation, "%s", strings.Join(messages, "; "))
+}
+
+// checkMaxDuration fails a test case whose main query took longer than its max_duration budget
+func checkMaxDuration(testCase *markdownparser.TestCase, result *ValidationResult) error {
+	if testCase == nil || testCase.MaxDuration <= 0 || result == nil {
+		return nil
+	}
+
+	if result.Duration <= testCase.MaxDuration {
+		return nil
+	}
+
+	return wrapAssertionFailure(ErrMaxDurationExceeded, "query took %s, exceeding max_duration %s",
+		result.Duration.Round(time.Microsecond), testCase.MaxDuration)
 }
 
 func formatArgsForContext(values []any) string {
@@ -502,7 +777,11 @@ func (e *Executor) executeTestSteps(execution *TestExecution) (*ValidationResult
 // NOTE: For now we only support strategies against execution.TestCase.ExpectedResults when
 // a table name is provided and the original (legacy) ExpectedResult slice is empty.
 // SELECT/RETURNING queries still use validateVerifyResults.
-func (e *Executor) validateTableStateBySpec(tx *sql.Tx, spec markdownparser.ExpectedResultSpec) error {
+func (e *Executor) validateTableStateBySpec(execution *TestExecution, spec markdownparser.ExpectedResultSpec) (err error) {
+	ctx := execution.Context
+	tx := execution.Transaction
+	opts := execution.Options
+
 	if spec.TableName == "" {
 		return nil // Nothing to do (legacy path handles unnamed expected results)
 	}
@@ -513,7 +792,7 @@ func (e *Executor) validateTableStateBySpec(tx *sql.Tx, spec markdownparser.Expe
 
 	// Load expected data from external file if specified
 	if spec.ExternalFile != "" && len(spec.Data) == 0 {
-		rows, err := e.loadExternalRows(spec.ExternalFile)
+		rows, err := e.loadExpectedRows(execution, spec.ExternalFile, spec.TableName)
 		if err != nil {
 			return fmt.Errorf("failed to load expected results from external file: %w", err)
 		}
@@ -526,6 +805,49 @@ func (e *Executor) validateTableStateBySpec(tx *sql.Tx, spec markdownparser.Expe
 		return fmt.Errorf("%w: %s", errTableInfoNotFound, spec.TableName)
 	}
 
+	actual, pkCols, err := e.fetchTableRows(ctx, tx, ti, spec.TableName)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			execution.recordFailedTable(spec.TableName, actual)
+		}
+	}()
+
+	// ignore_columns / only_columns narrow the compared columns; the artifacts keep the full rows
+	spec.Data = projectColumns(spec, spec.Data, pkCols)
+	compared := projectColumns(spec, actual, pkCols)
+
+	switch strategy {
+	case "all":
+		// expect full match with order irrelevant? design doc implies exact table contents.
+		// We compare counts and then match rows by index after sorting by PK (already ordered if PK exists).
+		if err := compareSpecRows(spec, spec.Data, compared, spec.TableName, pkCols, false); err != nil {
+			return err
+		}
+		return nil
+	case "pk-match":
+		return e.comparePKMatch(ti, spec.Data, compared, true)
+	case "pk-exists":
+		return e.comparePKMatch(ti, spec.Data, withoutSoftDeletedRows(compared, spec.TableName, opts), false)
+	case "pk-not-exists":
+		return e.comparePKNotExists(ti, spec.Data, withoutSoftDeletedRows(compared, spec.TableName, opts))
+	case "delta":
+		before, ok := execution.TableSnapshots[spec.TableName]
+		if !ok {
+			return fmt.Errorf("%w: %s", errDeltaSnapshotMissing, spec.TableName)
+		}
+		before = projectColumns(spec, before, pkCols)
+		return compareDelta(pkCols, spec.Data, withoutSoftDeletedRows(before, spec.TableName, opts), withoutSoftDeletedRows(compared, spec.TableName, opts))
+	default:
+		return fmt.Errorf("unknown expected results strategy: %s", strategy)
+	}
+}
+
+// fetchTableRows returns all rows of a table ordered by primary key, together with the primary key columns
+func (e *Executor) fetchTableRows(ctx context.Context, tx *sql.Tx, ti *snapsql.TableInfo, tableName string) ([]map[string]any, []string, error) {
 	// Build SELECT to fetch current table rows (simple full scan ordered by primary keys if exists)
 	cols := make([]string, 0, len(ti.Columns))
 	for name := range ti.Columns {
@@ -542,13 +864,13 @@ func (e *Executor) validateTableStateBySpec(tx *sql.Tx, spec markdownparser.Expe
 	if len(pkCols) > 0 {
 		order = " ORDER BY " + strings.Join(pkCols, ",")
 	}
-	query := fmt.Sprintf("SELECT %s FROM %s%s", strings.Join(cols, ","), spec.TableName, order)
+	query := fmt.Sprintf("SELECT %s FROM %s%s", strings.Join(cols, ","), tableName, order)
 	// Use a bounded context to avoid indefinite blocking (especially on SQLite under edge cases)
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 	rows, err := tx.QueryContext(ctx, query)
 	if err != nil {
-		return fmt.Errorf("failed to query table state: %w", err)
+		return nil, nil, fmt.Errorf("failed to query table state: %w", err)
 	}
 	defer rows.Close()
 
@@ -565,37 +887,122 @@ func (e *Executor) validateTableStateBySpec(tx *sql.Tx, spec markdownparser.Expe
 			scanPtrs[i] = &scanVals[i]
 		}
 		if err := rows.Scan(scanPtrs...); err != nil {
-			return err
+			return nil, nil, err
 		}
 		rowMap := make(map[string]any)
 		for i, n := range colNames {
-			rowMap[n] = scanVals[i]
+			rowMap[n] = e.decodeScannedValue(colTypes[i].DatabaseTypeName(), scanVals[i])
 		}
 		actual = append(actual, rowMap)
 	}
 	if err := rows.Err(); err != nil {
-		return err
+		return nil, nil, err
 	}
 
-	switch strategy {
-	case "all":
-		// expect full match with order irrelevant? design doc implies exact table contents.
-		// We compare counts and then match rows by index after sorting by PK (already ordered if PK exists).
-		if err := compareRowsSlice(spec.Data, actual, spec.TableName, pkCols, false, true); err != nil {
+	return actual, pkCols, nil
+}
+
+// snapshotDeltaTables records the rows of tables asserted with the delta strategy before the main query runs
+func (e *Executor) snapshotDeltaTables(execution *TestExecution) error {
+	for _, spec := range execution.TestCase.ExpectedResults {
+		if spec.Strategy != "delta" || spec.TableName == "" {
+			continue
+		}
+		if _, done := execution.TableSnapshots[spec.TableName]; done {
+			continue
+		}
+		ti, ok := e.tableInfo[spec.TableName]
+		if !ok {
+			return fmt.Errorf("%w: %s", errTableInfoNotFound, spec.TableName)
+		}
+		rows, _, err := e.fetchTableRows(execution.Context, execution.Transaction, ti, spec.TableName)
+		if err != nil {
 			return err
 		}
-		return nil
-	case "pk-match":
-		return e.comparePKMatch(ti, spec.Data, actual, true)
-	case "pk-exists":
-		return e.comparePKMatch(ti, spec.Data, actual, false)
-	case "pk-not-exists":
-		return e.comparePKNotExists(ti, spec.Data, actual)
-	default:
-		return fmt.Errorf("unknown expected results strategy: %s", strategy)
+		if execution.TableSnapshots == nil {
+			execution.TableSnapshots = make(map[string][]map[string]any)
+		}
+		execution.TableSnapshots[spec.TableName] = rows
 	}
+	return nil
+}
+
+// compareDelta checks the changes between before and after against the expected delta rows.
+// Each expected row carries "_change" (added / updated / deleted); added and updated rows are
+// compared with the new row, deleted rows with the removed row. Changes that are not listed fail.
+func compareDelta(pkCols []string, expected, before, after []map[string]any) error {
+	if len(pkCols) == 0 {
+		return errNoPrimaryKeyDefined
+	}
+
+	beforeIndex := make(map[string]map[string]any, len(before))
+	for _, row := range before {
+		beforeIndex[pkKey(pkCols, row)] = row
+	}
+	afterIndex := make(map[string]map[string]any, len(after))
+	for _, row := range after {
+		afterIndex[pkKey(pkCols, row)] = row
+	}
+
+	actualChanges := make(map[string]string)
+	for key, row := range afterIndex {
+		prev, existed := beforeIndex[key]
+		switch {
+		case !existed:
+			actualChanges[key] = deltaAdded
+		case !reflect.DeepEqual(prev, row):
+			actualChanges[key] = deltaUpdated
+		}
+	}
+	for key := range beforeIndex {
+		if _, exists := afterIndex[key]; !exists {
+			actualChanges[key] = deltaDeleted
+		}
+	}
+
+	for i, expRow := range expected {
+		change, _ := expRow[markdownparser.DeltaChangeKey].(string)
+		key := pkKey(pkCols, expRow)
+		if actual := actualChanges[key]; actual != change {
+			if actual == "" {
+				actual = "unchanged"
+			}
+			return fmt.Errorf("%w: index=%d key=%s expected=%s actual=%s", errDeltaMismatch, i, key, change, actual)
+		}
+		delete(actualChanges, key)
+
+		row := afterIndex[key]
+		if change == deltaDeleted {
+			row = beforeIndex[key]
+		}
+		values := make(map[string]any, len(expRow))
+		for col, v := range expRow {
+			if col != markdownparser.DeltaChangeKey {
+				values[col] = v
+			}
+		}
+		if err := compareRowsWithMatchers(values, row); err != nil {
+			return fmt.Errorf("%s row index=%d key=%s: %w", change, i, key, err)
+		}
+	}
+
+	if len(actualChanges) > 0 {
+		keys := make([]string, 0, len(actualChanges))
+		for key, change := range actualChanges {
+			keys = append(keys, change+" "+key)
+		}
+		sort.Strings(keys)
+		return fmt.Errorf("%w: %s", errDeltaUnexpectedChange, strings.Join(keys, ", "))
+	}
+	return nil
 }
 
+const (
+	deltaAdded   = "added"
+	deltaUpdated = "updated"
+	deltaDeleted = "deleted"
+)
+
 // comparePKMatch: For pk-match requires specified PK rows exist and their non-PK values (provided in expected) match.
 // For pk-exists only presence of PK combination is required (other columns ignored).
 func (e *Executor) comparePKMatch(ti *snapsql.TableInfo, expected, actual []map[string]any, checkValues bool) error {
@@ -655,6 +1062,24 @@ func (e *Executor) comparePKNotExists(ti *snapsql.TableInfo, expected, actual []
 	return nil
 }
 
+// withoutSoftDeletedRows drops rows whose soft delete column is set so existence checks see them as deleted.
+func withoutSoftDeletedRows(rows []map[string]any, table string, opts *ExecutionOptions) []map[string]any {
+	if opts == nil {
+		return rows
+	}
+	column, ok := opts.SoftDeleteColumns[strings.ToLower(table)]
+	if !ok {
+		return rows
+	}
+	live := make([]map[string]any, 0, len(rows))
+	for _, row := range rows {
+		if row[column] == nil {
+			live = append(live, row)
+		}
+	}
+	return live
+}
+
 func pkKey(pkCols []string, row map[string]any) string {
 	vals := make([]string, len(pkCols))
 	for i, c := range pkCols {
@@ -665,7 +1090,7 @@ func pkKey(pkCols []string, row map[string]any) string {
 
 // executeFixtureOnly executes only fixture insertion
 func (e *Executor) executeFixtureOnly(execution *TestExecution) (*ValidationResult, error) {
-	err := e.executeFixtures(execution.Transaction, execution.TestCase.Fixtures)
+	err := e.executeFixtures(execution.Context, execution.Transaction, execution.TestCase.Fixtures)
 	if err != nil {
 		return nil, err
 	}
@@ -679,6 +1104,10 @@ func (e *Executor) executeFixtureOnly(execution *TestExecution) (*ValidationResu
 
 // executeQueryOnly executes only the query without fixtures
 func (e *Executor) executeQueryOnly(execution *TestExecution) (*ValidationResult, error) {
+	if len(execution.TestCase.Steps) > 0 {
+		return e.executeScenario(execution)
+	}
+
 	// Execute the SQL query
 	result, err := e.executeQuery(execution, execution.SQL, execution.Parameters, execution.Args)
 	if err != nil {
@@ -692,7 +1121,7 @@ func (e *Executor) executeQueryOnly(execution *TestExecution) (*ValidationResult
 			return nil, wrapDefinitionFailure(err, "failed to parse validation specs")
 		}
 
-		if err := e.validateResult(execution.Transaction, result, specs); err != nil {
+		if err := e.validateResult(execution.Context, execution.Transaction, result, specs); err != nil {
 			return nil, wrapAssertionFailure(err, "validation failed")
 		}
 	}
@@ -703,10 +1132,23 @@ func (e *Executor) executeQueryOnly(execution *TestExecution) (*ValidationResult
 // executeFullTest executes the complete test flow
 func (e *Executor) executeFullTest(execution *TestExecution) (*ValidationResult, error) {
 	// 1. Execute fixtures
-	if err := e.executeFixtures(execution.Transaction, execution.TestCase.Fixtures); err != nil {
+	if err := e.executeFixtures(execution.Context, execution.Transaction, execution.TestCase.Fixtures); err != nil {
 		return nil, wrapDefinitionFailure(err, "failed to execute fixtures")
 	}
 
+	if len(execution.TestCase.Steps) > 0 {
+		return e.executeScenario(execution)
+	}
+
+	return e.executeMainQuery(execution)
+}
+
+// executeMainQuery executes the main query of the test case after its fixtures and validates the expectations
+func (e *Executor) executeMainQuery(execution *TestExecution) (*ValidationResult, error) {
+	if err := e.snapshotDeltaTables(execution); err != nil {
+		return nil, wrapDefinitionFailure(err, "failed to snapshot tables for delta expectations")
+	}
+
 	if execution.Options != nil && execution.Options.PerformanceEnabled {
 		if detectQueryType(execution.SQL) != SelectQuery && execution.Performance == nil {
 			execution.Performance = e.collectPerformanceBeforeDML(execution)
@@ -733,7 +1175,7 @@ func (e *Executor) executeFullTest(execution *TestExecution) (*ValidationResult,
 	if skipMainSelect {
 		// Execute the SQL to honor potential side effects while avoiding row iteration cost.
 		// Prefer ExecContext; if driver doesn't allow Exec on SELECT, fall back to QueryContext and close immediately.
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(execution.Context, 30*time.Second)
 		defer cancel()
 		if _, err := execution.Transaction.ExecContext(ctx, execution.SQL, execution.Args...); err != nil {
 			// Fallback: run as QueryContext then close immediately without iteration
@@ -755,6 +1197,11 @@ func (e *Executor) executeFullTest(execution *TestExecution) (*ValidationResult,
 		}
 	}
 
+	named, err := e.executeNamedVerifyQueries(execution)
+	if err != nil {
+		return nil, err
+	}
+
 	// 3. Execute verify query if present
 	if execution.TestCase.VerifyQuery != "" {
 		verifyResult, err := e.executeVerifyQuery(execution, execution.TestCase.VerifyQuery)
@@ -764,29 +1211,33 @@ func (e *Executor) executeFullTest(execution *TestExecution) (*ValidationResult,
 
 		// 4. Validate verify query results (legacy unnamed)
 		if len(execution.TestCase.ExpectedResult) > 0 {
-			if err := e.validateVerifyResults(verifyResult, execution.TestCase.ExpectedResult); err != nil {
+			if err := e.compareVerifyRows(execution, verifyResult, execution.TestCase.ExpectedResult); err != nil {
 				return nil, wrapAssertionFailure(err, "verify query validation failed")
 			}
 		} else {
 			// Support unnamed external expected results via ExpectedResults entry with empty TableName
 			if spec, ok := firstUnnamedExternalSpec(execution.TestCase.ExpectedResults); ok {
-				rows, err := e.loadExternalRows(spec.ExternalFile)
+				rows, err := e.loadExpectedRows(execution, spec.ExternalFile, "")
 				if err != nil {
 					return nil, wrapDefinitionFailure(err, "failed to load expected results from external file")
 				}
-				if err := e.validateVerifyResults(verifyResult, rows); err != nil {
+				if err := e.compareVerifyRows(execution, verifyResult, rows); err != nil {
 					return nil, wrapAssertionFailure(err, "verify query validation failed")
 				}
 			}
 		}
 
 		// 5. Also apply table-level expected results strategies
-		for _, spec := range execution.TestCase.ExpectedResults {
-			if spec.TableName != "" { // only table-qualified specs
-				if err := e.validateTableStateBySpec(execution.Transaction, spec); err != nil {
-					return nil, wrapAssertionFailure(err, "table state validation failed")
-				}
-			}
+		if err := e.validateNamedExpectations(execution, named); err != nil {
+			return nil, err
+		}
+
+		if err := e.checkQueryAssertions(execution); err != nil {
+			return nil, err
+		}
+
+		if result != nil {
+			verifyResult.Duration = result.Duration
 		}
 
 		return verifyResult, nil
@@ -794,31 +1245,152 @@ func (e *Executor) executeFullTest(execution *TestExecution) (*ValidationResult,
 
 	// 4. Validate (暫定: 旧式 ExpectedResult を直接比較) または 外部ファイル参照の無名期待
 	if result.QueryType == SelectQuery || hasReturningClause(execution.SQL) {
+		unnamed, _ := unnamedSpec(execution.TestCase.ExpectedResults)
 		if len(execution.TestCase.ExpectedResult) > 0 {
-			if err := compareRowsSlice(execution.TestCase.ExpectedResult, result.Data, "", nil, execution.TestCase.ResultOrdered, true); err != nil {
+			if err := compareSpecRows(unnamed, execution.TestCase.ExpectedResult, result.Data, "", nil, execution.TestCase.ResultOrdered); err != nil {
 				return nil, wrapAssertionFailure(err, "simple validation failed")
 			}
 		} else if spec, ok := firstUnnamedExternalSpec(execution.TestCase.ExpectedResults); ok {
-			rows, err := e.loadExternalRows(spec.ExternalFile)
+			rows, err := e.loadExpectedRows(execution, spec.ExternalFile, "")
 			if err != nil {
 				return nil, wrapDefinitionFailure(err, "failed to load expected results from external file")
 			}
-			if err := compareRowsSlice(rows, result.Data, "", nil, execution.TestCase.ResultOrdered, true); err != nil {
+			if err := compareSpecRows(unnamed, rows, result.Data, "", nil, execution.TestCase.ResultOrdered); err != nil {
 				return nil, wrapAssertionFailure(err, "simple validation failed")
 			}
 		}
 	}
 
 	// 5. Table-level ExpectedResults with strategies (pk-*, all) validation
+	if err := e.validateNamedExpectations(execution, named); err != nil {
+		return nil, err
+	}
+
+	if err := e.checkQueryAssertions(execution); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// compareVerifyRows validates the rows of the unnamed verify query. Ordering options of the
+// unnamed expected results section switch to the comparison of compareSpecRows.
+func (e *Executor) compareVerifyRows(execution *TestExecution, verifyResult *ValidationResult, expected []map[string]any) error {
+	if spec, ok := unnamedSpec(execution.TestCase.ExpectedResults); ok && hasOrderingOptions(spec) {
+		return compareSpecRows(spec, expected, verifyResult.Data, "", nil, true)
+	}
+
+	return e.validateVerifyResults(verifyResult, expected)
+}
+
+// executeNamedVerifyQueries runs the named verify queries (```sql verify:<name>) and returns
+// their results by name
+func (e *Executor) executeNamedVerifyQueries(execution *TestExecution) (map[string]*ValidationResult, error) {
+	if len(execution.TestCase.VerifyQueries) == 0 {
+		return nil, nil //nolint:nilnil // no named verify queries
+	}
+
+	results := make(map[string]*ValidationResult, len(execution.TestCase.VerifyQueries))
+	for _, verify := range execution.TestCase.VerifyQueries {
+		result, err := e.executeVerifyQuery(execution, verify.Query)
+		if err != nil {
+			return nil, wrapDefinitionFailure(err, "failed to execute verify query %q", verify.Name)
+		}
+
+		results[verify.Name] = result
+	}
+
+	return results, nil
+}
+
+// validateNamedExpectations validates the expected results sections with a name. A section named
+// after a verify query is compared with that query's rows; the others validate the table state
+// with their strategy.
+func (e *Executor) validateNamedExpectations(execution *TestExecution, named map[string]*ValidationResult) error {
 	for _, spec := range execution.TestCase.ExpectedResults {
-		if spec.TableName != "" { // only table-qualified specs
-			if err := e.validateTableStateBySpec(execution.Transaction, spec); err != nil {
-				return nil, wrapAssertionFailure(err, "table state validation failed")
+		if spec.TableName == "" { // only table-qualified specs
+			continue
+		}
+
+		verifyResult, ok := named[spec.TableName]
+		if !ok {
+			if err := e.validateTableStateBySpec(execution, spec); err != nil {
+				return wrapAssertionFailure(err, "table state validation failed")
 			}
+
+			continue
+		}
+
+		expected := spec.Data
+		if spec.ExternalFile != "" {
+			rows, err := e.loadExpectedRows(execution, spec.ExternalFile, "")
+			if err != nil {
+				return wrapDefinitionFailure(err, "failed to load expected results from external file")
+			}
+
+			expected = rows
+		}
+
+		if err := compareSpecRows(spec, expected, verifyResult.Data, spec.TableName, nil, true); err != nil {
+			return wrapAssertionFailure(err, "verify query %q validation failed", spec.TableName)
 		}
 	}
 
-	return result, nil
+	return nil
+}
+
+// checkQueryAssertions evaluates the expect_count / expect assertions after the main query.
+// expect_count ignores soft-deleted rows like the pk-exists strategy.
+func (e *Executor) checkQueryAssertions(execution *TestExecution) error {
+	for _, assertion := range execution.TestCase.Assertions {
+		query := assertion.Query
+		if assertion.Table != "" {
+			query = "SELECT COUNT(*) FROM " + e.quoteIdentifier(assertion.Table)
+			if execution.Options != nil {
+				if column, ok := execution.Options.SoftDeleteColumns[assertion.Table]; ok && column != "" {
+					query += " WHERE " + e.quoteIdentifier(column) + " IS NULL"
+				}
+			}
+		}
+
+		result, err := e.executeSelectQuery(execution.Context, execution.Transaction, query, nil, "assertion query")
+		execution.addTrace("assertion query", query, nil, nil, result)
+		if err != nil {
+			return wrapDefinitionFailure(err, "failed to execute assertion %q", assertion.String())
+		}
+
+		if len(result.Data) != 1 || len(result.Data[0]) != 1 {
+			return wrapDefinitionFailure(errAssertionNotScalar, "assertion %q returned %d rows", assertion.String(), len(result.Data))
+		}
+
+		var actual any
+		for _, v := range result.Data[0] {
+			actual = v
+		}
+
+		if !assertionValueEquals(assertion.Expected, actual) {
+			return wrapAssertionFailure(errAssertionMismatch, "assertion %q failed: got %v", assertion.String(), actual)
+		}
+	}
+
+	return nil
+}
+
+// assertionValueEquals compares the assertion value, accepting numeric strings returned by
+// drivers for aggregates such as SUM over DECIMAL columns
+func assertionValueEquals(expected, actual any) bool {
+	if valueEquals(expected, actual) {
+		return true
+	}
+
+	fe, ok := toNumber(expected)
+	if !ok {
+		return false
+	}
+
+	fa, ok := toNumber(actual)
+
+	return ok && math.Abs(fe-fa) < 1e-9
 }
 
 // firstUnnamedExternalSpec finds an ExpectedResultSpec with empty TableName and non-empty ExternalFile
@@ -915,8 +1487,10 @@ func hasReturningClause(sql string) bool {
 
 // executeQuery executes the SQL query and returns the result
 func (e *Executor) executeQuery(execution *TestExecution, sqlQuery string, parameters map[string]any, args []any) (*ValidationResult, error) {
+	ctx := execution.Context
 	queryType := detectQueryType(sqlQuery)
 	trx := execution.Transaction
+	start := time.Now()
 
 	// Parameter replacement in SQL query is handled by the template engine
 	// For now, execute the query as-is
@@ -925,13 +1499,14 @@ func (e *Executor) executeQuery(execution *TestExecution, sqlQuery string, param
 	if (queryType == InsertQuery || queryType == UpdateQuery || queryType == DeleteQuery) && hasReturningClause(sqlQuery) {
 		// Execute as SELECT query to get returned data
 		label := fmt.Sprintf("%s query with RETURNING", queryType.String())
-		result, err := e.executeSelectQuery(trx, sqlQuery, args, label)
+		result, err := e.executeSelectQuery(ctx, trx, sqlQuery, args, label)
 		if err != nil {
 			execution.addTrace("main query", sqlQuery, parameters, args, nil)
 			return nil, err
 		}
 		// Keep the original query type for validation logic
 		result.QueryType = queryType
+		result.Duration = time.Since(start)
 		execution.addTrace("main query", sqlQuery, parameters, args, result)
 		e.collectPerformance(execution, sqlQuery, args)
 
@@ -940,20 +1515,22 @@ func (e *Executor) executeQuery(execution *TestExecution, sqlQuery string, param
 
 	switch queryType {
 	case SelectQuery:
-		result, err := e.executeSelectQuery(trx, sqlQuery, args, "SELECT query")
+		result, err := e.executeSelectQuery(ctx, trx, sqlQuery, args, "SELECT query")
 		if err != nil {
 			execution.addTrace("main query", sqlQuery, parameters, args, nil)
 			return nil, err
 		}
+		result.Duration = time.Since(start)
 		execution.addTrace("main query", sqlQuery, parameters, args, result)
 		e.collectPerformance(execution, sqlQuery, args)
 		return result, nil
 	case InsertQuery, UpdateQuery, DeleteQuery:
-		result, err := e.executeDMLQuery(trx, sqlQuery, queryType, args)
+		result, err := e.executeDMLQuery(ctx, trx, sqlQuery, queryType, args)
 		if err != nil {
 			execution.addTrace("main query", sqlQuery, parameters, args, nil)
 			return nil, err
 		}
+		result.Duration = time.Since(start)
 		execution.addTrace("main query", sqlQuery, parameters, args, result)
 		e.collectPerformance(execution, sqlQuery, args)
 		return result, nil
@@ -964,9 +1541,9 @@ func (e *Executor) executeQuery(execution *TestExecution, sqlQuery string, param
 
 // executeSelectQuery executes a query expected to return rows and returns the data.
 // label describes the originating statement (e.g., "SELECT query", "UPDATE query with RETURNING").
-func (e *Executor) executeSelectQuery(tx *sql.Tx, sqlQuery string, args []any, label string) (*ValidationResult, error) {
+func (e *Executor) executeSelectQuery(ctx context.Context, tx *sql.Tx, sqlQuery string, args []any, label string) (*ValidationResult, error) {
 	// Guard against indefinite blocking by bounding query duration
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	if label == "" {
@@ -985,6 +1562,8 @@ func (e *Executor) executeSelectQuery(tx *sql.Tx, sqlQuery string, args []any, l
 		return nil, fmt.Errorf("failed to get column names for %s: %w", label, err)
 	}
 
+	typeNames := columnTypeNames(rows, len(columns))
+
 	// Prepare result data
 	var data []map[string]any
 
@@ -1014,6 +1593,7 @@ func (e *Executor) executeSelectQuery(tx *sql.Tx, sqlQuery string, args []any, l
 			} else {
 				row[col] = val
 			}
+			row[col] = e.decodeScannedValue(typeNames[i], row[col])
 		}
 
 		data = append(data, row)
@@ -1045,7 +1625,7 @@ func (e *Executor) collectPerformance(execution *TestExecution, sqlQuery string,
 
 	threshold := execution.SlowQueryThreshold
 
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(execution.Context, 15*time.Second)
 	defer cancel()
 
 	queryType := detectQueryType(sqlQuery)
@@ -1118,15 +1698,17 @@ func (e *Executor) collectPerformance(execution *TestExecution, sqlQuery string,
 		applyTableDescriptions(evaluation, execution.Options.TableReferenceMap, e.tableInfo, execution.Options.TableMetadata)
 	}
 
+	recordPlan(execution.Options, doc, evaluation)
 	execution.Performance = evaluation
 }
 
 func (e *Executor) collectPerformanceBeforeDML(execution *TestExecution) *explain.PerformanceEvaluation {
-	if execution.Transaction == nil {
+	// The plan is collected inside a savepoint, which needs a real transaction
+	if execution.Transaction == nil || !snapsql.SupportsTransactions(e.dialect) {
 		return nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(execution.Context, 15*time.Second)
 	defer cancel()
 
 	if _, err := execution.Transaction.ExecContext(ctx, "SAVEPOINT snapsql_explain_plan"); err != nil {
@@ -1204,9 +1786,21 @@ func (e *Executor) collectPerformanceBeforeDML(execution *TestExecution) *explai
 		applyTableDescriptions(evaluation, execution.Options.TableReferenceMap, e.tableInfo, execution.Options.TableMetadata)
 	}
 
+	recordPlan(execution.Options, doc, evaluation)
+
 	return evaluation
 }
 
+// recordPlan attaches the plan and rule violations to the evaluation in --explain mode
+func recordPlan(opts *ExecutionOptions, doc *explain.PlanDocument, evaluation *explain.PerformanceEvaluation) {
+	if opts == nil || !opts.Explain || evaluation == nil {
+		return
+	}
+
+	evaluation.Plan = doc
+	evaluation.Violations = explain.CheckRules(doc, evaluation, opts.ExplainRules, opts.TableMetadata)
+}
+
 func applyTableDescriptions(eval *explain.PerformanceEvaluation, mapping map[string]intermediate.TableReferenceInfo, schema map[string]*snapsql.TableInfo, metadata map[string]explain.TableMetadata) {
 	if eval == nil || len(mapping) == 0 {
 		return
@@ -1312,8 +1906,8 @@ func debugDumpPlanTables(stage string, raw []string, mapping map[string]intermed
 }
 
 // executeDMLQuery executes INSERT/UPDATE/DELETE queries and returns affected rows
-func (e *Executor) executeDMLQuery(tx *sql.Tx, sqlQuery string, queryType QueryType, args []any) (*ValidationResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func (e *Executor) executeDMLQuery(ctx context.Context, tx *sql.Tx, sqlQuery string, queryType QueryType, args []any) (*ValidationResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	result, err := tx.ExecContext(ctx, sqlQuery, args...)
@@ -1333,64 +1927,327 @@ func (e *Executor) executeDMLQuery(tx *sql.Tx, sqlQuery string, queryType QueryT
 	}, nil
 }
 
-func (e *Executor) executeFixtures(tx *sql.Tx, fixtures []markdownparser.TableFixture) error {
-	for _, fixture := range fixtures {
+func (e *Executor) executeFixtures(ctx context.Context, tx *sql.Tx, fixtures []markdownparser.TableFixture) (err error) {
+	run := e.fixtureRuns.Add(1)
+
+	if slices.ContainsFunc(fixtures, func(f markdownparser.TableFixture) bool { return f.DeferConstraints }) {
+		restore, deferErr := e.deferConstraints(ctx, tx)
+		if deferErr != nil {
+			return deferErr
+		}
+
+		defer func() {
+			// Restoring the checks also reports the violations the fixtures left behind (PostgreSQL)
+			if restoreErr := restore(); restoreErr != nil && err == nil {
+				err = restoreErr
+			}
+		}()
+	}
+
+	for block, fixture := range fixtures {
 		// Load external rows for fixture if needed
-		ctx := map[string]string{"table": fixture.TableName}
+		errCtx := map[string]string{"table": fixture.TableName}
 		if fixture.Strategy != "" {
-			ctx["strategy"] = string(fixture.Strategy)
+			errCtx["strategy"] = string(fixture.Strategy)
 		}
 
 		if fixture.Line > 0 {
-			ctx["line"] = strconv.Itoa(fixture.Line)
+			errCtx["line"] = strconv.Itoa(fixture.Line)
+		}
+
+		if fixture.ExternalFile != "" && len(fixture.Data) == 0 {
+			rows, err := e.loadExternalRows(fixture.ExternalFile, fixture.TableName)
+			if err != nil {
+				return wrapDefinitionFailureWithContext(errCtx, err, "failed to load fixture external file for table %s", fixture.TableName)
+			}
+			fixture.Data = rows
+		}
+
+		if now, ok := currentDateFrom(ctx); ok {
+			fixture.Data = anchorRows(fixture.Data, now, true)
+		}
+
+		fixture.Data = expandFixtureCount(fixture.Data, fixture.Count)
+
+		if fixture.Autofill {
+			rows, err := e.autofillRows(fixture.TableName, fixture.Data)
+			if err != nil {
+				return wrapDefinitionFailureWithContext(errCtx, err, "failed to autofill fixture for table %s", fixture.TableName)
+			}
+			fixture.Data = rows
+		}
+
+		err := e.executeTableFixture(ctx, tx, fixture, fixtureSeed(fixture.TableName, block, run))
+		if err != nil {
+			return wrapDefinitionFailureWithContext(errCtx, err, "failed to execute fixture for table %s", fixture.TableName)
+		}
+	}
+
+	return nil
+}
+
+// deferConstraints postpones foreign key checks in tx until the returned function restores them,
+// so that fixtures of tables referencing each other load in any order.
+func (e *Executor) deferConstraints(ctx context.Context, tx *sql.Tx) (func() error, error) {
+	var deferStmt, restoreStmt string
+
+	switch e.dialect {
+	case "postgres", "postgresql", "pg", "pgx":
+		// Only constraints declared DEFERRABLE are deferred
+		deferStmt, restoreStmt = "SET CONSTRAINTS ALL DEFERRED", "SET CONSTRAINTS ALL IMMEDIATE"
+	case snapsql.DialectSQLite:
+		deferStmt, restoreStmt = "PRAGMA defer_foreign_keys = ON", "PRAGMA defer_foreign_keys = OFF"
+	case snapsql.DialectMySQL, snapsql.DialectMariaDB:
+		// FOREIGN_KEY_CHECKS is a session variable; it is restored before the connection goes back to the pool
+		deferStmt, restoreStmt = "SET FOREIGN_KEY_CHECKS = 0", "SET FOREIGN_KEY_CHECKS = 1"
+	case snapsql.DialectClickHouse:
+		// ClickHouse has no foreign keys
+		return func() error { return nil }, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errDeferNotSupported, e.dialect)
+	}
+
+	if _, err := tx.ExecContext(ctx, deferStmt); err != nil {
+		return nil, wrapDefinitionFailureWithContext(map[string]string{"operation": "defer constraints", "sql": deferStmt}, err, "failed to defer constraints")
+	}
+
+	return func() error {
+		if _, err := tx.ExecContext(ctx, restoreStmt); err != nil {
+			return wrapDefinitionFailureWithContext(map[string]string{"operation": "restore constraints", "sql": restoreStmt}, err, "deferred constraints of the fixtures failed")
 		}
 
-		if fixture.ExternalFile != "" && len(fixture.Data) == 0 {
-			rows, err := e.loadExternalRows(fixture.ExternalFile)
-			if err != nil {
-				return wrapDefinitionFailureWithContext(ctx, err, "failed to load fixture external file for table %s", fixture.TableName)
+		return nil
+	}, nil
+}
+
+// executeTableFixture executes a single table fixture based on its strategy
+
+func (e *Executor) executeTableFixture(ctx context.Context, tx *sql.Tx, fixture markdownparser.TableFixture, seed int64) error {
+	switch fixture.Strategy {
+	case markdownparser.ClearInsert:
+		return e.executeClearInsert(ctx, tx, fixture, seed)
+	case markdownparser.Truncate:
+		return e.executeTruncateInsert(ctx, tx, fixture, seed)
+	case markdownparser.Upsert:
+		return e.executeUpsert(ctx, tx, fixture, seed)
+	case markdownparser.Delete:
+		return e.executeDelete(ctx, tx, fixture)
+	default:
+		return fmt.Errorf("%w: %s", snapsql.ErrUnsupportedInsertStrategy, fixture.Strategy)
+	}
+}
+
+// loadExpectedRows loads expected rows from an external file and anchors their [currentdate]
+// matchers to the time of the test case
+func (e *Executor) loadExpectedRows(execution *TestExecution, path string, tableName string) ([]map[string]any, error) {
+	rows, err := e.loadExternalRows(path, tableName)
+	if err != nil || execution.TimeAnchor.IsZero() {
+		return rows, err
+	}
+
+	return anchorRows(rows, execution.TimeAnchor, false), nil
+}
+
+// loadExternalRows loads rows from an external YAML/JSON/CSV/TSV file path (relative to baseDir if not absolute).
+// tableName is used to coerce CSV/TSV cells to the column types of the table; it may be empty.
+func (e *Executor) loadExternalRows(path string, tableName string) ([]map[string]any, error) {
+	if path == "" {
+		return nil, nil
+	}
+	p := path
+	if !isAbsPath(p) && e.baseDir != "" {
+		p = joinPath(e.baseDir, p)
+	}
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(p)) {
+	case ".csv":
+		return unmarshalDelimitedRows(b, ',', e.tableInfo[tableName])
+	case ".tsv":
+		return unmarshalDelimitedRows(b, '\t', e.tableInfo[tableName])
+	}
+	return unmarshalRows(b)
+}
+
+// unmarshalDelimitedRows parses CSV/TSV data whose first row holds the column names.
+// Cells are converted using the column types of table when available and inferred otherwise.
+// Empty cells and \N become NULL (empty cells stay "" for string columns), and cells written
+// as [..] are parsed as YAML flow sequences so that matchers like [notnull] keep working.
+func unmarshalDelimitedRows(b []byte, delimiter rune, table *snapsql.TableInfo) ([]map[string]any, error) {
+	reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(string(b), "\ufeff")))
+	reader.Comma = delimiter
+	reader.TrimLeadingSpace = true
+	if delimiter == '\t' {
+		reader.LazyQuotes = true
+	}
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal external rows: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	header := make([]string, len(records[0]))
+	for i, name := range records[0] {
+		header[i] = strings.TrimSpace(name)
+		if header[i] == "" {
+			return nil, fmt.Errorf("%w: column %d has no name", errExternalCSVHeader, i+1)
+		}
+	}
+	rows := make([]map[string]any, 0, len(records)-1)
+	for lineIdx, record := range records[1:] {
+		row := make(map[string]any, len(header))
+		for i, name := range header {
+			cell := ""
+			if i < len(record) {
+				cell = record[i]
+			}
+			var column *snapsql.ColumnInfo
+			if table != nil {
+				column = table.Columns[name]
+			}
+			value, err := convertDelimitedCell(cell, column)
+			if err != nil {
+				return nil, fmt.Errorf("%w: row %d, column %s: %w", errExternalCSVValue, lineIdx+2, name, err)
+			}
+			row[name] = value
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func convertDelimitedCell(cell string, column *snapsql.ColumnInfo) (any, error) {
+	if cell == `\N` {
+		return nil, nil
+	}
+	if strings.HasPrefix(cell, "[") && strings.HasSuffix(cell, "]") {
+		var seq []any
+		if err := yaml.Unmarshal([]byte(cell), &seq); err == nil {
+			return normalizeLoadedValue(seq), nil
+		}
+	}
+	if column == nil {
+		if cell == "" {
+			return nil, nil
+		}
+		return inferDelimitedCell(cell), nil
+	}
+	dataType := strings.ToLower(column.DataType)
+	if cell == "" {
+		if isStringColumnType(dataType) {
+			return "", nil
+		}
+		return nil, nil
+	}
+	switch {
+	case isIntegerColumnType(dataType):
+		return strconv.ParseInt(strings.TrimSpace(cell), 10, 64)
+	case strings.Contains(dataType, "float"), strings.Contains(dataType, "double"),
+		strings.Contains(dataType, "real"), strings.Contains(dataType, "decimal"), strings.Contains(dataType, "numeric"):
+		return strconv.ParseFloat(strings.TrimSpace(cell), 64)
+	case strings.Contains(dataType, "bool"):
+		return strconv.ParseBool(strings.TrimSpace(cell))
+	default:
+		return cell, nil
+	}
+}
+
+// autofillRows returns copies of rows in which the non-null columns a row omits hold the default
+// value of their type. Primary keys are left to the database.
+func (e *Executor) autofillRows(tableName string, rows []map[string]any) ([]map[string]any, error) {
+	tbl, ok := e.tableInfo[tableName]
+	if !ok || tbl == nil {
+		return nil, fmt.Errorf("%w: %s (autofill needs the table schema)", errTableInfoNotFound, tableName)
+	}
+
+	filled := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		clone := maps.Clone(row)
+		for name, column := range tbl.Columns {
+			if column.Nullable || column.IsPrimaryKey {
+				continue
+			}
+			if _, ok := clone[name]; !ok {
+				clone[name] = autofillValue(column)
 			}
-			fixture.Data = rows
-		}
-
-		err := e.executeTableFixture(tx, fixture)
-		if err != nil {
-			return wrapDefinitionFailureWithContext(ctx, err, "failed to execute fixture for table %s", fixture.TableName)
 		}
+		filled[i] = clone
 	}
+	return filled, nil
+}
 
-	return nil
+// autofillValue returns the value autofill writes to a column: the zero value of its type,
+// or the Unix epoch for temporal columns.
+func autofillValue(column *snapsql.ColumnInfo) any {
+	dataType := strings.ToLower(column.DataType)
+	switch {
+	case isIntegerColumnType(dataType):
+		return int64(0)
+	case strings.Contains(dataType, "float"), strings.Contains(dataType, "double"),
+		strings.Contains(dataType, "real"), strings.Contains(dataType, "decimal"), strings.Contains(dataType, "numeric"):
+		return float64(0)
+	case strings.Contains(dataType, "bool"):
+		return false
+	case strings.Contains(dataType, "timestamp"), strings.Contains(dataType, "datetime"), dataType == "date":
+		return time.Unix(0, 0).UTC()
+	case strings.HasPrefix(dataType, "time"):
+		return "00:00:00"
+	case strings.Contains(dataType, "json"):
+		return "{}"
+	case strings.Contains(dataType, "uuid"):
+		return "00000000-0000-0000-0000-000000000000"
+	case strings.Contains(dataType, "blob"), strings.Contains(dataType, "binary"), strings.Contains(dataType, "bytea"):
+		return []byte{}
+	default:
+		return ""
+	}
 }
 
-// executeTableFixture executes a single table fixture based on its strategy
+// isIntegerColumnType matches the integer type names of the supported dialects. Length and
+// modifiers such as "bigint(20) unsigned" are ignored.
+func isIntegerColumnType(dataType string) bool {
+	base, _, _ := strings.Cut(dataType, "(")
+	if fields := strings.Fields(base); len(fields) > 0 {
+		base = fields[0]
+	}
 
-func (e *Executor) executeTableFixture(tx *sql.Tx, fixture markdownparser.TableFixture) error {
-	switch fixture.Strategy {
-	case markdownparser.ClearInsert:
-		return e.executeClearInsert(tx, fixture)
-	case markdownparser.Upsert:
-		return e.executeUpsert(tx, fixture)
-	case markdownparser.Delete:
-		return e.executeDelete(tx, fixture)
+	switch base {
+	case "int", "integer", "tinyint", "smallint", "mediumint", "bigint",
+		"int2", "int4", "int8", "serial", "smallserial", "bigserial", "serial4", "serial8":
+		return true
 	default:
-		return fmt.Errorf("%w: %s", snapsql.ErrUnsupportedInsertStrategy, fixture.Strategy)
+		return false
 	}
 }
 
-// loadExternalRows loads rows from an external YAML/JSON file path (relative to baseDir if not absolute)
-func (e *Executor) loadExternalRows(path string) ([]map[string]any, error) {
-	if path == "" {
-		return nil, nil
+func isStringColumnType(dataType string) bool {
+	switch {
+	case dataType == "", dataType == "string", dataType == "text",
+		strings.Contains(dataType, "char"), strings.Contains(dataType, "clob"):
+		return true
+	default:
+		return false
 	}
-	p := path
-	if !isAbsPath(p) && e.baseDir != "" {
-		p = joinPath(e.baseDir, p)
+}
+
+// inferDelimitedCell guesses the type of a cell that is not backed by table information
+func inferDelimitedCell(cell string) any {
+	if i, err := strconv.ParseInt(cell, 10, 64); err == nil {
+		return i
 	}
-	b, err := os.ReadFile(p)
-	if err != nil {
-		return nil, err
+	if f, err := strconv.ParseFloat(cell, 64); err == nil {
+		return f
 	}
-	return unmarshalRows(b)
+	switch cell {
+	case "true", "TRUE", "True":
+		return true
+	case "false", "FALSE", "False":
+		return false
+	}
+	return cell
 }
 
 // Helpers for path and unmarshal
@@ -1451,36 +2308,99 @@ func normalizeLoadedValue(v any) any {
 }
 
 // executeClearInsert truncates the table and inserts data
-func (e *Executor) executeClearInsert(tx *sql.Tx, fixture markdownparser.TableFixture) error {
+func (e *Executor) executeClearInsert(ctx context.Context, tx *sql.Tx, fixture markdownparser.TableFixture, seed int64) error {
 	// 簡易DELETE実装（dialect依存truncateは未実装暫定）
 	query := "DELETE FROM " + e.quoteIdentifier(fixture.TableName)
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	if e.dialect == snapsql.DialectClickHouse {
+		// DELETE is an asynchronous mutation in ClickHouse; TRUNCATE empties the table immediately
+		query = "TRUNCATE TABLE " + e.quoteIdentifier(fixture.TableName)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 	if _, err := tx.ExecContext(ctx, query); err != nil {
 		return wrapDefinitionFailureWithContext(map[string]string{"table": fixture.TableName, "operation": "clear"}, err, "failed to clear table %s", fixture.TableName)
 	}
-	return e.insertData(tx, fixture.TableName, fixture.Data)
+	return e.insertData(ctx, tx, fixture.TableName, fixture.Data, seed)
+}
+
+// executeTruncateInsert empties the table and resets its identity counters before inserting,
+// so that auto-increment IDs generated by the test start from the same value on every run.
+func (e *Executor) executeTruncateInsert(ctx context.Context, tx *sql.Tx, fixture markdownparser.TableFixture, seed int64) error {
+	stmts, err := e.truncateStatements(fixture.TableName)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if e.dialect == snapsql.DialectSQLite {
+		// sqlite_sequence only exists once a table declared with AUTOINCREMENT has been created
+		var sequences int
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'sqlite_sequence'").Scan(&sequences); err != nil {
+			return wrapDefinitionFailureWithContext(map[string]string{"table": fixture.TableName, "operation": "truncate"}, err, "failed to look up sqlite_sequence")
+		}
+
+		if sequences == 0 {
+			stmts = stmts[:1]
+		}
+	}
+
+	for _, stmt := range stmts {
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return wrapDefinitionFailureWithContext(map[string]string{"table": fixture.TableName, "operation": "truncate", "sql": stmt}, err, "failed to truncate table %s", fixture.TableName)
+		}
+	}
+
+	return e.insertData(ctx, tx, fixture.TableName, fixture.Data, seed)
+}
+
+// truncateStatements returns the statements that empty a table and reset its identity counters.
+// MySQL and MariaDB are rejected because TRUNCATE commits implicitly, which would leak the
+// fixtures out of the test transaction.
+func (e *Executor) truncateStatements(tableName string) ([]string, error) {
+	table := e.quoteIdentifier(tableName)
+
+	switch e.dialect {
+	case "postgres", "postgresql", "pg", "pgx":
+		return []string{"TRUNCATE TABLE " + table + " RESTART IDENTITY"}, nil
+	case snapsql.DialectSQLite:
+		// SQLite has no TRUNCATE; AUTOINCREMENT counters live in sqlite_sequence
+		return []string{"DELETE FROM " + table, "DELETE FROM sqlite_sequence WHERE name = " + e.quoteString(tableName)}, nil
+	case snapsql.DialectClickHouse:
+		// ClickHouse has no identity columns
+		return []string{"TRUNCATE TABLE " + table}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errTruncateNotSupported, e.dialect)
+	}
 }
 
 // executeInsert just inserts data into the table
 
 // executeUpsert inserts data or updates if exists
-func (e *Executor) executeUpsert(tx *sql.Tx, fixture markdownparser.TableFixture) error {
+func (e *Executor) executeUpsert(ctx context.Context, tx *sql.Tx, fixture markdownparser.TableFixture, seed int64) error {
 	// Implementation depends on database dialect
 	switch e.dialect {
-	case "postgres":
-		return e.executePostgresUpsert(tx, fixture)
+	case "postgres", "duckdb", "cockroach":
+		// DuckDB and CockroachDB support the same INSERT ... ON CONFLICT DO UPDATE syntax
+		return e.executePostgresUpsert(ctx, tx, fixture, seed)
 	case "mysql":
-		return e.executeMySQLUpsert(tx, fixture)
+		return e.executeMySQLUpsert(ctx, tx, fixture, seed)
 	case "sqlite":
-		return e.executeSQLiteUpsert(tx, fixture)
+		return e.executeSQLiteUpsert(ctx, tx, fixture, seed)
+	case "clickhouse":
+		// ClickHouse has no ON CONFLICT: the rows are replaced by deleting their primary keys first
+		if err := e.executeDelete(ctx, tx, fixture); err != nil {
+			return err
+		}
+		return e.insertData(ctx, tx, fixture.TableName, fixture.Data, seed)
 	default:
 		return fmt.Errorf("%w: %s", snapsql.ErrUpsertNotSupported, e.dialect)
 	}
 }
 
 // executeDelete deletes rows that match the dataset's primary keys
-func (e *Executor) executeDelete(tx *sql.Tx, fixture markdownparser.TableFixture) error {
+func (e *Executor) executeDelete(ctx context.Context, tx *sql.Tx, fixture markdownparser.TableFixture) error {
 	if len(fixture.Data) == 0 {
 		return nil
 	}
@@ -1518,7 +2438,7 @@ func (e *Executor) executeDelete(tx *sql.Tx, fixture markdownparser.TableFixture
 		// 主キー以外のカラムは無視
 
 		query := fmt.Sprintf("DELETE FROM %s WHERE %s", e.quoteIdentifier(fixture.TableName), strings.Join(whereClauses, " AND "))
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 		defer cancel()
 		if _, err := tx.ExecContext(ctx, query, values...); err != nil {
 			return fmt.Errorf("failed to execute delete for table %s: %w", fixture.TableName, err)
@@ -1752,6 +2672,12 @@ func buildPrimaryKeyString(pkCols []string, row map[string]any) string {
 func evaluateMatcherDiff(column string, expected any, actual any) *ColumnDiff {
 	switch val := expected.(type) {
 	case []any:
+		if !isMatcherArray(val) {
+			if !valueEquals(expected, actual) {
+				return &ColumnDiff{Column: column, Expected: formatValueForDiff(expected), Actual: formatValueForDiff(actual), Reason: "value mismatch"}
+			}
+			return nil
+		}
 		if len(val) >= 1 {
 			switch first := val[0].(type) {
 			case nil:
@@ -1765,7 +2691,8 @@ func evaluateMatcherDiff(column string, expected any, actual any) *ColumnDiff {
 				case "currentdate", "current_date":
 					expectedTime, tolerance, display, err := evaluateRelativeTimeMatcher(val)
 					if err != nil {
-						return &ColumnDiff{Column: column, Expected: formatValueForDiff(val), Actual: formatValueForDiff(actual), Reason: err.Error()}
+						args, _ := splitCurrentDateAnchor(val)
+						return &ColumnDiff{Column: column, Expected: formatValueForDiff(args), Actual: formatValueForDiff(actual), Reason: err.Error()}
 					}
 
 					actualTime, ok := parseTimeValue(actual)
@@ -1807,6 +2734,25 @@ func evaluateMatcherDiff(column string, expected any, actual any) *ColumnDiff {
 						}
 						return nil
 					}
+				case "approx", "range":
+					display, err := matchNumericMatcher(matcher, val[1:], actual)
+					if err != nil {
+						return &ColumnDiff{Column: column, Expected: display, Actual: formatValueForDiff(actual), Reason: err.Error()}
+					}
+					return nil
+				case "json":
+					if err := matchJSONMatcher(val[1:], actual); err != nil {
+						return &ColumnDiff{Column: column, Expected: formatValueForDiff(expected), Actual: formatValueForDiff(actual), Reason: err.Error()}
+					}
+					return nil
+				case "literal":
+					if len(val) != 2 {
+						return &ColumnDiff{Column: column, Expected: formatValueForDiff(expected), Actual: formatValueForDiff(actual), Reason: "invalid matcher"}
+					}
+					if !valueEquals(val[1], actual) {
+						return &ColumnDiff{Column: column, Expected: formatValueForDiff(val[1]), Actual: formatValueForDiff(actual), Reason: "value mismatch"}
+					}
+					return nil
 				}
 			default:
 				return &ColumnDiff{Column: column, Expected: formatValueForDiff(expected), Actual: formatValueForDiff(actual), Reason: "invalid matcher"}
@@ -1821,8 +2767,235 @@ func evaluateMatcherDiff(column string, expected any, actual any) *ColumnDiff {
 	return nil
 }
 
+// matchNumericMatcher evaluates [approx, value, tolerance] and [range, min, max] against actual.
+// Range bounds are inclusive and a null bound leaves that side open. It returns the matcher
+// display form and an error when the matcher is malformed or actual does not satisfy it.
+func matchNumericMatcher(matcher string, args []any, actual any) (string, error) {
+	display := fmt.Sprintf("[%s,%s]", matcher, joinMatcherArgs(args))
+	if len(args) != 2 {
+		return display, errInvalidMatcherSyntax
+	}
+
+	var bounds [2]*float64
+	for i, arg := range args {
+		if arg == nil && matcher == "range" {
+			continue
+		}
+		n, ok := toNumber(arg)
+		if !ok {
+			return display, errInvalidMatcherSyntax
+		}
+		bounds[i] = &n
+	}
+
+	if actual == nil {
+		return display, errExpectedNumber
+	}
+	got, ok := toNumber(actual)
+	if !ok {
+		return display, errExpectedNumber
+	}
+
+	if matcher == "approx" {
+		if bounds[0] == nil || bounds[1] == nil {
+			return display, errInvalidMatcherSyntax
+		}
+		if math.Abs(got-*bounds[0]) > math.Abs(*bounds[1]) {
+			return display, errOutsideTolerance
+		}
+		return display, nil
+	}
+
+	if (bounds[0] != nil && got < *bounds[0]) || (bounds[1] != nil && got > *bounds[1]) {
+		return display, errOutsideRange
+	}
+	return display, nil
+}
+
+func joinMatcherArgs(args []any) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		if arg == nil {
+			parts[i] = "null"
+			continue
+		}
+		parts[i] = fmt.Sprint(arg)
+	}
+	return strings.Join(parts, ",")
+}
+
+// toNumber converts numeric values and numeric strings (as returned by some drivers) to float64
+func toNumber(v any) (float64, bool) {
+	if f, ok := toFloat(v); ok {
+		return f, true
+	}
+	var s string
+	switch val := v.(type) {
+	case string:
+		s = val
+	case []byte:
+		s = string(val)
+	default:
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, false
+	}
+	return f, true
+}
+
+// matchJSONMatcher evaluates [json, <expected>, <options>...]. The actual value is decoded from
+// JSON text when needed and compared structurally: object key order never matters. Arrays inside
+// the expected value are always literal JSON arrays; matchers are recognized only at the column value.
+// Options: "partial" ignores object keys missing from the expectation, "unordered" ignores array order.
+func matchJSONMatcher(args []any, actual any) error {
+	if len(args) == 0 {
+		return errInvalidMatcherSyntax
+	}
+
+	expected := args[0]
+	if text, ok := expected.(string); ok {
+		if err := json.Unmarshal([]byte(text), &expected); err != nil {
+			return fmt.Errorf("%w: %v", errInvalidMatcherSyntax, err)
+		}
+	}
+
+	var opts jsonMatchOptions
+	for _, arg := range args[1:] {
+		option, _ := arg.(string)
+		switch strings.ToLower(strings.TrimSpace(option)) {
+		case "partial":
+			opts.partial = true
+		case "unordered":
+			opts.unordered = true
+		default:
+			return fmt.Errorf("%w: unknown json option %v", errInvalidMatcherSyntax, arg)
+		}
+	}
+
+	var decoded any
+	switch val := actual.(type) {
+	case nil:
+		return errExpectedJSON
+	case string:
+		if err := json.Unmarshal([]byte(val), &decoded); err != nil {
+			return fmt.Errorf("%w: %v", errExpectedJSON, err)
+		}
+	case []byte:
+		if err := json.Unmarshal(val, &decoded); err != nil {
+			return fmt.Errorf("%w: %v", errExpectedJSON, err)
+		}
+	default:
+		decoded = actual
+	}
+
+	return compareJSONValue("$", expected, decoded, opts)
+}
+
+type jsonMatchOptions struct {
+	partial   bool
+	unordered bool
+}
+
+func compareJSONValue(path string, expected, actual any, opts jsonMatchOptions) error {
+	switch exp := expected.(type) {
+	case []any:
+		act, ok := actual.([]any)
+		if !ok {
+			return fmt.Errorf("%w at %s: expected array, got %v", errJSONMismatch, path, actual)
+		}
+		if len(exp) != len(act) {
+			return fmt.Errorf("%w at %s: expected %d elements, got %d", errJSONMismatch, path, len(exp), len(act))
+		}
+		if !opts.unordered {
+			for i := range exp {
+				if err := compareJSONValue(fmt.Sprintf("%s[%d]", path, i), exp[i], act[i], opts); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		used := make([]bool, len(act))
+		for i, elem := range exp {
+			found := false
+			for j, candidate := range act {
+				if used[j] {
+					continue
+				}
+				if compareJSONValue(path, elem, candidate, opts) == nil {
+					used[j] = true
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("%w at %s[%d]: no matching element for %v", errJSONMismatch, path, i, elem)
+			}
+		}
+		return nil
+	case map[string]any:
+		act, ok := actual.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%w at %s: expected object, got %v", errJSONMismatch, path, actual)
+		}
+		keys := make([]string, 0, len(exp))
+		for key := range exp {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value, ok := act[key]
+			if !ok {
+				return fmt.Errorf("%w at %s.%s: key missing", errJSONMismatch, path, key)
+			}
+			if err := compareJSONValue(path+"."+key, exp[key], value, opts); err != nil {
+				return err
+			}
+		}
+		if !opts.partial && len(act) != len(exp) {
+			extra := make([]string, 0)
+			for key := range act {
+				if _, ok := exp[key]; !ok {
+					extra = append(extra, key)
+				}
+			}
+			sort.Strings(extra)
+			return fmt.Errorf("%w at %s: unexpected keys %s", errJSONMismatch, path, strings.Join(extra, ", "))
+		}
+		return nil
+	default:
+		if !valueEquals(expected, actual) {
+			return fmt.Errorf("%w at %s: expected %v, got %v", errJSONMismatch, path, expected, actual)
+		}
+		return nil
+	}
+}
+
+// isMatcherArray reports whether arr is a value matcher like [null], [notnull] or [regexp, ...]
+// rather than a literal array. It is only consulted for column values; a literal array that starts
+// with a matcher name is written as [literal, [...]].
+func isMatcherArray(arr []any) bool {
+	if len(arr) == 0 {
+		return false
+	}
+	if arr[0] == nil {
+		return len(arr) == 1
+	}
+	name, ok := arr[0].(string)
+	if !ok {
+		return false
+	}
+	switch strings.ToLower(name) {
+	case "null", "notnull", "any", "currentdate", "current_date", "regexp", "approx", "range", "json", "literal":
+		return true
+	default:
+		return false
+	}
+}
+
 func evaluateRelativeTimeMatcher(arr []any) (time.Time, time.Duration, string, error) {
-	base := currentDateAnchorNow()
+	arr, base := splitCurrentDateAnchor(arr)
 	offset := time.Duration(0)
 	tolerance := time.Minute
 	offsetToken := ""
@@ -1920,6 +3093,13 @@ func compareRowsWithMatchers(expected, actual map[string]any) error {
 		// 値比較特殊指定
 		switch val := vExp.(type) {
 		case []any:
+			if !isMatcherArray(val) {
+				// 配列値の比較
+				if !valueEquals(vExp, vAct) {
+					return fmt.Errorf("%w: column=%s expected=%v got=%v", errValueMismatch, k, vExp, vAct)
+				}
+				break
+			}
 			if len(val) >= 1 {
 				switch first := val[0].(type) {
 				case nil:
@@ -1930,9 +3110,10 @@ func compareRowsWithMatchers(expected, actual map[string]any) error {
 					matcher := strings.ToLower(first)
 					switch matcher {
 					case "currentdate", "current_date":
+						args, base := splitCurrentDateAnchor(val)
 						tolerance := time.Minute
-						if len(val) >= 2 {
-							if durStr, ok := val[1].(string); ok && durStr != "" {
+						if len(args) >= 2 {
+							if durStr, ok := args[1].(string); ok && durStr != "" {
 								if parsed, err := time.ParseDuration(durStr); err == nil {
 									tolerance = parsed
 								}
@@ -1943,7 +3124,7 @@ func compareRowsWithMatchers(expected, actual map[string]any) error {
 						if !ok {
 							return fmt.Errorf("%w: column=%s value=%v", errInvalidMatcherSyntax, k, vAct)
 						}
-						if durationAbs(time.Since(actualTime)) > tolerance {
+						if durationAbs(base.Sub(actualTime)) > tolerance {
 							return fmt.Errorf("%w: column=%s value=%s tolerance=%s", errValueMismatch, k, actualTime.UTC().Format(time.RFC3339), tolerance.String())
 						}
 						break
@@ -1976,6 +3157,23 @@ func compareRowsWithMatchers(expected, actual map[string]any) error {
 						if !matched {
 							return fmt.Errorf("%w: column=%s value=%s pattern=%s", errRegexpNotMatch, k, s, pat)
 						}
+					case "approx", "range":
+						display, err := matchNumericMatcher(matcher, val[1:], vAct)
+						if err != nil {
+							return fmt.Errorf("%w: column=%s value=%v matcher=%s", err, k, vAct, display)
+						}
+					case "json":
+						if err := matchJSONMatcher(val[1:], vAct); err != nil {
+							return fmt.Errorf("column %s: %w", k, err)
+						}
+					case "literal":
+						// [literal, <value>]: マッチャー名で始まる配列をそのまま値として比較する
+						if len(val) != 2 {
+							return fmt.Errorf("%w: column=%s raw=%v", errInvalidMatcherSyntax, k, val)
+						}
+						if !valueEquals(val[1], vAct) {
+							return fmt.Errorf("%w: column=%s expected=%v got=%v", errValueMismatch, k, val[1], vAct)
+						}
 					default:
 						return fmt.Errorf("%w: column=%s matcher=%v", errUnknownMatcher, k, matcher)
 					}
@@ -2003,6 +3201,16 @@ func valueEquals(a, b any) bool {
 		return a == b
 	}
 
+	// 配列は要素ごとに比較（配列リテラルや JSON 配列の文字列も展開する）
+	if sa, ok := a.([]any); ok {
+		sb, ok := comparableSlice(b)
+		return ok && slicesEqual(sa, sb)
+	}
+	if sb, ok := b.([]any); ok {
+		sa, ok := comparableSlice(a)
+		return ok && slicesEqual(sa, sb)
+	}
+
 	// string と []byte の比較（SQLite で TEXT が []byte になるケース緩和）
 	if sa, ok := a.(string); ok {
 		if bb, ok2 := b.([]byte); ok2 {
@@ -2158,15 +3366,18 @@ func parseFlexibleDuration(raw string) (time.Duration, error) {
 }
 
 // insertData inserts data into a table
-func (e *Executor) insertData(tx *sql.Tx, tableName string, data []map[string]any) error {
+func (e *Executor) insertData(ctx context.Context, tx *sql.Tx, tableName string, data []map[string]any, seed int64) error {
 	if len(data) == 0 {
 		return nil
 	}
 
-	data, err := normalizeFixtureRows(data)
+	data, err := normalizeFixtureRows(data, seed)
 	if err != nil {
 		return wrapDefinitionFailureWithContext(map[string]string{"table": tableName, "operation": "normalize"}, err, "failed to normalize fixture row")
 	}
+	if err := e.encodeFixtureRows(tableName, data); err != nil {
+		return wrapDefinitionFailureWithContext(map[string]string{"table": tableName, "operation": "normalize"}, err, "failed to normalize fixture row")
+	}
 
 	// Determine column list: if schema present use ColumnOrder intersection with row keys for determinism
 	var columns []string
@@ -2212,40 +3423,53 @@ func (e *Executor) insertData(tx *sql.Tx, tableName string, data []map[string]an
 		placeholders[i] = e.getPlaceholder(i + 1)
 	}
 
-	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+	settings := ""
+	if e.dialect == snapsql.DialectClickHouse {
+		// Sent as asynchronous inserts that wait for the flush, so the rows are visible to the test
+		// even when the server buffers inserts
+		settings = " SETTINGS async_insert = 1, wait_for_async_insert = 1"
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s)%s VALUES (%s)",
 		e.quoteIdentifier(tableName),
 		strings.Join(quotedColumns, ", "),
+		settings,
 		strings.Join(placeholders, ", "))
 
-	// Prepare statement
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
-	stmt, err := tx.PrepareContext(ctx, query)
-	if err != nil {
-		ctxMap := map[string]string{
-			"table":     tableName,
-			"operation": "prepare",
-			"sql":       query,
+
+	// The ClickHouse driver turns an INSERT prepared in a transaction into a batch that is only
+	// sent on COMMIT, which never happens for a rolled back test, so its rows are executed directly
+	var stmt *sql.Stmt
+	if snapsql.SupportsTransactions(e.dialect) {
+		var err error
+		stmt, err = tx.PrepareContext(ctx, query)
+		if err != nil {
+			ctxMap := map[string]string{
+				"table":     tableName,
+				"operation": "prepare",
+				"sql":       query,
+			}
+			ctxMap["columns"] = strings.Join(columns, ",")
+			return wrapDefinitionFailureWithContext(ctxMap, err, "failed to prepare insert statement")
 		}
-		ctxMap["columns"] = strings.Join(columns, ",")
-		return wrapDefinitionFailureWithContext(ctxMap, err, "failed to prepare insert statement")
+		defer stmt.Close()
 	}
-	defer stmt.Close()
 
-	// Insert each row
-	for _, row := range data {
+	rowValues := func(row map[string]any) ([]any, error) {
 		if hasSchema && tbl != nil {
 			// per-row validation
 			for colName, colInfo := range tbl.Columns {
 				if !colInfo.Nullable && !colInfo.IsPrimaryKey {
 					if _, ok := row[colName]; !ok {
-						return fmt.Errorf("%w: %s", errMissingRequiredColumn, colName)
+						return nil, fmt.Errorf("%w: %s", errMissingRequiredColumn, colName)
 					}
 				}
 			}
 			for k := range row {
 				if _, ok := tbl.Columns[k]; !ok {
-					return fmt.Errorf("%w: %s", errUnknownFixtureColumn, k)
+					return nil, fmt.Errorf("%w: %s", errUnknownFixtureColumn, k)
 				}
 			}
 		}
@@ -2254,7 +3478,21 @@ func (e *Executor) insertData(tx *sql.Tx, tableName string, data []map[string]an
 			values[i] = row[col]
 		}
 
-		if _, err := stmt.ExecContext(ctx, values...); err != nil {
+		return values, nil
+	}
+
+	insertRow := func(row map[string]any) error {
+		values, err := rowValues(row)
+		if err != nil {
+			return err
+		}
+
+		if stmt != nil {
+			_, err = stmt.ExecContext(ctx, values...)
+		} else {
+			_, err = tx.ExecContext(ctx, query, values...)
+		}
+		if err != nil {
 			ctxMap := map[string]string{
 				"table":     tableName,
 				"operation": "insert",
@@ -2263,22 +3501,97 @@ func (e *Executor) insertData(tx *sql.Tx, tableName string, data []map[string]an
 			}
 			return wrapDefinitionFailureWithContext(ctxMap, err, "failed to insert row")
 		}
+
+		return nil
+	}
+
+	// MySQL and SQLite insert the rows with multi-row VALUES lists in chunks below the placeholder limit
+	rows := data
+	if rowsPerInsert := e.fixtureRowsPerInsert(len(columns)); rowsPerInsert > 1 {
+		rowPlaceholders := "(" + strings.Join(placeholders, ", ") + ")"
+
+		for len(rows) > 1 {
+			chunk := rows[:min(rowsPerInsert, len(rows))]
+
+			values := make([]any, 0, len(chunk)*len(columns))
+			for _, row := range chunk {
+				rowVals, err := rowValues(row)
+				if err != nil {
+					return err
+				}
+				values = append(values, rowVals...)
+			}
+
+			chunkQuery := fmt.Sprintf("INSERT INTO %s (%s) VALUES %s",
+				e.quoteIdentifier(tableName),
+				strings.Join(quotedColumns, ", "),
+				strings.Repeat(rowPlaceholders+", ", len(chunk)-1)+rowPlaceholders)
+
+			if _, err := tx.ExecContext(ctx, chunkQuery, values...); err != nil {
+				// The failed statement inserted nothing; replay the chunk row by row to report the offending row
+				for _, row := range chunk {
+					if rowErr := insertRow(row); rowErr != nil {
+						return rowErr
+					}
+				}
+
+				ctxMap := map[string]string{
+					"table":     tableName,
+					"operation": "insert",
+					"sql":       chunkQuery,
+					"rows":      strconv.Itoa(len(chunk)),
+				}
+				return wrapDefinitionFailureWithContext(ctxMap, err, "failed to insert rows")
+			}
+
+			rows = rows[len(chunk):]
+		}
+	}
+
+	// Insert each remaining row
+	for _, row := range rows {
+		if err := insertRow(row); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-// executePostgresUpsert implements upsert for PostgreSQL
-func (e *Executor) executePostgresUpsert(tx *sql.Tx, fixture markdownparser.TableFixture) error {
+// fixtureRowsPerInsert returns how many fixture rows of columns columns are inserted by a single
+// multi-row INSERT, or 0 when the dialect inserts them one statement per row.
+func (e *Executor) fixtureRowsPerInsert(columns int) int {
+	var maxPlaceholders int
+
+	switch e.dialect {
+	case snapsql.DialectMySQL, snapsql.DialectMariaDB:
+		maxPlaceholders = mysqlMaxPlaceholders
+	case snapsql.DialectSQLite:
+		maxPlaceholders = sqliteMaxPlaceholders
+	default:
+		return 0
+	}
+
+	if columns == 0 {
+		return 0
+	}
+
+	return min(maxPlaceholders/columns, maxFixtureRowsPerInsert)
+}
+
+// executePostgresUpsert implements upsert for PostgreSQL and DuckDB (INSERT ... ON CONFLICT)
+func (e *Executor) executePostgresUpsert(ctx context.Context, tx *sql.Tx, fixture markdownparser.TableFixture, seed int64) error {
 	pkCols, err := e.getPrimaryKeyColumns(fixture.TableName)
 	if err != nil {
 		return err
 	}
-	ctx := context.Background()
-	rows, err := normalizeFixtureRows(fixture.Data)
+	rows, err := normalizeFixtureRows(fixture.Data, seed)
 	if err != nil {
 		return fmt.Errorf("postgres upsert failed: %w", err)
 	}
+	if err := e.encodeFixtureRows(fixture.TableName, rows); err != nil {
+		return fmt.Errorf("postgres upsert failed: %w", err)
+	}
 	for _, row := range rows {
 		// スキーマ列順序使用。なければ行のキー集合
 		var cols []string
@@ -2357,17 +3670,20 @@ func (e *Executor) executePostgresUpsert(tx *sql.Tx, fixture markdownparser.Tabl
 }
 
 // executeMySQLUpsert implements upsert for MySQL
-func (e *Executor) executeMySQLUpsert(tx *sql.Tx, fixture markdownparser.TableFixture) error {
+func (e *Executor) executeMySQLUpsert(ctx context.Context, tx *sql.Tx, fixture markdownparser.TableFixture, seed int64) error {
 	pkCols, err := e.getPrimaryKeyColumns(fixture.TableName)
 	if err != nil {
 		return err
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
-	rows, err := normalizeFixtureRows(fixture.Data)
+	rows, err := normalizeFixtureRows(fixture.Data, seed)
 	if err != nil {
 		return fmt.Errorf("mysql upsert failed: %w", err)
 	}
+	if err := e.encodeFixtureRows(fixture.TableName, rows); err != nil {
+		return fmt.Errorf("mysql upsert failed: %w", err)
+	}
 	for _, row := range rows {
 		var cols []string
 		var placeholders []string
@@ -2421,17 +3737,20 @@ func (e *Executor) executeMySQLUpsert(tx *sql.Tx, fixture markdownparser.TableFi
 }
 
 // executeSQLiteUpsert implements upsert for SQLite
-func (e *Executor) executeSQLiteUpsert(tx *sql.Tx, fixture markdownparser.TableFixture) error {
+func (e *Executor) executeSQLiteUpsert(ctx context.Context, tx *sql.Tx, fixture markdownparser.TableFixture, seed int64) error {
 	pkCols, err := e.getPrimaryKeyColumns(fixture.TableName)
 	if err != nil {
 		return err
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
-	rows, err := normalizeFixtureRows(fixture.Data)
+	rows, err := normalizeFixtureRows(fixture.Data, seed)
 	if err != nil {
 		return fmt.Errorf("sqlite upsert failed: %w", err)
 	}
+	if err := e.encodeFixtureRows(fixture.TableName, rows); err != nil {
+		return fmt.Errorf("sqlite upsert failed: %w", err)
+	}
 	for _, row := range rows {
 		var cols []string
 		var placeholders []string
@@ -2526,7 +3845,7 @@ func reorderForSchema(order []string, cols []string, placeholders []string, valu
 // quoteIdentifier quotes database identifiers based on dialect
 func (e *Executor) quoteIdentifier(identifier string) string {
 	switch e.dialect {
-	case "postgres", "postgresql", "pg", "pgx":
+	case "postgres", "postgresql", "pg", "pgx", "duckdb", "clickhouse", "cockroach":
 		return fmt.Sprintf(`"%s"`, identifier)
 	case "mysql":
 		return fmt.Sprintf("`%s`", identifier)
@@ -2540,9 +3859,9 @@ func (e *Executor) quoteIdentifier(identifier string) string {
 // getPlaceholder returns the appropriate placeholder for the dialect
 func (e *Executor) getPlaceholder(position int) string {
 	switch e.dialect {
-	case "postgres", "postgresql", "pg", "pgx":
+	case "postgres", "postgresql", "pg", "pgx", "duckdb", "cockroach":
 		return fmt.Sprintf("$%d", position)
-	case "mysql", "sqlite":
+	case "mysql", "sqlite", "clickhouse":
 		return "?"
 	default:
 		return "?"
@@ -2561,7 +3880,7 @@ func (e *Executor) executeVerifyQuery(execution *TestExecution, verifyQuery stri
 			continue
 		}
 
-		result, err := e.executeSelectQuery(execution.Transaction, query, nil, "verify query")
+		result, err := e.executeSelectQuery(execution.Context, execution.Transaction, query, nil, "verify query")
 		if err != nil {
 			execution.addTrace("verify query", query, nil, nil, nil)
 			return nil, fmt.Errorf("failed to execute verify query: %w", err)
@@ -1,12 +1,18 @@
 package fixtureexecutor
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/explain"
 	"github.com/shibukawa/snapsql/markdownparser"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -176,6 +182,256 @@ func TestExecutor_ExecuteTest_UsesPreparedSQL(t *testing.T) {
 	assert.Equal(t, map[string]any{"id": 2}, trace[0].Parameters)
 }
 
+func TestExecutor_ExecuteTest_SoftDeletedRowsDoNotExist(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE users (
+			id INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			deleted_at TIMESTAMP
+		)
+	`)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`INSERT INTO users (id, name) VALUES (1, 'Alice'), (2, 'Bob')`)
+	require.NoError(t, err)
+
+	tableInfo := map[string]*snapsql.TableInfo{
+		"users": {
+			Name: "users",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":         {Name: "id", IsPrimaryKey: true},
+				"name":       {Name: "name"},
+				"deleted_at": {Name: "deleted_at"},
+			},
+		},
+	}
+
+	executor := NewExecutor(db, "sqlite", tableInfo)
+
+	testCase := &markdownparser.TestCase{
+		Name:        "Soft delete",
+		PreparedSQL: "UPDATE users SET deleted_at = CURRENT_TIMESTAMP WHERE (id = ?) AND deleted_at IS NULL",
+		SQLArgs:     []any{1},
+		ExpectedResults: []markdownparser.ExpectedResultSpec{
+			{TableName: "users", Strategy: "pk-not-exists", Data: []map[string]any{{"id": 1}}},
+			{TableName: "users", Strategy: "pk-exists", Data: []map[string]any{{"id": 2}}},
+			{TableName: "users", Strategy: "pk-match", Data: []map[string]any{{"id": 1, "deleted_at": []any{"notnull"}}}},
+		},
+	}
+
+	options := &ExecutionOptions{
+		Mode:              FullTest,
+		Parallel:          1,
+		Timeout:           time.Minute,
+		SoftDeleteColumns: map[string]string{"users": "deleted_at"},
+	}
+
+	_, _, _, err = executor.ExecuteTest(testCase, "DELETE FROM users WHERE id = /*= id */1", map[string]any{"id": 1}, options)
+	require.NoError(t, err)
+
+	// Without the soft delete column the row still exists physically
+	options.SoftDeleteColumns = nil
+	_, _, _, err = executor.ExecuteTest(testCase, "DELETE FROM users WHERE id = /*= id */1", map[string]any{"id": 1}, options)
+	require.ErrorContains(t, err, "pk row unexpectedly exists")
+}
+
+func TestExecutor_ExecuteTest_DeltaStrategy(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL)`)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`INSERT INTO users (id, name) VALUES (1, 'Alice'), (2, 'Bob')`)
+	require.NoError(t, err)
+
+	tableInfo := map[string]*snapsql.TableInfo{
+		"users": {
+			Name: "users",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":   {Name: "id", IsPrimaryKey: true},
+				"name": {Name: "name"},
+			},
+		},
+	}
+
+	executor := NewExecutor(db, "sqlite", tableInfo)
+
+	options := &ExecutionOptions{Mode: FullTest, Parallel: 1, Timeout: time.Minute}
+
+	newTestCase := func(expected ...map[string]any) *markdownparser.TestCase {
+		return &markdownparser.TestCase{
+			Name:        "Rename user",
+			PreparedSQL: "UPDATE users SET name = ? WHERE id = ?",
+			SQLArgs:     []any{"Alice2", 1},
+			ExpectedResults: []markdownparser.ExpectedResultSpec{
+				{TableName: "users", Strategy: "delta", Data: expected},
+			},
+		}
+	}
+
+	_, _, _, err = executor.ExecuteTest(newTestCase(
+		map[string]any{markdownparser.DeltaChangeKey: "updated", "id": 1, "name": "Alice2"},
+	), "", nil, options)
+	require.NoError(t, err)
+
+	_, _, _, err = executor.ExecuteTest(newTestCase(
+		map[string]any{markdownparser.DeltaChangeKey: "updated", "id": 1, "name": "Alice3"},
+	), "", nil, options)
+	require.ErrorContains(t, err, "value mismatch")
+
+	_, _, _, err = executor.ExecuteTest(newTestCase(), "", nil, options)
+	require.ErrorIs(t, err, errDeltaUnexpectedChange)
+}
+
+func TestExecutor_ExecuteTest_QueryAssertions(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE orders (id INTEGER PRIMARY KEY, status TEXT NOT NULL, amount REAL NOT NULL, deleted_at TIMESTAMP)`)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`INSERT INTO orders (id, status, amount, deleted_at) VALUES (1, 'paid', 10.5, NULL), (2, 'open', 5, NULL), (3, 'paid', 7, CURRENT_TIMESTAMP)`)
+	require.NoError(t, err)
+
+	executor := NewExecutor(db, "sqlite", nil)
+
+	options := &ExecutionOptions{
+		Mode:              FullTest,
+		Parallel:          1,
+		Timeout:           time.Minute,
+		SoftDeleteColumns: map[string]string{"orders": "deleted_at"},
+	}
+
+	newTestCase := func(assertions ...markdownparser.QueryAssertion) *markdownparser.TestCase {
+		return &markdownparser.TestCase{
+			Name:        "Pay order",
+			PreparedSQL: "UPDATE orders SET status = 'paid' WHERE id = ?",
+			SQLArgs:     []any{2},
+			Assertions:  assertions,
+		}
+	}
+
+	_, _, _, err = executor.ExecuteTest(newTestCase(
+		markdownparser.QueryAssertion{Table: "orders", Expected: int64(2)},
+		markdownparser.QueryAssertion{Query: "SELECT COUNT(*) FROM orders WHERE status = 'paid'", Expected: int64(3)},
+		markdownparser.QueryAssertion{Query: "SELECT SUM(amount) FROM orders WHERE deleted_at IS NULL", Expected: 15.5},
+	), "", nil, options)
+	require.NoError(t, err)
+
+	_, _, _, err = executor.ExecuteTest(newTestCase(
+		markdownparser.QueryAssertion{Table: "orders", Expected: int64(3)},
+	), "", nil, options)
+	require.ErrorIs(t, err, errAssertionMismatch)
+
+	_, _, _, err = executor.ExecuteTest(newTestCase(
+		markdownparser.QueryAssertion{Query: "SELECT id, status FROM orders", Expected: int64(1)},
+	), "", nil, options)
+	require.ErrorIs(t, err, errAssertionNotScalar)
+}
+
+func TestCompareDelta(t *testing.T) {
+	pkCols := []string{"id"}
+	before := []map[string]any{
+		{"id": int64(1), "name": "Alice"},
+		{"id": int64(2), "name": "Bob"},
+	}
+	after := []map[string]any{
+		{"id": int64(1), "name": "Alice"},
+		{"id": int64(3), "name": "Carol"},
+	}
+
+	err := compareDelta(pkCols, []map[string]any{
+		{markdownparser.DeltaChangeKey: "added", "id": 3, "name": "Carol"},
+		{markdownparser.DeltaChangeKey: "deleted", "id": 2, "name": "Bob"},
+	}, before, after)
+	require.NoError(t, err)
+
+	err = compareDelta(pkCols, []map[string]any{
+		{markdownparser.DeltaChangeKey: "added", "id": 3},
+		{markdownparser.DeltaChangeKey: "updated", "id": 2},
+	}, before, after)
+	require.ErrorIs(t, err, errDeltaMismatch)
+
+	err = compareDelta(pkCols, []map[string]any{
+		{markdownparser.DeltaChangeKey: "deleted", "id": 1},
+	}, before, after)
+	require.ErrorIs(t, err, errDeltaMismatch)
+
+	err = compareDelta(nil, nil, before, after)
+	require.ErrorIs(t, err, errNoPrimaryKeyDefined)
+}
+
+func TestExecutor_ExecuteTest_ExplainRules(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL)`)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`INSERT INTO users (id, name) VALUES (1, 'Alice'), (2, 'Bob')`)
+	require.NoError(t, err)
+
+	executor := NewExecutor(db, "sqlite", map[string]*snapsql.TableInfo{
+		"users": {
+			Name: "users",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":   {Name: "id", IsPrimaryKey: true},
+				"name": {Name: "name"},
+			},
+		},
+	})
+
+	testCase := &markdownparser.TestCase{
+		Name:           "scan by name",
+		PreparedSQL:    "SELECT id FROM users WHERE name = ?",
+		SQLArgs:        []any{"Bob"},
+		ExpectedResult: []map[string]any{{"id": 2}},
+	}
+
+	run := func(meta explain.TableMetadata) (*explain.PerformanceEvaluation, error) {
+		_, _, perf, err := executor.ExecuteTest(testCase, "", nil, &ExecutionOptions{
+			Mode:               FullTest,
+			Parallel:           1,
+			Timeout:            time.Minute,
+			PerformanceEnabled: true,
+			TableMetadata:      map[string]explain.TableMetadata{"users": meta},
+			Explain:            true,
+			ExplainRules:       []explain.Rule{{Kind: explain.WarningFullScan, MinRows: 100}},
+		})
+
+		return perf, err
+	}
+
+	perf, err := run(explain.TableMetadata{ExpectedRows: 1000})
+	require.ErrorIs(t, err, ErrExplainRuleViolation)
+	assert.Contains(t, err.Error(), "full scan on users (rows=1000, min_rows=100)")
+	require.NotNil(t, perf)
+	require.NotNil(t, perf.Plan)
+	assert.Contains(t, perf.Plan.Text(), "SCAN")
+
+	failure, ok := AsFixtureFailure(err)
+	require.True(t, ok)
+	assert.Equal(t, FailureKindAssertion, failure.Kind())
+
+	_, err = run(explain.TableMetadata{ExpectedRows: 50})
+	require.NoError(t, err)
+
+	_, err = run(explain.TableMetadata{ExpectedRows: 1000, AllowFullScan: true})
+	require.NoError(t, err)
+}
+
 func TestExecutor_ExecuteTest_TraceCapturedOnExecutionError(t *testing.T) {
 	// Create in-memory SQLite database
 	db, err := sql.Open("sqlite3", ":memory:")
@@ -288,6 +544,224 @@ func TestExecutor_FixtureCurrentDateValue(t *testing.T) {
 	assert.True(t, durationAbs(time.Since(created)) <= 3*time.Hour, "expected timestamp within tolerance: %v", created)
 }
 
+func TestExecutor_InsertDataInChunks(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	_, err = db.Exec("CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT NOT NULL)")
+	require.NoError(t, err)
+
+	executor := NewExecutor(db, "sqlite", map[string]*snapsql.TableInfo{
+		"items": {
+			Name:        "items",
+			ColumnOrder: []string{"id", "name"},
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":   {Name: "id", IsPrimaryKey: true},
+				"name": {Name: "name"},
+			},
+		},
+	})
+
+	// 999 placeholders / 2 columns = 499 rows per INSERT
+	assert.Equal(t, 499, executor.fixtureRowsPerInsert(2))
+	assert.Equal(t, 0, NewExecutor(db, "postgres", nil).fixtureRowsPerInsert(2))
+
+	ctx := context.Background()
+
+	t.Run("inserts every chunk", func(t *testing.T) {
+		tx, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+
+		defer tx.Rollback()
+
+		data := make([]map[string]any, 1200)
+		for i := range data {
+			data[i] = map[string]any{"id": i + 1, "name": "item" + strconv.Itoa(i+1)}
+		}
+
+		require.NoError(t, executor.insertData(ctx, tx, "items", data, 0))
+
+		var count, last int
+		require.NoError(t, tx.QueryRow("SELECT COUNT(*), MAX(id) FROM items").Scan(&count, &last))
+		assert.Equal(t, 1200, count)
+		assert.Equal(t, 1200, last)
+	})
+
+	t.Run("reports the offending row", func(t *testing.T) {
+		tx, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+
+		defer tx.Rollback()
+
+		data := []map[string]any{
+			{"id": 1, "name": "a"},
+			{"id": 2, "name": "b"},
+			{"id": 2, "name": "duplicate"},
+		}
+
+		err = executor.insertData(ctx, tx, "items", data, 0)
+		require.Error(t, err)
+
+		var fixtureErr *FixtureError
+		require.ErrorAs(t, err, &fixtureErr)
+		assert.Equal(t, "2,duplicate", fixtureErr.Context()["args"])
+	})
+}
+
+func TestExecutor_DeferConstraints(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file:defer_constraints?mode=memory&_foreign_keys=on")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`
+		CREATE TABLE teams (id INTEGER PRIMARY KEY, leader_id INTEGER NOT NULL REFERENCES members(id));
+		CREATE TABLE members (id INTEGER PRIMARY KEY, team_id INTEGER NOT NULL REFERENCES teams(id));
+	`)
+	require.NoError(t, err)
+
+	executor := NewExecutor(db, "sqlite", nil)
+	ctx := context.Background()
+
+	fixtures := func(deferConstraints bool) []markdownparser.TableFixture {
+		return []markdownparser.TableFixture{
+			{TableName: "teams", Strategy: markdownparser.ClearInsert, DeferConstraints: deferConstraints, Data: []map[string]any{{"id": 1, "leader_id": 10}}},
+			{TableName: "members", Strategy: markdownparser.ClearInsert, Data: []map[string]any{{"id": 10, "team_id": 1}}},
+		}
+	}
+
+	t.Run("immediate checks reject the circular rows", func(t *testing.T) {
+		tx, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+
+		defer tx.Rollback()
+
+		assert.Error(t, executor.executeFixtures(ctx, tx, fixtures(false)))
+	})
+
+	t.Run("deferred checks load the circular rows", func(t *testing.T) {
+		tx, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+
+		defer tx.Rollback()
+
+		require.NoError(t, executor.executeFixtures(ctx, tx, fixtures(true)))
+
+		var deferred int
+		require.NoError(t, tx.QueryRow("PRAGMA defer_foreign_keys").Scan(&deferred))
+		assert.Equal(t, 0, deferred, "defer_foreign_keys must be restored after the fixtures")
+	})
+
+	t.Run("unsupported dialect", func(t *testing.T) {
+		tx, err := db.BeginTx(ctx, nil)
+		require.NoError(t, err)
+
+		defer tx.Rollback()
+
+		err = NewExecutor(db, snapsql.DialectDuckDB, nil).executeFixtures(ctx, tx, fixtures(true))
+		assert.ErrorIs(t, err, errDeferNotSupported)
+	})
+}
+
+func TestExecutor_TruncateStrategy(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL)`)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`INSERT INTO users (name) VALUES ('old1'), ('old2'), ('old3')`)
+	require.NoError(t, err)
+
+	executor := NewExecutor(db, "sqlite", nil)
+	ctx := context.Background()
+	fixture := markdownparser.TableFixture{TableName: "users", Strategy: markdownparser.Truncate, Data: []map[string]any{{"name": "alice"}}}
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+
+	defer tx.Rollback()
+
+	require.NoError(t, executor.executeTableFixture(ctx, tx, fixture, 0))
+
+	var id int64
+	require.NoError(t, tx.QueryRow("SELECT id FROM users WHERE name = 'alice'").Scan(&id))
+	assert.Equal(t, int64(1), id, "the AUTOINCREMENT counter must be reset")
+
+	result, err := tx.Exec("INSERT INTO users (name) VALUES ('bob')")
+	require.NoError(t, err)
+
+	id, err = result.LastInsertId()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), id)
+
+	t.Run("unsupported dialect", func(t *testing.T) {
+		err := NewExecutor(db, snapsql.DialectMySQL, nil).executeTableFixture(ctx, tx, fixture, 0)
+		assert.ErrorIs(t, err, errTruncateNotSupported)
+	})
+}
+
+func TestExecutor_AutofillFixture(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, age INTEGER NOT NULL, active BOOLEAN NOT NULL, created_at TIMESTAMP NOT NULL, note TEXT)`)
+	require.NoError(t, err)
+
+	tableInfo := map[string]*snapsql.TableInfo{
+		"users": {
+			Name: "users",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":         {Name: "id", DataType: "int", IsPrimaryKey: true},
+				"name":       {Name: "name", DataType: "string"},
+				"age":        {Name: "age", DataType: "int"},
+				"active":     {Name: "active", DataType: "bool"},
+				"created_at": {Name: "created_at", DataType: "timestamp"},
+				"note":       {Name: "note", DataType: "string", Nullable: true},
+			},
+			ColumnOrder: []string{"id", "name", "age", "active", "created_at", "note"},
+		},
+	}
+	executor := NewExecutor(db, "sqlite", tableInfo)
+	ctx := context.Background()
+	rows := []map[string]any{{"id": 1, "name": "alice"}}
+
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+
+	defer tx.Rollback()
+
+	err = executor.executeFixtures(ctx, tx, []markdownparser.TableFixture{{TableName: "users", Strategy: markdownparser.ClearInsert, Data: rows}})
+	require.ErrorIs(t, err, errMissingRequiredColumn)
+
+	require.NoError(t, executor.executeFixtures(ctx, tx, []markdownparser.TableFixture{{TableName: "users", Strategy: markdownparser.ClearInsert, Autofill: true, Data: rows}}))
+
+	var (
+		age    int
+		active bool
+		note   sql.NullString
+	)
+
+	require.NoError(t, tx.QueryRow("SELECT age, active, note FROM users WHERE id = 1").Scan(&age, &active, &note))
+	assert.Equal(t, 0, age)
+	assert.False(t, active)
+	assert.False(t, note.Valid, "nullable columns are not filled")
+	assert.Equal(t, []map[string]any{{"id": 1, "name": "alice"}}, rows, "the fixture rows are not modified")
+
+	err = NewExecutor(db, "sqlite", nil).executeFixtures(ctx, tx, []markdownparser.TableFixture{{TableName: "users", Autofill: true, Data: rows}})
+	assert.ErrorIs(t, err, errTableInfoNotFound)
+}
+
 func TestExecutor_ClearInsertStrategy(t *testing.T) {
 	// Create in-memory SQLite database for testing
 	db, err := sql.Open("sqlite3", ":memory:")
@@ -451,15 +925,101 @@ func TestExecutor_ClearInsertStrategy_NullPlaceholder(t *testing.T) {
 	assert.False(t, archived.Valid, "archived_at should be NULL")
 }
 
-func TestTestRunner_RunTests(t *testing.T) {
-	// Create in-memory SQLite database for testing
-	db, err := sql.Open("sqlite3", ":memory:")
-	require.NoError(t, err)
+func TestExecutor_LoadExternalRows_CSVAndTSV(t *testing.T) {
+	dir := t.TempDir()
+	csvData := "id,name,score,active,note\n1,Alice,9.5,true,\n2,\"Bob, Jr.\",,false,[notnull]\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.csv"), []byte(csvData), 0o644))
+	tsvData := "id\tname\n10\tCarol\n11\t\\N\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.tsv"), []byte(tsvData), 0o644))
 
-	defer db.Close()
+	executor := NewExecutor(nil, "sqlite", map[string]*snapsql.TableInfo{
+		"users": {
+			Name: "users",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":     {Name: "id", DataType: "int", IsPrimaryKey: true},
+				"name":   {Name: "name", DataType: "string"},
+				"score":  {Name: "score", DataType: "float"},
+				"active": {Name: "active", DataType: "bool"},
+				"note":   {Name: "note", DataType: "string"},
+			},
+		},
+	})
+	executor.SetBaseDir(dir)
 
-	// Create test table
-	_, err = db.Exec(`
+	rows, err := executor.loadExternalRows("users.csv", "users")
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{"id": int64(1), "name": "Alice", "score": 9.5, "active": true, "note": ""},
+		{"id": int64(2), "name": "Bob, Jr.", "score": nil, "active": false, "note": []any{"notnull"}},
+	}, rows)
+
+	rows, err = executor.loadExternalRows("users.tsv", "")
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]any{
+		{"id": int64(10), "name": "Carol"},
+		{"id": int64(11), "name": nil},
+	}, rows)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.csv"), []byte("id\nabc\n"), 0o644))
+	_, err = executor.loadExternalRows("broken.csv", "users")
+	require.ErrorIs(t, err, errExternalCSVValue)
+	require.ErrorIs(t, err, strconv.ErrSyntax)
+}
+
+func TestIsIntegerColumnType(t *testing.T) {
+	for _, dataType := range []string{"int", "integer", "bigint", "bigint(20) unsigned", "smallint", "int8", "serial"} {
+		assert.True(t, isIntegerColumnType(dataType), dataType)
+	}
+
+	for _, dataType := range []string{"point", "interval", "uint", "varchar", "decimal(10,2)", ""} {
+		assert.False(t, isIntegerColumnType(dataType), dataType)
+	}
+}
+
+func TestNormalizeFixtureRows_Generators(t *testing.T) {
+	template := []map[string]any{
+		{"id": []any{"seq", 100}, "uuid": []any{"uuid"}, "email": "[faker, email]", "name": []any{"faker", "name"}},
+	}
+
+	seed := fixtureSeed("users", 0, 1)
+
+	rows, err := normalizeFixtureRows(expandFixtureCount(template, 3), seed)
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	for i, row := range rows {
+		assert.Equal(t, int64(100+i), row["id"])
+		assert.Len(t, row["uuid"], 36)
+		assert.Regexp(t, fmt.Sprintf(`^[a-z]+\.[a-z]+%d@example\.com$`, i+1), row["email"])
+		assert.Contains(t, row["name"], " ")
+	}
+
+	assert.NotEqual(t, rows[0]["uuid"], rows[1]["uuid"])
+	assert.Len(t, template, 1, "template rows must not be modified")
+
+	again, err := normalizeFixtureRows(expandFixtureCount(template, 3), seed)
+	require.NoError(t, err)
+	assert.Equal(t, rows, again, "generated values are deterministic")
+
+	for _, other := range []int64{fixtureSeed("orders", 0, 1), fixtureSeed("users", 1, 1), fixtureSeed("users", 0, 2)} {
+		next, err := normalizeFixtureRows(expandFixtureCount(template, 3), other)
+		require.NoError(t, err)
+		assert.NotEqual(t, rows[0]["uuid"], next[0]["uuid"], "table, block and run change the generated values")
+	}
+
+	_, err = normalizeFixtureRows([]map[string]any{{"x": []any{"faker", "unknown"}}}, seed)
+	require.ErrorIs(t, err, errUnknownGenerator)
+}
+
+func TestTestRunner_RunTests(t *testing.T) {
+	// Create in-memory SQLite database for testing
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	// Create test table
+	_, err = db.Exec(`
 		CREATE TABLE users (
 			id INTEGER PRIMARY KEY,
 			name TEXT NOT NULL,
@@ -541,6 +1101,316 @@ func TestTestRunner_RunTests(t *testing.T) {
 	assert.Equal(t, 0, count, "Data should be rolled back")
 }
 
+func TestTestRunner_ExecutionOrderShuffle(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	testCases := make([]*markdownparser.TestCase, 20)
+	for i := range testCases {
+		testCases[i] = &markdownparser.TestCase{Name: fmt.Sprintf("Test %d", i)}
+	}
+
+	names := func(cases []*markdownparser.TestCase) []string {
+		result := make([]string, len(cases))
+		for i, tc := range cases {
+			result[i] = tc.Name
+		}
+
+		return result
+	}
+
+	runner := NewTestRunner(db, "sqlite", &ExecutionOptions{Parallel: 1, Timeout: time.Minute})
+	assert.Equal(t, names(testCases), names(runner.executionOrder(testCases)))
+
+	shuffled := NewTestRunner(db, "sqlite", &ExecutionOptions{Parallel: 1, Timeout: time.Minute, Shuffle: true, ShuffleSeed: 42})
+	first := names(shuffled.executionOrder(testCases))
+	assert.Equal(t, first, names(shuffled.executionOrder(testCases)), "same seed gives the same order")
+	assert.NotEqual(t, names(testCases), first)
+	assert.ElementsMatch(t, names(testCases), first)
+	assert.Equal(t, "Test 0", testCases[0].Name, "input slice is not reordered")
+}
+
+func TestTestRunner_BuildJobsKeepsOrder(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	setup := &markdownparser.FixtureSetup{}
+	testCases := []*markdownparser.TestCase{
+		{Name: "A"},
+		{Name: "B", Setup: setup},
+		{Name: "C"},
+		{Name: "D", Setup: setup},
+	}
+
+	runner := NewTestRunner(db, "sqlite", &ExecutionOptions{Parallel: 4, Timeout: time.Minute})
+	jobs := runner.buildJobs(testCases)
+	require.Len(t, jobs, 3)
+
+	assert.Nil(t, jobs[0].setup)
+	assert.Equal(t, "A", jobs[0].testCases[0].Name)
+	assert.Equal(t, setup, jobs[1].setup, "setup group is placed at its first test case")
+	assert.Len(t, jobs[1].testCases, 2)
+	assert.Equal(t, "D", jobs[1].testCases[1].Name)
+	assert.Equal(t, "C", jobs[2].testCases[0].Name)
+}
+
+func TestTestRunner_NonTransactionalDialect(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	setup := &markdownparser.FixtureSetup{}
+	testCases := []*markdownparser.TestCase{
+		{Name: "A", Setup: setup},
+		{Name: "B", Setup: setup},
+	}
+
+	runner := NewTestRunner(db, snapsql.DialectClickHouse, &ExecutionOptions{Parallel: 4, Timeout: time.Minute})
+	assert.Equal(t, 1, cap(runner.workerPool), "test cases sharing one database run one at a time")
+
+	jobs := runner.buildJobs(testCases)
+	require.Len(t, jobs, 2, "setup groups are not shared through savepoints")
+	assert.Nil(t, jobs[0].setup)
+	assert.Nil(t, jobs[1].setup)
+
+	executor := NewExecutor(db, snapsql.DialectClickHouse, nil)
+	assert.Equal(t, `"users"`, executor.quoteIdentifier("users"))
+	assert.Equal(t, "?", executor.getPlaceholder(2))
+}
+
+func TestTestRunner_RunTests_MaxFailures(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	testCases := make([]*markdownparser.TestCase, 20)
+	for i := range testCases {
+		testCases[i] = &markdownparser.TestCase{
+			Name:           fmt.Sprintf("Failing %d", i),
+			PreparedSQL:    "SELECT 1 AS v",
+			ExpectedResult: []map[string]any{{"v": 2}},
+		}
+	}
+
+	options := &ExecutionOptions{Mode: FullTest, Parallel: 1, Timeout: time.Minute, MaxFailures: 1}
+
+	summary, err := NewTestRunner(db, "sqlite", options).RunTests(context.Background(), testCases)
+	require.NoError(t, err)
+
+	assert.GreaterOrEqual(t, summary.FailedTests, 1)
+	assert.Positive(t, summary.SkippedTests, "test cases after the first failure are skipped")
+	assert.Equal(t, len(testCases), summary.FailedTests+summary.SkippedTests)
+	assert.Len(t, summary.Results, summary.FailedTests)
+
+	options.MaxFailures = 0
+
+	summary, err = NewTestRunner(db, "sqlite", options).RunTests(context.Background(), testCases)
+	require.NoError(t, err)
+	assert.Equal(t, len(testCases), summary.FailedTests)
+	assert.Zero(t, summary.SkippedTests)
+}
+
+func TestTestRunner_RunTests_Retries(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE attempts (id INTEGER PRIMARY KEY AUTOINCREMENT, x INTEGER)")
+	require.NoError(t, err)
+
+	// Each committed attempt inserts a row, so only the second attempt returns id 2
+	flaky := &markdownparser.TestCase{
+		Name:           "Passes on second attempt",
+		PreparedSQL:    "INSERT INTO attempts (x) VALUES (1) RETURNING id AS v",
+		ExpectedResult: []map[string]any{{"v": 2}},
+	}
+	broken := &markdownparser.TestCase{
+		Name:           "Always fails",
+		PreparedSQL:    "SELECT 1 AS v",
+		ExpectedResult: []map[string]any{{"v": 2}},
+	}
+
+	options := &ExecutionOptions{Mode: FullTest, Parallel: 1, Timeout: time.Minute, Commit: true, Retries: 2}
+
+	summary, err := NewTestRunner(db, "sqlite", options).RunTests(context.Background(), []*markdownparser.TestCase{flaky, broken})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, summary.PassedTests)
+	assert.Equal(t, 1, summary.FailedTests)
+	assert.Equal(t, 1, summary.FlakyTests)
+
+	for _, result := range summary.Results {
+		switch result.TestCase {
+		case flaky:
+			assert.True(t, result.Flaky)
+			assert.Equal(t, 2, result.Attempts)
+			require.Error(t, result.FlakyError)
+		case broken:
+			assert.False(t, result.Flaky)
+			assert.Equal(t, 3, result.Attempts)
+		}
+	}
+}
+
+func TestTestRunner_RunTests_WorkerDatabases(t *testing.T) {
+	// The shared database has no schema; every test must run on a worker database
+	shared, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer shared.Close()
+
+	workerDBs := make([]*sql.DB, 0, 2)
+
+	for _, name := range []string{"worker-a", "worker-b"} {
+		db, err := sql.Open("sqlite3", "file:"+name+"?mode=memory&cache=shared")
+		require.NoError(t, err)
+
+		defer db.Close()
+
+		db.SetMaxOpenConns(1)
+
+		_, err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL)`)
+		require.NoError(t, err)
+
+		workerDBs = append(workerDBs, db)
+	}
+
+	testCases := make([]*markdownparser.TestCase, 0, 4)
+	for i := 1; i <= 4; i++ {
+		testCases = append(testCases, &markdownparser.TestCase{
+			Name: "Test",
+			Fixtures: []markdownparser.TableFixture{
+				{
+					TableName: "users",
+					Strategy:  markdownparser.Upsert,
+					Data:      []map[string]any{{"id": i, "name": "User"}},
+				},
+			},
+		})
+	}
+
+	options := &ExecutionOptions{
+		Mode:     FixtureOnly,
+		Commit:   true,
+		Parallel: 8,
+		Timeout:  time.Minute,
+	}
+
+	runner := NewTestRunner(shared, "sqlite", options)
+	runner.SetWorkerDatabases(workerDBs)
+	runner.SetTableInfo(map[string]*snapsql.TableInfo{
+		"users": {
+			Name: "users",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":   {Name: "id", IsPrimaryKey: true},
+				"name": {Name: "name"},
+			},
+		},
+	})
+
+	assert.Equal(t, 2, cap(runner.workerPool))
+
+	summary, err := runner.RunTests(t.Context(), testCases)
+	require.NoError(t, err)
+	assert.Equal(t, 4, summary.PassedTests)
+
+	total := 0
+
+	for _, db := range workerDBs {
+		var count int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM users").Scan(&count))
+
+		total += count
+	}
+
+	assert.Equal(t, 4, total, "committed rows should be spread over the worker databases")
+}
+
+func TestTestRunner_RunTests_SharedSetup(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	// The setup transaction and the test cases must share one in-memory database
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL)`)
+	require.NoError(t, err)
+
+	setup := &markdownparser.FixtureSetup{
+		Fixtures: []markdownparser.TableFixture{
+			{
+				TableName: "users",
+				Strategy:  markdownparser.ClearInsert,
+				Data: []map[string]any{
+					{"id": 1, "name": "Alice"},
+					{"id": 2, "name": "Bob"},
+				},
+			},
+		},
+	}
+
+	testCases := []*markdownparser.TestCase{
+		{
+			Name:        "Delete Alice",
+			Setup:       setup,
+			PreparedSQL: "DELETE FROM users WHERE id = ?",
+			SQLArgs:     []any{1},
+			ExpectedResults: []markdownparser.ExpectedResultSpec{
+				{TableName: "users", Strategy: "pk-not-exists", Data: []map[string]any{{"id": 1}}},
+				{TableName: "users", Strategy: "pk-exists", Data: []map[string]any{{"id": 2}}},
+			},
+		},
+		{
+			Name:           "Count after rollback to savepoint",
+			Setup:          setup,
+			PreparedSQL:    "SELECT COUNT(*) AS cnt FROM users",
+			ExpectedResult: []map[string]any{{"cnt": 2}},
+		},
+	}
+
+	options := &ExecutionOptions{
+		Mode:     FullTest,
+		Parallel: 1,
+		Timeout:  time.Minute,
+	}
+
+	runner := NewTestRunner(db, "sqlite", options)
+	runner.SetTableInfo(map[string]*snapsql.TableInfo{
+		"users": {
+			Name: "users",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":   {Name: "id", IsPrimaryKey: true},
+				"name": {Name: "name"},
+			},
+		},
+	})
+
+	summary, err := runner.RunTests(t.Context(), testCases)
+	require.NoError(t, err)
+
+	for _, result := range summary.Results {
+		assert.True(t, result.Success, "Test %s should succeed: %v", result.TestCase.Name, result.Error)
+	}
+
+	assert.Equal(t, 2, summary.PassedTests)
+
+	var count int
+
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM users").Scan(&count))
+	assert.Equal(t, 0, count, "setup fixtures should be rolled back")
+}
+
 func TestCompareRowsWithMatchersCurrentDate(t *testing.T) {
 	now := time.Now().UTC()
 	rowExpected := map[string]any{
@@ -563,6 +1433,103 @@ func TestCompareRowsWithMatchersCurrentDate(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestCompareRowsWithMatchersNumeric(t *testing.T) {
+	rowExpected := map[string]any{
+		"avg":   []any{"approx", 3.14, 0.01},
+		"count": []any{"range", 1, 100},
+		"total": []any{"range", 10, nil},
+	}
+	rowActual := map[string]any{
+		"avg":   3.1415,
+		"count": int64(42),
+		"total": []byte("1000.5"),
+	}
+
+	require.NoError(t, compareRowsWithMatchers(rowExpected, rowActual))
+	assert.Nil(t, evaluateMatcherDiff("avg", rowExpected["avg"], rowActual["avg"]))
+
+	err := compareRowsWithMatchers(map[string]any{"avg": []any{"approx", 3.14, 0.001}}, map[string]any{"avg": 3.15})
+	require.ErrorIs(t, err, errOutsideTolerance)
+
+	err = compareRowsWithMatchers(map[string]any{"count": []any{"range", 1, 100}}, map[string]any{"count": 101})
+	require.ErrorIs(t, err, errOutsideRange)
+
+	err = compareRowsWithMatchers(map[string]any{"count": []any{"range", 1, 100}}, map[string]any{"count": "many"})
+	require.ErrorIs(t, err, errExpectedNumber)
+
+	err = compareRowsWithMatchers(map[string]any{"avg": []any{"approx", 3.14}}, map[string]any{"avg": 3.14})
+	require.ErrorIs(t, err, errInvalidMatcherSyntax)
+
+	diff := evaluateMatcherDiff("count", []any{"range", 1, 100}, 0)
+	require.NotNil(t, diff)
+	assert.Equal(t, "[range,1,100]", diff.Expected)
+	assert.Equal(t, "value outside range", diff.Reason)
+}
+
+func TestCompareRowsWithMatchersJSON(t *testing.T) {
+	actual := map[string]any{
+		"profile": []byte(`{"name":"alice","tags":["b","a"],"score":1.502,"meta":{"id":"x-1","created":"2024-01-01"}}`),
+	}
+
+	expected := map[string]any{
+		"profile": []any{"json", map[string]any{
+			"name":  "alice",
+			"tags":  []any{"a", "b"},
+			"score": 1.502,
+			"meta":  map[string]any{"id": "x-1"},
+		}, "partial", "unordered"},
+	}
+	require.NoError(t, compareRowsWithMatchers(expected, actual))
+	assert.Nil(t, evaluateMatcherDiff("profile", expected["profile"], actual["profile"]))
+
+	err := compareRowsWithMatchers(map[string]any{
+		"profile": []any{"json", `{"name":"alice","tags":["b","a"],"score":1.502,"meta":{"id":"y-1"}}`, "partial"},
+	}, actual)
+	require.ErrorIs(t, err, errJSONMismatch)
+	assert.Contains(t, err.Error(), "$.meta.id")
+
+	err = compareRowsWithMatchers(map[string]any{
+		"profile": []any{"json", map[string]any{"name": "alice"}},
+	}, actual)
+	require.ErrorIs(t, err, errJSONMismatch)
+	assert.Contains(t, err.Error(), "unexpected keys meta, score, tags")
+
+	err = compareRowsWithMatchers(map[string]any{"profile": []any{"json", map[string]any{}}}, map[string]any{"profile": "not json"})
+	require.ErrorIs(t, err, errExpectedJSON)
+}
+
+func TestCompareRowsWithMatchersLiteralArray(t *testing.T) {
+	// 配列の中はマッチャーとして扱わない
+	actual := map[string]any{"profile": `{"tags":["json","yaml"],"codes":["regexp","^x-"]}`}
+	require.NoError(t, compareRowsWithMatchers(map[string]any{
+		"profile": []any{"json", map[string]any{"tags": []any{"json", "yaml"}, "codes": []any{"regexp", "^x-"}}},
+	}, actual))
+
+	err := compareRowsWithMatchers(map[string]any{
+		"profile": []any{"json", map[string]any{"tags": []any{"json", "yaml"}, "codes": []any{"regexp", "^x"}}},
+	}, actual)
+	require.ErrorIs(t, err, errJSONMismatch)
+
+	// 列の値としてマッチャー名で始まる配列は [literal, ...] で書く
+	tags := map[string]any{"tags": []any{"json", "yaml"}}
+	require.NoError(t, compareRowsWithMatchers(map[string]any{"tags": []any{"literal", []any{"json", "yaml"}}}, tags))
+	assert.Nil(t, evaluateMatcherDiff("tags", []any{"literal", []any{"json", "yaml"}}, tags["tags"]))
+
+	err = compareRowsWithMatchers(map[string]any{"tags": []any{"literal", []any{"json", "toml"}}}, tags)
+	require.ErrorIs(t, err, errValueMismatch)
+
+	diff := evaluateMatcherDiff("tags", []any{"literal", []any{"json", "toml"}}, tags["tags"])
+	require.NotNil(t, diff)
+	assert.Equal(t, "value mismatch", diff.Reason)
+
+	err = compareRowsWithMatchers(map[string]any{"tags": []any{"literal"}}, tags)
+	require.ErrorIs(t, err, errInvalidMatcherSyntax)
+
+	// [literal, ...] の中の [currentdate] は時刻を固定しない
+	anchored := anchorCurrentDate([]any{"literal", []any{"currentdate"}}, time.Now(), false).([]any)
+	assert.Equal(t, []any{"literal", []any{"currentdate"}}, anchored)
+}
+
 func TestParseFlexibleDurationRequiresSign(t *testing.T) {
 	_, err := parseFlexibleDuration("1m")
 	assert.Error(t, err)
@@ -579,17 +1546,17 @@ func TestParseFlexibleDurationRequiresSign(t *testing.T) {
 
 func TestEvaluateRelativeTimeMatcherDisplay(t *testing.T) {
 	anchor := time.Date(2025, 10, 6, 12, 0, 0, 0, time.UTC)
-	setCurrentDateAnchor(anchor)
-
-	defer clearCurrentDateAnchor()
+	anchored := func(arr ...any) []any {
+		return anchorCurrentDate(arr, anchor, false).([]any)
+	}
 
-	expected, tol, display, err := evaluateRelativeTimeMatcher([]any{"currentdate", "+10m"})
+	expected, tol, display, err := evaluateRelativeTimeMatcher(anchored("currentdate", "+10m"))
 	require.NoError(t, err)
 	assert.Equal(t, anchor.Add(10*time.Minute), expected)
 	assert.Equal(t, time.Minute, tol)
 	assert.Equal(t, "[currentdate,+10m]", display)
 
-	expected, tol, display, err = evaluateRelativeTimeMatcher([]any{"currentdate", "+1h", "+30s"})
+	expected, tol, display, err = evaluateRelativeTimeMatcher(anchored("currentdate", "+1h", "+30s"))
 	require.NoError(t, err)
 	assert.Equal(t, anchor.Add(time.Hour), expected)
 	assert.Equal(t, 30*time.Second, tol)
@@ -598,3 +1565,91 @@ func TestEvaluateRelativeTimeMatcherDisplay(t *testing.T) {
 	_, _, _, err = evaluateRelativeTimeMatcher([]any{"currentdate", "1h"})
 	assert.Error(t, err)
 }
+
+func TestTestRunner_RunTests_SharedSetupTimeout(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL)`)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`CREATE TABLE nums (n INTEGER);
+		INSERT INTO nums WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < 1000) SELECT x FROM c`)
+	require.NoError(t, err)
+
+	setup := &markdownparser.FixtureSetup{
+		Fixtures: []markdownparser.TableFixture{
+			{
+				TableName: "users",
+				Strategy:  markdownparser.ClearInsert,
+				Data:      []map[string]any{{"id": 1, "name": "Alice"}},
+			},
+		},
+	}
+
+	testCases := []*markdownparser.TestCase{
+		{
+			Name:  "Slow query",
+			Setup: setup,
+			// Scans 10^12 rows; only the test timeout stops it
+			PreparedSQL:    "SELECT COUNT(*) AS cnt FROM nums a, nums b, nums c, nums d",
+			ExpectedResult: []map[string]any{{"cnt": 0}},
+		},
+		{
+			Name:           "Count after timeout",
+			Setup:          setup,
+			PreparedSQL:    "SELECT COUNT(*) AS cnt FROM users",
+			ExpectedResult: []map[string]any{{"cnt": 1}},
+		},
+	}
+
+	options := &ExecutionOptions{
+		Mode:     FullTest,
+		Parallel: 1,
+		Timeout:  200 * time.Millisecond,
+	}
+
+	runner := NewTestRunner(db, "sqlite", options)
+	runner.SetTableInfo(map[string]*snapsql.TableInfo{
+		"users": {
+			Name: "users",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":   {Name: "id", IsPrimaryKey: true},
+				"name": {Name: "name"},
+			},
+		},
+	})
+
+	start := time.Now()
+	summary, err := runner.RunTests(t.Context(), testCases)
+	require.NoError(t, err)
+
+	assert.Less(t, time.Since(start), 10*time.Second, "the slow query should be interrupted by the timeout")
+	require.Len(t, summary.Results, 2)
+
+	for _, result := range summary.Results {
+		switch result.TestCase.Name {
+		case "Slow query":
+			assert.False(t, result.Success)
+			assert.Error(t, result.Error)
+		default:
+			assert.True(t, result.Success, "Test %s should succeed: %v", result.TestCase.Name, result.Error)
+		}
+	}
+}
+
+// sqlStateError mimics the SQLSTATE carrying errors of pgx and lib/pq
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "sqlstate " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+func TestIsSerializationFailure(t *testing.T) {
+	assert.True(t, isSerializationFailure(wrapDefinitionFailure(sqlStateError("40001"), "failed to execute query")))
+	assert.False(t, isSerializationFailure(wrapDefinitionFailure(sqlStateError("23505"), "failed to execute query")))
+	assert.False(t, isSerializationFailure(fmt.Errorf("plain error")))
+}
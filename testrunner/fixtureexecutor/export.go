@@ -0,0 +1,358 @@
+package fixtureexecutor
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/markdownparser"
+)
+
+var errUnexportableValue = errors.New("fixture value cannot be written as a SQL literal")
+
+// ExportFixturesSQL renders fixtures as SQL statements for the executor's dialect so that the
+// data used by tests can seed other databases. Special values and generators are resolved the
+// same way as when the fixtures are inserted; external files are read relative to the base dir.
+//
+// clear-insert fixtures become a DELETE (TRUNCATE on ClickHouse) followed by INSERTs, truncate
+// fixtures additionally reset the identity counters of the table, upsert fixtures become the
+// dialect's upsert statement and delete fixtures become DELETEs by primary key. The SQLite
+// counter reset expects sqlite_sequence, i.e. at least one AUTOINCREMENT table.
+func (e *Executor) ExportFixturesSQL(fixtures []markdownparser.TableFixture) (string, error) {
+	var b strings.Builder
+
+	for block, fixture := range fixtures {
+		if fixture.ExternalFile != "" && len(fixture.Data) == 0 {
+			rows, err := e.loadExternalRows(fixture.ExternalFile, fixture.TableName)
+			if err != nil {
+				return "", fmt.Errorf("failed to load fixture external file for table %s: %w", fixture.TableName, err)
+			}
+
+			fixture.Data = rows
+		}
+
+		data := expandFixtureCount(fixture.Data, fixture.Count)
+		if fixture.Autofill {
+			filled, err := e.autofillRows(fixture.TableName, data)
+			if err != nil {
+				return "", fmt.Errorf("failed to autofill fixture for table %s: %w", fixture.TableName, err)
+			}
+
+			data = filled
+		}
+
+		rows, err := normalizeFixtureRows(data, fixtureSeed(fixture.TableName, block, 0))
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve fixture for table %s: %w", fixture.TableName, err)
+		}
+
+		if err := e.encodeFixtureRows(fixture.TableName, rows); err != nil {
+			return "", fmt.Errorf("failed to resolve fixture for table %s: %w", fixture.TableName, err)
+		}
+
+		statements, err := e.exportTableFixture(fixture, rows)
+		if err != nil {
+			return "", fmt.Errorf("failed to export fixture for table %s: %w", fixture.TableName, err)
+		}
+
+		for _, stmt := range statements {
+			b.WriteString(stmt)
+			b.WriteString(";\n")
+		}
+	}
+
+	return b.String(), nil
+}
+
+func (e *Executor) exportTableFixture(fixture markdownparser.TableFixture, rows []map[string]any) ([]string, error) {
+	table := e.quoteIdentifier(fixture.TableName)
+
+	switch fixture.Strategy {
+	case markdownparser.ClearInsert, "":
+		clear := "DELETE FROM " + table
+		if e.dialect == snapsql.DialectClickHouse {
+			clear = "TRUNCATE TABLE " + table
+		}
+
+		inserts, err := e.exportInserts(fixture.TableName, rows, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return append([]string{clear}, inserts...), nil
+	case markdownparser.Truncate:
+		clear, err := e.truncateStatements(fixture.TableName)
+		if err != nil {
+			return nil, err
+		}
+
+		inserts, err := e.exportInserts(fixture.TableName, rows, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return append(clear, inserts...), nil
+	case markdownparser.Upsert:
+		pkCols, err := e.orderedPrimaryKeys(fixture.TableName)
+		if err != nil {
+			return nil, err
+		}
+
+		if e.dialect == snapsql.DialectClickHouse {
+			deletes, err := e.exportDeletes(fixture.TableName, rows, pkCols)
+			if err != nil {
+				return nil, err
+			}
+
+			inserts, err := e.exportInserts(fixture.TableName, rows, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			return append(deletes, inserts...), nil
+		}
+
+		return e.exportInserts(fixture.TableName, rows, pkCols)
+	case markdownparser.Delete:
+		pkCols, err := e.orderedPrimaryKeys(fixture.TableName)
+		if err != nil {
+			return nil, err
+		}
+
+		return e.exportDeletes(fixture.TableName, rows, pkCols)
+	default:
+		return nil, fmt.Errorf("%w: %s", snapsql.ErrUnsupportedInsertStrategy, fixture.Strategy)
+	}
+}
+
+// exportInserts renders one INSERT per row. When pkCols is set the statements update existing rows.
+func (e *Executor) exportInserts(tableName string, rows []map[string]any, pkCols []string) ([]string, error) {
+	tbl := e.tableInfo[tableName]
+
+	statements := make([]string, 0, len(rows))
+
+	for _, row := range rows {
+		if tbl != nil {
+			for k := range row {
+				if _, ok := tbl.Columns[k]; !ok {
+					return nil, fmt.Errorf("%w: %s", errUnknownFixtureColumn, k)
+				}
+			}
+		}
+
+		cols := e.exportColumns(tbl, row)
+
+		values := make([]string, len(cols))
+		for i, col := range cols {
+			literal, err := e.sqlLiteral(row[col])
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", col, err)
+			}
+
+			values[i] = literal
+		}
+
+		stmt := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", e.quoteIdentifier(tableName), joinQuoted(e, cols, ", "), strings.Join(values, ", "))
+
+		if len(pkCols) > 0 {
+			for _, pk := range pkCols {
+				if row[pk] == nil {
+					return nil, fmt.Errorf("%w: %s", errUpsertMissingPK, pk)
+				}
+			}
+
+			stmt += e.upsertClause(cols, pkCols)
+		}
+
+		statements = append(statements, stmt)
+	}
+
+	return statements, nil
+}
+
+func (e *Executor) upsertClause(cols, pkCols []string) string {
+	isPK := make(map[string]bool, len(pkCols))
+	for _, pk := range pkCols {
+		isPK[pk] = true
+	}
+
+	var sets []string
+
+	for _, col := range cols {
+		if isPK[col] {
+			continue
+		}
+
+		quoted := e.quoteIdentifier(col)
+		if e.dialect == snapsql.DialectMySQL || e.dialect == snapsql.DialectMariaDB {
+			sets = append(sets, fmt.Sprintf("%s = VALUES(%s)", quoted, quoted))
+		} else {
+			sets = append(sets, fmt.Sprintf("%s = EXCLUDED.%s", quoted, quoted))
+		}
+	}
+
+	if e.dialect == snapsql.DialectMySQL || e.dialect == snapsql.DialectMariaDB {
+		if len(sets) == 0 {
+			// Rows without other columns keep the existing row
+			pk := e.quoteIdentifier(pkCols[0])
+			sets = append(sets, fmt.Sprintf("%s = %s", pk, pk))
+		}
+
+		return " ON DUPLICATE KEY UPDATE " + strings.Join(sets, ", ")
+	}
+
+	if len(sets) == 0 {
+		return fmt.Sprintf(" ON CONFLICT (%s) DO NOTHING", joinQuoted(e, pkCols, ", "))
+	}
+
+	return fmt.Sprintf(" ON CONFLICT (%s) DO UPDATE SET %s", joinQuoted(e, pkCols, ", "), strings.Join(sets, ", "))
+}
+
+func (e *Executor) exportDeletes(tableName string, rows []map[string]any, pkCols []string) ([]string, error) {
+	statements := make([]string, 0, len(rows))
+
+	for _, row := range rows {
+		conditions := make([]string, len(pkCols))
+
+		for i, pk := range pkCols {
+			val, ok := row[pk]
+			if !ok {
+				return nil, fmt.Errorf("%w: %s (table %s)", errPrimaryKeyColumnMiss, pk, tableName)
+			}
+
+			literal, err := e.sqlLiteral(val)
+			if err != nil {
+				return nil, fmt.Errorf("column %s: %w", pk, err)
+			}
+
+			conditions[i] = fmt.Sprintf("%s = %s", e.quoteIdentifier(pk), literal)
+		}
+
+		statements = append(statements, fmt.Sprintf("DELETE FROM %s WHERE %s", e.quoteIdentifier(tableName), strings.Join(conditions, " AND ")))
+	}
+
+	return statements, nil
+}
+
+// exportColumns returns the row's columns in schema order, or sorted when the table is unknown
+func (e *Executor) exportColumns(tbl *snapsql.TableInfo, row map[string]any) []string {
+	cols := make([]string, 0, len(row))
+
+	if tbl != nil && len(tbl.ColumnOrder) > 0 {
+		for _, c := range tbl.ColumnOrder {
+			if _, ok := row[c]; ok {
+				cols = append(cols, c)
+			}
+		}
+
+		return cols
+	}
+
+	for c := range row {
+		cols = append(cols, c)
+	}
+
+	sort.Strings(cols)
+
+	return cols
+}
+
+// orderedPrimaryKeys returns the primary key columns in schema order so the output is stable
+func (e *Executor) orderedPrimaryKeys(tableName string) ([]string, error) {
+	pkCols, err := e.getPrimaryKeyColumns(tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	position := make(map[string]int)
+	for i, c := range e.tableInfo[tableName].ColumnOrder {
+		position[c] = i
+	}
+
+	sort.Slice(pkCols, func(i, j int) bool {
+		pi, iok := position[pkCols[i]]
+		pj, jok := position[pkCols[j]]
+
+		if iok && jok {
+			return pi < pj
+		}
+
+		if iok != jok {
+			return iok
+		}
+
+		return pkCols[i] < pkCols[j]
+	})
+
+	return pkCols, nil
+}
+
+// sqlLiteral renders a resolved fixture value as a literal of the executor's dialect
+func (e *Executor) sqlLiteral(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "NULL", nil
+	case bool:
+		if e.dialect == snapsql.DialectSQLite {
+			if v {
+				return "1", nil
+			}
+
+			return "0", nil
+		}
+
+		if v {
+			return "TRUE", nil
+		}
+
+		return "FALSE", nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", v), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case *big.Int:
+		return v.String(), nil
+	case json.Number:
+		return v.String(), nil
+	case string:
+		return e.quoteString(v), nil
+	case time.Time:
+		return e.quoteString(v.Format("2006-01-02 15:04:05.999999999")), nil
+	case []byte:
+		if e.dialect == snapsql.DialectPostgres || e.dialect == snapsql.DialectCockroach {
+			return `'\x` + hex.EncodeToString(v) + `'`, nil
+		}
+
+		return "X'" + hex.EncodeToString(v) + "'", nil
+	case map[string]any, []any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("%w: %w", errUnexportableValue, err)
+		}
+
+		return e.quoteString(string(data)), nil
+	default:
+		return "", fmt.Errorf("%w: %T", errUnexportableValue, value)
+	}
+}
+
+func (e *Executor) quoteString(s string) string {
+	s = strings.ReplaceAll(s, "'", "''")
+	if e.dialect == snapsql.DialectMySQL || e.dialect == snapsql.DialectMariaDB {
+		// MySQL treats backslashes in string literals as escapes by default
+		s = strings.ReplaceAll(s, `\`, `\\`)
+	}
+
+	return "'" + s + "'"
+}
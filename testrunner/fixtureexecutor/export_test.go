@@ -0,0 +1,134 @@
+package fixtureexecutor
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/markdownparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportTableInfo() map[string]*snapsql.TableInfo {
+	return map[string]*snapsql.TableInfo{
+		"users": {
+			Name: "users",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":     {Name: "id", DataType: "int", IsPrimaryKey: true},
+				"name":   {Name: "name", DataType: "string"},
+				"active": {Name: "active", DataType: "bool"},
+			},
+			ColumnOrder: []string{"id", "name", "active"},
+		},
+	}
+}
+
+func TestExecutor_ExportFixturesSQL(t *testing.T) {
+	fixtures := []markdownparser.TableFixture{
+		{TableName: "users", Strategy: markdownparser.ClearInsert, Data: []map[string]any{
+			{"name": "O'Brien", "id": int64(1), "active": true},
+			{"id": int64(2), "name": "[null]"},
+		}},
+		{TableName: "users", Strategy: markdownparser.Upsert, Data: []map[string]any{{"id": int64(1), "name": "Alice"}}},
+		{TableName: "users", Strategy: markdownparser.Delete, Data: []map[string]any{{"id": int64(2)}}},
+	}
+
+	tests := []struct {
+		dialect snapsql.Dialect
+		want    string
+	}{
+		{
+			dialect: snapsql.DialectPostgres,
+			want: `DELETE FROM "users";
+INSERT INTO "users" ("id", "name", "active") VALUES (1, 'O''Brien', TRUE);
+INSERT INTO "users" ("id", "name") VALUES (2, NULL);
+INSERT INTO "users" ("id", "name") VALUES (1, 'Alice') ON CONFLICT ("id") DO UPDATE SET "name" = EXCLUDED."name";
+DELETE FROM "users" WHERE "id" = 2;
+`,
+		},
+		{
+			dialect: snapsql.DialectMySQL,
+			want: "DELETE FROM `users`;\n" +
+				"INSERT INTO `users` (`id`, `name`, `active`) VALUES (1, 'O''Brien', TRUE);\n" +
+				"INSERT INTO `users` (`id`, `name`) VALUES (2, NULL);\n" +
+				"INSERT INTO `users` (`id`, `name`) VALUES (1, 'Alice') ON DUPLICATE KEY UPDATE `name` = VALUES(`name`);\n" +
+				"DELETE FROM `users` WHERE `id` = 2;\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.dialect), func(t *testing.T) {
+			executor := NewExecutor(nil, tt.dialect, exportTableInfo())
+
+			got, err := executor.ExportFixturesSQL(fixtures)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExecutor_ExportFixturesSQL_RunsOnSQLite(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "users.yaml"), []byte("- id: 1\n  name: Alice\n  active: true\n- id: 2\n  name: Bob\n  active: false\n"), 0o644))
+
+	executor := NewExecutor(nil, snapsql.DialectSQLite, exportTableInfo())
+	executor.SetBaseDir(dir)
+
+	script, err := executor.ExportFixturesSQL([]markdownparser.TableFixture{
+		{TableName: "users", Strategy: markdownparser.ClearInsert, ExternalFile: "users.yaml"},
+		{TableName: "users", Strategy: markdownparser.Upsert, Data: []map[string]any{{"id": int64(2), "name": "Bobby", "active": true}}},
+	})
+	require.NoError(t, err)
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	_, err = db.ExecContext(t.Context(), `CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, active BOOLEAN)`)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(t.Context(), script)
+	require.NoError(t, err)
+
+	var (
+		name   string
+		active bool
+	)
+
+	require.NoError(t, db.QueryRowContext(t.Context(), `SELECT name, active FROM users WHERE id = 2`).Scan(&name, &active))
+	assert.Equal(t, "Bobby", name)
+	assert.True(t, active)
+}
+
+func TestExecutor_ExportFixturesSQL_UpsertRequiresPrimaryKey(t *testing.T) {
+	executor := NewExecutor(nil, snapsql.DialectPostgres, nil)
+
+	_, err := executor.ExportFixturesSQL([]markdownparser.TableFixture{
+		{TableName: "logs", Strategy: markdownparser.Upsert, Data: []map[string]any{{"id": int64(1)}}},
+	})
+	assert.ErrorIs(t, err, errTableInfoNotFound)
+}
+
+func TestExecutor_ExportFixturesSQL_Truncate(t *testing.T) {
+	fixtures := []markdownparser.TableFixture{
+		{TableName: "users", Strategy: markdownparser.Truncate, Data: []map[string]any{{"id": int64(1), "name": "Alice"}}},
+	}
+
+	got, err := NewExecutor(nil, snapsql.DialectPostgres, exportTableInfo()).ExportFixturesSQL(fixtures)
+	require.NoError(t, err)
+	assert.Equal(t, `TRUNCATE TABLE "users" RESTART IDENTITY;
+INSERT INTO "users" ("id", "name") VALUES (1, 'Alice');
+`, got)
+
+	got, err = NewExecutor(nil, snapsql.DialectSQLite, exportTableInfo()).ExportFixturesSQL(fixtures)
+	require.NoError(t, err)
+	assert.Contains(t, got, `DELETE FROM sqlite_sequence WHERE name = 'users';`)
+
+	_, err = NewExecutor(nil, snapsql.DialectMySQL, exportTableInfo()).ExportFixturesSQL(fixtures)
+	assert.ErrorIs(t, err, errTruncateNotSupported)
+}
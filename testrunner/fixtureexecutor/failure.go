@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"maps"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/fatih/color"
@@ -22,27 +23,27 @@ const (
 	FailureKindDefinition
 )
 
+// Diff colors: expected values are red "-" lines and actual values green "+" lines
 var (
 	tableHeaderFmt     = color.New(color.FgBlue, color.Bold).SprintfFunc()
-	legendExpectedFmt  = color.New(color.FgGreen).SprintFunc()
-	legendActualFmt    = color.New(color.FgRed).SprintFunc()
-	primaryKeyNameFmt  = color.New(color.FgBlue, color.Bold, color.Underline).SprintfFunc()
-	primaryKeyValueFmt = color.New(color.FgBlue, color.Underline).SprintfFunc()
-	rowLabelFmt        = color.New(color.FgBlue, color.Bold).SprintfFunc()
-	expectPrefixFmt    = color.New(color.BgGreen, color.FgBlack).SprintFunc()
-	actualPrefixFmt    = color.New(color.BgRed, color.FgBlack).SprintFunc()
-	expectFieldFmt     = color.New(color.FgGreen).SprintfFunc()
-	actualFieldFmt     = color.New(color.FgRed).SprintfFunc()
-	expectValueFmt     = color.New(color.BgGreen, color.FgBlack).SprintfFunc()
-	actualValueFmt     = color.New(color.BgRed, color.FgBlack).SprintfFunc()
-	expectSeparatorFmt = color.New(color.FgGreen).SprintFunc()
-	actualSeparatorFmt = color.New(color.FgRed).SprintFunc()
+	legendExpectedFmt  = color.New(color.FgRed, color.Bold).SprintFunc()
+	legendActualFmt    = color.New(color.FgGreen, color.Bold).SprintFunc()
+	hunkHeaderFmt      = color.New(color.FgCyan).SprintfFunc()
+	primaryKeyNameFmt  = color.New(color.FgBlue, color.Bold).SprintfFunc()
+	primaryKeyValueFmt = color.New(color.FgBlue).SprintfFunc()
+	expectFieldFmt     = color.New(color.FgRed).SprintfFunc()
+	actualFieldFmt     = color.New(color.FgGreen).SprintfFunc()
+	expectValueFmt     = color.New(color.BgRed, color.FgBlack).SprintfFunc()
+	actualValueFmt     = color.New(color.BgGreen, color.FgBlack).SprintfFunc()
+	diffNoteFmt        = color.New(color.Faint).SprintfFunc()
 )
 
 // Sentinel errors for wrapping (err113 compliant)
 var (
 	ErrUnknownFixtureFailure = errors.New("unknown fixture failure")
 	ErrFixtureFailureMessage = errors.New("fixture failure")
+	ErrExplainRuleViolation  = errors.New("explain rule violated")
+	ErrMaxDurationExceeded   = errors.New("max duration exceeded")
 )
 
 // FixtureError is an error wrapper that retains the failure classification and optional context.
@@ -259,7 +260,10 @@ func copyContext(ctx map[string]string) map[string]string {
 	return out
 }
 
-// FormatDiffUnifiedYAML renders a DiffError as a compact textual report ready for CLI output.
+// FormatDiffUnifiedYAML renders a DiffError as a unified diff ready for CLI output. Rows are
+// grouped by primary key in "@@ ... @@" hunks and only the mismatched columns are listed, with
+// the expected value on "-" lines and the actual value on "+" lines (like go-cmp's -want +got).
+// Colors follow github.com/fatih/color, so they are dropped for NO_COLOR and non-terminals.
 func FormatDiffUnifiedYAML(diff *DiffError) string {
 	if diff == nil {
 		return ""
@@ -272,33 +276,38 @@ func FormatDiffUnifiedYAML(diff *DiffError) string {
 	var b strings.Builder
 
 	if diff.Table != "" {
-		b.WriteString(tableHeaderFmt("Table: %s\n", diff.Table))
+		b.WriteString(tableHeaderFmt("Table: %s", diff.Table))
+		b.WriteString("\n")
 	}
 
-	b.WriteString(legendExpectedFmt("- Expected\n"))
-	b.WriteString(legendActualFmt("+ Actual\n"))
+	b.WriteString(legendExpectedFmt("--- Expected"))
+	b.WriteString("\n")
+	b.WriteString(legendActualFmt("+++ Actual"))
+	b.WriteString("\n")
 
 	if diff.RowCountMismatch {
-		b.WriteString(expectFieldFmt("+ rows: %d\n", diff.ExpectedRows))
-		b.WriteString(actualFieldFmt("- rows: %d\n", diff.ActualRows))
+		b.WriteString(hunkHeaderFmt("@@ rows @@"))
+		b.WriteString("\n")
+		writeDiffLine(&b, true, "rows", strconv.Itoa(diff.ExpectedRows), "")
+		writeDiffLine(&b, false, "rows", strconv.Itoa(diff.ActualRows), "")
 	}
 
 	rowDiffs := make([]RowDiff, len(diff.RowDiffs))
 	copy(rowDiffs, diff.RowDiffs)
-	sort.Slice(rowDiffs, func(i, j int) bool {
+	sort.SliceStable(rowDiffs, func(i, j int) bool {
 		return formatKey(rowDiffs[i].Key) < formatKey(rowDiffs[j].Key)
 	})
 
 	for idx, row := range rowDiffs {
-		writePrimaryKeyLine(&b, row, idx)
+		writeHunkHeader(&b, row, idx)
 		writeRowDifferences(&b, row)
-		b.WriteString("\n")
 	}
 
 	return strings.TrimRight(b.String(), "\n")
 }
 
-func writePrimaryKeyLine(b *strings.Builder, row RowDiff, index int) {
+// writeHunkHeader writes the "@@ id: 10 [mismatch] @@" line that opens the hunk of a row
+func writeHunkHeader(b *strings.Builder, row RowDiff, index int) {
 	keys := make([]string, 0, len(row.Key))
 	for k := range row.Key {
 		if k == "row_index" {
@@ -308,24 +317,20 @@ func writePrimaryKeyLine(b *strings.Builder, row RowDiff, index int) {
 		keys = append(keys, k)
 	}
 
-	if len(keys) == 0 {
-		b.WriteString(rowLabelFmt("row #%d%s\n", index+1, rowStatusSuffix(row)))
-		return
-	}
+	label := fmt.Sprintf("row #%d", index+1)
 
-	sort.Strings(keys)
+	if len(keys) > 0 {
+		sort.Strings(keys)
 
-	for i, key := range keys {
-		if i > 0 {
-			b.WriteString(primaryKeyValueFmt(", "))
+		parts := make([]string, len(keys))
+		for i, key := range keys {
+			parts[i] = primaryKeyNameFmt("%s", key) + primaryKeyValueFmt(": %s", formatDiffScalar(formatValueForDiff(row.Key[key])))
 		}
 
-		b.WriteString(primaryKeyNameFmt("%s", key))
-		val := formatDiffScalar(formatValueForDiff(row.Key[key]))
-		b.WriteString(primaryKeyValueFmt(": %s", val))
+		label = strings.Join(parts, primaryKeyValueFmt(", "))
 	}
 
-	b.WriteString(rowStatusSuffix(row))
+	b.WriteString(hunkHeaderFmt("@@ ") + label + hunkHeaderFmt("%s @@", rowStatusSuffix(row)))
 	b.WriteString("\n")
 }
 
@@ -336,28 +341,24 @@ func writeRowDifferences(b *strings.Builder, row RowDiff) {
 		return columns[i].Column < columns[j].Column
 	})
 
-	expectedFields := buildFieldList(columns, true)
-	actualFields := buildFieldList(columns, false)
-
-	includeExpected := row.RowStatus != "unexpected" && len(expectedFields) > 0
-	includeActual := row.RowStatus != "missing" && len(actualFields) > 0
-
-	if row.RowStatus == "unexpected" {
-		expectedFields = []string{expectFieldFmt("(no expected row)")}
-		includeExpected = true
-	}
+	for _, col := range columns {
+		label := col.Column
+		if label == "__row__" {
+			label = ""
+		}
 
-	if row.RowStatus == "missing" {
-		actualFields = []string{actualFieldFmt("(no actual row)")}
-		includeActual = true
-	}
+		note := ""
+		if col.Reason != "" && col.Reason != "value mismatch" {
+			note = col.Reason
+		}
 
-	if includeExpected {
-		writeFieldLine(b, expectPrefixFmt, "+", expectSeparatorFmt, expectedFields)
-	}
+		if row.RowStatus != "unexpected" {
+			writeDiffLine(b, true, label, formatDiffScalar(formatValueForDiff(col.Expected)), "")
+		}
 
-	if includeActual {
-		writeFieldLine(b, actualPrefixFmt, "-", actualSeparatorFmt, actualFields)
+		if row.RowStatus != "missing" {
+			writeDiffLine(b, false, label, formatDiffScalar(formatValueForDiff(col.Actual)), note)
+		}
 	}
 }
 
@@ -378,48 +379,24 @@ func rowStatusSuffix(row RowDiff) string {
 	return status
 }
 
-func buildFieldList(columns []ColumnDiff, expected bool) []string {
-	fields := make([]string, 0, len(columns))
-	for _, col := range columns {
-		label := col.Column
-		if label == "__row__" {
-			label = "row"
-		}
-
-		var value any
-		if expected {
-			value = col.Expected
-		} else {
-			value = col.Actual
-		}
-
-		value = formatValueForDiff(value)
-
-		valStr := formatDiffScalar(value)
-		if expected {
-			fields = append(fields, expectFieldFmt("%s: %s", label, expectValueFmt("%s", valStr)))
-		} else {
-			fields = append(fields, actualFieldFmt("%s: %s", label, actualValueFmt("%s", valStr)))
-		}
+// writeDiffLine writes one "-" (expected) or "+" (actual) line. The value is highlighted so that
+// it stands out from the column name; note explains a mismatch that is not a plain inequality.
+func writeDiffLine(b *strings.Builder, expected bool, label, value, note string) {
+	sign, lineFmt, valueFmt := "+", actualFieldFmt, actualValueFmt
+	if expected {
+		sign, lineFmt, valueFmt = "-", expectFieldFmt, expectValueFmt
 	}
 
-	return fields
-}
+	b.WriteString(lineFmt("%s ", sign))
 
-func writeFieldLine(b *strings.Builder, prefix func(...any) string, sign string, sep func(...any) string, fields []string) {
-	if len(fields) == 0 {
-		return
+	if label != "" {
+		b.WriteString(lineFmt("%s: ", label))
 	}
 
-	b.WriteString(prefix(sign))
-	b.WriteString(" ")
-
-	for i, field := range fields {
-		if i > 0 {
-			b.WriteString(sep(", "))
-		}
+	b.WriteString(valueFmt("%s", value))
 
-		b.WriteString(field)
+	if note != "" {
+		b.WriteString(diffNoteFmt("  # %s", note))
 	}
 
 	b.WriteString("\n")
@@ -0,0 +1,170 @@
+package fixtureexecutor
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var (
+	errUnknownGenerator      = errors.New("unknown fixture generator")
+	errInvalidGeneratorParam = errors.New("invalid fixture generator parameter")
+)
+
+// generatorSeed keeps generated fixture data identical between runs
+const generatorSeed = 20250625
+
+var (
+	fakerFirstNames = []string{"Alice", "Bob", "Carol", "Dave", "Eve", "Frank", "Grace", "Heidi", "Ivan", "Judy", "Mallory", "Olivia", "Peggy", "Rupert", "Sybil", "Trent", "Victor", "Walter"}
+	fakerLastNames  = []string{"Smith", "Johnson", "Williams", "Brown", "Jones", "Miller", "Davis", "Wilson", "Taylor", "Clark", "Lewis", "Walker", "Young", "King", "Wright", "Scott"}
+	fakerCities     = []string{"Tokyo", "Osaka", "Nagoya", "Sapporo", "Fukuoka", "London", "Paris", "Berlin", "New York", "Seattle", "Sydney", "Toronto"}
+	fakerCompanies  = []string{"Acme", "Globex", "Initech", "Umbrella", "Hooli", "Stark Industries", "Wayne Enterprises", "Soylent", "Cyberdyne", "Tyrell"}
+	fakerWords      = []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot", "golf", "hotel", "india", "juliet", "kilo", "lima", "mike", "november", "oscar", "papa"}
+)
+
+// valueGenerator resolves generator tokens such as [seq], [uuid] and [faker,email] in fixture rows.
+// One generator is used per fixture so that sequences continue across rows.
+type valueGenerator struct {
+	seed int64
+	row  int
+	rng  *rand.Rand
+}
+
+func newValueGenerator(seed int64) *valueGenerator {
+	g := &valueGenerator{seed: seed}
+	g.moveTo(0, "")
+	return g
+}
+
+// fixtureSeed derives the generator seed of a fixture block from its table, its position in the
+// fixture list and the number of fixture lists inserted before it. Blocks for the same table, or
+// the same block inserted again by a later test case, get different values while a run with the
+// same execution order stays reproducible.
+func fixtureSeed(tableName string, block int, run uint64) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(tableName))
+	_, _ = fmt.Fprintf(h, "\x00%d\x00%d", block, run)
+	return generatorSeed ^ int64(h.Sum64())
+}
+
+// moveTo positions the generator at a cell. The random source is derived from the fixture seed,
+// the row and the column so that a cell gets the same value regardless of map iteration order.
+func (g *valueGenerator) moveTo(row int, column string) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(column))
+	_, _ = fmt.Fprintf(h, "\x00%d", row)
+	g.row = row
+	g.rng = rand.New(rand.NewSource(g.seed ^ int64(h.Sum64()))) //nolint:gosec // deterministic test data
+}
+
+// isGeneratorName reports whether name (lower-cased) is a fixture generator token
+func isGeneratorName(name string) bool {
+	switch name {
+	case "seq", "uuid", "faker":
+		return true
+	default:
+		return false
+	}
+}
+
+func (g *valueGenerator) generate(name string, args []any) (any, error) {
+	switch name {
+	case "seq":
+		start := int64(1)
+		if len(args) > 0 {
+			n, err := generatorIntArg(args[0])
+			if err != nil {
+				return nil, fmt.Errorf("%w: seq start %v", errInvalidGeneratorParam, args[0])
+			}
+			start = n
+		}
+		return start + int64(g.row), nil
+	case "uuid":
+		id, err := uuid.NewRandomFromReader(g.rng)
+		if err != nil {
+			return nil, err
+		}
+		return id.String(), nil
+	case "faker":
+		if len(args) == 0 {
+			return nil, fmt.Errorf("%w: faker requires a kind such as [faker, email]", errInvalidGeneratorParam)
+		}
+		kind, _ := args[0].(string)
+		return g.fake(strings.ToLower(strings.TrimSpace(kind)))
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnknownGenerator, name)
+	}
+}
+
+// fake returns a plausible value for kind. Values that are usually unique (email, username)
+// include the row number so that generated rows do not collide on unique constraints.
+func (g *valueGenerator) fake(kind string) (any, error) {
+	switch kind {
+	case "first_name", "firstname":
+		return g.pick(fakerFirstNames), nil
+	case "last_name", "lastname":
+		return g.pick(fakerLastNames), nil
+	case "name":
+		return g.pick(fakerFirstNames) + " " + g.pick(fakerLastNames), nil
+	case "email":
+		return fmt.Sprintf("%s.%s%d@example.com", strings.ToLower(g.pick(fakerFirstNames)), strings.ToLower(g.pick(fakerLastNames)), g.row+1), nil
+	case "username", "user_name":
+		return fmt.Sprintf("%s%d", strings.ToLower(g.pick(fakerFirstNames)), g.row+1), nil
+	case "phone":
+		return fmt.Sprintf("090-%04d-%04d", g.rng.Intn(10000), g.rng.Intn(10000)), nil
+	case "city":
+		return g.pick(fakerCities), nil
+	case "company":
+		return g.pick(fakerCompanies), nil
+	case "word":
+		return g.pick(fakerWords), nil
+	case "sentence":
+		words := make([]string, 4+g.rng.Intn(5))
+		for i := range words {
+			words[i] = g.pick(fakerWords)
+		}
+		sentence := strings.Join(words, " ")
+		return strings.ToUpper(sentence[:1]) + sentence[1:] + ".", nil
+	default:
+		return nil, fmt.Errorf("%w: faker kind %q", errUnknownGenerator, kind)
+	}
+}
+
+func (g *valueGenerator) pick(values []string) string {
+	return values[g.rng.Intn(len(values))]
+}
+
+func generatorIntArg(v any) (int64, error) {
+	if s, ok := v.(string); ok {
+		return strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	}
+	f, ok := toFloat(v)
+	if !ok || f != float64(int64(f)) {
+		return 0, errInvalidGeneratorParam
+	}
+	return int64(f), nil
+}
+
+// expandFixtureCount replicates the template rows count times. Generator tokens are kept
+// as-is so that each replicated row receives its own generated values on insert.
+func expandFixtureCount(rows []map[string]any, count int) []map[string]any {
+	if count <= 1 || len(rows) == 0 {
+		return rows
+	}
+	expanded := make([]map[string]any, 0, len(rows)*count)
+	for range count {
+		for _, row := range rows {
+			clone := make(map[string]any, len(row))
+			for k, v := range row {
+				clone[k] = v
+			}
+			expanded = append(expanded, clone)
+		}
+	}
+	return expanded
+}
@@ -0,0 +1,123 @@
+package fixtureexecutor
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+	"github.com/shibukawa/snapsql/markdownparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// autocommitDriver wraps SQLite so that it behaves like ClickHouse for the test runner: BEGIN and
+// ROLLBACK are accepted but every statement is applied immediately. The ClickHouse-only SQL the
+// executor emits is rewritten to SQLite.
+type autocommitDriver struct{ base sqlite3.SQLiteDriver }
+
+type autocommitConn struct{ driver.Conn }
+
+type autocommitTx struct{}
+
+var (
+	registerAutocommitDriver sync.Once
+	clickhouseOnlySQL        = regexp.MustCompile(`^TRUNCATE TABLE | SETTINGS async_insert = 1, wait_for_async_insert = 1`)
+)
+
+func (d *autocommitDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.base.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return autocommitConn{conn}, nil
+}
+
+func (c autocommitConn) Prepare(query string) (driver.Stmt, error) {
+	query = clickhouseOnlySQL.ReplaceAllStringFunc(query, func(match string) string {
+		if match == "TRUNCATE TABLE " {
+			return "DELETE FROM "
+		}
+
+		return ""
+	})
+
+	return c.Conn.Prepare(query)
+}
+
+func (c autocommitConn) Begin() (driver.Tx, error) { return autocommitTx{}, nil }
+
+func (autocommitTx) Commit() error   { return nil }
+func (autocommitTx) Rollback() error { return nil }
+
+func TestTestRunner_NonTransactionalIsolation(t *testing.T) {
+	registerAutocommitDriver.Do(func() { sql.Register("sqlite3-autocommit", &autocommitDriver{}) })
+
+	db, err := sql.Open("sqlite3-autocommit", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	db.SetMaxOpenConns(1)
+
+	_, err = db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL)")
+	require.NoError(t, err)
+
+	// The first test case inserts a row that survives its rollback
+	writer := &markdownparser.TestCase{
+		Name: "writes",
+		Fixtures: []markdownparser.TableFixture{
+			{TableName: "users", Strategy: markdownparser.ClearInsert, Data: []map[string]any{{"id": 2, "name": "fixture"}}},
+		},
+		PreparedSQL: "INSERT INTO users (id, name) VALUES (1, 'written') RETURNING id",
+		ExpectedResult: []map[string]any{
+			{"id": 1},
+		},
+	}
+
+	// The second test case has no fixtures; the table is found through the template's table references
+	reader := &markdownparser.TestCase{
+		Name:           "reads",
+		PreparedSQL:    "SELECT COUNT(*) AS n FROM users",
+		ExpectedResult: []map[string]any{{"n": 0}},
+	}
+
+	runner := NewTestRunner(db, snapsql.DialectClickHouse, &ExecutionOptions{Mode: FullTest, Parallel: 1, Timeout: time.Minute})
+	runner.SetTableReferences(map[*markdownparser.TestCase]map[string]intermediate.TableReferenceInfo{
+		reader: {"u": {Name: "users", TableName: "users", Alias: "u"}},
+	})
+
+	summary, err := runner.RunTests(context.Background(), []*markdownparser.TestCase{writer, reader})
+	require.NoError(t, err)
+
+	for _, result := range summary.Results {
+		assert.True(t, result.Success, "%s: %v", result.TestCase.Name, result.Error)
+	}
+
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM users").Scan(&count))
+	assert.Zero(t, count, "the rows of the first test case were removed before the second one ran")
+}
+
+func TestTouchedTables(t *testing.T) {
+	testCase := &markdownparser.TestCase{
+		Setup:           &markdownparser.FixtureSetup{Fixtures: []markdownparser.TableFixture{{TableName: "accounts"}}},
+		Fixtures:        []markdownparser.TableFixture{{TableName: "users"}},
+		ExpectedResults: []markdownparser.ExpectedResultSpec{{TableName: "audit_logs"}, {}},
+		Steps:           []markdownparser.TestStep{{ExpectedResults: []markdownparser.ExpectedResultSpec{{TableName: "orders"}}}},
+	}
+
+	refs := map[string]intermediate.TableReferenceInfo{
+		"u":      {Name: "users", TableName: "users", Alias: "u"},
+		"recent": {Name: "recent", Context: "cte"},
+	}
+
+	assert.Equal(t, []string{"accounts", "audit_logs", "orders", "users"}, touchedTables(testCase, refs))
+}
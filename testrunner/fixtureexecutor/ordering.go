@@ -0,0 +1,192 @@
+package fixtureexecutor
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/shibukawa/snapsql/markdownparser"
+)
+
+// compareSpecRows compares expected and actual rows honoring the ordering options of spec.
+// order_by sorts both sides by the listed columns before an ordered comparison; ordered
+// (defaulting to defaultOrdered) selects between an ordered and an order-insensitive comparison.
+// Rows are matched by primary key when pkCols is given and the comparison is not ordered.
+func compareSpecRows(spec markdownparser.ExpectedResultSpec, expected, actual []map[string]any, table string, pkCols []string, defaultOrdered bool) error {
+	expected = projectColumns(spec, expected, pkCols)
+	actual = projectColumns(spec, actual, pkCols)
+
+	if len(spec.OrderBy) > 0 {
+		// The sort columns identify the rows in the diff when there is no primary key
+		keys := pkCols
+		if len(keys) == 0 {
+			keys = spec.OrderBy
+		}
+
+		return compareRowsSliceOrdered(sortRowsBy(expected, spec.OrderBy), sortRowsBy(actual, spec.OrderBy), table, keys, true)
+	}
+
+	ordered := defaultOrdered
+	if spec.Ordered != nil {
+		ordered = *spec.Ordered
+	}
+
+	if !ordered && len(pkCols) == 0 {
+		expected, actual = alignUnorderedRows(expected, actual)
+
+		return compareRowsSliceOrdered(expected, actual, table, nil, true)
+	}
+
+	return compareRowsSlice(expected, actual, table, pkCols, ordered, true)
+}
+
+// projectColumns drops the columns excluded by ignore_columns and, with only_columns, keeps
+// the listed columns alone. Primary key columns and the change kind of delta rows are kept so
+// that rows can still be matched. rows is not modified.
+func projectColumns(spec markdownparser.ExpectedResultSpec, rows []map[string]any, pkCols []string) []map[string]any {
+	if len(spec.IgnoreColumns) == 0 && len(spec.OnlyColumns) == 0 {
+		return rows
+	}
+
+	keep := func(column string) bool {
+		if column == markdownparser.DeltaChangeKey || slices.Contains(pkCols, column) {
+			return true
+		}
+
+		if len(spec.OnlyColumns) > 0 && !slices.Contains(spec.OnlyColumns, column) {
+			return false
+		}
+
+		return !slices.Contains(spec.IgnoreColumns, column)
+	}
+
+	projected := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		out := make(map[string]any, len(row))
+		for column, value := range row {
+			if keep(column) {
+				out[column] = value
+			}
+		}
+		projected[i] = out
+	}
+
+	return projected
+}
+
+// hasOrderingOptions reports whether spec declares ordered or order_by
+func hasOrderingOptions(spec markdownparser.ExpectedResultSpec) bool {
+	return spec.Ordered != nil || len(spec.OrderBy) > 0
+}
+
+// unnamedSpec returns the expected results section without a table name
+func unnamedSpec(specs []markdownparser.ExpectedResultSpec) (markdownparser.ExpectedResultSpec, bool) {
+	for _, spec := range specs {
+		if spec.TableName == "" {
+			return spec, true
+		}
+	}
+
+	return markdownparser.ExpectedResultSpec{}, false
+}
+
+// alignUnorderedRows reorders both sides for an order-insensitive comparison: each expected
+// row is paired with the first unused actual row it matches, and the rows without a match follow
+// in their original order so that the diff shows them side by side.
+func alignUnorderedRows(expected, actual []map[string]any) ([]map[string]any, []map[string]any) {
+	used := make([]bool, len(actual))
+	alignedExpected := make([]map[string]any, 0, len(expected))
+	alignedActual := make([]map[string]any, 0, len(actual))
+
+	var unmatched []map[string]any
+
+	for _, expRow := range expected {
+		found := -1
+
+		for j, actRow := range actual {
+			if !used[j] && len(collectRowDiffs(expRow, actRow, true)) == 0 {
+				found = j
+				break
+			}
+		}
+
+		if found < 0 {
+			unmatched = append(unmatched, expRow)
+			continue
+		}
+
+		used[found] = true
+		alignedExpected = append(alignedExpected, expRow)
+		alignedActual = append(alignedActual, actual[found])
+	}
+
+	alignedExpected = append(alignedExpected, unmatched...)
+
+	for j, row := range actual {
+		if !used[j] {
+			alignedActual = append(alignedActual, row)
+		}
+	}
+
+	return alignedExpected, alignedActual
+}
+
+// sortRowsBy returns a copy of rows stably sorted by the columns
+func sortRowsBy(rows []map[string]any, columns []string) []map[string]any {
+	sorted := slices.Clone(rows)
+	slices.SortStableFunc(sorted, func(a, b map[string]any) int {
+		for _, column := range columns {
+			if c := compareSortValues(a[column], b[column]); c != 0 {
+				return c
+			}
+		}
+
+		return 0
+	})
+
+	return sorted
+}
+
+// compareSortValues orders values of a column: NULL first, then numbers, times and the rest as text
+func compareSortValues(a, b any) int {
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		default:
+			return 1
+		}
+	}
+
+	if fa, ok := toNumber(a); ok {
+		if fb, ok := toNumber(b); ok {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			default:
+				return 0
+			}
+		}
+	}
+
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			return ta.Compare(tb)
+		}
+	}
+
+	return strings.Compare(sortText(a), sortText(b))
+}
+
+func sortText(v any) string {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,127 @@
+package fixtureexecutor
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/markdownparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareSpecRows(t *testing.T) {
+	unordered := false
+	ordered := true
+
+	expected := []map[string]any{{"name": "alice", "age": 30}, {"name": "bob", "age": 25}}
+	actual := []map[string]any{{"name": "bob", "age": int64(25)}, {"name": "alice", "age": int64(30)}}
+
+	t.Run("default is ordered", func(t *testing.T) {
+		assert.Error(t, compareSpecRows(markdownparser.ExpectedResultSpec{}, expected, actual, "", nil, true))
+	})
+
+	t.Run("ordered false", func(t *testing.T) {
+		spec := markdownparser.ExpectedResultSpec{Ordered: &unordered}
+		assert.NoError(t, compareSpecRows(spec, expected, actual, "", nil, true))
+
+		// Unmatched rows are compared with each other
+		mismatch := []map[string]any{{"name": "bob", "age": int64(26)}, {"name": "alice", "age": int64(30)}}
+		err := compareSpecRows(spec, expected, mismatch, "", nil, true)
+
+		diff, ok := AsDiffError(err)
+		require.True(t, ok, "got %v", err)
+		require.Len(t, diff.RowDiffs, 1)
+		assert.Equal(t, "age", diff.RowDiffs[0].Diffs[0].Column)
+	})
+
+	t.Run("ordered true overrides the default", func(t *testing.T) {
+		spec := markdownparser.ExpectedResultSpec{Ordered: &ordered}
+		assert.Error(t, compareSpecRows(spec, expected, actual, "users", []string{"name"}, false))
+	})
+
+	t.Run("order_by", func(t *testing.T) {
+		spec := markdownparser.ExpectedResultSpec{OrderBy: []string{"age", "name"}}
+		assert.NoError(t, compareSpecRows(spec, expected, actual, "", nil, true))
+
+		err := compareSpecRows(spec, expected, actual[:1], "", nil, true)
+		diff, ok := AsDiffError(err)
+		require.True(t, ok, "got %v", err)
+		assert.True(t, diff.RowCountMismatch)
+		assert.Equal(t, []string{"age", "name"}, diff.PrimaryKeys)
+	})
+}
+
+func TestSortRowsBy(t *testing.T) {
+	rows := []map[string]any{
+		{"id": int64(10), "name": "b"},
+		{"id": nil, "name": "c"},
+		{"id": int64(9), "name": "a"},
+		{"id": []byte("9"), "name": "z"},
+	}
+
+	sorted := sortRowsBy(rows, []string{"id", "name"})
+	names := make([]string, len(sorted))
+	for i, row := range sorted {
+		names[i] = row["name"].(string)
+	}
+
+	assert.Equal(t, []string{"c", "a", "z", "b"}, names)
+	assert.Equal(t, "b", rows[0]["name"], "the input is not modified")
+}
+
+func TestProjectColumns(t *testing.T) {
+	rows := []map[string]any{{"id": int64(1), "name": "alice", "updated_at": "2024-06-01", "uuid": "x"}}
+
+	spec := markdownparser.ExpectedResultSpec{IgnoreColumns: []string{"updated_at", "uuid", "id"}}
+	assert.Equal(t, []map[string]any{{"id": int64(1), "name": "alice"}}, projectColumns(spec, rows, []string{"id"}))
+
+	spec = markdownparser.ExpectedResultSpec{OnlyColumns: []string{"name"}}
+	assert.Equal(t, []map[string]any{{"id": int64(1), "name": "alice"}}, projectColumns(spec, rows, []string{"id"}))
+	assert.Len(t, rows[0], 4, "the input is not modified")
+}
+
+func TestExecutor_ProjectedTableState(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL, updated_at TEXT NOT NULL)`)
+	require.NoError(t, err)
+
+	tableInfo := map[string]*snapsql.TableInfo{
+		"users": {
+			Name: "users",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":         {Name: "id", DataType: "int", IsPrimaryKey: true},
+				"name":       {Name: "name", DataType: "string"},
+				"updated_at": {Name: "updated_at", DataType: "string"},
+			},
+		},
+	}
+	executor := NewExecutor(db, snapsql.DialectSQLite, tableInfo)
+	query := "UPDATE users SET name = 'dave', updated_at = 'later' WHERE id = 1"
+
+	run := func(spec markdownparser.ExpectedResultSpec) error {
+		testCase := &markdownparser.TestCase{
+			Name: "Rename user",
+			Fixtures: []markdownparser.TableFixture{
+				{TableName: "users", Strategy: markdownparser.ClearInsert, Data: []map[string]any{{"id": 1, "name": "alice", "updated_at": "before"}}},
+			},
+			ExpectedResults: []markdownparser.ExpectedResultSpec{spec},
+		}
+
+		_, _, _, err := executor.ExecuteTest(testCase, query, map[string]any{}, &ExecutionOptions{Mode: FullTest, Parallel: 1, Timeout: time.Minute})
+
+		return err
+	}
+
+	expected := []map[string]any{{"id": 1, "name": "dave", "updated_at": "before"}}
+
+	assert.Error(t, run(markdownparser.ExpectedResultSpec{TableName: "users", Strategy: "all", Data: expected}))
+	assert.NoError(t, run(markdownparser.ExpectedResultSpec{TableName: "users", Strategy: "all", Data: expected, IgnoreColumns: []string{"updated_at"}}))
+	assert.NoError(t, run(markdownparser.ExpectedResultSpec{TableName: "users", Strategy: "pk-match", Data: expected, OnlyColumns: []string{"name"}}))
+	assert.Error(t, run(markdownparser.ExpectedResultSpec{TableName: "users", Strategy: "pk-match", Data: []map[string]any{{"id": 1, "name": "alice"}}, OnlyColumns: []string{"name"}}))
+}
@@ -0,0 +1,124 @@
+package fixtureexecutor
+
+import (
+	"errors"
+	"time"
+
+	"github.com/shibukawa/snapsql/markdownparser"
+)
+
+var errStepNotPrepared = errors.New("step has no prepared SQL")
+
+// executeScenario runs the steps of a test case in order inside the test case's transaction, after
+// its fixtures. Each step executes its template and validates its own expected results against the
+// database state at that point. The table expectations, verify queries and assertions of the test
+// case itself are checked after the last step. The returned result is the last step's, with the
+// duration of all steps.
+func (e *Executor) executeScenario(execution *TestExecution) (*ValidationResult, error) {
+	scenario := execution.TestCase
+	sql, parameters, args := execution.SQL, execution.Parameters, execution.Args
+
+	// Delta expectations of the test case compare the state before the first step with the final state
+	if err := e.snapshotDeltaTables(execution); err != nil {
+		return nil, wrapDefinitionFailure(err, "failed to snapshot tables for delta expectations")
+	}
+
+	snapshots := execution.TableSnapshots
+
+	defer func() {
+		execution.TestCase, execution.SQL, execution.Parameters, execution.Args = scenario, sql, parameters, args
+		execution.TableSnapshots = snapshots
+	}()
+
+	var (
+		result *ValidationResult
+		total  time.Duration
+	)
+
+	for i, step := range scenario.Steps {
+		if step.PreparedSQL == "" {
+			return nil, wrapDefinitionFailure(errStepNotPrepared, "step %d (%s)", i+1, stepLabel(step))
+		}
+
+		execution.TestCase = stepTestCase(scenario, step)
+		execution.SQL, execution.Args = e.resolveExecutableSQL(execution.TestCase, step.PreparedSQL)
+		execution.Parameters = step.Parameters
+		execution.TableSnapshots = nil
+
+		if !scenario.Now.IsZero() {
+			execution.SQL = e.rewriteCurrentTime(execution.SQL, execution.TimeAnchor)
+		}
+
+		stepResult, err := e.executeMainQuery(execution)
+		if err != nil {
+			return nil, wrapFailure(ClassifyFailure(err), err, nil, "step %d (%s)", i+1, stepLabel(step))
+		}
+
+		result = stepResult
+		total += stepResult.Duration
+	}
+
+	execution.TestCase, execution.TableSnapshots = scenario, snapshots
+
+	named, err := e.executeNamedVerifyQueries(execution)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.validateNamedExpectations(execution, named); err != nil {
+		return nil, err
+	}
+
+	if err := e.checkQueryAssertions(execution); err != nil {
+		return nil, err
+	}
+
+	if result != nil {
+		result.Duration = total
+	}
+
+	return result, nil
+}
+
+// stepTestCase returns the view of the test case that executeMainQuery validates for a step:
+// the step's parameters and expectations, without the checks that run after the last step
+func stepTestCase(scenario *markdownparser.TestCase, step markdownparser.TestStep) *markdownparser.TestCase {
+	stepCase := *scenario
+	stepCase.Parameters = step.Parameters
+	stepCase.HasParameters = step.HasParameters
+	stepCase.ExpectedResult = step.ExpectedResult
+	stepCase.ExpectedResults = step.ExpectedResults
+	stepCase.PreparedSQL = step.PreparedSQL
+	stepCase.SQLArgs = step.SQLArgs
+	stepCase.ResultOrdered = step.ResultOrdered
+	stepCase.VerifyQuery = ""
+	stepCase.VerifyQueries = nil
+	stepCase.Assertions = nil
+	stepCase.Steps = nil
+
+	return &stepCase
+}
+
+// stepLabel names a step in failure messages
+func stepLabel(step markdownparser.TestStep) string {
+	if step.Template == "" {
+		return "self"
+	}
+
+	return step.Template
+}
+
+// mainStatements returns the statements a test case executes as its main query: the prepared
+// SQL of each step for a scenario, otherwise sql
+func mainStatements(testCase *markdownparser.TestCase, sql string) []string {
+	if testCase == nil || len(testCase.Steps) == 0 {
+		return []string{sql}
+	}
+
+	statements := make([]string, 0, len(testCase.Steps))
+	for _, step := range testCase.Steps {
+		statements = append(statements, step.PreparedSQL)
+	}
+
+	return statements
+}
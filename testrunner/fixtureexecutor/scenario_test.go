@@ -0,0 +1,82 @@
+package fixtureexecutor
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/markdownparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutor_Scenario(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE notifications (id INTEGER PRIMARY KEY, status TEXT NOT NULL)`)
+	require.NoError(t, err)
+
+	tableInfo := map[string]*snapsql.TableInfo{
+		"notifications": {
+			Name: "notifications",
+			Columns: map[string]*snapsql.ColumnInfo{
+				"id":     {Name: "id", DataType: "int", IsPrimaryKey: true},
+				"status": {Name: "status", DataType: "string"},
+			},
+		},
+	}
+	executor := NewExecutor(db, snapsql.DialectSQLite, tableInfo)
+
+	newScenario := func(cancelStatus string) *markdownparser.TestCase {
+		return &markdownparser.TestCase{
+			Name: "create then cancel",
+			Fixtures: []markdownparser.TableFixture{
+				{TableName: "notifications", Strategy: markdownparser.ClearInsert, Data: []map[string]any{{"id": 1, "status": "sent"}}},
+			},
+			Steps: []markdownparser.TestStep{
+				{
+					Template:       "create_notification.snap.sql",
+					PreparedSQL:    "INSERT INTO notifications (id, status) VALUES (?, 'pending') RETURNING id",
+					SQLArgs:        []any{2},
+					ExpectedResult: []map[string]any{{"id": 2}},
+				},
+				{
+					Template:    "cancel_notification.snap.sql",
+					PreparedSQL: "UPDATE notifications SET status = 'canceled' WHERE id = ?",
+					SQLArgs:     []any{2},
+					ExpectedResults: []markdownparser.ExpectedResultSpec{
+						{TableName: "notifications", Strategy: "pk-match", Data: []map[string]any{{"id": 2, "status": cancelStatus}}},
+					},
+				},
+			},
+			ExpectedResults: []markdownparser.ExpectedResultSpec{
+				{TableName: "notifications", Strategy: "delta", Data: []map[string]any{{markdownparser.DeltaChangeKey: "added", "id": 2, "status": "canceled"}}},
+			},
+			Assertions: []markdownparser.QueryAssertion{{Table: "notifications", Expected: int64(2)}},
+		}
+	}
+
+	options := &ExecutionOptions{Mode: FullTest, Parallel: 1, Timeout: time.Minute}
+
+	result, _, _, err := executor.ExecuteTest(newScenario("canceled"), "SELECT 1", map[string]any{}, options)
+	require.NoError(t, err)
+	assert.Equal(t, UpdateQuery, result.QueryType, "the result is the last step's")
+
+	_, _, _, err = executor.ExecuteTest(newScenario("sent"), "SELECT 1", map[string]any{}, options)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "step 2 (cancel_notification.snap.sql)")
+	assert.Equal(t, FailureKindAssertion, ClassifyFailure(err))
+
+	t.Run("unprepared step", func(t *testing.T) {
+		testCase := newScenario("canceled")
+		testCase.Steps[1].PreparedSQL = ""
+
+		_, _, _, err := executor.ExecuteTest(testCase, "SELECT 1", map[string]any{}, options)
+		require.ErrorIs(t, err, errStepNotPrepared)
+		assert.Equal(t, FailureKindDefinition, ClassifyFailure(err))
+	})
+}
@@ -0,0 +1,269 @@
+package fixtureexecutor
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/shibukawa/snapsql/markdownparser"
+	"github.com/shibukawa/snapsql/tokenizer"
+)
+
+// ErrScopeNotConstrained is reported when assert_scoped is set and the executed SQL does not
+// constrain the scope column.
+var ErrScopeNotConstrained = errors.New("scope column is not constrained")
+
+// checkScopeAssertion fails the test when the executed SQL does not constrain the scope column
+// declared with assert_scoped.
+//
+// SELECT / UPDATE / DELETE must have a top-level WHERE clause that compares the column with
+// "=" or "IN" in a branch that cannot be bypassed by OR. Every UNION branch is checked on its own.
+// INSERT must list the column in its column list.
+func checkScopeAssertion(testCase *markdownparser.TestCase, sql string) error {
+	if testCase == nil || testCase.AssertScoped == "" {
+		return nil
+	}
+
+	column := testCase.AssertScoped
+
+	tokens, err := tokenizer.Tokenize(sql)
+	if err != nil {
+		return wrapDefinitionFailure(err, "failed to tokenize SQL for assert_scoped")
+	}
+
+	tokens = significantTokens(tokens)
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	if tokens[0].Type == tokenizer.INSERT {
+		if insertListsColumn(tokens, column) {
+			return nil
+		}
+
+		return wrapAssertionFailure(ErrScopeNotConstrained, "INSERT does not set scope column %q", column)
+	}
+
+	for _, branch := range splitTopLevel(tokens, isSetOperator) {
+		where := topLevelWhere(branch)
+		if where == nil {
+			return wrapAssertionFailure(ErrScopeNotConstrained, "executed SQL has no WHERE clause constraining scope column %q", column)
+		}
+
+		if !constrainsColumn(where, column) {
+			return wrapAssertionFailure(ErrScopeNotConstrained, "WHERE clause does not constrain scope column %q", column)
+		}
+	}
+
+	return nil
+}
+
+// significantTokens drops whitespace, comments and the terminating EOF/semicolon
+func significantTokens(tokens []tokenizer.Token) []tokenizer.Token {
+	result := make([]tokenizer.Token, 0, len(tokens))
+
+	for _, token := range tokens {
+		switch token.Type {
+		case tokenizer.WHITESPACE, tokenizer.LINE_COMMENT, tokenizer.BLOCK_COMMENT, tokenizer.EOF, tokenizer.SEMICOLON:
+		default:
+			result = append(result, token)
+		}
+	}
+
+	return result
+}
+
+func isSetOperator(token tokenizer.Token) bool {
+	return isKeyword(token, "UNION") || isKeyword(token, "INTERSECT") || isKeyword(token, "EXCEPT")
+}
+
+// splitTopLevel splits tokens at separators that are not nested in parentheses
+func splitTopLevel(tokens []tokenizer.Token, isSeparator func(tokenizer.Token) bool) [][]tokenizer.Token {
+	var parts [][]tokenizer.Token
+
+	depth := 0
+	start := 0
+
+	for i, token := range tokens {
+		switch {
+		case token.Type == tokenizer.OPENED_PARENS:
+			depth++
+		case token.Type == tokenizer.CLOSED_PARENS:
+			depth--
+		case depth == 0 && isSeparator(token):
+			parts = append(parts, tokens[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, tokens[start:])
+}
+
+// topLevelWhere returns the condition tokens of the WHERE clause that is not nested in parentheses
+func topLevelWhere(tokens []tokenizer.Token) []tokenizer.Token {
+	depth := 0
+	start := -1
+
+	for i, token := range tokens {
+		switch token.Type {
+		case tokenizer.OPENED_PARENS:
+			depth++
+		case tokenizer.CLOSED_PARENS:
+			depth--
+		case tokenizer.WHERE:
+			if depth == 0 && start < 0 {
+				start = i + 1
+			}
+		case tokenizer.GROUP, tokenizer.HAVING, tokenizer.ORDER, tokenizer.LIMIT, tokenizer.OFFSET, tokenizer.RETURNING, tokenizer.FOR:
+			if depth == 0 && start >= 0 {
+				return tokens[start:i]
+			}
+		}
+	}
+
+	if start < 0 {
+		return nil
+	}
+
+	return tokens[start:]
+}
+
+// constrainsColumn reports whether the condition limits rows to specific values of column.
+// All OR branches must constrain the column, while a single AND operand is enough.
+func constrainsColumn(condition []tokenizer.Token, column string) bool {
+	if len(condition) == 0 {
+		return false
+	}
+
+	branches := splitTopLevel(condition, func(token tokenizer.Token) bool { return token.Type == tokenizer.OR })
+	if len(branches) > 1 {
+		for _, branch := range branches {
+			if !constrainsColumn(branch, column) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for _, operand := range splitTopLevel(condition, func(token tokenizer.Token) bool { return token.Type == tokenizer.AND }) {
+		if comparesColumn(operand, column) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// comparesColumn checks a single AND operand: "col = x", "x = col", "col IN (...)" or a parenthesized condition
+func comparesColumn(operand []tokenizer.Token, column string) bool {
+	if len(operand) == 0 {
+		return false
+	}
+
+	if operand[0].Type == tokenizer.OPENED_PARENS && enclosesAll(operand) {
+		return constrainsColumn(operand[1:len(operand)-1], column)
+	}
+
+	depth := 0
+
+	for i, token := range operand {
+		switch token.Type {
+		case tokenizer.OPENED_PARENS:
+			depth++
+			continue
+		case tokenizer.CLOSED_PARENS:
+			depth--
+			continue
+		}
+
+		if depth > 0 || !isColumnReference(operand, i, column) {
+			continue
+		}
+
+		if i+1 < len(operand) && (operand[i+1].Type == tokenizer.EQUAL || isKeyword(operand[i+1], "IN")) {
+			return true
+		}
+
+		if start := qualifiedNameStart(operand, i); start > 0 && operand[start-1].Type == tokenizer.EQUAL {
+			return true
+		}
+	}
+
+	return false
+}
+
+// qualifiedNameStart returns the index of the first part of the dotted name ending at index
+func qualifiedNameStart(tokens []tokenizer.Token, index int) int {
+	for index >= 2 && tokens[index-1].Type == tokenizer.DOT {
+		index -= 2
+	}
+
+	return index
+}
+
+// isKeyword matches keywords regardless of how the raw tokenizer classified them
+// (e.g. IN and UNION are reported as RESERVED_IDENTIFIER)
+func isKeyword(token tokenizer.Token, keyword string) bool {
+	return token.Type != tokenizer.STRING && strings.EqualFold(token.Value, keyword)
+}
+
+// enclosesAll reports whether the first parenthesis closes at the last token
+func enclosesAll(tokens []tokenizer.Token) bool {
+	depth := 0
+
+	for i, token := range tokens {
+		switch token.Type {
+		case tokenizer.OPENED_PARENS:
+			depth++
+		case tokenizer.CLOSED_PARENS:
+			depth--
+			if depth == 0 {
+				return i == len(tokens)-1
+			}
+		}
+	}
+
+	return false
+}
+
+// isColumnReference matches column and alias-qualified column (t.column), ignoring identifier quotes
+func isColumnReference(tokens []tokenizer.Token, index int, column string) bool {
+	token := tokens[index]
+	if token.Type != tokenizer.IDENTIFIER && token.Type != tokenizer.CONTEXTUAL_IDENTIFIER {
+		return false
+	}
+
+	if !strings.EqualFold(strings.Trim(token.Value, "\"`[]"), column) {
+		return false
+	}
+
+	// The column name of "t.column" is the last part; "column.x" refers to something else
+	return index+1 >= len(tokens) || tokens[index+1].Type != tokenizer.DOT
+}
+
+// insertListsColumn reports whether the INSERT column list contains column
+func insertListsColumn(tokens []tokenizer.Token, column string) bool {
+	depth := 0
+
+	for i, token := range tokens {
+		switch token.Type {
+		case tokenizer.OPENED_PARENS:
+			depth++
+		case tokenizer.CLOSED_PARENS:
+			depth--
+			if depth == 0 {
+				return false
+			}
+		case tokenizer.VALUES, tokenizer.SELECT:
+			if depth == 0 {
+				return false
+			}
+		default:
+			if depth == 1 && isColumnReference(tokens, i, column) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
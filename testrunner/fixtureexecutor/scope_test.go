@@ -0,0 +1,49 @@
+package fixtureexecutor
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shibukawa/snapsql/markdownparser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckScopeAssertion(t *testing.T) {
+	tests := []struct {
+		name    string
+		sql     string
+		wantErr bool
+	}{
+		{name: "equal", sql: "SELECT id FROM orders WHERE tenant_id = $1 AND status = 'open'"},
+		{name: "qualified and reversed", sql: "SELECT o.id FROM orders o WHERE $1 = o.tenant_id ORDER BY o.id"},
+		{name: "in list", sql: "DELETE FROM orders WHERE tenant_id IN ($1, $2)"},
+		{name: "parenthesized", sql: "SELECT id FROM orders WHERE (id = $1 AND tenant_id = $2) AND deleted_at IS NULL"},
+		{name: "all or branches constrained", sql: "SELECT id FROM orders WHERE tenant_id = $1 AND id = $2 OR tenant_id = $1 AND id = $3"},
+		{name: "update", sql: "UPDATE orders SET status = $1 WHERE tenant_id = $2 RETURNING id"},
+		{name: "insert column list", sql: "INSERT INTO orders (id, tenant_id) VALUES ($1, $2)"},
+		{name: "union branches", sql: "SELECT id FROM a WHERE tenant_id = $1 UNION ALL SELECT id FROM b WHERE tenant_id = $1"},
+		{name: "no where", sql: "SELECT id FROM orders", wantErr: true},
+		{name: "other column", sql: "SELECT id FROM orders WHERE id = $1", wantErr: true},
+		{name: "bypassed by or", sql: "SELECT id FROM orders WHERE tenant_id = $1 OR is_public = true", wantErr: true},
+		{name: "only in subquery", sql: "SELECT id FROM orders WHERE id IN (SELECT order_id FROM items WHERE tenant_id = $1)", wantErr: true},
+		{name: "only in join", sql: "SELECT o.id FROM orders o JOIN tenants t ON t.tenant_id = o.tenant_id WHERE o.id = $1", wantErr: true},
+		{name: "not null check", sql: "SELECT id FROM orders WHERE tenant_id IS NOT NULL", wantErr: true},
+		{name: "union branch missing", sql: "SELECT id FROM a WHERE tenant_id = $1 UNION SELECT id FROM b", wantErr: true},
+		{name: "insert without column", sql: "INSERT INTO orders (id, status) VALUES ($1, $2)", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkScopeAssertion(&markdownparser.TestCase{AssertScoped: "tenant_id"}, tt.sql)
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.True(t, errors.Is(err, ErrScopeNotConstrained), "got %v", err)
+			assert.Equal(t, FailureKindAssertion, ClassifyFailure(err))
+		})
+	}
+
+	assert.NoError(t, checkScopeAssertion(&markdownparser.TestCase{}, "SELECT id FROM orders"))
+}
@@ -3,9 +3,12 @@ package fixtureexecutor
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"maps"
+	"math/rand"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/shibukawa/snapsql"
@@ -27,6 +30,12 @@ type TestResult struct {
 	ErrorMatch        bool    // Whether error matched expected
 	ErrorMatchMessage string  // Detailed error match message
 	Performance       *explain.PerformanceEvaluation
+	// Attempts is the number of times the test case ran (more than 1 when Retries is set)
+	Attempts int
+	// Flaky reports that the test case failed first and passed on a retry
+	Flaky bool
+	// FlakyError is the error of the first failed attempt of a flaky test case
+	FlakyError error
 }
 
 // TestSummary represents the overall test execution summary
@@ -34,6 +43,8 @@ type TestSummary struct {
 	TotalTests    int
 	PassedTests   int
 	FailedTests   int
+	SkippedTests  int // not run or cancelled because the run stopped at MaxFailures
+	FlakyTests    int // passed only on a retry (counted in PassedTests as well)
 	TotalDuration time.Duration
 	Results       []TestResult
 }
@@ -41,7 +52,8 @@ type TestSummary struct {
 // TestRunner manages parallel test execution
 type TestRunner struct {
 	executor        *Executor
-	workerPool      chan struct{} // セマフォ
+	executors       []*Executor    // all executors handed out to workers (shared or per-worker)
+	workerPool      chan *Executor // セマフォ (each slot carries the executor the worker uses)
 	options         *ExecutionOptions
 	sql             string         // SQL query from document
 	parameters      map[string]any // Default parameters from document
@@ -54,15 +66,56 @@ func NewTestRunner(db *sql.DB, dialect snapsql.Dialect, options *ExecutionOption
 		options = DefaultExecutionOptions()
 	}
 
+	executor := NewExecutor(db, dialect, make(map[string]*snapsql.TableInfo)) // schema info can be injected later via SetTableInfo
+
+	workers := sharedDatabaseWorkers(dialect, options)
+
+	workerPool := make(chan *Executor, workers)
+	for range workers {
+		workerPool <- executor
+	}
+
 	return &TestRunner{
-		executor:        NewExecutor(db, dialect, make(map[string]*snapsql.TableInfo)), // schema info can be injected later via SetTableInfo
-		workerPool:      make(chan struct{}, options.Parallel),
+		executor:        executor,
+		executors:       []*Executor{executor},
+		workerPool:      workerPool,
 		options:         options,
 		parameters:      make(map[string]any),
 		tableReferences: make(map[*markdownparser.TestCase]map[string]intermediate.TableReferenceInfo),
 	}
 }
 
+// sharedDatabaseWorkers returns the number of workers sharing one database. Test cases of a
+// dialect without transactions empty the tables they touch before they run (see touchedTables),
+// which would wipe the rows of a concurrent test case, so they run one at a time unless
+// SetWorkerDatabases gives every worker its own database.
+func sharedDatabaseWorkers(dialect snapsql.Dialect, options *ExecutionOptions) int {
+	if !snapsql.SupportsTransactions(dialect) {
+		return 1
+	}
+
+	return max(options.Parallel, 1)
+}
+
+// SetWorkerDatabases binds every parallel worker to its own database so that test cases do not
+// serialize on a single connection. Each database must already have the schema applied.
+// The number of databases replaces the configured worker count.
+func (tr *TestRunner) SetWorkerDatabases(dbs []*sql.DB) {
+	if len(dbs) == 0 {
+		return
+	}
+
+	tr.executors = make([]*Executor, 0, len(dbs))
+	tr.workerPool = make(chan *Executor, len(dbs))
+
+	for _, db := range dbs {
+		worker := NewExecutor(db, tr.executor.dialect, tr.executor.tableInfo)
+		worker.baseDir = tr.executor.baseDir
+		tr.executors = append(tr.executors, worker)
+		tr.workerPool <- worker
+	}
+}
+
 // SetTableInfo injects or replaces the schema information used during fixture execution.
 // This is primarily used by unit tests that construct an in-memory database schema on the fly.
 // It is safe to call multiple times; the reference is replaced atomically without locking because
@@ -71,6 +124,10 @@ func (tr *TestRunner) SetTableInfo(tableInfo map[string]*snapsql.TableInfo) {
 	if tr.executor != nil {
 		tr.executor.tableInfo = tableInfo
 	}
+
+	for _, executor := range tr.executors {
+		executor.tableInfo = tableInfo
+	}
 }
 
 // SetBaseDir sets the base directory used to resolve external file references during execution.
@@ -78,6 +135,10 @@ func (tr *TestRunner) SetBaseDir(dir string) {
 	if tr.executor != nil {
 		tr.executor.SetBaseDir(dir)
 	}
+
+	for _, executor := range tr.executors {
+		executor.SetBaseDir(dir)
+	}
 }
 
 // SetSQL sets the SQL query for test execution
@@ -116,21 +177,71 @@ func (tr *TestRunner) RunTests(ctx context.Context, testCases []*markdownparser.
 
 	startTime := time.Now()
 
+	testCases = tr.executionOrder(testCases)
+
+	// Cancelled when MaxFailures is reached so queued and running test cases stop
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
 	// Results channel
 	results := make(chan TestResult, len(testCases))
 
+	var (
+		failures atomic.Int64
+		stopped  atomic.Bool
+	)
+
+	// report is called by the workers so the run stops before the next queued job is pulled
+	report := func(result TestResult) {
+		skipped := stopped.Load() && isCancellation(result.Error)
+		if !result.Success && !skipped && tr.options.MaxFailures > 0 &&
+			failures.Add(1) >= int64(tr.options.MaxFailures) && !stopped.Swap(true) {
+			stop()
+		}
+
+		results <- result
+	}
+
+	// A fixed set of workers pulls the jobs in dispatch order, so a shuffled run starts the test
+	// cases in the same order for a given seed regardless of goroutine scheduling
+	jobs := tr.buildJobs(testCases)
+
+	queue := make(chan testJob, len(jobs))
+	for _, job := range jobs {
+		queue <- job
+	}
+
+	close(queue)
+
 	var wg sync.WaitGroup
 
-	// Execute tests in parallel
-	for _, testCase := range testCases {
+	for range min(cap(tr.workerPool), len(jobs)) {
 		wg.Add(1)
 
-		go func(tc *markdownparser.TestCase) {
+		go func() {
 			defer wg.Done()
 
-			result := tr.executeTestWithTimeout(ctx, tc)
-			results <- result
-		}(testCase)
+			for job := range queue {
+				// Jobs still queued when the run is stopped are reported as not started
+				if err := ctx.Err(); err != nil {
+					for _, testCase := range job.testCases {
+						report(TestResult{TestCase: testCase, Success: false, Error: err})
+					}
+
+					continue
+				}
+
+				if job.setup != nil {
+					for _, result := range tr.executeSetupGroup(ctx, job.setup, job.testCases) {
+						report(result)
+					}
+
+					continue
+				}
+
+				report(tr.executeTestWithTimeout(ctx, job.testCases[0]))
+			}
+		}()
 	}
 
 	// Wait for all tests to complete
@@ -141,9 +252,18 @@ func (tr *TestRunner) RunTests(ctx context.Context, testCases []*markdownparser.
 
 	// Collect results
 	for result := range results {
+		if stopped.Load() && !result.Success && isCancellation(result.Error) {
+			summary.SkippedTests++
+			continue
+		}
+
 		summary.Results = append(summary.Results, result)
 		if result.Success {
 			summary.PassedTests++
+
+			if result.Flaky {
+				summary.FlakyTests++
+			}
 		} else {
 			summary.FailedTests++
 		}
@@ -154,12 +274,71 @@ func (tr *TestRunner) RunTests(ctx context.Context, testCases []*markdownparser.
 	return summary, nil
 }
 
+// isCancellation reports whether err was caused by stopping the run: the test case did not start,
+// or its transaction was rolled back by the cancelled context while it was running
+func isCancellation(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, sql.ErrTxDone)
+}
+
+// testJob is a unit of work pulled by a worker: a single test case, or the test cases sharing a
+// setup that run sequentially in one transaction
+type testJob struct {
+	setup     *markdownparser.FixtureSetup
+	testCases []*markdownparser.TestCase
+}
+
+// buildJobs groups the test cases into jobs. A setup group is placed where its first test case
+// appears, keeping the (possibly shuffled) execution order. Dialects without transactions cannot
+// isolate the cases of a group by savepoints, so each test case becomes its own job.
+func (tr *TestRunner) buildJobs(testCases []*markdownparser.TestCase) []testJob {
+	jobs := make([]testJob, 0, len(testCases))
+	setupJobs := make(map[*markdownparser.FixtureSetup]int)
+	grouping := snapsql.SupportsTransactions(tr.executor.dialect)
+
+	for _, testCase := range testCases {
+		if testCase.Setup == nil || !grouping {
+			jobs = append(jobs, testJob{testCases: []*markdownparser.TestCase{testCase}})
+			continue
+		}
+
+		if index, exists := setupJobs[testCase.Setup]; exists {
+			jobs[index].testCases = append(jobs[index].testCases, testCase)
+			continue
+		}
+
+		setupJobs[testCase.Setup] = len(jobs)
+		jobs = append(jobs, testJob{setup: testCase.Setup, testCases: []*markdownparser.TestCase{testCase}})
+	}
+
+	return jobs
+}
+
+// executionOrder returns the test cases in the order they are dispatched to workers.
+// With the Shuffle option a copy is shuffled deterministically by ShuffleSeed.
+func (tr *TestRunner) executionOrder(testCases []*markdownparser.TestCase) []*markdownparser.TestCase {
+	if tr.options == nil || !tr.options.Shuffle {
+		return testCases
+	}
+
+	shuffled := make([]*markdownparser.TestCase, len(testCases))
+	copy(shuffled, testCases)
+
+	rng := rand.New(rand.NewSource(tr.options.ShuffleSeed)) //nolint:gosec // reproducible order, not security sensitive
+	rng.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	return shuffled
+}
+
 // executeTestWithTimeout executes a single test with timeout and semaphore
 func (tr *TestRunner) executeTestWithTimeout(ctx context.Context, testCase *markdownparser.TestCase) TestResult {
 	// Acquire semaphore
+	var executor *Executor
+
 	select {
-	case tr.workerPool <- struct{}{}:
-		defer func() { <-tr.workerPool }()
+	case executor = <-tr.workerPool:
+		defer func() { tr.workerPool <- executor }()
 	case <-ctx.Done():
 		return TestResult{
 			TestCase: testCase,
@@ -168,6 +347,81 @@ func (tr *TestRunner) executeTestWithTimeout(ctx context.Context, testCase *mark
 		}
 	}
 
+	return tr.executeTestOn(ctx, executor, nil, testCase)
+}
+
+// executeSetupGroup inserts the shared setup fixtures once and runs the test cases one by one,
+// each inside a savepoint of the same transaction
+func (tr *TestRunner) executeSetupGroup(ctx context.Context, setup *markdownparser.FixtureSetup, testCases []*markdownparser.TestCase) []TestResult {
+	results := make([]TestResult, 0, len(testCases))
+
+	failAll := func(err error) []TestResult {
+		for _, testCase := range testCases {
+			results = append(results, TestResult{
+				TestCase: testCase,
+				Success:  false,
+				Error:    err,
+			})
+		}
+
+		return results
+	}
+
+	var executor *Executor
+
+	select {
+	case executor = <-tr.workerPool:
+		defer func() { tr.workerPool <- executor }()
+	case <-ctx.Done():
+		return failAll(ctx.Err())
+	}
+
+	tx, err := executor.BeginSetup(ctx, setup)
+	if err != nil {
+		return failAll(err)
+	}
+
+	defer func() {
+		if tr.options.Commit {
+			tx.Commit()
+		} else {
+			tx.Rollback()
+		}
+	}()
+
+	for _, testCase := range testCases {
+		results = append(results, tr.executeTestOn(ctx, executor, tx, testCase))
+	}
+
+	return results
+}
+
+// executeTestOn executes a single test with timeout on the acquired executor. When tx is given,
+// the test runs inside a savepoint of that transaction. A failed test case is run again up to
+// Retries times; one that passes on a retry is reported as flaky.
+func (tr *TestRunner) executeTestOn(ctx context.Context, executor *Executor, tx *sql.Tx, testCase *markdownparser.TestCase) TestResult {
+	result := tr.executeAttempt(ctx, executor, tx, testCase)
+	result.Attempts = 1
+
+	firstError := result.Error
+
+	for attempts := 1; !result.Success && attempts <= tr.options.Retries && ctx.Err() == nil; {
+		attempts++
+
+		result = tr.executeAttempt(ctx, executor, tx, testCase)
+		result.Attempts = attempts
+
+		if result.Success {
+			result.Flaky = true
+			result.FlakyError = firstError
+		}
+	}
+
+	return result
+}
+
+// executeAttempt runs the test case once
+func (tr *TestRunner) executeAttempt(ctx context.Context, executor *Executor, tx *sql.Tx, testCase *markdownparser.TestCase) TestResult {
 	// Create timeout context
 	testCtx, cancel := context.WithTimeout(ctx, tr.options.Timeout)
 	defer cancel()
@@ -175,7 +429,7 @@ func (tr *TestRunner) executeTestWithTimeout(ctx context.Context, testCase *mark
 	startTime := time.Now()
 
 	// Execute test
-	result, trace, perf, err := tr.executeTestWithContext(testCtx, testCase)
+	result, trace, perf, err := tr.executeTestWithContext(testCtx, executor, tx, testCase)
 
 	// Handle error test cases
 	if testCase.ExpectedError != nil {
@@ -221,7 +475,17 @@ func (tr *TestRunner) handleErrorTest(testCase *markdownparser.TestCase, result
 	testResult.ActualErrorType = string(actualErrorType)
 
 	// Check if error matches expected type
-	matches, message := markdownparser.MatchesExpectedError(err, *testCase.ExpectedError)
+	var (
+		matches bool
+		message string
+	)
+
+	if testCase.ExpectedErrorSpec != nil {
+		matches, message = markdownparser.MatchesExpectedErrorSpec(err, testCase.ExpectedErrorSpec)
+	} else {
+		matches, message = markdownparser.MatchesExpectedError(err, *testCase.ExpectedError)
+	}
+
 	testResult.ErrorMatch = matches
 	testResult.ErrorMatchMessage = message
 	testResult.Success = matches
@@ -230,7 +494,7 @@ func (tr *TestRunner) handleErrorTest(testCase *markdownparser.TestCase, result
 }
 
 // executeTestWithContext executes a test within a context
-func (tr *TestRunner) executeTestWithContext(ctx context.Context, testCase *markdownparser.TestCase) (*ValidationResult, []SQLTrace, *explain.PerformanceEvaluation, error) {
+func (tr *TestRunner) executeTestWithContext(ctx context.Context, executor *Executor, tx *sql.Tx, testCase *markdownparser.TestCase) (*ValidationResult, []SQLTrace, *explain.PerformanceEvaluation, error) {
 	// Check for context cancellation
 	select {
 	case <-ctx.Done():
@@ -263,7 +527,11 @@ func (tr *TestRunner) executeTestWithContext(ctx context.Context, testCase *mark
 		execOptions.TableReferenceMap = nil
 	}
 
-	return tr.executor.ExecuteTest(testCase, sql, parameters, &execOptions)
+	if tx != nil {
+		return executor.ExecuteTestInSavepoint(ctx, tx, testCase, sql, parameters, &execOptions)
+	}
+
+	return executor.ExecuteTestContext(ctx, testCase, sql, parameters, &execOptions)
 }
 
 // NormalizeParameters walks parameter map and resolves fixture-style special tokens.
@@ -271,6 +539,12 @@ func (tr *TestRunner) executeTestWithContext(ctx context.Context, testCase *mark
 // the first element is "currentdate" (case-insensitive). It reuses resolveFixtureValue semantics
 // from executor.go by temporarily marshalling values into the same shapes.
 func NormalizeParameters(params map[string]any) error {
+	return NormalizeParametersAt(params, time.Time{})
+}
+
+// NormalizeParametersAt is NormalizeParameters with [currentdate] tokens resolved relative to now.
+// A zero now uses the wall time.
+func NormalizeParametersAt(params map[string]any, now time.Time) error {
 	for k, v := range params {
 		// Only handle string, []any, map[string]any types; other types remain unchanged
 		switch vv := v.(type) {
@@ -278,7 +552,12 @@ func NormalizeParameters(params map[string]any) error {
 			// Delegate to fixture resolver already present in executor.go
 			// We call resolveFixtureValue by constructing a value similar to fixture element
 			// Note: resolveFixtureValue lives in executor.go; import path allows access within package
-			nv, err := resolveFixtureValue(vv)
+			var value any = vv
+			if !now.IsZero() {
+				value = anchorCurrentDate(vv, now, true)
+			}
+
+			nv, err := resolveFixtureValue(value, nil)
 			if err != nil {
 				return fmt.Errorf("parameter %s: %w", k, err)
 			}
@@ -286,7 +565,7 @@ func NormalizeParameters(params map[string]any) error {
 			params[k] = nv
 		case map[string]any:
 			// recursively normalize nested maps
-			if err := NormalizeParameters(vv); err != nil {
+			if err := NormalizeParametersAt(vv, now); err != nil {
 				return err
 			}
 		}
@@ -384,8 +663,13 @@ func (tr *TestRunner) SetOptions(options *ExecutionOptions) {
 	}
 
 	tr.options = options
-	// Recreate worker pool if parallel count changed
-	if tr.workerPool == nil || cap(tr.workerPool) != options.Parallel {
-		tr.workerPool = make(chan struct{}, options.Parallel)
+	// Recreate worker pool if parallel count changed. Per-worker databases set by
+	// SetWorkerDatabases decide the worker count themselves and are kept.
+	workers := sharedDatabaseWorkers(tr.executor.dialect, options)
+	if len(tr.executors) == 1 && tr.executors[0] == tr.executor && (tr.workerPool == nil || cap(tr.workerPool) != workers) {
+		tr.workerPool = make(chan *Executor, workers)
+		for range workers {
+			tr.workerPool <- tr.executor
+		}
 	}
 }
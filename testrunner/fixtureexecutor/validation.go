@@ -92,9 +92,9 @@ func parseValidationSpec(key string, value any) (ValidationSpec, error) {
 }
 
 // validateResult validates the query result against the expected specifications
-func (e *Executor) validateResult(tx *sql.Tx, result *ValidationResult, specs []ValidationSpec) error {
+func (e *Executor) validateResult(ctx context.Context, tx *sql.Tx, result *ValidationResult, specs []ValidationSpec) error {
 	for _, spec := range specs {
-		err := e.validateSingleSpec(tx, result, spec)
+		err := e.validateSingleSpec(ctx, tx, result, spec)
 		if err != nil {
 			return fmt.Errorf("validation failed for %s[%s]: %w", spec.TableName, spec.Strategy, err)
 		}
@@ -104,18 +104,18 @@ func (e *Executor) validateResult(tx *sql.Tx, result *ValidationResult, specs []
 }
 
 // validateSingleSpec validates a single specification
-func (e *Executor) validateSingleSpec(tx *sql.Tx, result *ValidationResult, spec ValidationSpec) error {
+func (e *Executor) validateSingleSpec(ctx context.Context, tx *sql.Tx, result *ValidationResult, spec ValidationSpec) error {
 	switch spec.Strategy {
 	case DirectResult:
 		return e.validateDirectResult(result, spec)
 	case NumericResult:
 		return e.validateNumericResult(result, spec)
 	case TableState:
-		return e.validateTableState(tx, spec)
+		return e.validateTableState(ctx, tx, spec)
 	case Existence:
-		return e.validateExistence(tx, spec)
+		return e.validateExistence(ctx, tx, spec)
 	case Count:
-		return e.validateCount(tx, spec)
+		return e.validateCount(ctx, tx, spec)
 	default:
 		return fmt.Errorf("%w: %s", snapsql.ErrUnsupportedValidationStrategy, spec.Strategy)
 	}
@@ -175,7 +175,7 @@ func (e *Executor) validateNumericResult(result *ValidationResult, spec Validati
 }
 
 // validateTableState validates table state after DML operation
-func (e *Executor) validateTableState(tx *sql.Tx, spec ValidationSpec) error {
+func (e *Executor) validateTableState(ctx context.Context, tx *sql.Tx, spec ValidationSpec) error {
 	// Handle both array and single object formats
 	var expected []map[string]any
 
@@ -200,7 +200,7 @@ func (e *Executor) validateTableState(tx *sql.Tx, spec ValidationSpec) error {
 	// Query the table to get current state
 	query := "SELECT * FROM " + spec.TableName
 
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
 	rows, err := tx.QueryContext(ctx, query)
@@ -215,6 +215,8 @@ func (e *Executor) validateTableState(tx *sql.Tx, spec ValidationSpec) error {
 		return fmt.Errorf("failed to get column names: %w", err)
 	}
 
+	typeNames := columnTypeNames(rows, len(columns))
+
 	// Read actual data
 	var actualData []map[string]any
 
@@ -240,6 +242,7 @@ func (e *Executor) validateTableState(tx *sql.Tx, spec ValidationSpec) error {
 			} else {
 				row[col] = val
 			}
+			row[col] = e.decodeScannedValue(typeNames[i], row[col])
 		}
 
 		actualData = append(actualData, row)
@@ -271,7 +274,7 @@ func (e *Executor) validateTableState(tx *sql.Tx, spec ValidationSpec) error {
 }
 
 // validateExistence validates existence of records
-func (e *Executor) validateExistence(tx *sql.Tx, spec ValidationSpec) error {
+func (e *Executor) validateExistence(ctx context.Context, tx *sql.Tx, spec ValidationSpec) error {
 	// Handle both array and single object formats
 	var expectedRows []map[string]any
 
@@ -320,7 +323,7 @@ func (e *Executor) validateExistence(tx *sql.Tx, spec ValidationSpec) error {
 
 		var count int64
 
-		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 		defer cancel()
 
 		err := tx.QueryRowContext(ctx, query, args...).Scan(&count)
@@ -338,7 +341,7 @@ func (e *Executor) validateExistence(tx *sql.Tx, spec ValidationSpec) error {
 }
 
 // validateCount validates row count
-func (e *Executor) validateCount(tx *sql.Tx, spec ValidationSpec) error {
+func (e *Executor) validateCount(ctx context.Context, tx *sql.Tx, spec ValidationSpec) error {
 	expectedCount, err := convertToInt64(spec.Expected)
 	if err != nil {
 		return fmt.Errorf("invalid count value: %w", err)
@@ -348,7 +351,7 @@ func (e *Executor) validateCount(tx *sql.Tx, spec ValidationSpec) error {
 
 	var actualCount int64
 
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
 	err = tx.QueryRowContext(ctx, query).Scan(&actualCount)
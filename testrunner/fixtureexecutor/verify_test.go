@@ -0,0 +1,59 @@
+package fixtureexecutor
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/markdownparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutor_NamedVerifyQueries(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE orders (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL);
+CREATE TABLE order_items (order_id INTEGER NOT NULL, sku TEXT NOT NULL)`)
+	require.NoError(t, err)
+
+	executor := NewExecutor(db, snapsql.DialectSQLite, nil)
+	options := &ExecutionOptions{Mode: FullTest, Parallel: 1, Timeout: time.Minute}
+	query := "INSERT INTO orders (id, user_id) VALUES (1, 7); INSERT INTO order_items (order_id, sku) VALUES (1, 'A'), (1, 'B')"
+
+	testCase := &markdownparser.TestCase{
+		Name: "order and items",
+		VerifyQueries: []markdownparser.VerifyQuerySpec{
+			{Name: "orders", Query: "SELECT id, user_id FROM orders"},
+			{Name: "items", Query: "SELECT order_id, sku FROM order_items ORDER BY sku"},
+		},
+		ExpectedResults: []markdownparser.ExpectedResultSpec{
+			{TableName: "orders", Strategy: "all", Data: []map[string]any{{"id": 1, "user_id": 7}}},
+			{TableName: "items", Strategy: "all", Data: []map[string]any{{"order_id": 1, "sku": "A"}, {"order_id": 1, "sku": "B"}}},
+		},
+	}
+
+	_, _, _, err = executor.ExecuteTest(testCase, query, map[string]any{}, options)
+	require.NoError(t, err)
+
+	t.Run("mismatch names the verify query", func(t *testing.T) {
+		testCase := *testCase
+		testCase.ExpectedResults = []markdownparser.ExpectedResultSpec{
+			testCase.ExpectedResults[0],
+			{TableName: "items", Strategy: "all", Data: []map[string]any{{"order_id": 1, "sku": "A"}}},
+		}
+
+		_, _, _, err := executor.ExecuteTest(&testCase, query, map[string]any{}, options)
+		require.Error(t, err)
+		assert.Equal(t, FailureKindAssertion, ClassifyFailure(err))
+		assert.Contains(t, err.Error(), `verify query "items" validation failed`)
+
+		diff, ok := AsDiffError(err)
+		require.True(t, ok)
+		assert.Equal(t, "items", diff.Table)
+	})
+}
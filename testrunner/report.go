@@ -0,0 +1,319 @@
+package testrunner
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/shibukawa/snapsql/testrunner/fixtureexecutor"
+)
+
+// ReportFormat identifies a machine-readable test report format
+type ReportFormat string
+
+const (
+	ReportFormatJUnit ReportFormat = "junit"
+	ReportFormatJSON  ReportFormat = "json"
+	// ReportFormatSlowQueries writes the slow query ranking (WriteSlowQueryReport)
+	ReportFormatSlowQueries ReportFormat = "slow-queries"
+)
+
+var (
+	ErrUnsupportedReportFormat = errors.New("unsupported report format")
+)
+
+var ansiEscapePattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// WriteReportFile writes the summary to path in the requested format.
+// Parent directories are created when missing.
+func WriteReportFile(format ReportFormat, path string, summary *FixtureTestSummary) error {
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create report directory %s: %w", dir, err)
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	switch format {
+	case ReportFormatJUnit:
+		err = WriteJUnitReport(file, summary)
+	case ReportFormatJSON:
+		err = WriteJSONReport(file, summary)
+	case ReportFormatSlowQueries:
+		err = WriteSlowQueryReport(file, summary)
+	default:
+		err = fmt.Errorf("%w: %s", ErrUnsupportedReportFormat, format)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return file.Close()
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr,omitempty"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Errors   int             `xml:"errors,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Error     *junitFailure `xml:"error,omitempty"`
+	// FlakyFailure follows the Maven Surefire extension for test cases that passed on a retry
+	FlakyFailure *junitFailure `xml:"flakyFailure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnitReport writes the summary as JUnit XML. Test cases are grouped into one
+// testsuite per source file. Definition failures are reported as <error>, all other
+// failures as <failure>. Test cases that passed on a retry carry a <flakyFailure>.
+func WriteJUnitReport(w io.Writer, summary *FixtureTestSummary) error {
+	root := junitTestSuites{
+		Name:     "snapsql",
+		Tests:    summary.TotalTests,
+		Failures: summary.AssertionFailures + summary.UnknownFailures,
+		Errors:   summary.DefinitionFailures,
+		Skipped:  summary.SkippedTests,
+		Time:     formatSeconds(summary.TotalDuration.Seconds()),
+	}
+
+	fileOrder, fileGroups := groupResultsByFile(summary.Results)
+	for _, path := range fileOrder {
+		suite := junitTestSuite{Name: path}
+
+		var seconds float64
+
+		for _, result := range fileGroups[path] {
+			seconds += result.Duration.Seconds()
+
+			tc := junitTestCase{
+				Name:      result.TestName,
+				Classname: path,
+				Line:      result.SourceLine,
+				Time:      formatSeconds(result.Duration.Seconds()),
+			}
+			if path != "<unknown>" {
+				tc.File = path
+			}
+
+			if result.Flaky {
+				tc.FlakyFailure = &junitFailure{
+					Message: errorMessage(result.FlakyError),
+					Type:    "flaky",
+					Body:    failureDetail(result.FlakyError),
+				}
+			}
+
+			if !result.Success {
+				failure := &junitFailure{
+					Message: errorMessage(result.Error),
+					Type:    failureKindName(result.FailureKind),
+					Body:    failureDetail(result.Error),
+				}
+				if result.FailureKind == fixtureexecutor.FailureKindDefinition {
+					tc.Error = failure
+					suite.Errors++
+				} else {
+					tc.Failure = failure
+					suite.Failures++
+				}
+			}
+
+			suite.Cases = append(suite.Cases, tc)
+		}
+
+		suite.Tests = len(suite.Cases)
+		suite.Time = formatSeconds(seconds)
+		root.Suites = append(root.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(root); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+
+	if _, err := io.WriteString(w, "\n"); err != nil {
+		return fmt.Errorf("failed to write JUnit report: %w", err)
+	}
+
+	return nil
+}
+
+type jsonReport struct {
+	Total              int              `json:"total"`
+	Passed             int              `json:"passed"`
+	Failed             int              `json:"failed"`
+	Skipped            int              `json:"skipped,omitempty"`
+	Flaky              int              `json:"flaky,omitempty"`
+	AssertionFailures  int              `json:"assertion_failures"`
+	DefinitionFailures int              `json:"definition_failures"`
+	UnknownFailures    int              `json:"unknown_failures"`
+	DurationSeconds    float64          `json:"duration_seconds"`
+	Tests              []jsonTestResult `json:"tests"`
+}
+
+type jsonTestResult struct {
+	Name            string  `json:"name"`
+	File            string  `json:"file,omitempty"`
+	Line            int     `json:"line,omitempty"`
+	Success         bool    `json:"success"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	// QueryDurationSeconds and MaxDurationSeconds are recorded for test cases with max_duration
+	QueryDurationSeconds float64 `json:"query_duration_seconds,omitempty"`
+	MaxDurationSeconds   float64 `json:"max_duration_seconds,omitempty"`
+	FailureKind          string  `json:"failure_kind,omitempty"`
+	Error                string  `json:"error,omitempty"`
+	Diff                 string  `json:"diff,omitempty"`
+	// Plan is recorded by --explain
+	Plan string `json:"plan,omitempty"`
+	// Attempts, Flaky and FlakyError are recorded by --retries
+	Attempts   int    `json:"attempts,omitempty"`
+	Flaky      bool   `json:"flaky,omitempty"`
+	FlakyError string `json:"flaky_error,omitempty"`
+}
+
+// WriteJSONReport writes the summary as a JSON document with one entry per test case.
+func WriteJSONReport(w io.Writer, summary *FixtureTestSummary) error {
+	report := jsonReport{
+		Total:              summary.TotalTests,
+		Passed:             summary.PassedTests,
+		Failed:             summary.FailedTests,
+		Skipped:            summary.SkippedTests,
+		Flaky:              summary.FlakyTests,
+		AssertionFailures:  summary.AssertionFailures,
+		DefinitionFailures: summary.DefinitionFailures,
+		UnknownFailures:    summary.UnknownFailures,
+		DurationSeconds:    summary.TotalDuration.Seconds(),
+		Tests:              make([]jsonTestResult, 0, len(summary.Results)),
+	}
+
+	for _, result := range summary.Results {
+		entry := jsonTestResult{
+			Name:            result.TestName,
+			File:            result.SourceFile,
+			Line:            result.SourceLine,
+			Success:         result.Success,
+			DurationSeconds: result.Duration.Seconds(),
+		}
+
+		if result.TestCase != nil && result.TestCase.MaxDuration > 0 {
+			entry.QueryDurationSeconds = result.QueryDuration.Seconds()
+			entry.MaxDurationSeconds = result.TestCase.MaxDuration.Seconds()
+		}
+
+		if !result.Success {
+			entry.FailureKind = failureKindName(result.FailureKind)
+			entry.Error = errorMessage(result.Error)
+			entry.Diff = diffText(result.Error)
+		}
+
+		if result.Performance != nil && result.Performance.Plan != nil {
+			entry.Plan = result.Performance.Plan.Text()
+		}
+
+		if result.Attempts > 1 {
+			entry.Attempts = result.Attempts
+		}
+
+		if result.Flaky {
+			entry.Flaky = true
+			entry.FlakyError = errorMessage(result.FlakyError)
+		}
+
+		report.Tests = append(report.Tests, entry)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to write JSON report: %w", err)
+	}
+
+	return nil
+}
+
+func formatSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
+
+func failureKindName(kind fixtureexecutor.FailureKind) string {
+	switch kind {
+	case fixtureexecutor.FailureKindAssertion:
+		return "assertion"
+	case fixtureexecutor.FailureKindDefinition:
+		return "definition"
+	default:
+		return "unknown"
+	}
+}
+
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
+
+// diffText renders the expected/actual diff without terminal colors
+func diffText(err error) string {
+	diff, ok := fixtureexecutor.AsDiffError(err)
+	if !ok {
+		return ""
+	}
+
+	return ansiEscapePattern.ReplaceAllString(fixtureexecutor.FormatDiffUnifiedYAML(diff), "")
+}
+
+func failureDetail(err error) string {
+	parts := []string{errorMessage(err)}
+	if diff := diffText(err); diff != "" {
+		parts = append(parts, diff)
+	}
+
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,135 @@
+package testrunner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql/explain"
+	"github.com/shibukawa/snapsql/markdownparser"
+	"github.com/shibukawa/snapsql/testrunner/fixtureexecutor"
+)
+
+var errReportTestBroken = errors.New("broken fixture")
+
+func reportTestSummary() *FixtureTestSummary {
+	return &FixtureTestSummary{
+		TotalTests:         2,
+		PassedTests:        1,
+		FailedTests:        1,
+		DefinitionFailures: 1,
+		TotalDuration:      1500 * time.Millisecond,
+		Results: []FixtureTestResult{
+			{
+				TestName:   "finds user",
+				Success:    true,
+				Duration:   500 * time.Millisecond,
+				SourceFile: "queries/users.snap.md",
+				SourceLine: 12,
+			},
+			{
+				TestName:    "broken fixture",
+				Success:     false,
+				Duration:    time.Second,
+				Error:       errReportTestBroken,
+				FailureKind: fixtureexecutor.FailureKindDefinition,
+				SourceFile:  "queries/users.snap.md",
+				SourceLine:  40,
+			},
+		},
+	}
+}
+
+func TestWriteJUnitReport(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := WriteJUnitReport(&buf, reportTestSummary())
+	assert.NoError(t, err)
+
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, "<?xml"), out)
+	assert.Contains(t, out, `<testsuites name="snapsql" tests="2" failures="0" errors="1" time="1.500">`)
+	assert.Contains(t, out, `<testsuite name="queries/users.snap.md" tests="2" failures="0" errors="1" time="1.500">`)
+	assert.Contains(t, out, `<testcase name="finds user" classname="queries/users.snap.md" file="queries/users.snap.md" line="12" time="0.500"></testcase>`)
+	assert.Contains(t, out, `<error message="broken fixture" type="definition">broken fixture</error>`)
+}
+
+func TestWriteJSONReport(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := WriteJSONReport(&buf, reportTestSummary())
+	assert.NoError(t, err)
+
+	var report jsonReport
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, 2, report.Total)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, 2, len(report.Tests))
+	assert.Equal(t, jsonTestResult{
+		Name:            "broken fixture",
+		File:            "queries/users.snap.md",
+		Line:            40,
+		DurationSeconds: 1,
+		FailureKind:     "definition",
+		Error:           "broken fixture",
+	}, report.Tests[1])
+}
+
+func TestWriteJSONReportIncludesPlan(t *testing.T) {
+	summary := reportTestSummary()
+	summary.Results[0].Performance = &explain.PerformanceEvaluation{
+		Plan: &explain.PlanDocument{Dialect: "sqlite", RawText: "SCAN users"},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteJSONReport(&buf, summary))
+
+	var report jsonReport
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, "SCAN users", report.Tests[0].Plan)
+	assert.Equal(t, "", report.Tests[1].Plan)
+}
+
+func TestWriteJSONReportIncludesQueryBudget(t *testing.T) {
+	summary := reportTestSummary()
+	summary.Results[0].TestCase = &markdownparser.TestCase{MaxDuration: 50 * time.Millisecond}
+	summary.Results[0].QueryDuration = 20 * time.Millisecond
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteJSONReport(&buf, summary))
+
+	var report jsonReport
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, 0.02, report.Tests[0].QueryDurationSeconds)
+	assert.Equal(t, 0.05, report.Tests[0].MaxDurationSeconds)
+	assert.Equal(t, 0.0, report.Tests[1].MaxDurationSeconds)
+	assert.Equal(t, "query 20.00ms / max 50ms", formatQueryBudget(summary.Results[0]))
+	assert.Equal(t, "", formatQueryBudget(summary.Results[1]))
+}
+
+func TestReportsMarkFlakyTests(t *testing.T) {
+	summary := reportTestSummary()
+	summary.FlakyTests = 1
+	summary.Results[0].Attempts = 2
+	summary.Results[0].Flaky = true
+	summary.Results[0].FlakyError = errReportTestBroken
+
+	var junit bytes.Buffer
+	assert.NoError(t, WriteJUnitReport(&junit, summary))
+	assert.Contains(t, junit.String(), `<flakyFailure message="broken fixture" type="flaky">broken fixture</flakyFailure>`)
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteJSONReport(&buf, summary))
+
+	var report jsonReport
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, 1, report.Flaky)
+	assert.Equal(t, 2, report.Tests[0].Attempts)
+	assert.True(t, report.Tests[0].Flaky)
+	assert.Equal(t, "broken fixture", report.Tests[0].FlakyError)
+	assert.False(t, report.Tests[1].Flaky)
+}
@@ -0,0 +1,110 @@
+package testrunner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	snapsql "github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/intermediate"
+	"github.com/shibukawa/snapsql/markdownparser"
+	"github.com/shibukawa/snapsql/query"
+	"github.com/shibukawa/snapsql/testrunner/fixtureexecutor"
+)
+
+// ErrStepTemplateNotFound is returned when the template of a scenario step does not exist
+var ErrStepTemplateNotFound = errors.New("step template not found")
+
+// stepTemplateExtensions are tried in order for a step template written without an extension
+var stepTemplateExtensions = []string{".snap.sql", ".snap.md"}
+
+// prepareSteps renders the SQL of each step of a scenario test case. A step without a template
+// runs the test file's own query (own); other templates are resolved relative to the test file and
+// compiled once per run (templates caches them by path).
+func (ftr *FixtureTestRunner) prepareSteps(tc *markdownparser.TestCase, filePath string, own *intermediate.IntermediateFormat, config *snapsql.Config, templates map[string]*intermediate.IntermediateFormat) error {
+	for i := range tc.Steps {
+		step := &tc.Steps[i]
+
+		format := own
+		if step.Template != "" {
+			path, err := resolveStepTemplate(filePath, step.Template)
+			if err != nil {
+				return fmt.Errorf("step %d: %w", i+1, err)
+			}
+
+			format = templates[path]
+			if format == nil {
+				if format, err = ftr.compileStepTemplate(path, config); err != nil {
+					return fmt.Errorf("step %d: failed to compile %s: %w", i+1, step.Template, err)
+				}
+
+				templates[path] = format
+			}
+		}
+
+		if err := fixtureexecutor.NormalizeParametersAt(step.Parameters, tc.Now); err != nil {
+			return fmt.Errorf("step %d: failed to normalize parameters: %w", i+1, err)
+		}
+
+		generator := query.NewSQLGenerator(format, config.Dialect)
+		generator.SetNow(tc.Now)
+
+		finalSQL, args, err := generator.Generate(step.Parameters)
+		if err != nil {
+			return fmt.Errorf("step %d: failed to render SQL: %w", i+1, err)
+		}
+
+		step.PreparedSQL = finalSQL
+		step.SQLArgs = args
+		step.ResultOrdered = format.HasOrderedResult
+	}
+
+	return nil
+}
+
+// resolveStepTemplate returns the path of a step template. Relative paths are resolved from the
+// directory of the test file; ".snap.sql" and ".snap.md" are tried for a name without an extension.
+func resolveStepTemplate(filePath, template string) (string, error) {
+	path := filepath.FromSlash(template)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(filePath), path)
+	}
+
+	candidates := []string{path}
+	if filepath.Ext(path) == "" {
+		candidates = nil
+		for _, ext := range stepTemplateExtensions {
+			candidates = append(candidates, path+ext)
+		}
+	}
+
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrStepTemplateNotFound, template)
+}
+
+// compileStepTemplate compiles a .snap.sql or .snap.md template referenced by a step
+func (ftr *FixtureTestRunner) compileStepTemplate(path string, config *snapsql.Config) (*intermediate.IntermediateFormat, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if !strings.HasSuffix(path, ".md") {
+		return intermediate.GenerateFromSQL(file, nil, path, ftr.projectRoot, ftr.tableInfo, config)
+	}
+
+	doc, err := markdownparser.Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse markdown: %w", err)
+	}
+
+	return intermediate.GenerateFromMarkdown(doc, path, ftr.projectRoot, nil, ftr.tableInfo, config)
+}
@@ -0,0 +1,33 @@
+package testrunner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestResolveStepTemplate(t *testing.T) {
+	dir := t.TempDir()
+	testFile := filepath.Join(dir, "workflow.snap.md")
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "notifications"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notifications", "create.snap.sql"), []byte("SELECT 1"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "cancel.snap.md"), []byte("# Cancel"), 0o644))
+
+	path, err := resolveStepTemplate(testFile, "notifications/create.snap.sql")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "notifications", "create.snap.sql"), path)
+
+	path, err = resolveStepTemplate(testFile, "notifications/create")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "notifications", "create.snap.sql"), path)
+
+	path, err = resolveStepTemplate(testFile, "cancel")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "cancel.snap.md"), path)
+
+	_, err = resolveStepTemplate(testFile, "missing")
+	assert.IsError(t, err, ErrStepTemplateNotFound)
+}
@@ -0,0 +1,221 @@
+package testrunner
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/shibukawa/snapsql/markdownparser"
+)
+
+// slowQuerySQLWidth is the number of characters of the worst SQL shown in the summary table
+const slowQuerySQLWidth = 60
+
+// SlowQueryStat aggregates the test cases of one query function whose main query took at
+// least the slow query threshold
+type SlowQueryStat struct {
+	Function   string
+	SourceFile string
+	Count      int
+	P50        time.Duration
+	P95        time.Duration
+	Worst      time.Duration
+	// WorstTest and WorstSQL identify the slowest execution
+	WorstTest string
+	WorstSQL  string
+}
+
+// CollectSlowQueries groups the results whose main query took at least the slow query threshold
+// by function and ranks the groups by p95, slowest first. The threshold of a test case
+// (performance.slow_query_threshold) takes precedence over defaultThreshold; results without
+// a threshold are not collected.
+func CollectSlowQueries(results []FixtureTestResult, defaultThreshold time.Duration) []SlowQueryStat {
+	type group struct {
+		stat      SlowQueryStat
+		durations []time.Duration
+	}
+
+	var order []*group
+
+	groups := make(map[string]*group)
+
+	for _, result := range results {
+		threshold := defaultThreshold
+		if result.TestCase != nil && result.TestCase.SlowQueryThreshold > 0 {
+			threshold = result.TestCase.SlowQueryThreshold
+		}
+
+		if threshold <= 0 || result.QueryDuration <= 0 || result.QueryDuration < threshold {
+			continue
+		}
+
+		function := result.Function
+		if function == "" {
+			function = functionNameFromPath(result.SourceFile)
+		}
+
+		key := result.SourceFile + "\x00" + function
+
+		g, ok := groups[key]
+		if !ok {
+			g = &group{stat: SlowQueryStat{Function: function, SourceFile: result.SourceFile}}
+			groups[key] = g
+			order = append(order, g)
+		}
+
+		g.durations = append(g.durations, result.QueryDuration)
+
+		if result.QueryDuration > g.stat.Worst {
+			g.stat.Worst = result.QueryDuration
+			g.stat.WorstTest = result.TestName
+
+			if result.TestCase != nil {
+				g.stat.WorstSQL = result.TestCase.PreparedSQL
+			}
+		}
+	}
+
+	stats := make([]SlowQueryStat, 0, len(order))
+	for _, g := range order {
+		sort.Slice(g.durations, func(i, j int) bool { return g.durations[i] < g.durations[j] })
+
+		g.stat.Count = len(g.durations)
+		g.stat.P50 = percentile(g.durations, 50)
+		g.stat.P95 = percentile(g.durations, 95)
+		stats = append(stats, g.stat)
+	}
+
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].P95 != stats[j].P95 {
+			return stats[i].P95 > stats[j].P95
+		}
+
+		return stats[i].Count > stats[j].Count
+	})
+
+	return stats
+}
+
+// percentile returns the nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+// functionNameFromPath derives the function name from a .snap.md file path
+func functionNameFromPath(path string) string {
+	name := filepath.Base(filepath.FromSlash(path))
+
+	return strings.TrimSuffix(strings.TrimSuffix(name, ".md"), ".snap")
+}
+
+// documentFunctionName returns the function name of a test file: the function_name of the
+// front matter, or the file name
+func documentFunctionName(path string, doc *markdownparser.SnapSQLDocument) string {
+	if doc != nil {
+		if name, ok := doc.Metadata["function_name"].(string); ok && strings.TrimSpace(name) != "" {
+			return strings.TrimSpace(name)
+		}
+	}
+
+	return functionNameFromPath(path)
+}
+
+// PrintSlowQueryReport prints the ranked slow query table shown at the end of a test run
+func PrintSlowQueryReport(w io.Writer, stats []SlowQueryStat, threshold time.Duration) {
+	if len(stats) == 0 {
+		return
+	}
+
+	if threshold > 0 {
+		fmt.Fprintf(w, "\nSlow queries (threshold %s):\n", threshold)
+	} else {
+		fmt.Fprintln(w, "\nSlow queries:")
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  #\tFUNCTION\tCOUNT\tP50\tP95\tWORST SQL")
+
+	for i, stat := range stats {
+		fmt.Fprintf(tw, "  %d\t%s\t%d\t%s\t%s\t%s\n",
+			i+1, stat.Function, stat.Count, formatDuration(stat.P50), formatDuration(stat.P95), abbreviateSQL(stat.WorstSQL, slowQuerySQLWidth))
+	}
+
+	tw.Flush()
+}
+
+// abbreviateSQL collapses whitespace so that the SQL fits on one line and cuts it at width
+func abbreviateSQL(sql string, width int) string {
+	sql = strings.Join(strings.Fields(sql), " ")
+
+	runes := []rune(sql)
+	if len(runes) <= width {
+		return sql
+	}
+
+	return string(runes[:width-3]) + "..."
+}
+
+type slowQueryReport struct {
+	GeneratedAt      time.Time            `json:"generated_at"`
+	ThresholdSeconds float64              `json:"threshold_seconds,omitempty"`
+	Queries          []slowQueryReportRow `json:"queries"`
+}
+
+type slowQueryReportRow struct {
+	Rank         int     `json:"rank"`
+	Function     string  `json:"function"`
+	File         string  `json:"file,omitempty"`
+	Count        int     `json:"count"`
+	P50Seconds   float64 `json:"p50_seconds"`
+	P95Seconds   float64 `json:"p95_seconds"`
+	WorstSeconds float64 `json:"worst_seconds"`
+	WorstTest    string  `json:"worst_test,omitempty"`
+	WorstSQL     string  `json:"worst_sql,omitempty"`
+}
+
+// WriteSlowQueryReport writes the slow query ranking of the summary as a JSON document.
+// It carries the time of the run so that successive reports can be compared.
+func WriteSlowQueryReport(w io.Writer, summary *FixtureTestSummary) error {
+	report := slowQueryReport{
+		GeneratedAt:      time.Now().UTC(),
+		ThresholdSeconds: summary.SlowQueryThreshold.Seconds(),
+		Queries:          make([]slowQueryReportRow, 0, len(summary.SlowQueries)),
+	}
+
+	for i, stat := range summary.SlowQueries {
+		report.Queries = append(report.Queries, slowQueryReportRow{
+			Rank:         i + 1,
+			Function:     stat.Function,
+			File:         stat.SourceFile,
+			Count:        stat.Count,
+			P50Seconds:   stat.P50.Seconds(),
+			P95Seconds:   stat.P95.Seconds(),
+			WorstSeconds: stat.Worst.Seconds(),
+			WorstTest:    stat.WorstTest,
+			WorstSQL:     stat.WorstSQL,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("failed to write slow query report: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package testrunner
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/shibukawa/snapsql/markdownparser"
+)
+
+func slowQueryResult(function, name string, d time.Duration, sql string) FixtureTestResult {
+	return FixtureTestResult{
+		TestName:      name,
+		Function:      function,
+		SourceFile:    "queries/" + function + ".snap.md",
+		QueryDuration: d,
+		TestCase:      &markdownparser.TestCase{Name: name, PreparedSQL: sql},
+	}
+}
+
+func TestCollectSlowQueries(t *testing.T) {
+	results := []FixtureTestResult{
+		slowQueryResult("list_orders", "all", 120*time.Millisecond, "SELECT *\n  FROM orders"),
+		slowQueryResult("list_orders", "open", 300*time.Millisecond, "SELECT * FROM orders WHERE status = 'open'"),
+		slowQueryResult("list_orders", "fast", 10*time.Millisecond, "SELECT 1"),
+		slowQueryResult("get_user", "by id", 150*time.Millisecond, "SELECT * FROM users WHERE id = $1"),
+		slowQueryResult("list_orders", "not measured", 0, "SELECT 1"),
+	}
+
+	// A test case's own threshold takes precedence
+	results = append(results, slowQueryResult("report", "monthly", 2*time.Second, "SELECT sum(total) FROM orders"))
+	results[len(results)-1].TestCase.SlowQueryThreshold = 5 * time.Second
+
+	stats := CollectSlowQueries(results, 100*time.Millisecond)
+	assert.Equal(t, 2, len(stats))
+
+	assert.Equal(t, SlowQueryStat{
+		Function:   "list_orders",
+		SourceFile: "queries/list_orders.snap.md",
+		Count:      2,
+		P50:        120 * time.Millisecond,
+		P95:        300 * time.Millisecond,
+		Worst:      300 * time.Millisecond,
+		WorstTest:  "open",
+		WorstSQL:   "SELECT * FROM orders WHERE status = 'open'",
+	}, stats[0])
+	assert.Equal(t, "get_user", stats[1].Function)
+	assert.Equal(t, 1, stats[1].Count)
+
+	assert.Equal(t, 0, len(CollectSlowQueries(results, 0)))
+}
+
+func TestPercentile(t *testing.T) {
+	durations := make([]time.Duration, 20)
+	for i := range durations {
+		durations[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	assert.Equal(t, 10*time.Millisecond, percentile(durations, 50))
+	assert.Equal(t, 19*time.Millisecond, percentile(durations, 95))
+	assert.Equal(t, time.Millisecond, percentile(durations[:1], 95))
+	assert.Equal(t, time.Duration(0), percentile(nil, 50))
+}
+
+func TestPrintSlowQueryReport(t *testing.T) {
+	stats := []SlowQueryStat{{
+		Function: "list_orders",
+		Count:    2,
+		P50:      120 * time.Millisecond,
+		P95:      300 * time.Millisecond,
+		WorstSQL: "SELECT id, customer_id, status, total, created_at\n  FROM orders\n WHERE status = 'open'",
+	}}
+
+	var buf bytes.Buffer
+	PrintSlowQueryReport(&buf, stats, 100*time.Millisecond)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 3, len(lines))
+	assert.Equal(t, "Slow queries (threshold 100ms):", lines[0])
+	assert.Contains(t, lines[1], "FUNCTION")
+	assert.Contains(t, lines[2], "list_orders")
+	assert.Contains(t, lines[2], "120.00ms")
+	assert.Contains(t, lines[2], "SELECT id, customer_id, status, total, created_at FROM or...")
+
+	buf.Reset()
+	PrintSlowQueryReport(&buf, nil, 100*time.Millisecond)
+	assert.Equal(t, "", buf.String())
+}
+
+func TestWriteSlowQueryReport(t *testing.T) {
+	summary := &FixtureTestSummary{
+		SlowQueryThreshold: 100 * time.Millisecond,
+		SlowQueries: []SlowQueryStat{{
+			Function:   "list_orders",
+			SourceFile: "queries/list_orders.snap.md",
+			Count:      2,
+			P50:        120 * time.Millisecond,
+			P95:        300 * time.Millisecond,
+			Worst:      300 * time.Millisecond,
+			WorstTest:  "open",
+			WorstSQL:   "SELECT * FROM orders",
+		}},
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteSlowQueryReport(&buf, summary))
+
+	var report slowQueryReport
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.False(t, report.GeneratedAt.IsZero())
+	assert.Equal(t, 0.1, report.ThresholdSeconds)
+	assert.Equal(t, []slowQueryReportRow{{
+		Rank:         1,
+		Function:     "list_orders",
+		File:         "queries/list_orders.snap.md",
+		Count:        2,
+		P50Seconds:   0.12,
+		P95Seconds:   0.3,
+		WorstSeconds: 0.3,
+		WorstTest:    "open",
+		WorstSQL:     "SELECT * FROM orders",
+	}}, report.Queries)
+}
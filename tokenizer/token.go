@@ -187,6 +187,9 @@ const (
 	// FROM represents FROM keyword.
 	FROM
 
+	// MERGE represents MERGE keyword.
+	MERGE
+
 	// FOR represents FOR row locking keyword.
 	FOR
 	// SHARE represents SHARE keyword.
@@ -230,6 +233,10 @@ const (
 	OTHER
 	// UNION represents UNION keyword.
 	UNION
+	// INTERSECT represents INTERSECT keyword.
+	INTERSECT
+	// EXCEPT represents EXCEPT keyword.
+	EXCEPT
 
 	// CASE represents CASE expression keyword.
 	CASE
@@ -382,10 +389,14 @@ func (t TokenType) String() string {
 		return "SELECT"
 	case INSERT:
 		return "INSERT"
+	case INTO:
+		return "INTO"
 	case UPDATE:
 		return "UPDATE"
 	case DELETE:
 		return "DELETE"
+	case MERGE:
+		return "MERGE"
 	case FROM:
 		return "FROM"
 	case WHERE:
@@ -406,6 +417,10 @@ func (t TokenType) String() string {
 		return "RETURNING"
 	case UNION:
 		return "UNION"
+	case INTERSECT:
+		return "INTERSECT"
+	case EXCEPT:
+		return "EXCEPT"
 	case ALL:
 		return "ALL"
 	case DISTINCT:
@@ -524,9 +539,10 @@ func (t Token) String() string {
 
 // Directive represents a SnapSQL inline directive extracted from comments.
 type Directive struct {
-	Type        string // "if", "elseif", "else", "for", "end", "const", "variable", "system_value"
+	Type        string // "if", "elseif", "else", "for", "end", "set_optional", "ident", "orderby", "const", "variable", "system_value"
 	NextIndex   int    // Index of next directive token in block chain (if->elseif->else->end, for->end)
 	DummyRange  []int
-	Condition   string // Condition expression for if/elseif directives
-	SystemField string // System field name for "system_value" type
+	Condition   string   // Condition expression for if/elseif directives
+	SystemField string   // System field name for "system_value" type
+	Allowed     []string // Allowlisted items for "ident" and "orderby" directives (nil when the list is missing)
 }
@@ -384,6 +384,7 @@ var keywordLikeTokenTypeMap = map[string]TokenType{
 	"CONFLICT": CONFLICT,
 
 	"DELETE": DELETE,
+	"MERGE":  MERGE,
 
 	"WITH":      WITH,
 	"RECURSIVE": RECURSIVE,
@@ -393,6 +394,10 @@ var keywordLikeTokenTypeMap = map[string]TokenType{
 	"DISTINCT": DISTINCT,
 	"ALL":      ALL,
 
+	"UNION":     UNION,
+	"INTERSECT": INTERSECT,
+	"EXCEPT":    EXCEPT,
+
 	"JOIN":    JOIN,
 	"INNER":   INNER,
 	"OUTER":   OUTER,
@@ -700,6 +705,32 @@ func (t *tokenizer) newToken(tokenType TokenType, value string) Token {
 	}
 }
 
+// parseIdentDirective splits the body of /*# ident expr in [a, b, c] */ (or /*# orderby ... */) into
+// the expression and the allowlisted items. Quotes around the identifiers are removed. The allowlist is nil
+// when "in [...]" is missing so that the parser can report it.
+func parseIdentDirective(body string) (string, []string) {
+	idx := strings.LastIndex(body, " in ")
+	if idx < 0 || !strings.HasSuffix(body, "]") {
+		return body, nil
+	}
+
+	list := strings.TrimSpace(body[idx+4:])
+	if !strings.HasPrefix(list, "[") {
+		return body, nil
+	}
+
+	allowed := []string{}
+
+	for item := range strings.SplitSeq(list[1:len(list)-1], ",") {
+		item = strings.Trim(strings.TrimSpace(item), "'\"`")
+		if item != "" {
+			allowed = append(allowed, item)
+		}
+	}
+
+	return strings.TrimSpace(body[:idx]), allowed
+}
+
 // parseSnapSQLDirective parses SnapSQL extension directives
 func (t *tokenizer) parseSnapSQLDirective(comment string) *Directive {
 	trimmed := strings.TrimSpace(comment)
@@ -733,6 +764,14 @@ func (t *tokenizer) parseSnapSQLDirective(comment string) *Directive {
 			return &Directive{Type: "for", Condition: condition}
 		} else if content == "end" {
 			return &Directive{Type: "end"}
+		} else if content == "set_optional" {
+			return &Directive{Type: "set_optional"}
+		} else if strings.HasPrefix(content, "ident ") {
+			condition, allowed := parseIdentDirective(strings.TrimSpace(content[6:]))
+			return &Directive{Type: "ident", Condition: condition, Allowed: allowed}
+		} else if strings.HasPrefix(content, "orderby ") {
+			condition, allowed := parseIdentDirective(strings.TrimSpace(content[8:]))
+			return &Directive{Type: "orderby", Condition: condition, Allowed: allowed}
 		}
 	}
 
@@ -187,6 +187,27 @@ func TestSnapSQLDirectives(t *testing.T) {
 			isDirective:   true,
 			directiveType: "end",
 		},
+		{
+			name:          "set_optional directive",
+			input:         "/*# set_optional */",
+			expectedType:  BLOCK_COMMENT,
+			isDirective:   true,
+			directiveType: "set_optional",
+		},
+		{
+			name:          "ident directive",
+			input:         "/*# ident sort in [name, created_at] */",
+			expectedType:  BLOCK_COMMENT,
+			isDirective:   true,
+			directiveType: "ident",
+		},
+		{
+			name:          "orderby directive",
+			input:         "/*# orderby sort in [name, created_at desc] */",
+			expectedType:  BLOCK_COMMENT,
+			isDirective:   true,
+			directiveType: "orderby",
+		},
 	}
 
 	for _, test := range tests {
@@ -727,3 +748,33 @@ func TestModuloToken(t *testing.T) {
 	assert.Equal(t, MODULO, moduloToken.Type)
 	assert.Equal(t, "%", moduloToken.Value)
 }
+
+func TestIdentDirective(t *testing.T) {
+	tests := []struct {
+		input     string
+		condition string
+		allowed   []string
+	}{
+		{input: "/*# ident sort in [name, created_at] */", condition: "sort", allowed: []string{"name", "created_at"}},
+		{input: "/*# ident params.sort in ['u.name', \"u.id\"] */", condition: "params.sort", allowed: []string{"u.name", "u.id"}},
+		{input: "/*# ident sort in [] */", condition: "sort", allowed: []string{}},
+		{input: "/*# ident sort */", condition: "sort"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			tokens, err := Tokenize(tt.input)
+			assert.NoError(t, err)
+			assert.NotZero(t, tokens[0].Directive)
+			assert.Equal(t, "ident", tokens[0].Directive.Type)
+			assert.Equal(t, tt.condition, tokens[0].Directive.Condition)
+			assert.Equal(t, tt.allowed, tokens[0].Directive.Allowed)
+		})
+	}
+
+	tokens, err := Tokenize("/*# orderby sort in [name, created_at desc] */")
+	assert.NoError(t, err)
+	assert.Equal(t, "orderby", tokens[0].Directive.Type)
+	assert.Equal(t, "sort", tokens[0].Directive.Condition)
+	assert.Equal(t, []string{"name", "created_at desc"}, tokens[0].Directive.Allowed)
+}
@@ -33,6 +33,8 @@ func (d *DMLInferenceEngine) InferDMLStatementType(stmt parser.StatementNode) ([
 		return d.inferUpdateStatement(s)
 	case *parser.DeleteFromStatement:
 		return d.inferDeleteStatement(s)
+	case *parser.MergeStatement:
+		return d.inferMergeStatement(s)
 	default:
 		return nil, fmt.Errorf("%w: %T", snapsql.ErrUnsupportedDMLStatementType, stmt)
 	}
@@ -155,6 +157,40 @@ func (d *DMLInferenceEngine) inferDeleteStatement(stmt *parser.DeleteFromStateme
 	return fields, nil
 }
 
+// inferMergeStatement handles MERGE statement type inference
+func (d *DMLInferenceEngine) inferMergeStatement(stmt *parser.MergeStatement) ([]*InferredFieldInfo, error) {
+	if stmt.Into == nil {
+		return nil, snapsql.ErrMergeStatementMissingInto
+	}
+
+	// Handle RETURNING clause if present (PostgreSQL 17+)
+	if stmt.Returning != nil {
+		fields, err := d.inferReturningClause(stmt.Returning, stmt.Into.Table.TableName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to infer RETURNING clause: %w", err)
+		}
+
+		if len(fields) > 0 {
+			return fields, nil
+		}
+	}
+
+	return []*InferredFieldInfo{
+		{
+			Name: "affected_rows",
+			Type: &TypeInfo{
+				BaseType:   "int",
+				IsNullable: false,
+			},
+			Source: FieldSource{
+				Type:       "function",
+				Expression: "MERGE statement affected rows",
+			},
+			IsGenerated: true,
+		},
+	}, nil
+}
+
 // inferReturningClause handles RETURNING clause type inference
 func (d *DMLInferenceEngine) inferReturningClause(returning *parser.ReturningClause, targetTable string) ([]*InferredFieldInfo, error) {
 	var fields []*InferredFieldInfo
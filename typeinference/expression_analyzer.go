@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/shibukawa/snapsql/parser"
 	"github.com/shibukawa/snapsql/tokenizer"
 )
 
@@ -299,11 +300,12 @@ func (a *ExpressionCastAnalyzer) inferSingleToken(token tokenizer.Token) (*TypeI
 		return &TypeInfo{BaseType: "string", IsNullable: false}, nil
 
 	case tokenizer.IDENTIFIER:
-		// Try to resolve as column reference
+		// Try to resolve as column reference of the tables in FROM clause
 		if a.engine != nil && a.engine.schemaResolver != nil {
-			// Simple column resolution - this is a placeholder
-			// In reality, we'd need more context about tables
-			return &TypeInfo{BaseType: "any", IsNullable: true}, nil
+			fieldType, _, err := a.engine.inferSingleFieldType(&parser.SelectField{FieldKind: parser.SingleField, OriginalField: token.Value})
+			if err == nil && fieldType != nil {
+				return fieldType, nil
+			}
 		}
 
 		return &TypeInfo{BaseType: "any", IsNullable: true}, nil
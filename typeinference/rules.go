@@ -317,6 +317,8 @@ var FunctionTypeRules = map[string]FunctionInferenceRule{
 	"ROW_NUMBER":  WindowIntRule,
 	"RANK":        WindowIntRule,
 	"DENSE_RANK":  WindowIntRule,
+	"NTILE":       WindowIntRule,
+	"NTH_VALUE":   WindowArgRule,
 	"LAG":         WindowArgRule,
 	"LEAD":        WindowArgRule,
 	"FIRST_VALUE": WindowArgRule,
@@ -0,0 +1,67 @@
+package typeinference
+
+import (
+	"fmt"
+
+	"github.com/shibukawa/snapsql"
+	"github.com/shibukawa/snapsql/parser"
+)
+
+// mergeSetOperationTypes infers the columns of every UNION / INTERSECT / EXCEPT branch and merges
+// them into the columns of the first branch. Column names always come from the first branch.
+func (e *TypeInferenceEngine2) mergeSetOperationTypes(selectStmt *parser.SelectStatement, fields []*InferredFieldInfo) ([]*InferredFieldInfo, error) {
+	for i, op := range selectStmt.SetOperations {
+		branch := &parser.SelectStatement{Select: op.Select, From: op.From}
+		e.extractTableAliases(branch)
+
+		branchFields, err := e.inferSelectStatement(branch)
+		if err != nil {
+			return nil, fmt.Errorf("branch %d of %s: %w", i+2, op.Operator.Operator, err)
+		}
+
+		if len(branchFields) != len(fields) {
+			return nil, fmt.Errorf("%w: branch %d of %s returns %d columns, but the first branch returns %d",
+				snapsql.ErrSetOperationColumnMismatch, i+2, op.Operator.Operator, len(branchFields), len(fields))
+		}
+
+		for j, field := range fields {
+			field.Type = e.mergeSetOperationColumn(field, branchFields[j])
+
+			// 別テーブル由来の列は特定の列に紐付けられない
+			if field.Source.Table != branchFields[j].Source.Table || field.Source.Column != branchFields[j].Source.Column {
+				field.Source = FieldSource{Type: "expression", Expression: op.Operator.Operator}
+			}
+		}
+	}
+
+	// 後続ブランチで上書きしたエイリアス情報を先頭ブランチに戻す
+	e.extractTableAliases(selectStmt)
+
+	return fields, nil
+}
+
+// mergeSetOperationColumn returns the common type of the same column of two branches
+func (e *TypeInferenceEngine2) mergeSetOperationColumn(left, right *InferredFieldInfo) *TypeInfo {
+	l, r := left.Type, right.Type
+	isNullable := l.IsNullable || r.IsNullable
+
+	switch {
+	case l.BaseType == r.BaseType:
+		merged := *l
+		merged.IsNullable = isNullable
+
+		return &merged
+	case l.BaseType == "any":
+		return &TypeInfo{BaseType: r.BaseType, IsNullable: isNullable}
+	case r.BaseType == "any":
+		return &TypeInfo{BaseType: l.BaseType, IsNullable: isNullable}
+	case isNumericType(l.BaseType) && isNumericType(r.BaseType):
+		return &TypeInfo{BaseType: promoteNumericTypes(l.BaseType, r.BaseType), IsNullable: isNullable}
+	case isDateTimeType(l.BaseType) && isDateTimeType(r.BaseType):
+		return &TypeInfo{BaseType: "timestamp", IsNullable: isNullable}
+	}
+
+	e.addWarning(fmt.Sprintf("column %s has incompatible types across set operation branches (%s and %s)", left.Name, l.BaseType, r.BaseType))
+
+	return &TypeInfo{BaseType: "any", IsNullable: isNullable}
+}
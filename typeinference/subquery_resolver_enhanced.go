@@ -2,6 +2,7 @@ package typeinference
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/shibukawa/snapsql"
@@ -17,6 +18,8 @@ type EnhancedSubqueryResolver struct {
 	dialect            snapsql.Dialect                          // Database dialect
 	typeCache          map[string][]*InferredFieldInfo          // Cache subquery field types by dependency node ID
 	fieldResolverCache map[string]map[string]*InferredFieldInfo // nodeID -> fieldName -> type info
+	resolved           bool                                     // ResolveSubqueryTypesComplete has been run
+	resolveErr         error                                    // Result of the first resolution
 }
 
 // NewEnhancedSubqueryResolver creates an enhanced subquery resolver
@@ -35,8 +38,18 @@ func NewEnhancedSubqueryResolver(
 }
 
 // ResolveSubqueryTypesComplete performs complete subquery type resolution
-// including field-level type inference and table reference resolution
+// including field-level type inference and table reference resolution.
+// The resolution runs only once; later calls return the first result.
 func (esr *EnhancedSubqueryResolver) ResolveSubqueryTypesComplete() error {
+	if !esr.resolved {
+		esr.resolved = true
+		esr.resolveErr = esr.resolveSubqueryTypes()
+	}
+
+	return esr.resolveErr
+}
+
+func (esr *EnhancedSubqueryResolver) resolveSubqueryTypes() error {
 	if esr.statementNode == nil {
 		return nil
 	}
@@ -181,8 +194,89 @@ func (esr *EnhancedSubqueryResolver) inferSelectSubquery(
 	// Add available subquery tables from dependencies
 	esr.addDependentSubqueryTables(subEngine, node)
 
+	if node.NodeType == parser.DependencyFromSubquery {
+		esr.addLateralOuterTables(subEngine, node.Alias)
+	}
+
 	// Perform SELECT type inference
-	return subEngine.inferSelectStatement(stmt)
+	fields, err := subEngine.inferSelectStatement(stmt)
+	if err != nil {
+		return nil, err
+	}
+
+	esr.applyCTEColumnList(node.ID, fields)
+
+	if len(stmt.SetOperations) == 0 {
+		return fields, nil
+	}
+
+	// 再帰 CTE の後続ブランチは CTE 自身を参照するので、先頭ブランチの列を仮想テーブルとして登録してから推論する
+	esr.typeCache[node.ID] = fields
+	subEngine.enhancedResolver = esr
+
+	defer func() {
+		subEngine.enhancedResolver = nil
+	}()
+
+	return subEngine.mergeSetOperationTypes(stmt, fields)
+}
+
+// addLateralOuterTables makes the tables before a LATERAL subquery of the main query visible inside it
+func (esr *EnhancedSubqueryResolver) addLateralOuterTables(subEngine *TypeInferenceEngine2, alias string) {
+	mainStmt, ok := esr.statementNode.(*parser.SelectStatement)
+	if !ok || mainStmt.From == nil {
+		return
+	}
+
+	for i, table := range mainStmt.From.Tables {
+		if table.Name != alias {
+			continue
+		}
+
+		if !table.Lateral {
+			return
+		}
+
+		for _, outer := range mainStmt.From.Tables[:i] {
+			if outer.TableName == "" {
+				continue
+			}
+
+			// サブクエリ内の同名のエイリアスを優先する
+			if _, exists := subEngine.context.TableAliases[outer.Name]; exists || slices.Contains(subEngine.context.CurrentTables, outer.Name) {
+				continue
+			}
+
+			if outer.Name != outer.TableName {
+				subEngine.context.TableAliases[outer.Name] = outer.TableName
+			}
+
+			subEngine.context.OuterTables = appendIfMissing(subEngine.context.OuterTables, outer.Name, outer.TableName)
+		}
+
+		return
+	}
+}
+
+// applyCTEColumnList renames the fields by the column list of "name (col1, col2) AS (...)"
+func (esr *EnhancedSubqueryResolver) applyCTEColumnList(nodeID string, fields []*InferredFieldInfo) {
+	with := esr.statementNode.CTE()
+	if with == nil {
+		return
+	}
+
+	for _, def := range with.CTEs {
+		if def.Name != nodeID || len(def.Columns) == 0 {
+			continue
+		}
+
+		for i, field := range fields {
+			if i < len(def.Columns) {
+				field.Name = def.Columns[i]
+				field.Alias = def.Columns[i]
+			}
+		}
+	}
 }
 
 // addDependentSubqueryTables adds tables from dependent subqueries to the engine context
@@ -190,7 +284,7 @@ func (esr *EnhancedSubqueryResolver) addDependentSubqueryTables(subEngine *TypeI
 	for _, depID := range node.Dependencies {
 		if _, exists := esr.typeCache[depID]; exists {
 			// Add this dependency as an available "table"
-			cteName := esr.extractCTENameFromNodeID(depID)
+			cteName := esr.tableNameOf(depID)
 			if cteName != "" {
 				subEngine.context.CurrentTables = append(subEngine.context.CurrentTables, cteName)
 			}
@@ -260,6 +354,18 @@ func (esr *EnhancedSubqueryResolver) extractCTENameFromNodeID(nodeID string) str
 	return nodeID
 }
 
+// tableNameOf returns the name by which the query refers to the subquery node:
+// the alias for a FROM clause subquery and the CTE name for a CTE
+func (esr *EnhancedSubqueryResolver) tableNameOf(nodeID string) string {
+	if depGraph := esr.statementNode.GetSubqueryDependencies(); depGraph != nil {
+		if node := depGraph.GetNode(nodeID); node != nil && node.Alias != "" {
+			return node.Alias
+		}
+	}
+
+	return esr.extractCTENameFromNodeID(nodeID)
+}
+
 // cacheFieldMapping caches field-level type information for quick lookup
 func (esr *EnhancedSubqueryResolver) cacheFieldMapping(nodeID string, fieldInfos []*InferredFieldInfo) {
 	fieldMap := make(map[string]*InferredFieldInfo)
@@ -277,8 +383,7 @@ func (esr *EnhancedSubqueryResolver) cacheFieldMapping(nodeID string, fieldInfos
 func (esr *EnhancedSubqueryResolver) ResolveSubqueryFieldType(subqueryName, fieldName string) (*InferredFieldInfo, bool) {
 	// Find the subquery node by name
 	for nodeID, fieldMap := range esr.fieldResolverCache {
-		cteName := esr.extractCTENameFromNodeID(nodeID)
-		if cteName == subqueryName {
+		if esr.tableNameOf(nodeID) == subqueryName {
 			if fieldInfo, exists := fieldMap[fieldName]; exists {
 				return fieldInfo, true
 			}
@@ -323,14 +428,12 @@ func (esr *EnhancedSubqueryResolver) GetAvailableSubqueryTables() []string {
 		return tables
 	}
 
-	// Add CTE names as available tables
+	// Add CTE names and FROM clause subquery aliases as available tables
 	for nodeID := range esr.typeCache {
 		if node := dependencyGraph.GetNode(nodeID); node != nil {
-			if node.NodeType == parsercommon.SQDependencyCTE {
-				// Extract CTE name from node ID
-				cteName := esr.extractCTENameFromNodeID(nodeID)
-				if cteName != "" {
-					tables = append(tables, cteName)
+			if node.NodeType == parsercommon.SQDependencyCTE || node.NodeType == parsercommon.SQDependencyFromSubquery {
+				if name := esr.tableNameOf(nodeID); name != "" {
+					tables = append(tables, name)
 				}
 			}
 		}
@@ -389,8 +492,7 @@ func (esr *EnhancedSubqueryResolver) ValidateSubqueryReferences() []ValidationEr
 func (esr *EnhancedSubqueryResolver) ResolveSubqueryReference(tableName string) ([]*InferredFieldInfo, bool) {
 	// Check if this table name corresponds to a CTE
 	for nodeID, fieldInfos := range esr.typeCache {
-		cteName := esr.extractCTENameFromNodeID(nodeID)
-		if cteName == tableName {
+		if esr.tableNameOf(nodeID) == tableName {
 			return fieldInfos, true
 		}
 	}
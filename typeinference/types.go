@@ -55,6 +55,7 @@ type InferenceContext struct {
 	Dialect       snapsql.Dialect   // Database dialect
 	TableAliases  map[string]string // Table alias mappings
 	CurrentTables []string          // Currently available tables
+	OuterTables   []string          // Tables of the outer query visible from a LATERAL subquery
 	SubqueryDepth int               // Current subquery nesting depth
 }
 
@@ -146,8 +147,14 @@ func NewTypeInferenceEngine2(
 			dialect = snapsql.DialectPostgres
 		case "mysql":
 			dialect = snapsql.DialectMySQL
+		case "mariadb":
+			dialect = snapsql.DialectMariaDB
 		case "sqlite":
 			dialect = snapsql.DialectSQLite
+		case "duckdb":
+			dialect = snapsql.DialectDuckDB
+		case "clickhouse":
+			dialect = snapsql.DialectClickHouse
 		default:
 			dialect = snapsql.DialectPostgres
 		}
@@ -249,7 +256,12 @@ func (e *TypeInferenceEngine2) InferSelectTypes() ([]*InferredFieldInfo, error)
 		e.context.CurrentTables = append(e.context.CurrentTables, subqueryTables...)
 	}
 
-	return e.inferSelectStatement(selectStmt)
+	fields, err := e.inferSelectStatement(selectStmt)
+	if err != nil || len(selectStmt.SetOperations) == 0 {
+		return fields, err
+	}
+
+	return e.mergeSetOperationTypes(selectStmt, fields)
 }
 
 // InferTypes performs unified type inference for any statement type (Phase 6)
@@ -257,7 +269,7 @@ func (e *TypeInferenceEngine2) InferTypes() ([]*InferredFieldInfo, error) {
 	switch stmt := e.statementNode.(type) {
 	case *parser.SelectStatement:
 		return e.InferSelectTypes()
-	case *parser.InsertIntoStatement, *parser.UpdateStatement, *parser.DeleteFromStatement:
+	case *parser.InsertIntoStatement, *parser.UpdateStatement, *parser.DeleteFromStatement, *parser.MergeStatement:
 		// Phase 6: DML statement inference
 		return e.dmlEngine.InferDMLStatementType(e.statementNode)
 	default:
@@ -299,8 +311,10 @@ func (e *TypeInferenceEngine2) performSchemaValidation() []error {
 			}
 		}
 
-		// Add subquery validation errors if enhanced resolver is available
+		// Add subquery validation errors if enhanced resolver is available.
+		// CTE・サブクエリの型は検証前に解決しておかないと全て未解決として報告される
 		if e.enhancedResolver != nil {
+			_ = e.enhancedResolver.ResolveSubqueryTypesComplete() // エラーは InferSelectTypes が警告として報告する
 			subqueryErrors := e.enhancedResolver.ValidateSubqueryReferences()
 			// Convert ValidationError to error and add to allErrors
 			for _, vErr := range subqueryErrors {
@@ -663,6 +677,11 @@ func (e *TypeInferenceEngine2) inferSingleFieldType(field *parser.SelectField) (
 	// Find all tables that contain this column
 	matches := e.schemaResolver.FindColumnInTables(field.OriginalField, e.context.CurrentTables)
 
+	// LATERAL サブクエリでは、サブクエリ内のテーブルに無い列だけ外側のテーブルから探す
+	if len(matches) == 0 && len(e.context.OuterTables) > 0 {
+		matches = e.schemaResolver.FindColumnInTables(field.OriginalField, e.context.OuterTables)
+	}
+
 	if len(matches) == 0 {
 		return nil, FieldSource{}, fmt.Errorf("%w: %s", snapsql.ErrColumnNotFoundInAnyTable, field.OriginalField)
 	}
@@ -712,6 +731,10 @@ func (e *TypeInferenceEngine2) inferFunctionFieldType(field *parser.SelectField)
 		fieldType = e.applyFunctionTypeRule(functionName)
 	}
 
+	if field.Window != nil {
+		fieldType = e.inferWindowFunctionType(functionName, field.Window, argTokens, fieldType)
+	}
+
 	fieldSource := FieldSource{
 		Type:         "function",
 		FunctionName: functionName,
@@ -723,11 +746,22 @@ func (e *TypeInferenceEngine2) inferFunctionFieldType(field *parser.SelectField)
 
 // inferLiteralFieldType infers type for literal values
 func (e *TypeInferenceEngine2) inferLiteralFieldType(field *parser.SelectField) (*TypeInfo, FieldSource, error) {
+	// parserstep4 はリテラルの OriginalField を設定しないので式のトークンから値を取る
+	literal := field.OriginalField
+	if literal == "" {
+		var sb strings.Builder
+		for _, t := range field.Expression {
+			sb.WriteString(t.Value)
+		}
+
+		literal = sb.String()
+	}
+
 	// Basic literal type inference
-	literalType := e.inferLiteralType(field.OriginalField)
+	literalType := e.inferLiteralType(literal)
 	fieldSource := FieldSource{
 		Type:       "literal",
-		Expression: field.OriginalField,
+		Expression: literal,
 	}
 
 	return literalType, fieldSource, nil
@@ -1061,7 +1095,13 @@ func (e *TypeInferenceEngine2) applyAdvancedFunctionRule(funcName string, argTyp
 	case "ROW_NUMBER", "RANK", "DENSE_RANK":
 		return &TypeInfo{BaseType: "int", IsNullable: false}, nil
 
-	case "FIRST_VALUE", "LAST_VALUE", "LAG", "LEAD":
+	case "NTILE":
+		return &TypeInfo{BaseType: "int", IsNullable: false}, nil
+
+	case "PERCENT_RANK", "CUME_DIST":
+		return &TypeInfo{BaseType: "float", IsNullable: false}, nil
+
+	case "FIRST_VALUE", "LAST_VALUE", "NTH_VALUE", "LAG", "LEAD":
 		if len(argTypes) > 0 && argTypes[0] != nil {
 			return &TypeInfo{
 				BaseType:   argTypes[0].BaseType,
@@ -1108,19 +1148,54 @@ func (e *TypeInferenceEngine2) applyAdvancedFunctionRule(funcName string, argTyp
 	case "SQRT", "POWER", "POW", "EXP", "LN", "LOG":
 		return &TypeInfo{BaseType: "float", IsNullable: true}, nil
 
-	// JSON functions (PostgreSQL)
-	case "JSON_EXTRACT_PATH", "JSON_EXTRACT_PATH_TEXT":
-		return &TypeInfo{BaseType: "json", IsNullable: true}, nil
-
-	case "JSON_OBJECT", "JSON_ARRAY":
-		return &TypeInfo{BaseType: "json", IsNullable: false}, nil
-
 	default:
+		// JSON functions and other dialect specific functions come from the signature catalog
+		if typeInfo, ok := e.applyFunctionSignature(funcName, argTypes); ok {
+			return typeInfo, nil
+		}
+
 		// Unknown function - default to any type
 		return &TypeInfo{BaseType: "any", IsNullable: true}, nil
 	}
 }
 
+// applyFunctionSignature infers the result type from snapsql.FunctionSignatures of the current dialect.
+func (e *TypeInferenceEngine2) applyFunctionSignature(funcName string, argTypes []*TypeInfo) (*TypeInfo, bool) {
+	sig, ok := snapsql.LookupFunctionSignature(e.context.Dialect, funcName)
+	if !ok {
+		// JSON_EXTRACT / JSON_UNQUOTE は PostgreSQL 向けに -> / ->> へ変換されるので MySQL の型を使う
+		if e.context.Dialect != snapsql.DialectPostgres || (funcName != "JSON_EXTRACT" && funcName != "JSON_UNQUOTE") {
+			return nil, false
+		}
+
+		if sig, ok = snapsql.LookupFunctionSignature(snapsql.DialectMySQL, funcName); !ok {
+			return nil, false
+		}
+	}
+
+	baseType := sig.ReturnType
+	if sig.ReturnTypeByArg && len(argTypes) > 0 && argTypes[0] != nil {
+		baseType = argTypes[0].BaseType
+	}
+
+	// "array" などの Go の型に対応しない型は扱わない
+	switch baseType {
+	case "", "array":
+		return nil, false
+	}
+
+	nullable := sig.Nullable
+	if sig.NullableByArg {
+		for _, argType := range argTypes {
+			if argType == nil || argType.IsNullable {
+				nullable = true
+			}
+		}
+	}
+
+	return &TypeInfo{BaseType: baseType, IsNullable: nullable}, true
+}
+
 // InferCaseExpression infers type for CASE expressions
 func (e *TypeInferenceEngine2) InferCaseExpression(tokens []tokenizer.Token) (*TypeInfo, error) {
 	analyzer := NewCaseExpressionAnalyzer(tokens, e)
@@ -0,0 +1,43 @@
+package typeinference
+
+import (
+	"github.com/shibukawa/snapsql/parser"
+	"github.com/shibukawa/snapsql/tokenizer"
+)
+
+// inferWindowFunctionType adjusts the type of a function called with OVER (...).
+//
+// Aggregates used as window functions are computed per row over the window frame. The default
+// frame always contains the current row, so SUM/MIN/MAX/AVG only return NULL when the argument
+// is nullable. With an explicit ROWS / RANGE / GROUPS frame the frame may be empty and the
+// result stays nullable.
+func (e *TypeInferenceEngine2) inferWindowFunctionType(functionName string, window *parser.WindowSpec, argTokens [][]tokenizer.Token, fieldType *TypeInfo) *TypeInfo {
+	switch functionName {
+	case "ROW_NUMBER", "RANK", "DENSE_RANK", "NTILE", "COUNT":
+		return &TypeInfo{BaseType: "int", IsNullable: false}
+	case "PERCENT_RANK", "CUME_DIST":
+		return &TypeInfo{BaseType: "float", IsNullable: false}
+	case "LAG", "LEAD", "FIRST_VALUE", "LAST_VALUE", "NTH_VALUE":
+		// 前後の行やN番目の行が存在しない場合は NULL になる
+		result := *fieldType
+		result.IsNullable = true
+
+		return &result
+	case "SUM", "MIN", "MAX", "AVG":
+		if len(window.Frame) > 0 || len(argTokens) == 0 {
+			return fieldType
+		}
+
+		argType, err := NewExpressionCastAnalyzer(argTokens[0], e).InferExpressionType()
+		if err != nil || argType == nil || argType.BaseType == "any" {
+			return fieldType
+		}
+
+		result := *fieldType
+		result.IsNullable = argType.IsNullable
+
+		return &result
+	}
+
+	return fieldType
+}
@@ -0,0 +1,74 @@
+package typeinference
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestInferWindowFunctionTypes(t *testing.T) {
+	schemas, err := loadSchemaFromYAML(`
+name: test_db
+tables:
+  - name: emp
+    columns:
+      id:
+        name: id
+        data_type: int
+        nullable: false
+        is_primary_key: true
+      dept:
+        name: dept
+        data_type: string
+        nullable: false
+      salary:
+        name: salary
+        data_type: decimal
+        nullable: false
+      bonus:
+        name: bonus
+        data_type: decimal
+        nullable: true
+`)
+	assert.NoError(t, err)
+
+	stmt, err := parseSQL(`SELECT
+		ROW_NUMBER() OVER (PARTITION BY dept ORDER BY salary DESC) AS rn,
+		RANK() OVER (ORDER BY salary) AS rnk,
+		PERCENT_RANK() OVER (ORDER BY salary) AS pr,
+		COUNT(*) OVER (PARTITION BY dept) AS cnt,
+		SUM(salary) OVER (PARTITION BY dept) AS dept_total,
+		SUM(bonus) OVER (PARTITION BY dept) AS dept_bonus,
+		SUM(salary) OVER (ORDER BY id ROWS BETWEEN 2 PRECEDING AND 1 PRECEDING) AS prev_total,
+		LAG(salary, 1) OVER (ORDER BY id) AS prev_salary
+	FROM emp`)
+	assert.NoError(t, err)
+
+	results, err := InferFieldTypes(schemas, stmt, nil)
+	assert.NoError(t, err)
+
+	expected := []struct {
+		name       string
+		baseType   string
+		isNullable bool
+	}{
+		{"rn", "int", false},
+		{"rnk", "int", false},
+		{"pr", "float", false},
+		{"cnt", "int", false},
+		// 既定のフレームは現在行を含むので、引数が NOT NULL なら結果も NOT NULL
+		{"dept_total", "decimal", false},
+		{"dept_bonus", "decimal", true},
+		// 明示的なフレームは空になりうる
+		{"prev_total", "decimal", true},
+		{"prev_salary", "decimal", true},
+	}
+
+	assert.Equal(t, len(expected), len(results))
+
+	for i, want := range expected {
+		assert.Equal(t, want.name, results[i].Name)
+		assert.Equal(t, want.baseType, results[i].Type.BaseType, "type of %s", want.name)
+		assert.Equal(t, want.isNullable, results[i].Type.IsNullable, "nullability of %s", want.name)
+	}
+}
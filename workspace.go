@@ -0,0 +1,99 @@
+package snapsql
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// DefaultWorkspaceFile is the workspace file name looked up when none is specified
+const DefaultWorkspaceFile = "snapsql.workspace.yaml"
+
+// Workspace lists SnapSQL projects that live in one repository
+type Workspace struct {
+	// Members are project directories relative to the workspace file
+	Members []string `yaml:"members"`
+
+	// Dir is the directory containing the workspace file (set by LoadWorkspace)
+	Dir string `yaml:"-"`
+}
+
+// WorkspaceMember is a resolved member project of a workspace
+type WorkspaceMember struct {
+	// Name is the member path as written in the workspace file
+	Name string
+	// Dir is the absolute project directory
+	Dir string
+}
+
+// LoadWorkspace reads a workspace file and validates that every member directory exists
+func LoadWorkspace(path string) (*Workspace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrWorkspaceFileNotFound, path)
+		}
+
+		return nil, fmt.Errorf("failed to read workspace file: %w", err)
+	}
+
+	var workspace Workspace
+	if err := yaml.UnmarshalWithOptions(data, &workspace, yaml.Strict()); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace file: %w", err)
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve workspace file path: %w", err)
+	}
+
+	workspace.Dir = filepath.Dir(absPath)
+
+	if _, err := workspace.ResolveMembers(); err != nil {
+		return nil, err
+	}
+
+	return &workspace, nil
+}
+
+// ResolveMembers returns the member projects with absolute directories in declaration order.
+// Duplicate entries are skipped.
+func (w *Workspace) ResolveMembers() ([]WorkspaceMember, error) {
+	members := make([]WorkspaceMember, 0, len(w.Members))
+	seen := make(map[string]struct{}, len(w.Members))
+
+	for _, raw := range w.Members {
+		name := strings.TrimSpace(raw)
+		if name == "" {
+			continue
+		}
+
+		dir := name
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(w.Dir, dir)
+		}
+
+		dir = filepath.Clean(dir)
+
+		info, err := os.Stat(dir)
+		if err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("%w: %s", ErrWorkspaceMemberNotFound, name)
+		}
+
+		if _, ok := seen[dir]; ok {
+			continue
+		}
+
+		seen[dir] = struct{}{}
+		members = append(members, WorkspaceMember{Name: name, Dir: dir})
+	}
+
+	if len(members) == 0 {
+		return nil, ErrWorkspaceNoMembers
+	}
+
+	return members, nil
+}
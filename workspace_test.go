@@ -0,0 +1,47 @@
+package snapsql
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestLoadWorkspace(t *testing.T) {
+	root := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "services", "api"), 0o755))
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "batch"), 0o755))
+
+	path := filepath.Join(root, DefaultWorkspaceFile)
+	assert.NoError(t, os.WriteFile(path, []byte("members:\n  - services/api\n  - batch\n  - ./batch\n"), 0o644))
+
+	workspace, err := LoadWorkspace(path)
+	assert.NoError(t, err)
+
+	members, err := workspace.ResolveMembers()
+	assert.NoError(t, err)
+	assert.Equal(t, []WorkspaceMember{
+		{Name: "services/api", Dir: filepath.Join(root, "services", "api")},
+		{Name: "batch", Dir: filepath.Join(root, "batch")},
+	}, members)
+}
+
+func TestLoadWorkspace_Errors(t *testing.T) {
+	root := t.TempDir()
+
+	_, err := LoadWorkspace(filepath.Join(root, DefaultWorkspaceFile))
+	assert.IsError(t, err, ErrWorkspaceFileNotFound)
+
+	path := filepath.Join(root, "missing.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("members:\n  - nowhere\n"), 0o644))
+
+	_, err = LoadWorkspace(path)
+	assert.IsError(t, err, ErrWorkspaceMemberNotFound)
+
+	path = filepath.Join(root, "empty.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("members: []\n"), 0o644))
+
+	_, err = LoadWorkspace(path)
+	assert.IsError(t, err, ErrWorkspaceNoMembers)
+}